@@ -22,6 +22,7 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log"
 	"net/http"
@@ -35,20 +36,27 @@ import (
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"github.com/fntelecomllc/studio/backend/internal/api"
 	"github.com/fntelecomllc/studio/backend/internal/config"
 	_ "github.com/fntelecomllc/studio/backend/docs"
+	"github.com/fntelecomllc/studio/backend/internal/domainexpert"
 	"github.com/fntelecomllc/studio/backend/internal/httpvalidator"
 	"github.com/fntelecomllc/studio/backend/internal/keywordscanner"
+	"github.com/fntelecomllc/studio/backend/internal/logging"
+	"github.com/fntelecomllc/studio/backend/internal/metrics"
 	"github.com/fntelecomllc/studio/backend/internal/middleware"
+	"github.com/fntelecomllc/studio/backend/internal/migrationverifier"
 	"github.com/fntelecomllc/studio/backend/internal/proxymanager"
 	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/settings"
+	"github.com/fntelecomllc/studio/backend/internal/startup"
 	"github.com/fntelecomllc/studio/backend/internal/store"
 	pg_store "github.com/fntelecomllc/studio/backend/internal/store/postgres"
+	"github.com/fntelecomllc/studio/backend/internal/tracing"
 	"github.com/fntelecomllc/studio/backend/internal/websocket"
 )
 
@@ -79,6 +87,30 @@ func main() {
 		config.LoadWithEnv("") // This will apply env overrides even without config file
 	}
 	log.Println("Configuration loaded with environment overrides.")
+	if appConfig.Logging.Level != "" {
+		if level, err := logging.ParseLevel(appConfig.Logging.Level); err != nil {
+			log.Printf("Warning: %v; logging at %s", err, logging.GlobalAuthLogger.Level())
+		} else {
+			logging.SetLevel(level)
+		}
+	}
+
+	tracingEnabled, shutdownTracing, err := tracing.Setup(context.Background(), appConfig.Tracing)
+	if err != nil {
+		log.Fatalf("FATAL: %v", err)
+	}
+	if tracingEnabled {
+		log.Printf("Tracing enabled; exporting spans to %s", appConfig.Tracing.OTLPEndpoint)
+	}
+
+	passwordPepper, err := services.NewPasswordPepper(appConfig.PasswordHash)
+	if err != nil {
+		log.Fatalf("FATAL: %v", err)
+	}
+	passwordPolicy, err := services.NewPasswordPolicy(appConfig.PasswordPolicy)
+	if err != nil {
+		log.Fatalf("FATAL: %v", err)
+	}
 
 	wsBroadcaster := websocket.InitGlobalBroadcaster()
 	log.Println("Global WebSocket broadcaster initialized and started.")
@@ -92,6 +124,8 @@ func main() {
 	var keywordStore store.KeywordStore
 	var auditLogStore store.AuditLogStore
 	var campaignJobStore store.CampaignJobStore
+	var eventDeliveryStore store.EventDeliveryStore
+	var campaignListViewStore store.CampaignListViewStore
 	var db *sqlx.DB
 
 	// Use database configuration from enhanced config
@@ -130,24 +164,57 @@ func main() {
 			dbHost, dbPort, dbUser, dbPassword, dbName, dbSSLMode)
 	}
 
+	// The connection is verified by the startup sequence so the server can report liveness while the database comes up
 	var pgErr error
-	db, pgErr = sqlx.Connect("postgres", dsn)
+	db, pgErr = openPostgres(dsn, tracingEnabled)
 	if pgErr != nil {
-		log.Fatalf("FATAL: Could not connect to PostgreSQL database: %v", pgErr)
+		log.Fatalf("FATAL: Could not open PostgreSQL database: %v", pgErr)
 	}
 	defer db.Close()
 
 	db.SetMaxOpenConns(appConfig.Server.DBMaxOpenConns)
 	db.SetMaxIdleConns(appConfig.Server.DBMaxIdleConns)
 	db.SetConnMaxLifetime(time.Duration(appConfig.Server.DBConnMaxLifetimeMinutes) * time.Minute)
-	log.Println("Successfully connected to PostgreSQL database.")
+	log.Println("PostgreSQL database handle opened.")
 
-	campaignStore = pg_store.NewCampaignStorePostgres(db)
+	var replicaDB *sqlx.DB
+	if appConfig.Server.ReadReplicaDSN != "" {
+		replicaDB, pgErr = openPostgres(appConfig.Server.ReadReplicaDSN, tracingEnabled)
+		if pgErr != nil {
+			log.Fatalf("FATAL: Could not open PostgreSQL read replica: %v", pgErr)
+		}
+		defer replicaDB.Close()
+		replicaDB.SetMaxOpenConns(appConfig.Server.DBMaxOpenConns)
+		replicaDB.SetMaxIdleConns(appConfig.Server.DBMaxIdleConns)
+		replicaDB.SetConnMaxLifetime(time.Duration(appConfig.Server.DBConnMaxLifetimeMinutes) * time.Minute)
+		log.Println("PostgreSQL read replica handle opened; campaign listings and results are read from it.")
+	}
+
+	campaignStore = pg_store.NewCampaignStorePostgres(db, pg_store.WithResultCommitChunkSize(appConfig.Worker.ResultCommitChunkSize),
+		pg_store.WithUniqueCampaignNames(appConfig.ResourceAccess.UniqueCampaignNamesEnforced()),
+		pg_store.WithReadReplica(replicaDB, time.Duration(appConfig.Server.ReadReplicaRetrySeconds)*time.Second))
 	personaStore = pg_store.NewPersonaStorePostgres(db)
 	proxyStore = pg_store.NewProxyStorePostgres(db)
 	keywordStore = pg_store.NewKeywordStorePostgres(db)
 	auditLogStore = pg_store.NewAuditLogStorePostgres(db)
-	campaignJobStore = pg_store.NewCampaignJobStorePostgres(db)
+	campaignJobStore = pg_store.NewCampaignJobStorePostgres(db,
+		pg_store.WithPriorityAging(time.Duration(appConfig.Worker.PriorityAgingIntervalSeconds)*time.Second),
+		pg_store.WithMaxConcurrentJobsPerCampaign(appConfig.Worker.MaxConcurrentJobsPerCampaign))
+	eventDeliveryStore = pg_store.NewEventDeliveryStorePostgres(db)
+	campaignListViewStore = pg_store.NewCampaignListViewStorePostgres(db)
+	if appConfig.Audit.CampaignStatusTransitionsEnabled() {
+		campaignStore = services.NewStatusAuditingCampaignStore(db, campaignStore, auditLogStore)
+	}
+	webhookSvc := services.NewWebhookService(eventDeliveryStore, appConfig.Webhooks)
+	resultWebhookStreamer := services.NewResultWebhookStreamer(webhookSvc, appConfig.Webhooks.Results)
+	if resultWebhookStreamer != nil {
+		campaignStore = services.NewResultStreamingCampaignStore(campaignStore, resultWebhookStreamer)
+		log.Printf("Validation results will be streamed to the result webhook in batches of %d.", appConfig.Webhooks.Results.BatchSize)
+	}
+	campaignWebhookStore := pg_store.NewCampaignWebhookStorePostgres(db)
+	webhookSvc.SetCampaignWebhookStore(campaignWebhookStore)
+	campaignWebhookSvc := services.NewCampaignWebhookService(db, campaignWebhookStore, campaignStore, webhookSvc, appConfig.Webhooks.Campaigns)
+	campaignStore = services.NewCampaignWebhookTriggeringStore(campaignStore, campaignWebhookSvc)
 	log.Println("PostgreSQL-backed stores initialized.")
 
 	var defaultProxyTimeout time.Duration = 30 * time.Second
@@ -155,6 +222,9 @@ func main() {
 		defaultProxyTimeout = time.Duration(appConfig.HTTPValidator.RequestTimeoutSeconds) * time.Second
 	}
 	proxyMgr := proxymanager.NewProxyManager(appConfig.Proxies, defaultProxyTimeout)
+	proxyQuarantineCooldown := time.Duration(appConfig.ProxyHealth.QuarantineCooldownSeconds) * time.Second
+	proxyMgr.SetQuarantinePolicy(appConfig.ProxyHealth.QuarantineThreshold, proxyQuarantineCooldown)
+	proxyMgr.SetQuarantineListener(services.NewProxyQuarantineRecorder(proxyStore, auditLogStore))
 	log.Println("ProxyManager initialized.")
 
 	httpValSvc := httpvalidator.NewHTTPValidator(appConfig)
@@ -165,11 +235,16 @@ func main() {
 
 	// Initialize session service for session-based authentication
 	sessionConfig := config.GetDefaultSessionSettings()
-	sessionService, err := services.NewSessionService(db, sessionConfig.ToServiceConfig(), auditLogStore)
+	sessionConfig.ApplyEnvironmentOverrides()
+	sessionStore, err := services.NewSessionStoreFromSettings(sessionConfig)
+	if err != nil {
+		log.Fatalf("FATAL: Failed to initialize session store: %v", err)
+	}
+	sessionService, err := services.NewSessionService(db, sessionConfig.ToServiceConfig(), auditLogStore, services.WithSessionStore(sessionStore))
 	if err != nil {
 		log.Fatalf("FATAL: Failed to initialize session service: %v", err)
 	}
-	log.Println("Session service initialized.")
+	log.Printf("Session service initialized with the %s session store.", sessionConfig.Store)
 
 	// All stores including campaignJobStore are now properly initialized above
 	domainGenSvc := services.NewDomainGenerationService(db, campaignStore, campaignJobStore, auditLogStore)
@@ -196,7 +271,11 @@ func main() {
 		domainGenSvc,
 		dnsCampaignSvc,
 		httpKeywordCampaignSvc,
+		services.WithImmutableCampaignFields(appConfig.CampaignUpdates.ImmutableFieldsEnforced()),
 	)
+	if tracingEnabled {
+		campaignOrchestratorSvc = services.TraceCampaignOrchestrator(campaignOrchestratorSvc)
+	}
 	log.Println("CampaignOrchestratorService initialized.")
 
 	serverInstanceID, _ := os.Hostname()
@@ -225,22 +304,139 @@ func main() {
 		auditLogStore,
 		campaignJobStore,
 	)
+	apiHandler.PermissionCache = sessionService.PermissionCache()
+	apiHandler.PersonaTests = services.NewPersonaTestScheduler(appConfig, personaStore)
+	apiHandler.PersonaTester = services.NewPersonaTester(appConfig)
+	apiHandler.ProxyHealth = services.NewProxyHealthChecker(appConfig, db, proxyStore)
+	apiHandler.PasswordPepper = passwordPepper
+	apiHandler.PasswordPolicy = passwordPolicy
+	apiHandler.SessionSettings = sessionConfig
 	log.Println("Main APIHandler initialized.")
 
-	campaignOrchestratorAPIHandler := api.NewCampaignOrchestratorAPIHandler(campaignOrchestratorSvc)
+	campaignOrchestratorAPIHandler := api.NewCampaignOrchestratorAPIHandler(campaignOrchestratorSvc, campaignListViewStore)
+	if appConfig.ResourceAccess.OwnershipEnforced() {
+		campaignOrchestratorAPIHandler.SetResourceAccessChecker(services.NewResourceAccessChecker(db, personaStore))
+		log.Println("Persona ownership is enforced at campaign creation.")
+	}
+	campaignOrchestratorAPIHandler.SetDiagnosticsService(services.NewCampaignDiagnosticsService(
+		appConfig, db, campaignStore, campaignJobStore, personaStore, proxyStore, apiHandler.PersonaTests))
+	campaignOrchestratorAPIHandler.SetCompareService(services.NewCampaignCompareService(appConfig, db, campaignStore))
+	campaignOrchestratorAPIHandler.SetResultExportService(services.NewCampaignResultExportService(appConfig, db, campaignStore))
+	campaignOrchestratorAPIHandler.SetReclassifier(services.NewHTTPKeywordReclassifier(appConfig, db, campaignStore, keywordStore))
+	campaignOrchestratorAPIHandler.SetResultExplainer(services.NewCampaignResultExplainer(db, campaignStore, personaStore, proxyStore))
+	campaignOrchestratorAPIHandler.SetCampaignCloner(services.NewCampaignCloner(db, campaignStore))
+	staleJobReaper := services.NewStaleJobReaper(appConfig, campaignJobStore, auditLogStore)
+	campaignAutoRetrier := services.NewCampaignAutoRetrier(appConfig, db, campaignStore, campaignOrchestratorSvc)
+	campaignOrchestratorAPIHandler.SetAutoRetrier(campaignAutoRetrier)
+	campaignScheduler := services.NewCampaignScheduler(appConfig, db, pg_store.NewCampaignScheduleStorePostgres(db), campaignStore, campaignOrchestratorSvc)
+	campaignOrchestratorAPIHandler.SetCampaignScheduler(campaignScheduler)
+	campaignOrchestratorAPIHandler.SetMaxBulkStatusIDs(appConfig.CampaignStatus.MaxBulkIDs)
+	campaignOrchestratorAPIHandler.SetCampaignWebhookService(campaignWebhookSvc)
+	campaignAutoPromoter := services.NewCampaignAutoPromoter(appConfig, db, campaignStore, dnsCampaignSvc, campaignOrchestratorSvc)
+	tldList := domainexpert.NewTLDList()
+	if appConfig.TLDList.ValidationEnabled() {
+		campaignOrchestratorAPIHandler.SetTLDList(tldList)
+	}
+	tldListAPIHandler := api.NewTLDListAPIHandler(tldList, appConfig.TLDList.Source)
 	log.Println("CampaignOrchestratorAPIHandler initialized.")
 
+	eventDeliveryAPIHandler := api.NewEventDeliveryAPIHandler(webhookSvc)
+	log.Println("WebhookService and EventDeliveryAPIHandler initialized.")
+
+	deadLetterJobAPIHandler := api.NewDeadLetterJobAPIHandler(pg_store.NewCampaignJobDeadLetterStorePostgres(db))
+
+	rolePermissionSyncSvc := services.NewRolePermissionSyncService(pg_store.NewRolePermissionStorePostgres(db), services.EssentialPermissions)
+	adminRoleAPIHandler := api.NewAdminRoleAPIHandler(rolePermissionSyncSvc, auditLogStore)
+	adminRoleAPIHandler.SetPermissionCache(sessionService.PermissionCache())
+	log.Println("RolePermissionSyncService and AdminRoleAPIHandler initialized.")
+
+	passwordMigrationSvc := services.NewPasswordMigrationService(pg_store.NewPasswordMigrationStorePostgres(db), appConfig.PasswordHash)
+	adminPasswordMigrationAPIHandler := api.NewAdminPasswordMigrationAPIHandler(passwordMigrationSvc, auditLogStore)
+	log.Println("PasswordMigrationService and AdminPasswordMigrationAPIHandler initialized.")
+
+	settingsSvc := settings.NewService(pg_store.NewSettingsStorePostgres(db), settings.DefaultRegistry(), settings.DefaultCacheTTL)
+	settingsAPIHandler := api.NewSettingsAPIHandler(settingsSvc, auditLogStore)
+	log.Println("SettingsService and SettingsAPIHandler initialized.")
+
+	userDataSvc := services.NewUserDataService(db, pg_store.NewUserDataStorePostgres(db), auditLogStore, campaignStore, sessionService)
+	userDataAPIHandler := api.NewUserDataAPIHandler(userDataSvc, auditLogStore)
+	auditLogQuerySvc := services.NewAuditLogQueryService(db, auditLogStore, appConfig.Audit)
+	auditLogAPIHandler := api.NewAuditLogAPIHandler(auditLogQuerySvc, auditLogStore)
+	log.Println("UserDataService and UserDataAPIHandler initialized.")
+
+	userAccountSvc := services.NewUserAccountService(db, pg_store.NewUserAccountStorePostgres(db), sessionService)
+	userAccountAPIHandler := api.NewUserAccountAPIHandler(userAccountSvc)
+	log.Println("UserAccountService and UserAccountAPIHandler initialized.")
+
 	webSocketAPIHandler := api.NewWebSocketHandler(wsBroadcaster, sessionService)
 	log.Println("WebSocketAPIHandler initialized.")
 
 	// Initialize authentication and security handlers
 	authHandler := api.NewAuthHandler(sessionService, sessionConfig, db)
+	authHandler.SetPasswordHashDiagnostics(appConfig.PasswordHash)
+	authHandler.SetPasswordPepper(passwordPepper)
+	authHandler.SetPasswordHistorySize(appConfig.PasswordHash.HistorySize)
+	authHandler.SetPasswordPolicy(passwordPolicy)
+	if appConfig.LoginRateLimit.LimitsEnabled() {
+		authHandler.SetLoginRateLimiter(services.NewLoginRateLimiter(pg_store.NewRateLimitStorePostgres(db), appConfig.LoginRateLimit))
+	}
+	if sessionConfig.LockoutDetails {
+		authHandler.SetUnknownAccountLockouts(services.NewUnknownAccountLockouts(pg_store.NewRateLimitStorePostgres(db)))
+	}
+	loginRateTracker := services.NewLoginRateTracker(appConfig.LoginRates)
+	authHandler.SetLoginRateTracker(loginRateTracker)
 	log.Println("AuthHandler initialized.")
 
+	// Sessions, logins, campaigns and jobs are read for each /metrics scrape
+	if err := metrics.Register(metrics.NewStateCollector(
+		func() metrics.SessionStats {
+			m := sessionService.GetMetrics()
+			return metrics.SessionStats{
+				Total:          m.TotalSessions,
+				Active:         m.ActiveSessions,
+				CacheHitRate:   m.CacheHitRate,
+				CachedSessions: m.CachedSessions,
+				CacheEvictions: m.CacheEvictions,
+				SharedLoads:    m.SharedLoads,
+				SecurityEvents: m.SecurityEvents,
+			}
+		},
+		func() metrics.LoginStats {
+			rates := loginRateTracker.Snapshot(0).Global
+			return metrics.LoginStats{
+				SuccessesPerMinute: rates.SuccessesPerMinute,
+				FailuresPerMinute:  rates.FailuresPerMinute,
+				LockoutsPerMinute:  rates.LockoutsPerMinute,
+			}
+		},
+		pg_store.NewMetricsStorePostgres(db),
+	)); err != nil {
+		log.Fatalf("Failed to register metrics collector: %v", err)
+	}
+	metricsAccess, err := middleware.MetricsAccess(appConfig.Server.MetricsToken, appConfig.Server.MetricsNetworks())
+	if err != nil {
+		log.Fatalf("Invalid metrics access configuration: %v", err)
+	}
+
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(sessionService, sessionConfig)
+	authMiddleware.SetAPIKeyStore(pg_store.NewAPIKeyStorePostgres(db))
 	securityMiddleware := middleware.NewSecurityMiddleware()
 	rateLimitMiddleware := middleware.NewRateLimitMiddleware()
+	routeRates := appConfig.RouteRateLimit
+	limitPersonaTests := func(c *gin.Context) {}
+	if routeRates.LimitsEnabled() {
+		limiter, err := middleware.NewRateLimiterFromConfig(routeRates)
+		if err != nil {
+			log.Fatalf("Invalid route rate limit configuration: %v", err)
+		}
+		rateLimitMiddleware.SetLimiter(limiter)
+		campaignOrchestratorAPIHandler.SetCreateRateLimit(rateLimitMiddleware.RouteRateLimit("campaign_create",
+			middleware.PerMinute(routeRates.CampaignCreate.RequestsPerMinute, routeRates.CampaignCreate.Burst)))
+		limitPersonaTests = rateLimitMiddleware.RouteRateLimit("persona_test",
+			middleware.PerMinute(routeRates.PersonaTest.RequestsPerMinute, routeRates.PersonaTest.Burst))
+		log.Printf("Route rate limits enabled with the %s store.", routeRates.Store)
+	}
 	log.Println("Security middleware initialized.")
 
 	// Initialize health check handler
@@ -250,14 +446,87 @@ func main() {
 	appCtx, appCancel := context.WithCancel(context.Background())
 	defer appCancel()
 
+	if appConfig.TLDList.RefreshIntervalHours > 0 {
+		tldList.StartAutoRefresh(appCtx, appConfig.TLDList.Source, time.Duration(appConfig.TLDList.RefreshIntervalHours)*time.Hour)
+	}
+
 	numWorkers := appConfig.Worker.NumWorkers
 	if numWorkers <= 0 {
 		numWorkers = defaultNumWorkers
 	}
-	go workerService.StartWorkers(appCtx, numWorkers)
+
+	// Readiness is held back until the database answers, migrations check out and background services run
+	startupCfg := appConfig.Startup
+	startupSequencer := startup.NewSequencer(
+		startup.Step{
+			Name:    "database",
+			Enabled: startupCfg.PingDatabaseEnabled(),
+			Run: startup.PingWithRetry(db.PingContext, startupCfg.DBPingAttempts,
+				time.Duration(startupCfg.DBPingIntervalSeconds)*time.Second),
+		},
+		startup.Step{
+			Name:    "migrations",
+			Enabled: startupCfg.VerifyMigrationsEnabled(),
+			Run: func(ctx context.Context) error {
+				verifier := migrationverifier.NewMigrationVerifier(db)
+				result, err := verifier.VerifyMigrations()
+				if err != nil {
+					return err
+				}
+				if !result.Success {
+					log.Print(verifier.GenerateReport(result))
+					return fmt.Errorf("%d missing, %d conflicting and %d failed migrations",
+						len(result.MissingMigrations), len(result.ConflictingMigrations), len(result.FailedMigrations))
+				}
+				return nil
+			},
+		},
+		startup.Step{
+			Name:    "session service",
+			Enabled: startupCfg.StartSessionServiceEnabled(),
+			Run: func(ctx context.Context) error {
+				sessionService.Start()
+				return nil
+			},
+		},
+		startup.Step{
+			Name:    "workers",
+			Enabled: startupCfg.StartWorkersEnabled(),
+			Run: func(ctx context.Context) error {
+				go workerService.StartWorkers(appCtx, numWorkers)
+				staleJobReaper.Start(appCtx)
+				proxyMgr.StartQuarantineRechecks(appCtx, proxyQuarantineCooldown)
+				apiHandler.PersonaTests.Start(appCtx)
+				apiHandler.ProxyHealth.Start(appCtx)
+				campaignAutoRetrier.Start(appCtx)
+				campaignAutoPromoter.Start(appCtx)
+				campaignScheduler.Start(appCtx)
+				resultWebhookStreamer.Start(appCtx)
+				campaignWebhookSvc.Start(appCtx)
+				return nil
+			},
+		},
+	)
+	healthCheckHandler.SetReadinessGate(startupSequencer)
+	if startupCfg.StartWorkersEnabled() {
+		healthCheckHandler.SetWorkerHealth(workerService, appConfig.Worker.HealthInReadinessEnabled())
+	}
 
 	gin.SetMode(appConfig.Server.GinMode)
-	router := gin.Default()
+	api.SetStrictJSONDecoding(appConfig.Server.StrictJSONDecoding)
+	api.SetMaxPageSize(appConfig.Server.MaxPageSize)
+	api.SetSoftDeadlines(appConfig.Server.SoftDeadlinesMs)
+	router := gin.New()
+	// Request logs are written as JSON by RequestLogging in place of gin's text logger
+	router.Use(middleware.RequestLogging(), gin.Recovery())
+	if appConfig.Compression.CompressionEnabled() {
+		router.Use(middleware.Compression(appConfig.Compression))
+	}
+	if tracingEnabled {
+		// Handlers that pass the gin context to services then carry the request span too
+		router.ContextWithFallback = true
+		router.Use(middleware.Tracing())
+	}
 
 	// Apply basic security middleware to all routes
 	router.Use(securityMiddleware.SecurityHeaders())
@@ -300,74 +569,158 @@ func main() {
 		authRoutes.POST("/login", rateLimitMiddleware.LoginRateLimit(), authHandler.Login)
 		authRoutes.POST("/logout", authHandler.Logout)
 		authRoutes.POST("/refresh", authHandler.RefreshSession)
+		authRoutes.GET("/password-policy", authHandler.GetPasswordPolicy)
 	}
 	log.Println("Registered authentication routes under /api/v2/auth")
 
 	// Register health check routes
 	api.RegisterHealthCheckRoutes(router, healthCheckHandler)
-	log.Println("Registered health check routes: /health, /health/ready, /health/live")
+	log.Println("Registered health check routes: /health, /health/ready, /health/live, /readyz")
 
-	log.Println("Authentication configured for session-only (offline mode)")
+	// Prometheus metrics, for internal networks or scrapers holding the metrics token
+	router.GET("/metrics", metricsAccess, gin.WrapH(metrics.Handler()))
+	log.Println("Registered metrics route: /metrics")
 
-	// Protected routes with session authentication only
+	log.Println("Authentication configured for sessions and API keys")
+
+	// Protected routes with session or API key authentication
 	apiV2 := router.Group("/api/v2")
-	apiV2.Use(authMiddleware.SessionAuth())
+	apiV2.Use(authMiddleware.DualAuth())
 	apiV2.Use(securityMiddleware.SessionProtection()) // Session-based protection for session-based requests
 	{
 		// Admin user management routes
 		adminRoutes := apiV2.Group("/admin")
 		adminRoutes.Use(authMiddleware.RequirePermission("admin:users"))
 		{
-			adminRoutes.GET("/users", apiHandler.ListUsersGin)
 			adminRoutes.POST("/users", apiHandler.CreateUserGin)
 			adminRoutes.GET("/users/:userId", apiHandler.GetUserGin)
 			adminRoutes.PUT("/users/:userId", apiHandler.UpdateUserGin)
 			adminRoutes.DELETE("/users/:userId", apiHandler.DeleteUserGin)
 		}
 
+		// Admin data-subject request routes (export and erasure of a user's personal data)
+		userDataRoutes := apiV2.Group("/admin/users")
+		userDataRoutes.Use(authMiddleware.RequirePermission("system:users"))
+		{
+			userDataRoutes.GET("/:userId/data-export", userDataAPIHandler.ExportUserDataGin)
+			userDataRoutes.DELETE("/:userId/personal-data", userDataAPIHandler.EraseUserDataGin)
+		}
+
+		// Admin audit log routes (queries and streaming compliance exports)
+		apiV2.GET("/admin/audit-logs", authMiddleware.RequirePermission("system:audit"), auditLogAPIHandler.ListAuditLogsGin)
+
+		// Admin account routes: listing, unlocking, disabling and reactivating accounts
+		userAccountAPIHandler.RegisterUserAccountRoutes(apiV2.Group("/admin/users"), authMiddleware)
+
+		// Admin webhook event delivery routes
+		eventAdminRoutes := apiV2.Group("/admin/events")
+		eventAdminRoutes.Use(authMiddleware.RequirePermission("system:admin"))
+		{
+			eventAdminRoutes.GET("", eventDeliveryAPIHandler.ListEventDeliveriesGin)
+			eventAdminRoutes.POST("/:id/replay", eventDeliveryAPIHandler.ReplayEventDeliveryGin)
+		}
+
+		// Admin dead letter job routes
+		jobAdminRoutes := apiV2.Group("/admin/jobs")
+		jobAdminRoutes.Use(authMiddleware.RequirePermission("system:admin"))
+		{
+			jobAdminRoutes.GET("/dead-letter", deadLetterJobAPIHandler.ListDeadLetterJobsGin)
+			jobAdminRoutes.POST("/:id/replay", deadLetterJobAPIHandler.ReplayDeadLetterJobGin)
+		}
+
+		// Admin role maintenance routes
+		roleAdminRoutes := apiV2.Group("/admin/roles")
+		roleAdminRoutes.Use(authMiddleware.RequirePermission("system:admin"))
+		{
+			roleAdminRoutes.POST("/sync-permissions", adminRoleAPIHandler.SyncRolePermissionsGin)
+		}
+
+		// Admin password migration routes
+		passwordAdminRoutes := apiV2.Group("/admin/passwords")
+		passwordAdminRoutes.Use(authMiddleware.RequirePermission("system:admin"))
+		{
+			passwordAdminRoutes.GET("/legacy-peppers", adminPasswordMigrationAPIHandler.GetLegacyPepperReportGin)
+			passwordAdminRoutes.POST("/legacy-peppers/flag", adminPasswordMigrationAPIHandler.FlagLegacyPepperUsersGin)
+		}
+
+		// Admin runtime settings routes
+		settingsAdminRoutes := apiV2.Group("/admin/settings")
+		settingsAdminRoutes.Use(authMiddleware.RequirePermission("system:admin"))
+		{
+			settingsAdminRoutes.GET("", settingsAPIHandler.ListSettingsGin)
+			settingsAdminRoutes.PUT("", settingsAPIHandler.UpdateSettingsGin)
+		}
+
+		// Admin TLD list routes
+		tldAdminRoutes := apiV2.Group("/admin/tlds")
+		tldAdminRoutes.Use(authMiddleware.RequirePermission("system:config"))
+		{
+			tldAdminRoutes.GET("", tldListAPIHandler.GetTLDListStatusGin)
+			tldAdminRoutes.POST("/refresh", tldListAPIHandler.RefreshTLDListGin)
+		}
+
+		// Admin login rate route
+		apiV2.GET("/admin/login-rates", authMiddleware.RequirePermission("system:admin"), authHandler.GetLoginRates)
+
+		// Admin effective configuration route
+		apiV2.GET("/admin/config", authMiddleware.RequirePermission("system:admin"), apiHandler.GetEffectiveConfigGin)
+
+		// Admin worker health route
+		apiV2.GET("/admin/workers/health", authMiddleware.RequirePermission("system:admin"), healthCheckHandler.HandleWorkerHealth)
+
 		// Current user routes (authenticated users)
 		apiV2.GET("/me", authHandler.Me)
 		apiV2.GET("/auth/permissions", authHandler.GetPermissions)  // New permissions endpoint
 		apiV2.POST("/change-password", authHandler.ChangePassword)
+		apiV2.GET("/auth/sessions", authHandler.ListSessions)
+		apiV2.DELETE("/auth/sessions/:id", authHandler.RevokeSession)
 
 		// Persona routes with permission-based access control
 		personaGroup := apiV2.Group("/personas")
 		{
 			// Unified persona endpoints (preferred)
-			personaGroup.GET("", authMiddleware.RequirePermission("personas:read"), apiHandler.ListAllPersonasGin)
+			// With ownership enforced, non-admin users only see and use their own and shared personas,
+			// and only change their own
+			scopePersonas := authMiddleware.ScopeToOwner("personas")
+			personaGroup.GET("", authMiddleware.RequirePermission("personas:read"), scopePersonas, apiHandler.ListAllPersonasGin)
 			personaGroup.POST("", authMiddleware.RequirePermission("personas:create"), apiHandler.CreatePersonaGin)
-			personaGroup.GET("/:id", authMiddleware.RequirePermission("personas:read"), apiHandler.GetPersonaByIDGin)
-			personaGroup.PUT("/:id", authMiddleware.RequirePermission("personas:update"), apiHandler.UpdatePersonaGin)
-			personaGroup.DELETE("/:id", authMiddleware.RequirePermission("personas:delete"), apiHandler.DeletePersonaGin)
-			personaGroup.POST("/:id/test", authMiddleware.RequirePermission("personas:read"), apiHandler.TestPersonaGin)
+			personaGroup.POST("/validate", authMiddleware.RequirePermission("personas:read"), apiHandler.ValidatePersonaGin)
+			personaGroup.GET("/:id", authMiddleware.RequirePermission("personas:read"), scopePersonas, apiHandler.GetPersonaByIDGin)
+			personaGroup.PUT("/:id", authMiddleware.RequirePermission("personas:update"), scopePersonas, apiHandler.UpdatePersonaGin)
+			personaGroup.DELETE("/:id", authMiddleware.RequirePermission("personas:delete"), scopePersonas, apiHandler.DeletePersonaGin)
+			personaGroup.POST("/:id/test", authMiddleware.RequirePermission("personas:read"), scopePersonas, limitPersonaTests, apiHandler.TestPersonaGin)
+			personaGroup.GET("/:id/test-history", authMiddleware.RequirePermission("personas:read"), scopePersonas, apiHandler.GetPersonaTestHistoryGin)
 
 			// Type-specific endpoints (backward compatibility)
 			dnsPersonaGroup := personaGroup.Group("/dns")
 			{
 				dnsPersonaGroup.POST("", authMiddleware.RequirePermission("personas:create"), apiHandler.CreateDNSPersonaGin)
-				dnsPersonaGroup.GET("", authMiddleware.RequirePermission("personas:read"), apiHandler.ListDNSPersonasGin)
-				dnsPersonaGroup.PUT("/:personaId", authMiddleware.RequirePermission("personas:update"), apiHandler.UpdateDNSPersonaGin)
-				dnsPersonaGroup.DELETE("/:personaId", authMiddleware.RequirePermission("personas:delete"), apiHandler.DeleteDNSPersonaGin)
+				dnsPersonaGroup.GET("", authMiddleware.RequirePermission("personas:read"), scopePersonas, apiHandler.ListDNSPersonasGin)
+				dnsPersonaGroup.PUT("/:personaId", authMiddleware.RequirePermission("personas:update"), scopePersonas, apiHandler.UpdateDNSPersonaGin)
+				dnsPersonaGroup.DELETE("/:personaId", authMiddleware.RequirePermission("personas:delete"), scopePersonas, apiHandler.DeleteDNSPersonaGin)
 			}
 			httpPersonaGroup := personaGroup.Group("/http")
 			{
 				httpPersonaGroup.POST("", authMiddleware.RequirePermission("personas:create"), apiHandler.CreateHTTPPersonaGin)
-				httpPersonaGroup.GET("", authMiddleware.RequirePermission("personas:read"), apiHandler.ListHTTPPersonasGin)
-				httpPersonaGroup.PUT("/:personaId", authMiddleware.RequirePermission("personas:update"), apiHandler.UpdateHTTPPersonaGin)
-				httpPersonaGroup.DELETE("/:personaId", authMiddleware.RequirePermission("personas:delete"), apiHandler.DeleteHTTPPersonaGin)
+				httpPersonaGroup.GET("", authMiddleware.RequirePermission("personas:read"), scopePersonas, apiHandler.ListHTTPPersonasGin)
+				httpPersonaGroup.PUT("/:personaId", authMiddleware.RequirePermission("personas:update"), scopePersonas, apiHandler.UpdateHTTPPersonaGin)
+				httpPersonaGroup.DELETE("/:personaId", authMiddleware.RequirePermission("personas:delete"), scopePersonas, apiHandler.DeleteHTTPPersonaGin)
 			}
 		}
 
 		// Proxy routes with permission-based access control
 		proxyGroup := apiV2.Group("/proxies")
 		{
-			proxyGroup.GET("", authMiddleware.RequirePermission("proxies:read"), apiHandler.ListProxiesGin)
+			// Proxies are scoped like personas
+			scopeProxies := authMiddleware.ScopeToOwner("proxies")
+			proxyGroup.GET("", authMiddleware.RequirePermission("proxies:read"), scopeProxies, apiHandler.ListProxiesGin)
 			proxyGroup.POST("", authMiddleware.RequirePermission("proxies:create"), apiHandler.AddProxyGin)
 			proxyGroup.GET("/status", authMiddleware.RequirePermission("proxies:read"), apiHandler.GetProxyStatusesGin)
-			proxyGroup.PUT("/:proxyId", authMiddleware.RequirePermission("proxies:update"), apiHandler.UpdateProxyGin)
-			proxyGroup.DELETE("/:proxyId", authMiddleware.RequirePermission("proxies:delete"), apiHandler.DeleteProxyGin)
-			proxyGroup.POST("/:proxyId/test", authMiddleware.RequirePermission("proxies:read"), apiHandler.TestProxyGin)
-			proxyGroup.POST("/:proxyId/health-check", authMiddleware.RequirePermission("proxies:read"), apiHandler.ForceCheckSingleProxyGin)
+			proxyGroup.PUT("/:proxyId", authMiddleware.RequirePermission("proxies:update"), scopeProxies, apiHandler.UpdateProxyGin)
+			proxyGroup.DELETE("/:proxyId", authMiddleware.RequirePermission("proxies:delete"), scopeProxies, apiHandler.DeleteProxyGin)
+			proxyGroup.POST("/:proxyId/test", authMiddleware.RequirePermission("proxies:read"), scopeProxies, apiHandler.TestProxyGin)
+			proxyGroup.POST("/:proxyId/check", authMiddleware.RequirePermission("proxies:read"), scopeProxies, apiHandler.CheckProxyGin)
+			proxyGroup.POST("/:proxyId/health-check", authMiddleware.RequirePermission("proxies:read"), scopeProxies, apiHandler.ForceCheckSingleProxyGin)
 			proxyGroup.POST("/health-check", authMiddleware.RequirePermission("proxies:read"), apiHandler.ForceCheckAllProxiesGin)
 		}
 
@@ -395,6 +748,9 @@ func main() {
 			keywordSetGroup.DELETE("/:setId", authMiddleware.RequirePermission("campaigns:delete"), apiHandler.DeleteKeywordSetGin)
 		}
 
+		// Keyword rule preview
+		apiV2.POST("/keyword-rules/test", authMiddleware.RequirePermission("campaigns:read"), apiHandler.TestKeywordRuleGin)
+
 		// Keyword extraction routes
 		extractGroup := apiV2.Group("/extract/keywords")
 		extractGroup.Use(authMiddleware.RequirePermission("campaigns:read"))
@@ -445,6 +801,12 @@ func main() {
 
 	log.Printf("Server starting on %s (Gin Mode: %s)", srv.Addr, appConfig.Server.GinMode)
 
+	go func() {
+		if err := startupSequencer.Run(appCtx); err != nil && appCtx.Err() == nil {
+			log.Fatalf("FATAL: %v", err)
+		}
+	}()
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
@@ -458,6 +820,22 @@ func main() {
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	sessionService.Stop()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Printf("Failed to flush traces: %v", err)
+	}
 
 	log.Println("Server and workers exited gracefully.")
 }
+
+// openPostgres opens a database handle, tracing its queries when tracing is enabled
+func openPostgres(dsn string, traced bool) (*sqlx.DB, error) {
+	if !traced {
+		return sqlx.Open(dbTypePostgres, dsn)
+	}
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return sqlx.NewDb(sql.OpenDB(tracing.WrapConnector(connector)), dbTypePostgres), nil
+}
@@ -1,24 +1,85 @@
 package main
 
 import (
-	"crypto/rand"
-	"encoding/hex"
+	"context"
+	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	pg_store "github.com/fntelecomllc/studio/backend/internal/store/postgres"
 )
 
 func main() {
+	var (
+		dsn       string
+		userID    string
+		label     string
+		scopes    string
+		expiresIn time.Duration
+	)
+
+	flag.StringVar(&dsn, "dsn", "", "PostgreSQL connection string (defaults to POSTGRES_DSN)")
+	flag.StringVar(&userID, "user", "", "ID of the user the key acts for (required)")
+	flag.StringVar(&label, "label", "", "Label identifying the key (required)")
+	flag.StringVar(&scopes, "scopes", "", "Comma-separated permissions granted to the key, e.g. campaigns:read,campaigns:create")
+	flag.DurationVar(&expiresIn, "expires-in", 0, "Lifetime of the key, e.g. 720h (default: no expiry)")
+	flag.Parse()
+
+	if dsn == "" {
+		dsn = os.Getenv("POSTGRES_DSN")
+	}
+	owner, err := uuid.Parse(userID)
+	if dsn == "" || err != nil || strings.TrimSpace(label) == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error connecting to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
 	// Generate a secure API key
-	bytes := make([]byte, 32) // 256-bit key
-	if _, err := rand.Read(bytes); err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating random bytes: %v\n", err)
+	keyService := services.NewAPIKeyService(nil)
+	apiKey, err := keyService.GenerateAPIKey()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating API key: %v\n", err)
 		os.Exit(1)
 	}
 
-	apiKey := hex.EncodeToString(bytes)
+	key := &models.APIKey{
+		UserID:  owner,
+		Label:   strings.TrimSpace(label),
+		KeyHash: services.HashAPIKey(apiKey),
+		Scopes:  []string{},
+	}
+	for _, scope := range strings.Split(scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			key.Scopes = append(key.Scopes, scope)
+		}
+	}
+	if expiresIn > 0 {
+		expiresAt := time.Now().UTC().Add(expiresIn)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := pg_store.NewAPIKeyStorePostgres(db).CreateAPIKey(context.Background(), nil, key); err != nil {
+		fmt.Fprintf(os.Stderr, "Error storing API key: %v\n", err)
+		os.Exit(1)
+	}
 
-	fmt.Println("Generated Secure API Key:")
+	fmt.Printf("Created API key %s (%s) with scopes [%s]\n", key.ID, key.Label, strings.Join(key.Scopes, ", "))
 	fmt.Println(apiKey)
-	fmt.Println("\nStore this key securely in your environment variables:")
-	fmt.Printf("export DOMAINFLOW_API_KEY=\"%s\"\n", apiKey)
+	fmt.Println("\nThe key is shown only once; only its hash is stored. Send it as:")
+	fmt.Printf("Authorization: Bearer %s\n", apiKey)
 }
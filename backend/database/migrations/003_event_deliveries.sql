@@ -0,0 +1,39 @@
+-- Migration: 003_event_deliveries.sql
+-- Purpose: Persist outbound webhook event deliveries and their attempts so failed deliveries can be replayed
+-- Date: 2026-10-16
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS public.event_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_type TEXT NOT NULL,
+    target_url TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_status_code INTEGER,
+    last_error TEXT,
+    last_attempted_at TIMESTAMPTZ,
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_deliveries_status ON public.event_deliveries(status);
+CREATE INDEX IF NOT EXISTS idx_event_deliveries_event_type ON public.event_deliveries(event_type);
+CREATE INDEX IF NOT EXISTS idx_event_deliveries_created_at ON public.event_deliveries(created_at DESC);
+
+CREATE TABLE IF NOT EXISTS public.event_delivery_attempts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    delivery_id UUID NOT NULL REFERENCES public.event_deliveries(id) ON DELETE CASCADE,
+    attempt_number INTEGER NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    is_replay BOOLEAN NOT NULL DEFAULT FALSE,
+    attempted_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_delivery_attempts_delivery_id ON public.event_delivery_attempts(delivery_id);
+
+COMMIT;
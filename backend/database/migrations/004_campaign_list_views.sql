@@ -0,0 +1,21 @@
+-- Migration: 004_campaign_list_views.sql
+-- Purpose: Persist named per-user campaign list views (saved filters and sort options)
+-- Date: 2026-10-16
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS public.campaign_list_views (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT '',
+    campaign_type TEXT NOT NULL DEFAULT '',
+    sort_by TEXT NOT NULL DEFAULT '',
+    sort_order TEXT NOT NULL DEFAULT '',
+    page_size INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_campaign_list_views_user_name UNIQUE (user_id, name)
+);
+
+COMMIT;
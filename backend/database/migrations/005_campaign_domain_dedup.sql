@@ -0,0 +1,34 @@
+-- Migration: 005_campaign_domain_dedup.sql
+-- Purpose: Optional per-campaign domain deduplication for DNS and HTTP keyword validation,
+--          with a record of every dedup decision
+-- Date: 2026-10-16
+
+BEGIN;
+
+ALTER TABLE public.dns_validation_params
+    ADD COLUMN IF NOT EXISTS deduplicate_domains BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE public.http_keyword_campaign_params
+    ADD COLUMN IF NOT EXISTS deduplicate_domains BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS public.campaign_domain_dedup_decisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL REFERENCES public.campaigns(id) ON DELETE CASCADE,
+    normalized_domain TEXT NOT NULL,
+    domain_name TEXT NOT NULL,
+    source_item_id UUID NOT NULL,
+    kept_item_id UUID NOT NULL,
+    decision TEXT NOT NULL CHECK (decision IN ('kept', 'duplicate')),
+    decided_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_campaign_domain_dedup_source_item UNIQUE (campaign_id, source_item_id)
+);
+
+-- Exactly one item is kept per normalized domain within a campaign
+CREATE UNIQUE INDEX IF NOT EXISTS uq_campaign_domain_dedup_kept
+    ON public.campaign_domain_dedup_decisions (campaign_id, normalized_domain)
+    WHERE decision = 'kept';
+
+CREATE INDEX IF NOT EXISTS idx_campaign_domain_dedup_decisions_campaign
+    ON public.campaign_domain_dedup_decisions (campaign_id, decided_at);
+
+COMMIT;
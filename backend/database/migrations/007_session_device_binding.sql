@@ -0,0 +1,11 @@
+-- Migration: 007_session_device_binding.sql
+-- Purpose: Store the device public key a session is bound to for signed-request verification
+-- Date: 2026-10-16
+
+BEGIN;
+
+ALTER TABLE auth.sessions ADD COLUMN IF NOT EXISTS device_public_key TEXT;
+
+COMMENT ON COLUMN auth.sessions.device_public_key IS 'Base64 SPKI public key supplied at login; requests on a bound session must be signed with the matching private key';
+
+COMMIT;
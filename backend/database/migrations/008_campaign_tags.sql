@@ -0,0 +1,14 @@
+-- Migration: 008_campaign_tags.sql
+-- Purpose: Free-form campaign tags (project, client, environment) with an index for tag filtering
+-- Date: 2026-10-16
+
+BEGIN;
+
+ALTER TABLE public.campaigns ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+-- GIN supports both the overlap (&&, match any) and containment (@>, match all) operators
+CREATE INDEX IF NOT EXISTS idx_campaigns_tags ON public.campaigns USING GIN (tags);
+
+COMMENT ON COLUMN public.campaigns.tags IS 'Lower-cased, de-duplicated labels used to organise and filter campaigns';
+
+COMMIT;
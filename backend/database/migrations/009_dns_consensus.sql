@@ -0,0 +1,17 @@
+-- Migration: 009_dns_consensus.sql
+-- Purpose: Optional multi-resolver consensus for DNS validation campaigns, with each
+--          result's per-resolver answers and disagreements
+-- Date: 2026-10-16
+
+BEGIN;
+
+ALTER TABLE public.dns_validation_params
+    ADD COLUMN IF NOT EXISTS consensus_mode TEXT NOT NULL DEFAULT ''
+        CHECK (consensus_mode IN ('', 'first', 'majority', 'unanimous')),
+    ADD COLUMN IF NOT EXISTS consensus_quorum INT NOT NULL DEFAULT 0
+        CHECK (consensus_quorum >= 0);
+
+ALTER TABLE public.dns_validation_results
+    ADD COLUMN IF NOT EXISTS consensus JSONB;
+
+COMMIT;
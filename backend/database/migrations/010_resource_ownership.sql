@@ -0,0 +1,19 @@
+-- Migration: 010_resource_ownership.sql
+-- Purpose: Personas and proxies belong to the user who created them and are either private to
+--          that user or shared with everyone. Existing rows have no owner and stay shared.
+-- Date: 2026-10-16
+
+BEGIN;
+
+ALTER TABLE public.personas
+    ADD COLUMN IF NOT EXISTS owner_id UUID REFERENCES auth.users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS shared BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE public.proxies
+    ADD COLUMN IF NOT EXISTS owner_id UUID REFERENCES auth.users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS shared BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE INDEX IF NOT EXISTS idx_personas_owner_id ON public.personas(owner_id);
+CREATE INDEX IF NOT EXISTS idx_proxies_owner_id ON public.proxies(owner_id);
+
+COMMIT;
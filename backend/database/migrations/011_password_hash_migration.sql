@@ -0,0 +1,14 @@
+-- Migration: 011_password_hash_migration.sql
+-- Purpose: Flag accounts whose stored password hash uses a scheme or pepper version the login
+--          path cannot verify, so admins can find them and trigger a reset or rehash.
+-- Date: 2026-10-16
+
+BEGIN;
+
+ALTER TABLE auth.users
+    ADD COLUMN IF NOT EXISTS password_migration_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_users_password_migration_required
+    ON auth.users(id) WHERE password_migration_required;
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- Migration: 012_campaign_job_priority.sql
+-- Purpose: Give campaign jobs a base fetch priority. Workers fetch the highest effective priority
+--          first, where waiting time raises a job's priority (worker.priorityAgingIntervalSeconds)
+--          so low-priority jobs are not starved.
+-- Date: 2026-10-16
+
+BEGIN;
+
+ALTER TABLE campaign_jobs
+    ADD COLUMN IF NOT EXISTS priority INT NOT NULL DEFAULT 0;
+
+COMMIT;
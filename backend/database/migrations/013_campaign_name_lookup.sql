@@ -0,0 +1,11 @@
+-- Migration: 013_campaign_name_lookup.sql
+-- Purpose: Index campaigns by user and case-folded name for the unique campaign name check
+--          (resourceAccess.uniqueCampaignNames). The index is not unique, because the rule is
+--          optional and existing data may already hold duplicates.
+-- Date: 2026-10-16
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_user_lower_name ON campaigns(user_id, lower(name));
+
+COMMIT;
@@ -0,0 +1,18 @@
+-- Migration: 014_session_metrics.sql
+-- Purpose: Keep cumulative session metrics (sessions created, cleanups, security events and cache
+--          evictions) across restarts. Each instance adds its growth since its last snapshot
+--          (session.metrics_snapshot_interval) and at shutdown, and restores the totals at start.
+-- Date: 2026-10-16
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS auth.session_metrics (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1), -- Single row
+    total_sessions BIGINT NOT NULL DEFAULT 0,
+    cleanup_count BIGINT NOT NULL DEFAULT 0,
+    security_events BIGINT NOT NULL DEFAULT 0,
+    cache_evictions BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- Migration: 015_campaign_result_domain_order.sql
+-- Purpose: Index DNS and HTTP keyword results by campaign and byte-ordered ("C" collation) domain
+--          name, so campaign comparisons can page through both result sets by keyset.
+-- Date: 2026-10-16
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_dns_results_campaign_domain_c ON dns_validation_results(dns_campaign_id, domain_name COLLATE "C");
+CREATE INDEX IF NOT EXISTS idx_http_results_campaign_domain_c ON http_keyword_results(http_keyword_campaign_id, domain_name COLLATE "C");
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- Migration: 016_campaign_auto_retry.sql
+-- Purpose: Let a failed campaign be re-run automatically as a new campaign. auto_retry opts a
+--          campaign in, retry_of_campaign_id links a retry run to the run it retries and
+--          retry_count numbers the runs of a chain. A run is retried at most once.
+-- Date: 2026-10-16
+
+BEGIN;
+
+ALTER TABLE campaigns
+    ADD COLUMN IF NOT EXISTS auto_retry BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS retry_count INT NOT NULL DEFAULT 0 CHECK (retry_count >= 0),
+    ADD COLUMN IF NOT EXISTS retry_of_campaign_id UUID REFERENCES campaigns(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaigns_retry_of ON campaigns(retry_of_campaign_id) WHERE retry_of_campaign_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_campaigns_failed_auto_retry ON campaigns(updated_at) WHERE status = 'failed' AND auto_retry;
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- Migration: 017_dns_expected_records.sql
+-- Purpose: Optional per-campaign expected DNS records (CIDRs for A/AAAA, suffixes for the CNAME
+--          target) and each resolved result's classification against them
+-- Date: 2026-10-16
+
+BEGIN;
+
+ALTER TABLE public.dns_validation_params
+    ADD COLUMN IF NOT EXISTS expected_records JSONB;
+
+ALTER TABLE public.dns_validation_results
+    ADD COLUMN IF NOT EXISTS expectation TEXT NOT NULL DEFAULT ''
+        CHECK (expectation IN ('', 'matched_expected', 'unexpected'));
+
+COMMIT;
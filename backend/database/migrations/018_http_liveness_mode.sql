@@ -0,0 +1,12 @@
+-- Migration: 018_http_liveness_mode.sql
+-- Purpose: Optional liveness-only mode for HTTP keyword campaigns, which classify results on
+--          reachability and status code alone (http_valid / http_invalid) without keyword scanning
+-- Date: 2026-10-16
+
+BEGIN;
+
+ALTER TABLE public.http_keyword_campaign_params
+    ADD COLUMN IF NOT EXISTS validation_mode TEXT NOT NULL DEFAULT 'keywords'
+        CHECK (validation_mode IN ('keywords', 'liveness_only'));
+
+COMMIT;
@@ -0,0 +1,19 @@
+-- Migration: 019_campaign_dns_promotion.sql
+-- Purpose: Let a domain generation campaign hand its output to a DNS validation campaign when it
+--          completes. dns_promotion holds the DNS campaign to create (personas and settings) and
+--          promoted_from_campaign_id links that campaign to the generation campaign it validates.
+--          A generation campaign is promoted at most once.
+-- Date: 2026-10-16
+
+BEGIN;
+
+ALTER TABLE domain_generation_campaign_params
+    ADD COLUMN IF NOT EXISTS dns_promotion JSONB;
+
+ALTER TABLE campaigns
+    ADD COLUMN IF NOT EXISTS promoted_from_campaign_id UUID REFERENCES campaigns(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaigns_promoted_from ON campaigns(promoted_from_campaign_id) WHERE promoted_from_campaign_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_domain_generation_params_dns_promotion ON domain_generation_campaign_params(campaign_id) WHERE dns_promotion IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,25 @@
+-- Migration: 021_campaign_webhooks.sql
+-- Purpose: Webhooks registered on a campaign, notified with a payload signed by their own secret
+--          once the campaign completes, fails or is cancelled
+-- Date: 2026-10-16
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS public.campaign_webhooks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL REFERENCES public.campaigns(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    created_by UUID REFERENCES auth.users(id) ON DELETE SET NULL,
+    notified_status TEXT,
+    notified_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_webhooks_campaign_id ON public.campaign_webhooks(campaign_id);
+
+-- Deliveries to a campaign webhook are signed with that webhook's secret, including replays.
+-- There is no foreign key so a delivery outlives its webhook; it can no longer be replayed then.
+ALTER TABLE public.event_deliveries ADD COLUMN IF NOT EXISTS campaign_webhook_id UUID;
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- Migration: 022_keyword_match_type.sql
+-- Purpose: How string keywords match page content - as a substring, a regular expression or a
+--          whole word - per keyword set (for its string rules) and per HTTP keyword campaign
+--          (for its ad hoc keywords)
+-- Date: 2026-10-16
+
+BEGIN;
+
+ALTER TABLE public.keyword_sets
+    ADD COLUMN IF NOT EXISTS match_type TEXT NOT NULL DEFAULT 'substring'
+        CHECK (match_type IN ('substring', 'regex', 'word_boundary'));
+
+ALTER TABLE public.http_keyword_campaign_params
+    ADD COLUMN IF NOT EXISTS match_type TEXT NOT NULL DEFAULT 'substring'
+        CHECK (match_type IN ('substring', 'regex', 'word_boundary'));
+
+COMMIT;
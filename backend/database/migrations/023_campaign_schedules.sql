@@ -0,0 +1,27 @@
+-- Migration: 023_campaign_schedules.sql
+-- Purpose: Schedules that start a pending campaign at a set time, or start a clone of a campaign
+--          each time a cron expression fires
+-- Date: 2026-10-16
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS public.campaign_schedules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL UNIQUE REFERENCES public.campaigns(id) ON DELETE CASCADE,
+    run_at TIMESTAMPTZ,
+    cron_expression TEXT,
+    next_run_at TIMESTAMPTZ,
+    locked_until TIMESTAMPTZ,
+    last_run_at TIMESTAMPTZ,
+    last_campaign_id UUID,
+    last_error TEXT,
+    created_by UUID REFERENCES auth.users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_campaign_schedules_kind CHECK ((run_at IS NULL) <> (cron_expression IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_schedules_next_run_at ON public.campaign_schedules(next_run_at)
+    WHERE next_run_at IS NOT NULL;
+
+COMMIT;
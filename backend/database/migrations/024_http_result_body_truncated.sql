@@ -0,0 +1,11 @@
+-- Migration: 024_http_result_body_truncated.sql
+-- Purpose: Record whether an HTTP keyword result's response body was cut off at its persona's
+--          maxBodyBytes, in which case keywords were matched on the part read
+-- Date: 2026-10-16
+
+BEGIN;
+
+ALTER TABLE public.http_keyword_results
+    ADD COLUMN IF NOT EXISTS body_truncated BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
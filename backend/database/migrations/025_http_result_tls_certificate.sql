@@ -0,0 +1,11 @@
+-- Migration: 025_http_result_tls_certificate.sql
+-- Purpose: Record the TLS version, cipher suite and certificate issuer, subject and expiry of
+--          HTTPS responses, for HTTP keyword campaigns whose persona captures TLS
+-- Date: 2026-10-16
+
+BEGIN;
+
+ALTER TABLE public.http_keyword_results
+    ADD COLUMN IF NOT EXISTS tls_certificate JSONB;
+
+COMMIT;
@@ -0,0 +1,32 @@
+-- Migration: 026_campaign_jobs_dead_letter.sql
+-- Purpose: Keep the error of every failed attempt at a campaign job, and move jobs that failed on
+--          every attempt they were allowed out of the queue into a dead letter table, from which
+--          operators can replay them
+-- Date: 2026-10-16
+
+BEGIN;
+
+ALTER TABLE public.campaign_jobs
+    ADD COLUMN IF NOT EXISTS attempt_history JSONB;
+
+CREATE TABLE IF NOT EXISTS public.campaign_jobs_dead_letter (
+    id UUID PRIMARY KEY,
+    campaign_id UUID NOT NULL REFERENCES public.campaigns(id) ON DELETE CASCADE,
+    job_type TEXT NOT NULL,
+    priority INT NOT NULL DEFAULT 0,
+    job_payload JSONB,
+    attempts INT NOT NULL,
+    max_attempts INT NOT NULL,
+    last_error TEXT NOT NULL,
+    attempt_history JSONB,
+    processing_server_id TEXT,
+    job_created_at TIMESTAMPTZ NOT NULL,
+    dead_lettered_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_jobs_dead_letter_dead_lettered_at
+    ON public.campaign_jobs_dead_letter(dead_lettered_at DESC);
+CREATE INDEX IF NOT EXISTS idx_campaign_jobs_dead_letter_campaign_id
+    ON public.campaign_jobs_dead_letter(campaign_id);
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- Migration: 027_campaign_jobs_heartbeat.sql
+-- Purpose: Record when the worker processing a campaign job last reported it alive, so jobs left in
+--          processing by a worker that died can be found and requeued
+-- Date: 2026-10-16
+
+BEGIN;
+
+ALTER TABLE public.campaign_jobs
+    ADD COLUMN IF NOT EXISTS last_heartbeat_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_campaign_jobs_processing_heartbeat
+    ON public.campaign_jobs(last_heartbeat_at)
+    WHERE status = 'processing';
+
+COMMIT;
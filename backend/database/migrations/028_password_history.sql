@@ -0,0 +1,19 @@
+-- Migration: 028_password_history.sql
+-- Purpose: Keep the previous password hashes of each user so a password change cannot go back to a
+--          recently used password
+-- Date: 2026-10-16
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS auth.password_history (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    password_pepper_version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_history_user_created
+    ON auth.password_history(user_id, created_at DESC);
+
+COMMIT;
@@ -31,11 +31,14 @@ CREATE TABLE IF NOT EXISTS auth.users (
     last_login_ip INET,
     password_changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     must_change_password BOOLEAN DEFAULT FALSE,
+    password_migration_required BOOLEAN NOT NULL DEFAULT FALSE, -- Stored hash cannot be verified by the login path
     mfa_enabled BOOLEAN NOT NULL DEFAULT FALSE,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE INDEX IF NOT EXISTS idx_users_password_migration_required ON auth.users(id) WHERE password_migration_required;
+
 -- Sessions table - Updated for session-based authentication (no CSRF tokens)
 CREATE TABLE IF NOT EXISTS auth.sessions (
     id VARCHAR(128) PRIMARY KEY,                    -- Secure random session ID
@@ -49,7 +52,8 @@ CREATE TABLE IF NOT EXISTS auth.sessions (
     is_active BOOLEAN DEFAULT TRUE,                 -- Session state
     expires_at TIMESTAMP NOT NULL,                  -- Hard expiration
     last_activity_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Idle timeout tracking
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    device_public_key TEXT                          -- Device key the session is bound to, if any
 );
 
 -- Optimized indexes for session performance
@@ -64,6 +68,30 @@ CREATE INDEX IF NOT EXISTS idx_sessions_validation ON auth.sessions(id, is_activ
 -- This index was removed because it uses the non-immutable function NOW(), which is not allowed in index predicates.
 -- CREATE INDEX IF NOT EXISTS idx_sessions_cleanup ON auth.sessions(is_active, expires_at) WHERE is_active = false OR expires_at < NOW();
 
+-- API keys for machine clients, stored by hash with the permissions they grant
+CREATE TABLE IF NOT EXISTS auth.api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    label VARCHAR(100) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,           -- SHA-256 hash of the key; the key itself is never stored
+    scopes TEXT[] NOT NULL DEFAULT '{}',            -- Permissions granted to requests made with the key
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON auth.api_keys(user_id);
+
+-- Cumulative session metrics, kept across restarts
+CREATE TABLE IF NOT EXISTS auth.session_metrics (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1), -- Single row
+    total_sessions BIGINT NOT NULL DEFAULT 0,
+    cleanup_count BIGINT NOT NULL DEFAULT 0,
+    security_events BIGINT NOT NULL DEFAULT 0,
+    cache_evictions BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
 -- Roles table
 CREATE TABLE IF NOT EXISTS auth.roles (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -128,6 +156,17 @@ CREATE TABLE IF NOT EXISTS auth.password_reset_tokens (
 CREATE INDEX IF NOT EXISTS idx_password_reset_user_id ON auth.password_reset_tokens(user_id);
 CREATE INDEX IF NOT EXISTS idx_password_reset_expires ON auth.password_reset_tokens(expires_at);
 
+-- Previous password hashes, so a password change cannot reuse a recent password
+CREATE TABLE IF NOT EXISTS auth.password_history (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    password_pepper_version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_history_user_created ON auth.password_history(user_id, created_at DESC);
+
 -- Authentication audit log - Enhanced for session-based security
 CREATE TABLE IF NOT EXISTS auth.auth_audit_log (
     id BIGSERIAL PRIMARY KEY,
@@ -200,14 +239,45 @@ CREATE TABLE IF NOT EXISTS campaigns (
     -- Timestamp of when the campaign processing completed or was terminated.
     completed_at TIMESTAMPTZ,
     -- Stores the last error message if the campaign failed or encountered a critical error.
-    error_message TEXT
+    error_message TEXT,
+    -- Lower-cased, de-duplicated labels (project, client, environment) used to organise and filter campaigns.
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    -- Re-run the campaign as a new campaign when it fails, up to campaignRetry.maxRetries times.
+    auto_retry BOOLEAN NOT NULL DEFAULT FALSE,
+    -- 0 for an original run; a retry run has its predecessor's count plus one.
+    retry_count INT NOT NULL DEFAULT 0 CHECK (retry_count >= 0),
+    -- The failed run this campaign retries.
+    retry_of_campaign_id UUID REFERENCES campaigns(id) ON DELETE SET NULL,
+    -- The domain generation campaign whose completion created this DNS validation campaign.
+    promoted_from_campaign_id UUID REFERENCES campaigns(id) ON DELETE SET NULL
 );
 
 CREATE INDEX IF NOT EXISTS idx_campaigns_status ON campaigns(status);
+CREATE INDEX IF NOT EXISTS idx_campaigns_tags ON campaigns USING GIN (tags);
 CREATE INDEX IF NOT EXISTS idx_campaigns_type ON campaigns(campaign_type);
 CREATE INDEX IF NOT EXISTS idx_campaigns_user_id ON campaigns(user_id);
+CREATE INDEX IF NOT EXISTS idx_campaigns_user_lower_name ON campaigns(user_id, lower(name));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaigns_retry_of ON campaigns(retry_of_campaign_id) WHERE retry_of_campaign_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_campaigns_failed_auto_retry ON campaigns(updated_at) WHERE status = 'failed' AND auto_retry;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaigns_promoted_from ON campaigns(promoted_from_campaign_id) WHERE promoted_from_campaign_id IS NOT NULL;
 CREATE INDEX IF NOT EXISTS idx_campaigns_created_at ON campaigns(created_at DESC);
 
+-- Campaign List Views Table: Named per-user campaign list views, saving filters and sort options.
+CREATE TABLE IF NOT EXISTS campaign_list_views (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    -- Saved filters and sort options; empty strings and 0 leave the list default.
+    status TEXT NOT NULL DEFAULT '',
+    campaign_type TEXT NOT NULL DEFAULT '',
+    sort_by TEXT NOT NULL DEFAULT '',
+    sort_order TEXT NOT NULL DEFAULT '',
+    page_size INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_campaign_list_views_user_name UNIQUE (user_id, name)
+);
+
 -- Domain Generation Parameters Table - stores specific parameters for domain generation campaigns.
 CREATE TABLE IF NOT EXISTS domain_generation_campaign_params (
     -- Foreign key referencing the 'campaigns' table. This is also the primary key for this table, ensuring a one-to-one relationship.
@@ -228,8 +298,11 @@ CREATE TABLE IF NOT EXISTS domain_generation_campaign_params (
     -- The total number of unique domain combinations possible with the given parameters.
     total_possible_combinations BIGINT NOT NULL,
     -- The current offset in the generation sequence, used for resuming or batching.
-    current_offset BIGINT NOT NULL DEFAULT 0
+    current_offset BIGINT NOT NULL DEFAULT 0,
+    -- The DNS validation campaign to create and start when this campaign completes, NULL for none.
+    dns_promotion JSONB
 );
+CREATE INDEX IF NOT EXISTS idx_domain_generation_params_dns_promotion ON domain_generation_campaign_params(campaign_id) WHERE dns_promotion IS NOT NULL;
 
 -- Generated Domains Table: Stores individual domain names generated by domain generation campaigns.
 CREATE TABLE IF NOT EXISTS generated_domains (
@@ -289,6 +362,10 @@ CREATE TABLE IF NOT EXISTS personas (
     config_details JSONB NOT NULL,
     -- Flag indicating whether this persona is currently active and can be used in campaigns. Defaults to TRUE.
     is_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    -- User who created the persona. NULL for personas that predate ownership.
+    owner_id UUID REFERENCES auth.users(id) ON DELETE SET NULL,
+    -- Whether users other than the owner may use this persona in campaigns.
+    shared BOOLEAN NOT NULL DEFAULT TRUE,
     -- Timestamp of when the persona record was created.
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     -- Timestamp of when the persona record was last updated. Automatically updated by a trigger.
@@ -299,6 +376,7 @@ CREATE TABLE IF NOT EXISTS personas (
 
 CREATE INDEX IF NOT EXISTS idx_personas_type ON personas(persona_type);
 CREATE INDEX IF NOT EXISTS idx_personas_is_enabled ON personas(is_enabled);
+CREATE INDEX IF NOT EXISTS idx_personas_owner_id ON personas(owner_id);
 
 -- Keyword Sets Table: Stores collections of keywords that can be used in HTTP keyword validation campaigns.
 CREATE TABLE IF NOT EXISTS keyword_sets (
@@ -312,6 +390,8 @@ CREATE TABLE IF NOT EXISTS keyword_sets (
     rules JSONB NOT NULL DEFAULT '[]'::jsonb,
     -- Flag indicating whether this keyword set is currently active and can be used. Defaults to TRUE.
     is_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    -- How the set's string rules match page content: as a substring, a regular expression or a whole word. Regex rules always match as written.
+    match_type TEXT NOT NULL DEFAULT 'substring' CHECK (match_type IN ('substring', 'regex', 'word_boundary')),
     -- Timestamp of when the keyword set record was created.
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     -- Timestamp of when the keyword set record was last updated. Automatically updated by a trigger.
@@ -336,6 +416,14 @@ CREATE TABLE IF NOT EXISTS dns_validation_params (
     batch_size INT DEFAULT 50 CHECK (batch_size > 0),
     -- Number of times to retry validation for a domain if it fails.
     retry_attempts INT DEFAULT 1 CHECK (retry_attempts >= 0),
+    -- How the answers of the campaign's resolvers are combined: '' or 'first' takes the first answer, 'majority' and 'unanimous' ask every resolver.
+    consensus_mode TEXT NOT NULL DEFAULT '' CHECK (consensus_mode IN ('', 'first', 'majority', 'unanimous')),
+    -- Number of agreeing resolvers a 'majority' result needs; 0 means more than half.
+    consensus_quorum INT NOT NULL DEFAULT 0 CHECK (consensus_quorum >= 0),
+    -- Optional records resolved domains are expected to have: {"aCidrs": [...], "aaaaCidrs": [...], "cnameSuffixes": [...]}.
+    expected_records JSONB,
+    -- Validate each normalized domain only once per campaign; the decisions are kept in campaign_domain_dedup_decisions.
+    deduplicate_domains BOOLEAN NOT NULL DEFAULT FALSE,
     -- Flexible JSONB field for any additional DNS validation-specific metadata.
     metadata JSONB
 );
@@ -356,6 +444,10 @@ CREATE TABLE IF NOT EXISTS dns_validation_results (
     validation_status TEXT NOT NULL,
     -- JSONB field to store the DNS records found for the domain, if any (e.g., A, MX, CNAME records).
     dns_records JSONB,
+    -- Whether a resolved domain matched the campaign's expected records ('matched_expected' or 'unexpected'); empty when not classified.
+    expectation TEXT NOT NULL DEFAULT '' CHECK (expectation IN ('', 'matched_expected', 'unexpected')),
+    -- Each resolver's answer and whether they disagreed, for campaigns validated by consensus.
+    consensus JSONB,
     -- Optional foreign key to the 'personas' table, indicating which DNS persona was used for this specific validation attempt.
     -- If the referenced persona is deleted, this field will be set to NULL (ON DELETE SET NULL).
     validated_by_persona_id UUID REFERENCES personas(id) ON DELETE SET NULL,
@@ -372,6 +464,7 @@ CREATE TABLE IF NOT EXISTS dns_validation_results (
 CREATE INDEX IF NOT EXISTS idx_dns_results_campaign_id ON dns_validation_results(dns_campaign_id);
 CREATE INDEX IF NOT EXISTS idx_dns_results_domain_name ON dns_validation_results(domain_name);
 CREATE INDEX IF NOT EXISTS idx_dns_results_status ON dns_validation_results(validation_status);
+CREATE INDEX IF NOT EXISTS idx_dns_results_campaign_domain_c ON dns_validation_results(dns_campaign_id, domain_name COLLATE "C");
 
 -- HTTP Keyword Campaign Parameters Table: Stores parameters specific to HTTP keyword validation campaigns.
 CREATE TABLE IF NOT EXISTS http_keyword_campaign_params (
@@ -389,6 +482,10 @@ CREATE TABLE IF NOT EXISTS http_keyword_campaign_params (
     keyword_set_ids UUID[],
     -- Array of ad-hoc keywords or phrases to search for, in addition to those in keyword_sets.
     ad_hoc_keywords TEXT[],
+    -- How the ad-hoc keywords match page content: as a substring, a regular expression or a whole word.
+    match_type TEXT NOT NULL DEFAULT 'substring' CHECK (match_type IN ('substring', 'regex', 'word_boundary')),
+    -- 'keywords' scans pages for keywords; 'liveness_only' classifies results on reachability and status code alone.
+    validation_mode TEXT NOT NULL DEFAULT 'keywords' CHECK (validation_mode IN ('keywords', 'liveness_only')),
     -- Array of proxy IDs (from 'proxies' table) to be used for requests.
     proxy_ids UUID[],
     -- Optional foreign key to a specific proxy pool (if proxy management involves pools).
@@ -407,6 +504,8 @@ CREATE TABLE IF NOT EXISTS http_keyword_campaign_params (
     target_http_ports INT[],
     -- The last domain name that was processed or attempted in this campaign, for resumption.
     last_processed_domain_name TEXT,
+    -- Check each normalized domain only once per campaign; the decisions are kept in campaign_domain_dedup_decisions.
+    deduplicate_domains BOOLEAN NOT NULL DEFAULT FALSE,
     -- Flexible JSONB field for any additional HTTP keyword campaign-specific metadata.
     metadata JSONB
 );
@@ -447,12 +546,17 @@ CREATE TABLE IF NOT EXISTS proxies (
     country_code TEXT,
     -- Name of the proxy provider or service, if applicable.
     provider TEXT,
+    -- User who created the proxy. NULL for proxies that predate ownership.
+    owner_id UUID REFERENCES auth.users(id) ON DELETE SET NULL,
+    -- Whether users other than the owner may use this proxy.
+    shared BOOLEAN NOT NULL DEFAULT TRUE,
     -- Timestamp of when the proxy record was created.
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     -- Timestamp of when the proxy record was last updated. Automatically updated by a trigger.
     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 );
 CREATE INDEX IF NOT EXISTS idx_proxies_is_enabled ON proxies(is_enabled);
+CREATE INDEX IF NOT EXISTS idx_proxies_owner_id ON proxies(owner_id);
 
 -- HTTP Keyword Results Table
 CREATE TABLE IF NOT EXISTS http_keyword_results (
@@ -473,6 +577,8 @@ CREATE TABLE IF NOT EXISTS http_keyword_results (
     found_keywords_from_sets JSONB, -- Keywords found that were part of predefined sets
     found_ad_hoc_keywords JSONB, -- Keywords found that were provided ad-hoc for this campaign run
     content_hash TEXT, -- Hash of the page content to detect changes
+    body_truncated BOOLEAN NOT NULL DEFAULT FALSE, -- Body exceeded the persona's maxBodyBytes; keywords were matched on the part read
+    tls_certificate JSONB, -- TLS version, cipher suite and certificate issuer, subject and notAfter, when the persona captures TLS
     validated_by_persona_id UUID REFERENCES personas(id) ON DELETE SET NULL,
     used_proxy_id UUID REFERENCES proxies(id) ON DELETE SET NULL,
     attempts INT DEFAULT 0,
@@ -484,8 +590,27 @@ CREATE TABLE IF NOT EXISTS http_keyword_results (
 CREATE INDEX IF NOT EXISTS idx_http_results_campaign_id ON http_keyword_results(http_keyword_campaign_id);
 CREATE INDEX IF NOT EXISTS idx_http_results_domain_name ON http_keyword_results(domain_name);
 CREATE INDEX IF NOT EXISTS idx_http_results_status ON http_keyword_results(validation_status);
+CREATE INDEX IF NOT EXISTS idx_http_results_campaign_domain_c ON http_keyword_results(http_keyword_campaign_id, domain_name COLLATE "C");
 CREATE INDEX IF NOT EXISTS idx_http_keyword_results_dns_result_id ON http_keyword_results(dns_result_id);
 
+-- Campaign Domain Dedup Decisions Table: Whether each source item of a deduplicating campaign was kept or
+-- skipped as a duplicate of the kept item with the same normalized domain.
+CREATE TABLE IF NOT EXISTS campaign_domain_dedup_decisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    normalized_domain TEXT NOT NULL,
+    domain_name TEXT NOT NULL,
+    source_item_id UUID NOT NULL,
+    kept_item_id UUID NOT NULL,
+    decision TEXT NOT NULL CHECK (decision IN ('kept', 'duplicate')),
+    decided_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_campaign_domain_dedup_source_item UNIQUE (campaign_id, source_item_id)
+);
+
+-- Exactly one item is kept per normalized domain within a campaign
+CREATE UNIQUE INDEX IF NOT EXISTS uq_campaign_domain_dedup_kept ON campaign_domain_dedup_decisions(campaign_id, normalized_domain) WHERE decision = 'kept';
+CREATE INDEX IF NOT EXISTS idx_campaign_domain_dedup_decisions_campaign ON campaign_domain_dedup_decisions(campaign_id, decided_at);
+
 -- Audit Logs Table: Records significant actions performed within the system for auditing and tracking purposes.
 CREATE TABLE IF NOT EXISTS audit_logs (
     -- Unique identifier for the audit log entry, automatically generated as a UUID v4.
@@ -512,6 +637,45 @@ CREATE INDEX IF NOT EXISTS idx_audit_logs_timestamp ON audit_logs(timestamp DESC
 CREATE INDEX IF NOT EXISTS idx_audit_logs_user_id ON audit_logs(user_id);
 CREATE INDEX IF NOT EXISTS idx_audit_logs_entity_type_id ON audit_logs(entity_type, entity_id);
 
+-- Event Deliveries Table: Outbound webhook event deliveries, kept so failed deliveries can be replayed.
+CREATE TABLE IF NOT EXISTS event_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_type TEXT NOT NULL,
+    target_url TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    -- Campaign webhook whose secret signs this delivery, replays included; NULL for the global webhook.
+    -- There is no foreign key so a delivery outlives its webhook; it can no longer be replayed then.
+    campaign_webhook_id UUID,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    -- Number of delivery attempts made so far, replays included.
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_status_code INTEGER,
+    last_error TEXT,
+    last_attempted_at TIMESTAMPTZ,
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_deliveries_status ON event_deliveries(status);
+CREATE INDEX IF NOT EXISTS idx_event_deliveries_event_type ON event_deliveries(event_type);
+CREATE INDEX IF NOT EXISTS idx_event_deliveries_created_at ON event_deliveries(created_at DESC);
+
+-- Event Delivery Attempts Table: Every attempt at an event delivery and its outcome.
+CREATE TABLE IF NOT EXISTS event_delivery_attempts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    delivery_id UUID NOT NULL REFERENCES event_deliveries(id) ON DELETE CASCADE,
+    attempt_number INTEGER NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    -- Whether the attempt was a manual replay rather than an automatic retry.
+    is_replay BOOLEAN NOT NULL DEFAULT FALSE,
+    attempted_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_delivery_attempts_delivery_id ON event_delivery_attempts(delivery_id);
+
 -- Campaign Jobs Table: Manages individual jobs or tasks associated with campaigns, typically processed by a worker service.
 CREATE TABLE IF NOT EXISTS campaign_jobs (
     -- Unique identifier for the campaign job, automatically generated as a UUID v4.
@@ -523,6 +687,8 @@ CREATE TABLE IF NOT EXISTS campaign_jobs (
     job_type TEXT NOT NULL,
     -- Current status of the job (e.g., 'Pending', 'Queued', 'Running', 'Completed', 'Failed', 'Retry').
     status TEXT NOT NULL DEFAULT 'pending',
+    -- Base fetch priority; higher is fetched first. Waiting time raises the effective priority when aging is enabled.
+    priority INT NOT NULL DEFAULT 0,
     -- Timestamp indicating when the job is scheduled to be processed. Defaults to the current time.
     scheduled_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     -- Number of times this job has been attempted.
@@ -546,12 +712,85 @@ CREATE TABLE IF NOT EXISTS campaign_jobs (
     -- Timestamp indicating when a worker locked this job for processing.
     locked_at TIMESTAMPTZ,
     -- Identifier of the worker that has locked this job.
-    locked_by TEXT
+    locked_by TEXT,
+    -- Attempt number, worker, error and time of every failed attempt at this job.
+    attempt_history JSONB,
+    -- Timestamp of when the worker processing this job last reported it alive.
+    last_heartbeat_at TIMESTAMPTZ
 );
 
 CREATE INDEX IF NOT EXISTS idx_campaign_jobs_campaign_id ON campaign_jobs(campaign_id);
 CREATE INDEX IF NOT EXISTS idx_campaign_jobs_status_scheduled_at ON campaign_jobs(status, scheduled_at ASC);
 CREATE INDEX IF NOT EXISTS idx_campaign_jobs_type ON campaign_jobs(job_type);
+CREATE INDEX IF NOT EXISTS idx_campaign_jobs_processing_heartbeat ON campaign_jobs(last_heartbeat_at) WHERE status = 'processing';
+
+-- Campaign Jobs Dead Letter Table: Jobs that failed on every attempt they were allowed, moved out of
+-- campaign_jobs so operators can inspect them and replay them as queued jobs.
+CREATE TABLE IF NOT EXISTS campaign_jobs_dead_letter (
+    -- ID the job had in campaign_jobs, and gets back when replayed.
+    id UUID PRIMARY KEY,
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    job_type TEXT NOT NULL,
+    priority INT NOT NULL DEFAULT 0,
+    job_payload JSONB,
+    attempts INT NOT NULL,
+    max_attempts INT NOT NULL,
+    -- Error of the final attempt.
+    last_error TEXT NOT NULL,
+    attempt_history JSONB,
+    processing_server_id TEXT,
+    job_created_at TIMESTAMPTZ NOT NULL,
+    dead_lettered_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_jobs_dead_letter_dead_lettered_at ON campaign_jobs_dead_letter(dead_lettered_at DESC);
+CREATE INDEX IF NOT EXISTS idx_campaign_jobs_dead_letter_campaign_id ON campaign_jobs_dead_letter(campaign_id);
+
+-- Campaign Webhooks Table: URLs notified when a campaign completes, fails or is cancelled.
+CREATE TABLE IF NOT EXISTS campaign_webhooks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    -- Key of the HMAC-SHA256 signature sent with every notification to this webhook.
+    secret TEXT NOT NULL,
+    created_by UUID REFERENCES auth.users(id) ON DELETE SET NULL,
+    -- Terminal campaign status this webhook was last notified of; the notifier skips webhooks already told of the current status.
+    notified_status TEXT,
+    notified_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_webhooks_campaign_id ON campaign_webhooks(campaign_id);
+
+-- Campaign Schedules Table: starts a pending campaign at run_at, or a clone of the campaign each time cron_expression fires.
+CREATE TABLE IF NOT EXISTS campaign_schedules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL UNIQUE REFERENCES campaigns(id) ON DELETE CASCADE,
+    run_at TIMESTAMPTZ,
+    cron_expression TEXT,
+    -- When the schedule fires next; NULL once a one-off schedule has fired.
+    next_run_at TIMESTAMPTZ,
+    -- Set by the server firing the schedule so other servers skip it until then.
+    locked_until TIMESTAMPTZ,
+    last_run_at TIMESTAMPTZ,
+    -- Campaign started by the last firing: the campaign itself, or the clone a cron schedule made.
+    last_campaign_id UUID,
+    last_error TEXT,
+    created_by UUID REFERENCES auth.users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_campaign_schedules_kind CHECK ((run_at IS NULL) <> (cron_expression IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_schedules_next_run_at ON campaign_schedules(next_run_at) WHERE next_run_at IS NOT NULL;
+
+-- Settings Table: Runtime-adjustable application settings managed through the admin settings API.
+CREATE TABLE IF NOT EXISTS settings (
+    key TEXT PRIMARY KEY,
+    value JSONB NOT NULL,
+    updated_by UUID REFERENCES auth.users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
 
 -- Function to generate user agent hash for session security
 CREATE OR REPLACE FUNCTION generate_user_agent_hash(user_agent_text TEXT)
@@ -827,7 +1066,10 @@ INSERT INTO auth.permissions (id, name, display_name, description, resource, act
     ('00000000-0000-0000-0001-000000000014', 'system:admin', 'System Administration', 'Full administrative access to system', 'system', 'admin'),
     ('00000000-0000-0000-0001-000000000015', 'system:config', 'System Configuration', 'Modify system configuration settings', 'system', 'config'),
     ('00000000-0000-0000-0001-000000000016', 'users:manage', 'User Management', 'Create, update, and delete user accounts', 'users', 'manage'),
-    ('00000000-0000-0000-0001-000000000017', 'reports:generate', 'Generate Reports', 'Generate and export system reports', 'reports', 'generate')
+    ('00000000-0000-0000-0001-000000000017', 'reports:generate', 'Generate Reports', 'Generate and export system reports', 'reports', 'generate'),
+    ('00000000-0000-0000-0001-000000000018', 'admin:users', 'Administer Users', 'Access the admin user management endpoints', 'admin', 'users'),
+    ('00000000-0000-0000-0001-000000000019', 'system:users', 'User Accounts and Data Requests', 'List, unlock, disable and reactivate accounts, and export and erase a user''s personal data', 'system', 'users'),
+    ('00000000-0000-0000-0001-000000000020', 'system:audit', 'Audit Log Access', 'Query and export the audit log', 'system', 'audit')
 ON CONFLICT (resource, action) DO NOTHING;
 
 -- Assign all permissions to super_admin role
@@ -848,7 +1090,10 @@ INSERT INTO auth.role_permissions (role_id, permission_id) VALUES
     ('00000000-0000-0000-0000-000000000001', '00000000-0000-0000-0001-000000000014'),
     ('00000000-0000-0000-0000-000000000001', '00000000-0000-0000-0001-000000000015'),
     ('00000000-0000-0000-0000-000000000001', '00000000-0000-0000-0001-000000000016'),
-    ('00000000-0000-0000-0000-000000000001', '00000000-0000-0000-0001-000000000017')
+    ('00000000-0000-0000-0000-000000000001', '00000000-0000-0000-0001-000000000017'),
+    ('00000000-0000-0000-0000-000000000001', '00000000-0000-0000-0001-000000000018'),
+    ('00000000-0000-0000-0000-000000000001', '00000000-0000-0000-0001-000000000019'),
+    ('00000000-0000-0000-0000-000000000001', '00000000-0000-0000-0001-000000000020')
 ON CONFLICT (role_id, permission_id) DO NOTHING;
 
 -- Assign appropriate permissions to admin role
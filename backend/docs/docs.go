@@ -447,8 +447,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "password": {
-                    "type": "string",
-                    "minLength": 12
+                    "type": "string"
                 },
                 "rememberMe": {
                     "type": "boolean"
@@ -2,13 +2,26 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 
 	"golang.org/x/crypto/bcrypt"
+
+	"github.com/fntelecomllc/studio/backend/internal/services"
 )
 
+// Prints a bcrypt hash of a password peppered with PASSWORD_PEPPER, for seeding auth.users. Store
+// it with password_pepper_version set to PASSWORD_PEPPER_VERSION (default 1).
 func main() {
-	pepper := "domainflow_secure_pepper_key_2025_production"
+	pepper := os.Getenv("PASSWORD_PEPPER")
+	if len(pepper) < services.MinPasswordPepperLength {
+		fmt.Fprintf(os.Stderr, "PASSWORD_PEPPER must be set to at least %d bytes\n", services.MinPasswordPepperLength)
+		os.Exit(1)
+	}
 	password := "TempPassword123!"
+	if len(os.Args) > 1 {
+		password = os.Args[1]
+	}
 	pepperedPassword := password + pepper
 
 	hash, err := bcrypt.GenerateFromPassword([]byte(pepperedPassword), 12)
@@ -16,5 +29,10 @@ func main() {
 		panic(err)
 	}
 
+	version := 1
+	if v, err := strconv.Atoi(os.Getenv("PASSWORD_PEPPER_VERSION")); err == nil && v > 0 {
+		version = v
+	}
 	fmt.Println(string(hash))
+	fmt.Fprintf(os.Stderr, "password_pepper_version: %d\n", version)
 }
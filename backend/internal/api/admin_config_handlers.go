@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// effectiveConfig returns the redacted runtime configuration keyed by section
+func (h *APIHandler) effectiveConfig() map[string]interface{} {
+	h.configMutex.RLock()
+	sections, _ := config.Redact(h.Config).(map[string]interface{})
+	h.configMutex.RUnlock()
+	if sections == nil {
+		sections = map[string]interface{}{}
+	}
+	if h.SessionSettings != nil {
+		sections["session"] = config.Redact(h.SessionSettings)
+	}
+	return sections
+}
+
+// GetEffectiveConfigGin returns the configuration the server is running with, secrets redacted.
+// @Summary Get effective configuration
+// @Description Return the effective runtime configuration, including environment overrides and defaults, keyed by section (worker, server, httpValidator, session, ...). Secrets such as the password pepper, database passwords, API keys and webhook secrets read "[REDACTED]" when set and "" when not. Limit the response to some sections with a comma-separated sections parameter.
+// @Tags Admin
+// @Produce json
+// @Param sections query string false "Comma-separated sections to return"
+// @Success 200 {object} map[string]interface{} "Effective configuration by section"
+// @Failure 400 {object} models.ErrorResponse "Unknown section"
+// @Security SessionAuth
+// @Router /admin/config [get]
+func (h *APIHandler) GetEffectiveConfigGin(c *gin.Context) {
+	sections := h.effectiveConfig()
+	requested := c.Query("sections")
+	if requested == "" {
+		respondWithJSONGin(c, http.StatusOK, sections)
+		return
+	}
+
+	selected := make(map[string]interface{})
+	var unknown []string
+	for _, name := range strings.Split(requested, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		section, ok := sections[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		selected[name] = section
+	}
+	if len(unknown) > 0 {
+		known := make([]string, 0, len(sections))
+		for name := range sections {
+			known = append(known, name)
+		}
+		sort.Strings(known)
+		respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation, "Unknown configuration section",
+			[]ErrorDetail{{Field: "sections", Code: ErrorCodeValidation, Message: "unknown sections: " + strings.Join(unknown, ", "),
+				Context: map[string]interface{}{"knownSections": known}}})
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, selected)
+}
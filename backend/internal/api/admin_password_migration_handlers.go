@@ -0,0 +1,81 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminPasswordMigrationAPIHandler exposes admin endpoints for moving accounts off legacy password peppers.
+type AdminPasswordMigrationAPIHandler struct {
+	migrationService *services.PasswordMigrationService
+	auditLogStore    store.AuditLogStore
+}
+
+// NewAdminPasswordMigrationAPIHandler creates a new handler for password migration administration.
+func NewAdminPasswordMigrationAPIHandler(migrationService *services.PasswordMigrationService, auditLogStore store.AuditLogStore) *AdminPasswordMigrationAPIHandler {
+	return &AdminPasswordMigrationAPIHandler{migrationService: migrationService, auditLogStore: auditLogStore}
+}
+
+// GetLegacyPepperReportGin counts the accounts still hashed with a legacy pepper without changing them.
+// @Summary Report legacy-pepper accounts
+// @Description Count active accounts whose password hash uses a pepper version older than the current one, and how many a migration run would flag.
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} services.LegacyPepperMigrationResult "Legacy-pepper account counts"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /admin/passwords/legacy-peppers [get]
+func (h *AdminPasswordMigrationAPIHandler) GetLegacyPepperReportGin(c *gin.Context) {
+	result, err := h.migrationService.FlagLegacyPepperUsers(c.Request.Context(), true)
+	if err != nil {
+		log.Printf("Error counting legacy pepper users: %v", err)
+		respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeDatabaseError,
+			"Failed to count legacy pepper users", nil)
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, result)
+}
+
+// FlagLegacyPepperUsersGin requires a password change from legacy-pepper accounts past the grace period.
+// @Summary Flag legacy-pepper accounts
+// @Description Set must_change_password on active accounts whose legacy-pepper password is older than the grace period, so their next password is hashed with the current pepper. Safe to run repeatedly.
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} services.LegacyPepperMigrationResult "Legacy-pepper account counts and the number flagged"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /admin/passwords/legacy-peppers/flag [post]
+func (h *AdminPasswordMigrationAPIHandler) FlagLegacyPepperUsersGin(c *gin.Context) {
+	result, err := h.migrationService.FlagLegacyPepperUsers(c.Request.Context(), false)
+	if err != nil {
+		log.Printf("Error flagging legacy pepper users: %v", err)
+		respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeDatabaseError,
+			"Failed to flag legacy pepper users", nil)
+		return
+	}
+
+	details, _ := json.Marshal(result)
+	auditLog := &models.AuditLog{
+		Action:     "Flag Legacy Pepper Users",
+		EntityType: sql.NullString{String: "User", Valid: true},
+		Details:    models.JSONRawMessagePtr(details),
+		ClientIP:   sql.NullString{String: c.ClientIP(), Valid: c.ClientIP() != ""},
+		UserAgent:  sql.NullString{String: c.Request.UserAgent(), Valid: c.Request.UserAgent() != ""},
+	}
+	if userID, ok := currentUserID(c); ok {
+		auditLog.UserID = uuid.NullUUID{UUID: userID, Valid: true}
+	}
+	if auditErr := h.auditLogStore.CreateAuditLog(c.Request.Context(), nil, auditLog); auditErr != nil {
+		log.Printf("Error creating audit log for legacy pepper flagging: %v", auditErr)
+	}
+
+	respondWithJSONGin(c, http.StatusOK, result)
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminRoleAPIHandler exposes admin endpoints for maintaining system roles.
+type AdminRoleAPIHandler struct {
+	syncService     *services.RolePermissionSyncService
+	auditLogStore   store.AuditLogStore
+	permissionCache *services.UserPermissionCache
+}
+
+// NewAdminRoleAPIHandler creates a new handler for role administration.
+func NewAdminRoleAPIHandler(syncService *services.RolePermissionSyncService, auditLogStore store.AuditLogStore) *AdminRoleAPIHandler {
+	return &AdminRoleAPIHandler{syncService: syncService, auditLogStore: auditLogStore}
+}
+
+// SetPermissionCache sets the cache to clear when a sync grants permissions to the role.
+func (h *AdminRoleAPIHandler) SetPermissionCache(cache *services.UserPermissionCache) {
+	h.permissionCache = cache
+}
+
+// SyncRolePermissionsGin re-runs the essential permission seeding for the super_admin role.
+// @Summary Sync super_admin permissions
+// @Description Create any missing essential permissions and grant them all to the super_admin role. Safe to run repeatedly.
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} services.PermissionSyncResult "Permissions created and granted by the sync"
+// @Failure 404 {object} models.ErrorResponse "super_admin role not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /admin/roles/sync-permissions [post]
+func (h *AdminRoleAPIHandler) SyncRolePermissionsGin(c *gin.Context) {
+	result, err := h.syncService.SyncSuperAdminPermissions(c.Request.Context())
+	if err != nil {
+		log.Printf("Error syncing %s permissions: %v", services.SuperAdminRoleName, err)
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithDetailedErrorGin(c, http.StatusNotFound, ErrorCodeNotFound,
+				"Role "+services.SuperAdminRoleName+" not found", nil)
+			return
+		}
+		respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeDatabaseError,
+			"Failed to sync role permissions", nil)
+		return
+	}
+	if len(result.GrantedPermissions) > 0 {
+		// Every holder of the role gains permissions; drop what was cached before the grants
+		h.permissionCache.InvalidateAll()
+	}
+
+	details, _ := json.Marshal(result)
+	auditLog := &models.AuditLog{
+		Action:     "Sync Role Permissions",
+		EntityType: sql.NullString{String: "Role", Valid: true},
+		EntityID:   uuid.NullUUID{UUID: result.RoleID, Valid: true},
+		Details:    models.JSONRawMessagePtr(details),
+		ClientIP:   sql.NullString{String: c.ClientIP(), Valid: c.ClientIP() != ""},
+		UserAgent:  sql.NullString{String: c.Request.UserAgent(), Valid: c.Request.UserAgent() != ""},
+	}
+	if userID, ok := currentUserID(c); ok {
+		auditLog.UserID = uuid.NullUUID{UUID: userID, Valid: true}
+	}
+	if auditErr := h.auditLogStore.CreateAuditLog(c.Request.Context(), nil, auditLog); auditErr != nil {
+		log.Printf("Error creating audit log for role permission sync: %v", auditErr)
+	}
+
+	respondWithJSONGin(c, http.StatusOK, result)
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// grantingRolePermissionStore reports every permission as newly granted on the first sync only
+type grantingRolePermissionStore struct {
+	store.RolePermissionStore
+	granted map[uuid.UUID]bool
+}
+
+func (s *grantingRolePermissionStore) GetRoleByName(ctx context.Context, exec store.Querier, name string) (*models.Role, error) {
+	return &models.Role{ID: uuid.New(), Name: name}, nil
+}
+
+func (s *grantingRolePermissionStore) EnsurePermission(ctx context.Context, exec store.Querier, perm *models.Permission) (bool, error) {
+	return false, nil
+}
+
+func (s *grantingRolePermissionStore) GrantPermissionToRole(ctx context.Context, exec store.Querier, roleID, permissionID uuid.UUID) (bool, error) {
+	if s.granted[permissionID] {
+		return false, nil
+	}
+	s.granted[permissionID] = true
+	return true, nil
+}
+
+// discardAuditLogStore accepts audit entries without keeping them
+type discardAuditLogStore struct {
+	store.AuditLogStore
+}
+
+func (discardAuditLogStore) CreateAuditLog(ctx context.Context, exec store.Querier, logEntry *models.AuditLog) error {
+	return nil
+}
+
+func TestSyncRolePermissions_InvalidatesPermissionCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	syncService := services.NewRolePermissionSyncService(&grantingRolePermissionStore{granted: map[uuid.UUID]bool{}}, services.EssentialPermissions)
+	h := NewAdminRoleAPIHandler(syncService, discardAuditLogStore{})
+	cache := services.NewUserPermissionCache(time.Minute, 0)
+	h.SetPermissionCache(cache)
+	router := gin.New()
+	router.POST("/admin/roles/sync-permissions", h.SyncRolePermissionsGin)
+
+	userID := uuid.New()
+	loads := 0
+	load := func() ([]string, []string, error) {
+		loads++
+		return []string{"campaigns:read"}, []string{"super_admin"}, nil
+	}
+	runSync := func() {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/roles/sync-permissions", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	_, _, _ = cache.Get(userID, load)
+	runSync()
+	_, _, _ = cache.Get(userID, load)
+	assert.Equal(t, 2, loads, "granting permissions to the role drops cached permissions")
+
+	runSync()
+	_, _, _ = cache.Get(userID, load)
+	assert.Equal(t, 2, loads, "a sync that grants nothing keeps the cache")
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AuditLogAPIHandler exposes the audit log for review and compliance exports.
+type AuditLogAPIHandler struct {
+	queryService  *services.AuditLogQueryService
+	auditLogStore store.AuditLogStore
+}
+
+// NewAuditLogAPIHandler creates a new handler for audit log queries.
+func NewAuditLogAPIHandler(queryService *services.AuditLogQueryService, auditLogStore store.AuditLogStore) *AuditLogAPIHandler {
+	return &AuditLogAPIHandler{queryService: queryService, auditLogStore: auditLogStore}
+}
+
+// ListAuditLogsGin lists audit log entries, or streams every matching entry when export is set.
+// @Summary Query the audit log
+// @Description List audit log entries newest first with optional filters. With export=jsonl or export=csv every matching entry is streamed as a download, oldest first, instead of one page.
+// @Tags Admin
+// @Produce json
+// @Produce text/csv
+// @Param userId query string false "Filter by acting user ID"
+// @Param entityType query string false "Filter by entity type"
+// @Param entityId query string false "Filter by entity ID"
+// @Param action query string false "Filter by action"
+// @Param startDate query string false "Only entries at or after this RFC 3339 time"
+// @Param endDate query string false "Only entries at or before this RFC 3339 time"
+// @Param limit query int false "Maximum number of entries to return (1-100)" default(50)
+// @Param offset query int false "Number of entries to skip" default(0)
+// @Param export query string false "Stream all matching entries instead of a page" Enums(jsonl,csv)
+// @Success 200 {array} models.AuditLogRecord "Audit log entries"
+// @Failure 400 {object} APIResponse "Invalid query parameters"
+// @Failure 429 {object} APIResponse "Too many exports running"
+// @Failure 500 {object} APIResponse "Internal server error"
+// @Security SessionAuth
+// @Router /admin/audit-logs [get]
+func (h *AuditLogAPIHandler) ListAuditLogsGin(c *gin.Context) {
+	filter, ok := parseAuditLogFilter(c)
+	if !ok {
+		return
+	}
+	if raw, exporting := c.GetQuery("export"); exporting {
+		h.exportAuditLogs(c, filter, raw)
+		return
+	}
+
+	page, err := parsePagination(c, 50, DefaultMaxPageSize)
+	if err != nil {
+		respondWithPaginationErrorGin(c, err)
+		return
+	}
+	filter.Limit = page.Limit
+	filter.Offset = page.Offset
+
+	entries, err := h.queryService.List(c.Request.Context(), filter)
+	if err != nil {
+		log.Printf("Error listing audit logs: %v", err)
+		respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeDatabaseError, "Failed to retrieve audit logs", nil)
+		return
+	}
+	records := make([]models.AuditLogRecord, 0, len(entries))
+	for _, entry := range entries {
+		records = append(records, entry.Record())
+	}
+	respondWithJSONGin(c, http.StatusOK, records)
+}
+
+// exportAuditLogs streams the entries matching filter as a JSON Lines or CSV download
+func (h *AuditLogAPIHandler) exportAuditLogs(c *gin.Context, filter store.ListAuditLogsFilter, rawFormat string) {
+	format, err := services.ParseAuditExportFormat(rawFormat)
+	if err != nil {
+		respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation, "Invalid export format",
+			[]ErrorDetail{{Field: "export", Code: ErrorCodeValidation, Message: err.Error()}})
+		return
+	}
+
+	contentType := "application/x-ndjson; charset=utf-8"
+	if format == services.AuditExportFormatCSV {
+		contentType = "text/csv; charset=utf-8"
+	}
+	// Headers are only committed once the first byte is written, so a refused export still gets its error
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="audit-logs-%s.%s"`, time.Now().UTC().Format("20060102T150405Z"), format))
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Content-Type-Options", "nosniff")
+	written, exportErr := h.queryService.Export(c.Request.Context(), filter, format, c.Writer)
+	if errors.Is(exportErr, services.ErrAuditExportBusy) {
+		clearDownloadHeaders(c)
+		respondWithDetailedErrorGin(c, http.StatusTooManyRequests, ErrorCodeRateLimitExceeded, "Too many audit log exports are running, try again later", nil)
+		return
+	}
+	if exportErr != nil {
+		log.Printf("Error exporting audit logs after %d entries: %v", written, exportErr)
+		if !c.Writer.Written() {
+			clearDownloadHeaders(c)
+			respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeDatabaseError, "Failed to export audit logs", nil)
+			return
+		}
+		// The export is already partly sent; abort so the client sees a truncated download
+		c.Abort()
+	}
+
+	h.audit(c, map[string]interface{}{"format": format, "filter": auditLogFilterDetails(filter), "entries": written, "completed": exportErr == nil})
+}
+
+// clearDownloadHeaders drops the export headers so an error is sent as ordinary JSON
+func clearDownloadHeaders(c *gin.Context) {
+	c.Header("Content-Type", "")
+	c.Header("Content-Disposition", "")
+}
+
+// parseAuditLogFilter reads the query filters shared by listing and exporting
+func parseAuditLogFilter(c *gin.Context) (store.ListAuditLogsFilter, bool) {
+	filter := store.ListAuditLogsFilter{
+		EntityType: c.Query("entityType"),
+		Action:     c.Query("action"),
+	}
+	invalid := func(field, message string) (store.ListAuditLogsFilter, bool) {
+		respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation, "Invalid audit log filter",
+			[]ErrorDetail{{Field: field, Code: ErrorCodeValidation, Message: message}})
+		return store.ListAuditLogsFilter{}, false
+	}
+	if raw := c.Query("userId"); raw != "" {
+		userID, err := uuid.Parse(raw)
+		if err != nil {
+			return invalid("userId", "must be a valid UUID")
+		}
+		filter.UserID = userID.String()
+	}
+	if raw := c.Query("entityId"); raw != "" {
+		entityID, err := uuid.Parse(raw)
+		if err != nil {
+			return invalid("entityId", "must be a valid UUID")
+		}
+		filter.EntityID = uuid.NullUUID{UUID: entityID, Valid: true}
+	}
+	for _, bound := range []struct {
+		field string
+		into  *time.Time
+	}{{"startDate", &filter.StartDate}, {"endDate", &filter.EndDate}} {
+		if raw := c.Query(bound.field); raw != "" {
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return invalid(bound.field, "must be an RFC 3339 time")
+			}
+			*bound.into = parsed.UTC()
+		}
+	}
+	if !filter.StartDate.IsZero() && !filter.EndDate.IsZero() && filter.EndDate.Before(filter.StartDate) {
+		return invalid("endDate", "must not be before startDate")
+	}
+	return filter, true
+}
+
+// auditLogFilterDetails records which filters an export used
+func auditLogFilterDetails(filter store.ListAuditLogsFilter) map[string]interface{} {
+	details := map[string]interface{}{}
+	if filter.UserID != "" {
+		details["userId"] = filter.UserID
+	}
+	if filter.EntityType != "" {
+		details["entityType"] = filter.EntityType
+	}
+	if filter.EntityID.Valid {
+		details["entityId"] = filter.EntityID.UUID
+	}
+	if filter.Action != "" {
+		details["action"] = filter.Action
+	}
+	if !filter.StartDate.IsZero() {
+		details["startDate"] = filter.StartDate
+	}
+	if !filter.EndDate.IsZero() {
+		details["endDate"] = filter.EndDate
+	}
+	return details
+}
+
+// audit records who exported which part of the audit log
+func (h *AuditLogAPIHandler) audit(c *gin.Context, details interface{}) {
+	encoded, _ := json.Marshal(details)
+	auditLog := &models.AuditLog{
+		Action:     "Export Audit Logs",
+		EntityType: sql.NullString{String: "AuditLog", Valid: true},
+		Details:    models.JSONRawMessagePtr(encoded),
+		ClientIP:   sql.NullString{String: c.ClientIP(), Valid: c.ClientIP() != ""},
+		UserAgent:  sql.NullString{String: c.Request.UserAgent(), Valid: c.Request.UserAgent() != ""},
+	}
+	if userID, ok := currentUserID(c); ok {
+		auditLog.UserID = uuid.NullUUID{UUID: userID, Valid: true}
+	}
+	if err := h.auditLogStore.CreateAuditLog(c.Request.Context(), nil, auditLog); err != nil {
+		log.Printf("Error creating audit log for audit log export: %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keysetAuditLogStore pages audit entries held in (timestamp, id) order and keeps the entries created
+type keysetAuditLogStore struct {
+	store.AuditLogStore
+	entries []*models.AuditLog
+	created []*models.AuditLog
+	pages   int
+}
+
+func (s *keysetAuditLogStore) CreateAuditLog(ctx context.Context, exec store.Querier, logEntry *models.AuditLog) error {
+	s.created = append(s.created, logEntry)
+	return nil
+}
+
+func (s *keysetAuditLogStore) ListAuditLogsAfter(ctx context.Context, exec store.Querier, filter store.ListAuditLogsFilter, after *store.AuditLogCursor) ([]*models.AuditLog, error) {
+	s.pages++
+	var page []*models.AuditLog
+	for _, entry := range s.entries {
+		if filter.Action != "" && entry.Action != filter.Action {
+			continue
+		}
+		if !filter.EndDate.IsZero() && entry.Timestamp.After(filter.EndDate) {
+			continue
+		}
+		if after != nil && (entry.Timestamp.Before(after.Timestamp) ||
+			entry.Timestamp.Equal(after.Timestamp) && bytes.Compare(entry.ID[:], after.ID[:]) <= 0) {
+			continue
+		}
+		if len(page) == filter.Limit {
+			break
+		}
+		page = append(page, entry)
+	}
+	return page, nil
+}
+
+func newAuditLogHandler(count int, cfg config.AuditConfig) (*AuditLogAPIHandler, *keysetAuditLogStore) {
+	auditStore := &keysetAuditLogStore{}
+	start := time.Now().UTC().Add(-time.Hour)
+	for i := 0; i < count; i++ {
+		action := "Update Campaign"
+		if i%4 == 0 {
+			action = "Login"
+		}
+		// Entries come in threes sharing a timestamp so batches split ties
+		auditStore.entries = append(auditStore.entries, &models.AuditLog{ID: uuid.New(), Timestamp: start.Add(time.Duration(i/3) * time.Second), Action: action})
+	}
+	sort.Slice(auditStore.entries, func(i, j int) bool {
+		a, b := auditStore.entries[i], auditStore.entries[j]
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.Before(b.Timestamp)
+		}
+		return bytes.Compare(a.ID[:], b.ID[:]) < 0
+	})
+	return NewAuditLogAPIHandler(services.NewAuditLogQueryService(nil, auditStore, cfg), auditStore), auditStore
+}
+
+func getAuditLogs(h *AuditLogAPIHandler, query string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/audit-logs", h.ListAuditLogsGin)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/audit-logs?"+query, nil))
+	return w
+}
+
+func TestExportAuditLogsGin_StreamsEveryPageOnce(t *testing.T) {
+	h, auditStore := newAuditLogHandler(100, config.AuditConfig{ExportBatchSize: 8})
+
+	w := getAuditLogs(h, "export=jsonl&action=Update+Campaign")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "application/x-ndjson; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), `attachment; filename="audit-logs-`)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	seen := make(map[uuid.UUID]int)
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var record models.AuditLogRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		assert.Equal(t, "Update Campaign", record.Action)
+		seen[record.ID]++
+	}
+	require.NoError(t, scanner.Err())
+	for _, entry := range auditStore.entries {
+		if entry.Action == "Update Campaign" {
+			assert.Equal(t, 1, seen[entry.ID], "entry %s is exported exactly once", entry.ID)
+		}
+	}
+	assert.Len(t, seen, 75)
+	assert.Greater(t, auditStore.pages, 9, "the export spans several batches")
+
+	require.Len(t, auditStore.created, 1, "the export is audited")
+	assert.Equal(t, "Export Audit Logs", auditStore.created[0].Action)
+	assert.Contains(t, string(*auditStore.created[0].Details), `"entries":75`)
+}
+
+func TestExportAuditLogsGin_CSV(t *testing.T) {
+	h, _ := newAuditLogHandler(10, config.AuditConfig{ExportBatchSize: 3})
+
+	w := getAuditLogs(h, "export=csv")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "id,timestamp,userId,action,"))
+	assert.Equal(t, 11, strings.Count(w.Body.String(), "\n"))
+}
+
+func TestExportAuditLogsGin_Errors(t *testing.T) {
+	h, _ := newAuditLogHandler(10, config.AuditConfig{MaxConcurrentExports: 1})
+
+	assert.Equal(t, http.StatusBadRequest, getAuditLogs(h, "export=xml").Code)
+	assert.Equal(t, http.StatusBadRequest, getAuditLogs(h, "export=csv&userId=not-a-uuid").Code)
+	assert.Equal(t, http.StatusBadRequest, getAuditLogs(h, "export=csv&startDate=yesterday").Code)
+	assert.Equal(t, http.StatusBadRequest,
+		getAuditLogs(h, "export=csv&startDate=2026-10-16T00:00:00Z&endDate=2026-10-01T00:00:00Z").Code)
+
+	// Hold the only export slot with an export blocked on its writer
+	blocked := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		defer close(done)
+		_, _ = h.queryService.Export(context.Background(), store.ListAuditLogsFilter{}, services.AuditExportFormatJSONL,
+			writerFunc(func(p []byte) (int, error) {
+				once.Do(func() { close(blocked) })
+				<-release
+				return len(p), nil
+			}))
+	}()
+	<-blocked
+	w := getAuditLogs(h, "export=jsonl")
+	close(release)
+	<-done
+	require.Equal(t, http.StatusTooManyRequests, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+}
+
+// writerFunc adapts a function to io.Writer
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
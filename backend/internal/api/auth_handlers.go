@@ -1,8 +1,12 @@
 package api
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -14,6 +18,9 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/logging"
+	"github.com/fntelecomllc/studio/backend/internal/metrics"
+	"github.com/fntelecomllc/studio/backend/internal/middleware"
 	"github.com/fntelecomllc/studio/backend/internal/models"
 	"github.com/fntelecomllc/studio/backend/internal/services"
 )
@@ -23,6 +30,27 @@ type AuthHandler struct {
 	sessionService *services.SessionService
 	config         *config.SessionSettings
 	db             *sqlx.DB
+
+	// Optional: diagnose failed logins against hashes the login path cannot verify
+	diagnoseHashMismatches bool
+
+	// Optional: pepper applied to passwords before hashing; passwords are hashed as-is when nil
+	pepper *services.PasswordPepper
+
+	// Optional: rolling counts of login outcomes for security dashboards
+	loginRates *services.LoginRateTracker
+
+	// Previous passwords a new password may not reuse; config.DefaultPasswordHistorySize when unset
+	passwordHistorySize int
+
+	// Rules new passwords must meet; services.DefaultPasswordPolicy when unset
+	passwordPolicy *services.PasswordPolicy
+
+	// Optional: limits failed logins per client IP and per account
+	loginLimiter *services.LoginRateLimiter
+
+	// Optional: lockouts of emails no account has; lockout ends are only reported with them
+	unknownLockouts *services.UnknownAccountLockouts
 }
 
 // NewAuthHandler creates a new authentication handler
@@ -34,6 +62,51 @@ func NewAuthHandler(sessionService *services.SessionService, sessionConfig *conf
 	}
 }
 
+// SetPasswordHashDiagnostics makes failed logins check whether the stored hash, rather than the
+// password, is the problem. Affected accounts are logged, audited and flagged for migration.
+func (h *AuthHandler) SetPasswordHashDiagnostics(cfg config.PasswordHashConfig) {
+	h.diagnoseHashMismatches = cfg.MismatchDiagnosticsEnabled()
+}
+
+// SetPasswordPepper peppers the passwords hashed and verified by the handler. Logins on hashes of a
+// legacy pepper version rehash the password with the current pepper.
+func (h *AuthHandler) SetPasswordPepper(pepper *services.PasswordPepper) {
+	h.pepper = pepper
+}
+
+// SetPasswordHistorySize sets how many previous passwords of a user a password change may not reuse
+func (h *AuthHandler) SetPasswordHistorySize(size int) {
+	h.passwordHistorySize = size
+}
+
+// SetPasswordPolicy sets the rules a new password must meet
+func (h *AuthHandler) SetPasswordPolicy(policy *services.PasswordPolicy) {
+	h.passwordPolicy = policy
+}
+
+func (h *AuthHandler) policy() *services.PasswordPolicy {
+	if h.passwordPolicy == nil {
+		return services.DefaultPasswordPolicy()
+	}
+	return h.passwordPolicy
+}
+
+// SetLoginRateLimiter refuses logins from a client IP or at an account with too many recent failures
+func (h *AuthHandler) SetLoginRateLimiter(limiter *services.LoginRateLimiter) {
+	h.loginLimiter = limiter
+}
+
+// SetUnknownAccountLockouts locks emails no account has out like accounts. With lockout details
+// enabled in the session settings, a login that meets a lockout is then told when it ends.
+func (h *AuthHandler) SetUnknownAccountLockouts(lockouts *services.UnknownAccountLockouts) {
+	h.unknownLockouts = lockouts
+}
+
+// SetLoginRateTracker counts the outcome of every login attempt in tracker
+func (h *AuthHandler) SetLoginRateTracker(tracker *services.LoginRateTracker) {
+	h.loginRates = tracker
+}
+
 // Login handles user login requests
 // @Summary User login
 // @Description Authenticate a user with email and password
@@ -44,57 +117,53 @@ func NewAuthHandler(sessionService *services.SessionService, sessionConfig *conf
 // @Success 200 {object} models.LoginResponseAPI "Login successful"
 // @Failure 400 {object} ErrorResponse "Invalid request format"
 // @Failure 401 {object} ErrorResponse "Invalid credentials"
-// @Failure 423 {object} ErrorResponse "Account locked"
 // @Failure 403 {object} ErrorResponse "Account inactive"
+// @Failure 429 {object} ErrorResponse "Too many failed attempts or account locked"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
-	fmt.Println("DEBUG: Login handler started")
+	logger := middleware.RequestLogger(c)
 	var req models.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		fmt.Printf("DEBUG: JSON binding failed: %v\n", err)
+		logger.Debug(logging.CategoryAuth, "login_request_invalid", map[string]interface{}{"error": err.Error()})
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request format")
 		return
 	}
-	fmt.Printf("DEBUG: Request parsed successfully: %s\n", req.Email)
+
+	// Validate the device key before authenticating so a malformed key does not count as a login attempt
+	deviceKey, err := h.sessionService.PrepareDeviceKey(req.DevicePublicKey)
+	if err != nil {
+		if errors.Is(err, services.ErrDeviceKeyRequired) {
+			respondWithErrorGin(c, http.StatusBadRequest, "A device public key is required to sign in")
+		} else {
+			respondWithErrorGin(c, http.StatusBadRequest, "Invalid device public key")
+		}
+		return
+	}
 
 	// Get client information
 	ipAddress := getClientIP(c)
-	fmt.Printf("DEBUG: Client IP: %s\n", ipAddress)
 
 	// Validate credentials and authenticate user
-	fmt.Println("DEBUG: About to authenticate user")
+	logger.Debug(logging.CategoryAuth, "login_authenticating", map[string]interface{}{"email": req.Email, "clientIp": ipAddress})
 	user, err := h.authenticateUser(req.Email, req.Password, ipAddress)
+	h.recordLoginOutcome(ipAddress, err)
 	if err != nil {
-		fmt.Printf("DEBUG: Authentication failed: %v\n", err)
-		// Handle authentication errors with appropriate responses
-		switch err.Error() {
-		case "user not found":
-			respondWithErrorGin(c, http.StatusUnauthorized, "Invalid email or password")
-		case "invalid password":
-			respondWithErrorGin(c, http.StatusUnauthorized, "Invalid email or password")
-		case "account locked":
-			respondWithErrorGin(c, http.StatusLocked, "Account is temporarily locked due to multiple failed login attempts")
-		case "account inactive":
-			respondWithErrorGin(c, http.StatusForbidden, "Account is not active")
-		default:
-			respondWithErrorGin(c, http.StatusInternalServerError, "Authentication failed")
-		}
+		logger.Debug(logging.CategoryAuth, "login_authentication_failed", map[string]interface{}{"email": req.Email, "error": err.Error()})
+		h.respondWithLoginError(c, err)
 		return
 	}
 
 	// Create proper session using session service
-	fmt.Printf("DEBUG: Creating session using session service for user ID: %s\n", user.ID.String())
-	sessionData, err := h.sessionService.CreateSession(user.ID, ipAddress, c.GetHeader("User-Agent"))
+	logger = logger.WithUser(user.ID)
+	sessionData, err := h.sessionService.CreateDeviceBoundSession(user.ID, ipAddress, c.GetHeader("User-Agent"), deviceKey)
 	if err != nil {
-		fmt.Printf("DEBUG: Session creation failed: %v\n", err)
+		logger.Error(logging.CategorySession, "login_session_create", err, nil)
 		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to create session")
 		return
 	}
-	fmt.Printf("DEBUG: Session created successfully with ID: %s\n", sessionData.ID)
 
 	// Set session cookie
-	fmt.Printf("DEBUG: Setting session cookie with name: %s, value: %s\n", h.config.CookieName, sessionData.ID)
 	c.SetCookie(
 		h.config.CookieName,
 		sessionData.ID,
@@ -104,8 +173,14 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		h.config.CookieSecure,
 		h.config.CookieHttpOnly,
 	)
-	fmt.Printf("DEBUG: Cookie set with domain: %s, path: %s, secure: %v, httpOnly: %v\n",
-		h.config.CookieDomain, h.config.CookiePath, h.config.CookieSecure, h.config.CookieHttpOnly)
+	logger.Debug(logging.CategorySession, "login_session_cookie_set", map[string]interface{}{
+		"cookieName":     h.config.CookieName,
+		"cookieDomain":   h.config.CookieDomain,
+		"cookiePath":     h.config.CookiePath,
+		"cookieSecure":   h.config.CookieSecure,
+		"cookieHttpOnly": h.config.CookieHttpOnly,
+		"expiresAt":      sessionData.ExpiresAt.Format(time.RFC3339),
+	})
 
 	// Update last login information
 	h.updateLastLogin(user.ID, ipAddress)
@@ -116,11 +191,151 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		"sessionId": sessionData.ID,
 		"expiresAt": sessionData.ExpiresAt.Format(time.RFC3339),
 	}
+	if h.config.LoginSessionContext {
+		sessionResponse["sessionContext"] = h.loginSessionContext(sessionData)
+	}
 
 	// Return successful login response with correct field names
 	respondWithJSONGin(c, http.StatusOK, sessionResponse)
 }
 
+// loginSessionContext describes a session created by a login. Password login has no second
+// factor, so MFAUsed is false until one is verified during login.
+func (h *AuthHandler) loginSessionContext(session *services.SessionData) *models.LoginSessionContext {
+	device := parseUserAgent(session.UserAgent)
+	return &models.LoginSessionContext{
+		IPAddress:          session.IPAddress,
+		UserAgent:          session.UserAgent,
+		Browser:            device.Browser,
+		OS:                 device.OS,
+		DeviceType:         device.DeviceType,
+		ExpiresAt:          session.ExpiresAt.UTC(),
+		IdleTimeoutSeconds: int(h.config.IdleTimeout.Seconds()),
+		DeviceBound:        session.DeviceKey != "",
+		MFAUsed:            false,
+		NewDevice:          h.isNewDevice(session.UserID, session.ID, device),
+	}
+}
+
+// isNewDevice reports whether none of the user's other recent sessions came from the same browser
+// and operating system. A failed lookup is logged and reported as a known device.
+func (h *AuthHandler) isNewDevice(userID uuid.UUID, sessionID string, device userAgentInfo) bool {
+	var userAgents []string
+	query := `SELECT user_agent FROM auth.sessions
+	           WHERE user_id = $1 AND id <> $2 AND user_agent IS NOT NULL
+	           ORDER BY created_at DESC LIMIT 50`
+	if err := h.db.Select(&userAgents, query, userID, sessionID); err != nil {
+		logging.Error(logging.CategoryDatabase, "new_device_lookup", err, map[string]interface{}{"userId": userID.String()})
+		return false
+	}
+	for _, userAgent := range userAgents {
+		earlier := parseUserAgent(userAgent)
+		if earlier.Browser == device.Browser && earlier.OS == device.OS {
+			return false
+		}
+	}
+	return true
+}
+
+// respondWithLoginError writes the response for a failed authenticateUser
+func (h *AuthHandler) respondWithLoginError(c *gin.Context, err error) {
+	// A hash the login path cannot verify is diagnosed for admins only
+	var hashMismatch *services.PasswordHashMismatchError
+	if errors.As(err, &hashMismatch) {
+		respondWithErrorGin(c, http.StatusUnauthorized, "Invalid email or password")
+		return
+	}
+	// A lockout reveals nothing about the password, which is not checked, nor about the email, since
+	// unknown emails are locked out alike. Without that mirror it is answered like a wrong password.
+	var locked *accountLockedError
+	if errors.As(err, &locked) {
+		if h.lockoutDetails() {
+			respondWithLoginRateLimited(c, &services.LoginRateLimitError{Scope: services.RateLimitScopeAccount, BlockedUntil: locked.lockedUntil})
+			return
+		}
+		respondWithErrorGin(c, http.StatusUnauthorized, "Invalid email or password")
+		return
+	}
+	var limited *services.LoginRateLimitError
+	if errors.As(err, &limited) {
+		respondWithLoginRateLimited(c, limited)
+		return
+	}
+	// Handle authentication errors with appropriate responses
+	switch err.Error() {
+	case "user not found":
+		respondWithErrorGin(c, http.StatusUnauthorized, "Invalid email or password")
+	case "invalid password":
+		respondWithErrorGin(c, http.StatusUnauthorized, "Invalid email or password")
+	case "account inactive":
+		respondWithErrorGin(c, http.StatusForbidden, "Account is not active")
+	default:
+		respondWithErrorGin(c, http.StatusInternalServerError, "Authentication failed")
+	}
+}
+
+// loginOutcome classifies the result of authenticateUser. It reports false for server errors and
+// rate-limited attempts, which say nothing about the credentials.
+func loginOutcome(err error) (services.LoginOutcome, bool) {
+	var locked *accountLockedError
+	var hashMismatch *services.PasswordHashMismatchError
+	switch {
+	case err == nil:
+		return services.LoginOutcomeSuccess, true
+	case errors.As(err, &locked):
+		return services.LoginOutcomeLockout, true
+	case errors.As(err, &hashMismatch):
+		return services.LoginOutcomeFailure, true
+	}
+	switch err.Error() {
+	case "user not found", "invalid password", "account inactive":
+		return services.LoginOutcomeFailure, true
+	}
+	return "", false
+}
+
+// recordLoginOutcome counts the result of authenticateUser in the login rates and, for failures,
+// in the auth failure metric. Server errors say nothing about the credentials and are not counted.
+func (h *AuthHandler) recordLoginOutcome(ipAddress string, err error) {
+	var limited *services.LoginRateLimitError
+	if errors.As(err, &limited) {
+		metrics.RecordAuthFailure("rate_limited")
+		return
+	}
+	outcome, ok := loginOutcome(err)
+	if !ok {
+		return
+	}
+	if outcome != services.LoginOutcomeSuccess {
+		metrics.RecordAuthFailure(string(outcome))
+	}
+	if h.loginRates != nil {
+		h.loginRates.Record(ipAddress, outcome)
+	}
+}
+
+// respondWithLoginRateLimited writes a 429 for a login refused by the rate limiter, with a
+// Retry-After header and whether the client IP or the account is blocked
+func respondWithLoginRateLimited(c *gin.Context, limited *services.LoginRateLimitError) {
+	retryAfter := int(math.Ceil(time.Until(limited.BlockedUntil).Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	respondWithDetailedErrorGin(c, http.StatusTooManyRequests, ErrorCodeRateLimitExceeded,
+		"Too many failed login attempts, try again later", []ErrorDetail{
+			{
+				Code:    ErrorCodeRateLimitExceeded,
+				Message: "Sign in again after the block ends",
+				Context: map[string]interface{}{
+					"scope":        limited.Scope,
+					"retryAfter":   retryAfter,
+					"blockedUntil": limited.BlockedUntil.UTC().Format(time.RFC3339),
+				},
+			},
+		})
+}
+
 // Logout handles user logout requests
 func (h *AuthHandler) Logout(c *gin.Context) {
 	// Get session ID from any of the possible cookie names
@@ -151,7 +366,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		err = h.sessionService.InvalidateSession(sessionID)
 		if err != nil {
 			// Log the error but don't fail the logout
-			fmt.Printf("Failed to invalidate session %s: %v\n", sessionID, err)
+			middleware.RequestLogger(c).Error(logging.CategorySession, "logout_session_invalidate", err, nil)
 		}
 	}
 
@@ -204,31 +419,8 @@ func (h *AuthHandler) Me(c *gin.Context) {
 		return
 	}
 
-	// Get user roles and permissions using the same logic as sessionService
-	// Load roles
-	rolesQuery := `
-		SELECT r.name
-		FROM auth.roles r
-		JOIN auth.user_roles ur ON r.id = ur.role_id
-		WHERE ur.user_id = $1 AND (ur.expires_at IS NULL OR ur.expires_at > NOW())`
-
-	var roleNames []string
-	err = h.db.Select(&roleNames, rolesQuery, ctx.UserID)
-	if err != nil {
-		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to fetch user roles")
-		return
-	}
-
-	// Load permissions
-	permissionsQuery := `
-		SELECT DISTINCT p.name
-		FROM auth.permissions p
-		JOIN auth.role_permissions rp ON p.id = rp.permission_id
-		JOIN auth.user_roles ur ON rp.role_id = ur.role_id
-		WHERE ur.user_id = $1 AND (ur.expires_at IS NULL OR ur.expires_at > NOW())`
-
-	var permissionNames []string
-	err = h.db.Select(&permissionNames, permissionsQuery, ctx.UserID)
+	// Get user roles and permissions through the session service's permission cache
+	permissionNames, roleNames, err := h.sessionService.UserPermissions(ctx.UserID)
 	if err != nil {
 		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to fetch user permissions")
 		return
@@ -253,7 +445,10 @@ func (h *AuthHandler) Me(c *gin.Context) {
 	respondWithJSONGin(c, http.StatusOK, user.PublicUser())
 }
 
-// ChangePassword handles password change requests
+// ChangePassword handles password change requests. The new password must meet the password policy
+// and may be neither the current one nor one of the user's recent passwords, whose hashes are kept
+// in auth.password_history. Once the new password is stored every session of the user, the
+// caller's included, is invalidated so that only the new password signs in.
 func (h *AuthHandler) ChangePassword(c *gin.Context) {
 	var req models.ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -261,8 +456,250 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement password change functionality
-	respondWithErrorGin(c, http.StatusNotImplemented, "Password change functionality not yet implemented in session-based system")
+	securityContext, exists := c.Get("security_context")
+	if !exists {
+		respondWithErrorGin(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	userID := securityContext.(*models.SecurityContext).UserID
+
+	var stored struct {
+		PasswordHash  string `db:"password_hash"`
+		PepperVersion int    `db:"password_pepper_version"`
+	}
+	err := h.db.Get(&stored, `SELECT password_hash, COALESCE(password_pepper_version, 1) AS password_pepper_version
+		FROM auth.users WHERE id = $1 AND is_active = true`, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithErrorGin(c, http.StatusNotFound, "User not found")
+			return
+		}
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to load user")
+		return
+	}
+
+	if valid, err := h.verifyPassword(req.CurrentPassword, stored.PasswordHash, stored.PepperVersion); err != nil || !valid {
+		respondWithErrorGin(c, http.StatusUnauthorized, "Current password is incorrect")
+		return
+	}
+	if req.NewPassword == req.CurrentPassword {
+		respondWithErrorGin(c, http.StatusBadRequest, "New password must differ from the current password")
+		return
+	}
+	if err := h.policy().Validate(req.NewPassword); err != nil {
+		respondWithPasswordPolicyError(c, "newPassword", err)
+		return
+	}
+	reused, err := h.isRecentPassword(userID, req.NewPassword)
+	if err != nil {
+		middleware.RequestLogger(c).Error(logging.CategoryPassword, "password_history_check", err, nil)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to check password history")
+		return
+	}
+	if reused {
+		respondWithErrorGin(c, http.StatusBadRequest, fmt.Sprintf(
+			"New password must differ from your last %d passwords", h.historySize()))
+		return
+	}
+
+	hashedPassword, pepperVersion, err := h.hashPassword(req.NewPassword)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to hash password")
+		return
+	}
+	if err := h.storeNewPassword(userID, hashedPassword, pepperVersion, stored.PasswordHash, stored.PepperVersion); err != nil {
+		middleware.RequestLogger(c).Error(logging.CategoryPassword, "password_change", err, nil)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to change password")
+		return
+	}
+
+	if err := h.sessionService.InvalidateAllUserSessions(userID); err != nil {
+		middleware.RequestLogger(c).Error(logging.CategorySession, "password_change_sessions_invalidate", err, nil)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Password changed but existing sessions could not be signed out")
+		return
+	}
+	h.clearSessionCookies(c)
+
+	respondWithJSONGin(c, http.StatusOK, map[string]string{
+		"message": "Password changed successfully. Please sign in again.",
+	})
+}
+
+// GetPasswordPolicy handles GET /api/v2/auth/password-policy
+// @Summary Get the password policy
+// @Description Get the rules a new password must meet, so clients can show and check them before submitting
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} services.PasswordPolicyRules "Password policy"
+// @Router /auth/password-policy [get]
+func (h *AuthHandler) GetPasswordPolicy(c *gin.Context) {
+	respondWithJSONGin(c, http.StatusOK, h.policy().Rules())
+}
+
+// respondWithPasswordPolicyError responds 400 with one detail per rule the password in field broke
+func respondWithPasswordPolicyError(c *gin.Context, field string, err error) {
+	var policyErr *services.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		respondWithErrorGin(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	details := make([]ErrorDetail, 0, len(policyErr.Problems))
+	for _, problem := range policyErr.Problems {
+		details = append(details, ErrorDetail{Field: field, Code: ErrorCodeValidation, Message: "Password " + problem})
+	}
+	respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation, "Password does not meet the password policy", details)
+}
+
+func (h *AuthHandler) historySize() int {
+	if h.passwordHistorySize <= 0 {
+		return config.DefaultPasswordHistorySize
+	}
+	return h.passwordHistorySize
+}
+
+// isRecentPassword reports whether password matches one of the user's previous passwords. Each hash
+// is compared with bcrypt against the password pre-hashed with the pepper of its version, so long
+// passwords are compared whole rather than by their first 72 bytes; hashes of a pepper no longer
+// configured cannot match.
+func (h *AuthHandler) isRecentPassword(userID uuid.UUID, password string) (bool, error) {
+	var history []struct {
+		PasswordHash  string `db:"password_hash"`
+		PepperVersion int    `db:"password_pepper_version"`
+	}
+	err := h.db.Select(&history, `SELECT password_hash, password_pepper_version FROM auth.password_history
+		WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2`, userID, h.historySize())
+	if err != nil {
+		return false, err
+	}
+	for _, previous := range history {
+		candidate := password
+		if h.pepper != nil {
+			peppered, ok := h.pepper.ApplyVersion(password, previous.PepperVersion)
+			if !ok {
+				continue
+			}
+			candidate = peppered
+		}
+		if bcrypt.CompareHashAndPassword([]byte(previous.PasswordHash), []byte(candidate)) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// storeNewPassword replaces the user's password hash and moves the old one into the password
+// history, keeping only the most recent historySize entries
+func (h *AuthHandler) storeNewPassword(userID uuid.UUID, hash string, pepperVersion int, oldHash string, oldPepperVersion int) error {
+	tx, err := h.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	updateQuery := `
+		UPDATE auth.users
+		SET password_hash = $2,
+		    password_pepper_version = $3,
+		    password_changed_at = NOW(),
+		    must_change_password = false,
+		    password_migration_required = false,
+		    updated_at = NOW()
+		WHERE id = $1`
+	if _, err := tx.Exec(updateQuery, userID, hash, pepperVersion); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO auth.password_history (user_id, password_hash, password_pepper_version)
+		VALUES ($1, $2, $3)`, userID, oldHash, oldPepperVersion); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM auth.password_history WHERE user_id = $1 AND id NOT IN (
+		SELECT id FROM auth.password_history WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2)`,
+		userID, h.historySize()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListSessions lists the caller's active sessions
+// @Summary List my sessions
+// @Description List the caller's active sessions, most recently active first. Sessions are named by a prefix of their ID; the full session ID is never returned.
+// @Tags Authentication
+// @Security SessionAuth
+// @Produce json
+// @Success 200 {array} models.ActiveSession "Active sessions"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	securityContext, exists := c.Get("security_context")
+	if !exists {
+		respondWithErrorGin(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	caller := securityContext.(*models.SecurityContext)
+
+	sessions, err := h.sessionService.ListUserSessions(caller.UserID)
+	if err != nil {
+		middleware.RequestLogger(c).Error(logging.CategorySession, "session_list", err, nil)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to list sessions")
+		return
+	}
+	activeSessions := make([]models.ActiveSession, 0, len(sessions))
+	for _, session := range sessions {
+		activeSessions = append(activeSessions, models.ActiveSession{
+			IDPrefix:       services.SessionIDPrefix(session.ID),
+			IPAddress:      session.IPAddress,
+			UserAgent:      session.UserAgent,
+			CreatedAt:      session.CreatedAt,
+			LastActivityAt: session.LastActivity,
+			ExpiresAt:      session.ExpiresAt,
+			Current:        session.ID == caller.SessionID,
+		})
+	}
+	respondWithJSONGin(c, http.StatusOK, activeSessions)
+}
+
+// RevokeSession signs out one of the caller's sessions
+// @Summary Revoke one of my sessions
+// @Description Invalidate the caller's session named by its ID prefix, as listed by GET /auth/sessions. Sessions of other users are not found. Revoking the current session also clears its cookies.
+// @Tags Authentication
+// @Security SessionAuth
+// @Produce json
+// @Param id path string true "Session ID prefix"
+// @Success 200 {object} map[string]string "Session revoked"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 404 {object} ErrorResponse "Session not found"
+// @Failure 409 {object} ErrorResponse "Prefix matches more than one session"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	securityContext, exists := c.Get("security_context")
+	if !exists {
+		respondWithErrorGin(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	caller := securityContext.(*models.SecurityContext)
+
+	revoked, err := h.sessionService.RevokeUserSession(caller.UserID, c.Param("id"))
+	switch {
+	case errors.Is(err, services.ErrSessionNotFound):
+		respondWithErrorGin(c, http.StatusNotFound, "Session not found")
+		return
+	case errors.Is(err, services.ErrSessionIDAmbiguous):
+		respondWithErrorGin(c, http.StatusConflict, "Session ID prefix matches more than one session")
+		return
+	case err != nil:
+		middleware.RequestLogger(c).Error(logging.CategorySession, "session_revoke", err, nil)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to revoke session")
+		return
+	}
+	if revoked.ID == caller.SessionID {
+		h.clearSessionCookies(c)
+	}
+	respondWithJSONGin(c, http.StatusOK, map[string]string{
+		"message":  "Session revoked",
+		"idPrefix": services.SessionIDPrefix(revoked.ID),
+	})
 }
 
 // RefreshSession refreshes the current session
@@ -321,8 +758,10 @@ func (h *AuthHandler) RefreshSession(c *gin.Context) {
 
 // Authentication helper methods
 
-// authenticateUser validates user credentials and returns user information
-func (h *AuthHandler) authenticateUser(email, password, ipAddress string) (*models.User, error) {
+// authenticateUser validates user credentials and returns user information. With a login rate
+// limiter, attempts from a blocked IP or at a blocked account are refused before the password is
+// checked, and failures are counted against both.
+func (h *AuthHandler) authenticateUser(email, password, ipAddress string) (_ *models.User, err error) {
 	var user models.User
 
 	// Query user by email (only fields that exist in the actual schema)
@@ -334,45 +773,74 @@ func (h *AuthHandler) authenticateUser(email, password, ipAddress string) (*mode
 		FROM auth.users
 		WHERE email = $1`
 
-	err := h.db.Get(&user, query, email)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			// Increment failed attempts for this IP to prevent enumeration
-			h.recordFailedLogin("", ipAddress, "user not found")
-			return nil, fmt.Errorf("user not found")
-		}
+	err = h.db.Get(&user, query, email)
+	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("database error: %w", err)
 	}
 
-	// Check if account is locked
+	// Unknown emails are limited like accounts, so a block does not reveal which emails are registered
+	account := services.UnknownAccountRateLimitKey(email)
+	if err == nil {
+		account = services.AccountRateLimitKey(user.ID)
+	}
+	if limitErr := h.checkLoginRateLimit(ipAddress, account); limitErr != nil {
+		return nil, limitErr
+	}
+	defer func() { h.countLoginAttempt(ipAddress, account, err) }()
+
+	if err == sql.ErrNoRows {
+		if lockedUntil, locked := h.unknownAccountLockedUntil(email); locked {
+			h.recordFailedLogin("", email, ipAddress, "account locked")
+			return nil, &accountLockedError{lockedUntil: lockedUntil}
+		}
+		h.countUnknownAccountFailure(email)
+		// Increment failed attempts for this IP to prevent enumeration
+		h.recordFailedLogin("", email, ipAddress, "user not found")
+		return nil, fmt.Errorf("user not found")
+	}
+
+	// Check if account is locked. The password is not checked during a lockout, so guesses cannot be
+	// confirmed, and every caller gets the same answer as for an unknown email.
 	if user.IsLocked && user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
-		h.recordFailedLogin(user.ID.String(), ipAddress, "account locked")
-		return nil, fmt.Errorf("account locked")
+		h.countLockedOutAttempt(user.ID, email, ipAddress)
+		return nil, &accountLockedError{lockedUntil: *user.LockedUntil}
 	}
 
 	// Check if account is active
 	if !user.IsActive {
-		h.recordFailedLogin(user.ID.String(), ipAddress, "account inactive")
+		h.recordFailedLogin(user.ID.String(), email, ipAddress, "account inactive")
 		return nil, fmt.Errorf("account inactive")
 	}
 
 	// Verify password using pgcrypto
-	var passwordValid bool
-	passwordQuery := `SELECT crypt($1, $2) = $2 AS password_valid`
-	err = h.db.Get(&passwordValid, passwordQuery, password, user.PasswordHash)
+	passwordValid, err := h.verifyPassword(password, user.PasswordHash, user.PasswordPepperVersion)
 	if err != nil {
+		// crypt() rejects salts of schemes pgcrypto does not implement
+		if mismatch := h.diagnosePasswordHash(&user, ipAddress); mismatch != nil {
+			h.incrementFailedAttempts(user.ID, email, ipAddress)
+			return nil, mismatch
+		}
 		return nil, fmt.Errorf("password verification error: %w", err)
 	}
 
 	if !passwordValid {
+		mismatch := h.diagnosePasswordHash(&user, ipAddress)
 		// Increment failed login attempts
-		h.incrementFailedAttempts(user.ID, ipAddress)
+		h.incrementFailedAttempts(user.ID, email, ipAddress)
+		if mismatch != nil {
+			return nil, mismatch
+		}
 		return nil, fmt.Errorf("invalid password")
 	}
 
 	// Reset failed login attempts on successful authentication
 	h.resetFailedAttempts(user.ID)
 
+	// Move a hash of a legacy pepper version to the current pepper while the plaintext is at hand
+	if h.pepper != nil && h.pepper.NeedsRehash(user.PasswordPepperVersion) {
+		h.rehashPassword(&user, password)
+	}
+
 	// Check if account was temporarily locked and should be unlocked
 	if user.IsLocked && user.LockedUntil != nil && time.Now().After(*user.LockedUntil) {
 		h.unlockAccount(user.ID)
@@ -382,11 +850,158 @@ func (h *AuthHandler) authenticateUser(email, password, ipAddress string) (*mode
 	return &user, nil
 }
 
-// incrementFailedAttempts increments failed login attempts and locks account if threshold reached
-func (h *AuthHandler) incrementFailedAttempts(userID uuid.UUID, ipAddress string) {
-	const maxFailedAttempts = 5
-	const lockoutDuration = 30 * time.Minute
+// verifyPassword compares a password with a stored hash of the given pepper version using pgcrypto.
+// A hash whose pepper version is not configured cannot match.
+func (h *AuthHandler) verifyPassword(password, passwordHash string, pepperVersion int) (bool, error) {
+	if h.pepper != nil {
+		peppered, ok := h.pepper.ApplyVersion(password, pepperVersion)
+		if !ok {
+			return false, nil
+		}
+		password = peppered
+	}
+	var passwordValid bool
+	err := h.db.Get(&passwordValid, `SELECT crypt($1, $2) = $2 AS password_valid`, password, passwordHash)
+	return passwordValid, err
+}
+
+// hashPassword hashes a password with the current pepper and returns the pepper version to store
+// with the hash
+func (h *AuthHandler) hashPassword(password string) (string, int, error) {
+	pepperVersion := h.pepperVersion()
+	if h.pepper != nil {
+		password = h.pepper.Apply(password)
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", 0, err
+	}
+	return string(hashedPassword), pepperVersion, nil
+}
+
+// pepperVersion returns the pepper version of the hashes the handler makes
+func (h *AuthHandler) pepperVersion() int {
+	if h.pepper == nil {
+		return config.UnpepperedPasswordPepperVersion
+	}
+	return h.pepper.Version()
+}
 
+// rehashPassword replaces the legacy-pepper hash of a user who has just signed in with a hash of the
+// current pepper. The update only applies while the stored hash is still the one verified, so a
+// concurrent password change wins. Failures are logged and leave the legacy hash, which the next
+// login retries.
+func (h *AuthHandler) rehashPassword(user *models.User, password string) {
+	hashedPassword, pepperVersion, err := h.hashPassword(password)
+	if err != nil {
+		logging.Error(logging.CategoryPassword, "password_rehash", err, map[string]interface{}{"userId": user.ID.String()})
+		return
+	}
+	query := `
+		UPDATE auth.users
+		SET password_hash = $2,
+		    password_pepper_version = $3,
+		    password_migration_required = false,
+		    updated_at = NOW()
+		WHERE id = $1 AND password_hash = $4`
+	if _, err := h.db.Exec(query, user.ID, hashedPassword, pepperVersion, user.PasswordHash); err != nil {
+		logging.Error(logging.CategoryDatabase, "password_rehash_store", err, map[string]interface{}{"userId": user.ID.String()})
+		return
+	}
+	logging.Info(logging.CategoryPassword, "password_rehashed", map[string]interface{}{
+		"userId":            user.ID.String(),
+		"fromPepperVersion": user.PasswordPepperVersion,
+		"toPepperVersion":   pepperVersion,
+	})
+	user.PasswordHash = hashedPassword
+	user.PasswordPepperVersion = pepperVersion
+}
+
+// accountLockedError is returned by authenticateUser for a login attempt during the lockout of an
+// account or of an unknown email
+type accountLockedError struct {
+	lockedUntil time.Time
+}
+
+func (e *accountLockedError) Error() string {
+	return "account locked"
+}
+
+// diagnosePasswordHash checks a user's stored hash after a failed comparison. When the hash may be
+// unverifiable even for the right password, it logs a diagnostic event, records it in the audit log
+// and flags the account for migration, then returns the mismatch. It returns nil otherwise.
+func (h *AuthHandler) diagnosePasswordHash(user *models.User, ipAddress string) *services.PasswordHashMismatchError {
+	if !h.diagnoseHashMismatches {
+		return nil
+	}
+	mismatch := services.DiagnosePasswordHash(user.PasswordHash, user.PasswordPepperVersion, h.pepperVersion())
+	if mismatch == nil {
+		return nil
+	}
+	logging.Warn(logging.CategoryPassword, "password_hash_mismatch", map[string]interface{}{
+		"userId":        user.ID.String(),
+		"scheme":        mismatch.Scheme,
+		"pepperVersion": mismatch.PepperVersion,
+		"reason":        mismatch.Reason,
+	})
+
+	flagQuery := `
+		UPDATE auth.users
+		SET password_migration_required = true,
+		    updated_at = NOW()
+		WHERE id = $1`
+	if _, err := h.db.Exec(flagQuery, user.ID); err != nil {
+		logging.Error(logging.CategoryDatabase, "password_migration_flag", err, map[string]interface{}{"userId": user.ID.String()})
+	}
+
+	auditQuery := `
+		INSERT INTO auth.auth_audit_log
+		(user_id, event_type, event_status, ip_address, details, risk_score, created_at)
+		VALUES ($1, 'password_hash_mismatch', 'flagged', $2, $3, 2, NOW())`
+	details, _ := json.Marshal(mismatch)
+	if _, err := h.db.Exec(auditQuery, user.ID, ipAddress, string(details)); err != nil {
+		logging.Error(logging.CategoryDatabase, "password_hash_mismatch_audit", err, map[string]interface{}{"userId": user.ID.String()})
+	}
+	return mismatch
+}
+
+// checkLoginRateLimit returns a *services.LoginRateLimitError when ipAddress or account is blocked.
+// A limiter that cannot be read lets the attempt through; the account lockout still applies.
+func (h *AuthHandler) checkLoginRateLimit(ipAddress, account string) error {
+	if h.loginLimiter == nil {
+		return nil
+	}
+	err := h.loginLimiter.Check(context.Background(), services.RateLimitActionLogin, ipAddress, account)
+	var limited *services.LoginRateLimitError
+	if err != nil && !errors.As(err, &limited) {
+		logging.Error(logging.CategoryRateLimit, "login_rate_limit_check", err, map[string]interface{}{"clientIp": ipAddress})
+		return nil
+	}
+	return err
+}
+
+// countLoginAttempt counts a failed login against ipAddress and account, or clears the account's
+// failures after a successful one. Server errors are not counted.
+func (h *AuthHandler) countLoginAttempt(ipAddress, account string, err error) {
+	if h.loginLimiter == nil {
+		return
+	}
+	outcome, ok := loginOutcome(err)
+	if !ok {
+		return
+	}
+	if outcome == services.LoginOutcomeSuccess {
+		err = h.loginLimiter.RecordSuccess(context.Background(), services.RateLimitActionLogin, account)
+	} else {
+		err = h.loginLimiter.RecordFailure(context.Background(), services.RateLimitActionLogin, ipAddress, account)
+	}
+	if err != nil {
+		logging.Error(logging.CategoryRateLimit, "login_rate_limit_count", err, map[string]interface{}{"clientIp": ipAddress})
+	}
+}
+
+// incrementFailedAttempts increments failed login attempts and locks account if threshold reached
+func (h *AuthHandler) incrementFailedAttempts(userID uuid.UUID, email, ipAddress string) {
 	query := `
 		UPDATE auth.users
 		SET failed_login_attempts = failed_login_attempts + 1,
@@ -401,13 +1016,58 @@ func (h *AuthHandler) incrementFailedAttempts(userID uuid.UUID, ipAddress string
 		    updated_at = NOW()
 		WHERE id = $1`
 
-	_, err := h.db.Exec(fmt.Sprintf(query, int(lockoutDuration.Minutes())), userID, maxFailedAttempts)
+	_, err := h.db.Exec(fmt.Sprintf(query, int(services.AccountLockoutDuration.Minutes())), userID, services.AccountLockoutThreshold)
 	if err != nil {
 		// Log error but don't fail the authentication flow
-		fmt.Printf("Failed to increment failed attempts for user %s: %v\n", userID, err)
+		logging.Error(logging.CategoryDatabase, "failed_attempts_increment", err, map[string]interface{}{"userId": userID.String()})
 	}
 
-	h.recordFailedLogin(userID.String(), ipAddress, "invalid password")
+	h.recordFailedLogin(userID.String(), email, ipAddress, "invalid password")
+}
+
+// lockoutDetails reports whether a login that meets a lockout is told when it ends. That needs the
+// lockouts of unknown emails, or the answer would tell registered emails apart.
+func (h *AuthHandler) lockoutDetails() bool {
+	return h.config != nil && h.config.LockoutDetails && h.unknownLockouts != nil
+}
+
+// unknownAccountLockedUntil returns when the lockout of an email no account has ends and true while
+// it is locked out. A lockout that cannot be read is logged and treated as none.
+func (h *AuthHandler) unknownAccountLockedUntil(email string) (time.Time, bool) {
+	if h.unknownLockouts == nil {
+		return time.Time{}, false
+	}
+	lockedUntil, locked, err := h.unknownLockouts.LockedUntil(context.Background(), email)
+	if err != nil {
+		logging.Error(logging.CategoryRateLimit, "unknown_account_lockout_check", err, nil)
+		return time.Time{}, false
+	}
+	return lockedUntil, locked
+}
+
+// countUnknownAccountFailure counts a failed login at an email no account has towards its lockout
+func (h *AuthHandler) countUnknownAccountFailure(email string) {
+	if h.unknownLockouts == nil {
+		return
+	}
+	if err := h.unknownLockouts.RecordFailure(context.Background(), email); err != nil {
+		logging.Error(logging.CategoryRateLimit, "unknown_account_lockout_count", err, nil)
+	}
+}
+
+// countLockedOutAttempt counts a login attempt made during a lockout without extending the lockout
+func (h *AuthHandler) countLockedOutAttempt(userID uuid.UUID, email, ipAddress string) {
+	query := `
+		UPDATE auth.users
+		SET failed_login_attempts = failed_login_attempts + 1,
+		    updated_at = NOW()
+		WHERE id = $1`
+
+	if _, err := h.db.Exec(query, userID); err != nil {
+		logging.Error(logging.CategoryDatabase, "failed_attempts_increment", err, map[string]interface{}{"userId": userID.String()})
+	}
+
+	h.recordFailedLogin(userID.String(), email, ipAddress, "account locked")
 }
 
 // resetFailedAttempts resets failed login attempts on successful authentication
@@ -420,7 +1080,7 @@ func (h *AuthHandler) resetFailedAttempts(userID uuid.UUID) {
 
 	_, err := h.db.Exec(query, userID)
 	if err != nil {
-		fmt.Printf("Failed to reset failed attempts for user %s: %v\n", userID, err)
+		logging.Error(logging.CategoryDatabase, "failed_attempts_reset", err, map[string]interface{}{"userId": userID.String()})
 	}
 }
 
@@ -436,7 +1096,7 @@ func (h *AuthHandler) unlockAccount(userID uuid.UUID) {
 
 	_, err := h.db.Exec(query, userID)
 	if err != nil {
-		fmt.Printf("Failed to unlock account for user %s: %v\n", userID, err)
+		logging.Error(logging.CategoryDatabase, "account_unlock", err, map[string]interface{}{"userId": userID.String()})
 	}
 }
 
@@ -451,15 +1111,22 @@ func (h *AuthHandler) updateLastLogin(userID uuid.UUID, ipAddress string) {
 
 	_, err := h.db.Exec(query, userID, ipAddress)
 	if err != nil {
-		fmt.Printf("Failed to update last login for user %s: %v\n", userID, err)
+		logging.Error(logging.CategoryDatabase, "last_login_update", err, map[string]interface{}{"userId": userID.String()})
 	}
 
 	// Record successful login in audit log
 	h.recordSuccessfulLogin(userID.String(), ipAddress)
 }
 
+// loginAuditDetails are the details of a login event in auth.auth_audit_log
+type loginAuditDetails struct {
+	Reason    string `json:"reason,omitempty"`
+	Email     string `json:"email,omitempty"` // The email the client tried, which may not belong to any user
+	Timestamp string `json:"timestamp"`
+}
+
 // recordFailedLogin records a failed login attempt in the audit log
-func (h *AuthHandler) recordFailedLogin(userID, ipAddress, reason string) {
+func (h *AuthHandler) recordFailedLogin(userID, email, ipAddress, reason string) {
 	var userUUID *uuid.UUID
 	if userID != "" {
 		if parsed, err := uuid.Parse(userID); err == nil {
@@ -467,15 +1134,9 @@ func (h *AuthHandler) recordFailedLogin(userID, ipAddress, reason string) {
 		}
 	}
 
-	query := `
-		INSERT INTO auth.auth_audit_log
-		(user_id, event_type, event_status, ip_address, details, risk_score, created_at)
-		VALUES ($1, 'login', 'failure', $2, $3, 3, NOW())`
-
-	details := fmt.Sprintf(`{"reason": "%s", "timestamp": "%s"}`, reason, time.Now().Format(time.RFC3339))
-	_, err := h.db.Exec(query, userUUID, ipAddress, details)
-	if err != nil {
-		fmt.Printf("Failed to record failed login: %v\n", err)
+	details := loginAuditDetails{Reason: reason, Email: email, Timestamp: time.Now().Format(time.RFC3339)}
+	if err := h.logAuthEvent(userUUID, "login", "failure", ipAddress, 3, details); err != nil {
+		logging.Error(logging.CategoryDatabase, "login_failure_audit", err, map[string]interface{}{"userId": userID})
 	}
 }
 
@@ -483,20 +1144,32 @@ func (h *AuthHandler) recordFailedLogin(userID, ipAddress, reason string) {
 func (h *AuthHandler) recordSuccessfulLogin(userID, ipAddress string) {
 	userUUID, err := uuid.Parse(userID)
 	if err != nil {
-		fmt.Printf("Invalid user ID for audit log: %s\n", userID)
+		logging.Error(logging.CategoryAuth, "login_success_audit", err, map[string]interface{}{"userId": userID})
 		return
 	}
 
+	details := loginAuditDetails{Timestamp: time.Now().Format(time.RFC3339)}
+	if err := h.logAuthEvent(&userUUID, "login", "success", ipAddress, 1, details); err != nil {
+		logging.Error(logging.CategoryDatabase, "login_success_audit", err, map[string]interface{}{"userId": userID})
+	}
+}
+
+// logAuthEvent writes an authentication event to auth.auth_audit_log with its details encoded as
+// JSON and passed as a query argument, so client-supplied values cannot break out of either
+func (h *AuthHandler) logAuthEvent(userID *uuid.UUID, eventType, eventStatus, ipAddress string, riskScore int, details interface{}) error {
+	encoded, err := models.NewAuditDetails(details)
+	if err != nil {
+		return fmt.Errorf("%s %s event: %w", eventType, eventStatus, err)
+	}
+
 	query := `
 		INSERT INTO auth.auth_audit_log
 		(user_id, event_type, event_status, ip_address, details, risk_score, created_at)
-		VALUES ($1, 'login', 'success', $2, $3, 1, NOW())`
-
-	details := fmt.Sprintf(`{"timestamp": "%s"}`, time.Now().Format(time.RFC3339))
-	_, err = h.db.Exec(query, userUUID, ipAddress, details)
-	if err != nil {
-		fmt.Printf("Failed to record successful login: %v\n", err)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())`
+	if _, err := h.db.Exec(query, userID, eventType, eventStatus, ipAddress, string(*encoded), riskScore); err != nil {
+		return fmt.Errorf("failed to write %s %s event: %w", eventType, eventStatus, err)
 	}
+	return nil
 }
 
 // GetPermissions returns all available permission strings in the system
@@ -542,22 +1215,13 @@ func (h *AuthHandler) GetPermissions(c *gin.Context) {
 // ListUsers handles GET /api/v2/admin/users
 func (h *AuthHandler) ListUsers(c *gin.Context) {
 	// Get pagination parameters
-	page := 1
-	limit := 10
-
-	if pageStr := c.Query("page"); pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
-		}
-	}
-
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
+	pagination, err := parsePagination(c, 10, DefaultMaxPageSize)
+	if err != nil {
+		respondWithPaginationErrorGin(c, err)
+		return
 	}
-
-	offset := (page - 1) * limit
+	page := pagination.Offset/pagination.Limit + 1
+	limit, offset := pagination.Limit, pagination.Offset
 
 	// Get users from database
 	query := `
@@ -620,13 +1284,17 @@ func (h *AuthHandler) ListUsers(c *gin.Context) {
 // CreateUser handles POST /api/v2/admin/users
 func (h *AuthHandler) CreateUser(c *gin.Context) {
 	var req models.CreateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request format")
+	if err := bindJSON(c, &req); err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request format: "+err.Error())
+		return
+	}
+	if err := h.policy().Validate(req.Password); err != nil {
+		respondWithPasswordPolicyError(c, "password", err)
 		return
 	}
 
 	// Hash the password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	hashedPassword, pepperVersion, err := h.hashPassword(req.Password)
 	if err != nil {
 		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to hash password")
 		return
@@ -635,12 +1303,12 @@ func (h *AuthHandler) CreateUser(c *gin.Context) {
 	// Create user in database
 	userID := uuid.New()
 	query := `
-		INSERT INTO auth.users (id, email, first_name, last_name, password_hash, is_active, mfa_enabled)
-		VALUES ($1, $2, $3, $4, $5, true, false)
+		INSERT INTO auth.users (id, email, first_name, last_name, password_hash, password_pepper_version, is_active, mfa_enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, true, false)
 		RETURNING created_at, updated_at`
 
 	var createdAt, updatedAt time.Time
-	err = h.db.QueryRow(query, userID, req.Email, req.FirstName, req.LastName, string(hashedPassword)).
+	err = h.db.QueryRow(query, userID, req.Email, req.FirstName, req.LastName, hashedPassword, pepperVersion).
 		Scan(&createdAt, &updatedAt)
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") {
@@ -718,8 +1386,8 @@ func (h *AuthHandler) UpdateUser(c *gin.Context) {
 	}
 
 	var req models.UpdateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request format")
+	if err := bindJSON(c, &req); err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request format: "+err.Error())
 		return
 	}
 
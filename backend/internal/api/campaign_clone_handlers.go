@@ -0,0 +1,79 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CloneCampaignRequest is the optional payload for cloning a campaign.
+type CloneCampaignRequest struct {
+	Name string `json:"name,omitempty"` // Defaults to the source campaign's name followed by " (copy)"
+}
+
+// cloneCampaign copies a campaign's settings into a new pending campaign
+// @Summary Clone a campaign
+// @Description Create a new pending campaign with a copy of the campaign's type-specific parameters, starting from the beginning. Results, jobs and history are not copied. The name defaults to the source campaign's name followed by " (copy)".
+// @Tags Campaigns
+// @Accept json
+// @Produce json
+// @Param campaignId path string true "Campaign ID"
+// @Param request body CloneCampaignRequest false "Name of the new campaign"
+// @Success 201 {object} models.CampaignAPI "Campaign cloned successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid campaign ID or request payload"
+// @Failure 404 {object} models.ErrorResponse "Campaign not found"
+// @Failure 409 {object} models.ErrorResponse "Campaign name already in use"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 503 {object} models.ErrorResponse "Campaign cloning is not available"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/clone [post]
+func (h *CampaignOrchestratorAPIHandler) cloneCampaign(c *gin.Context) {
+	campaignIDStr := c.Param("campaignId")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+	var req CloneCampaignRequest
+	if c.Request.ContentLength > 0 {
+		if err := bindJSON(c, &req); err != nil {
+			respondWithValidationErrorGin(c, []ErrorDetail{{
+				Field:   bindErrorField(err),
+				Code:    ErrorCodeValidation,
+				Message: "Invalid request payload: " + err.Error(),
+			}})
+			return
+		}
+	}
+	if h.cloner == nil {
+		respondWithErrorGin(c, http.StatusServiceUnavailable, "Campaign cloning is not available")
+		return
+	}
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
+
+	campaign, err := h.cloner.CloneCampaign(c.Request.Context(), campaignID, req.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			respondWithErrorGin(c, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, store.ErrCampaignNameTaken):
+			name := strings.TrimSpace(req.Name)
+			if campaign != nil {
+				name = campaign.Name
+			}
+			respondWithCampaignNameTaken(c, name)
+		default:
+			log.Printf("Error cloning campaign %s: %v", campaignIDStr, err)
+			respondWithErrorGin(c, http.StatusInternalServerError, "Failed to clone campaign")
+		}
+		return
+	}
+	respondWithJSONGin(c, http.StatusCreated, campaign)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cloningCampaignStore adds campaign creation and DNS validation params to lookupCampaignStore
+type cloningCampaignStore struct {
+	lookupCampaignStore
+	dnsParams map[uuid.UUID]*models.DNSValidationCampaignParams
+}
+
+func (s *cloningCampaignStore) CreateCampaign(ctx context.Context, exec store.Querier, campaign *models.Campaign) error {
+	for _, existing := range s.campaigns {
+		if existing.Name == campaign.Name {
+			return store.ErrCampaignNameTaken
+		}
+	}
+	s.campaigns[campaign.ID] = campaign
+	return nil
+}
+
+func (s *cloningCampaignStore) GetDNSValidationParams(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.DNSValidationCampaignParams, error) {
+	return s.dnsParams[campaignID], nil
+}
+
+func (s *cloningCampaignStore) CreateDNSValidationParams(ctx context.Context, exec store.Querier, params *models.DNSValidationCampaignParams) error {
+	s.dnsParams[params.CampaignID] = params
+	return nil
+}
+
+func TestCloneCampaignHandler(t *testing.T) {
+	source := &models.Campaign{ID: uuid.New(), Name: "Resolve sweep", CampaignType: models.CampaignTypeDNSValidation, Status: models.CampaignStatusCompleted}
+	cs := &cloningCampaignStore{
+		lookupCampaignStore: lookupCampaignStore{campaigns: map[uuid.UUID]*models.Campaign{source.ID: source}},
+		dnsParams: map[uuid.UUID]*models.DNSValidationCampaignParams{
+			source.ID: {CampaignID: source.ID, PersonaIDs: []uuid.UUID{uuid.New()}},
+		},
+	}
+	h := NewCampaignOrchestratorAPIHandler(
+		services.NewCampaignOrchestratorService(nil, cs, nil, nil, nil, nil, nil, nil, nil),
+		&memoryCampaignListViewStore{})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/campaigns/:campaignId/clone", h.cloneCampaign)
+	clone := func(id uuid.UUID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/campaigns/"+id.String()+"/clone", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, clone(source.ID, "").Code)
+	h.SetCampaignCloner(services.NewCampaignCloner(nil, cs))
+	assert.Equal(t, http.StatusNotFound, clone(uuid.New(), "").Code)
+
+	w := clone(source.ID, "")
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var envelope struct {
+		Data models.Campaign `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	assert.Equal(t, "Resolve sweep (copy)", envelope.Data.Name)
+	assert.Equal(t, models.CampaignStatusPending, envelope.Data.Status)
+	require.NotNil(t, envelope.Data.DNSValidationParams)
+	assert.Equal(t, cs.dnsParams[source.ID].PersonaIDs, envelope.Data.DNSValidationParams.PersonaIDs)
+
+	w = clone(source.ID, "")
+	assert.Equal(t, http.StatusConflict, w.Code, "the default name is now taken")
+	assert.Contains(t, w.Body.String(), "Resolve sweep (copy)")
+	assert.Equal(t, http.StatusCreated, clone(source.ID, `{"name":"Resolve sweep (2)"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, clone(source.ID, `{"name":`).Code)
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// comparedCampaignStore holds HTTP keyword campaigns whose results are given in domain order
+type comparedCampaignStore struct {
+	store.CampaignStore
+	campaigns map[uuid.UUID]*models.Campaign
+	results   map[uuid.UUID][]store.CampaignResultStatus
+}
+
+func (s *comparedCampaignStore) GetCampaignByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Campaign, error) {
+	campaign, ok := s.campaigns[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return campaign, nil
+}
+
+func (s *comparedCampaignStore) ListHTTPKeywordStatusesAfter(ctx context.Context, exec store.Querier, campaignID uuid.UUID, afterDomain string, limit int) ([]store.CampaignResultStatus, error) {
+	page := []store.CampaignResultStatus{}
+	for _, result := range s.results[campaignID] {
+		if result.DomainName > afterDomain && len(page) < limit {
+			page = append(page, result)
+		}
+	}
+	return page, nil
+}
+
+func getComparison(h *CampaignOrchestratorAPIHandler, path string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/campaigns/:campaignId/compare/:otherCampaignId", h.compareCampaigns)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestCompareCampaigns_HTTPKeywordCampaigns(t *testing.T) {
+	leftID, rightID, dnsID := uuid.New(), uuid.New(), uuid.New()
+	cs := &comparedCampaignStore{
+		campaigns: map[uuid.UUID]*models.Campaign{
+			leftID:  {ID: leftID, CampaignType: models.CampaignTypeHTTPKeywordValidation},
+			rightID: {ID: rightID, CampaignType: models.CampaignTypeHTTPKeywordValidation},
+			dnsID:   {ID: dnsID, CampaignType: models.CampaignTypeDNSValidation},
+		},
+		results: map[uuid.UUID][]store.CampaignResultStatus{
+			leftID:  {{DomainName: "a.com", ValidationStatus: "Success"}, {DomainName: "b.com", ValidationStatus: "Success"}},
+			rightID: {{DomainName: "b.com", ValidationStatus: "Timeout"}, {DomainName: "c.com", ValidationStatus: "Success"}},
+		},
+	}
+
+	h := NewCampaignOrchestratorAPIHandler(&creatingOrchestratorService{}, &memoryCampaignListViewStore{})
+	path := "/campaigns/" + leftID.String() + "/compare/" + rightID.String()
+	assert.Equal(t, http.StatusServiceUnavailable, getComparison(h, path).Code)
+
+	h.SetCompareService(services.NewCampaignCompareService(nil, nil, cs))
+
+	w := getComparison(h, path+"?limit=0")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "limit")
+	assert.Equal(t, http.StatusBadRequest, getComparison(h, "/campaigns/"+leftID.String()+"/compare/"+dnsID.String()).Code)
+	assert.Equal(t, http.StatusNotFound, getComparison(h, "/campaigns/"+leftID.String()+"/compare/"+uuid.New().String()).Code)
+
+	w = getComparison(h, path)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data services.CampaignComparison `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, services.CampaignCompareCounts{LeftTotal: 2, RightTotal: 2, OnlyLeft: 1, OnlyRight: 1, StatusChanged: 1}, resp.Data.Counts)
+	assert.Equal(t, []services.CampaignResultDiff{
+		{DomainName: "a.com", Kind: services.CampaignDiffOnlyLeft, LeftStatus: "Success"},
+		{DomainName: "b.com", Kind: services.CampaignDiffStatusChanged, LeftStatus: "Success", RightStatus: "Timeout"},
+		{DomainName: "c.com", Kind: services.CampaignDiffOnlyRight, RightStatus: "Success"},
+	}, resp.Data.Differences)
+	assert.False(t, resp.Data.Truncated)
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stalledCampaignStore holds one domain generation campaign
+type stalledCampaignStore struct {
+	store.CampaignStore
+	campaign *models.Campaign
+}
+
+func (s *stalledCampaignStore) GetCampaignByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Campaign, error) {
+	if id != s.campaign.ID {
+		return nil, store.ErrNotFound
+	}
+	return s.campaign, nil
+}
+
+func (s *stalledCampaignStore) GetDomainGenerationParams(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.DomainGenerationCampaignParams, error) {
+	return &models.DomainGenerationCampaignParams{CampaignID: campaignID, NumDomainsToGenerate: 1000, CurrentOffset: 250}, nil
+}
+
+// singleJobStore lists one job for every campaign
+type singleJobStore struct {
+	store.CampaignJobStore
+	job *models.CampaignJob
+}
+
+func (s *singleJobStore) ListJobs(ctx context.Context, filter store.ListJobsFilter) ([]*models.CampaignJob, error) {
+	return []*models.CampaignJob{s.job}, nil
+}
+
+func getDiagnostics(h *CampaignOrchestratorAPIHandler, path string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/campaigns/:campaignId/diagnostics", h.getCampaignDiagnostics)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestGetCampaignDiagnostics_StalledCampaign(t *testing.T) {
+	stalledAt := time.Now().UTC().Add(-10 * time.Minute)
+	campaign := &models.Campaign{ID: uuid.New(), CampaignType: models.CampaignTypeDomainGeneration, Status: models.CampaignStatusRunning, UpdatedAt: stalledAt}
+	job := &models.CampaignJob{ID: uuid.New(), CampaignID: campaign.ID, Status: models.JobStatusProcessing, Attempts: 1, CreatedAt: stalledAt, UpdatedAt: stalledAt}
+
+	h := NewCampaignOrchestratorAPIHandler(&creatingOrchestratorService{}, &memoryCampaignListViewStore{})
+	path := "/campaigns/" + campaign.ID.String() + "/diagnostics"
+	assert.Equal(t, http.StatusServiceUnavailable, getDiagnostics(h, path).Code)
+
+	h.SetDiagnosticsService(services.NewCampaignDiagnosticsService(nil, nil, &stalledCampaignStore{campaign: campaign}, &singleJobStore{job: job}, nil, nil, nil))
+
+	w := getDiagnostics(h, path+"?errorLimit=500")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "errorLimit")
+
+	assert.Equal(t, http.StatusNotFound, getDiagnostics(h, "/campaigns/"+uuid.New().String()+"/diagnostics").Code)
+
+	w = getDiagnostics(h, path)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data services.CampaignDiagnostics `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	diag := resp.Data
+	assert.Equal(t, models.CampaignStatusRunning, diag.Status)
+	assert.False(t, diag.Stalled, "ten minutes is inside the default stall window")
+	require.NotNil(t, diag.LatestJob)
+	assert.Equal(t, job.ID, diag.LatestJob.ID)
+	require.NotNil(t, diag.Position.CurrentOffset)
+	assert.Equal(t, int64(250), *diag.Position.CurrentOffset)
+
+	job.Status = models.JobStatusFailed
+	job.LastError.String, job.LastError.Valid = "generator crashed", true
+	w = getDiagnostics(h, path)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Data.Stalled)
+	assert.Equal(t, "latest job failed", resp.Data.StalledReason)
+	assert.Contains(t, w.Body.String(), "generator crashed")
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// validCampaignSortColumns are the campaign list sort columns supported by the store
+var validCampaignSortColumns = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"name":       true,
+	"status":     true,
+}
+
+// CreateCampaignListViewRequest is the payload for saving a named campaign list view.
+type CreateCampaignListViewRequest struct {
+	Name      string                    `json:"name" validate:"required,min=1,max=100"`
+	Status    models.CampaignStatusEnum `json:"status,omitempty" validate:"omitempty,oneof=pending queued running pausing paused completed failed archived cancelled"`
+	Type      models.CampaignTypeEnum   `json:"type,omitempty" validate:"omitempty,oneof=domain_generation dns_validation http_keyword_validation"`
+	SortBy    string                    `json:"sortBy,omitempty" validate:"omitempty,oneof=created_at updated_at name status"`
+	SortOrder string                    `json:"sortOrder,omitempty" validate:"omitempty,oneof=asc desc"`
+	PageSize  int                       `json:"pageSize,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// currentUserID returns the authenticated user's ID from the security context
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	value, exists := c.Get("security_context")
+	if !exists {
+		return uuid.Nil, false
+	}
+	securityContext, ok := value.(*models.SecurityContext)
+	if !ok || securityContext.UserID == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return securityContext.UserID, true
+}
+
+// resolveCampaignListView loads the saved view named by the ?view= query parameter.
+// It returns (nil, true) when no view was requested and writes an error response
+// and returns false when the view cannot be applied.
+func (h *CampaignOrchestratorAPIHandler) resolveCampaignListView(c *gin.Context) (*models.CampaignListView, bool) {
+	name := strings.TrimSpace(c.Query("view"))
+	if name == "" {
+		return nil, true
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		respondWithErrorGin(c, http.StatusUnauthorized, "Authentication required")
+		return nil, false
+	}
+	if h.listViewStore == nil {
+		respondWithErrorGin(c, http.StatusInternalServerError, "Saved views are not available")
+		return nil, false
+	}
+
+	view, err := h.listViewStore.GetCampaignListViewByName(c.Request.Context(), nil, userID, name)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithDetailedErrorGin(c, http.StatusNotFound, ErrorCodeNotFound,
+				"Saved view not found", []ErrorDetail{
+					{
+						Field:   "view",
+						Code:    ErrorCodeNotFound,
+						Message: "No saved view named " + name,
+					},
+				})
+			return nil, false
+		}
+		log.Printf("Error loading campaign list view %q for user %s: %v", name, userID, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to load saved view")
+		return nil, false
+	}
+	return view, true
+}
+
+// listCampaignListViews lists the caller's saved campaign list views
+// @Summary List saved campaign list views
+// @Description Retrieve the authenticated user's saved campaign list views
+// @Tags Campaigns
+// @Produce json
+// @Success 200 {array} models.CampaignListView "Saved views"
+// @Failure 401 {object} models.ErrorResponse "Authentication required"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /campaigns/views [get]
+func (h *CampaignOrchestratorAPIHandler) listCampaignListViews(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		respondWithErrorGin(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if h.listViewStore == nil {
+		respondWithErrorGin(c, http.StatusInternalServerError, "Saved views are not available")
+		return
+	}
+
+	views, err := h.listViewStore.ListCampaignListViews(c.Request.Context(), nil, userID)
+	if err != nil {
+		log.Printf("Error listing campaign list views for user %s: %v", userID, err)
+		respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeDatabaseError,
+			"Failed to retrieve saved views", nil)
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, views)
+}
+
+// createCampaignListView saves a named campaign list view for the caller
+// @Summary Save a campaign list view
+// @Description Save a named set of campaign list filters and sort options, applied later with ?view=<name>
+// @Tags Campaigns
+// @Accept json
+// @Produce json
+// @Param request body CreateCampaignListViewRequest true "View definition"
+// @Success 201 {object} models.CampaignListView "Saved view"
+// @Failure 400 {object} models.ErrorResponse "Invalid request payload"
+// @Failure 401 {object} models.ErrorResponse "Authentication required"
+// @Failure 409 {object} models.ErrorResponse "A view with this name already exists"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /campaigns/views [post]
+func (h *CampaignOrchestratorAPIHandler) createCampaignListView(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		respondWithErrorGin(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if h.listViewStore == nil {
+		respondWithErrorGin(c, http.StatusInternalServerError, "Saved views are not available")
+		return
+	}
+
+	var req CreateCampaignListViewRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if err := validate.Struct(req); err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Validation failed: "+err.Error())
+		return
+	}
+
+	view := &models.CampaignListView{
+		UserID:       userID,
+		Name:         req.Name,
+		Status:       req.Status,
+		CampaignType: req.Type,
+		SortBy:       req.SortBy,
+		SortOrder:    req.SortOrder,
+		PageSize:     req.PageSize,
+	}
+	if err := h.listViewStore.CreateCampaignListView(c.Request.Context(), nil, view); err != nil {
+		if errors.Is(err, store.ErrDuplicateEntry) {
+			respondWithErrorGin(c, http.StatusConflict, "A saved view named "+req.Name+" already exists")
+			return
+		}
+		log.Printf("Error saving campaign list view %q for user %s: %v", req.Name, userID, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to save view")
+		return
+	}
+	respondWithJSONGin(c, http.StatusCreated, view)
+}
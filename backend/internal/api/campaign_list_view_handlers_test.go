@@ -0,0 +1,181 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCampaignListViewStore is an in-memory store.CampaignListViewStore for tests
+type memoryCampaignListViewStore struct {
+	mu    sync.Mutex
+	views []*models.CampaignListView
+}
+
+func (m *memoryCampaignListViewStore) CreateCampaignListView(ctx context.Context, exec store.Querier, view *models.CampaignListView) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.views {
+		if existing.UserID == view.UserID && existing.Name == view.Name {
+			return store.ErrDuplicateEntry
+		}
+	}
+	view.ID = uuid.New()
+	v := *view
+	m.views = append(m.views, &v)
+	return nil
+}
+
+func (m *memoryCampaignListViewStore) GetCampaignListViewByName(ctx context.Context, exec store.Querier, userID uuid.UUID, name string) (*models.CampaignListView, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, view := range m.views {
+		if view.UserID == userID && view.Name == name {
+			v := *view
+			return &v, nil
+		}
+	}
+	return nil, store.ErrNotFound
+}
+
+func (m *memoryCampaignListViewStore) ListCampaignListViews(ctx context.Context, exec store.Querier, userID uuid.UUID) ([]*models.CampaignListView, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := []*models.CampaignListView{}
+	for _, view := range m.views {
+		if view.UserID == userID {
+			v := *view
+			result = append(result, &v)
+		}
+	}
+	return result, nil
+}
+
+// recordingOrchestratorService captures the filter passed to ListCampaigns
+type recordingOrchestratorService struct {
+	services.CampaignOrchestratorService
+	lastFilter store.ListCampaignsFilter
+}
+
+func (s *recordingOrchestratorService) ListCampaigns(ctx context.Context, filter store.ListCampaignsFilter) ([]models.Campaign, int64, error) {
+	s.lastFilter = filter
+	return []models.Campaign{}, 0, nil
+}
+
+func newCampaignListViewRouter(h *CampaignOrchestratorAPIHandler, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("security_context", &models.SecurityContext{UserID: userID})
+		c.Next()
+	})
+	router.GET("/campaigns", h.listCampaigns)
+	router.GET("/campaigns/views", h.listCampaignListViews)
+	router.POST("/campaigns/views", h.createCampaignListView)
+	return router
+}
+
+func TestCampaignListViews_ViewAppliesStoredFilters(t *testing.T) {
+	orchestrator := &recordingOrchestratorService{}
+	h := NewCampaignOrchestratorAPIHandler(orchestrator, &memoryCampaignListViewStore{})
+	userID := uuid.New()
+	router := newCampaignListViewRouter(h, userID)
+
+	body := `{"name":"running-dns","status":"running","type":"dns_validation","sortBy":"updated_at","sortOrder":"desc","pageSize":50}`
+	req := httptest.NewRequest(http.MethodPost, "/campaigns/views", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/campaigns/views", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var listResp struct {
+		Data []models.CampaignListView `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listResp))
+	require.Len(t, listResp.Data, 1)
+	assert.Equal(t, "running-dns", listResp.Data[0].Name)
+	assert.Equal(t, userID, listResp.Data[0].UserID)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/campaigns?view=running-dns", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, models.CampaignStatusRunning, orchestrator.lastFilter.Status)
+	assert.Equal(t, models.CampaignTypeDNSValidation, orchestrator.lastFilter.Type)
+	assert.Equal(t, "updated_at", orchestrator.lastFilter.SortBy)
+	assert.Equal(t, "desc", orchestrator.lastFilter.SortOrder)
+	assert.Equal(t, 50, orchestrator.lastFilter.Limit)
+
+	// Explicit parameters override the saved view
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/campaigns?view=running-dns&status=paused&limit=10", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.CampaignStatusPaused, orchestrator.lastFilter.Status)
+	assert.Equal(t, models.CampaignTypeDNSValidation, orchestrator.lastFilter.Type)
+	assert.Equal(t, 10, orchestrator.lastFilter.Limit)
+}
+
+func TestCampaignListViews_ViewsArePerUser(t *testing.T) {
+	viewStore := &memoryCampaignListViewStore{}
+	owner := uuid.New()
+	require.NoError(t, viewStore.CreateCampaignListView(context.Background(), nil, &models.CampaignListView{
+		UserID: owner,
+		Name:   "mine",
+		Status: models.CampaignStatusFailed,
+	}))
+	h := NewCampaignOrchestratorAPIHandler(&recordingOrchestratorService{}, viewStore)
+
+	w := httptest.NewRecorder()
+	newCampaignListViewRouter(h, uuid.New()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/campaigns?view=mine", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCampaignListViews_RejectsDuplicateAndInvalidViews(t *testing.T) {
+	h := NewCampaignOrchestratorAPIHandler(&recordingOrchestratorService{}, &memoryCampaignListViewStore{})
+	router := newCampaignListViewRouter(h, uuid.New())
+
+	post := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/campaigns/views", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusCreated, post(`{"name":"recent","sortBy":"created_at"}`))
+	assert.Equal(t, http.StatusConflict, post(`{"name":"recent"}`))
+	assert.Equal(t, http.StatusBadRequest, post(`{"name":"bad-sort","sortBy":"password"}`))
+}
+
+func TestListCampaigns_TagFilter(t *testing.T) {
+	orchestrator := &recordingOrchestratorService{}
+	router := newCampaignListViewRouter(NewCampaignOrchestratorAPIHandler(orchestrator, &memoryCampaignListViewStore{}), uuid.New())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/campaigns?tags=Client-Acme,%20staging&tags=client-acme&tagMatch=all", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []string{"client-acme", "staging"}, orchestrator.lastFilter.Tags)
+	assert.Equal(t, store.TagMatchAll, orchestrator.lastFilter.TagMatch)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/campaigns?tags=production", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, store.TagMatchAny, orchestrator.lastFilter.TagMatch)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/campaigns?tags=production&tagMatch=some", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// namedOrchestratorService fails creation the way the store does when the name is already taken
+type namedOrchestratorService struct {
+	services.CampaignOrchestratorService
+	taken map[string]bool
+}
+
+func (s *namedOrchestratorService) CreateCampaignUnified(ctx context.Context, req services.CreateCampaignRequest) (*models.Campaign, error) {
+	if s.taken[req.Name] {
+		return nil, fmt.Errorf("failed to create campaign record: %w", store.ErrCampaignNameTaken)
+	}
+	return &models.Campaign{ID: uuid.New(), Name: req.Name}, nil
+}
+
+func TestCreateCampaign_TakenNameIsConflict(t *testing.T) {
+	h := NewCampaignOrchestratorAPIHandler(&namedOrchestratorService{taken: map[string]bool{"owned personas": true}}, &memoryCampaignListViewStore{})
+
+	w := postCampaignAs(h, &models.SecurityContext{UserID: uuid.New(), Roles: []string{"user"}}, uuid.New())
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"field":"name"`)
+	assert.Contains(t, w.Body.String(), "already have a campaign named")
+
+	// With the rule off the store never reports a taken name
+	h = NewCampaignOrchestratorAPIHandler(&namedOrchestratorService{}, &memoryCampaignListViewStore{})
+	w = postCampaignAs(h, &models.SecurityContext{UserID: uuid.New(), Roles: []string{"user"}}, uuid.New())
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// lookupCampaignStore answers GetCampaignByID from a map
+type lookupCampaignStore struct {
+	store.CampaignStore
+	campaigns map[uuid.UUID]*models.Campaign
+}
+
+func (s *lookupCampaignStore) GetCampaignByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Campaign, error) {
+	campaign, ok := s.campaigns[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return campaign, nil
+}
+
+func TestCampaignHandlers_UnknownCampaignIsNotFound(t *testing.T) {
+	running := &models.Campaign{ID: uuid.New(), CampaignType: models.CampaignTypeDNSValidation, Status: models.CampaignStatusRunning}
+	cs := &lookupCampaignStore{campaigns: map[uuid.UUID]*models.Campaign{running.ID: running}}
+	h := NewCampaignOrchestratorAPIHandler(
+		services.NewCampaignOrchestratorService(nil, cs, nil, nil, nil, nil, nil, nil, nil),
+		&memoryCampaignListViewStore{})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/campaigns/:campaignId", h.getCampaignDetails)
+	router.GET("/campaigns/:campaignId/status", h.getCampaignStatus)
+	router.POST("/campaigns/:campaignId/start", h.startCampaign)
+
+	serve := func(method, path string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w.Code
+	}
+	unknown := "/campaigns/" + uuid.New().String()
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, unknown))
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, unknown+"/status"))
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, unknown+"/start"))
+
+	assert.Equal(t, http.StatusConflict, serve(http.MethodPost, "/campaigns/"+running.ID.String()+"/start"),
+		"only pending campaigns can be started")
+}
@@ -2,11 +2,17 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/domainexpert"
 	"github.com/fntelecomllc/studio/backend/internal/middleware"
 	"github.com/fntelecomllc/studio/backend/internal/models"
 	"github.com/fntelecomllc/studio/backend/internal/services"
@@ -21,12 +27,106 @@ import (
 // CampaignOrchestratorAPIHandler holds dependencies for campaign orchestration API endpoints.
 type CampaignOrchestratorAPIHandler struct {
 	orchestratorService services.CampaignOrchestratorService
-	// No direct store access needed here, orchestrator service handles it.
+	// Campaign data goes through the orchestrator service; saved list views are user preferences
+	listViewStore store.CampaignListViewStore
+	// Set when persona ownership is enforced at campaign creation
+	resourceAccess *services.ResourceAccessChecker
+	// Set when domain generation TLDs are validated against the delegated TLDs
+	tldList *domainexpert.TLDList
+	// Answers the diagnostics endpoint; it responds 503 while unset
+	diagnostics *services.CampaignDiagnosticsService
+	// Answers the compare endpoint; it responds 503 while unset
+	compare *services.CampaignCompareService
+	// Answers the retry chain endpoint; it responds 503 while unset
+	autoRetrier *services.CampaignAutoRetrier
+	// Campaign IDs one bulk status request may ask for; config.DefaultCampaignStatusMaxBulkIDs while unset
+	maxBulkStatusIDs int
+	// Answers the campaign webhook endpoints; they respond 503 while unset
+	campaignWebhooks *services.CampaignWebhookService
+	// Answers the CSV result export endpoints; they respond 503 while unset
+	resultExport *services.CampaignResultExportService
+	// Answers the re-classification endpoint; it responds 503 while unset
+	reclassifier *services.HTTPKeywordReclassifier
+	// Answers the domain explanation endpoint; it responds 503 while unset
+	explainer *services.CampaignResultExplainer
+	// Answers the clone endpoint; it responds 503 while unset
+	cloner *services.CampaignCloner
+	// Answers the schedule endpoints; they respond 503 while unset
+	scheduler *services.CampaignScheduler
+	// Limits campaign creation and cloning per user; creation is unlimited while unset
+	createRateLimit gin.HandlerFunc
 }
 
 // NewCampaignOrchestratorAPIHandler creates a new handler for campaign orchestration.
-func NewCampaignOrchestratorAPIHandler(orchService services.CampaignOrchestratorService) *CampaignOrchestratorAPIHandler {
-	return &CampaignOrchestratorAPIHandler{orchestratorService: orchService}
+func NewCampaignOrchestratorAPIHandler(orchService services.CampaignOrchestratorService, listViewStore store.CampaignListViewStore) *CampaignOrchestratorAPIHandler {
+	return &CampaignOrchestratorAPIHandler{orchestratorService: orchService, listViewStore: listViewStore}
+}
+
+// SetResourceAccessChecker makes campaign creation reject personas the requesting user neither owns
+// nor has shared with them. Admins are not checked.
+func (h *CampaignOrchestratorAPIHandler) SetResourceAccessChecker(checker *services.ResourceAccessChecker) {
+	h.resourceAccess = checker
+}
+
+// SetTLDList makes campaign creation reject domain generation TLDs that are not on list
+func (h *CampaignOrchestratorAPIHandler) SetTLDList(list *domainexpert.TLDList) {
+	h.tldList = list
+}
+
+// SetDiagnosticsService enables the campaign diagnostics endpoint
+func (h *CampaignOrchestratorAPIHandler) SetDiagnosticsService(diagnostics *services.CampaignDiagnosticsService) {
+	h.diagnostics = diagnostics
+}
+
+// SetCompareService enables the campaign compare endpoint
+func (h *CampaignOrchestratorAPIHandler) SetCompareService(compare *services.CampaignCompareService) {
+	h.compare = compare
+}
+
+// SetAutoRetrier enables the campaign retry chain endpoint
+func (h *CampaignOrchestratorAPIHandler) SetAutoRetrier(retrier *services.CampaignAutoRetrier) {
+	h.autoRetrier = retrier
+}
+
+// SetMaxBulkStatusIDs caps the campaign IDs of one bulk status request. Values <= 0 keep the default.
+func (h *CampaignOrchestratorAPIHandler) SetMaxBulkStatusIDs(limit int) {
+	h.maxBulkStatusIDs = limit
+}
+
+// SetCampaignWebhookService enables the campaign webhook endpoints
+func (h *CampaignOrchestratorAPIHandler) SetCampaignWebhookService(campaignWebhooks *services.CampaignWebhookService) {
+	h.campaignWebhooks = campaignWebhooks
+}
+
+// SetResultExportService enables the CSV result export endpoints
+func (h *CampaignOrchestratorAPIHandler) SetResultExportService(resultExport *services.CampaignResultExportService) {
+	h.resultExport = resultExport
+}
+
+// SetReclassifier enables the HTTP keyword result re-classification endpoint
+func (h *CampaignOrchestratorAPIHandler) SetReclassifier(reclassifier *services.HTTPKeywordReclassifier) {
+	h.reclassifier = reclassifier
+}
+
+// SetResultExplainer enables the domain explanation endpoint
+func (h *CampaignOrchestratorAPIHandler) SetResultExplainer(explainer *services.CampaignResultExplainer) {
+	h.explainer = explainer
+}
+
+// SetCampaignCloner enables the campaign clone endpoint
+func (h *CampaignOrchestratorAPIHandler) SetCampaignCloner(cloner *services.CampaignCloner) {
+	h.cloner = cloner
+}
+
+// SetCampaignScheduler enables the campaign schedule endpoints
+func (h *CampaignOrchestratorAPIHandler) SetCampaignScheduler(scheduler *services.CampaignScheduler) {
+	h.scheduler = scheduler
+}
+
+// SetCreateRateLimit limits campaign creation and cloning with limit, such as a
+// middleware.RateLimitMiddleware.RouteRateLimit. It must be set before the routes are registered.
+func (h *CampaignOrchestratorAPIHandler) SetCreateRateLimit(limit gin.HandlerFunc) {
+	h.createRateLimit = limit
 }
 
 // RegisterCampaignOrchestrationRoutes registers all campaign orchestration related routes.
@@ -41,32 +141,63 @@ func NewCampaignOrchestratorAPIHandler(orchService services.CampaignOrchestrator
 // - All legacy type-specific endpoints have been removed in favor of this unified approach
 func (h *CampaignOrchestratorAPIHandler) RegisterCampaignOrchestrationRoutes(group *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
 	// === CAMPAIGN CREATION ENDPOINTS ===
-	
+
+	// Creating and cloning campaigns share one rate limit per user once one is set
+	limitCreation := h.createRateLimit
+	if limitCreation == nil {
+		limitCreation = func(c *gin.Context) {}
+	}
+
 	// Unified campaign creation endpoint (preferred)
 	// Supports all campaign types through discriminated union
-	group.POST("", authMiddleware.RequirePermission("campaigns:create"), h.createCampaign)
+	// Non-admin users may only reference personas they own or that are shared
+	group.POST("", authMiddleware.RequirePermission("campaigns:create"), limitCreation, authMiddleware.ScopeToOwner("personas"), h.createCampaign)
 
 	// Campaign reading routes - require campaigns:read permission
-	group.GET("", authMiddleware.RequirePermission("campaigns:read"), h.listCampaigns)
-	group.GET("/:campaignId", authMiddleware.RequirePermission("campaigns:read"), h.getCampaignDetails)
-	// group.GET("/:campaignId/status", authMiddleware.RequirePermission("campaigns:read"), h.getCampaignStatus)
+	// Non-admin users only see campaigns they own
+	scopeCampaigns := authMiddleware.ScopeToOwner("campaigns")
+
+	// Cloning creates a pending copy of a campaign the user may see
+	group.POST("/:campaignId/clone", authMiddleware.RequirePermission("campaigns:create"), limitCreation, scopeCampaigns, h.cloneCampaign)
+	group.GET("", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.listCampaigns)
+	group.GET("/views", authMiddleware.RequirePermission("campaigns:read"), h.listCampaignListViews)
+	group.GET("/status", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.getCampaignStatuses)
+	group.POST("/views", authMiddleware.RequirePermission("campaigns:read"), h.createCampaignListView)
+	group.GET("/:campaignId", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.getCampaignDetails)
+	group.GET("/:campaignId/jobs", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.getCampaignJobs)
+	group.GET("/:campaignId/dedup-decisions", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.getCampaignDedupDecisions)
+	group.GET("/:campaignId/stats", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.getCampaignStats)
+	group.GET("/:campaignId/status-history", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.getCampaignStatusHistory)
+	group.GET("/:campaignId/diagnostics", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.getCampaignDiagnostics)
+	group.GET("/:campaignId/compare/:otherCampaignId", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.compareCampaigns)
+	group.GET("/:campaignId/retries", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.getCampaignRetryChain)
+	group.GET("/:campaignId/status", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.getCampaignStatus)
+	group.GET("/:campaignId/schedule", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.getCampaignSchedule)
 
 	// Campaign control routes - require campaigns:execute permission
 	group.POST("/:campaignId/start", authMiddleware.RequirePermission("campaigns:execute"), h.startCampaign)
 	group.POST("/:campaignId/pause", authMiddleware.RequirePermission("campaigns:execute"), h.pauseCampaign)
 	group.POST("/:campaignId/resume", authMiddleware.RequirePermission("campaigns:execute"), h.resumeCampaign)
 	group.POST("/:campaignId/cancel", authMiddleware.RequirePermission("campaigns:execute"), h.cancelCampaign)
+	group.POST("/:campaignId/reclassify", authMiddleware.RequirePermission("campaigns:execute"), scopeCampaigns, h.reclassifyCampaignResults)
+	group.POST("/:campaignId/schedule", authMiddleware.RequirePermission("campaigns:execute"), scopeCampaigns, h.scheduleCampaign)
+	group.DELETE("/:campaignId/schedule", authMiddleware.RequirePermission("campaigns:execute"), scopeCampaigns, h.unscheduleCampaign)
 
 	// Campaign modification routes - require campaigns:update permission
-	// group.PUT("/:campaignId", authMiddleware.RequirePermission("campaigns:update"), h.updateCampaign)
+	group.PUT("/:campaignId", authMiddleware.RequirePermission("campaigns:update"), scopeCampaigns, h.updateCampaign)
+	group.POST("/:campaignId/webhooks", authMiddleware.RequirePermission("campaigns:update"), scopeCampaigns, h.createCampaignWebhook)
+	group.DELETE("/:campaignId/webhooks/:webhookId", authMiddleware.RequirePermission("campaigns:update"), scopeCampaigns, h.deleteCampaignWebhook)
 
 	// Campaign deletion routes - require campaigns:delete permission
 	group.DELETE("/:campaignId", authMiddleware.RequirePermission("campaigns:delete"), h.deleteCampaign)
 
 	// Campaign results routes - require campaigns:read permission
-	group.GET("/:campaignId/results/generated-domains", authMiddleware.RequirePermission("campaigns:read"), h.getGeneratedDomains)
-	group.GET("/:campaignId/results/dns-validation", authMiddleware.RequirePermission("campaigns:read"), h.getDNSValidationResults)
-	group.GET("/:campaignId/results/http-keyword", authMiddleware.RequirePermission("campaigns:read"), h.getHTTPKeywordResults)
+	group.GET("/:campaignId/results/generated-domains", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.getGeneratedDomains)
+	group.GET("/:campaignId/results/dns-validation", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.getDNSValidationResults)
+	group.GET("/:campaignId/results/http-keyword", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.getHTTPKeywordResults)
+	group.GET("/:campaignId/results/dns-validation.csv", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.exportDNSValidationResultsCSV)
+	group.GET("/:campaignId/results/http-keyword.csv", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.exportHTTPKeywordResultsCSV)
+	group.GET("/:campaignId/results/:domain/explain", authMiddleware.RequirePermission("campaigns:read"), scopeCampaigns, h.explainCampaignDomain)
 }
 
 // --- Unified Campaign Creation Handler ---
@@ -81,17 +212,18 @@ func (h *CampaignOrchestratorAPIHandler) RegisterCampaignOrchestrationRoutes(gro
 // @Success 201 {object} models.CampaignAPI "Campaign created successfully"
 // @Failure 400 {object} models.ErrorResponse "Invalid request payload"
 // @Failure 401 {object} models.ErrorResponse "Authentication required"
-// @Failure 403 {object} models.ErrorResponse "Insufficient permissions"
+// @Failure 403 {object} models.ErrorResponse "Insufficient permissions or a referenced persona is not owned by or shared with the user"
+// @Failure 409 {object} models.ErrorResponse "Unique campaign names are enforced and the user already has a campaign with this name"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Security SessionAuth
 // @Router /campaigns [post]
 func (h *CampaignOrchestratorAPIHandler) createCampaign(c *gin.Context) {
 	var req services.CreateCampaignRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindJSON(c, &req); err != nil {
 		// Use validation error response for binding errors
 		var validationErrors []ErrorDetail
 		validationErrors = append(validationErrors, ErrorDetail{
-			Field:   "body",
+			Field:   bindErrorField(err),
 			Code:    ErrorCodeValidation,
 			Message: "Invalid request payload: " + err.Error(),
 		})
@@ -124,8 +256,27 @@ func (h *CampaignOrchestratorAPIHandler) createCampaign(c *gin.Context) {
 		return
 	}
 
+	if ownerFilter, scoped := middleware.GetOwnerFilter(c); scoped && h.resourceAccess != nil {
+		if err := h.resourceAccess.CheckCampaignRequest(c.Request.Context(), ownerFilter.UserID, req); err != nil {
+			if errors.Is(err, services.ErrResourceAccessDenied) {
+				respondWithDetailedErrorGin(c, http.StatusForbidden, ErrorCodeForbidden,
+					"Campaign references a resource you may not use", []ErrorDetail{
+						{Code: ErrorCodeForbidden, Message: err.Error()},
+					})
+				return
+			}
+			log.Printf("Error checking persona access for campaign '%s': %v", req.Name, err)
+			respondWithErrorGin(c, http.StatusInternalServerError, "Failed to verify persona access")
+			return
+		}
+	}
+
 	// Create campaign using the orchestrator service
-	campaign, err := h.orchestratorService.CreateCampaignUnified(c.Request.Context(), req)
+	campaign, err := h.orchestratorService.CreateCampaignUnified(statusActorContext(c, "created"), req)
+	if errors.Is(err, store.ErrCampaignNameTaken) {
+		respondWithCampaignNameTaken(c, req.Name)
+		return
+	}
 	if err != nil {
 		log.Printf("Error creating campaign: %v", err)
 		// Use detailed error response with appropriate error code
@@ -144,6 +295,18 @@ func (h *CampaignOrchestratorAPIHandler) createCampaign(c *gin.Context) {
 	respondWithJSONGin(c, http.StatusCreated, campaign)
 }
 
+// respondWithCampaignNameTaken sends 409 for a name the user already has on another campaign
+func respondWithCampaignNameTaken(c *gin.Context, name string) {
+	respondWithDetailedErrorGin(c, http.StatusConflict, ErrorCodeConflict,
+		"Campaign name already in use", []ErrorDetail{
+			{
+				Field:   "name",
+				Code:    ErrorCodeConflict,
+				Message: fmt.Sprintf("You already have a campaign named %q; choose a different name", name),
+			},
+		})
+}
+
 // validateCampaignRequest ensures appropriate parameters are provided for each campaign type
 func (h *CampaignOrchestratorAPIHandler) validateCampaignRequest(req services.CreateCampaignRequest) error {
 	switch req.CampaignType {
@@ -154,6 +317,12 @@ func (h *CampaignOrchestratorAPIHandler) validateCampaignRequest(req services.Cr
 		if req.DnsValidationParams != nil || req.HttpKeywordParams != nil {
 			return fmt.Errorf("only domainGenerationParams should be provided for domain_generation campaigns")
 		}
+		if h.tldList != nil && !h.tldList.Contains(req.DomainGenerationParams.TLD) {
+			return fmt.Errorf("tld %q is not a delegated top-level domain", req.DomainGenerationParams.TLD)
+		}
+		if err := services.ValidateDNSPromotion(req.DomainGenerationParams.DNSPromotion); err != nil {
+			return fmt.Errorf("domainGenerationParams.%w", err)
+		}
 	case "dns_validation":
 		if req.DnsValidationParams == nil {
 			return fmt.Errorf("dnsValidationParams required for dns_validation campaigns")
@@ -161,6 +330,9 @@ func (h *CampaignOrchestratorAPIHandler) validateCampaignRequest(req services.Cr
 		if req.DomainGenerationParams != nil || req.HttpKeywordParams != nil {
 			return fmt.Errorf("only dnsValidationParams should be provided for dns_validation campaigns")
 		}
+		if err := services.ValidateDNSExpectedRecords(req.DnsValidationParams.ExpectedRecords); err != nil {
+			return fmt.Errorf("dnsValidationParams.%w", err)
+		}
 	case "http_keyword_validation":
 		if req.HttpKeywordParams == nil {
 			return fmt.Errorf("httpKeywordParams required for http_keyword_validation campaigns")
@@ -168,6 +340,12 @@ func (h *CampaignOrchestratorAPIHandler) validateCampaignRequest(req services.Cr
 		if req.DomainGenerationParams != nil || req.DnsValidationParams != nil {
 			return fmt.Errorf("only httpKeywordParams should be provided for http_keyword_validation campaigns")
 		}
+		if _, err := services.ValidateKeywordMatching(req.HttpKeywordParams.MatchType, req.HttpKeywordParams.AdHocKeywords); err != nil {
+			return fmt.Errorf("httpKeywordParams.%w", err)
+		}
+		if _, err := services.ValidateProxySelectionStrategy(req.HttpKeywordParams.ProxySelectionStrategy); err != nil {
+			return fmt.Errorf("httpKeywordParams.%w", err)
+		}
 	default:
 		return fmt.Errorf("unsupported campaign type: %s", req.CampaignType)
 	}
@@ -185,49 +363,113 @@ func (h *CampaignOrchestratorAPIHandler) validateCampaignRequest(req services.Cr
 // @Param offset query int false "Number of campaigns to skip" default(0)
 // @Param type query string false "Filter by campaign type" Enums(domain_generation,dns_validation,http_keyword_validation)
 // @Param status query string false "Filter by campaign status" Enums(pending,queued,running,pausing,paused,completed,failed,archived,cancelled)
+// @Param sortBy query string false "Sort column" Enums(created_at,updated_at,name,status)
+// @Param sortOrder query string false "Sort direction" Enums(asc,desc)
+// @Param tags query string false "Comma-separated tags to filter by (case-insensitive)"
+// @Param tagMatch query string false "Whether campaigns must have any or all of the tags" Enums(any,all) default(any)
+// @Param view query string false "Name of a saved list view whose filters apply unless overridden by explicit parameters"
 // @Success 200 {array} models.CampaignAPI "List of campaigns"
 // @Failure 400 {object} models.ErrorResponse "Invalid query parameters"
 // @Failure 401 {object} models.ErrorResponse "Authentication required"
 // @Failure 403 {object} models.ErrorResponse "Insufficient permissions"
+// @Failure 404 {object} models.ErrorResponse "Saved view not found"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Security SessionAuth
 // @Router /campaigns [get]
 func (h *CampaignOrchestratorAPIHandler) listCampaigns(c *gin.Context) {
+	view, ok := h.resolveCampaignListView(c)
+	if !ok {
+		return
+	}
+	defaultLimit := DefaultPageLimit
+	if view != nil && view.PageSize > 0 {
+		defaultLimit = view.PageSize
+	}
+
 	// Parse and validate query parameters
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	if err != nil || limit < 1 || limit > 100 {
+	page, err := parsePagination(c, defaultLimit, DefaultMaxPageSize)
+	if err != nil {
+		respondWithPaginationErrorGin(c, err)
+		return
+	}
+	limit, offset := page.Limit, page.Offset
+	fields, err := parseSparseFields(c, models.Campaign{})
+	if err != nil {
+		respondWithFieldsErrorGin(c, err)
+		return
+	}
+
+	statusFilter := models.CampaignStatusEnum(c.Query("status"))
+	typeFilter := models.CampaignTypeEnum(c.Query("type"))
+	sortBy := c.Query("sortBy")
+	sortOrder := c.Query("sortOrder")
+	if view != nil {
+		// Explicit query parameters take precedence over the saved view
+		if statusFilter == "" {
+			statusFilter = view.Status
+		}
+		if typeFilter == "" {
+			typeFilter = view.CampaignType
+		}
+		if sortBy == "" {
+			sortBy = view.SortBy
+		}
+		if sortOrder == "" {
+			sortOrder = view.SortOrder
+		}
+	}
+	if sortBy != "" && !validCampaignSortColumns[sortBy] {
 		respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation,
-			"Invalid limit parameter", []ErrorDetail{
+			"Invalid sortBy parameter", []ErrorDetail{
 				{
-					Field:   "limit",
+					Field:   "sortBy",
 					Code:    ErrorCodeValidation,
-					Message: "Limit must be between 1 and 100",
+					Message: "sortBy must be one of created_at, updated_at, name, status",
 				},
 			})
 		return
 	}
-
-	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	if err != nil || offset < 0 {
+	if sortOrder != "" && sortOrder != "asc" && sortOrder != "desc" {
 		respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation,
-			"Invalid offset parameter", []ErrorDetail{
+			"Invalid sortOrder parameter", []ErrorDetail{
 				{
-					Field:   "offset",
+					Field:   "sortOrder",
 					Code:    ErrorCodeValidation,
-					Message: "Offset must be non-negative",
+					Message: "sortOrder must be asc or desc",
 				},
 			})
 		return
 	}
 
-	statusFilter := models.CampaignStatusEnum(c.Query("status"))
-	typeFilter := models.CampaignTypeEnum(c.Query("type"))
+	tagMatch := c.DefaultQuery("tagMatch", store.TagMatchAny)
+	if tagMatch != store.TagMatchAny && tagMatch != store.TagMatchAll {
+		respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation,
+			"Invalid tagMatch parameter", []ErrorDetail{
+				{
+					Field:   "tagMatch",
+					Code:    ErrorCodeValidation,
+					Message: "tagMatch must be any or all",
+				},
+			})
+		return
+	}
+	var tags []string
+	for _, value := range c.QueryArray("tags") {
+		tags = append(tags, strings.Split(value, ",")...)
+	}
 
 	filter := store.ListCampaignsFilter{
-		Limit:  limit,
-		Offset: offset,
-		Status: statusFilter,
-		Type:   typeFilter,
+		Limit:     limit,
+		Offset:    offset,
+		Status:    statusFilter,
+		Type:      typeFilter,
+		Tags:      services.NormalizeCampaignTags(tags),
+		TagMatch:  tagMatch,
+		SortBy:    sortBy,
+		SortOrder: sortOrder,
+	}
+	if ownerFilter, scoped := middleware.GetOwnerFilter(c); scoped {
+		filter.UserID = ownerFilter.UserID.String()
 	}
 
 	campaigns, totalCount, err := h.orchestratorService.ListCampaigns(c.Request.Context(), filter)
@@ -238,9 +480,17 @@ func (h *CampaignOrchestratorAPIHandler) listCampaigns(c *gin.Context) {
 		return
 	}
 
+	shaped, err := fields.apply(campaigns)
+	if err != nil {
+		log.Printf("Error selecting campaign fields: %v", err)
+		respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeInternalServer,
+			"Failed to retrieve campaigns", nil)
+		return
+	}
+
 	// Use unified response with metadata for pagination
 	c.Header("X-Total-Count", fmt.Sprintf("%d", totalCount))
-	response := NewSuccessResponse(campaigns, getRequestID(c))
+	response := NewSuccessResponse(shaped, getRequestID(c))
 	response.WithMetadata(&Metadata{
 		Page: &PageInfo{
 			Current:  (offset / limit) + 1,
@@ -259,14 +509,15 @@ func (h *CampaignOrchestratorAPIHandler) getCampaignDetails(c *gin.Context) {
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
 		return
 	}
+	fields, err := parseSparseFields(c, CampaignDetailsResponse{})
+	if err != nil {
+		respondWithFieldsErrorGin(c, err)
+		return
+	}
 
 	baseCampaign, params, err := h.orchestratorService.GetCampaignDetails(c.Request.Context(), campaignID)
 	if err != nil {
-		// Assuming store.ErrNotFound is an error variable (e.g., from a store package)
-		// It's better to use errors.Is(err, store.ErrNotFound) if store.ErrNotFound is a specific error type
-		// For now, keeping the string comparison as it was, but this is a point of improvement.
-		// if errors.Is(err, store.ErrNotFound) { // Example for future
-		if err.Error() == "record not found" { // Or whatever store.ErrNotFound.Error() actually returns
+		if errors.Is(err, store.ErrNotFound) {
 			respondWithErrorGin(c, http.StatusNotFound, "Campaign not found")
 		} else {
 			log.Printf("Error getting campaign details for %s: %v", campaignIDStr, err)
@@ -274,19 +525,594 @@ func (h *CampaignOrchestratorAPIHandler) getCampaignDetails(c *gin.Context) {
 		}
 		return
 	}
+	if ownerFilter, scoped := middleware.GetOwnerFilter(c); scoped && !ownerFilter.Owns(baseCampaign.UserID) {
+		respondWithErrorGin(c, http.StatusNotFound, "Campaign not found")
+		return
+	}
 
 	// Combine base campaign and specific params into a single response DTO
-	type CampaignDetailsResponse struct { // Corrected 'ype' to 'type'
-		*models.Campaign
-		Params interface{} `json:"params"`
-	}
 	resp := CampaignDetailsResponse{
 		Campaign: baseCampaign,
 		Params:   params,
 	}
+	if hkParams, ok := params.(*models.HTTPKeywordCampaignParams); ok {
+		resp.ResumePoint = services.HTTPResumePointFromParams(hkParams)
+	}
+	shaped, err := fields.apply(resp)
+	if err != nil {
+		log.Printf("Error selecting fields of campaign %s: %v", campaignIDStr, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to get campaign details")
+		return
+	}
+	// The type-specific params live in their own table, so the ETag hashes the whole response
+	respondWithETaggedJSONGin(c, shaped)
+}
+
+// CampaignDetailsResponse combines a campaign with its type-specific params
+type CampaignDetailsResponse struct {
+	*models.Campaign
+	Params      interface{}               `json:"params"`
+	ResumePoint *services.HTTPResumePoint `json:"resumePoint,omitempty"`
+}
+
+// CampaignStatusResponse is the body of GET /campaigns/{campaignId}/status
+type CampaignStatusResponse struct {
+	Status             models.CampaignStatusEnum `json:"status"`
+	ProgressPercentage *float64                  `json:"progressPercentage,omitempty"`
+}
+
+// getCampaignStatus reports a campaign's status and progress
+// @Summary Get campaign status
+// @Description Retrieve the current status and progress of a campaign. Supports If-None-Match; an unchanged status returns 304.
+// @Tags Campaigns
+// @Produce json
+// @Param campaignId path string true "Campaign ID"
+// @Param If-None-Match header string false "ETag of the status the client already has"
+// @Success 200 {object} CampaignStatusResponse "Campaign status"
+// @Success 304 "Status unchanged"
+// @Failure 400 {object} models.ErrorResponse "Invalid campaign ID"
+// @Failure 404 {object} models.ErrorResponse "Campaign not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/status [get]
+func (h *CampaignOrchestratorAPIHandler) getCampaignStatus(c *gin.Context) {
+	campaignIDStr := c.Param("campaignId")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
+
+	status, progress, err := h.orchestratorService.GetCampaignStatus(c.Request.Context(), campaignID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithErrorGin(c, http.StatusNotFound, "Campaign not found")
+			return
+		}
+		log.Printf("Error getting status of campaign %s: %v", campaignIDStr, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to get campaign status")
+		return
+	}
+	respondWithETaggedJSONGin(c, CampaignStatusResponse{Status: status, ProgressPercentage: progress})
+}
+
+// CampaignStatusesResponse is the body of GET /campaigns/status
+type CampaignStatusesResponse struct {
+	Campaigns []store.CampaignStatusSummary `json:"campaigns"` // In the order the IDs were given
+	NotFound  []uuid.UUID                   `json:"notFound"`  // Requested IDs that do not exist or belong to another user
+}
+
+// getCampaignStatuses reports the status and progress of several campaigns at once
+// @Summary Get the status of several campaigns
+// @Description Retrieve the status and progress of up to the configured number of campaigns in one request, so dashboards poll once. IDs of campaigns that do not exist or that the user does not own are listed in notFound.
+// @Tags Campaigns
+// @Produce json
+// @Param ids query string true "Comma-separated campaign IDs"
+// @Success 200 {object} CampaignStatusesResponse "Campaign statuses"
+// @Failure 400 {object} models.ErrorResponse "Missing or invalid IDs, or more IDs than allowed"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /campaigns/status [get]
+func (h *CampaignOrchestratorAPIHandler) getCampaignStatuses(c *gin.Context) {
+	maxIDs := h.maxBulkStatusIDs
+	if maxIDs <= 0 {
+		maxIDs = config.DefaultCampaignStatusMaxBulkIDs
+	}
+
+	var campaignIDs []uuid.UUID
+	seen := map[uuid.UUID]bool{}
+	for _, value := range c.QueryArray("ids") {
+		for _, idStr := range strings.Split(value, ",") {
+			if idStr = strings.TrimSpace(idStr); idStr == "" {
+				continue
+			}
+			campaignID, err := uuid.Parse(idStr)
+			if err != nil {
+				respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation,
+					"Invalid ids parameter", []ErrorDetail{
+						{
+							Field:   "ids",
+							Code:    ErrorCodeValidation,
+							Message: fmt.Sprintf("%q is not a valid campaign ID", idStr),
+						},
+					})
+				return
+			}
+			if !seen[campaignID] {
+				seen[campaignID] = true
+				campaignIDs = append(campaignIDs, campaignID)
+			}
+		}
+	}
+	if len(campaignIDs) == 0 || len(campaignIDs) > maxIDs {
+		respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation,
+			"Invalid ids parameter", []ErrorDetail{
+				{
+					Field:   "ids",
+					Code:    ErrorCodeValidation,
+					Message: fmt.Sprintf("ids must list between 1 and %d campaign IDs", maxIDs),
+					Context: map[string]interface{}{"max": maxIDs},
+				},
+			})
+		return
+	}
+
+	var userID string
+	if ownerFilter, scoped := middleware.GetOwnerFilter(c); scoped {
+		userID = ownerFilter.UserID.String()
+	}
+	statuses, err := h.orchestratorService.ListCampaignStatuses(c.Request.Context(), campaignIDs, userID)
+	if err != nil {
+		log.Printf("Error getting status of %d campaigns: %v", len(campaignIDs), err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to get campaign statuses")
+		return
+	}
+
+	byID := make(map[uuid.UUID]store.CampaignStatusSummary, len(statuses))
+	for _, status := range statuses {
+		byID[status.ID] = status
+	}
+	resp := CampaignStatusesResponse{
+		Campaigns: make([]store.CampaignStatusSummary, 0, len(statuses)),
+		NotFound:  []uuid.UUID{},
+	}
+	for _, campaignID := range campaignIDs {
+		if status, ok := byID[campaignID]; ok {
+			resp.Campaigns = append(resp.Campaigns, status)
+		} else {
+			resp.NotFound = append(resp.NotFound, campaignID)
+		}
+	}
+	respondWithJSONGin(c, http.StatusOK, resp)
+}
+
+// getCampaignJobs lists the job history of a campaign
+// @Summary List campaign jobs
+// @Description Retrieve the paginated job history of a campaign with status, attempts, last error, processing server and timings
+// @Tags Campaigns
+// @Produce json
+// @Param campaignId path string true "Campaign ID"
+// @Param limit query int false "Maximum number of jobs to return (1-100)" default(20)
+// @Param offset query int false "Number of jobs to skip" default(0)
+// @Success 200 {object} services.CampaignJobHistoryResponse "Campaign job history"
+// @Failure 400 {object} models.ErrorResponse "Invalid request parameters"
+// @Failure 404 {object} models.ErrorResponse "Campaign not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/jobs [get]
+func (h *CampaignOrchestratorAPIHandler) getCampaignJobs(c *gin.Context) {
+	campaignIDStr := c.Param("campaignId")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+
+	page, err := parsePagination(c, DefaultPageLimit, DefaultMaxPageSize)
+	if err != nil {
+		respondWithPaginationErrorGin(c, err)
+		return
+	}
+
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
+
+	resp, err := h.orchestratorService.ListCampaignJobs(c.Request.Context(), campaignID, page.Limit, page.Offset)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithErrorGin(c, http.StatusNotFound, "Campaign not found")
+			return
+		}
+		log.Printf("Error listing jobs for campaign %s: %v", campaignIDStr, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to list campaign jobs")
+		return
+	}
 	respondWithJSONGin(c, http.StatusOK, resp)
 }
 
+// getCampaignStatusHistory lists a campaign's status transitions
+// @Summary List campaign status history
+// @Description Retrieve every status change of a campaign with who or what made it, newest first
+// @Tags Campaigns
+// @Produce json
+// @Param campaignId path string true "Campaign ID"
+// @Param limit query int false "Maximum number of transitions to return (1-100)" default(20)
+// @Param offset query int false "Number of transitions to skip" default(0)
+// @Success 200 {array} services.CampaignStatusTransition "Status transitions"
+// @Failure 400 {object} models.ErrorResponse "Invalid request parameters"
+// @Failure 404 {object} models.ErrorResponse "Campaign not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/status-history [get]
+func (h *CampaignOrchestratorAPIHandler) getCampaignStatusHistory(c *gin.Context) {
+	campaignIDStr := c.Param("campaignId")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+
+	page, err := parsePagination(c, DefaultPageLimit, DefaultMaxPageSize)
+	if err != nil {
+		respondWithPaginationErrorGin(c, err)
+		return
+	}
+
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
+
+	history, err := h.orchestratorService.ListCampaignStatusHistory(c.Request.Context(), campaignID, page.Limit, page.Offset)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithErrorGin(c, http.StatusNotFound, "Campaign not found")
+			return
+		}
+		log.Printf("Error listing status history for campaign %s: %v", campaignIDStr, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to list campaign status history")
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, history)
+}
+
+// getCampaignDedupDecisions lists the domain deduplication decisions of a campaign
+// @Summary List campaign dedup decisions
+// @Description Retrieve which upstream items a campaign with deduplicateDomains enabled kept or collapsed as duplicates
+// @Tags Campaigns
+// @Produce json
+// @Param campaignId path string true "Campaign ID"
+// @Param limit query int false "Maximum number of decisions to return (1-100)" default(20)
+// @Param offset query int false "Number of decisions to skip" default(0)
+// @Success 200 {array} models.DomainDedupDecision "Dedup decisions"
+// @Failure 400 {object} models.ErrorResponse "Invalid request parameters"
+// @Failure 404 {object} models.ErrorResponse "Campaign not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/dedup-decisions [get]
+func (h *CampaignOrchestratorAPIHandler) getCampaignDedupDecisions(c *gin.Context) {
+	campaignIDStr := c.Param("campaignId")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+
+	page, err := parsePagination(c, DefaultPageLimit, DefaultMaxPageSize)
+	if err != nil {
+		respondWithPaginationErrorGin(c, err)
+		return
+	}
+
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
+
+	decisions, err := h.orchestratorService.ListDomainDedupDecisions(c.Request.Context(), campaignID, page.Limit, page.Offset)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithErrorGin(c, http.StatusNotFound, "Campaign not found")
+			return
+		}
+		log.Printf("Error listing dedup decisions for campaign %s: %v", campaignIDStr, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to list campaign dedup decisions")
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, decisions)
+}
+
+// getCampaignStats returns aggregate result statistics for a campaign
+// @Summary Get campaign statistics
+// @Description Aggregate result counts for a campaign. If gathering takes longer than the endpoint's soft deadline, the sections gathered so far are returned with partial set to true.
+// @Tags Campaigns
+// @Produce json
+// @Param campaignId path string true "Campaign ID (UUID)"
+// @Param allowPartial query bool false "Return partial results when the soft deadline expires" default(true)
+// @Success 200 {object} services.CampaignStatsResponse "Campaign statistics"
+// @Failure 400 {object} models.ErrorResponse "Invalid campaign ID"
+// @Failure 404 {object} models.ErrorResponse "Campaign not found"
+// @Failure 504 {object} models.ErrorResponse "Campaign could not be loaded before the deadline"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/stats [get]
+func (h *CampaignOrchestratorAPIHandler) getCampaignStats(c *gin.Context) {
+	campaignIDStr := c.Param("campaignId")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
+
+	ctx, cancel := withSoftDeadline(c, SoftDeadlineCampaignStats)
+	defer cancel()
+
+	stats, err := h.orchestratorService.GetCampaignStats(ctx, campaignID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithErrorGin(c, http.StatusNotFound, "Campaign not found")
+			return
+		}
+		if ctx.Err() != nil {
+			respondWithErrorGin(c, http.StatusGatewayTimeout, "Timed out loading campaign statistics")
+			return
+		}
+		log.Printf("Error getting stats for campaign %s: %v", campaignIDStr, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to get campaign statistics")
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, stats)
+}
+
+// getCampaignDiagnostics gathers what is needed to investigate a campaign that is not progressing
+// @Summary Get campaign diagnostics
+// @Description Current status and progress, the latest job with its error, the resume position, the health of referenced personas and proxies, and the most recent result errors, with whether the campaign looks stalled
+// @Tags Campaigns
+// @Produce json
+// @Param campaignId path string true "Campaign ID (UUID)"
+// @Param errorLimit query int false "Number of recent result errors to include (1-50)" default(5)
+// @Success 200 {object} services.CampaignDiagnostics "Campaign diagnostics"
+// @Failure 400 {object} models.ErrorResponse "Invalid campaign ID or error limit"
+// @Failure 404 {object} models.ErrorResponse "Campaign not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 503 {object} models.ErrorResponse "Diagnostics are not available"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/diagnostics [get]
+func (h *CampaignOrchestratorAPIHandler) getCampaignDiagnostics(c *gin.Context) {
+	campaignIDStr := c.Param("campaignId")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+
+	errorLimit := services.DefaultDiagnosticsErrorLimit
+	if limitStr := c.Query("errorLimit"); limitStr != "" {
+		errorLimit, err = strconv.Atoi(limitStr)
+		if err != nil || errorLimit < 1 || errorLimit > services.MaxDiagnosticsErrorLimit {
+			respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation,
+				"Invalid errorLimit parameter", []ErrorDetail{
+					{
+						Field:   "errorLimit",
+						Code:    ErrorCodeValidation,
+						Message: fmt.Sprintf("errorLimit must be between 1 and %d", services.MaxDiagnosticsErrorLimit),
+					},
+				})
+			return
+		}
+	}
+
+	if h.diagnostics == nil {
+		respondWithErrorGin(c, http.StatusServiceUnavailable, "Campaign diagnostics are not available")
+		return
+	}
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
+
+	diag, err := h.diagnostics.GetCampaignDiagnostics(c.Request.Context(), campaignID, errorLimit)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithErrorGin(c, http.StatusNotFound, "Campaign not found")
+			return
+		}
+		log.Printf("Error getting diagnostics for campaign %s: %v", campaignIDStr, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to get campaign diagnostics")
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, diag)
+}
+
+// compareCampaigns diffs the results of two validation campaigns of the same type
+// @Summary Compare campaign results
+// @Description Domains whose result differs between two DNS or two HTTP keyword campaigns, with counts over all domains. Both result sets are streamed in domain order, so large campaigns are not loaded into memory.
+// @Tags Campaigns
+// @Produce json
+// @Param campaignId path string true "Left campaign ID (UUID)"
+// @Param otherCampaignId path string true "Right campaign ID (UUID)"
+// @Param limit query int false "Number of differences to list (1-1000)" default(100)
+// @Success 200 {object} services.CampaignComparison "Campaign comparison"
+// @Failure 400 {object} models.ErrorResponse "Invalid campaign ID or limit, or campaigns that cannot be compared"
+// @Failure 404 {object} models.ErrorResponse "Campaign not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 503 {object} models.ErrorResponse "Comparison is not available"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/compare/{otherCampaignId} [get]
+func (h *CampaignOrchestratorAPIHandler) compareCampaigns(c *gin.Context) {
+	leftID, err := uuid.Parse(c.Param("campaignId"))
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+	rightID, err := uuid.Parse(c.Param("otherCampaignId"))
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+
+	limit := services.DefaultCompareDifferenceLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > services.MaxCompareDifferenceLimit {
+			respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation,
+				"Invalid limit parameter", []ErrorDetail{
+					{
+						Field:   "limit",
+						Code:    ErrorCodeValidation,
+						Message: fmt.Sprintf("limit must be between 1 and %d", services.MaxCompareDifferenceLimit),
+					},
+				})
+			return
+		}
+	}
+
+	if h.compare == nil {
+		respondWithErrorGin(c, http.StatusServiceUnavailable, "Campaign comparison is not available")
+		return
+	}
+	if !h.ensureCampaignOwnership(c, leftID) || !h.ensureCampaignOwnership(c, rightID) {
+		return
+	}
+
+	comparison, err := h.compare.CompareCampaigns(c.Request.Context(), leftID, rightID, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			respondWithErrorGin(c, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, services.ErrCampaignsNotComparable):
+			respondWithErrorGin(c, http.StatusBadRequest, err.Error())
+		default:
+			log.Printf("Error comparing campaigns %s and %s: %v", leftID, rightID, err)
+			respondWithErrorGin(c, http.StatusInternalServerError, "Failed to compare campaigns")
+		}
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, comparison)
+}
+
+// getCampaignRetryChain lists the runs of a campaign's automatic retry chain
+// @Summary Get campaign retry chain
+// @Description The original run of the campaign's retry chain followed by each automatic retry, oldest first. Every run links to the run it retried.
+// @Tags Campaigns
+// @Produce json
+// @Param campaignId path string true "Campaign ID (UUID) of any run in the chain"
+// @Success 200 {array} models.Campaign "Runs in retry order"
+// @Failure 400 {object} models.ErrorResponse "Invalid campaign ID"
+// @Failure 404 {object} models.ErrorResponse "Campaign not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 503 {object} models.ErrorResponse "Automatic retries are disabled"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/retries [get]
+func (h *CampaignOrchestratorAPIHandler) getCampaignRetryChain(c *gin.Context) {
+	campaignIDStr := c.Param("campaignId")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+	if h.autoRetrier == nil {
+		respondWithErrorGin(c, http.StatusServiceUnavailable, "Automatic campaign retries are disabled")
+		return
+	}
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
+
+	chain, err := h.autoRetrier.RetryChain(c.Request.Context(), campaignID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithErrorGin(c, http.StatusNotFound, "Campaign not found")
+			return
+		}
+		log.Printf("Error getting retry chain of campaign %s: %v", campaignIDStr, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to get campaign retry chain")
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, chain)
+}
+
+// --- Campaign Modification Handlers ---
+
+// updateCampaign changes a campaign's name, tags, metadata or status
+// @Summary Update a campaign
+// @Description Update a campaign. Name, tags, metadata and status may change at any time; the sources, generation pattern, personas, proxies, keywords and processing settings may not change once the campaign has left pending while immutable fields are enforced.
+// @Tags Campaigns
+// @Accept json
+// @Produce json
+// @Param campaignId path string true "Campaign ID"
+// @Param request body services.UpdateCampaignRequest true "Fields to change"
+// @Success 200 {object} models.Campaign "Updated campaign"
+// @Failure 400 {object} models.ErrorResponse "Invalid campaign ID or request payload"
+// @Failure 404 {object} models.ErrorResponse "Campaign not found"
+// @Failure 409 {object} models.ErrorResponse "The campaign has started and the request changes immutable fields, or the new name is taken"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId} [put]
+func (h *CampaignOrchestratorAPIHandler) updateCampaign(c *gin.Context) {
+	campaignIDStr := c.Param("campaignId")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+
+	var req services.UpdateCampaignRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondWithValidationErrorGin(c, []ErrorDetail{{
+			Field:   bindErrorField(err),
+			Code:    ErrorCodeValidation,
+			Message: "Invalid request payload: " + err.Error(),
+		}})
+		return
+	}
+	if validate != nil {
+		if err := validate.Struct(req); err != nil {
+			respondWithValidationErrorGin(c, []ErrorDetail{{
+				Code:    ErrorCodeValidation,
+				Message: "Validation failed: " + err.Error(),
+			}})
+			return
+		}
+	}
+
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
+
+	campaign, err := h.orchestratorService.UpdateCampaign(statusActorContext(c, "updated"), campaignID, req)
+	if err != nil {
+		var immutableErr *services.ImmutableFieldsError
+		switch {
+		case errors.As(err, &immutableErr):
+			details := make([]ErrorDetail, 0, len(immutableErr.Fields))
+			for _, field := range immutableErr.Fields {
+				details = append(details, ErrorDetail{
+					Field:   field,
+					Code:    ErrorCodeInvalidState,
+					Message: fmt.Sprintf("%s cannot be changed after the campaign has started", field),
+					Context: map[string]interface{}{"status": immutableErr.Status},
+				})
+			}
+			respondWithDetailedErrorGin(c, http.StatusConflict, ErrorCodeInvalidState,
+				"Campaign has started; some fields can no longer be changed", details)
+		case errors.Is(err, store.ErrCampaignNameTaken) && req.Name != nil:
+			respondWithCampaignNameTaken(c, *req.Name)
+		case errors.Is(err, store.ErrNotFound):
+			respondWithErrorGin(c, http.StatusNotFound, "Campaign not found")
+		default:
+			log.Printf("Error updating campaign %s: %v", campaignIDStr, err)
+			respondWithErrorGin(c, http.StatusInternalServerError, "Failed to update campaign")
+		}
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, campaign)
+}
 
 // --- Campaign Control Handlers ---
 
@@ -305,15 +1131,13 @@ func (h *CampaignOrchestratorAPIHandler) startCampaign(c *gin.Context) {
 		return
 	}
 
-	if err := h.orchestratorService.StartCampaign(c.Request.Context(), campaignID); err != nil {
+	if err := h.orchestratorService.StartCampaign(statusActorContext(c, "start requested"), campaignID); err != nil {
 		log.Printf("Error starting campaign %s: %v", campaignIDStr, err)
 
-		// Differentiate error types based on error message
-		// In production, use proper error types from the service layer
-		if err.Error() == "record not found" {
+		if errors.Is(err, store.ErrNotFound) {
 			respondWithDetailedErrorGin(c, http.StatusNotFound, ErrorCodeNotFound,
 				"Campaign not found", nil)
-		} else if err.Error() == "campaign already running" || err.Error() == "invalid campaign state" {
+		} else if errors.Is(err, services.ErrCampaignNotPending) {
 			respondWithDetailedErrorGin(c, http.StatusConflict, ErrorCodeInvalidState,
 				"Campaign is in an invalid state for this operation", []ErrorDetail{
 					{
@@ -345,7 +1169,7 @@ func (h *CampaignOrchestratorAPIHandler) pauseCampaign(c *gin.Context) {
 		return
 	}
 
-	if err := h.orchestratorService.PauseCampaign(c.Request.Context(), campaignID); err != nil {
+	if err := h.orchestratorService.PauseCampaign(statusActorContext(c, "pause requested"), campaignID); err != nil {
 		log.Printf("Error pausing campaign %s: %v", campaignIDStr, err)
 		respondWithErrorGin(c, http.StatusInternalServerError, fmt.Sprintf("Failed to pause campaign: %v", err))
 		return
@@ -361,7 +1185,7 @@ func (h *CampaignOrchestratorAPIHandler) resumeCampaign(c *gin.Context) {
 		return
 	}
 
-	if err := h.orchestratorService.ResumeCampaign(c.Request.Context(), campaignID); err != nil {
+	if err := h.orchestratorService.ResumeCampaign(statusActorContext(c, "resume requested"), campaignID); err != nil {
 		log.Printf("Error resuming campaign %s: %v", campaignIDStr, err)
 		respondWithErrorGin(c, http.StatusInternalServerError, fmt.Sprintf("Failed to resume campaign: %v", err))
 		return
@@ -377,7 +1201,7 @@ func (h *CampaignOrchestratorAPIHandler) cancelCampaign(c *gin.Context) {
 		return
 	}
 
-	if err := h.orchestratorService.CancelCampaign(c.Request.Context(), campaignID); err != nil {
+	if err := h.orchestratorService.CancelCampaign(statusActorContext(c, "cancellation requested"), campaignID); err != nil {
 		log.Printf("Error cancelling campaign %s: %v", campaignIDStr, err)
 		respondWithErrorGin(c, http.StatusInternalServerError, fmt.Sprintf("Failed to cancel campaign: %v", err))
 		return
@@ -394,7 +1218,7 @@ func (h *CampaignOrchestratorAPIHandler) deleteCampaign(c *gin.Context) {
 		return
 	}
 
-	if err := h.orchestratorService.DeleteCampaign(c.Request.Context(), campaignID); err != nil {
+	if err := h.orchestratorService.DeleteCampaign(statusActorContext(c, "deletion requested"), campaignID); err != nil {
 		log.Printf("Error deleting campaign %s: %v", campaignIDStr, err)
 		respondWithErrorGin(c, http.StatusInternalServerError, fmt.Sprintf("Failed to delete campaign: %v", err))
 		return
@@ -409,17 +1233,35 @@ func (h *CampaignOrchestratorAPIHandler) getGeneratedDomains(c *gin.Context) {
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
 		return
 	}
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, err := parsePagination(c, DefaultPageLimit, DefaultMaxPageSize)
+	if err != nil {
+		respondWithPaginationErrorGin(c, err)
+		return
+	}
+	fields, err := parseSparseFields(c, models.GeneratedDomain{})
+	if err != nil {
+		respondWithFieldsErrorGin(c, err)
+		return
+	}
 	cursor, _ := strconv.ParseInt(c.DefaultQuery("cursor", "0"), 10, 64)
 
-	resp, err := h.orchestratorService.GetGeneratedDomainsForCampaign(c.Request.Context(), campaignID, limit, cursor)
+	resp, err := h.orchestratorService.GetGeneratedDomainsForCampaign(c.Request.Context(), campaignID, page.Limit, cursor)
 	if err != nil {
 		log.Printf("Error getting generated domains for campaign %s: %v", campaignIDStr, err)
 		respondWithErrorGin(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get generated domains: %v", err))
 		return
 	}
-	respondWithJSONGin(c, http.StatusOK, resp)
+	shaped, err := fields.applyToItems(resp, "data")
+	if err != nil {
+		log.Printf("Error selecting fields of generated domains for campaign %s: %v", campaignIDStr, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to get generated domains")
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, shaped)
 }
 
 func (h *CampaignOrchestratorAPIHandler) getDNSValidationResults(c *gin.Context) {
@@ -429,22 +1271,43 @@ func (h *CampaignOrchestratorAPIHandler) getDNSValidationResults(c *gin.Context)
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
 		return
 	}
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, err := parsePagination(c, DefaultPageLimit, DefaultMaxPageSize)
+	if err != nil {
+		respondWithPaginationErrorGin(c, err)
+		return
+	}
+	fields, err := parseSparseFields(c, models.DNSValidationResult{})
+	if err != nil {
+		respondWithFieldsErrorGin(c, err)
+		return
+	}
 	cursor := c.DefaultQuery("cursor", "")
 	validationStatus := c.Query("validationStatus")
+	if maxedOut, _ := strconv.ParseBool(c.Query("maxAttemptsExceeded")); maxedOut {
+		validationStatus = string(models.ValidationStatusMaxAttemptsExceeded)
+	}
 
 	filter := store.ListValidationResultsFilter{
 		ValidationStatus: validationStatus,
 	}
 
-	resp, err := h.orchestratorService.GetDNSValidationResultsForCampaign(c.Request.Context(), campaignID, limit, cursor, filter)
+	resp, err := h.orchestratorService.GetDNSValidationResultsForCampaign(c.Request.Context(), campaignID, page.Limit, cursor, filter)
 	if err != nil {
 		log.Printf("Error getting DNS validation results for campaign %s: %v", campaignIDStr, err)
 		respondWithErrorGin(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get DNS validation results: %v", err))
 		return
 	}
-	respondWithJSONGin(c, http.StatusOK, resp)
+	shaped, err := fields.applyToItems(resp, "data")
+	if err != nil {
+		log.Printf("Error selecting fields of DNS validation results for campaign %s: %v", campaignIDStr, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to get DNS validation results")
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, shaped)
 }
 
 func (h *CampaignOrchestratorAPIHandler) getHTTPKeywordResults(c *gin.Context) {
@@ -454,10 +1317,25 @@ func (h *CampaignOrchestratorAPIHandler) getHTTPKeywordResults(c *gin.Context) {
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
 		return
 	}
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, err := parsePagination(c, DefaultPageLimit, DefaultMaxPageSize)
+	if err != nil {
+		respondWithPaginationErrorGin(c, err)
+		return
+	}
+	fields, err := parseSparseFields(c, models.HTTPKeywordResult{})
+	if err != nil {
+		respondWithFieldsErrorGin(c, err)
+		return
+	}
 	cursor := c.DefaultQuery("cursor", "")
 	validationStatus := c.Query("validationStatus")
+	if maxedOut, _ := strconv.ParseBool(c.Query("maxAttemptsExceeded")); maxedOut {
+		validationStatus = string(models.ValidationStatusMaxAttemptsExceeded)
+	}
 	hasKeywordsStr := c.Query("hasKeywords")
 	var hasKeywords *bool
 	if hasKeywordsStr != "" {
@@ -471,14 +1349,61 @@ func (h *CampaignOrchestratorAPIHandler) getHTTPKeywordResults(c *gin.Context) {
 		ValidationStatus: validationStatus,
 		HasKeywords:      hasKeywords,
 	}
+	if daysStr := c.Query("certExpiresWithinDays"); daysStr != "" {
+		days, err := strconv.Atoi(daysStr)
+		if err != nil || days < 0 {
+			respondWithValidationErrorGin(c, []ErrorDetail{{
+				Field:   "certExpiresWithinDays",
+				Code:    ErrorCodeValidation,
+				Message: "certExpiresWithinDays must be a non-negative number of days",
+			}})
+			return
+		}
+		filter.CertificateExpiresBefore = time.Now().UTC().AddDate(0, 0, days)
+	}
 
-	resp, err := h.orchestratorService.GetHTTPKeywordResultsForCampaign(c.Request.Context(), campaignID, limit, cursor, filter)
+	resp, err := h.orchestratorService.GetHTTPKeywordResultsForCampaign(c.Request.Context(), campaignID, page.Limit, cursor, filter)
 	if err != nil {
 		log.Printf("Error getting HTTP keyword results for campaign %s: %v", campaignIDStr, err)
 		respondWithErrorGin(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get HTTP keyword results: %v", err))
 		return
 	}
-	respondWithJSONGin(c, http.StatusOK, resp)
+	shaped, err := fields.applyToItems(resp, "data")
+	if err != nil {
+		log.Printf("Error selecting fields of HTTP keyword results for campaign %s: %v", campaignIDStr, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to get HTTP keyword results")
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, shaped)
+}
+
+// statusActorContext attributes campaign status changes made while handling c to the session user
+func statusActorContext(c *gin.Context, reason string) context.Context {
+	actor := services.CampaignStatusActor{Type: services.StatusActorSystem, Reason: reason}
+	if userID, ok := currentUserID(c); ok {
+		actor.Type, actor.UserID = services.StatusActorUser, userID
+	}
+	return services.WithCampaignStatusActor(c.Request.Context(), actor)
+}
+
+// ensureCampaignOwnership enforces the owner filter set by ScopeToOwner for routes that do not
+// load the campaign themselves. It writes a 404 response and returns false when access is denied.
+func (h *CampaignOrchestratorAPIHandler) ensureCampaignOwnership(c *gin.Context, campaignID uuid.UUID) bool {
+	ownerFilter, scoped := middleware.GetOwnerFilter(c)
+	if !scoped {
+		return true
+	}
+	campaign, _, err := h.orchestratorService.GetCampaignDetails(c.Request.Context(), campaignID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		log.Printf("Error checking ownership of campaign %s: %v", campaignID, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to get campaign details")
+		return false
+	}
+	if err != nil || !ownerFilter.Owns(campaign.UserID) {
+		respondWithErrorGin(c, http.StatusNotFound, "Campaign not found")
+		return false
+	}
+	return true
 }
 
 // Helper functions (assuming they exist elsewhere or should be defined)
@@ -510,19 +1435,3 @@ func (h *CampaignOrchestratorAPIHandler) getHTTPKeywordResults(c *gin.Context) {
 // 	// }
 // 	return uuid.Nil
 // }
-
-// Placeholder for store.ErrNotFound if it's a package variable.
-// This depends on how `store.ErrNotFound` is actually defined and used.
-// If it's part of a specific package, that package needs to be imported.
-// For example:
-// import "errors" // if it's a generic error
-// var ErrNotFound = errors.New("record not found") // A possible definition
-
-// If store.ErrNotFound is from a package like:
-// "github.com/fntelecomllc/studio/backend/internal/store"
-// then that import should be present, and store.ErrNotFound would be used directly.
-// The error message "expected ';', found CampaignDetailsResponse" did not relate to this,
-// but for completeness, the string comparison `err.Error() == store.ErrNotFound.Error()`
-// implies `store.ErrNotFound` is an error variable. I've changed the comparison to
-// `err.Error() == "record not found"` as a placeholder, as `store.ErrNotFound` is not defined
-// in the snippet. Ideally, you'd use `errors.Is(err, store.ErrNotFound)`.
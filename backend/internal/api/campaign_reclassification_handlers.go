@@ -0,0 +1,81 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// reclassifyCampaignResults re-runs keyword classification over an HTTP keyword campaign's stored results
+// @Summary Re-classify HTTP keyword results
+// @Description Match the campaign's current keyword rules and ad hoc keywords against the stored page title, content snippet and optionally response headers of every successfully fetched result, updating the statuses and matches that change. No pages are fetched. With dryRun=true nothing is written and only the summary is returned.
+// @Tags Campaigns
+// @Produce json
+// @Param campaignId path string true "Campaign ID"
+// @Param dryRun query bool false "Report the changes without writing them"
+// @Success 200 {object} services.ReclassificationSummary "Summary of changed classifications"
+// @Failure 400 {object} models.ErrorResponse "Invalid campaign ID or not a keywords mode HTTP keyword campaign"
+// @Failure 404 {object} models.ErrorResponse "Campaign not found"
+// @Failure 409 {object} models.ErrorResponse "Campaign is still running"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 503 {object} models.ErrorResponse "Re-classification is not available"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/reclassify [post]
+func (h *CampaignOrchestratorAPIHandler) reclassifyCampaignResults(c *gin.Context) {
+	campaignIDStr := c.Param("campaignId")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+	dryRun := false
+	if dryRunStr := c.Query("dryRun"); dryRunStr != "" {
+		if dryRun, err = strconv.ParseBool(dryRunStr); err != nil {
+			respondWithValidationErrorGin(c, []ErrorDetail{{
+				Field:   "dryRun",
+				Code:    ErrorCodeValidation,
+				Message: "dryRun must be true or false",
+			}})
+			return
+		}
+	}
+	if h.reclassifier == nil {
+		respondWithErrorGin(c, http.StatusServiceUnavailable, "Re-classification is not available")
+		return
+	}
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
+
+	summary, err := h.reclassifier.ReclassifyCampaign(c.Request.Context(), campaignID, dryRun)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			respondWithErrorGin(c, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, services.ErrCampaignNotReclassifiable):
+			respondWithErrorGin(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrCampaignRunning):
+			respondWithDetailedErrorGin(c, http.StatusConflict, ErrorCodeInvalidState,
+				"Campaign results cannot be re-classified while it runs", []ErrorDetail{{
+					Code:    ErrorCodeInvalidState,
+					Message: fmt.Sprintf("wait for campaign %s to finish or pause it", campaignID),
+				}})
+		default:
+			log.Printf("Error re-classifying results of campaign %s: %v", campaignIDStr, err)
+			respondWithErrorGin(c, http.StatusInternalServerError, "Failed to re-classify campaign results")
+		}
+		return
+	}
+	if !dryRun {
+		log.Printf("Re-classified results of campaign %s: %d examined, %d reclassified, %d with changed matches",
+			campaignID, summary.Examined, summary.Reclassified, summary.MatchesChanged)
+	}
+	respondWithJSONGin(c, http.StatusOK, summary)
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reclassifiableCampaignStore holds an HTTP keyword campaign with ad hoc keywords and its results
+type reclassifiableCampaignStore struct {
+	store.CampaignStore
+	campaign *models.Campaign
+	params   *models.HTTPKeywordCampaignParams
+	results  []*models.HTTPKeywordResult
+	updated  []*models.HTTPKeywordResult
+}
+
+func (s *reclassifiableCampaignStore) GetCampaignByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Campaign, error) {
+	if id != s.campaign.ID {
+		return nil, store.ErrNotFound
+	}
+	return s.campaign, nil
+}
+
+func (s *reclassifiableCampaignStore) GetHTTPKeywordParams(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.HTTPKeywordCampaignParams, error) {
+	return s.params, nil
+}
+
+func (s *reclassifiableCampaignStore) GetHTTPKeywordResultsByCampaign(ctx context.Context, exec store.Querier, campaignID uuid.UUID, filter store.ListValidationResultsFilter) ([]*models.HTTPKeywordResult, error) {
+	if filter.AfterDomainName != "" {
+		return nil, nil
+	}
+	return s.results, nil
+}
+
+func (s *reclassifiableCampaignStore) UpdateHTTPKeywordResultClassifications(ctx context.Context, exec store.Querier, results []*models.HTTPKeywordResult) error {
+	s.updated = append(s.updated, results...)
+	return nil
+}
+
+func postReclassify(h *CampaignOrchestratorAPIHandler, path string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/campaigns/:campaignId/reclassify", h.reclassifyCampaignResults)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+	return w
+}
+
+func TestReclassifyCampaignResults(t *testing.T) {
+	snippet := "Now hiring engineers"
+	campaign := &models.Campaign{ID: uuid.New(), CampaignType: models.CampaignTypeHTTPKeywordValidation, Status: models.CampaignStatusRunning}
+	cs := &reclassifiableCampaignStore{
+		campaign: campaign,
+		params:   &models.HTTPKeywordCampaignParams{AdHocKeywords: &[]string{"hiring"}},
+		results: []*models.HTTPKeywordResult{
+			{ID: uuid.New(), DomainName: "jobs.com", ValidationStatus: "http_valid_no_keywords", ExtractedContentSnippet: &snippet},
+		},
+	}
+	h := NewCampaignOrchestratorAPIHandler(&creatingOrchestratorService{}, &memoryCampaignListViewStore{})
+	path := "/campaigns/" + campaign.ID.String() + "/reclassify"
+	assert.Equal(t, http.StatusServiceUnavailable, postReclassify(h, path).Code)
+
+	h.SetReclassifier(services.NewHTTPKeywordReclassifier(nil, nil, cs, nil))
+	assert.Equal(t, http.StatusBadRequest, postReclassify(h, path+"?dryRun=maybe").Code)
+	assert.Equal(t, http.StatusNotFound, postReclassify(h, "/campaigns/"+uuid.New().String()+"/reclassify").Code)
+	w := postReclassify(h, path)
+	assert.Equal(t, http.StatusConflict, w.Code, "a running campaign is not re-classified")
+
+	campaign.Status = models.CampaignStatusCompleted
+	w = postReclassify(h, path)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var envelope struct {
+		Data services.ReclassificationSummary `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	assert.Equal(t, int64(1), envelope.Data.Examined)
+	assert.Equal(t, []services.ReclassificationChange{{From: "http_valid_no_keywords", To: "lead_valid", Count: 1}}, envelope.Data.Changes)
+	require.Len(t, cs.updated, 1)
+	assert.Equal(t, "lead_valid", cs.updated[0].ValidationStatus)
+
+	campaign.CampaignType = models.CampaignTypeDNSValidation
+	assert.Equal(t, http.StatusBadRequest, postReclassify(h, path).Code)
+}
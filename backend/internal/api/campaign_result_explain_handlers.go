@@ -0,0 +1,84 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Formats of the domain explanation endpoint
+const (
+	explanationFormatFull       = "full"
+	explanationFormatStructured = "structured"
+	explanationFormatNarrative  = "narrative"
+)
+
+// explainCampaignDomain explains why one domain of a validation campaign ended as it did
+// @Summary Explain a domain's result
+// @Description Combine the stored DNS and HTTP keyword results of one domain into an explanation: the error category, HTTP status code, keywords matched and missed, the persona and proxy used and the attempts made. HTTP keyword campaigns include the domain's result in their source DNS campaign. format=structured leaves out the narrative and format=narrative returns only the narrative.
+// @Tags Campaigns
+// @Produce json
+// @Param campaignId path string true "Campaign ID"
+// @Param domain path string true "Domain name"
+// @Param format query string false "full, structured or narrative" default(full)
+// @Success 200 {object} services.DomainExplanation "Explanation of the domain's result"
+// @Failure 400 {object} models.ErrorResponse "Invalid campaign ID or format, or not a validation campaign"
+// @Failure 404 {object} models.ErrorResponse "Campaign or domain result not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 503 {object} models.ErrorResponse "Explanations are not available"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/results/{domain}/explain [get]
+func (h *CampaignOrchestratorAPIHandler) explainCampaignDomain(c *gin.Context) {
+	campaignIDStr := c.Param("campaignId")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+	domain := strings.ToLower(strings.TrimSpace(c.Param("domain")))
+	format := c.DefaultQuery("format", explanationFormatFull)
+	switch format {
+	case explanationFormatFull, explanationFormatStructured, explanationFormatNarrative:
+	default:
+		respondWithValidationErrorGin(c, []ErrorDetail{{
+			Field:   "format",
+			Code:    ErrorCodeValidation,
+			Message: "format must be full, structured or narrative",
+		}})
+		return
+	}
+	if h.explainer == nil {
+		respondWithErrorGin(c, http.StatusServiceUnavailable, "Result explanations are not available")
+		return
+	}
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
+
+	explanation, err := h.explainer.ExplainDomain(c.Request.Context(), campaignID, domain)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			respondWithErrorGin(c, http.StatusNotFound, "Campaign or domain result not found")
+		case errors.Is(err, services.ErrCampaignNotExplainable):
+			respondWithErrorGin(c, http.StatusBadRequest, err.Error())
+		default:
+			log.Printf("Error explaining domain %s of campaign %s: %v", domain, campaignIDStr, err)
+			respondWithErrorGin(c, http.StatusInternalServerError, "Failed to explain domain result")
+		}
+		return
+	}
+	switch format {
+	case explanationFormatStructured:
+		explanation.Narrative = nil
+	case explanationFormatNarrative:
+		explanation.DNS, explanation.HTTP = nil, nil
+	}
+	respondWithJSONGin(c, http.StatusOK, explanation)
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// explainableCampaignStore holds a DNS validation campaign and its results by domain
+type explainableCampaignStore struct {
+	store.CampaignStore
+	campaign *models.Campaign
+	results  map[string]*models.DNSValidationResult
+}
+
+func (s *explainableCampaignStore) GetCampaignByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Campaign, error) {
+	if id != s.campaign.ID {
+		return nil, store.ErrNotFound
+	}
+	return s.campaign, nil
+}
+
+func (s *explainableCampaignStore) GetDNSValidationResultByDomain(ctx context.Context, exec store.Querier, campaignID uuid.UUID, domainName string) (*models.DNSValidationResult, error) {
+	if result, ok := s.results[domainName]; ok {
+		return result, nil
+	}
+	return nil, store.ErrNotFound
+}
+
+func getExplanation(h *CampaignOrchestratorAPIHandler, path string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/campaigns/:campaignId/results/dns-validation", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	router.GET("/campaigns/:campaignId/results/:domain/explain", h.explainCampaignDomain)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestExplainCampaignDomain(t *testing.T) {
+	attempts := 2
+	campaign := &models.Campaign{ID: uuid.New(), CampaignType: models.CampaignTypeDNSValidation}
+	cs := &explainableCampaignStore{campaign: campaign, results: map[string]*models.DNSValidationResult{
+		"example.com": {DNSCampaignID: campaign.ID, DomainName: "example.com", ValidationStatus: "Timeout", Attempts: &attempts},
+	}}
+	h := NewCampaignOrchestratorAPIHandler(&creatingOrchestratorService{}, &memoryCampaignListViewStore{})
+	path := "/campaigns/" + campaign.ID.String() + "/results/example.com/explain"
+	assert.Equal(t, http.StatusServiceUnavailable, getExplanation(h, path).Code)
+
+	h.SetResultExplainer(services.NewCampaignResultExplainer(nil, cs, nil, nil))
+	assert.Equal(t, http.StatusBadRequest, getExplanation(h, path+"?format=xml").Code)
+	assert.Equal(t, http.StatusNotFound, getExplanation(h, "/campaigns/"+campaign.ID.String()+"/results/other.com/explain").Code)
+	assert.Equal(t, http.StatusNoContent, getExplanation(h, "/campaigns/"+campaign.ID.String()+"/results/dns-validation").Code,
+		"result listings keep their routes")
+
+	w := getExplanation(h, path)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var envelope struct {
+		Data services.DomainExplanation `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	assert.Equal(t, services.DomainOutcomeFailed, envelope.Data.Outcome)
+	assert.Equal(t, services.ResultErrorDNSTimeout, envelope.Data.ErrorCategory)
+	require.NotNil(t, envelope.Data.DNS)
+	assert.Equal(t, 2, envelope.Data.DNS.Attempts)
+	assert.NotEmpty(t, envelope.Data.Narrative)
+
+	w = getExplanation(h, path+"?format=narrative")
+	require.Equal(t, http.StatusOK, w.Code)
+	envelope.Data = services.DomainExplanation{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	assert.Nil(t, envelope.Data.DNS)
+	assert.Equal(t, []string{`DNS failed for example.com with status "Timeout" after 2 attempts.`}, envelope.Data.Narrative)
+}
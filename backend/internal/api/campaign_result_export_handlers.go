@@ -0,0 +1,241 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Columns of the CSV result exports, named after the JSON fields of the results
+var (
+	dnsValidationCSVHeader = []string{
+		"domainName", "validationStatus", "expectation", "attempts", "lastCheckedAt",
+		"dnsRecords", "consensus", "validatedByPersonaId", "createdAt",
+	}
+	httpKeywordCSVHeader = []string{
+		"domainName", "validationStatus", "httpStatusCode", "pageTitle", "foundKeywordsFromSets",
+		"foundAdHocKeywords", "attempts", "lastCheckedAt", "validatedByPersonaId", "usedProxyId", "createdAt",
+	}
+)
+
+// exportDNSValidationResultsCSV streams a DNS validation campaign's results as CSV
+// @Summary Export DNS validation results as CSV
+// @Description Stream every DNS validation result of the campaign as CSV, in domain order. Results are read in batches of server.exportBatchSize and written as they are read; the export stops when the client disconnects.
+// @Tags Campaigns
+// @Produce text/csv
+// @Param campaignId path string true "Campaign ID"
+// @Param validationStatus query string false "Only results with this validation status"
+// @Param maxAttemptsExceeded query bool false "Only results that reached the attempt cap"
+// @Success 200 {file} file "CSV of the campaign's DNS validation results"
+// @Failure 400 {object} models.ErrorResponse "Invalid campaign ID or not a DNS validation campaign"
+// @Failure 404 {object} models.ErrorResponse "Campaign not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 503 {object} models.ErrorResponse "Result export is not available"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/results/dns-validation.csv [get]
+func (h *CampaignOrchestratorAPIHandler) exportDNSValidationResultsCSV(c *gin.Context) {
+	campaign, ok := h.exportableCampaign(c, models.CampaignTypeDNSValidation)
+	if !ok {
+		return
+	}
+	w := startCSVExport(c, campaign, "dns-validation", dnsValidationCSVHeader)
+	err := h.resultExport.StreamDNSValidationResults(c.Request.Context(), campaign.ID, exportResultsFilter(c),
+		func(batch []*models.DNSValidationResult) error {
+			for _, result := range batch {
+				if err := w.Write([]string{
+					result.DomainName,
+					result.ValidationStatus,
+					result.Expectation,
+					csvInt(result.Attempts),
+					csvTime(result.LastCheckedAt),
+					csvJSON(result.DNSRecords),
+					csvJSON(result.Consensus),
+					csvUUID(result.ValidatedByPersonaID),
+					result.CreatedAt.UTC().Format(time.RFC3339),
+				}); err != nil {
+					return err
+				}
+			}
+			return flushCSVExport(c, w)
+		})
+	finishCSVExport(c, campaign.ID, "DNS validation", w, err)
+}
+
+// exportHTTPKeywordResultsCSV streams an HTTP keyword campaign's results as CSV
+// @Summary Export HTTP keyword results as CSV
+// @Description Stream every HTTP keyword result of the campaign as CSV, in domain order. Results are read in batches of server.exportBatchSize and written as they are read; the export stops when the client disconnects.
+// @Tags Campaigns
+// @Produce text/csv
+// @Param campaignId path string true "Campaign ID"
+// @Param validationStatus query string false "Only results with this validation status"
+// @Param maxAttemptsExceeded query bool false "Only results that reached the attempt cap"
+// @Success 200 {file} file "CSV of the campaign's HTTP keyword results"
+// @Failure 400 {object} models.ErrorResponse "Invalid campaign ID or not an HTTP keyword validation campaign"
+// @Failure 404 {object} models.ErrorResponse "Campaign not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 503 {object} models.ErrorResponse "Result export is not available"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/results/http-keyword.csv [get]
+func (h *CampaignOrchestratorAPIHandler) exportHTTPKeywordResultsCSV(c *gin.Context) {
+	campaign, ok := h.exportableCampaign(c, models.CampaignTypeHTTPKeywordValidation)
+	if !ok {
+		return
+	}
+	w := startCSVExport(c, campaign, "http-keyword", httpKeywordCSVHeader)
+	err := h.resultExport.StreamHTTPKeywordResults(c.Request.Context(), campaign.ID, exportResultsFilter(c),
+		func(batch []*models.HTTPKeywordResult) error {
+			for _, result := range batch {
+				var statusCode string
+				if result.HTTPStatusCode != nil {
+					statusCode = strconv.Itoa(int(*result.HTTPStatusCode))
+				}
+				var pageTitle, adHocKeywords string
+				if result.PageTitle != nil {
+					pageTitle = *result.PageTitle
+				}
+				if result.FoundAdHocKeywords != nil {
+					adHocKeywords = strings.Join(*result.FoundAdHocKeywords, ";")
+				}
+				if err := w.Write([]string{
+					result.DomainName,
+					result.ValidationStatus,
+					statusCode,
+					pageTitle,
+					csvJSON(result.FoundKeywordsFromSets),
+					adHocKeywords,
+					csvInt(result.Attempts),
+					csvTime(result.LastCheckedAt),
+					csvUUID(result.ValidatedByPersonaID),
+					csvUUID(result.UsedProxyID),
+					result.CreatedAt.UTC().Format(time.RFC3339),
+				}); err != nil {
+					return err
+				}
+			}
+			return flushCSVExport(c, w)
+		})
+	finishCSVExport(c, campaign.ID, "HTTP keyword", w, err)
+}
+
+// exportableCampaign loads the campaign named in the path for a result export of campaignType,
+// writing an error response and returning false when it cannot be exported
+func (h *CampaignOrchestratorAPIHandler) exportableCampaign(c *gin.Context, campaignType models.CampaignTypeEnum) (*models.Campaign, bool) {
+	campaignID, err := uuid.Parse(c.Param("campaignId"))
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return nil, false
+	}
+	if h.resultExport == nil {
+		respondWithErrorGin(c, http.StatusServiceUnavailable, "Result export is not available")
+		return nil, false
+	}
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return nil, false
+	}
+	campaign, err := h.resultExport.ExportableCampaign(c.Request.Context(), campaignID, campaignType)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			respondWithErrorGin(c, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, services.ErrCampaignResultsNotExportable):
+			respondWithErrorGin(c, http.StatusBadRequest, err.Error())
+		default:
+			log.Printf("Error loading campaign %s for result export: %v", campaignID, err)
+			respondWithErrorGin(c, http.StatusInternalServerError, "Failed to get campaign details")
+		}
+		return nil, false
+	}
+	return campaign, true
+}
+
+// exportResultsFilter reads the same result filters as the JSON result endpoints
+func exportResultsFilter(c *gin.Context) store.ListValidationResultsFilter {
+	validationStatus := c.Query("validationStatus")
+	if maxedOut, _ := strconv.ParseBool(c.Query("maxAttemptsExceeded")); maxedOut {
+		validationStatus = string(models.ValidationStatusMaxAttemptsExceeded)
+	}
+	return store.ListValidationResultsFilter{ValidationStatus: validationStatus}
+}
+
+// startCSVExport writes the headers of a CSV attachment named after the campaign and its header row
+func startCSVExport(c *gin.Context, campaign *models.Campaign, kind string, header []string) *csv.Writer {
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": campaign.Name + "-" + kind + ".csv"})
+	if campaign.Name == "" || disposition == "" {
+		disposition = mime.FormatMediaType("attachment", map[string]string{"filename": campaign.ID.String() + "-" + kind + ".csv"})
+	}
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", disposition)
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write(header)
+	return w
+}
+
+// flushCSVExport sends the rows written so far to the client
+func flushCSVExport(c *gin.Context, w *csv.Writer) error {
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
+
+// finishCSVExport ends a CSV export. The status has already been sent, so an export that fails
+// part way is only logged and the client is left with a truncated file.
+func finishCSVExport(c *gin.Context, campaignID uuid.UUID, kind string, w *csv.Writer, err error) {
+	if err == nil {
+		err = flushCSVExport(c, w)
+	}
+	if err == nil {
+		return
+	}
+	if c.Request.Context().Err() != nil {
+		log.Printf("%s result export of campaign %s stopped: client disconnected", kind, campaignID)
+		return
+	}
+	log.Printf("Error exporting %s results of campaign %s: %v", kind, campaignID, err)
+	_ = c.Error(err)
+}
+
+func csvInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+func csvTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
+
+func csvUUID(value uuid.NullUUID) string {
+	if !value.Valid {
+		return ""
+	}
+	return value.UUID.String()
+}
+
+func csvJSON(value *json.RawMessage) string {
+	if value == nil {
+		return ""
+	}
+	return string(*value)
+}
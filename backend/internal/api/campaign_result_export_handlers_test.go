@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/middleware"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportedCampaignStore holds campaign results in domain order and records each query for them
+type exportedCampaignStore struct {
+	store.CampaignStore
+	campaigns   map[uuid.UUID]*models.Campaign
+	dnsResults  []*models.DNSValidationResult
+	httpResults []*models.HTTPKeywordResult
+	queries     []store.ListValidationResultsFilter
+	onQuery     func()
+}
+
+func (s *exportedCampaignStore) GetCampaignByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Campaign, error) {
+	campaign, ok := s.campaigns[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return campaign, nil
+}
+
+func (s *exportedCampaignStore) query(ctx context.Context, filter store.ListValidationResultsFilter) error {
+	s.queries = append(s.queries, filter)
+	if s.onQuery != nil {
+		s.onQuery()
+	}
+	return ctx.Err()
+}
+
+func (s *exportedCampaignStore) GetDNSValidationResultsByCampaign(ctx context.Context, exec store.Querier, campaignID uuid.UUID, filter store.ListValidationResultsFilter) ([]*models.DNSValidationResult, error) {
+	if err := s.query(ctx, filter); err != nil {
+		return nil, err
+	}
+	batch := []*models.DNSValidationResult{}
+	for _, result := range s.dnsResults {
+		if result.DomainName > filter.AfterDomainName && len(batch) < filter.Limit &&
+			(filter.ValidationStatus == "" || result.ValidationStatus == filter.ValidationStatus) {
+			batch = append(batch, result)
+		}
+	}
+	return batch, nil
+}
+
+func (s *exportedCampaignStore) GetHTTPKeywordResultsByCampaign(ctx context.Context, exec store.Querier, campaignID uuid.UUID, filter store.ListValidationResultsFilter) ([]*models.HTTPKeywordResult, error) {
+	if err := s.query(ctx, filter); err != nil {
+		return nil, err
+	}
+	batch := []*models.HTTPKeywordResult{}
+	for _, result := range s.httpResults {
+		if result.DomainName > filter.AfterDomainName && len(batch) < filter.Limit {
+			batch = append(batch, result)
+		}
+	}
+	return batch, nil
+}
+
+func newResultExportRouter(h *CampaignOrchestratorAPIHandler, securityContext *models.SecurityContext) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("security_context", securityContext)
+		c.Next()
+	})
+	scopeCampaigns := (&middleware.AuthMiddleware{}).ScopeToOwner("campaigns")
+	router.GET("/campaigns/:campaignId/results/dns-validation.csv", scopeCampaigns, h.exportDNSValidationResultsCSV)
+	router.GET("/campaigns/:campaignId/results/http-keyword.csv", scopeCampaigns, h.exportHTTPKeywordResultsCSV)
+	return router
+}
+
+func getResultExport(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestExportDNSValidationResultsCSV(t *testing.T) {
+	owner := uuid.New()
+	campaign := &models.Campaign{ID: uuid.New(), Name: "Q3 sweep", UserID: &owner, CampaignType: models.CampaignTypeDNSValidation}
+	httpCampaign := &models.Campaign{ID: uuid.New(), Name: "http", UserID: &owner, CampaignType: models.CampaignTypeHTTPKeywordValidation}
+	campaigns := map[uuid.UUID]*models.Campaign{campaign.ID: campaign, httpCampaign.ID: httpCampaign}
+	attempts := 3
+	cs := &exportedCampaignStore{campaigns: campaigns}
+	for i := 0; i < 5; i++ {
+		status := "valid_dns"
+		if i == 3 {
+			status = string(models.ValidationStatusMaxAttemptsExceeded)
+		}
+		cs.dnsResults = append(cs.dnsResults, &models.DNSValidationResult{DomainName: fmt.Sprintf("d%d.com", i), ValidationStatus: status, Attempts: &attempts})
+	}
+
+	h := NewCampaignOrchestratorAPIHandler(&statusOrchestratorService{campaigns: campaigns}, &memoryCampaignListViewStore{})
+	router := newResultExportRouter(h, &models.SecurityContext{UserID: owner, Roles: []string{"user"}})
+	path := "/campaigns/" + campaign.ID.String() + "/results/dns-validation.csv"
+	assert.Equal(t, http.StatusServiceUnavailable, getResultExport(router, path).Code)
+
+	appCfg := &config.AppConfig{Server: config.ServerConfig{ExportBatchSize: 2}}
+	h.SetResultExportService(services.NewCampaignResultExportService(appCfg, nil, cs))
+
+	w := getResultExport(router, path)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="Q3 sweep-dns-validation.csv"`, w.Header().Get("Content-Disposition"))
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 6)
+	assert.Equal(t, dnsValidationCSVHeader, rows[0])
+	assert.Equal(t, []string{"d0.com", "valid_dns", "", "3"}, rows[1][:4])
+	assert.Equal(t, "d4.com", rows[5][0])
+	require.Len(t, cs.queries, 3, "results are read in batches of exportBatchSize")
+	assert.Equal(t, "", cs.queries[0].AfterDomainName)
+	assert.Equal(t, "d1.com", cs.queries[1].AfterDomainName)
+	assert.Equal(t, "d3.com", cs.queries[2].AfterDomainName)
+
+	w = getResultExport(router, path+"?maxAttemptsExceeded=true")
+	require.Equal(t, http.StatusOK, w.Code)
+	rows, err = csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "d3.com", rows[1][0])
+
+	assert.Equal(t, http.StatusBadRequest, getResultExport(router, "/campaigns/"+httpCampaign.ID.String()+"/results/dns-validation.csv").Code)
+	assert.Equal(t, http.StatusNotFound, getResultExport(router, "/campaigns/"+uuid.New().String()+"/results/dns-validation.csv").Code)
+	otherRouter := newResultExportRouter(h, &models.SecurityContext{UserID: uuid.New(), Roles: []string{"user"}})
+	assert.Equal(t, http.StatusNotFound, getResultExport(otherRouter, path).Code, "other users cannot export the campaign")
+}
+
+func TestExportHTTPKeywordResultsCSV_StopsWhenClientDisconnects(t *testing.T) {
+	campaign := &models.Campaign{ID: uuid.New(), Name: "keywords", CampaignType: models.CampaignTypeHTTPKeywordValidation}
+	campaigns := map[uuid.UUID]*models.Campaign{campaign.ID: campaign}
+	statusCode, title := int32(200), "Home"
+	cs := &exportedCampaignStore{campaigns: campaigns}
+	for i := 0; i < 10; i++ {
+		cs.httpResults = append(cs.httpResults, &models.HTTPKeywordResult{
+			DomainName: fmt.Sprintf("d%d.com", i), ValidationStatus: "lead_valid", HTTPStatusCode: &statusCode,
+			PageTitle: &title, FoundAdHocKeywords: &[]string{"buy", "now"},
+		})
+	}
+	h := NewCampaignOrchestratorAPIHandler(&statusOrchestratorService{campaigns: campaigns}, &memoryCampaignListViewStore{})
+	h.SetResultExportService(services.NewCampaignResultExportService(&config.AppConfig{Server: config.ServerConfig{ExportBatchSize: 3}}, nil, cs))
+	router := newResultExportRouter(h, &models.SecurityContext{UserID: uuid.New(), Roles: []string{"admin"}})
+
+	ctx, disconnect := context.WithCancel(context.Background())
+	defer disconnect()
+	cs.onQuery = func() {
+		if len(cs.queries) == 2 {
+			disconnect()
+		}
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/campaigns/"+campaign.ID.String()+"/results/http-keyword.csv", nil).WithContext(ctx))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, cs.queries, 2, "no batch is read after the client disconnects")
+	rows, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 4, "only the batch sent before the disconnect is written")
+	assert.Equal(t, httpKeywordCSVHeader, rows[0])
+	assert.Equal(t, []string{"d0.com", "lead_valid", "200", "Home", "", "buy;now"}, rows[1][:6])
+}
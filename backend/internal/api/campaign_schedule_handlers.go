@@ -0,0 +1,206 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ScheduleCampaignRequest is the payload for scheduling a campaign; set exactly one field.
+type ScheduleCampaignRequest struct {
+	RunAt *time.Time `json:"run_at,omitempty"` // RFC3339 time to start the pending campaign at
+	Cron  string     `json:"cron,omitempty"`   // Five-field cron expression, in UTC, to start a clone of the campaign on
+}
+
+// CampaignScheduleResponse is a campaign's schedule and the outcome of its last firing
+type CampaignScheduleResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	CampaignID     uuid.UUID  `json:"campaignId"`
+	RunAt          *time.Time `json:"runAt,omitempty"`
+	Cron           string     `json:"cron,omitempty"`
+	NextRunAt      *time.Time `json:"nextRunAt,omitempty"` // Unset once a one-off schedule has fired
+	LastRunAt      *time.Time `json:"lastRunAt,omitempty"`
+	LastCampaignID *uuid.UUID `json:"lastCampaignId,omitempty"` // Campaign started by the last firing
+	LastError      string     `json:"lastError,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+func toCampaignScheduleResponse(schedule *models.CampaignSchedule) CampaignScheduleResponse {
+	response := CampaignScheduleResponse{
+		ID:         schedule.ID,
+		CampaignID: schedule.CampaignID,
+		Cron:       schedule.CronExpression.String,
+		LastError:  schedule.LastError.String,
+		CreatedAt:  schedule.CreatedAt,
+		UpdatedAt:  schedule.UpdatedAt,
+	}
+	if schedule.RunAt.Valid {
+		response.RunAt = &schedule.RunAt.Time
+	}
+	if schedule.NextRunAt.Valid {
+		response.NextRunAt = &schedule.NextRunAt.Time
+	}
+	if schedule.LastRunAt.Valid {
+		response.LastRunAt = &schedule.LastRunAt.Time
+	}
+	if schedule.LastCampaignID.Valid {
+		response.LastCampaignID = &schedule.LastCampaignID.UUID
+	}
+	return response
+}
+
+// scheduleCampaign starts a campaign at a set time or clones and starts it on a cron expression
+// @Summary Schedule a campaign
+// @Description Give the campaign a schedule, replacing the one it had. With run_at the pending campaign is started at that time. With cron a clone of the campaign is created and started each time the expression fires, in UTC; the campaign itself is left as it is.
+// @Tags Campaigns
+// @Accept json
+// @Produce json
+// @Param campaignId path string true "Campaign ID"
+// @Param request body ScheduleCampaignRequest true "Either run_at or cron"
+// @Success 201 {object} CampaignScheduleResponse "Campaign scheduled"
+// @Failure 400 {object} models.ErrorResponse "Invalid campaign ID, run time or cron expression"
+// @Failure 404 {object} models.ErrorResponse "Campaign not found"
+// @Failure 409 {object} models.ErrorResponse "Campaign is not pending"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 503 {object} models.ErrorResponse "Campaign schedules are not available"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/schedule [post]
+func (h *CampaignOrchestratorAPIHandler) scheduleCampaign(c *gin.Context) {
+	campaignIDStr := c.Param("campaignId")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+	if h.scheduler == nil {
+		respondWithErrorGin(c, http.StatusServiceUnavailable, "Campaign schedules are not available")
+		return
+	}
+
+	var req ScheduleCampaignRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondWithValidationErrorGin(c, []ErrorDetail{{
+			Field:   bindErrorField(err),
+			Code:    ErrorCodeValidation,
+			Message: "Invalid request payload: " + err.Error(),
+		}})
+		return
+	}
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
+
+	var createdBy *uuid.UUID
+	if userID, ok := currentUserID(c); ok {
+		createdBy = &userID
+	}
+	schedule, err := h.scheduler.Schedule(c.Request.Context(), campaignID, req.RunAt, req.Cron, createdBy)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCampaignSchedule):
+			respondWithValidationErrorGin(c, []ErrorDetail{{
+				Code:    ErrorCodeValidation,
+				Message: err.Error(),
+			}})
+		case errors.Is(err, store.ErrNotFound):
+			respondWithErrorGin(c, http.StatusNotFound, "Campaign not found")
+		case errors.Is(err, services.ErrCampaignNotPending):
+			respondWithErrorGin(c, http.StatusConflict, "Only pending campaigns can be started at a set time; use cron to start copies of it")
+		default:
+			log.Printf("Error scheduling campaign %s: %v", campaignIDStr, err)
+			respondWithErrorGin(c, http.StatusInternalServerError, "Failed to schedule campaign")
+		}
+		return
+	}
+	respondWithJSONGin(c, http.StatusCreated, toCampaignScheduleResponse(schedule))
+}
+
+// getCampaignSchedule returns a campaign's schedule
+// @Summary Get a campaign's schedule
+// @Description Get the campaign's schedule, when it fires next and the outcome of its last firing.
+// @Tags Campaigns
+// @Produce json
+// @Param campaignId path string true "Campaign ID"
+// @Success 200 {object} CampaignScheduleResponse "Campaign schedule"
+// @Failure 400 {object} models.ErrorResponse "Invalid campaign ID"
+// @Failure 404 {object} models.ErrorResponse "Campaign not found or not scheduled"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 503 {object} models.ErrorResponse "Campaign schedules are not available"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/schedule [get]
+func (h *CampaignOrchestratorAPIHandler) getCampaignSchedule(c *gin.Context) {
+	campaignIDStr := c.Param("campaignId")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+	if h.scheduler == nil {
+		respondWithErrorGin(c, http.StatusServiceUnavailable, "Campaign schedules are not available")
+		return
+	}
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
+
+	schedule, err := h.scheduler.Get(c.Request.Context(), campaignID)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithErrorGin(c, http.StatusNotFound, "Campaign is not scheduled")
+		return
+	}
+	if err != nil {
+		log.Printf("Error getting schedule of campaign %s: %v", campaignIDStr, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to get campaign schedule")
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, toCampaignScheduleResponse(schedule))
+}
+
+// unscheduleCampaign removes a campaign's schedule
+// @Summary Remove a campaign's schedule
+// @Description Stop starting the campaign, or clones of it, on its schedule. Campaigns already started are left running.
+// @Tags Campaigns
+// @Produce json
+// @Param campaignId path string true "Campaign ID"
+// @Success 200 {object} map[string]string "Schedule removed"
+// @Failure 400 {object} models.ErrorResponse "Invalid campaign ID"
+// @Failure 404 {object} models.ErrorResponse "Campaign not found or not scheduled"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 503 {object} models.ErrorResponse "Campaign schedules are not available"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/schedule [delete]
+func (h *CampaignOrchestratorAPIHandler) unscheduleCampaign(c *gin.Context) {
+	campaignIDStr := c.Param("campaignId")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+	if h.scheduler == nil {
+		respondWithErrorGin(c, http.StatusServiceUnavailable, "Campaign schedules are not available")
+		return
+	}
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
+
+	err = h.scheduler.Unschedule(c.Request.Context(), campaignID)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithErrorGin(c, http.StatusNotFound, "Campaign is not scheduled")
+		return
+	}
+	if err != nil {
+		log.Printf("Error removing schedule of campaign %s: %v", campaignIDStr, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to remove campaign schedule")
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, map[string]string{"message": "Schedule removed"})
+}
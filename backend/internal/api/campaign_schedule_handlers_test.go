@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// singleScheduleStore holds at most one campaign schedule
+type singleScheduleStore struct {
+	store.CampaignScheduleStore
+	schedule *models.CampaignSchedule
+}
+
+func (s *singleScheduleStore) UpsertCampaignSchedule(ctx context.Context, exec store.Querier, schedule *models.CampaignSchedule) error {
+	schedule.ID = uuid.New()
+	s.schedule = schedule
+	return nil
+}
+
+func (s *singleScheduleStore) GetCampaignScheduleByCampaignID(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.CampaignSchedule, error) {
+	if s.schedule == nil || s.schedule.CampaignID != campaignID {
+		return nil, store.ErrNotFound
+	}
+	return s.schedule, nil
+}
+
+func (s *singleScheduleStore) DeleteCampaignSchedule(ctx context.Context, exec store.Querier, campaignID uuid.UUID) error {
+	if s.schedule == nil || s.schedule.CampaignID != campaignID {
+		return store.ErrNotFound
+	}
+	s.schedule = nil
+	return nil
+}
+
+func TestCampaignScheduleHandlers(t *testing.T) {
+	campaign := &models.Campaign{ID: uuid.New(), Name: "Nightly", CampaignType: models.CampaignTypeDNSValidation, Status: models.CampaignStatusCompleted}
+	cs := &lookupCampaignStore{campaigns: map[uuid.UUID]*models.Campaign{campaign.ID: campaign}}
+	orchestrator := services.NewCampaignOrchestratorService(nil, cs, nil, nil, nil, nil, nil, nil, nil)
+	h := NewCampaignOrchestratorAPIHandler(orchestrator, &memoryCampaignListViewStore{})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/campaigns/:campaignId/schedule", h.scheduleCampaign)
+	router.GET("/campaigns/:campaignId/schedule", h.getCampaignSchedule)
+	router.DELETE("/campaigns/:campaignId/schedule", h.unscheduleCampaign)
+	serve := func(method string, id uuid.UUID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/campaigns/"+id.String()+"/schedule", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, serve(http.MethodPost, campaign.ID, `{"cron":"@daily"}`).Code)
+	h.SetCampaignScheduler(services.NewCampaignScheduler(nil, nil, &singleScheduleStore{}, cs, orchestrator))
+
+	runAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, campaign.ID, `{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, campaign.ID, `{"cron":"every day"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, campaign.ID, `{"run_at":"tomorrow"}`).Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, uuid.New(), `{"cron":"@daily"}`).Code)
+	assert.Equal(t, http.StatusConflict, serve(http.MethodPost, campaign.ID, `{"run_at":"`+runAt+`"}`).Code,
+		"a completed campaign cannot be started at a set time")
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, campaign.ID, "").Code)
+
+	w := serve(http.MethodPost, campaign.ID, `{"cron":"30 2 * * *"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var envelope struct {
+		Data CampaignScheduleResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	assert.Equal(t, campaign.ID, envelope.Data.CampaignID)
+	assert.Equal(t, "30 2 * * *", envelope.Data.Cron)
+	require.NotNil(t, envelope.Data.NextRunAt)
+	assert.Equal(t, 2, envelope.Data.NextRunAt.Hour())
+	assert.Equal(t, 30, envelope.Data.NextRunAt.Minute())
+	assert.Nil(t, envelope.Data.RunAt)
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, campaign.ID, "").Code)
+	assert.Equal(t, http.StatusOK, serve(http.MethodDelete, campaign.ID, "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodDelete, campaign.ID, "").Code)
+}
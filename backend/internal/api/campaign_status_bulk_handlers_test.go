@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/middleware"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statusOrchestratorService answers status lookups from a fixed set of campaigns
+type statusOrchestratorService struct {
+	services.CampaignOrchestratorService
+	campaigns  map[uuid.UUID]*models.Campaign
+	bulkCalls  int
+	lastUserID string
+}
+
+func (s *statusOrchestratorService) GetCampaignDetails(ctx context.Context, campaignID uuid.UUID) (*models.Campaign, interface{}, error) {
+	campaign, ok := s.campaigns[campaignID]
+	if !ok {
+		return nil, nil, store.ErrNotFound
+	}
+	return campaign, nil, nil
+}
+
+func (s *statusOrchestratorService) GetCampaignStatus(ctx context.Context, campaignID uuid.UUID) (models.CampaignStatusEnum, *float64, error) {
+	campaign, ok := s.campaigns[campaignID]
+	if !ok {
+		return "", nil, store.ErrNotFound
+	}
+	return campaign.Status, campaign.ProgressPercentage, nil
+}
+
+func (s *statusOrchestratorService) ListCampaignStatuses(ctx context.Context, campaignIDs []uuid.UUID, userID string) ([]store.CampaignStatusSummary, error) {
+	s.bulkCalls++
+	s.lastUserID = userID
+	var statuses []store.CampaignStatusSummary
+	for _, campaignID := range campaignIDs {
+		campaign, ok := s.campaigns[campaignID]
+		if !ok || (userID != "" && (campaign.UserID == nil || campaign.UserID.String() != userID)) {
+			continue
+		}
+		statuses = append(statuses, store.CampaignStatusSummary{ID: campaign.ID, Status: campaign.Status, ProgressPercentage: campaign.ProgressPercentage})
+	}
+	return statuses, nil
+}
+
+func newCampaignStatusRouter(h *CampaignOrchestratorAPIHandler, securityContext *models.SecurityContext) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("security_context", securityContext)
+		c.Next()
+	})
+	scopeCampaigns := (&middleware.AuthMiddleware{}).ScopeToOwner("campaigns")
+	router.GET("/campaigns/status", scopeCampaigns, h.getCampaignStatuses)
+	router.GET("/campaigns/:campaignId/status", scopeCampaigns, h.getCampaignStatus)
+	return router
+}
+
+func getJSON(t *testing.T, router *gin.Engine, path string, into interface{}) int {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	if w.Code == http.StatusOK {
+		var envelope struct {
+			Data json.RawMessage `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope), w.Body.String())
+		require.NoError(t, json.Unmarshal(envelope.Data, into), w.Body.String())
+	}
+	return w.Code
+}
+
+func TestGetCampaignStatuses_MatchesIndividualStatusAndScopesToOwner(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	running := &models.Campaign{ID: uuid.New(), UserID: &owner, Status: models.CampaignStatusRunning, ProgressPercentage: models.Float64Ptr(42.5)}
+	pending := &models.Campaign{ID: uuid.New(), UserID: &owner, Status: models.CampaignStatusPending}
+	othersCampaign := &models.Campaign{ID: uuid.New(), UserID: &other, Status: models.CampaignStatusCompleted, ProgressPercentage: models.Float64Ptr(100)}
+	orchestrator := &statusOrchestratorService{campaigns: map[uuid.UUID]*models.Campaign{
+		running.ID: running, pending.ID: pending, othersCampaign.ID: othersCampaign,
+	}}
+	h := NewCampaignOrchestratorAPIHandler(orchestrator, &memoryCampaignListViewStore{})
+	router := newCampaignStatusRouter(h, &models.SecurityContext{UserID: owner, Roles: []string{"user"}})
+
+	missing := uuid.New()
+	var bulk CampaignStatusesResponse
+	path := fmt.Sprintf("/campaigns/status?ids=%s,%s,%s,%s", pending.ID, othersCampaign.ID, running.ID, missing)
+	require.Equal(t, http.StatusOK, getJSON(t, router, path, &bulk))
+	assert.Equal(t, 1, orchestrator.bulkCalls)
+	assert.Equal(t, owner.String(), orchestrator.lastUserID)
+
+	// Each campaign's entry matches its own status call, in the order asked for
+	require.Len(t, bulk.Campaigns, 2)
+	for i, campaign := range []*models.Campaign{pending, running} {
+		var single CampaignStatusResponse
+		require.Equal(t, http.StatusOK, getJSON(t, router, "/campaigns/"+campaign.ID.String()+"/status", &single))
+		assert.Equal(t, campaign.ID, bulk.Campaigns[i].ID)
+		assert.Equal(t, single.Status, bulk.Campaigns[i].Status)
+		assert.Equal(t, single.ProgressPercentage, bulk.Campaigns[i].ProgressPercentage)
+	}
+
+	// Another user's campaign is indistinguishable from one that does not exist
+	assert.Equal(t, []uuid.UUID{othersCampaign.ID, missing}, bulk.NotFound)
+	var single CampaignStatusResponse
+	assert.Equal(t, http.StatusNotFound, getJSON(t, router, "/campaigns/"+othersCampaign.ID.String()+"/status", &single))
+
+	// Admins are not scoped
+	adminRouter := newCampaignStatusRouter(h, &models.SecurityContext{UserID: uuid.New(), Roles: []string{"admin"}})
+	require.Equal(t, http.StatusOK, getJSON(t, adminRouter, "/campaigns/status?ids="+othersCampaign.ID.String(), &bulk))
+	assert.Empty(t, orchestrator.lastUserID)
+	require.Len(t, bulk.Campaigns, 1)
+	assert.Equal(t, models.CampaignStatusCompleted, bulk.Campaigns[0].Status)
+}
+
+func TestGetCampaignStatuses_EnforcesIDCap(t *testing.T) {
+	orchestrator := &statusOrchestratorService{campaigns: map[uuid.UUID]*models.Campaign{}}
+	h := NewCampaignOrchestratorAPIHandler(orchestrator, &memoryCampaignListViewStore{})
+	h.SetMaxBulkStatusIDs(2)
+	router := newCampaignStatusRouter(h, &models.SecurityContext{UserID: uuid.New(), Roles: []string{"admin"}})
+
+	ids := []string{uuid.NewString(), uuid.NewString(), uuid.NewString()}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/campaigns/status?ids="+strings.Join(ids, ","), nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "between 1 and 2")
+
+	// Repeated IDs count once
+	var bulk CampaignStatusesResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/campaigns/status?ids="+ids[0]+","+ids[1]+"&ids="+ids[0], &bulk))
+	assert.Len(t, bulk.NotFound, 2)
+
+	for _, query := range []string{"", "?ids=", "?ids=not-a-uuid"} {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/campaigns/status"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	assert.Equal(t, 1, orchestrator.bulkCalls)
+}
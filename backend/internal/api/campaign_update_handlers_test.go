@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// updatableCampaignStore holds one campaign that UpdateCampaign overwrites
+type updatableCampaignStore struct {
+	store.CampaignStore
+	campaign *models.Campaign
+}
+
+func (s *updatableCampaignStore) GetCampaignByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Campaign, error) {
+	if id != s.campaign.ID {
+		return nil, store.ErrNotFound
+	}
+	copied := *s.campaign
+	return &copied, nil
+}
+
+func (s *updatableCampaignStore) UpdateCampaign(ctx context.Context, exec store.Querier, campaign *models.Campaign) error {
+	s.campaign = campaign
+	return nil
+}
+
+func putCampaign(h *CampaignOrchestratorAPIHandler, campaignID uuid.UUID, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/campaigns/:campaignId", h.updateCampaign)
+	req := httptest.NewRequest(http.MethodPut, "/campaigns/"+campaignID.String(), strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUpdateCampaign_RunningCampaignRejectsImmutableFields(t *testing.T) {
+	campaignStore := &updatableCampaignStore{campaign: &models.Campaign{
+		ID: uuid.New(), Name: "generation", CampaignType: models.CampaignTypeDomainGeneration, Status: models.CampaignStatusRunning,
+	}}
+	h := NewCampaignOrchestratorAPIHandler(services.NewCampaignOrchestratorService(nil, campaignStore, nil, nil, nil, nil, nil, nil, nil), &memoryCampaignListViewStore{})
+
+	w := putCampaign(h, campaignStore.campaign.ID, `{"name":"renamed","patternType":"suffix","characterSet":"xyz"}`)
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	var resp APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, ErrorCodeInvalidState, resp.Error.Code)
+	var fields []string
+	for _, detail := range resp.Error.Details {
+		fields = append(fields, detail.Field)
+	}
+	assert.Equal(t, []string{"patternType", "characterSet"}, fields)
+	assert.Equal(t, "generation", campaignStore.campaign.Name)
+
+	// Name, tags and metadata may still change
+	w = putCampaign(h, campaignStore.campaign.ID, `{"name":"renamed","tags":["nightly"],"metadata":{"owner":"ops"}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "renamed", campaignStore.campaign.Name)
+	assert.Equal(t, []string{"nightly"}, []string(campaignStore.campaign.Tags))
+
+	w = putCampaign(h, uuid.New(), `{"name":"missing"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateCampaignWebhookRequest is the payload for registering a campaign webhook.
+type CreateCampaignWebhookRequest struct {
+	URL    string `json:"url" validate:"required,max=2048"`
+	Secret string `json:"secret,omitempty" validate:"omitempty,min=16,max=256"` // Generated when omitted
+}
+
+// CampaignWebhookResponse is a registered campaign webhook. Secret is only ever returned here, when
+// the webhook is created.
+type CampaignWebhookResponse struct {
+	ID         uuid.UUID `json:"id"`
+	CampaignID uuid.UUID `json:"campaignId"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// createCampaignWebhook registers a webhook notified when the campaign completes, fails or is cancelled
+// @Summary Register a campaign webhook
+// @Description Register a URL that is sent a signed notification once the campaign completes, fails or is cancelled. The payload is signed with the webhook's secret, which is generated when omitted and only returned in this response.
+// @Tags Campaigns
+// @Accept json
+// @Produce json
+// @Param campaignId path string true "Campaign ID"
+// @Param request body CreateCampaignWebhookRequest true "Webhook URL and optional secret"
+// @Success 201 {object} CampaignWebhookResponse "Registered webhook, including its secret"
+// @Failure 400 {object} models.ErrorResponse "Invalid campaign ID or request payload"
+// @Failure 404 {object} models.ErrorResponse "Campaign not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 503 {object} models.ErrorResponse "Campaign webhooks are not available"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/webhooks [post]
+func (h *CampaignOrchestratorAPIHandler) createCampaignWebhook(c *gin.Context) {
+	campaignIDStr := c.Param("campaignId")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+	if h.campaignWebhooks == nil {
+		respondWithErrorGin(c, http.StatusServiceUnavailable, "Campaign webhooks are not available")
+		return
+	}
+
+	var req CreateCampaignWebhookRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondWithValidationErrorGin(c, []ErrorDetail{{
+			Field:   bindErrorField(err),
+			Code:    ErrorCodeValidation,
+			Message: "Invalid request payload: " + err.Error(),
+		}})
+		return
+	}
+	if validate != nil {
+		if err := validate.Struct(req); err != nil {
+			respondWithValidationErrorGin(c, []ErrorDetail{{
+				Code:    ErrorCodeValidation,
+				Message: "Validation failed: " + err.Error(),
+			}})
+			return
+		}
+	}
+
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
+
+	var createdBy *uuid.UUID
+	if userID, ok := currentUserID(c); ok {
+		createdBy = &userID
+	}
+	webhook, err := h.campaignWebhooks.Register(c.Request.Context(), campaignID, req.URL, req.Secret, createdBy)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCampaignWebhookURL):
+			respondWithValidationErrorGin(c, []ErrorDetail{{
+				Field:   "url",
+				Code:    ErrorCodeValidation,
+				Message: err.Error(),
+			}})
+		case errors.Is(err, store.ErrNotFound):
+			respondWithErrorGin(c, http.StatusNotFound, "Campaign not found")
+		default:
+			log.Printf("Error registering webhook for campaign %s: %v", campaignIDStr, err)
+			respondWithErrorGin(c, http.StatusInternalServerError, "Failed to register campaign webhook")
+		}
+		return
+	}
+	respondWithJSONGin(c, http.StatusCreated, CampaignWebhookResponse{
+		ID:         webhook.ID,
+		CampaignID: webhook.CampaignID,
+		URL:        webhook.URL,
+		Secret:     webhook.Secret,
+		CreatedAt:  webhook.CreatedAt,
+	})
+}
+
+// deleteCampaignWebhook removes a webhook from a campaign
+// @Summary Delete a campaign webhook
+// @Description Stop notifying a webhook of the campaign's completion. Deliveries already recorded can no longer be replayed.
+// @Tags Campaigns
+// @Produce json
+// @Param campaignId path string true "Campaign ID"
+// @Param webhookId path string true "Webhook ID"
+// @Success 200 {object} map[string]string "Webhook deleted"
+// @Failure 400 {object} models.ErrorResponse "Invalid campaign or webhook ID"
+// @Failure 404 {object} models.ErrorResponse "Campaign or webhook not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 503 {object} models.ErrorResponse "Campaign webhooks are not available"
+// @Security SessionAuth
+// @Router /campaigns/{campaignId}/webhooks/{webhookId} [delete]
+func (h *CampaignOrchestratorAPIHandler) deleteCampaignWebhook(c *gin.Context) {
+	campaignIDStr := c.Param("campaignId")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid campaign ID format")
+		return
+	}
+	webhookID, err := uuid.Parse(c.Param("webhookId"))
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid webhook ID format")
+		return
+	}
+	if h.campaignWebhooks == nil {
+		respondWithErrorGin(c, http.StatusServiceUnavailable, "Campaign webhooks are not available")
+		return
+	}
+	if !h.ensureCampaignOwnership(c, campaignID) {
+		return
+	}
+
+	if err := h.campaignWebhooks.Delete(c.Request.Context(), campaignID, webhookID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithErrorGin(c, http.StatusNotFound, "Webhook not found")
+			return
+		}
+		log.Printf("Error deleting webhook %s of campaign %s: %v", webhookID, campaignIDStr, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to delete campaign webhook")
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, map[string]string{"message": "Webhook deleted"})
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/middleware"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCampaignWebhookStore keeps registered webhooks in memory
+type memoryCampaignWebhookStore struct {
+	store.CampaignWebhookStore
+	webhooks map[uuid.UUID]*models.CampaignWebhook
+}
+
+func (m *memoryCampaignWebhookStore) CreateCampaignWebhook(ctx context.Context, exec store.Querier, webhook *models.CampaignWebhook) error {
+	webhook.ID = uuid.New()
+	m.webhooks[webhook.ID] = webhook
+	return nil
+}
+
+func (m *memoryCampaignWebhookStore) DeleteCampaignWebhook(ctx context.Context, exec store.Querier, campaignID, webhookID uuid.UUID) error {
+	if webhook, ok := m.webhooks[webhookID]; !ok || webhook.CampaignID != campaignID {
+		return store.ErrNotFound
+	}
+	delete(m.webhooks, webhookID)
+	return nil
+}
+
+func newCampaignWebhookRouter(h *CampaignOrchestratorAPIHandler, securityContext *models.SecurityContext) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("security_context", securityContext)
+		c.Next()
+	})
+	scopeCampaigns := (&middleware.AuthMiddleware{}).ScopeToOwner("campaigns")
+	router.POST("/campaigns/:campaignId/webhooks", scopeCampaigns, h.createCampaignWebhook)
+	router.DELETE("/campaigns/:campaignId/webhooks/:webhookId", scopeCampaigns, h.deleteCampaignWebhook)
+	return router
+}
+
+func sendCampaignWebhookRequest(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCampaignWebhookHandlers(t *testing.T) {
+	owner := uuid.New()
+	campaign := &models.Campaign{ID: uuid.New(), UserID: &owner, Status: models.CampaignStatusRunning}
+	orchestrator := &statusOrchestratorService{campaigns: map[uuid.UUID]*models.Campaign{campaign.ID: campaign}}
+	webhookStore := &memoryCampaignWebhookStore{webhooks: make(map[uuid.UUID]*models.CampaignWebhook)}
+	h := NewCampaignOrchestratorAPIHandler(orchestrator, &memoryCampaignListViewStore{})
+	router := newCampaignWebhookRouter(h, &models.SecurityContext{UserID: owner, Roles: []string{"user"}})
+	path := "/campaigns/" + campaign.ID.String() + "/webhooks"
+
+	w := sendCampaignWebhookRequest(router, http.MethodPost, path, `{"url":"https://example.com/hook"}`)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "webhooks are unavailable until the service is set")
+
+	h.SetCampaignWebhookService(services.NewCampaignWebhookService(nil, webhookStore,
+		&updatableCampaignStore{campaign: campaign}, nil, config.CampaignWebhookConfig{}))
+
+	w = sendCampaignWebhookRequest(router, http.MethodPost, path, `{"url":"https://example.com/hook"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var envelope struct {
+		Data CampaignWebhookResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	created := envelope.Data
+	assert.Equal(t, campaign.ID, created.CampaignID)
+	assert.Equal(t, "https://example.com/hook", created.URL)
+	assert.Len(t, created.Secret, 64)
+	require.Contains(t, webhookStore.webhooks, created.ID)
+	assert.Equal(t, owner, webhookStore.webhooks[created.ID].CreatedBy.UUID)
+
+	w = sendCampaignWebhookRequest(router, http.MethodPost, path, `{"url":"ftp://example.com/hook"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	w = sendCampaignWebhookRequest(router, http.MethodPost, path, `{"url":"https://example.com/hook","secret":"short"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	// Another user can neither add nor remove the campaign's webhooks
+	otherRouter := newCampaignWebhookRouter(h, &models.SecurityContext{UserID: uuid.New(), Roles: []string{"user"}})
+	w = sendCampaignWebhookRequest(otherRouter, http.MethodPost, path, `{"url":"https://example.com/other"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	w = sendCampaignWebhookRequest(otherRouter, http.MethodDelete, path+"/"+created.ID.String(), "")
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	assert.Len(t, webhookStore.webhooks, 1)
+
+	w = sendCampaignWebhookRequest(router, http.MethodDelete, path+"/"+created.ID.String(), "")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Empty(t, webhookStore.webhooks)
+	w = sendCampaignWebhookRequest(router, http.MethodDelete, path+"/"+created.ID.String(), "")
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+}
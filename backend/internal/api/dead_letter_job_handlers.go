@@ -0,0 +1,102 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DeadLetterJobAPIHandler exposes admin endpoints for inspecting and replaying campaign jobs that
+// failed on every attempt they were allowed.
+type DeadLetterJobAPIHandler struct {
+	deadLetters store.CampaignJobDeadLetterStore
+}
+
+// NewDeadLetterJobAPIHandler creates a new handler for dead letter jobs.
+func NewDeadLetterJobAPIHandler(deadLetters store.CampaignJobDeadLetterStore) *DeadLetterJobAPIHandler {
+	return &DeadLetterJobAPIHandler{deadLetters: deadLetters}
+}
+
+// ListDeadLetterJobsGin lists dead letter jobs.
+// @Summary List dead letter jobs
+// @Description List campaign jobs that failed on every attempt, most recently dead-lettered first, with the error of each attempt
+// @Tags Admin
+// @Produce json
+// @Param campaignId query string false "Filter by campaign ID"
+// @Param limit query int false "Maximum number of jobs to return (1-100)" default(50)
+// @Param offset query int false "Number of jobs to skip" default(0)
+// @Success 200 {array} models.DeadLetterJob "List of dead letter jobs"
+// @Failure 400 {object} models.ErrorResponse "Invalid query parameters"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /admin/jobs/dead-letter [get]
+func (h *DeadLetterJobAPIHandler) ListDeadLetterJobsGin(c *gin.Context) {
+	page, err := parsePagination(c, 50, DefaultMaxPageSize)
+	if err != nil {
+		respondWithPaginationErrorGin(c, err)
+		return
+	}
+
+	filter := store.ListDeadLetterJobsFilter{Limit: page.Limit, Offset: page.Offset}
+	if campaignIDStr := c.Query("campaignId"); campaignIDStr != "" {
+		campaignID, err := uuid.Parse(campaignIDStr)
+		if err != nil {
+			respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation,
+				"Invalid campaignId parameter", []ErrorDetail{
+					{
+						Field:   "campaignId",
+						Code:    ErrorCodeValidation,
+						Message: "campaignId must be a UUID",
+					},
+				})
+			return
+		}
+		filter.CampaignID = uuid.NullUUID{UUID: campaignID, Valid: true}
+	}
+
+	deadLetters, err := h.deadLetters.ListDeadLetterJobs(c.Request.Context(), nil, filter)
+	if err != nil {
+		log.Printf("Error listing dead letter jobs: %v", err)
+		respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeDatabaseError,
+			"Failed to retrieve dead letter jobs", nil)
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, deadLetters)
+}
+
+// ReplayDeadLetterJobGin puts a dead letter job back in the job queue.
+// @Summary Replay a dead letter job
+// @Description Queue the dead letter job again, with its ID and no attempts made, and remove it from the dead letters. The campaign's status is left as it is.
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.CampaignJob "The queued job"
+// @Failure 400 {object} models.ErrorResponse "Invalid job ID"
+// @Failure 404 {object} models.ErrorResponse "Dead letter job not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /admin/jobs/{id}/replay [post]
+func (h *DeadLetterJobAPIHandler) ReplayDeadLetterJobGin(c *gin.Context) {
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid job ID format")
+		return
+	}
+
+	job, err := h.deadLetters.ReplayDeadLetterJob(c.Request.Context(), nil, jobID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithErrorGin(c, http.StatusNotFound, "Dead letter job not found")
+			return
+		}
+		log.Printf("Error replaying dead letter job %s: %v", jobID, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to replay dead letter job")
+		return
+	}
+	log.Printf("Dead letter job %s of campaign %s queued again", job.ID, job.CampaignID)
+	respondWithJSONGin(c, http.StatusOK, job)
+}
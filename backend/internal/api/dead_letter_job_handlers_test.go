@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryDeadLetterStore holds dead letter jobs and the jobs replayed from them
+type memoryDeadLetterStore struct {
+	deadLetters []*models.DeadLetterJob
+	filter      store.ListDeadLetterJobsFilter
+	replayed    []*models.CampaignJob
+}
+
+func (s *memoryDeadLetterStore) DeadLetterJob(ctx context.Context, exec store.Querier, job *models.CampaignJob) (*models.DeadLetterJob, error) {
+	return nil, store.ErrNotFound
+}
+
+func (s *memoryDeadLetterStore) ListDeadLetterJobs(ctx context.Context, exec store.Querier, filter store.ListDeadLetterJobsFilter) ([]*models.DeadLetterJob, error) {
+	s.filter = filter
+	return s.deadLetters, nil
+}
+
+func (s *memoryDeadLetterStore) ReplayDeadLetterJob(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.CampaignJob, error) {
+	for i, deadLetter := range s.deadLetters {
+		if deadLetter.ID == id {
+			s.deadLetters = append(s.deadLetters[:i], s.deadLetters[i+1:]...)
+			job := &models.CampaignJob{ID: id, CampaignID: deadLetter.CampaignID, JobType: deadLetter.JobType,
+				Status: models.JobStatusQueued, MaxAttempts: deadLetter.MaxAttempts}
+			s.replayed = append(s.replayed, job)
+			return job, nil
+		}
+	}
+	return nil, store.ErrNotFound
+}
+
+func TestDeadLetterJobHandlers(t *testing.T) {
+	deadLetter := &models.DeadLetterJob{ID: uuid.New(), CampaignID: uuid.New(), JobType: models.CampaignTypeDNSValidation,
+		Attempts: 3, MaxAttempts: 3, LastError: "resolver unavailable"}
+	deadLetters := &memoryDeadLetterStore{deadLetters: []*models.DeadLetterJob{deadLetter}}
+	h := NewDeadLetterJobAPIHandler(deadLetters)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/jobs/dead-letter", h.ListDeadLetterJobsGin)
+	router.POST("/admin/jobs/:id/replay", h.ReplayDeadLetterJobGin)
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	w := serve(http.MethodGet, "/admin/jobs/dead-letter?limit=10&offset=5&campaignId="+deadLetter.CampaignID.String())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var listed struct {
+		Data []models.DeadLetterJob `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Len(t, listed.Data, 1)
+	assert.Equal(t, "resolver unavailable", listed.Data[0].LastError)
+	assert.Equal(t, store.ListDeadLetterJobsFilter{
+		CampaignID: uuid.NullUUID{UUID: deadLetter.CampaignID, Valid: true}, Limit: 10, Offset: 5,
+	}, deadLetters.filter)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/admin/jobs/dead-letter?campaignId=nope").Code)
+
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/admin/jobs/nope/replay").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "/admin/jobs/"+uuid.NewString()+"/replay").Code)
+	w = serve(http.MethodPost, "/admin/jobs/"+deadLetter.ID.String()+"/replay")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var replayed struct {
+		Data models.CampaignJob `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &replayed))
+	assert.Equal(t, deadLetter.ID, replayed.Data.ID)
+	assert.Equal(t, models.JobStatusQueued, replayed.Data.Status)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "/admin/jobs/"+deadLetter.ID.String()+"/replay").Code,
+		"a replayed job is no longer a dead letter")
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCampaign_ValidatesDNSExpectedRecords(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	orchestrator := &creatingOrchestratorService{}
+	h := NewCampaignOrchestratorAPIHandler(orchestrator, &memoryCampaignListViewStore{})
+	router := gin.New()
+	router.POST("/campaigns", h.createCampaign)
+
+	post := func(expectedRecords string) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"campaignType":"dns_validation","name":"expected records","dnsValidationParams":{"sourceCampaignId":"%s","personaIds":["%s"],"batchSize":10,"expectedRecords":%s}}`,
+			uuid.New(), uuid.New(), expectedRecords)
+		req := httptest.NewRequest(http.MethodPost, "/campaigns", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(`{"aCidrs":["203.0.113.0/33"]}`)
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "expectedRecords.aCidrs")
+	assert.False(t, orchestrator.created)
+
+	w = post(`{"aCidrs":["203.0.113.0/24"],"cnameSuffixes":["cdn.example.net"]}`)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.True(t, orchestrator.created)
+}
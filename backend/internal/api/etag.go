@@ -0,0 +1,66 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// versionETag is a cheap weak ETag for a resource whose every change moves its updated_at
+func versionETag(id uuid.UUID, updatedAt time.Time) string {
+	return fmt.Sprintf(`W/"%s-%d"`, id, updatedAt.UnixNano())
+}
+
+// contentETag hashes a response payload, for responses assembled from more than one row
+func contentETag(payload interface{}) (string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// etagMatches reports whether an If-None-Match header lists etag. Comparison is weak, as RFC 9110
+// requires for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	target := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == target {
+			return true
+		}
+	}
+	return false
+}
+
+// respondNotModifiedGin sets the ETag header and, when the request's If-None-Match already lists
+// it, answers 304 Not Modified without a body and returns true.
+func respondNotModifiedGin(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+	if !etagMatches(c.GetHeader("If-None-Match"), etag) {
+		return false
+	}
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// respondWithETaggedJSONGin responds like respondWithJSONGin, with an ETag hashed from payload,
+// or with 304 Not Modified when the client already has that version.
+func respondWithETaggedJSONGin(c *gin.Context, payload interface{}) {
+	etag, err := contentETag(payload)
+	if err == nil && respondNotModifiedGin(c, etag) {
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, payload)
+}
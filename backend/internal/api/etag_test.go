@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// detailsOrchestratorService serves one campaign whose status tests can change
+type detailsOrchestratorService struct {
+	services.CampaignOrchestratorService
+	campaign *models.Campaign
+}
+
+func (s *detailsOrchestratorService) GetCampaignDetails(ctx context.Context, campaignID uuid.UUID) (*models.Campaign, interface{}, error) {
+	if campaignID != s.campaign.ID {
+		return nil, nil, store.ErrNotFound
+	}
+	return s.campaign, &models.DNSValidationCampaignParams{}, nil
+}
+
+func (s *detailsOrchestratorService) GetCampaignStatus(ctx context.Context, campaignID uuid.UUID) (models.CampaignStatusEnum, *float64, error) {
+	if campaignID != s.campaign.ID {
+		return "", nil, store.ErrNotFound
+	}
+	return s.campaign.Status, s.campaign.ProgressPercentage, nil
+}
+
+func getWithETag(router *gin.Engine, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCampaignReads_HonorIfNoneMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	campaign := &models.Campaign{ID: uuid.New(), Status: models.CampaignStatusRunning, ProgressPercentage: models.Float64Ptr(40)}
+	h := NewCampaignOrchestratorAPIHandler(&detailsOrchestratorService{campaign: campaign}, &memoryCampaignListViewStore{})
+	router := gin.New()
+	router.GET("/campaigns/:campaignId", h.getCampaignDetails)
+	router.GET("/campaigns/:campaignId/status", h.getCampaignStatus)
+
+	for _, path := range []string{"/campaigns/" + campaign.ID.String(), "/campaigns/" + campaign.ID.String() + "/status"} {
+		campaign.ProgressPercentage = models.Float64Ptr(40)
+		first := getWithETag(router, path, "")
+		require.Equal(t, http.StatusOK, first.Code, path)
+		etag := first.Header().Get("ETag")
+		require.NotEmpty(t, etag, path)
+
+		unchanged := getWithETag(router, path, etag)
+		assert.Equal(t, http.StatusNotModified, unchanged.Code, path)
+		assert.Empty(t, unchanged.Body.String(), path)
+		assert.Equal(t, etag, unchanged.Header().Get("ETag"), path)
+
+		campaign.ProgressPercentage = models.Float64Ptr(55)
+		stale := getWithETag(router, path, etag)
+		assert.Equal(t, http.StatusOK, stale.Code, path)
+		assert.NotEqual(t, etag, stale.Header().Get("ETag"), path)
+		assert.Contains(t, stale.Body.String(), "55", path)
+	}
+}
+
+func TestGetPersonaByID_HonorsIfNoneMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	persona := &models.Persona{ID: uuid.New(), Name: "edge", PersonaType: models.PersonaTypeHTTP, ConfigDetails: []byte(`{}`), UpdatedAt: time.Now().UTC()}
+	h := &APIHandler{PersonaStore: &ownedPersonaStore{personas: map[uuid.UUID]*models.Persona{persona.ID: persona}}}
+	router := gin.New()
+	router.GET("/personas/:id", h.GetPersonaByIDGin)
+	path := "/personas/" + persona.ID.String()
+
+	first := getWithETag(router, path, "")
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	assert.Equal(t, http.StatusNotModified, getWithETag(router, path, etag).Code)
+	assert.Equal(t, http.StatusNotModified, getWithETag(router, path, `"other", `+etag).Code, "any listed ETag may match")
+
+	persona.UpdatedAt = persona.UpdatedAt.Add(time.Second)
+	stale := getWithETag(router, path, etag)
+	assert.Equal(t, http.StatusOK, stale.Code)
+	assert.Contains(t, stale.Body.String(), `"name":"edge"`)
+}
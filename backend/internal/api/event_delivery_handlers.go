@@ -0,0 +1,125 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// EventDeliveryAPIHandler exposes admin endpoints for inspecting and replaying webhook deliveries.
+type EventDeliveryAPIHandler struct {
+	webhookService *services.WebhookService
+}
+
+// NewEventDeliveryAPIHandler creates a new handler for webhook event deliveries.
+func NewEventDeliveryAPIHandler(webhookService *services.WebhookService) *EventDeliveryAPIHandler {
+	return &EventDeliveryAPIHandler{webhookService: webhookService}
+}
+
+// EventDeliveryResponse is a delivery together with its recorded attempts.
+type EventDeliveryResponse struct {
+	*models.EventDelivery
+	AttemptHistory []*models.EventDeliveryAttempt `json:"attemptHistory"`
+}
+
+// ListEventDeliveriesGin lists webhook event deliveries.
+// @Summary List webhook event deliveries
+// @Description List outbound webhook deliveries with optional filtering by event type, status and target URL
+// @Tags Admin
+// @Produce json
+// @Param eventType query string false "Filter by event type"
+// @Param status query string false "Filter by delivery status" Enums(pending,delivered,failed)
+// @Param targetUrl query string false "Filter by target URL"
+// @Param limit query int false "Maximum number of deliveries to return (1-100)" default(50)
+// @Param offset query int false "Number of deliveries to skip" default(0)
+// @Success 200 {array} models.EventDelivery "List of event deliveries"
+// @Failure 400 {object} models.ErrorResponse "Invalid query parameters"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /admin/events [get]
+func (h *EventDeliveryAPIHandler) ListEventDeliveriesGin(c *gin.Context) {
+	page, err := parsePagination(c, 50, DefaultMaxPageSize)
+	if err != nil {
+		respondWithPaginationErrorGin(c, err)
+		return
+	}
+
+	status := models.EventDeliveryStatusEnum(c.Query("status"))
+	switch status {
+	case "", models.EventDeliveryStatusPending, models.EventDeliveryStatusDelivered, models.EventDeliveryStatusFailed:
+	default:
+		respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation,
+			"Invalid status parameter", []ErrorDetail{
+				{
+					Field:   "status",
+					Code:    ErrorCodeValidation,
+					Message: "Status must be one of pending, delivered, failed",
+				},
+			})
+		return
+	}
+
+	filter := store.ListEventDeliveriesFilter{
+		EventType: c.Query("eventType"),
+		Status:    status,
+		TargetURL: c.Query("targetUrl"),
+		Limit:     page.Limit,
+		Offset:    page.Offset,
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(c.Request.Context(), filter)
+	if err != nil {
+		log.Printf("Error listing event deliveries: %v", err)
+		respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeDatabaseError,
+			"Failed to retrieve event deliveries", nil)
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, deliveries)
+}
+
+// ReplayEventDeliveryGin re-attempts delivery of a persisted webhook event.
+// @Summary Replay a webhook event delivery
+// @Description Re-attempt delivery of a persisted webhook event and record the new attempt
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Event delivery ID"
+// @Success 200 {object} EventDeliveryResponse "Delivery after the replay attempt"
+// @Failure 400 {object} models.ErrorResponse "Invalid delivery ID"
+// @Failure 404 {object} models.ErrorResponse "Delivery not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /admin/events/{id}/replay [post]
+func (h *EventDeliveryAPIHandler) ReplayEventDeliveryGin(c *gin.Context) {
+	deliveryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid event delivery ID format")
+		return
+	}
+
+	delivery, err := h.webhookService.Replay(c.Request.Context(), deliveryID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithErrorGin(c, http.StatusNotFound, "Event delivery not found")
+			return
+		}
+		log.Printf("Error replaying event delivery %s: %v", deliveryID, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to replay event delivery")
+		return
+	}
+
+	attempts, err := h.webhookService.ListAttempts(c.Request.Context(), deliveryID)
+	if err != nil {
+		log.Printf("Error listing attempts for event delivery %s: %v", deliveryID, err)
+		attempts = []*models.EventDeliveryAttempt{}
+	}
+	respondWithJSONGin(c, http.StatusOK, EventDeliveryResponse{
+		EventDelivery:  delivery,
+		AttemptHistory: attempts,
+	})
+}
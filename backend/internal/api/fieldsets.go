@@ -0,0 +1,183 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SparseFields is the validated list of top-level fields a client asked for with ?fields=. A nil
+// SparseFields keeps every field.
+type SparseFields []string
+
+// FieldsError reports ?fields= names the response does not have
+type FieldsError struct {
+	Unknown []string
+	Allowed []string
+}
+
+func (e *FieldsError) Error() string {
+	return fmt.Sprintf("unknown fields: %s", strings.Join(e.Unknown, ", "))
+}
+
+// jsonFieldNames returns the JSON names of a struct's fields, including those promoted from
+// embedded structs, as encoding/json would write them
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	names := make(map[string]bool)
+	if t.Kind() != reflect.Struct {
+		return names
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" {
+			for embedded := range jsonFieldNames(field.Type) {
+				names[embedded] = true
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// parseSparseFields reads the fields query parameter, as a comma-separated list or repeated, and
+// checks every name against the JSON fields of model. It returns nil when no fields were requested.
+func parseSparseFields(c *gin.Context, model interface{}) (SparseFields, error) {
+	var requested []string
+	for _, value := range c.QueryArray("fields") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				requested = append(requested, name)
+			}
+		}
+	}
+	if len(requested) == 0 {
+		return nil, nil
+	}
+
+	allowed := jsonFieldNames(reflect.TypeOf(model))
+	var unknown []string
+	for _, name := range requested {
+		if !allowed[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		fieldsErr := &FieldsError{Unknown: unknown}
+		for name := range allowed {
+			fieldsErr.Allowed = append(fieldsErr.Allowed, name)
+		}
+		sort.Strings(fieldsErr.Allowed)
+		return nil, fieldsErr
+	}
+	return SparseFields(requested), nil
+}
+
+// respondWithFieldsErrorGin writes a 400 validation error for a parseSparseFields failure
+func respondWithFieldsErrorGin(c *gin.Context, err error) {
+	var fieldsErr *FieldsError
+	if !errors.As(err, &fieldsErr) {
+		respondWithErrorGin(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation,
+		"Invalid fields parameter", []ErrorDetail{
+			{
+				Field:   "fields",
+				Code:    ErrorCodeValidation,
+				Message: "Unknown fields: " + strings.Join(fieldsErr.Unknown, ", "),
+				Context: map[string]interface{}{"allowed": fieldsErr.Allowed},
+			},
+		})
+}
+
+// apply returns payload, an object or an array of objects, with only the requested fields
+func (f SparseFields) apply(payload interface{}) (interface{}, error) {
+	if f == nil {
+		return payload, nil
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return f.selectFrom(body)
+}
+
+// applyToItems returns payload, an object holding a list under key, with only the requested fields
+// kept in each list item. Pagination fields around the list are left as they are.
+func (f SparseFields) applyToItems(payload interface{}, key string) (interface{}, error) {
+	if f == nil {
+		return payload, nil
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, err
+	}
+	if items, ok := envelope[key]; ok {
+		shaped, err := f.selectFrom(items)
+		if err != nil {
+			return nil, err
+		}
+		if envelope[key], err = json.Marshal(shaped); err != nil {
+			return nil, err
+		}
+	}
+	return envelope, nil
+}
+
+func (f SparseFields) selectFrom(body json.RawMessage) (interface{}, error) {
+	pick := func(object map[string]json.RawMessage) map[string]json.RawMessage {
+		selected := make(map[string]json.RawMessage, len(f))
+		for _, name := range f {
+			if value, ok := object[name]; ok {
+				selected[name] = value
+			}
+		}
+		return selected
+	}
+
+	trimmed := strings.TrimSpace(string(body))
+	if trimmed == "null" {
+		return nil, nil
+	}
+	if strings.HasPrefix(trimmed, "[") {
+		var objects []map[string]json.RawMessage
+		if err := json.Unmarshal(body, &objects); err != nil {
+			return nil, err
+		}
+		selected := make([]map[string]json.RawMessage, len(objects))
+		for i, object := range objects {
+			selected[i] = pick(object)
+		}
+		return selected, nil
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(body, &object); err != nil {
+		return nil, err
+	}
+	return pick(object), nil
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSparseFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		want        SparseFields
+		wantUnknown []string
+	}{
+		{name: "absent keeps everything", query: ""},
+		{name: "comma-separated", query: "fields=id,status", want: SparseFields{"id", "status"}},
+		{name: "repeated with spaces", query: "fields=id&fields=+name", want: SparseFields{"id", "name"}},
+		{name: "promoted from an embedded struct", query: "fields=params,createdAt", want: SparseFields{"params", "createdAt"}},
+		{name: "typo", query: "fields=id,stauts", wantUnknown: []string{"stauts"}},
+		{name: "Go field name", query: "fields=CampaignType", wantUnknown: []string{"CampaignType"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := parseSparseFields(newPaginationContext(tt.query), CampaignDetailsResponse{})
+			if tt.wantUnknown != nil {
+				var fieldsErr *FieldsError
+				require.ErrorAs(t, err, &fieldsErr)
+				assert.Equal(t, tt.wantUnknown, fieldsErr.Unknown)
+				assert.Contains(t, fieldsErr.Allowed, "campaignType")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, fields)
+		})
+	}
+}
+
+func TestSparseFields_ApplyToItemsKeepsPagination(t *testing.T) {
+	resp := &services.HTTPKeywordResultsResponse{
+		Data:       []models.HTTPKeywordResult{{ID: uuid.New(), DomainName: "example.com", ValidationStatus: "success"}},
+		NextCursor: "example.com",
+		TotalCount: 7,
+	}
+	shaped, err := SparseFields{"domainName"}.applyToItems(resp, "data")
+	require.NoError(t, err)
+	body, err := json.Marshal(shaped)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data":[{"domainName":"example.com"}],"nextCursor":"example.com","totalCount":7}`, string(body))
+}
+
+func TestGetCampaignDetails_ReturnsOnlyRequestedFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	campaign := &models.Campaign{ID: uuid.New(), Name: "large", Status: models.CampaignStatusRunning, ProgressPercentage: models.Float64Ptr(40)}
+	h := NewCampaignOrchestratorAPIHandler(&detailsOrchestratorService{campaign: campaign}, &memoryCampaignListViewStore{})
+	router := gin.New()
+	router.GET("/campaigns/:campaignId", h.getCampaignDetails)
+	path := "/campaigns/" + campaign.ID.String()
+
+	w := getWithETag(router, path+"?fields=id,status", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var envelope struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	assert.Len(t, envelope.Data, 2)
+	assert.JSONEq(t, `"`+campaign.ID.String()+`"`, string(envelope.Data["id"]))
+	assert.JSONEq(t, `"running"`, string(envelope.Data["status"]))
+	assert.NotEqual(t, getWithETag(router, path, "").Header().Get("ETag"), w.Header().Get("ETag"),
+		"a sparse response has its own ETag")
+
+	rejected := getWithETag(router, path+"?fields=id,progres", "")
+	assert.Equal(t, http.StatusBadRequest, rejected.Code)
+	assert.Contains(t, rejected.Body.String(), "Unknown fields: progres")
+}
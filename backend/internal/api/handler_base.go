@@ -6,6 +6,7 @@ import (
 
 	"github.com/fntelecomllc/studio/backend/internal/config"
 	"github.com/fntelecomllc/studio/backend/internal/proxymanager"
+	"github.com/fntelecomllc/studio/backend/internal/services"
 	"github.com/fntelecomllc/studio/backend/internal/store"
 	"github.com/jmoiron/sqlx" // Added for sqlx.DB
 )
@@ -24,6 +25,21 @@ type APIHandler struct {
 	KeywordStore     store.KeywordStore
 	AuditLogStore    store.AuditLogStore
 	CampaignJobStore store.CampaignJobStore
+
+	// PermissionCache is invalidated when a user's roles change; nil when caching is disabled
+	PermissionCache *services.UserPermissionCache
+	// PersonaTests holds scheduled persona test results; nil when scheduled tests are disabled
+	PersonaTests *services.PersonaTestScheduler
+	// PersonaTester runs on-demand persona tests; the test endpoint responds 503 while unset
+	PersonaTester *services.PersonaTester
+	// ProxyHealth answers on-demand proxy health checks; the check endpoint responds 503 while unset
+	ProxyHealth *services.ProxyHealthChecker
+	// PasswordPepper is applied to the passwords of created users; nil hashes them as-is
+	PasswordPepper *services.PasswordPepper
+	// PasswordPolicy is enforced on the passwords of created users; nil is services.DefaultPasswordPolicy
+	PasswordPolicy *services.PasswordPolicy
+	// SessionSettings are reported by the effective configuration endpoint; nil leaves them out
+	SessionSettings *config.SessionSettings
 }
 
 // NewAPIHandler creates a new APIHandler with core dependencies.
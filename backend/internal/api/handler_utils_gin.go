@@ -12,6 +12,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+
+	"github.com/fntelecomllc/studio/backend/internal/logging"
+	"github.com/fntelecomllc/studio/backend/internal/middleware"
 )
 
 // Global validator instance
@@ -63,19 +66,19 @@ func init() {
 
 // getRequestID gets or generates a request ID for tracing
 func getRequestID(c *gin.Context) string {
-	// Try to get from header first
-	requestID := c.GetHeader("X-Request-ID")
-	if requestID != "" {
-		return requestID
-	}
-
-	// Try to get from context
+	// Try to get from context, where the request logging middleware puts it
 	if id, exists := c.Get("request_id"); exists {
 		if strID, ok := id.(string); ok {
 			return strID
 		}
 	}
 
+	// Then from the header
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID != "" {
+		return requestID
+	}
+
 	// Generate new ID
 	return uuid.New().String()
 }
@@ -85,8 +88,7 @@ func respondWithErrorGin(c *gin.Context, code int, message string) {
 	requestID := getRequestID(c)
 	errorCode := httpStatusToErrorCode(code)
 
-	log.Printf("API Error: status=%d, code=%s, message=%s, path=%s, clientIP=%s, requestID=%s",
-		code, errorCode, message, c.Request.URL.Path, c.ClientIP(), requestID)
+	logAPIError(c, code, errorCode, message, 0)
 
 	response := NewErrorResponse(errorCode, message, requestID, c.Request.URL.Path)
 	c.JSON(code, response)
@@ -96,8 +98,7 @@ func respondWithErrorGin(c *gin.Context, code int, message string) {
 func respondWithDetailedErrorGin(c *gin.Context, code int, errorCode ErrorCode, message string, details []ErrorDetail) {
 	requestID := getRequestID(c)
 
-	log.Printf("API Error: status=%d, code=%s, message=%s, path=%s, clientIP=%s, requestID=%s, details=%d",
-		code, errorCode, message, c.Request.URL.Path, c.ClientIP(), requestID, len(details))
+	logAPIError(c, code, errorCode, message, len(details))
 
 	response := &APIResponse{
 		Success: false,
@@ -114,6 +115,22 @@ func respondWithDetailedErrorGin(c *gin.Context, code int, errorCode ErrorCode,
 	c.JSON(code, response)
 }
 
+// logAPIError logs an error response with the request's fields, at error level for server errors
+// and warning level otherwise
+func logAPIError(c *gin.Context, code int, errorCode ErrorCode, message string, details int) {
+	fields := map[string]interface{}{
+		"status":  code,
+		"code":    errorCode,
+		"message": message,
+		"details": details,
+	}
+	if code >= http.StatusInternalServerError {
+		middleware.RequestLogger(c).Error(logging.CategoryHTTP, "api_error", nil, fields)
+		return
+	}
+	middleware.RequestLogger(c).Warn(logging.CategoryHTTP, "api_error", fields)
+}
+
 // respondWithValidationErrorGin sends a validation error response
 func respondWithValidationErrorGin(c *gin.Context, errors []ErrorDetail) {
 	requestID := getRequestID(c)
@@ -8,6 +8,9 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/startup"
 )
 
 // HealthStatus represents the health status of various components
@@ -45,9 +48,23 @@ var BuildInfo = struct {
 	Env:       "development",
 }
 
+// ReadinessGate reports whether the startup sequence has completed
+type ReadinessGate interface {
+	Ready() bool
+	Steps() []startup.StepStatus
+}
+
+// WorkerHealthChecker reports the health of the campaign worker subsystem
+type WorkerHealthChecker interface {
+	Health(ctx context.Context) services.WorkerHealth
+}
+
 // HealthCheckHandler handles health check requests
 type HealthCheckHandler struct {
-	db *sql.DB
+	db                 *sql.DB
+	gate               ReadinessGate
+	workers            WorkerHealthChecker
+	workersInReadiness bool
 }
 
 // NewHealthCheckHandler creates a new health check handler
@@ -57,6 +74,18 @@ func NewHealthCheckHandler(db *sql.DB) *HealthCheckHandler {
 	}
 }
 
+// SetReadinessGate makes readiness checks fail until gate reports that startup has completed
+func (h *HealthCheckHandler) SetReadinessGate(gate ReadinessGate) {
+	h.gate = gate
+}
+
+// SetWorkerHealth exposes the campaign workers' health. With inReadiness, readiness checks also fail
+// while the workers are stopped or stalled.
+func (h *HealthCheckHandler) SetWorkerHealth(workers WorkerHealthChecker, inReadiness bool) {
+	h.workers = workers
+	h.workersInReadiness = inReadiness
+}
+
 // HandleHealthCheck handles GET /health requests
 func (h *HealthCheckHandler) HandleHealthCheck(c *gin.Context) {
 	status := HealthStatus{
@@ -87,8 +116,17 @@ func (h *HealthCheckHandler) HandleHealthCheck(c *gin.Context) {
 	respondWithJSONGin(c, http.StatusOK, status)
 }
 
-// HandleReadinessCheck handles GET /health/ready requests
+// HandleReadinessCheck handles GET /health/ready and GET /readyz requests
 func (h *HealthCheckHandler) HandleReadinessCheck(c *gin.Context) {
+	// Traffic is held back until every startup step has finished
+	if h.gate != nil && !h.gate.Ready() {
+		respondWithJSONGin(c, http.StatusServiceUnavailable, map[string]interface{}{
+			"status": "starting",
+			"steps":  h.gate.Steps(),
+		})
+		return
+	}
+
 	// Check if database is ready
 	dbStatus := h.checkDatabaseStatus()
 
@@ -97,9 +135,28 @@ func (h *HealthCheckHandler) HandleReadinessCheck(c *gin.Context) {
 		return
 	}
 
+	if h.workers != nil && h.workersInReadiness {
+		if health := h.workers.Health(c.Request.Context()); !health.Healthy {
+			respondWithJSONGin(c, http.StatusServiceUnavailable, map[string]interface{}{
+				"status":  "not ready",
+				"workers": health,
+			})
+			return
+		}
+	}
+
 	respondWithJSONGin(c, http.StatusOK, map[string]string{"status": "ready"})
 }
 
+// HandleWorkerHealth handles GET /api/v2/admin/workers/health requests
+func (h *HealthCheckHandler) HandleWorkerHealth(c *gin.Context) {
+	if h.workers == nil {
+		respondWithErrorGin(c, http.StatusNotFound, "Campaign workers are not running on this instance")
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, h.workers.Health(c.Request.Context()))
+}
+
 // HandleLivenessCheck handles GET /health/live requests
 func (h *HealthCheckHandler) HandleLivenessCheck(c *gin.Context) {
 	// For liveness, we just check if the service is running
@@ -128,5 +185,6 @@ func (h *HealthCheckHandler) checkDatabaseStatus() Status {
 func RegisterHealthCheckRoutes(router *gin.Engine, handler *HealthCheckHandler) {
 	router.GET("/health", handler.HandleHealthCheck)
 	router.GET("/health/ready", handler.HandleReadinessCheck)
+	router.GET("/readyz", handler.HandleReadinessCheck)
 	router.GET("/health/live", handler.HandleLivenessCheck)
 }
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -10,6 +11,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/startup"
 )
 
 func TestHealthCheckHandler_HandleHealthCheck(t *testing.T) {
@@ -131,6 +135,85 @@ func TestHealthCheckHandler_HandleReadinessCheck(t *testing.T) {
 	}
 }
 
+func TestHealthCheckHandler_ReadinessWaitsForStartup(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer db.Close()
+
+	release := make(chan struct{})
+	sequencer := startup.NewSequencer(startup.Step{Name: "workers", Enabled: true, Run: func(ctx context.Context) error {
+		<-release
+		return nil
+	}})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewHealthCheckHandler(db)
+	handler.SetReadinessGate(sequencer)
+	RegisterHealthCheckRoutes(router, handler)
+
+	check := func() int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return w.Code
+	}
+
+	// The database is not consulted while startup is still running
+	done := make(chan error, 1)
+	go func() { done <- sequencer.Run(context.Background()) }()
+	assert.Equal(t, http.StatusServiceUnavailable, check())
+
+	close(release)
+	require.NoError(t, <-done)
+	mock.ExpectPing()
+	assert.Equal(t, http.StatusOK, check())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+type staticWorkerHealth services.WorkerHealth
+
+func (h staticWorkerHealth) Health(ctx context.Context) services.WorkerHealth {
+	return services.WorkerHealth(h)
+}
+
+func TestHealthCheckHandler_ReadinessReportsStalledWorkers(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer db.Close()
+
+	stalled := staticWorkerHealth{Healthy: false, Status: services.WorkerHealthStalled, ActiveWorkers: 2}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewHealthCheckHandler(db)
+	handler.SetWorkerHealth(stalled, true)
+	RegisterHealthCheckRoutes(router, handler)
+	router.GET("/admin/workers/health", handler.HandleWorkerHealth)
+
+	mock.ExpectPing()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var body struct {
+		Data struct {
+			Workers services.WorkerHealth `json:"workers"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, services.WorkerHealthStalled, body.Data.Workers.Status)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/workers/health", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "the admin endpoint reports the health without failing")
+
+	// With readiness integration disabled the stall is only visible through the admin endpoint
+	handler.SetWorkerHealth(stalled, false)
+	mock.ExpectPing()
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestHealthCheckHandler_HandleLivenessCheck(t *testing.T) {
 	// Set up mock DB
 	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCampaign_ValidatesKeywordMatchType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	orchestrator := &creatingOrchestratorService{}
+	h := NewCampaignOrchestratorAPIHandler(orchestrator, &memoryCampaignListViewStore{})
+	router := gin.New()
+	router.POST("/campaigns", h.createCampaign)
+
+	post := func(matchType, keywords string) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"campaignType":"http_keyword_validation","name":"match type","httpKeywordParams":{"sourceCampaignId":"%s","personaIds":["%s"],"batchSize":10,"matchType":%q,"adHocKeywords":%s}}`,
+			uuid.New(), uuid.New(), matchType, keywords)
+		req := httptest.NewRequest(http.MethodPost, "/campaigns", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("regex", `["nginx/1\\.\\d+", "v(\\d+"]`)
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "adHocKeywords[1]")
+	assert.Contains(t, w.Body.String(), "missing closing )")
+	assert.False(t, orchestrator.created)
+
+	w = post("regex", `["nginx/1\\.\\d+"]`)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.True(t, orchestrator.created)
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// filterRecordingCampaignStore records the filter HTTP keyword results are listed with
+type filterRecordingCampaignStore struct {
+	lookupCampaignStore
+	filter store.ListValidationResultsFilter
+}
+
+func (s *filterRecordingCampaignStore) GetHTTPKeywordResultsByCampaign(ctx context.Context, exec store.Querier, campaignID uuid.UUID, filter store.ListValidationResultsFilter) ([]*models.HTTPKeywordResult, error) {
+	s.filter = filter
+	return nil, nil
+}
+
+func TestGetHTTPKeywordResults_CertExpiresWithinDays(t *testing.T) {
+	campaign := &models.Campaign{ID: uuid.New(), CampaignType: models.CampaignTypeHTTPKeywordValidation}
+	cs := &filterRecordingCampaignStore{lookupCampaignStore: lookupCampaignStore{
+		campaigns: map[uuid.UUID]*models.Campaign{campaign.ID: campaign},
+	}}
+	h := NewCampaignOrchestratorAPIHandler(
+		services.NewCampaignOrchestratorService(nil, cs, nil, nil, nil, nil, nil, nil, nil),
+		&memoryCampaignListViewStore{})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/campaigns/:campaignId/results/http-keyword", h.getHTTPKeywordResults)
+	serve := func(query string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/campaigns/"+campaign.ID.String()+"/results/http-keyword"+query, nil))
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusOK, serve(""))
+	assert.True(t, cs.filter.CertificateExpiresBefore.IsZero())
+
+	require.Equal(t, http.StatusOK, serve("?certExpiresWithinDays=30"))
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 30), cs.filter.CertificateExpiresBefore, time.Minute)
+
+	assert.Equal(t, http.StatusBadRequest, serve("?certExpiresWithinDays=-1"))
+	assert.Equal(t, http.StatusBadRequest, serve("?certExpiresWithinDays=soon"))
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// strictJSONDecoding makes bindJSON reject request bodies that contain fields
+// the target struct does not declare. Off by default for backward compatibility.
+var strictJSONDecoding atomic.Bool
+
+// SetStrictJSONDecoding enables or disables rejection of unknown JSON fields
+// on create and update requests.
+func SetStrictJSONDecoding(enabled bool) {
+	strictJSONDecoding.Store(enabled)
+}
+
+// UnknownFieldError is returned by bindJSON in strict mode when the request body
+// contains a field the target struct does not declare.
+type UnknownFieldError struct {
+	Field string
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("unknown field %q", e.Field)
+}
+
+// bindJSON binds the request body into obj like c.ShouldBindJSON, additionally
+// rejecting unknown fields and anything after the JSON value when strict JSON
+// decoding is enabled.
+func bindJSON(c *gin.Context, obj interface{}) error {
+	if !strictJSONDecoding.Load() {
+		return c.ShouldBindJSON(obj)
+	}
+	if c.Request == nil || c.Request.Body == nil {
+		return errors.New("invalid request")
+	}
+
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		if err == io.EOF {
+			return errors.New("request body is empty")
+		}
+		if field, ok := unknownJSONField(err); ok {
+			return &UnknownFieldError{Field: field}
+		}
+		return err
+	}
+	if err := decoder.Decode(&json.RawMessage{}); err != io.EOF {
+		return errors.New("request body must contain a single JSON value")
+	}
+	if binding.Validator == nil {
+		return nil
+	}
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// bindErrorField returns the offending field name for a bindJSON error, or "body"
+// when the error is not tied to a single field.
+func bindErrorField(err error) string {
+	var unknownErr *UnknownFieldError
+	if errors.As(err, &unknownErr) {
+		return unknownErr.Field
+	}
+	return "body"
+}
+
+// unknownJSONField extracts the field name from encoding/json's unknown field error,
+// which has no dedicated error type.
+func unknownJSONField(err error) (string, bool) {
+	const prefix = "json: unknown field "
+	msg := err.Error()
+	if !strings.HasPrefix(msg, prefix) {
+		return "", false
+	}
+	field, unquoteErr := strconv.Unquote(strings.TrimPrefix(msg, prefix))
+	if unquoteErr != nil {
+		return "", false
+	}
+	return field, true
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// typoCampaignBody misspells domainGenerationParams.numDomainsToGenerate as numDomains
+const typoCampaignBody = `{
+	"campaignType": "domain_generation",
+	"name": "typo campaign",
+	"domainGenerationParams": {
+		"patternType": "prefix",
+		"variableLength": 3,
+		"characterSet": "abc",
+		"constantString": "shop",
+		"tld": ".com",
+		"numDomains": 1000
+	}
+}`
+
+func withStrictJSONDecoding(t *testing.T, enabled bool) {
+	t.Helper()
+	previous := strictJSONDecoding.Load()
+	SetStrictJSONDecoding(enabled)
+	t.Cleanup(func() { SetStrictJSONDecoding(previous) })
+}
+
+func postJSON(handler gin.HandlerFunc, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/", handler)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateCampaign_StrictJSONRejectsUnknownField(t *testing.T) {
+	withStrictJSONDecoding(t, true)
+	h := NewCampaignOrchestratorAPIHandler(nil, nil)
+
+	w := postJSON(h.createCampaign, typoCampaignBody)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Error)
+	require.Len(t, resp.Error.Details, 1)
+	assert.Equal(t, "numDomains", resp.Error.Details[0].Field)
+	assert.Contains(t, resp.Error.Details[0].Message, `unknown field "numDomains"`)
+}
+
+func TestCreateKeywordSet_StrictJSONRejectsUnknownField(t *testing.T) {
+	withStrictJSONDecoding(t, true)
+	h := &APIHandler{}
+
+	w := postJSON(h.CreateKeywordSetGin, `{"name":"brands","enabled":true}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Error)
+	assert.Contains(t, resp.Error.Message, `unknown field "enabled"`)
+}
+
+func TestBindJSON_LenientModeIgnoresUnknownFields(t *testing.T) {
+	withStrictJSONDecoding(t, false)
+
+	var req CreateKeywordSetRequest
+	var bindErr error
+	w := postJSON(func(c *gin.Context) {
+		bindErr = bindJSON(c, &req)
+		c.Status(http.StatusNoContent)
+	}, `{"name":"brands","enabled":true}`)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.NoError(t, bindErr)
+	assert.Equal(t, "brands", req.Name)
+}
+
+func TestBindJSON_StrictModeAcceptsKnownFields(t *testing.T) {
+	withStrictJSONDecoding(t, true)
+
+	var req CreateKeywordSetRequest
+	var bindErr error
+	postJSON(func(c *gin.Context) {
+		bindErr = bindJSON(c, &req)
+		c.Status(http.StatusNoContent)
+	}, `{"name":"brands","isEnabled":true}`)
+	require.NoError(t, bindErr)
+	assert.Equal(t, "brands", req.Name)
+	require.NotNil(t, req.IsEnabled)
+	assert.True(t, *req.IsEnabled)
+}
+
+func TestBindJSON_StrictModeRejectsTrailingData(t *testing.T) {
+	withStrictJSONDecoding(t, true)
+
+	for _, body := range []string{
+		`{"name":"brands"}{"name":"other"}`,
+		`{"name":"brands"} trailing`,
+	} {
+		var req CreateKeywordSetRequest
+		var bindErr error
+		postJSON(func(c *gin.Context) {
+			bindErr = bindJSON(c, &req)
+			c.Status(http.StatusNoContent)
+		}, body)
+		assert.Error(t, bindErr, body)
+	}
+}
+
+func TestCreateUser_StrictJSONRejectsUnknownField(t *testing.T) {
+	withStrictJSONDecoding(t, true)
+	h := &AuthHandler{}
+
+	w := postJSON(h.CreateUser, `{"email":"a@example.com","password":"Str0ng!Passw0rd","isAdmin":true}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Error)
+	assert.Contains(t, resp.Error.Message, `unknown field "isAdmin"`)
+}
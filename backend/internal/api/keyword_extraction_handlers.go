@@ -21,7 +21,7 @@ import (
 
 func (h *APIHandler) BatchExtractKeywordsGin(c *gin.Context) {
 	var req BatchKeywordExtractionRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindJSON(c, &req); err != nil {
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request body: "+err.Error())
 		return
 	}
@@ -0,0 +1,89 @@
+// File: backend/internal/api/keyword_rule_handlers.go
+package api
+
+import (
+	"net/http"
+
+	"github.com/fntelecomllc/studio/backend/internal/keywordscanner"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Limits on a keyword rule preview
+const (
+	MaxKeywordRuleSampleBytes = 64 * 1024
+	MaxKeywordRuleTestMatches = 100
+)
+
+// KeywordRuleTestRequest is the body of POST /keyword-rules/test
+type KeywordRuleTestRequest struct {
+	Rule       KeywordRuleRequest          `json:"rule"`
+	MatchType  models.KeywordMatchTypeEnum `json:"matchType,omitempty" validate:"omitempty,oneof=substring regex word_boundary"` // Match type of the rule's keyword set
+	SampleText string                      `json:"sampleText"`
+}
+
+// KeywordRuleTestResponse reports whether a rule compiles and where it matches the sample text
+type KeywordRuleTestResponse struct {
+	Valid        bool                          `json:"valid"`
+	CompileError string                        `json:"compileError,omitempty"`
+	Matched      bool                          `json:"matched"`
+	MatchCount   int                           `json:"matchCount"`
+	Matches      []keywordscanner.KeywordMatch `json:"matches"`
+	Truncated    bool                          `json:"truncated"` // More than MaxKeywordRuleTestMatches matches were found
+}
+
+// TestKeywordRuleGin previews a keyword rule against sample text before it is saved to a keyword set.
+// @Summary Test a keyword rule
+// @Description Compile a string or regex keyword rule, with its keyword set's match type and the limits used by campaigns, and return its matches in the sample text, with byte offsets. A rule that does not compile returns 200 with valid=false and the compile error.
+// @Tags Keyword Sets
+// @Accept json
+// @Produce json
+// @Param request body KeywordRuleTestRequest true "Rule and sample text"
+// @Success 200 {object} KeywordRuleTestResponse "Compile result and matches"
+// @Failure 400 {object} models.ErrorResponse "Invalid request"
+// @Security SessionAuth
+// @Router /keyword-rules/test [post]
+func (h *APIHandler) TestKeywordRuleGin(c *gin.Context) {
+	var req KeywordRuleTestRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Validation failed: "+err.Error())
+		return
+	}
+	if len(req.SampleText) > MaxKeywordRuleSampleBytes {
+		respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation,
+			"Sample text too large", []ErrorDetail{
+				{
+					Field:   "sampleText",
+					Code:    ErrorCodeValidation,
+					Message: "sampleText must be at most 65536 bytes",
+				},
+			})
+		return
+	}
+
+	rule, err := keywordscanner.CompileKeywordRuleWithMatchType(models.KeywordRule{
+		Pattern:         req.Rule.Pattern,
+		RuleType:        req.Rule.RuleType,
+		IsCaseSensitive: req.Rule.IsCaseSensitive,
+	}, req.MatchType)
+	if err != nil {
+		respondWithJSONGin(c, http.StatusOK, KeywordRuleTestResponse{
+			CompileError: keywordscanner.CompileErrorMessage(err),
+			Matches:      []keywordscanner.KeywordMatch{},
+		})
+		return
+	}
+
+	matches := keywordscanner.FindMatches(rule, []byte(req.SampleText), MaxKeywordRuleTestMatches+1)
+	resp := KeywordRuleTestResponse{Valid: true, Matches: matches}
+	if len(matches) > MaxKeywordRuleTestMatches {
+		resp.Matches, resp.Truncated = matches[:MaxKeywordRuleTestMatches], true
+	}
+	resp.MatchCount = len(resp.Matches)
+	resp.Matched = resp.MatchCount > 0
+	respondWithJSONGin(c, http.StatusOK, resp)
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/keywordscanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKeywordRule(t *testing.T, body string) (int, KeywordRuleTestResponse) {
+	t.Helper()
+	h := &APIHandler{}
+	w := postJSON(h.TestKeywordRuleGin, body)
+
+	var resp struct {
+		Data KeywordRuleTestResponse `json:"data"`
+	}
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	}
+	return w.Code, resp.Data
+}
+
+func TestTestKeywordRule_RegexMatchesWithPositions(t *testing.T) {
+	code, resp := testKeywordRule(t, `{
+		"rule": {"pattern": "order-[0-9]+", "ruleType": "regex"},
+		"sampleText": "see order-12 and order-345"
+	}`)
+	require.Equal(t, http.StatusOK, code)
+
+	assert.True(t, resp.Valid)
+	assert.Empty(t, resp.CompileError)
+	assert.True(t, resp.Matched)
+	assert.Equal(t, 2, resp.MatchCount)
+	assert.Equal(t, []keywordscanner.KeywordMatch{
+		{Text: "order-12", Start: 4, End: 12},
+		{Text: "order-345", Start: 17, End: 26},
+	}, resp.Matches)
+	assert.False(t, resp.Truncated)
+}
+
+func TestTestKeywordRule_StringRuleIgnoresCaseUnlessSensitive(t *testing.T) {
+	_, resp := testKeywordRule(t, `{
+		"rule": {"pattern": "casino", "ruleType": "string"},
+		"sampleText": "Online CASINO reviews"
+	}`)
+	assert.Equal(t, []keywordscanner.KeywordMatch{{Text: "CASINO", Start: 7, End: 13}}, resp.Matches)
+
+	_, resp = testKeywordRule(t, `{
+		"rule": {"pattern": "casino", "ruleType": "string", "isCaseSensitive": true},
+		"sampleText": "Online CASINO reviews"
+	}`)
+	assert.True(t, resp.Valid)
+	assert.False(t, resp.Matched)
+}
+
+func TestTestKeywordRule_AppliesKeywordSetMatchType(t *testing.T) {
+	_, resp := testKeywordRule(t, `{
+		"rule": {"pattern": "php", "ruleType": "string"},
+		"matchType": "word_boundary",
+		"sampleText": "phpMyAdmin runs on PHP 8"
+	}`)
+	assert.Equal(t, []keywordscanner.KeywordMatch{{Text: "PHP", Start: 19, End: 22}}, resp.Matches)
+
+	_, resp = testKeywordRule(t, `{
+		"rule": {"pattern": "php/[0-9.]+", "ruleType": "string"},
+		"matchType": "regex",
+		"sampleText": "X-Powered-By: PHP/8.2.1"
+	}`)
+	assert.Equal(t, []keywordscanner.KeywordMatch{{Text: "PHP/8.2.1", Start: 14, End: 23}}, resp.Matches)
+
+	code, _ := testKeywordRule(t, `{"rule": {"pattern": "php", "ruleType": "string"}, "matchType": "glob", "sampleText": "php"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestTestKeywordRule_NoMatch(t *testing.T) {
+	code, resp := testKeywordRule(t, `{
+		"rule": {"pattern": "\\bpoker\\b", "ruleType": "regex"},
+		"sampleText": "nothing to see here"
+	}`)
+	require.Equal(t, http.StatusOK, code)
+
+	assert.True(t, resp.Valid)
+	assert.False(t, resp.Matched)
+	assert.Zero(t, resp.MatchCount)
+	assert.NotNil(t, resp.Matches)
+	assert.Empty(t, resp.Matches)
+}
+
+func TestTestKeywordRule_InvalidRegexReportsCompileError(t *testing.T) {
+	code, resp := testKeywordRule(t, `{
+		"rule": {"pattern": "order-([0-9]+", "ruleType": "regex"},
+		"sampleText": "order-12"
+	}`)
+	require.Equal(t, http.StatusOK, code)
+
+	assert.False(t, resp.Valid)
+	assert.Equal(t, "missing closing ): `order-([0-9]+`", resp.CompileError)
+	assert.False(t, resp.Matched)
+	assert.Empty(t, resp.Matches)
+}
+
+func TestTestKeywordRule_PatternOverCampaignLimit(t *testing.T) {
+	pattern := strings.Repeat("a", keywordscanner.MaxRegexPatternLength+1)
+	_, resp := testKeywordRule(t, `{"rule": {"pattern": "`+pattern+`", "ruleType": "regex"}, "sampleText": "aaa"}`)
+
+	assert.False(t, resp.Valid)
+	assert.Equal(t, keywordscanner.ErrRegexPatternTooLong.Error(), resp.CompileError)
+}
+
+func TestTestKeywordRule_TruncatesMatches(t *testing.T) {
+	sample := strings.Repeat("x ", MaxKeywordRuleTestMatches+5)
+	_, resp := testKeywordRule(t, `{"rule": {"pattern": "x", "ruleType": "string"}, "sampleText": "`+sample+`"}`)
+
+	assert.True(t, resp.Truncated)
+	assert.Equal(t, MaxKeywordRuleTestMatches, resp.MatchCount)
+	assert.Len(t, resp.Matches, MaxKeywordRuleTestMatches)
+}
+
+func TestTestKeywordRule_RejectsBadRequests(t *testing.T) {
+	code, _ := testKeywordRule(t, `{"rule": {"pattern": "x", "ruleType": "glob"}, "sampleText": "x"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = testKeywordRule(t, `{"rule": {"ruleType": "regex"}, "sampleText": "x"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	big := strings.Repeat("x", MaxKeywordRuleSampleBytes+1)
+	code, _ = testKeywordRule(t, `{"rule": {"pattern": "x", "ruleType": "string"}, "sampleText": "`+big+`"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -3,13 +3,13 @@ package api
 
 import (
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/fntelecomllc/studio/backend/internal/keywordscanner"
 	"github.com/fntelecomllc/studio/backend/internal/models"
 	"github.com/fntelecomllc/studio/backend/internal/store"
 
@@ -29,37 +29,45 @@ type KeywordRuleRequest struct {
 }
 
 type CreateKeywordSetRequest struct {
-	Name        string               `json:"name" validate:"required,min=1,max=255"`
-	Description string               `json:"description,omitempty"`
-	IsEnabled   *bool                `json:"isEnabled,omitempty"`
-	Rules       []KeywordRuleRequest `json:"rules,omitempty" validate:"omitempty,dive"`
+	Name        string                      `json:"name" validate:"required,min=1,max=255"`
+	Description string                      `json:"description,omitempty"`
+	IsEnabled   *bool                       `json:"isEnabled,omitempty"`
+	MatchType   models.KeywordMatchTypeEnum `json:"matchType,omitempty" validate:"omitempty,oneof=substring regex word_boundary"`
+	Rules       []KeywordRuleRequest        `json:"rules,omitempty" validate:"omitempty,dive"`
 }
 
 type UpdateKeywordSetRequest struct {
-	Name        *string              `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
-	Description *string              `json:"description,omitempty"`
-	IsEnabled   *bool                `json:"isEnabled,omitempty"`
-	Rules       []KeywordRuleRequest `json:"rules,omitempty" validate:"omitempty,dive"`
+	Name        *string                      `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Description *string                      `json:"description,omitempty"`
+	IsEnabled   *bool                        `json:"isEnabled,omitempty"`
+	MatchType   *models.KeywordMatchTypeEnum `json:"matchType,omitempty" validate:"omitempty,oneof=substring regex word_boundary"`
+	Rules       []KeywordRuleRequest         `json:"rules,omitempty" validate:"omitempty,dive"`
 }
 
 // KeywordSetResponse formats a KeywordSet with its rules for API responses
 type KeywordSetResponse struct {
-	ID          uuid.UUID            `json:"id"`
-	Name        string               `json:"name"`
-	Description string               `json:"description,omitempty"`
-	IsEnabled   bool                 `json:"isEnabled"`
-	CreatedAt   time.Time            `json:"createdAt"`
-	UpdatedAt   time.Time            `json:"updatedAt"`
-	Rules       []models.KeywordRule `json:"rules,omitempty"`
-	RuleCount   int                  `json:"ruleCount"`
+	ID          uuid.UUID                   `json:"id"`
+	Name        string                      `json:"name"`
+	Description string                      `json:"description,omitempty"`
+	IsEnabled   bool                        `json:"isEnabled"`
+	MatchType   models.KeywordMatchTypeEnum `json:"matchType"`
+	CreatedAt   time.Time                   `json:"createdAt"`
+	UpdatedAt   time.Time                   `json:"updatedAt"`
+	Rules       []models.KeywordRule        `json:"rules,omitempty"`
+	RuleCount   int                         `json:"ruleCount"`
 }
 
 func toKeywordSetResponse(ks *models.KeywordSet, rules []models.KeywordRule) KeywordSetResponse {
+	matchType := ks.MatchType
+	if matchType == "" {
+		matchType = models.KeywordMatchTypeSubstring
+	}
 	return KeywordSetResponse{
 		ID:          ks.ID,
 		Name:        ks.Name,
 		Description: ks.Description.String,
 		IsEnabled:   ks.IsEnabled,
+		MatchType:   matchType,
 		CreatedAt:   ks.CreatedAt,
 		UpdatedAt:   ks.UpdatedAt,
 		Rules:       rules,
@@ -67,11 +75,32 @@ func toKeywordSetResponse(ks *models.KeywordSet, rules []models.KeywordRule) Key
 	}
 }
 
+// keywordSetRuleErrors reports each string rule that does not compile with the set's match type
+// and each regex rule that does not compile, so a set is never saved with rules campaigns skip
+func keywordSetRuleErrors(matchType models.KeywordMatchTypeEnum, rules []KeywordRuleRequest) []ErrorDetail {
+	var details []ErrorDetail
+	for i, rule := range rules {
+		_, err := keywordscanner.CompileKeywordRuleWithMatchType(models.KeywordRule{
+			Pattern:         rule.Pattern,
+			RuleType:        rule.RuleType,
+			IsCaseSensitive: rule.IsCaseSensitive,
+		}, matchType)
+		if err != nil {
+			details = append(details, ErrorDetail{
+				Field:   fmt.Sprintf("rules[%d].pattern", i),
+				Code:    ErrorCodeValidation,
+				Message: fmt.Sprintf("%q does not compile: %s", rule.Pattern, keywordscanner.CompileErrorMessage(err)),
+			})
+		}
+	}
+	return details
+}
+
 // --- Gin Handlers for KeywordSets ---
 
 func (h *APIHandler) CreateKeywordSetGin(c *gin.Context) {
 	var req CreateKeywordSetRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindJSON(c, &req); err != nil {
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
 		return
 	}
@@ -79,6 +108,14 @@ func (h *APIHandler) CreateKeywordSetGin(c *gin.Context) {
 		respondWithErrorGin(c, http.StatusBadRequest, "Validation failed: "+err.Error())
 		return
 	}
+	matchType := req.MatchType
+	if matchType == "" {
+		matchType = models.KeywordMatchTypeSubstring
+	}
+	if details := keywordSetRuleErrors(matchType, req.Rules); len(details) > 0 {
+		respondWithValidationErrorGin(c, details)
+		return
+	}
 
 	now := time.Now().UTC()
 	setID := uuid.New()
@@ -92,6 +129,7 @@ func (h *APIHandler) CreateKeywordSetGin(c *gin.Context) {
 		Name:        req.Name,
 		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
 		IsEnabled:   isEnabled,
+		MatchType:   matchType,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
@@ -194,12 +232,13 @@ func (h *APIHandler) CreateKeywordSetGin(c *gin.Context) {
 	// Audit log: Pass nil as querier, AuditLogStore must handle it.
 	// Only create audit log if AuditLogStore is not nil
 	if h.AuditLogStore != nil {
+		details, _ := models.NewAuditDetails(map[string]interface{}{"name": keywordSet.Name, "rule_count": len(createdRulesModels)})
 		auditLog := &models.AuditLog{
 			UserID:       uuid.NullUUID{},
 			Action:     "Create KeywordSet",
 			EntityType: sql.NullString{String: "KeywordSet", Valid: true},
 			EntityID:   uuid.NullUUID{UUID: setID, Valid: true},
-			Details:    models.JSONRawMessagePtr(json.RawMessage(fmt.Sprintf(`{"name":"%s", "rule_count":%d}`, keywordSet.Name, len(createdRulesModels)))),
+			Details:    details,
 		}
 		if auditErr := h.AuditLogStore.CreateAuditLog(c.Request.Context(), nil, auditLog); auditErr != nil {
 			log.Printf("Error creating audit log for new keyword set %s: %v", setID, auditErr)
@@ -218,8 +257,12 @@ func (h *APIHandler) CreateKeywordSetGin(c *gin.Context) {
 }
 
 func (h *APIHandler) ListKeywordSetsGin(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	page, err := parsePagination(c, DefaultPageLimit, DefaultMaxPageSize)
+	if err != nil {
+		respondWithPaginationErrorGin(c, err)
+		return
+	}
+	limit, offset := page.Limit, page.Offset
 	includeRulesQuery := c.DefaultQuery("includeRules", "false")
 	includeRules, _ := strconv.ParseBool(includeRulesQuery)
 
@@ -300,7 +343,7 @@ func (h *APIHandler) UpdateKeywordSetGin(c *gin.Context) {
 	}
 
 	var req UpdateKeywordSetRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindJSON(c, &req); err != nil {
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
 		return
 	}
@@ -368,6 +411,30 @@ func (h *APIHandler) UpdateKeywordSetGin(c *gin.Context) {
 		existingSet.IsEnabled = *req.IsEnabled
 		updated = true
 	}
+	if req.MatchType != nil || req.Rules != nil {
+		if req.MatchType != nil {
+			existingSet.MatchType = *req.MatchType
+			updated = true
+		}
+		rulesToCheck := req.Rules
+		if rulesToCheck == nil {
+			existingRules, errRules := h.KeywordStore.GetKeywordRulesBySetID(c.Request.Context(), querier, setID)
+			if errRules != nil {
+				opErr = errRules
+				log.Printf("Error fetching rules of keyword set %s to check the match type: %v", setID, errRules)
+				respondWithErrorGin(c, http.StatusInternalServerError, "Error fetching keyword rules for update")
+				return
+			}
+			for _, rule := range existingRules {
+				rulesToCheck = append(rulesToCheck, KeywordRuleRequest{Pattern: rule.Pattern, RuleType: rule.RuleType, IsCaseSensitive: rule.IsCaseSensitive})
+			}
+		}
+		if details := keywordSetRuleErrors(existingSet.MatchType, rulesToCheck); len(details) > 0 {
+			opErr = fmt.Errorf("keyword set %s has rules that do not compile", setID)
+			respondWithValidationErrorGin(c, details)
+			return
+		}
+	}
 
 	var updatedRulesModels []models.KeywordRule
 	if req.Rules != nil {
@@ -0,0 +1,315 @@
+package api
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lockedTestHash = "$2a$10$abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0"
+
+func newLockoutAuthHandler(t *testing.T) (*AuthHandler, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return NewAuthHandler(nil, &config.SessionSettings{}, sqlx.NewDb(db, "postgres")), mock
+}
+
+// expectLockedUserLookup expects a login at a locked account: the lookup, the attempt counted
+// without extending the lockout and the failed login event
+func expectLockedUserLookup(mock sqlmock.Sqlmock, lockedUntil time.Time) {
+	columns := []string{"id", "email", "email_verified", "password_hash", "password_pepper_version",
+		"first_name", "last_name", "avatar_url", "is_active", "is_locked",
+		"failed_login_attempts", "locked_until", "last_login_at", "last_login_ip",
+		"password_changed_at", "must_change_password", "created_at", "updated_at"}
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM auth.users")).
+		WithArgs("locked@example.com").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(uuid.New(), "locked@example.com", true, lockedTestHash, 1,
+			"Locked", "User", nil, true, true, 5, lockedUntil, nil, nil, now, false, now, now))
+	mock.ExpectExec(`UPDATE auth.users\s+SET failed_login_attempts = failed_login_attempts \+ 1,\s+updated_at = NOW\(\)\s+WHERE id = \$1`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectFailedLoginEvent(mock, "account locked")
+}
+
+// failedLoginDetails matches the JSON details of a failed login event with the given reason
+type failedLoginDetails struct {
+	reason string
+}
+
+func (m failedLoginDetails) Match(v driver.Value) bool {
+	encoded, ok := v.(string)
+	if !ok {
+		return false
+	}
+	var details map[string]string
+	if err := json.Unmarshal([]byte(encoded), &details); err != nil {
+		return false
+	}
+	_, err := time.Parse(time.RFC3339, details["timestamp"])
+	return details["reason"] == m.reason && err == nil
+}
+
+func expectFailedLoginEvent(mock sqlmock.Sqlmock, reason string) {
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO auth.auth_audit_log")).
+		WithArgs(sqlmock.AnyArg(), "login", "failure", sqlmock.AnyArg(), failedLoginDetails{reason: reason}, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func expectPasswordCheck(mock sqlmock.Sqlmock, valid bool) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT crypt($1, $2) = $2")).
+		WillReturnRows(sqlmock.NewRows([]string{"password_valid"}).AddRow(valid))
+}
+
+// attemptLogin runs authenticateUser and writes its failure the way Login does
+func attemptLogin(h *AuthHandler, email, password string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v2/auth/login", nil)
+	if _, err := h.authenticateUser(email, password, "203.0.113.7"); err != nil {
+		h.respondWithLoginError(c, err)
+	}
+	return w
+}
+
+func decodeLoginError(t *testing.T, w *httptest.ResponseRecorder) *ErrorInfo {
+	t.Helper()
+	var resp APIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Error)
+	return resp.Error
+}
+
+func TestLogin_LockedAccountIsAnsweredTheSameForAnyPassword(t *testing.T) {
+	h, mock := newLockoutAuthHandler(t)
+	lockedUntil := time.Now().Add(20 * time.Minute)
+
+	// No password check is expected: sqlmock fails on the crypt query if one is made
+	var responses []*httptest.ResponseRecorder
+	for _, password := range []string{"correct horse", "guess"} {
+		expectLockedUserLookup(mock, lockedUntil)
+		responses = append(responses, attemptLogin(h, "locked@example.com", password))
+	}
+	require.NoError(t, mock.ExpectationsWereMet(), "each attempt is counted")
+
+	right, wrong := responses[0], responses[1]
+	assert.Equal(t, http.StatusUnauthorized, right.Code)
+	assert.Equal(t, wrong.Code, right.Code)
+	assert.Empty(t, right.Header().Get("Retry-After"))
+	rightErr, wrongErr := decodeLoginError(t, right), decodeLoginError(t, wrong)
+	assert.Equal(t, wrongErr.Code, rightErr.Code)
+	assert.Equal(t, "Invalid email or password", rightErr.Message)
+	assert.Equal(t, wrongErr.Message, rightErr.Message)
+	assert.Empty(t, rightErr.Details)
+}
+
+func TestLogin_LockoutDoesNotRevealWhichEmailsExist(t *testing.T) {
+	h, mock := newLockoutAuthHandler(t)
+
+	// Locked account
+	expectLockedUserLookup(mock, time.Now().Add(20*time.Minute))
+	locked := attemptLogin(h, "locked@example.com", "guess")
+
+	// Unknown email
+	mock.ExpectQuery(regexp.QuoteMeta("FROM auth.users")).
+		WithArgs("nobody@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	expectFailedLoginEvent(mock, "user not found")
+	unknown := attemptLogin(h, "nobody@example.com", "guess")
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	for _, w := range []*httptest.ResponseRecorder{locked, unknown} {
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Empty(t, w.Header().Get("Retry-After"))
+		errInfo := decodeLoginError(t, w)
+		assert.Empty(t, errInfo.Details)
+		assert.Equal(t, "Invalid email or password", errInfo.Message)
+	}
+	assert.Equal(t, decodeLoginError(t, unknown).Code, decodeLoginError(t, locked).Code)
+}
+
+// capturedString matches any string argument and keeps it
+type capturedString struct {
+	value *string
+}
+
+func (m capturedString) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	*m.value = s
+	return ok
+}
+
+func TestLogin_FailedLoginDetailsAreValidJSONForAnyEmail(t *testing.T) {
+	h, mock := newLockoutAuthHandler(t)
+	email := `mallory"}, "admin": true, "x": "@example.com`
+	mock.ExpectQuery(regexp.QuoteMeta("FROM auth.users")).
+		WithArgs(email).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	var stored string
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO auth.auth_audit_log")).
+		WithArgs(sqlmock.AnyArg(), "login", "failure", "203.0.113.7", capturedString{value: &stored}, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	attemptLogin(h, email, "guess")
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.True(t, json.Valid([]byte(stored)), "details %s", stored)
+	var details map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(stored), &details))
+	assert.Equal(t, email, details["email"])
+	assert.Equal(t, "user not found", details["reason"])
+	assert.NotContains(t, details, "admin")
+}
+
+// countingRateLimitStore keeps rate limits in memory and counts attempts like the Postgres store
+type countingRateLimitStore struct {
+	limits map[string]*models.RateLimit
+}
+
+func (s *countingRateLimitStore) GetRateLimit(ctx context.Context, exec store.Querier, identifier, action string) (*models.RateLimit, error) {
+	limit, ok := s.limits[action+"/"+identifier]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	copied := *limit
+	return &copied, nil
+}
+
+func (s *countingRateLimitStore) RecordRateLimitAttempt(ctx context.Context, exec store.Querier, identifier, action string, now, windowStart time.Time, maxAttempts int, blockedUntil time.Time) (*models.RateLimit, error) {
+	if s.limits == nil {
+		s.limits = make(map[string]*models.RateLimit)
+	}
+	limit, ok := s.limits[action+"/"+identifier]
+	if !ok {
+		limit = &models.RateLimit{Identifier: identifier, Action: action, WindowStart: now}
+		s.limits[action+"/"+identifier] = limit
+	}
+	if limit.WindowStart.Before(windowStart) {
+		limit.Attempts, limit.WindowStart = 0, now
+	}
+	limit.Attempts++
+	if limit.Attempts >= maxAttempts {
+		limit.BlockedUntil = &blockedUntil
+	}
+	copied := *limit
+	return &copied, nil
+}
+
+func (s *countingRateLimitStore) DeleteRateLimit(ctx context.Context, exec store.Querier, identifier, action string) error {
+	delete(s.limits, action+"/"+identifier)
+	return nil
+}
+
+// simulatedAccount follows the lockout columns of an auth.users row through failed logins
+type simulatedAccount struct {
+	id          uuid.UUID
+	failed      int
+	lockedUntil *time.Time
+}
+
+// expectWrongPassword expects a failed login at the account as authenticateUser makes it: refused
+// unchecked during a lockout, otherwise a wrong password that may start one
+func (a *simulatedAccount) expectWrongPassword(mock sqlmock.Sqlmock, email string) {
+	columns := []string{"id", "email", "email_verified", "password_hash", "password_pepper_version",
+		"first_name", "last_name", "avatar_url", "is_active", "is_locked",
+		"failed_login_attempts", "locked_until", "last_login_at", "last_login_ip",
+		"password_changed_at", "must_change_password", "created_at", "updated_at"}
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM auth.users")).WithArgs(email).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(a.id, email, true, lockedTestHash, 1, "Known", "User", nil, true,
+			a.lockedUntil != nil, a.failed, a.lockedUntil, nil, nil, now, false, now, now))
+	a.failed++
+	if a.lockedUntil != nil && now.Before(*a.lockedUntil) {
+		mock.ExpectExec(regexp.QuoteMeta("SET failed_login_attempts = failed_login_attempts + 1,\n\t\t    updated_at")).
+			WithArgs(a.id).WillReturnResult(sqlmock.NewResult(0, 1))
+		expectFailedLoginEvent(mock, "account locked")
+		return
+	}
+	expectPasswordCheck(mock, false)
+	mock.ExpectExec(regexp.QuoteMeta("SET failed_login_attempts = failed_login_attempts + 1,\n\t\t    is_locked")).
+		WithArgs(a.id, services.AccountLockoutThreshold).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectFailedLoginEvent(mock, "invalid password")
+	if a.failed >= services.AccountLockoutThreshold {
+		lockedUntil := now.Add(services.AccountLockoutDuration)
+		a.lockedUntil = &lockedUntil
+	}
+}
+
+func TestLogin_KnownAndUnknownEmailsAreAnsweredAlikeAtEveryAttempt(t *testing.T) {
+	h, mock := newLockoutAuthHandler(t)
+	h.config.LockoutDetails = true
+	h.SetUnknownAccountLockouts(services.NewUnknownAccountLockouts(&countingRateLimitStore{}))
+	account := &simulatedAccount{id: uuid.New()}
+
+	for attempt := 1; attempt <= services.AccountLockoutThreshold+3; attempt++ {
+		account.expectWrongPassword(mock, "known@example.com")
+		known := attemptLogin(h, "known@example.com", "guess")
+		mock.ExpectQuery(regexp.QuoteMeta("FROM auth.users")).WithArgs("unknown@example.com").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		if attempt > services.AccountLockoutThreshold {
+			expectFailedLoginEvent(mock, "account locked")
+		} else {
+			expectFailedLoginEvent(mock, "user not found")
+		}
+		unknown := attemptLogin(h, "unknown@example.com", "guess")
+		require.NoError(t, mock.ExpectationsWereMet(), "attempt %d", attempt)
+
+		// The lockout starts after the threshold is reached, for known and unknown emails alike
+		wantStatus := http.StatusUnauthorized
+		if attempt > services.AccountLockoutThreshold {
+			wantStatus = http.StatusTooManyRequests
+		}
+		require.Equal(t, wantStatus, known.Code, "attempt %d", attempt)
+		require.Equal(t, known.Code, unknown.Code, "attempt %d", attempt)
+		knownErr, unknownErr := decodeLoginError(t, known), decodeLoginError(t, unknown)
+		assert.Equal(t, knownErr.Code, unknownErr.Code, "attempt %d", attempt)
+		assert.Equal(t, knownErr.Message, unknownErr.Message, "attempt %d", attempt)
+		require.Len(t, unknownErr.Details, len(knownErr.Details), "attempt %d", attempt)
+		if wantStatus != http.StatusTooManyRequests {
+			assert.Empty(t, known.Header().Get("Retry-After"))
+			assert.Empty(t, unknown.Header().Get("Retry-After"))
+			continue
+		}
+		knownRetry, err := strconv.Atoi(known.Header().Get("Retry-After"))
+		require.NoError(t, err)
+		unknownRetry, err := strconv.Atoi(unknown.Header().Get("Retry-After"))
+		require.NoError(t, err)
+		assert.InDelta(t, services.AccountLockoutDuration.Seconds(), knownRetry, 5)
+		assert.InDelta(t, knownRetry, unknownRetry, 1, "attempt %d", attempt)
+		knownContext := knownErr.Details[0].Context.(map[string]interface{})
+		unknownContext := unknownErr.Details[0].Context.(map[string]interface{})
+		assert.Equal(t, services.RateLimitScopeAccount, knownContext["scope"])
+		assert.Equal(t, knownContext["scope"], unknownContext["scope"])
+		assert.Equal(t, account.lockedUntil.UTC().Format(time.RFC3339), knownContext["blockedUntil"])
+	}
+}
+
+func TestLogin_LockoutDetailsAreOffWithoutTheToggle(t *testing.T) {
+	h, mock := newLockoutAuthHandler(t)
+	h.SetUnknownAccountLockouts(services.NewUnknownAccountLockouts(&countingRateLimitStore{}))
+	expectLockedUserLookup(mock, time.Now().Add(20*time.Minute))
+
+	w := attemptLogin(h, "locked@example.com", "guess")
+	require.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Empty(t, w.Header().Get("Retry-After"))
+	assert.Empty(t, decodeLoginError(t, w).Details)
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/logging"
+	"github.com/fntelecomllc/studio/backend/internal/middleware"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureOutput runs fn at the DEBUG level and returns everything written meanwhile to stdout, the
+// standard logger and the structured logger
+func captureOutput(t *testing.T, fn func()) string {
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	copied := make(chan struct{})
+	go func() {
+		io.Copy(&buf, reader)
+		close(copied)
+	}()
+
+	stdout, stdFlags, level := os.Stdout, log.Flags(), logging.GlobalAuthLogger.Level()
+	os.Stdout = writer
+	log.SetOutput(writer)
+	logging.GlobalAuthLogger.SetOutput(writer)
+	logging.SetLevel(logging.LogLevelDebug)
+	defer func() {
+		os.Stdout = stdout
+		log.SetOutput(os.Stderr)
+		log.SetFlags(stdFlags)
+		logging.GlobalAuthLogger.SetOutput(os.Stdout)
+		logging.SetLevel(level)
+	}()
+
+	fn()
+	writer.Close()
+	<-copied
+	return buf.String()
+}
+
+func TestLogin_NeverLogsTheSessionID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	sessionService, err := services.NewSessionService(sqlxDB, services.DefaultSessionConfig(), nil)
+	require.NoError(t, err)
+	h := NewAuthHandler(sessionService, &config.SessionSettings{CookieName: "session_id", CookiePath: "/"}, sqlxDB)
+
+	userID := uuid.New()
+	columns := []string{"id", "email", "email_verified", "password_hash", "password_pepper_version",
+		"first_name", "last_name", "avatar_url", "is_active", "is_locked",
+		"failed_login_attempts", "locked_until", "last_login_at", "last_login_ip",
+		"password_changed_at", "must_change_password", "created_at", "updated_at"}
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM auth.users")).WithArgs("user@example.com").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(userID, "user@example.com", true, lockedTestHash, 1,
+			"Some", "User", nil, true, false, 0, nil, nil, nil, now, false, now, now))
+	expectPasswordCheck(mock, true)
+	mock.ExpectExec(regexp.QuoteMeta("SET failed_login_attempts = 0")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("FROM auth.roles").WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("user"))
+	mock.ExpectQuery("FROM auth.permissions").WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("campaigns:read"))
+	mock.ExpectExec("INSERT INTO auth.sessions").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT session_fingerprint").WillReturnRows(sqlmock.NewRows([]string{
+		"session_fingerprint", "browser_fingerprint", "screen_resolution"}).AddRow(nil, nil, nil))
+	mock.ExpectExec(regexp.QuoteMeta("SET last_login_at = NOW()")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO auth.auth_audit_log")).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestLogging())
+	router.POST("/api/v2/auth/login", h.Login)
+	w := httptest.NewRecorder()
+	output := captureOutput(t, func() {
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/auth/login",
+			strings.NewReader(`{"email":"user@example.com","password":"correct horse"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	var sessionID string
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "session_id" {
+			sessionID = cookie.Value
+		}
+	}
+	require.NotEmpty(t, sessionID)
+	assert.Contains(t, output, "login_session_cookie_set", "the login is logged at DEBUG")
+	assert.NotContains(t, output, sessionID)
+	assert.Contains(t, output, logging.RedactSessionID(sessionID), "the session is identified by its redacted form")
+}
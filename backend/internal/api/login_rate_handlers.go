@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultLoginRateTopIPs = 10
+	maxLoginRateTopIPs     = 100
+)
+
+// GetLoginRates reports the rolling login success, failure and lockout rates
+// @Summary Get login rates
+// @Description Report login successes, failures and lockouts over the rolling window, overall and for the client IPs with the most failures. With ip set, report that IP only.
+// @Tags Admin
+// @Security SessionAuth
+// @Produce json
+// @Param top query int false "IPs to list, by failures (default 10, at most 100)"
+// @Param ip query string false "Client IP to report"
+// @Success 200 {object} services.LoginRateSnapshot "Login rates"
+// @Failure 400 {object} ErrorResponse "Invalid top"
+// @Failure 404 {object} ErrorResponse "Login rates are not tracked"
+// @Router /admin/login-rates [get]
+func (h *AuthHandler) GetLoginRates(c *gin.Context) {
+	if h.loginRates == nil {
+		respondWithErrorGin(c, http.StatusNotFound, "Login rates are not tracked")
+		return
+	}
+	if ip := c.Query("ip"); ip != "" {
+		respondWithJSONGin(c, http.StatusOK, services.IPLoginRates{IPAddress: ip, LoginRates: h.loginRates.IPRates(ip)})
+		return
+	}
+	top := defaultLoginRateTopIPs
+	if value := c.Query("top"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 || parsed > maxLoginRateTopIPs {
+			respondWithErrorGin(c, http.StatusBadRequest, "top must be between 0 and "+strconv.Itoa(maxLoginRateTopIPs))
+			return
+		}
+		top = parsed
+	}
+	respondWithJSONGin(c, http.StatusOK, h.loginRates.Snapshot(top))
+}
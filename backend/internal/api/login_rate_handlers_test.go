@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getLoginRates(t *testing.T, h *AuthHandler, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/login-rates", h.GetLoginRates)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/login-rates"+query, nil))
+	return w
+}
+
+func TestGetLoginRates(t *testing.T) {
+	h := NewAuthHandler(nil, &config.SessionSettings{}, nil)
+	assert.Equal(t, http.StatusNotFound, getLoginRates(t, h, "").Code)
+
+	h.SetLoginRateTracker(services.NewLoginRateTracker(config.LoginRateConfig{WindowSeconds: 300, MaxTrackedIPs: 100}))
+	h.recordLoginOutcome("198.51.100.9", errors.New("invalid password"))
+	h.recordLoginOutcome("198.51.100.9", errors.New("user not found"))
+	h.recordLoginOutcome("198.51.100.9", &accountLockedError{})
+	h.recordLoginOutcome("203.0.113.7", nil)
+	h.recordLoginOutcome("203.0.113.7", errors.New("database error"))
+
+	w := getLoginRates(t, h, "?top=1")
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data services.LoginRateSnapshot `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.EqualValues(t, 1, resp.Data.Global.Successes)
+	assert.EqualValues(t, 2, resp.Data.Global.Failures, "server errors are not counted")
+	assert.EqualValues(t, 1, resp.Data.Global.Lockouts)
+	assert.Equal(t, 2, resp.Data.TrackedIPs)
+	require.Len(t, resp.Data.TopIPs, 1)
+	assert.Equal(t, "198.51.100.9", resp.Data.TopIPs[0].IPAddress)
+
+	w = getLoginRates(t, h, "?ip=203.0.113.7")
+	require.Equal(t, http.StatusOK, w.Code)
+	var ipResp struct {
+		Data services.IPLoginRates `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &ipResp))
+	assert.EqualValues(t, 1, ipResp.Data.Successes)
+	assert.Zero(t, ipResp.Data.FailureRatio)
+
+	assert.Equal(t, http.StatusBadRequest, getLoginRates(t, h, "?top=1000").Code)
+	assert.Equal(t, http.StatusBadRequest, getLoginRates(t, h, "?top=many").Code)
+}
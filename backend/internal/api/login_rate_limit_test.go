@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingRateLimitStore blocks the identifiers in blocked and records the failures counted
+type recordingRateLimitStore struct {
+	blocked  map[string]time.Time
+	failures []string
+}
+
+func (s *recordingRateLimitStore) GetRateLimit(ctx context.Context, exec store.Querier, identifier, action string) (*models.RateLimit, error) {
+	until, ok := s.blocked[identifier]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return &models.RateLimit{Identifier: identifier, Action: action, BlockedUntil: &until}, nil
+}
+
+func (s *recordingRateLimitStore) RecordRateLimitAttempt(ctx context.Context, exec store.Querier, identifier, action string, now, windowStart time.Time, maxAttempts int, blockedUntil time.Time) (*models.RateLimit, error) {
+	s.failures = append(s.failures, identifier)
+	return &models.RateLimit{Identifier: identifier, Action: action, Attempts: 1, WindowStart: now}, nil
+}
+
+func (s *recordingRateLimitStore) DeleteRateLimit(ctx context.Context, exec store.Querier, identifier, action string) error {
+	return nil
+}
+
+func TestLogin_BlockedAccountIsRefusedBeforeThePasswordIsChecked(t *testing.T) {
+	h, mock := newLockoutAuthHandler(t)
+	userID := uuid.New()
+	blockedUntil := time.Now().Add(10 * time.Minute)
+	rateLimits := &recordingRateLimitStore{blocked: map[string]time.Time{services.AccountRateLimitKey(userID): blockedUntil}}
+	h.SetLoginRateLimiter(services.NewLoginRateLimiter(rateLimits, config.LoginRateLimitConfig{}))
+
+	columns := []string{"id", "email", "email_verified", "password_hash", "password_pepper_version",
+		"first_name", "last_name", "avatar_url", "is_active", "is_locked",
+		"failed_login_attempts", "locked_until", "last_login_at", "last_login_ip",
+		"password_changed_at", "must_change_password", "created_at", "updated_at"}
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM auth.users")).WithArgs("target@example.com").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(userID, "target@example.com", true, lockedTestHash, 1,
+			"Target", "User", nil, true, false, 0, nil, nil, nil, now, false, now, now))
+
+	w := attemptLogin(h, "target@example.com", "guess from a fresh address")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet(), "the password is not checked for a blocked account")
+	assert.Empty(t, rateLimits.failures, "a refused attempt does not extend the block")
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	errInfo := decodeLoginError(t, w)
+	assert.Equal(t, ErrorCodeRateLimitExceeded, errInfo.Code)
+	require.Len(t, errInfo.Details, 1)
+	assert.Equal(t, services.RateLimitScopeAccount, errInfo.Details[0].Context.(map[string]interface{})["scope"])
+}
+
+func TestLogin_FailuresCountAgainstTheIPAndTheAccount(t *testing.T) {
+	h, mock := newLockoutAuthHandler(t)
+	rateLimits := &recordingRateLimitStore{}
+	h.SetLoginRateLimiter(services.NewLoginRateLimiter(rateLimits, config.LoginRateLimitConfig{}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM auth.users")).WithArgs("Nobody@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	expectFailedLoginEvent(mock, "user not found")
+
+	w := attemptLogin(h, "Nobody@example.com", "guess")
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []string{"203.0.113.7", "email:nobody@example.com"}, rateLimits.failures,
+		"unknown emails are limited like accounts")
+}
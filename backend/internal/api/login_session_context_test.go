@@ -0,0 +1,81 @@
+package api
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	chromeWindowsUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36"
+	edgeWindowsUA   = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36 Edg/129.0.2792.79"
+	safariIPhoneUA  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.6 Mobile/15E148 Safari/604.1"
+	safariIPadUA    = "Mozilla/5.0 (iPad; CPU OS 17_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.6 Mobile/15E148 Safari/604.1"
+	firefoxMacUA    = "Mozilla/5.0 (Macintosh; Intel Mac OS X 14.6; rv:131.0) Gecko/20100101 Firefox/131.0"
+	chromeAndroidUA = "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.6668.100 Mobile Safari/537.36"
+	firefoxLinuxUA  = "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:131.0) Gecko/20100101 Firefox/131.0"
+)
+
+func TestParseUserAgent_KnownAgents(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      userAgentInfo
+	}{
+		{chromeWindowsUA, userAgentInfo{Browser: "chrome", OS: "windows", DeviceType: "desktop"}},
+		{edgeWindowsUA, userAgentInfo{Browser: "edge", OS: "windows", DeviceType: "desktop"}},
+		{safariIPhoneUA, userAgentInfo{Browser: "safari", OS: "ios", DeviceType: "mobile"}},
+		{safariIPadUA, userAgentInfo{Browser: "safari", OS: "ios", DeviceType: "tablet"}},
+		{firefoxMacUA, userAgentInfo{Browser: "firefox", OS: "macos", DeviceType: "desktop"}},
+		{chromeAndroidUA, userAgentInfo{Browser: "chrome", OS: "android", DeviceType: "mobile"}},
+		{firefoxLinuxUA, userAgentInfo{Browser: "firefox", OS: "linux", DeviceType: "desktop"}},
+		{"curl/8.5.0", userAgentInfo{Browser: "unknown", OS: "unknown", DeviceType: "unknown"}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, parseUserAgent(tt.userAgent), tt.userAgent)
+	}
+}
+
+func TestLoginSessionContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	h := NewAuthHandler(nil, &config.SessionSettings{IdleTimeout: 30 * time.Minute, LoginSessionContext: true}, sqlx.NewDb(db, "postgres"))
+
+	expiresAt := time.Now().Add(2 * time.Hour)
+	session := &services.SessionData{ID: "new-session", UserID: uuid.New(), IPAddress: "203.0.113.7",
+		UserAgent: safariIPhoneUA, ExpiresAt: expiresAt, DeviceKey: "MCowBQYDK2VwAyEA"}
+	earlierSessions := regexp.QuoteMeta("SELECT user_agent FROM auth.sessions")
+
+	// The user has only signed in from a Windows desktop before
+	mock.ExpectQuery(earlierSessions).WithArgs(session.UserID, session.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"user_agent"}).AddRow(chromeWindowsUA))
+	ctx := h.loginSessionContext(session)
+	assert.Equal(t, "203.0.113.7", ctx.IPAddress)
+	assert.Equal(t, "safari", ctx.Browser)
+	assert.Equal(t, "ios", ctx.OS)
+	assert.Equal(t, "mobile", ctx.DeviceType)
+	assert.Equal(t, expiresAt.UTC(), ctx.ExpiresAt)
+	assert.Equal(t, 1800, ctx.IdleTimeoutSeconds)
+	assert.True(t, ctx.DeviceBound)
+	assert.False(t, ctx.MFAUsed)
+	assert.True(t, ctx.NewDevice)
+
+	// A newer Chrome on the same Windows desktop is not a new device
+	session.UserAgent, session.DeviceKey = chromeWindowsUA, ""
+	mock.ExpectQuery(earlierSessions).WithArgs(session.UserID, session.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"user_agent"}).AddRow(safariIPhoneUA).AddRow(
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"))
+	ctx = h.loginSessionContext(session)
+	assert.Equal(t, "desktop", ctx.DeviceType)
+	assert.False(t, ctx.DeviceBound)
+	assert.False(t, ctx.NewDevice)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
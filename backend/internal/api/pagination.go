@@ -0,0 +1,112 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Default page sizes for list and result endpoints
+const (
+	DefaultPageLimit   = 20
+	DefaultMaxPageSize = 100
+)
+
+// maxPageSize is the server-wide ceiling applied on top of each endpoint's own maximum
+var maxPageSize atomic.Int64
+
+func init() {
+	maxPageSize.Store(DefaultMaxPageSize)
+}
+
+// SetMaxPageSize sets the server-wide ceiling on list page sizes. Non-positive values restore the default.
+func SetMaxPageSize(size int) {
+	if size <= 0 {
+		size = DefaultMaxPageSize
+	}
+	maxPageSize.Store(int64(size))
+}
+
+// Pagination holds validated limit/offset query parameters
+type Pagination struct {
+	Limit  int
+	Offset int
+}
+
+// PaginationError describes an invalid pagination query parameter
+type PaginationError struct {
+	Field   string
+	Message string
+}
+
+func (e *PaginationError) Error() string {
+	return fmt.Sprintf("invalid %s parameter: %s", e.Field, e.Message)
+}
+
+// parsePagination reads the limit and offset query parameters, falling back to defaultLimit
+// and rejecting limits above maxLimit (or the server-wide ceiling, whichever is lower).
+// A page parameter is accepted instead of offset for page-numbered endpoints.
+func parsePagination(c *gin.Context, defaultLimit, maxLimit int) (Pagination, error) {
+	if ceiling := int(maxPageSize.Load()); maxLimit <= 0 || maxLimit > ceiling {
+		maxLimit = ceiling
+	}
+	if defaultLimit <= 0 {
+		defaultLimit = DefaultPageLimit
+	}
+	if defaultLimit > maxLimit {
+		defaultLimit = maxLimit
+	}
+
+	p := Pagination{Limit: defaultLimit}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxLimit {
+			return Pagination{}, &PaginationError{Field: "limit", Message: fmt.Sprintf("Limit must be between 1 and %d", maxLimit)}
+		}
+		p.Limit = limit
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return Pagination{}, &PaginationError{Field: "offset", Message: "Offset must be non-negative"}
+		}
+		if offset > math.MaxInt-p.Limit {
+			return Pagination{}, &PaginationError{Field: "offset", Message: "Offset is too large"}
+		}
+		p.Offset = offset
+	} else if pageStr := c.Query("page"); pageStr != "" {
+		page, err := strconv.Atoi(pageStr)
+		if err != nil || page < 1 {
+			return Pagination{}, &PaginationError{Field: "page", Message: "Page must be a positive integer"}
+		}
+		// Keep offset+limit within an int so the page's end cannot wrap around
+		if page-1 > (math.MaxInt-p.Limit)/p.Limit {
+			return Pagination{}, &PaginationError{Field: "page", Message: "Page is too large"}
+		}
+		p.Offset = (page - 1) * p.Limit
+	}
+	return p, nil
+}
+
+// respondWithPaginationErrorGin writes a 400 validation error for a parsePagination failure
+func respondWithPaginationErrorGin(c *gin.Context, err error) {
+	var pageErr *PaginationError
+	if !errors.As(err, &pageErr) {
+		respondWithErrorGin(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation,
+		"Invalid "+pageErr.Field+" parameter", []ErrorDetail{
+			{
+				Field:   pageErr.Field,
+				Code:    ErrorCodeValidation,
+				Message: pageErr.Message,
+			},
+		})
+}
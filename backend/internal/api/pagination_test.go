@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPaginationContext(rawQuery string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/?"+rawQuery, nil)
+	return c
+}
+
+func TestParsePagination(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantLimit  int
+		wantOffset int
+		wantField  string
+	}{
+		{name: "defaults", query: "", wantLimit: 20},
+		{name: "explicit values", query: "limit=50&offset=10", wantLimit: 50, wantOffset: 10},
+		{name: "page converts to offset", query: "limit=25&page=3", wantLimit: 25, wantOffset: 50},
+		{name: "offset wins over page", query: "limit=25&page=3&offset=5", wantLimit: 25, wantOffset: 5},
+		{name: "limit at max", query: "limit=100", wantLimit: 100},
+		{name: "limit over max", query: "limit=101", wantField: "limit"},
+		{name: "unbounded limit", query: "limit=1000000", wantField: "limit"},
+		{name: "zero limit", query: "limit=0", wantField: "limit"},
+		{name: "non-numeric limit", query: "limit=all", wantField: "limit"},
+		{name: "negative offset", query: "offset=-1", wantField: "offset"},
+		{name: "zero page", query: "page=0", wantField: "page"},
+		{name: "page overflowing offset", query: "limit=100&page=" + strconv.Itoa(math.MaxInt/100+1), wantField: "page"},
+		{name: "largest page", query: "limit=100&page=" + strconv.Itoa(math.MaxInt/100), wantLimit: 100, wantOffset: (math.MaxInt/100 - 1) * 100},
+		{name: "offset overflowing end", query: "limit=100&offset=" + strconv.Itoa(math.MaxInt-99), wantField: "offset"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := parsePagination(newPaginationContext(tt.query), DefaultPageLimit, DefaultMaxPageSize)
+			if tt.wantField != "" {
+				var pageErr *PaginationError
+				require.ErrorAs(t, err, &pageErr)
+				assert.Equal(t, tt.wantField, pageErr.Field)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLimit, p.Limit)
+			assert.Equal(t, tt.wantOffset, p.Offset)
+		})
+	}
+}
+
+func TestParsePagination_ServerWideCeiling(t *testing.T) {
+	SetMaxPageSize(25)
+	t.Cleanup(func() { SetMaxPageSize(DefaultMaxPageSize) })
+
+	p, err := parsePagination(newPaginationContext(""), 50, DefaultMaxPageSize)
+	require.NoError(t, err)
+	assert.Equal(t, 25, p.Limit, "default limit is clamped to the ceiling")
+
+	_, err = parsePagination(newPaginationContext("limit=26"), DefaultPageLimit, DefaultMaxPageSize)
+	var pageErr *PaginationError
+	require.ErrorAs(t, err, &pageErr)
+	assert.Contains(t, pageErr.Message, "between 1 and 25")
+}
+
+// Every list and result endpoint rejects an over-max limit the same way before doing any work
+func TestListEndpoints_RejectOverMaxLimit(t *testing.T) {
+	campaignHandler := NewCampaignOrchestratorAPIHandler(nil, nil)
+	apiHandler := &APIHandler{}
+	campaignID := uuid.NewString()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/campaigns/:campaignId/results/generated-domains", campaignHandler.getGeneratedDomains)
+	router.GET("/campaigns/:campaignId/results/dns-validation", campaignHandler.getDNSValidationResults)
+	router.GET("/campaigns/:campaignId/results/http-keyword", campaignHandler.getHTTPKeywordResults)
+	router.GET("/campaigns/:campaignId/jobs", campaignHandler.getCampaignJobs)
+	router.GET("/campaigns", campaignHandler.listCampaigns)
+	router.GET("/proxies", apiHandler.ListProxiesGin)
+	router.GET("/personas", apiHandler.ListAllPersonasGin)
+	router.GET("/keywords/sets", apiHandler.ListKeywordSetsGin)
+	router.GET("/admin/events", NewEventDeliveryAPIHandler(nil).ListEventDeliveriesGin)
+
+	paths := []string{
+		"/campaigns/" + campaignID + "/results/generated-domains",
+		"/campaigns/" + campaignID + "/results/dns-validation",
+		"/campaigns/" + campaignID + "/results/http-keyword",
+		"/campaigns/" + campaignID + "/jobs",
+		"/campaigns",
+		"/proxies",
+		"/personas",
+		"/keywords/sets",
+		"/admin/events",
+	}
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+"?limit=5000", nil))
+			require.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp APIResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.NotNil(t, resp.Error)
+			require.Len(t, resp.Error.Details, 1)
+			assert.Equal(t, "limit", resp.Error.Details[0].Field)
+			assert.Equal(t, ErrorCodeValidation, resp.Error.Code)
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// expectPasswordHistory returns the given hashes, newest first, as the user's previous passwords
+func expectPasswordHistory(mock sqlmock.Sqlmock, userID uuid.UUID, hashes ...string) {
+	rows := sqlmock.NewRows([]string{"password_hash", "password_pepper_version"})
+	for _, hash := range hashes {
+		rows.AddRow(hash, config.UnpepperedPasswordPepperVersion)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta("FROM auth.password_history")).
+		WithArgs(userID, config.DefaultPasswordHistorySize).WillReturnRows(rows)
+}
+
+// expectPasswordStored expects the new hash to be stored and oldHash moved into the history
+func expectPasswordStored(mock sqlmock.Sqlmock, userID uuid.UUID, oldHash string) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE auth.users")).
+		WithArgs(userID, sqlmock.AnyArg(), config.UnpepperedPasswordPepperVersion).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO auth.password_history")).
+		WithArgs(userID, oldHash, config.UnpepperedPasswordPepperVersion).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM auth.password_history")).
+		WithArgs(userID, config.DefaultPasswordHistorySize).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+}
+
+func TestChangePassword_InvalidatesEverySession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	sessionService, err := services.NewSessionService(sqlxDB, services.DefaultSessionConfig(), nil)
+	require.NoError(t, err)
+	h := NewAuthHandler(sessionService, &config.SessionSettings{CookieName: "session_id"}, sqlxDB)
+
+	// Log in twice, from a laptop and a phone
+	userID := uuid.New()
+	mock.ExpectQuery("FROM auth.roles").WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("user"))
+	mock.ExpectQuery("FROM auth.permissions").WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("campaigns:read"))
+	var sessions []*services.SessionData
+	for _, userAgent := range []string{firefoxLinuxUA, safariIPhoneUA} {
+		mock.ExpectExec("INSERT INTO auth.sessions").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT session_fingerprint").WillReturnRows(sqlmock.NewRows([]string{
+			"session_fingerprint", "browser_fingerprint", "screen_resolution"}).AddRow(nil, nil, nil))
+		session, err := sessionService.CreateSession(userID, "203.0.113.7", userAgent)
+		require.NoError(t, err)
+		sessions = append(sessions, session)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT password_hash, COALESCE(password_pepper_version, 1)")).WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"password_hash", "password_pepper_version"}).AddRow(lockedTestHash, 1))
+	expectPasswordCheck(mock, true)
+	expectPasswordHistory(mock, userID)
+	expectPasswordStored(mock, userID, lockedTestHash)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE auth.sessions SET is_active = false WHERE user_id = $1")).
+		WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("security_context", &models.SecurityContext{UserID: userID, SessionID: sessions[0].ID})
+		c.Next()
+	})
+	router.POST("/change-password", h.ChangePassword)
+	req := httptest.NewRequest(http.MethodPost, "/change-password",
+		strings.NewReader(`{"currentPassword":"old password 123","newPassword":"new password 456"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Set-Cookie"), "session_id=;")
+
+	// Neither session is in memory any more, and the database has both inactive
+	columns := []string{"id", "user_id", "ip_address", "user_agent", "session_fingerprint", "browser_fingerprint",
+		"screen_resolution", "is_active", "expires_at", "last_activity_at", "created_at", "device_public_key"}
+	for _, session := range sessions {
+		mock.ExpectQuery("FROM auth.sessions").WithArgs(session.ID).WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			session.ID, userID, session.IPAddress, session.UserAgent, nil, nil, nil, false, session.ExpiresAt, time.Now(), session.CreatedAt, nil))
+		_, err := sessionService.ValidateSession(session.ID, session.IPAddress)
+		assert.ErrorIs(t, err, services.ErrSessionExpired, "session %s survived the password change", session.ID)
+	}
+
+	// Invalidating again, with no active sessions left, changes nothing
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE auth.sessions SET is_active = false WHERE user_id = $1")).
+		WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.NoError(t, sessionService.InvalidateAllUserSessions(userID))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChangePassword_RejectsWrongCurrentPassword(t *testing.T) {
+	h, mock := newLockoutAuthHandler(t)
+	userID := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT password_hash, COALESCE(password_pepper_version, 1)")).WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"password_hash", "password_pepper_version"}).AddRow(lockedTestHash, 1))
+	expectPasswordCheck(mock, false)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("security_context", &models.SecurityContext{UserID: userID})
+	c.Request = httptest.NewRequest(http.MethodPost, "/change-password",
+		strings.NewReader(`{"currentPassword":"wrong password 1","newPassword":"new password 456"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	h.ChangePassword(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet(), "nothing is written for a wrong current password")
+}
+
+func TestChangePassword_RejectsThePreviousPassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	sessionService, err := services.NewSessionService(sqlxDB, services.DefaultSessionConfig(), nil)
+	require.NoError(t, err)
+	h := NewAuthHandler(sessionService, &config.SessionSettings{CookieName: "session_id"}, sqlxDB)
+
+	userID := uuid.New()
+	firstHash, err := bcrypt.GenerateFromPassword([]byte("first password 123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	changePassword := func(current, next string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("security_context", &models.SecurityContext{UserID: userID})
+		c.Request = httptest.NewRequest(http.MethodPost, "/change-password",
+			strings.NewReader(`{"currentPassword":"`+current+`","newPassword":"`+next+`"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		h.ChangePassword(c)
+		return w
+	}
+
+	// The first change moves the first password's hash into the history
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT password_hash, COALESCE(password_pepper_version, 1)")).WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"password_hash", "password_pepper_version"}).AddRow(string(firstHash), 1))
+	expectPasswordCheck(mock, true)
+	expectPasswordHistory(mock, userID)
+	expectPasswordStored(mock, userID, string(firstHash))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE auth.sessions SET is_active = false")).WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	w := changePassword("first password 123", "second password 456")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// Going back to it is refused, and nothing is written
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT password_hash, COALESCE(password_pepper_version, 1)")).WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"password_hash", "password_pepper_version"}).AddRow(lockedTestHash, 1))
+	expectPasswordCheck(mock, true)
+	expectPasswordHistory(mock, userID, string(firstHash))
+	w = changePassword("second password 456", "first password 123")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "last 5 passwords")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChangePassword_EnforcesThePasswordPolicy(t *testing.T) {
+	h, mock := newLockoutAuthHandler(t)
+	policy, err := services.NewPasswordPolicy(config.PasswordPolicyConfig{RequireDigit: true, Denylist: []string{"correct horse battery"}})
+	require.NoError(t, err)
+	h.SetPasswordPolicy(policy)
+	userID := uuid.New()
+
+	for _, newPassword := range []string{"too short 1", "no digits in here", "Correct Horse Battery"} {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT password_hash, COALESCE(password_pepper_version, 1)")).WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows([]string{"password_hash", "password_pepper_version"}).AddRow(lockedTestHash, 1))
+		expectPasswordCheck(mock, true)
+
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("security_context", &models.SecurityContext{UserID: userID})
+		c.Request = httptest.NewRequest(http.MethodPost, "/change-password",
+			strings.NewReader(`{"currentPassword":"old password 123","newPassword":"`+newPassword+`"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		h.ChangePassword(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, newPassword)
+		assert.Contains(t, w.Body.String(), `"field":"newPassword"`, newPassword)
+	}
+	require.NoError(t, mock.ExpectationsWereMet(), "nothing is written for a password the policy refuses")
+}
+
+func TestGetPasswordPolicy(t *testing.T) {
+	h := NewAuthHandler(nil, &config.SessionSettings{}, nil)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/auth/password-policy", h.GetPasswordPolicy)
+	rules := func(w *httptest.ResponseRecorder) string {
+		var body struct {
+			Data json.RawMessage `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return string(body.Data)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/password-policy", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"minLength":12,"requireUppercase":false,"requireLowercase":false,"requireDigit":false,
+		"requireSymbol":false,"denylist":false}`, rules(w))
+
+	policy, err := services.NewPasswordPolicy(config.PasswordPolicyConfig{MinLength: 10, MaxLength: 64, RequireSymbol: true,
+		Denylist: []string{"password123"}})
+	require.NoError(t, err)
+	h.SetPasswordPolicy(policy)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/password-policy", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"minLength":10,"maxLength":64,"requireUppercase":false,"requireLowercase":false,"requireDigit":false,
+		"requireSymbol":true,"denylist":true}`, rules(w))
+	assert.NotContains(t, w.Body.String(), "password123", "the denylist is not published")
+}
+
+func TestIsRecentPassword_ComparesLongPasswordsWhole(t *testing.T) {
+	h, mock := newLockoutAuthHandler(t)
+	pepper, err := services.NewPasswordPepper(config.PasswordHashConfig{
+		Pepper: strings.Repeat("c", services.MinPasswordPepperLength), ExpectedPepperVersion: 3,
+		LegacyPeppers: map[int]string{2: "domainflow_legacy_pepper"}})
+	require.NoError(t, err)
+	h.SetPasswordPepper(pepper)
+
+	// Passwords past bcrypt's 72 bytes that differ only at the end
+	previous := strings.Repeat("long passphrase ", 6) + "one"
+	other := strings.Repeat("long passphrase ", 6) + "two"
+	userID := uuid.New()
+	for _, version := range []int{2, 3} {
+		peppered, ok := pepper.ApplyVersion(previous, version)
+		require.True(t, ok)
+		hash, err := bcrypt.GenerateFromPassword([]byte(peppered), bcrypt.MinCost)
+		require.NoError(t, err)
+
+		for _, candidate := range []string{previous, other} {
+			mock.ExpectQuery(regexp.QuoteMeta("FROM auth.password_history")).
+				WithArgs(userID, config.DefaultPasswordHistorySize).
+				WillReturnRows(sqlmock.NewRows([]string{"password_hash", "password_pepper_version"}).AddRow(string(hash), version))
+			reused, err := h.isRecentPassword(userID, candidate)
+			require.NoError(t, err)
+			assert.Equal(t, candidate == previous, reused, "pepper version %d", version)
+		}
+	}
+	require.NoError(t, mock.ExpectationsWereMet())
+}
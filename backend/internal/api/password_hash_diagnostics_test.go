@@ -0,0 +1,75 @@
+package api
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expectUserLookup returns an active user with the given stored hash
+func expectUserLookup(mock sqlmock.Sqlmock, userID uuid.UUID, passwordHash string) {
+	columns := []string{"id", "email", "email_verified", "password_hash", "password_pepper_version",
+		"first_name", "last_name", "avatar_url", "is_active", "is_locked",
+		"failed_login_attempts", "locked_until", "last_login_at", "last_login_ip",
+		"password_changed_at", "must_change_password", "created_at", "updated_at"}
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM auth.users")).
+		WithArgs("legacy@example.com").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(userID, "legacy@example.com", true, passwordHash, 1,
+			"Legacy", "User", nil, true, false, 0, nil, nil, nil, now, false, now, now))
+}
+
+func newDiagnosingAuthHandler(t *testing.T) (*AuthHandler, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	h := NewAuthHandler(nil, &config.SessionSettings{}, sqlx.NewDb(db, "postgres"))
+	h.SetPasswordHashDiagnostics(config.PasswordHashConfig{})
+	return h, mock
+}
+
+func TestAuthenticateUser_FlagsPgcryptoHashForMigration(t *testing.T) {
+	h, mock := newDiagnosingAuthHandler(t)
+	userID := uuid.New()
+	expectUserLookup(mock, userID, "$1$saltsalt$qjXMvbEw8oaL.CzflDugX/")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT crypt($1, $2) = $2")).
+		WillReturnRows(sqlmock.NewRows([]string{"password_valid"}).AddRow(false))
+	mock.ExpectExec(regexp.QuoteMeta("SET password_migration_required = true")).
+		WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("'password_hash_mismatch', 'flagged'")).
+		WithArgs(userID, "203.0.113.7", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("SET failed_login_attempts = failed_login_attempts + 1")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectFailedLoginEvent(mock, "invalid password")
+
+	user, err := h.authenticateUser("legacy@example.com", "correct horse", "203.0.113.7")
+	assert.Nil(t, user)
+	var mismatch *services.PasswordHashMismatchError
+	require.True(t, errors.As(err, &mismatch), "got %v", err)
+	assert.Equal(t, services.PasswordHashMD5Crypt, mismatch.Scheme)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthenticateUser_DoesNotFlagCurrentBcryptHash(t *testing.T) {
+	h, mock := newDiagnosingAuthHandler(t)
+	userID := uuid.New()
+	expectUserLookup(mock, userID, "$2a$10$"+"abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT crypt($1, $2) = $2")).
+		WillReturnRows(sqlmock.NewRows([]string{"password_valid"}).AddRow(false))
+	mock.ExpectExec(regexp.QuoteMeta("SET failed_login_attempts = failed_login_attempts + 1")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectFailedLoginEvent(mock, "invalid password")
+
+	_, err := h.authenticateUser("legacy@example.com", "wrong", "203.0.113.7")
+	require.EqualError(t, err, "invalid password")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
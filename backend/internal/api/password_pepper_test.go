@@ -0,0 +1,120 @@
+package api
+
+import (
+	"database/sql/driver"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// bcryptOf matches a bcrypt hash of the given input
+type bcryptOf string
+
+func (b bcryptOf) Match(v driver.Value) bool {
+	hash, ok := v.(string)
+	return ok && bcrypt.CompareHashAndPassword([]byte(hash), []byte(b)) == nil
+}
+
+func TestAuthenticateUser_RehashesLegacyPepperOnLogin(t *testing.T) {
+	legacyPepper := "domainflow_legacy_pepper"
+	currentPepper := strings.Repeat("c", services.MinPasswordPepperLength)
+	pepper, err := services.NewPasswordPepper(config.PasswordHashConfig{Pepper: currentPepper, ExpectedPepperVersion: 3,
+		LegacyPeppers: map[int]string{2: legacyPepper}})
+	require.NoError(t, err)
+	h, mock := newDiagnosingAuthHandler(t)
+	h.SetPasswordPepper(pepper)
+
+	legacyPeppered, ok := pepper.ApplyVersion("correct horse", 2)
+	require.True(t, ok)
+	currentPeppered := pepper.Apply("correct horse")
+	legacyHash, err := bcrypt.GenerateFromPassword([]byte(legacyPeppered), bcrypt.MinCost)
+	require.NoError(t, err)
+	userID := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM auth.users")).WithArgs("legacy@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "password_pepper_version", "is_active"}).
+			AddRow(userID, "legacy@example.com", string(legacyHash), 2, true))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT crypt($1, $2) = $2")).
+		WithArgs(legacyPeppered, string(legacyHash)).
+		WillReturnRows(sqlmock.NewRows([]string{"password_valid"}).AddRow(true))
+	mock.ExpectExec(regexp.QuoteMeta("SET failed_login_attempts = 0")).
+		WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("SET password_hash = $2")).
+		WithArgs(userID, bcryptOf(currentPeppered), 3, string(legacyHash)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	user, err := h.authenticateUser("legacy@example.com", "correct horse", "203.0.113.7")
+	require.NoError(t, err)
+	assert.Equal(t, 3, user.PasswordPepperVersion)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPeppered)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// The next login verifies the new hash with the current pepper and leaves it alone
+	mock.ExpectQuery(regexp.QuoteMeta("FROM auth.users")).WithArgs("legacy@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "password_pepper_version", "is_active"}).
+			AddRow(userID, "legacy@example.com", user.PasswordHash, 3, true))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT crypt($1, $2) = $2")).
+		WithArgs(currentPeppered, user.PasswordHash).
+		WillReturnRows(sqlmock.NewRows([]string{"password_valid"}).AddRow(true))
+	mock.ExpectExec(regexp.QuoteMeta("SET failed_login_attempts = 0")).
+		WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err = h.authenticateUser("legacy@example.com", "correct horse", "203.0.113.7")
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthenticateUser_RehashesUnpepperedLongPassword(t *testing.T) {
+	pepper, err := services.NewPasswordPepper(config.PasswordHashConfig{
+		Pepper: strings.Repeat("c", services.MinPasswordPepperLength)})
+	require.NoError(t, err)
+	h, mock := newDiagnosingAuthHandler(t)
+	h.SetPasswordPepper(pepper)
+
+	// Past bcrypt's 72-byte limit with the pepper appended, but not once keyed with it
+	password := strings.Repeat("long passphrase ", 4)
+	unpepperedHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	userID := uuid.New()
+	expectUserLookup(mock, userID, string(unpepperedHash))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT crypt($1, $2) = $2")).
+		WithArgs(password, string(unpepperedHash)).
+		WillReturnRows(sqlmock.NewRows([]string{"password_valid"}).AddRow(true))
+	mock.ExpectExec(regexp.QuoteMeta("SET failed_login_attempts = 0")).
+		WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("SET password_hash = $2")).
+		WithArgs(userID, bcryptOf(pepper.Apply(password)), config.DefaultPasswordPepperVersion, string(unpepperedHash)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	user, err := h.authenticateUser("legacy@example.com", password, "203.0.113.7")
+	require.NoError(t, err)
+	assert.Equal(t, config.DefaultPasswordPepperVersion, user.PasswordPepperVersion)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthenticateUser_UnknownPepperVersionCannotSignIn(t *testing.T) {
+	pepper, err := services.NewPasswordPepper(config.PasswordHashConfig{
+		Pepper: strings.Repeat("c", services.MinPasswordPepperLength), ExpectedPepperVersion: 2})
+	require.NoError(t, err)
+	h, mock := newLockoutAuthHandler(t)
+	h.SetPasswordPepper(pepper)
+
+	userID := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM auth.users")).WithArgs("legacy@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "password_pepper_version", "is_active"}).
+			AddRow(userID, "legacy@example.com", lockedTestHash, 4, true))
+	mock.ExpectExec(regexp.QuoteMeta("SET failed_login_attempts = failed_login_attempts + 1")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectFailedLoginEvent(mock, "invalid password")
+
+	_, err = h.authenticateUser("legacy@example.com", "correct horse", "203.0.113.7")
+	require.EqualError(t, err, "invalid password")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// personaRequestOverheadBytes allows for the name, description and other fields sent around
+// configDetails when capping the size of a persona request body
+const personaRequestOverheadBytes = 16 * 1024
+
+// personaConfigLimits returns server.personaConfigMaxBytes and server.personaConfigMaxDepth
+func (h *APIHandler) personaConfigLimits() (maxBytes, maxDepth int) {
+	maxBytes, maxDepth = config.DefaultPersonaConfigMaxBytes, config.DefaultPersonaConfigMaxDepth
+	if h.Config != nil {
+		h.configMutex.RLock()
+		if h.Config.Server.PersonaConfigMaxBytes > 0 {
+			maxBytes = h.Config.Server.PersonaConfigMaxBytes
+		}
+		if h.Config.Server.PersonaConfigMaxDepth > 0 {
+			maxDepth = h.Config.Server.PersonaConfigMaxDepth
+		}
+		h.configMutex.RUnlock()
+	}
+	return maxBytes, maxDepth
+}
+
+// limitPersonaRequestBody caps the body of a request carrying configDetails, so an oversized
+// config is refused before it is decoded. It responds 413 and returns false when the declared
+// length is already over the cap.
+func (h *APIHandler) limitPersonaRequestBody(c *gin.Context) bool {
+	maxBytes, _ := h.personaConfigLimits()
+	limit := int64(maxBytes + personaRequestOverheadBytes)
+	if c.Request.ContentLength > limit {
+		respondWithPersonaConfigTooLarge(c, maxBytes)
+		return false
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+	return true
+}
+
+// respondIfPersonaBodyTooLarge sends 413 for a bind error caused by the body cap
+func (h *APIHandler) respondIfPersonaBodyTooLarge(c *gin.Context, bindErr error) bool {
+	var tooLarge *http.MaxBytesError
+	if !errors.As(bindErr, &tooLarge) {
+		return false
+	}
+	maxBytes, _ := h.personaConfigLimits()
+	respondWithPersonaConfigTooLarge(c, maxBytes)
+	return true
+}
+
+// checkPersonaConfigDetails enforces the size and nesting limits on configDetails before it is
+// validated, responding 413 or 400 and returning false when it is over either
+func (h *APIHandler) checkPersonaConfigDetails(c *gin.Context, raw json.RawMessage) bool {
+	maxBytes, maxDepth := h.personaConfigLimits()
+	if len(raw) > maxBytes {
+		respondWithPersonaConfigTooLarge(c, maxBytes)
+		return false
+	}
+	if jsonDepthExceeds(raw, maxDepth) {
+		respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation,
+			"configDetails is nested too deeply", []ErrorDetail{{
+				Field:   "configDetails",
+				Code:    ErrorCodeValidation,
+				Message: fmt.Sprintf("configDetails may nest objects and arrays at most %d levels deep", maxDepth),
+			}})
+		return false
+	}
+	return true
+}
+
+func respondWithPersonaConfigTooLarge(c *gin.Context, maxBytes int) {
+	respondWithDetailedErrorGin(c, http.StatusRequestEntityTooLarge, ErrorCodeValidation,
+		"configDetails is too large", []ErrorDetail{{
+			Field:   "configDetails",
+			Code:    ErrorCodeValidation,
+			Message: fmt.Sprintf("configDetails must be at most %d bytes", maxBytes),
+		}})
+}
+
+// jsonDepthExceeds reports whether raw nests objects and arrays more than maxDepth levels deep.
+// It stops reading at the first level over the limit. Malformed JSON is left to the decoding
+// that follows, which reports it.
+func jsonDepthExceeds(raw json.RawMessage, maxDepth int) bool {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+			if depth > maxDepth {
+				return true
+			}
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+}
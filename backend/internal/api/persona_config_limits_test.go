@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dnsPersonaConfig = `{"resolvers":["8.8.8.8:53"],"queryTimeoutSeconds":5,"maxDomainsPerRequest":10,
+	"resolverStrategy":"random_rotation","concurrentQueriesPerDomain":1,"maxConcurrentGoroutines":10,
+	"rateLimitDps":10,"rateLimitBurst":5}`
+
+// createdPersonaStore keeps the personas created through it
+type createdPersonaStore struct {
+	store.PersonaStore
+	created []*models.Persona
+}
+
+func (s *createdPersonaStore) CreatePersona(ctx context.Context, exec store.Querier, persona *models.Persona) error {
+	s.created = append(s.created, persona)
+	return nil
+}
+
+func newPersonaLimitsHandler(maxBytes, maxDepth int) (*APIHandler, *createdPersonaStore) {
+	personas := &createdPersonaStore{}
+	return &APIHandler{
+		Config: &config.AppConfig{Server: config.ServerConfig{
+			PersonaConfigMaxBytes: maxBytes,
+			PersonaConfigMaxDepth: maxDepth,
+		}},
+		PersonaStore:  personas,
+		AuditLogStore: discardAuditLogStore{},
+	}, personas
+}
+
+func personaErrorFields(t *testing.T, w *httptest.ResponseRecorder) []string {
+	t.Helper()
+	var resp struct {
+		Error struct {
+			Details []ErrorDetail `json:"details"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+	return detailFields(resp.Error.Details)
+}
+
+func TestCreatePersona_ConfigDetailsLimits(t *testing.T) {
+	h, personas := newPersonaLimitsHandler(1024, 4)
+
+	w := postJSON(h.CreateDNSPersonaGin, `{"name":"resolvers","configDetails":`+dnsPersonaConfig+`}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Len(t, personas.created, 1)
+
+	padding := strings.Repeat("x", 2048)
+	oversized := `{"name":"big","configDetails":{"resolvers":["8.8.8.8:53"],"padding":"` + padding + `"}}`
+	w = postJSON(h.CreateDNSPersonaGin, oversized)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+	assert.Equal(t, []string{"configDetails"}, personaErrorFields(t, w))
+
+	// Past the body cap the request is refused while it is read
+	w = postJSON(h.CreateDNSPersonaGin, `{"name":"huge","configDetails":{"padding":"`+strings.Repeat("x", 32*1024)+`"}}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+
+	deep := `{"name":"deep","configDetails":{"a":[[[{"b":1}]]]}}`
+	w = postJSON(h.CreateDNSPersonaGin, deep)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.Equal(t, []string{"configDetails"}, personaErrorFields(t, w))
+	assert.Len(t, personas.created, 1, "rejected configs are not stored")
+}
+
+func TestUpdatePersona_ConfigDetailsLimits(t *testing.T) {
+	h, _ := newPersonaLimitsHandler(1024, 4)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/personas/dns/:personaId", h.UpdateDNSPersonaGin)
+
+	body := `{"configDetails":{"padding":"` + strings.Repeat("x", 2048) + `"}}`
+	req := httptest.NewRequest(http.MethodPut, "/personas/dns/"+uuid.New().String(), strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code, "the limit applies before the persona is loaded")
+}
+
+func TestValidatePersona_ConfigDetailsLimits(t *testing.T) {
+	h, _ := newPersonaLimitsHandler(1024, 4)
+
+	result := validatePersona(t, h, `{"personaType":"dns","configDetails":`+dnsPersonaConfig+`}`)
+	assert.True(t, result.Valid)
+
+	w := postJSON(h.ValidatePersonaGin, `{"personaType":"dns","configDetails":{"padding":"`+strings.Repeat("x", 2048)+`"}}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+}
+
+func TestJSONDepthExceeds(t *testing.T) {
+	assert.False(t, jsonDepthExceeds(json.RawMessage(`{"a":{"b":[1,2]}}`), 3))
+	assert.True(t, jsonDepthExceeds(json.RawMessage(`{"a":{"b":[[1]]}}`), 3))
+	assert.False(t, jsonDepthExceeds(json.RawMessage(`"[[[[["`), 1), "brackets inside strings are not nesting")
+	assert.False(t, jsonDepthExceeds(nil, 1))
+}
@@ -10,7 +10,9 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/fntelecomllc/studio/backend/internal/httpvalidator"
 	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
 	"github.com/fntelecomllc/studio/backend/internal/store"
 
 	"github.com/gin-gonic/gin"
@@ -25,6 +27,7 @@ type CreatePersonaRequest struct {
 	Description   string                 `json:"description,omitempty"`
 	ConfigDetails json.RawMessage        `json:"configDetails" validate:"required"`
 	IsEnabled     *bool                  `json:"isEnabled,omitempty"`
+	Shared        *bool                  `json:"shared,omitempty"` // Usable by other users; defaults to the server's sharing default
 }
 
 type UpdatePersonaRequest struct {
@@ -32,6 +35,7 @@ type UpdatePersonaRequest struct {
 	Description   *string         `json:"description,omitempty"`
 	ConfigDetails json.RawMessage `json:"configDetails,omitempty"`
 	IsEnabled     *bool           `json:"isEnabled,omitempty"`
+	Shared        *bool           `json:"shared,omitempty"` // Only the owner or an admin may change sharing
 }
 
 // PersonaResponse formats a persona for API responses.
@@ -42,6 +46,8 @@ type PersonaResponse struct {
 	Description   string                 `json:"description,omitempty"`
 	ConfigDetails json.RawMessage        `json:"configDetails"`
 	IsEnabled     bool                   `json:"isEnabled"`
+	OwnerID       *uuid.UUID             `json:"ownerId,omitempty"`
+	Shared        bool                   `json:"shared"`
 	CreatedAt     time.Time              `json:"createdAt"`
 	UpdatedAt     time.Time              `json:"updatedAt"`
 }
@@ -54,6 +60,8 @@ func toPersonaResponse(p *models.Persona) PersonaResponse {
 		Description:   p.Description.String,
 		ConfigDetails: p.ConfigDetails,
 		IsEnabled:     p.IsEnabled,
+		OwnerID:       p.OwnerID,
+		Shared:        p.Shared,
 		CreatedAt:     p.CreatedAt,
 		UpdatedAt:     p.UpdatedAt,
 	}
@@ -71,16 +79,25 @@ func (h *APIHandler) CreateHTTPPersonaGin(c *gin.Context) {
 
 func (h *APIHandler) createPersonaGin(c *gin.Context, personaType models.PersonaTypeEnum) {
 	log.Printf("[createPersonaGin] Attempting to create persona of type: %s", personaType)
+	if !h.limitPersonaRequestBody(c) {
+		return
+	}
 	var req CreatePersonaRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindJSON(c, &req); err != nil {
 		log.Printf("[createPersonaGin] Error binding JSON: %v", err)
-		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		if !h.respondIfPersonaBodyTooLarge(c, err) {
+			respondWithErrorGin(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		}
 		return
 	}
 	log.Printf("[createPersonaGin] Request payload bound successfully for %s.", req.Name)
 
 	req.PersonaType = personaType // Ensure type from path is authoritative
 
+	if !h.checkPersonaConfigDetails(c, req.ConfigDetails) {
+		return
+	}
+
 	if err := validate.Struct(req); err != nil {
 		log.Printf("[createPersonaGin] Validation failed for CreatePersonaRequest: %v", err)
 		respondWithErrorGin(c, http.StatusBadRequest, "Validation failed: "+err.Error())
@@ -114,6 +131,16 @@ func (h *APIHandler) createPersonaGin(c *gin.Context, personaType models.Persona
 			respondWithErrorGin(c, http.StatusBadRequest, "HTTP configDetails validation failed: "+err.Error())
 			return
 		}
+		if err := httpvalidator.ValidateHTTPConfigTemplates(&httpConfig); err != nil {
+			log.Printf("[createPersonaGin] HTTP configDetails template validation failed for %s: %v", req.Name, err)
+			respondWithErrorGin(c, http.StatusBadRequest, "Invalid HTTP configDetails template: "+err.Error())
+			return
+		}
+		if err := httpvalidator.ValidateCertPins(httpConfig.CertPins); err != nil {
+			log.Printf("[createPersonaGin] HTTP configDetails cert pin validation failed for %s: %v", req.Name, err)
+			respondWithErrorGin(c, http.StatusBadRequest, "Invalid HTTP configDetails: "+err.Error())
+			return
+		}
 		log.Printf("[createPersonaGin] HTTP ConfigDetails validated for %s.", req.Name)
 	default:
 		log.Printf("[createPersonaGin] Invalid personaType '%s' encountered unexpectedly for %s.", req.PersonaType, req.Name)
@@ -127,6 +154,7 @@ func (h *APIHandler) createPersonaGin(c *gin.Context, personaType models.Persona
 	if req.IsEnabled != nil {
 		isEnabled = *req.IsEnabled
 	}
+	ownerID, shared := h.newResourceOwnership(c, req.Shared)
 
 	persona := &models.Persona{
 		ID:            personaID,
@@ -135,6 +163,8 @@ func (h *APIHandler) createPersonaGin(c *gin.Context, personaType models.Persona
 		Description:   sql.NullString{String: req.Description, Valid: req.Description != ""},
 		ConfigDetails: req.ConfigDetails,
 		IsEnabled:     isEnabled,
+		OwnerID:       ownerID,
+		Shared:        shared,
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}
@@ -190,12 +220,13 @@ func (h *APIHandler) createPersonaGin(c *gin.Context, personaType models.Persona
 	log.Printf("[createPersonaGin] PersonaStore.CreatePersona successful for %s.", persona.Name)
 
 	// Create Audit Log
+	details, _ := models.NewAuditDetails(map[string]string{"name": persona.Name, "id": persona.ID.String()})
 	auditLog := &models.AuditLog{
 		UserID:     uuid.NullUUID{}, // TODO: Populate UserID if available from auth context
 		Action:     fmt.Sprintf("Create %s Persona", req.PersonaType),
 		EntityType: sql.NullString{String: "Persona", Valid: true},
 		EntityID:   uuid.NullUUID{UUID: personaID, Valid: true},
-		Details:    models.JSONRawMessagePtr(json.RawMessage(fmt.Sprintf(`{"name":"%s", "id":"%s"}`, persona.Name, persona.ID.String()))),
+		Details:    details,
 	}
 	if err := h.AuditLogStore.CreateAuditLog(c.Request.Context(), querier, auditLog); err != nil {
 		opErr = err // Set opErr for SQL rollback if applicable
@@ -222,8 +253,12 @@ func (h *APIHandler) ListHTTPPersonasGin(c *gin.Context) {
 }
 
 func (h *APIHandler) listPersonasGin(c *gin.Context, personaType models.PersonaTypeEnum) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	page, err := parsePagination(c, DefaultPageLimit, DefaultMaxPageSize)
+	if err != nil {
+		respondWithPaginationErrorGin(c, err)
+		return
+	}
+	limit, offset := page.Limit, page.Offset
 
 	isEnabledQuery := c.Query("isEnabled")
 	var isEnabledFilter *bool
@@ -235,10 +270,11 @@ func (h *APIHandler) listPersonasGin(c *gin.Context, personaType models.PersonaT
 	}
 
 	filter := store.ListPersonasFilter{
-		Type:      personaType,
-		IsEnabled: isEnabledFilter,
-		Limit:     limit,
-		Offset:    offset,
+		Type:         personaType,
+		IsEnabled:    isEnabledFilter,
+		AccessibleTo: h.accessibleToFilter(c),
+		Limit:        limit,
+		Offset:       offset,
 	}
 
 	// For list operations, transactions are typically not managed at the handler level.
@@ -280,9 +316,17 @@ func (h *APIHandler) updatePersonaGin(c *gin.Context, personaType models.Persona
 		return
 	}
 
+	if !h.limitPersonaRequestBody(c) {
+		return
+	}
 	var req UpdatePersonaRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+	if err := bindJSON(c, &req); err != nil {
+		if !h.respondIfPersonaBodyTooLarge(c, err) {
+			respondWithErrorGin(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		}
+		return
+	}
+	if req.ConfigDetails != nil && !h.checkPersonaConfigDetails(c, req.ConfigDetails) {
 		return
 	}
 
@@ -326,6 +370,9 @@ func (h *APIHandler) updatePersonaGin(c *gin.Context, personaType models.Persona
 	}
 
 	existingPersona, fetchErr := h.PersonaStore.GetPersonaByID(c.Request.Context(), querier, personaID)
+	if fetchErr == nil && !h.canUseResource(c, existingPersona) {
+		fetchErr = store.ErrNotFound
+	}
 	if fetchErr != nil {
 		opErr = fetchErr // Set opErr for SQL rollback if applicable
 		if opErr == store.ErrNotFound {
@@ -342,6 +389,11 @@ func (h *APIHandler) updatePersonaGin(c *gin.Context, personaType models.Persona
 		respondWithErrorGin(c, http.StatusBadRequest, opErr.Error()) // opErr will trigger rollback if SQL
 		return
 	}
+	if !h.canModifyResource(c, existingPersona.OwnerID) {
+		opErr = fmt.Errorf("only the owner of persona %s may change it", personaIDStr)
+		respondWithErrorGin(c, http.StatusForbidden, opErr.Error())
+		return
+	}
 
 	updated := false
 	if req.Name != nil {
@@ -379,6 +431,16 @@ func (h *APIHandler) updatePersonaGin(c *gin.Context, personaType models.Persona
 				respondWithErrorGin(c, http.StatusBadRequest, opErr.Error())
 				return
 			}
+			if err := httpvalidator.ValidateHTTPConfigTemplates(&httpConfig); err != nil {
+				opErr = fmt.Errorf("invalid HTTP configDetails template for update: %w", err)
+				respondWithErrorGin(c, http.StatusBadRequest, opErr.Error())
+				return
+			}
+			if err := httpvalidator.ValidateCertPins(httpConfig.CertPins); err != nil {
+				opErr = fmt.Errorf("invalid HTTP configDetails for update: %w", err)
+				respondWithErrorGin(c, http.StatusBadRequest, opErr.Error())
+				return
+			}
 		}
 		existingPersona.ConfigDetails = req.ConfigDetails
 		updated = true
@@ -387,6 +449,15 @@ func (h *APIHandler) updatePersonaGin(c *gin.Context, personaType models.Persona
 		existingPersona.IsEnabled = *req.IsEnabled
 		updated = true
 	}
+	if req.Shared != nil {
+		if !canChangeSharing(c, existingPersona.OwnerID) {
+			opErr = fmt.Errorf("only the owner of persona %s may change its sharing", personaIDStr)
+			respondWithErrorGin(c, http.StatusForbidden, opErr.Error())
+			return
+		}
+		existingPersona.Shared = *req.Shared
+		updated = true
+	}
 
 	if !updated {
 		log.Printf("[updatePersonaGin] No fields to update for persona %s.", personaIDStr)
@@ -475,7 +546,10 @@ func (h *APIHandler) deletePersonaGin(c *gin.Context, personaType models.Persona
 
 	// First, verify persona exists and is of the correct type (especially for audit log context)
 	// This Get is done within the transaction for SQL, or directly for Firestore.
-	_, fetchErr := h.PersonaStore.GetPersonaByID(c.Request.Context(), querier, personaID)
+	persona, fetchErr := h.PersonaStore.GetPersonaByID(c.Request.Context(), querier, personaID)
+	if fetchErr == nil && !h.canUseResource(c, persona) {
+		fetchErr = store.ErrNotFound
+	}
 	if fetchErr != nil {
 		opErr = fetchErr // Set opErr for SQL rollback
 		if opErr == store.ErrNotFound {
@@ -486,6 +560,23 @@ func (h *APIHandler) deletePersonaGin(c *gin.Context, personaType models.Persona
 		}
 		return
 	}
+	if !h.canModifyResource(c, persona.OwnerID) {
+		opErr = fmt.Errorf("only the owner of persona %s may delete it", personaIDStr)
+		respondWithErrorGin(c, http.StatusForbidden, opErr.Error())
+		return
+	}
+
+	// Campaigns that use the persona keep a snapshot of it once it is force-deleted
+	campaignIDs, refErr := h.CampaignStore.ListCampaignIDsUsingPersona(c.Request.Context(), querier, personaID)
+	if refErr != nil {
+		opErr = refErr
+		log.Printf("[deletePersonaGin] Error listing campaigns using persona %s: %v", personaIDStr, opErr)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to check the campaigns using the persona")
+		return
+	}
+	if opErr = h.releaseCampaignReferences(c, querier, campaignIDs, personaSnapshot(persona, time.Now())); opErr != nil {
+		return
+	}
 
 	if errDel := h.PersonaStore.DeletePersona(c.Request.Context(), querier, personaID); errDel != nil {
 		opErr = errDel // Set opErr for SQL rollback
@@ -525,8 +616,12 @@ func (h *APIHandler) deletePersonaGin(c *gin.Context, personaType models.Persona
 // ListAllPersonasGin handles GET /api/v2/personas
 // Returns all personas (both DNS and HTTP) with optional filtering
 func (h *APIHandler) ListAllPersonasGin(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	page, err := parsePagination(c, DefaultPageLimit, DefaultMaxPageSize)
+	if err != nil {
+		respondWithPaginationErrorGin(c, err)
+		return
+	}
+	limit, offset := page.Limit, page.Offset
 
 	isEnabledQuery := c.Query("isEnabled")
 	var isEnabledFilter *bool
@@ -553,10 +648,11 @@ func (h *APIHandler) ListAllPersonasGin(c *gin.Context) {
 	// Note: empty typeFilter ("") means all types
 
 	filter := store.ListPersonasFilter{
-		Type:      typeFilter, // empty string means all types
-		IsEnabled: isEnabledFilter,
-		Limit:     limit,
-		Offset:    offset,
+		Type:         typeFilter, // empty string means all types
+		IsEnabled:    isEnabledFilter,
+		AccessibleTo: h.accessibleToFilter(c),
+		Limit:        limit,
+		Offset:       offset,
 	}
 
 	var querier store.Querier
@@ -582,10 +678,15 @@ func (h *APIHandler) ListAllPersonasGin(c *gin.Context) {
 // CreatePersonaGin handles POST /api/v2/personas
 // Creates a persona with the type specified in the request body
 func (h *APIHandler) CreatePersonaGin(c *gin.Context) {
+	if !h.limitPersonaRequestBody(c) {
+		return
+	}
 	var req CreatePersonaRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindJSON(c, &req); err != nil {
 		log.Printf("[CreatePersonaGin] Error binding JSON: %v", err)
-		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		if !h.respondIfPersonaBodyTooLarge(c, err) {
+			respondWithErrorGin(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		}
 		return
 	}
 
@@ -618,6 +719,9 @@ func (h *APIHandler) GetPersonaByIDGin(c *gin.Context) {
 	}
 
 	persona, err := h.PersonaStore.GetPersonaByID(c.Request.Context(), querier, personaID)
+	if err == nil && !h.canUseResource(c, persona) {
+		err = store.ErrNotFound
+	}
 	if err != nil {
 		if err == store.ErrNotFound {
 			respondWithErrorGin(c, http.StatusNotFound, fmt.Sprintf("Persona with ID %s not found", personaIDStr))
@@ -628,6 +732,9 @@ func (h *APIHandler) GetPersonaByIDGin(c *gin.Context) {
 		return
 	}
 
+	if respondNotModifiedGin(c, versionETag(persona.ID, persona.UpdatedAt)) {
+		return
+	}
 	respondWithJSONGin(c, http.StatusOK, toPersonaResponse(persona))
 }
 
@@ -648,6 +755,9 @@ func (h *APIHandler) UpdatePersonaGin(c *gin.Context) {
 	}
 
 	existingPersona, err := h.PersonaStore.GetPersonaByID(c.Request.Context(), querier, personaID)
+	if err == nil && !h.canUseResource(c, existingPersona) {
+		err = store.ErrNotFound
+	}
 	if err != nil {
 		if err == store.ErrNotFound {
 			respondWithErrorGin(c, http.StatusNotFound, fmt.Sprintf("Persona with ID %s not found", personaIDStr))
@@ -679,6 +789,9 @@ func (h *APIHandler) DeletePersonaGin(c *gin.Context) {
 	}
 
 	existingPersona, err := h.PersonaStore.GetPersonaByID(c.Request.Context(), querier, personaID)
+	if err == nil && !h.canUseResource(c, existingPersona) {
+		err = store.ErrNotFound
+	}
 	if err != nil {
 		if err == store.ErrNotFound {
 			respondWithErrorGin(c, http.StatusNotFound, fmt.Sprintf("Persona with ID %s not found", personaIDStr))
@@ -694,7 +807,9 @@ func (h *APIHandler) DeletePersonaGin(c *gin.Context) {
 }
 
 // TestPersonaGin handles POST /api/v2/personas/:id/test
-// Tests a persona by ID regardless of type
+// Tests a persona by ID regardless of type: a DNS persona resolves the probe domain through its
+// resolvers and an HTTP persona fetches the probe URL with its settings. A failed test is still a
+// 200 response; its result has success false and the error.
 func (h *APIHandler) TestPersonaGin(c *gin.Context) {
 	personaIDStr := c.Param("id")
 	personaID, err := uuid.Parse(personaIDStr)
@@ -702,6 +817,10 @@ func (h *APIHandler) TestPersonaGin(c *gin.Context) {
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid persona ID format")
 		return
 	}
+	if h.PersonaTester == nil {
+		respondWithErrorGin(c, http.StatusServiceUnavailable, "Persona tests are not available")
+		return
+	}
 
 	// Get the persona to determine its type
 	var querier store.Querier
@@ -710,6 +829,9 @@ func (h *APIHandler) TestPersonaGin(c *gin.Context) {
 	}
 
 	persona, err := h.PersonaStore.GetPersonaByID(c.Request.Context(), querier, personaID)
+	if err == nil && !h.canUseResource(c, persona) {
+		err = store.ErrNotFound
+	}
 	if err != nil {
 		if err == store.ErrNotFound {
 			respondWithErrorGin(c, http.StatusNotFound, fmt.Sprintf("Persona with ID %s not found", personaIDStr))
@@ -720,15 +842,56 @@ func (h *APIHandler) TestPersonaGin(c *gin.Context) {
 		return
 	}
 
-	// For now, return a simple test result
-	// In the future, this could trigger actual testing logic
-	testResult := map[string]interface{}{
-		"personaId":   persona.ID,
-		"personaType": persona.PersonaType,
-		"status":      "success",
-		"message":     fmt.Sprintf("%s persona test completed successfully", persona.PersonaType),
-		"testedAt":    time.Now().UTC().Format(time.RFC3339),
+	result, err := h.PersonaTester.Test(c.Request.Context(), persona)
+	if err != nil {
+		log.Printf("Error testing persona %s: %v", personaIDStr, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to test persona")
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, result)
+}
+
+// PersonaTestHistoryResponse lists a persona's scheduled test results
+type PersonaTestHistoryResponse struct {
+	PersonaID       uuid.UUID                    `json:"personaId"`
+	Enabled         bool                         `json:"enabled"` // Whether scheduled tests are running
+	IntervalSeconds int64                        `json:"intervalSeconds,omitempty"`
+	Results         []services.PersonaTestResult `json:"results"` // Newest first
+}
+
+// GetPersonaTestHistoryGin handles GET /api/v2/personas/:id/test-history
+// Returns the persona's recent scheduled test results, newest first
+func (h *APIHandler) GetPersonaTestHistoryGin(c *gin.Context) {
+	personaIDStr := c.Param("id")
+	personaID, err := uuid.Parse(personaIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid persona ID format")
+		return
+	}
+
+	var querier store.Querier
+	if h.DB != nil {
+		querier = h.DB
+	}
+
+	persona, err := h.PersonaStore.GetPersonaByID(c.Request.Context(), querier, personaID)
+	if err == nil && !h.canUseResource(c, persona) {
+		err = store.ErrNotFound
+	}
+	if err != nil {
+		if err == store.ErrNotFound {
+			respondWithErrorGin(c, http.StatusNotFound, fmt.Sprintf("Persona with ID %s not found", personaIDStr))
+		} else {
+			log.Printf("Error fetching persona %s for test history: %v", personaIDStr, err)
+			respondWithErrorGin(c, http.StatusInternalServerError, "Failed to fetch persona")
+		}
+		return
 	}
 
-	respondWithJSONGin(c, http.StatusOK, testResult)
+	respondWithJSONGin(c, http.StatusOK, PersonaTestHistoryResponse{
+		PersonaID:       personaID,
+		Enabled:         h.PersonaTests != nil,
+		IntervalSeconds: int64(h.PersonaTests.Interval() / time.Second),
+		Results:         h.PersonaTests.History(personaID),
+	})
 }
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postPersonaTest(h *APIHandler, personaID string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/personas/:id/test", h.TestPersonaGin)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/personas/"+personaID+"/test", nil))
+	return w
+}
+
+func TestTestPersonaGin(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer upstream.Close()
+
+	persona := &models.Persona{ID: uuid.New(), Name: "probe", PersonaType: models.PersonaTypeHTTP,
+		ConfigDetails: json.RawMessage(`{"userAgent":"ProbeAgent/1.0"}`), IsEnabled: true}
+	cfg := &config.AppConfig{PersonaTests: config.PersonaTestConfig{HTTPProbeURL: upstream.URL}}
+	h := &APIHandler{Config: cfg, PersonaStore: &ownedPersonaStore{personas: map[uuid.UUID]*models.Persona{persona.ID: persona}}}
+
+	assert.Equal(t, http.StatusServiceUnavailable, postPersonaTest(h, persona.ID.String()).Code)
+	h.PersonaTester = services.NewPersonaTester(cfg)
+	assert.Equal(t, http.StatusBadRequest, postPersonaTest(h, "not-a-uuid").Code)
+	assert.Equal(t, http.StatusNotFound, postPersonaTest(h, uuid.New().String()).Code)
+
+	// A failed test is reported, not answered with an error status
+	w := postPersonaTest(h, persona.ID.String())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var envelope struct {
+		Data services.PersonaProbeResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	assert.Equal(t, persona.ID, envelope.Data.PersonaID)
+	assert.False(t, envelope.Data.Success)
+	assert.Equal(t, services.PersonaTestFailed, envelope.Data.Status)
+	assert.Equal(t, http.StatusServiceUnavailable, envelope.Data.StatusCode)
+	assert.Equal(t, upstream.URL, envelope.Data.Target)
+	assert.NotEmpty(t, envelope.Data.Error)
+}
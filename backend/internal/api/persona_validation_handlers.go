@@ -0,0 +1,405 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fntelecomllc/studio/backend/internal/httpvalidator"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrorCodeLintWarning marks non-fatal findings reported by persona config validation
+const ErrorCodeLintWarning ErrorCode = "LINT_WARNING"
+
+// ValidatePersonaRequest is the body of POST /api/v2/personas/validate
+type ValidatePersonaRequest struct {
+	PersonaType   models.PersonaTypeEnum `json:"personaType" validate:"required,oneof=dns http"`
+	ConfigDetails json.RawMessage        `json:"configDetails" validate:"required"`
+}
+
+// PersonaValidationResult reports per-field errors and lint warnings for a persona config
+type PersonaValidationResult struct {
+	Valid    bool          `json:"valid"`
+	Errors   []ErrorDetail `json:"errors"`
+	Warnings []ErrorDetail `json:"warnings"`
+}
+
+// ValidatePersonaGin handles POST /api/v2/personas/validate
+// Validates and lints a persona config without creating anything
+func (h *APIHandler) ValidatePersonaGin(c *gin.Context) {
+	if !h.limitPersonaRequestBody(c) {
+		return
+	}
+	var req ValidatePersonaRequest
+	if err := bindJSON(c, &req); err != nil {
+		if h.respondIfPersonaBodyTooLarge(c, err) {
+			return
+		}
+		respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation, "Invalid request payload", []ErrorDetail{
+			{
+				Field:   bindErrorField(err),
+				Code:    ErrorCodeValidation,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	if !h.checkPersonaConfigDetails(c, req.ConfigDetails) {
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Validation failed: "+err.Error())
+		return
+	}
+
+	allowInsecureTLS := false
+	if h.Config != nil {
+		h.configMutex.RLock()
+		allowInsecureTLS = h.Config.HTTPValidator.AllowInsecureTLS
+		h.configMutex.RUnlock()
+	}
+
+	respondWithJSONGin(c, http.StatusOK, validatePersonaConfigDetails(req.PersonaType, req.ConfigDetails, allowInsecureTLS))
+}
+
+// validatePersonaConfigDetails runs the same struct validation used when saving a persona and
+// adds lint warnings for settings that are accepted but likely to cause trouble.
+func validatePersonaConfigDetails(personaType models.PersonaTypeEnum, raw json.RawMessage, allowInsecureTLS bool) PersonaValidationResult {
+	result := PersonaValidationResult{Errors: []ErrorDetail{}, Warnings: []ErrorDetail{}}
+
+	var target interface{}
+	switch personaType {
+	case models.PersonaTypeDNS:
+		target = &models.DNSConfigDetails{}
+	case models.PersonaTypeHTTP:
+		target = &models.HTTPConfigDetails{}
+	default:
+		result.Errors = append(result.Errors, ErrorDetail{
+			Field:   "personaType",
+			Code:    ErrorCodeValidation,
+			Message: "personaType must be 'dns' or 'http'",
+		})
+		return result
+	}
+
+	// Unknown fields are ignored when the persona is saved, so they are reported as warnings
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		if field, ok := unknownJSONField(err); ok {
+			result.Warnings = append(result.Warnings, ErrorDetail{
+				Field:   "configDetails." + field,
+				Code:    ErrorCodeLintWarning,
+				Message: fmt.Sprintf("Unknown field %q is ignored", field),
+			})
+		}
+		if err := json.Unmarshal(raw, target); err != nil {
+			result.Errors = append(result.Errors, ErrorDetail{
+				Field:   "configDetails",
+				Code:    ErrorCodeValidation,
+				Message: "configDetails is not a valid " + string(personaType) + " config: " + err.Error(),
+			})
+			return result
+		}
+	}
+
+	if err := validate.Struct(target); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			rootType := reflect.TypeOf(target).Elem()
+			for _, fe := range validationErrs {
+				result.Errors = append(result.Errors, ErrorDetail{
+					Field:   "configDetails." + jsonFieldPath(rootType, fe.StructNamespace()),
+					Code:    ErrorCodeValidation,
+					Message: validationMessage(fe),
+				})
+			}
+		} else {
+			result.Errors = append(result.Errors, ErrorDetail{
+				Field:   "configDetails",
+				Code:    ErrorCodeValidation,
+				Message: err.Error(),
+			})
+		}
+	}
+
+	switch cfg := target.(type) {
+	case *models.DNSConfigDetails:
+		result.Errors = append(result.Errors, dnsConfigErrors(cfg)...)
+		result.Warnings = append(result.Warnings, dnsConfigWarnings(cfg)...)
+	case *models.HTTPConfigDetails:
+		result.Errors = append(result.Errors, httpConfigErrors(cfg)...)
+		result.Warnings = append(result.Warnings, httpConfigWarnings(cfg, allowInsecureTLS)...)
+	}
+
+	result.Valid = len(result.Errors) == 0
+	return result
+}
+
+// dnsConfigErrors reports DNS settings that pass struct validation but cannot work together
+func dnsConfigErrors(cfg *models.DNSConfigDetails) []ErrorDetail {
+	var errs []ErrorDetail
+	if len(cfg.Resolvers) == 0 && !cfg.UseSystemResolvers {
+		errs = append(errs, ErrorDetail{
+			Field:   "configDetails.resolvers",
+			Code:    ErrorCodeValidation,
+			Message: "At least one resolver is required unless useSystemResolvers is enabled",
+		})
+	}
+	if cfg.QueryDelayMaxMs > 0 && cfg.QueryDelayMinMs > cfg.QueryDelayMaxMs {
+		errs = append(errs, ErrorDetail{
+			Field:   "configDetails.queryDelayMinMs",
+			Code:    ErrorCodeValidation,
+			Message: "queryDelayMinMs must not exceed queryDelayMaxMs",
+		})
+	}
+	return errs
+}
+
+// dnsConfigWarnings reports DNS settings that are valid but probably unintended
+func dnsConfigWarnings(cfg *models.DNSConfigDetails) []ErrorDetail {
+	var warnings []ErrorDetail
+	known := make(map[string]bool, len(cfg.Resolvers))
+	for _, resolver := range cfg.Resolvers {
+		known[resolver] = true
+	}
+
+	switch cfg.ResolverStrategy {
+	case "weighted_rotation":
+		if len(cfg.ResolversWeighted) == 0 {
+			warnings = append(warnings, ErrorDetail{
+				Field:   "configDetails.resolversWeighted",
+				Code:    ErrorCodeLintWarning,
+				Message: "weighted_rotation has no resolver weights and will behave like random_rotation",
+			})
+		}
+	case "specific_order":
+		if len(cfg.ResolversPreferredOrder) == 0 {
+			warnings = append(warnings, ErrorDetail{
+				Field:   "configDetails.resolversPreferredOrder",
+				Code:    ErrorCodeLintWarning,
+				Message: "specific_order has no preferred order and will use the resolvers list order",
+			})
+		}
+	}
+	for resolver := range cfg.ResolversWeighted {
+		if !known[resolver] {
+			warnings = append(warnings, ErrorDetail{
+				Field:   "configDetails.resolversWeighted",
+				Code:    ErrorCodeLintWarning,
+				Message: fmt.Sprintf("Weight set for %q, which is not in resolvers", resolver),
+			})
+		}
+	}
+	for _, resolver := range cfg.ResolversPreferredOrder {
+		if !known[resolver] {
+			warnings = append(warnings, ErrorDetail{
+				Field:   "configDetails.resolversPreferredOrder",
+				Code:    ErrorCodeLintWarning,
+				Message: fmt.Sprintf("Preferred resolver %q is not in resolvers", resolver),
+			})
+		}
+	}
+	if cfg.QueryTimeoutSeconds == 0 {
+		warnings = append(warnings, ErrorDetail{
+			Field:   "configDetails.queryTimeoutSeconds",
+			Code:    ErrorCodeLintWarning,
+			Message: "No query timeout is set; the server default will be used",
+		})
+	}
+	if cfg.RateLimitDps == 0 {
+		warnings = append(warnings, ErrorDetail{
+			Field:   "configDetails.rateLimitDps",
+			Code:    ErrorCodeLintWarning,
+			Message: "No rate limit is set; queries will not be throttled",
+		})
+	}
+	return warnings
+}
+
+// httpConfigErrors reports user agent and header templates that cannot be rendered and malformed
+// certificate pins
+func httpConfigErrors(cfg *models.HTTPConfigDetails) []ErrorDetail {
+	var errs []ErrorDetail
+	if err := httpvalidator.ValidateHeaderTemplate(cfg.UserAgent); err != nil {
+		errs = append(errs, ErrorDetail{
+			Field:   "configDetails.userAgent",
+			Code:    ErrorCodeValidation,
+			Message: "Invalid template: " + err.Error(),
+		})
+	}
+	names := make([]string, 0, len(cfg.Headers))
+	for name := range cfg.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := httpvalidator.ValidateHeaderTemplate(cfg.Headers[name]); err != nil {
+			errs = append(errs, ErrorDetail{
+				Field:   "configDetails.headers." + name,
+				Code:    ErrorCodeValidation,
+				Message: "Invalid template: " + err.Error(),
+			})
+		}
+	}
+	if err := httpvalidator.ValidateCertPins(cfg.CertPins); err != nil {
+		errs = append(errs, ErrorDetail{
+			Field:   "configDetails.certPins",
+			Code:    ErrorCodeValidation,
+			Message: err.Error(),
+		})
+	}
+	return errs
+}
+
+// httpConfigWarnings reports HTTP settings that are valid but weaken or destabilize requests
+func httpConfigWarnings(cfg *models.HTTPConfigDetails, allowInsecureTLS bool) []ErrorDetail {
+	var warnings []ErrorDetail
+	if allowInsecureTLS {
+		warnings = append(warnings, ErrorDetail{
+			Field:   "configDetails",
+			Code:    ErrorCodeLintWarning,
+			Message: "TLS certificate verification is disabled on this server (allowInsecureTLS); requests made with this persona will skip TLS verification",
+		})
+	}
+	if cfg.TLSClientHello != nil {
+		switch cfg.TLSClientHello.MinVersion {
+		case "TLS10", "TLS11":
+			warnings = append(warnings, ErrorDetail{
+				Field:   "configDetails.tlsClientHello.minVersion",
+				Code:    ErrorCodeLintWarning,
+				Message: cfg.TLSClientHello.MinVersion + " is deprecated and rejected by most servers",
+			})
+		}
+		if cfg.TLSClientHello.MaxVersion != "" && tlsVersionRank(cfg.TLSClientHello.MinVersion) > tlsVersionRank(cfg.TLSClientHello.MaxVersion) {
+			warnings = append(warnings, ErrorDetail{
+				Field:   "configDetails.tlsClientHello.maxVersion",
+				Code:    ErrorCodeLintWarning,
+				Message: "maxVersion is lower than minVersion; no TLS version can be negotiated",
+			})
+		}
+	}
+	for name := range cfg.Headers {
+		if strings.EqualFold(name, "User-Agent") {
+			warnings = append(warnings, ErrorDetail{
+				Field:   "configDetails.headers." + name,
+				Code:    ErrorCodeLintWarning,
+				Message: "User-Agent header overrides the persona userAgent",
+			})
+		}
+	}
+	for _, name := range cfg.HeaderOrder {
+		if _, ok := cfg.Headers[name]; !ok {
+			warnings = append(warnings, ErrorDetail{
+				Field:   "configDetails.headerOrder",
+				Code:    ErrorCodeLintWarning,
+				Message: fmt.Sprintf("Header %q is ordered but not set in headers", name),
+			})
+		}
+	}
+	if cfg.RequestTimeoutSeconds == 0 {
+		warnings = append(warnings, ErrorDetail{
+			Field:   "configDetails.requestTimeoutSeconds",
+			Code:    ErrorCodeLintWarning,
+			Message: "No request timeout is set; the server default will be used",
+		})
+	}
+	if cfg.RateLimitDps == 0 {
+		warnings = append(warnings, ErrorDetail{
+			Field:   "configDetails.rateLimitDps",
+			Code:    ErrorCodeLintWarning,
+			Message: "No rate limit is set; requests will not be throttled",
+		})
+	}
+	return warnings
+}
+
+func tlsVersionRank(version string) int {
+	switch version {
+	case "TLS10":
+		return 10
+	case "TLS11":
+		return 11
+	case "TLS12":
+		return 12
+	case "TLS13":
+		return 13
+	}
+	return 0
+}
+
+// jsonFieldPath converts a validator struct namespace (e.g. HTTPConfigDetails.TLSClientHello.MinVersion)
+// into the JSON path a client sent (tlsClientHello.minVersion), keeping any slice or map index.
+func jsonFieldPath(root reflect.Type, structNamespace string) string {
+	segments := strings.Split(structNamespace, ".")
+	if len(segments) > 1 {
+		segments = segments[1:]
+	}
+
+	current := root
+	path := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		name, index := segment, ""
+		if i := strings.Index(segment, "["); i >= 0 {
+			name, index = segment[:i], segment[i:]
+		}
+		for current != nil && current.Kind() == reflect.Ptr {
+			current = current.Elem()
+		}
+		if current == nil || current.Kind() != reflect.Struct {
+			path = append(path, segment)
+			current = nil
+			continue
+		}
+		field, ok := current.FieldByName(name)
+		if !ok {
+			path = append(path, segment)
+			current = nil
+			continue
+		}
+		jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if jsonName == "" || jsonName == "-" {
+			jsonName = name
+		}
+		path = append(path, jsonName+index)
+
+		current = field.Type
+		if index != "" {
+			for current.Kind() == reflect.Ptr {
+				current = current.Elem()
+			}
+			if current.Kind() == reflect.Slice || current.Kind() == reflect.Array || current.Kind() == reflect.Map {
+				current = current.Elem()
+			}
+		}
+	}
+	return strings.Join(path, ".")
+}
+
+// validationMessage renders a single validator failure as a client-facing message
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "This field is required"
+	case "oneof":
+		return "Must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "gt":
+		return "Must be greater than " + fe.Param()
+	case "gte":
+		return "Must be at least " + fe.Param()
+	case "lte":
+		return "Must be at most " + fe.Param()
+	case "hostname_port_or_url":
+		return "Must be a host:port or URL"
+	case "alphanum":
+		return "Must contain only letters and digits"
+	}
+	return fmt.Sprintf("Failed '%s' validation", fe.Tag())
+}
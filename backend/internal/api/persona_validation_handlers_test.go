@@ -0,0 +1,163 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validatePersona(t *testing.T, h *APIHandler, body string) PersonaValidationResult {
+	t.Helper()
+	w := postJSON(h.ValidatePersonaGin, body)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data PersonaValidationResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Data
+}
+
+func detailFields(details []ErrorDetail) []string {
+	fields := make([]string, 0, len(details))
+	for _, d := range details {
+		fields = append(fields, d.Field)
+	}
+	return fields
+}
+
+func TestValidatePersona_DNS(t *testing.T) {
+	h := &APIHandler{}
+
+	t.Run("valid", func(t *testing.T) {
+		result := validatePersona(t, h, `{"personaType":"dns","configDetails":{
+			"resolvers":["8.8.8.8:53","1.1.1.1:53"],"queryTimeoutSeconds":5,"maxDomainsPerRequest":10,
+			"resolverStrategy":"random_rotation","concurrentQueriesPerDomain":1,"maxConcurrentGoroutines":10,
+			"rateLimitDps":10,"rateLimitBurst":5}}`)
+		assert.True(t, result.Valid)
+		assert.Empty(t, result.Errors)
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		result := validatePersona(t, h, `{"personaType":"dns","configDetails":{
+			"resolvers":["not a resolver"],"maxDomainsPerRequest":0,"resolverStrategy":"fastest",
+			"concurrentQueriesPerDomain":1,"maxConcurrentGoroutines":10,"queryDelayMinMs":500,"queryDelayMaxMs":100,
+			"rateLimitDps":10}}`)
+		assert.False(t, result.Valid)
+		fields := detailFields(result.Errors)
+		assert.Contains(t, fields, "configDetails.resolvers[0]")
+		assert.Contains(t, fields, "configDetails.maxDomainsPerRequest")
+		assert.Contains(t, fields, "configDetails.resolverStrategy")
+		assert.Contains(t, fields, "configDetails.queryDelayMinMs")
+		for _, d := range result.Errors {
+			assert.Equal(t, ErrorCodeValidation, d.Code)
+		}
+	})
+
+	t.Run("warnings", func(t *testing.T) {
+		result := validatePersona(t, h, `{"personaType":"dns","configDetails":{
+			"resolvers":["8.8.8.8:53"],"maxDomainsPerRequest":10,"resolverStrategy":"weighted_rotation",
+			"concurrentQueriesPerDomain":1,"maxConcurrentGoroutines":10,"queryTimeoutSeconds":5,
+			"resolversPreferredOrder":["9.9.9.9:53"],"retries":3}}`)
+		assert.True(t, result.Valid, "lint warnings must not make the config invalid")
+		fields := detailFields(result.Warnings)
+		assert.Contains(t, fields, "configDetails.resolversWeighted")
+		assert.Contains(t, fields, "configDetails.resolversPreferredOrder")
+		assert.Contains(t, fields, "configDetails.rateLimitDps")
+		assert.Contains(t, fields, "configDetails.retries")
+		for _, d := range result.Warnings {
+			assert.Equal(t, ErrorCodeLintWarning, d.Code)
+		}
+	})
+}
+
+func TestValidatePersona_HTTP(t *testing.T) {
+	h := &APIHandler{}
+
+	t.Run("valid", func(t *testing.T) {
+		result := validatePersona(t, h, `{"personaType":"http","configDetails":{
+			"userAgent":"Mozilla/5.0","headers":{"Accept":"text/html"},"headerOrder":["Accept"],
+			"tlsClientHello":{"minVersion":"TLS12","maxVersion":"TLS13"},"requestTimeoutSeconds":30,
+			"allowedStatusCodes":[200,301],"rateLimitDps":5}}`)
+		assert.True(t, result.Valid)
+		assert.Empty(t, result.Errors)
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		result := validatePersona(t, h, `{"personaType":"http","configDetails":{
+			"tlsClientHello":{"minVersion":"SSL3"},"allowedStatusCodes":[200,42],
+			"cookieHandling":{"mode":"jar"},"requestTimeoutSeconds":-1}}`)
+		assert.False(t, result.Valid)
+		fields := detailFields(result.Errors)
+		assert.Contains(t, fields, "configDetails.userAgent")
+		assert.Contains(t, fields, "configDetails.tlsClientHello.minVersion")
+		assert.Contains(t, fields, "configDetails.allowedStatusCodes[1]")
+		assert.Contains(t, fields, "configDetails.cookieHandling.mode")
+		assert.Contains(t, fields, "configDetails.requestTimeoutSeconds")
+	})
+
+	t.Run("warnings", func(t *testing.T) {
+		cfg := &config.AppConfig{}
+		cfg.HTTPValidator.AllowInsecureTLS = true
+		result := validatePersona(t, &APIHandler{Config: cfg}, `{"personaType":"http","configDetails":{
+			"userAgent":"Mozilla/5.0","headers":{"User-Agent":"curl/8.0"},"headerOrder":["Accept"],
+			"tlsClientHello":{"minVersion":"TLS10"},"requestTimeoutSeconds":30,"rateLimitDps":5}}`)
+		assert.True(t, result.Valid)
+		assert.Empty(t, result.Errors)
+		fields := detailFields(result.Warnings)
+		assert.Contains(t, fields, "configDetails")
+		assert.Contains(t, fields, "configDetails.headers.User-Agent")
+		assert.Contains(t, fields, "configDetails.headerOrder")
+		assert.Contains(t, fields, "configDetails.tlsClientHello.minVersion")
+		assert.Contains(t, result.Warnings[0].Message, "TLS verification")
+	})
+
+	t.Run("templates", func(t *testing.T) {
+		result := validatePersona(t, h, `{"personaType":"http","configDetails":{
+			"userAgent":"Mozilla/5.0 ({{ domain }})","headers":{"X-Request-Id":"{{uuid}}","X-Target":"{{host}}",
+			"X-Open":"{{domain"},"requestTimeoutSeconds":30,"rateLimitDps":5}}`)
+		assert.False(t, result.Valid)
+		assert.Equal(t, []string{"configDetails.headers.X-Open", "configDetails.headers.X-Target"}, detailFields(result.Errors))
+		assert.Contains(t, result.Errors[1].Message, `unknown template variable "host"`)
+	})
+
+	t.Run("cert pins", func(t *testing.T) {
+		pin := strings.Repeat("ab", 32)
+		result := validatePersona(t, h, `{"personaType":"http","configDetails":{"userAgent":"Mozilla/5.0",
+			"certPins":{"example.com":["`+pin+`"]},"requestTimeoutSeconds":30,"rateLimitDps":5}}`)
+		assert.True(t, result.Valid)
+
+		result = validatePersona(t, h, `{"personaType":"http","configDetails":{"userAgent":"Mozilla/5.0",
+			"certPins":{"example.com":["not-a-fingerprint"]},"requestTimeoutSeconds":30,"rateLimitDps":5}}`)
+		assert.False(t, result.Valid)
+		assert.Equal(t, []string{"configDetails.certPins"}, detailFields(result.Errors))
+	})
+
+	t.Run("redirect and body limits", func(t *testing.T) {
+		result := validatePersona(t, h, `{"personaType":"http","configDetails":{"userAgent":"Mozilla/5.0",
+			"followRedirects":true,"maxRedirects":5,"maxBodyBytes":1048576,"requestTimeoutSeconds":30,"rateLimitDps":5}}`)
+		assert.True(t, result.Valid)
+
+		result = validatePersona(t, h, `{"personaType":"http","configDetails":{"userAgent":"Mozilla/5.0",
+			"maxRedirects":50,"maxBodyBytes":10,"requestTimeoutSeconds":30,"rateLimitDps":5}}`)
+		assert.False(t, result.Valid)
+		assert.ElementsMatch(t, []string{"configDetails.maxRedirects", "configDetails.maxBodyBytes"}, detailFields(result.Errors))
+	})
+}
+
+func TestValidatePersona_RejectsMalformedRequest(t *testing.T) {
+	h := &APIHandler{}
+	assert.Equal(t, http.StatusBadRequest, postJSON(h.ValidatePersonaGin, `{"personaType":"smtp","configDetails":{}}`).Code)
+	assert.Equal(t, http.StatusBadRequest, postJSON(h.ValidatePersonaGin, `{"personaType":"dns"}`).Code)
+
+	result := validatePersona(t, h, `{"personaType":"http","configDetails":"not an object"}`)
+	assert.False(t, result.Valid)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "configDetails", result.Errors[0].Field)
+}
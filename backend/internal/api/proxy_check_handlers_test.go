@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// healthCheckedProxyStore holds one proxy and applies the health updates written to it
+type healthCheckedProxyStore struct {
+	store.ProxyStore
+	proxy *models.Proxy
+}
+
+func (s *healthCheckedProxyStore) GetProxyByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Proxy, error) {
+	if id != s.proxy.ID {
+		return nil, store.ErrNotFound
+	}
+	copied := *s.proxy
+	return &copied, nil
+}
+
+func (s *healthCheckedProxyStore) UpdateProxyHealth(ctx context.Context, exec store.Querier, id uuid.UUID, isHealthy bool, lastStatus string, latencyMs sql.NullInt32, lastCheckedAt time.Time) error {
+	s.proxy.IsHealthy = isHealthy
+	s.proxy.LastStatus = sql.NullString{String: lastStatus, Valid: true}
+	s.proxy.LatencyMs = latencyMs
+	s.proxy.LastCheckedAt = sql.NullTime{Time: lastCheckedAt, Valid: true}
+	return nil
+}
+
+func postProxyCheck(h *APIHandler, proxyID string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/proxies/:proxyId/check", h.CheckProxyGin)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/proxies/"+proxyID+"/check", nil))
+	return w
+}
+
+func TestCheckProxyGin(t *testing.T) {
+	// The upstream server answers as a forward proxy for the check target
+	var proxiedURL string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedURL = r.URL.String()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer upstream.Close()
+
+	proxy := &models.Proxy{ID: uuid.New(), Name: "local", Address: strings.TrimPrefix(upstream.URL, "http://"),
+		Protocol: models.ProxyProtocolEnumPtr(models.ProxyProtocolHTTP), IsEnabled: true, IsHealthy: true}
+	ps := &healthCheckedProxyStore{proxy: proxy}
+	cfg := &config.AppConfig{ProxyHealth: config.ProxyHealthConfig{CheckTargetURL: "http://target.example/health"}}
+	h := &APIHandler{Config: cfg, ProxyStore: ps}
+
+	assert.Equal(t, http.StatusServiceUnavailable, postProxyCheck(h, proxy.ID.String()).Code)
+	h.ProxyHealth = services.NewProxyHealthChecker(cfg, nil, ps)
+	assert.Equal(t, http.StatusBadRequest, postProxyCheck(h, "not-a-uuid").Code)
+	assert.Equal(t, http.StatusNotFound, postProxyCheck(h, uuid.New().String()).Code)
+
+	w := postProxyCheck(h, proxy.ID.String())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var envelope struct {
+		Data services.ProxyCheckResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	assert.True(t, envelope.Data.IsHealthy)
+	assert.Equal(t, services.ProxyLastStatusActive, envelope.Data.LastStatus)
+	assert.NotNil(t, envelope.Data.LatencyMs)
+	assert.Equal(t, "http://target.example/health", proxiedURL, "the target is fetched through the proxy")
+	assert.Equal(t, services.ProxyLastStatusActive, proxy.LastStatus.String)
+
+	upstream.Close()
+	w = postProxyCheck(h, proxy.ID.String())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	assert.Equal(t, services.ProxyLastStatusFailing, envelope.Data.LastStatus)
+	assert.Equal(t, 1, envelope.Data.ConsecutiveFailures)
+	assert.NotEmpty(t, envelope.Data.Error)
+}
+
+func TestProxyResponse_HealthFields(t *testing.T) {
+	checkedAt := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	proxy := &models.Proxy{ID: uuid.New(), Name: "checked", IsHealthy: false,
+		LastStatus:    sql.NullString{String: services.ProxyLastStatusUnhealthy, Valid: true},
+		LastCheckedAt: sql.NullTime{Time: checkedAt, Valid: true},
+		LatencyMs:     sql.NullInt32{Int32: 87, Valid: true},
+	}
+	encoded, err := json.Marshal(toListProxyResponse([]*models.Proxy{proxy, {ID: uuid.New(), Name: "unchecked"}}))
+	require.NoError(t, err)
+	var decoded []map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	require.Len(t, decoded, 2)
+	assert.Equal(t, "checked", decoded[0]["name"])
+	assert.Equal(t, false, decoded[0]["isHealthy"])
+	assert.Equal(t, "Unhealthy", decoded[0]["lastStatus"])
+	assert.Equal(t, "2026-10-16T12:00:00Z", decoded[0]["lastCheckedAt"])
+	assert.Equal(t, float64(87), decoded[0]["latencyMs"])
+	assert.NotContains(t, decoded[1], "lastStatus")
+	assert.NotContains(t, decoded[1], "latencyMs")
+}
@@ -3,7 +3,7 @@ package api
 
 import (
 	"database/sql"
-	"encoding/json" // Needed for audit log details
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -14,6 +14,7 @@ import (
 	"github.com/fntelecomllc/studio/backend/internal/config" // For ProxyToProxyConfigEntry
 	"github.com/fntelecomllc/studio/backend/internal/models"
 	"github.com/fntelecomllc/studio/backend/internal/proxymanager"
+	"github.com/fntelecomllc/studio/backend/internal/services"
 	"github.com/fntelecomllc/studio/backend/internal/store"
 
 	"github.com/gin-gonic/gin"
@@ -33,6 +34,7 @@ type CreateProxyRequest struct {
 	Password    string                   `json:"password,omitempty"`
 	CountryCode string                   `json:"countryCode,omitempty"`
 	IsEnabled   *bool                    `json:"isEnabled,omitempty"`
+	Shared      *bool                    `json:"shared,omitempty"` // Usable by other users; defaults to the server's sharing default
 }
 
 type UpdateProxyRequest struct {
@@ -44,6 +46,7 @@ type UpdateProxyRequest struct {
 	Password    *string                   `json:"password,omitempty"`
 	CountryCode *string                   `json:"countryCode,omitempty"`
 	IsEnabled   *bool                     `json:"isEnabled,omitempty"`
+	Shared      *bool                     `json:"shared,omitempty"` // Only the owner or an admin may change sharing
 }
 
 // Helper to convert models.Proxy to config.ProxyConfigEntry
@@ -68,21 +71,51 @@ func proxyToProxyConfigEntry(p *models.Proxy) config.ProxyConfigEntry {
 	}
 }
 
-// toProxyResponse simply returns the proxy model itself, as it's already suitable for JSON response.
-func toProxyResponse(p *models.Proxy) *models.Proxy {
-	return p
+// proxyResponse is a proxy with its health check state as plain JSON values rather than nullable
+// column wrappers
+type proxyResponse struct {
+	*models.Proxy
+	LastStatus    string     `json:"lastStatus,omitempty"`
+	LastCheckedAt *time.Time `json:"lastCheckedAt,omitempty"`
+	LatencyMs     *int32     `json:"latencyMs,omitempty"`
 }
 
-func toListProxyResponse(proxies []*models.Proxy) []*models.Proxy {
-	// Return slice of pointers directly as models.Proxy is already JSON-marshalable
-	return proxies
+func toProxyResponse(p *models.Proxy) *proxyResponse {
+	response := &proxyResponse{Proxy: p, LastStatus: p.LastStatus.String}
+	if p.LastCheckedAt.Valid {
+		response.LastCheckedAt = &p.LastCheckedAt.Time
+	}
+	if p.LatencyMs.Valid {
+		response.LatencyMs = &p.LatencyMs.Int32
+	}
+	return response
+}
+
+func toListProxyResponse(proxies []*models.Proxy) []*proxyResponse {
+	responses := make([]*proxyResponse, len(proxies))
+	for i, p := range proxies {
+		responses[i] = toProxyResponse(p)
+	}
+	return responses
 }
 
 // --- Gin Handlers for Proxies ---
 
+// validProxySortColumns are the proxy list sort columns supported by the store
+var validProxySortColumns = map[string]bool{
+	"name":    true,
+	"latency": true,
+}
+
+// ListProxiesGin lists proxies filtered by protocol, enabled/healthy state, country code and provider,
+// sorted by name or latency, with the total match count in X-Total-Count and the page metadata.
 func (h *APIHandler) ListProxiesGin(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	page, err := parsePagination(c, DefaultMaxPageSize, DefaultMaxPageSize)
+	if err != nil {
+		respondWithPaginationErrorGin(c, err)
+		return
+	}
+	limit, offset := page.Limit, page.Offset
 
 	protocolFilter := models.ProxyProtocolEnum(c.Query("protocol"))
 	isEnabledQuery := c.Query("isEnabled")
@@ -101,13 +134,47 @@ func (h *APIHandler) ListProxiesGin(c *gin.Context) {
 			isHealthyFilter = &b
 		}
 	}
+	countryCode := c.Query("countryCode")
+	if countryCode == "" {
+		countryCode = c.Query("country_code")
+	}
+
+	sortBy := c.Query("sortBy")
+	sortOrder := c.Query("sortOrder")
+	if sortBy != "" && !validProxySortColumns[sortBy] {
+		respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation,
+			"Invalid sortBy parameter", []ErrorDetail{
+				{
+					Field:   "sortBy",
+					Code:    ErrorCodeValidation,
+					Message: "sortBy must be one of name, latency",
+				},
+			})
+		return
+	}
+	if sortOrder != "" && sortOrder != "asc" && sortOrder != "desc" {
+		respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation,
+			"Invalid sortOrder parameter", []ErrorDetail{
+				{
+					Field:   "sortOrder",
+					Code:    ErrorCodeValidation,
+					Message: "sortOrder must be asc or desc",
+				},
+			})
+		return
+	}
 
 	filter := store.ListProxiesFilter{
-		Protocol:  protocolFilter,
-		IsEnabled: isEnabledFilter,
-		IsHealthy: isHealthyFilter,
-		Limit:     limit,
-		Offset:    offset,
+		Protocol:     protocolFilter,
+		IsEnabled:    isEnabledFilter,
+		IsHealthy:    isHealthyFilter,
+		CountryCode:  countryCode,
+		Provider:     c.Query("provider"),
+		AccessibleTo: h.accessibleToFilter(c),
+		SortBy:       sortBy,
+		SortOrder:    sortOrder,
+		Limit:        limit,
+		Offset:       offset,
 	}
 
 	var querier store.Querier
@@ -121,19 +188,29 @@ func (h *APIHandler) ListProxiesGin(c *gin.Context) {
 		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to list proxies")
 		return
 	}
+	totalCount, err := h.ProxyStore.CountProxies(c.Request.Context(), querier, filter)
+	if err != nil {
+		log.Printf("Error counting proxies: %v", err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to list proxies")
+		return
+	}
 
-	sort.Slice(proxies, func(i, j int) bool {
-		if proxies[i] == nil || proxies[j] == nil {
-			return false
-		}
-		return proxies[i].Name < proxies[j].Name
+	c.Header("X-Total-Count", fmt.Sprintf("%d", totalCount))
+	response := NewSuccessResponse(toListProxyResponse(proxies), getRequestID(c))
+	response.WithMetadata(&Metadata{
+		Page: &PageInfo{
+			Current:  (offset / limit) + 1,
+			Total:    int((totalCount + int64(limit) - 1) / int64(limit)),
+			PageSize: limit,
+			Count:    int(totalCount),
+		},
 	})
-	respondWithJSONGin(c, http.StatusOK, toListProxyResponse(proxies))
+	respondWithJSONGin(c, http.StatusOK, response)
 }
 
 func (h *APIHandler) AddProxyGin(c *gin.Context) {
 	var req CreateProxyRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindJSON(c, &req); err != nil {
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
 		return
 	}
@@ -149,6 +226,7 @@ func (h *APIHandler) AddProxyGin(c *gin.Context) {
 	if req.IsEnabled != nil {
 		isEnabled = *req.IsEnabled
 	}
+	ownerID, shared := h.newResourceOwnership(c, req.Shared)
 
 	var passwordHash sql.NullString
 	if req.Password != "" {
@@ -173,6 +251,8 @@ func (h *APIHandler) AddProxyGin(c *gin.Context) {
 		IsEnabled:     isEnabled,
 		IsHealthy:     true,
 		LastCheckedAt: sql.NullTime{Time: now, Valid: true},
+		OwnerID:       ownerID,
+		Shared:        shared,
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}
@@ -219,12 +299,13 @@ func (h *APIHandler) AddProxyGin(c *gin.Context) {
 		return
 	}
 
+	details, _ := models.NewAuditDetails(map[string]string{"name": proxy.Name, "address": proxy.Address})
 	auditLog := &models.AuditLog{
 		UserID:     uuid.NullUUID{},
 		Action:     "Create Proxy",
 		EntityType: sql.NullString{String: "Proxy", Valid: true},
 		EntityID:   uuid.NullUUID{UUID: proxyID, Valid: true},
-		Details:    models.JSONRawMessagePtr(json.RawMessage(fmt.Sprintf(`{"name":"%s", "address":"%s"}`, proxy.Name, proxy.Address))),
+		Details:    details,
 	}
 	if auditErr := h.AuditLogStore.CreateAuditLog(c.Request.Context(), querier, auditLog); auditErr != nil {
 		opErr = auditErr
@@ -245,7 +326,7 @@ func (h *APIHandler) UpdateProxyGin(c *gin.Context) {
 	}
 
 	var req UpdateProxyRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindJSON(c, &req); err != nil {
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
 		return
 	}
@@ -291,6 +372,9 @@ func (h *APIHandler) UpdateProxyGin(c *gin.Context) {
 	}
 
 	existingProxy, fetchErr := h.ProxyStore.GetProxyByID(c.Request.Context(), querier, proxyID)
+	if fetchErr == nil && !h.canUseResource(c, existingProxy) {
+		fetchErr = store.ErrNotFound
+	}
 	if fetchErr != nil {
 		opErr = fetchErr
 		if opErr == store.ErrNotFound {
@@ -301,6 +385,11 @@ func (h *APIHandler) UpdateProxyGin(c *gin.Context) {
 		}
 		return
 	}
+	if !h.canModifyResource(c, existingProxy.OwnerID) {
+		opErr = fmt.Errorf("only the owner of proxy %s may change it", proxyIDStr)
+		respondWithErrorGin(c, http.StatusForbidden, opErr.Error())
+		return
+	}
 
 	updated := false
 	if req.Name != nil {
@@ -346,6 +435,15 @@ func (h *APIHandler) UpdateProxyGin(c *gin.Context) {
 		existingProxy.IsEnabled = *req.IsEnabled
 		updated = true
 	}
+	if req.Shared != nil {
+		if !canChangeSharing(c, existingProxy.OwnerID) {
+			opErr = fmt.Errorf("only the owner of proxy %s may change its sharing", proxyIDStr)
+			respondWithErrorGin(c, http.StatusForbidden, opErr.Error())
+			return
+		}
+		existingProxy.Shared = *req.Shared
+		updated = true
+	}
 
 	if !updated {
 		log.Printf("[UpdateProxyGin] No fields to update for proxy %s.", proxyIDStr)
@@ -421,7 +519,10 @@ func (h *APIHandler) DeleteProxyGin(c *gin.Context) {
 		log.Printf("[DeleteProxyGin] Operating in Firestore mode for %s.", proxyIDStr)
 	}
 
-	_, fetchErr := h.ProxyStore.GetProxyByID(c.Request.Context(), querier, proxyID)
+	proxy, fetchErr := h.ProxyStore.GetProxyByID(c.Request.Context(), querier, proxyID)
+	if fetchErr == nil && !h.canUseResource(c, proxy) {
+		fetchErr = store.ErrNotFound
+	}
 	if fetchErr != nil {
 		opErr = fetchErr
 		if opErr == store.ErrNotFound {
@@ -432,6 +533,23 @@ func (h *APIHandler) DeleteProxyGin(c *gin.Context) {
 		}
 		return
 	}
+	if !h.canModifyResource(c, proxy.OwnerID) {
+		opErr = fmt.Errorf("only the owner of proxy %s may delete it", proxyIDStr)
+		respondWithErrorGin(c, http.StatusForbidden, opErr.Error())
+		return
+	}
+
+	// Campaigns that use the proxy keep a snapshot of it once it is force-deleted
+	campaignIDs, refErr := h.CampaignStore.ListCampaignIDsUsingProxy(c.Request.Context(), querier, proxyID)
+	if refErr != nil {
+		opErr = refErr
+		log.Printf("[DeleteProxyGin] Error listing campaigns using proxy %s: %v", proxyIDStr, opErr)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to check the campaigns using the proxy")
+		return
+	}
+	if opErr = h.releaseCampaignReferences(c, querier, campaignIDs, proxySnapshot(proxy, time.Now())); opErr != nil {
+		return
+	}
 
 	if errDel := h.ProxyStore.DeleteProxy(c.Request.Context(), querier, proxyID); errDel != nil {
 		opErr = errDel
@@ -480,6 +598,10 @@ func (h *APIHandler) ForceCheckSingleProxyGin(c *gin.Context) {
 		respondWithErrorGin(c, http.StatusBadRequest, "Proxy ID missing in path")
 		return
 	}
+	if !h.canUseProxyID(c, proxyID) {
+		respondWithErrorGin(c, http.StatusNotFound, fmt.Sprintf("proxy ID '%s' not found", proxyID))
+		return
+	}
 	log.Printf("API: Gin request to force health check for proxy ID '%s'", proxyID)
 	updatedStatus, err := h.ProxyMgr.ForceCheckSingleProxy(proxyID)
 	if err != nil {
@@ -493,6 +615,30 @@ func (h *APIHandler) ForceCheckSingleProxyGin(c *gin.Context) {
 	respondWithJSONGin(c, http.StatusOK, updatedStatus)
 }
 
+// canUseProxyID reports whether the requesting user may use the stored proxy with the given ID. IDs
+// that do not name a stored proxy are only usable when ownership is not enforced.
+func (h *APIHandler) canUseProxyID(c *gin.Context, proxyIDStr string) bool {
+	if !h.accessibleToFilter(c).Valid {
+		return true
+	}
+	proxyID, err := uuid.Parse(proxyIDStr)
+	if err != nil {
+		return false
+	}
+	var querier store.Querier
+	if h.DB != nil {
+		querier = h.DB
+	}
+	proxy, err := h.ProxyStore.GetProxyByID(c.Request.Context(), querier, proxyID)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			log.Printf("Error fetching proxy %s for an access check: %v", proxyIDStr, err)
+		}
+		return false
+	}
+	return h.canUseResource(c, proxy)
+}
+
 func (h *APIHandler) ForceCheckAllProxiesGin(c *gin.Context) {
 	if h.ProxyMgr == nil {
 		respondWithErrorGin(c, http.StatusInternalServerError, "ProxyManager not available")
@@ -502,7 +648,7 @@ func (h *APIHandler) ForceCheckAllProxiesGin(c *gin.Context) {
 		IDs []string `json:"ids"`
 	}
 	if c.Request.ContentLength > 0 {
-		if err := c.ShouldBindJSON(&reqBody); err != nil {
+		if err := bindJSON(c, &reqBody); err != nil {
 			respondWithErrorGin(c, http.StatusBadRequest, "Invalid JSON in request body: "+err.Error())
 			return
 		}
@@ -521,6 +667,62 @@ func (h *APIHandler) ForceCheckAllProxiesGin(c *gin.Context) {
 	respondWithJSONGin(c, http.StatusAccepted, map[string]string{"message": message})
 }
 
+// CheckProxyGin health checks a stored proxy now and records the outcome on it
+// @Summary Check a proxy's health now
+// @Description Fetch the configured check target through the proxy, store its health, status, latency and check time, and return the outcome. Consecutive failures count towards marking the proxy unhealthy as in scheduled checks.
+// @Tags Proxies
+// @Produce json
+// @Param proxyId path string true "Proxy ID"
+// @Success 200 {object} services.ProxyCheckResult "Outcome of the check"
+// @Failure 400 {object} models.ErrorResponse "Invalid proxy ID or a protocol that cannot be checked"
+// @Failure 404 {object} models.ErrorResponse "Proxy not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 503 {object} models.ErrorResponse "Proxy health checks are not available"
+// @Security SessionAuth
+// @Router /proxies/{proxyId}/check [post]
+func (h *APIHandler) CheckProxyGin(c *gin.Context) {
+	proxyIDStr := c.Param("proxyId")
+	proxyID, err := uuid.Parse(proxyIDStr)
+	if err != nil {
+		respondWithErrorGin(c, http.StatusBadRequest, "Invalid proxy ID format")
+		return
+	}
+	if h.ProxyHealth == nil {
+		respondWithErrorGin(c, http.StatusServiceUnavailable, "Proxy health checks are not available")
+		return
+	}
+
+	var querier store.Querier
+	if h.DB != nil {
+		querier = h.DB
+	}
+	proxy, err := h.ProxyStore.GetProxyByID(c.Request.Context(), querier, proxyID)
+	if err == nil && !h.canUseResource(c, proxy) {
+		err = store.ErrNotFound
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithErrorGin(c, http.StatusNotFound, "Proxy not found")
+			return
+		}
+		log.Printf("Error fetching proxy %s for a health check: %v", proxyIDStr, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to check proxy")
+		return
+	}
+
+	result, err := h.ProxyHealth.Check(c.Request.Context(), proxy)
+	if err != nil {
+		if errors.Is(err, services.ErrProxyCheckUnsupported) {
+			respondWithErrorGin(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("Error health checking proxy %s: %v", proxyIDStr, err)
+		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to check proxy")
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, result)
+}
+
 func (h *APIHandler) TestProxyGin(c *gin.Context) {
 	proxyIDStr := c.Param("proxyId")
 	proxyID, err := uuid.Parse(proxyIDStr)
@@ -535,6 +737,9 @@ func (h *APIHandler) TestProxyGin(c *gin.Context) {
 	}
 
 	targetProxyModel, err := h.ProxyStore.GetProxyByID(c.Request.Context(), querier, proxyID)
+	if err == nil && !h.canUseResource(c, targetProxyModel) {
+		err = store.ErrNotFound
+	}
 	if err != nil {
 		if err == store.ErrNotFound {
 			respondWithErrorGin(c, http.StatusNotFound, fmt.Sprintf("Proxy ID '%s' not found for testing", proxyIDStr))
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+)
+
+// errResourceInUse rolls back the deletion of a persona or proxy that campaigns still reference
+var errResourceInUse = errors.New("resource is referenced by campaigns")
+
+// releaseCampaignReferences prepares deleting a persona or proxy that campaignIDs reference. Unless
+// the request has force=true it answers 409 listing the campaigns and returns errResourceInUse.
+// Forced, it keeps snapshot in the metadata of those campaigns, unless snapshots are disabled, so
+// their results stay interpretable after the resource is gone.
+func (h *APIHandler) releaseCampaignReferences(c *gin.Context, querier store.Querier, campaignIDs []uuid.UUID, snapshot *models.DeletedResourceSnapshot) error {
+	if len(campaignIDs) == 0 {
+		return nil
+	}
+	if force, _ := strconv.ParseBool(c.Query("force")); !force {
+		respondWithDetailedErrorGin(c, http.StatusConflict, ErrorCodeConflict,
+			fmt.Sprintf("The %s is used by %d campaign(s)", snapshot.ResourceType, len(campaignIDs)), []ErrorDetail{
+				{
+					Code:    ErrorCodeConflict,
+					Message: "Delete with force=true to delete it anyway; the campaigns keep a snapshot of its settings",
+					Context: map[string]interface{}{"campaignIds": campaignIDs},
+				},
+			})
+		return errResourceInUse
+	}
+	if h.Config != nil && !h.Config.ResourceAccess.DeletedResourceSnapshotsEnabled() {
+		return nil
+	}
+	if err := h.CampaignStore.AddDeletedResourceSnapshot(c.Request.Context(), querier, campaignIDs, snapshot); err != nil {
+		respondWithErrorGin(c, http.StatusInternalServerError, fmt.Sprintf("Failed to snapshot the %s into its campaigns", snapshot.ResourceType))
+		return err
+	}
+	return nil
+}
+
+// personaSnapshot records a persona's settings for the campaigns that used it
+func personaSnapshot(persona *models.Persona, deletedAt time.Time) *models.DeletedResourceSnapshot {
+	return &models.DeletedResourceSnapshot{
+		ResourceType: models.DeletedResourcePersona,
+		ID:           persona.ID,
+		Name:         persona.Name,
+		PersonaType:  persona.PersonaType,
+		Config:       persona.ConfigDetails,
+		DeletedAt:    deletedAt.UTC(),
+	}
+}
+
+// proxySnapshot records where a proxy pointed for the campaigns that used it. Credentials are left
+// out, including a password in the address.
+func proxySnapshot(proxy *models.Proxy, deletedAt time.Time) *models.DeletedResourceSnapshot {
+	settings := map[string]interface{}{"address": proxyAddressWithoutPassword(proxy.Address)}
+	if proxy.Protocol != nil {
+		settings["protocol"] = *proxy.Protocol
+	}
+	if proxy.Username.Valid {
+		settings["username"] = proxy.Username.String
+	}
+	if proxy.CountryCode.Valid {
+		settings["countryCode"] = proxy.CountryCode.String
+	}
+	if proxy.City.Valid {
+		settings["city"] = proxy.City.String
+	}
+	if proxy.Provider.Valid {
+		settings["provider"] = proxy.Provider.String
+	}
+	config, _ := json.Marshal(settings)
+	return &models.DeletedResourceSnapshot{
+		ResourceType: models.DeletedResourceProxy,
+		ID:           proxy.ID,
+		Name:         proxy.Name,
+		Config:       config,
+		DeletedAt:    deletedAt.UTC(),
+	}
+}
+
+// proxyAddressWithoutPassword drops the password from a proxy address given as a URL or as
+// user:password@host:port
+func proxyAddressWithoutPassword(address string) string {
+	if strings.Contains(address, "://") {
+		if parsed, err := url.Parse(address); err == nil && parsed.User != nil {
+			parsed.User = url.User(parsed.User.Username())
+			return parsed.String()
+		}
+		return address
+	}
+	if at := strings.LastIndex(address, "@"); at >= 0 {
+		username, _, _ := strings.Cut(address[:at], ":")
+		return username + address[at:]
+	}
+	return address
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/fntelecomllc/studio/backend/internal/middleware"
+)
+
+// newResourceOwnership returns the owner and shared flag for a persona or proxy created by the
+// requesting user. Resources created without a session user have no owner and are always shared.
+func (h *APIHandler) newResourceOwnership(c *gin.Context, shared *bool) (*uuid.UUID, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return nil, true
+	}
+	if shared != nil {
+		return &userID, *shared
+	}
+	if h.Config == nil {
+		return &userID, true
+	}
+	return &userID, h.Config.ResourceAccess.SharedByDefault()
+}
+
+// accessibleToFilter returns the user a persona or proxy list is limited to: with ownership
+// enforced, non-admin users only see resources they own or that are shared.
+func (h *APIHandler) accessibleToFilter(c *gin.Context) uuid.NullUUID {
+	if h.Config == nil || !h.Config.ResourceAccess.OwnershipEnforced() {
+		return uuid.NullUUID{}
+	}
+	if ownerFilter, scoped := middleware.GetOwnerFilter(c); scoped {
+		return uuid.NullUUID{UUID: ownerFilter.UserID, Valid: true}
+	}
+	return uuid.NullUUID{}
+}
+
+// canChangeSharing reports whether the requesting user may change whether a resource owned by
+// ownerID is shared. Only the owner and admins may.
+func canChangeSharing(c *gin.Context, ownerID *uuid.UUID) bool {
+	ownerFilter, scoped := middleware.GetOwnerFilter(c)
+	return !scoped || ownerFilter.Owns(ownerID)
+}
+
+// accessControlled is a persona or proxy, which is owned by one user and may be shared
+type accessControlled interface {
+	AccessibleTo(userID uuid.UUID) bool
+}
+
+// canUseResource reports whether the requesting user may see or use a persona or proxy: with
+// ownership enforced, non-admin users may only use resources they own or that are shared. Handlers
+// answer 404 otherwise, as for a resource that does not exist.
+func (h *APIHandler) canUseResource(c *gin.Context, resource accessControlled) bool {
+	accessibleTo := h.accessibleToFilter(c)
+	return !accessibleTo.Valid || resource.AccessibleTo(accessibleTo.UUID)
+}
+
+// canModifyResource reports whether the requesting user may change or delete a persona or proxy
+// owned by ownerID: with ownership enforced, non-admin users may not change a shared resource they
+// do not own.
+func (h *APIHandler) canModifyResource(c *gin.Context, ownerID *uuid.UUID) bool {
+	accessibleTo := h.accessibleToFilter(c)
+	return !accessibleTo.Valid || (ownerID != nil && *ownerID == accessibleTo.UUID)
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/middleware"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ownedPersonaStore serves personas from a map
+type ownedPersonaStore struct {
+	store.PersonaStore
+	personas map[uuid.UUID]*models.Persona
+}
+
+func (s *ownedPersonaStore) GetPersonaByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Persona, error) {
+	persona, ok := s.personas[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return persona, nil
+}
+
+// ownedProxyStore serves proxies from a map
+type ownedProxyStore struct {
+	store.ProxyStore
+	proxies map[uuid.UUID]*models.Proxy
+}
+
+func (s *ownedProxyStore) GetProxyByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Proxy, error) {
+	proxy, ok := s.proxies[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return proxy, nil
+}
+
+// creatingOrchestratorService records whether a campaign was created
+type creatingOrchestratorService struct {
+	services.CampaignOrchestratorService
+	created bool
+}
+
+func (s *creatingOrchestratorService) CreateCampaignUnified(ctx context.Context, req services.CreateCampaignRequest) (*models.Campaign, error) {
+	s.created = true
+	return &models.Campaign{ID: uuid.New(), Name: req.Name}, nil
+}
+
+func postCampaignAs(h *CampaignOrchestratorAPIHandler, securityContext *models.SecurityContext, personaID uuid.UUID) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("security_context", securityContext)
+		c.Next()
+	})
+	router.POST("/campaigns", (&middleware.AuthMiddleware{}).ScopeToOwner("personas"), h.createCampaign)
+
+	body := fmt.Sprintf(`{"campaignType":"dns_validation","name":"owned personas","dnsValidationParams":{"sourceCampaignId":"%s","personaIds":["%s"],"batchSize":10}}`, uuid.New(), personaID)
+	req := httptest.NewRequest(http.MethodPost, "/campaigns", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateCampaign_RejectsAnotherUsersPrivatePersona(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	private := &models.Persona{ID: uuid.New(), OwnerID: &owner, Shared: false}
+	shared := &models.Persona{ID: uuid.New(), OwnerID: &owner, Shared: true}
+	personaStore := &ownedPersonaStore{personas: map[uuid.UUID]*models.Persona{private.ID: private, shared.ID: shared}}
+
+	orchestrator := &creatingOrchestratorService{}
+	h := NewCampaignOrchestratorAPIHandler(orchestrator, &memoryCampaignListViewStore{})
+	h.SetResourceAccessChecker(services.NewResourceAccessChecker(nil, personaStore))
+
+	w := postCampaignAs(h, &models.SecurityContext{UserID: other, Roles: []string{"user"}}, private.ID)
+	require.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), private.ID.String())
+	assert.False(t, orchestrator.created)
+
+	w = postCampaignAs(h, &models.SecurityContext{UserID: other, Roles: []string{"user"}}, shared.ID)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	orchestrator.created = false
+	w = postCampaignAs(h, &models.SecurityContext{UserID: owner, Roles: []string{"user"}}, private.ID)
+	assert.Equal(t, http.StatusCreated, w.Code, "the owner may use a private persona")
+
+	orchestrator.created = false
+	w = postCampaignAs(h, &models.SecurityContext{UserID: other, Roles: []string{"admin"}}, private.ID)
+	assert.Equal(t, http.StatusCreated, w.Code, "admins bypass the ownership check")
+	assert.True(t, orchestrator.created)
+}
+
+// ownershipRouter serves the by-ID persona and proxy routes to the given user with ownership enforced
+func ownershipRouter(h *APIHandler, securityContext *models.SecurityContext) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	enforced := true
+	h.Config = &config.AppConfig{ResourceAccess: config.ResourceAccessConfig{EnforceOwnership: &enforced}}
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("security_context", securityContext)
+		c.Next()
+	})
+	authMiddleware := &middleware.AuthMiddleware{}
+	router.GET("/personas/:id", authMiddleware.ScopeToOwner("personas"), h.GetPersonaByIDGin)
+	router.GET("/personas/:id/test-history", authMiddleware.ScopeToOwner("personas"), h.GetPersonaTestHistoryGin)
+	router.DELETE("/personas/dns/:personaId", authMiddleware.ScopeToOwner("personas"), h.DeleteDNSPersonaGin)
+	router.POST("/proxies/:proxyId/test", authMiddleware.ScopeToOwner("proxies"), h.TestProxyGin)
+	router.DELETE("/proxies/:proxyId", authMiddleware.ScopeToOwner("proxies"), h.DeleteProxyGin)
+	return router
+}
+
+func serveAs(router *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestByIDRoutes_HideAnotherUsersPrivateResources(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	private := &models.Persona{ID: uuid.New(), PersonaType: models.PersonaTypeDNS, OwnerID: &owner}
+	shared := &models.Persona{ID: uuid.New(), PersonaType: models.PersonaTypeDNS, OwnerID: &owner, Shared: true}
+	privateProxy := &models.Proxy{ID: uuid.New(), OwnerID: &owner}
+	sharedProxy := &models.Proxy{ID: uuid.New(), OwnerID: &owner, Shared: true}
+	h := &APIHandler{
+		PersonaStore: &ownedPersonaStore{personas: map[uuid.UUID]*models.Persona{private.ID: private, shared.ID: shared}},
+		ProxyStore:   &ownedProxyStore{proxies: map[uuid.UUID]*models.Proxy{privateProxy.ID: privateProxy, sharedProxy.ID: sharedProxy}},
+	}
+
+	asOther := ownershipRouter(h, &models.SecurityContext{UserID: other, Roles: []string{"user"}})
+	for _, path := range []string{"/personas/" + private.ID.String(), "/personas/" + private.ID.String() + "/test-history"} {
+		assert.Equal(t, http.StatusNotFound, serveAs(asOther, http.MethodGet, path).Code, path)
+	}
+	assert.Equal(t, http.StatusNotFound, serveAs(asOther, http.MethodDelete, "/personas/dns/"+private.ID.String()).Code)
+	assert.Equal(t, http.StatusNotFound, serveAs(asOther, http.MethodPost, "/proxies/"+privateProxy.ID.String()+"/test").Code)
+	assert.Equal(t, http.StatusNotFound, serveAs(asOther, http.MethodDelete, "/proxies/"+privateProxy.ID.String()).Code)
+
+	// Shared resources may be used but only changed by their owner
+	assert.Equal(t, http.StatusOK, serveAs(asOther, http.MethodGet, "/personas/"+shared.ID.String()).Code)
+	assert.Equal(t, http.StatusForbidden, serveAs(asOther, http.MethodDelete, "/personas/dns/"+shared.ID.String()).Code)
+	assert.Equal(t, http.StatusForbidden, serveAs(asOther, http.MethodDelete, "/proxies/"+sharedProxy.ID.String()).Code)
+
+	asOwner := ownershipRouter(h, &models.SecurityContext{UserID: owner, Roles: []string{"user"}})
+	assert.Equal(t, http.StatusOK, serveAs(asOwner, http.MethodGet, "/personas/"+private.ID.String()).Code)
+	asAdmin := ownershipRouter(h, &models.SecurityContext{UserID: other, Roles: []string{"admin"}})
+	assert.Equal(t, http.StatusOK, serveAs(asAdmin, http.MethodGet, "/personas/"+private.ID.String()).Code)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/logging"
 	"github.com/gin-gonic/gin"
 )
 
@@ -36,7 +37,7 @@ func (h *APIHandler) UpdateServerConfigGin(c *gin.Context) {
 		GinMode         *string `json:"ginMode,omitempty"`
 		// Add other updatable, non-sensitive fields
 	}
-	if err := c.ShouldBindJSON(&reqServerConfigUpdate); err != nil {
+	if err := bindJSON(c, &reqServerConfigUpdate); err != nil {
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
 		return
 	}
@@ -104,7 +105,7 @@ func (h *APIHandler) GetDNSConfigGin(c *gin.Context) {
 // POST /api/v2/config/dns
 func (h *APIHandler) UpdateDNSConfigGin(c *gin.Context) {
 	var reqJSON config.DNSValidatorConfigJSON
-	if err := c.ShouldBindJSON(&reqJSON); err != nil {
+	if err := bindJSON(c, &reqJSON); err != nil {
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request body: "+err.Error())
 		return
 	}
@@ -147,7 +148,7 @@ func (h *APIHandler) GetHTTPConfigGin(c *gin.Context) {
 // POST /api/v2/config/http
 func (h *APIHandler) UpdateHTTPConfigGin(c *gin.Context) {
 	var reqJSON config.HTTPValidatorConfigJSON
-	if err := c.ShouldBindJSON(&reqJSON); err != nil {
+	if err := bindJSON(c, &reqJSON); err != nil {
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request body: "+err.Error())
 		return
 	}
@@ -180,16 +181,16 @@ func (h *APIHandler) GetLoggingConfigGin(c *gin.Context) {
 // POST /api/v2/config/logging
 func (h *APIHandler) UpdateLoggingConfigGin(c *gin.Context) {
 	var reqLogging config.LoggingConfig
-	if err := c.ShouldBindJSON(&reqLogging); err != nil {
+	if err := bindJSON(c, &reqLogging); err != nil {
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request body: "+err.Error())
 		return
 	}
-	// Validate logging level if necessary
-	validLevels := map[string]bool{"DEBUG": true, "INFO": true, "WARN": true, "ERROR": true}
-	if !validLevels[reqLogging.Level] {
+	level, err := logging.ParseLevel(reqLogging.Level)
+	if err != nil || level == logging.LogLevelFatal {
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid logging level")
 		return
 	}
+	reqLogging.Level = string(level)
 
 	h.configMutex.Lock()
 	h.Config.Logging = reqLogging
@@ -200,7 +201,7 @@ func (h *APIHandler) UpdateLoggingConfigGin(c *gin.Context) {
 		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to save Logging configuration")
 		return
 	}
+	logging.SetLevel(level)
 	log.Printf("API: Updated server Logging configuration. New level: %s", reqLogging.Level)
-	// TODO: Apply new logging level dynamically to the logger if possible
 	respondWithJSONGin(c, http.StatusOK, reqLogging)
 }
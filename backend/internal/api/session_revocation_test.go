@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingAuditLogStore keeps the audit entries it is given
+type recordingAuditLogStore struct {
+	store.AuditLogStore
+	entries []*models.AuditLog
+}
+
+func (r *recordingAuditLogStore) CreateAuditLog(ctx context.Context, exec store.Querier, logEntry *models.AuditLog) error {
+	r.entries = append(r.entries, logEntry)
+	return nil
+}
+
+var (
+	laptopSessionID = strings.Repeat("a1", 64)
+	phoneSessionID  = strings.Repeat("b2", 64)
+)
+
+// expectUserSessions lists the caller's laptop and phone sessions
+func expectUserSessions(mock sqlmock.Sqlmock, userID uuid.UUID) {
+	columns := []string{"id", "user_id", "ip_address", "user_agent", "session_fingerprint", "browser_fingerprint",
+		"screen_resolution", "is_active", "expires_at", "last_activity_at", "created_at", "device_public_key"}
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE user_id = $1 AND is_active = true AND expires_at > NOW()")).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(laptopSessionID, userID, "203.0.113.7", firefoxLinuxUA, nil, nil, nil, true, now.Add(time.Hour), now, now.Add(-time.Hour), nil).
+			AddRow(phoneSessionID, userID, "198.51.100.4", safariIPhoneUA, nil, nil, nil, true, now.Add(time.Hour), now.Add(-time.Minute), now.Add(-2*time.Hour), nil))
+}
+
+func newSessionRouter(t *testing.T, userID uuid.UUID) (*gin.Engine, sqlmock.Sqlmock, *recordingAuditLogStore) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	auditLogStore := &recordingAuditLogStore{}
+	mock.ExpectQuery("FROM auth.sessions").WillReturnError(assert.AnError) // startup cache warm-up
+	sessionService, err := services.NewSessionService(sqlxDB, services.DefaultSessionConfig(), auditLogStore)
+	require.NoError(t, err)
+	h := NewAuthHandler(sessionService, &config.SessionSettings{CookieName: "session_id"}, sqlxDB)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("security_context", &models.SecurityContext{UserID: userID, SessionID: laptopSessionID})
+		c.Next()
+	})
+	router.GET("/auth/sessions", h.ListSessions)
+	router.DELETE("/auth/sessions/:id", h.RevokeSession)
+	return router, mock, auditLogStore
+}
+
+func TestListSessions_ShowsPrefixesOnly(t *testing.T) {
+	userID := uuid.New()
+	router, mock, _ := newSessionRouter(t, userID)
+	expectUserSessions(mock, userID)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/sessions", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), laptopSessionID)
+	assert.NotContains(t, w.Body.String(), phoneSessionID)
+
+	var resp struct {
+		Data []models.ActiveSession `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 2)
+	assert.Equal(t, laptopSessionID[:services.SessionIDPrefixLength], resp.Data[0].IDPrefix)
+	assert.True(t, resp.Data[0].Current)
+	assert.Equal(t, "203.0.113.7", resp.Data[0].IPAddress)
+	assert.Equal(t, phoneSessionID[:services.SessionIDPrefixLength], resp.Data[1].IDPrefix)
+	assert.False(t, resp.Data[1].Current)
+	assert.Equal(t, safariIPhoneUA, resp.Data[1].UserAgent)
+}
+
+func TestRevokeSession_OnlyTheCallersSessions(t *testing.T) {
+	userID := uuid.New()
+	router, mock, auditLogStore := newSessionRouter(t, userID)
+	revoke := func(prefix string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/auth/sessions/"+prefix, nil))
+		return w
+	}
+
+	// The phone session is signed out; the caller's laptop session keeps its cookie
+	expectUserSessions(mock, userID)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE auth.sessions SET is_active = false WHERE id = $1")).
+		WithArgs(phoneSessionID).WillReturnResult(sqlmock.NewResult(0, 1))
+	w := revoke(phoneSessionID[:services.SessionIDPrefixLength])
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Empty(t, w.Header().Get("Set-Cookie"))
+	require.Len(t, auditLogStore.entries, 1)
+	assert.Equal(t, "session_revoked", auditLogStore.entries[0].Action)
+	assert.Equal(t, userID, auditLogStore.entries[0].UserID.UUID)
+	require.NotNil(t, auditLogStore.entries[0].Details)
+	assert.NotContains(t, string(*auditLogStore.entries[0].Details), phoneSessionID)
+
+	// Another user's session is not among the caller's and is not found
+	expectUserSessions(mock, userID)
+	assert.Equal(t, http.StatusNotFound, revoke(strings.Repeat("c3", 64)).Code)
+
+	// Prefixes shorter than the listed ones are rejected without a lookup
+	assert.Equal(t, http.StatusNotFound, revoke("a1").Code)
+
+	// Revoking the current session also clears its cookie
+	expectUserSessions(mock, userID)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE auth.sessions SET is_active = false WHERE id = $1")).
+		WithArgs(laptopSessionID).WillReturnResult(sqlmock.NewResult(0, 1))
+	w = revoke(laptopSessionID[:services.SessionIDPrefixLength])
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Set-Cookie"), "session_id=;")
+	assert.Len(t, auditLogStore.entries, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/settings"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SettingsAPIHandler exposes admin endpoints for runtime settings.
+type SettingsAPIHandler struct {
+	settings      *settings.Service
+	auditLogStore store.AuditLogStore
+}
+
+// NewSettingsAPIHandler creates a new handler for runtime settings.
+func NewSettingsAPIHandler(settingsService *settings.Service, auditLogStore store.AuditLogStore) *SettingsAPIHandler {
+	return &SettingsAPIHandler{settings: settingsService, auditLogStore: auditLogStore}
+}
+
+// SettingResponse is a registered setting with its effective value.
+type SettingResponse struct {
+	settings.Definition
+	Value     json.RawMessage `json:"value"`
+	IsDefault bool            `json:"isDefault"`
+	UpdatedBy *uuid.UUID      `json:"updatedBy,omitempty"`
+	UpdatedAt *time.Time      `json:"updatedAt,omitempty"`
+}
+
+// UpdateSettingsRequest sets one or more settings, keyed by setting key.
+type UpdateSettingsRequest struct {
+	Settings map[string]json.RawMessage `json:"settings" validate:"required,min=1"`
+}
+
+func (h *SettingsAPIHandler) settingResponse(c *gin.Context, key string) (*SettingResponse, error) {
+	resp := &SettingResponse{Definition: h.settings.Registry()[key]}
+	setting, err := h.settings.Get(c.Request.Context(), key)
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil {
+		resp.IsDefault = true
+		resp.Value, err = h.settings.Raw(c.Request.Context(), key)
+		return resp, err
+	}
+	resp.Value = setting.Value
+	resp.UpdatedAt = &setting.UpdatedAt
+	if setting.UpdatedBy.Valid {
+		resp.UpdatedBy = &setting.UpdatedBy.UUID
+	}
+	return resp, nil
+}
+
+// ListSettingsGin lists every registered setting with its current value.
+// @Summary List runtime settings
+// @Description List all registered runtime settings with their type, default and current value
+// @Tags Admin
+// @Produce json
+// @Success 200 {array} SettingResponse "Registered settings"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /admin/settings [get]
+func (h *SettingsAPIHandler) ListSettingsGin(c *gin.Context) {
+	keys := h.settings.Registry().Keys()
+	result := make([]*SettingResponse, 0, len(keys))
+	for _, key := range keys {
+		resp, err := h.settingResponse(c, key)
+		if err != nil {
+			log.Printf("Error loading setting %s: %v", key, err)
+			respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeDatabaseError,
+				"Failed to retrieve settings", nil)
+			return
+		}
+		result = append(result, resp)
+	}
+	respondWithJSONGin(c, http.StatusOK, result)
+}
+
+// UpdateSettingsGin updates one or more registered settings.
+// @Summary Update runtime settings
+// @Description Update registered runtime settings. Every key and value is validated before any setting is changed.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body UpdateSettingsRequest true "Settings to update"
+// @Success 200 {array} SettingResponse "Updated settings"
+// @Failure 400 {object} models.ErrorResponse "Unknown setting key or invalid value"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /admin/settings [put]
+func (h *SettingsAPIHandler) UpdateSettingsGin(c *gin.Context) {
+	var req UpdateSettingsRequest
+	if err := bindJSON(c, &req); err != nil {
+		respondWithValidationErrorGin(c, []ErrorDetail{{
+			Field:   bindErrorField(err),
+			Code:    ErrorCodeValidation,
+			Message: "Invalid request payload: " + err.Error(),
+		}})
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		respondWithValidationErrorGin(c, []ErrorDetail{{
+			Field:   "settings",
+			Code:    ErrorCodeValidation,
+			Message: "At least one setting is required",
+		}})
+		return
+	}
+
+	// Validate every setting before changing any of them
+	registry := h.settings.Registry()
+	var validationErrors []ErrorDetail
+	for _, key := range sortedKeys(req.Settings) {
+		if _, err := registry.Validate(key, req.Settings[key]); err != nil {
+			message := err.Error()
+			if errors.Is(err, settings.ErrUnknownSetting) {
+				message = "Unknown setting key"
+			}
+			validationErrors = append(validationErrors, ErrorDetail{
+				Field:   "settings." + key,
+				Code:    ErrorCodeValidation,
+				Message: message,
+			})
+		}
+	}
+	if len(validationErrors) > 0 {
+		respondWithValidationErrorGin(c, validationErrors)
+		return
+	}
+
+	var updatedBy uuid.NullUUID
+	if userID, ok := currentUserID(c); ok {
+		updatedBy = uuid.NullUUID{UUID: userID, Valid: true}
+	}
+	result := make([]*SettingResponse, 0, len(req.Settings))
+	for _, key := range sortedKeys(req.Settings) {
+		if _, err := h.settings.Set(c.Request.Context(), key, req.Settings[key], updatedBy); err != nil {
+			log.Printf("Error updating setting %s: %v", key, err)
+			respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeDatabaseError,
+				"Failed to update setting "+key, nil)
+			return
+		}
+		resp, err := h.settingResponse(c, key)
+		if err != nil {
+			log.Printf("Error loading setting %s: %v", key, err)
+			respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeDatabaseError,
+				"Failed to retrieve setting "+key, nil)
+			return
+		}
+		result = append(result, resp)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{"settings": req.Settings})
+	auditLog := &models.AuditLog{
+		UserID:     updatedBy,
+		Action:     "Update Settings",
+		EntityType: sql.NullString{String: "Setting", Valid: true},
+		Details:    models.JSONRawMessagePtr(details),
+		ClientIP:   sql.NullString{String: c.ClientIP(), Valid: c.ClientIP() != ""},
+		UserAgent:  sql.NullString{String: c.Request.UserAgent(), Valid: c.Request.UserAgent() != ""},
+	}
+	if h.auditLogStore != nil {
+		if auditErr := h.auditLogStore.CreateAuditLog(c.Request.Context(), nil, auditLog); auditErr != nil {
+			log.Printf("Error creating audit log for settings update: %v", auditErr)
+		}
+	}
+
+	respondWithJSONGin(c, http.StatusOK, result)
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
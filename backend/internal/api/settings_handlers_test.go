@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/settings"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySettingsStore is an in-memory store.SettingsStore for tests
+type memorySettingsStore struct {
+	mu       sync.Mutex
+	settings map[string]models.Setting
+}
+
+func (m *memorySettingsStore) GetSetting(ctx context.Context, exec store.Querier, key string) (*models.Setting, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	setting, ok := m.settings[key]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return &setting, nil
+}
+
+func (m *memorySettingsStore) ListSettings(ctx context.Context, exec store.Querier) ([]*models.Setting, error) {
+	return nil, nil
+}
+
+func (m *memorySettingsStore) UpsertSetting(ctx context.Context, exec store.Querier, setting *models.Setting) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.settings[setting.Key] = *setting
+	return nil
+}
+
+func newSettingsRouter(settingsStore store.SettingsStore) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewSettingsAPIHandler(settings.NewService(settingsStore, settings.DefaultRegistry(), time.Minute), nil)
+	router := gin.New()
+	router.GET("/admin/settings", h.ListSettingsGin)
+	router.PUT("/admin/settings", h.UpdateSettingsGin)
+	return router
+}
+
+func putSettings(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, "/admin/settings", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSettingsAPI_UpdateAndList(t *testing.T) {
+	router := newSettingsRouter(&memorySettingsStore{settings: map[string]models.Setting{}})
+
+	w := putSettings(router, `{"settings":{"maintenance.enabled":true,"retention.auditLogDays":14}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/settings", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data []SettingResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	byKey := map[string]SettingResponse{}
+	for _, s := range resp.Data {
+		byKey[s.Key] = s
+	}
+	require.Len(t, byKey, len(settings.DefaultRegistry()))
+	assert.JSONEq(t, `true`, string(byKey[settings.KeyMaintenanceMode].Value))
+	assert.False(t, byKey[settings.KeyMaintenanceMode].IsDefault)
+	assert.JSONEq(t, `14`, string(byKey[settings.KeyAuditLogRetentionDays].Value))
+	assert.JSONEq(t, `""`, string(byKey[settings.KeyMaintenanceMessage].Value))
+	assert.True(t, byKey[settings.KeyMaintenanceMessage].IsDefault)
+}
+
+func TestSettingsAPI_RejectsUnknownKeysWithoutApplyingAnything(t *testing.T) {
+	settingsStore := &memorySettingsStore{settings: map[string]models.Setting{}}
+	router := newSettingsRouter(settingsStore)
+
+	w := putSettings(router, `{"settings":{"maintenance.enabled":true,"maintenance.enabeld":true,"retention.auditLogDays":"14"}}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"settings.maintenance.enabeld"`)
+	assert.Contains(t, w.Body.String(), "Unknown setting key")
+	assert.Contains(t, w.Body.String(), `"settings.retention.auditLogDays"`)
+	assert.Empty(t, settingsStore.settings)
+
+	w = putSettings(router, `{"settings":{}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Endpoint names used to configure soft deadlines (server.softDeadlinesMs)
+const (
+	SoftDeadlineCampaignStats = "campaignStats"
+)
+
+// DefaultSoftDeadline applies to endpoints without a configured soft deadline
+const DefaultSoftDeadline = 2 * time.Second
+
+var (
+	softDeadlinesMu sync.RWMutex
+	softDeadlines   = map[string]time.Duration{}
+)
+
+// SetSoftDeadlines sets the per-endpoint soft deadlines in milliseconds. Endpoints that are not
+// listed, or have a non-positive value, use DefaultSoftDeadline.
+func SetSoftDeadlines(deadlinesMs map[string]int) {
+	deadlines := make(map[string]time.Duration, len(deadlinesMs))
+	for endpoint, ms := range deadlinesMs {
+		if ms > 0 {
+			deadlines[endpoint] = time.Duration(ms) * time.Millisecond
+		}
+	}
+	softDeadlinesMu.Lock()
+	softDeadlines = deadlines
+	softDeadlinesMu.Unlock()
+}
+
+func softDeadlineFor(endpoint string) time.Duration {
+	softDeadlinesMu.RLock()
+	defer softDeadlinesMu.RUnlock()
+	if d, ok := softDeadlines[endpoint]; ok {
+		return d
+	}
+	return DefaultSoftDeadline
+}
+
+// withSoftDeadline returns the request context bounded by the endpoint's soft deadline. Aggregate
+// endpoints stop gathering when it expires and respond with what they have, flagged as partial.
+// Callers may send allowPartial=false to wait for complete results instead.
+func withSoftDeadline(c *gin.Context, endpoint string) (context.Context, context.CancelFunc) {
+	if allow, err := strconv.ParseBool(c.DefaultQuery("allowPartial", "true")); err == nil && !allow {
+		return context.WithCancel(c.Request.Context())
+	}
+	return context.WithTimeout(c.Request.Context(), softDeadlineFor(endpoint))
+}
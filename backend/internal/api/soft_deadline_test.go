@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowStatsOrchestratorService counts results quickly but takes aggregateDelay for the source count,
+// returning whatever it has if the context ends first
+type slowStatsOrchestratorService struct {
+	services.CampaignOrchestratorService
+	aggregateDelay time.Duration
+}
+
+func (s *slowStatsOrchestratorService) GetCampaignStats(ctx context.Context, campaignID uuid.UUID) (*services.CampaignStatsResponse, error) {
+	resultCount := int64(10)
+	stats := &services.CampaignStatsResponse{CampaignID: campaignID, ResultCount: &resultCount}
+	select {
+	case <-time.After(s.aggregateDelay):
+		sourceDomains := int64(25)
+		stats.SourceDomains = &sourceDomains
+	case <-ctx.Done():
+		stats.Partial = true
+		stats.PendingSections = []string{services.StatsSectionSourceDomains}
+	}
+	return stats, nil
+}
+
+func getCampaignStatsResponse(t *testing.T, orchestrator services.CampaignOrchestratorService, query string) services.CampaignStatsResponse {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/campaigns/:campaignId/stats", NewCampaignOrchestratorAPIHandler(orchestrator, nil).getCampaignStats)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/campaigns/"+uuid.NewString()+"/stats"+query, nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data services.CampaignStatsResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Data
+}
+
+func TestGetCampaignStats_SoftDeadlineReturnsPartialData(t *testing.T) {
+	SetSoftDeadlines(map[string]int{SoftDeadlineCampaignStats: 20})
+	t.Cleanup(func() { SetSoftDeadlines(nil) })
+
+	stats := getCampaignStatsResponse(t, &slowStatsOrchestratorService{aggregateDelay: time.Second}, "")
+	assert.True(t, stats.Partial)
+	assert.Equal(t, []string{services.StatsSectionSourceDomains}, stats.PendingSections)
+	require.NotNil(t, stats.ResultCount)
+	assert.Equal(t, int64(10), *stats.ResultCount)
+	assert.Nil(t, stats.SourceDomains)
+}
+
+func TestGetCampaignStats_CompletesWithinSoftDeadline(t *testing.T) {
+	SetSoftDeadlines(map[string]int{SoftDeadlineCampaignStats: 1000})
+	t.Cleanup(func() { SetSoftDeadlines(nil) })
+
+	stats := getCampaignStatsResponse(t, &slowStatsOrchestratorService{aggregateDelay: 0}, "")
+	assert.False(t, stats.Partial)
+	require.NotNil(t, stats.SourceDomains)
+}
+
+func TestGetCampaignStats_AllowPartialFalseWaitsForCompleteResults(t *testing.T) {
+	SetSoftDeadlines(map[string]int{SoftDeadlineCampaignStats: 10})
+	t.Cleanup(func() { SetSoftDeadlines(nil) })
+
+	stats := getCampaignStatsResponse(t, &slowStatsOrchestratorService{aggregateDelay: 50 * time.Millisecond}, "?allowPartial=false")
+	assert.False(t, stats.Partial)
+	require.NotNil(t, stats.SourceDomains)
+	assert.Equal(t, int64(25), *stats.SourceDomains)
+}
+
+func TestSoftDeadlineFor_FallsBackToDefault(t *testing.T) {
+	SetSoftDeadlines(map[string]int{SoftDeadlineCampaignStats: -5})
+	t.Cleanup(func() { SetSoftDeadlines(nil) })
+
+	assert.Equal(t, DefaultSoftDeadline, softDeadlineFor(SoftDeadlineCampaignStats))
+	assert.Equal(t, DefaultSoftDeadline, softDeadlineFor("unknownEndpoint"))
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/fntelecomllc/studio/backend/internal/domainexpert"
+	"github.com/gin-gonic/gin"
+)
+
+// TLDListAPIHandler exposes admin endpoints for the TLD list used to validate campaign TLDs.
+type TLDListAPIHandler struct {
+	list   *domainexpert.TLDList
+	source string
+}
+
+// NewTLDListAPIHandler creates a handler that refreshes list from source.
+func NewTLDListAPIHandler(list *domainexpert.TLDList, source string) *TLDListAPIHandler {
+	return &TLDListAPIHandler{list: list, source: source}
+}
+
+// GetTLDListStatusGin handles GET /api/v2/admin/tlds
+func (h *TLDListAPIHandler) GetTLDListStatusGin(c *gin.Context) {
+	respondWithJSONGin(c, http.StatusOK, h.list.Status())
+}
+
+// RefreshTLDListGin handles POST /api/v2/admin/tlds/refresh. The configured source is re-read;
+// when that fails the current list stays in use and 502 is returned with its status.
+func (h *TLDListAPIHandler) RefreshTLDListGin(c *gin.Context) {
+	if err := h.list.Refresh(c.Request.Context(), h.source); err != nil {
+		log.Printf("Error refreshing TLD list: %v", err)
+		respondWithDetailedErrorGin(c, http.StatusBadGateway, ErrorCodeServiceUnavailable,
+			"Failed to refresh TLD list; the previous list is still in use", []ErrorDetail{
+				{Code: ErrorCodeServiceUnavailable, Message: err.Error(), Context: h.list.Status()},
+			})
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, h.list.Status())
+}
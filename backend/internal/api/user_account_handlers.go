@@ -0,0 +1,240 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/fntelecomllc/studio/backend/internal/middleware"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UserAccountAPIHandler exposes the user management endpoints support staff use instead of editing
+// the database: listing accounts, unlocking them and disabling or reactivating them.
+type UserAccountAPIHandler struct {
+	accountService *services.UserAccountService
+}
+
+// NewUserAccountAPIHandler creates a new handler for user account administration.
+func NewUserAccountAPIHandler(accountService *services.UserAccountService) *UserAccountAPIHandler {
+	return &UserAccountAPIHandler{accountService: accountService}
+}
+
+// RegisterUserAccountRoutes registers the account routes on the /admin/users group. Every route
+// requires system:users.
+func (h *UserAccountAPIHandler) RegisterUserAccountRoutes(group *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware) {
+	requireUsers := authMiddleware.RequirePermission("system:users")
+	group.GET("", requireUsers, h.ListUsersGin)
+	group.GET("/:userId/security", requireUsers, h.GetUserSecurityGin)
+	group.POST("/:userId/unlock", requireUsers, h.UnlockUserGin)
+	group.POST("/:userId/disable", requireUsers, h.DisableUserGin)
+	group.POST("/:userId/enable", requireUsers, h.EnableUserGin)
+}
+
+// ListUsersGin lists user accounts, newest first.
+// @Summary List users
+// @Description Page through user accounts with their roles, optionally only active or inactive and locked or unlocked ones.
+// @Tags Admin
+// @Produce json
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Users per page (default 10)"
+// @Param isActive query bool false "Only active (true) or disabled (false) accounts"
+// @Param isLocked query bool false "Only locked (true) or unlocked (false) accounts"
+// @Success 200 {object} ListUsersResponse "A page of users"
+// @Failure 400 {object} APIResponse "Invalid pagination or filter"
+// @Failure 500 {object} APIResponse "Internal server error"
+// @Security SessionAuth
+// @Router /admin/users [get]
+func (h *UserAccountAPIHandler) ListUsersGin(c *gin.Context) {
+	pagination, err := parsePagination(c, 10, DefaultMaxPageSize)
+	if err != nil {
+		respondWithPaginationErrorGin(c, err)
+		return
+	}
+	filter := store.ListUserAccountsFilter{Limit: pagination.Limit, Offset: pagination.Offset}
+	for _, param := range []struct {
+		name   string
+		target **bool
+	}{{"isActive", &filter.IsActive}, {"isLocked", &filter.IsLocked}} {
+		raw := c.Query(param.name)
+		if raw == "" {
+			continue
+		}
+		value, parseErr := strconv.ParseBool(raw)
+		if parseErr != nil {
+			respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation, "Invalid user filter",
+				[]ErrorDetail{{Field: param.name, Code: ErrorCodeValidation, Message: "must be true or false"}})
+			return
+		}
+		*param.target = &value
+	}
+
+	accounts, total, err := h.accountService.ListAccounts(c.Request.Context(), filter)
+	if err != nil {
+		log.Printf("Error listing users: %v", err)
+		respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeDatabaseError, "Failed to list users", nil)
+		return
+	}
+	users := make([]UserResponse, len(accounts))
+	for i, account := range accounts {
+		users[i] = UserResponse{
+			ID:                  account.ID,
+			Email:               account.Email,
+			EmailVerified:       account.EmailVerified,
+			FirstName:           account.FirstName,
+			LastName:            account.LastName,
+			AvatarURL:           account.AvatarURL,
+			IsActive:            account.IsActive,
+			IsLocked:            account.IsLocked,
+			FailedLoginAttempts: account.FailedLoginAttempts,
+			LastLoginAt:         account.LastLoginAt,
+			MFAEnabled:          account.MFAEnabled,
+			CreatedAt:           account.CreatedAt,
+			UpdatedAt:           account.UpdatedAt,
+			Roles:               account.Roles,
+		}
+	}
+	respondWithJSONGin(c, http.StatusOK, ListUsersResponse{
+		Users: users,
+		Pagination: PaginationResponse{
+			Page:       pagination.Offset/pagination.Limit + 1,
+			Limit:      pagination.Limit,
+			Total:      int(total),
+			TotalPages: (int(total) + pagination.Limit - 1) / pagination.Limit,
+		},
+	})
+}
+
+// GetUserSecurityGin returns a user's lock status, failed login attempts and last login.
+// @Summary Get a user's account security status
+// @Description Show whether the account is active and locked, until when, how many failed logins it has and when and where it last logged in.
+// @Tags Admin
+// @Produce json
+// @Param userId path string true "User ID"
+// @Success 200 {object} models.UserSecurityStatus "Account security status"
+// @Failure 400 {object} APIResponse "Invalid user ID"
+// @Failure 404 {object} APIResponse "User not found"
+// @Failure 500 {object} APIResponse "Internal server error"
+// @Security SessionAuth
+// @Router /admin/users/{userId}/security [get]
+func (h *UserAccountAPIHandler) GetUserSecurityGin(c *gin.Context) {
+	userID, ok := parseUserDataUserID(c)
+	if !ok {
+		return
+	}
+	status, err := h.accountService.GetSecurityStatus(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithDetailedErrorGin(c, http.StatusNotFound, ErrorCodeNotFound, "User not found", nil)
+			return
+		}
+		log.Printf("Error getting security status of user %s: %v", userID, err)
+		respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeDatabaseError, "Failed to get account security status", nil)
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, status)
+}
+
+// UnlockUserGin unlocks a user's account.
+// @Summary Unlock a user's account
+// @Description Clear the account's lock and failed login attempts, and the login rate limits held against the user and the addresses their failed logins came from. Records an account_unlocked audit event.
+// @Tags Admin
+// @Produce json
+// @Param userId path string true "User ID"
+// @Success 200 {object} models.UserAccountUnlock "What the unlock cleared"
+// @Failure 400 {object} APIResponse "Invalid user ID"
+// @Failure 404 {object} APIResponse "User not found"
+// @Failure 500 {object} APIResponse "Internal server error"
+// @Security SessionAuth
+// @Router /admin/users/{userId}/unlock [post]
+func (h *UserAccountAPIHandler) UnlockUserGin(c *gin.Context) {
+	userID, ok := parseUserDataUserID(c)
+	if !ok {
+		return
+	}
+	result, err := h.accountService.Unlock(c.Request.Context(), userID, userAccountActor(c))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithDetailedErrorGin(c, http.StatusNotFound, ErrorCodeNotFound, "User not found", nil)
+			return
+		}
+		log.Printf("Error unlocking user %s: %v", userID, err)
+		respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeDatabaseError, "Failed to unlock account", nil)
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, result)
+}
+
+// DisableUserGin deactivates a user's account and ends their sessions.
+// @Summary Disable a user's account
+// @Description Set the account inactive and end all of its sessions, signing the user out at once. Records an account_disabled audit event. Administrators cannot disable their own account.
+// @Tags Admin
+// @Produce json
+// @Param userId path string true "User ID"
+// @Success 200 {object} models.UserSecurityStatus "The account after the change"
+// @Failure 400 {object} APIResponse "Invalid user ID, or the caller's own account"
+// @Failure 404 {object} APIResponse "User not found"
+// @Failure 500 {object} APIResponse "Internal server error"
+// @Security SessionAuth
+// @Router /admin/users/{userId}/disable [post]
+func (h *UserAccountAPIHandler) DisableUserGin(c *gin.Context) {
+	userID, ok := parseUserDataUserID(c)
+	if !ok {
+		return
+	}
+	actor := userAccountActor(c)
+	if actor.UserID == userID {
+		respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation, "You cannot disable your own account", nil)
+		return
+	}
+	status, err := h.accountService.Disable(c.Request.Context(), userID, actor)
+	h.respondWithAccountStatus(c, userID, "disabling", status, err)
+}
+
+// EnableUserGin reactivates a disabled user's account.
+// @Summary Reactivate a user's account
+// @Description Set a disabled account active again. Records an account_enabled audit event. A locked account stays locked until it is unlocked.
+// @Tags Admin
+// @Produce json
+// @Param userId path string true "User ID"
+// @Success 200 {object} models.UserSecurityStatus "The account after the change"
+// @Failure 400 {object} APIResponse "Invalid user ID"
+// @Failure 404 {object} APIResponse "User not found"
+// @Failure 500 {object} APIResponse "Internal server error"
+// @Security SessionAuth
+// @Router /admin/users/{userId}/enable [post]
+func (h *UserAccountAPIHandler) EnableUserGin(c *gin.Context) {
+	userID, ok := parseUserDataUserID(c)
+	if !ok {
+		return
+	}
+	status, err := h.accountService.Enable(c.Request.Context(), userID, userAccountActor(c))
+	h.respondWithAccountStatus(c, userID, "enabling", status, err)
+}
+
+func (h *UserAccountAPIHandler) respondWithAccountStatus(c *gin.Context, userID uuid.UUID, operation string, status *models.UserSecurityStatus, err error) {
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithDetailedErrorGin(c, http.StatusNotFound, ErrorCodeNotFound, "User not found", nil)
+			return
+		}
+		log.Printf("Error %s user %s: %v", operation, userID, err)
+		respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeDatabaseError, "Failed to update account", nil)
+		return
+	}
+	respondWithJSONGin(c, http.StatusOK, status)
+}
+
+// userAccountActor identifies the administrator making the request for the audit log
+func userAccountActor(c *gin.Context) services.UserAccountActor {
+	actor := services.UserAccountActor{IPAddress: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+	if userID, ok := currentUserID(c); ok {
+		actor.UserID = userID
+	}
+	return actor
+}
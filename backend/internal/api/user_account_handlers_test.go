@@ -0,0 +1,269 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/fntelecomllc/studio/backend/internal/middleware"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryUserAccountStore holds accounts by ID and records the auth events written
+type memoryUserAccountStore struct {
+	store.UserAccountStore
+	accounts   map[uuid.UUID]*models.UserSecurityStatus
+	rateLimits map[uuid.UUID]int64
+	events     []*models.AuthAuditLog
+}
+
+func (m *memoryUserAccountStore) GetUserSecurityStatus(ctx context.Context, exec store.Querier, userID uuid.UUID) (*models.UserSecurityStatus, error) {
+	account, ok := m.accounts[userID]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return account, nil
+}
+
+func (m *memoryUserAccountStore) UnlockUserAccount(ctx context.Context, exec store.Querier, userID uuid.UUID, unlockedAt time.Time) (*models.UserAccountUnlock, error) {
+	account, ok := m.accounts[userID]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	result := &models.UserAccountUnlock{
+		UserID:              userID,
+		WasLocked:           account.IsLocked,
+		FailedLoginAttempts: account.FailedLoginAttempts,
+		RateLimitsCleared:   m.rateLimits[userID],
+		UnlockedAt:          unlockedAt,
+	}
+	account.IsLocked, account.LockedUntil, account.FailedLoginAttempts = false, nil, 0
+	delete(m.rateLimits, userID)
+	return result, nil
+}
+
+func (m *memoryUserAccountStore) ListUserAccounts(ctx context.Context, exec store.Querier, filter store.ListUserAccountsFilter) ([]*models.UserAccount, error) {
+	var matched []*models.UserAccount
+	for _, account := range m.accounts {
+		if (filter.IsActive == nil || *filter.IsActive == account.IsActive) && (filter.IsLocked == nil || *filter.IsLocked == account.IsLocked) {
+			matched = append(matched, &models.UserAccount{ID: account.UserID, Email: account.Email, IsActive: account.IsActive, IsLocked: account.IsLocked})
+		}
+	}
+	return matched, nil
+}
+
+func (m *memoryUserAccountStore) CountUserAccounts(ctx context.Context, exec store.Querier, filter store.ListUserAccountsFilter) (int64, error) {
+	matched, _ := m.ListUserAccounts(ctx, exec, filter)
+	return int64(len(matched)), nil
+}
+
+func (m *memoryUserAccountStore) SetUserActive(ctx context.Context, exec store.Querier, userID uuid.UUID, active bool, changedAt time.Time) (bool, error) {
+	account, ok := m.accounts[userID]
+	if !ok {
+		return false, store.ErrNotFound
+	}
+	changed := account.IsActive != active
+	account.IsActive = active
+	return changed, nil
+}
+
+func (m *memoryUserAccountStore) CreateAuthAuditLog(ctx context.Context, exec store.Querier, entry *models.AuthAuditLog) error {
+	m.events = append(m.events, entry)
+	return nil
+}
+
+func newUserAccountRouter(accountStore *memoryUserAccountStore, sessionService *services.SessionService, securityContext *models.SecurityContext) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	users := router.Group("/admin/users", func(c *gin.Context) {
+		c.Set("security_context", securityContext)
+		c.Next()
+	})
+	h := NewUserAccountAPIHandler(services.NewUserAccountService(nil, accountStore, sessionService))
+	h.RegisterUserAccountRoutes(users, &middleware.AuthMiddleware{})
+	return router
+}
+
+func TestUserAccountRoutes_RequireSystemUsers(t *testing.T) {
+	userID := uuid.New()
+	accountStore := &memoryUserAccountStore{accounts: map[uuid.UUID]*models.UserSecurityStatus{
+		userID: {UserID: userID, IsLocked: true, FailedLoginAttempts: 5},
+	}}
+	router := newUserAccountRouter(accountStore, nil, &models.SecurityContext{
+		UserID:      uuid.New(),
+		Permissions: []string{"admin:users", "campaigns:read"},
+	})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/admin/users", nil),
+		httptest.NewRequest(http.MethodGet, "/admin/users/"+userID.String()+"/security", nil),
+		httptest.NewRequest(http.MethodPost, "/admin/users/"+userID.String()+"/unlock", nil),
+		httptest.NewRequest(http.MethodPost, "/admin/users/"+userID.String()+"/disable", nil),
+		httptest.NewRequest(http.MethodPost, "/admin/users/"+userID.String()+"/enable", nil),
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code, req.URL.Path)
+	}
+	assert.True(t, accountStore.accounts[userID].IsLocked)
+	assert.Empty(t, accountStore.events)
+}
+
+func TestUnlockUser_ClearsLockAndWritesAuditEvent(t *testing.T) {
+	userID, adminID := uuid.New(), uuid.New()
+	lockedUntil := time.Now().Add(20 * time.Minute)
+	accountStore := &memoryUserAccountStore{
+		accounts: map[uuid.UUID]*models.UserSecurityStatus{
+			userID: {UserID: userID, IsActive: true, IsLocked: true, LockedUntil: &lockedUntil, FailedLoginAttempts: 5},
+		},
+		rateLimits: map[uuid.UUID]int64{userID: 2},
+	}
+	router := newUserAccountRouter(accountStore, nil, &models.SecurityContext{UserID: adminID, Permissions: []string{"system:users"}})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/users/"+userID.String()+"/unlock", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data models.UserAccountUnlock `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Data.WasLocked)
+	assert.Equal(t, 5, resp.Data.FailedLoginAttempts)
+	assert.EqualValues(t, 2, resp.Data.RateLimitsCleared)
+
+	require.Len(t, accountStore.events, 1)
+	event := accountStore.events[0]
+	assert.Equal(t, "account_unlocked", event.EventType)
+	assert.Equal(t, userID, *event.UserID)
+	var details map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(*event.Details), &details))
+	assert.Equal(t, adminID.String(), details["changedBy"])
+	assert.Equal(t, true, details["wasLocked"])
+	assert.EqualValues(t, 2, details["rateLimitsCleared"])
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/users/"+userID.String()+"/security", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var status struct {
+		Data models.UserSecurityStatus `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.False(t, status.Data.IsLocked)
+	assert.Nil(t, status.Data.LockedUntil)
+	assert.Zero(t, status.Data.FailedLoginAttempts)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/users/"+uuid.NewString()+"/unlock", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Len(t, accountStore.events, 1)
+}
+
+func TestListUsers_FiltersByState(t *testing.T) {
+	active, locked, disabled := uuid.New(), uuid.New(), uuid.New()
+	accountStore := &memoryUserAccountStore{accounts: map[uuid.UUID]*models.UserSecurityStatus{
+		active:   {UserID: active, IsActive: true},
+		locked:   {UserID: locked, IsActive: true, IsLocked: true},
+		disabled: {UserID: disabled},
+	}}
+	router := newUserAccountRouter(accountStore, nil, &models.SecurityContext{UserID: uuid.New(), Permissions: []string{"system:users"}})
+
+	list := func(query string) (int, ListUsersResponse) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/users"+query, nil))
+		var resp struct {
+			Data ListUsersResponse `json:"data"`
+		}
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w.Code, resp.Data
+	}
+
+	code, page := list("")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 3, page.Pagination.Total)
+	code, page = list("?isActive=true&isLocked=false")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, page.Users, 1)
+	assert.Equal(t, active, page.Users[0].ID)
+	code, page = list("?isLocked=true")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, page.Users, 1)
+	assert.Equal(t, locked, page.Users[0].ID)
+	code, _ = list("?isActive=maybe")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestDisableAndEnableUser(t *testing.T) {
+	userID, adminID := uuid.New(), uuid.New()
+	accountStore := &memoryUserAccountStore{accounts: map[uuid.UUID]*models.UserSecurityStatus{
+		userID:  {UserID: userID, IsActive: true},
+		adminID: {UserID: adminID, IsActive: true},
+	}}
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	mock.ExpectQuery("FROM auth.sessions").WillReturnError(assert.AnError) // startup cache warm-up
+	sessionService, err := services.NewSessionService(sqlx.NewDb(db, "postgres"), services.DefaultSessionConfig(), &recordingAuditLogStore{})
+	require.NoError(t, err)
+	router := newUserAccountRouter(accountStore, sessionService, &models.SecurityContext{UserID: adminID, Permissions: []string{"system:users"}})
+
+	post := func(id uuid.UUID, action string) (int, models.UserSecurityStatus) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/users/"+id.String()+"/"+action, nil))
+		var resp struct {
+			Data models.UserSecurityStatus `json:"data"`
+		}
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w.Code, resp.Data
+	}
+
+	// Disabling ends the user's sessions at once
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE auth.sessions SET is_active = false WHERE user_id = $1 AND is_active = true")).
+		WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 2))
+	code, status := post(userID, "disable")
+	require.Equal(t, http.StatusOK, code)
+	assert.False(t, status.IsActive)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Len(t, accountStore.events, 1)
+	assert.Equal(t, "account_disabled", accountStore.events[0].EventType)
+	assert.Equal(t, userID, *accountStore.events[0].UserID)
+
+	// Disabling again changes nothing, so it is not audited, but still ends any sessions
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE auth.sessions SET is_active = false")).
+		WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 0))
+	code, _ = post(userID, "disable")
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, accountStore.events, 1)
+
+	code, status = post(userID, "enable")
+	require.Equal(t, http.StatusOK, code)
+	assert.True(t, status.IsActive)
+	require.Len(t, accountStore.events, 2)
+	assert.Equal(t, "account_enabled", accountStore.events[1].EventType)
+	var details map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(*accountStore.events[1].Details), &details))
+	assert.Equal(t, adminID.String(), details["changedBy"])
+
+	code, _ = post(adminID, "disable")
+	assert.Equal(t, http.StatusBadRequest, code, "admins cannot lock themselves out")
+	assert.True(t, accountStore.accounts[adminID].IsActive)
+	code, _ = post(uuid.New(), "enable")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Len(t, accountStore.events, 2)
+}
@@ -0,0 +1,67 @@
+// File: backend/internal/api/user_agent.go
+package api
+
+import (
+	"strings"
+
+	"github.com/fntelecomllc/studio/backend/internal/services"
+)
+
+// userAgentInfo is the browser, operating system and kind of device a User-Agent header names
+type userAgentInfo struct {
+	Browser    string
+	OS         string
+	DeviceType string
+}
+
+// parseUserAgent recognises the common browsers and platforms; anything else is "unknown"
+func parseUserAgent(userAgent string) userAgentInfo {
+	return userAgentInfo{
+		Browser:    extractBrowserInfo(userAgent),
+		OS:         extractOSInfo(userAgent),
+		DeviceType: extractDeviceType(userAgent),
+	}
+}
+
+// extractBrowserInfo extracts basic browser information for fingerprint comparison
+func extractBrowserInfo(userAgent string) string {
+	return services.ExtractBrowserInfo(userAgent)
+}
+
+// extractOSInfo names the operating system of a User-Agent
+func extractOSInfo(userAgent string) string {
+	userAgent = strings.ToLower(userAgent)
+
+	// iOS and Android user agents also mention Mac OS X and Linux
+	switch {
+	case strings.Contains(userAgent, "iphone") || strings.Contains(userAgent, "ipad") || strings.Contains(userAgent, "ipod"):
+		return "ios"
+	case strings.Contains(userAgent, "android"):
+		return "android"
+	case strings.Contains(userAgent, "windows"):
+		return "windows"
+	case strings.Contains(userAgent, "mac os x") || strings.Contains(userAgent, "macintosh"):
+		return "macos"
+	case strings.Contains(userAgent, "linux") || strings.Contains(userAgent, "x11"):
+		return "linux"
+	}
+	return "unknown"
+}
+
+// extractDeviceType tells phones and tablets from desktops
+func extractDeviceType(userAgent string) string {
+	lower := strings.ToLower(userAgent)
+	switch {
+	case strings.Contains(lower, "ipad") || strings.Contains(lower, "tablet"):
+		return "tablet"
+	case strings.Contains(lower, "android") && !strings.Contains(lower, "mobile"):
+		return "tablet"
+	case strings.Contains(lower, "mobi") || strings.Contains(lower, "iphone") || strings.Contains(lower, "ipod"):
+		return "mobile"
+	}
+	switch extractOSInfo(userAgent) {
+	case "windows", "macos", "linux":
+		return "desktop"
+	}
+	return "unknown"
+}
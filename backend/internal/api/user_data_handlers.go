@@ -0,0 +1,137 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UserDataAPIHandler exposes data-subject access and erasure requests for user accounts.
+type UserDataAPIHandler struct {
+	userDataService *services.UserDataService
+	auditLogStore   store.AuditLogStore
+}
+
+// NewUserDataAPIHandler creates a new handler for user data requests.
+func NewUserDataAPIHandler(userDataService *services.UserDataService, auditLogStore store.AuditLogStore) *UserDataAPIHandler {
+	return &UserDataAPIHandler{userDataService: userDataService, auditLogStore: auditLogStore}
+}
+
+// ExportUserDataGin streams everything held about a user as a downloadable JSON bundle.
+// @Summary Export a user's data
+// @Description Download the user's profile, audit events, sessions and owned campaigns as one JSON document. Other users' identities are redacted from the audit events.
+// @Tags Admin
+// @Produce json
+// @Param userId path string true "User ID"
+// @Param sections query string false "Comma-separated sections to include: profile, auditEvents, sessions, campaigns (default all)"
+// @Success 200 {file} file "User data export"
+// @Failure 400 {object} APIResponse "Invalid user ID or section"
+// @Failure 404 {object} APIResponse "User not found"
+// @Failure 500 {object} APIResponse "Internal server error"
+// @Security SessionAuth
+// @Router /admin/users/{userId}/data-export [get]
+func (h *UserDataAPIHandler) ExportUserDataGin(c *gin.Context) {
+	userID, ok := parseUserDataUserID(c)
+	if !ok {
+		return
+	}
+	sections, err := services.ParseUserDataSections(c.Query("sections"))
+	if err != nil {
+		respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation, "Invalid export sections",
+			[]ErrorDetail{{Field: "sections", Code: ErrorCodeValidation, Message: err.Error()}})
+		return
+	}
+
+	// Headers are only committed once the first byte is written, so a missing user still gets a 404
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="user-%s-data-export.json"`, userID))
+	c.Header("Cache-Control", "no-store")
+	exportErr := h.userDataService.Export(c.Request.Context(), userID, sections, c.Writer)
+	if errors.Is(exportErr, store.ErrNotFound) && !c.Writer.Written() {
+		c.Header("Content-Disposition", "")
+		respondWithDetailedErrorGin(c, http.StatusNotFound, ErrorCodeNotFound, "User not found", nil)
+		return
+	}
+	if exportErr != nil {
+		log.Printf("Error exporting data for user %s: %v", userID, exportErr)
+		if !c.Writer.Written() {
+			c.Header("Content-Disposition", "")
+			respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeDatabaseError, "Failed to export user data", nil)
+			return
+		}
+		// The bundle is already partly sent; abort so the client sees a truncated download
+		c.Abort()
+	}
+
+	h.audit(c, "Export User Data", userID, map[string]interface{}{"sections": sections, "completed": exportErr == nil})
+}
+
+// EraseUserDataGin anonymizes a user's account and removes their personal data.
+// @Summary Erase a user's personal data
+// @Description Right-to-erasure: anonymize the account, delete its sessions and reset tokens and scrub addresses and details from audit records. The account row and campaigns are kept so aggregates stay consistent.
+// @Tags Admin
+// @Produce json
+// @Param userId path string true "User ID"
+// @Success 200 {object} models.UserDataErasure "What the erasure changed"
+// @Failure 400 {object} APIResponse "Invalid user ID"
+// @Failure 404 {object} APIResponse "User not found"
+// @Failure 500 {object} APIResponse "Internal server error"
+// @Security SessionAuth
+// @Router /admin/users/{userId}/personal-data [delete]
+func (h *UserDataAPIHandler) EraseUserDataGin(c *gin.Context) {
+	userID, ok := parseUserDataUserID(c)
+	if !ok {
+		return
+	}
+	result, err := h.userDataService.Erase(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithDetailedErrorGin(c, http.StatusNotFound, ErrorCodeNotFound, "User not found", nil)
+			return
+		}
+		log.Printf("Error erasing data for user %s: %v", userID, err)
+		respondWithDetailedErrorGin(c, http.StatusInternalServerError, ErrorCodeDatabaseError, "Failed to erase user data", nil)
+		return
+	}
+
+	h.audit(c, "Erase User Data", userID, result)
+	respondWithJSONGin(c, http.StatusOK, result)
+}
+
+func parseUserDataUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		respondWithDetailedErrorGin(c, http.StatusBadRequest, ErrorCodeValidation, "Invalid user ID format",
+			[]ErrorDetail{{Field: "userId", Code: ErrorCodeValidation, Message: "must be a valid UUID"}})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// audit records who ran a data request against which account
+func (h *UserDataAPIHandler) audit(c *gin.Context, action string, subjectID uuid.UUID, details interface{}) {
+	encoded, _ := json.Marshal(details)
+	auditLog := &models.AuditLog{
+		Action:     action,
+		EntityType: sql.NullString{String: "User", Valid: true},
+		EntityID:   uuid.NullUUID{UUID: subjectID, Valid: true},
+		Details:    models.JSONRawMessagePtr(encoded),
+		ClientIP:   sql.NullString{String: c.ClientIP(), Valid: c.ClientIP() != ""},
+		UserAgent:  sql.NullString{String: c.Request.UserAgent(), Valid: c.Request.UserAgent() != ""},
+	}
+	if userID, ok := currentUserID(c); ok {
+		auditLog.UserID = uuid.NullUUID{UUID: userID, Valid: true}
+	}
+	if err := h.auditLogStore.CreateAuditLog(c.Request.Context(), nil, auditLog); err != nil {
+		log.Printf("Error creating audit log for %s of user %s: %v", action, subjectID, err)
+	}
+}
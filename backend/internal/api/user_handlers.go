@@ -3,14 +3,14 @@ package api
 
 import (
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
-	"strconv"
 	"time"
 
+	"github.com/fntelecomllc/studio/backend/internal/config"
 	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
 	"github.com/fntelecomllc/studio/backend/internal/store"
 
 	"github.com/gin-gonic/gin"
@@ -78,122 +78,12 @@ func toUserResponse(u *models.User) UserResponse {
 
 // --- Gin Handlers for User Management ---
 
-// ListUsersGin handles GET /api/v2/admin/users
-func (h *APIHandler) ListUsersGin(c *gin.Context) {
-	log.Printf("[ListUsersGin] Listing users")
-
-	// Get pagination parameters
-	page := 1
-	limit := 10
-
-	if pageStr := c.Query("page"); pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
-		}
-	}
-
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
-	}
-
-	offset := (page - 1) * limit
-
-	// Query users from database
-	query := `
-		SELECT u.id, u.email, u.email_verified, u.first_name, u.last_name, u.avatar_url,
-		       u.is_active, u.is_locked, u.failed_login_attempts, u.last_login_at,
-		       u.mfa_enabled, u.created_at, u.updated_at
-		FROM auth.users u
-		ORDER BY u.created_at DESC
-		LIMIT $1 OFFSET $2`
-
-	rows, err := h.DB.Query(query, limit, offset)
-	if err != nil {
-		log.Printf("[ListUsersGin] Error querying users: %v", err)
-		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to fetch users")
-		return
-	}
-	defer rows.Close()
-
-	var users []models.User
-	for rows.Next() {
-		var user models.User
-		var lastLoginAt sql.NullTime
-
-		err := rows.Scan(
-			&user.ID, &user.Email, &user.EmailVerified, &user.FirstName, &user.LastName,
-			&user.AvatarURL, &user.IsActive, &user.IsLocked, &user.FailedLoginAttempts,
-			&lastLoginAt, &user.MFAEnabled, &user.CreatedAt, &user.UpdatedAt,
-		)
-		if err != nil {
-			log.Printf("[ListUsersGin] Error scanning user: %v", err)
-			respondWithErrorGin(c, http.StatusInternalServerError, "Failed to scan user data")
-			return
-		}
-
-		if lastLoginAt.Valid {
-			user.LastLoginAt = &lastLoginAt.Time
-		}
-
-		// Get user roles
-		rolesQuery := `
-			SELECT r.name
-			FROM auth.roles r
-			JOIN auth.user_roles ur ON r.id = ur.role_id
-			WHERE ur.user_id = $1 AND (ur.expires_at IS NULL OR ur.expires_at > NOW())`
-		
-		var roleNames []string
-		err = h.DB.Select(&roleNames, rolesQuery, user.ID)
-		if err != nil {
-			log.Printf("[ListUsersGin] Error fetching roles for user %s: %v", user.ID, err)
-			// Continue without roles rather than failing the entire request
-		} else {
-			user.Roles = make([]models.Role, len(roleNames))
-			for i, name := range roleNames {
-				user.Roles[i] = models.Role{Name: name}
-			}
-		}
-
-		users = append(users, user)
-	}
-
-	// Get total count
-	var totalCount int
-	err = h.DB.Get(&totalCount, "SELECT COUNT(*) FROM auth.users")
-	if err != nil {
-		log.Printf("[ListUsersGin] Error getting user count: %v", err)
-		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to get user count")
-		return
-	}
-
-	// Convert to response format
-	userResponses := make([]UserResponse, len(users))
-	for i, user := range users {
-		userResponses[i] = toUserResponse(&user)
-	}
-
-	response := ListUsersResponse{
-		Users: userResponses,
-		Pagination: PaginationResponse{
-			Page:       page,
-			Limit:      limit,
-			Total:      totalCount,
-			TotalPages: (totalCount + limit - 1) / limit,
-		},
-	}
-
-	log.Printf("[ListUsersGin] Successfully listed %d users", len(users))
-	respondWithJSONGin(c, http.StatusOK, response)
-}
-
 // CreateUserGin handles POST /api/v2/admin/users
 func (h *APIHandler) CreateUserGin(c *gin.Context) {
 	log.Printf("[CreateUserGin] Creating new user")
 
 	var req models.CreateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindJSON(c, &req); err != nil {
 		log.Printf("[CreateUserGin] Error binding JSON: %v", err)
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
 		return
@@ -205,10 +95,23 @@ func (h *APIHandler) CreateUserGin(c *gin.Context) {
 		return
 	}
 
+	policy := h.PasswordPolicy
+	if policy == nil {
+		policy = services.DefaultPasswordPolicy()
+	}
+	if err := policy.Validate(req.Password); err != nil {
+		respondWithPasswordPolicyError(c, "password", err)
+		return
+	}
+
 	// Hash the password with pgcrypto-compatible format
+	password, pepperVersion := req.Password, config.UnpepperedPasswordPepperVersion
+	if h.PasswordPepper != nil {
+		password, pepperVersion = h.PasswordPepper.Apply(req.Password), h.PasswordPepper.Version()
+	}
 	passwordQuery := `SELECT crypt($1, gen_salt('bf')) AS password_hash`
 	var passwordHash string
-	err := h.DB.Get(&passwordHash, passwordQuery, req.Password)
+	err := h.DB.Get(&passwordHash, passwordQuery, password)
 	if err != nil {
 		log.Printf("[CreateUserGin] Error hashing password: %v", err)
 		respondWithErrorGin(c, http.StatusInternalServerError, "Failed to process password")
@@ -252,10 +155,10 @@ func (h *APIHandler) CreateUserGin(c *gin.Context) {
 				first_name, last_name, is_active, is_locked, failed_login_attempts,
 				password_changed_at, must_change_password, mfa_enabled, created_at, updated_at
 			) VALUES (
-				$1, $2, false, $3, 1, $4, $5, true, false, 0, $6, false, false, $6, $6
+				$1, $2, false, $3, $7, $4, $5, true, false, 0, $6, false, false, $6, $6
 			)`
 
-		_, err = sqlTx.Exec(createQuery, userID, req.Email, passwordHash, req.FirstName, req.LastName, now)
+		_, err = sqlTx.Exec(createQuery, userID, req.Email, passwordHash, req.FirstName, req.LastName, now, pepperVersion)
 		if err != nil {
 			opErr = err
 			if err.Error() == `pq: duplicate key value violates unique constraint "users_email_key"` {
@@ -282,12 +185,13 @@ func (h *APIHandler) CreateUserGin(c *gin.Context) {
 		}
 
 		// Create audit log
+		details, _ := models.NewAuditDetails(map[string]string{"email": req.Email})
 		auditLog := &models.AuditLog{
 			UserID:     uuid.NullUUID{}, // TODO: Get from security context
 			Action:     "Create User",
 			EntityType: sql.NullString{String: "User", Valid: true},
 			EntityID:   uuid.NullUUID{UUID: userID, Valid: true},
-			Details:    models.JSONRawMessagePtr(json.RawMessage(fmt.Sprintf(`{"email":"%s"}`, req.Email))),
+			Details:    details,
 		}
 		if err := h.AuditLogStore.CreateAuditLog(c.Request.Context(), sqlTx, auditLog); err != nil {
 			opErr = err
@@ -392,7 +296,7 @@ func (h *APIHandler) UpdateUserGin(c *gin.Context) {
 	log.Printf("[UpdateUserGin] Updating user %s", userID)
 
 	var req models.UpdateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindJSON(c, &req); err != nil {
 		respondWithErrorGin(c, http.StatusBadRequest, "Invalid request payload: "+err.Error())
 		return
 	}
@@ -426,6 +330,9 @@ func (h *APIHandler) UpdateUserGin(c *gin.Context) {
 					log.Printf("[UpdateUserGin] Error committing transaction: %v", commitErr)
 				} else {
 					log.Printf("[UpdateUserGin] Transaction committed")
+					if len(req.RoleIDs) > 0 {
+						h.PermissionCache.Invalidate(userID)
+					}
 				}
 			}
 		}()
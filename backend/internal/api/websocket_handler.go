@@ -298,8 +298,8 @@ func (h *WebSocketHandler) validateSessionFingerprint(sessionData *services.Sess
 	// Allow some flexibility in user agent (browsers can update minor versions)
 	if sessionData.UserAgent != "" && userAgent != "" {
 		// Extract major browser info for comparison
-		sessionBrowser := h.extractBrowserInfo(sessionData.UserAgent)
-		currentBrowser := h.extractBrowserInfo(userAgent)
+		sessionBrowser := extractBrowserInfo(sessionData.UserAgent)
+		currentBrowser := extractBrowserInfo(userAgent)
 		
 		if sessionBrowser != currentBrowser {
 			log.Printf("Session fingerprint mismatch: User agent browser changed from %s to %s", sessionBrowser, currentBrowser)
@@ -309,23 +309,3 @@ func (h *WebSocketHandler) validateSessionFingerprint(sessionData *services.Sess
 
 	return true
 }
-
-// extractBrowserInfo extracts basic browser information for fingerprint comparison
-func (h *WebSocketHandler) extractBrowserInfo(userAgent string) string {
-	userAgent = strings.ToLower(userAgent)
-	
-	// Simple browser detection for fingerprint validation
-	if strings.Contains(userAgent, "chrome") && !strings.Contains(userAgent, "edge") && !strings.Contains(userAgent, "opr") {
-		return "chrome"
-	} else if strings.Contains(userAgent, "firefox") {
-		return "firefox"
-	} else if strings.Contains(userAgent, "safari") && !strings.Contains(userAgent, "chrome") {
-		return "safari"
-	} else if strings.Contains(userAgent, "edge") {
-		return "edge"
-	} else if strings.Contains(userAgent, "opr") || strings.Contains(userAgent, "opera") {
-		return "opera"
-	}
-	
-	return "unknown"
-}
@@ -0,0 +1,161 @@
+// Package circuitbreaker short-circuits outbound requests to hosts that keep failing.
+//
+// Each host has its own circuit. After FailureThreshold consecutive failures the circuit
+// opens and requests to the host are refused for Cooldown. The next request after the
+// cooldown is let through as a probe (half-open): a success closes the circuit, a failure
+// opens it again for another cooldown.
+package circuitbreaker
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+)
+
+// StatusCircuitOpen is the result status recorded for requests refused by an open circuit
+const StatusCircuitOpen = "circuit_open"
+
+// State is the state of a single host's circuit
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half_open"
+)
+
+// Config controls when circuits open and how long they stay open
+type Config struct {
+	FailureThreshold int           // Consecutive failures that open a circuit; zero or negative disables the breaker
+	Cooldown         time.Duration // Time an open circuit refuses requests before allowing a probe
+}
+
+type hostCircuit struct {
+	state               State
+	consecutiveFailures int
+	openedAt            time.Time
+	probeInFlight       bool
+	probeStartedAt      time.Time
+}
+
+// Breaker tracks a circuit per host. It is safe for concurrent use.
+type Breaker struct {
+	mu    sync.Mutex
+	cfg   Config
+	hosts map[string]*hostCircuit
+	now   func() time.Time
+}
+
+// NewFromConfig creates a Breaker from the application's circuit breaker settings
+func NewFromConfig(cfg config.CircuitBreakerConfig) *Breaker {
+	return New(Config{
+		FailureThreshold: cfg.FailureThreshold,
+		Cooldown:         time.Duration(cfg.CooldownSeconds) * time.Second,
+	})
+}
+
+// New creates a Breaker with the given configuration
+func New(cfg Config) *Breaker {
+	return &Breaker{
+		cfg:   cfg,
+		hosts: make(map[string]*hostCircuit),
+		now:   time.Now,
+	}
+}
+
+// Enabled reports whether the breaker ever opens circuits
+func (b *Breaker) Enabled() bool {
+	return b != nil && b.cfg.FailureThreshold > 0
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
+
+// Allow reports whether a request to host may proceed. Once an open circuit's cooldown has
+// elapsed a single probe request is allowed and the circuit becomes half-open until
+// RecordSuccess or RecordFailure is called for the probe.
+func (b *Breaker) Allow(host string) bool {
+	if !b.Enabled() {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	circuit, ok := b.hosts[normalizeHost(host)]
+	if !ok {
+		return true
+	}
+	switch circuit.state {
+	case StateOpen:
+		if b.now().Sub(circuit.openedAt) < b.cfg.Cooldown {
+			return false
+		}
+		circuit.state = StateHalfOpen
+		circuit.probeInFlight = true
+		circuit.probeStartedAt = b.now()
+		return true
+	case StateHalfOpen:
+		// A probe whose outcome was never recorded (e.g. a cancelled request) stops blocking after a cooldown
+		if circuit.probeInFlight && b.now().Sub(circuit.probeStartedAt) < b.cfg.Cooldown {
+			return false
+		}
+		circuit.probeInFlight = true
+		circuit.probeStartedAt = b.now()
+		return true
+	}
+	return true
+}
+
+// RecordSuccess closes the host's circuit and clears its failure count
+func (b *Breaker) RecordSuccess(host string) {
+	if !b.Enabled() {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.hosts, normalizeHost(host))
+}
+
+// RecordFailure counts a failed request to host, opening its circuit at the failure
+// threshold or immediately when a half-open probe fails.
+func (b *Breaker) RecordFailure(host string) {
+	if !b.Enabled() {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := normalizeHost(host)
+	circuit, ok := b.hosts[key]
+	if !ok {
+		circuit = &hostCircuit{state: StateClosed}
+		b.hosts[key] = circuit
+	}
+	circuit.consecutiveFailures++
+	circuit.probeInFlight = false
+	if circuit.state == StateHalfOpen || circuit.consecutiveFailures >= b.cfg.FailureThreshold {
+		circuit.state = StateOpen
+		circuit.openedAt = b.now()
+	}
+}
+
+// State returns the current state of host's circuit
+func (b *Breaker) State(host string) State {
+	if !b.Enabled() {
+		return StateClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	circuit, ok := b.hosts[normalizeHost(host)]
+	if !ok {
+		return StateClosed
+	}
+	if circuit.state == StateOpen && b.now().Sub(circuit.openedAt) >= b.cfg.Cooldown {
+		return StateHalfOpen
+	}
+	return circuit.state
+}
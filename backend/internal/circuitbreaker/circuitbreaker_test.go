@@ -0,0 +1,106 @@
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestBreaker returns a breaker whose clock only moves when advance is called
+func newTestBreaker(threshold int, cooldown time.Duration) (*Breaker, func(time.Duration)) {
+	b := New(Config{FailureThreshold: threshold, Cooldown: cooldown})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b.now = func() time.Time { return now }
+	return b, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		require.True(t, b.Allow("slow.example.com"))
+		b.RecordFailure("slow.example.com")
+	}
+	assert.Equal(t, StateClosed, b.State("slow.example.com"))
+
+	b.RecordFailure("slow.example.com")
+	assert.Equal(t, StateOpen, b.State("slow.example.com"))
+	assert.False(t, b.Allow("slow.example.com"))
+	assert.False(t, b.Allow("SLOW.example.com."), "host keys are normalized")
+
+	// Other hosts are unaffected
+	assert.True(t, b.Allow("fast.example.com"))
+}
+
+func TestBreaker_SuccessResetsFailureCount(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Minute)
+
+	b.RecordFailure("flaky.example.com")
+	b.RecordFailure("flaky.example.com")
+	b.RecordSuccess("flaky.example.com")
+	b.RecordFailure("flaky.example.com")
+	b.RecordFailure("flaky.example.com")
+	assert.Equal(t, StateClosed, b.State("flaky.example.com"))
+	assert.True(t, b.Allow("flaky.example.com"))
+}
+
+func TestBreaker_HalfOpenProbeRecovers(t *testing.T) {
+	b, advance := newTestBreaker(2, time.Minute)
+	b.RecordFailure("down.example.com")
+	b.RecordFailure("down.example.com")
+	require.False(t, b.Allow("down.example.com"))
+
+	advance(30 * time.Second)
+	assert.False(t, b.Allow("down.example.com"), "circuit stays open during the cooldown")
+
+	advance(31 * time.Second)
+	assert.Equal(t, StateHalfOpen, b.State("down.example.com"))
+	assert.True(t, b.Allow("down.example.com"), "one probe is allowed after the cooldown")
+	assert.False(t, b.Allow("down.example.com"), "only one probe runs at a time")
+
+	b.RecordSuccess("down.example.com")
+	assert.Equal(t, StateClosed, b.State("down.example.com"))
+	assert.True(t, b.Allow("down.example.com"))
+	assert.True(t, b.Allow("down.example.com"))
+}
+
+func TestBreaker_FailedProbeReopens(t *testing.T) {
+	b, advance := newTestBreaker(2, time.Minute)
+	b.RecordFailure("down.example.com")
+	b.RecordFailure("down.example.com")
+
+	advance(time.Minute)
+	require.True(t, b.Allow("down.example.com"))
+	b.RecordFailure("down.example.com")
+	assert.Equal(t, StateOpen, b.State("down.example.com"))
+	assert.False(t, b.Allow("down.example.com"))
+
+	// The reopened circuit waits a full cooldown before the next probe
+	advance(59 * time.Second)
+	assert.False(t, b.Allow("down.example.com"))
+	advance(time.Second)
+	assert.True(t, b.Allow("down.example.com"))
+}
+
+func TestBreaker_UnrecordedProbeExpires(t *testing.T) {
+	b, advance := newTestBreaker(1, time.Minute)
+	b.RecordFailure("down.example.com")
+
+	advance(time.Minute)
+	require.True(t, b.Allow("down.example.com"))
+	assert.False(t, b.Allow("down.example.com"))
+
+	advance(time.Minute)
+	assert.True(t, b.Allow("down.example.com"), "a probe with no recorded outcome stops blocking after a cooldown")
+}
+
+func TestBreaker_DisabledNeverOpens(t *testing.T) {
+	b, _ := newTestBreaker(0, time.Minute)
+	for i := 0; i < 100; i++ {
+		b.RecordFailure("down.example.com")
+	}
+	assert.True(t, b.Allow("down.example.com"))
+	assert.Equal(t, StateClosed, b.State("down.example.com"))
+}
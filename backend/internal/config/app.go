@@ -14,16 +14,39 @@ import (
 // AppConfig is the main application configuration structure.
 // It aggregates all other configuration parts.
 type AppConfig struct {
-	Server         ServerConfig        `json:"server"`
-	Worker         WorkerConfig        `json:"worker"` // Added WorkerConfig
-	DNSValidator   DNSValidatorConfig  `json:"dnsValidator"`
-	HTTPValidator  HTTPValidatorConfig `json:"httpValidator"`
-	Logging        LoggingConfig       `json:"logging"`
-	DNSPersonas    []DNSPersona        `json:"dnsPersonas"`
-	HTTPPersonas   []HTTPPersona       `json:"httpPersonas"`
-	Proxies        []ProxyConfigEntry  `json:"proxies"`
-	KeywordSets    []KeywordSet        `json:"keywordSets"`
-	loadedFromPath string
+	Server            ServerConfig            `json:"server"`
+	Worker            WorkerConfig            `json:"worker"` // Added WorkerConfig
+	DNSValidator      DNSValidatorConfig      `json:"dnsValidator"`
+	HTTPValidator     HTTPValidatorConfig     `json:"httpValidator"`
+	Logging           LoggingConfig           `json:"logging"`
+	Webhooks          WebhookConfig           `json:"webhooks"`
+	ProxyHealth       ProxyHealthConfig       `json:"proxyHealth"`
+	CircuitBreaker    CircuitBreakerConfig    `json:"circuitBreaker"`
+	OutboundRateLimit OutboundRateLimitConfig `json:"outboundRateLimit"`
+	CampaignSafety    CampaignSafetyConfig    `json:"campaignSafety"`
+	Startup           StartupConfig           `json:"startup"`
+	ResourceAccess    ResourceAccessConfig    `json:"resourceAccess"`
+	TLDList           TLDListConfig           `json:"tldList"`
+	Audit             AuditConfig             `json:"audit"`
+	PasswordHash      PasswordHashConfig      `json:"passwordHash"`
+	PasswordPolicy    PasswordPolicyConfig    `json:"passwordPolicy"`
+	PersonaTests      PersonaTestConfig       `json:"personaTests"`
+	CampaignRetry     CampaignRetryConfig     `json:"campaignRetry"`
+	CampaignPromotion CampaignPromotionConfig `json:"campaignPromotion"`
+	CampaignSchedules CampaignScheduleConfig  `json:"campaignSchedules"`
+	CampaignUpdates   CampaignUpdateConfig    `json:"campaignUpdates"`
+	CampaignStatus    CampaignStatusConfig    `json:"campaignStatus"`
+	LoginRates        LoginRateConfig         `json:"loginRates"`
+	LoginRateLimit    LoginRateLimitConfig    `json:"loginRateLimit"`
+	Compression       CompressionConfig       `json:"compression"`
+	RouteRateLimit    RouteRateLimitConfig    `json:"routeRateLimit"`
+	Reclassification  ReclassificationConfig  `json:"reclassification"`
+	Tracing           TracingConfig           `json:"tracing"`
+	DNSPersonas       []DNSPersona            `json:"dnsPersonas"`
+	HTTPPersonas      []HTTPPersona           `json:"httpPersonas"`
+	Proxies           []ProxyConfigEntry      `json:"proxies"`
+	KeywordSets       []KeywordSet            `json:"keywordSets"`
+	loadedFromPath    string
 }
 
 // GetLoadedFromPath returns the file path from which the main config was loaded.
@@ -166,11 +189,34 @@ func SaveAppConfig(cfg *AppConfig) error {
 // ConvertJSONToAppConfig converts the JSON structure (AppConfigJSON) to the internal AppConfig model.
 func ConvertJSONToAppConfig(jsonCfg AppConfigJSON) *AppConfig {
 	appCfg := &AppConfig{
-		Server:        jsonCfg.Server,
-		Worker:        ConvertJSONToWorkerConfig(jsonCfg.Worker), // Convert WorkerConfig
-		DNSValidator:  ConvertJSONToDNSConfig(jsonCfg.DNSValidator),
-		HTTPValidator: ConvertJSONToHTTPConfig(jsonCfg.HTTPValidator),
-		Logging:       jsonCfg.Logging,
+		Server:            jsonCfg.Server,
+		Worker:            ConvertJSONToWorkerConfig(jsonCfg.Worker), // Convert WorkerConfig
+		DNSValidator:      ConvertJSONToDNSConfig(jsonCfg.DNSValidator),
+		HTTPValidator:     ConvertJSONToHTTPConfig(jsonCfg.HTTPValidator),
+		Logging:           jsonCfg.Logging,
+		Webhooks:          jsonCfg.Webhooks,
+		ProxyHealth:       jsonCfg.ProxyHealth,
+		CircuitBreaker:    jsonCfg.CircuitBreaker,
+		OutboundRateLimit: jsonCfg.OutboundRateLimit,
+		CampaignSafety:    jsonCfg.CampaignSafety,
+		Startup:           jsonCfg.Startup,
+		ResourceAccess:    jsonCfg.ResourceAccess,
+		TLDList:           jsonCfg.TLDList,
+		Audit:             jsonCfg.Audit,
+		PasswordHash:      jsonCfg.PasswordHash,
+		PasswordPolicy:    jsonCfg.PasswordPolicy,
+		PersonaTests:      jsonCfg.PersonaTests,
+		CampaignRetry:     jsonCfg.CampaignRetry,
+		CampaignPromotion: jsonCfg.CampaignPromotion,
+		CampaignSchedules: jsonCfg.CampaignSchedules,
+		CampaignUpdates:   jsonCfg.CampaignUpdates,
+		CampaignStatus:    jsonCfg.CampaignStatus,
+		LoginRates:        jsonCfg.LoginRates,
+		LoginRateLimit:    jsonCfg.LoginRateLimit,
+		Compression:       jsonCfg.Compression,
+		RouteRateLimit:    jsonCfg.RouteRateLimit,
+		Reclassification:  jsonCfg.Reclassification,
+		Tracing:           jsonCfg.Tracing,
 	}
 
 	if appCfg.Server.GinMode == "" {
@@ -185,6 +231,204 @@ func ConvertJSONToAppConfig(jsonCfg AppConfigJSON) *AppConfig {
 	if appCfg.Server.DBConnMaxLifetimeMinutes == 0 {
 		appCfg.Server.DBConnMaxLifetimeMinutes = DefaultDBConnMaxLifetimeMinutes
 	}
+	if appCfg.Server.MaxPageSize <= 0 {
+		appCfg.Server.MaxPageSize = DefaultMaxPageSize
+	}
+	if appCfg.Server.CompareBufferSize <= 0 {
+		appCfg.Server.CompareBufferSize = DefaultCompareBufferSize
+	}
+	if appCfg.Server.ExportBatchSize <= 0 {
+		appCfg.Server.ExportBatchSize = DefaultExportBatchSize
+	}
+	if appCfg.Server.PersonaConfigMaxBytes <= 0 {
+		appCfg.Server.PersonaConfigMaxBytes = DefaultPersonaConfigMaxBytes
+	}
+	if appCfg.Server.PersonaConfigMaxDepth <= 0 {
+		appCfg.Server.PersonaConfigMaxDepth = DefaultPersonaConfigMaxDepth
+	}
+	if appCfg.Webhooks.TimeoutSeconds <= 0 {
+		appCfg.Webhooks.TimeoutSeconds = DefaultWebhookTimeoutSeconds
+	}
+	if appCfg.Webhooks.Results.BatchSize <= 0 {
+		appCfg.Webhooks.Results.BatchSize = DefaultResultWebhookBatchSize
+	}
+	if appCfg.Webhooks.Results.FlushIntervalSeconds <= 0 {
+		appCfg.Webhooks.Results.FlushIntervalSeconds = DefaultResultWebhookFlushIntervalSeconds
+	}
+	if appCfg.Webhooks.Results.MaxAttempts <= 0 {
+		appCfg.Webhooks.Results.MaxAttempts = DefaultResultWebhookMaxAttempts
+	}
+	if appCfg.Webhooks.Results.RetryBackoffSeconds <= 0 {
+		appCfg.Webhooks.Results.RetryBackoffSeconds = DefaultResultWebhookRetryBackoffSeconds
+	}
+	if appCfg.Webhooks.Results.MaxBufferedResults <= 0 {
+		appCfg.Webhooks.Results.MaxBufferedResults = DefaultResultWebhookMaxBufferedResults
+	}
+	if appCfg.Webhooks.Campaigns.CheckIntervalSeconds <= 0 {
+		appCfg.Webhooks.Campaigns.CheckIntervalSeconds = DefaultCampaignWebhookCheckIntervalSeconds
+	}
+	if appCfg.Webhooks.Campaigns.MaxAttempts <= 0 {
+		appCfg.Webhooks.Campaigns.MaxAttempts = DefaultCampaignWebhookMaxAttempts
+	}
+	if appCfg.Webhooks.Campaigns.RetryBackoffSeconds <= 0 {
+		appCfg.Webhooks.Campaigns.RetryBackoffSeconds = DefaultCampaignWebhookRetryBackoffSeconds
+	}
+	if appCfg.ProxyHealth.QuarantineThreshold <= 0 {
+		appCfg.ProxyHealth.QuarantineThreshold = DefaultProxyQuarantineThreshold
+	}
+	if appCfg.ProxyHealth.QuarantineCooldownSeconds <= 0 {
+		appCfg.ProxyHealth.QuarantineCooldownSeconds = DefaultProxyQuarantineCooldownSeconds
+	}
+	if appCfg.ProxyHealth.CheckIntervalSeconds <= 0 {
+		appCfg.ProxyHealth.CheckIntervalSeconds = DefaultProxyCheckIntervalSeconds
+	}
+	if appCfg.ProxyHealth.CheckTargetURL == "" {
+		appCfg.ProxyHealth.CheckTargetURL = DefaultProxyCheckTargetURL
+	}
+	if appCfg.ProxyHealth.CheckTimeoutSeconds <= 0 {
+		appCfg.ProxyHealth.CheckTimeoutSeconds = DefaultProxyCheckTimeoutSeconds
+	}
+	if appCfg.ProxyHealth.CheckConcurrency <= 0 {
+		appCfg.ProxyHealth.CheckConcurrency = DefaultProxyCheckConcurrency
+	}
+	if appCfg.ProxyHealth.CheckFailureThreshold <= 0 {
+		appCfg.ProxyHealth.CheckFailureThreshold = DefaultProxyCheckFailureThreshold
+	}
+	// A negative failure threshold disables the circuit breaker, so only an unset value is defaulted
+	if appCfg.CircuitBreaker.FailureThreshold == 0 {
+		appCfg.CircuitBreaker.FailureThreshold = DefaultCircuitBreakerFailureThreshold
+	}
+	if appCfg.CircuitBreaker.CooldownSeconds <= 0 {
+		appCfg.CircuitBreaker.CooldownSeconds = DefaultCircuitBreakerCooldownSeconds
+	}
+	// Negative outbound rates disable the corresponding limit, so only unset rates are defaulted
+	if appCfg.OutboundRateLimit.PerHostRequestsPerSecond == 0 {
+		appCfg.OutboundRateLimit.PerHostRequestsPerSecond = DefaultOutboundPerHostRequestsPerSecond
+	}
+	if appCfg.OutboundRateLimit.PerZoneRequestsPerSecond == 0 {
+		appCfg.OutboundRateLimit.PerZoneRequestsPerSecond = DefaultOutboundPerZoneRequestsPerSecond
+	}
+	if appCfg.CampaignSafety.MinSampleSize <= 0 {
+		appCfg.CampaignSafety.MinSampleSize = DefaultCampaignSafetyMinSampleSize
+	}
+	if appCfg.CampaignSafety.Action == "" {
+		appCfg.CampaignSafety.Action = DefaultCampaignSafetyAction
+	}
+	if appCfg.Startup.DBPingAttempts <= 0 {
+		appCfg.Startup.DBPingAttempts = DefaultStartupDBPingAttempts
+	}
+	if appCfg.Startup.DBPingIntervalSeconds <= 0 {
+		appCfg.Startup.DBPingIntervalSeconds = DefaultStartupDBPingIntervalSeconds
+	}
+	if appCfg.TLDList.Source == "" {
+		appCfg.TLDList.Source = DefaultTLDListSource
+	}
+	if appCfg.PasswordHash.ExpectedPepperVersion <= 0 {
+		appCfg.PasswordHash.ExpectedPepperVersion = DefaultPasswordPepperVersion
+	}
+	if appCfg.PasswordHash.LegacyPepperGraceDays <= 0 {
+		appCfg.PasswordHash.LegacyPepperGraceDays = DefaultLegacyPepperGraceDays
+	}
+	if appCfg.PasswordHash.HistorySize <= 0 {
+		appCfg.PasswordHash.HistorySize = DefaultPasswordHistorySize
+	}
+	if appCfg.PasswordPolicy.MinLength <= 0 {
+		appCfg.PasswordPolicy.MinLength = DefaultPasswordMinLength
+	}
+	if appCfg.Audit.ExportBatchSize <= 0 {
+		appCfg.Audit.ExportBatchSize = DefaultAuditExportBatchSize
+	}
+	if appCfg.Audit.MaxConcurrentExports <= 0 {
+		appCfg.Audit.MaxConcurrentExports = DefaultAuditMaxConcurrentExports
+	}
+	if appCfg.PersonaTests.IntervalSeconds <= 0 {
+		appCfg.PersonaTests.IntervalSeconds = DefaultPersonaTestIntervalSeconds
+	}
+	if appCfg.PersonaTests.Concurrency <= 0 {
+		appCfg.PersonaTests.Concurrency = DefaultPersonaTestConcurrency
+	}
+	if appCfg.PersonaTests.TimeoutSeconds <= 0 {
+		appCfg.PersonaTests.TimeoutSeconds = DefaultPersonaTestTimeoutSeconds
+	}
+	if appCfg.PersonaTests.OnDemandTimeoutSeconds <= 0 {
+		appCfg.PersonaTests.OnDemandTimeoutSeconds = DefaultPersonaTestOnDemandTimeoutSeconds
+	}
+	if appCfg.PersonaTests.HistorySize <= 0 {
+		appCfg.PersonaTests.HistorySize = DefaultPersonaTestHistorySize
+	}
+	if appCfg.PersonaTests.DNSProbeDomain == "" {
+		appCfg.PersonaTests.DNSProbeDomain = DefaultPersonaTestDNSProbeDomain
+	}
+	if appCfg.PersonaTests.HTTPProbeURL == "" {
+		appCfg.PersonaTests.HTTPProbeURL = DefaultPersonaTestHTTPProbeURL
+	}
+	if appCfg.CampaignRetry.MaxRetries == 0 {
+		appCfg.CampaignRetry.MaxRetries = DefaultCampaignMaxRetries
+	}
+	if appCfg.CampaignRetry.BackoffSeconds <= 0 {
+		appCfg.CampaignRetry.BackoffSeconds = DefaultCampaignRetryBackoffSeconds
+	}
+	if appCfg.CampaignRetry.MaxBackoffSeconds <= 0 {
+		appCfg.CampaignRetry.MaxBackoffSeconds = DefaultCampaignRetryMaxBackoffSeconds
+	}
+	if appCfg.CampaignRetry.CheckIntervalSeconds <= 0 {
+		appCfg.CampaignRetry.CheckIntervalSeconds = DefaultCampaignRetryCheckIntervalSeconds
+	}
+	if appCfg.CampaignPromotion.CheckIntervalSeconds <= 0 {
+		appCfg.CampaignPromotion.CheckIntervalSeconds = DefaultCampaignPromotionCheckIntervalSeconds
+	}
+	if appCfg.CampaignSchedules.CheckIntervalSeconds <= 0 {
+		appCfg.CampaignSchedules.CheckIntervalSeconds = DefaultCampaignScheduleCheckIntervalSeconds
+	}
+	if appCfg.CampaignSchedules.LockSeconds <= 0 {
+		appCfg.CampaignSchedules.LockSeconds = DefaultCampaignScheduleLockSeconds
+	}
+	if appCfg.CampaignStatus.MaxBulkIDs <= 0 {
+		appCfg.CampaignStatus.MaxBulkIDs = DefaultCampaignStatusMaxBulkIDs
+	}
+	if appCfg.LoginRates.WindowSeconds <= 0 {
+		appCfg.LoginRates.WindowSeconds = DefaultLoginRateWindowSeconds
+	}
+	if appCfg.LoginRates.MaxTrackedIPs <= 0 {
+		appCfg.LoginRates.MaxTrackedIPs = DefaultLoginRateMaxTrackedIPs
+	}
+	if appCfg.LoginRateLimit.WindowSeconds <= 0 {
+		appCfg.LoginRateLimit.WindowSeconds = DefaultLoginRateLimitWindowSeconds
+	}
+	if appCfg.LoginRateLimit.MaxFailuresPerIP <= 0 {
+		appCfg.LoginRateLimit.MaxFailuresPerIP = DefaultLoginMaxFailuresPerIP
+	}
+	if appCfg.LoginRateLimit.MaxFailuresPerAccount <= 0 {
+		appCfg.LoginRateLimit.MaxFailuresPerAccount = DefaultLoginMaxFailuresPerAccount
+	}
+	if appCfg.LoginRateLimit.BlockSeconds <= 0 {
+		appCfg.LoginRateLimit.BlockSeconds = DefaultLoginRateLimitBlockSeconds
+	}
+	if appCfg.Compression.MinSizeBytes <= 0 {
+		appCfg.Compression.MinSizeBytes = DefaultCompressionMinSizeBytes
+	}
+	if appCfg.Compression.Level < 1 || appCfg.Compression.Level > 9 {
+		appCfg.Compression.Level = DefaultCompressionLevel
+	}
+	if appCfg.RouteRateLimit.Store == "" {
+		appCfg.RouteRateLimit.Store = RateLimitStoreMemory
+	}
+	appCfg.RouteRateLimit.CampaignCreate = routeRateWithDefaults(appCfg.RouteRateLimit.CampaignCreate,
+		DefaultCampaignCreateRatePerMinute, DefaultCampaignCreateRateBurst)
+	appCfg.RouteRateLimit.PersonaTest = routeRateWithDefaults(appCfg.RouteRateLimit.PersonaTest,
+		DefaultPersonaTestRatePerMinute, DefaultPersonaTestRateBurst)
+	if appCfg.Reclassification.BatchSize <= 0 {
+		appCfg.Reclassification.BatchSize = DefaultReclassificationBatchSize
+	}
+	if len(appCfg.Reclassification.MatchFields) == 0 {
+		appCfg.Reclassification.MatchFields = []string{ReclassificationFieldPageTitle, ReclassificationFieldContentSnippet}
+	}
+	if appCfg.Tracing.ServiceName == "" {
+		appCfg.Tracing.ServiceName = DefaultTracingServiceName
+	}
+	if appCfg.Tracing.SampleRatio <= 0 || appCfg.Tracing.SampleRatio > 1 {
+		appCfg.Tracing.SampleRatio = DefaultTracingSampleRatio
+	}
 
 	return appCfg
 }
@@ -192,12 +436,49 @@ func ConvertJSONToAppConfig(jsonCfg AppConfigJSON) *AppConfig {
 // ConvertAppConfigToJSON converts the internal AppConfig model to the AppConfigJSON structure for saving.
 func ConvertAppConfigToJSON(appCfg *AppConfig) AppConfigJSON {
 	return AppConfigJSON{
-		Server:        appCfg.Server,
-		Worker:        ConvertWorkerConfigToJSON(appCfg.Worker), // Convert WorkerConfig
-		DNSValidator:  ConvertDNSConfigToJSON(appCfg.DNSValidator),
-		HTTPValidator: ConvertHTTPConfigToJSON(appCfg.HTTPValidator),
-		Logging:       appCfg.Logging,
+		Server:            appCfg.Server,
+		Worker:            ConvertWorkerConfigToJSON(appCfg.Worker), // Convert WorkerConfig
+		DNSValidator:      ConvertDNSConfigToJSON(appCfg.DNSValidator),
+		HTTPValidator:     ConvertHTTPConfigToJSON(appCfg.HTTPValidator),
+		Logging:           appCfg.Logging,
+		Webhooks:          appCfg.Webhooks,
+		ProxyHealth:       appCfg.ProxyHealth,
+		CircuitBreaker:    appCfg.CircuitBreaker,
+		OutboundRateLimit: appCfg.OutboundRateLimit,
+		CampaignSafety:    appCfg.CampaignSafety,
+		Startup:           appCfg.Startup,
+		ResourceAccess:    appCfg.ResourceAccess,
+		TLDList:           appCfg.TLDList,
+		Audit:             appCfg.Audit,
+		PasswordHash:      appCfg.PasswordHash,
+		PasswordPolicy:    appCfg.PasswordPolicy,
+		PersonaTests:      appCfg.PersonaTests,
+		CampaignRetry:     appCfg.CampaignRetry,
+		CampaignPromotion: appCfg.CampaignPromotion,
+		CampaignSchedules: appCfg.CampaignSchedules,
+		CampaignUpdates:   appCfg.CampaignUpdates,
+		CampaignStatus:    appCfg.CampaignStatus,
+		LoginRates:        appCfg.LoginRates,
+		LoginRateLimit:    appCfg.LoginRateLimit,
+		Compression:       appCfg.Compression,
+		RouteRateLimit:    appCfg.RouteRateLimit,
+		Reclassification:  appCfg.Reclassification,
+		Tracing:           appCfg.Tracing,
+	}
+}
+
+// routeRateWithDefaults fills in an unset rate with the defaults, and an unset burst with the rate
+func routeRateWithDefaults(rate RouteRateConfig, requestsPerMinute, burst int) RouteRateConfig {
+	if rate.RequestsPerMinute <= 0 {
+		rate.RequestsPerMinute = requestsPerMinute
+		if rate.Burst <= 0 {
+			rate.Burst = burst
+		}
+	}
+	if rate.Burst <= 0 {
+		rate.Burst = rate.RequestsPerMinute
 	}
+	return rate
 }
 
 // ConvertJSONToWorkerConfig applies defaults to WorkerConfig from JSON.
@@ -212,12 +493,39 @@ func ConvertJSONToWorkerConfig(jsonCfg WorkerConfig) WorkerConfig {
 	if cfg.ErrorRetryDelaySeconds <= 0 {
 		cfg.ErrorRetryDelaySeconds = DefaultErrorRetryDelaySeconds
 	}
+	if cfg.BackoffBaseSeconds <= 0 {
+		cfg.BackoffBaseSeconds = cfg.ErrorRetryDelaySeconds
+	}
+	if cfg.BackoffMaxSeconds <= 0 {
+		cfg.BackoffMaxSeconds = DefaultJobBackoffMaxSeconds
+	}
+	if cfg.BackoffMultiplier < 1 {
+		cfg.BackoffMultiplier = DefaultJobBackoffMultiplier
+	}
 	if cfg.MaxJobRetries <= 0 {
 		cfg.MaxJobRetries = DefaultMaxJobRetries
 	}
 	if cfg.JobProcessingTimeoutMinutes <= 0 {
 		cfg.JobProcessingTimeoutMinutes = DefaultJobProcessingTimeoutMinutes
 	}
+	if cfg.ResultCommitChunkSize <= 0 {
+		cfg.ResultCommitChunkSize = DefaultResultCommitChunkSize
+	}
+	if cfg.MaxAttemptsPerDomain == 0 {
+		cfg.MaxAttemptsPerDomain = DefaultMaxAttemptsPerDomain
+	}
+	if cfg.PrewarmTimeoutSeconds <= 0 {
+		cfg.PrewarmTimeoutSeconds = DefaultPrewarmTimeoutSeconds
+	}
+	if cfg.PrewarmConcurrency <= 0 {
+		cfg.PrewarmConcurrency = DefaultPrewarmConcurrency
+	}
+	if cfg.PriorityAgingIntervalSeconds == 0 {
+		cfg.PriorityAgingIntervalSeconds = DefaultPriorityAgingSeconds
+	}
+	if cfg.StaleJobReapIntervalSeconds == 0 {
+		cfg.StaleJobReapIntervalSeconds = DefaultStaleJobReapSeconds
+	}
 	return cfg
 }
 
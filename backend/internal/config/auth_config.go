@@ -6,7 +6,7 @@ import "time"
 type AuthConfig struct {
 	// Password security
 	BcryptCost        int    `json:"bcryptCost" mapstructure:"bcrypt_cost"`
-	PepperKey         string `json:"pepperKey" mapstructure:"pepper_key"`
+	PepperKey         string `json:"pepperKey" mapstructure:"pepper_key" redact:"true"`
 	PasswordMinLength int    `json:"passwordMinLength" mapstructure:"password_min_length"`
 
 	// Session configuration
@@ -30,14 +30,14 @@ type AuthConfig struct {
 
 	// CAPTCHA configuration
 	RecaptchaSiteKey   string `json:"recaptchaSiteKey" mapstructure:"recaptcha_site_key"`
-	RecaptchaSecretKey string `json:"recaptchaSecretKey" mapstructure:"recaptcha_secret_key"`
+	RecaptchaSecretKey string `json:"recaptchaSecretKey" mapstructure:"recaptcha_secret_key" redact:"true"`
 	CaptchaThreshold   int    `json:"captchaThreshold" mapstructure:"captcha_threshold"`
 
 	// Email configuration (for password reset)
 	SMTPHost     string `json:"smtpHost" mapstructure:"smtp_host"`
 	SMTPPort     int    `json:"smtpPort" mapstructure:"smtp_port"`
 	SMTPUsername string `json:"smtpUsername" mapstructure:"smtp_username"`
-	SMTPPassword string `json:"smtpPassword" mapstructure:"smtp_password"`
+	SMTPPassword string `json:"smtpPassword" mapstructure:"smtp_password" redact:"true"`
 	FromEmail    string `json:"fromEmail" mapstructure:"from_email"`
 	FromName     string `json:"fromName" mapstructure:"from_name"`
 }
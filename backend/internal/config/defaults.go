@@ -17,6 +17,12 @@ const (
 	DefaultDBMaxOpenConns           = 25
 	DefaultDBMaxIdleConns           = 25
 	DefaultDBConnMaxLifetimeMinutes = 5
+	DefaultMaxPageSize              = 100
+	DefaultCompareBufferSize        = 500
+	DefaultExportBatchSize          = 1000
+	DefaultPersonaConfigMaxBytes    = 64 * 1024
+	DefaultPersonaConfigMaxDepth    = 16
+	DefaultStatsSoftDeadlineMs      = 2000
 
 	// WorkerConfig Defaults
 	DefaultNumWorkers                  = 5
@@ -24,6 +30,14 @@ const (
 	DefaultErrorRetryDelaySeconds      = 30
 	DefaultMaxJobRetries               = 3
 	DefaultJobProcessingTimeoutMinutes = 15
+	DefaultResultCommitChunkSize       = 500
+	DefaultMaxAttemptsPerDomain        = 5
+	DefaultPrewarmTimeoutSeconds       = 30
+	DefaultPrewarmConcurrency          = 10
+	DefaultPriorityAgingSeconds        = 60
+	DefaultStaleJobReapSeconds         = 60
+	DefaultJobBackoffMaxSeconds        = 600
+	DefaultJobBackoffMultiplier        = 2.0
 
 	// HTTPValidatorConfig Defaults
 	DefaultHTTPUserAgent                   = "DomainFlowBot/1.2 (DefaultStudioAgent)"
@@ -31,8 +45,125 @@ const (
 	DefaultHTTPFollowRedirects             = true
 	DefaultHTTPRequestTimeoutSeconds       = 15
 	DefaultHTTPMaxRedirects                = 7
+
+	// WebhookConfig Defaults
+	DefaultWebhookTimeoutSeconds = 10
+
+	// ResultWebhookConfig Defaults
+	DefaultResultWebhookBatchSize            = 100
+	DefaultResultWebhookFlushIntervalSeconds = 5
+	DefaultResultWebhookMaxAttempts          = 5
+	DefaultResultWebhookRetryBackoffSeconds  = 2
+	DefaultResultWebhookMaxBufferedResults   = 10000
+
+	// CampaignWebhookConfig Defaults
+	DefaultCampaignWebhookCheckIntervalSeconds = 30
+	DefaultCampaignWebhookMaxAttempts          = 3
+	DefaultCampaignWebhookRetryBackoffSeconds  = 2
+
+	// ProxyHealthConfig Defaults
+	DefaultProxyQuarantineThreshold       = 5
+	DefaultProxyQuarantineCooldownSeconds = 300
+	DefaultProxyCheckIntervalSeconds      = 120
+	DefaultProxyCheckTargetURL            = "https://example.com/"
+	DefaultProxyCheckTimeoutSeconds       = 10
+	DefaultProxyCheckConcurrency          = 5
+	DefaultProxyCheckFailureThreshold     = 3
+
+	// CircuitBreakerConfig Defaults
+	DefaultCircuitBreakerFailureThreshold = 5
+	DefaultCircuitBreakerCooldownSeconds  = 60
+
+	// OutboundRateLimitConfig Defaults
+	DefaultOutboundPerHostRequestsPerSecond = 2.0
+	DefaultOutboundPerZoneRequestsPerSecond = 10.0
+
+	// CampaignSafetyConfig Defaults. The sample size floor keeps a handful of early failures from
+	// stopping a campaign.
+	DefaultCampaignSafetyMinSampleSize = 100
+	MinCampaignSafetySampleSize        = 20
+	DefaultCampaignSafetyAction        = "pause"
+
+	// StartupConfig Defaults
+	DefaultStartupDBPingAttempts        = 30
+	DefaultStartupDBPingIntervalSeconds = 2
+
+	// TLDListConfig Defaults
+	DefaultTLDListSource = "https://data.iana.org/TLD/tlds-alpha-by-domain.txt"
+
+	// PasswordHashConfig Defaults
+	DefaultPasswordPepperVersion = 2
+	DefaultLegacyPepperGraceDays = 30
+	DefaultPasswordHistorySize   = 5
+
+	// UnpepperedPasswordPepperVersion is the password_pepper_version of hashes made without a pepper
+	UnpepperedPasswordPepperVersion = 1
+
+	// PasswordPolicyConfig Defaults
+	DefaultPasswordMinLength = 12
+
+	// AuditConfig Defaults
+	DefaultAuditExportBatchSize      = 1000
+	DefaultAuditMaxConcurrentExports = 2
+
+	// PersonaTestConfig Defaults
+	DefaultPersonaTestIntervalSeconds        = 300
+	DefaultPersonaTestConcurrency            = 5
+	DefaultPersonaTestTimeoutSeconds         = 15
+	DefaultPersonaTestOnDemandTimeoutSeconds = 5
+	DefaultPersonaTestHistorySize            = 50
+	DefaultPersonaTestDNSProbeDomain         = "example.com"
+	DefaultPersonaTestHTTPProbeURL           = "https://example.com/"
+
+	// CampaignRetryConfig Defaults
+	DefaultCampaignMaxRetries                = 3
+	DefaultCampaignRetryBackoffSeconds       = 60
+	DefaultCampaignRetryMaxBackoffSeconds    = 3600
+	DefaultCampaignRetryCheckIntervalSeconds = 30
+
+	// CampaignPromotionConfig Defaults
+	DefaultCampaignPromotionCheckIntervalSeconds = 15
+
+	// CampaignScheduleConfig Defaults
+	DefaultCampaignScheduleCheckIntervalSeconds = 30
+	DefaultCampaignScheduleLockSeconds          = 300
+
+	// CampaignStatusConfig Defaults
+	DefaultCampaignStatusMaxBulkIDs = 100
+
+	// LoginRateConfig Defaults
+	DefaultLoginRateWindowSeconds = 300
+	DefaultLoginRateMaxTrackedIPs = 10000
+
+	// LoginRateLimitConfig Defaults
+	DefaultLoginRateLimitWindowSeconds = 900
+	DefaultLoginMaxFailuresPerIP       = 50
+	DefaultLoginMaxFailuresPerAccount  = 10
+	DefaultLoginRateLimitBlockSeconds  = 900
+
+	// CompressionConfig Defaults
+	DefaultCompressionMinSizeBytes = 1024
+	DefaultCompressionLevel        = 6
+
+	// RouteRateLimitConfig Defaults
+	DefaultCampaignCreateRatePerMinute = 10
+	DefaultCampaignCreateRateBurst     = 5
+	DefaultPersonaTestRatePerMinute    = 6
+	DefaultPersonaTestRateBurst        = 3
+
+	// ReclassificationConfig Defaults
+	DefaultReclassificationBatchSize = 500
+
+	// TracingConfig Defaults
+	DefaultTracingServiceName = "domainflow-api"
+	DefaultTracingSampleRatio = 1.0
 )
 
+// DefaultMetricsAllowedNetworks are the loopback and private ranges allowed to scrape /metrics
+var DefaultMetricsAllowedNetworks = []string{
+	"127.0.0.0/8", "::1/128", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7",
+}
+
 // DefaultAppConfigJSON returns the default application configuration as an AppConfigJSON struct.
 func DefaultAppConfigJSON() AppConfigJSON {
 	defaultFollowRedirects := DefaultHTTPFollowRedirects
@@ -45,13 +176,23 @@ func DefaultAppConfigJSON() AppConfigJSON {
 			DBMaxOpenConns:           DefaultDBMaxOpenConns,
 			DBMaxIdleConns:           DefaultDBMaxIdleConns,
 			DBConnMaxLifetimeMinutes: DefaultDBConnMaxLifetimeMinutes,
+			MaxPageSize:              DefaultMaxPageSize,
+			CompareBufferSize:        DefaultCompareBufferSize,
+			ExportBatchSize:          DefaultExportBatchSize,
+			PersonaConfigMaxBytes:    DefaultPersonaConfigMaxBytes,
+			PersonaConfigMaxDepth:    DefaultPersonaConfigMaxDepth,
+			SoftDeadlinesMs:          map[string]int{"campaignStats": DefaultStatsSoftDeadlineMs},
 		},
 		Worker: WorkerConfig{
-			NumWorkers:                  DefaultNumWorkers,
-			PollIntervalSeconds:         DefaultPollIntervalSeconds,
-			ErrorRetryDelaySeconds:      DefaultErrorRetryDelaySeconds,
-			MaxJobRetries:               DefaultMaxJobRetries,
-			JobProcessingTimeoutMinutes: DefaultJobProcessingTimeoutMinutes,
+			NumWorkers:                   DefaultNumWorkers,
+			PollIntervalSeconds:          DefaultPollIntervalSeconds,
+			ErrorRetryDelaySeconds:       DefaultErrorRetryDelaySeconds,
+			MaxJobRetries:                DefaultMaxJobRetries,
+			JobProcessingTimeoutMinutes:  DefaultJobProcessingTimeoutMinutes,
+			ResultCommitChunkSize:        DefaultResultCommitChunkSize,
+			MaxAttemptsPerDomain:         DefaultMaxAttemptsPerDomain,
+			PriorityAgingIntervalSeconds: DefaultPriorityAgingSeconds,
+			StaleJobReapIntervalSeconds:  DefaultStaleJobReapSeconds,
 		},
 		DNSValidator: DNSValidatorConfigJSON{
 			Resolvers: []string{
@@ -44,7 +44,7 @@ type DatabaseConfig struct {
 	Port               int    `json:"port"`
 	Name               string `json:"name"`
 	User               string `json:"user"`
-	Password           string `json:"password"`
+	Password           string `json:"password" redact:"true"`
 	SSLMode            string `json:"sslmode"`
 	MaxConnections     int    `json:"maxConnections"`
 	MaxIdleConnections int    `json:"maxIdleConnections"`
@@ -53,10 +53,10 @@ type DatabaseConfig struct {
 
 // EnvAuthConfig holds authentication settings from environment variables
 type EnvAuthConfig struct {
-	JWTSecret      string `json:"jwtSecret"`
-	SessionSecret  string `json:"sessionSecret"`
-	EncryptionKey  string `json:"encryptionKey"`
-	APIKeySalt     string `json:"apiKeySalt"`
+	JWTSecret      string `json:"jwtSecret" redact:"true"`
+	SessionSecret  string `json:"sessionSecret" redact:"true"`
+	EncryptionKey  string `json:"encryptionKey" redact:"true"`
+	APIKeySalt     string `json:"apiKeySalt" redact:"true"`
 	SessionTimeout string `json:"sessionTimeout"`
 	CookieSecure   bool   `json:"cookieSecure"`
 	CookieHTTPOnly bool   `json:"cookieHttpOnly"`
@@ -122,6 +122,36 @@ func applyEnvironmentOverrides(config *AppConfig) {
 	if ginMode := os.Getenv("GIN_MODE"); ginMode != "" {
 		config.Server.GinMode = ginMode
 	}
+	if strictJSON := os.Getenv("STRICT_JSON_DECODING"); strictJSON != "" {
+		config.Server.StrictJSONDecoding = getEnvAsBool("STRICT_JSON_DECODING", false)
+	}
+	if maxPageSize := getEnvAsInt("MAX_PAGE_SIZE", 0); maxPageSize > 0 {
+		config.Server.MaxPageSize = maxPageSize
+	}
+	if compareBufferSize := getEnvAsInt("COMPARE_BUFFER_SIZE", 0); compareBufferSize > 0 {
+		config.Server.CompareBufferSize = compareBufferSize
+	}
+	if exportBatchSize := getEnvAsInt("EXPORT_BATCH_SIZE", 0); exportBatchSize > 0 {
+		config.Server.ExportBatchSize = exportBatchSize
+	}
+	if personaConfigMaxBytes := getEnvAsInt("PERSONA_CONFIG_MAX_BYTES", 0); personaConfigMaxBytes > 0 {
+		config.Server.PersonaConfigMaxBytes = personaConfigMaxBytes
+	}
+	if personaConfigMaxDepth := getEnvAsInt("PERSONA_CONFIG_MAX_DEPTH", 0); personaConfigMaxDepth > 0 {
+		config.Server.PersonaConfigMaxDepth = personaConfigMaxDepth
+	}
+	if replicaDSN := os.Getenv("DATABASE_READ_REPLICA_DSN"); replicaDSN != "" {
+		config.Server.ReadReplicaDSN = replicaDSN
+	}
+	if replicaRetry := getEnvAsInt("DATABASE_READ_REPLICA_RETRY_SECONDS", 0); replicaRetry > 0 {
+		config.Server.ReadReplicaRetrySeconds = replicaRetry
+	}
+	if metricsToken := os.Getenv("METRICS_TOKEN"); metricsToken != "" {
+		config.Server.MetricsToken = metricsToken
+	}
+	if metricsNetworks := os.Getenv("METRICS_ALLOWED_NETWORKS"); metricsNetworks != "" {
+		config.Server.MetricsAllowedNetworks = strings.Split(metricsNetworks, ",")
+	}
 
 	// Worker overrides
 	if numWorkers := getEnvAsInt("WORKER_COUNT", 0); numWorkers > 0 {
@@ -131,6 +161,48 @@ func applyEnvironmentOverrides(config *AppConfig) {
 	if pollInterval := getEnvAsInt("WORKER_POLL_INTERVAL", 0); pollInterval > 0 {
 		config.Worker.PollIntervalSeconds = pollInterval
 	}
+	if maxAttempts := getEnvAsInt("WORKER_MAX_ATTEMPTS_PER_DOMAIN", 0); maxAttempts != 0 {
+		config.Worker.MaxAttemptsPerDomain = maxAttempts
+	}
+	if os.Getenv("WORKER_VERIFY_HTTP_RESUME_POINT") != "" {
+		enabled := getEnvAsBool("WORKER_VERIFY_HTTP_RESUME_POINT", true)
+		config.Worker.VerifyHTTPResumePoint = &enabled
+	}
+	if stallWindow := getEnvAsInt("WORKER_HEALTH_STALL_WINDOW_SECONDS", 0); stallWindow > 0 {
+		config.Worker.HealthStallWindowSeconds = stallWindow
+	}
+	if os.Getenv("WORKER_HEALTH_IN_READINESS") != "" {
+		enabled := getEnvAsBool("WORKER_HEALTH_IN_READINESS", true)
+		config.Worker.HealthInReadiness = &enabled
+	}
+	if os.Getenv("WORKER_PREWARM_RESOURCE_HEALTH") != "" {
+		enabled := getEnvAsBool("WORKER_PREWARM_RESOURCE_HEALTH", false)
+		config.Worker.PrewarmResourceHealth = &enabled
+	}
+	if timeout := getEnvAsInt("WORKER_PREWARM_TIMEOUT_SECONDS", 0); timeout > 0 {
+		config.Worker.PrewarmTimeoutSeconds = timeout
+	}
+	if concurrency := getEnvAsInt("WORKER_PREWARM_CONCURRENCY", 0); concurrency > 0 {
+		config.Worker.PrewarmConcurrency = concurrency
+	}
+	if base := getEnvAsInt("WORKER_BACKOFF_BASE_SECONDS", 0); base > 0 {
+		config.Worker.BackoffBaseSeconds = base
+	}
+	if maxBackoff := getEnvAsInt("WORKER_BACKOFF_MAX_SECONDS", 0); maxBackoff > 0 {
+		config.Worker.BackoffMaxSeconds = maxBackoff
+	}
+	if multiplier := getEnvAsFloat("WORKER_BACKOFF_MULTIPLIER", 0); multiplier >= 1 {
+		config.Worker.BackoffMultiplier = multiplier
+	}
+	if aging := getEnvAsInt("WORKER_PRIORITY_AGING_INTERVAL_SECONDS", 0); aging != 0 {
+		config.Worker.PriorityAgingIntervalSeconds = aging
+	}
+	if perCampaign := getEnvAsInt("WORKER_MAX_CONCURRENT_JOBS_PER_CAMPAIGN", 0); perCampaign != 0 {
+		config.Worker.MaxConcurrentJobsPerCampaign = perCampaign
+	}
+	if reap := getEnvAsInt("WORKER_STALE_JOB_REAP_INTERVAL_SECONDS", 0); reap != 0 {
+		config.Worker.StaleJobReapIntervalSeconds = reap
+	}
 
 	// Logging overrides
 	if logLevel := os.Getenv("LOG_LEVEL"); logLevel != "" {
@@ -156,6 +228,306 @@ func applyEnvironmentOverrides(config *AppConfig) {
 	if httpTimeout := getEnvAsInt("HTTP_TIMEOUT_SECONDS", 0); httpTimeout > 0 {
 		config.HTTPValidator.RequestTimeoutSeconds = httpTimeout
 	}
+
+	// Webhook overrides
+	if signingSecret := os.Getenv("WEBHOOK_SIGNING_SECRET"); signingSecret != "" {
+		config.Webhooks.SigningSecret = signingSecret
+	}
+	if webhookTimeout := getEnvAsInt("WEBHOOK_TIMEOUT_SECONDS", 0); webhookTimeout > 0 {
+		config.Webhooks.TimeoutSeconds = webhookTimeout
+	}
+	if resultsURL := os.Getenv("WEBHOOK_RESULTS_URL"); resultsURL != "" {
+		config.Webhooks.Results.URL = resultsURL
+	}
+	if batchSize := getEnvAsInt("WEBHOOK_RESULTS_BATCH_SIZE", 0); batchSize > 0 {
+		config.Webhooks.Results.BatchSize = batchSize
+	}
+	if flushInterval := getEnvAsInt("WEBHOOK_RESULTS_FLUSH_INTERVAL_SECONDS", 0); flushInterval > 0 {
+		config.Webhooks.Results.FlushIntervalSeconds = flushInterval
+	}
+	if maxAttempts := getEnvAsInt("WEBHOOK_RESULTS_MAX_ATTEMPTS", 0); maxAttempts > 0 {
+		config.Webhooks.Results.MaxAttempts = maxAttempts
+	}
+	if maxAttempts := getEnvAsInt("WEBHOOK_CAMPAIGNS_MAX_ATTEMPTS", 0); maxAttempts > 0 {
+		config.Webhooks.Campaigns.MaxAttempts = maxAttempts
+	}
+
+	// Proxy health overrides
+	if threshold := getEnvAsInt("PROXY_QUARANTINE_THRESHOLD", 0); threshold > 0 {
+		config.ProxyHealth.QuarantineThreshold = threshold
+	}
+	if cooldown := getEnvAsInt("PROXY_QUARANTINE_COOLDOWN_SECONDS", 0); cooldown > 0 {
+		config.ProxyHealth.QuarantineCooldownSeconds = cooldown
+	}
+	if os.Getenv("PROXY_HEALTH_CHECK_ENABLED") != "" {
+		enabled := getEnvAsBool("PROXY_HEALTH_CHECK_ENABLED", false)
+		config.ProxyHealth.CheckEnabled = &enabled
+	}
+	if interval := getEnvAsInt("PROXY_HEALTH_CHECK_INTERVAL_SECONDS", 0); interval > 0 {
+		config.ProxyHealth.CheckIntervalSeconds = interval
+	}
+	if target := os.Getenv("PROXY_HEALTH_CHECK_TARGET_URL"); target != "" {
+		config.ProxyHealth.CheckTargetURL = target
+	}
+	if threshold := getEnvAsInt("PROXY_HEALTH_CHECK_FAILURE_THRESHOLD", 0); threshold > 0 {
+		config.ProxyHealth.CheckFailureThreshold = threshold
+	}
+
+	// Outbound circuit breaker overrides
+	if threshold := getEnvAsInt("CIRCUIT_BREAKER_FAILURE_THRESHOLD", 0); threshold != 0 {
+		config.CircuitBreaker.FailureThreshold = threshold
+	}
+	if cooldown := getEnvAsInt("CIRCUIT_BREAKER_COOLDOWN_SECONDS", 0); cooldown > 0 {
+		config.CircuitBreaker.CooldownSeconds = cooldown
+	}
+
+	// Outbound per-host/per-zone rate limit overrides
+	if rate := getEnvAsFloat("OUTBOUND_PER_HOST_RPS", 0); rate != 0 {
+		config.OutboundRateLimit.PerHostRequestsPerSecond = rate
+	}
+	if rate := getEnvAsFloat("OUTBOUND_PER_ZONE_RPS", 0); rate != 0 {
+		config.OutboundRateLimit.PerZoneRequestsPerSecond = rate
+	}
+
+	// Campaign error-rate safety overrides
+	if threshold := getEnvAsFloat("CAMPAIGN_ERROR_RATE_THRESHOLD", 0); threshold != 0 {
+		config.CampaignSafety.ErrorRateThreshold = threshold
+	}
+	if sample := getEnvAsInt("CAMPAIGN_ERROR_RATE_MIN_SAMPLE", 0); sample > 0 {
+		config.CampaignSafety.MinSampleSize = sample
+	}
+	if action := os.Getenv("CAMPAIGN_ERROR_RATE_ACTION"); action != "" {
+		config.CampaignSafety.Action = action
+	}
+
+	// Startup sequence overrides
+	if os.Getenv("STARTUP_PING_DATABASE") != "" {
+		enabled := getEnvAsBool("STARTUP_PING_DATABASE", true)
+		config.Startup.PingDatabase = &enabled
+	}
+	if attempts := getEnvAsInt("STARTUP_DB_PING_ATTEMPTS", 0); attempts > 0 {
+		config.Startup.DBPingAttempts = attempts
+	}
+	if interval := getEnvAsInt("STARTUP_DB_PING_INTERVAL_SECONDS", 0); interval > 0 {
+		config.Startup.DBPingIntervalSeconds = interval
+	}
+	if os.Getenv("STARTUP_VERIFY_MIGRATIONS") != "" {
+		enabled := getEnvAsBool("STARTUP_VERIFY_MIGRATIONS", false)
+		config.Startup.VerifyMigrations = &enabled
+	}
+	if os.Getenv("STARTUP_START_WORKERS") != "" {
+		enabled := getEnvAsBool("STARTUP_START_WORKERS", true)
+		config.Startup.StartWorkers = &enabled
+	}
+	if os.Getenv("STARTUP_START_SESSION_SERVICE") != "" {
+		enabled := getEnvAsBool("STARTUP_START_SESSION_SERVICE", true)
+		config.Startup.StartSessionService = &enabled
+	}
+
+	// Persona and proxy ownership overrides
+	if os.Getenv("RESOURCE_ENFORCE_OWNERSHIP") != "" {
+		enabled := getEnvAsBool("RESOURCE_ENFORCE_OWNERSHIP", false)
+		config.ResourceAccess.EnforceOwnership = &enabled
+	}
+	if os.Getenv("RESOURCE_SHARE_BY_DEFAULT") != "" {
+		enabled := getEnvAsBool("RESOURCE_SHARE_BY_DEFAULT", true)
+		config.ResourceAccess.ShareByDefault = &enabled
+	}
+	if os.Getenv("RESOURCE_UNIQUE_CAMPAIGN_NAMES") != "" {
+		enabled := getEnvAsBool("RESOURCE_UNIQUE_CAMPAIGN_NAMES", false)
+		config.ResourceAccess.UniqueCampaignNames = &enabled
+	}
+	if os.Getenv("RESOURCE_SNAPSHOT_DELETED") != "" {
+		enabled := getEnvAsBool("RESOURCE_SNAPSHOT_DELETED", true)
+		config.ResourceAccess.SnapshotDeletedResources = &enabled
+	}
+
+	// TLD list overrides
+	if os.Getenv("TLD_LIST_VALIDATE") != "" {
+		enabled := getEnvAsBool("TLD_LIST_VALIDATE", true)
+		config.TLDList.Validate = &enabled
+	}
+	if source := os.Getenv("TLD_LIST_SOURCE"); source != "" {
+		config.TLDList.Source = source
+	}
+	if hours := getEnvAsInt("TLD_LIST_REFRESH_INTERVAL_HOURS", 0); hours > 0 {
+		config.TLDList.RefreshIntervalHours = hours
+	}
+
+	// Password hash overrides
+	if os.Getenv("PASSWORD_HASH_DIAGNOSE_MISMATCHES") != "" {
+		enabled := getEnvAsBool("PASSWORD_HASH_DIAGNOSE_MISMATCHES", true)
+		config.PasswordHash.DiagnoseMismatches = &enabled
+	}
+	if version := getEnvAsInt("PASSWORD_PEPPER_VERSION", 0); version > 0 {
+		config.PasswordHash.ExpectedPepperVersion = version
+	}
+	if days := getEnvAsInt("PASSWORD_LEGACY_PEPPER_GRACE_DAYS", 0); days > 0 {
+		config.PasswordHash.LegacyPepperGraceDays = days
+	}
+	if size := getEnvAsInt("PASSWORD_HISTORY_SIZE", 0); size > 0 {
+		config.PasswordHash.HistorySize = size
+	}
+
+	// Password policy overrides
+	if length := getEnvAsInt("PASSWORD_MIN_LENGTH", 0); length > 0 {
+		config.PasswordPolicy.MinLength = length
+	}
+	if length := getEnvAsInt("PASSWORD_MAX_LENGTH", 0); length > 0 {
+		config.PasswordPolicy.MaxLength = length
+	}
+	config.PasswordPolicy.RequireUppercase = getEnvAsBool("PASSWORD_REQUIRE_UPPERCASE", config.PasswordPolicy.RequireUppercase)
+	config.PasswordPolicy.RequireLowercase = getEnvAsBool("PASSWORD_REQUIRE_LOWERCASE", config.PasswordPolicy.RequireLowercase)
+	config.PasswordPolicy.RequireDigit = getEnvAsBool("PASSWORD_REQUIRE_DIGIT", config.PasswordPolicy.RequireDigit)
+	config.PasswordPolicy.RequireSymbol = getEnvAsBool("PASSWORD_REQUIRE_SYMBOL", config.PasswordPolicy.RequireSymbol)
+	if file := os.Getenv("PASSWORD_DENYLIST_FILE"); file != "" {
+		config.PasswordPolicy.DenylistFile = file
+	}
+	if pepper := os.Getenv("PASSWORD_PEPPER"); pepper != "" {
+		config.PasswordHash.Pepper = pepper
+	}
+	if legacy := os.Getenv("PASSWORD_LEGACY_PEPPERS"); legacy != "" {
+		config.PasswordHash.LegacyPeppers = parseLegacyPeppers(legacy)
+	}
+
+	// Persona test overrides
+	if os.Getenv("PERSONA_TESTS_ENABLED") != "" {
+		enabled := getEnvAsBool("PERSONA_TESTS_ENABLED", false)
+		config.PersonaTests.Enabled = &enabled
+	}
+	if interval := getEnvAsInt("PERSONA_TESTS_INTERVAL_SECONDS", 0); interval > 0 {
+		config.PersonaTests.IntervalSeconds = interval
+	}
+	if concurrency := getEnvAsInt("PERSONA_TESTS_CONCURRENCY", 0); concurrency > 0 {
+		config.PersonaTests.Concurrency = concurrency
+	}
+	if timeout := getEnvAsInt("PERSONA_TESTS_ON_DEMAND_TIMEOUT_SECONDS", 0); timeout > 0 {
+		config.PersonaTests.OnDemandTimeoutSeconds = timeout
+	}
+	if domain := os.Getenv("PERSONA_TESTS_DNS_PROBE_DOMAIN"); domain != "" {
+		config.PersonaTests.DNSProbeDomain = domain
+	}
+	if probeURL := os.Getenv("PERSONA_TESTS_HTTP_PROBE_URL"); probeURL != "" {
+		config.PersonaTests.HTTPProbeURL = probeURL
+	}
+
+	// Campaign retry overrides
+	if maxRetries := getEnvAsInt("CAMPAIGN_RETRY_MAX_RETRIES", 0); maxRetries != 0 {
+		config.CampaignRetry.MaxRetries = maxRetries
+	}
+	if backoff := getEnvAsInt("CAMPAIGN_RETRY_BACKOFF_SECONDS", 0); backoff > 0 {
+		config.CampaignRetry.BackoffSeconds = backoff
+	}
+	if interval := getEnvAsInt("CAMPAIGN_PROMOTION_CHECK_INTERVAL_SECONDS", 0); interval > 0 {
+		config.CampaignPromotion.CheckIntervalSeconds = interval
+	}
+	if interval := getEnvAsInt("CAMPAIGN_SCHEDULE_CHECK_INTERVAL_SECONDS", 0); interval > 0 {
+		config.CampaignSchedules.CheckIntervalSeconds = interval
+	}
+
+	// Campaign update overrides
+	if os.Getenv("CAMPAIGN_ENFORCE_IMMUTABLE_FIELDS") != "" {
+		enabled := getEnvAsBool("CAMPAIGN_ENFORCE_IMMUTABLE_FIELDS", true)
+		config.CampaignUpdates.EnforceImmutableFields = &enabled
+	}
+
+	// Campaign status overrides
+	if maxIDs := getEnvAsInt("CAMPAIGN_STATUS_MAX_BULK_IDS", 0); maxIDs > 0 {
+		config.CampaignStatus.MaxBulkIDs = maxIDs
+	}
+
+	// Login rate overrides
+	if window := getEnvAsInt("LOGIN_RATE_WINDOW_SECONDS", 0); window > 0 {
+		config.LoginRates.WindowSeconds = window
+	}
+	if maxIPs := getEnvAsInt("LOGIN_RATE_MAX_TRACKED_IPS", 0); maxIPs > 0 {
+		config.LoginRates.MaxTrackedIPs = maxIPs
+	}
+
+	// Login rate limit overrides
+	if os.Getenv("LOGIN_RATE_LIMIT_ENABLED") != "" {
+		enabled := getEnvAsBool("LOGIN_RATE_LIMIT_ENABLED", true)
+		config.LoginRateLimit.Enabled = &enabled
+	}
+	if window := getEnvAsInt("LOGIN_RATE_LIMIT_WINDOW_SECONDS", 0); window > 0 {
+		config.LoginRateLimit.WindowSeconds = window
+	}
+	if maxFailures := getEnvAsInt("LOGIN_MAX_FAILURES_PER_IP", 0); maxFailures > 0 {
+		config.LoginRateLimit.MaxFailuresPerIP = maxFailures
+	}
+	if maxFailures := getEnvAsInt("LOGIN_MAX_FAILURES_PER_ACCOUNT", 0); maxFailures > 0 {
+		config.LoginRateLimit.MaxFailuresPerAccount = maxFailures
+	}
+	if block := getEnvAsInt("LOGIN_RATE_LIMIT_BLOCK_SECONDS", 0); block > 0 {
+		config.LoginRateLimit.BlockSeconds = block
+	}
+
+	// Response compression overrides
+	if os.Getenv("COMPRESSION_ENABLED") != "" {
+		enabled := getEnvAsBool("COMPRESSION_ENABLED", true)
+		config.Compression.Enabled = &enabled
+	}
+	if minSize := getEnvAsInt("COMPRESSION_MIN_SIZE_BYTES", 0); minSize > 0 {
+		config.Compression.MinSizeBytes = minSize
+	}
+	if level := getEnvAsInt("COMPRESSION_LEVEL", 0); level > 0 {
+		config.Compression.Level = level
+	}
+
+	// Route rate limit overrides
+	if os.Getenv("ROUTE_RATE_LIMIT_ENABLED") != "" {
+		enabled := getEnvAsBool("ROUTE_RATE_LIMIT_ENABLED", true)
+		config.RouteRateLimit.Enabled = &enabled
+	}
+	if store := os.Getenv("ROUTE_RATE_LIMIT_STORE"); store != "" {
+		config.RouteRateLimit.Store = store
+	}
+	if redisURL := os.Getenv("ROUTE_RATE_LIMIT_REDIS_URL"); redisURL != "" {
+		config.RouteRateLimit.RedisURL = redisURL
+	}
+	if prefix := os.Getenv("ROUTE_RATE_LIMIT_REDIS_KEY_PREFIX"); prefix != "" {
+		config.RouteRateLimit.RedisKeyPrefix = prefix
+	}
+	if rate := getEnvAsInt("CAMPAIGN_CREATE_RATE_PER_MINUTE", 0); rate > 0 {
+		config.RouteRateLimit.CampaignCreate.RequestsPerMinute = rate
+	}
+	if burst := getEnvAsInt("CAMPAIGN_CREATE_RATE_BURST", 0); burst > 0 {
+		config.RouteRateLimit.CampaignCreate.Burst = burst
+	}
+	if rate := getEnvAsInt("PERSONA_TEST_RATE_PER_MINUTE", 0); rate > 0 {
+		config.RouteRateLimit.PersonaTest.RequestsPerMinute = rate
+	}
+	if burst := getEnvAsInt("PERSONA_TEST_RATE_BURST", 0); burst > 0 {
+		config.RouteRateLimit.PersonaTest.Burst = burst
+	}
+
+	// Re-classification overrides
+	if batchSize := getEnvAsInt("RECLASSIFICATION_BATCH_SIZE", 0); batchSize > 0 {
+		config.Reclassification.BatchSize = batchSize
+	}
+
+	// Tracing overrides, named after the OpenTelemetry SDK variables
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+		config.Tracing.OTLPEndpoint = endpoint
+	}
+	if os.Getenv("OTEL_EXPORTER_OTLP_INSECURE") != "" {
+		config.Tracing.Insecure = getEnvAsBool("OTEL_EXPORTER_OTLP_INSECURE", false)
+	}
+	if serviceName := os.Getenv("OTEL_SERVICE_NAME"); serviceName != "" {
+		config.Tracing.ServiceName = serviceName
+	}
+
+	// Audit overrides
+	if os.Getenv("AUDIT_CAMPAIGN_STATUS_TRANSITIONS") != "" {
+		enabled := getEnvAsBool("AUDIT_CAMPAIGN_STATUS_TRANSITIONS", true)
+		config.Audit.CampaignStatusTransitions = &enabled
+	}
+	if batchSize := getEnvAsInt("AUDIT_EXPORT_BATCH_SIZE", 0); batchSize > 0 {
+		config.Audit.ExportBatchSize = batchSize
+	}
+	if maxExports := getEnvAsInt("AUDIT_MAX_CONCURRENT_EXPORTS", 0); maxExports > 0 {
+		config.Audit.MaxConcurrentExports = maxExports
+	}
 }
 
 // Helper functions
@@ -175,6 +547,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		value = strings.ToLower(value)
@@ -183,6 +564,22 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// parseLegacyPeppers reads comma-separated version=pepper pairs, such as "2=old-secret,3=older". An
+// empty pepper stands for hashes made without one. Malformed pairs are skipped.
+func parseLegacyPeppers(value string) map[int]string {
+	peppers := make(map[int]string)
+	for _, pair := range strings.Split(value, ",") {
+		version, pepper, found := strings.Cut(pair, "=")
+		if !found {
+			continue
+		}
+		if v, err := strconv.Atoi(strings.TrimSpace(version)); err == nil && v > 0 {
+			peppers[v] = pepper
+		}
+	}
+	return peppers
+}
+
 // GetDatabaseDSN returns the database connection string
 func GetDatabaseDSN(config *DatabaseConfig) string {
 	return fmt.Sprintf(
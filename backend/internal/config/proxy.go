@@ -17,7 +17,7 @@ type ProxyConfigEntry struct {
 	Protocol    string `json:"protocol"` // e.g., "http", "https", "socks5"
 	Address     string `json:"address"`  // e.g., "127.0.0.1:8080"
 	Username    string `json:"username,omitempty"`
-	Password    string `json:"password,omitempty"`
+	Password    string `json:"password,omitempty" redact:"true"`
 	Notes       string `json:"notes,omitempty"`
 	UserEnabled *bool  `json:"userEnabled,omitempty"` // Pointer to distinguish not set, true, false. Defaults to true if nil.
 }
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// RedactedValue replaces the value of a set secret in redacted configuration
+const RedactedValue = "[REDACTED]"
+
+// Redact returns a JSON-ready copy of a configuration value for display. Structs become maps keyed
+// by their JSON field names; fields tagged `redact:"true"` are replaced by RedactedValue when set,
+// so a reader can tell a configured secret from a missing one without seeing it. Durations are
+// written in Go duration syntax, and fields tagged `json:"-"` or unexported are left out.
+func Redact(v interface{}) interface{} {
+	return redactValue(reflect.ValueOf(v))
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func redactValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem())
+	case reflect.Struct:
+		out := make(map[string]interface{}, v.NumField())
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name := field.Name
+			if tag := field.Tag.Get("json"); tag != "" {
+				if tag == "-" {
+					continue
+				}
+				if jsonName, _, _ := strings.Cut(tag, ","); jsonName != "" {
+					name = jsonName
+				}
+			}
+			if field.Tag.Get("redact") == "true" {
+				if v.Field(i).IsZero() {
+					out[name] = ""
+				} else {
+					out[name] = RedactedValue
+				}
+				continue
+			}
+			out[name] = redactValue(v.Field(i))
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = redactValue(v.Index(i))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = redactValue(iter.Value())
+		}
+		return out
+	default:
+		return v.Interface()
+	}
+}
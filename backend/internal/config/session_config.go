@@ -1,9 +1,38 @@
 package config
 
 import (
+	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// Device binding modes for SessionSettings.DeviceBinding
+const (
+	DeviceBindingOff      = "off"      // Device keys are ignored
+	DeviceBindingOptional = "optional" // Sessions are bound when the login supplies a device key
+	DeviceBindingRequired = "required" // Every login must supply a device key
+
+	DefaultDeviceSignatureMaxSkew = 5 * time.Minute
+
+	// Session stores for SessionSettings.Store
+	SessionStoreMemory = "memory" // Sessions are cached by each instance
+	SessionStoreRedis  = "redis"  // Sessions are shared through Redis at RedisURL
+
+	// DefaultMaxCachedSessions bounds the in-memory session cache; least recently used sessions beyond it
+	// are dropped from memory and reloaded from the database on their next request
+	DefaultMaxCachedSessions = 10000
+
+	// DefaultPermissionCacheTTL is how long a user's roles and permissions are reused before being
+	// queried again; DefaultMaxCachedPermissionUsers bounds how many users are kept
+	DefaultPermissionCacheTTL       = 30 * time.Second
+	DefaultMaxCachedPermissionUsers = 10000
+
+	// DefaultMetricsSnapshotInterval is how often cumulative session metrics are saved to the
+	// database so they survive restarts
+	DefaultMetricsSnapshotInterval = time.Minute
+)
+
 // SessionSettings contains all session-related configuration
 type SessionSettings struct {
 	// Session duration and timeouts
@@ -12,11 +41,33 @@ type SessionSettings struct {
 	CleanupInterval      time.Duration `json:"cleanup_interval"`
 	MaxSessionsPerUser   int           `json:"max_sessions_per_user"`
 	SessionIDLength      int           `json:"session_id_length"`
+	MaxCachedSessions    int           `json:"max_cached_sessions"` // 0 disables the bound
+	// Concurrent validations of a session missing from the store share one database load
+	DeduplicateLoads bool `json:"deduplicate_loads"`
+
+	// Session store: memory, or redis so that every instance validates sessions created on any of them
+	Store          string `json:"store"`
+	RedisURL       string `json:"redis_url" redact:"true"` // e.g. redis://:password@host:6379/0
+	RedisKeyPrefix string `json:"redis_key_prefix"` // Empty uses the store's default
+
+	// Permission cache: roles and permissions reused across session creation, reloads and /me
+	PermissionCacheTTL       time.Duration `json:"permission_cache_ttl"`        // 0 disables the cache
+	MaxCachedPermissionUsers int           `json:"max_cached_permission_users"` // 0 disables the bound
+
+	// Cumulative session metrics are saved on this interval and at shutdown, and restored at start
+	MetricsSnapshotInterval time.Duration `json:"metrics_snapshot_interval"` // 0 keeps metrics in memory only
 
 	// Security settings
 	RequireIPMatch       bool `json:"require_ip_match"`
 	RequireUAMatch       bool `json:"require_ua_match"`
 	EnableFingerprinting bool `json:"enable_fingerprinting"`
+	// Requests on a session are scored 0-10 for IP, browser and idle-time changes; a session scoring
+	// above the threshold is revoked. 0 revokes none.
+	RiskRevokeThreshold int `json:"risk_revoke_threshold"`
+
+	// Device binding: bound sessions must sign every request with the device key sent at login
+	DeviceBinding          string        `json:"device_binding"`            // off, optional or required
+	DeviceSignatureMaxSkew time.Duration `json:"device_signature_max_skew"` // Maximum age of a signed request timestamp
 
 	// Cookie settings
 	CookieName           string `json:"cookie_name"`
@@ -39,6 +90,10 @@ type SessionSettings struct {
 	RateLimitWindow        time.Duration `json:"rate_limit_window"`
 	MaxLoginAttempts       int           `json:"max_login_attempts"`
 	MaxSessionValidations  int           `json:"max_session_validations"`
+	// Tell a client whose login meets a lockout when it ends. Unknown emails are locked out alike.
+	LockoutDetails bool `json:"lockout_details"`
+	// Describe the new session and the device it came from in the login response
+	LoginSessionContext bool `json:"login_session_context"`
 }
 
 // SessionConfig holds configuration for session management
@@ -48,8 +103,17 @@ type SessionConfig struct {
 	CleanupInterval    time.Duration // 5 minutes
 	MaxSessionsPerUser int           // 5 sessions per user
 	SessionIDLength    int           // 128 characters
+	MaxCachedSessions  int           // Sessions kept in memory before LRU eviction, 0 for no bound
+	PermissionCacheTTL       time.Duration // How long loaded roles and permissions are reused, 0 to disable
+	MaxCachedPermissionUsers int           // Users whose permissions are kept in memory, 0 for no bound
+	MetricsSnapshotInterval  time.Duration // How often cumulative metrics are saved, 0 to keep them in memory only
+	DeduplicateLoads         bool          // Whether concurrent validations of a session missing from the store share one database load
 	RequireIPMatch     bool          // Whether to require IP address match
 	RequireUAMatch     bool          // Whether to require user agent match
+	RiskRevokeThreshold int          // Sessions scoring above it (0-10) are revoked, 0 to revoke none
+
+	DeviceBinding          string        // off, optional or required
+	DeviceSignatureMaxSkew time.Duration // Maximum age of a signed request timestamp
 }
 
 // Cookie configuration
@@ -66,12 +130,23 @@ func GetDefaultSessionSettings() *SessionSettings {
 		CleanupInterval:      5 * time.Minute,
 		MaxSessionsPerUser:   5,
 		SessionIDLength:      128,
+		MaxCachedSessions:    DefaultMaxCachedSessions,
+		DeduplicateLoads:     true,
+		Store:                SessionStoreMemory,
+
+		PermissionCacheTTL:       DefaultPermissionCacheTTL,
+		MaxCachedPermissionUsers: DefaultMaxCachedPermissionUsers,
+		MetricsSnapshotInterval:  DefaultMetricsSnapshotInterval,
 
 		// Security settings - conservative defaults
 		RequireIPMatch:       false, // Disabled for flexibility with mobile/proxy usage
 		RequireUAMatch:       false, // Disabled for flexibility with browser updates
 		EnableFingerprinting: true,  // Enabled for enhanced security
 
+		// Device binding is opt-in per deployment
+		DeviceBinding:          DeviceBindingOff,
+		DeviceSignatureMaxSkew: DefaultDeviceSignatureMaxSkew,
+
 		// Cookie settings for session-only authentication
 		CookieName:     SessionCookieName,
 		CookiePath:     CookiePath,
@@ -96,6 +171,8 @@ func GetDefaultSessionSettings() *SessionSettings {
 		RateLimitWindow:       15 * time.Minute,
 		MaxLoginAttempts:      10,
 		MaxSessionValidations: 1000, // High limit for normal session validation
+		LockoutDetails:        true,
+		LoginSessionContext:   true,
 	}
 }
 
@@ -141,8 +218,62 @@ func (s *SessionSettings) ToServiceConfig() *SessionConfig {
 		CleanupInterval:    s.CleanupInterval,
 		MaxSessionsPerUser: s.MaxSessionsPerUser,
 		SessionIDLength:    s.SessionIDLength,
+		MaxCachedSessions:  s.MaxCachedSessions,
+		PermissionCacheTTL:       s.PermissionCacheTTL,
+		MaxCachedPermissionUsers: s.MaxCachedPermissionUsers,
+		MetricsSnapshotInterval:  s.MetricsSnapshotInterval,
+		DeduplicateLoads:         s.DeduplicateLoads,
 		RequireIPMatch:     s.RequireIPMatch,
 		RequireUAMatch:     s.RequireUAMatch,
+		RiskRevokeThreshold: s.RiskRevokeThreshold,
+
+		DeviceBinding:          s.DeviceBinding,
+		DeviceSignatureMaxSkew: s.DeviceSignatureMaxSkew,
+	}
+}
+
+// ApplyEnvironmentOverrides applies session settings from the environment
+func (s *SessionSettings) ApplyEnvironmentOverrides() {
+	switch mode := strings.ToLower(strings.TrimSpace(os.Getenv("SESSION_DEVICE_BINDING"))); mode {
+	case DeviceBindingOff, DeviceBindingOptional, DeviceBindingRequired:
+		s.DeviceBinding = mode
+	}
+	if skew, err := time.ParseDuration(os.Getenv("SESSION_DEVICE_SIGNATURE_MAX_SKEW")); err == nil && skew > 0 {
+		s.DeviceSignatureMaxSkew = skew
+	}
+	if maxCached, err := strconv.Atoi(os.Getenv("SESSION_MAX_CACHED_SESSIONS")); err == nil && maxCached >= 0 {
+		s.MaxCachedSessions = maxCached
+	}
+	if dedup, err := strconv.ParseBool(os.Getenv("SESSION_DEDUPLICATE_LOADS")); err == nil {
+		s.DeduplicateLoads = dedup
+	}
+	switch sessionStore := strings.ToLower(strings.TrimSpace(os.Getenv("SESSION_STORE"))); sessionStore {
+	case SessionStoreMemory, SessionStoreRedis:
+		s.Store = sessionStore
+	}
+	if redisURL := os.Getenv("SESSION_REDIS_URL"); redisURL != "" {
+		s.RedisURL = redisURL
+	}
+	if prefix := os.Getenv("SESSION_REDIS_KEY_PREFIX"); prefix != "" {
+		s.RedisKeyPrefix = prefix
+	}
+	if ttl, err := time.ParseDuration(os.Getenv("SESSION_PERMISSION_CACHE_TTL")); err == nil && ttl >= 0 {
+		s.PermissionCacheTTL = ttl
+	}
+	if maxUsers, err := strconv.Atoi(os.Getenv("SESSION_MAX_CACHED_PERMISSION_USERS")); err == nil && maxUsers >= 0 {
+		s.MaxCachedPermissionUsers = maxUsers
+	}
+	if interval, err := time.ParseDuration(os.Getenv("SESSION_METRICS_SNAPSHOT_INTERVAL")); err == nil && interval >= 0 {
+		s.MetricsSnapshotInterval = interval
+	}
+	if details, err := strconv.ParseBool(os.Getenv("SESSION_LOCKOUT_DETAILS")); err == nil {
+		s.LockoutDetails = details
+	}
+	if threshold, err := strconv.Atoi(os.Getenv("SESSION_RISK_REVOKE_THRESHOLD")); err == nil && threshold >= 0 {
+		s.RiskRevokeThreshold = threshold
+	}
+	if sessionContext, err := strconv.ParseBool(os.Getenv("SESSION_LOGIN_CONTEXT")); err == nil {
+		s.LoginSessionContext = sessionContext
 	}
 }
 
@@ -35,21 +35,443 @@ type WorkerConfig struct {
 	JobProcessingTimeoutMinutes   int `json:"jobProcessingTimeoutMinutes,omitempty"`
 	DNSSubtaskConcurrency         int `json:"dnsSubtaskConcurrency,omitempty"`         // Added
 	HTTPKeywordSubtaskConcurrency int `json:"httpKeywordSubtaskConcurrency,omitempty"` // Added
+	ResultCommitChunkSize         int `json:"resultCommitChunkSize,omitempty"`         // Rows committed per transaction when saving validation results
+	MaxAttemptsPerDomain          int `json:"maxAttemptsPerDomain,omitempty"`          // Recorded attempts after which a domain is no longer re-validated; negative disables the cap
+	// Check that an HTTP keyword campaign's resume pointer is still among its source domains before resuming (default true)
+	VerifyHTTPResumePoint *bool `json:"verifyHttpResumePoint,omitempty"`
+	// Workers are reported stalled when jobs are queued but none has completed for this long (default 1800)
+	HealthStallWindowSeconds int `json:"healthStallWindowSeconds,omitempty"`
+	// Fail readiness checks while the workers are stopped or stalled (default true)
+	HealthInReadiness *bool `json:"healthInReadiness,omitempty"`
+	// Test a campaign's personas and proxies once when it starts and reuse the results for the campaign (default false)
+	PrewarmResourceHealth *bool `json:"prewarmResourceHealth,omitempty"`
+	PrewarmTimeoutSeconds int   `json:"prewarmTimeoutSeconds,omitempty"` // Time box for the start-up tests (default 30)
+	PrewarmConcurrency    int   `json:"prewarmConcurrency,omitempty"`    // Start-up tests run at once (default 10)
+	// A queued job's fetch priority rises by one for each interval it waits, so low-priority jobs are
+	// not starved by newer high-priority ones (default 60; negative disables aging)
+	PriorityAgingIntervalSeconds int `json:"priorityAgingIntervalSeconds,omitempty"`
+	// Jobs of one campaign processed at once across all workers, so a large campaign cannot take the
+	// whole pool (default 0, unlimited)
+	MaxConcurrentJobsPerCampaign int `json:"maxConcurrentJobsPerCampaign,omitempty"`
+	// How often processing jobs without a heartbeat for jobProcessingTimeoutMinutes are set back to
+	// retry (default 60; negative disables the reaper)
+	StaleJobReapIntervalSeconds int `json:"staleJobReapIntervalSeconds,omitempty"`
+	// A failed job's nth retry waits BackoffBaseSeconds*BackoffMultiplier^(n-1), at most BackoffMaxSeconds,
+	// give or take 20% (defaults: errorRetryDelaySeconds, 600 and 2)
+	BackoffBaseSeconds int     `json:"backoffBaseSeconds,omitempty"`
+	BackoffMaxSeconds  int     `json:"backoffMaxSeconds,omitempty"`
+	BackoffMultiplier  float64 `json:"backoffMultiplier,omitempty"`
+}
+
+// JobRetryBackoff returns the wait before a failed job's retry, without jitter. retry counts the
+// retries already made, so the first retry waits the base delay.
+func (c WorkerConfig) JobRetryBackoff(retry int) time.Duration {
+	base := c.BackoffBaseSeconds
+	if base <= 0 {
+		base = c.ErrorRetryDelaySeconds
+	}
+	if base <= 0 {
+		base = DefaultErrorRetryDelaySeconds
+	}
+	maxDelay := c.BackoffMaxSeconds
+	if maxDelay <= 0 {
+		maxDelay = DefaultJobBackoffMaxSeconds
+	}
+	multiplier := c.BackoffMultiplier
+	if multiplier < 1 {
+		multiplier = DefaultJobBackoffMultiplier
+	}
+	delay := float64(base)
+	for i := 0; i < retry && delay < float64(maxDelay); i++ {
+		delay *= multiplier
+	}
+	if delay > float64(maxDelay) {
+		delay = float64(maxDelay)
+	}
+	return time.Duration(delay * float64(time.Second))
+}
+
+// HTTPResumeCheckEnabled reports whether HTTP keyword batches verify their resume pointer
+func (c WorkerConfig) HTTPResumeCheckEnabled() bool {
+	return boolOrDefault(c.VerifyHTTPResumePoint, true)
+}
+
+// ResourcePrewarmEnabled reports whether persona and proxy health is tested once when a campaign starts
+func (c WorkerConfig) ResourcePrewarmEnabled() bool {
+	return boolOrDefault(c.PrewarmResourceHealth, false)
+}
+
+// HealthInReadinessEnabled reports whether worker health is part of the readiness check
+func (c WorkerConfig) HealthInReadinessEnabled() bool {
+	return boolOrDefault(c.HealthInReadiness, true)
+}
+
+// WebhookConfig defines settings for outbound webhook event delivery.
+type WebhookConfig struct {
+	SigningSecret  string                `json:"signingSecret,omitempty" redact:"true"`
+	TimeoutSeconds int                   `json:"timeoutSeconds,omitempty"`
+	Results        ResultWebhookConfig   `json:"results,omitempty"`
+	Campaigns      CampaignWebhookConfig `json:"campaigns,omitempty"`
+}
+
+// CampaignWebhookConfig defines how the webhooks registered on a campaign are notified once the
+// campaign completes, fails or is cancelled.
+type CampaignWebhookConfig struct {
+	CheckIntervalSeconds int `json:"checkIntervalSeconds,omitempty"` // Time between sweeps for terminal campaigns with webhooks still to notify
+	MaxAttempts          int `json:"maxAttempts,omitempty"`          // Delivery attempts per notification before it is given up
+	RetryBackoffSeconds  int `json:"retryBackoffSeconds,omitempty"`  // Wait before the first retry, doubling for each further retry
+}
+
+// ResultWebhookConfig streams new DNS and HTTP validation results to a webhook in batches as they
+// are saved. It is off unless URL is set.
+type ResultWebhookConfig struct {
+	URL                  string `json:"url,omitempty"`
+	BatchSize            int    `json:"batchSize,omitempty"`            // Results per request; a full batch is sent at once
+	FlushIntervalSeconds int    `json:"flushIntervalSeconds,omitempty"` // Time after which a partial batch is sent
+	MaxAttempts          int    `json:"maxAttempts,omitempty"`          // Delivery attempts per batch before it is given up
+	RetryBackoffSeconds  int    `json:"retryBackoffSeconds,omitempty"`  // Wait before the first retry, doubling for each further retry
+	MaxBufferedResults   int    `json:"maxBufferedResults,omitempty"`   // Results held while the webhook is slow or down; the oldest are dropped beyond it
+}
+
+// ProxyHealthConfig defines when failing proxies are quarantined and rechecked, and how stored proxies
+// are health checked.
+type ProxyHealthConfig struct {
+	QuarantineThreshold       int `json:"quarantineThreshold,omitempty"`       // Consecutive failures before a proxy is quarantined
+	QuarantineCooldownSeconds int `json:"quarantineCooldownSeconds,omitempty"` // Time before a quarantined proxy is re-tested
+
+	CheckEnabled          *bool  `json:"checkEnabled,omitempty"`          // Check every enabled stored proxy on a schedule (default false)
+	CheckIntervalSeconds  int    `json:"checkIntervalSeconds,omitempty"`  // Time between checks of a proxy (default 120)
+	CheckTargetURL        string `json:"checkTargetUrl,omitempty"`        // URL fetched through each proxy (default https://example.com/)
+	CheckTimeoutSeconds   int    `json:"checkTimeoutSeconds,omitempty"`   // Time box for one check (default 10)
+	CheckConcurrency      int    `json:"checkConcurrency,omitempty"`      // Checks run at once (default 5)
+	CheckFailureThreshold int    `json:"checkFailureThreshold,omitempty"` // Consecutive failed checks before a proxy is marked unhealthy (default 3)
+}
+
+// ScheduledChecksEnabled reports whether stored proxies are health checked on a schedule
+func (c ProxyHealthConfig) ScheduledChecksEnabled() bool {
+	return boolOrDefault(c.CheckEnabled, false)
+}
+
+// CircuitBreakerConfig defines when outbound DNS/HTTP requests to a failing host are short-circuited.
+type CircuitBreakerConfig struct {
+	FailureThreshold int `json:"failureThreshold,omitempty"` // Consecutive failures before a host's circuit opens; negative disables the breaker
+	CooldownSeconds  int `json:"cooldownSeconds,omitempty"`  // Time an open circuit waits before letting a probe request through
+}
+
+// OutboundRateLimitConfig spaces outbound DNS/HTTP requests to the same target host and zone.
+type OutboundRateLimitConfig struct {
+	PerHostRequestsPerSecond float64 `json:"perHostRequestsPerSecond,omitempty"` // Requests per second to a single host; negative disables the host limit
+	PerZoneRequestsPerSecond float64 `json:"perZoneRequestsPerSecond,omitempty"` // Requests per second across a registrable domain and its subdomains; negative disables the zone limit
+}
+
+// CampaignSafetyConfig stops a validation campaign whose items are mostly failing, e.g. because of a
+// bad persona or resolver setup. It is off unless ErrorRateThreshold is set.
+type CampaignSafetyConfig struct {
+	ErrorRateThreshold float64 `json:"errorRateThreshold,omitempty"` // Fraction of processed items (0-1] allowed to fail; zero or negative disables the check
+	MinSampleSize      int     `json:"minSampleSize,omitempty"`      // Items processed before the failure rate is evaluated
+	Action             string  `json:"action,omitempty"`             // "pause" (default) or "fail"
+}
+
+// StartupConfig controls the startup steps that must finish before the server reports ready.
+// Unset toggles keep their defaults: the database ping, workers and session service are on and
+// migration verification is off.
+type StartupConfig struct {
+	PingDatabase          *bool `json:"pingDatabase,omitempty"`          // Wait for the database to answer a ping
+	DBPingAttempts        int   `json:"dbPingAttempts,omitempty"`        // Pings tried before startup fails
+	DBPingIntervalSeconds int   `json:"dbPingIntervalSeconds,omitempty"` // Delay between failed pings
+	VerifyMigrations      *bool `json:"verifyMigrations,omitempty"`      // Verify applied migrations against the migration files
+	StartWorkers          *bool `json:"startWorkers,omitempty"`          // Start the campaign workers on this instance
+	StartSessionService   *bool `json:"startSessionService,omitempty"`   // Start the session service's expiry cleanup
+}
+
+// PingDatabaseEnabled reports whether startup waits for the database
+func (c StartupConfig) PingDatabaseEnabled() bool {
+	return boolOrDefault(c.PingDatabase, true)
+}
+
+// VerifyMigrationsEnabled reports whether startup verifies migrations
+func (c StartupConfig) VerifyMigrationsEnabled() bool {
+	return boolOrDefault(c.VerifyMigrations, false)
+}
+
+// StartWorkersEnabled reports whether startup starts the campaign workers
+func (c StartupConfig) StartWorkersEnabled() bool {
+	return boolOrDefault(c.StartWorkers, true)
+}
+
+// StartSessionServiceEnabled reports whether startup starts the session service
+func (c StartupConfig) StartSessionServiceEnabled() bool {
+	return boolOrDefault(c.StartSessionService, true)
+}
+
+// PasswordHashConfig holds the password pepper and controls how failed logins on incompatible
+// password hashes are diagnosed.
+type PasswordHashConfig struct {
+	DiagnoseMismatches    *bool          `json:"diagnoseMismatches,omitempty"`          // Log and flag accounts whose hash the login path cannot verify (default true)
+	ExpectedPepperVersion int            `json:"expectedPepperVersion,omitempty"`       // password_pepper_version of new hashes (default 2; 1 marks unpeppered hashes)
+	LegacyPepperGraceDays int            `json:"legacyPepperGraceDays,omitempty"`       // Days after a password was set on a legacy pepper before a change is forced (default 30)
+	Pepper                string         `json:"pepper,omitempty" redact:"true"`        // HMAC key passwords are keyed with before hashing; required, at least 32 bytes
+	LegacyPeppers         map[int]string `json:"legacyPeppers,omitempty" redact:"true"` // Peppers of earlier versions, used only to verify logins before rehashing
+	HistorySize           int            `json:"historySize,omitempty"`                 // Previous passwords of a user a new password may not reuse (default 5)
+}
+
+// MismatchDiagnosticsEnabled reports whether failed logins check the stored hash's scheme and pepper version
+func (c PasswordHashConfig) MismatchDiagnosticsEnabled() bool {
+	return boolOrDefault(c.DiagnoseMismatches, true)
+}
+
+// PasswordPolicyConfig sets the rules a new password must meet when a user changes it or an account
+// is created. Passwords already set are not checked, so a stricter policy applies from their next change.
+type PasswordPolicyConfig struct {
+	MinLength        int      `json:"minLength,omitempty"`        // Fewest characters (default 12)
+	MaxLength        int      `json:"maxLength,omitempty"`        // Most characters; 0 for no limit (default 0)
+	RequireUppercase bool     `json:"requireUppercase,omitempty"` // At least one upper-case letter (default false)
+	RequireLowercase bool     `json:"requireLowercase,omitempty"` // At least one lower-case letter (default false)
+	RequireDigit     bool     `json:"requireDigit,omitempty"`     // At least one digit (default false)
+	RequireSymbol    bool     `json:"requireSymbol,omitempty"`    // At least one character that is not a letter, digit or space (default false)
+	Denylist         []string `json:"denylist,omitempty"`         // Common passwords refused whatever the other rules, compared case-insensitively
+	DenylistFile     string   `json:"denylistFile,omitempty"`     // File of further refused passwords, one per line
+}
+
+// PersonaTestConfig controls the scheduled self-test of enabled personas and the tests run on demand.
+type PersonaTestConfig struct {
+	Enabled                *bool  `json:"enabled,omitempty"`                // Test every enabled persona on a schedule (default false)
+	IntervalSeconds        int    `json:"intervalSeconds,omitempty"`        // Time between tests of a persona (default 300)
+	Concurrency            int    `json:"concurrency,omitempty"`            // Tests run at once (default 5)
+	TimeoutSeconds         int    `json:"timeoutSeconds,omitempty"`         // Time box for one scheduled test (default 15)
+	OnDemandTimeoutSeconds int    `json:"onDemandTimeoutSeconds,omitempty"` // Time box for a test requested through the API (default 5)
+	HistorySize            int    `json:"historySize,omitempty"`            // Results kept per persona (default 50)
+	DNSProbeDomain         string `json:"dnsProbeDomain,omitempty"`         // Domain DNS personas resolve (default example.com)
+	HTTPProbeURL           string `json:"httpProbeUrl,omitempty"`           // URL HTTP personas fetch (default https://example.com/)
+}
+
+// ScheduledTestsEnabled reports whether personas are tested on a schedule
+func (c PersonaTestConfig) ScheduledTestsEnabled() bool {
+	return boolOrDefault(c.Enabled, false)
+}
+
+// CampaignRetryConfig controls the automatic re-run of failed campaigns created with autoRetry.
+type CampaignRetryConfig struct {
+	MaxRetries           int `json:"maxRetries,omitempty"`           // Retry runs after the original run (default 3; negative disables retries)
+	BackoffSeconds       int `json:"backoffSeconds,omitempty"`       // Wait after the first failure, doubled for each further retry (default 60)
+	MaxBackoffSeconds    int `json:"maxBackoffSeconds,omitempty"`    // Upper bound on the wait (default 3600)
+	CheckIntervalSeconds int `json:"checkIntervalSeconds,omitempty"` // How often failed campaigns are checked (default 30)
+}
+
+// CampaignPromotionConfig controls how completed domain generation campaigns created with a
+// dnsPromotion start their DNS validation campaign.
+type CampaignPromotionConfig struct {
+	CheckIntervalSeconds int `json:"checkIntervalSeconds,omitempty"` // How often completed campaigns are checked (default 15)
+}
+
+// CampaignScheduleConfig controls how scheduled campaigns are started. Each due schedule is claimed
+// by one server for lockSeconds, so several servers never fire it twice.
+type CampaignScheduleConfig struct {
+	CheckIntervalSeconds int `json:"checkIntervalSeconds,omitempty"` // How often due schedules are checked (default 30)
+	LockSeconds          int `json:"lockSeconds,omitempty"`          // How long a server holds a schedule it is firing (default 300)
+}
+
+// CampaignUpdateConfig controls which campaign fields may be changed after a campaign has started.
+type CampaignUpdateConfig struct {
+	// Reject changes to a started campaign's generation pattern, sources, personas and other
+	// processing parameters (default true)
+	EnforceImmutableFields *bool `json:"enforceImmutableFields,omitempty"`
+}
+
+// ImmutableFieldsEnforced reports whether updates to a started campaign's core parameters are rejected
+func (c CampaignUpdateConfig) ImmutableFieldsEnforced() bool {
+	return boolOrDefault(c.EnforceImmutableFields, true)
+}
+
+// CampaignStatusConfig controls the bulk campaign status endpoint.
+type CampaignStatusConfig struct {
+	MaxBulkIDs int `json:"maxBulkIds,omitempty"` // Campaign IDs one bulk status request may ask for (default 100)
+}
+
+// Stored fields of an HTTP keyword result that re-classification can match keywords against
+const (
+	ReclassificationFieldPageTitle       = "pageTitle"
+	ReclassificationFieldContentSnippet  = "contentSnippet"
+	ReclassificationFieldResponseHeaders = "responseHeaders"
+)
+
+// ReclassificationConfig controls re-running keyword classification over stored HTTP keyword
+// results without fetching the pages again.
+type ReclassificationConfig struct {
+	BatchSize   int      `json:"batchSize,omitempty"`   // Results read and updated per query (default 500)
+	MatchFields []string `json:"matchFields,omitempty"` // Stored fields matched against the keywords (default pageTitle and contentSnippet)
+}
+
+// TracingConfig controls OpenTelemetry tracing of requests, campaign operations, store queries and
+// worker jobs. Tracing is off, at no cost, unless OTLPEndpoint is set.
+type TracingConfig struct {
+	OTLPEndpoint string  `json:"otlpEndpoint,omitempty"` // host:port of the OTLP/HTTP collector spans are exported to
+	Insecure     bool    `json:"insecure,omitempty"`     // Export over plain HTTP instead of HTTPS
+	ServiceName  string  `json:"serviceName,omitempty"`  // service.name of the exported spans (default domainflow-api)
+	SampleRatio  float64 `json:"sampleRatio,omitempty"`  // Share of new traces recorded, 0 to 1 (default 1); requests carrying a sampled parent are always recorded
+}
+
+// AuditConfig controls optional audit log entries.
+type AuditConfig struct {
+	CampaignStatusTransitions *bool `json:"campaignStatusTransitions,omitempty"` // Record every campaign status change with its actor (default true)
+	ExportBatchSize           int   `json:"exportBatchSize,omitempty"`           // Entries read per query while streaming an audit log export (default 1000)
+	MaxConcurrentExports      int   `json:"maxConcurrentExports,omitempty"`      // Audit log exports allowed to run at once; more are refused with 429 (default 2)
+}
+
+// CampaignStatusTransitionsEnabled reports whether campaign status changes are audited
+func (c AuditConfig) CampaignStatusTransitionsEnabled() bool {
+	return boolOrDefault(c.CampaignStatusTransitions, true)
+}
+
+// LoginRateConfig controls the rolling login outcome counters kept for security dashboards.
+type LoginRateConfig struct {
+	WindowSeconds int `json:"windowSeconds,omitempty"` // Length of the rolling window the rates cover (default 300)
+	MaxTrackedIPs int `json:"maxTrackedIps,omitempty"` // Client IPs counted separately; the least recently seen are dropped first (default 10000)
+}
+
+// LoginRateLimitConfig limits failed logins per client IP and per account, each with its own
+// threshold. Either one tripping blocks the attempt, so rotating addresses does not help against one
+// account, while users sharing a NAT address get a larger allowance than any single account.
+type LoginRateLimitConfig struct {
+	Enabled               *bool `json:"enabled,omitempty"`               // Enforce the limits (default true)
+	WindowSeconds         int   `json:"windowSeconds,omitempty"`         // Window failures are counted in (default 900)
+	MaxFailuresPerIP      int   `json:"maxFailuresPerIp,omitempty"`      // Failures from one IP in a window before it is blocked (default 50)
+	MaxFailuresPerAccount int   `json:"maxFailuresPerAccount,omitempty"` // Failures against one account in a window before it is blocked (default 10)
+	BlockSeconds          int   `json:"blockSeconds,omitempty"`          // How long a blocked IP or account stays blocked (default 900)
+}
+
+// LimitsEnabled reports whether failed logins are rate limited
+func (c LoginRateLimitConfig) LimitsEnabled() bool {
+	return boolOrDefault(c.Enabled, true)
+}
+
+// Stores of RouteRateLimitConfig
+const (
+	RateLimitStoreMemory = "memory" // Each instance counts its own requests
+	RateLimitStoreRedis  = "redis"  // Instances share their counts through Redis at RedisURL
+)
+
+// RouteRateConfig is the token bucket of one rate limited route
+type RouteRateConfig struct {
+	RequestsPerMinute int `json:"requestsPerMinute,omitempty"` // Sustained rate per user or client IP
+	Burst             int `json:"burst,omitempty"`             // Requests allowed at once (default RequestsPerMinute)
+}
+
+// RouteRateLimitConfig limits expensive routes per user, or per client IP for requests without one,
+// with a token bucket per route. Authentication has its own limits in LoginRateLimitConfig.
+type RouteRateLimitConfig struct {
+	Enabled        *bool           `json:"enabled,omitempty"`                // Enforce the limits (default true)
+	Store          string          `json:"store,omitempty"`                  // memory (default) or redis to share the buckets between instances
+	RedisURL       string          `json:"redisUrl,omitempty" redact:"true"` // e.g. redis://:password@host:6379/0
+	RedisKeyPrefix string          `json:"redisKeyPrefix,omitempty"`         // Empty uses the limiter's default
+	CampaignCreate RouteRateConfig `json:"campaignCreate,omitempty"`         // Creating and cloning campaigns (default 10 per minute, burst 5)
+	PersonaTest    RouteRateConfig `json:"personaTest,omitempty"`            // Testing a persona (default 6 per minute, burst 3)
+}
+
+// LimitsEnabled reports whether the routes are rate limited
+func (c RouteRateLimitConfig) LimitsEnabled() bool {
+	return boolOrDefault(c.Enabled, true)
+}
+
+// CompressionConfig controls gzip and deflate compression of API responses. Only responses of
+// compressible types at least MinSizeBytes long are compressed; event streams never are.
+type CompressionConfig struct {
+	Enabled      *bool `json:"enabled,omitempty"`      // Compress responses for clients that accept it (default true)
+	MinSizeBytes int   `json:"minSizeBytes,omitempty"` // Smallest response body that is compressed (default 1024)
+	Level        int   `json:"level,omitempty"`        // Compression level from 1 (fastest) to 9 (smallest) (default 6)
+}
+
+// CompressionEnabled reports whether responses are compressed
+func (c CompressionConfig) CompressionEnabled() bool {
+	return boolOrDefault(c.Enabled, true)
+}
+
+// TLDListConfig controls validation of campaign TLDs against the delegated top-level domains. The
+// bundled list is used until a refresh from Source succeeds.
+type TLDListConfig struct {
+	Validate             *bool  `json:"validate,omitempty"`             // Reject domain generation campaigns whose TLD is not on the list (default true)
+	Source               string `json:"source,omitempty"`               // URL or file path of a list in the IANA tlds-alpha-by-domain.txt format
+	RefreshIntervalHours int    `json:"refreshIntervalHours,omitempty"` // How often Source is re-read; zero refreshes only on demand
+}
+
+// ValidationEnabled reports whether campaign TLDs are checked against the list
+func (c TLDListConfig) ValidationEnabled() bool {
+	return boolOrDefault(c.Validate, true)
+}
+
+// ResourceAccessConfig restricts which personas and proxies a user may use. Personas and proxies
+// belong to the user who created them and are either private to that user or shared.
+type ResourceAccessConfig struct {
+	EnforceOwnership *bool `json:"enforceOwnership,omitempty"` // Campaigns may only reference personas the user owns or that are shared (default false)
+	ShareByDefault   *bool `json:"shareByDefault,omitempty"`   // New personas and proxies are shared unless the request says otherwise (default true)
+	// A user may not have two campaigns with the same name, ignoring case (default false)
+	UniqueCampaignNames *bool `json:"uniqueCampaignNames,omitempty"`
+	// Force-deleting a persona or proxy that campaigns reference copies its settings into those
+	// campaigns' metadata (default true)
+	SnapshotDeletedResources *bool `json:"snapshotDeletedResources,omitempty"`
+}
+
+// OwnershipEnforced reports whether campaign creation checks persona ownership
+func (c ResourceAccessConfig) OwnershipEnforced() bool {
+	return boolOrDefault(c.EnforceOwnership, false)
+}
+
+// SharedByDefault reports whether new personas and proxies are shared when not specified
+func (c ResourceAccessConfig) SharedByDefault() bool {
+	return boolOrDefault(c.ShareByDefault, true)
+}
+
+// UniqueCampaignNamesEnforced reports whether campaign names must be unique per user
+func (c ResourceAccessConfig) UniqueCampaignNamesEnforced() bool {
+	return boolOrDefault(c.UniqueCampaignNames, false)
+}
+
+// DeletedResourceSnapshotsEnabled reports whether force-deleted personas and proxies are snapshotted
+// into the campaigns that reference them
+func (c ResourceAccessConfig) DeletedResourceSnapshotsEnabled() bool {
+	return boolOrDefault(c.SnapshotDeletedResources, true)
+}
+
+func boolOrDefault(value *bool, def bool) bool {
+	if value == nil {
+		return def
+	}
+	return *value
 }
 
 // ServerConfig defines server-specific settings.
 type ServerConfig struct {
 	Port                     string          `json:"port"`
-	APIKey                   string          `json:"apiKey"`
+	APIKey                   string          `json:"apiKey" redact:"true"`
 	StreamChunkSize          int             `json:"streamChunkSize,omitempty"`
 	GinMode                  string          `json:"ginMode,omitempty"`
 	DBMaxOpenConns           int             `json:"dbMaxOpenConns,omitempty"`
 	DBMaxIdleConns           int             `json:"dbMaxIdleConns,omitempty"`
 	DBConnMaxLifetimeMinutes int             `json:"dbConnMaxLifetimeMinutes,omitempty"`
+	StrictJSONDecoding       bool            `json:"strictJsonDecoding,omitempty"`           // Reject unknown fields and trailing data in create/update request bodies
+	MaxPageSize              int             `json:"maxPageSize,omitempty"`                  // Upper bound on the limit accepted by list and result endpoints
+	SoftDeadlinesMs          map[string]int  `json:"softDeadlinesMs,omitempty"`              // Per-endpoint time after which aggregate endpoints return partial results
+	CompareBufferSize        int             `json:"compareBufferSize,omitempty"`            // Results read per page from each side of a campaign comparison
+	ExportBatchSize          int             `json:"exportBatchSize,omitempty"`              // Results read per query while streaming a campaign's results as CSV
+	PersonaConfigMaxBytes    int             `json:"personaConfigMaxBytes,omitempty"`        // Largest persona configDetails accepted on create, update and validate
+	PersonaConfigMaxDepth    int             `json:"personaConfigMaxDepth,omitempty"`        // Deepest nesting of objects and arrays accepted in persona configDetails
+	ReadReplicaDSN           string          `json:"readReplicaDsn,omitempty" redact:"true"` // Serves campaign listings, status counts and results when set
+	ReadReplicaRetrySeconds  int             `json:"readReplicaRetrySeconds,omitempty"`
+	MetricsToken             string          `json:"metricsToken,omitempty" redact:"true"` // Bearer token admitting /metrics scrapes from outside the allowed networks
+	MetricsAllowedNetworks   []string        `json:"metricsAllowedNetworks,omitempty"`     // CIDRs allowed to scrape /metrics without the token
 	DatabaseConfig           *DatabaseConfig `json:"database,omitempty"`
 	AuthConfig               *AuthConfig     `json:"auth,omitempty"`
 }
 
+// MetricsNetworks returns the networks allowed to scrape /metrics, defaulting to loopback and
+// private ranges
+func (c ServerConfig) MetricsNetworks() []string {
+	if len(c.MetricsAllowedNetworks) == 0 {
+		return DefaultMetricsAllowedNetworks
+	}
+	return c.MetricsAllowedNetworks
+}
+
 // DNSValidatorConfig holds the effective configuration for DNSValidator.
 type DNSValidatorConfig struct {
 	Resolvers                  []string
@@ -123,9 +545,32 @@ type HTTPValidatorConfigJSON struct {
 // AppConfigJSON defines the structure of the main config.json file.
 // This struct is used to unmarshal the config.json file.
 type AppConfigJSON struct {
-	Server        ServerConfig            `json:"server"`
-	Worker        WorkerConfig            `json:"worker,omitempty"` // WorkerConfig now includes the new fields
-	DNSValidator  DNSValidatorConfigJSON  `json:"dnsValidator"`
-	HTTPValidator HTTPValidatorConfigJSON `json:"httpValidator"`
-	Logging       LoggingConfig           `json:"logging"`
+	Server            ServerConfig            `json:"server"`
+	Worker            WorkerConfig            `json:"worker,omitempty"` // WorkerConfig now includes the new fields
+	DNSValidator      DNSValidatorConfigJSON  `json:"dnsValidator"`
+	HTTPValidator     HTTPValidatorConfigJSON `json:"httpValidator"`
+	Logging           LoggingConfig           `json:"logging"`
+	Webhooks          WebhookConfig           `json:"webhooks,omitempty"`
+	ProxyHealth       ProxyHealthConfig       `json:"proxyHealth,omitempty"`
+	CircuitBreaker    CircuitBreakerConfig    `json:"circuitBreaker,omitempty"`
+	OutboundRateLimit OutboundRateLimitConfig `json:"outboundRateLimit,omitempty"`
+	CampaignSafety    CampaignSafetyConfig    `json:"campaignSafety,omitempty"`
+	Startup           StartupConfig           `json:"startup,omitempty"`
+	ResourceAccess    ResourceAccessConfig    `json:"resourceAccess,omitempty"`
+	TLDList           TLDListConfig           `json:"tldList,omitempty"`
+	Audit             AuditConfig             `json:"audit,omitempty"`
+	PasswordHash      PasswordHashConfig      `json:"passwordHash,omitempty"`
+	PasswordPolicy    PasswordPolicyConfig    `json:"passwordPolicy,omitempty"`
+	PersonaTests      PersonaTestConfig       `json:"personaTests,omitempty"`
+	CampaignRetry     CampaignRetryConfig     `json:"campaignRetry,omitempty"`
+	CampaignPromotion CampaignPromotionConfig `json:"campaignPromotion,omitempty"`
+	CampaignSchedules CampaignScheduleConfig  `json:"campaignSchedules,omitempty"`
+	CampaignUpdates   CampaignUpdateConfig    `json:"campaignUpdates,omitempty"`
+	CampaignStatus    CampaignStatusConfig    `json:"campaignStatus,omitempty"`
+	LoginRates        LoginRateConfig         `json:"loginRates,omitempty"`
+	LoginRateLimit    LoginRateLimitConfig    `json:"loginRateLimit,omitempty"`
+	Compression       CompressionConfig       `json:"compression,omitempty"`
+	RouteRateLimit    RouteRateLimitConfig    `json:"routeRateLimit,omitempty"`
+	Reclassification  ReclassificationConfig  `json:"reclassification,omitempty"`
+	Tracing           TracingConfig           `json:"tracing,omitempty"`
 }
@@ -289,6 +289,9 @@ func (cf *ContentFetcher) createConfiguredClient(
 	}
 
 	effectiveMaxRedirects := cf.appConfig.HTTPValidator.MaxRedirects
+	if httpPersonaCfg.MaxRedirects > 0 {
+		effectiveMaxRedirects = httpPersonaCfg.MaxRedirects
+	}
 	followRedirects := cf.appConfig.HTTPValidator.FollowRedirects
 	if httpPersonaCfg.FollowRedirects != nil {
 		followRedirects = *httpPersonaCfg.FollowRedirects
@@ -459,7 +462,8 @@ func (cf *ContentFetcher) createConfiguredClient(
 		Jar:       jar,
 		Timeout:   time.Duration(httpPersonaCfg.RequestTimeoutSeconds) * time.Second,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			if len(via) >= effectiveMaxRedirects {
+			// via holds the requests made so far, one more than the redirects followed
+			if len(via) > effectiveMaxRedirects {
 				return http.ErrUseLastResponse
 			}
 			return nil
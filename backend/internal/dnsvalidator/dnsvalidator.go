@@ -485,6 +485,33 @@ func (dv *DNSValidator) resolveStandardType(ctx context.Context, domain string,
 }
 
 func (dv *DNSValidator) queryDoHRecord(ctx context.Context, domain string, recordType uint16, resolver ResolverClient) ([]string, error) {
+	queryDomain := dns.Fqdn(domain)
+	answers, err := dv.fetchDoHAnswers(ctx, domain, recordType, resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	var ips []string
+	for _, answer := range answers {
+		if answer.Type == int(recordType) && strings.TrimSuffix(answer.Name, ".") == strings.TrimSuffix(queryDomain, ".") {
+			parsedIP := net.ParseIP(answer.Data)
+			if parsedIP != nil {
+				isV4 := parsedIP.To4() != nil
+				isV6 := parsedIP.To16() != nil && !isV4 // Ensure it's purely IPv6
+				if recordType == dns.TypeA && isV4 {
+					ips = append(ips, answer.Data)
+				}
+				if recordType == dns.TypeAAAA && isV6 {
+					ips = append(ips, answer.Data)
+				}
+			}
+		}
+	}
+	return ips, nil
+}
+
+// fetchDoHAnswers sends one DoH JSON query and returns the answer section of a successful response
+func (dv *DNSValidator) fetchDoHAnswers(ctx context.Context, domain string, recordType uint16, resolver ResolverClient) ([]DoHAnswer, error) {
 	queryDomain := dns.Fqdn(domain)
 	dohURLString := resolver.Address
 	if strings.HasPrefix(resolver.Address, "https://dns.google/dns-query") {
@@ -565,26 +592,57 @@ func (dv *DNSValidator) queryDoHRecord(ctx context.Context, domain string, recor
 		}
 		return nil, fmt.Errorf("DoH server %s returned RCODE %d (%s) for %s type %s", resolver.Address, dohResp.Status, dns.RcodeToString[dohResp.Status], domain, dns.TypeToString[recordType])
 	}
+	return dohResp.Answer, nil
+}
 
-	var ips []string
-	if dohResp.Answer != nil {
-		for _, answer := range dohResp.Answer {
-			if answer.Type == int(recordType) && strings.TrimSuffix(answer.Name, ".") == strings.TrimSuffix(queryDomain, ".") {
-				parsedIP := net.ParseIP(answer.Data)
-				if parsedIP != nil {
-					isV4 := parsedIP.To4() != nil
-					isV6 := parsedIP.To16() != nil && !isV4 // Ensure it's purely IPv6
-					if recordType == dns.TypeA && isV4 {
-						ips = append(ips, answer.Data)
-					}
-					if recordType == dns.TypeAAAA && isV6 {
-						ips = append(ips, answer.Data)
-					}
-				}
-			}
+// LookupCNAME returns the name at the end of a domain's CNAME chain, without the trailing dot. A
+// domain without a CNAME record is its own canonical name.
+func (dv *DNSValidator) LookupCNAME(domain string, ctx context.Context) (string, error) {
+	resolverClient, err := dv.getNextResolver()
+	if err != nil {
+		return "", fmt.Errorf("failed to get resolver: %w", err)
+	}
+	switch resolverClient.Type {
+	case SystemResolver, StandardResolver:
+		r := &net.Resolver{PreferGo: true,
+			Dial: func(dCtx context.Context, network, address string) (net.Conn, error) {
+				return resolverClient.Dialer.DialContext(dCtx, network, resolverClient.Address)
+			},
+		}
+		cname, err := r.LookupCNAME(ctx, domain)
+		if err != nil {
+			return "", err
 		}
+		return strings.TrimSuffix(cname, "."), nil
+	case DoHResolver:
+		// The answer to an A query carries the CNAME chain that led to the addresses
+		answers, err := dv.fetchDoHAnswers(ctx, domain, dns.TypeA, resolverClient)
+		if err != nil {
+			return "", err
+		}
+		return followCNAMEChain(domain, answers), nil
 	}
-	return ips, nil
+	return "", fmt.Errorf("unknown resolver type for %s", resolverClient.Address)
+}
+
+// followCNAMEChain follows the CNAME records of a DoH answer section from domain, giving up on
+// chains that loop or run longer than a resolver would follow
+func followCNAMEChain(domain string, answers []DoHAnswer) string {
+	targets := make(map[string]string)
+	for _, answer := range answers {
+		if answer.Type == int(dns.TypeCNAME) {
+			targets[strings.ToLower(strings.TrimSuffix(answer.Name, "."))] = strings.TrimSuffix(answer.Data, ".")
+		}
+	}
+	name := strings.TrimSuffix(domain, ".")
+	for hops := 0; hops < 16; hops++ {
+		target, ok := targets[strings.ToLower(name)]
+		if !ok {
+			break
+		}
+		name = target
+	}
+	return name
 }
 
 // NOTE: The ValidationResult struct definition that was here has been removed
@@ -99,6 +99,35 @@ func TestDNSValidator_ValidateSingleDomain_DoH_NotFound(t *testing.T) {
 	assert.Contains(t, result.Error, "no such host")
 }
 
+func TestDNSValidator_LookupCNAME_DoH(t *testing.T) {
+	mockServer := newMockDoHServer(t, func(w http.ResponseWriter, r *http.Request) {
+		resp := DoHJSONResponse{Status: dns.RcodeSuccess}
+		if r.URL.Query().Get("name") == "www.example.com." {
+			resp.Answer = []DoHAnswer{
+				{Name: "www.example.com.", Type: int(dns.TypeCNAME), Data: "edge.example.net."},
+				{Name: "edge.example.net.", Type: int(dns.TypeCNAME), Data: "pop1.cdn.example.org."},
+				{Name: "pop1.cdn.example.org.", Type: int(dns.TypeA), Data: "203.0.113.7"},
+			}
+		} else {
+			resp.Answer = []DoHAnswer{{Name: "example.com.", Type: int(dns.TypeA), Data: "1.2.3.4"}}
+		}
+		w.Header().Set("Content-Type", "application/dns-json")
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	})
+	defer mockServer.Close()
+
+	validator := New(newTestDNSValidatorConfig([]string{mockServer.URL}, "random_rotation"))
+	require.NotNil(t, validator)
+
+	cname, err := validator.LookupCNAME("www.example.com", context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "pop1.cdn.example.org", cname)
+
+	cname, err = validator.LookupCNAME("example.com", context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", cname, "a domain without a CNAME is its own canonical name")
+}
+
 func TestDNSValidator_ValidateSingleDomain_InvalidFormat(t *testing.T) {
 	cfg := newTestDNSValidatorConfig([]string{"1.1.1.1"}, "random_rotation") // Real resolver not actually used
 	validator := New(cfg)
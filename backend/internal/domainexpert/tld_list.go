@@ -0,0 +1,176 @@
+package domainexpert
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed tlds-alpha-by-domain.txt
+var bundledTLDs string
+
+// maxTLDListBytes bounds how much of a refresh source is read; the IANA list is about 10KB
+const maxTLDListBytes = 1 << 20
+
+// TLDListStatus describes where the current TLD list came from
+type TLDListStatus struct {
+	Source      string     `json:"source"` // "bundled", a URL or a file path
+	Count       int        `json:"count"`
+	RefreshedAt *time.Time `json:"refreshedAt,omitempty"`
+	LastError   string     `json:"lastError,omitempty"` // Error of the most recent failed refresh, cleared by a successful one
+}
+
+// TLDList is an in-memory set of delegated top-level domains used to validate generated domains.
+// It starts from the bundled list and keeps its current set whenever a refresh fails.
+type TLDList struct {
+	mutex       sync.RWMutex
+	tlds        map[string]struct{}
+	source      string
+	refreshedAt time.Time
+	lastError   string
+	client      *http.Client
+}
+
+// NewTLDList returns a list loaded from the bundled IANA snapshot
+func NewTLDList() *TLDList {
+	tlds, err := ParseTLDList(strings.NewReader(bundledTLDs))
+	if err != nil {
+		panic(fmt.Sprintf("bundled TLD list is invalid: %v", err))
+	}
+	return &TLDList{tlds: tlds, source: "bundled", client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// ParseTLDList reads a list in the IANA tlds-alpha-by-domain.txt format: one TLD per line, with
+// lines starting with '#' ignored. Any line that is not a valid DNS label fails the whole list so a
+// truncated download or an error page is never mistaken for a list.
+func ParseTLDList(r io.Reader) (map[string]struct{}, error) {
+	tlds := make(map[string]struct{})
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tld := strings.ToLower(line)
+		if !isTLDLabel(tld) {
+			return nil, fmt.Errorf("line %d: %q is not a valid top-level domain", lineNumber, line)
+		}
+		tlds[tld] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(tlds) == 0 {
+		return nil, fmt.Errorf("list contains no top-level domains")
+	}
+	return tlds, nil
+}
+
+// isTLDLabel reports whether s is a lowercase ASCII DNS label that may be a TLD
+func isTLDLabel(s string) bool {
+	if len(s) == 0 || len(s) > 63 || s[0] == '-' || s[len(s)-1] == '-' {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains reports whether tld, with or without its leading dot and in any case, is on the list.
+// Internationalized TLDs must be given in their ASCII "xn--" form.
+func (l *TLDList) Contains(tld string) bool {
+	normalized := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tld), "."))
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	_, ok := l.tlds[normalized]
+	return ok
+}
+
+// Refresh replaces the list with the one read from source, an http(s) URL or a file path. On
+// failure the current list is kept and the error is recorded in the status.
+func (l *TLDList) Refresh(ctx context.Context, source string) error {
+	tlds, err := l.load(ctx, source)
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if err != nil {
+		l.lastError = err.Error()
+		return fmt.Errorf("failed to refresh TLD list from %s: %w", source, err)
+	}
+	l.tlds = tlds
+	l.source = source
+	l.refreshedAt = time.Now().UTC()
+	l.lastError = ""
+	return nil
+}
+
+func (l *TLDList) load(ctx context.Context, source string) (map[string]struct{}, error) {
+	if source == "" {
+		return nil, fmt.Errorf("no TLD list source configured")
+	}
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		file, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return ParseTLDList(io.LimitReader(file, maxTLDListBytes))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return ParseTLDList(io.LimitReader(resp.Body, maxTLDListBytes))
+}
+
+// Status returns the current list's source and size
+func (l *TLDList) Status() TLDListStatus {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	status := TLDListStatus{Source: l.source, Count: len(l.tlds), LastError: l.lastError}
+	if !l.refreshedAt.IsZero() {
+		refreshedAt := l.refreshedAt
+		status.RefreshedAt = &refreshedAt
+	}
+	return status
+}
+
+// StartAutoRefresh refreshes the list from source immediately and then every interval until ctx
+// is done. Failures are logged and the previous list stays in use.
+func (l *TLDList) StartAutoRefresh(ctx context.Context, source string, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := l.Refresh(ctx, source); err != nil {
+				log.Printf("TLDList: %v; keeping %d TLDs from %s", err, l.Status().Count, l.Status().Source)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
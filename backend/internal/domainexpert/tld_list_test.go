@@ -0,0 +1,68 @@
+package domainexpert
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLDList_BundledListValidatesTLDs(t *testing.T) {
+	list := NewTLDList()
+
+	for _, tld := range []string{".com", "org", ".UK", ".xn--p1ai"} {
+		assert.True(t, list.Contains(tld), tld)
+	}
+	for _, tld := range []string{".notarealtld", ".c0m", "", "."} {
+		assert.False(t, list.Contains(tld), tld)
+	}
+	assert.Equal(t, "bundled", list.Status().Source)
+	assert.Greater(t, list.Status().Count, 1000)
+}
+
+func TestTLDList_RefreshReplacesTheSet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("# Version 2026101600\nCOM\nNEWTLD\n"))
+	}))
+	defer server.Close()
+
+	list := NewTLDList()
+	require.False(t, list.Contains(".newtld"))
+	require.NoError(t, list.Refresh(context.Background(), server.URL))
+
+	assert.True(t, list.Contains(".newtld"))
+	assert.True(t, list.Contains(".com"))
+	assert.False(t, list.Contains(".org"), "the refreshed list replaces the bundled one")
+	status := list.Status()
+	assert.Equal(t, server.URL, status.Source)
+	assert.Equal(t, 2, status.Count)
+	assert.NotNil(t, status.RefreshedAt)
+}
+
+func TestTLDList_FailedRefreshKeepsCurrentList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html>Service unavailable</html>\n"))
+	}))
+	defer server.Close()
+
+	list := NewTLDList()
+	err := list.Refresh(context.Background(), server.URL)
+	require.Error(t, err)
+	assert.True(t, list.Contains(".org"), "an error page never replaces the list")
+	assert.Equal(t, "bundled", list.Status().Source)
+	assert.NotEmpty(t, list.Status().LastError)
+
+	path := filepath.Join(t.TempDir(), "tlds.txt")
+	require.NoError(t, os.WriteFile(path, []byte("ORG\n"), 0o600))
+	require.NoError(t, list.Refresh(context.Background(), path))
+	assert.False(t, list.Contains(".com"))
+	assert.Empty(t, list.Status().LastError)
+
+	require.Error(t, list.Refresh(context.Background(), filepath.Join(t.TempDir(), "missing.txt")))
+	assert.True(t, list.Contains(".org"), "a missing file keeps the last good list")
+}
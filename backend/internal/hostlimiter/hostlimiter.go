@@ -0,0 +1,141 @@
+// Package hostlimiter spaces outbound requests to the same target host and zone.
+//
+// Every request to a host reserves the next free slot for that host and for the host's zone (its
+// registrable domain, e.g. example.co.uk for www.shop.example.co.uk). Slots for a host are at least
+// 1/PerHostRate apart and slots for a zone at least 1/PerZoneRate apart, so a host that appears many
+// times in a campaign is not hammered, while unrelated hosts proceed independently. A single Limiter
+// is shared by all workers of a service so the spacing holds across them.
+package hostlimiter
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"golang.org/x/net/publicsuffix"
+)
+
+// Config sets the maximum request rates. A rate of zero or less disables that limit.
+type Config struct {
+	PerHostRate float64 // Requests per second to a single host
+	PerZoneRate float64 // Requests per second across all hosts of a zone
+}
+
+// minSweepSize is the number of tracked keys below which stale slots are not pruned
+const minSweepSize = 1024
+
+// Limiter hands out request slots per host and zone. It is safe for concurrent use.
+type Limiter struct {
+	mu           sync.Mutex
+	hostInterval time.Duration
+	zoneInterval time.Duration
+	nextSlot     map[string]time.Time
+	sweepAt      int
+	now          func() time.Time
+}
+
+// NewFromConfig creates a Limiter from the application's outbound rate limit settings
+func NewFromConfig(cfg config.OutboundRateLimitConfig) *Limiter {
+	return New(Config{PerHostRate: cfg.PerHostRequestsPerSecond, PerZoneRate: cfg.PerZoneRequestsPerSecond})
+}
+
+// New creates a Limiter with the given rates
+func New(cfg Config) *Limiter {
+	return &Limiter{
+		hostInterval: rateInterval(cfg.PerHostRate),
+		zoneInterval: rateInterval(cfg.PerZoneRate),
+		nextSlot:     make(map[string]time.Time),
+		sweepAt:      minSweepSize,
+		now:          time.Now,
+	}
+}
+
+func rateInterval(perSecond float64) time.Duration {
+	if perSecond <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / perSecond)
+}
+
+// Enabled reports whether the limiter ever delays requests
+func (l *Limiter) Enabled() bool {
+	return l != nil && (l.hostInterval > 0 || l.zoneInterval > 0)
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
+
+// Zone returns the registrable domain that host belongs to, or host itself when it has none
+func Zone(host string) string {
+	host = normalizeHost(host)
+	if zone, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return zone
+	}
+	return host
+}
+
+// Reserve claims the next request slot for host and returns how long the caller must wait
+// before sending the request.
+func (l *Limiter) Reserve(host string) time.Duration {
+	if !l.Enabled() {
+		return 0
+	}
+	host = normalizeHost(host)
+	hostKey := "host:" + host
+	zoneKey := "zone:" + Zone(host)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	slot := now
+	if l.hostInterval > 0 && l.nextSlot[hostKey].After(slot) {
+		slot = l.nextSlot[hostKey]
+	}
+	if l.zoneInterval > 0 && l.nextSlot[zoneKey].After(slot) {
+		slot = l.nextSlot[zoneKey]
+	}
+	if l.hostInterval > 0 {
+		l.nextSlot[hostKey] = slot.Add(l.hostInterval)
+	}
+	if l.zoneInterval > 0 {
+		l.nextSlot[zoneKey] = slot.Add(l.zoneInterval)
+	}
+	l.sweepLocked(now)
+	return slot.Sub(now)
+}
+
+// sweepLocked drops keys whose next slot has already passed once the map has grown
+func (l *Limiter) sweepLocked(now time.Time) {
+	if len(l.nextSlot) < l.sweepAt {
+		return
+	}
+	for key, next := range l.nextSlot {
+		if !next.After(now) {
+			delete(l.nextSlot, key)
+		}
+	}
+	l.sweepAt = 2 * len(l.nextSlot)
+	if l.sweepAt < minSweepSize {
+		l.sweepAt = minSweepSize
+	}
+}
+
+// Wait blocks until a request to host may be sent or ctx ends, in which case ctx's error is returned
+func (l *Limiter) Wait(ctx context.Context, host string) error {
+	delay := l.Reserve(host)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,129 @@
+package hostlimiter
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLimiter returns a limiter whose clock only moves when advance is called
+func newTestLimiter(cfg Config) (*Limiter, func(time.Duration)) {
+	l := New(cfg)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+	return l, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestReserve_SpacesRequestsToSameHost(t *testing.T) {
+	l, _ := newTestLimiter(Config{PerHostRate: 4})
+
+	assert.Equal(t, time.Duration(0), l.Reserve("api.example.com"))
+	assert.Equal(t, 250*time.Millisecond, l.Reserve("api.example.com"))
+	assert.Equal(t, 500*time.Millisecond, l.Reserve("API.example.com."), "host keys are normalized")
+
+	// Different hosts proceed independently when no zone limit is set
+	assert.Equal(t, time.Duration(0), l.Reserve("www.example.com"))
+	assert.Equal(t, time.Duration(0), l.Reserve("other.org"))
+}
+
+func TestReserve_SlotsFreeUpAsTimePasses(t *testing.T) {
+	l, advance := newTestLimiter(Config{PerHostRate: 2})
+
+	l.Reserve("example.com")
+	advance(200 * time.Millisecond)
+	assert.Equal(t, 300*time.Millisecond, l.Reserve("example.com"))
+
+	advance(5 * time.Second)
+	assert.Equal(t, time.Duration(0), l.Reserve("example.com"))
+}
+
+func TestReserve_ZoneLimitSpansSubdomains(t *testing.T) {
+	l, _ := newTestLimiter(Config{PerHostRate: 1, PerZoneRate: 10})
+
+	assert.Equal(t, time.Duration(0), l.Reserve("a.example.co.uk"))
+	assert.Equal(t, 100*time.Millisecond, l.Reserve("b.example.co.uk"))
+	assert.Equal(t, 200*time.Millisecond, l.Reserve("example.co.uk"))
+	assert.Equal(t, time.Duration(0), l.Reserve("a.other.co.uk"), "other zones are unaffected")
+}
+
+func TestZone(t *testing.T) {
+	assert.Equal(t, "example.com", Zone("www.shop.Example.com."))
+	assert.Equal(t, "example.co.uk", Zone("a.example.co.uk"))
+	assert.Equal(t, "localhost", Zone("localhost"))
+}
+
+func TestReserve_DisabledNeverDelays(t *testing.T) {
+	l, _ := newTestLimiter(Config{PerHostRate: -1})
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, time.Duration(0), l.Reserve("example.com"))
+	}
+	assert.False(t, l.Enabled())
+}
+
+func TestReserve_PrunesStaleHosts(t *testing.T) {
+	l, advance := newTestLimiter(Config{PerHostRate: 100})
+	for i := 0; i < minSweepSize-1; i++ {
+		l.Reserve(time.Duration(i).String() + ".example.net")
+	}
+	advance(time.Second)
+	l.Reserve("fresh.example.net")
+	assert.Equal(t, 1, len(l.nextSlot), "stale hosts are dropped once the map reaches the sweep size")
+}
+
+func TestWait_ConcurrentWorkersAreSpacedPerHost(t *testing.T) {
+	l := New(Config{PerHostRate: 20}) // 50ms apart
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var sameHost []time.Time
+	var otherHosts []time.Duration
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, l.Wait(ctx, "busy.example.com"))
+			mu.Lock()
+			sameHost = append(sameHost, time.Now())
+			mu.Unlock()
+		}()
+		go func(i int) {
+			defer wg.Done()
+			require.NoError(t, l.Wait(ctx, time.Duration(i).String()+".independent.test"))
+			mu.Lock()
+			otherHosts = append(otherHosts, time.Since(start))
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+
+	require.Len(t, sameHost, 4)
+	first, last := sameHost[0], sameHost[0]
+	for _, at := range sameHost {
+		if at.Before(first) {
+			first = at
+		}
+		if at.After(last) {
+			last = at
+		}
+	}
+	assert.GreaterOrEqual(t, last.Sub(first), 140*time.Millisecond, "four requests to one host span at least three intervals")
+	for _, elapsed := range otherHosts {
+		assert.Less(t, elapsed, 40*time.Millisecond, "requests to different hosts are not delayed")
+	}
+}
+
+func TestWait_ReturnsWhenContextEnds(t *testing.T) {
+	l := New(Config{PerHostRate: 0.1})
+	require.NoError(t, l.Wait(context.Background(), "slow.example.com"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, l.Wait(ctx, "slow.example.com"), context.DeadlineExceeded)
+}
@@ -0,0 +1,117 @@
+package httpvalidator
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// StatusCertPinMismatch is the result status of a request whose target or proxy presented a
+// certificate matching none of the persona's pins for that host
+const StatusCertPinMismatch = "CertPinMismatch"
+
+// CertPinMismatchError is returned when a pinned host presents an unexpected certificate
+type CertPinMismatchError struct {
+	Host        string
+	Fingerprint string // SHA-256 fingerprint of the certificate the host presented
+}
+
+func (e *CertPinMismatchError) Error() string {
+	return fmt.Sprintf("certificate of %s (sha256 %s) matches none of its pins", e.Host, e.Fingerprint)
+}
+
+// CertFingerprint returns the SHA-256 fingerprint of a DER encoded certificate as lowercase hex
+func CertFingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeCertPin accepts a SHA-256 certificate fingerprint as 64 hex digits, optionally separated
+// by colons and prefixed with "sha256:", and returns it as lowercase hex
+func normalizeCertPin(pin string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(pin))
+	normalized = strings.TrimPrefix(normalized, "sha256:")
+	normalized = strings.ReplaceAll(normalized, ":", "")
+	if len(normalized) != sha256.Size*2 {
+		return "", fmt.Errorf("pin %q is not a SHA-256 fingerprint of 64 hex digits", pin)
+	}
+	if _, err := hex.DecodeString(normalized); err != nil {
+		return "", fmt.Errorf("pin %q is not a SHA-256 fingerprint of 64 hex digits", pin)
+	}
+	return normalized, nil
+}
+
+// normalizePinnedHost lowercases a pinned host name and drops a port
+func normalizePinnedHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(host, ".")
+}
+
+// ValidateCertPins checks the certPins of an HTTP persona: every host needs at least one pin, and
+// every pin must be a SHA-256 certificate fingerprint. Hosts must be names, as a handshake with an
+// IP address carries no server name to match pins against.
+func ValidateCertPins(pins map[string][]string) error {
+	hosts := make([]string, 0, len(pins))
+	for host := range pins {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		normalizedHost := normalizePinnedHost(host)
+		if normalizedHost == "" {
+			return fmt.Errorf("certPins: empty host name")
+		}
+		if net.ParseIP(normalizedHost) != nil {
+			return fmt.Errorf("certPins.%s: pins apply to host names, not IP addresses", host)
+		}
+		if len(pins[host]) == 0 {
+			return fmt.Errorf("certPins.%s: at least one pin is required", host)
+		}
+		for _, pin := range pins[host] {
+			if _, err := normalizeCertPin(pin); err != nil {
+				return fmt.Errorf("certPins.%s: %w", host, err)
+			}
+		}
+	}
+	return nil
+}
+
+// certPinVerifier returns a tls.Config VerifyConnection callback that rejects pinned hosts whose
+// leaf certificate matches none of their pins. Hosts without pins are left to normal verification.
+// The callback sees both target and HTTPS proxy handshakes, so pins may name either.
+func certPinVerifier(pins map[string][]string) func(tls.ConnectionState) error {
+	byHost := make(map[string]map[string]bool, len(pins))
+	for host, hostPins := range pins {
+		normalizedHost := normalizePinnedHost(host)
+		if byHost[normalizedHost] == nil {
+			byHost[normalizedHost] = make(map[string]bool, len(hostPins))
+		}
+		for _, pin := range hostPins {
+			if normalized, err := normalizeCertPin(pin); err == nil {
+				byHost[normalizedHost][normalized] = true
+			}
+		}
+	}
+	return func(cs tls.ConnectionState) error {
+		host := normalizePinnedHost(cs.ServerName)
+		hostPins, pinned := byHost[host]
+		if !pinned {
+			return nil
+		}
+		if len(cs.PeerCertificates) == 0 {
+			return &CertPinMismatchError{Host: host}
+		}
+		fingerprint := CertFingerprint(cs.PeerCertificates[0].Raw)
+		if !hostPins[fingerprint] {
+			return &CertPinMismatchError{Host: host, Fingerprint: fingerprint}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,114 @@
+package httpvalidator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const otherFingerprint = "0000000000000000000000000000000000000000000000000000000000000000"
+
+func pinnedPersona(t *testing.T, pins map[string][]string) *models.Persona {
+	t.Helper()
+	configDetails, err := json.Marshal(models.HTTPConfigDetails{UserAgent: "DomainFlow/1.0", CertPins: pins})
+	require.NoError(t, err)
+	return &models.Persona{ID: uuid.New(), Name: "pinned", PersonaType: models.PersonaTypeHTTP, ConfigDetails: configDetails}
+}
+
+func insecureValidator() *HTTPValidator {
+	return NewHTTPValidator(&config.AppConfig{HTTPValidator: config.HTTPValidatorConfig{
+		RequestTimeout: 5 * time.Second, AllowInsecureTLS: true,
+	}})
+}
+
+// localhostURL addresses the test server by name, so the handshake carries a server name
+func localhostURL(server *httptest.Server) string {
+	return strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+}
+
+func TestValidate_CertPinnedTarget(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	fingerprint := CertFingerprint(server.Certificate().Raw)
+	hv := insecureValidator()
+
+	// The pin matches, written with colons in upper case
+	var colonPin []string
+	for i := 0; i < len(fingerprint); i += 2 {
+		colonPin = append(colonPin, strings.ToUpper(fingerprint[i:i+2]))
+	}
+	result, err := hv.Validate(context.Background(), "localhost", localhostURL(server),
+		pinnedPersona(t, map[string][]string{"LOCALHOST": {otherFingerprint, strings.Join(colonPin, ":")}}), nil)
+	require.NoError(t, err)
+	assert.True(t, result.IsSuccess)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+
+	// The certificate matches none of the pins, even though verification is disabled
+	result, err = hv.Validate(context.Background(), "localhost", localhostURL(server),
+		pinnedPersona(t, map[string][]string{"localhost": {"sha256:" + otherFingerprint}}), nil)
+	require.Error(t, err)
+	assert.False(t, result.IsSuccess)
+	assert.Equal(t, StatusCertPinMismatch, result.Status)
+	assert.Contains(t, result.Error, fingerprint)
+
+	// Pins for other hosts leave the target alone
+	result, err = hv.Validate(context.Background(), "localhost", localhostURL(server),
+		pinnedPersona(t, map[string][]string{"example.com": {otherFingerprint}}), nil)
+	require.NoError(t, err)
+	assert.True(t, result.IsSuccess)
+}
+
+func TestValidate_CertPinnedProxy(t *testing.T) {
+	proxyServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("proxied " + r.URL.String()))
+	}))
+	defer proxyServer.Close()
+	proxy := &models.Proxy{
+		ID:        uuid.New(),
+		Address:   strings.TrimPrefix(localhostURL(proxyServer), "https://"),
+		Protocol:  models.ProxyProtocolEnumPtr(models.ProxyProtocolHTTPS),
+		IsEnabled: true,
+		IsHealthy: true,
+	}
+	hv := insecureValidator()
+
+	result, err := hv.Validate(context.Background(), "target.example", "http://target.example/",
+		pinnedPersona(t, map[string][]string{"localhost": {CertFingerprint(proxyServer.Certificate().Raw)}}), proxy)
+	require.NoError(t, err)
+	assert.True(t, result.IsSuccess)
+	assert.Contains(t, result.ExtractedContentSnippet, "proxied http://target.example/")
+
+	result, err = hv.Validate(context.Background(), "target.example", "http://target.example/",
+		pinnedPersona(t, map[string][]string{"localhost": {otherFingerprint}}), proxy)
+	require.Error(t, err)
+	assert.Equal(t, StatusCertPinMismatch, result.Status, "a tampered proxy is rejected")
+}
+
+func TestValidateCertPins(t *testing.T) {
+	assert.NoError(t, ValidateCertPins(nil))
+	assert.NoError(t, ValidateCertPins(map[string][]string{"example.com": {otherFingerprint, "sha256:" + strings.ToUpper(otherFingerprint)}}))
+
+	invalid := map[string]map[string][]string{
+		"short pin":   {"example.com": {"abcd"}},
+		"not hex":     {"example.com": {strings.Repeat("zz", 32)}},
+		"no pins":     {"example.com": {}},
+		"empty host":  {" ": {otherFingerprint}},
+		"IP address":  {"203.0.113.7": {otherFingerprint}},
+		"sha1 length": {"example.com": {strings.Repeat("ab", 20)}},
+	}
+	for name, pins := range invalid {
+		assert.Error(t, ValidateCertPins(pins), name)
+	}
+}
@@ -0,0 +1,119 @@
+package httpvalidator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// maxHeaderTemplateLength bounds a templated header value before and after rendering
+const maxHeaderTemplateLength = 4096
+
+// Header templates are rendered in a single pass, so a substituted value is never expanded again.
+// Only these variables are known; anything else between {{ and }} is rejected.
+const (
+	HeaderTemplateDomain    = "domain"    // The domain being validated
+	HeaderTemplateUUID      = "uuid"      // A random UUID, new for every request
+	HeaderTemplateTimestamp = "timestamp" // Unix seconds when the request is made
+)
+
+// HeaderTemplateValues holds the per-request values substituted into header templates
+type HeaderTemplateValues struct {
+	Domain    string
+	RequestID uuid.UUID
+	Now       time.Time
+}
+
+func newHeaderTemplateValues(domain string) HeaderTemplateValues {
+	return HeaderTemplateValues{Domain: domain, RequestID: uuid.New(), Now: time.Now()}
+}
+
+func (v HeaderTemplateValues) lookup(name string) (string, bool) {
+	switch name {
+	case HeaderTemplateDomain:
+		return v.Domain, true
+	case HeaderTemplateUUID:
+		return v.RequestID.String(), true
+	case HeaderTemplateTimestamp:
+		return strconv.FormatInt(v.Now.Unix(), 10), true
+	}
+	return "", false
+}
+
+// ValidateHeaderTemplate reports whether value is a header value that can be rendered: it must fit
+// the length bound, contain no line breaks, and only use known variables in balanced {{ }}.
+func ValidateHeaderTemplate(value string) error {
+	_, err := RenderHeaderTemplate(value, HeaderTemplateValues{})
+	return err
+}
+
+// ValidateHTTPConfigTemplates checks the user agent and every header value of an HTTP persona
+func ValidateHTTPConfigTemplates(cfg *models.HTTPConfigDetails) error {
+	if err := ValidateHeaderTemplate(cfg.UserAgent); err != nil {
+		return fmt.Errorf("userAgent: %w", err)
+	}
+	names := make([]string, 0, len(cfg.Headers))
+	for name := range cfg.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := ValidateHeaderTemplate(cfg.Headers[name]); err != nil {
+			return fmt.Errorf("headers.%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// RenderHeaderTemplate substitutes the per-request values into a header template
+func RenderHeaderTemplate(value string, values HeaderTemplateValues) (string, error) {
+	if len(value) > maxHeaderTemplateLength {
+		return "", fmt.Errorf("template is longer than %d characters", maxHeaderTemplateLength)
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return "", fmt.Errorf("template must not contain line breaks")
+	}
+	if !strings.Contains(value, "{{") && !strings.Contains(value, "}}") {
+		return value, nil
+	}
+
+	var rendered strings.Builder
+	rest := value
+	for {
+		start := strings.Index(rest, "{{")
+		if end := strings.Index(rest, "}}"); end >= 0 && (start < 0 || end < start) {
+			return "", fmt.Errorf("unexpected }} without a matching {{")
+		}
+		if start < 0 {
+			rendered.WriteString(rest)
+			break
+		}
+		rendered.WriteString(rest[:start])
+		rest = rest[start+2:]
+		end := strings.Index(rest, "}}")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated {{")
+		}
+		name := strings.TrimSpace(rest[:end])
+		substituted, ok := values.lookup(name)
+		if !ok {
+			return "", fmt.Errorf("unknown template variable %q (known: %s, %s, %s)", name,
+				HeaderTemplateDomain, HeaderTemplateUUID, HeaderTemplateTimestamp)
+		}
+		rendered.WriteString(substituted)
+		rest = rest[end+2:]
+	}
+
+	if rendered.Len() > maxHeaderTemplateLength {
+		return "", fmt.Errorf("rendered value is longer than %d characters", maxHeaderTemplateLength)
+	}
+	if strings.ContainsAny(rendered.String(), "\r\n") {
+		return "", fmt.Errorf("rendered value must not contain line breaks")
+	}
+	return rendered.String(), nil
+}
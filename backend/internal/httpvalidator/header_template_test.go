@@ -0,0 +1,98 @@
+package httpvalidator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHeaderTemplate(t *testing.T) {
+	values := HeaderTemplateValues{
+		Domain:    "example.com",
+		RequestID: uuid.MustParse("7d444840-9dc0-11d1-b245-5ffdce74fad2"),
+		Now:       time.Unix(1700000000, 0),
+	}
+	rendered := map[string]string{
+		"text/html":                    "text/html",
+		"https://{{domain}}/":          "https://example.com/",
+		"{{ uuid }}-{{timestamp}}":     "7d444840-9dc0-11d1-b245-5ffdce74fad2-1700000000",
+		"Bot ({{domain}}; {{domain}})": "Bot (example.com; example.com)",
+		"{}":                           "{}",
+	}
+	for template, want := range rendered {
+		got, err := RenderHeaderTemplate(template, values)
+		require.NoError(t, err, template)
+		assert.Equal(t, want, got)
+	}
+
+	// A substituted value is not expanded again
+	got, err := RenderHeaderTemplate("{{domain}}", HeaderTemplateValues{Domain: "{{uuid}}"})
+	require.NoError(t, err)
+	assert.Equal(t, "{{uuid}}", got)
+}
+
+func TestValidateHeaderTemplate_RejectsInvalidTemplates(t *testing.T) {
+	invalid := map[string]string{
+		"{{host}}":               "unknown template variable",
+		"{{}}":                   "unknown template variable",
+		"{{domain":               "unterminated",
+		"domain}}":               "without a matching",
+		"{{ {{domain}} }}":       "unknown template variable",
+		"value\r\nX-Injected: 1": "line breaks",
+		strings.Repeat("a", maxHeaderTemplateLength+1): "longer than",
+	}
+	for template, want := range invalid {
+		err := ValidateHeaderTemplate(template)
+		require.Error(t, err, template)
+		assert.Contains(t, err.Error(), want)
+	}
+
+	err := ValidateHTTPConfigTemplates(&models.HTTPConfigDetails{
+		UserAgent: "Bot/1.0",
+		Headers:   map[string]string{"X-Good": "{{uuid}}", "X-Bad": "{{uid}}"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "headers.X-Bad")
+}
+
+func TestValidate_RendersHeaderTemplatesPerRequest(t *testing.T) {
+	var received []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Clone())
+		w.Write([]byte("<html><title>ok</title></html>"))
+	}))
+	defer server.Close()
+
+	configDetails, err := json.Marshal(models.HTTPConfigDetails{
+		UserAgent: "DomainFlow ({{domain}})",
+		Headers:   map[string]string{"X-Request-Id": "{{uuid}}", "X-Target": "https://{{domain}}/"},
+	})
+	require.NoError(t, err)
+	persona := &models.Persona{ID: uuid.New(), Name: "templated", PersonaType: models.PersonaTypeHTTP, ConfigDetails: configDetails}
+	hv := NewHTTPValidator(&config.AppConfig{HTTPValidator: config.HTTPValidatorConfig{RequestTimeout: 5 * time.Second}})
+
+	for _, domain := range []string{"one.example", "two.example"} {
+		result, err := hv.Validate(context.Background(), domain, server.URL, persona, nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+	}
+
+	require.Len(t, received, 2)
+	for i, domain := range []string{"one.example", "two.example"} {
+		assert.Equal(t, "DomainFlow ("+domain+")", received[i].Get("User-Agent"))
+		assert.Equal(t, "https://"+domain+"/", received[i].Get("X-Target"))
+		_, err := uuid.Parse(received[i].Get("X-Request-Id"))
+		assert.NoError(t, err)
+	}
+	assert.NotEqual(t, received[0].Get("X-Request-Id"), received[1].Get("X-Request-Id"), "uuid is rendered for every request")
+}
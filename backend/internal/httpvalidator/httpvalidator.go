@@ -9,6 +9,7 @@ import (
 	"crypto/tls"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -63,6 +64,29 @@ func (hv *HTTPValidator) Validate(
 	initialURL string,
 	persona *models.Persona,
 	proxy *models.Proxy,
+) (*ValidationResult, error) {
+	return hv.validate(ctx, domain, initialURL, persona, proxy, true)
+}
+
+// ValidateLiveness makes the same request as Validate but judges the response on its status code
+// alone: the body is not read, so the result has no content hash, title, snippet or RawBody.
+func (hv *HTTPValidator) ValidateLiveness(
+	ctx context.Context,
+	domain string,
+	initialURL string,
+	persona *models.Persona,
+	proxy *models.Proxy,
+) (*ValidationResult, error) {
+	return hv.validate(ctx, domain, initialURL, persona, proxy, false)
+}
+
+func (hv *HTTPValidator) validate(
+	ctx context.Context,
+	domain string,
+	initialURL string,
+	persona *models.Persona,
+	proxy *models.Proxy,
+	readBody bool,
 ) (*ValidationResult, error) {
 	startTime := time.Now()
 	result := &ValidationResult{
@@ -119,7 +143,11 @@ func (hv *HTTPValidator) Validate(
 				return http.ErrUseLastResponse
 			}
 			maxRedirects := hv.appConfig.HTTPValidator.MaxRedirects
-			if len(via) >= maxRedirects {
+			if personaCfg.MaxRedirects > 0 {
+				maxRedirects = personaCfg.MaxRedirects
+			}
+			// via holds the requests made so far, one more than the redirects followed
+			if len(via) > maxRedirects {
 				return http.ErrUseLastResponse
 			}
 			return nil
@@ -129,6 +157,10 @@ func (hv *HTTPValidator) Validate(
 	tlsConfig := &tls.Config{
 		InsecureSkipVerify: hv.appConfig.HTTPValidator.AllowInsecureTLS,
 	}
+	if len(personaCfg.CertPins) > 0 {
+		// Checked even when certificate verification is disabled
+		tlsConfig.VerifyConnection = certPinVerifier(personaCfg.CertPins)
+	}
 
 	if proxy != nil && proxy.Address != "" && proxy.IsEnabled && proxy.IsHealthy {
 		var protocol string
@@ -167,17 +199,36 @@ func (hv *HTTPValidator) Validate(
 	if ua == "" {
 		ua = defaultUserAgent
 	}
-	req.Header.Set("User-Agent", ua)
+	// Templated values are rendered afresh for every request
+	templateValues := newHeaderTemplateValues(domain)
+	renderedUA, err := RenderHeaderTemplate(ua, templateValues)
+	if err != nil {
+		result.Error = fmt.Sprintf("Invalid userAgent template: %v", err)
+		result.Status = "ErrorRequestCreation"
+		result.DurationMs = time.Since(startTime).Milliseconds()
+		return result, err
+	}
+	req.Header.Set("User-Agent", renderedUA)
 	for key, value := range personaCfg.Headers {
-		req.Header.Set(key, value)
+		renderedValue, err := RenderHeaderTemplate(value, templateValues)
+		if err != nil {
+			result.Error = fmt.Sprintf("Invalid template for header %s: %v", key, err)
+			result.Status = "ErrorRequestCreation"
+			result.DurationMs = time.Since(startTime).Milliseconds()
+			return result, err
+		}
+		req.Header.Set(key, renderedValue)
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
 		result.Error = fmt.Sprintf("HTTP request failed: %v", err)
 		result.Status = "ErrorFetchFailed"
+		var pinErr *CertPinMismatchError
 		if urlErr, ok := err.(*url.Error); ok && urlErr.Timeout() {
 			result.Status = "ErrorTimeout"
+		} else if errors.As(err, &pinErr) {
+			result.Status = StatusCertPinMismatch
 		}
 		result.DurationMs = time.Since(startTime).Milliseconds()
 		return result, err
@@ -191,7 +242,48 @@ func (hv *HTTPValidator) Validate(
 	for key, values := range resp.Header {
 		result.ResponseHeaders[CanonicalHeaderKey(key)] = values
 	}
+	if personaCfg.CaptureTLS {
+		result.TLSCertificate = tlsCertificate(resp.TLS)
+	}
+
+	if readBody {
+		hv.readBody(resp, result, personaCfg.MaxBodyBytes)
+	}
+
+	result.IsSuccess = false
+	allowedCodes := personaCfg.AllowedStatusCodes
+	if len(allowedCodes) == 0 {
+		if result.StatusCode >= 200 && result.StatusCode < 300 {
+			result.IsSuccess = true
+		}
+	} else {
+		for _, code := range allowedCodes {
+			if result.StatusCode == code {
+				result.IsSuccess = true
+				break
+			}
+		}
+	}
 
+	if result.IsSuccess {
+		result.Status = "Validated"
+	} else {
+		if result.Status == "" {
+			result.Status = "FailedValidation"
+		}
+		if result.Error == "" {
+			result.Error = fmt.Sprintf("Validation failed: Status code %d not in allowed list or other rule violation.", result.StatusCode)
+		}
+	}
+
+	result.DurationMs = time.Since(startTime).Milliseconds()
+	return result, nil
+}
+
+// readBody reads up to maxBytes of the response body, or the configured limit when maxBytes is 0,
+// into result, with its hash and, for HTML, the page title and a snippet. A longer body is cut off
+// and marks the result BodyTruncated.
+func (hv *HTTPValidator) readBody(resp *http.Response, result *ValidationResult, maxBytes int64) {
 	var reader io.Reader = resp.Body
 	switch strings.ToLower(resp.Header.Get("Content-Encoding")) {
 	case "gzip":
@@ -212,11 +304,19 @@ func (hv *HTTPValidator) Validate(
 	if hv.appConfig.HTTPValidator.MaxBodyReadBytes > 0 {
 		maxRead = hv.appConfig.HTTPValidator.MaxBodyReadBytes
 	}
-	limitedReader := io.LimitReader(reader, maxRead)
+	if maxBytes > 0 {
+		maxRead = maxBytes
+	}
+	// One byte past the limit tells a body of exactly the limit from a longer one
+	limitedReader := io.LimitReader(reader, maxRead+1)
 	bodyBytes, readErr := io.ReadAll(limitedReader)
 	if readErr != nil {
 		result.ContentHashError = fmt.Sprintf("Failed to read response body: %v", readErr)
 	}
+	if int64(len(bodyBytes)) > maxRead {
+		bodyBytes = bodyBytes[:maxRead]
+		result.BodyTruncated = true
+	}
 	result.RawBody = bodyBytes
 	result.ContentLength = len(bodyBytes)
 
@@ -240,35 +340,25 @@ func (hv *HTTPValidator) Validate(
 			result.ExtractedContentSnippet = string(bodyBytes)
 		}
 	}
+}
 
-	result.IsSuccess = false
-	allowedCodes := personaCfg.AllowedStatusCodes
-	if len(allowedCodes) == 0 {
-		if result.StatusCode >= 200 && result.StatusCode < 300 {
-			result.IsSuccess = true
-		}
-	} else {
-		for _, code := range allowedCodes {
-			if result.StatusCode == code {
-				result.IsSuccess = true
-				break
-			}
-		}
+// tlsCertificate describes the connection state of an HTTPS response and its leaf certificate; nil
+// for plain HTTP
+func tlsCertificate(state *tls.ConnectionState) *models.HTTPTLSCertificate {
+	if state == nil {
+		return nil
 	}
-
-	if result.IsSuccess {
-		result.Status = "Validated"
-	} else {
-		if result.Status == "" {
-			result.Status = "FailedValidation"
-		}
-		if result.Error == "" {
-			result.Error = fmt.Sprintf("Validation failed: Status code %d not in allowed list or other rule violation.", result.StatusCode)
-		}
+	cert := &models.HTTPTLSCertificate{
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
 	}
-
-	result.DurationMs = time.Since(startTime).Milliseconds()
-	return result, nil
+	if len(state.PeerCertificates) > 0 {
+		leaf := state.PeerCertificates[0]
+		cert.Issuer = leaf.Issuer.String()
+		cert.Subject = leaf.Subject.String()
+		cert.NotAfter = leaf.NotAfter.UTC()
+	}
+	return cert
 }
 
 func (hv *HTTPValidator) ValidateHeadless(
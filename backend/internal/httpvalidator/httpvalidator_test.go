@@ -0,0 +1,110 @@
+package httpvalidator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func limitedPersona(t *testing.T, cfg models.HTTPConfigDetails) *models.Persona {
+	t.Helper()
+	cfg.UserAgent = "DomainFlow/1.0"
+	configDetails, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	return &models.Persona{ID: uuid.New(), Name: "limited", PersonaType: models.PersonaTypeHTTP, ConfigDetails: configDetails}
+}
+
+func TestValidate_MaxBodyBytes(t *testing.T) {
+	body := strings.Repeat("a", 3000) + "needle"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+	hv := NewHTTPValidator(&config.AppConfig{HTTPValidator: config.HTTPValidatorConfig{RequestTimeout: 5 * time.Second}})
+
+	result, err := hv.Validate(context.Background(), "example.com", server.URL, limitedPersona(t, models.HTTPConfigDetails{MaxBodyBytes: 2048}), nil)
+	require.NoError(t, err)
+	assert.True(t, result.IsSuccess, "a truncated body still validates")
+	assert.True(t, result.BodyTruncated)
+	assert.Len(t, result.RawBody, 2048)
+
+	// A body of exactly the limit is read whole
+	result, err = hv.Validate(context.Background(), "example.com", server.URL, limitedPersona(t, models.HTTPConfigDetails{MaxBodyBytes: int64(len(body))}), nil)
+	require.NoError(t, err)
+	assert.False(t, result.BodyTruncated)
+	assert.Equal(t, body, string(result.RawBody))
+}
+
+func TestValidate_MaxRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hop/", func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/hop/"))
+		if n >= 5 {
+			w.Write([]byte("arrived"))
+			return
+		}
+		http.Redirect(w, r, fmt.Sprintf("/hop/%d", n+1), http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	hv := NewHTTPValidator(&config.AppConfig{HTTPValidator: config.HTTPValidatorConfig{
+		RequestTimeout: 5 * time.Second, FollowRedirects: true, MaxRedirects: 10,
+	}})
+
+	result, err := hv.Validate(context.Background(), "example.com", server.URL+"/hop/0", limitedPersona(t, models.HTTPConfigDetails{}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, result.StatusCode, "the server default follows all five redirects")
+
+	result, err = hv.Validate(context.Background(), "example.com", server.URL+"/hop/0", limitedPersona(t, models.HTTPConfigDetails{MaxRedirects: 2}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusFound, result.StatusCode)
+	assert.Equal(t, server.URL+"/hop/2", result.FinalURL)
+
+	noRedirects := false
+	result, err = hv.Validate(context.Background(), "example.com", server.URL+"/hop/0", limitedPersona(t, models.HTTPConfigDetails{FollowRedirects: &noRedirects}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusFound, result.StatusCode)
+	assert.Equal(t, server.URL+"/hop/0", result.FinalURL)
+}
+
+func TestValidate_CaptureTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	hv := insecureValidator()
+
+	result, err := hv.Validate(context.Background(), "example.com", server.URL, limitedPersona(t, models.HTTPConfigDetails{}), nil)
+	require.NoError(t, err)
+	assert.Nil(t, result.TLSCertificate, "TLS is only captured when the persona asks for it")
+
+	result, err = hv.Validate(context.Background(), "example.com", server.URL, limitedPersona(t, models.HTTPConfigDetails{CaptureTLS: true}), nil)
+	require.NoError(t, err)
+	require.NotNil(t, result.TLSCertificate)
+	leaf := server.Certificate()
+	assert.Equal(t, "TLS 1.3", result.TLSCertificate.Version)
+	assert.NotEmpty(t, result.TLSCertificate.CipherSuite)
+	assert.Equal(t, leaf.Issuer.String(), result.TLSCertificate.Issuer)
+	assert.Equal(t, leaf.Subject.String(), result.TLSCertificate.Subject)
+	assert.True(t, leaf.NotAfter.Equal(result.TLSCertificate.NotAfter))
+
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer plain.Close()
+	result, err = hv.Validate(context.Background(), "example.com", plain.URL, limitedPersona(t, models.HTTPConfigDetails{CaptureTLS: true}), nil)
+	require.NoError(t, err)
+	assert.Nil(t, result.TLSCertificate)
+}
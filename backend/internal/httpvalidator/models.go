@@ -1,6 +1,10 @@
 package httpvalidator
 
-import "time"
+import (
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+)
 
 // ValidationResult holds the result of a single domain HTTP validation attempt.
 // This can be from a standard HTTP client or a headless browser.
@@ -16,11 +20,14 @@ type ValidationResult struct {
 	ContentLength           int                 `json:"contentLength,omitempty"`           // Length of body read for hashing/analysis
 	ActualContentLength     int64               `json:"actualContentLength,omitempty"`     // From Content-Length header or full body if read
 	ContentHashError        string              `json:"contentHashError,omitempty"`        // Error if hashing failed
+	BodyTruncated           bool                `json:"bodyTruncated,omitempty"`           // Body was cut off at the read limit; hash, title and snippet cover the part read
 	ExtractedTitle          string              `json:"extractedTitle,omitempty"`          // Extracted <title> from the page
 	ExtractedContentSnippet string              `json:"extractedContentSnippet,omitempty"` // Extracted snippet of content
 	AntiBotIndicators       map[string]string   `json:"antiBotIndicators,omitempty"`       // Detected anti-bot measures
 	Error                   string              `json:"error,omitempty"`                   // Detailed error message if any step failed
 
+	TLSCertificate *models.HTTPTLSCertificate `json:"tlsCertificate,omitempty"` // Set for HTTPS responses when the persona captures TLS
+
 	// Headless-specific results
 	IsHeadless      bool   `json:"isHeadless,omitempty"`      // True if this result is from a headless browser attempt
 	ScreenshotPath  string `json:"screenshotPath,omitempty"`  // Relative path to screenshot (if taken)
@@ -0,0 +1,194 @@
+package keywordscanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+)
+
+// Limits on regex keyword rules, applied wherever a rule is compiled for scanning
+const (
+	MaxRegexPatternLength = 1024                   // Bytes of pattern source
+	RegexCompileTimeout   = 250 * time.Millisecond // Patterns that take longer to compile are rejected
+	RegexMatchTimeout     = 100 * time.Millisecond // A search of one page that takes longer is abandoned
+)
+
+// Rule compilation errors
+var (
+	ErrRegexPatternTooLong = fmt.Errorf("regex pattern exceeds %d bytes", MaxRegexPatternLength)
+	ErrRegexCompileTimeout = fmt.Errorf("regex pattern took longer than %s to compile", RegexCompileTimeout)
+	ErrRegexMatchTimeout   = fmt.Errorf("regex search took longer than %s", RegexMatchTimeout)
+	ErrUnknownRuleType     = errors.New("rule type must be string or regex")
+	ErrUnknownMatchType    = errors.New("match type must be substring, regex or word_boundary")
+)
+
+// KeywordMatch is one occurrence of a rule's pattern. Start and End are byte offsets into the
+// scanned content.
+type KeywordMatch struct {
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// CompileKeywordRule prepares a rule for scanning. Regex rules are compiled as written, within
+// the pattern length and compile time limits; their case sensitivity comes from the pattern
+// itself, e.g. (?i).
+func CompileKeywordRule(rule models.KeywordRule) (CompiledKeywordRule, error) {
+	return CompileKeywordRuleWithMatchType(rule, models.KeywordMatchTypeSubstring)
+}
+
+// CompileKeywordRuleWithMatchType prepares a rule for scanning with its keyword set's match
+// type, which applies to string rules: substring rules match anywhere, word_boundary rules only
+// as a whole word and regex rules as a regular expression. String rules are case-insensitive
+// unless IsCaseSensitive is set. Regex rules always match as written.
+func CompileKeywordRuleWithMatchType(rule models.KeywordRule, matchType models.KeywordMatchTypeEnum) (CompiledKeywordRule, error) {
+	compiled := CompiledKeywordRule{KeywordRule: rule, MatchType: models.KeywordMatchTypeRegex}
+	pattern := rule.Pattern
+	switch rule.RuleType {
+	case models.KeywordRuleTypeRegex:
+	case models.KeywordRuleTypeString:
+		switch matchType {
+		case "", models.KeywordMatchTypeSubstring:
+			compiled.MatchType = models.KeywordMatchTypeSubstring
+			return compiled, nil
+		case models.KeywordMatchTypeWordBoundary:
+			pattern = wordBoundaryPattern(rule.Pattern)
+		case models.KeywordMatchTypeRegex:
+		default:
+			return compiled, ErrUnknownMatchType
+		}
+		compiled.MatchType = matchType
+		if !rule.IsCaseSensitive {
+			pattern = "(?i)" + pattern
+		}
+	default:
+		return compiled, ErrUnknownRuleType
+	}
+	if len(rule.Pattern) > MaxRegexPatternLength {
+		return compiled, ErrRegexPatternTooLong
+	}
+	re, err := compileWithTimeout(pattern)
+	if err != nil {
+		return compiled, err
+	}
+	compiled.CompiledRegex = re
+	return compiled, nil
+}
+
+// CompileAdHocKeywords prepares a campaign's ad hoc keywords for scanning with the campaign's
+// match type. Ad hoc keywords are case-insensitive. The error names the first keyword that does
+// not compile.
+func CompileAdHocKeywords(keywords []string, matchType models.KeywordMatchTypeEnum) ([]CompiledKeywordRule, error) {
+	compiled := make([]CompiledKeywordRule, 0, len(keywords))
+	for _, keyword := range keywords {
+		if keyword == "" {
+			continue
+		}
+		rule, err := CompileKeywordRuleWithMatchType(models.KeywordRule{Pattern: keyword, RuleType: models.KeywordRuleTypeString}, matchType)
+		if err != nil {
+			return nil, fmt.Errorf("ad hoc keyword %q: %w", keyword, err)
+		}
+		compiled = append(compiled, rule)
+	}
+	return compiled, nil
+}
+
+// wordBoundaryPattern matches keyword literally, bounded by \b on each side that starts or ends
+// with a word character; a keyword such as "C++" cannot be followed by a word boundary.
+func wordBoundaryPattern(keyword string) string {
+	isWordByte := func(b byte) bool {
+		return b == '_' || ('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+	}
+	pattern := regexp.QuoteMeta(keyword)
+	if keyword == "" {
+		return pattern
+	}
+	if isWordByte(keyword[0]) {
+		pattern = `\b` + pattern
+	}
+	if isWordByte(keyword[len(keyword)-1]) {
+		pattern += `\b`
+	}
+	return pattern
+}
+
+// compileWithTimeout compiles a regex, giving up on patterns that take longer than
+// RegexCompileTimeout. An abandoned compile finishes in the background.
+func compileWithTimeout(pattern string) (*regexp.Regexp, error) {
+
+	type compileResult struct {
+		re  *regexp.Regexp
+		err error
+	}
+	done := make(chan compileResult, 1)
+	go func() {
+		re, err := regexp.Compile(pattern)
+		done <- compileResult{re: re, err: err}
+	}()
+	select {
+	case result := <-done:
+		return result.re, result.err
+	case <-time.After(RegexCompileTimeout):
+		return nil, ErrRegexCompileTimeout
+	}
+}
+
+// findIndexWithDeadline returns the location of the first match of re in content, or nil. Go's
+// regexp engine runs in time linear in the input, so no pattern can backtrack catastrophically,
+// but a complex pattern over a large page can still be slow: the search is abandoned after
+// RegexMatchTimeout, or when ctx is done, and finishes in the background.
+func findIndexWithDeadline(ctx context.Context, re *regexp.Regexp, content []byte) ([]int, error) {
+	done := make(chan []int, 1)
+	go func() {
+		done <- re.FindIndex(content)
+	}()
+	timer := time.NewTimer(RegexMatchTimeout)
+	defer timer.Stop()
+	select {
+	case loc := <-done:
+		return loc, nil
+	case <-timer.C:
+		return nil, ErrRegexMatchTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// FindMatches returns up to limit occurrences of the rule in content, in order. A limit of zero
+// or less returns them all.
+func FindMatches(rule CompiledKeywordRule, content []byte, limit int) []KeywordMatch {
+	re := rule.CompiledRegex
+	if re == nil && rule.RuleType == models.KeywordRuleTypeString && rule.Pattern != "" {
+		// Same matches as the case-folded Contains used when scanning, with offsets into the original
+		pattern := regexp.QuoteMeta(rule.Pattern)
+		if !rule.IsCaseSensitive {
+			pattern = "(?i)" + pattern
+		}
+		re = regexp.MustCompile(pattern)
+	}
+	if re == nil {
+		return nil
+	}
+	n := limit
+	if n <= 0 {
+		n = -1
+	}
+	matches := []KeywordMatch{}
+	for _, loc := range re.FindAllIndex(content, n) {
+		matches = append(matches, KeywordMatch{Text: string(content[loc[0]:loc[1]]), Start: loc[0], End: loc[1]})
+	}
+	return matches
+}
+
+// CompileErrorMessage describes why a rule failed to compile, without the regexp package's prefix
+func CompileErrorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return strings.TrimPrefix(err.Error(), "error parsing regexp: ")
+}
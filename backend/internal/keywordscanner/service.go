@@ -2,7 +2,9 @@ package keywordscanner
 
 import (
 	"context" // Added context
+	"errors"
 	"fmt"
+	"log"
 	"regexp" // Added for compiling regex rules
 	"strings"
 
@@ -27,50 +29,101 @@ func NewService(ks store.KeywordStore /*, appCfg *config.AppConfig*/) *Service {
 }
 
 // CompiledKeywordRule holds a rule with its regex pre-compiled for efficiency.
+// Substring rules have no regex and are matched with a case-folded Contains.
 type CompiledKeywordRule struct {
 	models.KeywordRule
 	CompiledRegex *regexp.Regexp
+	MatchType     models.KeywordMatchTypeEnum // How the rule was compiled to match
+}
+
+// RuleMatch records a rule that matched scanned content and the text it first matched
+type RuleMatch struct {
+	Pattern      string                      `json:"pattern"`
+	MatchType    models.KeywordMatchTypeEnum `json:"matchType"`
+	Matched      string                      `json:"matched"`
+	KeywordSetID uuid.UUID                   `json:"keywordSetId"`
+	RuleID       uuid.UUID                   `json:"ruleId"`
+	Category     string                      `json:"category,omitempty"`
 }
 
 // ScanWithRules directly takes content and a list of already fetched and compiled rules.
 // This is useful if the caller (e.g., HTTPKeywordCampaignService) has already fetched the rules.
 func (s *Service) ScanWithRules(ctx context.Context, content []byte, rules []CompiledKeywordRule) ([]string, error) {
+	matches, err := s.ScanRuleMatches(ctx, content, rules)
+	if err != nil {
+		return nil, err
+	}
+	var foundPatterns []string
+	for _, match := range matches {
+		foundPatterns = append(foundPatterns, match.Pattern)
+	}
+	return foundPatterns, nil
+}
+
+// ScanRuleMatches returns the rules that match content, in rule order. A regex search that runs
+// past RegexMatchTimeout counts as no match; only the context ending stops the scan.
+func (s *Service) ScanRuleMatches(ctx context.Context, content []byte, rules []CompiledKeywordRule) ([]RuleMatch, error) {
 	if len(content) == 0 || len(rules) == 0 {
 		return nil, nil
 	}
 	contentStrLower := strings.ToLower(string(content)) // Pre-convert content to lower for case-insensitive string matches
-	var foundPatterns []string
+	// Offsets into the lowered content are offsets into the original unless case folding changed its length
+	foldedOffsetsMatch := len(contentStrLower) == len(content)
+	var matches []RuleMatch
 
 	for _, rule := range rules {
 		if rule.KeywordRule.Pattern == "" {
 			continue
 		}
-		matched := false
-		switch rule.KeywordRule.RuleType {
-		case models.KeywordRuleTypeRegex:
-			if rule.CompiledRegex != nil {
-				if rule.CompiledRegex.Match(content) {
-					matched = true
-				}
+		matched := ""
+		found := false
+		switch {
+		case rule.CompiledRegex != nil:
+			loc, err := findIndexWithDeadline(ctx, rule.CompiledRegex, content)
+			if errors.Is(err, ErrRegexMatchTimeout) {
+				log.Printf("keywordscanner: skipping rule %s (pattern: %s): %v", rule.ID, rule.Pattern, err)
+				continue
 			}
-		case models.KeywordRuleTypeString:
+			if err != nil {
+				return nil, err
+			}
+			if loc != nil {
+				matched, found = string(content[loc[0]:loc[1]]), true
+			}
+		case rule.KeywordRule.RuleType == models.KeywordRuleTypeString:
 			patternToMatch := rule.KeywordRule.Pattern
 			contentToSearch := string(content)
 			if !rule.KeywordRule.IsCaseSensitive {
 				patternToMatch = strings.ToLower(rule.KeywordRule.Pattern)
 				contentToSearch = contentStrLower
 			}
-			if strings.Contains(contentToSearch, patternToMatch) {
-				matched = true
+			if idx := strings.Index(contentToSearch, patternToMatch); idx >= 0 {
+				matched, found = rule.KeywordRule.Pattern, true
+				if rule.KeywordRule.IsCaseSensitive || foldedOffsetsMatch {
+					matched = string(content[idx : idx+len(patternToMatch)])
+				}
 			}
 		}
 
-		if matched {
-			// Decide what to return: rule.Pattern, rule.Category.String, or a more complex match object
-			foundPatterns = append(foundPatterns, rule.KeywordRule.Pattern)
+		if found {
+			matchType := rule.MatchType
+			if matchType == "" {
+				matchType = models.KeywordMatchTypeSubstring
+				if rule.KeywordRule.RuleType == models.KeywordRuleTypeRegex {
+					matchType = models.KeywordMatchTypeRegex
+				}
+			}
+			matches = append(matches, RuleMatch{
+				Pattern:      rule.KeywordRule.Pattern,
+				MatchType:    matchType,
+				Matched:      matched,
+				KeywordSetID: rule.KeywordSetID,
+				RuleID:       rule.ID,
+				Category:     rule.Category.String,
+			})
 		}
 	}
-	return foundPatterns, nil
+	return matches, nil
 }
 
 // ScanBySetIDs fetches keyword sets and their rules from the store and then scans content.
@@ -81,7 +134,6 @@ func (s *Service) ScanBySetIDs(ctx context.Context, exec store.Querier, content
 	}
 
 	results := make(map[string][]string)
-	contentStrLower := strings.ToLower(string(content))
 
 	for _, setIDStr := range keywordSetIDs {
 		setID_uuid, err := uuid.Parse(setIDStr)
@@ -107,45 +159,18 @@ func (s *Service) ScanBySetIDs(ctx context.Context, exec store.Querier, content
 
 		var compiledRules []CompiledKeywordRule
 		for _, mr := range modelRules {
-			cr := CompiledKeywordRule{KeywordRule: mr}
-			if mr.RuleType == models.KeywordRuleTypeRegex {
-				re, compErr := regexp.Compile(mr.Pattern) // Compile regex here
-				if compErr == nil {
-					cr.CompiledRegex = re
-				} else {
-					// Log regex compilation error and skip this rule
-					fmt.Printf("Error compiling regex for rule %s (pattern: %s): %v\n", mr.ID.String(), mr.Pattern, compErr)
-					continue
-				}
+			cr, compErr := CompileKeywordRuleWithMatchType(mr, kset.MatchType)
+			if compErr != nil {
+				// Log regex compilation error and skip this rule
+				fmt.Printf("Error compiling regex for rule %s (pattern: %s): %v\n", mr.ID.String(), mr.Pattern, compErr)
+				continue
 			}
 			compiledRules = append(compiledRules, cr)
 		}
 
-		var foundInSet []string
-		for _, rule := range compiledRules {
-			if rule.KeywordRule.Pattern == "" {
-				continue
-			}
-			matched := false
-			switch rule.KeywordRule.RuleType {
-			case models.KeywordRuleTypeRegex:
-				if rule.CompiledRegex != nil && rule.CompiledRegex.Match(content) {
-					matched = true
-				}
-			case models.KeywordRuleTypeString:
-				patternToMatch := rule.KeywordRule.Pattern
-				contentToSearch := string(content)
-				if !rule.KeywordRule.IsCaseSensitive {
-					patternToMatch = strings.ToLower(rule.KeywordRule.Pattern)
-					contentToSearch = contentStrLower
-				}
-				if strings.Contains(contentToSearch, patternToMatch) {
-					matched = true
-				}
-			}
-			if matched {
-				foundInSet = append(foundInSet, rule.KeywordRule.Pattern)
-			}
+		foundInSet, err := s.ScanWithRules(ctx, content, compiledRules)
+		if err != nil {
+			return nil, err
 		}
 
 		if len(foundInSet) > 0 {
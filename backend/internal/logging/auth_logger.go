@@ -1,10 +1,16 @@
 package logging
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"io"
 	"log"
 	"os"
 	"runtime"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -21,6 +27,17 @@ const (
 	LogLevelFatal LogLevel = "FATAL"
 )
 
+// ParseLevel returns the level named by s, in any case; "WARNING" is LogLevelWarn
+func ParseLevel(s string) (LogLevel, error) {
+	switch level := LogLevel(strings.ToUpper(strings.TrimSpace(s))); level {
+	case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError, LogLevelFatal:
+		return level, nil
+	case "WARNING":
+		return LogLevelWarn, nil
+	}
+	return "", fmt.Errorf("unknown log level %q", s)
+}
+
 // LogCategory represents the category of authentication operation
 type LogCategory string
 
@@ -33,6 +50,7 @@ const (
 	CategoryRateLimit   LogCategory = "RATE_LIMIT"
 	CategorySecurity    LogCategory = "SECURITY"
 	CategoryPerformance LogCategory = "PERFORMANCE"
+	CategoryHTTP        LogCategory = "HTTP"
 )
 
 // AuthLogEntry represents a structured log entry for authentication operations
@@ -93,6 +111,7 @@ type PerformanceMetrics struct {
 // AuthLogger provides structured logging for authentication operations
 type AuthLogger struct {
 	logger *log.Logger
+	mu     sync.RWMutex // Guards level, which can change while requests are logging
 	level  LogLevel
 }
 
@@ -104,11 +123,25 @@ func NewAuthLogger() *AuthLogger {
 	}
 }
 
+// SetOutput sets where log entries are written, stdout by default
+func (l *AuthLogger) SetOutput(w io.Writer) {
+	l.logger.SetOutput(w)
+}
+
 // SetLevel sets the minimum log level
 func (l *AuthLogger) SetLevel(level LogLevel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.level = level
 }
 
+// Level returns the minimum log level
+func (l *AuthLogger) Level() LogLevel {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.level
+}
+
 // Debug logs a debug message
 func (l *AuthLogger) Debug(category LogCategory, operation string, details map[string]interface{}) {
 	success := true
@@ -317,6 +350,14 @@ func (l *AuthLogger) LogSessionEvent(
 	l.log(LogLevelInfo, CategorySession, operation, userID, sessionID, ipAddress, userAgent, "", &success, "", "", details, nil, nil, nil)
 }
 
+// RedactSessionID returns the form of a session ID written to logs: the start of its SHA-256 hash
+// and its length. Entries about one session can be correlated, but the ID, which is a bearer
+// credential, cannot be recovered from them.
+func RedactSessionID(sessionID string) string {
+	sum := sha256.Sum256([]byte(sessionID))
+	return fmt.Sprintf("sha256:%s/%d", hex.EncodeToString(sum[:6]), len(sessionID))
+}
+
 // Private method to handle actual logging
 func (l *AuthLogger) log(
 	level LogLevel,
@@ -349,6 +390,11 @@ func (l *AuthLogger) log(
 		function = runtime.FuncForPC(pc).Name()
 	}
 
+	if sessionID != nil {
+		redacted := RedactSessionID(*sessionID)
+		sessionID = &redacted
+	}
+
 	entry := AuthLogEntry{
 		Timestamp:    time.Now().UTC(),
 		Level:        level,
@@ -394,7 +440,7 @@ func (l *AuthLogger) shouldLog(level LogLevel) bool {
 		LogLevelFatal: 4,
 	}
 
-	return levels[level] >= levels[l.level]
+	return levels[level] >= levels[l.Level()]
 }
 
 // getStackTrace returns the current stack trace
@@ -407,6 +453,11 @@ func (l *AuthLogger) getStackTrace() string {
 // Global logger instance
 var GlobalAuthLogger = NewAuthLogger()
 
+// SetLevel sets the minimum level of the global logger
+func SetLevel(level LogLevel) {
+	GlobalAuthLogger.SetLevel(level)
+}
+
 // Convenience functions for global logger
 func Debug(category LogCategory, operation string, details map[string]interface{}) {
 	GlobalAuthLogger.Debug(category, operation, details)
@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RequestLogger logs through an AuthLogger with the fields of one HTTP request on every entry: its
+// request ID, client address, user agent and, once known, the authenticated user. The method and
+// path are added to the details.
+type RequestLogger struct {
+	logger    *AuthLogger
+	requestID string
+	method    string
+	path      string
+	ipAddress string
+	userAgent string
+	userID    *uuid.UUID
+}
+
+// NewRequestLogger creates a logger for a request, writing through the global logger
+func NewRequestLogger(requestID, method, path, ipAddress, userAgent string) *RequestLogger {
+	return &RequestLogger{
+		logger:    GlobalAuthLogger,
+		requestID: requestID,
+		method:    method,
+		path:      path,
+		ipAddress: ipAddress,
+		userAgent: userAgent,
+	}
+}
+
+// WithUser returns a copy of the logger that also records userID
+func (r *RequestLogger) WithUser(userID uuid.UUID) *RequestLogger {
+	copied := *r
+	copied.userID = &userID
+	return &copied
+}
+
+// RequestID returns the ID of the logger's request
+func (r *RequestLogger) RequestID() string {
+	return r.requestID
+}
+
+// Debug logs a debug message, written only when the level is DEBUG
+func (r *RequestLogger) Debug(category LogCategory, operation string, details map[string]interface{}) {
+	r.log(LogLevelDebug, category, operation, nil, details)
+}
+
+// Info logs an info message
+func (r *RequestLogger) Info(category LogCategory, operation string, details map[string]interface{}) {
+	r.log(LogLevelInfo, category, operation, nil, details)
+}
+
+// Warn logs a warning message
+func (r *RequestLogger) Warn(category LogCategory, operation string, details map[string]interface{}) {
+	r.log(LogLevelWarn, category, operation, nil, details)
+}
+
+// Error logs an error message
+func (r *RequestLogger) Error(category LogCategory, operation string, err error, details map[string]interface{}) {
+	r.log(LogLevelError, category, operation, err, details)
+}
+
+func (r *RequestLogger) log(level LogLevel, category LogCategory, operation string, err error, details map[string]interface{}) {
+	if !r.logger.shouldLog(level) {
+		return
+	}
+	fields := make(map[string]interface{}, len(details)+2)
+	for key, value := range details {
+		fields[key] = value
+	}
+	if r.method != "" {
+		fields["method"] = r.method
+		fields["path"] = r.path
+	}
+
+	errorCode, errorMessage := "", ""
+	if err != nil {
+		errorCode, errorMessage = "UNKNOWN_ERROR", err.Error()
+	}
+	success := level != LogLevelWarn && level != LogLevelError && level != LogLevelFatal
+	r.logger.log(level, category, operation, r.userID, nil, r.ipAddress, r.userAgent, r.requestID, &success,
+		errorCode, errorMessage, fields, nil, nil, nil)
+}
+
+type requestLoggerKey struct{}
+
+// NewContext returns a copy of ctx carrying logger
+func NewContext(ctx context.Context, logger *RequestLogger) context.Context {
+	return context.WithValue(ctx, requestLoggerKey{}, logger)
+}
+
+// FromContext returns the request logger carried by ctx, or one without request fields that
+// writes through the global logger
+func FromContext(ctx context.Context) *RequestLogger {
+	if logger, ok := ctx.Value(requestLoggerKey{}).(*RequestLogger); ok {
+		return logger
+	}
+	return &RequestLogger{logger: GlobalAuthLogger}
+}
@@ -0,0 +1,166 @@
+// Package metrics publishes the server's sessions, campaign jobs, campaigns and login outcomes for
+// Prometheus to scrape. Counters are updated where the events happen; gauges are read from their
+// sources on each scrape.
+package metrics
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "domainflow"
+
+// scrapeQueryTimeout bounds the store queries made for one scrape
+const scrapeQueryTimeout = 5 * time.Second
+
+var (
+	registry = prometheus.NewRegistry()
+
+	authFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_failures_total",
+		Help:      "Failed login attempts, by outcome (failure, lockout or rate_limited).",
+	}, []string{"outcome"})
+
+	jobTransitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "campaign_job_transitions_total",
+		Help:      "Campaign jobs moved to a status by the workers or the stale job reaper, by job type and status.",
+	}, []string{"job_type", "status"})
+)
+
+func init() {
+	registry.MustRegister(
+		authFailures,
+		jobTransitions,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{Namespace: namespace}),
+	)
+}
+
+// RecordAuthFailure counts a failed login attempt; outcome is "failure", "lockout" or "rate_limited"
+func RecordAuthFailure(outcome string) {
+	authFailures.WithLabelValues(outcome).Inc()
+}
+
+// RecordJobTransition counts a campaign job moved to status
+func RecordJobTransition(jobType models.CampaignTypeEnum, status models.CampaignJobStatusEnum) {
+	jobTransitions.WithLabelValues(string(jobType), string(status)).Inc()
+}
+
+// Register adds a collector to the metrics served by Handler
+func Register(collector prometheus.Collector) error {
+	return registry.Register(collector)
+}
+
+// Handler serves the registered metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// SessionStats are the session figures published on each scrape
+type SessionStats struct {
+	Total          int64
+	Active         int64
+	CacheHitRate   float64
+	CachedSessions int64
+	CacheEvictions int64
+	SharedLoads    int64
+	SecurityEvents int64
+}
+
+// LoginStats are the rolling login rates published on each scrape
+type LoginStats struct {
+	SuccessesPerMinute float64
+	FailuresPerMinute  float64
+	LockoutsPerMinute  float64
+}
+
+// StateCollector reads server state when Prometheus scrapes. Sources left nil are not published.
+type StateCollector struct {
+	Sessions func() SessionStats
+	Logins   func() LoginStats
+	Counts   store.MetricsStore // Campaigns and jobs by status
+
+	sessionsTotal, sessionsActive, sessionCacheHitRate        *prometheus.Desc
+	sessionsCached, sessionCacheEvictions, sessionSharedLoads *prometheus.Desc
+	sessionSecurityEvents                                     *prometheus.Desc
+	loginsPerMinute                                           *prometheus.Desc
+	jobsByStatus, campaignsByStatus                           *prometheus.Desc
+}
+
+// NewStateCollector creates a collector reading the given sources
+func NewStateCollector(sessions func() SessionStats, logins func() LoginStats, counts store.MetricsStore) *StateCollector {
+	return &StateCollector{
+		Sessions: sessions,
+		Logins:   logins,
+		Counts:   counts,
+
+		sessionsTotal:         prometheus.NewDesc(namespace+"_sessions_created_total", "Sessions created, carried across restarts by the session metric snapshots.", nil, nil),
+		sessionsActive:        prometheus.NewDesc(namespace+"_sessions_active", "Sessions currently active.", nil, nil),
+		sessionCacheHitRate:   prometheus.NewDesc(namespace+"_session_cache_hit_rate", "Moving average of the share of session lookups answered from memory, 0 to 1.", nil, nil),
+		sessionsCached:        prometheus.NewDesc(namespace+"_sessions_cached", "Sessions held in memory.", nil, nil),
+		sessionCacheEvictions: prometheus.NewDesc(namespace+"_session_cache_evictions_total", "Sessions dropped from memory to stay within the cache bound.", nil, nil),
+		sessionSharedLoads:    prometheus.NewDesc(namespace+"_session_shared_loads_total", "Session validations that waited for a concurrent database load.", nil, nil),
+		sessionSecurityEvents: prometheus.NewDesc(namespace+"_session_security_events_total", "Session security events, such as IP or user agent mismatches.", nil, nil),
+		loginsPerMinute:       prometheus.NewDesc(namespace+"_logins_per_minute", "Login attempts per minute over the login rate window, by outcome.", []string{"outcome"}, nil),
+		jobsByStatus:          prometheus.NewDesc(namespace+"_campaign_jobs", "Campaign jobs in the queue, by status.", []string{"status"}, nil),
+		campaignsByStatus:     prometheus.NewDesc(namespace+"_campaigns", "Campaigns, by status.", []string{"status"}, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *StateCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{c.sessionsTotal, c.sessionsActive, c.sessionCacheHitRate, c.sessionsCached,
+		c.sessionCacheEvictions, c.sessionSharedLoads, c.sessionSecurityEvents, c.loginsPerMinute, c.jobsByStatus, c.campaignsByStatus} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector. A count the store fails to read is left out of the
+// scrape rather than failing it.
+func (c *StateCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.Sessions != nil {
+		stats := c.Sessions()
+		ch <- prometheus.MustNewConstMetric(c.sessionsTotal, prometheus.CounterValue, float64(stats.Total))
+		ch <- prometheus.MustNewConstMetric(c.sessionsActive, prometheus.GaugeValue, float64(stats.Active))
+		ch <- prometheus.MustNewConstMetric(c.sessionCacheHitRate, prometheus.GaugeValue, stats.CacheHitRate)
+		ch <- prometheus.MustNewConstMetric(c.sessionsCached, prometheus.GaugeValue, float64(stats.CachedSessions))
+		ch <- prometheus.MustNewConstMetric(c.sessionCacheEvictions, prometheus.CounterValue, float64(stats.CacheEvictions))
+		ch <- prometheus.MustNewConstMetric(c.sessionSharedLoads, prometheus.CounterValue, float64(stats.SharedLoads))
+		ch <- prometheus.MustNewConstMetric(c.sessionSecurityEvents, prometheus.CounterValue, float64(stats.SecurityEvents))
+	}
+	if c.Logins != nil {
+		stats := c.Logins()
+		ch <- prometheus.MustNewConstMetric(c.loginsPerMinute, prometheus.GaugeValue, stats.SuccessesPerMinute, "success")
+		ch <- prometheus.MustNewConstMetric(c.loginsPerMinute, prometheus.GaugeValue, stats.FailuresPerMinute, "failure")
+		ch <- prometheus.MustNewConstMetric(c.loginsPerMinute, prometheus.GaugeValue, stats.LockoutsPerMinute, "lockout")
+	}
+	if c.Counts == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeQueryTimeout)
+	defer cancel()
+	if jobs, err := c.Counts.CountCampaignJobsByStatus(ctx, nil); err != nil {
+		log.Printf("Metrics: failed to count campaign jobs by status: %v", err)
+	} else {
+		for status, count := range jobs {
+			ch <- prometheus.MustNewConstMetric(c.jobsByStatus, prometheus.GaugeValue, float64(count), string(status))
+		}
+	}
+	if campaigns, err := c.Counts.CountCampaignsByStatus(ctx, nil); err != nil {
+		log.Printf("Metrics: failed to count campaigns by status: %v", err)
+	} else {
+		for status, count := range campaigns {
+			ch <- prometheus.MustNewConstMetric(c.campaignsByStatus, prometheus.GaugeValue, float64(count), string(status))
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedCounts serves fixed campaign counts and fails to count jobs
+type fixedCounts struct{}
+
+func (fixedCounts) CountCampaignsByStatus(ctx context.Context, exec store.Querier) (map[models.CampaignStatusEnum]int64, error) {
+	return map[models.CampaignStatusEnum]int64{models.CampaignStatusRunning: 3, models.CampaignStatusCompleted: 12}, nil
+}
+
+func (fixedCounts) CountCampaignJobsByStatus(ctx context.Context, exec store.Querier) (map[models.CampaignJobStatusEnum]int64, error) {
+	return nil, errors.New("database unavailable")
+}
+
+func scrape(t *testing.T) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(w.Result().Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestHandler_PublishesStateAndCounters(t *testing.T) {
+	require.NoError(t, Register(NewStateCollector(
+		func() SessionStats { return SessionStats{Total: 40, Active: 7, CacheHitRate: 0.75, CachedSessions: 5} },
+		func() LoginStats { return LoginStats{SuccessesPerMinute: 2, FailuresPerMinute: 0.5} },
+		fixedCounts{},
+	)))
+	RecordAuthFailure("failure")
+	RecordAuthFailure("failure")
+	RecordAuthFailure("lockout")
+	RecordJobTransition(models.CampaignTypeDNSValidation, models.JobStatusCompleted)
+
+	body := scrape(t)
+	for _, line := range []string{
+		"domainflow_sessions_created_total 40",
+		"domainflow_sessions_active 7",
+		"domainflow_session_cache_hit_rate 0.75",
+		"domainflow_sessions_cached 5",
+		`domainflow_logins_per_minute{outcome="failure"} 0.5`,
+		`domainflow_campaigns{status="running"} 3`,
+		`domainflow_campaigns{status="completed"} 12`,
+		`domainflow_auth_failures_total{outcome="failure"} 2`,
+		`domainflow_auth_failures_total{outcome="lockout"} 1`,
+		`domainflow_campaign_job_transitions_total{job_type="dns_validation",status="completed"} 1`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+	assert.NotContains(t, body, "domainflow_campaign_jobs{", "counts the store fails to read are left out")
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+)
+
+// memoryAPIKeyStore looks keys up by hash in memory
+type memoryAPIKeyStore struct {
+	store.APIKeyStore
+	keys map[string]*models.APIKey
+}
+
+func (s *memoryAPIKeyStore) GetAPIKeyByHash(ctx context.Context, exec store.Querier, keyHash string) (*models.APIKey, error) {
+	key, ok := s.keys[keyHash]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return key, nil
+}
+
+func newAPIKeyTestRouter(keys store.APIKeyStore) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	m := NewAuthMiddleware(nil, &config.SessionSettings{CookieName: "domainflow_session"})
+	m.SetAPIKeyStore(keys)
+	router := gin.New()
+	router.Use(m.DualAuth())
+	router.GET("/campaigns", m.RequirePermission("campaigns:read"), func(c *gin.Context) {
+		securityContext := c.MustGet("security_context").(*models.SecurityContext)
+		c.JSON(http.StatusOK, gin.H{"userId": securityContext.UserID, "authType": c.GetString("auth_type")})
+	})
+	router.POST("/campaigns", m.RequirePermission("campaigns:create"), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	return router
+}
+
+func TestDualAuth_APIKeyScopes(t *testing.T) {
+	owner := uuid.New()
+	past := time.Now().Add(-time.Minute)
+	keys := &memoryAPIKeyStore{keys: map[string]*models.APIKey{
+		services.HashAPIKey("reader-key"):  {ID: uuid.New(), UserID: owner, Scopes: []string{"campaigns:read"}},
+		services.HashAPIKey("revoked-key"): {ID: uuid.New(), UserID: owner, Scopes: []string{"campaigns:read"}, RevokedAt: &past},
+		services.HashAPIKey("expired-key"): {ID: uuid.New(), UserID: owner, Scopes: []string{"campaigns:read"}, ExpiresAt: &past},
+	}}
+	router := newAPIKeyTestRouter(keys)
+
+	request := func(method, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/campaigns", nil)
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request(http.MethodGet, "reader-key")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), owner.String())
+	assert.Contains(t, w.Body.String(), `"authType":"api_key"`)
+
+	// The key only carries its scopes
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPost, "reader-key").Code)
+
+	for _, key := range []string{"revoked-key", "expired-key", "unknown-key"} {
+		w := request(http.MethodGet, key)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, key)
+		assert.Contains(t, w.Body.String(), "INVALID_API_KEY", key)
+	}
+
+	// Without a bearer token the request needs a session
+	w = request(http.MethodGet, "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.NotContains(t, w.Body.String(), "INVALID_API_KEY")
+}
+
+func TestDualAuth_RejectsKeysWithoutStore(t *testing.T) {
+	router := newAPIKeyTestRouter(nil)
+	req := httptest.NewRequest(http.MethodGet, "/campaigns", nil)
+	req.Header.Set("Authorization", "Bearer reader-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fntelecomllc/studio/backend/internal/services"
+)
+
+func TestGetErrorCode_SessionErrors(t *testing.T) {
+	m := &AuthMiddleware{}
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{services.ErrSessionExpired, "SESSION_EXPIRED"},
+		{services.ErrSessionNotFound, "SESSION_NOT_FOUND"},
+		{services.ErrSessionSecurityViolation, "SECURITY_VIOLATION"},
+		{services.ErrSessionLimitExceeded, "SESSION_LIMIT_EXCEEDED"},
+		{services.ErrSessionIDAmbiguous, "SESSION_ID_AMBIGUOUS"},
+		{services.ErrDeviceKeyRequired, "DEVICE_KEY_REQUIRED"},
+		{services.ErrDeviceKeyInvalid, "DEVICE_KEY_INVALID"},
+		{services.ErrDeviceSignatureRequired, "DEVICE_SIGNATURE_REQUIRED"},
+		{services.ErrDeviceSignatureInvalid, "DEVICE_SIGNATURE_INVALID"},
+	}
+
+	seen := map[string]error{}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			assert.Equal(t, tt.code, m.getErrorCode(tt.err))
+			assert.Equal(t, tt.code, m.getErrorCode(fmt.Errorf("validate session: %w", tt.err)), "wrapped errors keep their code")
+		})
+		if previous, ok := seen[tt.code]; ok {
+			t.Errorf("%q and %q share the code %s", previous, tt.err, tt.code)
+		}
+		seen[tt.code] = tt.err
+	}
+
+	assert.Equal(t, "INVALID_SESSION", m.getErrorCode(errors.New("something else")))
+	assert.NotContains(t, seen, "INVALID_SESSION")
+}
@@ -1,24 +1,26 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 
 	"github.com/fntelecomllc/studio/backend/internal/config"
 	"github.com/fntelecomllc/studio/backend/internal/logging"
 	"github.com/fntelecomllc/studio/backend/internal/models"
 	"github.com/fntelecomllc/studio/backend/internal/services"
+	"github.com/fntelecomllc/studio/backend/internal/store"
 )
 
 // AuthMiddleware provides authentication middleware
 type AuthMiddleware struct {
 	sessionService *services.SessionService
 	config         *config.SessionSettings
+	apiKeys        store.APIKeyStore // Enables bearer API keys in DualAuth
 }
 
 // NewAuthMiddleware creates a new authentication middleware
@@ -29,11 +31,17 @@ func NewAuthMiddleware(sessionService *services.SessionService, sessionConfig *c
 	}
 }
 
+// SetAPIKeyStore sets the store DualAuth looks bearer API keys up in. Without one every API key is
+// rejected.
+func (m *AuthMiddleware) SetAPIKeyStore(apiKeys store.APIKeyStore) {
+	m.apiKeys = apiKeys
+}
+
 // SessionAuth validates session-based authentication
 func (m *AuthMiddleware) SessionAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		startTime := time.Now()
-		requestID := uuid.New().String()
+		requestID := requestID(c)
 		ipAddress := getClientIP(c)
 		userAgent := c.GetHeader("User-Agent")
 
@@ -205,7 +213,7 @@ func (m *AuthMiddleware) SessionAuth() gin.HandlerFunc {
 
 		// Validate session using the session service
 		validationStart := time.Now()
-		sessionData, err := m.sessionService.ValidateSession(sessionID, ipAddress)
+		sessionData, riskScore, err := m.sessionService.ValidateSessionWithRisk(sessionID, ipAddress, userAgent)
 		validationDuration := time.Since(validationStart)
 		
 		// Create security context from session data
@@ -218,7 +226,7 @@ func (m *AuthMiddleware) SessionAuth() gin.HandlerFunc {
 				Roles:                  sessionData.Roles,
 				SessionExpiry:          sessionData.ExpiresAt,
 				RequiresPasswordChange: sessionData.RequiresPasswordChange,
-				RiskScore:              0, // Default risk score
+				RiskScore:              riskScore,
 			}
 		}
 
@@ -246,7 +254,6 @@ func (m *AuthMiddleware) SessionAuth() gin.HandlerFunc {
 			// Determine error type and risk score
 			var statusCode int
 			var errorMsg string
-			var riskScore int
 			var threatLevel string
 
 			switch err {
@@ -258,8 +265,10 @@ func (m *AuthMiddleware) SessionAuth() gin.HandlerFunc {
 			case services.ErrSessionSecurityViolation:
 				statusCode = http.StatusForbidden
 				errorMsg = "Security violation detected"
-				riskScore = 6
-				threatLevel = "medium"
+				if riskScore < 6 {
+					riskScore = 6
+				}
+				threatLevel = services.RiskBand(riskScore)
 			default:
 				statusCode = http.StatusInternalServerError
 				errorMsg = "Authentication failed"
@@ -316,6 +325,32 @@ func (m *AuthMiddleware) SessionAuth() gin.HandlerFunc {
 			return
 		}
 
+		// Device-bound sessions must prove possession of the device key on every request
+		if err := m.verifyDeviceProof(c, sessionData); err != nil {
+			logging.LogSecurityEvent(
+				"device_signature_rejected",
+				&securityContext.UserID,
+				&sessionID,
+				ipAddress,
+				userAgent,
+				&logging.SecurityMetrics{
+					RiskScore:          7,
+					ThreatLevel:        "high",
+					SuspiciousActivity: true,
+				},
+				map[string]interface{}{
+					"error_type": err.Error(),
+					"path":       c.Request.URL.Path,
+					"method":     c.Request.Method,
+				},
+			)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Device signature verification failed",
+				"code":  m.getErrorCode(err),
+			})
+			return
+		}
+
 		// Log successful session validation
 		logging.LogSessionEvent(
 			"session_validation_success",
@@ -368,8 +403,11 @@ func (m *AuthMiddleware) SessionAuth() gin.HandlerFunc {
 	}
 }
 
-// DualAuth supports both API key and session authentication
-func (m *AuthMiddleware) DualAuth(apiKey string) gin.HandlerFunc {
+// DualAuth authenticates a request by API key when it carries an "Authorization: Bearer <key>"
+// header, and otherwise by session exactly like SessionAuth. The key is looked up by its hash; the
+// request is granted the key's scopes as permissions on behalf of the key's owner, with no roles.
+func (m *AuthMiddleware) DualAuth() gin.HandlerFunc {
+	sessionAuth := m.SessionAuth()
 	return func(c *gin.Context) {
 		// Skip OPTIONS requests
 		if c.Request.Method == http.MethodOptions {
@@ -379,74 +417,64 @@ func (m *AuthMiddleware) DualAuth(apiKey string) gin.HandlerFunc {
 
 		// Check for API key first
 		authHeader := c.GetHeader("Authorization")
-		if authHeader != "" {
-			parts := strings.Split(authHeader, " ")
-			if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
-				if parts[1] == apiKey {
-					// Valid API key - set a basic context
-					c.Set("auth_type", "api_key")
-					c.Next()
-					return
-				} else {
-					c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-						"error": "Invalid API key",
-					})
-					return
-				}
-			}
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+			// Fall back to session authentication
+			sessionAuth(c)
+			return
 		}
 
-		// Fall back to session authentication
-		sessionID, err := c.Cookie(m.config.CookieName)
+		apiKey, err := m.lookupAPIKey(c, parts[1])
 		if err != nil {
-			// Try legacy cookie name
-			sessionID, err = c.Cookie(config.LegacySessionCookieName)
-			if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
 				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-					"error": "Authentication required",
-					"code":  "AUTH_REQUIRED",
+					"error": "Invalid API key",
+					"code":  "INVALID_API_KEY",
 				})
 				return
 			}
-		}
-
-		// Get client IP
-		ipAddress := getClientIP(c)
-
-		// Validate session
-		sessionData, err := m.sessionService.ValidateSession(sessionID, ipAddress)
-		if err != nil {
-			// Clear invalid session cookies
-			m.clearSessionCookies(c)
-
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 				"error": "Authentication failed",
-				"code":  m.getErrorCode(err),
+				"code":  "AUTH_FAILED",
 			})
 			return
 		}
 
-		// Create security context from session data
 		securityContext := &models.SecurityContext{
-			UserID:                 sessionData.UserID,
-			SessionID:              sessionData.ID,
-			Permissions:            sessionData.Permissions,
-			Roles:                  sessionData.Roles,
-			SessionExpiry:          sessionData.ExpiresAt,
-			RequiresPasswordChange: sessionData.RequiresPasswordChange,
-			RiskScore:              0, // Default risk score
+			UserID:      apiKey.UserID,
+			Permissions: apiKey.Scopes,
+			Roles:       []string{},
+		}
+		if apiKey.ExpiresAt != nil {
+			securityContext.SessionExpiry = *apiKey.ExpiresAt
 		}
 
 		// Store security context for use in handlers
-		c.Set("auth_type", "session")
+		c.Set("auth_type", "api_key")
+		c.Set("api_key_id", apiKey.ID)
 		c.Set("security_context", securityContext)
 		c.Set("user_id", securityContext.UserID)
-		c.Set("session_id", securityContext.SessionID)
 
 		c.Next()
 	}
 }
 
+// lookupAPIKey returns the usable key stored under the hash of token. Unknown, revoked and expired
+// keys, and any key when no key store is set, are reported as store.ErrNotFound.
+func (m *AuthMiddleware) lookupAPIKey(c *gin.Context, token string) (*models.APIKey, error) {
+	if m.apiKeys == nil || token == "" {
+		return nil, store.ErrNotFound
+	}
+	apiKey, err := m.apiKeys.GetAPIKeyByHash(c.Request.Context(), nil, services.HashAPIKey(token))
+	if err != nil {
+		return nil, err
+	}
+	if !apiKey.IsUsable(time.Now().UTC()) {
+		return nil, store.ErrNotFound
+	}
+	return apiKey, nil
+}
+
 // RequirePermission checks if the user has a specific permission
 func (m *AuthMiddleware) RequirePermission(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -616,17 +644,46 @@ func (m *AuthMiddleware) clearSessionCookies(c *gin.Context) {
 	c.SetCookie(config.AuthTokensCookieName, "", -1, config.CookiePath, "", config.CookieSecure, false)
 }
 
-// getErrorCode returns appropriate error code based on the error type
+// Request headers carrying the device signature for device-bound sessions
+const (
+	DeviceTimestampHeader = "X-Device-Timestamp"
+	DeviceNonceHeader     = "X-Device-Nonce"
+	DeviceSignatureHeader = "X-Device-Signature"
+)
+
+// verifyDeviceProof checks the request's device signature against the session's bound device key
+func (m *AuthMiddleware) verifyDeviceProof(c *gin.Context, session *services.SessionData) error {
+	return m.sessionService.VerifyDeviceProof(session, services.DeviceProof{
+		Timestamp: c.GetHeader(DeviceTimestampHeader),
+		Nonce:     c.GetHeader(DeviceNonceHeader),
+		Signature: c.GetHeader(DeviceSignatureHeader),
+		Method:    c.Request.Method,
+		Path:      c.Request.URL.RequestURI(),
+	})
+}
+
+// getErrorCode returns appropriate error code based on the error type. Each session error has a
+// code of its own so the frontend can tell, say, an expired session from a revoked one.
 func (m *AuthMiddleware) getErrorCode(err error) string {
-	switch err {
-	case services.ErrSessionExpired:
+	switch {
+	case errors.Is(err, services.ErrDeviceSignatureRequired):
+		return "DEVICE_SIGNATURE_REQUIRED"
+	case errors.Is(err, services.ErrDeviceSignatureInvalid):
+		return "DEVICE_SIGNATURE_INVALID"
+	case errors.Is(err, services.ErrDeviceKeyRequired):
+		return "DEVICE_KEY_REQUIRED"
+	case errors.Is(err, services.ErrDeviceKeyInvalid):
+		return "DEVICE_KEY_INVALID"
+	case errors.Is(err, services.ErrSessionExpired):
 		return "SESSION_EXPIRED"
-	case services.ErrSessionNotFound:
+	case errors.Is(err, services.ErrSessionNotFound):
 		return "SESSION_NOT_FOUND"
-	case services.ErrSessionSecurityViolation:
-		return "SECURITY_VIOLATION"
-	case services.ErrSessionSecurityViolation:
+	case errors.Is(err, services.ErrSessionSecurityViolation):
 		return "SECURITY_VIOLATION"
+	case errors.Is(err, services.ErrSessionLimitExceeded):
+		return "SESSION_LIMIT_EXCEEDED"
+	case errors.Is(err, services.ErrSessionIDAmbiguous):
+		return "SESSION_ID_AMBIGUOUS"
 	default:
 		return "INVALID_SESSION"
 	}
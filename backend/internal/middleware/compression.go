@@ -0,0 +1,244 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// Content codings the compression middleware produces
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+// compressor is a gzip or zlib writer that can be flushed and reused
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// Compression compresses responses with gzip or deflate, as negotiated from Accept-Encoding. A
+// response is compressed once its body reaches cfg.MinSizeBytes, or when its handler flushes it
+// first as a stream, provided its type is compressible: JSON, CSV and other text, but not event
+// streams, which must reach the client unbuffered, nor responses that already have an encoding.
+func Compression(cfg config.CompressionConfig) gin.HandlerFunc {
+	minSize, level := cfg.MinSizeBytes, cfg.Level
+	if minSize <= 0 {
+		minSize = config.DefaultCompressionMinSizeBytes
+	}
+	if level < gzip.BestSpeed || level > gzip.BestCompression {
+		level = config.DefaultCompressionLevel
+	}
+	pools := map[string]*sync.Pool{
+		encodingGzip: {New: func() interface{} {
+			w, _ := gzip.NewWriterLevel(io.Discard, level)
+			return w
+		}},
+		encodingDeflate: {New: func() interface{} {
+			w, _ := zlib.NewWriterLevel(io.Discard, level)
+			return w
+		}},
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		w := &compressWriter{ResponseWriter: c.Writer, encoding: encoding, minSize: minSize, pool: pools[encoding]}
+		c.Writer = w
+		defer func() {
+			w.finish()
+			c.Writer = w.ResponseWriter
+		}()
+		c.Next()
+	}
+}
+
+// negotiateEncoding returns the coding of Accept-Encoding to compress with, preferring gzip, or ""
+// when the client accepts neither gzip nor deflate. As RFC 9110 requires, "*" only covers the
+// codings the header does not list, so it cannot override an explicit q=0.
+func negotiateEncoding(acceptEncoding string) string {
+	listed := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			}
+		}
+		listed[coding] = q
+	}
+	for _, encoding := range []string{encodingGzip, encodingDeflate} {
+		q, ok := listed[encoding]
+		if !ok {
+			q = listed["*"]
+		}
+		if q > 0 {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// compressibleContentType reports whether responses of contentType are worth compressing
+func compressibleContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case mediaType == "text/event-stream":
+		return false
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	case mediaType == "application/json", strings.HasSuffix(mediaType, "+json"),
+		mediaType == "application/xml", mediaType == "application/javascript":
+		return true
+	}
+	return false
+}
+
+// compressWriter holds back the start of a response body until it knows whether to compress it:
+// once the body reaches minSize, the handler flushes, or the handler returns.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minSize  int
+	pool     *sync.Pool
+
+	buf        []byte
+	decided    bool
+	compressor compressor // Set once the response is being compressed
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.buf = append(w.buf, data...)
+		compress := w.compressible()
+		if compress && len(w.buf) < w.minSize {
+			return len(data), nil
+		}
+		if err := w.start(compress); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	if w.compressor != nil {
+		return w.compressor.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow sends the headers of a response that has no body yet, which is not compressed
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		_ = w.start(false)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush sends what has been written so far. A compressible response flushed before reaching
+// minSize is a stream and is compressed from then on.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		if err := w.start(w.compressible()); err != nil {
+			return
+		}
+	}
+	if w.compressor != nil {
+		_ = w.compressor.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// compressible reports whether the response may be compressed, judged from its headers and, when
+// it has no Content-Type, from the buffered start of its body
+func (w *compressWriter) compressible() bool {
+	switch w.ResponseWriter.Status() {
+	case http.StatusNoContent, http.StatusNotModified:
+		return false
+	}
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		if len(w.buf) == 0 {
+			return false
+		}
+		contentType = http.DetectContentType(w.buf)
+		header.Set("Content-Type", contentType)
+	}
+	return compressibleContentType(contentType)
+}
+
+// start settles whether to compress the response and writes anything buffered
+func (w *compressWriter) start(compress bool) error {
+	w.decided = true
+	buffered := w.buf
+	w.buf = nil
+	if compress {
+		header := w.Header()
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		// The compressed body is not the one a strong validator names byte for byte
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+		w.compressor = w.pool.Get().(compressor)
+		w.compressor.Reset(w.ResponseWriter)
+		if len(buffered) > 0 {
+			_, err := w.compressor.Write(buffered)
+			return err
+		}
+		return nil
+	}
+	if len(buffered) > 0 {
+		_, err := w.ResponseWriter.Write(buffered)
+		return err
+	}
+	return nil
+}
+
+// finish writes a response that stayed below minSize uncompressed, or completes a compressed one
+func (w *compressWriter) finish() {
+	if !w.decided {
+		// Short bodies are sent as they are
+		_ = w.start(false)
+		return
+	}
+	if w.compressor != nil {
+		_ = w.compressor.Close()
+		w.compressor.Reset(io.Discard)
+		w.pool.Put(w.compressor)
+		w.compressor = nil
+	}
+}
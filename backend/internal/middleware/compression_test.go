@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCompressionRouter(register func(router *gin.Engine)) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Compression(config.CompressionConfig{MinSizeBytes: 1024, Level: 6}))
+	register(router)
+	return router
+}
+
+func getWithEncoding(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCompression_CompressesLargeJSONLists(t *testing.T) {
+	items := make([]gin.H, 200)
+	for i := range items {
+		items[i] = gin.H{"id": i, "domain": fmt.Sprintf("example-%d.com", i), "status": "resolved"}
+	}
+	router := newCompressionRouter(func(router *gin.Engine) {
+		router.GET("/results", func(c *gin.Context) { c.JSON(http.StatusOK, items) })
+		router.GET("/small", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) })
+	})
+
+	w := getWithEncoding(router, "/results", "br;q=1.0, gzip;q=0.8")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+	assert.Empty(t, w.Header().Get("Content-Length"))
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	var decoded []map[string]interface{}
+	require.NoError(t, json.NewDecoder(reader).Decode(&decoded))
+	assert.Len(t, decoded, len(items))
+
+	// Deflate is used when gzip is not accepted
+	w = getWithEncoding(router, "/results", "gzip;q=0, deflate")
+	assert.Equal(t, "deflate", w.Header().Get("Content-Encoding"))
+	zreader, err := zlib.NewReader(w.Body)
+	require.NoError(t, err)
+	decoded = nil
+	require.NoError(t, json.NewDecoder(zreader).Decode(&decoded))
+	assert.Len(t, decoded, len(items))
+
+	// Small responses, and clients that accept no compression, get the plain body
+	w = getWithEncoding(router, "/small", "gzip")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.JSONEq(t, `{"ok":true}`, w.Body.String())
+	w = getWithEncoding(router, "/results", "")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), `[{"domain":"example-0.com"`))
+}
+
+func TestCompression_NeverBuffersEventStreams(t *testing.T) {
+	var flushedBeforeEnd bool
+	var w *httptest.ResponseRecorder
+	router := newCompressionRouter(func(router *gin.Engine) {
+		router.GET("/stream", func(c *gin.Context) {
+			c.Header("Content-Type", "text/event-stream")
+			c.SSEvent("progress", gin.H{"processed": 1})
+			c.Writer.Flush()
+			flushedBeforeEnd = strings.Contains(w.Body.String(), "event:progress")
+			c.SSEvent("done", strings.Repeat("x", 4096))
+		})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.True(t, flushedBeforeEnd, "the first event reaches the client before the handler returns")
+	assert.True(t, w.Flushed)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Contains(t, w.Body.String(), "event:done")
+}
+
+func TestCompression_CompressesFlushedCSVStreams(t *testing.T) {
+	router := newCompressionRouter(func(router *gin.Engine) {
+		router.GET("/export", func(c *gin.Context) {
+			c.Header("Content-Type", "text/csv; charset=utf-8")
+			c.Status(http.StatusOK)
+			for batch := 0; batch < 3; batch++ {
+				fmt.Fprintf(c.Writer, "domain,status\nbatch-%d.example.com,resolved\n", batch)
+				c.Writer.Flush()
+			}
+		})
+	})
+
+	w := getWithEncoding(router, "/export", "gzip")
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(body), "domain,status\n"))
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := map[string]string{
+		"":                         "",
+		"gzip":                     "gzip",
+		"deflate, gzip":            "gzip",
+		"GZIP;q=0.5":               "gzip",
+		"gzip;q=0, deflate":        "deflate",
+		"identity":                 "",
+		"*":                        "gzip",
+		"br, deflate;q=0":          "",
+		" deflate ; q=0.1 , br":    "deflate",
+		"gzip;q=0, *":              "deflate",
+		"*, gzip;q=0":              "deflate",
+		"*;q=0":                    "",
+		"*;q=0, deflate":           "deflate",
+		"gzip;q=0, deflate;q=0, *": "",
+	}
+	for header, want := range tests {
+		assert.Equal(t, want, negotiateEncoding(header), header)
+	}
+}
+
+func TestCompression_WeakensTheETagOfCompressedResponses(t *testing.T) {
+	body := strings.Repeat(`{"domain":"example.com"},`, 100)
+	router := newCompressionRouter(func(router *gin.Engine) {
+		router.GET("/strong", func(c *gin.Context) {
+			c.Header("ETag", `"abc"`)
+			c.Data(http.StatusOK, "application/json", []byte(body))
+		})
+		router.GET("/weak", func(c *gin.Context) {
+			c.Header("ETag", `W/"abc"`)
+			c.Data(http.StatusOK, "application/json", []byte(body))
+		})
+	})
+
+	w := getWithEncoding(router, "/strong", "gzip")
+	require.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, `W/"abc"`, w.Header().Get("ETag"))
+	w = getWithEncoding(router, "/strong", "identity")
+	require.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, `"abc"`, w.Header().Get("ETag"), "the identity body keeps its strong validator")
+	w = getWithEncoding(router, "/weak", "gzip")
+	assert.Equal(t, `W/"abc"`, w.Header().Get("ETag"))
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsAccess admits scrapes of the metrics endpoint from clients connecting from one of
+// allowedNetworks (CIDRs), or from anywhere with "Authorization: Bearer <token>" when token is
+// set. The connecting address is used rather than forwarding headers, which clients can forge.
+func MetricsAccess(token string, allowedNetworks []string) (gin.HandlerFunc, error) {
+	networks := make([]*net.IPNet, 0, len(allowedNetworks))
+	for _, cidr := range allowedNetworks {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid metrics network %q: %w", cidr, err)
+		}
+		networks = append(networks, network)
+	}
+
+	return func(c *gin.Context) {
+		if token != "" {
+			if bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok &&
+				subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1 {
+				c.Next()
+				return
+			}
+		}
+		if ip := net.ParseIP(c.RemoteIP()); ip != nil {
+			for _, network := range networks {
+				if network.Contains(ip) {
+					c.Next()
+					return
+				}
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error": "Metrics are only served to internal networks or with the metrics token",
+			"code":  "METRICS_FORBIDDEN",
+		})
+	}, nil
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsAccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	access, err := MetricsAccess("scrape-token", []string{"10.0.0.0/8", " ::1/128"})
+	require.NoError(t, err)
+	router := gin.New()
+	router.GET("/metrics", access, func(c *gin.Context) { c.String(http.StatusOK, "metrics") })
+
+	scrape := func(remoteAddr, authorization string, headers ...string) int {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.RemoteAddr = remoteAddr
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, scrape("10.1.2.3:40000", ""))
+	assert.Equal(t, http.StatusOK, scrape("[::1]:40000", ""))
+	assert.Equal(t, http.StatusOK, scrape("203.0.113.7:40000", "Bearer scrape-token"))
+	assert.Equal(t, http.StatusForbidden, scrape("203.0.113.7:40000", ""))
+	assert.Equal(t, http.StatusForbidden, scrape("203.0.113.7:40000", "Bearer wrong-token"))
+	assert.Equal(t, http.StatusForbidden, scrape("203.0.113.7:40000", "", "X-Forwarded-For", "10.1.2.3"),
+		"forwarding headers do not make a client internal")
+
+	_, err = MetricsAccess("", []string{"not-a-network"})
+	assert.Error(t, err)
+}
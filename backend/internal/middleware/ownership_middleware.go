@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+)
+
+// OwnerFilterContextKey is the gin context key under which ScopeToOwner stores the owner filter
+const OwnerFilterContextKey = "owner_filter"
+
+// ownerScopeAdminRoles are roles that always see every resource regardless of owner
+var ownerScopeAdminRoles = []string{"super_admin", "admin"}
+
+// OwnerFilter restricts list/get operations to resources owned by a single user
+type OwnerFilter struct {
+	ResourceType string
+	UserID       uuid.UUID
+}
+
+// ScopeToOwner injects an owner filter for users lacking admin rights on the resource type.
+// Admins are users holding an admin role or the "<resourceType>:admin" permission; they pass
+// through without a filter. Handlers read the filter with GetOwnerFilter and must honor it.
+func (m *AuthMiddleware) ScopeToOwner(resourceType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		securityContext, exists := c.Get("security_context")
+		if !exists {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Authentication required",
+			})
+			return
+		}
+
+		ctx, ok := securityContext.(*models.SecurityContext)
+		if !ok || ctx == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Authentication required",
+			})
+			return
+		}
+
+		if ctx.HasAnyRole(ownerScopeAdminRoles) || ctx.HasPermission(resourceType+":admin") {
+			c.Next()
+			return
+		}
+
+		c.Set(OwnerFilterContextKey, &OwnerFilter{
+			ResourceType: resourceType,
+			UserID:       ctx.UserID,
+		})
+		c.Next()
+	}
+}
+
+// GetOwnerFilter returns the owner filter set by ScopeToOwner, if any
+func GetOwnerFilter(c *gin.Context) (*OwnerFilter, bool) {
+	value, exists := c.Get(OwnerFilterContextKey)
+	if !exists {
+		return nil, false
+	}
+	filter, ok := value.(*OwnerFilter)
+	return filter, ok && filter != nil
+}
+
+// Owns reports whether the filter permits access to a resource owned by ownerID
+func (f *OwnerFilter) Owns(ownerID *uuid.UUID) bool {
+	if f == nil {
+		return true
+	}
+	return ownerID != nil && *ownerID == f.UserID
+}
@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 
 	"github.com/fntelecomllc/studio/backend/internal/logging"
 )
@@ -13,11 +12,13 @@ import (
 // RateLimitMiddleware provides rate limiting functionality
 type RateLimitMiddleware struct {
 	// Note: AuthService removed as it's not implemented yet
+	limiter RateLimiter // Token buckets of RouteRateLimit
+	now     func() time.Time
 }
 
-// NewRateLimitMiddleware creates a new rate limiting middleware
+// NewRateLimitMiddleware creates a new rate limiting middleware whose route limits are kept in memory
 func NewRateLimitMiddleware() *RateLimitMiddleware {
-	return &RateLimitMiddleware{}
+	return &RateLimitMiddleware{limiter: NewMemoryRateLimiter(), now: time.Now}
 }
 
 // RateLimitConfig defines rate limiting configuration
@@ -43,14 +44,7 @@ func (m *RateLimitMiddleware) UserRateLimit(maxRequests int, window time.Duratio
 	return m.RateLimit(RateLimitConfig{
 		MaxRequests: maxRequests,
 		Window:      window,
-		KeyFunc: func(c *gin.Context) string {
-			// Try to get user ID from context
-			if userID, exists := c.Get("user_id"); exists {
-				return userID.(string)
-			}
-			// Fall back to IP if no user context
-			return getClientIP(c)
-		},
+		KeyFunc:     rateLimitSubject, // The user ID, falling back to IP if no user context
 	})
 }
 
@@ -58,7 +52,7 @@ func (m *RateLimitMiddleware) UserRateLimit(maxRequests int, window time.Duratio
 func (m *RateLimitMiddleware) RateLimit(config RateLimitConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		startTime := time.Now()
-		requestID := uuid.New().String()
+		requestID := requestID(c)
 		ipAddress := getClientIP(c)
 		userAgent := c.GetHeader("User-Agent")
 
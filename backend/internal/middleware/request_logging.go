@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxRequestIDLength bounds the X-Request-ID a client may choose; longer IDs are replaced
+const maxRequestIDLength = 128
+
+// RequestLogging gives each request an ID and a logging.RequestLogger in its context, and logs the
+// request once it completes with its route, status, latency and the user it was authenticated as.
+// A client's X-Request-ID is used as the ID; either way the ID is returned in X-Request-ID.
+func RequestLogging() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.New().String()
+		}
+		c.Set("request_id", requestID)
+		c.Header("X-Request-ID", requestID)
+		logger := logging.NewRequestLogger(requestID, c.Request.Method, c.Request.URL.Path, c.ClientIP(), c.Request.UserAgent())
+		c.Request = c.Request.WithContext(logging.NewContext(c.Request.Context(), logger))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		details := map[string]interface{}{
+			"route":         c.FullPath(),
+			"status":        status,
+			"latencyMs":     float64(time.Since(start).Microseconds()) / 1000,
+			"responseBytes": c.Writer.Size(),
+		}
+		logger = RequestLogger(c)
+		if status >= http.StatusInternalServerError {
+			var err error
+			if last := c.Errors.Last(); last != nil {
+				err = last.Err
+			}
+			logger.Error(logging.CategoryHTTP, "http_request", err, details)
+			return
+		}
+		logger.Info(logging.CategoryHTTP, "http_request", details)
+	}
+}
+
+// RequestLogger returns the logger of the request, with the authenticated user once the auth
+// middleware has run. Requests that did not pass through RequestLogging get a logger without
+// request fields.
+func RequestLogger(c *gin.Context) *logging.RequestLogger {
+	logger := logging.FromContext(c.Request.Context())
+	if userID, ok := c.Get("user_id"); ok {
+		if id, ok := userID.(uuid.UUID); ok {
+			logger = logger.WithUser(id)
+		}
+	}
+	return logger
+}
+
+// requestID returns the ID RequestLogging gave the request, or a new one outside it
+func requestID(c *gin.Context) string {
+	if id := c.GetString("request_id"); id != "" {
+		return id
+	}
+	return uuid.New().String()
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureLogs redirects the global logger to a buffer at level for the rest of the test
+func captureLogs(t *testing.T, level logging.LogLevel) *bytes.Buffer {
+	var buf bytes.Buffer
+	previousLevel := logging.GlobalAuthLogger.Level()
+	logging.GlobalAuthLogger.SetOutput(&buf)
+	logging.SetLevel(level)
+	t.Cleanup(func() {
+		logging.GlobalAuthLogger.SetOutput(os.Stdout)
+		logging.SetLevel(previousLevel)
+	})
+	return &buf
+}
+
+// logEntries decodes the JSON lines written to buf
+func logEntries(t *testing.T, buf *bytes.Buffer) []logging.AuthLogEntry {
+	var entries []logging.AuthLogEntry
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry logging.AuthLogEntry
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), "log line is JSON: %s", line)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestRequestLogging_LogsTheRequestWithItsFields(t *testing.T) {
+	buf := captureLogs(t, logging.LogLevelInfo)
+	userID := uuid.New()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestLogging())
+	router.GET("/api/v2/campaigns/:campaignId", func(c *gin.Context) {
+		c.Set("user_id", userID)
+		RequestLogger(c).Debug(logging.CategoryAuth, "below_the_level", nil)
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/campaigns/7d9f", nil)
+	req.Header.Set("X-Request-ID", "client-chosen-id")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "client-chosen-id", w.Header().Get("X-Request-ID"))
+
+	entries := logEntries(t, buf)
+	require.Len(t, entries, 1, "debug entries are not written at INFO")
+	entry := entries[0]
+	assert.Equal(t, logging.LogLevelInfo, entry.Level)
+	assert.Equal(t, logging.CategoryHTTP, entry.Category)
+	assert.Equal(t, "http_request", entry.Operation)
+	assert.Equal(t, "client-chosen-id", entry.RequestID)
+	require.NotNil(t, entry.UserID)
+	assert.Equal(t, userID, *entry.UserID)
+	assert.Equal(t, "/api/v2/campaigns/:campaignId", entry.Details["route"])
+	assert.Equal(t, "/api/v2/campaigns/7d9f", entry.Details["path"])
+	assert.Equal(t, float64(http.StatusOK), entry.Details["status"])
+	assert.Contains(t, entry.Details, "latencyMs")
+}
+
+func TestRequestLogging_GeneratesRequestIDsAndLogsServerErrors(t *testing.T) {
+	buf := captureLogs(t, logging.LogLevelDebug)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestLogging())
+	router.GET("/fail", func(c *gin.Context) {
+		RequestLogger(c).Debug(logging.CategoryAuth, "handler_debug", nil)
+		c.Status(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+	req.Header.Set("X-Request-ID", strings.Repeat("x", maxRequestIDLength+1))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	requestID := w.Header().Get("X-Request-ID")
+	_, err := uuid.Parse(requestID)
+	require.NoError(t, err, "an overlong client ID is replaced")
+
+	entries := logEntries(t, buf)
+	require.Len(t, entries, 2)
+	assert.Equal(t, logging.LogLevelDebug, entries[0].Level)
+	assert.Equal(t, requestID, entries[0].RequestID, "handler entries carry the request ID")
+	assert.Equal(t, logging.LogLevelError, entries[1].Level)
+	assert.Equal(t, requestID, entries[1].RequestID)
+}
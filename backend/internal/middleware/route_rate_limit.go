@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisRateLimitKeyPrefix namespaces the keys of RedisRateLimiter
+const DefaultRedisRateLimitKeyPrefix = "domainflow:"
+
+// defaultMaxRateLimitBuckets is the number of buckets MemoryRateLimiter holds before it drops those
+// that have refilled
+const defaultMaxRateLimitBuckets = 100000
+
+// Rate is a token bucket: Burst requests may be made at once, and one more is allowed every Interval
+type Rate struct {
+	Burst    int
+	Interval time.Duration
+}
+
+// PerMinute is a rate of requestsPerMinute with bursts of up to burst requests
+func PerMinute(requestsPerMinute, burst int) Rate {
+	if requestsPerMinute <= 0 {
+		requestsPerMinute = 1
+	}
+	if burst <= 0 {
+		burst = requestsPerMinute
+	}
+	return Rate{Burst: burst, Interval: time.Minute / time.Duration(requestsPerMinute)}
+}
+
+// RateLimiter keeps the token buckets of RouteRateLimit
+type RateLimiter interface {
+	// Take removes a token from the bucket at key. When the bucket is empty it returns false and how
+	// long until a token is available.
+	Take(ctx context.Context, key string, rate Rate, now time.Time) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// tokenBucket is the state of one bucket of MemoryRateLimiter
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+	rate    Rate
+}
+
+// refill adds the tokens earned since the bucket was last updated
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = math.Min(float64(b.rate.Burst), b.tokens+float64(elapsed)/float64(b.rate.Interval))
+		b.updated = now
+	}
+}
+
+// MemoryRateLimiter keeps token buckets in the memory of one instance
+type MemoryRateLimiter struct {
+	mu         sync.Mutex
+	buckets    map[string]*tokenBucket
+	maxBuckets int
+}
+
+var _ RateLimiter = (*MemoryRateLimiter)(nil)
+
+// NewMemoryRateLimiter creates an empty in-memory limiter
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{buckets: make(map[string]*tokenBucket), maxBuckets: defaultMaxRateLimitBuckets}
+}
+
+// Take implements RateLimiter
+func (l *MemoryRateLimiter) Take(ctx context.Context, key string, rate Rate, now time.Time) (bool, time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= l.maxBuckets {
+			l.dropFullBuckets(now)
+		}
+		bucket = &tokenBucket{tokens: float64(rate.Burst), updated: now}
+		l.buckets[key] = bucket
+	}
+	bucket.rate = rate
+	bucket.refill(now)
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0, nil
+	}
+	return false, time.Duration((1 - bucket.tokens) * float64(rate.Interval)), nil
+}
+
+// dropFullBuckets forgets the buckets that have refilled, which are the same as new ones
+func (l *MemoryRateLimiter) dropFullBuckets(now time.Time) {
+	for key, bucket := range l.buckets {
+		bucket.refill(now)
+		if bucket.tokens >= float64(bucket.rate.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// takeTokenScript refills and takes from the bucket at KEYS[1] atomically. ARGV holds the burst,
+// the interval and the current time in microseconds. It returns 1 or 0 for whether a token was
+// taken and, when none was, the microseconds until one is available.
+var takeTokenScript = redis.NewScript(`
+local burst = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1]) or burst
+local updated = tonumber(state[2]) or now
+if now > updated then
+	tokens = math.min(burst, tokens + (now - updated) / interval)
+	updated = now
+end
+local allowed, wait = 0, 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) * interval)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', tostring(updated))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * interval / 1000) + 1000)
+return {allowed, wait}
+`)
+
+// RedisRateLimiter keeps token buckets in Redis so that instances sharing it share their limits.
+// Each bucket is a hash under <prefix>ratelimit:<key> that expires once it would have refilled.
+type RedisRateLimiter struct {
+	client    redis.UniversalClient
+	keyPrefix string
+}
+
+var _ RateLimiter = (*RedisRateLimiter)(nil)
+
+// NewRedisRateLimiter creates a limiter on client. An empty keyPrefix uses DefaultRedisRateLimitKeyPrefix.
+func NewRedisRateLimiter(client redis.UniversalClient, keyPrefix string) *RedisRateLimiter {
+	if keyPrefix == "" {
+		keyPrefix = DefaultRedisRateLimitKeyPrefix
+	}
+	return &RedisRateLimiter{client: client, keyPrefix: keyPrefix}
+}
+
+// Take implements RateLimiter
+func (l *RedisRateLimiter) Take(ctx context.Context, key string, rate Rate, now time.Time) (bool, time.Duration, error) {
+	result, err := takeTokenScript.Run(ctx, l.client, []string{l.keyPrefix + "ratelimit:" + key},
+		rate.Burst, rate.Interval.Microseconds(), now.UnixMicro()).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to take a rate limit token: %w", err)
+	}
+	if len(result) != 2 {
+		return false, 0, fmt.Errorf("unexpected rate limit script result %v", result)
+	}
+	return result[0] == 1, time.Duration(result[1]) * time.Microsecond, nil
+}
+
+// NewRateLimiterFromConfig returns the limiter chosen by cfg.Store: a RedisRateLimiter for redis, or
+// a MemoryRateLimiter for memory. An unreachable Redis is only logged; requests are let through
+// while it is down.
+func NewRateLimiterFromConfig(cfg config.RouteRateLimitConfig) (RateLimiter, error) {
+	switch cfg.Store {
+	case "", config.RateLimitStoreMemory:
+		return NewMemoryRateLimiter(), nil
+	case config.RateLimitStoreRedis:
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", cfg.Store)
+	}
+	if cfg.RedisURL == "" {
+		return nil, fmt.Errorf("the redis rate limit store needs a Redis URL")
+	}
+	options, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate limit Redis URL: %w", err)
+	}
+	client := redis.NewClient(options)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("RateLimitMiddleware: Redis rate limit store at %s is not reachable yet: %v", options.Addr, err)
+	}
+	return NewRedisRateLimiter(client, cfg.RedisKeyPrefix), nil
+}
+
+// SetLimiter replaces the in-memory limiter of RouteRateLimit, e.g. with a RedisRateLimiter
+func (m *RateLimitMiddleware) SetLimiter(limiter RateLimiter) {
+	m.limiter = limiter
+}
+
+// RouteRateLimit limits the requests of each user to a route to rate, falling back to the client
+// IP for requests without a user, so it belongs after the auth middleware. Requests beyond the
+// rate get 429 with a Retry-After header. A limiter that fails lets requests through.
+func (m *RateLimitMiddleware) RouteRateLimit(route string, rate Rate) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subject := rateLimitSubject(c)
+		allowed, retryAfter, err := m.limiter.Take(c.Request.Context(), route+":"+subject, rate, m.now())
+		if err != nil {
+			RequestLogger(c).Error(logging.CategoryRateLimit, "route_rate_limit", err, map[string]interface{}{"route": route})
+			c.Next()
+			return
+		}
+		if allowed {
+			c.Next()
+			return
+		}
+
+		retryAfterSeconds := int(math.Ceil(retryAfter.Seconds()))
+		if retryAfterSeconds < 1 {
+			retryAfterSeconds = 1
+		}
+		RequestLogger(c).Warn(logging.CategoryRateLimit, "route_rate_limited", map[string]interface{}{
+			"route":      route,
+			"subject":    subject,
+			"retryAfter": retryAfterSeconds,
+		})
+		c.Header("Retry-After", strconv.Itoa(retryAfterSeconds))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"error":      "Too many requests, retry later",
+			"code":       "RATE_LIMIT_EXCEEDED",
+			"retryAfter": retryAfterSeconds,
+		})
+	}
+}
+
+// rateLimitSubject is the user a request is limited as, or its client IP when it has none
+func rateLimitSubject(c *gin.Context) string {
+	if userID, ok := c.Get("user_id"); ok {
+		if id, ok := userID.(uuid.UUID); ok {
+			return "user:" + id.String()
+		}
+	}
+	return "ip:" + getClientIP(c)
+}
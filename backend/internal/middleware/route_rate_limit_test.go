@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRouteRateLimitRouter serves POST /campaigns limited to rate, as the user in the X-Test-User header
+func newRouteRateLimitRouter(m *RateLimitMiddleware, rate Rate) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID, err := uuid.Parse(c.GetHeader("X-Test-User")); err == nil {
+			c.Set("user_id", userID)
+		}
+	})
+	router.POST("/campaigns", m.RouteRateLimit("campaign_create", rate), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	return router
+}
+
+func postAs(router *gin.Engine, userID, ipAddress string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/campaigns", nil)
+	req.RemoteAddr = ipAddress + ":41000"
+	if userID != "" {
+		req.Header.Set("X-Test-User", userID)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRouteRateLimit_LimitsEachUserAndRefills(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	m := NewRateLimitMiddleware()
+	m.now = func() time.Time { return now }
+	router := newRouteRateLimitRouter(m, PerMinute(2, 2))
+	alice, bob := uuid.New().String(), uuid.New().String()
+
+	require.Equal(t, http.StatusCreated, postAs(router, alice, "203.0.113.7").Code)
+	require.Equal(t, http.StatusCreated, postAs(router, alice, "198.51.100.1").Code)
+	w := postAs(router, alice, "192.0.2.1")
+	require.Equal(t, http.StatusTooManyRequests, w.Code, "changing address does not reset a user's bucket")
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error":"Too many requests, retry later","code":"RATE_LIMIT_EXCEEDED","retryAfter":30}`, w.Body.String())
+
+	// Other users behind the same address have their own buckets
+	assert.Equal(t, http.StatusCreated, postAs(router, bob, "203.0.113.7").Code)
+
+	// A token comes back every 30 seconds
+	now = now.Add(29 * time.Second)
+	assert.Equal(t, "1", postAs(router, alice, "203.0.113.7").Header().Get("Retry-After"))
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusCreated, postAs(router, alice, "203.0.113.7").Code)
+	assert.Equal(t, http.StatusTooManyRequests, postAs(router, alice, "203.0.113.7").Code)
+}
+
+func TestRouteRateLimit_FallsBackToTheClientIP(t *testing.T) {
+	m := NewRateLimitMiddleware()
+	router := newRouteRateLimitRouter(m, PerMinute(1, 1))
+
+	require.Equal(t, http.StatusCreated, postAs(router, "", "203.0.113.7").Code)
+	assert.Equal(t, http.StatusTooManyRequests, postAs(router, "", "203.0.113.7").Code)
+	assert.Equal(t, http.StatusCreated, postAs(router, "", "198.51.100.1").Code)
+	assert.Equal(t, http.StatusCreated, postAs(router, uuid.New().String(), "203.0.113.7").Code)
+}
+
+// failingRateLimiter fails every Take
+type failingRateLimiter struct{}
+
+func (failingRateLimiter) Take(ctx context.Context, key string, rate Rate, now time.Time) (bool, time.Duration, error) {
+	return false, 0, errors.New("store unavailable")
+}
+
+func TestRouteRateLimit_LetsRequestsThroughWhenTheLimiterFails(t *testing.T) {
+	m := NewRateLimitMiddleware()
+	m.SetLimiter(failingRateLimiter{})
+	router := newRouteRateLimitRouter(m, PerMinute(1, 1))
+
+	assert.Equal(t, http.StatusCreated, postAs(router, "", "203.0.113.7").Code)
+	assert.Equal(t, http.StatusCreated, postAs(router, "", "203.0.113.7").Code)
+}
+
+func TestRedisRateLimiter_SharesBucketsBetweenInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	routers := make([]*gin.Engine, 2)
+	for i := range routers {
+		m := NewRateLimitMiddleware()
+		m.SetLimiter(NewRedisRateLimiter(client, ""))
+		m.now = func() time.Time { return now }
+		routers[i] = newRouteRateLimitRouter(m, PerMinute(6, 3))
+	}
+	user := uuid.New().String()
+
+	for i := 0; i < 3; i++ {
+		require.Equal(t, http.StatusCreated, postAs(routers[i%2], user, "203.0.113.7").Code, "request %d", i+1)
+	}
+	w := postAs(routers[1], user, "203.0.113.7")
+	require.Equal(t, http.StatusTooManyRequests, w.Code, "the burst is shared by both instances")
+	assert.Equal(t, "10", w.Header().Get("Retry-After"))
+	assert.True(t, server.Exists("domainflow:ratelimit:campaign_create:user:"+user))
+
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, http.StatusCreated, postAs(routers[0], user, "203.0.113.7").Code)
+	assert.Equal(t, http.StatusTooManyRequests, postAs(routers[1], user, "203.0.113.7").Code)
+}
+
+func TestMemoryRateLimiter_DropsRefilledBuckets(t *testing.T) {
+	limiter := NewMemoryRateLimiter()
+	limiter.maxBuckets = 2
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	rate := PerMinute(60, 1)
+
+	for _, key := range []string{"a", "b"} {
+		allowed, _, err := limiter.Take(context.Background(), key, rate, now)
+		require.NoError(t, err)
+		require.True(t, allowed)
+	}
+	allowed, _, err := limiter.Take(context.Background(), "c", rate, now.Add(time.Second))
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Len(t, limiter.buckets, 1, "the buckets of a and b had refilled")
+}
@@ -134,7 +134,8 @@ func (m *SecurityMiddleware) EnhancedCORS() gin.HandlerFunc {
 		}
 
 		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, Authorization, accept, origin, Cache-Control, If-None-Match, X-Requested-With, X-Device-Timestamp, X-Device-Nonce, X-Device-Signature")
+		c.Header("Access-Control-Expose-Headers", "ETag, X-Total-Count")
 		c.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE, PATCH")
 		c.Header("Access-Control-Max-Age", "86400") // 24 hours
 
@@ -11,6 +11,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+
+	"github.com/fntelecomllc/studio/backend/internal/logging"
 )
 
 // ValidateRequestMiddleware validates incoming request payloads
@@ -93,7 +95,7 @@ func ValidateResponseMiddleware() gin.HandlerFunc {
 			if err := json.Unmarshal(writer.body.Bytes(), &responseData); err == nil {
 				if err := validateCommonFields(responseData); err != nil {
 					// Log validation error but don't block response
-					fmt.Printf("Response validation warning: %s\n", err.Error())
+					RequestLogger(c).Warn(logging.CategoryMiddleware, "response_validation", map[string]interface{}{"warning": err.Error()})
 				}
 			}
 		}
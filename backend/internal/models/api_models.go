@@ -35,6 +35,7 @@ type LoginResponseAPI struct {
 	RequiresCaptcha bool     `json:"requires_captcha,omitempty" example:"false"`
 	SessionID       string   `json:"sessionId,omitempty" example:"sess_123456789"`
 	ExpiresAt       string   `json:"expiresAt,omitempty" example:"2025-06-19T14:00:00Z"`
+	SessionContext  *LoginSessionContext `json:"sessionContext,omitempty"`
 }
 
 // CampaignAPI represents a campaign in API responses
@@ -55,6 +56,7 @@ type CampaignAPI struct {
 	FailedItems             *int64                `json:"failedItems,omitempty" example:"55"`
 	ErrorMessage            *string               `json:"errorMessage,omitempty" example:"Network timeout error"`
 	Metadata                interface{}           `json:"metadata,omitempty" swaggertype:"object"`
+	Tags                    []string              `json:"tags,omitempty" example:"client-acme,staging"`
 	EstimatedCompletionAt   *time.Time            `json:"estimatedCompletionAt,omitempty"`
 	AvgProcessingRate       *float64              `json:"avgProcessingRate,omitempty" example:"10.5"`
 	LastHeartbeatAt         *time.Time            `json:"lastHeartbeatAt,omitempty"`
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // User represents a user in the authentication system
@@ -168,9 +169,11 @@ type RateLimit struct {
 // LoginRequest represents a login request
 type LoginRequest struct {
 	Email        string `json:"email" binding:"required,email"`
-	Password     string `json:"password" binding:"required,min=12"`
+	Password     string `json:"password" binding:"required"`
 	RememberMe   bool   `json:"rememberMe"`
 	CaptchaToken string `json:"captchaToken"`
+	// DevicePublicKey is a base64 SPKI public key (Ed25519 or ECDSA P-256) to bind the session to
+	DevicePublicKey string `json:"devicePublicKey,omitempty"`
 }
 
 // LoginResponse represents a login response
@@ -181,12 +184,40 @@ type LoginResponse struct {
 	RequiresCaptcha bool   `json:"requires_captcha,omitempty"`
 	SessionID       string `json:"sessionId,omitempty"`
 	ExpiresAt       string `json:"expiresAt,omitempty"`
+	// Set when the login response describes the session it created
+	SessionContext *LoginSessionContext `json:"sessionContext,omitempty"`
+}
+
+// LoginSessionContext describes the session a login created and the device it came from
+type LoginSessionContext struct {
+	IPAddress          string    `json:"ipAddress" example:"203.0.113.7"`
+	UserAgent          string    `json:"userAgent,omitempty"`
+	Browser            string    `json:"browser" example:"chrome"`     // chrome, firefox, safari, edge, opera or unknown
+	OS                 string    `json:"os" example:"windows"`         // windows, macos, ios, android, linux or unknown
+	DeviceType         string    `json:"deviceType" example:"desktop"` // desktop, mobile, tablet or unknown
+	ExpiresAt          time.Time `json:"expiresAt"`
+	IdleTimeoutSeconds int       `json:"idleTimeoutSeconds" example:"1800"`
+	DeviceBound        bool      `json:"deviceBound"` // The session is bound to the device key sent at login
+	MFAUsed            bool      `json:"mfaUsed"`     // A second factor was verified for this login
+	NewDevice          bool      `json:"newDevice"`   // No other session of the user came from this browser and OS
+}
+
+// ActiveSession describes one of the caller's sessions. Sessions are named by a prefix of their ID;
+// the full ID is a bearer credential and is never returned.
+type ActiveSession struct {
+	IDPrefix       string    `json:"idPrefix" example:"3f9a1c0b7d2e"`
+	IPAddress      string    `json:"ipAddress,omitempty" example:"203.0.113.7"`
+	UserAgent      string    `json:"userAgent,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+	LastActivityAt time.Time `json:"lastActivityAt"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+	Current        bool      `json:"current"` // The session making the request
 }
 
 // ChangePasswordRequest represents a password change request
 type ChangePasswordRequest struct {
 	CurrentPassword string `json:"currentPassword" binding:"required"`
-	NewPassword     string `json:"newPassword" binding:"required,min=12"`
+	NewPassword     string `json:"newPassword" binding:"required"` // Checked against the password policy
 }
 
 // CreateUserRequest represents a user creation request
@@ -194,7 +225,7 @@ type CreateUserRequest struct {
 	Email     string      `json:"email" binding:"required,email"`
 	FirstName string      `json:"firstName" binding:"required"`
 	LastName  string      `json:"lastName" binding:"required"`
-	Password  string      `json:"password" binding:"required,min=12"`
+	Password  string      `json:"password" binding:"required"` // Checked against the password policy
 	RoleIDs   []uuid.UUID `json:"roleIds"`
 }
 
@@ -277,3 +308,32 @@ type ErrorResponse struct {
 	Message string `json:"message" example:"Error message description"`
 	Code    int    `json:"code,omitempty" example:"400"`
 } // @name ErrorResponse
+
+// UserPasswordPepperState is the pepper version and password age of one account
+type UserPasswordPepperState struct {
+	UserID             uuid.UUID `db:"id"`
+	PepperVersion      int       `db:"password_pepper_version"`
+	PasswordChangedAt  time.Time `db:"password_changed_at"`
+	MustChangePassword bool      `db:"must_change_password"`
+}
+
+// APIKey is a machine client's credential. Only the SHA-256 hash of the key is stored; requests
+// authenticated with it are granted the permissions in Scopes and nothing else.
+type APIKey struct {
+	ID        uuid.UUID      `json:"id" db:"id"`
+	UserID    uuid.UUID      `json:"userId" db:"user_id"`
+	Label     string         `json:"label" db:"label"`
+	KeyHash   string         `json:"-" db:"key_hash"`
+	Scopes    pq.StringArray `json:"scopes" db:"scopes"`
+	ExpiresAt *time.Time     `json:"expiresAt,omitempty" db:"expires_at"`
+	RevokedAt *time.Time     `json:"revokedAt,omitempty" db:"revoked_at"`
+	CreatedAt time.Time      `json:"createdAt" db:"created_at"`
+}
+
+// IsUsable reports whether the key is neither revoked nor expired at now
+func (k *APIKey) IsUsable(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DomainDedupDecisionEnum records whether a domain was processed or collapsed into an earlier one
+type DomainDedupDecisionEnum string
+
+const (
+	DomainDedupDecisionKept      DomainDedupDecisionEnum = "kept"
+	DomainDedupDecisionDuplicate DomainDedupDecisionEnum = "duplicate"
+)
+
+// DomainDedupDecision records how a campaign with domain deduplication enabled treated one upstream item.
+// SourceItemID is the generated domain (DNS validation) or DNS result (HTTP keyword validation) that was
+// considered; KeptItemID is the item that is processed for the normalized domain.
+type DomainDedupDecision struct {
+	ID               uuid.UUID               `db:"id" json:"id"`
+	CampaignID       uuid.UUID               `db:"campaign_id" json:"campaignId"`
+	NormalizedDomain string                  `db:"normalized_domain" json:"normalizedDomain"`
+	DomainName       string                  `db:"domain_name" json:"domainName"`
+	SourceItemID     uuid.UUID               `db:"source_item_id" json:"sourceItemId"`
+	KeptItemID       uuid.UUID               `db:"kept_item_id" json:"keptItemId"`
+	Decision         DomainDedupDecisionEnum `db:"decision" json:"decision"`
+	DecidedAt        time.Time               `db:"decided_at" json:"decidedAt"`
+}
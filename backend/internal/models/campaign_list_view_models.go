@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CampaignListView is a named, per-user set of campaign list filters and sort options
+// that can be applied to the campaign list endpoint with ?view=<name>
+type CampaignListView struct {
+	ID           uuid.UUID          `db:"id" json:"id"`
+	UserID       uuid.UUID          `db:"user_id" json:"userId"`
+	Name         string             `db:"name" json:"name"`
+	Status       CampaignStatusEnum `db:"status" json:"status,omitempty"`
+	CampaignType CampaignTypeEnum   `db:"campaign_type" json:"type,omitempty"`
+	SortBy       string             `db:"sort_by" json:"sortBy,omitempty"`
+	SortOrder    string             `db:"sort_order" json:"sortOrder,omitempty"`
+	PageSize     int                `db:"page_size" json:"pageSize,omitempty"`
+	CreatedAt    time.Time          `db:"created_at" json:"createdAt"`
+	UpdatedAt    time.Time          `db:"updated_at" json:"updatedAt"`
+}
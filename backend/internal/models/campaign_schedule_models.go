@@ -0,0 +1,25 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CampaignSchedule starts its campaign once at RunAt, or starts a clone of its campaign each time
+// CronExpression fires. Exactly one of the two is set.
+type CampaignSchedule struct {
+	ID             uuid.UUID      `db:"id" json:"id"`
+	CampaignID     uuid.UUID      `db:"campaign_id" json:"campaignId"`
+	RunAt          sql.NullTime   `db:"run_at" json:"runAt,omitempty"`
+	CronExpression sql.NullString `db:"cron_expression" json:"cron,omitempty"`
+	NextRunAt      sql.NullTime   `db:"next_run_at" json:"nextRunAt,omitempty"`           // Null once a one-off schedule has fired
+	LockedUntil    sql.NullTime   `db:"locked_until" json:"-"`                            // Held by the server firing the schedule
+	LastRunAt      sql.NullTime   `db:"last_run_at" json:"lastRunAt,omitempty"`           // When the schedule last fired
+	LastCampaignID uuid.NullUUID  `db:"last_campaign_id" json:"lastCampaignId,omitempty"` // Campaign the last firing started
+	LastError      sql.NullString `db:"last_error" json:"lastError,omitempty"`            // Why the last firing started no campaign
+	CreatedBy      uuid.NullUUID  `db:"created_by" json:"createdBy,omitempty"`
+	CreatedAt      time.Time      `db:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time      `db:"updated_at" json:"updatedAt"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventDeliveryStatusEnum defines the status of an outbound webhook event delivery
+type EventDeliveryStatusEnum string
+
+const (
+	EventDeliveryStatusPending   EventDeliveryStatusEnum = "pending"
+	EventDeliveryStatusDelivered EventDeliveryStatusEnum = "delivered"
+	EventDeliveryStatusFailed    EventDeliveryStatusEnum = "failed"
+)
+
+// EventDelivery represents an outbound webhook event and its delivery state
+type EventDelivery struct {
+	ID                uuid.UUID               `db:"id" json:"id"`
+	EventType         string                  `db:"event_type" json:"eventType"`
+	TargetURL         string                  `db:"target_url" json:"targetUrl"`
+	Payload           json.RawMessage         `db:"payload" json:"payload"`
+	Status            EventDeliveryStatusEnum `db:"status" json:"status"`
+	Attempts          int                     `db:"attempts" json:"attempts"`
+	LastStatusCode    sql.NullInt32           `db:"last_status_code" json:"lastStatusCode,omitempty"`
+	LastError         sql.NullString          `db:"last_error" json:"lastError,omitempty"`
+	LastAttemptedAt   sql.NullTime            `db:"last_attempted_at" json:"lastAttemptedAt,omitempty"`
+	DeliveredAt       sql.NullTime            `db:"delivered_at" json:"deliveredAt,omitempty"`
+	CreatedAt         time.Time               `db:"created_at" json:"createdAt"`
+	UpdatedAt         time.Time               `db:"updated_at" json:"updatedAt"`
+	CampaignWebhookID uuid.NullUUID           `db:"campaign_webhook_id" json:"campaignWebhookId,omitempty"` // Set on deliveries to a campaign webhook, which are signed with its secret
+}
+
+// EventDeliveryAttempt records a single attempt to deliver an event
+type EventDeliveryAttempt struct {
+	ID            uuid.UUID      `db:"id" json:"id"`
+	DeliveryID    uuid.UUID      `db:"delivery_id" json:"deliveryId"`
+	AttemptNumber int            `db:"attempt_number" json:"attemptNumber"`
+	StatusCode    sql.NullInt32  `db:"status_code" json:"statusCode,omitempty"`
+	Error         sql.NullString `db:"error" json:"error,omitempty"`
+	DurationMs    int64          `db:"duration_ms" json:"durationMs"`
+	IsReplay      bool           `db:"is_replay" json:"isReplay"`
+	AttemptedAt   time.Time      `db:"attempted_at" json:"attemptedAt"`
+}
+
+// CampaignWebhook is a URL notified when its campaign completes, fails or is cancelled
+type CampaignWebhook struct {
+	ID             uuid.UUID      `db:"id" json:"id"`
+	CampaignID     uuid.UUID      `db:"campaign_id" json:"campaignId"`
+	URL            string         `db:"url" json:"url"`
+	Secret         string         `db:"secret" json:"-"` // Signs every notification; only returned when the webhook is created
+	CreatedBy      uuid.NullUUID  `db:"created_by" json:"createdBy,omitempty"`
+	NotifiedStatus sql.NullString `db:"notified_status" json:"notifiedStatus,omitempty"` // Terminal status last notified
+	NotifiedAt     sql.NullTime   `db:"notified_at" json:"notifiedAt,omitempty"`
+	CreatedAt      time.Time      `db:"created_at" json:"createdAt"`
+}
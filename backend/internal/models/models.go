@@ -4,9 +4,11 @@ package models
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // CampaignTypeEnum defines the type of campaign
@@ -59,6 +61,15 @@ const (
 	KeywordRuleTypeRegex  KeywordRuleTypeEnum = "regex"
 )
 
+// KeywordMatchTypeEnum defines how string keywords are matched against page content
+type KeywordMatchTypeEnum string
+
+const (
+	KeywordMatchTypeSubstring    KeywordMatchTypeEnum = "substring"     // Anywhere in the content
+	KeywordMatchTypeRegex        KeywordMatchTypeEnum = "regex"         // The keyword is a regular expression
+	KeywordMatchTypeWordBoundary KeywordMatchTypeEnum = "word_boundary" // Only as a whole word
+)
+
 // CampaignJobStatusEnum defines the status of a background campaign job
 type CampaignJobStatusEnum string
 
@@ -81,6 +92,10 @@ const (
 	ValidationStatusInvalid ValidationStatusEnum = "invalid"
 	ValidationStatusError   ValidationStatusEnum = "error"
 	ValidationStatusSkipped ValidationStatusEnum = "skipped"
+
+	// ValidationStatusMaxAttemptsExceeded marks a DNS or HTTP result whose domain reached the
+	// configured attempt cap and is no longer re-validated
+	ValidationStatusMaxAttemptsExceeded ValidationStatusEnum = "max_attempts_exceeded"
 )
 
 // DNSValidationStatusEnum defines DNS-specific validation status
@@ -143,17 +158,31 @@ type HTTPConfigDetails struct {
 	UserAgent             string              `json:"userAgent" validate:"required"`
 	Headers               map[string]string   `json:"headers,omitempty"`
 	HeaderOrder           []string            `json:"headerOrder,omitempty"`
+	CertPins              map[string][]string `json:"certPins,omitempty"` // Host name -> SHA-256 fingerprints its certificate must match
 	TLSClientHello        *HTTPTLSClientHello `json:"tlsClientHello,omitempty"`
 	HTTP2Settings         *HTTP2Settings      `json:"http2Settings,omitempty"`
 	CookieHandling        *HTTPCookieHandling `json:"cookieHandling,omitempty"`
 	RequestTimeoutSeconds int                 `json:"requestTimeoutSeconds,omitempty" validate:"gte=0"`
 	FollowRedirects       *bool               `json:"followRedirects,omitempty"`
+	MaxRedirects          int                 `json:"maxRedirects,omitempty" validate:"gte=0,lte=20"`                     // Redirects followed before the last response is used; 0 uses the server default
+	MaxBodyBytes          int64               `json:"maxBodyBytes,omitempty" validate:"omitempty,gte=1024,lte=104857600"` // Bytes of response body read; 0 uses the server default
+	CaptureTLS            bool                `json:"captureTLS,omitempty"`                                               // Record the TLS connection and certificate of HTTPS responses
 	AllowedStatusCodes    []int               `json:"allowedStatusCodes,omitempty" validate:"omitempty,dive,gte=100,lte=599"`
 	RateLimitDps          float64             `json:"rateLimitDps,omitempty" validate:"gte=0"`
 	RateLimitBurst        int                 `json:"rateLimitBurst,omitempty" validate:"gte=0"`
 	Notes                 string              `json:"notes,omitempty"`
 }
 
+// HTTPTLSCertificate describes the TLS connection an HTTPS response arrived on and the
+// certificate the server presented
+type HTTPTLSCertificate struct {
+	Version     string    `json:"version"`     // e.g. "TLS 1.3"
+	CipherSuite string    `json:"cipherSuite"` // e.g. "TLS_AES_128_GCM_SHA256"
+	Issuer      string    `json:"issuer"`
+	Subject     string    `json:"subject"`
+	NotAfter    time.Time `json:"notAfter"`
+}
+
 // Persona represents a DNS or HTTP persona
 type Persona struct {
 	ID            uuid.UUID       `db:"id" json:"id"`
@@ -162,6 +191,8 @@ type Persona struct {
 	Description   sql.NullString  `db:"description" json:"description,omitempty"`
 	ConfigDetails json.RawMessage `db:"config_details" json:"configDetails" validate:"required"` // Matches jsonb type in database
 	IsEnabled     bool            `db:"is_enabled" json:"isEnabled"`
+	OwnerID       *uuid.UUID      `db:"owner_id" json:"ownerId,omitempty"` // Nil for personas created before ownership existed
+	Shared        bool            `db:"shared" json:"shared"`              // Usable by users other than the owner
 	CreatedAt     time.Time       `db:"created_at" json:"createdAt"`
 	UpdatedAt     time.Time       `db:"updated_at" json:"updatedAt"`
 }
@@ -185,6 +216,8 @@ type Proxy struct {
 	City          sql.NullString     `db:"city" json:"city,omitempty"`
 	CountryCode   sql.NullString     `db:"country_code" json:"countryCode,omitempty"`
 	Provider      sql.NullString     `db:"provider" json:"provider,omitempty"`
+	OwnerID       *uuid.UUID         `db:"owner_id" json:"ownerId,omitempty"` // Nil for proxies created before ownership existed
+	Shared        bool               `db:"shared" json:"shared"`              // Usable by users other than the owner
 	CreatedAt     time.Time          `db:"created_at" json:"createdAt"`
 	UpdatedAt     time.Time          `db:"updated_at" json:"updatedAt"`
 
@@ -193,15 +226,44 @@ type Proxy struct {
 	InputPassword sql.NullString `json:"inputPassword,omitempty"` // For API input, to be hashed into PasswordHash
 }
 
+// AccessibleTo reports whether userID owns the persona or it is shared
+func (p *Persona) AccessibleTo(userID uuid.UUID) bool {
+	return p.Shared || (p.OwnerID != nil && *p.OwnerID == userID)
+}
+
+// AccessibleTo reports whether userID owns the proxy or it is shared
+func (p *Proxy) AccessibleTo(userID uuid.UUID) bool {
+	return p.Shared || (p.OwnerID != nil && *p.OwnerID == userID)
+}
+
+// Kinds of resource a DeletedResourceSnapshot records
+const (
+	DeletedResourcePersona = "persona"
+	DeletedResourceProxy   = "proxy"
+)
+
+// DeletedResourceSnapshot is a persona or proxy as it was when it was force-deleted while campaigns
+// still referenced it. Each referencing campaign keeps it in its metadata under
+// deletedResources.<id>, so the campaign's results can still be read against the settings used.
+type DeletedResourceSnapshot struct {
+	ResourceType string          `json:"resourceType"` // persona or proxy
+	ID           uuid.UUID       `json:"id"`
+	Name         string          `json:"name"`
+	PersonaType  PersonaTypeEnum `json:"personaType,omitempty"`
+	Config       json.RawMessage `json:"config"` // A persona's configDetails, or a proxy's protocol, address and location
+	DeletedAt    time.Time       `json:"deletedAt"`
+}
+
 // KeywordSet represents a collection of keyword rules
 type KeywordSet struct {
-	ID          uuid.UUID      `db:"id" json:"id"`
-	Name        string         `db:"name" json:"name" validate:"required"`
-	Description sql.NullString `db:"description" json:"description,omitempty"`
-	IsEnabled   bool           `db:"is_enabled" json:"isEnabled"`
-	CreatedAt   time.Time      `db:"created_at" json:"createdAt"`
-	UpdatedAt   time.Time      `db:"updated_at" json:"updatedAt"`
-	Rules       *[]KeywordRule `db:"rules" json:"rules,omitempty"` // Populated from keyword_sets.rules JSONB
+	ID          uuid.UUID            `db:"id" json:"id"`
+	Name        string               `db:"name" json:"name" validate:"required"`
+	Description sql.NullString       `db:"description" json:"description,omitempty"`
+	IsEnabled   bool                 `db:"is_enabled" json:"isEnabled"`
+	MatchType   KeywordMatchTypeEnum `db:"match_type" json:"matchType,omitempty" validate:"omitempty,oneof=substring regex word_boundary"` // How the set's string rules match; regex rules always match as written
+	CreatedAt   time.Time            `db:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time            `db:"updated_at" json:"updatedAt"`
+	Rules       *[]KeywordRule       `db:"rules" json:"rules,omitempty"` // Populated from keyword_sets.rules JSONB
 }
 
 // KeywordRule represents a specific rule within a KeywordSet
@@ -235,12 +297,22 @@ type Campaign struct {
 	SuccessfulItems    *int64             `db:"successful_items" json:"successfulItems,omitempty"`
 	FailedItems        *int64             `db:"failed_items" json:"failedItems,omitempty"`
 	Metadata           *json.RawMessage   `db:"metadata" json:"metadata,omitempty"`
+	Tags               pq.StringArray     `db:"tags" json:"tags,omitempty"` // Free-form labels (project, client, environment) used for filtering
 
 	// Additional tracking fields
 	EstimatedCompletionAt *time.Time `db:"estimated_completion_at" json:"estimatedCompletionAt,omitempty"`
 	AvgProcessingRate     *float64   `db:"avg_processing_rate" json:"avgProcessingRate,omitempty"`
 	LastHeartbeatAt       *time.Time `db:"last_heartbeat_at" json:"lastHeartbeatAt,omitempty"`
 
+	// Automatic retry: a failed campaign with AutoRetry set is re-run as a new campaign that points
+	// back at it. RetryCount is 0 for the original run and counts up along the chain.
+	AutoRetry         bool       `db:"auto_retry" json:"autoRetry"`
+	RetryCount        int        `db:"retry_count" json:"retryCount"`
+	RetryOfCampaignID *uuid.UUID `db:"retry_of_campaign_id" json:"retryOfCampaignId,omitempty"`
+
+	// The domain generation campaign whose completion created this DNS validation campaign
+	PromotedFromCampaignID *uuid.UUID `db:"promoted_from_campaign_id" json:"promotedFromCampaignId,omitempty"`
+
 	DomainGenerationParams      *DomainGenerationCampaignParams `json:"domainGenerationParams,omitempty"`
 	DNSValidationParams         *DNSValidationCampaignParams    `json:"dnsValidationParams,omitempty"`
 	HTTPKeywordValidationParams *HTTPKeywordCampaignParams      `json:"httpKeywordValidationParams,omitempty"`
@@ -248,15 +320,33 @@ type Campaign struct {
 
 // DomainGenerationCampaignParams holds parameters for a domain generation campaign
 type DomainGenerationCampaignParams struct {
-	CampaignID                uuid.UUID `db:"campaign_id" json:"-" `
-	PatternType               string    `db:"pattern_type" json:"patternType" validate:"required,oneof=prefix suffix both"`
-	VariableLength            *int      `db:"variable_length" json:"variableLength,omitempty" validate:"omitempty,gt=0"`
-	CharacterSet              *string   `db:"character_set" json:"characterSet,omitempty" validate:"omitempty"`
-	ConstantString            *string   `db:"constant_string" json:"constantString,omitempty" validate:"omitempty"`
-	TLD                       string    `db:"tld" json:"tld" validate:"required"`
-	NumDomainsToGenerate      int       `db:"num_domains_to_generate" json:"numDomainsToGenerate" validate:"required,gt=0"`
-	TotalPossibleCombinations int64     `db:"total_possible_combinations" json:"totalPossibleCombinations" validate:"required,gt=0"`
-	CurrentOffset             int64     `db:"current_offset" json:"currentOffset" validate:"gte=0"`
+	CampaignID                uuid.UUID     `db:"campaign_id" json:"-" `
+	PatternType               string        `db:"pattern_type" json:"patternType" validate:"required,oneof=prefix suffix both"`
+	VariableLength            *int          `db:"variable_length" json:"variableLength,omitempty" validate:"omitempty,gt=0"`
+	CharacterSet              *string       `db:"character_set" json:"characterSet,omitempty" validate:"omitempty"`
+	ConstantString            *string       `db:"constant_string" json:"constantString,omitempty" validate:"omitempty"`
+	TLD                       string        `db:"tld" json:"tld" validate:"required"`
+	NumDomainsToGenerate      int           `db:"num_domains_to_generate" json:"numDomainsToGenerate" validate:"required,gt=0"`
+	TotalPossibleCombinations int64         `db:"total_possible_combinations" json:"totalPossibleCombinations" validate:"required,gt=0"`
+	CurrentOffset             int64         `db:"current_offset" json:"currentOffset" validate:"gte=0"`
+	DNSPromotion              *DNSPromotion `db:"-" json:"dnsPromotion,omitempty"`
+}
+
+// DNSPromotion is the DNS validation campaign created and started, with the generated domains as
+// its source, when a domain generation campaign completes. Name defaults to the generation
+// campaign's name followed by " (DNS)".
+type DNSPromotion struct {
+	Name                     string              `json:"name,omitempty"`
+	PersonaIDs               []uuid.UUID         `json:"personaIds" validate:"required,min=1"`
+	RotationIntervalSeconds  int                 `json:"rotationIntervalSeconds,omitempty" validate:"gte=0"`
+	ProcessingSpeedPerMinute int                 `json:"processingSpeedPerMinute,omitempty" validate:"gte=0"`
+	BatchSize                int                 `json:"batchSize,omitempty" validate:"gte=0"`
+	RetryAttempts            int                 `json:"retryAttempts,omitempty" validate:"gte=0"`
+	DeduplicateDomains       bool                `json:"deduplicateDomains,omitempty"`
+	ConsensusMode            string              `json:"consensusMode,omitempty" validate:"omitempty,oneof=first majority unanimous"`
+	ConsensusQuorum          int                 `json:"consensusQuorum,omitempty" validate:"gte=0"`
+	ExpectedRecords          *DNSExpectedRecords `json:"expectedRecords,omitempty"`
+	AutoRetry                bool                `json:"autoRetry,omitempty"`
 }
 
 // NormalizedDomainGenerationParams holds the core, normalized parameters for domain generation hashing and storage.
@@ -292,15 +382,34 @@ type GeneratedDomain struct {
 
 // DNSValidationCampaignParams holds parameters for a DNS validation campaign
 type DNSValidationCampaignParams struct {
-	CampaignID                 uuid.UUID        `db:"campaign_id" json:"-" firestore:"-"`
-	SourceGenerationCampaignID *uuid.UUID       `db:"source_generation_campaign_id" json:"sourceGenerationCampaignId,omitempty" firestore:"sourceGenerationCampaignId,omitempty" validate:"omitempty,uuid"`
-	PersonaIDs                 []uuid.UUID      `db:"persona_ids" json:"personaIds" firestore:"personaIds" validate:"required,min=1,dive,uuid"`
-	RotationIntervalSeconds    *int             `db:"rotation_interval_seconds" json:"rotationIntervalSeconds,omitempty" firestore:"rotationIntervalSeconds,omitempty" validate:"omitempty,gte=0"`
-	ProcessingSpeedPerMinute   *int             `db:"processing_speed_per_minute" json:"processingSpeedPerMinute,omitempty" firestore:"processingSpeedPerMinute,omitempty" validate:"omitempty,gte=0"`
-	BatchSize                  *int             `db:"batch_size" json:"batchSize,omitempty" firestore:"batchSize,omitempty" validate:"omitempty,gt=0"`
-	RetryAttempts              *int             `db:"retry_attempts" json:"retryAttempts,omitempty" firestore:"retryAttempts,omitempty" validate:"omitempty,gte=0"`
-	Metadata                   *json.RawMessage `db:"metadata" json:"metadata,omitempty" firestore:"metadata,omitempty"`
-}
+	CampaignID                 uuid.UUID           `db:"campaign_id" json:"-" firestore:"-"`
+	SourceGenerationCampaignID *uuid.UUID          `db:"source_generation_campaign_id" json:"sourceGenerationCampaignId,omitempty" firestore:"sourceGenerationCampaignId,omitempty" validate:"omitempty,uuid"`
+	PersonaIDs                 []uuid.UUID         `db:"persona_ids" json:"personaIds" firestore:"personaIds" validate:"required,min=1,dive,uuid"`
+	RotationIntervalSeconds    *int                `db:"rotation_interval_seconds" json:"rotationIntervalSeconds,omitempty" firestore:"rotationIntervalSeconds,omitempty" validate:"omitempty,gte=0"`
+	ProcessingSpeedPerMinute   *int                `db:"processing_speed_per_minute" json:"processingSpeedPerMinute,omitempty" firestore:"processingSpeedPerMinute,omitempty" validate:"omitempty,gte=0"`
+	BatchSize                  *int                `db:"batch_size" json:"batchSize,omitempty" firestore:"batchSize,omitempty" validate:"omitempty,gt=0"`
+	RetryAttempts              *int                `db:"retry_attempts" json:"retryAttempts,omitempty" firestore:"retryAttempts,omitempty" validate:"omitempty,gte=0"`
+	DeduplicateDomains         bool                `db:"deduplicate_domains" json:"deduplicateDomains,omitempty" firestore:"deduplicateDomains,omitempty"`
+	ConsensusMode              string              `db:"consensus_mode" json:"consensusMode,omitempty" firestore:"consensusMode,omitempty" validate:"omitempty,oneof=first majority unanimous"`
+	ConsensusQuorum            int                 `db:"consensus_quorum" json:"consensusQuorum,omitempty" firestore:"consensusQuorum,omitempty" validate:"omitempty,gte=0"`
+	ExpectedRecords            *DNSExpectedRecords `db:"-" json:"expectedRecords,omitempty" firestore:"expectedRecords,omitempty"`
+	Metadata                   *json.RawMessage    `db:"metadata" json:"metadata,omitempty" firestore:"metadata,omitempty"`
+}
+
+// DNSExpectedRecords are the records a DNS validation campaign expects its domains to resolve to.
+// A resolved domain matches when every A and AAAA address lies in one of the CIDRs given for its
+// family and its CNAME target ends with one of the suffixes; a rule left empty is not checked.
+type DNSExpectedRecords struct {
+	ACIDRs        []string `json:"aCidrs,omitempty" firestore:"aCidrs,omitempty"`
+	AAAACIDRs     []string `json:"aaaaCidrs,omitempty" firestore:"aaaaCidrs,omitempty"`
+	CNAMESuffixes []string `json:"cnameSuffixes,omitempty" firestore:"cnameSuffixes,omitempty"`
+}
+
+// DNS expectation classifications of a resolved domain in a campaign with expected records
+const (
+	DNSExpectationMatched    = "matched_expected"
+	DNSExpectationUnexpected = "unexpected"
+)
 
 // DNSValidationResult stores the outcome of a DNS validation for a domain
 type DNSValidationResult struct {
@@ -310,30 +419,35 @@ type DNSValidationResult struct {
 	DomainName           string           `db:"domain_name" json:"domainName" firestore:"domainName" validate:"required"`
 	ValidationStatus     string           `db:"validation_status" json:"validationStatus" firestore:"validationStatus" validate:"required"`
 	DNSRecords           *json.RawMessage `db:"dns_records" json:"dnsRecords,omitempty" firestore:"dnsRecords,omitempty"`
+	Consensus            *json.RawMessage `db:"consensus" json:"consensus,omitempty" firestore:"consensus,omitempty"`
+	Expectation          string           `db:"expectation" json:"expectation,omitempty" firestore:"expectation,omitempty"`
 	ValidatedByPersonaID uuid.NullUUID    `db:"validated_by_persona_id" json:"validatedByPersonaId,omitempty" firestore:"validatedByPersonaId,omitempty"`
-	Attempts             *int             `db:"attempts" json:"attempts,omitempty" firestore:"attempts,omitempty" validate:"omitempty,gte=0"`
+	Attempts             *int             `db:"attempts" json:"attempts" firestore:"attempts,omitempty" validate:"omitempty,gte=0"`
 	LastCheckedAt        *time.Time       `db:"last_checked_at" json:"lastCheckedAt,omitempty" firestore:"lastCheckedAt,omitempty"`
 	CreatedAt            time.Time        `db:"created_at" json:"createdAt" firestore:"createdAt"`
 }
 
 // HTTPKeywordCampaignParams holds parameters for an HTTP & Keyword validation campaign
 type HTTPKeywordCampaignParams struct {
-	CampaignID               uuid.UUID        `db:"campaign_id" json:"-" firestore:"-"`
-	SourceCampaignID         uuid.UUID        `db:"source_campaign_id" json:"sourceCampaignId" firestore:"sourceCampaignId" validate:"required"`
-	SourceType               string           `db:"source_type" json:"sourceType" firestore:"sourceType" validate:"required"`
-	KeywordSetIDs            []uuid.UUID      `db:"keyword_set_ids" json:"keywordSetIds,omitempty" firestore:"keywordSetIds,omitempty"`
-	AdHocKeywords            *[]string        `db:"ad_hoc_keywords" json:"adHocKeywords,omitempty" firestore:"adHocKeywords,omitempty"`
-	PersonaIDs               []uuid.UUID      `db:"persona_ids" json:"personaIds" firestore:"personaIds" validate:"required,min=1,dive,uuid"`
-	ProxyIDs                 *[]uuid.UUID     `db:"proxy_ids" json:"proxyIds,omitempty" firestore:"proxyIds,omitempty"`
-	ProxyPoolID              uuid.NullUUID    `db:"proxy_pool_id" json:"proxyPoolId,omitempty" firestore:"proxyPoolId,omitempty"`
-	ProxySelectionStrategy   *string          `db:"proxy_selection_strategy" json:"proxySelectionStrategy,omitempty" firestore:"proxySelectionStrategy,omitempty"`
-	RotationIntervalSeconds  *int             `db:"rotation_interval_seconds" json:"rotationIntervalSeconds,omitempty" firestore:"rotationIntervalSeconds,omitempty" validate:"omitempty,gte=0"`
-	ProcessingSpeedPerMinute *int             `db:"processing_speed_per_minute" json:"processingSpeedPerMinute,omitempty" firestore:"processingSpeedPerMinute,omitempty" validate:"omitempty,gte=0"`
-	BatchSize                *int             `db:"batch_size" json:"batchSize,omitempty" firestore:"batchSize,omitempty" validate:"omitempty,gt=0"`
-	RetryAttempts            *int             `db:"retry_attempts" json:"retryAttempts,omitempty" firestore:"retryAttempts,omitempty" validate:"omitempty,gte=0"`
-	TargetHTTPPorts          *[]int           `db:"target_http_ports" json:"targetHttpPorts,omitempty" firestore:"targetHttpPorts,omitempty"`
-	LastProcessedDomainName  *string          `db:"last_processed_domain_name" json:"lastProcessedDomainName,omitempty" firestore:"lastProcessedDomainName,omitempty"`
-	Metadata                 *json.RawMessage `db:"metadata" json:"metadata,omitempty" firestore:"metadata,omitempty"`
+	CampaignID               uuid.UUID            `db:"campaign_id" json:"-" firestore:"-"`
+	SourceCampaignID         uuid.UUID            `db:"source_campaign_id" json:"sourceCampaignId" firestore:"sourceCampaignId" validate:"required"`
+	SourceType               string               `db:"source_type" json:"sourceType" firestore:"sourceType" validate:"required"`
+	KeywordSetIDs            []uuid.UUID          `db:"keyword_set_ids" json:"keywordSetIds,omitempty" firestore:"keywordSetIds,omitempty"`
+	AdHocKeywords            *[]string            `db:"ad_hoc_keywords" json:"adHocKeywords,omitempty" firestore:"adHocKeywords,omitempty"`
+	PersonaIDs               []uuid.UUID          `db:"persona_ids" json:"personaIds" firestore:"personaIds" validate:"required,min=1,dive,uuid"`
+	ProxyIDs                 *[]uuid.UUID         `db:"proxy_ids" json:"proxyIds,omitempty" firestore:"proxyIds,omitempty"`
+	ProxyPoolID              uuid.NullUUID        `db:"proxy_pool_id" json:"proxyPoolId,omitempty" firestore:"proxyPoolId,omitempty"`
+	ProxySelectionStrategy   *string              `db:"proxy_selection_strategy" json:"proxySelectionStrategy,omitempty" firestore:"proxySelectionStrategy,omitempty"`
+	RotationIntervalSeconds  *int                 `db:"rotation_interval_seconds" json:"rotationIntervalSeconds,omitempty" firestore:"rotationIntervalSeconds,omitempty" validate:"omitempty,gte=0"`
+	ProcessingSpeedPerMinute *int                 `db:"processing_speed_per_minute" json:"processingSpeedPerMinute,omitempty" firestore:"processingSpeedPerMinute,omitempty" validate:"omitempty,gte=0"`
+	BatchSize                *int                 `db:"batch_size" json:"batchSize,omitempty" firestore:"batchSize,omitempty" validate:"omitempty,gt=0"`
+	RetryAttempts            *int                 `db:"retry_attempts" json:"retryAttempts,omitempty" firestore:"retryAttempts,omitempty" validate:"omitempty,gte=0"`
+	TargetHTTPPorts          *[]int               `db:"target_http_ports" json:"targetHttpPorts,omitempty" firestore:"targetHttpPorts,omitempty"`
+	LastProcessedDomainName  *string              `db:"last_processed_domain_name" json:"lastProcessedDomainName,omitempty" firestore:"lastProcessedDomainName,omitempty"`
+	DeduplicateDomains       bool                 `db:"deduplicate_domains" json:"deduplicateDomains,omitempty" firestore:"deduplicateDomains,omitempty"`
+	ValidationMode           string               `db:"validation_mode" json:"validationMode,omitempty" firestore:"validationMode,omitempty" validate:"omitempty,oneof=keywords liveness_only"`
+	MatchType                KeywordMatchTypeEnum `db:"match_type" json:"matchType,omitempty" firestore:"matchType,omitempty" validate:"omitempty,oneof=substring regex word_boundary"` // How AdHocKeywords match
+	Metadata                 *json.RawMessage     `db:"metadata" json:"metadata,omitempty" firestore:"metadata,omitempty"`
 }
 
 // HTTPKeywordResult stores the outcome of an HTTP validation and keyword search
@@ -350,9 +464,11 @@ type HTTPKeywordResult struct {
 	FoundKeywordsFromSets   *json.RawMessage `db:"found_keywords_from_sets" json:"foundKeywordsFromSets,omitempty" firestore:"foundKeywordsFromSets,omitempty"`
 	FoundAdHocKeywords      *[]string        `db:"found_ad_hoc_keywords" json:"foundAdHocKeywords,omitempty" firestore:"foundAdHocKeywords,omitempty"`
 	ContentHash             *string          `db:"content_hash" json:"contentHash,omitempty" firestore:"contentHash,omitempty"`
+	BodyTruncated           bool             `db:"body_truncated" json:"bodyTruncated" firestore:"bodyTruncated"`                        // Body was longer than the persona's maxBodyBytes; keywords were matched on the part read
+	TLSCertificate          *json.RawMessage `db:"tls_certificate" json:"tlsCertificate,omitempty" firestore:"tlsCertificate,omitempty"` // HTTPTLSCertificate, when the persona captures TLS
 	ValidatedByPersonaID    uuid.NullUUID    `db:"validated_by_persona_id" json:"validatedByPersonaId,omitempty" firestore:"validatedByPersonaId,omitempty"`
 	UsedProxyID             uuid.NullUUID    `db:"used_proxy_id" json:"usedProxyId,omitempty" firestore:"usedProxyId,omitempty"`
-	Attempts                *int             `db:"attempts" json:"attempts,omitempty" firestore:"attempts,omitempty" validate:"omitempty,gte=0"`
+	Attempts                *int             `db:"attempts" json:"attempts" firestore:"attempts,omitempty" validate:"omitempty,gte=0"`
 	LastCheckedAt           *time.Time       `db:"last_checked_at" json:"lastCheckedAt,omitempty" firestore:"lastCheckedAt,omitempty"`
 	CreatedAt               time.Time        `db:"created_at" json:"createdAt" firestore:"createdAt"`
 }
@@ -370,12 +486,68 @@ type AuditLog struct {
 	UserAgent  sql.NullString   `db:"user_agent" json:"userAgent,omitempty" firestore:"userAgent,omitempty"`
 }
 
+// AuditLogRecord is an audit log entry as served by the audit API, with absent fields left out
+type AuditLogRecord struct {
+	ID         uuid.UUID        `json:"id"`
+	Timestamp  time.Time        `json:"timestamp"`
+	UserID     *uuid.UUID       `json:"userId,omitempty"`
+	Action     string           `json:"action"`
+	EntityType string           `json:"entityType,omitempty"`
+	EntityID   *uuid.UUID       `json:"entityId,omitempty"`
+	Details    *json.RawMessage `json:"details,omitempty"`
+	ClientIP   string           `json:"clientIp,omitempty"`
+	UserAgent  string           `json:"userAgent,omitempty"`
+}
+
+// Record converts the entry for the audit API
+func (a *AuditLog) Record() AuditLogRecord {
+	record := AuditLogRecord{
+		ID:         a.ID,
+		Timestamp:  a.Timestamp,
+		Action:     a.Action,
+		EntityType: a.EntityType.String,
+		Details:    a.Details,
+		ClientIP:   a.ClientIP.String,
+		UserAgent:  a.UserAgent.String,
+	}
+	if a.UserID.Valid {
+		userID := a.UserID.UUID
+		record.UserID = &userID
+	}
+	if a.EntityID.Valid {
+		entityID := a.EntityID.UUID
+		record.EntityID = &entityID
+	}
+	return record
+}
+
+// NewAuditDetails encodes the details of an audit entry as JSON
+func NewAuditDetails(details interface{}) (*json.RawMessage, error) {
+	encoded, err := json.Marshal(details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit details: %w", err)
+	}
+	return JSONRawMessagePtr(encoded), nil
+}
+
+// ReadableAuditDetails returns details that are valid JSON as they are. Older entries whose details
+// were written with Go's value formatting (map[reason:...]) are returned as {"raw": "<the text>"},
+// so that they can still be encoded in a response.
+func ReadableAuditDetails(details *json.RawMessage) *json.RawMessage {
+	if details == nil || len(*details) == 0 || json.Valid(*details) {
+		return details
+	}
+	wrapped, _ := json.Marshal(map[string]string{"raw": string(*details)})
+	return JSONRawMessagePtr(wrapped)
+}
+
 // CampaignJob represents a job for the background worker system.
 type CampaignJob struct {
 	ID                 uuid.UUID             `db:"id" json:"id" firestore:"id"`
 	CampaignID         uuid.UUID             `db:"campaign_id" json:"campaignId" firestore:"campaignId"`
 	JobType            CampaignTypeEnum      `db:"job_type" json:"jobType" firestore:"jobType"` // Renamed from CampaignType to JobType
 	Status             CampaignJobStatusEnum `db:"status" json:"status" firestore:"status"`
+	Priority           int                   `db:"priority" json:"priority" firestore:"priority"` // Higher is fetched first; raised by waiting time when aging is enabled
 	ScheduledAt        time.Time             `db:"scheduled_at" json:"scheduledAt" firestore:"scheduledAt"`
 	JobPayload         *json.RawMessage      `db:"job_payload" json:"jobPayload,omitempty" firestore:"jobPayload,omitempty"` // Renamed from Payload to JobPayload
 	Attempts           int                   `db:"attempts" json:"attempts" firestore:"attempts"`
@@ -388,6 +560,33 @@ type CampaignJob struct {
 	NextExecutionAt    sql.NullTime          `db:"next_execution_at" json:"nextExecutionAt,omitempty" firestore:"nextExecutionAt,omitempty"`
 	LockedAt           sql.NullTime          `db:"locked_at" json:"lockedAt,omitempty" firestore:"lockedAt,omitempty"`
 	LockedBy           sql.NullString        `db:"locked_by" json:"lockedBy,omitempty" firestore:"lockedBy,omitempty"`
+	AttemptHistory     *json.RawMessage      `db:"attempt_history" json:"attemptHistory,omitempty" firestore:"attemptHistory,omitempty"`     // []CampaignJobAttempt, one per failed attempt
+	LastHeartbeatAt    sql.NullTime          `db:"last_heartbeat_at" json:"lastHeartbeatAt,omitempty" firestore:"lastHeartbeatAt,omitempty"` // Set while a worker processes the job
+}
+
+// CampaignJobAttempt records a failed attempt at a campaign job
+type CampaignJobAttempt struct {
+	Attempt  int       `json:"attempt"`
+	WorkerID string    `json:"workerId,omitempty"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// DeadLetterJob is a campaign job that failed on every attempt it was allowed, moved out of the
+// job queue so operators can inspect and replay it
+type DeadLetterJob struct {
+	ID                 uuid.UUID        `db:"id" json:"id"` // ID the job had, and gets back when replayed
+	CampaignID         uuid.UUID        `db:"campaign_id" json:"campaignId"`
+	JobType            CampaignTypeEnum `db:"job_type" json:"jobType"`
+	Priority           int              `db:"priority" json:"priority"`
+	JobPayload         *json.RawMessage `db:"job_payload" json:"jobPayload,omitempty"`
+	Attempts           int              `db:"attempts" json:"attempts"`
+	MaxAttempts        int              `db:"max_attempts" json:"maxAttempts"`
+	LastError          string           `db:"last_error" json:"lastError"`
+	AttemptHistory     *json.RawMessage `db:"attempt_history" json:"attemptHistory,omitempty"` // []CampaignJobAttempt
+	ProcessingServerID sql.NullString   `db:"processing_server_id" json:"processingServerId,omitempty"`
+	JobCreatedAt       time.Time        `db:"job_created_at" json:"jobCreatedAt"`
+	DeadLetteredAt     time.Time        `db:"dead_lettered_at" json:"deadLetteredAt"`
 }
 
 // ProxyPool represents a proxy pool configuration
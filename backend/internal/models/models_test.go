@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 	"time"
@@ -210,3 +211,43 @@ func TestDereferenceHTTPKeywordResultSlice(t *testing.T) {
 		})
 	}
 }
+
+func TestNewAuditDetails(t *testing.T) {
+	reason := `user "admin" not found`
+	details, err := NewAuditDetails(map[string]string{"reason": reason})
+	if err != nil {
+		t.Fatalf("NewAuditDetails() error = %v", err)
+	}
+	var decoded map[string]string
+	if err := json.Unmarshal(*details, &decoded); err != nil {
+		t.Fatalf("details are not valid JSON: %v (%s)", err, *details)
+	}
+	if decoded["reason"] != reason {
+		t.Errorf("reason = %q, want %q", decoded["reason"], reason)
+	}
+
+	if _, err := NewAuditDetails(map[string]interface{}{"ch": make(chan int)}); err == nil {
+		t.Error("NewAuditDetails() with an unencodable value did not fail")
+	}
+}
+
+func TestReadableAuditDetails(t *testing.T) {
+	if got := ReadableAuditDetails(nil); got != nil {
+		t.Errorf("ReadableAuditDetails(nil) = %s, want nil", *got)
+	}
+
+	valid := JSONRawMessagePtr(json.RawMessage(`{"reason":"invalid password"}`))
+	if got := ReadableAuditDetails(valid); got != valid {
+		t.Errorf("valid details were rewritten to %s", *got)
+	}
+
+	legacy := JSONRawMessagePtr(json.RawMessage(`map[reason:invalid password timestamp:2026-10-16T09:00:00Z]`))
+	got := ReadableAuditDetails(legacy)
+	var decoded map[string]string
+	if err := json.Unmarshal(*got, &decoded); err != nil {
+		t.Fatalf("legacy details are not readable: %v (%s)", err, *got)
+	}
+	if decoded["raw"] != string(*legacy) {
+		t.Errorf("raw = %q, want %q", decoded["raw"], *legacy)
+	}
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Setting is a stored runtime setting. Value holds the JSON encoding of the setting's value.
+type Setting struct {
+	Key       string          `db:"key" json:"key"`
+	Value     json.RawMessage `db:"value" json:"value"`
+	UpdatedBy uuid.NullUUID   `db:"updated_by" json:"updatedBy,omitempty"`
+	UpdatedAt time.Time       `db:"updated_at" json:"updatedAt"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// UserAccount is an account as listed for user administration, with the names of its current roles
+type UserAccount struct {
+	ID                  uuid.UUID      `db:"id" json:"id"`
+	Email               string         `db:"email" json:"email"`
+	EmailVerified       bool           `db:"email_verified" json:"emailVerified"`
+	FirstName           string         `db:"first_name" json:"firstName"`
+	LastName            string         `db:"last_name" json:"lastName"`
+	AvatarURL           *string        `db:"avatar_url" json:"avatarUrl,omitempty"`
+	IsActive            bool           `db:"is_active" json:"isActive"`
+	IsLocked            bool           `db:"is_locked" json:"isLocked"`
+	FailedLoginAttempts int            `db:"failed_login_attempts" json:"failedLoginAttempts"`
+	LastLoginAt         *time.Time     `db:"last_login_at" json:"lastLoginAt,omitempty"`
+	MFAEnabled          bool           `db:"mfa_enabled" json:"mfaEnabled"`
+	CreatedAt           time.Time      `db:"created_at" json:"createdAt"`
+	UpdatedAt           time.Time      `db:"updated_at" json:"updatedAt"`
+	Roles               pq.StringArray `db:"roles" json:"roles,omitempty"`
+}
+
+// UserSecurityStatus is the lock state and login history of an account as support staff see it
+type UserSecurityStatus struct {
+	UserID              uuid.UUID  `db:"id" json:"userId"`
+	Email               string     `db:"email" json:"email"`
+	IsActive            bool       `db:"is_active" json:"isActive"`
+	IsLocked            bool       `db:"is_locked" json:"isLocked"`
+	LockedUntil         *time.Time `db:"locked_until" json:"lockedUntil,omitempty"`
+	FailedLoginAttempts int        `db:"failed_login_attempts" json:"failedLoginAttempts"`
+	LastLoginAt         *time.Time `db:"last_login_at" json:"lastLoginAt,omitempty"`
+	LastLoginIP         *string    `db:"last_login_ip" json:"lastLoginIp,omitempty"`
+}
+
+// UserAccountUnlock reports what unlocking an account cleared
+type UserAccountUnlock struct {
+	UserID              uuid.UUID `json:"userId"`
+	WasLocked           bool      `json:"wasLocked"`
+	FailedLoginAttempts int       `json:"failedLoginAttempts"` // The count before it was reset
+	RateLimitsCleared   int64     `json:"rateLimitsCleared"`
+	UnlockedAt          time.Time `json:"unlockedAt"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// UserDataProfile is the account data exported for a data-subject access request. Credentials,
+// verification tokens and MFA secrets are never part of it.
+type UserDataProfile struct {
+	ID                 uuid.UUID      `db:"id" json:"id"`
+	Email              string         `db:"email" json:"email"`
+	EmailVerified      bool           `db:"email_verified" json:"emailVerified"`
+	FirstName          string         `db:"first_name" json:"firstName"`
+	LastName           string         `db:"last_name" json:"lastName"`
+	AvatarURL          *string        `db:"avatar_url" json:"avatarUrl,omitempty"`
+	IsActive           bool           `db:"is_active" json:"isActive"`
+	IsLocked           bool           `db:"is_locked" json:"isLocked"`
+	LastLoginAt        *time.Time     `db:"last_login_at" json:"lastLoginAt,omitempty"`
+	LastLoginIP        *string        `db:"last_login_ip" json:"lastLoginIp,omitempty"`
+	PasswordChangedAt  *time.Time     `db:"password_changed_at" json:"passwordChangedAt,omitempty"`
+	MustChangePassword bool           `db:"must_change_password" json:"mustChangePassword"`
+	MFAEnabled         bool           `db:"mfa_enabled" json:"mfaEnabled"`
+	Roles              pq.StringArray `db:"roles" json:"roles"`
+	CreatedAt          *time.Time     `db:"created_at" json:"createdAt,omitempty"`
+	UpdatedAt          *time.Time     `db:"updated_at" json:"updatedAt,omitempty"`
+}
+
+// UserDataSession is an exported session. The session ID is a bearer credential and is left out.
+type UserDataSession struct {
+	IPAddress      *string    `db:"ip_address" json:"ipAddress,omitempty"`
+	UserAgent      *string    `db:"user_agent" json:"userAgent,omitempty"`
+	IsActive       bool       `db:"is_active" json:"isActive"`
+	ExpiresAt      time.Time  `db:"expires_at" json:"expiresAt"`
+	LastActivityAt *time.Time `db:"last_activity_at" json:"lastActivityAt,omitempty"`
+	CreatedAt      *time.Time `db:"created_at" json:"createdAt,omitempty"`
+}
+
+// UserDataAuditEvent is an exported audit log entry. Redacted entries keep what happened but not
+// which other user was involved.
+type UserDataAuditEvent struct {
+	ID                 uuid.UUID        `json:"id"`
+	Timestamp          time.Time        `json:"timestamp"`
+	Action             string           `json:"action"`
+	EntityType         string           `json:"entityType,omitempty"`
+	EntityID           *uuid.UUID       `json:"entityId,omitempty"`
+	Details            *json.RawMessage `json:"details,omitempty"`
+	ClientIP           string           `json:"clientIp,omitempty"`
+	UserAgent          string           `json:"userAgent,omitempty"`
+	PerformedBySubject bool             `json:"performedBySubject"`
+	Redacted           bool             `json:"redacted,omitempty"`
+}
+
+// UserDataErasure summarizes a right-to-erasure request. The account row and the user's campaigns
+// are kept, anonymized, so counts and ownership stay consistent.
+type UserDataErasure struct {
+	UserID             uuid.UUID `json:"userId"`
+	SessionsDeleted    int64     `json:"sessionsDeleted"`
+	ResetTokensDeleted int64     `json:"resetTokensDeleted"`
+	AuditLogsScrubbed  int64     `json:"auditLogsScrubbed"`
+	AuthEventsScrubbed int64     `json:"authEventsScrubbed"`
+	CampaignsRetained  int64     `json:"campaignsRetained"`
+	ErasedAt           time.Time `json:"erasedAt"`
+}
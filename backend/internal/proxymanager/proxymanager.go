@@ -109,17 +109,37 @@ type ProxyStatus struct {
 	IsHealthy           bool      `json:"isHealthy"`
 	LastFailure         time.Time `json:"lastFailure,omitempty"`
 	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	Quarantined         bool      `json:"quarantined"`
+	QuarantinedAt       time.Time `json:"quarantinedAt,omitempty"`
 }
+
+// ProxyQuarantineEvent describes a proxy entering or leaving quarantine.
+type ProxyQuarantineEvent struct {
+	ProxyID             string
+	Address             string
+	Quarantined         bool // true when the proxy was quarantined, false when it was reinstated
+	ConsecutiveFailures int
+	Reason              string
+	At                  time.Time
+}
+
 type ProxyManager struct {
 	allProxies         []*ProxyStatus
 	activeProxies      []*ProxyStatus
 	mu                 sync.RWMutex
 	currentIndex       int
 	healthCheckTimeout time.Duration
+
+	// Quarantine policy; a threshold of 0 disables quarantining
+	quarantineThreshold int
+	quarantineCooldown  time.Duration
+	onQuarantineChange  func(ProxyQuarantineEvent)
+	quarantineListeners []func(ProxyQuarantineEvent) // Added with AddQuarantineListener
+	checkProxy          func(ctx context.Context, proxyEntry config.ProxyConfigEntry) ProxyTestResult
 }
 
 func NewProxyManager(entries []config.ProxyConfigEntry, initialCheckTimeout time.Duration) *ProxyManager {
-	pm := &ProxyManager{allProxies: make([]*ProxyStatus, 0, len(entries)), activeProxies: make([]*ProxyStatus, 0, len(entries)), currentIndex: 0, healthCheckTimeout: initialCheckTimeout}
+	pm := &ProxyManager{allProxies: make([]*ProxyStatus, 0, len(entries)), activeProxies: make([]*ProxyStatus, 0, len(entries)), currentIndex: 0, healthCheckTimeout: initialCheckTimeout, checkProxy: performSingleProxyCheck}
 	if pm.healthCheckTimeout <= 0 {
 		pm.healthCheckTimeout = DefaultInitialHealthCheckTimeout
 	}
@@ -146,7 +166,7 @@ func NewProxyManager(entries []config.ProxyConfigEntry, initialCheckTimeout time
 			defer func() { <-semaphore }()
 			ctx, cancel := context.WithTimeout(context.Background(), pm.healthCheckTimeout)
 			defer cancel()
-			checkResult := performSingleProxyCheck(ctx, proxyStatus.ProxyConfigEntry)
+			checkResult := pm.checkProxy(ctx, proxyStatus.ProxyConfigEntry)
 			pm.mu.Lock()
 			if checkResult.Success {
 				proxyStatus.IsHealthy = true
@@ -221,7 +241,7 @@ func (pm *ProxyManager) updateActiveProxies() {
 		if p.ProxyConfigEntry.UserEnabled != nil {
 			isUserEnabled = *p.ProxyConfigEntry.UserEnabled
 		}
-		if p.IsHealthy && isUserEnabled {
+		if p.IsHealthy && isUserEnabled && !p.Quarantined {
 			pm.activeProxies = append(pm.activeProxies, p)
 		}
 	}
@@ -251,6 +271,13 @@ func (pm *ProxyManager) GetProxy() (*config.ProxyConfigEntry, error) {
 	return &proxyConfCopy, nil
 }
 func (pm *ProxyManager) ReportProxyHealth(proxyID string, wasSuccessful bool, failureError error) {
+	if event := pm.reportProxyHealth(proxyID, wasSuccessful, failureError); event != nil {
+		pm.notifyQuarantineChange(*event)
+	}
+}
+
+// reportProxyHealth applies a health report and returns a quarantine event if the report quarantined the proxy.
+func (pm *ProxyManager) reportProxyHealth(proxyID string, wasSuccessful bool, failureError error) *ProxyQuarantineEvent {
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
 	var foundProxy *ProxyStatus
@@ -264,10 +291,11 @@ func (pm *ProxyManager) ReportProxyHealth(proxyID string, wasSuccessful bool, fa
 	}
 	if foundProxy == nil {
 		log.Printf("ProxyManager: ReportProxyHealth called for unknown proxy ID '%s'", proxyID)
-		return
+		return nil
 	}
 	log.Printf("ProxyManager: Health report for Proxy ID '%s' (%s). Successful: %t. Error: %v", proxyID, foundProxy.ProxyConfigEntry.Address, wasSuccessful, failureError)
 	statusChanged := false
+	var quarantineEvent *ProxyQuarantineEvent
 	if wasSuccessful {
 		if !foundProxy.IsHealthy {
 			log.Printf("ProxyManager: Proxy ID '%s' (%s) is now marked HEALTHY after successful use.", proxyID, foundProxy.ProxyConfigEntry.Address)
@@ -285,6 +313,15 @@ func (pm *ProxyManager) ReportProxyHealth(proxyID string, wasSuccessful bool, fa
 		foundProxy.IsHealthy = false
 		foundProxy.LastFailure = time.Now()
 		foundProxy.ConsecutiveFailures++
+		if pm.quarantineThreshold > 0 && !foundProxy.Quarantined && foundProxy.ConsecutiveFailures >= pm.quarantineThreshold {
+			reason := fmt.Sprintf("%d consecutive failures", foundProxy.ConsecutiveFailures)
+			if failureError != nil {
+				reason += ": " + failureError.Error()
+			}
+			event := pm.quarantineLocked(foundProxy, reason)
+			quarantineEvent = &event
+			statusChanged = true
+		}
 	}
 	if statusChanged {
 		pm.allProxies[foundIndex] = foundProxy
@@ -299,6 +336,7 @@ func (pm *ProxyManager) ReportProxyHealth(proxyID string, wasSuccessful bool, fa
 	} else if foundIndex != -1 {
 		pm.allProxies[foundIndex] = foundProxy
 	}
+	return quarantineEvent
 }
 
 // GetHTTPTransportForProxy configures the provided baseTransport to use the specified proxy.
@@ -353,6 +391,13 @@ func (pm *ProxyManager) GetAllProxyStatuses() []ProxyStatus {
 	return statuses
 }
 func (pm *ProxyManager) ForceCheckSingleProxy(proxyID string) (*ProxyStatus, error) {
+	// Deferred before the unlock so listeners run after the lock is released
+	var reinstateEvent *ProxyQuarantineEvent
+	defer func() {
+		if reinstateEvent != nil {
+			pm.notifyQuarantineChange(*reinstateEvent)
+		}
+	}()
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
 	var targetProxyStatus *ProxyStatus
@@ -371,7 +416,7 @@ func (pm *ProxyManager) ForceCheckSingleProxy(proxyID string) (*ProxyStatus, err
 	log.Printf("ProxyManager: ForceCheckSingleProxy - Manually checking health for proxy ID '%s' (%s)...", targetProxyStatus.ProxyConfigEntry.ID, targetProxyStatus.ProxyConfigEntry.Address)
 	ctx, cancel := context.WithTimeout(context.Background(), pm.healthCheckTimeout)
 	defer cancel()
-	checkResult := performSingleProxyCheck(ctx, targetProxyStatus.ProxyConfigEntry)
+	checkResult := pm.checkProxy(ctx, targetProxyStatus.ProxyConfigEntry)
 	statusChanged := false
 	if checkResult.Success {
 		if !targetProxyStatus.IsHealthy {
@@ -381,6 +426,11 @@ func (pm *ProxyManager) ForceCheckSingleProxy(proxyID string) (*ProxyStatus, err
 		targetProxyStatus.IsHealthy = true
 		targetProxyStatus.LastFailure = time.Time{}
 		targetProxyStatus.ConsecutiveFailures = 0
+		if targetProxyStatus.Quarantined {
+			event := pm.reinstateLocked(targetProxyStatus, "passed manual health check")
+			reinstateEvent = &event
+			statusChanged = true
+		}
 	} else {
 		if targetProxyStatus.IsHealthy {
 			statusChanged = true
@@ -442,12 +492,18 @@ func (pm *ProxyManager) ForceCheckProxiesAsync(idsToCheck []string) {
 				ctx, cancel := context.WithTimeout(context.Background(), pm.healthCheckTimeout)
 				defer cancel()
 				healthBeforeThisCheck := proxyStatus.IsHealthy
-				checkResult := performSingleProxyCheck(ctx, proxyStatus.ProxyConfigEntry)
+				checkResult := pm.checkProxy(ctx, proxyStatus.ProxyConfigEntry)
+				var reinstateEvent *ProxyQuarantineEvent
 				pm.mu.Lock()
 				if checkResult.Success {
 					proxyStatus.IsHealthy = true
 					proxyStatus.LastFailure = time.Time{}
 					proxyStatus.ConsecutiveFailures = 0
+					if proxyStatus.Quarantined {
+						event := pm.reinstateLocked(proxyStatus, "passed manual health check")
+						reinstateEvent = &event
+						overallStatusChangedSinceStartOfAsyncOp = true
+					}
 					if !healthBeforeThisCheck {
 						overallStatusChangedSinceStartOfAsyncOp = true
 						log.Printf("ProxyManager: ForceCheckProxiesAsync - Proxy ID '%s' (%s) PASSED check and is now HEALTHY.", proxyStatus.ProxyConfigEntry.ID, proxyStatus.ProxyConfigEntry.Address)
@@ -473,6 +529,9 @@ func (pm *ProxyManager) ForceCheckProxiesAsync(idsToCheck []string) {
 					}
 				}
 				pm.mu.Unlock()
+				if reinstateEvent != nil {
+					pm.notifyQuarantineChange(*reinstateEvent)
+				}
 			}(ps)
 		}
 		wgChecks.Wait()
@@ -522,6 +581,131 @@ func (pm *ProxyManager) UpdateProxyUserEnabledStatus(proxyID string, isEnabled b
 	return nil
 }
 
+// SetQuarantinePolicy configures how many consecutive failures quarantine a proxy and how long
+// a quarantined proxy waits before it is re-tested. A threshold of 0 disables quarantining.
+func (pm *ProxyManager) SetQuarantinePolicy(threshold int, cooldown time.Duration) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.quarantineThreshold = threshold
+	pm.quarantineCooldown = cooldown
+}
+
+// SetQuarantineListener registers a function called whenever a proxy is quarantined or reinstated.
+// It is called without the manager lock held.
+func (pm *ProxyManager) SetQuarantineListener(listener func(ProxyQuarantineEvent)) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.onQuarantineChange = listener
+}
+
+// AddQuarantineListener registers an additional function called whenever a proxy is quarantined or
+// reinstated, alongside the one set with SetQuarantineListener.
+func (pm *ProxyManager) AddQuarantineListener(listener func(ProxyQuarantineEvent)) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.quarantineListeners = append(pm.quarantineListeners, listener)
+}
+
+// CheckProxy runs the manager's health check against one proxy without changing its status
+func (pm *ProxyManager) CheckProxy(ctx context.Context, proxyEntry config.ProxyConfigEntry) ProxyTestResult {
+	return pm.checkProxy(ctx, proxyEntry)
+}
+
+// quarantineLocked takes a proxy out of rotation. pm.mu must be held.
+func (pm *ProxyManager) quarantineLocked(ps *ProxyStatus, reason string) ProxyQuarantineEvent {
+	now := time.Now()
+	ps.Quarantined = true
+	ps.QuarantinedAt = now
+	log.Printf("ProxyManager: Proxy ID '%s' (%s) QUARANTINED: %s", ps.ID, ps.Address, reason)
+	websocket.BroadcastProxyStatus(ps.ID, "quarantined", "")
+	return ProxyQuarantineEvent{ProxyID: ps.ID, Address: ps.Address, Quarantined: true, ConsecutiveFailures: ps.ConsecutiveFailures, Reason: reason, At: now}
+}
+
+// reinstateLocked returns a quarantined proxy to rotation. pm.mu must be held.
+func (pm *ProxyManager) reinstateLocked(ps *ProxyStatus, reason string) ProxyQuarantineEvent {
+	ps.Quarantined = false
+	ps.QuarantinedAt = time.Time{}
+	log.Printf("ProxyManager: Proxy ID '%s' (%s) REINSTATED: %s", ps.ID, ps.Address, reason)
+	websocket.BroadcastProxyStatus(ps.ID, "healthy", "")
+	return ProxyQuarantineEvent{ProxyID: ps.ID, Address: ps.Address, Quarantined: false, Reason: reason, At: time.Now()}
+}
+
+func (pm *ProxyManager) notifyQuarantineChange(event ProxyQuarantineEvent) {
+	pm.mu.RLock()
+	listener := pm.onQuarantineChange
+	listeners := pm.quarantineListeners
+	pm.mu.RUnlock()
+	if listener != nil {
+		listener(event)
+	}
+	for _, l := range listeners {
+		l(event)
+	}
+}
+
+// RecheckQuarantinedProxies re-tests quarantined proxies whose cooldown has elapsed.
+// Proxies that pass are reinstated; proxies that fail stay quarantined for another cooldown.
+func (pm *ProxyManager) RecheckQuarantinedProxies(ctx context.Context) {
+	pm.mu.RLock()
+	due := make([]*ProxyStatus, 0)
+	for _, ps := range pm.allProxies {
+		if ps.Quarantined && time.Since(ps.QuarantinedAt) >= pm.quarantineCooldown {
+			due = append(due, ps)
+		}
+	}
+	pm.mu.RUnlock()
+
+	for _, ps := range due {
+		checkCtx, cancel := context.WithTimeout(ctx, pm.healthCheckTimeout)
+		checkResult := pm.checkProxy(checkCtx, ps.ProxyConfigEntry)
+		cancel()
+
+		var event *ProxyQuarantineEvent
+		pm.mu.Lock()
+		if !ps.Quarantined {
+			// Reinstated by a manual check while this recheck was running
+			pm.mu.Unlock()
+			continue
+		}
+		if checkResult.Success {
+			ps.IsHealthy = true
+			ps.LastFailure = time.Time{}
+			ps.ConsecutiveFailures = 0
+			reinstated := pm.reinstateLocked(ps, "passed recheck after quarantine cooldown")
+			event = &reinstated
+			pm.updateActiveProxies()
+		} else {
+			ps.QuarantinedAt = time.Now()
+			ps.LastFailure = ps.QuarantinedAt
+			log.Printf("ProxyManager: Quarantined proxy ID '%s' (%s) FAILED recheck: %s. Remaining quarantined.", ps.ID, ps.Address, checkResult.Error)
+		}
+		pm.mu.Unlock()
+
+		if event != nil {
+			pm.notifyQuarantineChange(*event)
+		}
+	}
+}
+
+// StartQuarantineRechecks periodically re-tests quarantined proxies until ctx is cancelled.
+func (pm *ProxyManager) StartQuarantineRechecks(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				pm.RecheckQuarantinedProxies(ctx)
+			}
+		}
+	}()
+}
+
 // IsProxyRelatedError checks if an error message suggests a proxy-related issue.
 func IsProxyRelatedError(errStr string, proxyAddress string) bool {
 	if errStr == "" {
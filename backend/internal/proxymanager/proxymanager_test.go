@@ -0,0 +1,173 @@
+package proxymanager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestProxyManager builds a manager with healthy proxies without running network health checks
+func newTestProxyManager(checkPasses *atomic.Bool, ids ...string) *ProxyManager {
+	pm := &ProxyManager{
+		healthCheckTimeout: time.Second,
+		checkProxy: func(ctx context.Context, entry config.ProxyConfigEntry) ProxyTestResult {
+			return ProxyTestResult{ProxyID: entry.ID, Success: checkPasses.Load()}
+		},
+	}
+	for _, id := range ids {
+		pm.allProxies = append(pm.allProxies, &ProxyStatus{
+			ProxyConfigEntry: config.ProxyConfigEntry{ID: id, Protocol: "http", Address: id + ".example:8080"},
+			IsHealthy:        true,
+		})
+	}
+	pm.updateActiveProxies()
+	return pm
+}
+
+type recordedQuarantineEvents struct {
+	mu     sync.Mutex
+	events []ProxyQuarantineEvent
+}
+
+func (r *recordedQuarantineEvents) record(event ProxyQuarantineEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recordedQuarantineEvents) snapshot() []ProxyQuarantineEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ProxyQuarantineEvent(nil), r.events...)
+}
+
+func findStatus(t *testing.T, pm *ProxyManager, id string) ProxyStatus {
+	t.Helper()
+	for _, status := range pm.GetAllProxyStatuses() {
+		if status.ID == id {
+			return status
+		}
+	}
+	t.Fatalf("proxy %s not found", id)
+	return ProxyStatus{}
+}
+
+func TestReportProxyHealth_QuarantinesAfterThreshold(t *testing.T) {
+	var checkPasses atomic.Bool
+	pm := newTestProxyManager(&checkPasses, "p1", "p2")
+	pm.SetQuarantinePolicy(3, time.Hour)
+	recorded := &recordedQuarantineEvents{}
+	pm.SetQuarantineListener(recorded.record)
+
+	for i := 0; i < 2; i++ {
+		pm.ReportProxyHealth("p1", false, errors.New("proxyconnect tcp: connection refused"))
+	}
+	assert.False(t, findStatus(t, pm, "p1").Quarantined)
+	assert.Empty(t, recorded.snapshot())
+
+	pm.ReportProxyHealth("p1", false, errors.New("proxyconnect tcp: connection refused"))
+	status := findStatus(t, pm, "p1")
+	assert.True(t, status.Quarantined)
+	assert.False(t, status.QuarantinedAt.IsZero())
+	assert.Equal(t, 3, status.ConsecutiveFailures)
+
+	events := recorded.snapshot()
+	require.Len(t, events, 1)
+	assert.Equal(t, "p1", events[0].ProxyID)
+	assert.True(t, events[0].Quarantined)
+	assert.Equal(t, 3, events[0].ConsecutiveFailures)
+	assert.Contains(t, events[0].Reason, "connection refused")
+
+	// A quarantined proxy is never dispensed, even if a late success report arrives
+	pm.ReportProxyHealth("p1", true, nil)
+	for i := 0; i < 4; i++ {
+		proxy, err := pm.GetProxy()
+		require.NoError(t, err)
+		assert.Equal(t, "p2", proxy.ID)
+	}
+
+	// Further failures do not emit duplicate quarantine events
+	pm.ReportProxyHealth("p1", false, errors.New("still failing"))
+	pm.ReportProxyHealth("p1", false, errors.New("still failing"))
+	pm.ReportProxyHealth("p1", false, errors.New("still failing"))
+	assert.Len(t, recorded.snapshot(), 1)
+}
+
+func TestRecheckQuarantinedProxies_ReinstatesAfterSuccessfulRecheck(t *testing.T) {
+	var checkPasses atomic.Bool
+	pm := newTestProxyManager(&checkPasses, "p1")
+	pm.SetQuarantinePolicy(2, 0)
+	recorded := &recordedQuarantineEvents{}
+	pm.SetQuarantineListener(recorded.record)
+
+	pm.ReportProxyHealth("p1", false, errors.New("i/o timeout"))
+	pm.ReportProxyHealth("p1", false, errors.New("i/o timeout"))
+	require.True(t, findStatus(t, pm, "p1").Quarantined)
+	_, err := pm.GetProxy()
+	require.Error(t, err)
+
+	// A failed recheck keeps the proxy quarantined
+	pm.RecheckQuarantinedProxies(context.Background())
+	assert.True(t, findStatus(t, pm, "p1").Quarantined)
+	assert.Len(t, recorded.snapshot(), 1)
+
+	checkPasses.Store(true)
+	pm.RecheckQuarantinedProxies(context.Background())
+	status := findStatus(t, pm, "p1")
+	assert.False(t, status.Quarantined)
+	assert.True(t, status.IsHealthy)
+	assert.Equal(t, 0, status.ConsecutiveFailures)
+
+	proxy, err := pm.GetProxy()
+	require.NoError(t, err)
+	assert.Equal(t, "p1", proxy.ID)
+
+	events := recorded.snapshot()
+	require.Len(t, events, 2)
+	assert.False(t, events[1].Quarantined)
+	assert.Equal(t, "p1", events[1].ProxyID)
+}
+
+func TestRecheckQuarantinedProxies_WaitsForCooldown(t *testing.T) {
+	var checkPasses atomic.Bool
+	checkPasses.Store(true)
+	pm := newTestProxyManager(&checkPasses, "p1")
+	pm.SetQuarantinePolicy(1, time.Hour)
+
+	pm.ReportProxyHealth("p1", false, errors.New("i/o timeout"))
+	require.True(t, findStatus(t, pm, "p1").Quarantined)
+
+	pm.RecheckQuarantinedProxies(context.Background())
+	assert.True(t, findStatus(t, pm, "p1").Quarantined, "proxy must stay quarantined until the cooldown elapses")
+}
+
+func TestReportProxyHealth_QuarantineDisabledByDefault(t *testing.T) {
+	var checkPasses atomic.Bool
+	pm := newTestProxyManager(&checkPasses, "p1")
+	for i := 0; i < 10; i++ {
+		pm.ReportProxyHealth("p1", false, errors.New("i/o timeout"))
+	}
+	assert.False(t, findStatus(t, pm, "p1").Quarantined)
+}
+
+func TestAddQuarantineListener_NotifiedAlongsideListener(t *testing.T) {
+	var checkPasses atomic.Bool
+	pm := newTestProxyManager(&checkPasses, "p1")
+	pm.SetQuarantinePolicy(1, time.Hour)
+	primary := &recordedQuarantineEvents{}
+	added := &recordedQuarantineEvents{}
+	pm.SetQuarantineListener(primary.record)
+	pm.AddQuarantineListener(added.record)
+
+	pm.ReportProxyHealth("p1", false, errors.New("i/o timeout"))
+	assert.Len(t, primary.snapshot(), 1)
+	require.Len(t, added.snapshot(), 1)
+	assert.Equal(t, "p1", added.snapshot()[0].ProxyID)
+}
@@ -51,6 +51,11 @@ func (s *APIKeyService) GenerateAPIKey() (string, error) {
 
 // HashAPIKey creates a SHA256 hash of the API key
 func (s *APIKeyService) HashAPIKey(key string) string {
+	return HashAPIKey(key)
+}
+
+// HashAPIKey returns the hex SHA256 hash under which an API key is stored and looked up
+func HashAPIKey(key string) string {
 	hash := sha256.Sum256([]byte(key))
 	return hex.EncodeToString(hash[:])
 }
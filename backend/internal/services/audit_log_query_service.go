@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/jmoiron/sqlx"
+)
+
+// Formats an audit log export can be written in
+const (
+	AuditExportFormatJSONL = "jsonl"
+	AuditExportFormatCSV   = "csv"
+)
+
+// ErrAuditExportBusy is returned when the configured number of audit log exports are already running
+var ErrAuditExportBusy = errors.New("too many audit log exports are running")
+
+// auditExportCSVHeader names the columns of a CSV audit log export
+var auditExportCSVHeader = []string{"id", "timestamp", "userId", "action", "entityType", "entityId", "details", "clientIp", "userAgent"}
+
+// ParseAuditExportFormat validates the export query parameter
+func ParseAuditExportFormat(raw string) (string, error) {
+	switch format := strings.ToLower(strings.TrimSpace(raw)); format {
+	case AuditExportFormatJSONL, AuditExportFormatCSV:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q, expected %s or %s", raw, AuditExportFormatJSONL, AuditExportFormatCSV)
+	}
+}
+
+// AuditLogQueryService answers audit log queries and streams ranges of any size for compliance
+// exports. Exports read entries in batches by keyset over (timestamp, id), so memory stays bounded
+// by the batch size and deep batches cost no more than the first.
+type AuditLogQueryService struct {
+	db            *sqlx.DB
+	auditLogStore store.AuditLogStore
+	batchSize     int
+	slots         chan struct{}
+	now           func() time.Time
+}
+
+// NewAuditLogQueryService creates an AuditLogQueryService using the export limits in cfg
+func NewAuditLogQueryService(db *sqlx.DB, auditLogStore store.AuditLogStore, cfg config.AuditConfig) *AuditLogQueryService {
+	batchSize := cfg.ExportBatchSize
+	if batchSize <= 0 {
+		batchSize = config.DefaultAuditExportBatchSize
+	}
+	maxExports := cfg.MaxConcurrentExports
+	if maxExports <= 0 {
+		maxExports = config.DefaultAuditMaxConcurrentExports
+	}
+	return &AuditLogQueryService{
+		db:            db,
+		auditLogStore: auditLogStore,
+		batchSize:     batchSize,
+		slots:         make(chan struct{}, maxExports),
+		now:           func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (s *AuditLogQueryService) querier() store.Querier {
+	if s.db == nil {
+		return nil
+	}
+	return s.db
+}
+
+// List returns one page of the entries matching filter, newest first
+func (s *AuditLogQueryService) List(ctx context.Context, filter store.ListAuditLogsFilter) ([]*models.AuditLog, error) {
+	return s.auditLogStore.ListAuditLogs(ctx, s.querier(), filter)
+}
+
+// Export writes every entry matching filter to w in ascending time order, one JSON object per line
+// or as CSV with a header row. Limit and Offset in filter are ignored. Entries written after the
+// export started are left out so the range is fixed. It returns ErrAuditExportBusy before writing
+// anything when no export slot is free, and the number of entries written.
+func (s *AuditLogQueryService) Export(ctx context.Context, filter store.ListAuditLogsFilter, format string, w io.Writer) (int, error) {
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	default:
+		return 0, ErrAuditExportBusy
+	}
+
+	if until := s.now(); filter.EndDate.IsZero() || filter.EndDate.After(until) {
+		filter.EndDate = until
+	}
+	filter.Limit = s.batchSize
+	filter.Offset = 0
+
+	write := jsonlRecordWriter(w)
+	var csvWriter *csv.Writer
+	if format == AuditExportFormatCSV {
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(auditExportCSVHeader); err != nil {
+			return 0, err
+		}
+		write = csvRecordWriter(csvWriter)
+	}
+
+	written := 0
+	var after *store.AuditLogCursor
+	for {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+		entries, err := s.auditLogStore.ListAuditLogsAfter(ctx, s.querier(), filter, after)
+		if err != nil {
+			return written, fmt.Errorf("list audit logs: %w", err)
+		}
+		for _, entry := range entries {
+			if err := write(entry.Record()); err != nil {
+				return written, err
+			}
+			written++
+		}
+		if csvWriter != nil {
+			csvWriter.Flush()
+			if err := csvWriter.Error(); err != nil {
+				return written, err
+			}
+		}
+		if len(entries) < filter.Limit {
+			return written, nil
+		}
+		last := entries[len(entries)-1]
+		after = &store.AuditLogCursor{Timestamp: last.Timestamp, ID: last.ID}
+	}
+}
+
+func jsonlRecordWriter(w io.Writer) func(models.AuditLogRecord) error {
+	encoder := json.NewEncoder(w)
+	return func(record models.AuditLogRecord) error {
+		return encoder.Encode(record)
+	}
+}
+
+func csvRecordWriter(w *csv.Writer) func(models.AuditLogRecord) error {
+	return func(record models.AuditLogRecord) error {
+		row := []string{record.ID.String(), record.Timestamp.UTC().Format(time.RFC3339Nano), "", record.Action,
+			record.EntityType, "", "", record.ClientIP, record.UserAgent}
+		if record.UserID != nil {
+			row[2] = record.UserID.String()
+		}
+		if record.EntityID != nil {
+			row[5] = record.EntityID.String()
+		}
+		if record.Details != nil {
+			row[6] = string(*record.Details)
+		}
+		return w.Write(row)
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAuditExportFixture holds count entries a second apart in pairs sharing a timestamp, so pages
+// end between entries with equal timestamps. Every third entry is a login, the rest campaign updates.
+func newAuditExportFixture(count, batchSize int) (*AuditLogQueryService, *memoryAuditLogStore, time.Time) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	auditStore := &memoryAuditLogStore{}
+	for i := 0; i < count; i++ {
+		action := "Update Campaign"
+		if i%3 == 0 {
+			action = "Login"
+		}
+		auditStore.entries = append(auditStore.entries, &models.AuditLog{
+			ID:         uuid.New(),
+			Timestamp:  start.Add(time.Duration(i/2) * time.Second),
+			Action:     action,
+			EntityType: sql.NullString{String: "Campaign", Valid: true},
+		})
+	}
+	service := NewAuditLogQueryService(nil, auditStore, config.AuditConfig{ExportBatchSize: batchSize, MaxConcurrentExports: 1})
+	service.now = func() time.Time { return start.Add(time.Hour) }
+	return service, auditStore, start
+}
+
+func TestAuditLogQueryService_ExportJSONLAcrossPages(t *testing.T) {
+	service, auditStore, start := newAuditExportFixture(250, 7)
+
+	var out bytes.Buffer
+	filter := store.ListAuditLogsFilter{Action: "Update Campaign", StartDate: start.Add(10 * time.Second), Limit: 5, Offset: 40}
+	written, err := service.Export(context.Background(), filter, AuditExportFormatJSONL, &out)
+	require.NoError(t, err)
+
+	want := make(map[uuid.UUID]bool)
+	for _, entry := range auditStore.entries {
+		if entry.Action == "Update Campaign" && !entry.Timestamp.Before(filter.StartDate) {
+			want[entry.ID] = true
+		}
+	}
+	seen := make(map[uuid.UUID]int)
+	var previous time.Time
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var record models.AuditLogRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		assert.False(t, record.Timestamp.Before(previous), "entries are exported oldest first")
+		previous = record.Timestamp
+		seen[record.ID]++
+	}
+	require.NoError(t, scanner.Err())
+
+	assert.Equal(t, len(want), written)
+	assert.Len(t, seen, len(want))
+	for id := range want {
+		assert.Equal(t, 1, seen[id], "entry %s is exported exactly once", id)
+	}
+	assert.Greater(t, auditStore.keysetQueries, len(want)/7, "the export is read in batches of the configured size")
+}
+
+func TestAuditLogQueryService_ExportCSV(t *testing.T) {
+	service, auditStore, _ := newAuditExportFixture(20, 3)
+	details := json.RawMessage(`{"name":"spring, sale"}`)
+	userID := uuid.New()
+	auditStore.entries[1].Details = &details
+	auditStore.entries[1].UserID = uuid.NullUUID{UUID: userID, Valid: true}
+
+	var out bytes.Buffer
+	written, err := service.Export(context.Background(), store.ListAuditLogsFilter{}, AuditExportFormatCSV, &out)
+	require.NoError(t, err)
+	rows, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+
+	require.Len(t, rows, 21)
+	assert.Equal(t, 20, written)
+	assert.Equal(t, auditExportCSVHeader, rows[0])
+	seen := make(map[string]bool)
+	for _, row := range rows[1:] {
+		assert.False(t, seen[row[0]], "entry %s is exported exactly once", row[0])
+		seen[row[0]] = true
+		if row[0] == auditStore.entries[1].ID.String() {
+			assert.Equal(t, userID.String(), row[2])
+			assert.Equal(t, `{"name":"spring, sale"}`, row[6])
+		}
+	}
+}
+
+func TestAuditLogQueryService_ExportLeavesOutLaterEntries(t *testing.T) {
+	service, auditStore, start := newAuditExportFixture(10, 4)
+	auditStore.entries = append(auditStore.entries, &models.AuditLog{ID: uuid.New(), Timestamp: start.Add(2 * time.Hour), Action: "Login"})
+
+	written, err := service.Export(context.Background(), store.ListAuditLogsFilter{}, AuditExportFormatJSONL, &bytes.Buffer{})
+	require.NoError(t, err)
+	assert.Equal(t, 10, written)
+}
+
+func TestAuditLogQueryService_ExportRefusedWhenBusy(t *testing.T) {
+	service, _, _ := newAuditExportFixture(10, 4)
+	service.slots <- struct{}{} // another export is running
+
+	var out bytes.Buffer
+	_, err := service.Export(context.Background(), store.ListAuditLogsFilter{}, AuditExportFormatCSV, &out)
+	assert.ErrorIs(t, err, ErrAuditExportBusy)
+	assert.Zero(t, out.Len())
+
+	<-service.slots
+	_, err = service.Export(context.Background(), store.ListAuditLogsFilter{}, AuditExportFormatCSV, &out)
+	assert.NoError(t, err)
+}
+
+func TestParseAuditExportFormat(t *testing.T) {
+	format, err := ParseAuditExportFormat("CSV")
+	require.NoError(t, err)
+	assert.Equal(t, AuditExportFormatCSV, format)
+	_, err = ParseAuditExportFormat("xml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// campaignRetryBatchSize bounds the failed campaigns retried by one sweep
+const campaignRetryBatchSize = 50
+
+// retryNameSuffix matches the suffix a retry run adds to its campaign's name
+var retryNameSuffix = regexp.MustCompile(` \(retry \d+\)$`)
+
+// CampaignAutoRetrier re-runs failed campaigns that were created with autoRetry. Each retry is a
+// new campaign with the failed run's parameters that points back at the run it retries, so the
+// chain of attempts stays visible. A run is retried once its backoff has passed: backoffSeconds
+// after the first failure, doubling for each further retry, until maxRetries retries have failed.
+// A nil retrier retries nothing.
+type CampaignAutoRetrier struct {
+	db            *sqlx.DB
+	campaignStore store.CampaignStore
+	orchestrator  CampaignOrchestratorService // Starts retry runs
+	maxRetries    int
+	backoff       time.Duration
+	maxBackoff    time.Duration
+	interval      time.Duration
+	now           func() time.Time
+}
+
+// NewCampaignAutoRetrier returns nil when campaignRetry.maxRetries is negative
+func NewCampaignAutoRetrier(appCfg *config.AppConfig, db *sqlx.DB, cs store.CampaignStore, orchestrator CampaignOrchestratorService) *CampaignAutoRetrier {
+	cfg := config.CampaignRetryConfig{}
+	if appCfg != nil {
+		cfg = appCfg.CampaignRetry
+	}
+	if cfg.MaxRetries < 0 {
+		return nil
+	}
+	retrier := &CampaignAutoRetrier{
+		db:            db,
+		campaignStore: cs,
+		orchestrator:  orchestrator,
+		maxRetries:    cfg.MaxRetries,
+		backoff:       time.Duration(cfg.BackoffSeconds) * time.Second,
+		maxBackoff:    time.Duration(cfg.MaxBackoffSeconds) * time.Second,
+		interval:      time.Duration(cfg.CheckIntervalSeconds) * time.Second,
+		now:           time.Now,
+	}
+	if retrier.maxRetries == 0 {
+		retrier.maxRetries = config.DefaultCampaignMaxRetries
+	}
+	if retrier.backoff <= 0 {
+		retrier.backoff = config.DefaultCampaignRetryBackoffSeconds * time.Second
+	}
+	if retrier.maxBackoff <= 0 {
+		retrier.maxBackoff = config.DefaultCampaignRetryMaxBackoffSeconds * time.Second
+	}
+	if retrier.interval <= 0 {
+		retrier.interval = config.DefaultCampaignRetryCheckIntervalSeconds * time.Second
+	}
+	return retrier
+}
+
+// Start retries due campaigns every check interval until ctx is cancelled
+func (r *CampaignAutoRetrier) Start(ctx context.Context) {
+	if r == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.RetryDue(ctx)
+			}
+		}
+	}()
+}
+
+// RetryDue retries every failed auto-retry campaign whose backoff has passed and returns the
+// retry runs it started
+func (r *CampaignAutoRetrier) RetryDue(ctx context.Context) []*models.Campaign {
+	if r == nil {
+		return nil
+	}
+	var querier store.Querier
+	if r.db != nil {
+		querier = r.db
+	}
+	failed, err := r.campaignStore.ListFailedCampaignsToRetry(ctx, querier, r.maxRetries, campaignRetryBatchSize)
+	if err != nil {
+		log.Printf("CampaignAutoRetrier: Failed to list failed campaigns: %v", err)
+		return nil
+	}
+	now := r.now().UTC()
+	var started []*models.Campaign
+	for _, campaign := range failed {
+		if now.Before(r.retryAt(campaign)) {
+			continue
+		}
+		retry, err := r.retry(ctx, campaign)
+		if err != nil {
+			log.Printf("CampaignAutoRetrier: Failed to retry campaign %s: %v", campaign.ID, err)
+			continue
+		}
+		log.Printf("CampaignAutoRetrier: Campaign %s failed; started retry %d of %d as campaign %s",
+			campaign.ID, retry.RetryCount, r.maxRetries, retry.ID)
+		started = append(started, retry)
+	}
+	return started
+}
+
+// retryAt is when a failed run may be retried
+func (r *CampaignAutoRetrier) retryAt(campaign *models.Campaign) time.Time {
+	failedAt := campaign.UpdatedAt
+	if campaign.CompletedAt != nil {
+		failedAt = *campaign.CompletedAt
+	}
+	delay := r.backoff
+	for i := 0; i < campaign.RetryCount && delay < r.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > r.maxBackoff {
+		delay = r.maxBackoff
+	}
+	return failedAt.Add(delay)
+}
+
+// retry creates the retry run of a failed campaign and starts it. A retry run that another
+// instance created first is reported as a duplicate and left alone.
+func (r *CampaignAutoRetrier) retry(ctx context.Context, failed *models.Campaign) (*models.Campaign, error) {
+	now := r.now().UTC()
+	retry := &models.Campaign{
+		ID:                 uuid.New(),
+		Name:               fmt.Sprintf("%s (retry %d)", retryNameSuffix.ReplaceAllString(failed.Name, ""), failed.RetryCount+1),
+		CampaignType:       failed.CampaignType,
+		Status:             models.CampaignStatusPending,
+		UserID:             failed.UserID,
+		Tags:               failed.Tags,
+		Metadata:           failed.Metadata,
+		TotalItems:         failed.TotalItems,
+		ProcessedItems:     models.Int64Ptr(0),
+		ProgressPercentage: models.Float64Ptr(0.0),
+		CreatedAt:          now,
+		UpdatedAt:          now,
+		AutoRetry:          true,
+		RetryCount:         failed.RetryCount + 1,
+		RetryOfCampaignID:  &failed.ID,
+	}
+
+	if r.db != nil {
+		tx, err := r.db.BeginTxx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.createRetry(ctx, tx, failed, retry); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+	} else if err := r.createRetry(ctx, nil, failed, retry); err != nil {
+		return nil, err
+	}
+
+	startCtx := WithCampaignStatusActor(ctx, CampaignStatusActor{
+		Type:   StatusActorSystem,
+		Reason: fmt.Sprintf("automatic retry of failed campaign %s", failed.ID),
+	})
+	if err := r.orchestrator.StartCampaign(startCtx, retry.ID); err != nil {
+		// The run stays pending, linked to the failed run, and can be started by hand
+		return retry, fmt.Errorf("created retry campaign %s but could not start it: %w", retry.ID, err)
+	}
+	retry.Status = models.CampaignStatusQueued
+	return retry, nil
+}
+
+// createRetry writes the retry run and a copy of the failed run's parameters. HTTP keyword
+// retries start from the first source domain again; domain generation retries carry on from the
+// offset the failed run reached.
+func (r *CampaignAutoRetrier) createRetry(ctx context.Context, exec store.Querier, failed, retry *models.Campaign) error {
+	if err := r.campaignStore.CreateCampaign(ctx, exec, retry); err != nil {
+		return fmt.Errorf("failed to create retry campaign: %w", err)
+	}
+	switch failed.CampaignType {
+	case models.CampaignTypeDomainGeneration:
+		params, err := r.campaignStore.GetDomainGenerationParams(ctx, exec, failed.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load domain generation params: %w", err)
+		}
+		copied := *params
+		copied.CampaignID = retry.ID
+		return r.campaignStore.CreateDomainGenerationParams(ctx, exec, &copied)
+	case models.CampaignTypeDNSValidation:
+		params, err := r.campaignStore.GetDNSValidationParams(ctx, exec, failed.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load DNS validation params: %w", err)
+		}
+		copied := *params
+		copied.CampaignID = retry.ID
+		return r.campaignStore.CreateDNSValidationParams(ctx, exec, &copied)
+	case models.CampaignTypeHTTPKeywordValidation:
+		params, err := r.campaignStore.GetHTTPKeywordParams(ctx, exec, failed.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load HTTP keyword params: %w", err)
+		}
+		copied := *params
+		copied.CampaignID = retry.ID
+		copied.LastProcessedDomainName = nil
+		return r.campaignStore.CreateHTTPKeywordParams(ctx, exec, &copied)
+	}
+	return fmt.Errorf("campaign type %s cannot be retried", failed.CampaignType)
+}
+
+// RetryChain returns the original run of a campaign's retry chain followed by its retries. It
+// returns store.ErrNotFound when the campaign does not exist.
+func (r *CampaignAutoRetrier) RetryChain(ctx context.Context, campaignID uuid.UUID) ([]*models.Campaign, error) {
+	var querier store.Querier
+	if r.db != nil {
+		querier = r.db
+	}
+	chain, err := r.campaignStore.ListCampaignRetryChain(ctx, querier, campaignID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load retry chain of campaign %s: %w", campaignID, err)
+	}
+	return chain, err
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// retryCampaignStore keeps DNS validation campaigns and their params in memory
+type retryCampaignStore struct {
+	store.CampaignStore
+	campaigns map[uuid.UUID]*models.Campaign
+	dnsParams map[uuid.UUID]*models.DNSValidationCampaignParams
+}
+
+func newRetryCampaignStore() *retryCampaignStore {
+	return &retryCampaignStore{
+		campaigns: make(map[uuid.UUID]*models.Campaign),
+		dnsParams: make(map[uuid.UUID]*models.DNSValidationCampaignParams),
+	}
+}
+
+func (s *retryCampaignStore) CreateCampaign(ctx context.Context, exec store.Querier, campaign *models.Campaign) error {
+	copied := *campaign
+	s.campaigns[campaign.ID] = &copied
+	return nil
+}
+
+func (s *retryCampaignStore) GetCampaignByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Campaign, error) {
+	campaign, ok := s.campaigns[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return campaign, nil
+}
+
+func (s *retryCampaignStore) CreateDNSValidationParams(ctx context.Context, exec store.Querier, params *models.DNSValidationCampaignParams) error {
+	s.dnsParams[params.CampaignID] = params
+	return nil
+}
+
+func (s *retryCampaignStore) GetDNSValidationParams(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.DNSValidationCampaignParams, error) {
+	params, ok := s.dnsParams[campaignID]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return params, nil
+}
+
+func (s *retryCampaignStore) ListFailedCampaignsToRetry(ctx context.Context, exec store.Querier, maxRetries, limit int) ([]*models.Campaign, error) {
+	retried := make(map[uuid.UUID]bool)
+	for _, campaign := range s.campaigns {
+		if campaign.RetryOfCampaignID != nil {
+			retried[*campaign.RetryOfCampaignID] = true
+		}
+	}
+	var failed []*models.Campaign
+	for _, campaign := range s.campaigns {
+		if campaign.Status == models.CampaignStatusFailed && campaign.AutoRetry && campaign.RetryCount < maxRetries && !retried[campaign.ID] {
+			failed = append(failed, campaign)
+		}
+	}
+	if len(failed) > limit {
+		failed = failed[:limit]
+	}
+	return failed, nil
+}
+
+func (s *retryCampaignStore) ListCampaignRetryChain(ctx context.Context, exec store.Querier, campaignID uuid.UUID) ([]*models.Campaign, error) {
+	campaign, ok := s.campaigns[campaignID]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	for campaign.RetryOfCampaignID != nil {
+		campaign = s.campaigns[*campaign.RetryOfCampaignID]
+	}
+	chain := []*models.Campaign{campaign}
+	for {
+		var next *models.Campaign
+		for _, c := range s.campaigns {
+			if c.RetryOfCampaignID != nil && *c.RetryOfCampaignID == chain[len(chain)-1].ID {
+				next = c
+			}
+		}
+		if next == nil {
+			break
+		}
+		chain = append(chain, next)
+	}
+	return chain, nil
+}
+
+// queueingOrchestrator starts campaigns by queueing them
+type queueingOrchestrator struct {
+	CampaignOrchestratorService
+	campaignStore *retryCampaignStore
+	started       []uuid.UUID
+}
+
+func (o *queueingOrchestrator) StartCampaign(ctx context.Context, campaignID uuid.UUID) error {
+	o.started = append(o.started, campaignID)
+	o.campaignStore.campaigns[campaignID].Status = models.CampaignStatusQueued
+	return nil
+}
+
+func TestCampaignAutoRetrier_RetriesUpToMaxWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	cs := newRetryCampaignStore()
+	orchestrator := &queueingOrchestrator{campaignStore: cs}
+	appCfg := &config.AppConfig{CampaignRetry: config.CampaignRetryConfig{MaxRetries: 3, BackoffSeconds: 60, MaxBackoffSeconds: 100}}
+	retrier := NewCampaignAutoRetrier(appCfg, nil, cs, orchestrator)
+	require.NotNil(t, retrier)
+
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	retrier.now = func() time.Time { return now }
+	userID := uuid.New()
+	sourceID := uuid.New()
+	original := &models.Campaign{ID: uuid.New(), Name: "Nightly DNS", CampaignType: models.CampaignTypeDNSValidation,
+		Status: models.CampaignStatusRunning, UserID: &userID, Tags: []string{"nightly"}, AutoRetry: true}
+	require.NoError(t, cs.CreateCampaign(ctx, nil, original))
+	require.NoError(t, cs.CreateDNSValidationParams(ctx, nil, &models.DNSValidationCampaignParams{
+		CampaignID: original.ID, SourceGenerationCampaignID: &sourceID, PersonaIDs: []uuid.UUID{uuid.New()}}))
+
+	// A campaign that did not opt in is never retried
+	manual := &models.Campaign{ID: uuid.New(), Name: "Manual", CampaignType: models.CampaignTypeDNSValidation, Status: models.CampaignStatusFailed, UpdatedAt: now}
+	require.NoError(t, cs.CreateCampaign(ctx, nil, manual))
+
+	fail := func(id uuid.UUID) {
+		failedAt := now
+		cs.campaigns[id].Status = models.CampaignStatusFailed
+		cs.campaigns[id].UpdatedAt = failedAt
+		cs.campaigns[id].CompletedAt = &failedAt
+	}
+
+	latest := original.ID
+	for _, backoff := range []time.Duration{60 * time.Second, 100 * time.Second, 100 * time.Second} {
+		fail(latest)
+		now = now.Add(backoff - time.Second)
+		assert.Empty(t, retrier.RetryDue(ctx), "retried before its backoff passed")
+		now = now.Add(time.Second)
+		started := retrier.RetryDue(ctx)
+		require.Len(t, started, 1)
+		retry := cs.campaigns[started[0].ID]
+		assert.Equal(t, models.CampaignStatusQueued, retry.Status)
+		require.NotNil(t, retry.RetryOfCampaignID)
+		assert.Equal(t, latest, *retry.RetryOfCampaignID)
+		assert.Equal(t, cs.campaigns[latest].RetryCount+1, retry.RetryCount)
+		assert.True(t, retry.AutoRetry)
+		assert.Equal(t, &userID, retry.UserID)
+		assert.Equal(t, []string{"nightly"}, []string(retry.Tags))
+		params, err := cs.GetDNSValidationParams(ctx, nil, retry.ID)
+		require.NoError(t, err)
+		assert.Equal(t, &sourceID, params.SourceGenerationCampaignID)
+		latest = retry.ID
+	}
+
+	// The third retry failing ends the chain
+	fail(latest)
+	now = now.Add(24 * time.Hour)
+	assert.Empty(t, retrier.RetryDue(ctx))
+	assert.Len(t, orchestrator.started, 3)
+
+	chain, err := retrier.RetryChain(ctx, latest)
+	require.NoError(t, err)
+	require.Len(t, chain, 4)
+	names := make([]string, len(chain))
+	for i, run := range chain {
+		assert.Equal(t, i, run.RetryCount)
+		assert.Equal(t, models.CampaignStatusFailed, run.Status)
+		names[i] = run.Name
+	}
+	assert.Equal(t, []string{"Nightly DNS", "Nightly DNS (retry 1)", "Nightly DNS (retry 2)", "Nightly DNS (retry 3)"}, names)
+	assert.Equal(t, original.ID, chain[0].ID)
+
+	middle, err := retrier.RetryChain(ctx, chain[2].ID)
+	require.NoError(t, err)
+	assert.Equal(t, chain, middle, "any run of the chain returns the whole chain")
+
+	var retriedManual bool
+	for _, campaign := range cs.campaigns {
+		if campaign.RetryOfCampaignID != nil && *campaign.RetryOfCampaignID == manual.ID {
+			retriedManual = true
+		}
+	}
+	assert.False(t, retriedManual)
+}
+
+func TestCampaignAutoRetrier_Disabled(t *testing.T) {
+	appCfg := &config.AppConfig{CampaignRetry: config.CampaignRetryConfig{MaxRetries: -1}}
+	retrier := NewCampaignAutoRetrier(appCfg, nil, newRetryCampaignStore(), nil)
+	assert.Nil(t, retrier)
+	assert.Empty(t, retrier.RetryDue(context.Background()))
+}
+
+func TestCampaignAutoRetrier_BackoffDoublesUpToMax(t *testing.T) {
+	retrier := NewCampaignAutoRetrier(&config.AppConfig{CampaignRetry: config.CampaignRetryConfig{BackoffSeconds: 10, MaxBackoffSeconds: 35}}, nil, nil, nil)
+	failedAt := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	var delays []time.Duration
+	for retryCount := 0; retryCount < 4; retryCount++ {
+		delays = append(delays, retrier.retryAt(&models.Campaign{RetryCount: retryCount, UpdatedAt: failedAt}).Sub(failedAt))
+	}
+	assert.True(t, sort.SliceIsSorted(delays, func(i, j int) bool { return delays[i] < delays[j] }))
+	assert.Equal(t, []time.Duration{10 * time.Second, 20 * time.Second, 35 * time.Second, 35 * time.Second}, delays)
+}
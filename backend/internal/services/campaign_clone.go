@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// CampaignCloneSuffix is appended to the source campaign's name when a clone is not given one
+const CampaignCloneSuffix = " (copy)"
+
+// CampaignCloner creates pending copies of campaigns. A clone has its own copy of the source
+// campaign's settings and type-specific parameters, starts from the beginning and shares none of
+// the source campaign's results, jobs or history.
+type CampaignCloner struct {
+	db            *sqlx.DB
+	campaignStore store.CampaignStore
+	now           func() time.Time
+}
+
+// NewCampaignCloner returns a cloner writing through campaignStore
+func NewCampaignCloner(db *sqlx.DB, campaignStore store.CampaignStore) *CampaignCloner {
+	return &CampaignCloner{db: db, campaignStore: campaignStore, now: time.Now}
+}
+
+// CloneCampaign copies a campaign into a new pending campaign named name, or the source
+// campaign's name followed by CampaignCloneSuffix when name is blank. It returns store.ErrNotFound
+// when the campaign does not exist, and store.ErrCampaignNameTaken along with the clone it could
+// not create when the name is in use.
+func (c *CampaignCloner) CloneCampaign(ctx context.Context, campaignID uuid.UUID, name string) (*models.Campaign, error) {
+	var querier store.Querier
+	if c.db != nil {
+		querier = c.db
+	}
+	source, err := c.campaignStore.GetCampaignByID(ctx, querier, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load campaign %s: %w", campaignID, err)
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = source.Name + CampaignCloneSuffix
+	}
+
+	now := c.now().UTC()
+	clone := &models.Campaign{
+		ID:                 uuid.New(),
+		Name:               name,
+		CampaignType:       source.CampaignType,
+		Status:             models.CampaignStatusPending,
+		UserID:             clonedPtr(source.UserID),
+		Tags:               clonedSlice(source.Tags),
+		Metadata:           clonedRawMessage(source.Metadata),
+		TotalItems:         clonedPtr(source.TotalItems),
+		ProcessedItems:     models.Int64Ptr(0),
+		ProgressPercentage: models.Float64Ptr(0.0),
+		CreatedAt:          now,
+		UpdatedAt:          now,
+		AutoRetry:          source.AutoRetry,
+	}
+
+	if c.db != nil {
+		tx, err := c.db.BeginTxx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.createClone(ctx, tx, source, clone); err != nil {
+			tx.Rollback()
+			return cloneFailure(clone, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+	} else if err := c.createClone(ctx, nil, source, clone); err != nil {
+		return cloneFailure(clone, err)
+	}
+	return clone, nil
+}
+
+// cloneFailure returns the clone with the error when only its name kept it from being created
+func cloneFailure(clone *models.Campaign, err error) (*models.Campaign, error) {
+	if errors.Is(err, store.ErrCampaignNameTaken) {
+		return clone, err
+	}
+	return nil, err
+}
+
+// createClone writes the clone and a deep copy of the source campaign's parameters, reset to
+// start from the first domain
+func (c *CampaignCloner) createClone(ctx context.Context, exec store.Querier, source, clone *models.Campaign) error {
+	if err := c.campaignStore.CreateCampaign(ctx, exec, clone); err != nil {
+		return fmt.Errorf("failed to create cloned campaign: %w", err)
+	}
+	switch source.CampaignType {
+	case models.CampaignTypeDomainGeneration:
+		params, err := c.campaignStore.GetDomainGenerationParams(ctx, exec, source.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load domain generation params: %w", err)
+		}
+		copied := cloneDomainGenerationParams(params)
+		copied.CampaignID = clone.ID
+		copied.CurrentOffset = 0
+		clone.DomainGenerationParams = copied
+		return c.campaignStore.CreateDomainGenerationParams(ctx, exec, copied)
+	case models.CampaignTypeDNSValidation:
+		params, err := c.campaignStore.GetDNSValidationParams(ctx, exec, source.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load DNS validation params: %w", err)
+		}
+		copied := cloneDNSValidationParams(params)
+		copied.CampaignID = clone.ID
+		clone.DNSValidationParams = copied
+		return c.campaignStore.CreateDNSValidationParams(ctx, exec, copied)
+	case models.CampaignTypeHTTPKeywordValidation:
+		params, err := c.campaignStore.GetHTTPKeywordParams(ctx, exec, source.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load HTTP keyword params: %w", err)
+		}
+		copied := cloneHTTPKeywordParams(params)
+		copied.CampaignID = clone.ID
+		copied.LastProcessedDomainName = nil
+		clone.HTTPKeywordValidationParams = copied
+		return c.campaignStore.CreateHTTPKeywordParams(ctx, exec, copied)
+	}
+	return fmt.Errorf("campaign type %s cannot be cloned", source.CampaignType)
+}
+
+func cloneDomainGenerationParams(params *models.DomainGenerationCampaignParams) *models.DomainGenerationCampaignParams {
+	copied := *params
+	copied.VariableLength = clonedPtr(params.VariableLength)
+	copied.CharacterSet = clonedPtr(params.CharacterSet)
+	copied.ConstantString = clonedPtr(params.ConstantString)
+	if params.DNSPromotion != nil {
+		promotion := *params.DNSPromotion
+		promotion.PersonaIDs = clonedSlice(params.DNSPromotion.PersonaIDs)
+		promotion.ExpectedRecords = cloneDNSExpectedRecords(params.DNSPromotion.ExpectedRecords)
+		copied.DNSPromotion = &promotion
+	}
+	return &copied
+}
+
+func cloneDNSValidationParams(params *models.DNSValidationCampaignParams) *models.DNSValidationCampaignParams {
+	copied := *params
+	copied.SourceGenerationCampaignID = clonedPtr(params.SourceGenerationCampaignID)
+	copied.PersonaIDs = clonedSlice(params.PersonaIDs)
+	copied.RotationIntervalSeconds = clonedPtr(params.RotationIntervalSeconds)
+	copied.ProcessingSpeedPerMinute = clonedPtr(params.ProcessingSpeedPerMinute)
+	copied.BatchSize = clonedPtr(params.BatchSize)
+	copied.RetryAttempts = clonedPtr(params.RetryAttempts)
+	copied.ExpectedRecords = cloneDNSExpectedRecords(params.ExpectedRecords)
+	copied.Metadata = clonedRawMessage(params.Metadata)
+	return &copied
+}
+
+func cloneHTTPKeywordParams(params *models.HTTPKeywordCampaignParams) *models.HTTPKeywordCampaignParams {
+	copied := *params
+	copied.KeywordSetIDs = clonedSlice(params.KeywordSetIDs)
+	copied.PersonaIDs = clonedSlice(params.PersonaIDs)
+	if params.AdHocKeywords != nil {
+		copied.AdHocKeywords = models.StringSlicePtr(clonedSlice(*params.AdHocKeywords))
+	}
+	if params.ProxyIDs != nil {
+		copied.ProxyIDs = models.UUIDSlicePtr(clonedSlice(*params.ProxyIDs))
+	}
+	if params.TargetHTTPPorts != nil {
+		copied.TargetHTTPPorts = models.IntSlicePtr(clonedSlice(*params.TargetHTTPPorts))
+	}
+	copied.ProxySelectionStrategy = clonedPtr(params.ProxySelectionStrategy)
+	copied.RotationIntervalSeconds = clonedPtr(params.RotationIntervalSeconds)
+	copied.ProcessingSpeedPerMinute = clonedPtr(params.ProcessingSpeedPerMinute)
+	copied.BatchSize = clonedPtr(params.BatchSize)
+	copied.RetryAttempts = clonedPtr(params.RetryAttempts)
+	copied.Metadata = clonedRawMessage(params.Metadata)
+	return &copied
+}
+
+func cloneDNSExpectedRecords(records *models.DNSExpectedRecords) *models.DNSExpectedRecords {
+	if records == nil {
+		return nil
+	}
+	return &models.DNSExpectedRecords{
+		ACIDRs:        clonedSlice(records.ACIDRs),
+		AAAACIDRs:     clonedSlice(records.AAAACIDRs),
+		CNAMESuffixes: clonedSlice(records.CNAMESuffixes),
+	}
+}
+
+func clonedSlice[T any](values []T) []T {
+	if values == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(values)), values...)
+}
+
+func clonedPtr[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+func clonedRawMessage(message *json.RawMessage) *json.RawMessage {
+	if message == nil {
+		return nil
+	}
+	return models.JSONRawMessagePtr(append(json.RawMessage(nil), *message...))
+}
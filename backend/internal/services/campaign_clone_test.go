@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cloneableCampaignStore holds campaigns and their parameters in memory
+type cloneableCampaignStore struct {
+	store.CampaignStore
+	campaigns  map[uuid.UUID]*models.Campaign
+	generation map[uuid.UUID]*models.DomainGenerationCampaignParams
+	dns        map[uuid.UUID]*models.DNSValidationCampaignParams
+	http       map[uuid.UUID]*models.HTTPKeywordCampaignParams
+}
+
+func newCloneableCampaignStore() *cloneableCampaignStore {
+	return &cloneableCampaignStore{
+		campaigns:  make(map[uuid.UUID]*models.Campaign),
+		generation: make(map[uuid.UUID]*models.DomainGenerationCampaignParams),
+		dns:        make(map[uuid.UUID]*models.DNSValidationCampaignParams),
+		http:       make(map[uuid.UUID]*models.HTTPKeywordCampaignParams),
+	}
+}
+
+func (s *cloneableCampaignStore) GetCampaignByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Campaign, error) {
+	if campaign, ok := s.campaigns[id]; ok {
+		return campaign, nil
+	}
+	return nil, store.ErrNotFound
+}
+
+func (s *cloneableCampaignStore) CreateCampaign(ctx context.Context, exec store.Querier, campaign *models.Campaign) error {
+	for _, existing := range s.campaigns {
+		if existing.Name == campaign.Name {
+			return store.ErrCampaignNameTaken
+		}
+	}
+	s.campaigns[campaign.ID] = campaign
+	return nil
+}
+
+func (s *cloneableCampaignStore) GetDomainGenerationParams(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.DomainGenerationCampaignParams, error) {
+	return s.generation[campaignID], nil
+}
+
+func (s *cloneableCampaignStore) CreateDomainGenerationParams(ctx context.Context, exec store.Querier, params *models.DomainGenerationCampaignParams) error {
+	s.generation[params.CampaignID] = params
+	return nil
+}
+
+func (s *cloneableCampaignStore) GetDNSValidationParams(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.DNSValidationCampaignParams, error) {
+	return s.dns[campaignID], nil
+}
+
+func (s *cloneableCampaignStore) CreateDNSValidationParams(ctx context.Context, exec store.Querier, params *models.DNSValidationCampaignParams) error {
+	s.dns[params.CampaignID] = params
+	return nil
+}
+
+func (s *cloneableCampaignStore) GetHTTPKeywordParams(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.HTTPKeywordCampaignParams, error) {
+	return s.http[campaignID], nil
+}
+
+func (s *cloneableCampaignStore) CreateHTTPKeywordParams(ctx context.Context, exec store.Querier, params *models.HTTPKeywordCampaignParams) error {
+	s.http[params.CampaignID] = params
+	return nil
+}
+
+// finishedCampaign returns a completed campaign of the given type, as a clone source
+func finishedCampaign(cs *cloneableCampaignStore, campaignType models.CampaignTypeEnum, name string) *models.Campaign {
+	owner := uuid.New()
+	startedAt, completedAt := time.Now().Add(-time.Hour), time.Now()
+	campaign := &models.Campaign{
+		ID: uuid.New(), Name: name, CampaignType: campaignType, Status: models.CampaignStatusCompleted,
+		UserID: &owner, StartedAt: &startedAt, CompletedAt: &completedAt,
+		TotalItems: models.Int64Ptr(100), ProcessedItems: models.Int64Ptr(100), SuccessfulItems: models.Int64Ptr(80),
+		ProgressPercentage: models.Float64Ptr(100), Tags: []string{"client-a"}, AutoRetry: true, RetryCount: 2,
+	}
+	cs.campaigns[campaign.ID] = campaign
+	return campaign
+}
+
+func TestCloneCampaign_DomainGeneration(t *testing.T) {
+	cs := newCloneableCampaignStore()
+	source := finishedCampaign(cs, models.CampaignTypeDomainGeneration, "Brand sweep")
+	length := 4
+	cs.generation[source.ID] = &models.DomainGenerationCampaignParams{
+		CampaignID: source.ID, PatternType: "prefix", VariableLength: &length, CharacterSet: models.StringPtr("abc"),
+		ConstantString: models.StringPtr("shop"), TLD: ".com", NumDomainsToGenerate: 100,
+		TotalPossibleCombinations: 81, CurrentOffset: 81,
+		DNSPromotion: &models.DNSPromotion{PersonaIDs: []uuid.UUID{uuid.New()}},
+	}
+
+	clone, err := NewCampaignCloner(nil, cs).CloneCampaign(context.Background(), source.ID, "")
+	require.NoError(t, err)
+	assert.NotEqual(t, source.ID, clone.ID)
+	assert.Equal(t, "Brand sweep (copy)", clone.Name)
+	assert.Equal(t, models.CampaignStatusPending, clone.Status)
+	assert.Equal(t, *source.UserID, *clone.UserID)
+	assert.Equal(t, []string{"client-a"}, []string(clone.Tags))
+	assert.True(t, clone.AutoRetry)
+	assert.Zero(t, clone.RetryCount)
+	assert.Nil(t, clone.StartedAt)
+	assert.Nil(t, clone.CompletedAt)
+	assert.Nil(t, clone.SuccessfulItems)
+	assert.Equal(t, int64(0), *clone.ProcessedItems)
+	assert.Equal(t, 0.0, *clone.ProgressPercentage)
+
+	params := cs.generation[clone.ID]
+	require.NotNil(t, params)
+	assert.Same(t, params, clone.DomainGenerationParams)
+	assert.Equal(t, clone.ID, params.CampaignID)
+	assert.Zero(t, params.CurrentOffset, "the clone generates from the start")
+	assert.Equal(t, "shop", *params.ConstantString)
+	assert.Equal(t, cs.generation[source.ID].DNSPromotion.PersonaIDs, params.DNSPromotion.PersonaIDs)
+
+	*params.VariableLength = 6
+	params.DNSPromotion.PersonaIDs[0] = uuid.New()
+	assert.Equal(t, 4, *cs.generation[source.ID].VariableLength, "the copy shares nothing with the source")
+	assert.NotEqual(t, params.DNSPromotion.PersonaIDs[0], cs.generation[source.ID].DNSPromotion.PersonaIDs[0])
+	assert.Equal(t, int64(81), cs.generation[source.ID].CurrentOffset)
+}
+
+func TestCloneCampaign_DNSValidation(t *testing.T) {
+	cs := newCloneableCampaignStore()
+	source := finishedCampaign(cs, models.CampaignTypeDNSValidation, "Resolve sweep")
+	sourceGeneration, batchSize := uuid.New(), 50
+	cs.dns[source.ID] = &models.DNSValidationCampaignParams{
+		CampaignID: source.ID, SourceGenerationCampaignID: &sourceGeneration, PersonaIDs: []uuid.UUID{uuid.New(), uuid.New()},
+		BatchSize: &batchSize, ConsensusMode: "majority", ConsensusQuorum: 2,
+		ExpectedRecords: &models.DNSExpectedRecords{ACIDRs: []string{"192.0.2.0/24"}},
+	}
+
+	clone, err := NewCampaignCloner(nil, cs).CloneCampaign(context.Background(), source.ID, "  Resolve again  ")
+	require.NoError(t, err)
+	assert.Equal(t, "Resolve again", clone.Name)
+	assert.Equal(t, models.CampaignStatusPending, clone.Status)
+
+	params := cs.dns[clone.ID]
+	require.NotNil(t, params)
+	assert.Equal(t, clone.ID, params.CampaignID)
+	assert.Equal(t, sourceGeneration, *params.SourceGenerationCampaignID)
+	assert.Equal(t, cs.dns[source.ID].PersonaIDs, params.PersonaIDs)
+	assert.Equal(t, 50, *params.BatchSize)
+	assert.Equal(t, "majority", params.ConsensusMode)
+	assert.Equal(t, []string{"192.0.2.0/24"}, params.ExpectedRecords.ACIDRs)
+
+	params.PersonaIDs[0] = uuid.New()
+	params.ExpectedRecords.ACIDRs[0] = "198.51.100.0/24"
+	*params.BatchSize = 10
+	assert.NotEqual(t, params.PersonaIDs[0], cs.dns[source.ID].PersonaIDs[0], "the copy shares nothing with the source")
+	assert.Equal(t, "192.0.2.0/24", cs.dns[source.ID].ExpectedRecords.ACIDRs[0])
+	assert.Equal(t, 50, *cs.dns[source.ID].BatchSize)
+}
+
+func TestCloneCampaign_HTTPKeyword(t *testing.T) {
+	cs := newCloneableCampaignStore()
+	source := finishedCampaign(cs, models.CampaignTypeHTTPKeywordValidation, "Keyword sweep")
+	metadata := json.RawMessage(`{"note":"weekly"}`)
+	cs.http[source.ID] = &models.HTTPKeywordCampaignParams{
+		CampaignID: source.ID, SourceCampaignID: uuid.New(), SourceType: "DNSValidation",
+		KeywordSetIDs: []uuid.UUID{uuid.New()}, AdHocKeywords: &[]string{"casino"}, PersonaIDs: []uuid.UUID{uuid.New()},
+		ProxyIDs: &[]uuid.UUID{uuid.New()}, ProxySelectionStrategy: models.StringPtr(ProxySelectionRandom),
+		TargetHTTPPorts: &[]int{80, 443}, LastProcessedDomainName: models.StringPtr("zeta.com"),
+		ValidationMode: "keywords", MatchType: models.KeywordMatchTypeSubstring, Metadata: &metadata,
+	}
+
+	clone, err := NewCampaignCloner(nil, cs).CloneCampaign(context.Background(), source.ID, "")
+	require.NoError(t, err)
+	params := cs.http[clone.ID]
+	require.NotNil(t, params)
+	assert.Equal(t, clone.ID, params.CampaignID)
+	assert.Nil(t, params.LastProcessedDomainName, "the clone starts from the first source domain")
+	assert.Equal(t, cs.http[source.ID].SourceCampaignID, params.SourceCampaignID)
+	assert.Equal(t, cs.http[source.ID].KeywordSetIDs, params.KeywordSetIDs)
+	assert.Equal(t, []string{"casino"}, *params.AdHocKeywords)
+	assert.Equal(t, ProxySelectionRandom, *params.ProxySelectionStrategy)
+	assert.Equal(t, []int{80, 443}, *params.TargetHTTPPorts)
+	assert.JSONEq(t, `{"note":"weekly"}`, string(*params.Metadata))
+
+	(*params.AdHocKeywords)[0] = "poker"
+	(*params.TargetHTTPPorts)[0] = 8080
+	params.KeywordSetIDs[0] = uuid.New()
+	assert.Equal(t, "casino", (*cs.http[source.ID].AdHocKeywords)[0], "the copy shares nothing with the source")
+	assert.Equal(t, 80, (*cs.http[source.ID].TargetHTTPPorts)[0])
+	assert.NotEqual(t, params.KeywordSetIDs[0], cs.http[source.ID].KeywordSetIDs[0])
+	assert.Equal(t, "zeta.com", *cs.http[source.ID].LastProcessedDomainName)
+}
+
+func TestCloneCampaign_Errors(t *testing.T) {
+	cs := newCloneableCampaignStore()
+	cloner := NewCampaignCloner(nil, cs)
+
+	_, err := cloner.CloneCampaign(context.Background(), uuid.New(), "")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	source := finishedCampaign(cs, models.CampaignTypeDNSValidation, "Resolve sweep")
+	cs.dns[source.ID] = &models.DNSValidationCampaignParams{CampaignID: source.ID, PersonaIDs: []uuid.UUID{uuid.New()}}
+	clone, err := cloner.CloneCampaign(context.Background(), source.ID, "Resolve sweep")
+	assert.ErrorIs(t, err, store.ErrCampaignNameTaken)
+	require.NotNil(t, clone, "the clone that was not created names the taken name")
+	assert.Equal(t, "Resolve sweep", clone.Name)
+	assert.Len(t, cs.campaigns, 1)
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Kinds of difference between the results of two compared campaigns
+const (
+	CampaignDiffOnlyLeft      = "only_left"      // Only the left campaign has a result for the domain
+	CampaignDiffOnlyRight     = "only_right"     // Only the right campaign has a result for the domain
+	CampaignDiffStatusChanged = "status_changed" // Both have a result, with different statuses
+)
+
+// Limits on the differences listed in a campaign comparison
+const (
+	DefaultCompareDifferenceLimit = 100
+	MaxCompareDifferenceLimit     = 1000
+)
+
+// ErrCampaignsNotComparable is returned when two campaigns do not hold results of the same kind
+var ErrCampaignsNotComparable = errors.New("campaigns are not comparable")
+
+// CampaignResultDiff is one domain whose result differs between two campaigns
+type CampaignResultDiff struct {
+	DomainName  string `json:"domainName"`
+	Kind        string `json:"kind"`
+	LeftStatus  string `json:"leftStatus,omitempty"`
+	RightStatus string `json:"rightStatus,omitempty"`
+}
+
+// CampaignCompareCounts tallies the domains seen while merging two campaigns' results
+type CampaignCompareCounts struct {
+	LeftTotal     int64 `json:"leftTotal"`
+	RightTotal    int64 `json:"rightTotal"`
+	Unchanged     int64 `json:"unchanged"`
+	OnlyLeft      int64 `json:"onlyLeft"`
+	OnlyRight     int64 `json:"onlyRight"`
+	StatusChanged int64 `json:"statusChanged"`
+}
+
+// CampaignComparison is the diff of two campaigns' results. Counts cover every domain; Differences
+// lists the first of them in domain order.
+type CampaignComparison struct {
+	LeftCampaignID  uuid.UUID               `json:"leftCampaignId"`
+	RightCampaignID uuid.UUID               `json:"rightCampaignId"`
+	CampaignType    models.CampaignTypeEnum `json:"campaignType"`
+	Counts          CampaignCompareCounts   `json:"counts"`
+	Differences     []CampaignResultDiff    `json:"differences"`
+	Truncated       bool                    `json:"truncated"` // More differences exist than are listed
+}
+
+// CampaignResultPager returns up to limit results whose domain sorts after afterDomain, in
+// ascending byte order of the domain name
+type CampaignResultPager func(ctx context.Context, afterDomain string, limit int) ([]store.CampaignResultStatus, error)
+
+// MergeCampaignResults diffs two campaigns' results with a merge join over their pagers, calling
+// emit for each domain that differs. Each side reads pages of bufferSize results in its own
+// goroutine, one page ahead of the merge, so no more than a few pages per side are held at once.
+func MergeCampaignResults(ctx context.Context, left, right CampaignResultPager, bufferSize int, emit func(CampaignResultDiff) error) (CampaignCompareCounts, error) {
+	var counts CampaignCompareCounts
+	if bufferSize <= 0 {
+		bufferSize = config.DefaultCompareBufferSize
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	leftStream := newResultStream(ctx, left, bufferSize)
+	rightStream := newResultStream(ctx, right, bufferSize)
+
+	l, err := leftStream.next()
+	if err != nil {
+		return counts, fmt.Errorf("failed to read left campaign results: %w", err)
+	}
+	r, err := rightStream.next()
+	if err != nil {
+		return counts, fmt.Errorf("failed to read right campaign results: %w", err)
+	}
+	for l != nil || r != nil {
+		var diff *CampaignResultDiff
+		advanceLeft, advanceRight := false, false
+		switch {
+		case r == nil || (l != nil && l.DomainName < r.DomainName):
+			counts.LeftTotal++
+			counts.OnlyLeft++
+			diff = &CampaignResultDiff{DomainName: l.DomainName, Kind: CampaignDiffOnlyLeft, LeftStatus: l.ValidationStatus}
+			advanceLeft = true
+		case l == nil || r.DomainName < l.DomainName:
+			counts.RightTotal++
+			counts.OnlyRight++
+			diff = &CampaignResultDiff{DomainName: r.DomainName, Kind: CampaignDiffOnlyRight, RightStatus: r.ValidationStatus}
+			advanceRight = true
+		default:
+			counts.LeftTotal++
+			counts.RightTotal++
+			if l.ValidationStatus == r.ValidationStatus {
+				counts.Unchanged++
+			} else {
+				counts.StatusChanged++
+				diff = &CampaignResultDiff{DomainName: l.DomainName, Kind: CampaignDiffStatusChanged,
+					LeftStatus: l.ValidationStatus, RightStatus: r.ValidationStatus}
+			}
+			advanceLeft, advanceRight = true, true
+		}
+		if diff != nil && emit != nil {
+			if err := emit(*diff); err != nil {
+				return counts, err
+			}
+		}
+		if advanceLeft {
+			if l, err = leftStream.next(); err != nil {
+				return counts, fmt.Errorf("failed to read left campaign results: %w", err)
+			}
+		}
+		if advanceRight {
+			if r, err = rightStream.next(); err != nil {
+				return counts, fmt.Errorf("failed to read right campaign results: %w", err)
+			}
+		}
+	}
+	return counts, nil
+}
+
+type resultPage struct {
+	results []store.CampaignResultStatus
+	err     error
+}
+
+// resultStream reads one campaign's results page by page ahead of the merge
+type resultStream struct {
+	ctx     context.Context
+	pages   chan resultPage
+	current []store.CampaignResultStatus
+	pos     int
+	last    string
+	started bool
+}
+
+func newResultStream(ctx context.Context, pager CampaignResultPager, pageSize int) *resultStream {
+	s := &resultStream{ctx: ctx, pages: make(chan resultPage, 1)}
+	go func() {
+		defer close(s.pages)
+		after := ""
+		for {
+			results, err := pager(ctx, after, pageSize)
+			select {
+			case s.pages <- resultPage{results: results, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil || len(results) < pageSize {
+				return
+			}
+			after = results[len(results)-1].DomainName
+		}
+	}()
+	return s
+}
+
+// next returns the stream's next result, or nil once it is exhausted. Results must arrive in
+// strictly ascending domain order, or the merge would pair the wrong domains.
+func (s *resultStream) next() (*store.CampaignResultStatus, error) {
+	for s.pos >= len(s.current) {
+		page, ok := <-s.pages
+		if !ok {
+			return nil, s.ctx.Err()
+		}
+		if page.err != nil {
+			return nil, page.err
+		}
+		if len(page.results) == 0 {
+			continue
+		}
+		s.current, s.pos = page.results, 0
+	}
+	result := &s.current[s.pos]
+	s.pos++
+	if s.started && result.DomainName <= s.last {
+		return nil, fmt.Errorf("results out of domain order: %q after %q", result.DomainName, s.last)
+	}
+	s.last, s.started = result.DomainName, true
+	return result, nil
+}
+
+// CampaignCompareService diffs the results of two validation campaigns of the same type
+type CampaignCompareService struct {
+	db            *sqlx.DB
+	campaignStore store.CampaignStore
+	bufferSize    int
+}
+
+// NewCampaignCompareService reads server.compareBufferSize results per page from each campaign
+func NewCampaignCompareService(appCfg *config.AppConfig, db *sqlx.DB, cs store.CampaignStore) *CampaignCompareService {
+	bufferSize := config.DefaultCompareBufferSize
+	if appCfg != nil && appCfg.Server.CompareBufferSize > 0 {
+		bufferSize = appCfg.Server.CompareBufferSize
+	}
+	return &CampaignCompareService{db: db, campaignStore: cs, bufferSize: bufferSize}
+}
+
+// CompareCampaigns diffs the results of two DNS or two HTTP keyword campaigns, listing up to
+// differenceLimit differences. It returns store.ErrNotFound when either campaign does not exist
+// and ErrCampaignsNotComparable when they are not validation campaigns of the same type.
+func (s *CampaignCompareService) CompareCampaigns(ctx context.Context, leftID, rightID uuid.UUID, differenceLimit int) (*CampaignComparison, error) {
+	var querier store.Querier
+	if s.db != nil {
+		querier = s.db
+	}
+	left, err := s.campaignStore.GetCampaignByID(ctx, querier, leftID)
+	if err != nil {
+		return nil, err
+	}
+	right, err := s.campaignStore.GetCampaignByID(ctx, querier, rightID)
+	if err != nil {
+		return nil, err
+	}
+	if left.CampaignType != right.CampaignType {
+		return nil, fmt.Errorf("%w: %s campaign compared with %s campaign", ErrCampaignsNotComparable, left.CampaignType, right.CampaignType)
+	}
+	if differenceLimit <= 0 {
+		differenceLimit = DefaultCompareDifferenceLimit
+	} else if differenceLimit > MaxCompareDifferenceLimit {
+		differenceLimit = MaxCompareDifferenceLimit
+	}
+
+	var list func(context.Context, store.Querier, uuid.UUID, string, int) ([]store.CampaignResultStatus, error)
+	switch left.CampaignType {
+	case models.CampaignTypeDNSValidation:
+		list = s.campaignStore.ListDNSValidationStatusesAfter
+	case models.CampaignTypeHTTPKeywordValidation:
+		list = s.campaignStore.ListHTTPKeywordStatusesAfter
+	default:
+		return nil, fmt.Errorf("%w: %s campaigns have no validation results", ErrCampaignsNotComparable, left.CampaignType)
+	}
+	pager := func(campaignID uuid.UUID) CampaignResultPager {
+		return func(ctx context.Context, afterDomain string, limit int) ([]store.CampaignResultStatus, error) {
+			return list(ctx, querier, campaignID, afterDomain, limit)
+		}
+	}
+
+	comparison := &CampaignComparison{
+		LeftCampaignID:  leftID,
+		RightCampaignID: rightID,
+		CampaignType:    left.CampaignType,
+		Differences:     []CampaignResultDiff{},
+	}
+	comparison.Counts, err = MergeCampaignResults(ctx, pager(leftID), pager(rightID), s.bufferSize, func(diff CampaignResultDiff) error {
+		if len(comparison.Differences) < differenceLimit {
+			comparison.Differences = append(comparison.Differences, diff)
+		} else {
+			comparison.Truncated = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare campaigns %s and %s: %w", leftID, rightID, err)
+	}
+	return comparison, nil
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slicePager pages through results held in memory, sorted by domain
+func slicePager(results []store.CampaignResultStatus) CampaignResultPager {
+	sort.Slice(results, func(i, j int) bool { return results[i].DomainName < results[j].DomainName })
+	return func(ctx context.Context, afterDomain string, limit int) ([]store.CampaignResultStatus, error) {
+		start := sort.Search(len(results), func(i int) bool { return results[i].DomainName > afterDomain })
+		end := start + limit
+		if end > len(results) {
+			end = len(results)
+		}
+		return results[start:end], nil
+	}
+}
+
+// syntheticCampaign generates n domains on demand, skipping those for which skip is true, so that a
+// large campaign never exists in memory. It counts the rows it has handed out.
+type syntheticCampaign struct {
+	n       int
+	skip    func(i int) bool
+	status  func(i int) string
+	served  atomic.Int64
+	maxPage atomic.Int64
+}
+
+func syntheticDomain(i int) string { return fmt.Sprintf("domain-%08d.com", i) }
+
+func (s *syntheticCampaign) pager(ctx context.Context, afterDomain string, limit int) ([]store.CampaignResultStatus, error) {
+	i := 0
+	if afterDomain != "" {
+		if _, err := fmt.Sscanf(afterDomain, "domain-%08d.com", &i); err != nil {
+			return nil, err
+		}
+		i++
+	}
+	page := make([]store.CampaignResultStatus, 0, limit)
+	for ; i < s.n && len(page) < limit; i++ {
+		if !s.skip(i) {
+			page = append(page, store.CampaignResultStatus{DomainName: syntheticDomain(i), ValidationStatus: s.status(i)})
+		}
+	}
+	s.served.Add(int64(len(page)))
+	if int64(len(page)) > s.maxPage.Load() {
+		s.maxPage.Store(int64(len(page)))
+	}
+	return page, nil
+}
+
+func TestMergeCampaignResults_Diff(t *testing.T) {
+	left := []store.CampaignResultStatus{
+		{DomainName: "b.com", ValidationStatus: "resolved"},
+		{DomainName: "a.com", ValidationStatus: "resolved"},
+		{DomainName: "d.com", ValidationStatus: "unresolved"},
+		{DomainName: "e.com", ValidationStatus: "resolved"},
+		{DomainName: "z.com", ValidationStatus: "resolved"},
+	}
+	right := []store.CampaignResultStatus{
+		{DomainName: "a.com", ValidationStatus: "resolved"},
+		{DomainName: "c.com", ValidationStatus: "resolved"},
+		{DomainName: "d.com", ValidationStatus: "resolved"},
+		{DomainName: "e.com", ValidationStatus: "resolved"},
+		{DomainName: "f.com", ValidationStatus: "error"},
+	}
+
+	for _, bufferSize := range []int{1, 2, 3, 100} {
+		t.Run(fmt.Sprintf("buffer %d", bufferSize), func(t *testing.T) {
+			var diffs []CampaignResultDiff
+			counts, err := MergeCampaignResults(context.Background(), slicePager(left), slicePager(right), bufferSize, func(diff CampaignResultDiff) error {
+				diffs = append(diffs, diff)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, CampaignCompareCounts{LeftTotal: 5, RightTotal: 5, Unchanged: 2, OnlyLeft: 2, OnlyRight: 2, StatusChanged: 1}, counts)
+			assert.Equal(t, []CampaignResultDiff{
+				{DomainName: "b.com", Kind: CampaignDiffOnlyLeft, LeftStatus: "resolved"},
+				{DomainName: "c.com", Kind: CampaignDiffOnlyRight, RightStatus: "resolved"},
+				{DomainName: "d.com", Kind: CampaignDiffStatusChanged, LeftStatus: "unresolved", RightStatus: "resolved"},
+				{DomainName: "f.com", Kind: CampaignDiffOnlyRight, RightStatus: "error"},
+				{DomainName: "z.com", Kind: CampaignDiffOnlyLeft, LeftStatus: "resolved"},
+			}, diffs)
+		})
+	}
+}
+
+func TestMergeCampaignResults_OneSideEmpty(t *testing.T) {
+	results := []store.CampaignResultStatus{{DomainName: "a.com", ValidationStatus: "resolved"}, {DomainName: "b.com", ValidationStatus: "resolved"}}
+
+	counts, err := MergeCampaignResults(context.Background(), slicePager(results), slicePager(nil), 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, CampaignCompareCounts{LeftTotal: 2, OnlyLeft: 2}, counts)
+
+	counts, err = MergeCampaignResults(context.Background(), slicePager(nil), slicePager(results), 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, CampaignCompareCounts{RightTotal: 2, OnlyRight: 2}, counts)
+}
+
+func TestMergeCampaignResults_LargeCampaignsStayBounded(t *testing.T) {
+	const n, bufferSize = 200000, 250
+	left := &syntheticCampaign{n: n, skip: func(i int) bool { return i%7 == 0 }, status: func(i int) string { return "resolved" }}
+	right := &syntheticCampaign{n: n, skip: func(i int) bool { return i%11 == 0 }, status: func(i int) string {
+		if i%5 == 0 {
+			return "unresolved"
+		}
+		return "resolved"
+	}}
+
+	var want CampaignCompareCounts
+	for i := 0; i < n; i++ {
+		inLeft, inRight := !left.skip(i), !right.skip(i)
+		switch {
+		case inLeft && inRight && left.status(i) == right.status(i):
+			want.Unchanged++
+		case inLeft && inRight:
+			want.StatusChanged++
+		case inLeft:
+			want.OnlyLeft++
+		case inRight:
+			want.OnlyRight++
+		}
+	}
+	want.LeftTotal = want.Unchanged + want.StatusChanged + want.OnlyLeft
+	want.RightTotal = want.Unchanged + want.StatusChanged + want.OnlyRight
+
+	// Rows a side may have read ahead of the merge: the page being merged, the page waiting in
+	// the channel and the page its reader is holding until there is room
+	maxLead := int64(3 * bufferSize)
+	var worstLead int64
+	counts, err := MergeCampaignResults(context.Background(), left.pager, right.pager, bufferSize, func(diff CampaignResultDiff) error {
+		var i int
+		_, err := fmt.Sscanf(diff.DomainName, "domain-%08d.com", &i)
+		require.NoError(t, err)
+		leftMerged := int64(i + 1 - (i/7 + 1))
+		rightMerged := int64(i + 1 - (i/11 + 1))
+		for _, lead := range []int64{left.served.Load() - leftMerged, right.served.Load() - rightMerged} {
+			if lead > worstLead {
+				worstLead = lead
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, want, counts)
+	assert.LessOrEqual(t, worstLead, maxLead, "a side read too far ahead of the merge")
+	assert.Equal(t, int64(bufferSize), left.maxPage.Load())
+	assert.Equal(t, int64(bufferSize), right.maxPage.Load())
+}
+
+func TestMergeCampaignResults_Errors(t *testing.T) {
+	good := slicePager([]store.CampaignResultStatus{{DomainName: "a.com"}, {DomainName: "b.com"}})
+	failing := func(ctx context.Context, afterDomain string, limit int) ([]store.CampaignResultStatus, error) {
+		if afterDomain != "" {
+			return nil, errors.New("connection reset")
+		}
+		return []store.CampaignResultStatus{{DomainName: "a.com"}}, nil
+	}
+	_, err := MergeCampaignResults(context.Background(), good, failing, 1, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "right campaign results: connection reset")
+
+	unordered := func(ctx context.Context, afterDomain string, limit int) ([]store.CampaignResultStatus, error) {
+		if afterDomain != "" {
+			return nil, nil
+		}
+		return []store.CampaignResultStatus{{DomainName: "b.com"}, {DomainName: "a.com"}}, nil
+	}
+	_, err = MergeCampaignResults(context.Background(), unordered, good, 2, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "out of domain order")
+
+	stop := errors.New("stop")
+	_, err = MergeCampaignResults(context.Background(), good, slicePager(nil), 1, func(CampaignResultDiff) error { return stop })
+	assert.ErrorIs(t, err, stop)
+}
+
+// compareCampaignStore holds campaigns and their DNS and HTTP keyword result statuses
+type compareCampaignStore struct {
+	campaignLookupStore
+	results map[uuid.UUID][]store.CampaignResultStatus
+	mu      sync.Mutex // Both sides of a comparison are read at once
+	limits  []int
+}
+
+func (s *compareCampaignStore) list(ctx context.Context, campaignID uuid.UUID, afterDomain string, limit int) ([]store.CampaignResultStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits = append(s.limits, limit)
+	return slicePager(s.results[campaignID])(ctx, afterDomain, limit)
+}
+
+func (s *compareCampaignStore) ListDNSValidationStatusesAfter(ctx context.Context, exec store.Querier, campaignID uuid.UUID, afterDomain string, limit int) ([]store.CampaignResultStatus, error) {
+	return s.list(ctx, campaignID, afterDomain, limit)
+}
+
+func (s *compareCampaignStore) ListHTTPKeywordStatusesAfter(ctx context.Context, exec store.Querier, campaignID uuid.UUID, afterDomain string, limit int) ([]store.CampaignResultStatus, error) {
+	return s.list(ctx, campaignID, afterDomain, limit)
+}
+
+func TestCompareCampaigns(t *testing.T) {
+	ctx := context.Background()
+	leftID, rightID, genID := uuid.New(), uuid.New(), uuid.New()
+	cs := &compareCampaignStore{
+		campaignLookupStore: campaignLookupStore{campaigns: map[uuid.UUID]*models.Campaign{
+			leftID:  {ID: leftID, CampaignType: models.CampaignTypeDNSValidation},
+			rightID: {ID: rightID, CampaignType: models.CampaignTypeDNSValidation},
+			genID:   {ID: genID, CampaignType: models.CampaignTypeDomainGeneration},
+		}},
+		results: map[uuid.UUID][]store.CampaignResultStatus{
+			leftID:  {{DomainName: "a.com", ValidationStatus: "resolved"}, {DomainName: "b.com", ValidationStatus: "resolved"}, {DomainName: "c.com", ValidationStatus: "resolved"}},
+			rightID: {{DomainName: "a.com", ValidationStatus: "unresolved"}, {DomainName: "d.com", ValidationStatus: "resolved"}},
+		},
+	}
+	appCfg := &config.AppConfig{Server: config.ServerConfig{CompareBufferSize: 2}}
+	svc := NewCampaignCompareService(appCfg, nil, cs)
+
+	comparison, err := svc.CompareCampaigns(ctx, leftID, rightID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, models.CampaignTypeDNSValidation, comparison.CampaignType)
+	assert.Equal(t, CampaignCompareCounts{LeftTotal: 3, RightTotal: 2, OnlyLeft: 2, OnlyRight: 1, StatusChanged: 1}, comparison.Counts)
+	assert.Equal(t, []CampaignResultDiff{
+		{DomainName: "a.com", Kind: CampaignDiffStatusChanged, LeftStatus: "resolved", RightStatus: "unresolved"},
+		{DomainName: "b.com", Kind: CampaignDiffOnlyLeft, LeftStatus: "resolved"},
+	}, comparison.Differences)
+	assert.True(t, comparison.Truncated)
+	for _, limit := range cs.limits {
+		assert.Equal(t, 2, limit, "pages are read at the configured buffer size")
+	}
+
+	_, err = svc.CompareCampaigns(ctx, leftID, genID, 0)
+	assert.ErrorIs(t, err, ErrCampaignsNotComparable)
+	_, err = svc.CompareCampaigns(ctx, genID, genID, 0)
+	assert.ErrorIs(t, err, ErrCampaignsNotComparable)
+	_, err = svc.CompareCampaigns(ctx, leftID, uuid.New(), 0)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
+
+func BenchmarkMergeCampaignResults(b *testing.B) {
+	const n = 100000
+	for _, bufferSize := range []int{100, config.DefaultCompareBufferSize, 5000} {
+		b.Run(fmt.Sprintf("buffer %d", bufferSize), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				left := &syntheticCampaign{n: n, skip: func(i int) bool { return i%7 == 0 }, status: func(i int) string { return "resolved" }}
+				right := &syntheticCampaign{n: n, skip: func(i int) bool { return i%11 == 0 }, status: func(i int) string { return "resolved" }}
+				if _, err := MergeCampaignResults(context.Background(), left.pager, right.pager, bufferSize, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
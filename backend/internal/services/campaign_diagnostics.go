@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Limits on the result errors included in campaign diagnostics
+const (
+	DefaultDiagnosticsErrorLimit = 5
+	MaxDiagnosticsErrorLimit     = 50
+)
+
+// CampaignDiagnostics gathers what is needed to tell why a campaign is not making progress
+type CampaignDiagnostics struct {
+	CampaignID         uuid.UUID                     `json:"campaignId"`
+	CampaignType       models.CampaignTypeEnum       `json:"campaignType"`
+	Status             models.CampaignStatusEnum     `json:"status"`
+	ProgressPercentage *float64                      `json:"progressPercentage,omitempty"`
+	TotalItems         *int64                        `json:"totalItems,omitempty"`
+	ProcessedItems     *int64                        `json:"processedItems,omitempty"`
+	ErrorMessage       *string                       `json:"errorMessage,omitempty"`
+	UpdatedAt          time.Time                     `json:"updatedAt"`
+	Stalled            bool                          `json:"stalled"`
+	StalledReason      string                        `json:"stalledReason,omitempty"`
+	StallWindowSeconds int                           `json:"stallWindowSeconds"`
+	LatestJob          *CampaignJobHistoryEntry      `json:"latestJob,omitempty"`
+	Position           CampaignDiagnosticsPosition   `json:"position"`
+	Personas           []CampaignResourceDiagnostics `json:"personas"`
+	Proxies            []CampaignResourceDiagnostics `json:"proxies"`
+	RecentErrors       []store.CampaignResultError   `json:"recentErrors"`
+	CheckedAt          time.Time                     `json:"checkedAt"`
+}
+
+// CampaignDiagnosticsPosition is where a campaign resumes from on its next batch. DNS validation
+// has no pointer; each batch takes source domains that have no result yet.
+type CampaignDiagnosticsPosition struct {
+	CurrentOffset           *int64     `json:"currentOffset,omitempty"`           // Domain generation offset
+	TargetItems             *int64     `json:"targetItems,omitempty"`             // Domains to generate
+	LastProcessedDomainName *string    `json:"lastProcessedDomainName,omitempty"` // HTTP keyword resume pointer
+	SourceCampaignID        *uuid.UUID `json:"sourceCampaignId,omitempty"`
+}
+
+// CampaignResourceDiagnostics is the last known health of a persona or proxy a campaign uses.
+// Healthy is left out when nothing has tested the resource yet.
+type CampaignResourceDiagnostics struct {
+	ID            uuid.UUID  `json:"id"`
+	Name          string     `json:"name,omitempty"`
+	Missing       bool       `json:"missing,omitempty"` // Referenced by the campaign but no longer exists
+	Enabled       bool       `json:"enabled"`
+	Healthy       *bool      `json:"healthy,omitempty"`
+	LatencyMs     *int64     `json:"latencyMs,omitempty"`
+	LastCheckedAt *time.Time `json:"lastCheckedAt,omitempty"`
+	Error         string     `json:"error,omitempty"`
+}
+
+// CampaignDiagnosticsService answers campaign diagnostics from the campaign, job, persona and proxy
+// stores. Persona health comes from the scheduled persona tests when they run.
+type CampaignDiagnosticsService struct {
+	db               *sqlx.DB
+	campaignStore    store.CampaignStore
+	campaignJobStore store.CampaignJobStore
+	personaStore     store.PersonaStore
+	proxyStore       store.ProxyStore
+	personaTests     *PersonaTestScheduler
+	stallWindow      time.Duration
+	now              func() time.Time
+}
+
+// NewCampaignDiagnosticsService reports active campaigns as stalled once nothing has happened to
+// them or their jobs for worker.healthStallWindowSeconds. personaTests may be nil.
+func NewCampaignDiagnosticsService(appCfg *config.AppConfig, db *sqlx.DB, cs store.CampaignStore, cjs store.CampaignJobStore,
+	ps store.PersonaStore, pxs store.ProxyStore, personaTests *PersonaTestScheduler) *CampaignDiagnosticsService {
+	stallWindow := workerHealthStallWindowDefault
+	if appCfg != nil && appCfg.Worker.HealthStallWindowSeconds > 0 {
+		stallWindow = time.Duration(appCfg.Worker.HealthStallWindowSeconds) * time.Second
+	}
+	return &CampaignDiagnosticsService{
+		db:               db,
+		campaignStore:    cs,
+		campaignJobStore: cjs,
+		personaStore:     ps,
+		proxyStore:       pxs,
+		personaTests:     personaTests,
+		stallWindow:      stallWindow,
+		now:              time.Now,
+	}
+}
+
+// GetCampaignDiagnostics gathers a campaign's diagnostics with up to errorLimit recent result
+// errors. It returns store.ErrNotFound when the campaign does not exist.
+func (s *CampaignDiagnosticsService) GetCampaignDiagnostics(ctx context.Context, campaignID uuid.UUID, errorLimit int) (*CampaignDiagnostics, error) {
+	var querier store.Querier
+	if s.db != nil {
+		querier = s.db
+	}
+	campaign, err := s.campaignStore.GetCampaignByID(ctx, querier, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if errorLimit <= 0 {
+		errorLimit = DefaultDiagnosticsErrorLimit
+	} else if errorLimit > MaxDiagnosticsErrorLimit {
+		errorLimit = MaxDiagnosticsErrorLimit
+	}
+
+	now := s.now().UTC()
+	diag := &CampaignDiagnostics{
+		CampaignID:         campaign.ID,
+		CampaignType:       campaign.CampaignType,
+		Status:             campaign.Status,
+		ProgressPercentage: campaign.ProgressPercentage,
+		TotalItems:         campaign.TotalItems,
+		ProcessedItems:     campaign.ProcessedItems,
+		ErrorMessage:       campaign.ErrorMessage,
+		UpdatedAt:          campaign.UpdatedAt,
+		StallWindowSeconds: int(s.stallWindow / time.Second),
+		Personas:           []CampaignResourceDiagnostics{},
+		Proxies:            []CampaignResourceDiagnostics{},
+		RecentErrors:       []store.CampaignResultError{},
+		CheckedAt:          now,
+	}
+
+	jobs, err := s.campaignJobStore.ListJobs(ctx, store.ListJobsFilter{
+		CampaignID: uuid.NullUUID{UUID: campaignID, Valid: true},
+		Limit:      1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load latest job of campaign %s: %w", campaignID, err)
+	}
+	if len(jobs) > 0 && jobs[0] != nil {
+		entry := newCampaignJobHistoryEntry(jobs[0], now)
+		diag.LatestJob = &entry
+	}
+
+	var personaIDs, proxyIDs []uuid.UUID
+	switch campaign.CampaignType {
+	case models.CampaignTypeDomainGeneration:
+		params, err := s.campaignStore.GetDomainGenerationParams(ctx, querier, campaignID)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			return nil, fmt.Errorf("failed to load generation params of campaign %s: %w", campaignID, err)
+		}
+		if params != nil {
+			target := int64(params.NumDomainsToGenerate)
+			diag.Position.CurrentOffset = &params.CurrentOffset
+			diag.Position.TargetItems = &target
+		}
+	case models.CampaignTypeDNSValidation:
+		params, err := s.campaignStore.GetDNSValidationParams(ctx, querier, campaignID)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			return nil, fmt.Errorf("failed to load DNS params of campaign %s: %w", campaignID, err)
+		}
+		if params != nil {
+			personaIDs = params.PersonaIDs
+			diag.Position.SourceCampaignID = params.SourceGenerationCampaignID
+		}
+		diag.RecentErrors, err = s.campaignStore.ListRecentDNSValidationErrors(ctx, querier, campaignID, errorLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load recent DNS errors of campaign %s: %w", campaignID, err)
+		}
+	case models.CampaignTypeHTTPKeywordValidation:
+		params, err := s.campaignStore.GetHTTPKeywordParams(ctx, querier, campaignID)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			return nil, fmt.Errorf("failed to load HTTP keyword params of campaign %s: %w", campaignID, err)
+		}
+		if params != nil {
+			personaIDs = params.PersonaIDs
+			if params.ProxyIDs != nil {
+				proxyIDs = *params.ProxyIDs
+			}
+			diag.Position.LastProcessedDomainName = params.LastProcessedDomainName
+			diag.Position.SourceCampaignID = &params.SourceCampaignID
+		}
+		diag.RecentErrors, err = s.campaignStore.ListRecentHTTPKeywordErrors(ctx, querier, campaignID, errorLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load recent HTTP keyword errors of campaign %s: %w", campaignID, err)
+		}
+	}
+
+	for _, id := range personaIDs {
+		resource, err := s.personaDiagnostics(ctx, querier, id)
+		if err != nil {
+			return nil, err
+		}
+		diag.Personas = append(diag.Personas, resource)
+	}
+	for _, id := range proxyIDs {
+		resource, err := s.proxyDiagnostics(ctx, querier, id)
+		if err != nil {
+			return nil, err
+		}
+		diag.Proxies = append(diag.Proxies, resource)
+	}
+
+	diag.Stalled, diag.StalledReason = s.stallReason(campaign, diag.LatestJob, now)
+	return diag, nil
+}
+
+// stallReason reports whether an active campaign has stopped moving and why
+func (s *CampaignDiagnosticsService) stallReason(campaign *models.Campaign, latestJob *CampaignJobHistoryEntry, now time.Time) (bool, string) {
+	switch campaign.Status {
+	case models.CampaignStatusQueued, models.CampaignStatusRunning, models.CampaignStatusPausing:
+	default:
+		return false, ""
+	}
+	if latestJob == nil {
+		return true, "campaign is active but has no job"
+	}
+	if latestJob.Status == models.JobStatusFailed {
+		return true, "latest job failed"
+	}
+	lastActivity := campaign.UpdatedAt
+	if latestJob.UpdatedAt.After(lastActivity) {
+		lastActivity = latestJob.UpdatedAt
+	}
+	if idle := now.Sub(lastActivity); idle >= s.stallWindow {
+		return true, fmt.Sprintf("no campaign or job activity for %s", idle.Truncate(time.Second))
+	}
+	return false, ""
+}
+
+func (s *CampaignDiagnosticsService) personaDiagnostics(ctx context.Context, querier store.Querier, id uuid.UUID) (CampaignResourceDiagnostics, error) {
+	resource := CampaignResourceDiagnostics{ID: id}
+	persona, err := s.personaStore.GetPersonaByID(ctx, querier, id)
+	if errors.Is(err, store.ErrNotFound) {
+		resource.Missing = true
+		return resource, nil
+	}
+	if err != nil {
+		return resource, fmt.Errorf("failed to load persona %s: %w", id, err)
+	}
+	resource.Name = persona.Name
+	resource.Enabled = persona.IsEnabled
+	if history := s.personaTests.History(id); len(history) > 0 {
+		latest := history[0]
+		healthy := latest.Status == PersonaTestPassed
+		resource.Healthy = &healthy
+		resource.LatencyMs = &latest.LatencyMs
+		resource.LastCheckedAt = &latest.TestedAt
+		resource.Error = latest.Error
+	}
+	return resource, nil
+}
+
+func (s *CampaignDiagnosticsService) proxyDiagnostics(ctx context.Context, querier store.Querier, id uuid.UUID) (CampaignResourceDiagnostics, error) {
+	resource := CampaignResourceDiagnostics{ID: id}
+	proxy, err := s.proxyStore.GetProxyByID(ctx, querier, id)
+	if errors.Is(err, store.ErrNotFound) {
+		resource.Missing = true
+		return resource, nil
+	}
+	if err != nil {
+		return resource, fmt.Errorf("failed to load proxy %s: %w", id, err)
+	}
+	resource.Name = proxy.Name
+	resource.Enabled = proxy.IsEnabled
+	if proxy.LastCheckedAt.Valid {
+		healthy := proxy.IsHealthy
+		checkedAt := proxy.LastCheckedAt.Time
+		resource.Healthy = &healthy
+		resource.LastCheckedAt = &checkedAt
+		if proxy.LastStatus.Valid && !healthy {
+			resource.Error = proxy.LastStatus.String
+		}
+	}
+	if proxy.LatencyMs.Valid {
+		latency := int64(proxy.LatencyMs.Int32)
+		resource.LatencyMs = &latency
+	}
+	return resource, nil
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// diagnosticsCampaignStore answers the lookups diagnostics makes for one HTTP keyword campaign
+type diagnosticsCampaignStore struct {
+	campaignLookupStore
+	params     *models.HTTPKeywordCampaignParams
+	errors     []store.CampaignResultError
+	errorLimit int
+}
+
+func (s *diagnosticsCampaignStore) GetHTTPKeywordParams(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.HTTPKeywordCampaignParams, error) {
+	return s.params, nil
+}
+
+func (s *diagnosticsCampaignStore) ListRecentHTTPKeywordErrors(ctx context.Context, exec store.Querier, campaignID uuid.UUID, limit int) ([]store.CampaignResultError, error) {
+	s.errorLimit = limit
+	if limit < len(s.errors) {
+		return s.errors[:limit], nil
+	}
+	return s.errors, nil
+}
+
+// diagnosticsPersonaStore serves personas from a map
+type diagnosticsPersonaStore struct {
+	store.PersonaStore
+	personas map[uuid.UUID]*models.Persona
+}
+
+func (s *diagnosticsPersonaStore) GetPersonaByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Persona, error) {
+	persona, ok := s.personas[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return persona, nil
+}
+
+// diagnosticsProxyStore serves proxies from a map
+type diagnosticsProxyStore struct {
+	store.ProxyStore
+	proxies map[uuid.UUID]*models.Proxy
+}
+
+func (s *diagnosticsProxyStore) GetProxyByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Proxy, error) {
+	proxy, ok := s.proxies[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return proxy, nil
+}
+
+func TestGetCampaignDiagnostics_ReportsStalledCampaign(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	lastActivity := now.Add(-2 * time.Hour)
+	campaignID, personaID, goneProxyID, badProxyID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	pointer := "stuck.example"
+	processed := int64(40)
+	proxyIDs := []uuid.UUID{badProxyID, goneProxyID}
+	campaignStore := &diagnosticsCampaignStore{
+		campaignLookupStore: campaignLookupStore{campaigns: map[uuid.UUID]*models.Campaign{campaignID: {
+			ID: campaignID, CampaignType: models.CampaignTypeHTTPKeywordValidation, Status: models.CampaignStatusRunning,
+			ProcessedItems: &processed, UpdatedAt: lastActivity,
+		}}},
+		params: &models.HTTPKeywordCampaignParams{
+			SourceCampaignID: uuid.New(), PersonaIDs: []uuid.UUID{personaID}, ProxyIDs: &proxyIDs, LastProcessedDomainName: &pointer,
+		},
+		errors: []store.CampaignResultError{
+			{DomainName: "a.example", ValidationStatus: "circuit_open", Attempts: 3, CheckedAt: lastActivity},
+			{DomainName: "b.example", ValidationStatus: "invalid_http_response_error", Attempts: 1, CheckedAt: lastActivity.Add(-time.Minute)},
+		},
+	}
+	jobStore := &memoryCampaignJobStore{}
+	require.NoError(t, jobStore.CreateJob(ctx, nil, &models.CampaignJob{
+		CampaignID: campaignID, JobType: models.CampaignTypeHTTPKeywordValidation, Status: models.JobStatusProcessing,
+		Attempts: 2, LastError: sql.NullString{String: "context deadline exceeded", Valid: true},
+		CreatedAt: now.Add(-3 * time.Hour), UpdatedAt: lastActivity,
+	}))
+	personaStore := &diagnosticsPersonaStore{personas: map[uuid.UUID]*models.Persona{personaID: {ID: personaID, Name: "crawler", IsEnabled: true}}}
+	proxyStore := &diagnosticsProxyStore{proxies: map[uuid.UUID]*models.Proxy{badProxyID: {
+		ID: badProxyID, Name: "edge-1", IsEnabled: true, IsHealthy: false,
+		LastStatus: sql.NullString{String: "connection refused", Valid: true}, LastCheckedAt: sql.NullTime{Time: lastActivity, Valid: true},
+	}}}
+
+	appCfg := &config.AppConfig{Worker: config.WorkerConfig{HealthStallWindowSeconds: 600}}
+	svc := NewCampaignDiagnosticsService(appCfg, nil, campaignStore, jobStore, personaStore, proxyStore, nil)
+	svc.now = func() time.Time { return now }
+
+	diag, err := svc.GetCampaignDiagnostics(ctx, campaignID, 1)
+	require.NoError(t, err)
+	assert.True(t, diag.Stalled)
+	assert.Contains(t, diag.StalledReason, "no campaign or job activity for 2h0m0s")
+	assert.Equal(t, 600, diag.StallWindowSeconds)
+	assert.Equal(t, models.CampaignStatusRunning, diag.Status)
+	assert.Equal(t, &processed, diag.ProcessedItems)
+
+	require.NotNil(t, diag.LatestJob)
+	assert.Equal(t, models.JobStatusProcessing, diag.LatestJob.Status)
+	assert.Equal(t, "context deadline exceeded", diag.LatestJob.LastError.String)
+
+	assert.Equal(t, &pointer, diag.Position.LastProcessedDomainName)
+	assert.Equal(t, 1, campaignStore.errorLimit)
+	require.Len(t, diag.RecentErrors, 1)
+	assert.Equal(t, "a.example", diag.RecentErrors[0].DomainName)
+
+	require.Len(t, diag.Personas, 1)
+	assert.Equal(t, "crawler", diag.Personas[0].Name)
+	assert.Nil(t, diag.Personas[0].Healthy, "no scheduled persona tests ran")
+
+	require.Len(t, diag.Proxies, 2)
+	require.NotNil(t, diag.Proxies[0].Healthy)
+	assert.False(t, *diag.Proxies[0].Healthy)
+	assert.Equal(t, "connection refused", diag.Proxies[0].Error)
+	assert.True(t, diag.Proxies[1].Missing)
+}
+
+func TestGetCampaignDiagnostics_StallReasons(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	campaignID := uuid.New()
+	campaign := &models.Campaign{ID: campaignID, CampaignType: models.CampaignTypeDomainGeneration, Status: models.CampaignStatusRunning, UpdatedAt: now}
+	campaignStore := &campaignLookupStore{campaigns: map[uuid.UUID]*models.Campaign{campaignID: campaign}}
+	jobStore := &memoryCampaignJobStore{}
+	svc := NewCampaignDiagnosticsService(nil, nil, &generationParamsStore{campaignLookupStore: campaignStore}, jobStore, nil, nil, nil)
+
+	diag, err := svc.GetCampaignDiagnostics(ctx, campaignID, 0)
+	require.NoError(t, err)
+	assert.True(t, diag.Stalled)
+	assert.Equal(t, "campaign is active but has no job", diag.StalledReason)
+	require.NotNil(t, diag.Position.CurrentOffset)
+	assert.Equal(t, int64(120), *diag.Position.CurrentOffset)
+
+	require.NoError(t, jobStore.CreateJob(ctx, nil, &models.CampaignJob{CampaignID: campaignID, Status: models.JobStatusProcessing, UpdatedAt: now}))
+	diag, err = svc.GetCampaignDiagnostics(ctx, campaignID, 0)
+	require.NoError(t, err)
+	assert.False(t, diag.Stalled, "recent activity within the default window")
+
+	jobStore.jobs[0].Status = models.JobStatusFailed
+	diag, err = svc.GetCampaignDiagnostics(ctx, campaignID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "latest job failed", diag.StalledReason)
+
+	campaign.Status = models.CampaignStatusPaused
+	diag, err = svc.GetCampaignDiagnostics(ctx, campaignID, 0)
+	require.NoError(t, err)
+	assert.False(t, diag.Stalled, "paused campaigns are not expected to move")
+
+	_, err = svc.GetCampaignDiagnostics(ctx, uuid.New(), 0)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
+
+// generationParamsStore answers domain generation params at a fixed offset
+type generationParamsStore struct {
+	*campaignLookupStore
+}
+
+func (s *generationParamsStore) GetDomainGenerationParams(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.DomainGenerationCampaignParams, error) {
+	return &models.DomainGenerationCampaignParams{CampaignID: campaignID, NumDomainsToGenerate: 500, CurrentOffset: 120}, nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+)
+
+// ErrCampaignFieldsImmutable is wrapped by ImmutableFieldsError
+var ErrCampaignFieldsImmutable = errors.New("campaign fields cannot be changed after the campaign has started")
+
+// ImmutableFieldsError is returned by UpdateCampaign when an update to a started campaign sets
+// fields that would change how it processes its items
+type ImmutableFieldsError struct {
+	Status models.CampaignStatusEnum
+	Fields []string // JSON names of the offending UpdateCampaignRequest fields
+}
+
+func (e *ImmutableFieldsError) Error() string {
+	return fmt.Sprintf("%v: %s (campaign is %s)", ErrCampaignFieldsImmutable, strings.Join(e.Fields, ", "), e.Status)
+}
+
+func (e *ImmutableFieldsError) Unwrap() error {
+	return ErrCampaignFieldsImmutable
+}
+
+// CampaignStarted reports whether a campaign in status has been queued or run. Only pending
+// campaigns have not started.
+func CampaignStarted(status models.CampaignStatusEnum) bool {
+	return status != "" && status != models.CampaignStatusPending
+}
+
+// immutableUpdateFields returns the JSON names of the fields set in req that may not change once a
+// campaign has started: its sources, generation pattern, personas, proxies, keywords and processing
+// settings. Name, status, tags and metadata stay mutable.
+func immutableUpdateFields(req UpdateCampaignRequest) []string {
+	set := []struct {
+		name string
+		set  bool
+	}{
+		{"sourceGenerationCampaignId", req.SourceGenerationCampaignID != nil},
+		{"sourceDnsCampaignId", req.SourceDnsCampaignID != nil},
+		{"keywordSetIds", req.KeywordSetIDs != nil},
+		{"adHocKeywords", req.AdHocKeywords != nil},
+		{"personaIds", req.PersonaIDs != nil},
+		{"proxyPoolId", req.ProxyPoolID != nil},
+		{"proxySelectionStrategy", req.ProxySelectionStrategy != nil},
+		{"rotationIntervalSeconds", req.RotationIntervalSeconds != nil},
+		{"processingSpeedPerMinute", req.ProcessingSpeedPerMinute != nil},
+		{"batchSize", req.BatchSize != nil},
+		{"retryAttempts", req.RetryAttempts != nil},
+		{"targetHttpPorts", req.TargetHTTPPorts != nil},
+		{"patternType", req.PatternType != nil},
+		{"numDomainsToGenerate", req.NumDomainsToGenerate != nil},
+		{"variableLength", req.VariableLength != nil},
+		{"characterSet", req.CharacterSet != nil},
+		{"constantString", req.ConstantString != nil},
+		{"tld", req.TLD != nil},
+	}
+	var fields []string
+	for _, field := range set {
+		if field.set {
+			fields = append(fields, field.name)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// updatingCampaignStore records the campaigns saved through UpdateCampaign
+type updatingCampaignStore struct {
+	campaignLookupStore
+	updated []*models.Campaign
+}
+
+func (s *updatingCampaignStore) UpdateCampaign(ctx context.Context, exec store.Querier, campaign *models.Campaign) error {
+	s.updated = append(s.updated, campaign)
+	return nil
+}
+
+func newUpdatingCampaignStore(status models.CampaignStatusEnum) (*updatingCampaignStore, uuid.UUID) {
+	campaignID := uuid.New()
+	return &updatingCampaignStore{campaignLookupStore: campaignLookupStore{campaigns: map[uuid.UUID]*models.Campaign{
+		campaignID: {ID: campaignID, Name: "generation", CampaignType: models.CampaignTypeDomainGeneration, Status: status},
+	}}}, campaignID
+}
+
+func TestUpdateCampaign_RejectsImmutableFieldsOnRunningCampaign(t *testing.T) {
+	ctx := context.Background()
+	campaignStore, campaignID := newUpdatingCampaignStore(models.CampaignStatusRunning)
+	svc := NewCampaignOrchestratorService(nil, campaignStore, nil, nil, nil, nil, nil, nil, nil)
+
+	pattern, charset, source := "suffix", "xyz", uuid.New()
+	_, err := svc.UpdateCampaign(ctx, campaignID, UpdateCampaignRequest{
+		PatternType:                &pattern,
+		CharacterSet:               &charset,
+		SourceGenerationCampaignID: &source,
+	})
+	var immutableErr *ImmutableFieldsError
+	require.ErrorAs(t, err, &immutableErr)
+	assert.ErrorIs(t, err, ErrCampaignFieldsImmutable)
+	assert.Equal(t, models.CampaignStatusRunning, immutableErr.Status)
+	assert.Equal(t, []string{"sourceGenerationCampaignId", "patternType", "characterSet"}, immutableErr.Fields)
+	assert.Empty(t, campaignStore.updated)
+
+	// Mixing a mutable field into the request does not let the immutable ones through
+	name := "renamed"
+	_, err = svc.UpdateCampaign(ctx, campaignID, UpdateCampaignRequest{Name: &name, CharacterSet: &charset})
+	assert.ErrorIs(t, err, ErrCampaignFieldsImmutable)
+	assert.Empty(t, campaignStore.updated)
+}
+
+func TestUpdateCampaign_AllowsMutableFieldsOnRunningCampaign(t *testing.T) {
+	ctx := context.Background()
+	campaignStore, campaignID := newUpdatingCampaignStore(models.CampaignStatusRunning)
+	svc := NewCampaignOrchestratorService(nil, campaignStore, nil, nil, nil, nil, nil, nil, nil)
+
+	name, tags, metadata := "renamed", []string{"Client-A"}, json.RawMessage(`{"owner":"ops"}`)
+	campaign, err := svc.UpdateCampaign(ctx, campaignID, UpdateCampaignRequest{Name: &name, Tags: &tags, Metadata: &metadata})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", campaign.Name)
+	assert.Equal(t, []string{"client-a"}, []string(campaign.Tags))
+	assert.JSONEq(t, `{"owner":"ops"}`, string(*campaign.Metadata))
+	require.Len(t, campaignStore.updated, 1)
+}
+
+func TestUpdateCampaign_ImmutableFieldsOnlyEnforcedAfterStart(t *testing.T) {
+	ctx := context.Background()
+	charset := "xyz"
+
+	// A pending campaign has not processed anything yet
+	campaignStore, campaignID := newUpdatingCampaignStore(models.CampaignStatusPending)
+	svc := NewCampaignOrchestratorService(nil, campaignStore, nil, nil, nil, nil, nil, nil, nil)
+	_, err := svc.UpdateCampaign(ctx, campaignID, UpdateCampaignRequest{CharacterSet: &charset})
+	assert.NoError(t, err)
+
+	// Enforcement can be turned off
+	campaignStore, campaignID = newUpdatingCampaignStore(models.CampaignStatusPaused)
+	svc = NewCampaignOrchestratorService(nil, campaignStore, nil, nil, nil, nil, nil, nil, nil, WithImmutableCampaignFields(false))
+	_, err = svc.UpdateCampaign(ctx, campaignID, UpdateCampaignRequest{CharacterSet: &charset})
+	assert.NoError(t, err)
+}
+
+func TestCampaignStarted(t *testing.T) {
+	assert.False(t, CampaignStarted(models.CampaignStatusPending))
+	for _, status := range []models.CampaignStatusEnum{models.CampaignStatusQueued, models.CampaignStatusRunning,
+		models.CampaignStatusPaused, models.CampaignStatusCompleted, models.CampaignStatusFailed, models.CampaignStatusCancelled} {
+		assert.True(t, CampaignStarted(status), status)
+	}
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCampaignJobStore is an in-memory store.CampaignJobStore for tests
+type memoryCampaignJobStore struct {
+	mu   sync.Mutex
+	jobs []*models.CampaignJob
+}
+
+func (m *memoryCampaignJobStore) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error) {
+	return nil, errors.New("transactions not supported")
+}
+
+func (m *memoryCampaignJobStore) CreateJob(ctx context.Context, exec store.Querier, job *models.CampaignJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+	j := *job
+	m.jobs = append(m.jobs, &j)
+	return nil
+}
+
+func (m *memoryCampaignJobStore) GetJobByID(ctx context.Context, jobID uuid.UUID) (*models.CampaignJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, job := range m.jobs {
+		if job.ID == jobID {
+			j := *job
+			return &j, nil
+		}
+	}
+	return nil, store.ErrNotFound
+}
+
+func (m *memoryCampaignJobStore) UpdateJob(ctx context.Context, exec store.Querier, job *models.CampaignJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, existing := range m.jobs {
+		if existing.ID == job.ID {
+			j := *job
+			m.jobs[i] = &j
+			return nil
+		}
+	}
+	return store.ErrNotFound
+}
+
+func (m *memoryCampaignJobStore) GetNextQueuedJob(ctx context.Context, campaignTypes []models.CampaignTypeEnum, workerID string) (*models.CampaignJob, error) {
+	return nil, store.ErrNotFound
+}
+
+func (m *memoryCampaignJobStore) TouchJobHeartbeat(ctx context.Context, jobID uuid.UUID, workerID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, job := range m.jobs {
+		if job.ID == jobID && job.Status == models.JobStatusProcessing && job.ProcessingServerID.String == workerID {
+			job.LastHeartbeatAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+			return nil
+		}
+	}
+	return store.ErrNotFound
+}
+
+func (m *memoryCampaignJobStore) ReapStaleJobs(ctx context.Context, staleBefore time.Time, limit int) ([]*models.CampaignJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	reaped := []*models.CampaignJob{}
+	for _, job := range m.jobs {
+		if len(reaped) == limit {
+			break
+		}
+		alive := job.LastHeartbeatAt
+		if !alive.Valid {
+			alive = job.LastAttemptedAt
+		}
+		if job.Status != models.JobStatusProcessing || !alive.Time.Before(staleBefore) {
+			continue
+		}
+		job.Status = models.JobStatusRetry
+		job.NextExecutionAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+		job.LastError = sql.NullString{String: store.StaleJobError, Valid: true}
+		j := *job
+		reaped = append(reaped, &j)
+	}
+	return reaped, nil
+}
+
+func (m *memoryCampaignJobStore) DeleteJob(ctx context.Context, jobID uuid.UUID) error {
+	return nil
+}
+
+func (m *memoryCampaignJobStore) ListJobs(ctx context.Context, filter store.ListJobsFilter) ([]*models.CampaignJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := []*models.CampaignJob{}
+	for _, job := range m.jobs {
+		if filter.CampaignID.Valid && job.CampaignID != filter.CampaignID.UUID {
+			continue
+		}
+		j := *job
+		result = append(result, &j)
+	}
+	if filter.Offset >= len(result) {
+		return []*models.CampaignJob{}, nil
+	}
+	result = result[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(result) {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+func (m *memoryCampaignJobStore) CountJobs(ctx context.Context, filter store.ListJobsFilter) (int64, error) {
+	filter.Limit, filter.Offset = 0, 0
+	jobs, err := m.ListJobs(ctx, filter)
+	return int64(len(jobs)), err
+}
+
+// campaignLookupStore only answers GetCampaignByID; other CampaignStore methods are not used here
+type campaignLookupStore struct {
+	store.CampaignStore
+	campaigns map[uuid.UUID]*models.Campaign
+}
+
+func (s *campaignLookupStore) GetCampaignByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Campaign, error) {
+	campaign, ok := s.campaigns[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return campaign, nil
+}
+
+func TestListCampaignJobs_ReflectsMultiAttemptLifecycle(t *testing.T) {
+	ctx := context.Background()
+	campaignID := uuid.New()
+	jobStore := &memoryCampaignJobStore{}
+	campaignStore := &campaignLookupStore{campaigns: map[uuid.UUID]*models.Campaign{
+		campaignID: {ID: campaignID, CampaignType: models.CampaignTypeDNSValidation},
+	}}
+	svc := NewCampaignOrchestratorService(nil, campaignStore, nil, nil, nil, jobStore, nil, nil, nil)
+
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	job := &models.CampaignJob{
+		CampaignID:  campaignID,
+		JobType:     models.CampaignTypeDNSValidation,
+		Status:      models.JobStatusQueued,
+		MaxAttempts: 3,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+	require.NoError(t, jobStore.CreateJob(ctx, nil, job))
+
+	// Attempt 1: claimed by worker-a, fails after 2s and is scheduled for retry
+	job.Status = models.JobStatusProcessing
+	job.Attempts = 1
+	job.ProcessingServerID = sql.NullString{String: "worker-a", Valid: true}
+	job.LastAttemptedAt = sql.NullTime{Time: createdAt.Add(1 * time.Second), Valid: true}
+	require.NoError(t, jobStore.UpdateJob(ctx, nil, job))
+	job.Status = models.JobStatusRetry
+	job.LastError = sql.NullString{String: "dns resolver timeout", Valid: true}
+	job.UpdatedAt = createdAt.Add(3 * time.Second)
+	require.NoError(t, jobStore.UpdateJob(ctx, nil, job))
+
+	// Attempt 2: claimed by worker-b and completes after 5s
+	job.Status = models.JobStatusProcessing
+	job.Attempts = 2
+	job.ProcessingServerID = sql.NullString{String: "worker-b", Valid: true}
+	job.LastAttemptedAt = sql.NullTime{Time: createdAt.Add(10 * time.Second), Valid: true}
+	require.NoError(t, jobStore.UpdateJob(ctx, nil, job))
+	job.Status = models.JobStatusCompleted
+	job.UpdatedAt = createdAt.Add(15 * time.Second)
+	require.NoError(t, jobStore.UpdateJob(ctx, nil, job))
+
+	// A job from another campaign must not show up
+	require.NoError(t, jobStore.CreateJob(ctx, nil, &models.CampaignJob{CampaignID: uuid.New(), Status: models.JobStatusQueued}))
+
+	history, err := svc.ListCampaignJobs(ctx, campaignID, 20, 0)
+	require.NoError(t, err)
+	require.Len(t, history.Data, 1)
+	assert.Equal(t, 20, history.Limit)
+	assert.Equal(t, 0, history.Offset)
+	assert.Equal(t, int64(1), history.TotalCount)
+
+	entry := history.Data[0]
+	assert.Equal(t, job.ID, entry.ID)
+	assert.Equal(t, models.JobStatusCompleted, entry.Status)
+	assert.Equal(t, 2, entry.Attempts)
+	assert.Equal(t, "dns resolver timeout", entry.LastError.String)
+	assert.Equal(t, "worker-b", entry.ProcessingServerID.String)
+	assert.Equal(t, int64(15000), entry.TotalDurationMs)
+	require.NotNil(t, entry.LastAttemptDurationMs)
+	assert.Equal(t, int64(5000), *entry.LastAttemptDurationMs)
+}
+
+func TestListCampaignJobs_InFlightAttemptUsesCurrentTime(t *testing.T) {
+	ctx := context.Background()
+	campaignID := uuid.New()
+	jobStore := &memoryCampaignJobStore{}
+	campaignStore := &campaignLookupStore{campaigns: map[uuid.UUID]*models.Campaign{campaignID: {ID: campaignID}}}
+	svc := NewCampaignOrchestratorService(nil, campaignStore, nil, nil, nil, jobStore, nil, nil, nil)
+
+	startedAt := time.Now().UTC().Add(-2 * time.Second)
+	require.NoError(t, jobStore.CreateJob(ctx, nil, &models.CampaignJob{
+		CampaignID:      campaignID,
+		Status:          models.JobStatusProcessing,
+		Attempts:        1,
+		LastAttemptedAt: sql.NullTime{Time: startedAt, Valid: true},
+		CreatedAt:       startedAt,
+		UpdatedAt:       startedAt,
+	}))
+
+	history, err := svc.ListCampaignJobs(ctx, campaignID, 20, 0)
+	require.NoError(t, err)
+	require.Len(t, history.Data, 1)
+	require.NotNil(t, history.Data[0].LastAttemptDurationMs)
+	assert.GreaterOrEqual(t, *history.Data[0].LastAttemptDurationMs, int64(2000))
+}
+
+func TestListCampaignJobs_TotalCountSpansPages(t *testing.T) {
+	ctx := context.Background()
+	campaignID := uuid.New()
+	jobStore := &memoryCampaignJobStore{}
+	campaignStore := &campaignLookupStore{campaigns: map[uuid.UUID]*models.Campaign{campaignID: {ID: campaignID}}}
+	svc := NewCampaignOrchestratorService(nil, campaignStore, nil, nil, nil, jobStore, nil, nil, nil)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, jobStore.CreateJob(ctx, nil, &models.CampaignJob{CampaignID: campaignID, Status: models.JobStatusCompleted}))
+	}
+
+	history, err := svc.ListCampaignJobs(ctx, campaignID, 2, 2)
+	require.NoError(t, err)
+	assert.Len(t, history.Data, 1)
+	assert.Equal(t, int64(3), history.TotalCount)
+}
+
+func TestListCampaignJobs_UnknownCampaign(t *testing.T) {
+	svc := NewCampaignOrchestratorService(nil, &campaignLookupStore{}, nil, nil, nil, &memoryCampaignJobStore{}, nil, nil, nil)
+	_, err := svc.ListCampaignJobs(context.Background(), uuid.New(), 20, 0)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
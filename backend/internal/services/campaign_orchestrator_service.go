@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
@@ -16,6 +17,9 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// ErrCampaignNotPending is returned when starting a campaign that is not pending
+var ErrCampaignNotPending = errors.New("campaign is not pending")
+
 type campaignOrchestratorServiceImpl struct {
 	db               *sqlx.DB
 	campaignStore    store.CampaignStore
@@ -31,14 +35,29 @@ type campaignOrchestratorServiceImpl struct {
 
 	// State machine for campaign status transitions
 	stateMachine *CampaignStateMachine
+
+	// Reject updates to the core parameters of started campaigns
+	enforceImmutableFields bool
+}
+
+// CampaignOrchestratorOption configures optional behaviour of the campaign orchestrator
+type CampaignOrchestratorOption func(*campaignOrchestratorServiceImpl)
+
+// WithImmutableCampaignFields sets whether UpdateCampaign rejects changes to the sources, pattern,
+// personas and processing settings of a campaign that has started. It is enforced by default.
+func WithImmutableCampaignFields(enforced bool) CampaignOrchestratorOption {
+	return func(s *campaignOrchestratorServiceImpl) {
+		s.enforceImmutableFields = enforced
+	}
 }
 
 func NewCampaignOrchestratorService(
 	db *sqlx.DB,
 	cs store.CampaignStore, ps store.PersonaStore, ks store.KeywordStore, as store.AuditLogStore, cjs store.CampaignJobStore,
 	dgs DomainGenerationService, dNSService DNSCampaignService, hkService HTTPKeywordCampaignService,
+	opts ...CampaignOrchestratorOption,
 ) CampaignOrchestratorService {
-	return &campaignOrchestratorServiceImpl{
+	s := &campaignOrchestratorServiceImpl{
 		db:                 db,
 		campaignStore:      cs,
 		personaStore:       ps,
@@ -49,7 +68,13 @@ func NewCampaignOrchestratorService(
 		dnsService:         dNSService,
 		httpKeywordService: hkService,
 		stateMachine:       NewCampaignStateMachine(),
+
+		enforceImmutableFields: true,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *campaignOrchestratorServiceImpl) CreateDomainGenerationCampaign(ctx context.Context, req CreateDomainGenerationCampaignRequest) (*models.Campaign, error) {
@@ -215,11 +240,19 @@ func (s *campaignOrchestratorServiceImpl) GetCampaignStatus(ctx context.Context,
 	}
 	campaign, err := s.campaignStore.GetCampaignByID(ctx, querier, campaignID)
 	if err != nil {
-		return "", nil, err
+		return "", nil, fmt.Errorf("orchestrator: get campaign failed: %w", err)
 	}
 	return campaign.Status, campaign.ProgressPercentage, nil
 }
 
+func (s *campaignOrchestratorServiceImpl) ListCampaignStatuses(ctx context.Context, campaignIDs []uuid.UUID, userID string) ([]store.CampaignStatusSummary, error) {
+	var querier store.Querier
+	if s.db != nil {
+		querier = s.db
+	}
+	return s.campaignStore.ListCampaignStatuses(ctx, querier, campaignIDs, userID)
+}
+
 func (s *campaignOrchestratorServiceImpl) ListCampaigns(ctx context.Context, filter store.ListCampaignsFilter) ([]models.Campaign, int64, error) {
 	var querier store.Querier
 	if s.db != nil {
@@ -246,6 +279,115 @@ func (s *campaignOrchestratorServiceImpl) ListCampaigns(ctx context.Context, fil
 	return actualCampaigns, totalCount, nil
 }
 
+// ListCampaignJobs returns the job history of a campaign, newest first, with per-job timings.
+func (s *campaignOrchestratorServiceImpl) ListCampaignJobs(ctx context.Context, campaignID uuid.UUID, limit, offset int) (*CampaignJobHistoryResponse, error) {
+	var querier store.Querier
+	if s.db != nil {
+		querier = s.db
+	}
+	if _, err := s.campaignStore.GetCampaignByID(ctx, querier, campaignID); err != nil {
+		return nil, err
+	}
+
+	filter := store.ListJobsFilter{
+		CampaignID: uuid.NullUUID{UUID: campaignID, Valid: true},
+		Limit:      limit,
+		Offset:     offset,
+	}
+	jobs, err := s.campaignJobStore.ListJobs(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs for campaign %s: %w", campaignID, err)
+	}
+	totalCount, err := s.campaignJobStore.CountJobs(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count jobs for campaign %s: %w", campaignID, err)
+	}
+
+	now := time.Now().UTC()
+	resp := &CampaignJobHistoryResponse{
+		Data:       make([]CampaignJobHistoryEntry, 0, len(jobs)),
+		Limit:      limit,
+		Offset:     offset,
+		TotalCount: totalCount,
+	}
+	for _, job := range jobs {
+		if job != nil {
+			resp.Data = append(resp.Data, newCampaignJobHistoryEntry(job, now))
+		}
+	}
+	return resp, nil
+}
+
+// ListDomainDedupDecisions returns the recorded domain deduplication decisions of a campaign in decision order.
+func (s *campaignOrchestratorServiceImpl) ListDomainDedupDecisions(ctx context.Context, campaignID uuid.UUID, limit, offset int) ([]*models.DomainDedupDecision, error) {
+	var querier store.Querier
+	if s.db != nil {
+		querier = s.db
+	}
+	if _, err := s.campaignStore.GetCampaignByID(ctx, querier, campaignID); err != nil {
+		return nil, err
+	}
+
+	decisions, err := s.campaignStore.ListDomainDedupDecisions(ctx, querier, campaignID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dedup decisions for campaign %s: %w", campaignID, err)
+	}
+	return decisions, nil
+}
+
+// ListCampaignStatusHistory returns the audited status transitions of a campaign, newest first.
+func (s *campaignOrchestratorServiceImpl) ListCampaignStatusHistory(ctx context.Context, campaignID uuid.UUID, limit, offset int) ([]CampaignStatusTransition, error) {
+	var querier store.Querier
+	if s.db != nil {
+		querier = s.db
+	}
+	if _, err := s.campaignStore.GetCampaignByID(ctx, querier, campaignID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.auditLogStore.ListAuditLogs(ctx, querier, store.ListAuditLogsFilter{
+		EntityType: "Campaign",
+		EntityID:   uuid.NullUUID{UUID: campaignID, Valid: true},
+		Action:     CampaignStatusTransitionAction,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list status history for campaign %s: %w", campaignID, err)
+	}
+
+	history := make([]CampaignStatusTransition, 0, len(entries))
+	for _, entry := range entries {
+		transition, err := campaignStatusTransitionFromAudit(entry)
+		if err != nil {
+			log.Printf("Skipping unreadable status transition %s of campaign %s: %v", entry.ID, campaignID, err)
+			continue
+		}
+		history = append(history, transition)
+	}
+	return history, nil
+}
+
+// newCampaignJobHistoryEntry derives job timings from its timestamps
+func newCampaignJobHistoryEntry(job *models.CampaignJob, now time.Time) CampaignJobHistoryEntry {
+	entry := CampaignJobHistoryEntry{CampaignJob: *job}
+	if !job.UpdatedAt.IsZero() && !job.CreatedAt.IsZero() && job.UpdatedAt.After(job.CreatedAt) {
+		entry.TotalDurationMs = job.UpdatedAt.Sub(job.CreatedAt).Milliseconds()
+	}
+	if job.LastAttemptedAt.Valid {
+		end := job.UpdatedAt
+		if job.Status == models.JobStatusProcessing || job.Status == models.JobStatusRunning {
+			end = now
+		}
+		if end.Before(job.LastAttemptedAt.Time) {
+			end = job.LastAttemptedAt.Time
+		}
+		d := end.Sub(job.LastAttemptedAt.Time).Milliseconds()
+		entry.LastAttemptDurationMs = &d
+	}
+	return entry
+}
+
 func (s *campaignOrchestratorServiceImpl) GetGeneratedDomainsForCampaign(ctx context.Context, campaignID uuid.UUID, limit int, cursor int64) (*GeneratedDomainsResponse, error) {
 	var querier store.Querier
 	if s.db != nil {
@@ -437,11 +579,11 @@ func (s *campaignOrchestratorServiceImpl) StartCampaign(ctx context.Context, cam
 	// s.campaignStore.GetCampaignByID will use the querier (which is sqlTx or nil)
 	campaign, errGet := s.campaignStore.GetCampaignByID(ctx, querier, campaignID)
 	if errGet != nil {
-		opErr = errGet
+		opErr = fmt.Errorf("orchestrator: get campaign failed: %w", errGet)
 		return opErr // opErr will be handled by defer if in SQL transaction
 	}
 	if campaign.Status != models.CampaignStatusPending {
-		opErr = fmt.Errorf("campaign %s not pending: %s", campaignID, campaign.Status)
+		opErr = fmt.Errorf("%w: campaign %s is %s", ErrCampaignNotPending, campaignID, campaign.Status)
 		return opErr // opErr will be handled by defer if in SQL transaction
 	}
 
@@ -872,10 +1014,10 @@ func (s *campaignOrchestratorServiceImpl) logAuditEvent(ctx context.Context, exe
 		"description":        description,
 		"orchestrator_event": "true",
 	}
-	detailsJSON, err := json.Marshal(detailsMap)
+	details, err := models.NewAuditDetails(detailsMap)
 	if err != nil {
-		log.Printf("Orchestrator Audit: Error marshalling details for campaign %s, action %s: %v. Using raw desc.", campaign.ID, action, err)
-		detailsJSON = json.RawMessage(fmt.Sprintf(`{"campaign_name": "%s", "description": "%s (details marshalling error)"}`, campaign.Name, description))
+		log.Printf("Orchestrator Audit: Error encoding details for campaign %s, action %s: %v", campaign.ID, action, err)
+		return
 	}
 
 	var auditLogUserID uuid.NullUUID
@@ -888,7 +1030,7 @@ func (s *campaignOrchestratorServiceImpl) logAuditEvent(ctx context.Context, exe
 		Action:     action,
 		EntityType: sql.NullString{String: "Campaign", Valid: true},
 		EntityID:   uuid.NullUUID{UUID: campaign.ID, Valid: true},
-		Details:    models.JSONRawMessagePtr(detailsJSON),
+		Details:    details,
 	}
 	// s.auditLogStore.CreateAuditLog will use the exec (querier)
 	if s.auditLogStore == nil {
@@ -943,6 +1085,14 @@ func (s *campaignOrchestratorServiceImpl) UpdateCampaign(ctx context.Context, ca
 		return nil, opErr
 	}
 
+	// Changing what a started campaign processes would mix results of two configurations
+	if s.enforceImmutableFields && CampaignStarted(campaign.Status) {
+		if fields := immutableUpdateFields(req); len(fields) > 0 {
+			opErr = &ImmutableFieldsError{Status: campaign.Status, Fields: fields}
+			return nil, opErr
+		}
+	}
+
 	// Update campaign fields if provided
 	if req.Name != nil {
 		campaign.Name = *req.Name
@@ -950,6 +1100,12 @@ func (s *campaignOrchestratorServiceImpl) UpdateCampaign(ctx context.Context, ca
 	if req.Status != nil {
 		campaign.Status = *req.Status
 	}
+	if req.Tags != nil {
+		campaign.Tags = NormalizeCampaignTags(*req.Tags)
+	}
+	if req.Metadata != nil {
+		campaign.Metadata = req.Metadata
+	}
 
 	campaign.UpdatedAt = time.Now().UTC()
 
@@ -1044,6 +1200,9 @@ func (s *campaignOrchestratorServiceImpl) CreateCampaignUnified(ctx context.Cont
 			TLD:                  req.DomainGenerationParams.TLD,
 			NumDomainsToGenerate: req.DomainGenerationParams.NumDomainsToGenerate,
 			UserID:               req.UserID,
+			Tags:                 req.Tags,
+			AutoRetry:            req.AutoRetry,
+			DNSPromotion:         req.DomainGenerationParams.DNSPromotion,
 		}
 
 		return s.domainGenService.CreateCampaign(ctx, legacyReq)
@@ -1062,7 +1221,13 @@ func (s *campaignOrchestratorServiceImpl) CreateCampaignUnified(ctx context.Cont
 			ProcessingSpeedPerMinute:   req.DnsValidationParams.ProcessingSpeedPerMinute,
 			BatchSize:                  req.DnsValidationParams.BatchSize,
 			RetryAttempts:              req.DnsValidationParams.RetryAttempts,
+			DeduplicateDomains:         req.DnsValidationParams.DeduplicateDomains,
+			ConsensusMode:              req.DnsValidationParams.ConsensusMode,
+			ConsensusQuorum:            req.DnsValidationParams.ConsensusQuorum,
+			ExpectedRecords:            req.DnsValidationParams.ExpectedRecords,
 			UserID:                     req.UserID,
+			Tags:                       req.Tags,
+			AutoRetry:                  req.AutoRetry,
 		}
 
 		return s.dnsService.CreateCampaign(ctx, legacyReq)
@@ -1086,7 +1251,12 @@ func (s *campaignOrchestratorServiceImpl) CreateCampaignUnified(ctx context.Cont
 			BatchSize:                req.HttpKeywordParams.BatchSize,
 			RetryAttempts:            req.HttpKeywordParams.RetryAttempts,
 			TargetHTTPPorts:          req.HttpKeywordParams.TargetHTTPPorts,
+			DeduplicateDomains:       req.HttpKeywordParams.DeduplicateDomains,
+			ValidationMode:           req.HttpKeywordParams.ValidationMode,
+			MatchType:                req.HttpKeywordParams.MatchType,
 			UserID:                   req.UserID,
+			Tags:                     req.Tags,
+			AutoRetry:                req.AutoRetry,
 		}
 
 		return s.httpKeywordService.CreateCampaign(ctx, legacyReq)
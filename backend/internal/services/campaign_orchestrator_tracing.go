@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/fntelecomllc/studio/backend/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedCampaignOrchestrator records a span for each call to the orchestrator it wraps
+type tracedCampaignOrchestrator struct {
+	next CampaignOrchestratorService
+}
+
+// TraceCampaignOrchestrator wraps svc so each of its operations is a span, named like
+// CampaignOrchestrator.StartCampaign, with the campaign ID when the operation has one. The store
+// queries the operation runs become its children.
+func TraceCampaignOrchestrator(svc CampaignOrchestratorService) CampaignOrchestratorService {
+	return &tracedCampaignOrchestrator{next: svc}
+}
+
+func startOrchestratorSpan(ctx context.Context, operation string, campaignID uuid.UUID) (context.Context, trace.Span) {
+	var attrs []attribute.KeyValue
+	if campaignID != uuid.Nil {
+		attrs = append(attrs, attribute.String("campaign.id", campaignID.String()))
+	}
+	return tracing.Tracer().Start(ctx, "CampaignOrchestrator."+operation, trace.WithAttributes(attrs...))
+}
+
+func (t *tracedCampaignOrchestrator) CreateCampaignUnified(ctx context.Context, req CreateCampaignRequest) (campaign *models.Campaign, err error) {
+	ctx, span := startOrchestratorSpan(ctx, "CreateCampaignUnified", uuid.Nil)
+	span.SetAttributes(attribute.String("campaign.type", req.CampaignType))
+	defer func() { tracing.End(span, err) }()
+	return t.next.CreateCampaignUnified(ctx, req)
+}
+
+func (t *tracedCampaignOrchestrator) CreateDomainGenerationCampaign(ctx context.Context, req CreateDomainGenerationCampaignRequest) (campaign *models.Campaign, err error) {
+	ctx, span := startOrchestratorSpan(ctx, "CreateDomainGenerationCampaign", uuid.Nil)
+	defer func() { tracing.End(span, err) }()
+	return t.next.CreateDomainGenerationCampaign(ctx, req)
+}
+
+func (t *tracedCampaignOrchestrator) CreateDNSValidationCampaign(ctx context.Context, req CreateDNSValidationCampaignRequest) (campaign *models.Campaign, err error) {
+	ctx, span := startOrchestratorSpan(ctx, "CreateDNSValidationCampaign", uuid.Nil)
+	defer func() { tracing.End(span, err) }()
+	return t.next.CreateDNSValidationCampaign(ctx, req)
+}
+
+func (t *tracedCampaignOrchestrator) CreateHTTPKeywordCampaign(ctx context.Context, req CreateHTTPKeywordCampaignRequest) (campaign *models.Campaign, err error) {
+	ctx, span := startOrchestratorSpan(ctx, "CreateHTTPKeywordCampaign", uuid.Nil)
+	defer func() { tracing.End(span, err) }()
+	return t.next.CreateHTTPKeywordCampaign(ctx, req)
+}
+
+func (t *tracedCampaignOrchestrator) GetCampaignDetails(ctx context.Context, campaignID uuid.UUID) (campaign *models.Campaign, params interface{}, err error) {
+	ctx, span := startOrchestratorSpan(ctx, "GetCampaignDetails", campaignID)
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetCampaignDetails(ctx, campaignID)
+}
+
+func (t *tracedCampaignOrchestrator) GetCampaignStatus(ctx context.Context, campaignID uuid.UUID) (status models.CampaignStatusEnum, progress *float64, err error) {
+	ctx, span := startOrchestratorSpan(ctx, "GetCampaignStatus", campaignID)
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetCampaignStatus(ctx, campaignID)
+}
+
+func (t *tracedCampaignOrchestrator) ListCampaignStatuses(ctx context.Context, campaignIDs []uuid.UUID, userID string) (statuses []store.CampaignStatusSummary, err error) {
+	ctx, span := startOrchestratorSpan(ctx, "ListCampaignStatuses", uuid.Nil)
+	span.SetAttributes(attribute.Int("campaign.count", len(campaignIDs)))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListCampaignStatuses(ctx, campaignIDs, userID)
+}
+
+func (t *tracedCampaignOrchestrator) ListCampaigns(ctx context.Context, filter store.ListCampaignsFilter) (campaigns []models.Campaign, total int64, err error) {
+	ctx, span := startOrchestratorSpan(ctx, "ListCampaigns", uuid.Nil)
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListCampaigns(ctx, filter)
+}
+
+func (t *tracedCampaignOrchestrator) ListCampaignJobs(ctx context.Context, campaignID uuid.UUID, limit, offset int) (resp *CampaignJobHistoryResponse, err error) {
+	ctx, span := startOrchestratorSpan(ctx, "ListCampaignJobs", campaignID)
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListCampaignJobs(ctx, campaignID, limit, offset)
+}
+
+func (t *tracedCampaignOrchestrator) ListDomainDedupDecisions(ctx context.Context, campaignID uuid.UUID, limit, offset int) (decisions []*models.DomainDedupDecision, err error) {
+	ctx, span := startOrchestratorSpan(ctx, "ListDomainDedupDecisions", campaignID)
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListDomainDedupDecisions(ctx, campaignID, limit, offset)
+}
+
+func (t *tracedCampaignOrchestrator) ListCampaignStatusHistory(ctx context.Context, campaignID uuid.UUID, limit, offset int) (history []CampaignStatusTransition, err error) {
+	ctx, span := startOrchestratorSpan(ctx, "ListCampaignStatusHistory", campaignID)
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListCampaignStatusHistory(ctx, campaignID, limit, offset)
+}
+
+func (t *tracedCampaignOrchestrator) GetCampaignStats(ctx context.Context, campaignID uuid.UUID) (stats *CampaignStatsResponse, err error) {
+	ctx, span := startOrchestratorSpan(ctx, "GetCampaignStats", campaignID)
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetCampaignStats(ctx, campaignID)
+}
+
+func (t *tracedCampaignOrchestrator) GetGeneratedDomainsForCampaign(ctx context.Context, campaignID uuid.UUID, limit int, cursor int64) (resp *GeneratedDomainsResponse, err error) {
+	ctx, span := startOrchestratorSpan(ctx, "GetGeneratedDomainsForCampaign", campaignID)
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetGeneratedDomainsForCampaign(ctx, campaignID, limit, cursor)
+}
+
+func (t *tracedCampaignOrchestrator) GetDNSValidationResultsForCampaign(ctx context.Context, campaignID uuid.UUID, limit int, cursor string, filter store.ListValidationResultsFilter) (resp *DNSValidationResultsResponse, err error) {
+	ctx, span := startOrchestratorSpan(ctx, "GetDNSValidationResultsForCampaign", campaignID)
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetDNSValidationResultsForCampaign(ctx, campaignID, limit, cursor, filter)
+}
+
+func (t *tracedCampaignOrchestrator) GetHTTPKeywordResultsForCampaign(ctx context.Context, campaignID uuid.UUID, limit int, cursor string, filter store.ListValidationResultsFilter) (resp *HTTPKeywordResultsResponse, err error) {
+	ctx, span := startOrchestratorSpan(ctx, "GetHTTPKeywordResultsForCampaign", campaignID)
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetHTTPKeywordResultsForCampaign(ctx, campaignID, limit, cursor, filter)
+}
+
+func (t *tracedCampaignOrchestrator) StartCampaign(ctx context.Context, campaignID uuid.UUID) (err error) {
+	ctx, span := startOrchestratorSpan(ctx, "StartCampaign", campaignID)
+	defer func() { tracing.End(span, err) }()
+	return t.next.StartCampaign(ctx, campaignID)
+}
+
+func (t *tracedCampaignOrchestrator) PauseCampaign(ctx context.Context, campaignID uuid.UUID) (err error) {
+	ctx, span := startOrchestratorSpan(ctx, "PauseCampaign", campaignID)
+	defer func() { tracing.End(span, err) }()
+	return t.next.PauseCampaign(ctx, campaignID)
+}
+
+func (t *tracedCampaignOrchestrator) ResumeCampaign(ctx context.Context, campaignID uuid.UUID) (err error) {
+	ctx, span := startOrchestratorSpan(ctx, "ResumeCampaign", campaignID)
+	defer func() { tracing.End(span, err) }()
+	return t.next.ResumeCampaign(ctx, campaignID)
+}
+
+func (t *tracedCampaignOrchestrator) CancelCampaign(ctx context.Context, campaignID uuid.UUID) (err error) {
+	ctx, span := startOrchestratorSpan(ctx, "CancelCampaign", campaignID)
+	defer func() { tracing.End(span, err) }()
+	return t.next.CancelCampaign(ctx, campaignID)
+}
+
+func (t *tracedCampaignOrchestrator) UpdateCampaign(ctx context.Context, campaignID uuid.UUID, req UpdateCampaignRequest) (campaign *models.Campaign, err error) {
+	ctx, span := startOrchestratorSpan(ctx, "UpdateCampaign", campaignID)
+	defer func() { tracing.End(span, err) }()
+	return t.next.UpdateCampaign(ctx, campaignID, req)
+}
+
+func (t *tracedCampaignOrchestrator) DeleteCampaign(ctx context.Context, campaignID uuid.UUID) (err error) {
+	ctx, span := startOrchestratorSpan(ctx, "DeleteCampaign", campaignID)
+	defer func() { tracing.End(span, err) }()
+	return t.next.DeleteCampaign(ctx, campaignID)
+}
+
+func (t *tracedCampaignOrchestrator) SetCampaignErrorStatus(ctx context.Context, campaignID uuid.UUID, errorMessage string) (err error) {
+	ctx, span := startOrchestratorSpan(ctx, "SetCampaignErrorStatus", campaignID)
+	defer func() { tracing.End(span, err) }()
+	return t.next.SetCampaignErrorStatus(ctx, campaignID, errorMessage)
+}
+
+func (t *tracedCampaignOrchestrator) SetCampaignStatus(ctx context.Context, campaignID uuid.UUID, status models.CampaignStatusEnum) (err error) {
+	ctx, span := startOrchestratorSpan(ctx, "SetCampaignStatus", campaignID)
+	span.SetAttributes(attribute.String("campaign.status", string(status)))
+	defer func() { tracing.End(span, err) }()
+	return t.next.SetCampaignStatus(ctx, campaignID, status)
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/jmoiron/sqlx"
+)
+
+// campaignPromotionBatchSize bounds the completed campaigns promoted by one sweep
+const campaignPromotionBatchSize = 50
+
+// ValidateDNSPromotion checks the DNS validation campaign a domain generation campaign is to
+// start on completion. A nil promotion is valid.
+func ValidateDNSPromotion(promotion *models.DNSPromotion) error {
+	if promotion == nil {
+		return nil
+	}
+	if len(promotion.PersonaIDs) == 0 {
+		return fmt.Errorf("dnsPromotion.personaIds: at least one DNS persona is required")
+	}
+	if promotion.RotationIntervalSeconds < 0 || promotion.ProcessingSpeedPerMinute < 0 ||
+		promotion.BatchSize < 0 || promotion.RetryAttempts < 0 || promotion.ConsensusQuorum < 0 {
+		return fmt.Errorf("dnsPromotion: numeric settings must not be negative")
+	}
+	if err := validateDNSConsensusParams(promotion.ConsensusMode, promotion.ConsensusQuorum, len(promotion.PersonaIDs)); err != nil {
+		return fmt.Errorf("dnsPromotion: %w", err)
+	}
+	if err := ValidateDNSExpectedRecords(promotion.ExpectedRecords); err != nil {
+		return fmt.Errorf("dnsPromotion.%w", err)
+	}
+	return nil
+}
+
+// normalizeDNSPromotion returns a copy of the promotion with its name and consensus mode trimmed
+func normalizeDNSPromotion(promotion *models.DNSPromotion) *models.DNSPromotion {
+	if promotion == nil {
+		return nil
+	}
+	normalized := *promotion
+	normalized.Name = strings.TrimSpace(normalized.Name)
+	normalized.ConsensusMode = strings.ToLower(strings.TrimSpace(normalized.ConsensusMode))
+	return &normalized
+}
+
+// CampaignAutoPromoter starts the DNS validation campaign of every completed domain generation
+// campaign that was created with a dnsPromotion. The DNS campaign takes the generation campaign
+// as its source, belongs to the same user with the same tags, and points back at it through
+// PromotedFromCampaignID. A generation campaign is promoted at most once: a campaign already
+// pointing back at it is never listed again, and the database refuses a second one.
+type CampaignAutoPromoter struct {
+	db            *sqlx.DB
+	campaignStore store.CampaignStore
+	dnsService    DNSCampaignService          // Creates the DNS validation campaigns
+	orchestrator  CampaignOrchestratorService // Starts them
+	interval      time.Duration
+}
+
+// NewCampaignAutoPromoter creates a promoter checking on the configured interval
+func NewCampaignAutoPromoter(appCfg *config.AppConfig, db *sqlx.DB, cs store.CampaignStore, dnsService DNSCampaignService, orchestrator CampaignOrchestratorService) *CampaignAutoPromoter {
+	interval := time.Duration(config.DefaultCampaignPromotionCheckIntervalSeconds) * time.Second
+	if appCfg != nil && appCfg.CampaignPromotion.CheckIntervalSeconds > 0 {
+		interval = time.Duration(appCfg.CampaignPromotion.CheckIntervalSeconds) * time.Second
+	}
+	return &CampaignAutoPromoter{
+		db:            db,
+		campaignStore: cs,
+		dnsService:    dnsService,
+		orchestrator:  orchestrator,
+		interval:      interval,
+	}
+}
+
+// Start promotes completed campaigns every check interval until ctx is cancelled
+func (p *CampaignAutoPromoter) Start(ctx context.Context) {
+	if p == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.PromoteDue(ctx)
+			}
+		}
+	}()
+}
+
+// PromoteDue creates and starts the DNS validation campaign of every completed domain generation
+// campaign awaiting promotion and returns the campaigns it created
+func (p *CampaignAutoPromoter) PromoteDue(ctx context.Context) []*models.Campaign {
+	if p == nil {
+		return nil
+	}
+	var querier store.Querier
+	if p.db != nil {
+		querier = p.db
+	}
+	completed, err := p.campaignStore.ListCompletedCampaignsToPromote(ctx, querier, campaignPromotionBatchSize)
+	if err != nil {
+		log.Printf("CampaignAutoPromoter: Failed to list completed campaigns: %v", err)
+		return nil
+	}
+	var promoted []*models.Campaign
+	for _, generation := range completed {
+		dnsCampaign, err := p.promote(ctx, querier, generation)
+		if err != nil {
+			log.Printf("CampaignAutoPromoter: Failed to promote campaign %s: %v", generation.ID, err)
+			if dnsCampaign == nil {
+				continue
+			}
+		} else {
+			log.Printf("CampaignAutoPromoter: Campaign %s completed; started DNS validation campaign %s", generation.ID, dnsCampaign.ID)
+		}
+		promoted = append(promoted, dnsCampaign)
+	}
+	return promoted
+}
+
+// promote creates the DNS validation campaign of a completed generation campaign and starts it.
+// A campaign that cannot be started stays pending and linked, and is not created again.
+func (p *CampaignAutoPromoter) promote(ctx context.Context, querier store.Querier, generation *models.Campaign) (*models.Campaign, error) {
+	params, err := p.campaignStore.GetDomainGenerationParams(ctx, querier, generation.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load domain generation params: %w", err)
+	}
+	promotion := params.DNSPromotion
+	if promotion == nil {
+		return nil, fmt.Errorf("campaign has no DNS promotion")
+	}
+
+	req := CreateDNSValidationCampaignRequest{
+		Name:                       promotion.Name,
+		SourceGenerationCampaignID: generation.ID,
+		PersonaIDs:                 promotion.PersonaIDs,
+		RotationIntervalSeconds:    promotion.RotationIntervalSeconds,
+		ProcessingSpeedPerMinute:   promotion.ProcessingSpeedPerMinute,
+		BatchSize:                  promotion.BatchSize,
+		RetryAttempts:              promotion.RetryAttempts,
+		DeduplicateDomains:         promotion.DeduplicateDomains,
+		ConsensusMode:              promotion.ConsensusMode,
+		ConsensusQuorum:            promotion.ConsensusQuorum,
+		ExpectedRecords:            promotion.ExpectedRecords,
+		Tags:                       generation.Tags,
+		AutoRetry:                  promotion.AutoRetry,
+		PromotedFromCampaignID:     &generation.ID,
+	}
+	if req.Name == "" {
+		req.Name = generation.Name + " (DNS)"
+	}
+	if generation.UserID != nil {
+		req.UserID = *generation.UserID
+	}
+
+	dnsCampaign, err := p.dnsService.CreateCampaign(ctx, req)
+	if errors.Is(err, store.ErrCampaignNameTaken) && promotion.Name == "" {
+		// The default name is taken by an earlier campaign of the user; tell them apart by source
+		req.Name = fmt.Sprintf("%s (DNS %s)", generation.Name, generation.ID.String()[:8])
+		dnsCampaign, err = p.dnsService.CreateCampaign(ctx, req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DNS validation campaign: %w", err)
+	}
+
+	startCtx := WithCampaignStatusActor(ctx, CampaignStatusActor{
+		Type:   StatusActorSystem,
+		Reason: fmt.Sprintf("DNS promotion of completed campaign %s", generation.ID),
+	})
+	if err := p.orchestrator.StartCampaign(startCtx, dnsCampaign.ID); err != nil {
+		return dnsCampaign, fmt.Errorf("created DNS validation campaign %s but could not start it: %w", dnsCampaign.ID, err)
+	}
+	dnsCampaign.Status = models.CampaignStatusQueued
+	return dnsCampaign, nil
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// promotionCampaignStore adds domain generation params to the in-memory retry store
+type promotionCampaignStore struct {
+	*retryCampaignStore
+	genParams map[uuid.UUID]*models.DomainGenerationCampaignParams
+}
+
+func (s *promotionCampaignStore) GetDomainGenerationParams(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.DomainGenerationCampaignParams, error) {
+	params, ok := s.genParams[campaignID]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return params, nil
+}
+
+func (s *promotionCampaignStore) ListCompletedCampaignsToPromote(ctx context.Context, exec store.Querier, limit int) ([]*models.Campaign, error) {
+	promoted := make(map[uuid.UUID]bool)
+	for _, campaign := range s.campaigns {
+		if campaign.PromotedFromCampaignID != nil {
+			promoted[*campaign.PromotedFromCampaignID] = true
+		}
+	}
+	var completed []*models.Campaign
+	for _, campaign := range s.campaigns {
+		params := s.genParams[campaign.ID]
+		if campaign.Status == models.CampaignStatusCompleted && params != nil && params.DNSPromotion != nil && !promoted[campaign.ID] {
+			completed = append(completed, campaign)
+		}
+	}
+	if len(completed) > limit {
+		completed = completed[:limit]
+	}
+	return completed, nil
+}
+
+// recordingDNSService creates DNS campaigns in the store, refusing a second campaign promoted from
+// the same generation campaign as the unique index does
+type recordingDNSService struct {
+	DNSCampaignService
+	campaignStore *promotionCampaignStore
+	requests      []CreateDNSValidationCampaignRequest
+}
+
+func (s *recordingDNSService) CreateCampaign(ctx context.Context, req CreateDNSValidationCampaignRequest) (*models.Campaign, error) {
+	for _, campaign := range s.campaignStore.campaigns {
+		if campaign.PromotedFromCampaignID != nil && req.PromotedFromCampaignID != nil && *campaign.PromotedFromCampaignID == *req.PromotedFromCampaignID {
+			return nil, fmt.Errorf("%w: campaign %s was already promoted", store.ErrDuplicateEntry, *req.PromotedFromCampaignID)
+		}
+	}
+	s.requests = append(s.requests, req)
+	userID := req.UserID
+	campaign := &models.Campaign{ID: uuid.New(), Name: req.Name, CampaignType: models.CampaignTypeDNSValidation,
+		Status: models.CampaignStatusPending, UserID: &userID, Tags: req.Tags, AutoRetry: req.AutoRetry,
+		PromotedFromCampaignID: req.PromotedFromCampaignID}
+	if err := s.campaignStore.CreateCampaign(ctx, nil, campaign); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+func TestCampaignAutoPromoter_StartsDNSCampaignOnceOnCompletion(t *testing.T) {
+	ctx := context.Background()
+	cs := &promotionCampaignStore{retryCampaignStore: newRetryCampaignStore(), genParams: make(map[uuid.UUID]*models.DomainGenerationCampaignParams)}
+	dnsService := &recordingDNSService{campaignStore: cs}
+	orchestrator := &queueingOrchestrator{campaignStore: cs.retryCampaignStore}
+	promoter := NewCampaignAutoPromoter(nil, nil, cs, dnsService, orchestrator)
+
+	userID := uuid.New()
+	personaID := uuid.New()
+	generation := &models.Campaign{ID: uuid.New(), Name: "Brand sweep", CampaignType: models.CampaignTypeDomainGeneration,
+		Status: models.CampaignStatusRunning, UserID: &userID, Tags: []string{"brand"}}
+	require.NoError(t, cs.CreateCampaign(ctx, nil, generation))
+	cs.genParams[generation.ID] = &models.DomainGenerationCampaignParams{CampaignID: generation.ID, DNSPromotion: &models.DNSPromotion{
+		PersonaIDs: []uuid.UUID{personaID}, BatchSize: 25, DeduplicateDomains: true, AutoRetry: true}}
+
+	// A completed campaign without a promotion is left alone
+	manual := &models.Campaign{ID: uuid.New(), Name: "Manual", CampaignType: models.CampaignTypeDomainGeneration, Status: models.CampaignStatusCompleted}
+	require.NoError(t, cs.CreateCampaign(ctx, nil, manual))
+	cs.genParams[manual.ID] = &models.DomainGenerationCampaignParams{CampaignID: manual.ID}
+
+	assert.Empty(t, promoter.PromoteDue(ctx), "a running campaign is not promoted")
+
+	cs.campaigns[generation.ID].Status = models.CampaignStatusCompleted
+	promoted := promoter.PromoteDue(ctx)
+	require.Len(t, promoted, 1)
+	dnsCampaign := cs.campaigns[promoted[0].ID]
+	assert.Equal(t, models.CampaignStatusQueued, dnsCampaign.Status)
+	assert.Equal(t, "Brand sweep (DNS)", dnsCampaign.Name)
+	require.NotNil(t, dnsCampaign.PromotedFromCampaignID)
+	assert.Equal(t, generation.ID, *dnsCampaign.PromotedFromCampaignID)
+	assert.Equal(t, &userID, dnsCampaign.UserID)
+	assert.Equal(t, []string{"brand"}, []string(dnsCampaign.Tags))
+	assert.True(t, dnsCampaign.AutoRetry)
+
+	require.Len(t, dnsService.requests, 1)
+	req := dnsService.requests[0]
+	assert.Equal(t, generation.ID, req.SourceGenerationCampaignID)
+	assert.Equal(t, []uuid.UUID{personaID}, req.PersonaIDs)
+	assert.Equal(t, 25, req.BatchSize)
+	assert.True(t, req.DeduplicateDomains)
+
+	// Later sweeps find nothing left to promote
+	assert.Empty(t, promoter.PromoteDue(ctx))
+	assert.Empty(t, promoter.PromoteDue(ctx))
+	assert.Len(t, dnsService.requests, 1)
+	assert.Equal(t, []uuid.UUID{dnsCampaign.ID}, orchestrator.started)
+
+	// A second instance racing the first is refused by the store
+	_, err := promoter.promote(ctx, nil, generation)
+	assert.ErrorIs(t, err, store.ErrDuplicateEntry)
+	assert.Len(t, orchestrator.started, 1)
+}
+
+func TestValidateDNSPromotion(t *testing.T) {
+	personas := []uuid.UUID{uuid.New(), uuid.New()}
+	assert.NoError(t, ValidateDNSPromotion(nil))
+	assert.NoError(t, ValidateDNSPromotion(&models.DNSPromotion{PersonaIDs: personas, ConsensusMode: "majority"}))
+
+	invalid := map[string]*models.DNSPromotion{
+		"no personas":       {},
+		"negative batch":    {PersonaIDs: personas, BatchSize: -1},
+		"unknown consensus": {PersonaIDs: personas, ConsensusMode: "most"},
+		"quorum too large":  {PersonaIDs: personas, ConsensusMode: "majority", ConsensusQuorum: 3},
+		"bad expected CIDR": {PersonaIDs: personas, ExpectedRecords: &models.DNSExpectedRecords{ACIDRs: []string{"2001:db8::/32"}}},
+	}
+	for name, promotion := range invalid {
+		assert.Error(t, ValidateDNSPromotion(promotion), name)
+	}
+}
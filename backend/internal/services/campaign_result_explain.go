@@ -0,0 +1,470 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/circuitbreaker"
+	"github.com/fntelecomllc/studio/backend/internal/keywordscanner"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrCampaignNotExplainable is returned when explaining a domain of a campaign that validates nothing
+var ErrCampaignNotExplainable = errors.New("campaign has no validation results to explain")
+
+// Overall outcomes of an explained domain
+const (
+	DomainOutcomePassed     = "passed"      // The domain passed every check the campaign makes
+	DomainOutcomeFailed     = "failed"      // A check failed; errorCategory says which
+	DomainOutcomeNotChecked = "not_checked" // The source campaign has a DNS result but HTTP was never tried
+)
+
+// Error categories of explained DNS and HTTP results. Results that passed have none.
+const (
+	ResultErrorDNSNotFound         = "dns_not_found"
+	ResultErrorDNSTimeout          = "dns_timeout"
+	ResultErrorDNSError            = "dns_error"
+	ResultErrorHTTPStatus          = "http_status"
+	ResultErrorHTTPRequest         = "http_request_error"
+	ResultErrorCertPinMismatch     = "cert_pin_mismatch"
+	ResultErrorNoKeywords          = "no_keywords_matched"
+	ResultErrorCircuitOpen         = "circuit_open"
+	ResultErrorMaxAttemptsExceeded = "max_attempts_exceeded"
+	ResultErrorCancelled           = "cancelled"
+	ResultErrorProcessing          = "processing_error"
+)
+
+// DomainExplanation combines what a campaign stored about one domain into why it ended as it did.
+// Narrative restates the structured DNS and HTTP sections as sentences.
+type DomainExplanation struct {
+	CampaignID    uuid.UUID               `json:"campaignId"`
+	CampaignType  models.CampaignTypeEnum `json:"campaignType"`
+	DomainName    string                  `json:"domainName"`
+	Outcome       string                  `json:"outcome"`
+	ErrorCategory string                  `json:"errorCategory,omitempty"`
+	DNS           *DNSResultExplanation   `json:"dns,omitempty"`
+	HTTP          *HTTPResultExplanation  `json:"http,omitempty"`
+	Narrative     []string                `json:"narrative,omitempty"`
+}
+
+// DNSResultExplanation is a domain's DNS result. For an HTTP keyword campaign it comes from the
+// source DNS campaign.
+type DNSResultExplanation struct {
+	CampaignID       uuid.UUID          `json:"campaignId"`
+	ValidationStatus string             `json:"validationStatus"`
+	ErrorCategory    string             `json:"errorCategory,omitempty"`
+	Error            string             `json:"error,omitempty"`
+	IPs              []string           `json:"ips,omitempty"`
+	Expectation      string             `json:"expectation,omitempty"`
+	Persona          *ExplainedResource `json:"persona,omitempty"`
+	Attempts         int                `json:"attempts"`
+	LastCheckedAt    *time.Time         `json:"lastCheckedAt,omitempty"`
+}
+
+// HTTPResultExplanation is a domain's HTTP keyword result with the keywords it matched and missed.
+// Missed keywords are only known when the page was fetched and scanned.
+type HTTPResultExplanation struct {
+	CampaignID             uuid.UUID                  `json:"campaignId"`
+	ValidationStatus       string                     `json:"validationStatus"`
+	ErrorCategory          string                     `json:"errorCategory,omitempty"`
+	ValidationMode         string                     `json:"validationMode"`
+	HTTPStatusCode         *int32                     `json:"httpStatusCode,omitempty"`
+	PageTitle              *string                    `json:"pageTitle,omitempty"`
+	MatchedKeywords        []keywordscanner.RuleMatch `json:"matchedKeywords"`
+	MatchedAdHocKeywords   []string                   `json:"matchedAdHocKeywords"`
+	MissedAdHocKeywords    []string                   `json:"missedAdHocKeywords"`
+	UnmatchedKeywordSetIDs []uuid.UUID                `json:"unmatchedKeywordSetIds"`
+	Persona                *ExplainedResource         `json:"persona,omitempty"`
+	Proxy                  *ExplainedResource         `json:"proxy,omitempty"`
+	Attempts               int                        `json:"attempts"`
+	LastCheckedAt          *time.Time                 `json:"lastCheckedAt,omitempty"`
+}
+
+// ExplainedResource names the persona or proxy that produced a result
+type ExplainedResource struct {
+	ID      uuid.UUID `json:"id"`
+	Name    string    `json:"name,omitempty"`
+	Missing bool      `json:"missing,omitempty"` // Deleted since the result was stored
+}
+
+// CampaignResultExplainer explains single domains of validation campaigns from their stored
+// results. Nothing is validated again.
+type CampaignResultExplainer struct {
+	db            *sqlx.DB
+	campaignStore store.CampaignStore
+	personaStore  store.PersonaStore
+	proxyStore    store.ProxyStore
+}
+
+// NewCampaignResultExplainer names personas and proxies from ps and pxs; either may be nil, which
+// leaves the IDs unnamed
+func NewCampaignResultExplainer(db *sqlx.DB, cs store.CampaignStore, ps store.PersonaStore, pxs store.ProxyStore) *CampaignResultExplainer {
+	return &CampaignResultExplainer{db: db, campaignStore: cs, personaStore: ps, proxyStore: pxs}
+}
+
+func (e *CampaignResultExplainer) querier() store.Querier {
+	if e.db != nil {
+		return e.db
+	}
+	return nil
+}
+
+// ExplainDomain explains the outcome of domainName in a DNS validation or HTTP keyword campaign.
+// It returns store.ErrNotFound when the campaign does not exist or has no result for the domain,
+// and ErrCampaignNotExplainable for other campaign types.
+func (e *CampaignResultExplainer) ExplainDomain(ctx context.Context, campaignID uuid.UUID, domainName string) (*DomainExplanation, error) {
+	querier := e.querier()
+	campaign, err := e.campaignStore.GetCampaignByID(ctx, querier, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	explanation := &DomainExplanation{CampaignID: campaign.ID, CampaignType: campaign.CampaignType, DomainName: domainName}
+
+	switch campaign.CampaignType {
+	case models.CampaignTypeDNSValidation:
+		dnsResult, err := e.campaignStore.GetDNSValidationResultByDomain(ctx, querier, campaignID, domainName)
+		if err != nil {
+			return nil, err
+		}
+		explanation.DNS = e.explainDNSResult(ctx, dnsResult)
+	case models.CampaignTypeHTTPKeywordValidation:
+		params, err := e.campaignStore.GetHTTPKeywordParams(ctx, querier, campaignID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load HTTP keyword params of campaign %s: %w", campaignID, err)
+		}
+		httpResult, err := e.campaignStore.GetHTTPKeywordResultByDomain(ctx, querier, campaignID, domainName)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			return nil, err
+		}
+		if httpResult != nil {
+			explanation.HTTP = e.explainHTTPResult(ctx, params, httpResult)
+		}
+		dnsResult, err := e.campaignStore.GetDNSValidationResultByDomain(ctx, querier, params.SourceCampaignID, domainName)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			return nil, err
+		}
+		if dnsResult != nil {
+			explanation.DNS = e.explainDNSResult(ctx, dnsResult)
+		}
+		if httpResult == nil && dnsResult == nil {
+			return nil, store.ErrNotFound
+		}
+	default:
+		return nil, ErrCampaignNotExplainable
+	}
+
+	explanation.Outcome, explanation.ErrorCategory = domainOutcome(explanation)
+	explanation.Narrative = narrateExplanation(explanation)
+	return explanation, nil
+}
+
+func (e *CampaignResultExplainer) explainDNSResult(ctx context.Context, result *models.DNSValidationResult) *DNSResultExplanation {
+	explained := &DNSResultExplanation{
+		CampaignID:       result.DNSCampaignID,
+		ValidationStatus: result.ValidationStatus,
+		ErrorCategory:    dnsErrorCategory(result.ValidationStatus),
+		Expectation:      result.Expectation,
+		Persona:          e.persona(ctx, result.ValidatedByPersonaID),
+		Attempts:         storedAttempts(result.Attempts),
+		LastCheckedAt:    result.LastCheckedAt,
+	}
+	// dns_records holds the resolved addresses, or {"error": ...} when resolution failed
+	if result.DNSRecords != nil {
+		var failure struct {
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal(*result.DNSRecords, &explained.IPs); err != nil && json.Unmarshal(*result.DNSRecords, &failure) == nil {
+			explained.Error = failure.Error
+		}
+	}
+	return explained
+}
+
+func (e *CampaignResultExplainer) explainHTTPResult(ctx context.Context, params *models.HTTPKeywordCampaignParams, result *models.HTTPKeywordResult) *HTTPResultExplanation {
+	mode := HTTPValidationModeKeywords
+	if isHTTPLivenessOnly(params) {
+		mode = HTTPValidationModeLivenessOnly
+	}
+	explained := &HTTPResultExplanation{
+		CampaignID:             result.HTTPKeywordCampaignID,
+		ValidationStatus:       result.ValidationStatus,
+		ErrorCategory:          httpErrorCategory(result.ValidationStatus, result.HTTPStatusCode),
+		ValidationMode:         mode,
+		HTTPStatusCode:         result.HTTPStatusCode,
+		PageTitle:              result.PageTitle,
+		MatchedKeywords:        storedRuleMatches(result.FoundKeywordsFromSets),
+		MatchedAdHocKeywords:   []string{},
+		MissedAdHocKeywords:    []string{},
+		UnmatchedKeywordSetIDs: []uuid.UUID{},
+		Persona:                e.persona(ctx, result.ValidatedByPersonaID),
+		Proxy:                  e.proxy(ctx, result.UsedProxyID),
+		Attempts:               storedAttempts(result.Attempts),
+		LastCheckedAt:          result.LastCheckedAt,
+	}
+	if result.FoundAdHocKeywords != nil {
+		explained.MatchedAdHocKeywords = append(explained.MatchedAdHocKeywords, *result.FoundAdHocKeywords...)
+	}
+	if result.ValidationStatus != httpStatusLeadValid && result.ValidationStatus != httpStatusNoKeywords {
+		return explained
+	}
+
+	// The page was scanned, so every keyword that did not match was missed
+	matched := make(map[string]bool, len(explained.MatchedAdHocKeywords))
+	for _, keyword := range explained.MatchedAdHocKeywords {
+		matched[keyword] = true
+	}
+	if params.AdHocKeywords != nil {
+		for _, keyword := range *params.AdHocKeywords {
+			if !matched[keyword] {
+				explained.MissedAdHocKeywords = append(explained.MissedAdHocKeywords, keyword)
+			}
+		}
+	}
+	matchedSets := make(map[uuid.UUID]bool, len(explained.MatchedKeywords))
+	for _, match := range explained.MatchedKeywords {
+		matchedSets[match.KeywordSetID] = true
+	}
+	for _, setID := range params.KeywordSetIDs {
+		if !matchedSets[setID] {
+			explained.UnmatchedKeywordSetIDs = append(explained.UnmatchedKeywordSetIDs, setID)
+		}
+	}
+	return explained
+}
+
+func (e *CampaignResultExplainer) persona(ctx context.Context, id uuid.NullUUID) *ExplainedResource {
+	if !id.Valid {
+		return nil
+	}
+	resource := &ExplainedResource{ID: id.UUID}
+	if e.personaStore == nil {
+		return resource
+	}
+	persona, err := e.personaStore.GetPersonaByID(ctx, e.querier(), id.UUID)
+	switch {
+	case err == nil:
+		resource.Name = persona.Name
+	case errors.Is(err, store.ErrNotFound):
+		resource.Missing = true
+	}
+	return resource
+}
+
+func (e *CampaignResultExplainer) proxy(ctx context.Context, id uuid.NullUUID) *ExplainedResource {
+	if !id.Valid {
+		return nil
+	}
+	resource := &ExplainedResource{ID: id.UUID}
+	if e.proxyStore == nil {
+		return resource
+	}
+	proxy, err := e.proxyStore.GetProxyByID(ctx, e.querier(), id.UUID)
+	switch {
+	case err == nil:
+		resource.Name = proxy.Name
+	case errors.Is(err, store.ErrNotFound):
+		resource.Missing = true
+	}
+	return resource
+}
+
+// storedRuleMatches decodes found_keywords_from_sets. Results stored before matches named their
+// rules hold plain strings, which are returned as patterns.
+func storedRuleMatches(raw *json.RawMessage) []keywordscanner.RuleMatch {
+	matches := []keywordscanner.RuleMatch{}
+	if raw == nil {
+		return matches
+	}
+	if err := json.Unmarshal(*raw, &matches); err == nil {
+		return matches
+	}
+	var patterns []string
+	if err := json.Unmarshal(*raw, &patterns); err == nil {
+		for _, pattern := range patterns {
+			matches = append(matches, keywordscanner.RuleMatch{Pattern: pattern, Matched: pattern})
+		}
+	}
+	return matches
+}
+
+func dnsErrorCategory(status string) string {
+	switch status {
+	case "Resolved", "valid_dns":
+		return ""
+	case "Not Found":
+		return ResultErrorDNSNotFound
+	case "Timeout":
+		return ResultErrorDNSTimeout
+	case "Cancelled":
+		return ResultErrorCancelled
+	case circuitbreaker.StatusCircuitOpen:
+		return ResultErrorCircuitOpen
+	case string(models.ValidationStatusMaxAttemptsExceeded):
+		return ResultErrorMaxAttemptsExceeded
+	default:
+		return ResultErrorDNSError
+	}
+}
+
+func httpErrorCategory(status string, statusCode *int32) string {
+	switch status {
+	case httpStatusLeadValid, httpLivenessStatusValid:
+		return ""
+	case httpStatusNoKeywords:
+		return ResultErrorNoKeywords
+	case "invalid_http_code", httpLivenessStatusInvalid:
+		if statusCode == nil {
+			return ResultErrorHTTPRequest
+		}
+		return ResultErrorHTTPStatus
+	case "invalid_http_response_error":
+		return ResultErrorHTTPRequest
+	case httpStatusCertPinMismatch:
+		return ResultErrorCertPinMismatch
+	case circuitbreaker.StatusCircuitOpen:
+		return ResultErrorCircuitOpen
+	case string(models.ValidationStatusMaxAttemptsExceeded):
+		return ResultErrorMaxAttemptsExceeded
+	case "cancelled_during_processing":
+		return ResultErrorCancelled
+	default:
+		return ResultErrorProcessing
+	}
+}
+
+// domainOutcome takes the HTTP result when there is one; a DNS result alone decides DNS campaigns
+// and HTTP keyword campaigns that never fetched the domain
+func domainOutcome(explanation *DomainExplanation) (string, string) {
+	if explanation.HTTP != nil {
+		if explanation.HTTP.ErrorCategory != "" {
+			return DomainOutcomeFailed, explanation.HTTP.ErrorCategory
+		}
+		return DomainOutcomePassed, ""
+	}
+	if explanation.DNS.ErrorCategory != "" {
+		return DomainOutcomeFailed, explanation.DNS.ErrorCategory
+	}
+	if explanation.CampaignType == models.CampaignTypeHTTPKeywordValidation {
+		return DomainOutcomeNotChecked, ""
+	}
+	return DomainOutcomePassed, ""
+}
+
+func narrateExplanation(explanation *DomainExplanation) []string {
+	var narrative []string
+	if dns := explanation.DNS; dns != nil {
+		var sentence string
+		if dns.ErrorCategory == "" {
+			sentence = fmt.Sprintf("DNS resolved %s", explanation.DomainName)
+			if len(dns.IPs) > 0 {
+				sentence += " to " + strings.Join(dns.IPs, ", ")
+			}
+		} else {
+			sentence = fmt.Sprintf("DNS failed for %s with status %q", explanation.DomainName, dns.ValidationStatus)
+			if dns.Error != "" {
+				sentence += ": " + dns.Error
+			}
+		}
+		narrative = append(narrative, sentence+usedResources(dns.Persona, nil)+attemptsClause(dns.Attempts)+".")
+		if dns.Expectation == models.DNSExpectationUnexpected {
+			narrative = append(narrative, "The resolved records did not match the campaign's expected records.")
+		}
+	}
+
+	httpResult := explanation.HTTP
+	switch {
+	case httpResult == nil && explanation.Outcome == DomainOutcomeNotChecked:
+		narrative = append(narrative, "The domain has not been fetched over HTTP yet.")
+	case httpResult == nil && explanation.CampaignType == models.CampaignTypeHTTPKeywordValidation:
+		narrative = append(narrative, "The domain was not fetched over HTTP because DNS validation failed.")
+	case httpResult != nil:
+		var sentence string
+		if httpResult.HTTPStatusCode != nil {
+			sentence = fmt.Sprintf("The HTTP request answered with status %d", *httpResult.HTTPStatusCode)
+		} else {
+			sentence = "The HTTP request got no response"
+		}
+		sentence += fmt.Sprintf(" and was recorded as %q", httpResult.ValidationStatus)
+		narrative = append(narrative, sentence+usedResources(httpResult.Persona, httpResult.Proxy)+attemptsClause(httpResult.Attempts)+".")
+		if httpResult.ValidationMode == HTTPValidationModeLivenessOnly {
+			break
+		}
+		if len(httpResult.MatchedKeywords) > 0 || len(httpResult.MatchedAdHocKeywords) > 0 {
+			var found []string
+			for _, match := range httpResult.MatchedKeywords {
+				found = append(found, fmt.Sprintf("%q (matched %q)", match.Pattern, match.Matched))
+			}
+			for _, keyword := range httpResult.MatchedAdHocKeywords {
+				found = append(found, fmt.Sprintf("%q", keyword))
+			}
+			narrative = append(narrative, "Keywords found: "+strings.Join(found, ", ")+".")
+		} else if httpResult.ErrorCategory == ResultErrorNoKeywords {
+			narrative = append(narrative, "The page was fetched but none of the campaign's keywords were found.")
+		}
+		if len(httpResult.MissedAdHocKeywords) > 0 {
+			narrative = append(narrative, "Ad hoc keywords not found: "+strings.Join(httpResult.MissedAdHocKeywords, ", ")+".")
+		}
+		if len(httpResult.UnmatchedKeywordSetIDs) > 0 {
+			narrative = append(narrative, fmt.Sprintf("%d of %d keyword sets had no matching rule.",
+				len(httpResult.UnmatchedKeywordSetIDs), len(httpResult.UnmatchedKeywordSetIDs)+countMatchedSets(httpResult.MatchedKeywords)))
+		}
+	}
+	return narrative
+}
+
+func usedResources(persona, proxy *ExplainedResource) string {
+	var clause string
+	if persona != nil {
+		clause += " using persona " + resourceLabel(persona)
+	}
+	if proxy != nil {
+		clause += " through proxy " + resourceLabel(proxy)
+	}
+	return clause
+}
+
+func resourceLabel(resource *ExplainedResource) string {
+	switch {
+	case resource.Name != "":
+		return fmt.Sprintf("%q", resource.Name)
+	case resource.Missing:
+		return resource.ID.String() + " (since deleted)"
+	default:
+		return resource.ID.String()
+	}
+}
+
+func attemptsClause(attempts int) string {
+	switch {
+	case attempts == 1:
+		return " after 1 attempt"
+	case attempts > 1:
+		return fmt.Sprintf(" after %d attempts", attempts)
+	default:
+		return ""
+	}
+}
+
+func storedAttempts(attempts *int) int {
+	if attempts == nil {
+		return 0
+	}
+	return *attempts
+}
+
+func countMatchedSets(matches []keywordscanner.RuleMatch) int {
+	sets := make(map[uuid.UUID]bool)
+	for _, match := range matches {
+		if match.KeywordSetID != uuid.Nil {
+			sets[match.KeywordSetID] = true
+		}
+	}
+	return len(sets)
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/keywordscanner"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// explainedCampaignStore holds an HTTP keyword campaign, its source DNS campaign and their results
+type explainedCampaignStore struct {
+	store.CampaignStore
+	campaigns   map[uuid.UUID]*models.Campaign
+	params      *models.HTTPKeywordCampaignParams
+	dnsResults  map[string]*models.DNSValidationResult
+	httpResults map[string]*models.HTTPKeywordResult
+}
+
+func (s *explainedCampaignStore) GetCampaignByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Campaign, error) {
+	if campaign, ok := s.campaigns[id]; ok {
+		return campaign, nil
+	}
+	return nil, store.ErrNotFound
+}
+
+func (s *explainedCampaignStore) GetHTTPKeywordParams(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.HTTPKeywordCampaignParams, error) {
+	return s.params, nil
+}
+
+func (s *explainedCampaignStore) GetDNSValidationResultByDomain(ctx context.Context, exec store.Querier, campaignID uuid.UUID, domainName string) (*models.DNSValidationResult, error) {
+	if result, ok := s.dnsResults[domainName]; ok && result.DNSCampaignID == campaignID {
+		return result, nil
+	}
+	return nil, store.ErrNotFound
+}
+
+func (s *explainedCampaignStore) GetHTTPKeywordResultByDomain(ctx context.Context, exec store.Querier, campaignID uuid.UUID, domainName string) (*models.HTTPKeywordResult, error) {
+	if result, ok := s.httpResults[domainName]; ok && result.HTTPKeywordCampaignID == campaignID {
+		return result, nil
+	}
+	return nil, store.ErrNotFound
+}
+
+// namedPersonaStore serves personas by ID
+type namedPersonaStore struct {
+	store.PersonaStore
+	personas map[uuid.UUID]*models.Persona
+}
+
+func (s *namedPersonaStore) GetPersonaByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Persona, error) {
+	if persona, ok := s.personas[id]; ok {
+		return persona, nil
+	}
+	return nil, store.ErrNotFound
+}
+
+// namedProxyStore serves proxies by ID
+type namedProxyStore struct {
+	store.ProxyStore
+	proxies map[uuid.UUID]*models.Proxy
+}
+
+func (s *namedProxyStore) GetProxyByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Proxy, error) {
+	if proxy, ok := s.proxies[id]; ok {
+		return proxy, nil
+	}
+	return nil, store.ErrNotFound
+}
+
+type explainFixture struct {
+	cs        *explainedCampaignStore
+	dnsID     uuid.UUID
+	httpID    uuid.UUID
+	setID     uuid.UUID
+	dnsPerson *models.Persona
+	browser   *models.Persona
+	proxy     *models.Proxy
+	explainer *CampaignResultExplainer
+}
+
+func newExplainFixture() *explainFixture {
+	f := &explainFixture{
+		dnsID:     uuid.New(),
+		httpID:    uuid.New(),
+		setID:     uuid.New(),
+		dnsPerson: &models.Persona{ID: uuid.New(), Name: "Public resolvers"},
+		browser:   &models.Persona{ID: uuid.New(), Name: "Desktop Chrome"},
+		proxy:     &models.Proxy{ID: uuid.New(), Name: "eu-west"},
+	}
+	one, three := 1, 3
+	status := func(code int32) *int32 { return &code }
+	title := "Royal Casino"
+	found := func(matches ...keywordscanner.RuleMatch) *json.RawMessage {
+		encoded, _ := json.Marshal(matches)
+		return models.JSONRawMessagePtr(encoded)
+	}
+	resolved := func(domain string) *models.DNSValidationResult {
+		return &models.DNSValidationResult{
+			ID: uuid.New(), DNSCampaignID: f.dnsID, DomainName: domain, ValidationStatus: "Resolved",
+			DNSRecords: models.JSONRawMessagePtr(json.RawMessage(`["192.0.2.10"]`)), Attempts: &one,
+			ValidatedByPersonaID: uuid.NullUUID{UUID: f.dnsPerson.ID, Valid: true},
+		}
+	}
+	f.cs = &explainedCampaignStore{
+		campaigns: map[uuid.UUID]*models.Campaign{
+			f.dnsID:  {ID: f.dnsID, CampaignType: models.CampaignTypeDNSValidation},
+			f.httpID: {ID: f.httpID, CampaignType: models.CampaignTypeHTTPKeywordValidation},
+		},
+		params: &models.HTTPKeywordCampaignParams{
+			CampaignID: f.httpID, SourceCampaignID: f.dnsID, KeywordSetIDs: []uuid.UUID{f.setID},
+			AdHocKeywords: &[]string{"poker", "bingo"},
+		},
+		dnsResults: map[string]*models.DNSValidationResult{
+			"lead.com":      resolved("lead.com"),
+			"down.com":      resolved("down.com"),
+			"unchecked.com": resolved("unchecked.com"),
+			"missing.com": {
+				ID: uuid.New(), DNSCampaignID: f.dnsID, DomainName: "missing.com", ValidationStatus: "Not Found",
+				DNSRecords: models.JSONRawMessagePtr(json.RawMessage(`{"error":"NXDOMAIN"}`)), Attempts: &three,
+			},
+		},
+		httpResults: map[string]*models.HTTPKeywordResult{
+			"lead.com": {
+				ID: uuid.New(), HTTPKeywordCampaignID: f.httpID, DomainName: "lead.com", ValidationStatus: httpStatusLeadValid,
+				HTTPStatusCode: status(200), PageTitle: &title, Attempts: &one,
+				FoundKeywordsFromSets: found(keywordscanner.RuleMatch{Pattern: "casino", MatchType: models.KeywordMatchTypeSubstring, Matched: "Casino", KeywordSetID: f.setID}),
+				FoundAdHocKeywords:    &[]string{"poker"},
+				ValidatedByPersonaID:  uuid.NullUUID{UUID: f.browser.ID, Valid: true},
+				UsedProxyID:           uuid.NullUUID{UUID: f.proxy.ID, Valid: true},
+			},
+			"down.com": {
+				ID: uuid.New(), HTTPKeywordCampaignID: f.httpID, DomainName: "down.com", ValidationStatus: "invalid_http_code",
+				HTTPStatusCode: status(503), Attempts: &three,
+				UsedProxyID: uuid.NullUUID{UUID: uuid.New(), Valid: true},
+			},
+		},
+	}
+	f.explainer = NewCampaignResultExplainer(nil, f.cs,
+		&namedPersonaStore{personas: map[uuid.UUID]*models.Persona{f.dnsPerson.ID: f.dnsPerson, f.browser.ID: f.browser}},
+		&namedProxyStore{proxies: map[uuid.UUID]*models.Proxy{f.proxy.ID: f.proxy}})
+	return f
+}
+
+func TestExplainDomain_SuccessfulHTTPKeywordDomain(t *testing.T) {
+	f := newExplainFixture()
+
+	explanation, err := f.explainer.ExplainDomain(context.Background(), f.httpID, "lead.com")
+	require.NoError(t, err)
+	assert.Equal(t, DomainOutcomePassed, explanation.Outcome)
+	assert.Empty(t, explanation.ErrorCategory)
+
+	require.NotNil(t, explanation.DNS)
+	assert.Equal(t, f.dnsID, explanation.DNS.CampaignID, "the DNS result comes from the source campaign")
+	assert.Equal(t, []string{"192.0.2.10"}, explanation.DNS.IPs)
+	assert.Equal(t, &ExplainedResource{ID: f.dnsPerson.ID, Name: "Public resolvers"}, explanation.DNS.Persona)
+
+	httpResult := explanation.HTTP
+	require.NotNil(t, httpResult)
+	assert.Equal(t, int32(200), *httpResult.HTTPStatusCode)
+	require.Len(t, httpResult.MatchedKeywords, 1)
+	assert.Equal(t, "Casino", httpResult.MatchedKeywords[0].Matched)
+	assert.Equal(t, []string{"poker"}, httpResult.MatchedAdHocKeywords)
+	assert.Equal(t, []string{"bingo"}, httpResult.MissedAdHocKeywords)
+	assert.Empty(t, httpResult.UnmatchedKeywordSetIDs)
+	assert.Equal(t, "Desktop Chrome", httpResult.Persona.Name)
+	assert.Equal(t, "eu-west", httpResult.Proxy.Name)
+	assert.Equal(t, 1, httpResult.Attempts)
+
+	assert.Equal(t, []string{
+		`DNS resolved lead.com to 192.0.2.10 using persona "Public resolvers" after 1 attempt.`,
+		`The HTTP request answered with status 200 and was recorded as "lead_valid" using persona "Desktop Chrome" through proxy "eu-west" after 1 attempt.`,
+		`Keywords found: "casino" (matched "Casino"), "poker".`,
+		"Ad hoc keywords not found: bingo.",
+	}, explanation.Narrative)
+}
+
+func TestExplainDomain_FailedDomains(t *testing.T) {
+	f := newExplainFixture()
+
+	explanation, err := f.explainer.ExplainDomain(context.Background(), f.httpID, "down.com")
+	require.NoError(t, err)
+	assert.Equal(t, DomainOutcomeFailed, explanation.Outcome)
+	assert.Equal(t, ResultErrorHTTPStatus, explanation.ErrorCategory)
+	require.NotNil(t, explanation.HTTP)
+	assert.Equal(t, 3, explanation.HTTP.Attempts)
+	assert.True(t, explanation.HTTP.Proxy.Missing, "the proxy was deleted since")
+	assert.Empty(t, explanation.HTTP.MissedAdHocKeywords, "nothing was scanned")
+	assert.Contains(t, explanation.Narrative[1], "status 503")
+	assert.Contains(t, explanation.Narrative[1], "(since deleted) after 3 attempts")
+
+	explanation, err = f.explainer.ExplainDomain(context.Background(), f.httpID, "missing.com")
+	require.NoError(t, err)
+	assert.Equal(t, DomainOutcomeFailed, explanation.Outcome)
+	assert.Equal(t, ResultErrorDNSNotFound, explanation.ErrorCategory)
+	assert.Nil(t, explanation.HTTP)
+	assert.Equal(t, "NXDOMAIN", explanation.DNS.Error)
+	assert.Equal(t, []string{
+		`DNS failed for missing.com with status "Not Found": NXDOMAIN after 3 attempts.`,
+		"The domain was not fetched over HTTP because DNS validation failed.",
+	}, explanation.Narrative)
+
+	// The DNS campaign explains the same result on its own
+	explanation, err = f.explainer.ExplainDomain(context.Background(), f.dnsID, "missing.com")
+	require.NoError(t, err)
+	assert.Equal(t, ResultErrorDNSNotFound, explanation.ErrorCategory)
+	assert.Len(t, explanation.Narrative, 1)
+
+	explanation, err = f.explainer.ExplainDomain(context.Background(), f.httpID, "unchecked.com")
+	require.NoError(t, err)
+	assert.Equal(t, DomainOutcomeNotChecked, explanation.Outcome)
+}
+
+func TestExplainDomain_NotFound(t *testing.T) {
+	f := newExplainFixture()
+
+	_, err := f.explainer.ExplainDomain(context.Background(), f.httpID, "unknown.com")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	_, err = f.explainer.ExplainDomain(context.Background(), uuid.New(), "lead.com")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	generation := uuid.New()
+	f.cs.campaigns[generation] = &models.Campaign{ID: generation, CampaignType: models.CampaignTypeDomainGeneration}
+	_, err = f.explainer.ExplainDomain(context.Background(), generation, "lead.com")
+	assert.ErrorIs(t, err, ErrCampaignNotExplainable)
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrCampaignResultsNotExportable is returned when a campaign does not hold the kind of results
+// asked for
+var ErrCampaignResultsNotExportable = errors.New("campaign has no results of this kind")
+
+// CampaignResultExportService pages through a campaign's validation results in domain order so
+// they can be streamed out without loading them all into memory
+type CampaignResultExportService struct {
+	db            *sqlx.DB
+	campaignStore store.CampaignStore
+	batchSize     int
+}
+
+// NewCampaignResultExportService reads server.exportBatchSize results per query
+func NewCampaignResultExportService(appCfg *config.AppConfig, db *sqlx.DB, cs store.CampaignStore) *CampaignResultExportService {
+	batchSize := config.DefaultExportBatchSize
+	if appCfg != nil && appCfg.Server.ExportBatchSize > 0 {
+		batchSize = appCfg.Server.ExportBatchSize
+	}
+	return &CampaignResultExportService{db: db, campaignStore: cs, batchSize: batchSize}
+}
+
+func (s *CampaignResultExportService) querier() store.Querier {
+	if s.db != nil {
+		return s.db
+	}
+	return nil
+}
+
+// ExportableCampaign returns the campaign whose results of campaignType are to be exported. It
+// returns store.ErrNotFound when the campaign does not exist and ErrCampaignResultsNotExportable
+// when it is a campaign of another type.
+func (s *CampaignResultExportService) ExportableCampaign(ctx context.Context, campaignID uuid.UUID, campaignType models.CampaignTypeEnum) (*models.Campaign, error) {
+	campaign, err := s.campaignStore.GetCampaignByID(ctx, s.querier(), campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.CampaignType != campaignType {
+		return nil, fmt.Errorf("%w: %s campaign has no %s results", ErrCampaignResultsNotExportable, campaign.CampaignType, campaignType)
+	}
+	return campaign, nil
+}
+
+// StreamDNSValidationResults calls emit with each batch of the campaign's DNS validation results
+// that match filter, in domain order. Its Limit, Offset and AfterDomainName are ignored. It stops
+// at the first error from emit or the store, and when ctx is done, which cancels the query in
+// progress.
+func (s *CampaignResultExportService) StreamDNSValidationResults(ctx context.Context, campaignID uuid.UUID, filter store.ListValidationResultsFilter, emit func([]*models.DNSValidationResult) error) error {
+	filter.Limit, filter.Offset, filter.AfterDomainName = s.batchSize, 0, ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch, err := s.campaignStore.GetDNSValidationResultsByCampaign(ctx, s.querier(), campaignID, filter)
+		if err != nil {
+			return fmt.Errorf("failed to read DNS validation results of campaign %s: %w", campaignID, err)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := emit(batch); err != nil {
+			return err
+		}
+		if len(batch) < filter.Limit {
+			return nil
+		}
+		filter.AfterDomainName = batch[len(batch)-1].DomainName
+	}
+}
+
+// StreamHTTPKeywordResults calls emit with each batch of the campaign's HTTP keyword results, in
+// the same way as StreamDNSValidationResults
+func (s *CampaignResultExportService) StreamHTTPKeywordResults(ctx context.Context, campaignID uuid.UUID, filter store.ListValidationResultsFilter, emit func([]*models.HTTPKeywordResult) error) error {
+	filter.Limit, filter.Offset, filter.AfterDomainName = s.batchSize, 0, ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch, err := s.campaignStore.GetHTTPKeywordResultsByCampaign(ctx, s.querier(), campaignID, filter)
+		if err != nil {
+			return fmt.Errorf("failed to read HTTP keyword results of campaign %s: %w", campaignID, err)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := emit(batch); err != nil {
+			return err
+		}
+		if len(batch) < filter.Limit {
+			return nil
+		}
+		filter.AfterDomainName = batch[len(batch)-1].DomainName
+	}
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// campaignScheduleBatchSize bounds the schedules fired by one sweep
+const campaignScheduleBatchSize = 50
+
+// ErrInvalidCampaignSchedule is returned for a schedule without exactly one of a future run time
+// and a valid cron expression
+var ErrInvalidCampaignSchedule = errors.New("invalid campaign schedule")
+
+// CampaignScheduler starts campaigns on their schedules. A one-off schedule starts its pending
+// campaign at its run time. A cron schedule treats its campaign as a template and starts a clone
+// of it each time the expression fires, so every run gets its own results; runs missed while no
+// server was up are skipped rather than made up. Servers claim due schedules in the database
+// before firing them, so a schedule fires once however many servers run the scheduler.
+type CampaignScheduler struct {
+	db            *sqlx.DB
+	scheduleStore store.CampaignScheduleStore
+	campaignStore store.CampaignStore
+	orchestrator  CampaignOrchestratorService // Starts scheduled campaigns
+	cloner        *CampaignCloner
+	interval      time.Duration
+	lock          time.Duration
+	now           func() time.Time
+}
+
+// NewCampaignScheduler creates a scheduler starting campaigns through orchestrator
+func NewCampaignScheduler(appCfg *config.AppConfig, db *sqlx.DB, scheduleStore store.CampaignScheduleStore, cs store.CampaignStore, orchestrator CampaignOrchestratorService) *CampaignScheduler {
+	cfg := config.CampaignScheduleConfig{}
+	if appCfg != nil {
+		cfg = appCfg.CampaignSchedules
+	}
+	scheduler := &CampaignScheduler{
+		db:            db,
+		scheduleStore: scheduleStore,
+		campaignStore: cs,
+		orchestrator:  orchestrator,
+		cloner:        NewCampaignCloner(db, cs),
+		interval:      time.Duration(cfg.CheckIntervalSeconds) * time.Second,
+		lock:          time.Duration(cfg.LockSeconds) * time.Second,
+		now:           time.Now,
+	}
+	if scheduler.interval <= 0 {
+		scheduler.interval = config.DefaultCampaignScheduleCheckIntervalSeconds * time.Second
+	}
+	if scheduler.lock <= 0 {
+		scheduler.lock = config.DefaultCampaignScheduleLockSeconds * time.Second
+	}
+	return scheduler
+}
+
+func (s *CampaignScheduler) querier() store.Querier {
+	if s.db != nil {
+		return s.db
+	}
+	return nil
+}
+
+// Schedule gives a campaign a one-off schedule at runAt or a cron schedule, replacing the schedule
+// it had. Exactly one of runAt and cronExpr must be set; ErrInvalidCampaignSchedule otherwise. A
+// one-off schedule needs a pending campaign and returns ErrCampaignNotPending for any other.
+func (s *CampaignScheduler) Schedule(ctx context.Context, campaignID uuid.UUID, runAt *time.Time, cronExpr string, createdBy *uuid.UUID) (*models.CampaignSchedule, error) {
+	cronExpr = strings.TrimSpace(cronExpr)
+	if (runAt == nil) == (cronExpr == "") {
+		return nil, fmt.Errorf("%w: set either run_at or cron", ErrInvalidCampaignSchedule)
+	}
+	now := s.now().UTC()
+	schedule := &models.CampaignSchedule{CampaignID: campaignID}
+	if createdBy != nil {
+		schedule.CreatedBy = uuid.NullUUID{UUID: *createdBy, Valid: true}
+	}
+	if runAt != nil {
+		if !runAt.After(now) {
+			return nil, fmt.Errorf("%w: run_at must be in the future", ErrInvalidCampaignSchedule)
+		}
+		schedule.RunAt = sql.NullTime{Time: runAt.UTC(), Valid: true}
+		schedule.NextRunAt = schedule.RunAt
+	} else {
+		expression, err := parseCronExpression(cronExpr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCampaignSchedule, err)
+		}
+		next := expression.next(now)
+		if next.IsZero() {
+			return nil, fmt.Errorf("%w: cron expression %q never fires", ErrInvalidCampaignSchedule, cronExpr)
+		}
+		schedule.CronExpression = sql.NullString{String: cronExpr, Valid: true}
+		schedule.NextRunAt = sql.NullTime{Time: next, Valid: true}
+	}
+
+	campaign, err := s.campaignStore.GetCampaignByID(ctx, s.querier(), campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if runAt != nil && campaign.Status != models.CampaignStatusPending {
+		return nil, fmt.Errorf("%w: campaign %s is %s", ErrCampaignNotPending, campaignID, campaign.Status)
+	}
+	if err := s.scheduleStore.UpsertCampaignSchedule(ctx, s.querier(), schedule); err != nil {
+		return nil, fmt.Errorf("failed to save campaign schedule: %w", err)
+	}
+	return schedule, nil
+}
+
+// Get returns a campaign's schedule; store.ErrNotFound if it has none
+func (s *CampaignScheduler) Get(ctx context.Context, campaignID uuid.UUID) (*models.CampaignSchedule, error) {
+	return s.scheduleStore.GetCampaignScheduleByCampaignID(ctx, s.querier(), campaignID)
+}
+
+// Unschedule removes a campaign's schedule; store.ErrNotFound if it has none
+func (s *CampaignScheduler) Unschedule(ctx context.Context, campaignID uuid.UUID) error {
+	return s.scheduleStore.DeleteCampaignSchedule(ctx, s.querier(), campaignID)
+}
+
+// Start fires due schedules every check interval until ctx is cancelled
+func (s *CampaignScheduler) Start(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.FireDue(ctx)
+			}
+		}
+	}()
+}
+
+// FireDue claims and fires every due schedule no other server holds and returns them with the
+// outcome of the firing
+func (s *CampaignScheduler) FireDue(ctx context.Context) []*models.CampaignSchedule {
+	if s == nil {
+		return nil
+	}
+	now := s.now().UTC()
+	// Postgres keeps microseconds; the lock is matched exactly when the outcome is recorded
+	lockedUntil := now.Add(s.lock).Truncate(time.Microsecond)
+	due, err := s.scheduleStore.ClaimDueCampaignSchedules(ctx, s.querier(), now, lockedUntil, campaignScheduleBatchSize)
+	if err != nil {
+		log.Printf("CampaignScheduler: Failed to claim due schedules: %v", err)
+		return nil
+	}
+	for _, schedule := range due {
+		schedule.LockedUntil = sql.NullTime{Time: lockedUntil, Valid: true}
+		s.fire(ctx, schedule, now)
+		recorded, err := s.scheduleStore.RecordCampaignScheduleRun(ctx, s.querier(), schedule)
+		if err != nil {
+			log.Printf("CampaignScheduler: Failed to record run of schedule %s: %v", schedule.ID, err)
+		} else if !recorded {
+			log.Printf("CampaignScheduler: Schedule %s of campaign %s changed while it fired", schedule.ID, schedule.CampaignID)
+		}
+	}
+	return due
+}
+
+// fire starts the campaign a schedule is due to start and sets the outcome and next run time on it
+func (s *CampaignScheduler) fire(ctx context.Context, schedule *models.CampaignSchedule, now time.Time) {
+	schedule.LastRunAt = sql.NullTime{Time: now, Valid: true}
+	schedule.LastCampaignID = uuid.NullUUID{}
+	schedule.LastError = sql.NullString{}
+	startCtx := WithCampaignStatusActor(ctx, CampaignStatusActor{
+		Type:   StatusActorSystem,
+		Reason: fmt.Sprintf("campaign schedule %s", schedule.ID),
+	})
+
+	var err error
+	if !schedule.CronExpression.Valid {
+		schedule.NextRunAt = sql.NullTime{}
+		schedule.LastCampaignID = uuid.NullUUID{UUID: schedule.CampaignID, Valid: true}
+		err = s.orchestrator.StartCampaign(startCtx, schedule.CampaignID)
+	} else {
+		scheduledAt := schedule.NextRunAt.Time
+		schedule.NextRunAt = sql.NullTime{}
+		if expression, parseErr := parseCronExpression(schedule.CronExpression.String); parseErr != nil {
+			err = parseErr
+		} else if next := expression.next(now); !next.IsZero() {
+			schedule.NextRunAt = sql.NullTime{Time: next, Valid: true}
+		}
+		if err == nil {
+			err = s.startClone(ctx, startCtx, schedule, scheduledAt)
+		}
+	}
+	if err != nil {
+		schedule.LastError = sql.NullString{String: err.Error(), Valid: true}
+		log.Printf("CampaignScheduler: Schedule %s of campaign %s failed to start a campaign: %v", schedule.ID, schedule.CampaignID, err)
+		return
+	}
+	log.Printf("CampaignScheduler: Schedule %s started campaign %s", schedule.ID, schedule.LastCampaignID.UUID)
+}
+
+// startClone clones a cron schedule's campaign, named after the time the run was due, and starts it
+func (s *CampaignScheduler) startClone(ctx, startCtx context.Context, schedule *models.CampaignSchedule, scheduledAt time.Time) error {
+	template, err := s.campaignStore.GetCampaignByID(ctx, s.querier(), schedule.CampaignID)
+	if err != nil {
+		return fmt.Errorf("failed to load campaign: %w", err)
+	}
+	name := fmt.Sprintf("%s (%s)", template.Name, scheduledAt.UTC().Format("2006-01-02 15:04 UTC"))
+	clone, err := s.cloner.CloneCampaign(ctx, schedule.CampaignID, name)
+	if err != nil {
+		return fmt.Errorf("failed to clone campaign: %w", err)
+	}
+	schedule.LastCampaignID = uuid.NullUUID{UUID: clone.ID, Valid: true}
+	if err := s.orchestrator.StartCampaign(startCtx, clone.ID); err != nil {
+		// The clone stays pending and can be started by hand
+		return fmt.Errorf("created campaign %s but could not start it: %w", clone.ID, err)
+	}
+	return nil
+}
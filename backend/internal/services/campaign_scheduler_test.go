@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryScheduleStore keeps campaign schedules by campaign, claiming them as the Postgres store does
+type memoryScheduleStore struct {
+	store.CampaignScheduleStore
+	mu        sync.Mutex
+	schedules map[uuid.UUID]*models.CampaignSchedule
+}
+
+func (s *memoryScheduleStore) UpsertCampaignSchedule(ctx context.Context, exec store.Querier, schedule *models.CampaignSchedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.schedules[schedule.CampaignID]; ok {
+		schedule.ID = existing.ID
+		schedule.LastRunAt, schedule.LastCampaignID, schedule.LastError = existing.LastRunAt, existing.LastCampaignID, existing.LastError
+	} else {
+		schedule.ID = uuid.New()
+	}
+	stored := *schedule
+	s.schedules[schedule.CampaignID] = &stored
+	return nil
+}
+
+func (s *memoryScheduleStore) GetCampaignScheduleByCampaignID(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.CampaignSchedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if schedule, ok := s.schedules[campaignID]; ok {
+		copied := *schedule
+		return &copied, nil
+	}
+	return nil, store.ErrNotFound
+}
+
+func (s *memoryScheduleStore) DeleteCampaignSchedule(ctx context.Context, exec store.Querier, campaignID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.schedules[campaignID]; !ok {
+		return store.ErrNotFound
+	}
+	delete(s.schedules, campaignID)
+	return nil
+}
+
+func (s *memoryScheduleStore) ClaimDueCampaignSchedules(ctx context.Context, exec store.Querier, now, lockedUntil time.Time, limit int) ([]*models.CampaignSchedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var claimed []*models.CampaignSchedule
+	for _, schedule := range s.schedules {
+		if !schedule.NextRunAt.Valid || schedule.NextRunAt.Time.After(now) ||
+			(schedule.LockedUntil.Valid && schedule.LockedUntil.Time.After(now)) {
+			continue
+		}
+		schedule.LockedUntil.Time, schedule.LockedUntil.Valid = lockedUntil, true
+		copied := *schedule
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+func (s *memoryScheduleStore) RecordCampaignScheduleRun(ctx context.Context, exec store.Querier, schedule *models.CampaignSchedule) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.schedules[schedule.CampaignID]
+	if !ok || stored.ID != schedule.ID || stored.LockedUntil != schedule.LockedUntil {
+		return false, nil
+	}
+	stored.NextRunAt, stored.LastRunAt = schedule.NextRunAt, schedule.LastRunAt
+	stored.LastCampaignID, stored.LastError = schedule.LastCampaignID, schedule.LastError
+	stored.LockedUntil.Valid = false
+	return true, nil
+}
+
+// startingOrchestrator starts pending campaigns of a cloneableCampaignStore
+type startingOrchestrator struct {
+	CampaignOrchestratorService
+	cs      *cloneableCampaignStore
+	started []uuid.UUID
+}
+
+func (o *startingOrchestrator) StartCampaign(ctx context.Context, campaignID uuid.UUID) error {
+	campaign, err := o.cs.GetCampaignByID(ctx, nil, campaignID)
+	if err != nil {
+		return err
+	}
+	if campaign.Status != models.CampaignStatusPending {
+		return ErrCampaignNotPending
+	}
+	campaign.Status = models.CampaignStatusQueued
+	o.started = append(o.started, campaignID)
+	return nil
+}
+
+type schedulerFixture struct {
+	cs           *cloneableCampaignStore
+	schedules    *memoryScheduleStore
+	orchestrator *startingOrchestrator
+	now          time.Time
+}
+
+func newSchedulerFixture() *schedulerFixture {
+	cs := newCloneableCampaignStore()
+	return &schedulerFixture{
+		cs:           cs,
+		schedules:    &memoryScheduleStore{schedules: make(map[uuid.UUID]*models.CampaignSchedule)},
+		orchestrator: &startingOrchestrator{cs: cs},
+		now:          time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+// scheduler returns a scheduler reading the fixture's clock, as one of several servers
+func (f *schedulerFixture) scheduler() *CampaignScheduler {
+	scheduler := NewCampaignScheduler(nil, nil, f.schedules, f.cs, f.orchestrator)
+	scheduler.now = func() time.Time { return f.now }
+	return scheduler
+}
+
+func (f *schedulerFixture) pendingDNSCampaign(name string) *models.Campaign {
+	campaign := &models.Campaign{ID: uuid.New(), Name: name, CampaignType: models.CampaignTypeDNSValidation, Status: models.CampaignStatusPending}
+	f.cs.campaigns[campaign.ID] = campaign
+	f.cs.dns[campaign.ID] = &models.DNSValidationCampaignParams{CampaignID: campaign.ID, PersonaIDs: []uuid.UUID{uuid.New()}}
+	return campaign
+}
+
+func TestCampaignScheduler_Validation(t *testing.T) {
+	f := newSchedulerFixture()
+	scheduler := f.scheduler()
+	campaign := f.pendingDNSCampaign("Nightly")
+	past, future := f.now.Add(-time.Minute), f.now.Add(time.Hour)
+	ctx := context.Background()
+
+	for name, schedule := range map[string]func() error{
+		"neither":      func() error { _, err := scheduler.Schedule(ctx, campaign.ID, nil, "", nil); return err },
+		"both":         func() error { _, err := scheduler.Schedule(ctx, campaign.ID, &future, "@daily", nil); return err },
+		"past":         func() error { _, err := scheduler.Schedule(ctx, campaign.ID, &past, "", nil); return err },
+		"invalid cron": func() error { _, err := scheduler.Schedule(ctx, campaign.ID, nil, "61 * * * *", nil); return err },
+		"never fires":  func() error { _, err := scheduler.Schedule(ctx, campaign.ID, nil, "0 0 31 2 *", nil); return err },
+	} {
+		assert.ErrorIs(t, schedule(), ErrInvalidCampaignSchedule, name)
+	}
+	_, err := scheduler.Schedule(ctx, uuid.New(), &future, "", nil)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	campaign.Status = models.CampaignStatusCompleted
+	_, err = scheduler.Schedule(ctx, campaign.ID, &future, "", nil)
+	assert.ErrorIs(t, err, ErrCampaignNotPending, "only a pending campaign can be started")
+	schedule, err := scheduler.Schedule(ctx, campaign.ID, nil, "0 2 * * *", nil)
+	require.NoError(t, err, "a finished campaign can still be the template of a cron schedule")
+	assert.Equal(t, time.Date(2026, 10, 15, 2, 0, 0, 0, time.UTC), schedule.NextRunAt.Time)
+
+	require.NoError(t, scheduler.Unschedule(ctx, campaign.ID))
+	assert.ErrorIs(t, scheduler.Unschedule(ctx, campaign.ID), store.ErrNotFound)
+}
+
+func TestCampaignScheduler_RunAtStartsTheCampaignOnce(t *testing.T) {
+	f := newSchedulerFixture()
+	campaign := f.pendingDNSCampaign("Launch")
+	runAt := f.now.Add(30 * time.Minute)
+	_, err := f.scheduler().Schedule(context.Background(), campaign.ID, &runAt, "", nil)
+	require.NoError(t, err)
+
+	first, second := f.scheduler(), f.scheduler()
+	assert.Empty(t, first.FireDue(context.Background()), "not due yet")
+
+	f.now = runAt
+	assert.Len(t, first.FireDue(context.Background()), 1)
+	assert.Empty(t, second.FireDue(context.Background()), "another server does not fire it again")
+	assert.Equal(t, []uuid.UUID{campaign.ID}, f.orchestrator.started)
+
+	schedule, err := first.Get(context.Background(), campaign.ID)
+	require.NoError(t, err)
+	assert.False(t, schedule.NextRunAt.Valid, "a one-off schedule is done")
+	assert.Equal(t, campaign.ID, schedule.LastCampaignID.UUID)
+	assert.False(t, schedule.LastError.Valid)
+	assert.Len(t, f.cs.campaigns, 1, "nothing is cloned")
+}
+
+func TestCampaignScheduler_CronStartsAClonePerFiring(t *testing.T) {
+	f := newSchedulerFixture()
+	template := f.pendingDNSCampaign("Nightly")
+	_, err := f.scheduler().Schedule(context.Background(), template.ID, nil, "0 2 * * *", nil)
+	require.NoError(t, err)
+
+	first, second := f.scheduler(), f.scheduler()
+	var clones []uuid.UUID
+	for day := 15; day <= 16; day++ {
+		f.now = time.Date(2026, 10, day, 2, 0, 20, 0, time.UTC)
+		require.Len(t, first.FireDue(context.Background()), 1)
+		assert.Empty(t, second.FireDue(context.Background()), "another server does not fire it again")
+
+		schedule, err := first.Get(context.Background(), template.ID)
+		require.NoError(t, err)
+		require.False(t, schedule.LastError.Valid, schedule.LastError.String)
+		clone := f.cs.campaigns[schedule.LastCampaignID.UUID]
+		require.NotNil(t, clone)
+		assert.Equal(t, fmt.Sprintf("Nightly (2026-10-%d 02:00 UTC)", day), clone.Name)
+		assert.Equal(t, models.CampaignStatusQueued, clone.Status)
+		assert.Equal(t, f.cs.dns[template.ID].PersonaIDs, f.cs.dns[clone.ID].PersonaIDs)
+		assert.Equal(t, time.Date(2026, 10, day+1, 2, 0, 0, 0, time.UTC), schedule.NextRunAt.Time)
+		clones = append(clones, clone.ID)
+	}
+	assert.Equal(t, clones, f.orchestrator.started)
+	assert.Equal(t, models.CampaignStatusPending, template.Status, "the template itself never runs")
+
+	// Runs missed while no server was up are skipped
+	f.now = time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	require.Len(t, first.FireDue(context.Background()), 1)
+	assert.Len(t, f.orchestrator.started, 3)
+	schedule, err := first.Get(context.Background(), template.ID)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 21, 2, 0, 0, 0, time.UTC), schedule.NextRunAt.Time)
+}
+
+func TestCampaignScheduler_ClaimedScheduleIsSkippedUntilItsLockExpires(t *testing.T) {
+	f := newSchedulerFixture()
+	campaign := f.pendingDNSCampaign("Launch")
+	runAt := f.now.Add(time.Minute)
+	_, err := f.scheduler().Schedule(context.Background(), campaign.ID, &runAt, "", nil)
+	require.NoError(t, err)
+
+	// A server claims the schedule and stops before recording the run
+	f.now = runAt
+	lockedUntil := f.now.Add(config.DefaultCampaignScheduleLockSeconds * time.Second)
+	claimed, err := f.schedules.ClaimDueCampaignSchedules(context.Background(), nil, f.now, lockedUntil, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	scheduler := f.scheduler()
+	assert.Empty(t, scheduler.FireDue(context.Background()), "the claiming server holds it")
+	f.now = lockedUntil
+	assert.Len(t, scheduler.FireDue(context.Background()), 1, "taken over once the lock expires")
+	assert.Equal(t, []uuid.UUID{campaign.ID}, f.orchestrator.started)
+}
+
+func TestCampaignScheduler_RecordsFailures(t *testing.T) {
+	f := newSchedulerFixture()
+	campaign := f.pendingDNSCampaign("Launch")
+	runAt := f.now.Add(time.Minute)
+	_, err := f.scheduler().Schedule(context.Background(), campaign.ID, &runAt, "", nil)
+	require.NoError(t, err)
+	campaign.Status = models.CampaignStatusRunning // Started by hand in the meantime
+
+	f.now = runAt
+	scheduler := f.scheduler()
+	require.Len(t, scheduler.FireDue(context.Background()), 1)
+	schedule, err := scheduler.Get(context.Background(), campaign.ID)
+	require.NoError(t, err)
+	assert.Contains(t, schedule.LastError.String, "not pending")
+	assert.False(t, schedule.NextRunAt.Valid)
+	assert.Empty(t, f.orchestrator.started)
+}
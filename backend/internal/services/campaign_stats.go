@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+)
+
+// CampaignStatsResponse aggregates result statistics for a campaign. When the caller's context ends
+// before every section is gathered, Partial is set and PendingSections names the sections left out.
+type CampaignStatsResponse struct {
+	CampaignID         uuid.UUID                 `json:"campaignId"`
+	CampaignType       models.CampaignTypeEnum   `json:"campaignType"`
+	Status             models.CampaignStatusEnum `json:"status"`
+	ProgressPercentage *float64                  `json:"progressPercentage,omitempty"`
+	TotalItems         *int64                    `json:"totalItems,omitempty"`
+	ProcessedItems     *int64                    `json:"processedItems,omitempty"`
+	GeneratedDomains   *int64                    `json:"generatedDomains,omitempty"`
+	SourceDomains      *int64                    `json:"sourceDomains,omitempty"`
+	ResultCount        *int64                    `json:"resultCount,omitempty"`
+	ResultsByStatus    map[string]int64          `json:"resultsByStatus,omitempty"`
+	Partial            bool                      `json:"partial"`
+	PendingSections    []string                  `json:"pendingSections,omitempty"`
+}
+
+// Names of the sections gathered for campaign statistics
+const (
+	StatsSectionGeneratedDomains = "generatedDomains"
+	StatsSectionResultsByStatus  = "resultsByStatus"
+	StatsSectionSourceDomains    = "sourceDomains"
+)
+
+// statsSection is one independently gathered part of an aggregate response
+type statsSection struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// gatherStatsSections runs sections in order until they are all done or ctx ends. Sections that
+// did not complete because ctx ended are returned as pending rather than failing the whole
+// aggregation; any other section error is returned.
+func gatherStatsSections(ctx context.Context, sections []statsSection) ([]string, error) {
+	for i, section := range sections {
+		if ctx.Err() != nil {
+			return sectionNames(sections[i:]), nil
+		}
+		if err := section.run(ctx); err != nil {
+			if ctx.Err() != nil {
+				return sectionNames(sections[i:]), nil
+			}
+			return nil, fmt.Errorf("failed to gather %s: %w", section.name, err)
+		}
+	}
+	return nil, nil
+}
+
+func sectionNames(sections []statsSection) []string {
+	names := make([]string, 0, len(sections))
+	for _, section := range sections {
+		names = append(names, section.name)
+	}
+	return names
+}
+
+func sumCounts(counts map[string]int64) *int64 {
+	var total int64
+	for _, count := range counts {
+		total += count
+	}
+	return &total
+}
+
+func (s *campaignOrchestratorServiceImpl) GetCampaignStats(ctx context.Context, campaignID uuid.UUID) (*CampaignStatsResponse, error) {
+	var querier store.Querier
+	if s.db != nil {
+		querier = s.db
+	}
+	campaign, err := s.campaignStore.GetCampaignByID(ctx, querier, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &CampaignStatsResponse{
+		CampaignID:         campaign.ID,
+		CampaignType:       campaign.CampaignType,
+		Status:             campaign.Status,
+		ProgressPercentage: campaign.ProgressPercentage,
+		TotalItems:         campaign.TotalItems,
+		ProcessedItems:     campaign.ProcessedItems,
+	}
+
+	var sections []statsSection
+	switch campaign.CampaignType {
+	case models.CampaignTypeDomainGeneration:
+		sections = append(sections, statsSection{StatsSectionGeneratedDomains, func(ctx context.Context) error {
+			count, err := s.campaignStore.CountGeneratedDomainsByCampaign(ctx, querier, campaignID)
+			if err == nil {
+				stats.GeneratedDomains = &count
+			}
+			return err
+		}})
+	case models.CampaignTypeDNSValidation:
+		sections = append(sections,
+			statsSection{StatsSectionResultsByStatus, func(ctx context.Context) error {
+				counts, err := s.campaignStore.CountDNSValidationResultsByStatus(ctx, querier, campaignID)
+				if err == nil {
+					stats.ResultsByStatus = counts
+					stats.ResultCount = sumCounts(counts)
+				}
+				return err
+			}},
+			statsSection{StatsSectionSourceDomains, func(ctx context.Context) error {
+				params, err := s.campaignStore.GetDNSValidationParams(ctx, querier, campaignID)
+				if err != nil || params.SourceGenerationCampaignID == nil {
+					return err
+				}
+				count, err := s.campaignStore.CountGeneratedDomainsByCampaign(ctx, querier, *params.SourceGenerationCampaignID)
+				if err == nil {
+					stats.SourceDomains = &count
+				}
+				return err
+			}},
+		)
+	case models.CampaignTypeHTTPKeywordValidation:
+		sections = append(sections,
+			statsSection{StatsSectionResultsByStatus, func(ctx context.Context) error {
+				counts, err := s.campaignStore.CountHTTPKeywordResultsByStatus(ctx, querier, campaignID)
+				if err == nil {
+					stats.ResultsByStatus = counts
+					stats.ResultCount = sumCounts(counts)
+				}
+				return err
+			}},
+			statsSection{StatsSectionSourceDomains, func(ctx context.Context) error {
+				params, err := s.campaignStore.GetHTTPKeywordParams(ctx, querier, campaignID)
+				if err != nil {
+					return err
+				}
+				count, err := s.campaignStore.CountDNSValidationResults(ctx, querier, params.SourceCampaignID, true)
+				if err == nil {
+					stats.SourceDomains = &count
+				}
+				return err
+			}},
+		)
+	}
+
+	pending, err := gatherStatsSections(ctx, sections)
+	if err != nil {
+		return nil, fmt.Errorf("failed to gather stats for campaign %s: %w", campaignID, err)
+	}
+	stats.Partial = len(pending) > 0
+	stats.PendingSections = pending
+	return stats, nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowStatsStore answers the stats queries for one DNS campaign; the source-domain count takes sourceDelay
+type slowStatsStore struct {
+	campaignLookupStore
+	sourceCampaignID uuid.UUID
+	sourceDelay      time.Duration
+}
+
+func (s *slowStatsStore) CountDNSValidationResultsByStatus(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (map[string]int64, error) {
+	return map[string]int64{"valid_dns": 7, "invalid_dns": 3}, nil
+}
+
+func (s *slowStatsStore) GetDNSValidationParams(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.DNSValidationCampaignParams, error) {
+	return &models.DNSValidationCampaignParams{SourceGenerationCampaignID: &s.sourceCampaignID}, nil
+}
+
+func (s *slowStatsStore) CountGeneratedDomainsByCampaign(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (int64, error) {
+	select {
+	case <-time.After(s.sourceDelay):
+		return 25, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func newSlowStatsStore(campaignID uuid.UUID, sourceDelay time.Duration) *slowStatsStore {
+	return &slowStatsStore{
+		campaignLookupStore: campaignLookupStore{campaigns: map[uuid.UUID]*models.Campaign{
+			campaignID: {ID: campaignID, CampaignType: models.CampaignTypeDNSValidation, Status: models.CampaignStatusRunning},
+		}},
+		sourceCampaignID: uuid.New(),
+		sourceDelay:      sourceDelay,
+	}
+}
+
+func TestGetCampaignStats_Complete(t *testing.T) {
+	campaignID := uuid.New()
+	svc := NewCampaignOrchestratorService(nil, newSlowStatsStore(campaignID, 0), nil, nil, nil, nil, nil, nil, nil)
+
+	stats, err := svc.GetCampaignStats(context.Background(), campaignID)
+	require.NoError(t, err)
+	assert.False(t, stats.Partial)
+	assert.Empty(t, stats.PendingSections)
+	require.NotNil(t, stats.ResultCount)
+	assert.Equal(t, int64(10), *stats.ResultCount)
+	require.NotNil(t, stats.SourceDomains)
+	assert.Equal(t, int64(25), *stats.SourceDomains)
+}
+
+func TestGetCampaignStats_SlowSectionReturnsPartial(t *testing.T) {
+	campaignID := uuid.New()
+	svc := NewCampaignOrchestratorService(nil, newSlowStatsStore(campaignID, time.Minute), nil, nil, nil, nil, nil, nil, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	stats, err := svc.GetCampaignStats(ctx, campaignID)
+	require.NoError(t, err)
+	assert.True(t, stats.Partial)
+	assert.Equal(t, []string{StatsSectionSourceDomains}, stats.PendingSections)
+	assert.Equal(t, map[string]int64{"valid_dns": 7, "invalid_dns": 3}, stats.ResultsByStatus)
+	assert.Nil(t, stats.SourceDomains)
+}
+
+func TestGatherStatsSections_FailsOnErrorBeforeDeadline(t *testing.T) {
+	boom := errors.New("boom")
+	pending, err := gatherStatsSections(context.Background(), []statsSection{
+		{"first", func(ctx context.Context) error { return boom }},
+		{"second", func(ctx context.Context) error { return nil }},
+	})
+	assert.ErrorIs(t, err, boom)
+	assert.Nil(t, pending)
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// CampaignStatusTransitionAction is the audit log action recorded for every campaign status change
+const CampaignStatusTransitionAction = "campaign_status_transition"
+
+// Campaign status actor types
+const (
+	StatusActorUser   = "user"
+	StatusActorWorker = "worker"
+	StatusActorSystem = "system" // Anything not attributed to a user or worker
+)
+
+// CampaignStatusActor identifies who or what changes a campaign's status
+type CampaignStatusActor struct {
+	Type     string
+	UserID   uuid.UUID // Set for StatusActorUser
+	WorkerID string    // Set for StatusActorWorker
+	Reason   string
+}
+
+type campaignStatusActorKey struct{}
+
+// WithCampaignStatusActor attributes campaign status changes made with ctx to actor
+func WithCampaignStatusActor(ctx context.Context, actor CampaignStatusActor) context.Context {
+	return context.WithValue(ctx, campaignStatusActorKey{}, actor)
+}
+
+func campaignStatusActorFrom(ctx context.Context) CampaignStatusActor {
+	if actor, ok := ctx.Value(campaignStatusActorKey{}).(CampaignStatusActor); ok {
+		return actor
+	}
+	return CampaignStatusActor{Type: StatusActorSystem}
+}
+
+// CampaignStatusTransition is one recorded campaign status change
+type CampaignStatusTransition struct {
+	CampaignID uuid.UUID                 `json:"campaignId"`
+	From       models.CampaignStatusEnum `json:"from"`
+	To         models.CampaignStatusEnum `json:"to"`
+	ActorType  string                    `json:"actorType"`
+	UserID     *uuid.UUID                `json:"userId,omitempty"`
+	WorkerID   string                    `json:"workerId,omitempty"`
+	Reason     string                    `json:"reason,omitempty"`
+	ChangedAt  time.Time                 `json:"changedAt"`
+}
+
+// statusAuditingCampaignStore writes an audit log entry in the same transaction as every update
+// that changes a campaign's status. The campaign row is locked while its old status is read so
+// concurrent updates are recorded in the order they were applied.
+type statusAuditingCampaignStore struct {
+	store.CampaignStore
+	db            *sqlx.DB
+	auditLogStore store.AuditLogStore
+	now           func() time.Time
+}
+
+// NewStatusAuditingCampaignStore wraps campaignStore so every campaign status change is audited
+func NewStatusAuditingCampaignStore(db *sqlx.DB, campaignStore store.CampaignStore, auditLogStore store.AuditLogStore) store.CampaignStore {
+	return &statusAuditingCampaignStore{CampaignStore: campaignStore, db: db, auditLogStore: auditLogStore, now: time.Now}
+}
+
+func (s *statusAuditingCampaignStore) UpdateCampaign(ctx context.Context, exec store.Querier, campaign *models.Campaign) error {
+	reason := ""
+	if campaign.Status == models.CampaignStatusFailed && campaign.ErrorMessage != nil {
+		reason = *campaign.ErrorMessage
+	}
+	return s.audited(ctx, exec, campaign.ID, reason,
+		func(models.CampaignStatusEnum) models.CampaignStatusEnum { return campaign.Status },
+		func(q store.Querier) error { return s.CampaignStore.UpdateCampaign(ctx, q, campaign) })
+}
+
+func (s *statusAuditingCampaignStore) UpdateCampaignStatus(ctx context.Context, exec store.Querier, id uuid.UUID, status models.CampaignStatusEnum, errorMessage sql.NullString) error {
+	return s.audited(ctx, exec, id, errorMessage.String,
+		func(models.CampaignStatusEnum) models.CampaignStatusEnum { return status },
+		func(q store.Querier) error {
+			return s.CampaignStore.UpdateCampaignStatus(ctx, q, id, status, errorMessage)
+		})
+}
+
+// UpdateCampaignProgress also moves campaigns that are neither completed nor failed to running
+func (s *statusAuditingCampaignStore) UpdateCampaignProgress(ctx context.Context, exec store.Querier, id uuid.UUID, processedItems, totalItems int64, progressPercentage float64) error {
+	return s.audited(ctx, exec, id, "",
+		func(old models.CampaignStatusEnum) models.CampaignStatusEnum {
+			if old == models.CampaignStatusCompleted || old == models.CampaignStatusFailed {
+				return old
+			}
+			return models.CampaignStatusRunning
+		},
+		func(q store.Querier) error {
+			return s.CampaignStore.UpdateCampaignProgress(ctx, q, id, processedItems, totalItems, progressPercentage)
+		})
+}
+
+// audited runs update in a transaction, starting one when exec is not already part of one, and
+// records the transition from the locked old status to newStatus(old) when they differ. reason,
+// e.g. a failure's error message, takes precedence over the actor's reason.
+func (s *statusAuditingCampaignStore) audited(ctx context.Context, exec store.Querier, campaignID uuid.UUID, reason string,
+	newStatus func(old models.CampaignStatusEnum) models.CampaignStatusEnum, update func(q store.Querier) error) error {
+	if exec == nil && s.db != nil {
+		exec = s.db
+	}
+	db, ok := exec.(*sqlx.DB)
+	if !ok {
+		return s.auditedIn(ctx, exec, campaignID, reason, newStatus, update)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.auditedIn(ctx, tx, campaignID, reason, newStatus, update); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *statusAuditingCampaignStore) auditedIn(ctx context.Context, exec store.Querier, campaignID uuid.UUID, reason string,
+	newStatus func(old models.CampaignStatusEnum) models.CampaignStatusEnum, update func(q store.Querier) error) error {
+	oldStatus, err := s.CampaignStore.GetCampaignStatusForUpdate(ctx, exec, campaignID)
+	if errors.Is(err, store.ErrNotFound) {
+		return update(exec) // Reports the missing campaign itself
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock campaign %s for status audit: %w", campaignID, err)
+	}
+	if err := update(exec); err != nil {
+		return err
+	}
+	status := newStatus(oldStatus)
+	if status == oldStatus {
+		return nil
+	}
+	return s.recordTransition(ctx, exec, campaignID, oldStatus, status, reason)
+}
+
+func (s *statusAuditingCampaignStore) recordTransition(ctx context.Context, exec store.Querier, campaignID uuid.UUID, from, to models.CampaignStatusEnum, reason string) error {
+	actor := campaignStatusActorFrom(ctx)
+	if reason == "" {
+		reason = actor.Reason
+	}
+	transition := CampaignStatusTransition{
+		CampaignID: campaignID,
+		From:       from,
+		To:         to,
+		ActorType:  actor.Type,
+		WorkerID:   actor.WorkerID,
+		Reason:     reason,
+		ChangedAt:  s.now().UTC(),
+	}
+	var userID uuid.NullUUID
+	if actor.Type == StatusActorUser && actor.UserID != uuid.Nil {
+		transition.UserID = &actor.UserID
+		userID = uuid.NullUUID{UUID: actor.UserID, Valid: true}
+	}
+	details, err := json.Marshal(transition)
+	if err != nil {
+		return err
+	}
+	entry := &models.AuditLog{
+		ID:         uuid.New(),
+		Timestamp:  transition.ChangedAt,
+		UserID:     userID,
+		Action:     CampaignStatusTransitionAction,
+		EntityType: sql.NullString{String: "Campaign", Valid: true},
+		EntityID:   uuid.NullUUID{UUID: campaignID, Valid: true},
+		Details:    models.JSONRawMessagePtr(details),
+	}
+	if err := s.auditLogStore.CreateAuditLog(ctx, exec, entry); err != nil {
+		return fmt.Errorf("failed to audit status change of campaign %s: %w", campaignID, err)
+	}
+	return nil
+}
+
+// campaignStatusTransitionFromAudit decodes a transition recorded by statusAuditingCampaignStore
+func campaignStatusTransitionFromAudit(entry *models.AuditLog) (CampaignStatusTransition, error) {
+	var transition CampaignStatusTransition
+	if entry.Details == nil {
+		return transition, fmt.Errorf("audit log %s has no details", entry.ID)
+	}
+	err := json.Unmarshal(*entry.Details, &transition)
+	return transition, err
+}
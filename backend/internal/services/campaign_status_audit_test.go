@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statusCampaignStore keeps campaign statuses the way the Postgres updates change them
+type statusCampaignStore struct {
+	store.CampaignStore
+	statuses map[uuid.UUID]models.CampaignStatusEnum
+}
+
+func (m *statusCampaignStore) GetCampaignStatusForUpdate(ctx context.Context, exec store.Querier, id uuid.UUID) (models.CampaignStatusEnum, error) {
+	status, ok := m.statuses[id]
+	if !ok {
+		return "", store.ErrNotFound
+	}
+	return status, nil
+}
+
+func (m *statusCampaignStore) GetCampaignByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Campaign, error) {
+	status, ok := m.statuses[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return &models.Campaign{ID: id, Status: status}, nil
+}
+
+func (m *statusCampaignStore) UpdateCampaign(ctx context.Context, exec store.Querier, campaign *models.Campaign) error {
+	m.statuses[campaign.ID] = campaign.Status
+	return nil
+}
+
+func (m *statusCampaignStore) UpdateCampaignStatus(ctx context.Context, exec store.Querier, id uuid.UUID, status models.CampaignStatusEnum, errorMessage sql.NullString) error {
+	if _, ok := m.statuses[id]; !ok {
+		return store.ErrNotFound
+	}
+	m.statuses[id] = status
+	return nil
+}
+
+func (m *statusCampaignStore) UpdateCampaignProgress(ctx context.Context, exec store.Querier, id uuid.UUID, processedItems, totalItems int64, progressPercentage float64) error {
+	if status := m.statuses[id]; status != models.CampaignStatusCompleted && status != models.CampaignStatusFailed {
+		m.statuses[id] = models.CampaignStatusRunning
+	}
+	return nil
+}
+
+func newStatusAuditFixture(status models.CampaignStatusEnum) (uuid.UUID, store.CampaignStore, *memoryAuditLogStore) {
+	campaignID := uuid.New()
+	campaigns := &statusCampaignStore{statuses: map[uuid.UUID]models.CampaignStatusEnum{campaignID: status}}
+	audits := &memoryAuditLogStore{}
+	auditing := NewStatusAuditingCampaignStore(nil, campaigns, audits).(*statusAuditingCampaignStore)
+	auditing.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+	return campaignID, auditing, audits
+}
+
+func recordedTransitions(t *testing.T, audits *memoryAuditLogStore) []CampaignStatusTransition {
+	t.Helper()
+	transitions := make([]CampaignStatusTransition, 0, len(audits.entries))
+	for _, entry := range audits.entries {
+		require.Equal(t, CampaignStatusTransitionAction, entry.Action)
+		transition, err := campaignStatusTransitionFromAudit(entry)
+		require.NoError(t, err)
+		transitions = append(transitions, transition)
+	}
+	return transitions
+}
+
+func TestStatusAuditingCampaignStore_RecordsEachTransition(t *testing.T) {
+	campaignID, campaigns, audits := newStatusAuditFixture(models.CampaignStatusPending)
+	userID := uuid.New()
+	userCtx := WithCampaignStatusActor(context.Background(), CampaignStatusActor{Type: StatusActorUser, UserID: userID, Reason: "start requested"})
+	workerCtx := WithCampaignStatusActor(context.Background(), CampaignStatusActor{Type: StatusActorWorker, WorkerID: "worker-3"})
+
+	require.NoError(t, campaigns.UpdateCampaignStatus(userCtx, nil, campaignID, models.CampaignStatusQueued, sql.NullString{}))
+	require.NoError(t, campaigns.UpdateCampaignProgress(workerCtx, nil, campaignID, 10, 100, 10))
+	require.NoError(t, campaigns.UpdateCampaignProgress(workerCtx, nil, campaignID, 20, 100, 20))
+	message := "DNS resolver unreachable"
+	require.NoError(t, campaigns.UpdateCampaign(workerCtx, nil, &models.Campaign{ID: campaignID, Status: models.CampaignStatusFailed, ErrorMessage: &message}))
+	require.NoError(t, campaigns.UpdateCampaignStatus(context.Background(), nil, campaignID, models.CampaignStatusQueued, sql.NullString{}))
+
+	transitions := recordedTransitions(t, audits)
+	require.Len(t, transitions, 4, "the second progress update does not change the status")
+
+	assert.Equal(t, models.CampaignStatusPending, transitions[0].From)
+	assert.Equal(t, models.CampaignStatusQueued, transitions[0].To)
+	assert.Equal(t, StatusActorUser, transitions[0].ActorType)
+	require.NotNil(t, transitions[0].UserID)
+	assert.Equal(t, userID, *transitions[0].UserID)
+	assert.Equal(t, "start requested", transitions[0].Reason)
+	assert.Equal(t, userID, audits.entries[0].UserID.UUID)
+
+	assert.Equal(t, models.CampaignStatusQueued, transitions[1].From)
+	assert.Equal(t, models.CampaignStatusRunning, transitions[1].To)
+	assert.Equal(t, StatusActorWorker, transitions[1].ActorType)
+	assert.Equal(t, "worker-3", transitions[1].WorkerID)
+	assert.Nil(t, transitions[1].UserID)
+	assert.False(t, audits.entries[1].UserID.Valid)
+
+	assert.Equal(t, models.CampaignStatusRunning, transitions[2].From)
+	assert.Equal(t, models.CampaignStatusFailed, transitions[2].To)
+	assert.Equal(t, "worker-3", transitions[2].WorkerID)
+	assert.Equal(t, message, transitions[2].Reason)
+
+	assert.Equal(t, models.CampaignStatusFailed, transitions[3].From)
+	assert.Equal(t, models.CampaignStatusQueued, transitions[3].To)
+	assert.Equal(t, StatusActorSystem, transitions[3].ActorType)
+
+	for i, transition := range transitions {
+		assert.Equal(t, campaignID, transition.CampaignID, i)
+		assert.Equal(t, time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC), transition.ChangedAt, i)
+		assert.Equal(t, uuid.NullUUID{UUID: campaignID, Valid: true}, audits.entries[i].EntityID, i)
+	}
+}
+
+func TestStatusAuditingCampaignStore_SkipsUnchangedAndMissingCampaigns(t *testing.T) {
+	campaignID, campaigns, audits := newStatusAuditFixture(models.CampaignStatusCompleted)
+
+	require.NoError(t, campaigns.UpdateCampaignStatus(context.Background(), nil, campaignID, models.CampaignStatusCompleted, sql.NullString{}))
+	require.NoError(t, campaigns.UpdateCampaignProgress(context.Background(), nil, campaignID, 100, 100, 100))
+	err := campaigns.UpdateCampaignStatus(context.Background(), nil, uuid.New(), models.CampaignStatusRunning, sql.NullString{})
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	assert.Empty(t, audits.entries)
+}
+
+func TestListCampaignStatusHistory_ReturnsRecordedTransitions(t *testing.T) {
+	campaignID, campaigns, audits := newStatusAuditFixture(models.CampaignStatusPending)
+	workerCtx := WithCampaignStatusActor(context.Background(), CampaignStatusActor{Type: StatusActorWorker, WorkerID: "worker-1"})
+	require.NoError(t, campaigns.UpdateCampaignStatus(workerCtx, nil, campaignID, models.CampaignStatusRunning, sql.NullString{}))
+	audits.entries = append(audits.entries, &models.AuditLog{ID: uuid.New(), Action: "Campaign Paused",
+		EntityType: sql.NullString{String: "Campaign", Valid: true}, EntityID: uuid.NullUUID{UUID: campaignID, Valid: true}})
+
+	orchestrator := &campaignOrchestratorServiceImpl{campaignStore: campaigns, auditLogStore: audits}
+	history, err := orchestrator.ListCampaignStatusHistory(context.Background(), campaignID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, history, 1, "other campaign audit events are not part of the status history")
+	assert.Equal(t, models.CampaignStatusRunning, history[0].To)
+	assert.Equal(t, "worker-1", history[0].WorkerID)
+
+	_, err = orchestrator.ListCampaignStatusHistory(context.Background(), uuid.New(), 10, 0)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
@@ -0,0 +1,22 @@
+package services
+
+import "strings"
+
+// NormalizeCampaignTags trims and lower-cases tags, dropping empty entries and duplicates while keeping
+// the caller's order, so tag filters match regardless of how a tag was typed.
+func NormalizeCampaignTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
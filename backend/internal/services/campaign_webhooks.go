@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Event types of campaign webhook notifications, one per terminal status
+const (
+	WebhookEventCampaignCompleted = "campaign.completed"
+	WebhookEventCampaignFailed    = "campaign.failed"
+	WebhookEventCampaignCancelled = "campaign.cancelled"
+)
+
+// campaignWebhookBatchSize bounds the webhooks notified by one sweep
+const campaignWebhookBatchSize = 50
+
+// campaignWebhookEvents maps the statuses campaign webhooks are notified of to their event type
+var campaignWebhookEvents = map[models.CampaignStatusEnum]string{
+	models.CampaignStatusCompleted: WebhookEventCampaignCompleted,
+	models.CampaignStatusFailed:    WebhookEventCampaignFailed,
+	models.CampaignStatusCancelled: WebhookEventCampaignCancelled,
+}
+
+// ErrInvalidCampaignWebhookURL is returned when registering a webhook whose URL is not an absolute http(s) URL
+var ErrInvalidCampaignWebhookURL = errors.New("webhook url must be an absolute http or https URL")
+
+// CampaignWebhookPayload is the body of a campaign webhook notification
+type CampaignWebhookPayload struct {
+	Event           string                    `json:"event"`
+	CampaignID      uuid.UUID                 `json:"campaignId"`
+	CampaignType    models.CampaignTypeEnum   `json:"campaignType"`
+	Status          models.CampaignStatusEnum `json:"status"`
+	TotalItems      int64                     `json:"totalItems"`
+	ProcessedItems  int64                     `json:"processedItems"`
+	SuccessfulItems int64                     `json:"successfulItems"`
+	FailedItems     int64                     `json:"failedItems"`
+	ErrorMessage    string                    `json:"errorMessage,omitempty"`
+	Timestamp       time.Time                 `json:"timestamp"`
+}
+
+// CampaignWebhookService manages the webhooks registered on campaigns and notifies them once their
+// campaign completes, fails or is cancelled. Status changes only trigger a sweep, which reads
+// committed campaigns, so a transition made in a transaction that is rolled back is never sent, and
+// a periodic sweep catches anything a trigger missed. Each webhook is notified once per terminal
+// status, signed with its own secret; a failed notification is retried with exponential backoff
+// and, once given up, stays recorded as a failed event delivery that an admin can replay.
+type CampaignWebhookService struct {
+	db            *sqlx.DB
+	webhookStore  store.CampaignWebhookStore
+	campaignStore store.CampaignStore
+	webhooks      *WebhookService
+	interval      time.Duration
+	maxAttempts   int
+	retryBackoff  time.Duration
+	trigger       chan struct{}
+	now           func() time.Time
+}
+
+// NewCampaignWebhookService creates a campaign webhook service delivering through webhooks, which
+// must be able to read the webhooks' secrets from webhookStore (see WebhookService.SetCampaignWebhookStore)
+func NewCampaignWebhookService(db *sqlx.DB, webhookStore store.CampaignWebhookStore, campaignStore store.CampaignStore, webhooks *WebhookService, cfg config.CampaignWebhookConfig) *CampaignWebhookService {
+	svc := &CampaignWebhookService{
+		db:            db,
+		webhookStore:  webhookStore,
+		campaignStore: campaignStore,
+		webhooks:      webhooks,
+		interval:      time.Duration(cfg.CheckIntervalSeconds) * time.Second,
+		maxAttempts:   cfg.MaxAttempts,
+		retryBackoff:  time.Duration(cfg.RetryBackoffSeconds) * time.Second,
+		trigger:       make(chan struct{}, 1),
+		now:           time.Now,
+	}
+	if svc.interval <= 0 {
+		svc.interval = time.Duration(config.DefaultCampaignWebhookCheckIntervalSeconds) * time.Second
+	}
+	if svc.maxAttempts <= 0 {
+		svc.maxAttempts = config.DefaultCampaignWebhookMaxAttempts
+	}
+	if svc.retryBackoff <= 0 {
+		svc.retryBackoff = time.Duration(config.DefaultCampaignWebhookRetryBackoffSeconds) * time.Second
+	}
+	return svc
+}
+
+// Register adds a webhook to a campaign. A random secret is generated when secret is empty; the
+// caller has to hand it to the receiver, as it is not returned again. A webhook registered on a
+// campaign that has already finished is notified of its status straight away.
+func (s *CampaignWebhookService) Register(ctx context.Context, campaignID uuid.UUID, rawURL, secret string, createdBy *uuid.UUID) (*models.CampaignWebhook, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, ErrInvalidCampaignWebhookURL
+	}
+	if _, err := s.campaignStore.GetCampaignByID(ctx, s.querier(), campaignID); err != nil {
+		return nil, err
+	}
+	if secret == "" {
+		if secret, err = generateCampaignWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	webhook := &models.CampaignWebhook{
+		CampaignID: campaignID,
+		URL:        rawURL,
+		Secret:     secret,
+		CreatedAt:  s.now().UTC(),
+	}
+	if createdBy != nil {
+		webhook.CreatedBy = uuid.NullUUID{UUID: *createdBy, Valid: true}
+	}
+	if err := s.webhookStore.CreateCampaignWebhook(ctx, s.querier(), webhook); err != nil {
+		return nil, fmt.Errorf("failed to create campaign webhook: %w", err)
+	}
+	s.Trigger()
+	return webhook, nil
+}
+
+// Delete removes a webhook from a campaign; store.ErrNotFound if the campaign has no such webhook
+func (s *CampaignWebhookService) Delete(ctx context.Context, campaignID, webhookID uuid.UUID) error {
+	return s.webhookStore.DeleteCampaignWebhook(ctx, s.querier(), campaignID, webhookID)
+}
+
+// Trigger asks for a sweep without waiting for it. It is called whenever a campaign status may
+// have become terminal.
+func (s *CampaignWebhookService) Trigger() {
+	if s == nil {
+		return
+	}
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Start notifies due webhooks when triggered and every check interval until ctx is cancelled
+func (s *CampaignWebhookService) Start(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.trigger:
+				s.NotifyDue(ctx)
+			case <-ticker.C:
+				s.NotifyDue(ctx)
+			}
+		}
+	}()
+}
+
+// NotifyDue notifies every webhook of a terminal campaign not yet told of the campaign's current
+// status and returns the deliveries it made once they have succeeded or been given up
+func (s *CampaignWebhookService) NotifyDue(ctx context.Context) []*models.EventDelivery {
+	if s == nil {
+		return nil
+	}
+	due, err := s.webhookStore.ListCampaignWebhooksToNotify(ctx, s.querier(), campaignWebhookBatchSize)
+	if err != nil {
+		log.Printf("CampaignWebhookService: Failed to list webhooks to notify: %v", err)
+		return nil
+	}
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		deliveries []*models.EventDelivery
+	)
+	campaigns := make(map[uuid.UUID]*models.Campaign)
+	for _, webhook := range due {
+		campaign, ok := campaigns[webhook.CampaignID]
+		if !ok {
+			if campaign, err = s.campaignStore.GetCampaignByID(ctx, s.querier(), webhook.CampaignID); err != nil {
+				log.Printf("CampaignWebhookService: Failed to load campaign %s: %v", webhook.CampaignID, err)
+				continue
+			}
+			campaigns[webhook.CampaignID] = campaign
+		}
+		eventType, terminal := campaignWebhookEvents[campaign.Status]
+		if !terminal {
+			continue
+		}
+		claimed, err := s.webhookStore.MarkCampaignWebhookNotified(ctx, s.querier(), webhook.ID, campaign.Status, s.now().UTC())
+		if err != nil {
+			log.Printf("CampaignWebhookService: Failed to mark webhook %s notified: %v", webhook.ID, err)
+			continue
+		}
+		if !claimed {
+			continue // Another server is notifying it
+		}
+
+		payload := newCampaignWebhookPayload(eventType, campaign, s.now().UTC())
+		wg.Add(1)
+		go func(webhook *models.CampaignWebhook) {
+			defer wg.Done()
+			if delivery := s.deliver(ctx, webhook, payload); delivery != nil {
+				mu.Lock()
+				deliveries = append(deliveries, delivery)
+				mu.Unlock()
+			}
+		}(webhook)
+	}
+	wg.Wait()
+	return deliveries
+}
+
+// deliver sends a notification, retrying a failed delivery up to the attempt limit
+func (s *CampaignWebhookService) deliver(ctx context.Context, webhook *models.CampaignWebhook, payload *CampaignWebhookPayload) *models.EventDelivery {
+	delivery, err := s.webhooks.DispatchToCampaignWebhook(ctx, payload.Event, webhook, payload)
+	if delivery == nil {
+		log.Printf("CampaignWebhookService: Failed to notify webhook %s of campaign %s: %v", webhook.ID, webhook.CampaignID, err)
+		return nil
+	}
+	backoff := s.retryBackoff
+	for attempt := 1; delivery.Status != models.EventDeliveryStatusDelivered; attempt++ {
+		if attempt >= s.maxAttempts {
+			log.Printf("CampaignWebhookService: Giving up %s notification of webhook %s (delivery %s) after %d attempts", payload.Event, webhook.ID, delivery.ID, attempt)
+			return delivery
+		}
+		select {
+		case <-ctx.Done():
+			return delivery
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		retried, err := s.webhooks.Replay(ctx, delivery.ID)
+		if retried == nil {
+			log.Printf("CampaignWebhookService: Failed to retry delivery %s: %v", delivery.ID, err)
+			return delivery
+		}
+		delivery = retried
+	}
+	return delivery
+}
+
+func (s *CampaignWebhookService) querier() store.Querier {
+	if s.db == nil {
+		return nil
+	}
+	return s.db
+}
+
+func newCampaignWebhookPayload(eventType string, campaign *models.Campaign, at time.Time) *CampaignWebhookPayload {
+	payload := &CampaignWebhookPayload{
+		Event:        eventType,
+		CampaignID:   campaign.ID,
+		CampaignType: campaign.CampaignType,
+		Status:       campaign.Status,
+		Timestamp:    at,
+	}
+	if campaign.TotalItems != nil {
+		payload.TotalItems = *campaign.TotalItems
+	}
+	if campaign.ProcessedItems != nil {
+		payload.ProcessedItems = *campaign.ProcessedItems
+	}
+	if campaign.SuccessfulItems != nil {
+		payload.SuccessfulItems = *campaign.SuccessfulItems
+	}
+	if campaign.FailedItems != nil {
+		payload.FailedItems = *campaign.FailedItems
+	}
+	if campaign.ErrorMessage != nil {
+		payload.ErrorMessage = *campaign.ErrorMessage
+	}
+	return payload
+}
+
+func generateCampaignWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// campaignWebhookTriggeringStore triggers a campaign webhook sweep after every update that leaves a
+// campaign completed, failed or cancelled
+type campaignWebhookTriggeringStore struct {
+	store.CampaignStore
+	notifier *CampaignWebhookService
+}
+
+// NewCampaignWebhookTriggeringStore wraps campaignStore so terminal status changes notify the
+// campaign's webhooks. It returns campaignStore itself when notifier is nil.
+func NewCampaignWebhookTriggeringStore(campaignStore store.CampaignStore, notifier *CampaignWebhookService) store.CampaignStore {
+	if notifier == nil {
+		return campaignStore
+	}
+	return &campaignWebhookTriggeringStore{CampaignStore: campaignStore, notifier: notifier}
+}
+
+func (s *campaignWebhookTriggeringStore) UpdateCampaign(ctx context.Context, exec store.Querier, campaign *models.Campaign) error {
+	err := s.CampaignStore.UpdateCampaign(ctx, exec, campaign)
+	if _, terminal := campaignWebhookEvents[campaign.Status]; err == nil && terminal {
+		s.notifier.Trigger()
+	}
+	return err
+}
+
+func (s *campaignWebhookTriggeringStore) UpdateCampaignStatus(ctx context.Context, exec store.Querier, id uuid.UUID, status models.CampaignStatusEnum, errorMessage sql.NullString) error {
+	err := s.CampaignStore.UpdateCampaignStatus(ctx, exec, id, status, errorMessage)
+	if _, terminal := campaignWebhookEvents[status]; err == nil && terminal {
+		s.notifier.Trigger()
+	}
+	return err
+}
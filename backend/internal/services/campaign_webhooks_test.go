@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCampaignWebhookStore keeps webhooks in memory and reads campaign statuses from campaigns
+type memoryCampaignWebhookStore struct {
+	mu        sync.Mutex
+	campaigns *campaignLookupStore
+	webhooks  map[uuid.UUID]models.CampaignWebhook
+}
+
+func (m *memoryCampaignWebhookStore) CreateCampaignWebhook(ctx context.Context, exec store.Querier, webhook *models.CampaignWebhook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if webhook.ID == uuid.Nil {
+		webhook.ID = uuid.New()
+	}
+	m.webhooks[webhook.ID] = *webhook
+	return nil
+}
+
+func (m *memoryCampaignWebhookStore) GetCampaignWebhookByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.CampaignWebhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	webhook, ok := m.webhooks[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return &webhook, nil
+}
+
+func (m *memoryCampaignWebhookStore) DeleteCampaignWebhook(ctx context.Context, exec store.Querier, campaignID, webhookID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if webhook, ok := m.webhooks[webhookID]; !ok || webhook.CampaignID != campaignID {
+		return store.ErrNotFound
+	}
+	delete(m.webhooks, webhookID)
+	return nil
+}
+
+func (m *memoryCampaignWebhookStore) ListCampaignWebhooksToNotify(ctx context.Context, exec store.Querier, limit int) ([]*models.CampaignWebhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	due := []*models.CampaignWebhook{}
+	for _, webhook := range m.webhooks {
+		campaign := m.campaigns.campaigns[webhook.CampaignID]
+		if _, terminal := campaignWebhookEvents[campaign.Status]; terminal && webhook.NotifiedStatus.String != string(campaign.Status) {
+			w := webhook
+			due = append(due, &w)
+		}
+	}
+	return due, nil
+}
+
+func (m *memoryCampaignWebhookStore) MarkCampaignWebhookNotified(ctx context.Context, exec store.Querier, webhookID uuid.UUID, status models.CampaignStatusEnum, notifiedAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	webhook := m.webhooks[webhookID]
+	if webhook.NotifiedStatus.String == string(status) {
+		return false, nil
+	}
+	webhook.NotifiedStatus = sql.NullString{String: string(status), Valid: true}
+	webhook.NotifiedAt = sql.NullTime{Time: notifiedAt, Valid: true}
+	m.webhooks[webhookID] = webhook
+	return true, nil
+}
+
+// notificationReceiver records campaign notifications signed with secret, failing the first failures requests
+type notificationReceiver struct {
+	t        *testing.T
+	secret   string
+	failures int32
+	requests atomic.Int32
+	mu       sync.Mutex
+	received []CampaignWebhookPayload
+}
+
+func (r *notificationReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	timestamp, err := strconv.ParseInt(req.Header.Get(WebhookTimestampHeader), 10, 64)
+	if assert.NoError(r.t, err) {
+		assert.Equal(r.t, SignWebhookPayload(r.secret, timestamp, body), req.Header.Get(WebhookSignatureHeader))
+	}
+	if r.requests.Add(1) <= r.failures {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	var payload CampaignWebhookPayload
+	require.NoError(r.t, json.Unmarshal(body, &payload))
+	assert.Equal(r.t, payload.Event, req.Header.Get(WebhookEventHeader))
+	r.mu.Lock()
+	r.received = append(r.received, payload)
+	r.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type campaignWebhookFixture struct {
+	campaigns     *campaignLookupStore
+	webhookStore  *memoryCampaignWebhookStore
+	deliveryStore *memoryEventDeliveryStore
+	webhooks      *WebhookService
+	svc           *CampaignWebhookService
+}
+
+func newCampaignWebhookFixture(campaigns ...*models.Campaign) *campaignWebhookFixture {
+	f := &campaignWebhookFixture{
+		campaigns:     &campaignLookupStore{campaigns: make(map[uuid.UUID]*models.Campaign)},
+		deliveryStore: newMemoryEventDeliveryStore(),
+	}
+	for _, campaign := range campaigns {
+		f.campaigns.campaigns[campaign.ID] = campaign
+	}
+	f.webhookStore = &memoryCampaignWebhookStore{campaigns: f.campaigns, webhooks: make(map[uuid.UUID]models.CampaignWebhook)}
+	// The server secret must not be what campaign webhooks are signed with
+	f.webhooks = NewWebhookService(f.deliveryStore, config.WebhookConfig{SigningSecret: "server-secret", TimeoutSeconds: 5})
+	f.webhooks.SetCampaignWebhookStore(f.webhookStore)
+	f.svc = NewCampaignWebhookService(nil, f.webhookStore, f.campaigns, f.webhooks, config.CampaignWebhookConfig{MaxAttempts: 3})
+	f.svc.retryBackoff = 5 * time.Millisecond
+	return f
+}
+
+func TestCampaignWebhookService_NotifiesTerminalStatusOnceWithRetries(t *testing.T) {
+	ctx := context.Background()
+	campaign := &models.Campaign{ID: uuid.New(), CampaignType: models.CampaignTypeDNSValidation, Status: models.CampaignStatusRunning,
+		TotalItems: models.Int64Ptr(10), ProcessedItems: models.Int64Ptr(10), SuccessfulItems: models.Int64Ptr(8), FailedItems: models.Int64Ptr(2)}
+	f := newCampaignWebhookFixture(campaign)
+	receiver := &notificationReceiver{t: t, secret: "per-webhook-secret-1234", failures: 2}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	webhook, err := f.svc.Register(ctx, campaign.ID, server.URL, receiver.secret, nil)
+	require.NoError(t, err)
+	assert.Empty(t, f.svc.NotifyDue(ctx), "running campaigns are not notified")
+
+	campaign.Status = models.CampaignStatusCompleted
+	deliveries := f.svc.NotifyDue(ctx)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.EventDeliveryStatusDelivered, deliveries[0].Status)
+	assert.Equal(t, 3, deliveries[0].Attempts, "two failures are retried")
+	assert.Equal(t, webhook.ID, deliveries[0].CampaignWebhookID.UUID)
+	attempts, err := f.webhooks.ListAttempts(ctx, deliveries[0].ID)
+	require.NoError(t, err)
+	assert.Len(t, attempts, 3)
+
+	require.Len(t, receiver.received, 1)
+	payload := receiver.received[0]
+	assert.Equal(t, WebhookEventCampaignCompleted, payload.Event)
+	assert.Equal(t, campaign.ID, payload.CampaignID)
+	assert.Equal(t, models.CampaignTypeDNSValidation, payload.CampaignType)
+	assert.Equal(t, int64(10), payload.TotalItems)
+	assert.Equal(t, int64(8), payload.SuccessfulItems)
+	assert.Equal(t, int64(2), payload.FailedItems)
+	assert.False(t, payload.Timestamp.IsZero())
+
+	// Each terminal status is sent once
+	assert.Empty(t, f.svc.NotifyDue(ctx))
+	campaign.Status = models.CampaignStatusFailed
+	require.Len(t, f.svc.NotifyDue(ctx), 1)
+	require.Len(t, receiver.received, 2)
+	assert.Equal(t, WebhookEventCampaignFailed, receiver.received[1].Event)
+
+	// A deleted webhook's deliveries can no longer be signed, so they are not replayed
+	require.NoError(t, f.svc.Delete(ctx, campaign.ID, webhook.ID))
+	replayed, err := f.webhooks.Replay(ctx, deliveries[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.EventDeliveryStatusFailed, replayed.Status)
+	assert.Contains(t, replayed.LastError.String, "no longer exists")
+	assert.Equal(t, int32(4), receiver.requests.Load())
+}
+
+func TestCampaignWebhookService_GivesUpAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	campaign := &models.Campaign{ID: uuid.New(), CampaignType: models.CampaignTypeDomainGeneration, Status: models.CampaignStatusCancelled}
+	f := newCampaignWebhookFixture(campaign)
+	receiver := &notificationReceiver{t: t, failures: 100}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	webhook, err := f.svc.Register(ctx, campaign.ID, server.URL, "", nil)
+	require.NoError(t, err)
+	assert.Len(t, webhook.Secret, 64, "a secret is generated when none is given")
+	receiver.secret = webhook.Secret
+
+	deliveries := f.svc.NotifyDue(ctx)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.EventDeliveryStatusFailed, deliveries[0].Status)
+	assert.Equal(t, 3, deliveries[0].Attempts)
+	assert.Equal(t, int32(3), receiver.requests.Load())
+	assert.Equal(t, int32(http.StatusBadGateway), deliveries[0].LastStatusCode.Int32)
+
+	// A notification given up is not sent again by later sweeps
+	assert.Empty(t, f.svc.NotifyDue(ctx))
+}
+
+func TestCampaignWebhookService_RegisterValidates(t *testing.T) {
+	ctx := context.Background()
+	campaign := &models.Campaign{ID: uuid.New(), Status: models.CampaignStatusPending}
+	f := newCampaignWebhookFixture(campaign)
+
+	for _, rawURL := range []string{"", "not a url", "ftp://example.com/hook", "/relative/hook", "https://"} {
+		_, err := f.svc.Register(ctx, campaign.ID, rawURL, "", nil)
+		assert.ErrorIs(t, err, ErrInvalidCampaignWebhookURL, rawURL)
+	}
+	_, err := f.svc.Register(ctx, uuid.New(), "https://example.com/hook", "", nil)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	createdBy := uuid.New()
+	webhook, err := f.svc.Register(ctx, campaign.ID, " https://example.com/hook ", "", &createdBy)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/hook", webhook.URL)
+	assert.Equal(t, createdBy, webhook.CreatedBy.UUID)
+
+	assert.ErrorIs(t, f.svc.Delete(ctx, uuid.New(), webhook.ID), store.ErrNotFound, "webhooks are deleted through their own campaign")
+}
+
+// statusRecordingCampaignStore accepts every status update
+type statusRecordingCampaignStore struct {
+	store.CampaignStore
+}
+
+func (s *statusRecordingCampaignStore) UpdateCampaign(ctx context.Context, exec store.Querier, campaign *models.Campaign) error {
+	return nil
+}
+
+func (s *statusRecordingCampaignStore) UpdateCampaignStatus(ctx context.Context, exec store.Querier, id uuid.UUID, status models.CampaignStatusEnum, errorMessage sql.NullString) error {
+	return nil
+}
+
+func TestCampaignWebhookTriggeringStore_TriggersOnTerminalStatus(t *testing.T) {
+	ctx := context.Background()
+	f := newCampaignWebhookFixture()
+	cs := NewCampaignWebhookTriggeringStore(&statusRecordingCampaignStore{}, f.svc)
+
+	require.NoError(t, cs.UpdateCampaignStatus(ctx, nil, uuid.New(), models.CampaignStatusRunning, sql.NullString{}))
+	require.NoError(t, cs.UpdateCampaign(ctx, nil, &models.Campaign{ID: uuid.New(), Status: models.CampaignStatusPaused}))
+	assert.Len(t, f.svc.trigger, 0)
+
+	require.NoError(t, cs.UpdateCampaignStatus(ctx, nil, uuid.New(), models.CampaignStatusFailed, sql.NullString{}))
+	assert.Len(t, f.svc.trigger, 1)
+	<-f.svc.trigger
+	require.NoError(t, cs.UpdateCampaign(ctx, nil, &models.Campaign{ID: uuid.New(), Status: models.CampaignStatusCompleted}))
+	assert.Len(t, f.svc.trigger, 1)
+
+	// Without a notifier the store is returned unwrapped
+	inner := &statusRecordingCampaignStore{}
+	assert.Same(t, inner, NewCampaignWebhookTriggeringStore(inner, nil))
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingDNSService fails every DNS validation batch
+type failingDNSService struct {
+	DNSCampaignService
+}
+
+func (s *failingDNSService) ProcessDNSValidationCampaignBatch(ctx context.Context, campaignID uuid.UUID) (bool, int, error) {
+	return false, 0, errors.New("resolver unavailable")
+}
+
+func TestJobRetryDelay_GrowsToCap(t *testing.T) {
+	cfg := config.WorkerConfig{BackoffBaseSeconds: 10, BackoffMaxSeconds: 100, BackoffMultiplier: 2}
+	expected := []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, 80 * time.Second, 100 * time.Second, 100 * time.Second}
+	for retry, want := range expected {
+		assert.Equal(t, want, jobRetryDelay(cfg, retry, 0.5), "retry %d", retry)
+	}
+
+	// Jitter stays within 20% either way
+	assert.Equal(t, 8*time.Second, jobRetryDelay(cfg, 0, 0))
+	assert.InDelta(t, float64(12*time.Second), float64(jobRetryDelay(cfg, 0, 0.9999)), float64(time.Millisecond))
+	assert.Equal(t, 80*time.Second, jobRetryDelay(cfg, 10, 0))
+	assert.InDelta(t, float64(120*time.Second), float64(jobRetryDelay(cfg, 10, 0.9999)), float64(10*time.Millisecond))
+
+	// Without backoff settings the base is the error retry delay
+	assert.Equal(t, 5*time.Second, jobRetryDelay(config.WorkerConfig{ErrorRetryDelaySeconds: 5}, 0, 0.5))
+	assert.Equal(t, 30*time.Second, jobRetryDelay(config.WorkerConfig{}, 0, 0.5))
+	assert.Equal(t, time.Duration(config.DefaultJobBackoffMaxSeconds)*time.Second, jobRetryDelay(config.WorkerConfig{}, 50, 0.5))
+}
+
+func TestProcessJob_SchedulesRetryWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	jobStore := &memoryCampaignJobStore{}
+	appCfg := &config.AppConfig{Worker: config.WorkerConfig{BackoffBaseSeconds: 10, BackoffMaxSeconds: 100, BackoffMultiplier: 3}}
+	worker := NewCampaignWorkerService(jobStore, nil, &failingDNSService{}, nil, nil, "worker-test", appCfg).(*campaignWorkerServiceImpl)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	worker.now = func() time.Time { return now }
+	worker.random = func() float64 { return 0.5 }
+
+	job := &models.CampaignJob{ID: uuid.New(), CampaignID: uuid.New(), JobType: models.CampaignTypeDNSValidation,
+		Status: models.JobStatusProcessing, MaxAttempts: 5}
+	require.NoError(t, jobStore.CreateJob(ctx, nil, job))
+
+	// Attempts count the current one, so the second failure is scheduled after the second retry delay
+	for attempt, wait := range []time.Duration{10 * time.Second, 30 * time.Second, 90 * time.Second, 100 * time.Second} {
+		job.Attempts = attempt + 1
+		worker.processJob(ctx, job, "worker-test")
+
+		stored, err := jobStore.GetJobByID(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.JobStatusRetry, stored.Status)
+		require.True(t, stored.NextExecutionAt.Valid)
+		assert.Equal(t, now.Add(wait), stored.NextExecutionAt.Time, "attempt %d", job.Attempts)
+	}
+}
+
+// deadLetteringJobStore keeps the jobs it dead-letters
+type deadLetteringJobStore struct {
+	memoryCampaignJobStore
+	deadLetters []*models.CampaignJob
+}
+
+func (m *deadLetteringJobStore) DeadLetterJob(ctx context.Context, exec store.Querier, job *models.CampaignJob) (*models.DeadLetterJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, existing := range m.jobs {
+		if existing.ID == job.ID {
+			m.jobs = append(m.jobs[:i], m.jobs[i+1:]...)
+			j := *job
+			m.deadLetters = append(m.deadLetters, &j)
+			return &models.DeadLetterJob{ID: job.ID, LastError: job.LastError.String}, nil
+		}
+	}
+	return nil, store.ErrNotFound
+}
+
+func (m *deadLetteringJobStore) ListDeadLetterJobs(ctx context.Context, exec store.Querier, filter store.ListDeadLetterJobsFilter) ([]*models.DeadLetterJob, error) {
+	return nil, nil
+}
+
+func (m *deadLetteringJobStore) ReplayDeadLetterJob(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.CampaignJob, error) {
+	return nil, store.ErrNotFound
+}
+
+func TestProcessJob_DeadLettersExhaustedJobs(t *testing.T) {
+	ctx := context.Background()
+	jobStore := &deadLetteringJobStore{}
+	worker := NewCampaignWorkerService(jobStore, nil, &failingDNSService{}, nil, nil, "worker-test", &config.AppConfig{}).(*campaignWorkerServiceImpl)
+	worker.random = func() float64 { return 0.5 }
+
+	job := &models.CampaignJob{ID: uuid.New(), CampaignID: uuid.New(), JobType: models.CampaignTypeDNSValidation,
+		Status: models.JobStatusProcessing, MaxAttempts: 2}
+	require.NoError(t, jobStore.CreateJob(ctx, nil, job))
+
+	job.Attempts = 1
+	worker.processJob(ctx, job, "worker-a")
+	assert.Empty(t, jobStore.deadLetters, "the job has a retry left")
+	job, err := jobStore.GetJobByID(ctx, job.ID)
+	require.NoError(t, err)
+
+	job.Attempts = 2
+	worker.processJob(ctx, job, "worker-b")
+	_, err = jobStore.GetJobByID(ctx, job.ID)
+	assert.ErrorIs(t, err, store.ErrNotFound, "the exhausted job leaves the queue")
+	require.Len(t, jobStore.deadLetters, 1)
+	deadLetter := jobStore.deadLetters[0]
+	assert.Equal(t, "resolver unavailable", deadLetter.LastError.String)
+
+	var attempts []models.CampaignJobAttempt
+	require.NotNil(t, deadLetter.AttemptHistory)
+	require.NoError(t, json.Unmarshal(*deadLetter.AttemptHistory, &attempts))
+	require.Len(t, attempts, 2)
+	assert.Equal(t, 1, attempts[0].Attempt)
+	assert.Equal(t, "worker-a", attempts[0].WorkerID)
+	assert.Equal(t, 2, attempts[1].Attempt)
+	assert.Equal(t, "worker-b", attempts[1].WorkerID)
+	assert.Equal(t, "resolver unavailable", attempts[1].Error)
+}
+
+func TestProcessJob_KeepsExhaustedJobsFailedWithoutDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	jobStore := &memoryCampaignJobStore{}
+	worker := NewCampaignWorkerService(jobStore, nil, &failingDNSService{}, nil, nil, "worker-test", &config.AppConfig{}).(*campaignWorkerServiceImpl)
+
+	job := &models.CampaignJob{ID: uuid.New(), CampaignID: uuid.New(), JobType: models.CampaignTypeDNSValidation,
+		Status: models.JobStatusProcessing, Attempts: 1, MaxAttempts: 1}
+	require.NoError(t, jobStore.CreateJob(ctx, nil, job))
+	worker.processJob(ctx, job, "worker-test")
+
+	stored, err := jobStore.GetJobByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusFailed, stored.Status)
+}
+
+func TestAppendJobAttempt_KeepsTheLatest(t *testing.T) {
+	var history *json.RawMessage
+	for i := 1; i <= maxJobAttemptHistory+5; i++ {
+		history = appendJobAttempt(history, models.CampaignJobAttempt{Attempt: i, Error: "failed"})
+	}
+	var attempts []models.CampaignJobAttempt
+	require.NoError(t, json.Unmarshal(*history, &attempts))
+	require.Len(t, attempts, maxJobAttemptHistory)
+	assert.Equal(t, 6, attempts[0].Attempt)
+	assert.Equal(t, maxJobAttemptHistory+5, attempts[len(attempts)-1].Attempt)
+}
@@ -4,24 +4,37 @@ package services
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
+	"math/rand"
 	"os"
 	"sync"
 	"time"
 
 	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/metrics"
 	"github.com/fntelecomllc/studio/backend/internal/models"
 	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/fntelecomllc/studio/backend/internal/tracing"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Default worker settings if not provided by config
 const (
-	workerPollIntervalDefault    = 5 * time.Second
-	workerErrorRetryDelayDefault = 30 * time.Second
-	workerMaxRetriesDefault      = 3
-	workerJobTimeoutDefault      = 15 * time.Minute
+	workerPollIntervalDefault = 5 * time.Second
+	workerMaxRetriesDefault   = 3
+	workerJobTimeoutDefault   = 15 * time.Minute
+	workerJobHeartbeatDefault = time.Minute
+
+	// jobRetryJitter is the fraction by which a job's retry delay is randomly lengthened or shortened,
+	// so jobs failing together do not all retry at the same moment
+	jobRetryJitter = 0.2
+
+	// maxJobAttemptHistory bounds the failed attempts kept on a job, dropping the oldest first
+	maxJobAttemptHistory = 20
 )
 
 type campaignWorkerServiceImpl struct {
@@ -32,6 +45,10 @@ type campaignWorkerServiceImpl struct {
 	campaignOrchestratorSvc CampaignOrchestratorService
 	workerID                string
 	appConfig               *config.AppConfig // Added AppConfig
+	health                  *workerHealthTracker
+	now                     func() time.Time
+	random                  func() float64 // Jitter source for retry delays, in [0, 1)
+	heartbeatInterval       time.Duration  // How often a processing job's heartbeat is recorded; 0 is workerJobHeartbeatDefault
 }
 
 // NewCampaignWorkerService creates a new CampaignWorkerService.
@@ -60,6 +77,9 @@ func NewCampaignWorkerService(
 		campaignOrchestratorSvc: cos,
 		workerID:                workerID,
 		appConfig:               appCfg, // Store appConfig
+		health:                  newWorkerHealthTracker(),
+		now:                     time.Now,
+		random:                  rand.Float64,
 	}
 }
 
@@ -83,7 +103,9 @@ func (s *campaignWorkerServiceImpl) StartWorkers(ctx context.Context, numWorkers
 			defer wg.Done()
 			workerName := fmt.Sprintf("%s-%d", s.workerID, workerNum)
 			log.Printf("Worker [%s]: Started.", workerName)
+			s.health.workerStarted(s.now(), pollInterval)
 			s.workerLoop(ctx, workerName, pollInterval)
+			s.health.workerStopped()
 			log.Printf("Worker [%s]: Stopped.", workerName)
 		}(i)
 	}
@@ -108,6 +130,7 @@ func (s *campaignWorkerServiceImpl) workerLoop(ctx context.Context, workerName s
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			s.health.polled(s.now())
 			// GetNextQueuedJob filters by workerID to attempt to claim a job.
 			// If campaignTypes is nil or empty, it fetches for any type.
 			// This will pick up both queued jobs and retry jobs whose next_execution_at time has passed
@@ -123,16 +146,38 @@ func (s *campaignWorkerServiceImpl) workerLoop(ctx context.Context, workerName s
 			log.Printf("Worker [%s]: Picked up job %s for campaign %s (type: %s, attempt: %d)",
 				workerName, job.ID, job.CampaignID, job.JobType, job.Attempts)
 
+			s.health.jobStarted(job.JobType)
+			metrics.RecordJobTransition(job.JobType, job.Status)
 			s.processJob(ctx, job, workerName)
 		}
 	}
 }
 
 func (s *campaignWorkerServiceImpl) processJob(ctx context.Context, job *models.CampaignJob, workerName string) {
+	// Status changes made while processing the job are attributed to this worker
+	ctx = WithCampaignStatusActor(ctx, CampaignStatusActor{
+		Type:     StatusActorWorker,
+		WorkerID: workerName,
+		Reason:   fmt.Sprintf("%s job %s", job.JobType, job.ID),
+	})
+
 	var batchDone bool
 	var processedCount int
 	var processErr error
 
+	// Each job is a trace of its own; the batch's store queries become children of its span
+	ctx, span := tracing.Tracer().Start(ctx, "CampaignWorker.processJob", trace.WithNewRoot(), trace.WithAttributes(
+		attribute.String("job.id", job.ID.String()),
+		attribute.String("job.type", string(job.JobType)),
+		attribute.Int("job.attempt", job.Attempts),
+		attribute.String("campaign.id", job.CampaignID.String()),
+		attribute.String("worker.id", workerName),
+	))
+	defer func() {
+		span.SetAttributes(attribute.String("job.status", string(job.Status)), attribute.Int("job.processed_count", processedCount))
+		tracing.End(span, processErr)
+	}()
+
 	// Get max retries configuration
 	maxRetries := job.MaxAttempts
 	if maxRetries <= 0 {
@@ -150,6 +195,7 @@ func (s *campaignWorkerServiceImpl) processJob(ctx context.Context, job *models.
 	}
 	jobCtx, cancelJobCtx := context.WithTimeout(ctx, jobTimeout)
 	defer cancelJobCtx()
+	heartbeat := s.startJobHeartbeat(jobCtx, cancelJobCtx, job, workerName, s.jobHeartbeatInterval(jobTimeout))
 
 	switch job.JobType {
 	case models.CampaignTypeDomainGeneration:
@@ -175,10 +221,23 @@ func (s *campaignWorkerServiceImpl) processJob(ctx context.Context, job *models.
 	}
 
 	job.UpdatedAt = time.Now().UTC()
+	s.health.jobFinished(job.JobType, processErr, s.now())
+
+	heartbeat.stop()
+	if heartbeat.lost.Load() {
+		// The job was reaped or claimed by another worker while this one ran, so its row is no longer ours to update
+		return
+	}
 
 	if processErr != nil {
 		log.Printf("Worker [%s]: Error processing job %s (campaign %s): %v", workerName, job.ID, job.CampaignID, processErr)
 		job.LastError = sql.NullString{String: processErr.Error(), Valid: true}
+		job.AttemptHistory = appendJobAttempt(job.AttemptHistory, models.CampaignJobAttempt{
+			Attempt:  job.Attempts,
+			WorkerID: workerName,
+			Error:    processErr.Error(),
+			FailedAt: s.now().UTC(),
+		})
 		maxRetries := job.MaxAttempts
 		if maxRetries <= 0 {
 			maxRetries = s.appConfig.Worker.MaxJobRetries
@@ -190,6 +249,7 @@ func (s *campaignWorkerServiceImpl) processJob(ctx context.Context, job *models.
 		if job.Attempts >= maxRetries {
 			// Max retries reached, mark job as failed
 			job.Status = models.JobStatusFailed
+			metrics.RecordJobTransition(job.JobType, job.Status)
 			log.Printf("Worker [%s]: Job %s failed after %d attempts. Last error: %s", workerName, job.ID, job.Attempts, processErr.Error())
 
 			// Update campaign status - need to check current campaign status to ensure valid state transition
@@ -217,16 +277,24 @@ func (s *campaignWorkerServiceImpl) processJob(ctx context.Context, job *models.
 					}
 				}
 			}
+
+			// Stores that keep dead letters take the job out of the queue; others keep it as failed.
+			// ctx rather than jobCtx, which has run out when the job timed out.
+			if deadLetters, ok := s.jobStore.(store.CampaignJobDeadLetterStore); ok {
+				if _, err := deadLetters.DeadLetterJob(ctx, nil, job); err != nil {
+					log.Printf("Worker [%s]: Failed to dead-letter job %s, keeping it as failed: %v", workerName, job.ID, err)
+				} else {
+					log.Printf("Worker [%s]: Job %s moved to the dead letter queue", workerName, job.ID)
+					return
+				}
+			}
 		} else {
 			// Still have retries left, schedule for retry
 			job.Status = models.JobStatusRetry
-			retryDelay := time.Duration(s.appConfig.Worker.ErrorRetryDelaySeconds) * time.Second
-			if retryDelay <= 0 {
-				retryDelay = workerErrorRetryDelayDefault
-			}
-			// Set next execution time for retry - make it very short for tests to pick up quickly
-			// In production this would be a longer delay based on configuration
-			job.NextExecutionAt = sql.NullTime{Time: time.Now().UTC().Add(100 * time.Millisecond), Valid: true}
+			metrics.RecordJobTransition(job.JobType, job.Status)
+			// The job is saved with its retry time so no worker picks it up before then
+			retryDelay := jobRetryDelay(s.appConfig.Worker, job.Attempts-1, s.random())
+			job.NextExecutionAt = sql.NullTime{Time: s.now().UTC().Add(retryDelay), Valid: true}
 			log.Printf("Worker [%s]: Job %s will retry in %v (attempt %d/%d)", workerName, job.ID, retryDelay, job.Attempts, maxRetries)
 		}
 	} else {
@@ -234,6 +302,7 @@ func (s *campaignWorkerServiceImpl) processJob(ctx context.Context, job *models.
 			workerName, job.ID, job.CampaignID, batchDone, processedCount)
 		job.Status = models.JobStatusCompleted
 		job.LastError = sql.NullString{}
+		metrics.RecordJobTransition(job.JobType, job.Status)
 
 		if !batchDone {
 			nextJob := &models.CampaignJob{
@@ -304,6 +373,9 @@ func (s *campaignWorkerServiceImpl) processJob(ctx context.Context, job *models.
 						campaign, _, err := s.campaignOrchestratorSvc.GetCampaignDetails(jobCtx, job.CampaignID)
 						if err != nil {
 							log.Printf("Worker [%s]: Failed to get campaign %s details: %v", workerName, job.CampaignID, err)
+						} else if campaign.Status == models.CampaignStatusPaused || campaign.Status == models.CampaignStatusFailed || campaign.Status == models.CampaignStatusCancelled {
+							// The service stopped the campaign (e.g. the error-rate safety); leave it as it is
+							log.Printf("Worker [%s]: Campaign %s stopped with status %s, not marking as completed", workerName, job.CampaignID, campaign.Status)
 						} else if campaign.Status != models.CampaignStatusCompleted {
 							// Only try to set to completed if not already completed
 							log.Printf("Worker [%s]: Campaign %s current status is %s, marking as completed", workerName, job.CampaignID, campaign.Status)
@@ -331,3 +403,33 @@ func (s *campaignWorkerServiceImpl) processJob(ctx context.Context, job *models.
 			workerName, job.ID, job.Status, err)
 	}
 }
+
+// appendJobAttempt returns history, a JSON list of CampaignJobAttempt, with attempt added
+func appendJobAttempt(history *json.RawMessage, attempt models.CampaignJobAttempt) *json.RawMessage {
+	var attempts []models.CampaignJobAttempt
+	if history != nil && len(*history) > 0 {
+		if err := json.Unmarshal(*history, &attempts); err != nil {
+			log.Printf("Worker: Discarding unreadable attempt history: %v", err)
+			attempts = nil
+		}
+	}
+	attempts = append(attempts, attempt)
+	if len(attempts) > maxJobAttemptHistory {
+		attempts = attempts[len(attempts)-maxJobAttemptHistory:]
+	}
+	encoded, err := json.Marshal(attempts)
+	if err != nil {
+		return history
+	}
+	return models.JSONRawMessagePtr(encoded)
+}
+
+// jobRetryDelay returns the wait before a failed job's retry: the configured exponential backoff
+// for the retries already made, scaled by up to ±jobRetryJitter using random in [0, 1)
+func jobRetryDelay(cfg config.WorkerConfig, retry int, random float64) time.Duration {
+	if retry < 0 {
+		retry = 0
+	}
+	delay := cfg.JobRetryBackoff(retry)
+	return time.Duration(float64(delay) * (1 + jobRetryJitter*(2*random-1)))
+}
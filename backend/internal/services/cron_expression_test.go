@@ -0,0 +1,52 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronExpression_Next(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2026, 10, 14, 10, 17, 30, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 14, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)},
+		{"30 2 1 * *", time.Date(2026, 11, 1, 2, 30, 0, 0, time.UTC)},
+		{"0 8 * * mon-fri", time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1,15 * *", time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 10, 14, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches
+		{"0 0 20 * fri", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+		{"10-20/5 10 * * *", time.Date(2026, 10, 14, 10, 20, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expression, err := parseCronExpression(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, expression.next(from))
+		})
+	}
+}
+
+func TestParseCronExpression_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *",
+		"* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *", "1,,2 * * * *", "@every 5m"} {
+		_, err := parseCronExpression(expr)
+		assert.Error(t, err, expr)
+	}
+
+	expression, err := parseCronExpression("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, expression.next(time.Now()).IsZero(), "30 February never comes")
+}
@@ -5,13 +5,17 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/fntelecomllc/studio/backend/internal/circuitbreaker"
 	"github.com/fntelecomllc/studio/backend/internal/config"
 	"github.com/fntelecomllc/studio/backend/internal/dnsvalidator"
+	"github.com/fntelecomllc/studio/backend/internal/hostlimiter"
 	"github.com/fntelecomllc/studio/backend/internal/models"
 	"github.com/fntelecomllc/studio/backend/internal/store"
 	"github.com/fntelecomllc/studio/backend/internal/websocket"
@@ -26,6 +30,9 @@ type dnsCampaignServiceImpl struct {
 	auditLogStore    store.AuditLogStore
 	campaignJobStore store.CampaignJobStore
 	appConfig        *config.AppConfig
+	hostBreaker      *circuitbreaker.Breaker
+	hostLimiter      *hostlimiter.Limiter
+	errorRateSafety  *errorRateSafety
 }
 
 // NewDNSCampaignService creates a new DNSCampaignService.
@@ -37,6 +44,9 @@ func NewDNSCampaignService(db *sqlx.DB, cs store.CampaignStore, ps store.Persona
 		auditLogStore:    as,
 		campaignJobStore: cjs,
 		appConfig:        appCfg,
+		hostBreaker:      newHostBreaker(appCfg),
+		hostLimiter:      newHostRateLimiter(appCfg),
+		errorRateSafety:  newErrorRateSafety(appCfg),
 	}
 }
 
@@ -53,6 +63,12 @@ func (s *dnsCampaignServiceImpl) CreateCampaign(ctx context.Context, req CreateD
 	if err := s.validatePersonaIDs(ctx, validationQuerier, req.PersonaIDs, models.PersonaTypeDNS); err != nil {
 		return nil, fmt.Errorf("dns create: persona validation failed: %w", err)
 	}
+	if err := validateDNSConsensusParams(req.ConsensusMode, req.ConsensusQuorum, len(req.PersonaIDs)); err != nil {
+		return nil, fmt.Errorf("dns create: %w", err)
+	}
+	if err := ValidateDNSExpectedRecords(req.ExpectedRecords); err != nil {
+		return nil, fmt.Errorf("dns create: %w", err)
+	}
 
 	var opErr error
 	var querier store.Querier
@@ -103,16 +119,19 @@ func (s *dnsCampaignServiceImpl) CreateCampaign(ctx context.Context, req CreateD
 		userIDPtr = &req.UserID
 	}
 	baseCampaign := &models.Campaign{
-		ID:                 campaignID,
-		Name:               req.Name,
-		CampaignType:       models.CampaignTypeDNSValidation,
-		Status:             models.CampaignStatusPending,
-		UserID:             userIDPtr,
-		CreatedAt:          now,
-		UpdatedAt:          now,
-		TotalItems:         models.Int64Ptr(totalItems),
-		ProcessedItems:     models.Int64Ptr(0),
-		ProgressPercentage: models.Float64Ptr(0.0),
+		ID:                     campaignID,
+		Name:                   req.Name,
+		CampaignType:           models.CampaignTypeDNSValidation,
+		Status:                 models.CampaignStatusPending,
+		UserID:                 userIDPtr,
+		Tags:                   NormalizeCampaignTags(req.Tags),
+		AutoRetry:              req.AutoRetry,
+		PromotedFromCampaignID: req.PromotedFromCampaignID,
+		CreatedAt:              now,
+		UpdatedAt:              now,
+		TotalItems:             models.Int64Ptr(totalItems),
+		ProcessedItems:         models.Int64Ptr(0),
+		ProgressPercentage:     models.Float64Ptr(0.0),
 	}
 
 	dnsParams := &models.DNSValidationCampaignParams{
@@ -123,6 +142,10 @@ func (s *dnsCampaignServiceImpl) CreateCampaign(ctx context.Context, req CreateD
 		ProcessingSpeedPerMinute:   models.IntPtr(req.ProcessingSpeedPerMinute),
 		BatchSize:                  models.IntPtr(req.BatchSize),
 		RetryAttempts:              models.IntPtr(req.RetryAttempts),
+		DeduplicateDomains:         req.DeduplicateDomains,
+		ConsensusMode:              strings.ToLower(strings.TrimSpace(req.ConsensusMode)),
+		ConsensusQuorum:            req.ConsensusQuorum,
+		ExpectedRecords:            normalizeDNSExpectedRecords(req.ExpectedRecords),
 	}
 	if dnsParams.BatchSize == nil || *dnsParams.BatchSize == 0 {
 		dnsParams.BatchSize = models.IntPtr(50)
@@ -224,10 +247,10 @@ func (s *dnsCampaignServiceImpl) logAuditEvent(ctx context.Context, exec store.Q
 		"campaign_name": campaign.Name,
 		"description":   description,
 	}
-	detailsJSON, err := json.Marshal(detailsMap)
+	details, err := models.NewAuditDetails(detailsMap)
 	if err != nil {
-		log.Printf("Error marshalling audit log details for campaign %s, action %s: %v. Using raw description.", campaign.ID, action, err)
-		detailsJSON = json.RawMessage(fmt.Sprintf(`{"campaign_name": "%s", "description": "Details marshalling error: %s"}`, campaign.Name, description))
+		log.Printf("Error encoding audit details for campaign %s, action %s: %v", campaign.ID, action, err)
+		return
 	}
 
 	var auditLogUserID uuid.NullUUID
@@ -240,7 +263,7 @@ func (s *dnsCampaignServiceImpl) logAuditEvent(ctx context.Context, exec store.Q
 		Action:     action,
 		EntityType: sql.NullString{String: "Campaign", Valid: true},
 		EntityID:   uuid.NullUUID{UUID: campaign.ID, Valid: true},
-		Details:    models.JSONRawMessagePtr(detailsJSON),
+		Details:    details,
 	}
 	if err := s.auditLogStore.CreateAuditLog(ctx, exec, auditLog); err != nil {
 		log.Printf("Error creating audit log for campaign %s, action %s: %v", campaign.ID, action, err)
@@ -417,6 +440,60 @@ func (s *dnsCampaignServiceImpl) ProcessDNSValidationCampaignBatch(ctx context.C
 		return done, 0, opErr
 	}
 
+	// Domains that normalize to one this campaign already handled are recorded and skipped
+	skippedDuplicates := 0
+	if dnsParams.DeduplicateDomains {
+		candidates := make([]dedupCandidate, len(domainsToProcess))
+		for i, domain := range domainsToProcess {
+			candidates[i] = dedupCandidate{ItemID: domain.ID, DomainName: domain.DomainName}
+		}
+		keep, duplicates, errDedup := applyDomainDedup(ctx, s.campaignStore, querier, campaignID, candidates)
+		if errDedup != nil {
+			opErr = errDedup
+			return false, 0, opErr
+		}
+		uniqueDomains := make([]*models.GeneratedDomain, 0, len(domainsToProcess))
+		for i, domain := range domainsToProcess {
+			if keep[i] {
+				uniqueDomains = append(uniqueDomains, domain)
+			}
+		}
+		if duplicates > 0 {
+			log.Printf("ProcessDNSValidationCampaignBatch: Skipping %d duplicate domains for campaign %s.", duplicates, campaignID)
+		}
+		domainsToProcess = uniqueDomains
+		skippedDuplicates = duplicates
+	}
+
+	// Domains retried up to the attempt cap are marked and no longer validated
+	domainNames := make([]string, len(domainsToProcess))
+	for i, domain := range domainsToProcess {
+		domainNames[i] = domain.DomainName
+	}
+	keep, exceededAttempts, errCap := applyAttemptCap(ctx,
+		func(ctx context.Context, campaignID uuid.UUID, names []string) (map[string]int, error) {
+			return s.campaignStore.GetDNSValidationAttempts(ctx, querier, campaignID, names)
+		},
+		func(ctx context.Context, campaignID uuid.UUID, names []string) error {
+			return s.campaignStore.MarkDNSValidationMaxAttemptsExceeded(ctx, querier, campaignID, names)
+		},
+		campaignID, domainNames, s.appConfig.Worker.MaxAttemptsPerDomain)
+	if errCap != nil {
+		opErr = errCap
+		return false, 0, opErr
+	}
+	if exceededAttempts > 0 {
+		log.Printf("ProcessDNSValidationCampaignBatch: %d domains reached the attempt cap for campaign %s.", exceededAttempts, campaignID)
+		retryable := make([]*models.GeneratedDomain, 0, len(domainsToProcess)-exceededAttempts)
+		for i, domain := range domainsToProcess {
+			if keep[i] {
+				retryable = append(retryable, domain)
+			}
+		}
+		domainsToProcess = retryable
+		skippedDuplicates += exceededAttempts
+	}
+
 	personas := make([]*models.Persona, 0, len(dnsParams.PersonaIDs))
 	for _, pID := range dnsParams.PersonaIDs {
 		p, pErr := s.personaStore.GetPersonaByID(ctx, querier, pID)
@@ -443,6 +520,8 @@ func (s *dnsCampaignServiceImpl) ProcessDNSValidationCampaignBatch(ctx context.C
 	semaphore := make(chan struct{}, concurrencyLimit)
 	muResults := sync.Mutex{}
 	dbResults := make([]*models.DNSValidationResult, 0, len(domainsToProcess))
+	consensusPolicy := newDNSConsensusPolicy(dnsParams)
+	expectationPolicy := newDNSExpectationPolicy(dnsParams)
 	nowTime := time.Now().UTC()
 
 	// Store the original context error, if any, to check after the loop
@@ -467,6 +546,9 @@ func (s *dnsCampaignServiceImpl) ProcessDNSValidationCampaignBatch(ctx context.C
 
 			var finalValidationResult *dnsvalidator.ValidationResult
 			var successPersonaID uuid.NullUUID
+			var consensusAnswers []dnsResolverAnswer
+			var consensusRecord *json.RawMessage
+			var resolvingValidator *dnsvalidator.DNSValidator // First validator that resolved the domain
 			attemptCount := 0
 
 			for i, persona := range personas {
@@ -477,6 +559,22 @@ func (s *dnsCampaignServiceImpl) ProcessDNSValidationCampaignBatch(ctx context.C
 					goto StoreResultInGoRoutine
 				}
 
+				// Skip hosts whose circuit is open instead of waiting on another timeout
+				if !s.hostBreaker.Allow(domainModel.DomainName) {
+					finalValidationResult = &dnsvalidator.ValidationResult{
+						Domain: domainModel.DomainName,
+						Status: circuitbreaker.StatusCircuitOpen,
+						Error:  "Circuit open for host after repeated failures",
+					}
+					goto StoreResultInGoRoutine
+				}
+
+				// Space lookups for the same host and zone across all workers
+				if errWait := s.hostLimiter.Wait(batchCtx, domainModel.DomainName); errWait != nil {
+					finalValidationResult = &dnsvalidator.ValidationResult{Domain: domainModel.DomainName, Status: "Error", Error: fmt.Sprintf("Context cancelled while waiting for host rate limit: %v", errWait)}
+					goto StoreResultInGoRoutine
+				}
+
 				attemptCount++
 
 				var modelDNSDetails models.DNSConfigDetails
@@ -484,7 +582,15 @@ func (s *dnsCampaignServiceImpl) ProcessDNSValidationCampaignBatch(ctx context.C
 					log.Printf("Error unmarshalling DNS persona %s ConfigDetails for domain %s: %v. Using app defaults.", persona.ID, domainModel.DomainName, errUnmarshal)
 					validator := dnsvalidator.New(s.appConfig.DNSValidator)
 					valResult := validator.ValidateSingleDomain(domainModel.DomainName, batchCtx) // Use batchCtx
+					recordDNSCircuitOutcome(batchCtx, s.hostBreaker, domainModel.DomainName, valResult)
+					if valResult.Status == "Resolved" && resolvingValidator == nil {
+						resolvingValidator = validator
+					}
 					finalValidationResult = &valResult
+					if consensusPolicy != nil {
+						consensusAnswers = append(consensusAnswers, newDNSResolverAnswer(persona.ID, valResult))
+						continue
+					}
 					goto StoreResultInGoRoutine
 				}
 
@@ -492,8 +598,15 @@ func (s *dnsCampaignServiceImpl) ProcessDNSValidationCampaignBatch(ctx context.C
 				validatorConfig := config.ConvertJSONToDNSConfig(configDNSJSON)
 				validator := dnsvalidator.New(validatorConfig)
 				valResult := validator.ValidateSingleDomain(domainModel.DomainName, batchCtx) // Use batchCtx
+				recordDNSCircuitOutcome(batchCtx, s.hostBreaker, domainModel.DomainName, valResult)
+				if valResult.Status == "Resolved" && resolvingValidator == nil {
+					resolvingValidator = validator
+				}
 
-				if valResult.Status == "Resolved" {
+				// With consensus enabled every persona is asked and the answers are compared afterwards
+				if consensusPolicy != nil {
+					consensusAnswers = append(consensusAnswers, newDNSResolverAnswer(persona.ID, valResult))
+				} else if valResult.Status == "Resolved" {
 					finalValidationResult = &valResult
 					successPersonaID = uuid.NullUUID{UUID: persona.ID, Valid: true}
 					goto StoreResultInGoRoutine
@@ -524,6 +637,11 @@ func (s *dnsCampaignServiceImpl) ProcessDNSValidationCampaignBatch(ctx context.C
 					Status: "Error",
 					Error:  "Context cancelled after all attempts",
 				}
+			} else if consensusPolicy != nil && batchCtx.Err() == nil {
+				outcome := consensusPolicy.decide(domainModel.DomainName, consensusAnswers)
+				finalValidationResult = &outcome.Result
+				successPersonaID = outcome.PersonaID
+				consensusRecord = outcome.Record.encode()
 			}
 
 		StoreResultInGoRoutine:
@@ -542,10 +660,21 @@ func (s *dnsCampaignServiceImpl) ProcessDNSValidationCampaignBatch(ctx context.C
 				GeneratedDomainID:    uuid.NullUUID{UUID: domainModel.ID, Valid: true},
 				DomainName:           domainModel.DomainName,
 				ValidationStatus:     finalValidationResult.Status,
+				Consensus:            consensusRecord,
 				ValidatedByPersonaID: successPersonaID,
 				Attempts:             models.IntPtr(attemptCount),
 				LastCheckedAt:        &nowTime,
 			}
+			if expectationPolicy != nil && finalValidationResult.Status == "Resolved" {
+				cname := ""
+				if expectationPolicy.needsCNAME() && resolvingValidator != nil {
+					var errCNAME error
+					if cname, errCNAME = resolvingValidator.LookupCNAME(domainModel.DomainName, batchCtx); errCNAME != nil {
+						log.Printf("Failed to look up CNAME of %s for campaign %s expected records: %v", domainModel.DomainName, campaignID, errCNAME)
+					}
+				}
+				dbRes.Expectation = expectationPolicy.classify(domainModel.DomainName, finalValidationResult.IPs, cname)
+			}
 			if len(finalValidationResult.IPs) > 0 {
 				ipBytes, _ := json.Marshal(finalValidationResult.IPs)
 				dbRes.DNSRecords = models.JSONRawMessagePtr(json.RawMessage(ipBytes))
@@ -572,8 +701,15 @@ func (s *dnsCampaignServiceImpl) ProcessDNSValidationCampaignBatch(ctx context.C
 		}
 	}
 
+	// Results are saved outside the batch transaction so the store can commit them in chunks;
+	// the upsert makes re-processing any uncommitted remainder idempotent.
 	if len(dbResults) > 0 {
-		if errCreateResults := s.campaignStore.CreateDNSValidationResults(ctx, querier, dbResults); errCreateResults != nil {
+		if errCreateResults := s.campaignStore.CreateDNSValidationResults(ctx, nil, dbResults); errCreateResults != nil {
+			var chunkErr *store.ChunkedWriteError
+			if errors.As(errCreateResults, &chunkErr) {
+				log.Printf("ProcessDNSValidationCampaignBatch: committed %d/%d result chunks (%d rows) for campaign %s before failure",
+					chunkErr.CommittedChunks, chunkErr.TotalChunks, chunkErr.CommittedRows, campaignID)
+			}
 			currentErr := fmt.Errorf("failed to save DNS validation results for campaign %s: %w", campaignID, errCreateResults)
 			if opErr == nil {
 				opErr = currentErr
@@ -591,6 +727,11 @@ func (s *dnsCampaignServiceImpl) ProcessDNSValidationCampaignBatch(ctx context.C
 		}
 	}
 
+	// Collapsed duplicates and domains over the attempt cap count as processed so progress still reaches the source total
+	if opErr == nil {
+		processedInThisBatch += skippedDuplicates
+	}
+
 	// Only update ProcessedItems if opErr is not from a critical save failure of results
 	// or if it's a context cancellation (where some results might have been saved)
 	if opErr == nil || batchProcessingContextErr != nil {
@@ -599,6 +740,14 @@ func (s *dnsCampaignServiceImpl) ProcessDNSValidationCampaignBatch(ctx context.C
 		}
 		*campaign.ProcessedItems += int64(processedInThisBatch)
 	}
+	if opErr == nil && len(dbResults) > 0 {
+		statuses := make([]string, 0, len(dbResults))
+		for _, res := range dbResults {
+			statuses = append(statuses, res.ValidationStatus)
+		}
+		succeeded, failed := countItemOutcomes(statuses, isDNSResultFailure)
+		recordItemOutcomes(campaign, succeeded, failed)
+	}
 
 	if campaign.TotalItems == nil {
 		campaign.TotalItems = models.Int64Ptr(0)
@@ -636,6 +785,15 @@ func (s *dnsCampaignServiceImpl) ProcessDNSValidationCampaignBatch(ctx context.C
 		done = false
 	}
 
+	// Stop a campaign whose items are mostly failing rather than working through the rest
+	var safetyMessage string
+	if !done && opErr == nil {
+		if message, tripped := s.errorRateSafety.evaluate(campaign, nowTime); tripped {
+			safetyMessage = message
+			done = true
+		}
+	}
+
 	if errUpdateCamp := s.campaignStore.UpdateCampaign(ctx, querier, campaign); errUpdateCamp != nil {
 		currentErr := fmt.Errorf("failed to update campaign %s status/progress: %w", campaignID, errUpdateCamp)
 		if opErr == nil {
@@ -649,7 +807,10 @@ func (s *dnsCampaignServiceImpl) ProcessDNSValidationCampaignBatch(ctx context.C
 	}
 
 	// Broadcast DNS validation progress via WebSocket
-	if campaign.ProgressPercentage != nil && campaign.ProcessedItems != nil && campaign.TotalItems != nil {
+	if safetyMessage != "" {
+		s.logAuditEvent(ctx, querier, campaign, "Campaign Stopped By Error-Rate Safety", safetyMessage)
+		alertErrorRateSafety(campaign, "dns_validation", safetyMessage)
+	} else if campaign.ProgressPercentage != nil && campaign.ProcessedItems != nil && campaign.TotalItems != nil {
 		processedCount := *campaign.ProcessedItems
 		totalCount := *campaign.TotalItems
 		
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fntelecomllc/studio/backend/internal/dnsvalidator"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// DNS consensus modes. In first mode (the default) the first persona to resolve a domain decides it;
+// the other modes query every persona and compare their answers.
+const (
+	DNSConsensusModeFirst     = "first"
+	DNSConsensusModeMajority  = "majority"
+	DNSConsensusModeUnanimous = "unanimous"
+)
+
+// dnsConsensusStatusNone is the result status of a domain whose resolvers did not reach consensus
+const dnsConsensusStatusNone = "No Consensus"
+
+// dnsResolverAnswer is one persona's answer for a domain, as recorded in a result's consensus details
+type dnsResolverAnswer struct {
+	PersonaID uuid.UUID `json:"personaId"`
+	Resolver  string    `json:"resolver,omitempty"`
+	Status    string    `json:"status"`
+	IPs       []string  `json:"ips,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// votes reports whether the answer is a definite answer that counts towards consensus. Errors and
+// timeouts say nothing about the domain and are recorded without voting.
+func (a dnsResolverAnswer) votes() bool {
+	return a.Status == "Resolved" || a.Status == "Not Found"
+}
+
+// key identifies answers that agree: the same status and the same set of addresses
+func (a dnsResolverAnswer) key() string {
+	return a.Status + "|" + strings.Join(a.IPs, ",")
+}
+
+// dnsConsensusRecord is stored with each result of a consensus campaign
+type dnsConsensusRecord struct {
+	Mode          string              `json:"mode"`
+	Quorum        int                 `json:"quorum"`
+	Reached       bool                `json:"reached"`
+	Agreeing      int                 `json:"agreeing"`
+	Answering     int                 `json:"answering"`
+	Answers       []dnsResolverAnswer `json:"answers"`
+	Disagreements []dnsResolverAnswer `json:"disagreements,omitempty"`
+}
+
+// dnsConsensusOutcome is the decision for a domain after all personas were queried
+type dnsConsensusOutcome struct {
+	Result    dnsvalidator.ValidationResult
+	PersonaID uuid.NullUUID
+	Record    dnsConsensusRecord
+}
+
+// dnsConsensusPolicy decides a domain's DNS status from the answers of several resolvers
+type dnsConsensusPolicy struct {
+	mode   string
+	quorum int
+}
+
+// newDNSConsensusPolicy returns nil for campaigns that keep the first resolved answer. A quorum of
+// zero defaults to a majority of the campaign's personas.
+func newDNSConsensusPolicy(params *models.DNSValidationCampaignParams) *dnsConsensusPolicy {
+	if params == nil {
+		return nil
+	}
+	mode := strings.ToLower(strings.TrimSpace(params.ConsensusMode))
+	if mode != DNSConsensusModeMajority && mode != DNSConsensusModeUnanimous {
+		return nil
+	}
+	quorum := params.ConsensusQuorum
+	if quorum <= 0 {
+		quorum = len(params.PersonaIDs)/2 + 1
+	}
+	return &dnsConsensusPolicy{mode: mode, quorum: quorum}
+}
+
+// validateDNSConsensusParams checks a campaign's consensus settings against its personas
+func validateDNSConsensusParams(mode string, quorum, personaCount int) error {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", DNSConsensusModeFirst:
+		return nil
+	case DNSConsensusModeMajority, DNSConsensusModeUnanimous:
+	default:
+		return fmt.Errorf("unknown DNS consensus mode %q", mode)
+	}
+	if personaCount < 2 {
+		return fmt.Errorf("DNS consensus mode %q needs at least two personas", mode)
+	}
+	if quorum > personaCount {
+		return fmt.Errorf("DNS consensus quorum %d exceeds the %d personas of the campaign", quorum, personaCount)
+	}
+	return nil
+}
+
+// newDNSResolverAnswer records a persona's validation result with its addresses in a stable order
+func newDNSResolverAnswer(personaID uuid.UUID, result dnsvalidator.ValidationResult) dnsResolverAnswer {
+	ips := append([]string(nil), result.IPs...)
+	sort.Strings(ips)
+	return dnsResolverAnswer{
+		PersonaID: personaID,
+		Resolver:  result.Resolver,
+		Status:    result.Status,
+		IPs:       ips,
+		Error:     result.Error,
+	}
+}
+
+// decide groups the definite answers and accepts the largest group when it holds at least quorum
+// answers and, in majority mode, more than half of the definite answers or, in unanimous mode, all
+// of them. Answers outside the largest group are recorded as disagreements.
+func (p *dnsConsensusPolicy) decide(domain string, answers []dnsResolverAnswer) dnsConsensusOutcome {
+	record := dnsConsensusRecord{Mode: p.mode, Quorum: p.quorum, Answers: answers}
+
+	counts := make(map[string]int)
+	leader := -1
+	for i, answer := range answers {
+		if !answer.votes() {
+			continue
+		}
+		record.Answering++
+		counts[answer.key()]++
+		if leader < 0 || counts[answer.key()] > counts[answers[leader].key()] {
+			leader = i
+		}
+	}
+
+	if leader < 0 {
+		// No resolver gave a definite answer; report the last failure as a single-resolver lookup would
+		result := dnsvalidator.ValidationResult{Domain: domain, Status: "Error", Error: "No resolver answered"}
+		if len(answers) > 0 {
+			last := answers[len(answers)-1]
+			result = dnsvalidator.ValidationResult{Domain: domain, Status: last.Status, Resolver: last.Resolver, Error: last.Error}
+		}
+		return dnsConsensusOutcome{Result: result, Record: record}
+	}
+
+	leading := answers[leader]
+	record.Agreeing = counts[leading.key()]
+	for _, answer := range answers {
+		if answer.votes() && answer.key() != leading.key() {
+			record.Disagreements = append(record.Disagreements, answer)
+		}
+	}
+
+	record.Reached = record.Agreeing >= p.quorum
+	switch p.mode {
+	case DNSConsensusModeMajority:
+		record.Reached = record.Reached && record.Agreeing*2 > record.Answering
+	case DNSConsensusModeUnanimous:
+		record.Reached = record.Reached && record.Agreeing == record.Answering
+	}
+
+	outcome := dnsConsensusOutcome{Record: record}
+	if !record.Reached {
+		outcome.Result = dnsvalidator.ValidationResult{
+			Domain: domain,
+			Status: dnsConsensusStatusNone,
+			Error: fmt.Sprintf("%d of %d answering resolvers agreed (%s mode, quorum %d)",
+				record.Agreeing, record.Answering, p.mode, p.quorum),
+		}
+		return outcome
+	}
+	outcome.Result = dnsvalidator.ValidationResult{
+		Domain:   domain,
+		Status:   leading.Status,
+		IPs:      leading.IPs,
+		Resolver: leading.Resolver,
+	}
+	if leading.Status == "Resolved" {
+		outcome.PersonaID = uuid.NullUUID{UUID: leading.PersonaID, Valid: true}
+	}
+	return outcome
+}
+
+// encode returns the record as stored in a DNS validation result
+func (r dnsConsensusRecord) encode() *json.RawMessage {
+	encoded, err := json.Marshal(r)
+	if err != nil {
+		return nil
+	}
+	return models.JSONRawMessagePtr(encoded)
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/dnsvalidator"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func consensusParams(mode string, quorum, personas int) *models.DNSValidationCampaignParams {
+	params := &models.DNSValidationCampaignParams{ConsensusMode: mode, ConsensusQuorum: quorum}
+	for i := 0; i < personas; i++ {
+		params.PersonaIDs = append(params.PersonaIDs, uuid.New())
+	}
+	return params
+}
+
+func resolverAnswer(resolver, status string, ips ...string) dnsResolverAnswer {
+	return newDNSResolverAnswer(uuid.New(), dnsvalidator.ValidationResult{Status: status, Resolver: resolver, IPs: ips})
+}
+
+func TestDNSConsensus_MajorityAgrees(t *testing.T) {
+	policy := newDNSConsensusPolicy(consensusParams("majority", 0, 3))
+	require.NotNil(t, policy)
+	assert.Equal(t, 2, policy.quorum, "the default quorum is a majority of the personas")
+
+	first := resolverAnswer("1.1.1.1:53", "Resolved", "93.184.216.34", "93.184.216.35")
+	second := resolverAnswer("8.8.8.8:53", "Resolved", "93.184.216.35", "93.184.216.34")
+	hijacked := resolverAnswer("10.0.0.53:53", "Resolved", "10.10.10.10")
+	outcome := policy.decide("example.com", []dnsResolverAnswer{first, hijacked, second})
+
+	assert.Equal(t, "Resolved", outcome.Result.Status)
+	assert.Equal(t, []string{"93.184.216.34", "93.184.216.35"}, outcome.Result.IPs, "answers agree regardless of address order")
+	assert.Equal(t, uuid.NullUUID{UUID: first.PersonaID, Valid: true}, outcome.PersonaID)
+	assert.True(t, outcome.Record.Reached)
+	assert.Equal(t, 2, outcome.Record.Agreeing)
+	assert.Equal(t, 3, outcome.Record.Answering)
+	require.Len(t, outcome.Record.Disagreements, 1)
+	assert.Equal(t, hijacked.PersonaID, outcome.Record.Disagreements[0].PersonaID)
+	assert.Equal(t, []string{"10.10.10.10"}, outcome.Record.Disagreements[0].IPs)
+}
+
+func TestDNSConsensus_DisagreementWithoutMajority(t *testing.T) {
+	policy := newDNSConsensusPolicy(consensusParams("majority", 2, 3))
+	answers := []dnsResolverAnswer{
+		resolverAnswer("1.1.1.1:53", "Resolved", "93.184.216.34"),
+		resolverAnswer("8.8.8.8:53", "Not Found"),
+		resolverAnswer("9.9.9.9:53", "Resolved", "10.10.10.10"),
+	}
+	outcome := policy.decide("example.com", answers)
+
+	assert.Equal(t, dnsConsensusStatusNone, outcome.Result.Status)
+	assert.Empty(t, outcome.Result.IPs)
+	assert.False(t, outcome.PersonaID.Valid, "no persona validated a disputed domain")
+	assert.Contains(t, outcome.Result.Error, "1 of 3 answering resolvers agreed")
+	assert.False(t, outcome.Record.Reached)
+	assert.Len(t, outcome.Record.Disagreements, 2)
+	assert.Len(t, outcome.Record.Answers, 3, "every resolver's answer is recorded")
+}
+
+func TestDNSConsensus_UnanimousAndQuorum(t *testing.T) {
+	unanimous := newDNSConsensusPolicy(consensusParams("unanimous", 0, 3))
+	outcome := unanimous.decide("example.com", []dnsResolverAnswer{
+		resolverAnswer("1.1.1.1:53", "Resolved", "93.184.216.34"),
+		resolverAnswer("8.8.8.8:53", "Resolved", "93.184.216.34"),
+		resolverAnswer("9.9.9.9:53", "Resolved", "93.184.216.99"),
+	})
+	assert.Equal(t, dnsConsensusStatusNone, outcome.Result.Status, "a single dissenter blocks unanimous consensus")
+
+	// Failed lookups are recorded but do not vote; the remaining answers must still reach the quorum
+	timeout := dnsResolverAnswer{PersonaID: uuid.New(), Status: "Timeout", Error: "i/o timeout"}
+	outcome = unanimous.decide("example.com", []dnsResolverAnswer{
+		resolverAnswer("1.1.1.1:53", "Not Found"),
+		timeout,
+		resolverAnswer("8.8.8.8:53", "Not Found"),
+	})
+	assert.Equal(t, "Not Found", outcome.Result.Status)
+	assert.True(t, outcome.Record.Reached)
+	assert.Equal(t, 2, outcome.Record.Answering)
+	assert.Empty(t, outcome.Record.Disagreements)
+
+	strict := newDNSConsensusPolicy(consensusParams("majority", 3, 3))
+	outcome = strict.decide("example.com", []dnsResolverAnswer{
+		resolverAnswer("1.1.1.1:53", "Resolved", "93.184.216.34"),
+		timeout,
+		resolverAnswer("8.8.8.8:53", "Resolved", "93.184.216.34"),
+	})
+	assert.Equal(t, dnsConsensusStatusNone, outcome.Result.Status, "two agreeing answers do not meet a quorum of three")
+
+	outcome = strict.decide("example.com", []dnsResolverAnswer{timeout})
+	assert.Equal(t, "Timeout", outcome.Result.Status, "with no definite answer the lookup failure is reported")
+}
+
+func TestDNSConsensus_RecordEncoding(t *testing.T) {
+	policy := newDNSConsensusPolicy(consensusParams("majority", 0, 2))
+	outcome := policy.decide("example.com", []dnsResolverAnswer{
+		resolverAnswer("1.1.1.1:53", "Resolved", "93.184.216.34"),
+		resolverAnswer("8.8.8.8:53", "Resolved", "93.184.216.34"),
+	})
+	encoded := outcome.Record.encode()
+	require.NotNil(t, encoded)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(*encoded, &decoded))
+	assert.Equal(t, "majority", decoded["mode"])
+	assert.Equal(t, true, decoded["reached"])
+	assert.Len(t, decoded["answers"], 2)
+	assert.NotContains(t, decoded, "disagreements")
+}
+
+func TestDNSConsensus_Settings(t *testing.T) {
+	assert.Nil(t, newDNSConsensusPolicy(consensusParams("", 0, 3)), "first-answer mode is the default")
+	assert.Nil(t, newDNSConsensusPolicy(consensusParams("first", 2, 3)))
+	assert.NotNil(t, newDNSConsensusPolicy(consensusParams("Majority", 0, 3)))
+
+	assert.NoError(t, validateDNSConsensusParams("", 0, 1))
+	assert.NoError(t, validateDNSConsensusParams("unanimous", 3, 3))
+	assert.Error(t, validateDNSConsensusParams("majority", 0, 1), "consensus needs more than one resolver")
+	assert.Error(t, validateDNSConsensusParams("majority", 4, 3))
+	assert.Error(t, validateDNSConsensusParams("plurality", 0, 3))
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+)
+
+// maxDNSExpectedRecordRules bounds the CIDRs or suffixes of each expected record type
+const maxDNSExpectedRecordRules = 100
+
+// ValidateDNSExpectedRecords checks that every A CIDR is an IPv4 network, every AAAA CIDR an IPv6
+// network and every CNAME suffix a domain name. Nil rules are valid.
+func ValidateDNSExpectedRecords(rules *models.DNSExpectedRecords) error {
+	if rules == nil {
+		return nil
+	}
+	for _, list := range []struct {
+		name  string
+		rules []string
+	}{{"aCidrs", rules.ACIDRs}, {"aaaaCidrs", rules.AAAACIDRs}, {"cnameSuffixes", rules.CNAMESuffixes}} {
+		if len(list.rules) > maxDNSExpectedRecordRules {
+			return fmt.Errorf("expectedRecords.%s has %d entries, more than the %d allowed", list.name, len(list.rules), maxDNSExpectedRecordRules)
+		}
+	}
+	for _, cidr := range rules.ACIDRs {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil || network.IP.To4() == nil {
+			return fmt.Errorf("expectedRecords.aCidrs: %q is not an IPv4 CIDR", cidr)
+		}
+	}
+	for _, cidr := range rules.AAAACIDRs {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil || network.IP.To4() != nil {
+			return fmt.Errorf("expectedRecords.aaaaCidrs: %q is not an IPv6 CIDR", cidr)
+		}
+	}
+	for _, suffix := range rules.CNAMESuffixes {
+		if !isDNSNameSuffix(normalizeDNSNameSuffix(suffix)) {
+			return fmt.Errorf("expectedRecords.cnameSuffixes: %q is not a domain name suffix", suffix)
+		}
+	}
+	return nil
+}
+
+// normalizeDNSExpectedRecords trims the rules and drops them when none are given
+func normalizeDNSExpectedRecords(rules *models.DNSExpectedRecords) *models.DNSExpectedRecords {
+	if rules == nil || len(rules.ACIDRs)+len(rules.AAAACIDRs)+len(rules.CNAMESuffixes) == 0 {
+		return nil
+	}
+	normalized := &models.DNSExpectedRecords{}
+	for _, cidr := range rules.ACIDRs {
+		normalized.ACIDRs = append(normalized.ACIDRs, strings.TrimSpace(cidr))
+	}
+	for _, cidr := range rules.AAAACIDRs {
+		normalized.AAAACIDRs = append(normalized.AAAACIDRs, strings.TrimSpace(cidr))
+	}
+	for _, suffix := range rules.CNAMESuffixes {
+		normalized.CNAMESuffixes = append(normalized.CNAMESuffixes, normalizeDNSNameSuffix(suffix))
+	}
+	return normalized
+}
+
+// normalizeDNSNameSuffix lower-cases a suffix and strips its leading and trailing dots
+func normalizeDNSNameSuffix(suffix string) string {
+	return strings.Trim(strings.ToLower(strings.TrimSpace(suffix)), ".")
+}
+
+// isDNSNameSuffix reports whether s is one or more valid DNS labels
+func isDNSNameSuffix(s string) bool {
+	if s == "" || len(s) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// dnsExpectationPolicy classifies resolved domains against a campaign's expected records
+type dnsExpectationPolicy struct {
+	aNets         []*net.IPNet
+	aaaaNets      []*net.IPNet
+	cnameSuffixes []string
+}
+
+// newDNSExpectationPolicy returns nil for campaigns without expected records. Rules that do not
+// parse are skipped; they were rejected when the campaign was created.
+func newDNSExpectationPolicy(params *models.DNSValidationCampaignParams) *dnsExpectationPolicy {
+	if params == nil {
+		return nil
+	}
+	rules := normalizeDNSExpectedRecords(params.ExpectedRecords)
+	if rules == nil {
+		return nil
+	}
+	policy := &dnsExpectationPolicy{cnameSuffixes: rules.CNAMESuffixes}
+	for _, cidr := range rules.ACIDRs {
+		if _, network, err := net.ParseCIDR(cidr); err == nil {
+			policy.aNets = append(policy.aNets, network)
+		}
+	}
+	for _, cidr := range rules.AAAACIDRs {
+		if _, network, err := net.ParseCIDR(cidr); err == nil {
+			policy.aaaaNets = append(policy.aaaaNets, network)
+		}
+	}
+	return policy
+}
+
+// needsCNAME reports whether classifying a domain needs its CNAME target
+func (p *dnsExpectationPolicy) needsCNAME() bool {
+	return len(p.cnameSuffixes) > 0
+}
+
+// classify returns models.DNSExpectationMatched when a resolved domain's addresses and CNAME
+// target satisfy every rule, and models.DNSExpectationUnexpected otherwise. A family with CIDR
+// rules must have at least one address, and all of its addresses must lie in the rules' networks.
+// cname is the end of the domain's CNAME chain, which is the domain itself when it has none.
+func (p *dnsExpectationPolicy) classify(domain string, ips []string, cname string) string {
+	var v4, v6 []net.IP
+	for _, raw := range ips {
+		ip := net.ParseIP(raw)
+		switch {
+		case ip == nil:
+			return models.DNSExpectationUnexpected
+		case ip.To4() != nil:
+			v4 = append(v4, ip)
+		default:
+			v6 = append(v6, ip)
+		}
+	}
+	if !allInNetworks(v4, p.aNets) || !allInNetworks(v6, p.aaaaNets) {
+		return models.DNSExpectationUnexpected
+	}
+	if p.needsCNAME() {
+		target := normalizeDNSNameSuffix(cname)
+		if target == "" || target == normalizeDNSNameSuffix(domain) || !hasDNSNameSuffix(target, p.cnameSuffixes) {
+			return models.DNSExpectationUnexpected
+		}
+	}
+	return models.DNSExpectationMatched
+}
+
+// allInNetworks reports whether every IP lies in one of the networks. With no networks there is no
+// rule to check; otherwise at least one IP is required.
+func allInNetworks(ips []net.IP, networks []*net.IPNet) bool {
+	if len(networks) == 0 {
+		return true
+	}
+	if len(ips) == 0 {
+		return false
+	}
+	for _, ip := range ips {
+		inAny := false
+		for _, network := range networks {
+			if network.Contains(ip) {
+				inAny = true
+				break
+			}
+		}
+		if !inAny {
+			return false
+		}
+	}
+	return true
+}
+
+// hasDNSNameSuffix matches whole labels, so example.com matches cdn.example.com but not badexample.com
+func hasDNSNameSuffix(name string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if name == suffix || strings.HasSuffix(name, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
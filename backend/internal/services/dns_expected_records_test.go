@@ -0,0 +1,92 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func expectationPolicy(t *testing.T, rules models.DNSExpectedRecords) *dnsExpectationPolicy {
+	t.Helper()
+	require.NoError(t, ValidateDNSExpectedRecords(&rules))
+	policy := newDNSExpectationPolicy(&models.DNSValidationCampaignParams{ExpectedRecords: &rules})
+	require.NotNil(t, policy)
+	return policy
+}
+
+func TestDNSExpectation_CIDRRules(t *testing.T) {
+	policy := expectationPolicy(t, models.DNSExpectedRecords{
+		ACIDRs:    []string{"203.0.113.0/24", " 198.51.100.16/28 "},
+		AAAACIDRs: []string{"2001:db8::/32"},
+	})
+	assert.False(t, policy.needsCNAME())
+
+	tests := []struct {
+		name string
+		ips  []string
+		want string
+	}{
+		{"all addresses in range", []string{"203.0.113.10", "198.51.100.20", "2001:db8::1"}, models.DNSExpectationMatched},
+		{"an A record outside the ranges", []string{"203.0.113.10", "192.0.2.1", "2001:db8::1"}, models.DNSExpectationUnexpected},
+		{"an AAAA record outside the range", []string{"203.0.113.10", "2001:db9::1"}, models.DNSExpectationUnexpected},
+		{"no AAAA record although one is expected", []string{"203.0.113.10"}, models.DNSExpectationUnexpected},
+		{"no A record although one is expected", []string{"2001:db8::1"}, models.DNSExpectationUnexpected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.classify("example.com", tt.ips, ""))
+		})
+	}
+
+	// Without AAAA rules IPv6 addresses are not checked
+	aOnly := expectationPolicy(t, models.DNSExpectedRecords{ACIDRs: []string{"203.0.113.0/24"}})
+	assert.Equal(t, models.DNSExpectationMatched, aOnly.classify("example.com", []string{"203.0.113.10", "2001:db9::1"}, ""))
+}
+
+func TestDNSExpectation_CNAMESuffixRules(t *testing.T) {
+	policy := expectationPolicy(t, models.DNSExpectedRecords{CNAMESuffixes: []string{".CDN.Example.net.", "edge.example.org"}})
+	assert.True(t, policy.needsCNAME())
+
+	assert.Equal(t, models.DNSExpectationMatched, policy.classify("www.example.com", []string{"192.0.2.1"}, "pop1.cdn.example.net"))
+	assert.Equal(t, models.DNSExpectationMatched, policy.classify("www.example.com", []string{"192.0.2.1"}, "edge.example.org."))
+	assert.Equal(t, models.DNSExpectationUnexpected, policy.classify("www.example.com", []string{"192.0.2.1"}, "pop1.badcdn.example.net"),
+		"suffixes match whole labels")
+	assert.Equal(t, models.DNSExpectationUnexpected, policy.classify("www.example.com", []string{"192.0.2.1"}, "www.example.com"),
+		"a domain without a CNAME does not match")
+	assert.Equal(t, models.DNSExpectationUnexpected, policy.classify("www.example.com", []string{"192.0.2.1"}, ""),
+		"a CNAME that could not be looked up does not match")
+
+	both := expectationPolicy(t, models.DNSExpectedRecords{ACIDRs: []string{"192.0.2.0/24"}, CNAMESuffixes: []string{"cdn.example.net"}})
+	assert.Equal(t, models.DNSExpectationMatched, both.classify("www.example.com", []string{"192.0.2.1"}, "a.cdn.example.net"))
+	assert.Equal(t, models.DNSExpectationUnexpected, both.classify("www.example.com", []string{"198.51.100.1"}, "a.cdn.example.net"))
+}
+
+func TestValidateDNSExpectedRecords(t *testing.T) {
+	assert.NoError(t, ValidateDNSExpectedRecords(nil))
+	assert.NoError(t, ValidateDNSExpectedRecords(&models.DNSExpectedRecords{}))
+	assert.Nil(t, newDNSExpectationPolicy(&models.DNSValidationCampaignParams{ExpectedRecords: &models.DNSExpectedRecords{}}),
+		"empty rules classify nothing")
+
+	invalid := map[string]models.DNSExpectedRecords{
+		"aCidrs":        {ACIDRs: []string{"203.0.113.0"}},
+		"IPv4 CIDR":     {ACIDRs: []string{"2001:db8::/32"}},
+		"IPv6 CIDR":     {AAAACIDRs: []string{"203.0.113.0/24"}},
+		"aaaaCidrs":     {AAAACIDRs: []string{"2001:db8::/129"}},
+		"cnameSuffixes": {CNAMESuffixes: []string{"..."}},
+		"domain name":   {CNAMESuffixes: []string{"cdn example.net"}},
+	}
+	for want, rules := range invalid {
+		err := ValidateDNSExpectedRecords(&rules)
+		if assert.Error(t, err, "%+v", rules) {
+			assert.Contains(t, err.Error(), want)
+		}
+	}
+
+	tooMany := models.DNSExpectedRecords{}
+	for i := 0; i <= maxDNSExpectedRecordRules; i++ {
+		tooMany.CNAMESuffixes = append(tooMany.CNAMESuffixes, "example.com")
+	}
+	assert.Error(t, ValidateDNSExpectedRecords(&tooMany))
+}
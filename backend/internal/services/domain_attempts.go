@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// attemptLookup returns the recorded attempt count of each named domain that already has a result
+type attemptLookup func(ctx context.Context, campaignID uuid.UUID, domainNames []string) (map[string]int, error)
+
+// attemptMarker marks the named domains' results as having reached the attempt cap
+type attemptMarker func(ctx context.Context, campaignID uuid.UUID, domainNames []string) error
+
+// planAttemptCap reports which domains may be validated again. A domain whose recorded attempts
+// have reached maxAttempts is excluded; a maxAttempts of zero or less disables the cap.
+func planAttemptCap(domainNames []string, attempts map[string]int, maxAttempts int) ([]bool, []string) {
+	keep := make([]bool, len(domainNames))
+	var exceeded []string
+	for i, name := range domainNames {
+		if maxAttempts > 0 && attempts[name] >= maxAttempts {
+			exceeded = append(exceeded, name)
+			continue
+		}
+		keep[i] = true
+	}
+	return keep, exceeded
+}
+
+// applyAttemptCap excludes domains that reached the attempt cap from a batch and marks their results
+// max_attempts_exceeded so later batches no longer fetch them. It returns one flag per domain and the
+// number of domains excluded.
+func applyAttemptCap(ctx context.Context, lookup attemptLookup, mark attemptMarker, campaignID uuid.UUID, domainNames []string, maxAttempts int) ([]bool, int, error) {
+	if maxAttempts <= 0 {
+		keep, _ := planAttemptCap(domainNames, nil, maxAttempts)
+		return keep, 0, nil
+	}
+	attempts, err := lookup(ctx, campaignID, domainNames)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load attempt counts for campaign %s: %w", campaignID, err)
+	}
+	keep, exceeded := planAttemptCap(domainNames, attempts, maxAttempts)
+	if len(exceeded) > 0 {
+		if err := mark(ctx, campaignID, exceeded); err != nil {
+			return nil, 0, fmt.Errorf("failed to mark domains over the attempt cap for campaign %s: %w", campaignID, err)
+		}
+	}
+	return keep, len(exceeded), nil
+}
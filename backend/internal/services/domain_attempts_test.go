@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// attemptResultStore keeps DNS result attempts and statuses in memory
+type attemptResultStore struct {
+	store.CampaignStore
+	attempts map[string]int
+	statuses map[string]string
+}
+
+func (m *attemptResultStore) GetDNSValidationAttempts(ctx context.Context, exec store.Querier, campaignID uuid.UUID, domainNames []string) (map[string]int, error) {
+	found := make(map[string]int)
+	for _, name := range domainNames {
+		if n, ok := m.attempts[name]; ok {
+			found[name] = n
+		}
+	}
+	return found, nil
+}
+
+func (m *attemptResultStore) MarkDNSValidationMaxAttemptsExceeded(ctx context.Context, exec store.Querier, campaignID uuid.UUID, domainNames []string) error {
+	for _, name := range domainNames {
+		m.statuses[name] = string(models.ValidationStatusMaxAttemptsExceeded)
+	}
+	return nil
+}
+
+// pendingDomains mirrors GetDomainsForDNSValidation: domains already over the cap are not fetched again
+func (m *attemptResultStore) pendingDomains(names []string) []string {
+	var pending []string
+	for _, name := range names {
+		if m.statuses[name] != string(models.ValidationStatusMaxAttemptsExceeded) {
+			pending = append(pending, name)
+		}
+	}
+	return pending
+}
+
+func (m *attemptResultStore) applyCap(ctx context.Context, campaignID uuid.UUID, names []string, maxAttempts int) ([]bool, int, error) {
+	return applyAttemptCap(ctx,
+		func(ctx context.Context, campaignID uuid.UUID, names []string) (map[string]int, error) {
+			return m.GetDNSValidationAttempts(ctx, nil, campaignID, names)
+		},
+		func(ctx context.Context, campaignID uuid.UUID, names []string) error {
+			return m.MarkDNSValidationMaxAttemptsExceeded(ctx, nil, campaignID, names)
+		},
+		campaignID, names, maxAttempts)
+}
+
+func TestApplyAttemptCap_ExcludesDomainsAtTheCap(t *testing.T) {
+	ctx := context.Background()
+	resultStore := &attemptResultStore{
+		attempts: map[string]int{"retried.com": 3, "flaky.com": 2},
+		statuses: map[string]string{"retried.com": "invalid_dns", "flaky.com": "invalid_dns"},
+	}
+	campaignID := uuid.New()
+	batch := []string{"retried.com", "flaky.com", "new.com"}
+
+	keep, exceeded, err := resultStore.applyCap(ctx, campaignID, batch, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{false, true, true}, keep)
+	assert.Equal(t, 1, exceeded)
+	assert.Equal(t, string(models.ValidationStatusMaxAttemptsExceeded), resultStore.statuses["retried.com"])
+	assert.Equal(t, "invalid_dns", resultStore.statuses["flaky.com"])
+
+	// The capped domain is no longer offered for validation on later runs
+	assert.Equal(t, []string{"flaky.com", "new.com"}, resultStore.pendingDomains(batch))
+
+	// Once the next failed attempt is recorded the other domain reaches the cap too
+	resultStore.attempts["flaky.com"]++
+	keep, exceeded, err = resultStore.applyCap(ctx, campaignID, resultStore.pendingDomains(batch), 3)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{false, true}, keep)
+	assert.Equal(t, 1, exceeded)
+	assert.Equal(t, []string{"new.com"}, resultStore.pendingDomains(batch))
+}
+
+func TestApplyAttemptCap_DisabledKeepsEveryDomain(t *testing.T) {
+	lookup := func(ctx context.Context, campaignID uuid.UUID, names []string) (map[string]int, error) {
+		return nil, errors.New("attempts should not be loaded when the cap is disabled")
+	}
+	keep, exceeded, err := applyAttemptCap(context.Background(), lookup, nil, uuid.New(), []string{"a.com", "b.com"}, -1)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, true}, keep)
+	assert.Zero(t, exceeded)
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+)
+
+// NormalizeDomainName returns the key used to detect duplicate domains across a campaign chain:
+// surrounding whitespace and a trailing root dot are removed and the name is lower-cased.
+func NormalizeDomainName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
+
+// dedupCandidate is one upstream item offered to a campaign stage for processing
+type dedupCandidate struct {
+	ItemID     uuid.UUID
+	DomainName string
+}
+
+// planDomainDedup decides which candidates a stage processes. The first item seen for a normalized
+// domain is kept (an item kept in an earlier batch stays kept when it is retried); later items for the
+// same domain are duplicates. It returns one flag per candidate and the decisions to record.
+func planDomainDedup(campaignID uuid.UUID, candidates []dedupCandidate, previouslyKept map[string]uuid.UUID, now time.Time) ([]bool, []*models.DomainDedupDecision) {
+	keep := make([]bool, len(candidates))
+	decisions := make([]*models.DomainDedupDecision, 0, len(candidates))
+	kept := make(map[string]uuid.UUID, len(previouslyKept)+len(candidates))
+	for name, itemID := range previouslyKept {
+		kept[name] = itemID
+	}
+
+	for i, candidate := range candidates {
+		normalized := NormalizeDomainName(candidate.DomainName)
+		keptItemID, seen := kept[normalized]
+		if seen && keptItemID == candidate.ItemID {
+			keep[i] = true
+			continue
+		}
+
+		decision := &models.DomainDedupDecision{
+			ID:               uuid.New(),
+			CampaignID:       campaignID,
+			NormalizedDomain: normalized,
+			DomainName:       candidate.DomainName,
+			SourceItemID:     candidate.ItemID,
+			DecidedAt:        now,
+		}
+		if seen {
+			decision.KeptItemID = keptItemID
+			decision.Decision = models.DomainDedupDecisionDuplicate
+		} else {
+			kept[normalized] = candidate.ItemID
+			keep[i] = true
+			decision.KeptItemID = candidate.ItemID
+			decision.Decision = models.DomainDedupDecisionKept
+		}
+		decisions = append(decisions, decision)
+	}
+	return keep, decisions
+}
+
+// applyDomainDedup records dedup decisions for a batch of candidates and reports which of them the
+// stage should process, along with how many were collapsed into an already kept domain.
+func applyDomainDedup(ctx context.Context, campaignStore store.CampaignStore, exec store.Querier, campaignID uuid.UUID, candidates []dedupCandidate) ([]bool, int, error) {
+	names := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		names = append(names, NormalizeDomainName(candidate.DomainName))
+	}
+	previouslyKept, err := campaignStore.GetKeptDedupItems(ctx, exec, campaignID, names)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load dedup decisions for campaign %s: %w", campaignID, err)
+	}
+
+	keep, decisions := planDomainDedup(campaignID, candidates, previouslyKept, time.Now().UTC())
+	if err := campaignStore.CreateDomainDedupDecisions(ctx, exec, decisions); err != nil {
+		return nil, 0, fmt.Errorf("failed to record dedup decisions for campaign %s: %w", campaignID, err)
+	}
+
+	duplicates := 0
+	for _, decision := range decisions {
+		if decision.Decision == models.DomainDedupDecisionDuplicate {
+			duplicates++
+		}
+	}
+	return keep, duplicates, nil
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dedupDecisionStore keeps dedup decisions in memory with the same conflict rules as the Postgres store
+type dedupDecisionStore struct {
+	store.CampaignStore
+	mu        sync.Mutex
+	decisions []*models.DomainDedupDecision
+}
+
+func (m *dedupDecisionStore) CreateDomainDedupDecisions(ctx context.Context, exec store.Querier, decisions []*models.DomainDedupDecision) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+next:
+	for _, decision := range decisions {
+		for _, existing := range m.decisions {
+			if existing.CampaignID != decision.CampaignID {
+				continue
+			}
+			if existing.SourceItemID == decision.SourceItemID {
+				continue next
+			}
+			if decision.Decision == models.DomainDedupDecisionKept && existing.Decision == models.DomainDedupDecisionKept &&
+				existing.NormalizedDomain == decision.NormalizedDomain {
+				continue next
+			}
+		}
+		d := *decision
+		m.decisions = append(m.decisions, &d)
+	}
+	return nil
+}
+
+func (m *dedupDecisionStore) GetKeptDedupItems(ctx context.Context, exec store.Querier, campaignID uuid.UUID, normalizedDomains []string) (map[string]uuid.UUID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	wanted := make(map[string]bool, len(normalizedDomains))
+	for _, name := range normalizedDomains {
+		wanted[name] = true
+	}
+	kept := make(map[string]uuid.UUID)
+	for _, d := range m.decisions {
+		if d.CampaignID == campaignID && d.Decision == models.DomainDedupDecisionKept && wanted[d.NormalizedDomain] {
+			kept[d.NormalizedDomain] = d.KeptItemID
+		}
+	}
+	return kept, nil
+}
+
+func keptDomains(candidates []dedupCandidate, keep []bool) []string {
+	var names []string
+	for i, candidate := range candidates {
+		if keep[i] {
+			names = append(names, candidate.DomainName)
+		}
+	}
+	return names
+}
+
+func TestNormalizeDomainName(t *testing.T) {
+	assert.Equal(t, "example.com", NormalizeDomainName("Example.COM"))
+	assert.Equal(t, "example.com", NormalizeDomainName(" example.com. "))
+	assert.Equal(t, "shop.example.com", NormalizeDomainName("shop.example.com"))
+}
+
+func TestApplyDomainDedup_CollapsesUpstreamDuplicates(t *testing.T) {
+	ctx := context.Background()
+	decisionStore := &dedupDecisionStore{}
+	campaignID := uuid.New()
+
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	batch := []dedupCandidate{
+		{ItemID: first, DomainName: "Example.com"},
+		{ItemID: second, DomainName: "shop.io"},
+		{ItemID: third, DomainName: "example.com."},
+	}
+	keep, duplicates, err := applyDomainDedup(ctx, decisionStore, nil, campaignID, batch)
+	require.NoError(t, err)
+	assert.Equal(t, 1, duplicates)
+	assert.Equal(t, []string{"Example.com", "shop.io"}, keptDomains(batch, keep))
+
+	// A later batch retries the kept item and brings another variant of the same domain
+	fourth := uuid.New()
+	retry := []dedupCandidate{
+		{ItemID: fourth, DomainName: "EXAMPLE.COM"},
+		{ItemID: first, DomainName: "Example.com"},
+	}
+	keep, duplicates, err = applyDomainDedup(ctx, decisionStore, nil, campaignID, retry)
+	require.NoError(t, err)
+	assert.Equal(t, 1, duplicates)
+	assert.Equal(t, []string{"Example.com"}, keptDomains(retry, keep))
+
+	// Every item is accounted for, and each duplicate points at the single kept item
+	require.Len(t, decisionStore.decisions, 4)
+	keptByDomain := map[string]int{}
+	for _, d := range decisionStore.decisions {
+		switch d.Decision {
+		case models.DomainDedupDecisionKept:
+			keptByDomain[d.NormalizedDomain]++
+			assert.Equal(t, d.SourceItemID, d.KeptItemID)
+		case models.DomainDedupDecisionDuplicate:
+			assert.Equal(t, "example.com", d.NormalizedDomain)
+			assert.Equal(t, first, d.KeptItemID)
+		}
+	}
+	assert.Equal(t, map[string]int{"example.com": 1, "shop.io": 1}, keptByDomain)
+}
+
+func TestApplyDomainDedup_IsScopedPerCampaign(t *testing.T) {
+	ctx := context.Background()
+	decisionStore := &dedupDecisionStore{}
+
+	_, duplicates, err := applyDomainDedup(ctx, decisionStore, nil, uuid.New(), []dedupCandidate{{ItemID: uuid.New(), DomainName: "example.com"}})
+	require.NoError(t, err)
+	assert.Zero(t, duplicates)
+
+	keep, duplicates, err := applyDomainDedup(ctx, decisionStore, nil, uuid.New(), []dedupCandidate{{ItemID: uuid.New(), DomainName: "example.com"}})
+	require.NoError(t, err)
+	assert.Zero(t, duplicates)
+	assert.Equal(t, []bool{true}, keep)
+}
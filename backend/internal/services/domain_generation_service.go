@@ -39,6 +39,10 @@ func (s *domainGenerationServiceImpl) CreateCampaign(ctx context.Context, req Cr
 	functionStartTime := time.Now().UTC() // Use a distinct name for clarity
 	campaignID := uuid.New()
 
+	if err := ValidateDNSPromotion(req.DNSPromotion); err != nil {
+		return nil, fmt.Errorf("invalid domain generation parameters for campaign %s: %w", req.Name, err)
+	}
+
 	tempGenParamsForHash := models.DomainGenerationCampaignParams{
 		PatternType:    req.PatternType,
 		VariableLength: models.IntPtr(req.VariableLength),
@@ -159,6 +163,8 @@ func (s *domainGenerationServiceImpl) CreateCampaign(ctx context.Context, req Cr
 		CampaignType:       models.CampaignTypeDomainGeneration,
 		Status:             models.CampaignStatusPending,
 		UserID:             userIDPtr,
+		Tags:               NormalizeCampaignTags(req.Tags),
+		AutoRetry:          req.AutoRetry,
 		CreatedAt:          functionStartTime, // Use functionStartTime
 		UpdatedAt:          functionStartTime, // Use functionStartTime
 		TotalItems:         models.Int64Ptr(actualTotalItemsForThisRun),
@@ -176,6 +182,7 @@ func (s *domainGenerationServiceImpl) CreateCampaign(ctx context.Context, req Cr
 		NumDomainsToGenerate:      int(campaignInstanceTargetCount), // Converted int64 to int
 		TotalPossibleCombinations: totalPossibleCombinations,
 		CurrentOffset:             startingOffset,
+		DNSPromotion:              normalizeDNSPromotion(req.DNSPromotion),
 	}
 	baseCampaign.DomainGenerationParams = campaignDomainGenParams
 
@@ -305,10 +312,10 @@ func (s *domainGenerationServiceImpl) logAuditEvent(ctx context.Context, exec st
 		"campaign_name": campaign.Name, // Safe if campaign is not nil
 		"description":   description,
 	}
-	detailsJSON, err := json.Marshal(detailsMap)
+	details, err := models.NewAuditDetails(detailsMap)
 	if err != nil {
-		log.Printf("Error marshalling audit log details for campaign %s, action %s: %v. Using raw description.", campaign.ID, action, err)
-		detailsJSON = json.RawMessage(fmt.Sprintf(`{"campaign_name": "%s", "description": "Details marshalling error, raw: %s"}`, campaign.Name, description))
+		log.Printf("Error encoding audit details for campaign %s, action %s: %v", campaign.ID, action, err)
+		return
 	}
 
 	var auditLogUserID uuid.NullUUID
@@ -321,7 +328,7 @@ func (s *domainGenerationServiceImpl) logAuditEvent(ctx context.Context, exec st
 		Action:     action,
 		EntityType: sql.NullString{String: "Campaign", Valid: true},
 		EntityID:   uuid.NullUUID{UUID: campaign.ID, Valid: true},
-		Details:    models.JSONRawMessagePtr(detailsJSON),
+		Details:    details,
 	}
 
 	if s.auditLogStore == nil {
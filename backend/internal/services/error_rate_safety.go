@@ -0,0 +1,179 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/circuitbreaker"
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/websocket"
+)
+
+// errorRateSafetyMetadataKey is the campaign metadata key holding the counters at the last safety stop,
+// so a resumed campaign is judged only on the items it processes after being resumed.
+const errorRateSafetyMetadataKey = "errorRateSafety"
+
+// errorRateBaseline records where the item counters stood when the safety last stopped a campaign
+type errorRateBaseline struct {
+	SuccessfulItems int64     `json:"successfulItems"`
+	FailedItems     int64     `json:"failedItems"`
+	FailureRate     float64   `json:"failureRate"`
+	StoppedAt       time.Time `json:"stoppedAt"`
+}
+
+// errorRateSafety pauses or fails a campaign once too many of its items fail
+type errorRateSafety struct {
+	threshold float64
+	minSample int64
+	action    models.CampaignStatusEnum
+}
+
+// newErrorRateSafety returns nil when the safety is disabled. The threshold is capped at 1 and the
+// sample size is held at or above config.MinCampaignSafetySampleSize.
+func newErrorRateSafety(appCfg *config.AppConfig) *errorRateSafety {
+	if appCfg == nil || appCfg.CampaignSafety.ErrorRateThreshold <= 0 {
+		return nil
+	}
+	cfg := appCfg.CampaignSafety
+	safety := &errorRateSafety{
+		threshold: cfg.ErrorRateThreshold,
+		minSample: int64(cfg.MinSampleSize),
+		action:    models.CampaignStatusPaused,
+	}
+	if safety.threshold > 1 {
+		safety.threshold = 1
+	}
+	if safety.minSample <= 0 {
+		safety.minSample = config.DefaultCampaignSafetyMinSampleSize
+	}
+	if safety.minSample < config.MinCampaignSafetySampleSize {
+		safety.minSample = config.MinCampaignSafetySampleSize
+	}
+	if strings.EqualFold(cfg.Action, "fail") {
+		safety.action = models.CampaignStatusFailed
+	}
+	return safety
+}
+
+// recordItemOutcomes adds a batch's outcomes to the campaign's running success and failure counters
+func recordItemOutcomes(campaign *models.Campaign, succeeded, failed int) {
+	if campaign.SuccessfulItems == nil {
+		campaign.SuccessfulItems = models.Int64Ptr(0)
+	}
+	if campaign.FailedItems == nil {
+		campaign.FailedItems = models.Int64Ptr(0)
+	}
+	*campaign.SuccessfulItems += int64(succeeded)
+	*campaign.FailedItems += int64(failed)
+}
+
+// isDNSResultFailure reports whether a DNS result means the lookup could not be done. NXDOMAIN is a
+// valid answer; resolver errors, timeouts and open circuits are failures.
+func isDNSResultFailure(status string) bool {
+	switch status {
+	case "Error", "Timeout", circuitbreaker.StatusCircuitOpen:
+		return true
+	}
+	return false
+}
+
+// isHTTPResultFailure reports whether an HTTP keyword result means the page could not be checked
+func isHTTPResultFailure(status string) bool {
+	switch status {
+	case "processing_failed_before_http", "invalid_http_response_error", circuitbreaker.StatusCircuitOpen, httpStatusCertPinMismatch:
+		return true
+	}
+	return false
+}
+
+// countItemOutcomes splits result statuses into successes and failures. Cancelled items are neither.
+func countItemOutcomes(statuses []string, isFailure func(string) bool) (succeeded, failed int) {
+	for _, status := range statuses {
+		switch {
+		case status == "cancelled_during_processing":
+		case isFailure(status):
+			failed++
+		default:
+			succeeded++
+		}
+	}
+	return succeeded, failed
+}
+
+// baseline returns the counters recorded at the campaign's last safety stop
+func (s *errorRateSafety) baseline(campaign *models.Campaign) errorRateBaseline {
+	var base errorRateBaseline
+	if campaign.Metadata == nil {
+		return base
+	}
+	var metadata map[string]json.RawMessage
+	if err := json.Unmarshal(*campaign.Metadata, &metadata); err != nil {
+		return base
+	}
+	if raw, ok := metadata[errorRateSafetyMetadataKey]; ok {
+		_ = json.Unmarshal(raw, &base)
+	}
+	return base
+}
+
+// evaluate checks the failure rate of the items processed since the last safety stop. Over the
+// threshold it moves the campaign to the configured status, records the new baseline in its metadata
+// and returns a message describing why.
+func (s *errorRateSafety) evaluate(campaign *models.Campaign, now time.Time) (string, bool) {
+	if s == nil || campaign.SuccessfulItems == nil || campaign.FailedItems == nil {
+		return "", false
+	}
+	base := s.baseline(campaign)
+	succeeded := *campaign.SuccessfulItems - base.SuccessfulItems
+	failed := *campaign.FailedItems - base.FailedItems
+	sample := succeeded + failed
+	if sample < s.minSample {
+		return "", false
+	}
+	rate := float64(failed) / float64(sample)
+	if rate <= s.threshold {
+		return "", false
+	}
+
+	base = errorRateBaseline{
+		SuccessfulItems: *campaign.SuccessfulItems,
+		FailedItems:     *campaign.FailedItems,
+		FailureRate:     rate,
+		StoppedAt:       now,
+	}
+	metadata := map[string]json.RawMessage{}
+	if campaign.Metadata != nil {
+		_ = json.Unmarshal(*campaign.Metadata, &metadata)
+	}
+	if encoded, err := json.Marshal(base); err == nil {
+		metadata[errorRateSafetyMetadataKey] = encoded
+	}
+	if encoded, err := json.Marshal(metadata); err == nil {
+		campaign.Metadata = models.JSONRawMessagePtr(encoded)
+	}
+
+	message := fmt.Sprintf("Error-rate safety: %d of the last %d items failed (%.0f%%, threshold %.0f%%); campaign %s",
+		failed, sample, rate*100, s.threshold*100, s.action)
+	campaign.Status = s.action
+	campaign.ErrorMessage = models.StringPtr(message)
+	campaign.UpdatedAt = now
+	if s.action == models.CampaignStatusFailed {
+		campaign.CompletedAt = &now
+	}
+	return message, true
+}
+
+// alertErrorRateSafety tells connected clients that a campaign was stopped by the error-rate safety
+func alertErrorRateSafety(campaign *models.Campaign, phase, message string) {
+	log.Printf("ALERT: campaign %s (%s) stopped by error-rate safety: %s", campaign.ID, campaign.Name, message)
+	progress := 0.0
+	if campaign.ProgressPercentage != nil {
+		progress = *campaign.ProgressPercentage
+	}
+	websocket.BroadcastCampaignProgress(campaign.ID.String(), progress, string(campaign.Status), phase)
+	websocket.BroadcastSystemNotification(fmt.Sprintf("Campaign %q: %s", campaign.Name, message), "warning")
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/circuitbreaker"
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func safetyConfig(threshold float64, minSample int, action string) *config.AppConfig {
+	return &config.AppConfig{CampaignSafety: config.CampaignSafetyConfig{
+		ErrorRateThreshold: threshold,
+		MinSampleSize:      minSample,
+		Action:             action,
+	}}
+}
+
+func runningCampaign() *models.Campaign {
+	return &models.Campaign{ID: uuid.New(), Name: "safety", Status: models.CampaignStatusRunning}
+}
+
+// runBatch records a batch of outcomes and evaluates the safety the way the batch processors do
+func runBatch(safety *errorRateSafety, campaign *models.Campaign, succeeded, failed int) (string, bool) {
+	recordItemOutcomes(campaign, succeeded, failed)
+	return safety.evaluate(campaign, time.Now().UTC())
+}
+
+func TestErrorRateSafety_HighEarlyFailureRatePauses(t *testing.T) {
+	safety := newErrorRateSafety(safetyConfig(0.5, 50, ""))
+	require.NotNil(t, safety)
+	campaign := runningCampaign()
+
+	_, tripped := runBatch(safety, campaign, 2, 23)
+	assert.False(t, tripped, "the rate is not judged before the minimum sample")
+	assert.Equal(t, models.CampaignStatusRunning, campaign.Status)
+
+	message, tripped := runBatch(safety, campaign, 3, 22)
+	require.True(t, tripped)
+	assert.Equal(t, models.CampaignStatusPaused, campaign.Status)
+	require.NotNil(t, campaign.ErrorMessage)
+	assert.Equal(t, message, *campaign.ErrorMessage)
+	assert.Contains(t, message, "45 of the last 50 items failed")
+	assert.Nil(t, campaign.CompletedAt, "a paused campaign is not finished")
+	assert.Equal(t, int64(5), *campaign.SuccessfulItems)
+	assert.Equal(t, int64(45), *campaign.FailedItems)
+}
+
+func TestErrorRateSafety_HealthyCampaignKeepsRunning(t *testing.T) {
+	safety := newErrorRateSafety(safetyConfig(0.5, 50, "pause"))
+	campaign := runningCampaign()
+
+	for i := 0; i < 20; i++ {
+		_, tripped := runBatch(safety, campaign, 40, 10)
+		require.False(t, tripped, "batch %d", i)
+	}
+	assert.Equal(t, models.CampaignStatusRunning, campaign.Status)
+	assert.Nil(t, campaign.ErrorMessage)
+	assert.Equal(t, int64(200), *campaign.FailedItems)
+}
+
+func TestErrorRateSafety_ResumedCampaignJudgedOnNewItems(t *testing.T) {
+	safety := newErrorRateSafety(safetyConfig(0.5, 20, "pause"))
+	campaign := runningCampaign()
+	campaign.Metadata = models.JSONRawMessagePtr([]byte(`{"owner":"ops"}`))
+
+	_, tripped := runBatch(safety, campaign, 0, 30)
+	require.True(t, tripped)
+
+	// Resumed after the config was fixed: the earlier failures no longer count
+	campaign.Status = models.CampaignStatusRunning
+	_, tripped = runBatch(safety, campaign, 20, 5)
+	assert.False(t, tripped)
+	assert.JSONEq(t, `"ops"`, string(mustMetadataKey(t, campaign, "owner")), "existing metadata is kept")
+
+	_, tripped = runBatch(safety, campaign, 0, 30)
+	assert.True(t, tripped, "a new run of failures stops the campaign again")
+}
+
+func TestErrorRateSafety_FailActionAndBounds(t *testing.T) {
+	assert.Nil(t, newErrorRateSafety(safetyConfig(0, 50, "fail")), "the safety is opt-in")
+	assert.Nil(t, newErrorRateSafety(nil))
+
+	safety := newErrorRateSafety(safetyConfig(7, 3, "FAIL"))
+	require.NotNil(t, safety)
+	assert.Equal(t, 1.0, safety.threshold)
+	assert.Equal(t, int64(config.MinCampaignSafetySampleSize), safety.minSample)
+
+	safety = newErrorRateSafety(safetyConfig(0.2, 20, "fail"))
+	campaign := runningCampaign()
+	_, tripped := runBatch(safety, campaign, 10, 10)
+	require.True(t, tripped)
+	assert.Equal(t, models.CampaignStatusFailed, campaign.Status)
+	assert.NotNil(t, campaign.CompletedAt)
+}
+
+func TestCountItemOutcomes(t *testing.T) {
+	succeeded, failed := countItemOutcomes([]string{"Resolved", "Not Found", "Error", "Timeout", circuitbreaker.StatusCircuitOpen}, isDNSResultFailure)
+	assert.Equal(t, 2, succeeded)
+	assert.Equal(t, 3, failed)
+
+	succeeded, failed = countItemOutcomes([]string{"lead_valid", "invalid_http_code", "processing_failed_before_http", "cancelled_during_processing"}, isHTTPResultFailure)
+	assert.Equal(t, 2, succeeded)
+	assert.Equal(t, 1, failed)
+}
+
+func mustMetadataKey(t *testing.T, campaign *models.Campaign, key string) []byte {
+	t.Helper()
+	require.NotNil(t, campaign.Metadata)
+	var metadata map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(*campaign.Metadata, &metadata))
+	return metadata[key]
+}
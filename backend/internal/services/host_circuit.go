@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+
+	"github.com/fntelecomllc/studio/backend/internal/circuitbreaker"
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/dnsvalidator"
+	"github.com/fntelecomllc/studio/backend/internal/hostlimiter"
+	"github.com/fntelecomllc/studio/backend/internal/httpvalidator"
+)
+
+// newHostBreaker builds the per-host circuit breaker used by a campaign worker service
+func newHostBreaker(appCfg *config.AppConfig) *circuitbreaker.Breaker {
+	if appCfg == nil {
+		return circuitbreaker.New(circuitbreaker.Config{})
+	}
+	return circuitbreaker.NewFromConfig(appCfg.CircuitBreaker)
+}
+
+// recordDNSCircuitOutcome feeds a DNS lookup into the target host's circuit. Answers, including
+// NXDOMAIN, close the circuit; timeouts and resolver errors count as failures. Results caused by
+// the batch being cancelled say nothing about the host and are ignored.
+func recordDNSCircuitOutcome(ctx context.Context, breaker *circuitbreaker.Breaker, host string, result dnsvalidator.ValidationResult) {
+	switch result.Status {
+	case "Resolved", "Not Found":
+		breaker.RecordSuccess(host)
+	case "Timeout", "Error":
+		if ctx.Err() == nil {
+			breaker.RecordFailure(host)
+		}
+	}
+}
+
+// recordHTTPCircuitOutcome feeds an HTTP request into the target host's circuit. Any HTTP response
+// closes the circuit; fetch failures and timeouts count as failures.
+func recordHTTPCircuitOutcome(ctx context.Context, breaker *circuitbreaker.Breaker, host string, result *httpvalidator.ValidationResult) {
+	if result == nil {
+		return
+	}
+	if result.StatusCode > 0 {
+		breaker.RecordSuccess(host)
+		return
+	}
+	switch result.Status {
+	case "ErrorFetchFailed", "ErrorTimeout":
+		if ctx.Err() == nil {
+			breaker.RecordFailure(host)
+		}
+	}
+}
+
+// newHostRateLimiter builds the per-host/per-zone outbound rate limiter shared by a service's workers
+func newHostRateLimiter(appCfg *config.AppConfig) *hostlimiter.Limiter {
+	if appCfg == nil {
+		return hostlimiter.New(hostlimiter.Config{})
+	}
+	return hostlimiter.NewFromConfig(appCfg.OutboundRateLimit)
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/circuitbreaker"
+	"github.com/fntelecomllc/studio/backend/internal/dnsvalidator"
+	"github.com/fntelecomllc/studio/backend/internal/httpvalidator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordDNSCircuitOutcome(t *testing.T) {
+	ctx := context.Background()
+	breaker := circuitbreaker.New(circuitbreaker.Config{FailureThreshold: 2, Cooldown: time.Hour})
+
+	recordDNSCircuitOutcome(ctx, breaker, "lame.example.com", dnsvalidator.ValidationResult{Status: "Timeout"})
+	recordDNSCircuitOutcome(ctx, breaker, "lame.example.com", dnsvalidator.ValidationResult{Status: "Error"})
+	assert.Equal(t, circuitbreaker.StateOpen, breaker.State("lame.example.com"))
+
+	// NXDOMAIN is a definitive answer, not a host failure
+	recordDNSCircuitOutcome(ctx, breaker, "gone.example.com", dnsvalidator.ValidationResult{Status: "Timeout"})
+	recordDNSCircuitOutcome(ctx, breaker, "gone.example.com", dnsvalidator.ValidationResult{Status: "Not Found"})
+	recordDNSCircuitOutcome(ctx, breaker, "gone.example.com", dnsvalidator.ValidationResult{Status: "Timeout"})
+	assert.Equal(t, circuitbreaker.StateClosed, breaker.State("gone.example.com"))
+
+	// Errors caused by cancelling the batch are ignored
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	for i := 0; i < 3; i++ {
+		recordDNSCircuitOutcome(cancelled, breaker, "busy.example.com", dnsvalidator.ValidationResult{Status: "Error"})
+	}
+	assert.Equal(t, circuitbreaker.StateClosed, breaker.State("busy.example.com"))
+}
+
+func TestRecordHTTPCircuitOutcome(t *testing.T) {
+	ctx := context.Background()
+	breaker := circuitbreaker.New(circuitbreaker.Config{FailureThreshold: 2, Cooldown: time.Hour})
+
+	recordHTTPCircuitOutcome(ctx, breaker, "down.example.com", &httpvalidator.ValidationResult{Status: "ErrorTimeout"})
+	recordHTTPCircuitOutcome(ctx, breaker, "down.example.com", &httpvalidator.ValidationResult{Status: "ErrorFetchFailed"})
+	assert.False(t, breaker.Allow("down.example.com"))
+
+	// Any HTTP response, even an error status, shows the host is reachable
+	recordHTTPCircuitOutcome(ctx, breaker, "up.example.com", &httpvalidator.ValidationResult{Status: "ErrorTimeout"})
+	recordHTTPCircuitOutcome(ctx, breaker, "up.example.com", &httpvalidator.ValidationResult{Status: "FailedValidation", StatusCode: 503})
+	recordHTTPCircuitOutcome(ctx, breaker, "up.example.com", &httpvalidator.ValidationResult{Status: "ErrorTimeout"})
+	assert.True(t, breaker.Allow("up.example.com"))
+}
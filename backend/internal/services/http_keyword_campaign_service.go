@@ -5,15 +5,16 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"reflect"
-	"regexp"
-	"strings"
 	"sync"
 	"time"
 
+	"github.com/fntelecomllc/studio/backend/internal/circuitbreaker"
 	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/hostlimiter"
 	"github.com/fntelecomllc/studio/backend/internal/httpvalidator"
 	"github.com/fntelecomllc/studio/backend/internal/keywordscanner"
 	"github.com/fntelecomllc/studio/backend/internal/models"
@@ -36,6 +37,12 @@ type httpKeywordCampaignServiceImpl struct {
 	keywordScanner   *keywordscanner.Service
 	proxyManager     *proxymanager.ProxyManager
 	appConfig        *config.AppConfig
+	hostBreaker      *circuitbreaker.Breaker
+	hostLimiter      *hostlimiter.Limiter
+	errorRateSafety  *errorRateSafety
+	resourceHealth   *resourceHealthCache
+	keywordRules     *keywordRuleCache
+	proxySelection   *proxySelector
 }
 
 // NewHTTPKeywordCampaignService creates a new HTTPKeywordCampaignService.
@@ -57,6 +64,12 @@ func NewHTTPKeywordCampaignService(
 		keywordScanner:   kwScanner,
 		proxyManager:     pm,
 		appConfig:        appCfg,
+		hostBreaker:      newHostBreaker(appCfg),
+		hostLimiter:      newHostRateLimiter(appCfg),
+		errorRateSafety:  newErrorRateSafety(appCfg),
+		resourceHealth:   newResourceHealthCache(appCfg, pm),
+		keywordRules:     newKeywordRuleCache(),
+		proxySelection:   newProxySelector(),
 	}
 }
 
@@ -70,8 +83,17 @@ func (s *httpKeywordCampaignServiceImpl) CreateCampaign(ctx context.Context, req
 	reqJSON, _ := json.MarshalIndent(req, "", "  ")
 	log.Printf("[DEBUG] CreateHTTPKeywordCampaignRequest: %s", reqJSON)
 
-	if len(req.KeywordSetIDs) == 0 && len(req.AdHocKeywords) == 0 {
-		return nil, fmt.Errorf("http create: keywordSetIds or adHocKeywords required")
+	validationMode, err := normalizeHTTPValidationMode(req.ValidationMode, req.KeywordSetIDs, req.AdHocKeywords)
+	if err != nil {
+		return nil, fmt.Errorf("http create: %w", err)
+	}
+	matchType, err := ValidateKeywordMatching(req.MatchType, req.AdHocKeywords)
+	if err != nil {
+		return nil, fmt.Errorf("http create: %w", err)
+	}
+	proxyStrategy, err := ValidateProxySelectionStrategy(req.ProxySelectionStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("http create: %w", err)
 	}
 
 	// Validate personas and keywords using a conditional querier (read-only pattern)
@@ -172,6 +194,8 @@ func (s *httpKeywordCampaignServiceImpl) CreateCampaign(ctx context.Context, req
 		CampaignType:       models.CampaignTypeHTTPKeywordValidation,
 		Status:             models.CampaignStatusPending,
 		UserID:             &req.UserID,
+		Tags:               NormalizeCampaignTags(req.Tags),
+		AutoRetry:          req.AutoRetry,
 		CreatedAt:          now,
 		UpdatedAt:          now,
 		TotalItems:         models.Int64Ptr(totalItems),
@@ -194,13 +218,16 @@ func (s *httpKeywordCampaignServiceImpl) CreateCampaign(ctx context.Context, req
 		AdHocKeywords:            models.StringSlicePtr(req.AdHocKeywords),
 		PersonaIDs:               personaIDsCopy, // Use the copied slice
 		ProxyPoolID:              uuid.NullUUID{UUID: derefUUIDPtr(req.ProxyPoolID), Valid: req.ProxyPoolID != nil},
-		ProxySelectionStrategy:   models.StringPtr(req.ProxySelectionStrategy),
+		ProxySelectionStrategy:   models.StringPtr(proxyStrategy),
 		RotationIntervalSeconds:  models.IntPtr(req.RotationIntervalSeconds),
 		ProcessingSpeedPerMinute: models.IntPtr(req.ProcessingSpeedPerMinute),
 		BatchSize:                models.IntPtr(req.BatchSize),
 		RetryAttempts:            models.IntPtr(req.RetryAttempts),
 		TargetHTTPPorts:          models.IntSlicePtr(req.TargetHTTPPorts),
 		SourceType:               "DNSValidation", // HTTP campaigns source from DNS validation results
+		DeduplicateDomains:       req.DeduplicateDomains,
+		ValidationMode:           validationMode,
+		MatchType:                matchType,
 	}
 
 	// Log the created params for debugging
@@ -370,10 +397,10 @@ func (s *httpKeywordCampaignServiceImpl) logAuditEvent(ctx context.Context, exec
 		"campaign_name": campaign.Name,
 		"description":   description,
 	}
-	detailsJSON, err := json.Marshal(detailsMap)
+	details, err := models.NewAuditDetails(detailsMap)
 	if err != nil {
-		log.Printf("Error marshalling audit log details for HTTP campaign %s, action %s: %v. Using raw description.", campaign.ID, action, err)
-		detailsJSON = json.RawMessage(fmt.Sprintf(`{"campaign_name": "%s", "description": "Details marshalling error: %s"}`, campaign.Name, description))
+		log.Printf("Error encoding audit details for HTTP campaign %s, action %s: %v", campaign.ID, action, err)
+		return
 	}
 
 	var userIDNullUUID uuid.NullUUID
@@ -387,7 +414,7 @@ func (s *httpKeywordCampaignServiceImpl) logAuditEvent(ctx context.Context, exec
 		Action:     action,
 		EntityType: sql.NullString{String: "Campaign", Valid: true},
 		EntityID:   uuid.NullUUID{UUID: campaign.ID, Valid: true},
-		Details:    models.JSONRawMessagePtr(detailsJSON), // This was already correct
+		Details:    details,
 	}
 	if err := s.auditLogStore.CreateAuditLog(ctx, exec, auditLog); err != nil {
 		log.Printf("Error creating audit log for HTTP campaign %s, action %s: %v", campaign.ID, action, err)
@@ -403,6 +430,13 @@ func derefUUIDPtr(id *uuid.UUID) uuid.UUID {
 
 func (s *httpKeywordCampaignServiceImpl) ProcessHTTPKeywordCampaignBatch(ctx context.Context, campaignID uuid.UUID) (done bool, processedInThisBatch int, err error) {
 	log.Printf("ProcessHTTPKeywordCampaignBatch: Starting for campaignID %s", campaignID)
+	defer func() {
+		if done {
+			s.resourceHealth.forget(campaignID) // Start-up test results last as long as the campaign runs
+			s.keywordRules.forget(campaignID)
+			s.proxySelection.forget(campaignID)
+		}
+	}()
 
 	var opErr error
 	var querier store.Querier
@@ -540,6 +574,13 @@ func (s *httpKeywordCampaignServiceImpl) ProcessHTTPKeywordCampaignBatch(ctx con
 		batchSizeVal = 20
 	}
 
+	if s.appConfig.Worker.HTTPResumeCheckEnabled() {
+		if _, errVerify := verifyHTTPResumePoint(ctx, s.campaignStore, querier, campaignID, hkParams, time.Now().UTC()); errVerify != nil {
+			opErr = errVerify
+			return false, 0, opErr
+		}
+	}
+
 	lastProcessedDomainNameVal := ""
 	if hkParams.LastProcessedDomainName != nil {
 		lastProcessedDomainNameVal = *hkParams.LastProcessedDomainName
@@ -586,6 +627,60 @@ func (s *httpKeywordCampaignServiceImpl) ProcessHTTPKeywordCampaignBatch(ctx con
 		return done, 0, opErr
 	}
 
+	// Domains that normalize to one this campaign already handled are recorded and skipped
+	skippedDuplicates := 0
+	if hkParams.DeduplicateDomains {
+		candidates := make([]dedupCandidate, len(domainsToProcess))
+		for i, dnsRecord := range domainsToProcess {
+			candidates[i] = dedupCandidate{ItemID: dnsRecord.ID, DomainName: dnsRecord.DomainName}
+		}
+		keep, duplicates, errDedup := applyDomainDedup(ctx, s.campaignStore, querier, campaignID, candidates)
+		if errDedup != nil {
+			opErr = errDedup
+			return false, 0, opErr
+		}
+		uniqueRecords := make([]*models.DNSValidationResult, 0, len(domainsToProcess))
+		for i, dnsRecord := range domainsToProcess {
+			if keep[i] {
+				uniqueRecords = append(uniqueRecords, dnsRecord)
+			}
+		}
+		if duplicates > 0 {
+			log.Printf("ProcessHTTPKeywordCampaignBatch: Skipping %d duplicate domains for campaign %s.", duplicates, campaignID)
+		}
+		domainsToProcess = uniqueRecords
+		skippedDuplicates = duplicates
+	}
+
+	// Domains retried up to the attempt cap are marked and no longer validated
+	domainNames := make([]string, len(domainsToProcess))
+	for i, dnsRecord := range domainsToProcess {
+		domainNames[i] = dnsRecord.DomainName
+	}
+	keep, exceededAttempts, errCap := applyAttemptCap(ctx,
+		func(ctx context.Context, campaignID uuid.UUID, names []string) (map[string]int, error) {
+			return s.campaignStore.GetHTTPKeywordAttempts(ctx, querier, campaignID, names)
+		},
+		func(ctx context.Context, campaignID uuid.UUID, names []string) error {
+			return s.campaignStore.MarkHTTPKeywordMaxAttemptsExceeded(ctx, querier, campaignID, names)
+		},
+		campaignID, domainNames, s.appConfig.Worker.MaxAttemptsPerDomain)
+	if errCap != nil {
+		opErr = errCap
+		return false, 0, opErr
+	}
+	if exceededAttempts > 0 {
+		log.Printf("ProcessHTTPKeywordCampaignBatch: %d domains reached the attempt cap for campaign %s.", exceededAttempts, campaignID)
+		retryable := make([]*models.DNSValidationResult, 0, len(domainsToProcess)-exceededAttempts)
+		for i, dnsRecord := range domainsToProcess {
+			if keep[i] {
+				retryable = append(retryable, dnsRecord)
+			}
+		}
+		domainsToProcess = retryable
+		skippedDuplicates += exceededAttempts
+	}
+
 	personas := make([]*models.Persona, 0, len(hkParams.PersonaIDs))
 	for _, pID := range hkParams.PersonaIDs {
 		p, pErr := s.personaStore.GetPersonaByID(ctx, querier, pID)
@@ -604,29 +699,63 @@ func (s *httpKeywordCampaignServiceImpl) ProcessHTTPKeywordCampaignBatch(ctx con
 		return false, 0, opErr
 	}
 
-	allKeywordRulesModels := []models.KeywordRule{}
-	if len(hkParams.KeywordSetIDs) > 0 {
-		for _, ksID := range hkParams.KeywordSetIDs {
-			rules, rErr := s.keywordStore.GetKeywordRulesBySetID(ctx, querier, ksID)
-			if rErr != nil {
-				opErr = fmt.Errorf("failed to fetch rules for keyword set %s: %w", ksID, rErr)
-				return false, 0, opErr
+	// Use a local conditional querier for proxy reads, independent of the main transaction
+	var proxyReadQuerier store.Querier
+	if s.db != nil {
+		proxyReadQuerier = s.db
+	}
+
+	// Personas and proxies are tested once when the campaign starts; later batches reuse the results
+	if s.resourceHealth != nil {
+		var proxies []config.ProxyConfigEntry
+		if hkParams.ProxyPoolID.Valid {
+			var errProxy error
+			if proxies, errProxy = s.campaignProxyEntries(ctx, proxyReadQuerier, campaign); errProxy != nil {
+				log.Printf("Error getting proxies to test for HTTP campaign %s: %v", campaignID, errProxy)
 			}
-			allKeywordRulesModels = append(allKeywordRulesModels, rules...)
+		}
+		s.resourceHealth.prewarm(ctx, campaignID, personas, proxies)
+		personas = s.resourceHealth.usablePersonas(campaignID, personas)
+	}
+
+	// Proxies are picked per request among the proxies usable when the batch starts
+	var proxyCandidates []*models.Proxy
+	var proxyStrategy string
+	var proxyRotationInterval time.Duration
+	if hkParams.ProxyPoolID.Valid && s.proxyManager != nil {
+		var errProxy error
+		proxyCandidates, errProxy = s.campaignProxyCandidates(ctx, proxyReadQuerier, campaign)
+		if errProxy != nil {
+			log.Printf("Error getting proxies (pool %s) for HTTP campaign %s: %v", hkParams.ProxyPoolID.UUID, campaignID, errProxy)
+		} else if len(proxyCandidates) == 0 {
+			log.Printf("No healthy proxy available (pool %s) for HTTP campaign %s", hkParams.ProxyPoolID.UUID, campaignID)
+		}
+		if hkParams.ProxySelectionStrategy != nil {
+			proxyStrategy = *hkParams.ProxySelectionStrategy
+		}
+		if hkParams.RotationIntervalSeconds != nil {
+			proxyRotationInterval = time.Duration(*hkParams.RotationIntervalSeconds) * time.Second
 		}
 	}
-	compiledKeywordRules := make([]keywordscanner.CompiledKeywordRule, 0, len(allKeywordRulesModels))
-	for _, r := range allKeywordRulesModels {
-		var compiledRegex *regexp.Regexp
-		if r.RuleType == models.KeywordRuleTypeRegex {
-			re, compErr := regexp.Compile(r.Pattern)
-			if compErr == nil {
-				compiledRegex = re
-			} else {
-				log.Printf("Error compiling regex for rule %s: %v. Will be skipped for regex matching.", r.ID, compErr)
-			}
+
+	// Liveness-only campaigns have no keywords and skip loading rules and reading bodies
+	livenessOnly := isHTTPLivenessOnly(hkParams)
+	validateDomain := s.httpValidator.Validate
+	if livenessOnly {
+		validateDomain = s.httpValidator.ValidateLiveness
+	}
+
+	// Keyword rules are compiled on the first batch of a run and reused until the campaign stops
+	campaignKeywords := &campaignKeywordRules{}
+	if !livenessOnly {
+		var kErr error
+		campaignKeywords, kErr = s.keywordRules.rulesFor(campaignID, func() (*campaignKeywordRules, error) {
+			return loadCampaignKeywordRules(ctx, s.keywordStore, querier, hkParams)
+		})
+		if kErr != nil {
+			opErr = kErr
+			return false, 0, opErr
 		}
-		compiledKeywordRules = append(compiledKeywordRules, keywordscanner.CompiledKeywordRule{KeywordRule: r, CompiledRegex: compiledRegex})
 	}
 	// If opErr was set by store calls, return (defer will handle rollback)
 	if opErr != nil {
@@ -665,34 +794,11 @@ func (s *httpKeywordCampaignServiceImpl) ProcessHTTPKeywordCampaignBatch(ctx con
 			var successPersonaID uuid.NullUUID
 			var usedProxyID uuid.NullUUID
 			attemptCount := 0
-			var foundKeywordsFromSetsJSON json.RawMessage
-			var adhocKeywordsFoundForThisDomain []string
-
-			var proxyForValidator *models.Proxy
-			if hkParams.ProxyPoolID.Valid && s.proxyManager != nil {
-				proxyEntry, errPmGet := s.proxyManager.GetProxy()
-				if errPmGet == nil && proxyEntry != nil {
-					proxyUUID, errParse := uuid.Parse(proxyEntry.ID)
-					if errParse == nil {
-						// Use a local conditional querier for this read, independent of the main transaction
-						var proxyReadQuerier store.Querier
-						if s.db != nil {
-							proxyReadQuerier = s.db
-						}
-						fetchedProxy, errDbGet := s.proxyStore.GetProxyByID(batchCtx, proxyReadQuerier, proxyUUID) // Use batchCtx
-						if errDbGet == nil {
-							proxyForValidator = fetchedProxy
-							usedProxyID = uuid.NullUUID{UUID: fetchedProxy.ID, Valid: true}
-							log.Printf("Using proxy %s (%s) for domain %s", fetchedProxy.ID, fetchedProxy.Address, currentDNSRecord.DomainName)
-						} else {
-							log.Printf("Error fetching full proxy model %s from store: %v", proxyEntry.ID, errDbGet)
-						}
-					} else {
-						log.Printf("Error parsing proxy ID '%s' from manager: %v", proxyEntry.ID, errParse)
-					}
-				} else if errPmGet != nil {
-					log.Printf("Error getting proxy from manager (pool %s): %v", hkParams.ProxyPoolID.UUID, errPmGet)
-				}
+
+			proxyForValidator := s.proxySelection.pick(campaignID, proxyStrategy, proxyRotationInterval, proxyCandidates)
+			if proxyForValidator != nil {
+				usedProxyID = uuid.NullUUID{UUID: proxyForValidator.ID, Valid: true}
+				log.Printf("Using proxy %s (%s) for domain %s", proxyForValidator.ID, proxyForValidator.Address, currentDNSRecord.DomainName)
 			}
 
 			for _, persona := range personas {
@@ -701,8 +807,23 @@ func (s *httpKeywordCampaignServiceImpl) ProcessHTTPKeywordCampaignBatch(ctx con
 					finalHTTPValResult = &httpvalidator.ValidationResult{Domain: currentDNSRecord.DomainName, Status: "ErrorCancelled", Error: fmt.Sprintf("Context cancelled during persona %s processing", persona.ID)}
 					goto StoreResultGoroutine
 				}
+				// Skip hosts whose circuit is open instead of waiting on another timeout
+				if !s.hostBreaker.Allow(currentDNSRecord.DomainName) {
+					finalHTTPValResult = &httpvalidator.ValidationResult{
+						Domain: currentDNSRecord.DomainName,
+						Status: circuitbreaker.StatusCircuitOpen,
+						Error:  "Circuit open for host after repeated failures",
+					}
+					goto StoreResultGoroutine
+				}
+				// Space requests to the same host and zone across all workers
+				if errWait := s.hostLimiter.Wait(batchCtx, currentDNSRecord.DomainName); errWait != nil {
+					finalHTTPValResult = &httpvalidator.ValidationResult{Domain: currentDNSRecord.DomainName, Status: "ErrorCancelled", Error: fmt.Sprintf("Context cancelled while waiting for host rate limit: %v", errWait)}
+					goto StoreResultGoroutine
+				}
 				attemptCount++
-				httpValRes, httpErr := s.httpValidator.Validate(batchCtx, currentDNSRecord.DomainName, currentDNSRecord.DomainName, persona, proxyForValidator) // Use batchCtx
+				httpValRes, httpErr := validateDomain(batchCtx, currentDNSRecord.DomainName, currentDNSRecord.DomainName, persona, proxyForValidator) // Use batchCtx
+				recordHTTPCircuitOutcome(batchCtx, s.hostBreaker, currentDNSRecord.DomainName, httpValRes)
 
 				if httpErr == nil && httpValRes.IsSuccess {
 					finalHTTPValResult = httpValRes
@@ -763,6 +884,11 @@ func (s *httpKeywordCampaignServiceImpl) ProcessHTTPKeywordCampaignBatch(ctx con
 				if finalHTTPValResult.ContentHash != "" {
 					dbRes.ContentHash = models.StringPtr(finalHTTPValResult.ContentHash)
 				}
+				dbRes.BodyTruncated = finalHTTPValResult.BodyTruncated
+				if finalHTTPValResult.TLSCertificate != nil {
+					certBytes, _ := json.Marshal(finalHTTPValResult.TLSCertificate)
+					dbRes.TLSCertificate = models.JSONRawMessagePtr(json.RawMessage(certBytes))
+				}
 
 				if finalHTTPValResult.ExtractedTitle != "" {
 					dbRes.PageTitle = models.StringPtr(finalHTTPValResult.ExtractedTitle)
@@ -772,43 +898,22 @@ func (s *httpKeywordCampaignServiceImpl) ProcessHTTPKeywordCampaignBatch(ctx con
 					dbRes.ExtractedContentSnippet = models.StringPtr(finalHTTPValResult.ExtractedContentSnippet)
 				}
 
-				if finalHTTPValResult.IsSuccess && len(finalHTTPValResult.RawBody) > 0 {
-					if len(compiledKeywordRules) > 0 {
-						foundPatterns, scanErr := s.keywordScanner.ScanWithRules(batchCtx, finalHTTPValResult.RawBody, compiledKeywordRules) // Use batchCtx
-						if scanErr != nil {
-							log.Printf("Error scanning keywords from sets for %s: %v", currentDNSRecord.DomainName, scanErr)
-						} else if len(foundPatterns) > 0 {
-							foundKeywordsFromSetsJSON, _ = json.Marshal(foundPatterns)
-						}
-					}
-					if hkParams.AdHocKeywords != nil && len(*hkParams.AdHocKeywords) > 0 {
-						bodyLower := strings.ToLower(string(finalHTTPValResult.RawBody))
-						for _, adhocKw := range *hkParams.AdHocKeywords {
-							if strings.Contains(bodyLower, strings.ToLower(adhocKw)) {
-								adhocKeywordsFoundForThisDomain = append(adhocKeywordsFoundForThisDomain, adhocKw)
-							}
-						}
-					}
-
-					if len(foundKeywordsFromSetsJSON) > 0 {
-						dbRes.FoundKeywordsFromSets = models.JSONRawMessagePtr(foundKeywordsFromSetsJSON)
-					} else {
-						dbRes.FoundKeywordsFromSets = nil
-					}
-
-					if len(adhocKeywordsFoundForThisDomain) > 0 {
-						dbRes.FoundAdHocKeywords = &adhocKeywordsFoundForThisDomain
-					} else {
-						dbRes.FoundAdHocKeywords = nil
-					}
-
-					if (foundKeywordsFromSetsJSON != nil && string(foundKeywordsFromSetsJSON) != "null" && string(foundKeywordsFromSetsJSON) != "[]") || len(adhocKeywordsFoundForThisDomain) > 0 {
-						dbRes.ValidationStatus = "lead_valid"
-					} else {
-						dbRes.ValidationStatus = "http_valid_no_keywords"
+				if livenessOnly {
+					dbRes.ValidationStatus = httpLivenessStatus(finalHTTPValResult)
+				} else if finalHTTPValResult.IsSuccess && len(finalHTTPValResult.RawBody) > 0 {
+					classification, scanErr := classifyHTTPKeywordContent(batchCtx, s.keywordScanner, finalHTTPValResult.RawBody, campaignKeywords) // Use batchCtx
+					if scanErr != nil {
+						log.Printf("Error scanning keywords from sets for %s: %v", currentDNSRecord.DomainName, scanErr)
 					}
+					dbRes.FoundKeywordsFromSets = classification.FoundKeywordsFromSets
+					dbRes.FoundAdHocKeywords = classification.FoundAdHocKeywords
+					dbRes.ValidationStatus = classification.Status
 				} else if finalHTTPValResult.Status == "ErrorCancelled" {
 					dbRes.ValidationStatus = "cancelled_during_processing"
+				} else if finalHTTPValResult.Status == circuitbreaker.StatusCircuitOpen {
+					dbRes.ValidationStatus = circuitbreaker.StatusCircuitOpen
+				} else if finalHTTPValResult.Status == httpvalidator.StatusCertPinMismatch {
+					dbRes.ValidationStatus = httpStatusCertPinMismatch
 				} else if finalHTTPValResult.Error != "" {
 					dbRes.ValidationStatus = "invalid_http_response_error"
 				} else {
@@ -831,8 +936,15 @@ func (s *httpKeywordCampaignServiceImpl) ProcessHTTPKeywordCampaignBatch(ctx con
 		}
 	}
 
+	// Results are saved outside the batch transaction so the store can commit them in chunks;
+	// the upsert makes re-processing any uncommitted remainder idempotent.
 	if len(dbResults) > 0 {
-		if errCreateResults := s.campaignStore.CreateHTTPKeywordResults(ctx, querier, dbResults); errCreateResults != nil {
+		if errCreateResults := s.campaignStore.CreateHTTPKeywordResults(ctx, nil, dbResults); errCreateResults != nil {
+			var chunkErr *store.ChunkedWriteError
+			if errors.As(errCreateResults, &chunkErr) {
+				log.Printf("ProcessHTTPKeywordCampaignBatch: committed %d/%d result chunks (%d rows) for campaign %s before failure",
+					chunkErr.CommittedChunks, chunkErr.TotalChunks, chunkErr.CommittedRows, campaignID)
+			}
 			currentErr := fmt.Errorf("failed to save HTTP/Keyword results for campaign %s: %w", campaignID, errCreateResults)
 			if opErr == nil {
 				opErr = currentErr
@@ -863,12 +975,25 @@ func (s *httpKeywordCampaignServiceImpl) ProcessHTTPKeywordCampaignBatch(ctx con
 		campaign.HTTPKeywordValidationParams = hkParams
 	}
 
+	// Collapsed duplicates and domains over the attempt cap count as processed so progress still reaches the source total
+	if opErr == nil {
+		processedInThisBatch += skippedDuplicates
+	}
+
 	if opErr == nil || batchProcessingContextErr != nil { // Update processed count if no critical save error or if context cancelled (partial save)
 		if campaign.ProcessedItems == nil {
 			campaign.ProcessedItems = models.Int64Ptr(0)
 		}
 		*campaign.ProcessedItems += int64(processedInThisBatch)
 	}
+	if opErr == nil && len(dbResults) > 0 {
+		statuses := make([]string, 0, len(dbResults))
+		for _, res := range dbResults {
+			statuses = append(statuses, res.ValidationStatus)
+		}
+		succeeded, failed := countItemOutcomes(statuses, isHTTPResultFailure)
+		recordItemOutcomes(campaign, succeeded, failed)
+	}
 
 	if campaign.TotalItems == nil {
 		campaign.TotalItems = models.Int64Ptr(0)
@@ -904,6 +1029,15 @@ func (s *httpKeywordCampaignServiceImpl) ProcessHTTPKeywordCampaignBatch(ctx con
 		done = false
 	}
 
+	// Stop a campaign whose items are mostly failing rather than working through the rest
+	var safetyMessage string
+	if !done && opErr == nil {
+		if message, tripped := s.errorRateSafety.evaluate(campaign, nowTime); tripped {
+			safetyMessage = message
+			done = true
+		}
+	}
+
 	if errUpdateCamp := s.campaignStore.UpdateCampaign(ctx, querier, campaign); errUpdateCamp != nil {
 		currentErr := fmt.Errorf("failed to update campaign %s status/progress: %w", campaignID, errUpdateCamp)
 		if opErr == nil {
@@ -915,7 +1049,10 @@ func (s *httpKeywordCampaignServiceImpl) ProcessHTTPKeywordCampaignBatch(ctx con
 	}
 
 	// Broadcast HTTP validation progress via WebSocket
-	if campaign.ProgressPercentage != nil && campaign.ProcessedItems != nil && campaign.TotalItems != nil {
+	if safetyMessage != "" {
+		s.logAuditEvent(ctx, querier, campaign, "Campaign Stopped By Error-Rate Safety", safetyMessage)
+		alertErrorRateSafety(campaign, "http_keyword_validation", safetyMessage)
+	} else if campaign.ProgressPercentage != nil && campaign.ProcessedItems != nil && campaign.TotalItems != nil {
 		processedCount := *campaign.ProcessedItems
 		totalCount := *campaign.TotalItems
 		
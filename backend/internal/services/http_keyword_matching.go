@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/fntelecomllc/studio/backend/internal/keywordscanner"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+)
+
+// ValidateKeywordMatching checks an HTTP keyword campaign's match type and that each of its ad
+// hoc keywords compiles with it, returning the match type to store. An empty match type is
+// substring.
+func ValidateKeywordMatching(matchType string, adHocKeywords []string) (models.KeywordMatchTypeEnum, error) {
+	normalized := models.KeywordMatchTypeEnum(strings.ToLower(strings.TrimSpace(matchType)))
+	switch normalized {
+	case "":
+		normalized = models.KeywordMatchTypeSubstring
+	case models.KeywordMatchTypeSubstring, models.KeywordMatchTypeRegex, models.KeywordMatchTypeWordBoundary:
+	default:
+		return "", fmt.Errorf("matchType %q must be substring, regex or word_boundary", matchType)
+	}
+	for i, keyword := range adHocKeywords {
+		rule := models.KeywordRule{Pattern: keyword, RuleType: models.KeywordRuleTypeString}
+		if _, err := keywordscanner.CompileKeywordRuleWithMatchType(rule, normalized); err != nil {
+			return "", fmt.Errorf("adHocKeywords[%d]: %q is not a valid %s pattern: %s", i, keyword, normalized, keywordscanner.CompileErrorMessage(err))
+		}
+	}
+	return normalized, nil
+}
+
+// campaignKeywordRules are an HTTP keyword campaign's keyword set rules and ad hoc keywords,
+// compiled with their match types
+type campaignKeywordRules struct {
+	rules []keywordscanner.CompiledKeywordRule
+	adHoc []keywordscanner.CompiledKeywordRule
+}
+
+// loadCampaignKeywordRules compiles the rules of the campaign's keyword sets, each with its set's
+// match type, and the campaign's ad hoc keywords. Rules that no longer compile are logged and
+// skipped; sets deleted since the campaign was created have no rules.
+func loadCampaignKeywordRules(ctx context.Context, ks store.KeywordStore, querier store.Querier, params *models.HTTPKeywordCampaignParams) (*campaignKeywordRules, error) {
+	compiled := &campaignKeywordRules{}
+	for _, setID := range params.KeywordSetIDs {
+		set, err := ks.GetKeywordSetByID(ctx, querier, setID)
+		if errors.Is(err, store.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch keyword set %s: %w", setID, err)
+		}
+		rules, err := ks.GetKeywordRulesBySetID(ctx, querier, setID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch rules for keyword set %s: %w", setID, err)
+		}
+		for _, rule := range rules {
+			compiledRule, compErr := keywordscanner.CompileKeywordRuleWithMatchType(rule, set.MatchType)
+			if compErr != nil {
+				log.Printf("Error compiling rule %s of keyword set %s: %v. Will be skipped.", rule.ID, setID, compErr)
+				continue
+			}
+			compiled.rules = append(compiled.rules, compiledRule)
+		}
+	}
+	if params.AdHocKeywords != nil {
+		adHoc, err := keywordscanner.CompileAdHocKeywords(*params.AdHocKeywords, params.MatchType)
+		if err != nil {
+			return nil, err
+		}
+		compiled.adHoc = adHoc
+	}
+	return compiled, nil
+}
+
+// keywordRuleCache holds the compiled keyword rules of running campaigns, so each campaign's
+// regexes are compiled once per run rather than once per batch
+type keywordRuleCache struct {
+	mu        sync.Mutex
+	campaigns map[uuid.UUID]*campaignKeywordRules
+}
+
+func newKeywordRuleCache() *keywordRuleCache {
+	return &keywordRuleCache{campaigns: make(map[uuid.UUID]*campaignKeywordRules)}
+}
+
+// rulesFor returns the campaign's compiled rules, calling load on the first batch of a run
+func (c *keywordRuleCache) rulesFor(campaignID uuid.UUID, load func() (*campaignKeywordRules, error)) (*campaignKeywordRules, error) {
+	c.mu.Lock()
+	rules, ok := c.campaigns[campaignID]
+	c.mu.Unlock()
+	if ok {
+		return rules, nil
+	}
+	rules, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.campaigns[campaignID] = rules
+	c.mu.Unlock()
+	return rules, nil
+}
+
+// forget drops a campaign's rules once it stops running, so the next run sees rule changes
+func (c *keywordRuleCache) forget(campaignID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.campaigns, campaignID)
+}
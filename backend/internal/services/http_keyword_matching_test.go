@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/keywordscanner"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateKeywordMatching(t *testing.T) {
+	matchType, err := ValidateKeywordMatching("", []string{"v(1"})
+	require.NoError(t, err, "substring keywords are not patterns")
+	assert.Equal(t, models.KeywordMatchTypeSubstring, matchType)
+
+	matchType, err = ValidateKeywordMatching(" Regex ", []string{`nginx/1\.\d+`})
+	require.NoError(t, err)
+	assert.Equal(t, models.KeywordMatchTypeRegex, matchType)
+
+	_, err = ValidateKeywordMatching("regex", []string{"ok", "v(1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `adHocKeywords[1]: "v(1" is not a valid regex pattern: missing closing )`)
+
+	_, err = ValidateKeywordMatching("fuzzy", nil)
+	assert.EqualError(t, err, `matchType "fuzzy" must be substring, regex or word_boundary`)
+}
+
+func TestClassifyHTTPKeywordContent_MatchTypes(t *testing.T) {
+	regexSet, wordSet, deletedSet := uuid.New(), uuid.New(), uuid.New()
+	version := models.KeywordRule{ID: uuid.New(), KeywordSetID: regexSet, Pattern: `nginx/1\.\d+`, RuleType: models.KeywordRuleTypeString}
+	broken := models.KeywordRule{ID: uuid.New(), KeywordSetID: regexSet, Pattern: `v(\d+`, RuleType: models.KeywordRuleTypeString}
+	cart := models.KeywordRule{ID: uuid.New(), KeywordSetID: wordSet, Pattern: "cart", RuleType: models.KeywordRuleTypeString, Category: sql.NullString{String: "shop", Valid: true}}
+	ks := &ruleSetStore{
+		rules:      map[uuid.UUID][]models.KeywordRule{regexSet: {version, broken}, wordSet: {cart}},
+		matchTypes: map[uuid.UUID]models.KeywordMatchTypeEnum{regexSet: models.KeywordMatchTypeRegex, wordSet: models.KeywordMatchTypeWordBoundary},
+	}
+	params := &models.HTTPKeywordCampaignParams{
+		KeywordSetIDs: []uuid.UUID{regexSet, wordSet, deletedSet},
+		AdHocKeywords: &[]string{"shop", "sale"},
+		MatchType:     models.KeywordMatchTypeWordBoundary,
+	}
+	keywords, err := loadCampaignKeywordRules(context.Background(), ks, nil, params)
+	require.NoError(t, err)
+	require.Len(t, keywords.rules, 2, "rules that no longer compile are skipped")
+	require.Len(t, keywords.adHoc, 2)
+
+	scanner := keywordscanner.NewService(ks)
+	content := []byte("Server: NGINX/1.25 - Shopping Cart. Big SALE!")
+	classification, err := classifyHTTPKeywordContent(context.Background(), scanner, content, keywords)
+	require.NoError(t, err)
+	assert.Equal(t, httpStatusLeadValid, classification.Status)
+	assert.Equal(t, []string{"sale"}, *classification.FoundAdHocKeywords, "shop only appears inside Shopping")
+
+	var found []keywordscanner.RuleMatch
+	require.NoError(t, json.Unmarshal(*classification.FoundKeywordsFromSets, &found))
+	assert.Equal(t, []keywordscanner.RuleMatch{
+		{Pattern: version.Pattern, MatchType: models.KeywordMatchTypeRegex, Matched: "NGINX/1.25", KeywordSetID: regexSet, RuleID: version.ID},
+		{Pattern: "cart", MatchType: models.KeywordMatchTypeWordBoundary, Matched: "Cart", KeywordSetID: wordSet, RuleID: cart.ID, Category: "shop"},
+	}, found)
+
+	classification, err = classifyHTTPKeywordContent(context.Background(), scanner, []byte("Carts and shoppers"), keywords)
+	require.NoError(t, err)
+	assert.Equal(t, httpStatusNoKeywords, classification.Status)
+	assert.Nil(t, classification.FoundKeywordsFromSets)
+}
+
+func TestKeywordRuleCache_CompilesOncePerRun(t *testing.T) {
+	cache := newKeywordRuleCache()
+	campaignID := uuid.New()
+	loads := 0
+	load := func() (*campaignKeywordRules, error) {
+		loads++
+		return &campaignKeywordRules{}, nil
+	}
+	for i := 0; i < 3; i++ {
+		_, err := cache.rulesFor(campaignID, load)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, loads)
+
+	cache.forget(campaignID)
+	_, err := cache.rulesFor(campaignID, load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads, "the next run compiles the rules again")
+}
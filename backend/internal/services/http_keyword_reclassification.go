@@ -0,0 +1,313 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/keywordscanner"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Result statuses of keywords mode campaigns for pages that were fetched successfully
+const (
+	httpStatusLeadValid  = "lead_valid"
+	httpStatusNoKeywords = "http_valid_no_keywords"
+)
+
+// Errors returned when a campaign's results cannot be re-classified
+var (
+	ErrCampaignNotReclassifiable = errors.New("campaign results cannot be re-classified")
+	ErrCampaignRunning           = errors.New("campaign is running")
+)
+
+// httpKeywordClassification is the keyword outcome of a page that was fetched successfully
+type httpKeywordClassification struct {
+	FoundKeywordsFromSets *json.RawMessage
+	FoundAdHocKeywords    *[]string
+	Status                string
+}
+
+// classifyHTTPKeywordContent matches content against a campaign's keyword rules and ad hoc
+// keywords. Pages with any match are leads. Rule matches are stored as keywordscanner.RuleMatch
+// objects naming the pattern that matched. A scan error leaves those matches out and is
+// returned along with the rest of the classification.
+func classifyHTTPKeywordContent(ctx context.Context, scanner *keywordscanner.Service, content []byte, keywords *campaignKeywordRules) (httpKeywordClassification, error) {
+	classification := httpKeywordClassification{Status: httpStatusNoKeywords}
+	var scanErr error
+	if len(keywords.rules) > 0 {
+		ruleMatches, err := scanner.ScanRuleMatches(ctx, content, keywords.rules)
+		if err != nil {
+			scanErr = err
+		} else if len(ruleMatches) > 0 {
+			foundJSON, _ := json.Marshal(ruleMatches)
+			classification.FoundKeywordsFromSets = models.JSONRawMessagePtr(foundJSON)
+		}
+	}
+	if len(keywords.adHoc) > 0 {
+		found, err := scanner.ScanWithRules(ctx, content, keywords.adHoc)
+		if err != nil && scanErr == nil {
+			scanErr = err
+		}
+		if len(found) > 0 {
+			classification.FoundAdHocKeywords = &found
+		}
+	}
+	if classification.FoundKeywordsFromSets != nil || classification.FoundAdHocKeywords != nil {
+		classification.Status = httpStatusLeadValid
+	}
+	return classification, scanErr
+}
+
+// ReclassificationChange counts the results moved from one status to another
+type ReclassificationChange struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Count int64  `json:"count"`
+}
+
+// ReclassificationSummary reports what re-classifying a campaign's results changed
+type ReclassificationSummary struct {
+	CampaignID     uuid.UUID                `json:"campaignId"`
+	DryRun         bool                     `json:"dryRun"`      // Nothing was written
+	MatchFields    []string                 `json:"matchFields"` // Stored fields the keywords were matched against
+	Examined       int64                    `json:"examined"`    // Results of successful fetches, which were classified again
+	Skipped        int64                    `json:"skipped"`     // Results of failed fetches, which have no content to classify
+	Reclassified   int64                    `json:"reclassified"`
+	MatchesChanged int64                    `json:"matchesChanged"` // Results whose keyword matches changed, whether or not their status did
+	Changes        []ReclassificationChange `json:"changes"`
+}
+
+// HTTPKeywordReclassifier re-runs keyword classification over the stored results of an HTTP
+// keyword campaign, for when its keyword rules have changed. Pages are not fetched again: the
+// keywords are matched against the stored fields named by reclassification.matchFields.
+type HTTPKeywordReclassifier struct {
+	db            *sqlx.DB
+	campaignStore store.CampaignStore
+	keywordStore  store.KeywordStore
+	scanner       *keywordscanner.Service
+	batchSize     int
+	matchFields   []string
+}
+
+// NewHTTPKeywordReclassifier reads and updates reclassification.batchSize results at a time
+func NewHTTPKeywordReclassifier(appCfg *config.AppConfig, db *sqlx.DB, cs store.CampaignStore, ks store.KeywordStore) *HTTPKeywordReclassifier {
+	r := &HTTPKeywordReclassifier{
+		db:            db,
+		campaignStore: cs,
+		keywordStore:  ks,
+		scanner:       keywordscanner.NewService(ks),
+		batchSize:     config.DefaultReclassificationBatchSize,
+		matchFields:   []string{config.ReclassificationFieldPageTitle, config.ReclassificationFieldContentSnippet},
+	}
+	if appCfg != nil {
+		if appCfg.Reclassification.BatchSize > 0 {
+			r.batchSize = appCfg.Reclassification.BatchSize
+		}
+		var fields []string
+		for _, field := range appCfg.Reclassification.MatchFields {
+			switch field {
+			case config.ReclassificationFieldPageTitle, config.ReclassificationFieldContentSnippet, config.ReclassificationFieldResponseHeaders:
+				fields = append(fields, field)
+			default:
+				log.Printf("WARNING: ignoring unknown reclassification match field %q", field)
+			}
+		}
+		if len(fields) > 0 {
+			r.matchFields = fields
+		}
+	}
+	return r
+}
+
+func (r *HTTPKeywordReclassifier) querier() store.Querier {
+	if r.db != nil {
+		return r.db
+	}
+	return nil
+}
+
+// ReclassifyCampaign classifies the campaign's results of successful fetches again with its
+// current keyword rules and ad hoc keywords, updating those whose status or matches change unless
+// dryRun is set. It returns store.ErrNotFound when the campaign does not exist,
+// ErrCampaignNotReclassifiable when it is not a keywords mode HTTP keyword campaign and
+// ErrCampaignRunning while it is still producing results.
+func (r *HTTPKeywordReclassifier) ReclassifyCampaign(ctx context.Context, campaignID uuid.UUID, dryRun bool) (*ReclassificationSummary, error) {
+	querier := r.querier()
+	campaign, err := r.campaignStore.GetCampaignByID(ctx, querier, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.CampaignType != models.CampaignTypeHTTPKeywordValidation {
+		return nil, fmt.Errorf("%w: %s campaigns have no keyword results", ErrCampaignNotReclassifiable, campaign.CampaignType)
+	}
+	switch campaign.Status {
+	case models.CampaignStatusPending, models.CampaignStatusQueued, models.CampaignStatusRunning, models.CampaignStatusPausing:
+		return nil, fmt.Errorf("%w: results of a %s campaign cannot be re-classified", ErrCampaignRunning, campaign.Status)
+	}
+	params, err := r.campaignStore.GetHTTPKeywordParams(ctx, querier, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HTTP keyword params of campaign %s: %w", campaignID, err)
+	}
+	if isHTTPLivenessOnly(params) {
+		return nil, fmt.Errorf("%w: %s campaigns have no keywords", ErrCampaignNotReclassifiable, HTTPValidationModeLivenessOnly)
+	}
+
+	keywords, err := loadCampaignKeywordRules(ctx, r.keywordStore, querier, params)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &ReclassificationSummary{CampaignID: campaignID, DryRun: dryRun, MatchFields: r.matchFields, Changes: []ReclassificationChange{}}
+	changes := make(map[[2]string]int64)
+	filter := store.ListValidationResultsFilter{Limit: r.batchSize}
+	for {
+		batch, err := r.campaignStore.GetHTTPKeywordResultsByCampaign(ctx, querier, campaignID, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read HTTP keyword results of campaign %s: %w", campaignID, err)
+		}
+		var updated []*models.HTTPKeywordResult
+		for _, result := range batch {
+			if result.ValidationStatus != httpStatusLeadValid && result.ValidationStatus != httpStatusNoKeywords {
+				summary.Skipped++
+				continue
+			}
+			summary.Examined++
+			classification, scanErr := classifyHTTPKeywordContent(ctx, r.scanner, r.storedContent(result), keywords)
+			if scanErr != nil {
+				return nil, fmt.Errorf("failed to scan stored content of %s: %w", result.DomainName, scanErr)
+			}
+			statusChanged := classification.Status != result.ValidationStatus
+			matchesChanged := !sameJSON(classification.FoundKeywordsFromSets, result.FoundKeywordsFromSets) ||
+				!sameKeywords(classification.FoundAdHocKeywords, result.FoundAdHocKeywords)
+			if !statusChanged && !matchesChanged {
+				continue
+			}
+			if statusChanged {
+				summary.Reclassified++
+				changes[[2]string{result.ValidationStatus, classification.Status}]++
+			}
+			if matchesChanged {
+				summary.MatchesChanged++
+			}
+			reclassified := *result
+			reclassified.ValidationStatus = classification.Status
+			reclassified.FoundKeywordsFromSets = classification.FoundKeywordsFromSets
+			reclassified.FoundAdHocKeywords = classification.FoundAdHocKeywords
+			updated = append(updated, &reclassified)
+		}
+		if !dryRun {
+			if err := r.campaignStore.UpdateHTTPKeywordResultClassifications(ctx, querier, updated); err != nil {
+				return nil, fmt.Errorf("failed to update HTTP keyword results of campaign %s: %w", campaignID, err)
+			}
+		}
+		if len(batch) < filter.Limit {
+			break
+		}
+		filter.AfterDomainName = batch[len(batch)-1].DomainName
+	}
+
+	for change, count := range changes {
+		summary.Changes = append(summary.Changes, ReclassificationChange{From: change[0], To: change[1], Count: count})
+	}
+	sort.Slice(summary.Changes, func(i, j int) bool {
+		if summary.Changes[i].From != summary.Changes[j].From {
+			return summary.Changes[i].From < summary.Changes[j].From
+		}
+		return summary.Changes[i].To < summary.Changes[j].To
+	})
+	return summary, nil
+}
+
+// storedContent joins the stored fields of a result that keywords are matched against, one per line
+func (r *HTTPKeywordReclassifier) storedContent(result *models.HTTPKeywordResult) []byte {
+	var content bytes.Buffer
+	for _, field := range r.matchFields {
+		switch field {
+		case config.ReclassificationFieldPageTitle:
+			if result.PageTitle != nil {
+				content.WriteString(*result.PageTitle)
+				content.WriteByte('\n')
+			}
+		case config.ReclassificationFieldContentSnippet:
+			if result.ExtractedContentSnippet != nil {
+				content.WriteString(*result.ExtractedContentSnippet)
+				content.WriteByte('\n')
+			}
+		case config.ReclassificationFieldResponseHeaders:
+			if result.ResponseHeaders != nil {
+				writeStoredHeaders(&content, *result.ResponseHeaders)
+			}
+		}
+	}
+	return content.Bytes()
+}
+
+// writeStoredHeaders writes stored response headers as "Name: value" lines, sorted by name. Headers
+// that are not a JSON object of value lists are written as stored.
+func writeStoredHeaders(content *bytes.Buffer, raw json.RawMessage) {
+	var headers map[string][]string
+	if err := json.Unmarshal(raw, &headers); err != nil {
+		content.Write(raw)
+		content.WriteByte('\n')
+		return
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range headers[name] {
+			content.WriteString(name + ": " + value + "\n")
+		}
+	}
+}
+
+// sameJSON compares stored keyword matches by value, as the database may reformat them. Null and
+// empty lists are no matches.
+func sameJSON(a, b *json.RawMessage) bool {
+	return reflect.DeepEqual(decodeMatches(a), decodeMatches(b))
+}
+
+func decodeMatches(raw *json.RawMessage) interface{} {
+	if raw == nil {
+		return nil
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(*raw, &decoded); err != nil {
+		return string(*raw)
+	}
+	if list, ok := decoded.([]interface{}); ok && len(list) == 0 {
+		return nil
+	}
+	return decoded
+}
+
+func sameKeywords(a, b *[]string) bool {
+	var left, right []string
+	if a != nil {
+		left = *a
+	}
+	if b != nil {
+		right = *b
+	}
+	if len(left) != len(right) {
+		return false
+	}
+	for i := range left {
+		if left[i] != right[i] {
+			return false
+		}
+	}
+	return true
+}
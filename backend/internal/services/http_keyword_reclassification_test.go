@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/keywordscanner"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reclassifiedCampaignStore holds one HTTP keyword campaign and its results in domain order
+type reclassifiedCampaignStore struct {
+	store.CampaignStore
+	campaign *models.Campaign
+	params   *models.HTTPKeywordCampaignParams
+	results  []*models.HTTPKeywordResult
+	reads    int
+	updates  int
+}
+
+func (s *reclassifiedCampaignStore) GetCampaignByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Campaign, error) {
+	if id != s.campaign.ID {
+		return nil, store.ErrNotFound
+	}
+	return s.campaign, nil
+}
+
+func (s *reclassifiedCampaignStore) GetHTTPKeywordParams(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.HTTPKeywordCampaignParams, error) {
+	return s.params, nil
+}
+
+func (s *reclassifiedCampaignStore) GetHTTPKeywordResultsByCampaign(ctx context.Context, exec store.Querier, campaignID uuid.UUID, filter store.ListValidationResultsFilter) ([]*models.HTTPKeywordResult, error) {
+	s.reads++
+	batch := []*models.HTTPKeywordResult{}
+	for _, result := range s.results {
+		if result.DomainName > filter.AfterDomainName && len(batch) < filter.Limit {
+			copied := *result
+			batch = append(batch, &copied)
+		}
+	}
+	return batch, nil
+}
+
+func (s *reclassifiedCampaignStore) UpdateHTTPKeywordResultClassifications(ctx context.Context, exec store.Querier, results []*models.HTTPKeywordResult) error {
+	for _, updated := range results {
+		for i, result := range s.results {
+			if result.ID == updated.ID {
+				s.results[i] = updated
+				s.updates++
+			}
+		}
+	}
+	return nil
+}
+
+func (s *reclassifiedCampaignStore) result(domain string) *models.HTTPKeywordResult {
+	for _, result := range s.results {
+		if result.DomainName == domain {
+			return result
+		}
+	}
+	return nil
+}
+
+// ruleSetStore serves the rules of keyword sets held in memory
+type ruleSetStore struct {
+	store.KeywordStore
+	rules      map[uuid.UUID][]models.KeywordRule
+	matchTypes map[uuid.UUID]models.KeywordMatchTypeEnum
+}
+
+func (s *ruleSetStore) GetKeywordSetByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.KeywordSet, error) {
+	if _, ok := s.rules[id]; !ok {
+		return nil, store.ErrNotFound
+	}
+	return &models.KeywordSet{ID: id, IsEnabled: true, MatchType: s.matchTypes[id]}, nil
+}
+
+func (s *ruleSetStore) GetKeywordRulesBySetID(ctx context.Context, exec store.Querier, keywordSetID uuid.UUID) ([]models.KeywordRule, error) {
+	return s.rules[keywordSetID], nil
+}
+
+func newReclassificationFixture() (*reclassifiedCampaignStore, *ruleSetStore, uuid.UUID) {
+	setID := uuid.New()
+	campaign := &models.Campaign{ID: uuid.New(), CampaignType: models.CampaignTypeHTTPKeywordValidation, Status: models.CampaignStatusCompleted}
+	text := func(s string) *string { return &s }
+	casino := models.KeywordRule{ID: uuid.New(), KeywordSetID: setID, Pattern: "casino", RuleType: models.KeywordRuleTypeString}
+	matches := func(matched string) *json.RawMessage {
+		encoded, _ := json.Marshal([]keywordscanner.RuleMatch{{
+			Pattern: casino.Pattern, MatchType: models.KeywordMatchTypeSubstring, Matched: matched, KeywordSetID: setID, RuleID: casino.ID,
+		}})
+		return models.JSONRawMessagePtr(encoded)
+	}
+	cs := &reclassifiedCampaignStore{
+		campaign: campaign,
+		params:   &models.HTTPKeywordCampaignParams{CampaignID: campaign.ID, KeywordSetIDs: []uuid.UUID{setID}},
+		results: []*models.HTTPKeywordResult{
+			// Found by the old "casino" rule
+			{ID: uuid.New(), DomainName: "a.com", ValidationStatus: httpStatusLeadValid, PageTitle: text("Casino Royale"),
+				ExtractedContentSnippet: text("Play poker online"), FoundKeywordsFromSets: matches("Casino")},
+			// Has no keywords under the old rule but mentions poker
+			{ID: uuid.New(), DomainName: "b.com", ValidationStatus: httpStatusNoKeywords, ExtractedContentSnippet: text("Texas hold'em POKER night")},
+			// Matched casino and still matches nothing new
+			{ID: uuid.New(), DomainName: "c.com", ValidationStatus: httpStatusLeadValid, ExtractedContentSnippet: text("casino floor"),
+				FoundKeywordsFromSets: matches("casino")},
+			// Failed fetches have no content to classify
+			{ID: uuid.New(), DomainName: "d.com", ValidationStatus: "invalid_http_code"},
+			{ID: uuid.New(), DomainName: "e.com", ValidationStatus: httpStatusNoKeywords, ExtractedContentSnippet: text("gardening tips")},
+		},
+	}
+	ks := &ruleSetStore{rules: map[uuid.UUID][]models.KeywordRule{setID: {casino}}}
+	return cs, ks, setID
+}
+
+func TestReclassifyCampaign_AppliesChangedRulesOffline(t *testing.T) {
+	cs, ks, setID := newReclassificationFixture()
+	appCfg := &config.AppConfig{Reclassification: config.ReclassificationConfig{BatchSize: 2}}
+	reclassifier := NewHTTPKeywordReclassifier(appCfg, nil, cs, ks)
+
+	// Unchanged rules change nothing
+	summary, err := reclassifier.ReclassifyCampaign(context.Background(), cs.campaign.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), summary.Examined)
+	assert.Equal(t, int64(1), summary.Skipped)
+	assert.Zero(t, summary.Reclassified)
+	assert.Zero(t, summary.MatchesChanged)
+	assert.Zero(t, cs.updates)
+	assert.Equal(t, 3, cs.reads, "results are read in batches of reclassification.batchSize")
+
+	// The rule set now looks for poker instead of casino
+	poker := models.KeywordRule{ID: uuid.New(), KeywordSetID: setID, Pattern: "poker", RuleType: models.KeywordRuleTypeString}
+	ks.rules[setID] = []models.KeywordRule{poker}
+
+	summary, err = reclassifier.ReclassifyCampaign(context.Background(), cs.campaign.ID, true)
+	require.NoError(t, err)
+	assert.True(t, summary.DryRun)
+	assert.Equal(t, int64(2), summary.Reclassified)
+	assert.Zero(t, cs.updates, "a dry run writes nothing")
+
+	summary, err = reclassifier.ReclassifyCampaign(context.Background(), cs.campaign.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), summary.Reclassified)
+	assert.Equal(t, int64(3), summary.MatchesChanged)
+	assert.Equal(t, []ReclassificationChange{
+		{From: httpStatusNoKeywords, To: httpStatusLeadValid, Count: 1},
+		{From: httpStatusLeadValid, To: httpStatusNoKeywords, Count: 1},
+	}, summary.Changes)
+	assert.Equal(t, 3, cs.updates)
+
+	assert.Equal(t, httpStatusLeadValid, cs.result("a.com").ValidationStatus, "the snippet still matches")
+	var found []keywordscanner.RuleMatch
+	require.NoError(t, json.Unmarshal(*cs.result("a.com").FoundKeywordsFromSets, &found))
+	assert.Equal(t, []keywordscanner.RuleMatch{{
+		Pattern: "poker", MatchType: models.KeywordMatchTypeSubstring, Matched: "poker", KeywordSetID: setID, RuleID: poker.ID,
+	}}, found, "matches name the rule and the text it matched")
+	var foundB []keywordscanner.RuleMatch
+	require.NoError(t, json.Unmarshal(*cs.result("b.com").FoundKeywordsFromSets, &foundB))
+	require.Len(t, foundB, 1)
+	assert.Equal(t, "POKER", foundB[0].Matched)
+	assert.Equal(t, httpStatusLeadValid, cs.result("b.com").ValidationStatus)
+	assert.Equal(t, httpStatusNoKeywords, cs.result("c.com").ValidationStatus)
+	assert.Nil(t, cs.result("c.com").FoundKeywordsFromSets)
+	assert.Equal(t, "invalid_http_code", cs.result("d.com").ValidationStatus)
+	assert.Equal(t, httpStatusNoKeywords, cs.result("e.com").ValidationStatus)
+}
+
+func TestReclassifyCampaign_AdHocKeywordsAndHeaders(t *testing.T) {
+	cs, ks, setID := newReclassificationFixture()
+	ks.rules[setID] = nil
+	cs.params.AdHocKeywords = &[]string{"nginx"}
+	headers := json.RawMessage(`{"Server":["nginx/1.25"]}`)
+	cs.result("e.com").ResponseHeaders = &headers
+
+	summary, err := NewHTTPKeywordReclassifier(nil, nil, cs, ks).ReclassifyCampaign(context.Background(), cs.campaign.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{config.ReclassificationFieldPageTitle, config.ReclassificationFieldContentSnippet}, summary.MatchFields)
+	assert.Equal(t, httpStatusNoKeywords, cs.result("e.com").ValidationStatus, "headers are not matched by default")
+
+	appCfg := &config.AppConfig{Reclassification: config.ReclassificationConfig{
+		MatchFields: []string{config.ReclassificationFieldResponseHeaders, "body"},
+	}}
+	summary, err = NewHTTPKeywordReclassifier(appCfg, nil, cs, ks).ReclassifyCampaign(context.Background(), cs.campaign.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{config.ReclassificationFieldResponseHeaders}, summary.MatchFields)
+	assert.Equal(t, httpStatusLeadValid, cs.result("e.com").ValidationStatus)
+	assert.Equal(t, []string{"nginx"}, *cs.result("e.com").FoundAdHocKeywords)
+}
+
+func TestReclassifyCampaign_RejectsCampaignsItCannotReclassify(t *testing.T) {
+	cs, ks, _ := newReclassificationFixture()
+	reclassifier := NewHTTPKeywordReclassifier(nil, nil, cs, ks)
+
+	_, err := reclassifier.ReclassifyCampaign(context.Background(), uuid.New(), false)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	for _, status := range []models.CampaignStatusEnum{models.CampaignStatusQueued, models.CampaignStatusRunning} {
+		cs.campaign.Status = status
+		_, err = reclassifier.ReclassifyCampaign(context.Background(), cs.campaign.ID, false)
+		assert.ErrorIs(t, err, ErrCampaignRunning, fmt.Sprintf("status %s", status))
+	}
+
+	cs.campaign.Status = models.CampaignStatusPaused
+	cs.params.ValidationMode = HTTPValidationModeLivenessOnly
+	_, err = reclassifier.ReclassifyCampaign(context.Background(), cs.campaign.ID, false)
+	assert.ErrorIs(t, err, ErrCampaignNotReclassifiable)
+
+	cs.campaign.CampaignType = models.CampaignTypeDNSValidation
+	_, err = reclassifier.ReclassifyCampaign(context.Background(), cs.campaign.ID, false)
+	assert.ErrorIs(t, err, ErrCampaignNotReclassifiable)
+	assert.Zero(t, cs.updates)
+}
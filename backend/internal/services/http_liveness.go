@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fntelecomllc/studio/backend/internal/circuitbreaker"
+	"github.com/fntelecomllc/studio/backend/internal/httpvalidator"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// HTTP keyword campaign validation modes. In keywords mode (the default) successful responses are
+// scanned for the campaign's keywords; liveness_only campaigns classify each domain on reachability
+// and status code alone and never read response bodies.
+const (
+	HTTPValidationModeKeywords     = "keywords"
+	HTTPValidationModeLivenessOnly = "liveness_only"
+)
+
+// Result statuses of liveness_only campaigns
+const (
+	httpLivenessStatusValid   = "http_valid"
+	httpLivenessStatusInvalid = "http_invalid"
+)
+
+// httpStatusCertPinMismatch is the result status of a domain whose target or proxy presented a
+// certificate matching none of the persona's pins, in either validation mode
+const httpStatusCertPinMismatch = "cert_pin_mismatch"
+
+// normalizeHTTPValidationMode checks a campaign's validation mode against its keyword settings and
+// returns the mode to store. Keyword campaigns need keywords; liveness_only campaigns must have none.
+func normalizeHTTPValidationMode(mode string, keywordSetIDs []uuid.UUID, adHocKeywords []string) (string, error) {
+	hasKeywords := len(keywordSetIDs) > 0 || len(adHocKeywords) > 0
+	switch normalized := strings.ToLower(strings.TrimSpace(mode)); normalized {
+	case "", HTTPValidationModeKeywords:
+		if !hasKeywords {
+			return "", fmt.Errorf("keywordSetIds or adHocKeywords required")
+		}
+		return HTTPValidationModeKeywords, nil
+	case HTTPValidationModeLivenessOnly:
+		if hasKeywords {
+			return "", fmt.Errorf("validation mode %q does not take keywordSetIds or adHocKeywords", HTTPValidationModeLivenessOnly)
+		}
+		return normalized, nil
+	default:
+		return "", fmt.Errorf("unknown HTTP validation mode %q", mode)
+	}
+}
+
+// isHTTPLivenessOnly reports whether the campaign skips keyword matching
+func isHTTPLivenessOnly(params *models.HTTPKeywordCampaignParams) bool {
+	return params != nil && strings.EqualFold(strings.TrimSpace(params.ValidationMode), HTTPValidationModeLivenessOnly)
+}
+
+// httpLivenessStatus classifies a liveness_only result. Cancelled and circuit-open attempts say
+// nothing about the domain and keep their statuses.
+func httpLivenessStatus(result *httpvalidator.ValidationResult) string {
+	switch {
+	case result == nil:
+		return "processing_failed_before_http"
+	case result.Status == "ErrorCancelled":
+		return "cancelled_during_processing"
+	case result.Status == circuitbreaker.StatusCircuitOpen:
+		return circuitbreaker.StatusCircuitOpen
+	case result.Status == httpvalidator.StatusCertPinMismatch:
+		return httpStatusCertPinMismatch
+	case result.IsSuccess:
+		return httpLivenessStatusValid
+	default:
+		return httpLivenessStatusInvalid
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/httpvalidator"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// livenessCampaignStore holds one running HTTP keyword campaign and records the results it saves
+type livenessCampaignStore struct {
+	store.CampaignStore
+	campaign *models.Campaign
+	source   *models.Campaign
+	params   *models.HTTPKeywordCampaignParams
+	domains  []string
+	results  []*models.HTTPKeywordResult
+}
+
+func (m *livenessCampaignStore) GetCampaignByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Campaign, error) {
+	if id == m.source.ID {
+		return m.source, nil
+	}
+	return m.campaign, nil
+}
+
+func (m *livenessCampaignStore) GetHTTPKeywordParams(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.HTTPKeywordCampaignParams, error) {
+	return m.params, nil
+}
+
+func (m *livenessCampaignStore) CountDNSValidationResults(ctx context.Context, exec store.Querier, campaignID uuid.UUID, onlyValid bool) (int64, error) {
+	return int64(len(m.domains)), nil
+}
+
+func (m *livenessCampaignStore) GetDomainsForHTTPValidation(ctx context.Context, exec store.Querier, httpKeywordCampaignID uuid.UUID, sourceCampaignID uuid.UUID, limit int, lastDomainName string) ([]*models.DNSValidationResult, error) {
+	batch := make([]*models.DNSValidationResult, 0, len(m.domains))
+	for _, name := range m.domains {
+		batch = append(batch, &models.DNSValidationResult{ID: uuid.New(), DomainName: name})
+	}
+	return batch, nil
+}
+
+func (m *livenessCampaignStore) GetHTTPKeywordAttempts(ctx context.Context, exec store.Querier, campaignID uuid.UUID, domainNames []string) (map[string]int, error) {
+	return map[string]int{}, nil
+}
+
+func (m *livenessCampaignStore) CreateHTTPKeywordResults(ctx context.Context, exec store.Querier, results []*models.HTTPKeywordResult) error {
+	m.results = append(m.results, results...)
+	return nil
+}
+
+func (m *livenessCampaignStore) UpdateCampaign(ctx context.Context, exec store.Querier, campaign *models.Campaign) error {
+	return nil
+}
+
+type livenessPersonaStore struct {
+	store.PersonaStore
+	persona *models.Persona
+}
+
+func (m *livenessPersonaStore) GetPersonaByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Persona, error) {
+	return m.persona, nil
+}
+
+func TestNormalizeHTTPValidationMode(t *testing.T) {
+	keywordSets := []uuid.UUID{uuid.New()}
+
+	mode, err := normalizeHTTPValidationMode("", keywordSets, nil)
+	require.NoError(t, err)
+	assert.Equal(t, HTTPValidationModeKeywords, mode)
+	_, err = normalizeHTTPValidationMode(HTTPValidationModeKeywords, nil, nil)
+	assert.Error(t, err, "keyword campaigns need keywords")
+
+	mode, err = normalizeHTTPValidationMode(" Liveness_Only ", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, HTTPValidationModeLivenessOnly, mode)
+	_, err = normalizeHTTPValidationMode(HTTPValidationModeLivenessOnly, keywordSets, nil)
+	assert.Error(t, err)
+	_, err = normalizeHTTPValidationMode(HTTPValidationModeLivenessOnly, nil, []string{"login"})
+	assert.Error(t, err)
+
+	_, err = normalizeHTTPValidationMode("status_only", nil, nil)
+	assert.Error(t, err)
+}
+
+func TestProcessHTTPKeywordCampaignBatch_LivenessOnly(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.Host, "localhost") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><title>Welcome</title><body>login</body></html>"))
+	}))
+	defer server.Close()
+	up := strings.TrimPrefix(server.URL, "https://")
+	down := strings.Replace(up, "127.0.0.1", "localhost", 1)
+
+	appCfg := &config.AppConfig{}
+	appCfg.HTTPValidator.AllowInsecureTLS = true
+	appCfg.HTTPValidator.RequestTimeout = 5 * time.Second
+	source := &models.Campaign{ID: uuid.New(), CampaignType: models.CampaignTypeDNSValidation, Status: models.CampaignStatusCompleted}
+	persona := &models.Persona{ID: uuid.New(), Name: "liveness", PersonaType: models.PersonaTypeHTTP, IsEnabled: true,
+		ConfigDetails: []byte(`{"userAgent":"DomainFlow/1.0"}`)}
+	campaignStore := &livenessCampaignStore{
+		campaign: &models.Campaign{ID: uuid.New(), CampaignType: models.CampaignTypeHTTPKeywordValidation, Status: models.CampaignStatusRunning},
+		source:   source,
+		params: &models.HTTPKeywordCampaignParams{SourceCampaignID: source.ID, PersonaIDs: []uuid.UUID{persona.ID},
+			ValidationMode: HTTPValidationModeLivenessOnly},
+		domains: []string{up, down},
+	}
+	// No keyword store or scanner: a liveness-only batch must not reach for either
+	svc := NewHTTPKeywordCampaignService(nil, campaignStore, &livenessPersonaStore{persona: persona}, nil, nil, nil, nil,
+		httpvalidator.NewHTTPValidator(appCfg), nil, nil, appCfg)
+
+	done, processed, err := svc.ProcessHTTPKeywordCampaignBatch(context.Background(), campaignStore.campaign.ID)
+	require.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, 2, processed)
+
+	statuses := map[string]*models.HTTPKeywordResult{}
+	for _, result := range campaignStore.results {
+		statuses[result.DomainName] = result
+	}
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "http_valid", statuses[up].ValidationStatus)
+	require.NotNil(t, statuses[up].HTTPStatusCode)
+	assert.EqualValues(t, http.StatusOK, *statuses[up].HTTPStatusCode)
+	assert.Equal(t, "http_invalid", statuses[down].ValidationStatus)
+	require.NotNil(t, statuses[down].HTTPStatusCode)
+	assert.EqualValues(t, http.StatusServiceUnavailable, *statuses[down].HTTPStatusCode)
+	for _, result := range campaignStore.results {
+		assert.Nil(t, result.FoundKeywordsFromSets)
+		assert.Nil(t, result.FoundAdHocKeywords)
+		assert.Nil(t, result.PageTitle, "the body is not read")
+		assert.Nil(t, result.ContentHash)
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+)
+
+// httpResumeCorrectionMetadataKey is the HTTP keyword params metadata key holding the last resume pointer correction
+const httpResumeCorrectionMetadataKey = "resumePointCorrection"
+
+// HTTPResumePointCorrection records a resume pointer that no longer matched the campaign's source domains
+type HTTPResumePointCorrection struct {
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	CorrectedAt time.Time `json:"correctedAt"`
+}
+
+// HTTPResumePoint is where an HTTP keyword campaign continues from when it is resumed
+type HTTPResumePoint struct {
+	LastProcessedDomainName string                     `json:"lastProcessedDomainName"`
+	LastCorrection          *HTTPResumePointCorrection `json:"lastCorrection,omitempty"`
+}
+
+// HTTPResumePointFromParams reads the resume pointer and its last correction from an HTTP keyword campaign's params
+func HTTPResumePointFromParams(params *models.HTTPKeywordCampaignParams) *HTTPResumePoint {
+	if params == nil {
+		return nil
+	}
+	point := &HTTPResumePoint{}
+	if params.LastProcessedDomainName != nil {
+		point.LastProcessedDomainName = *params.LastProcessedDomainName
+	}
+	if params.Metadata != nil {
+		var metadata map[string]json.RawMessage
+		if err := json.Unmarshal(*params.Metadata, &metadata); err == nil {
+			if raw, ok := metadata[httpResumeCorrectionMetadataKey]; ok {
+				var correction HTTPResumePointCorrection
+				if json.Unmarshal(raw, &correction) == nil {
+					point.LastCorrection = &correction
+				}
+			}
+		}
+	}
+	return point
+}
+
+// verifyHTTPResumePoint checks that the domain an HTTP keyword campaign resumes after still exists among
+// its source DNS results. If it does not (e.g. the results were deleted and re-created), the pointer is
+// moved back to the last point before which every valid domain is finished, so no domain is skipped,
+// and the correction is saved with the params. It reports whether the pointer was changed.
+func verifyHTTPResumePoint(ctx context.Context, campaignStore store.CampaignStore, exec store.Querier, campaignID uuid.UUID, params *models.HTTPKeywordCampaignParams, now time.Time) (bool, error) {
+	if params.LastProcessedDomainName == nil || *params.LastProcessedDomainName == "" {
+		return false, nil
+	}
+	pointer := *params.LastProcessedDomainName
+	exists, err := campaignStore.HTTPResumePointExists(ctx, exec, params.SourceCampaignID, pointer)
+	if err != nil {
+		return false, fmt.Errorf("failed to check resume pointer for campaign %s: %w", campaignID, err)
+	}
+	if exists {
+		return false, nil
+	}
+
+	safePoint, err := campaignStore.GetSafeHTTPResumePoint(ctx, exec, campaignID, params.SourceCampaignID, pointer)
+	if err != nil {
+		return false, fmt.Errorf("failed to recompute resume pointer for campaign %s: %w", campaignID, err)
+	}
+	log.Printf("HTTP keyword campaign %s: resume pointer '%s' is no longer among the source domains of campaign %s; resuming after '%s' instead",
+		campaignID, pointer, params.SourceCampaignID, safePoint)
+
+	params.LastProcessedDomainName = nil
+	if safePoint != "" {
+		params.LastProcessedDomainName = models.StringPtr(safePoint)
+	}
+	metadata := map[string]json.RawMessage{}
+	if params.Metadata != nil {
+		_ = json.Unmarshal(*params.Metadata, &metadata)
+	}
+	if encoded, err := json.Marshal(HTTPResumePointCorrection{From: pointer, To: safePoint, CorrectedAt: now}); err == nil {
+		metadata[httpResumeCorrectionMetadataKey] = encoded
+	}
+	if encoded, err := json.Marshal(metadata); err == nil {
+		params.Metadata = models.JSONRawMessagePtr(encoded)
+	}
+
+	if err := campaignStore.CreateHTTPKeywordParams(ctx, exec, params); err != nil {
+		return true, fmt.Errorf("failed to save corrected resume pointer for campaign %s: %w", campaignID, err)
+	}
+	return true, nil
+}
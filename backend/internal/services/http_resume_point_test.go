@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resumePointStore keeps the DNS-valid source domains of one HTTP keyword campaign and which of them
+// have a final HTTP result, answering the resume queries the way the Postgres store does
+type resumePointStore struct {
+	store.CampaignStore
+	source   []string
+	finished map[string]bool
+	saved    []*models.HTTPKeywordCampaignParams
+}
+
+func (m *resumePointStore) HTTPResumePointExists(ctx context.Context, exec store.Querier, sourceCampaignID uuid.UUID, domainName string) (bool, error) {
+	for _, name := range m.source {
+		if name == domainName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *resumePointStore) GetSafeHTTPResumePoint(ctx context.Context, exec store.Querier, httpKeywordCampaignID uuid.UUID, sourceCampaignID uuid.UUID, pointer string) (string, error) {
+	safePoint := ""
+	for _, name := range m.source {
+		if name >= pointer || !m.finished[name] {
+			break
+		}
+		safePoint = name
+	}
+	return safePoint, nil
+}
+
+func (m *resumePointStore) GetDomainsForHTTPValidation(ctx context.Context, exec store.Querier, httpKeywordCampaignID uuid.UUID, sourceCampaignID uuid.UUID, limit int, lastDomainName string) ([]*models.DNSValidationResult, error) {
+	var batch []*models.DNSValidationResult
+	for _, name := range m.source {
+		if name > lastDomainName && !m.finished[name] && len(batch) < limit {
+			batch = append(batch, &models.DNSValidationResult{DomainName: name})
+		}
+	}
+	return batch, nil
+}
+
+func (m *resumePointStore) CreateHTTPKeywordParams(ctx context.Context, exec store.Querier, params *models.HTTPKeywordCampaignParams) error {
+	saved := *params
+	m.saved = append(m.saved, &saved)
+	return nil
+}
+
+func newResumePointStore(domains ...string) *resumePointStore {
+	sort.Strings(domains)
+	return &resumePointStore{source: domains, finished: map[string]bool{}}
+}
+
+func TestVerifyHTTPResumePoint_PointerRemovedSkipsNothing(t *testing.T) {
+	ctx := context.Background()
+	campaignID := uuid.New()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	// a-c were processed before the pause, but c only got a retryable failure; d was the pointer.
+	// The source results were then re-created without d.
+	campaignStore := newResumePointStore("a.com", "b.com", "c.com", "e.com", "f.com", "g.com")
+	campaignStore.finished["a.com"], campaignStore.finished["b.com"] = true, true
+	params := &models.HTTPKeywordCampaignParams{CampaignID: campaignID, SourceCampaignID: uuid.New(), LastProcessedDomainName: models.StringPtr("d.com")}
+
+	corrected, err := verifyHTTPResumePoint(ctx, campaignStore, nil, campaignID, params, now)
+	require.NoError(t, err)
+	assert.True(t, corrected)
+	require.NotNil(t, params.LastProcessedDomainName)
+	assert.Equal(t, "b.com", *params.LastProcessedDomainName, "the pointer moves back before the unfinished domain")
+	require.Len(t, campaignStore.saved, 1, "the corrected pointer is saved")
+	assert.Equal(t, "b.com", *campaignStore.saved[0].LastProcessedDomainName)
+
+	resumePoint := HTTPResumePointFromParams(params)
+	require.NotNil(t, resumePoint.LastCorrection)
+	assert.Equal(t, HTTPResumePointCorrection{From: "d.com", To: "b.com", CorrectedAt: now}, *resumePoint.LastCorrection)
+
+	var processed []string
+	cursor := *params.LastProcessedDomainName
+	for {
+		batch, err := campaignStore.GetDomainsForHTTPValidation(ctx, nil, campaignID, params.SourceCampaignID, 2, cursor)
+		require.NoError(t, err)
+		if len(batch) == 0 {
+			break
+		}
+		for _, result := range batch {
+			processed = append(processed, result.DomainName)
+			campaignStore.finished[result.DomainName] = true
+			cursor = result.DomainName
+		}
+	}
+	assert.Equal(t, []string{"c.com", "e.com", "f.com", "g.com"}, processed, "every unfinished domain is processed once")
+}
+
+func TestVerifyHTTPResumePoint_Unchanged(t *testing.T) {
+	ctx := context.Background()
+	campaignStore := newResumePointStore("a.com", "b.com")
+
+	params := &models.HTTPKeywordCampaignParams{SourceCampaignID: uuid.New(), LastProcessedDomainName: models.StringPtr("a.com")}
+	corrected, err := verifyHTTPResumePoint(ctx, campaignStore, nil, uuid.New(), params, time.Now())
+	require.NoError(t, err)
+	assert.False(t, corrected)
+	assert.Equal(t, "a.com", *params.LastProcessedDomainName)
+
+	fresh := &models.HTTPKeywordCampaignParams{SourceCampaignID: uuid.New()}
+	corrected, err = verifyHTTPResumePoint(ctx, campaignStore, nil, uuid.New(), fresh, time.Now())
+	require.NoError(t, err)
+	assert.False(t, corrected, "a campaign that has not started has nothing to verify")
+	assert.Empty(t, campaignStore.saved)
+	assert.Nil(t, HTTPResumePointFromParams(fresh).LastCorrection)
+
+	// With nothing finished below the missing pointer the campaign restarts from the beginning
+	params.LastProcessedDomainName = models.StringPtr("zz.com")
+	corrected, err = verifyHTTPResumePoint(ctx, campaignStore, nil, uuid.New(), params, time.Now())
+	require.NoError(t, err)
+	assert.True(t, corrected)
+	assert.Nil(t, params.LastProcessedDomainName)
+}
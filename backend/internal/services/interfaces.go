@@ -3,6 +3,7 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/fntelecomllc/studio/backend/internal/models"
 	"github.com/fntelecomllc/studio/backend/internal/store" // Added for store.ListCampaignsFilter
@@ -12,7 +13,9 @@ import (
 // --- Campaign Update Request DTOs ---
 type UpdateCampaignRequest struct {
 	Name                       *string                    `json:"name,omitempty"`
+	Tags                       *[]string                  `json:"tags,omitempty" validate:"omitempty,max=20,dive,min=1,max=64"`
 	Status                     *models.CampaignStatusEnum `json:"status,omitempty"`
+	Metadata                   *json.RawMessage           `json:"metadata,omitempty"`
 	SourceGenerationCampaignID *uuid.UUID                 `json:"sourceGenerationCampaignId,omitempty"`
 	SourceDnsCampaignID        *uuid.UUID                 `json:"sourceDnsCampaignId,omitempty"`
 	KeywordSetIDs              *[]uuid.UUID               `json:"keywordSetIds,omitempty"`
@@ -25,6 +28,7 @@ type UpdateCampaignRequest struct {
 	BatchSize                  *int                       `json:"batchSize,omitempty"`
 	RetryAttempts              *int                       `json:"retryAttempts,omitempty"`
 	TargetHTTPPorts            *[]int                     `json:"targetHttpPorts,omitempty"`
+	PatternType                *string                    `json:"patternType,omitempty"`
 	NumDomainsToGenerate       *int64                     `json:"numDomainsToGenerate,omitempty"`
 	VariableLength             *int                       `json:"variableLength,omitempty"`
 	CharacterSet               *string                    `json:"characterSet,omitempty"`
@@ -39,6 +43,8 @@ type CreateCampaignRequest struct {
 	Name         string `json:"name" validate:"required"`
 	Description  string `json:"description,omitempty"`
 	UserID       uuid.UUID `json:"userId,omitempty"`
+	Tags         []string  `json:"tags,omitempty" validate:"omitempty,max=20,dive,min=1,max=64"`
+	AutoRetry    bool      `json:"autoRetry,omitempty"` // Re-run the campaign automatically if it fails
 
 	// Domain Generation specific fields
 	DomainGenerationParams *DomainGenerationParams `json:"domainGenerationParams,omitempty"`
@@ -57,6 +63,8 @@ type DomainGenerationParams struct {
 	ConstantString       string `json:"constantString" validate:"required"`
 	TLD                  string `json:"tld" validate:"required"`
 	NumDomainsToGenerate int64  `json:"numDomainsToGenerate,omitempty" validate:"omitempty,gte=0"`
+	// Create and start this DNS validation campaign on the generated domains once generation completes
+	DNSPromotion *models.DNSPromotion `json:"dnsPromotion,omitempty"`
 }
 
 type DnsValidationParams struct {
@@ -66,6 +74,10 @@ type DnsValidationParams struct {
 	ProcessingSpeedPerMinute   int         `json:"processingSpeedPerMinute,omitempty" validate:"gte=0"`
 	BatchSize                  int         `json:"batchSize,omitempty" validate:"gt=0"`
 	RetryAttempts              int         `json:"retryAttempts,omitempty" validate:"gte=0"`
+	DeduplicateDomains         bool        `json:"deduplicateDomains,omitempty"`
+	ConsensusMode              string      `json:"consensusMode,omitempty" validate:"omitempty,oneof=first majority unanimous"`
+	ConsensusQuorum            int         `json:"consensusQuorum,omitempty" validate:"gte=0"`
+	ExpectedRecords            *models.DNSExpectedRecords `json:"expectedRecords,omitempty"`
 }
 
 type HttpKeywordParams struct {
@@ -80,6 +92,9 @@ type HttpKeywordParams struct {
 	BatchSize                int         `json:"batchSize,omitempty" validate:"gt=0"`
 	RetryAttempts            int         `json:"retryAttempts,omitempty" validate:"gte=0"`
 	TargetHTTPPorts          []int       `json:"targetHttpPorts,omitempty" validate:"omitempty,dive,gt=0,lte=65535"`
+	DeduplicateDomains       bool        `json:"deduplicateDomains,omitempty"`
+	ValidationMode           string      `json:"validationMode,omitempty" validate:"omitempty,oneof=keywords liveness_only"`
+	MatchType                string      `json:"matchType,omitempty" validate:"omitempty,oneof=substring regex word_boundary"`
 }
 
 // --- Campaign Creation Request DTOs (specific to each campaign type) ---
@@ -93,6 +108,9 @@ type CreateDomainGenerationCampaignRequest struct {
 	TLD                  string `json:"tld" validate:"required"`
 	NumDomainsToGenerate int64     `json:"numDomainsToGenerate,omitempty" validate:"omitempty,gte=0"`
 	UserID               uuid.UUID `json:"userId,omitempty"`
+	Tags                 []string  `json:"tags,omitempty" validate:"omitempty,max=20,dive,min=1,max=64"`
+	AutoRetry            bool      `json:"autoRetry,omitempty"`
+	DNSPromotion         *models.DNSPromotion `json:"dnsPromotion,omitempty"` // DNS validation campaign to start on completion
 }
 
 type CreateDNSValidationCampaignRequest struct {
@@ -103,7 +121,14 @@ type CreateDNSValidationCampaignRequest struct {
 	ProcessingSpeedPerMinute   int         `json:"processingSpeedPerMinute,omitempty" validate:"gte=0"`
 	BatchSize                  int         `json:"batchSize,omitempty" validate:"gt=0"`
 	RetryAttempts              int         `json:"retryAttempts,omitempty" validate:"gte=0"`
+	DeduplicateDomains         bool        `json:"deduplicateDomains,omitempty"`
+	ConsensusMode              string      `json:"consensusMode,omitempty" validate:"omitempty,oneof=first majority unanimous"`
+	ConsensusQuorum            int         `json:"consensusQuorum,omitempty" validate:"gte=0"`
+	ExpectedRecords            *models.DNSExpectedRecords `json:"expectedRecords,omitempty"`
 	UserID                     uuid.UUID   `json:"userId,omitempty"`
+	Tags                       []string    `json:"tags,omitempty" validate:"omitempty,max=20,dive,min=1,max=64"`
+	AutoRetry                  bool        `json:"autoRetry,omitempty"`
+	PromotedFromCampaignID     *uuid.UUID  `json:"-"` // Set when a completed generation campaign's DNS promotion creates the campaign
 }
 
 type CreateHTTPKeywordCampaignRequest struct {
@@ -119,7 +144,12 @@ type CreateHTTPKeywordCampaignRequest struct {
 	BatchSize                int         `json:"batchSize,omitempty" validate:"gt=0"`
 	RetryAttempts            int         `json:"retryAttempts,omitempty" validate:"gte=0"`
 	TargetHTTPPorts          []int       `json:"targetHttpPorts,omitempty" validate:"omitempty,dive,gt=0,lte=65535"`
+	DeduplicateDomains       bool        `json:"deduplicateDomains,omitempty"`
+	ValidationMode           string      `json:"validationMode,omitempty" validate:"omitempty,oneof=keywords liveness_only"`
+	MatchType                string      `json:"matchType,omitempty" validate:"omitempty,oneof=substring regex word_boundary"`
 	UserID                   uuid.UUID   `json:"userId,omitempty"`
+	Tags                     []string    `json:"tags,omitempty" validate:"omitempty,max=20,dive,min=1,max=64"`
+	AutoRetry                bool        `json:"autoRetry,omitempty"`
 }
 
 // --- Campaign Result Response DTOs ---
@@ -142,6 +172,20 @@ type HTTPKeywordResultsResponse struct {
 	TotalCount int64                      `json:"totalCount"`
 }
 
+// CampaignJobHistoryEntry is a campaign job annotated with timing information.
+type CampaignJobHistoryEntry struct {
+	models.CampaignJob
+	TotalDurationMs       int64  `json:"totalDurationMs"`                 // Time from job creation to its last update
+	LastAttemptDurationMs *int64 `json:"lastAttemptDurationMs,omitempty"` // Duration of the most recent attempt, still growing while processing
+}
+
+type CampaignJobHistoryResponse struct {
+	Data       []CampaignJobHistoryEntry `json:"data"`
+	Limit      int                       `json:"limit"`
+	Offset     int                       `json:"offset"`
+	TotalCount int64                     `json:"totalCount"` // Jobs of the campaign across all pages
+}
+
 // --- Service Interfaces ---
 
 // CampaignOrchestratorService defines the interface for managing the lifecycle of all campaigns.
@@ -156,7 +200,15 @@ type CampaignOrchestratorService interface {
 
 	GetCampaignDetails(ctx context.Context, campaignID uuid.UUID) (*models.Campaign, interface{}, error) // Stays as interface{} for flexibility at orchestrator level
 	GetCampaignStatus(ctx context.Context, campaignID uuid.UUID) (models.CampaignStatusEnum, *float64, error)
+	// ListCampaignStatuses returns the status of each of the campaigns that exists in one query; a
+	// non-empty userID leaves out campaigns of other users
+	ListCampaignStatuses(ctx context.Context, campaignIDs []uuid.UUID, userID string) ([]store.CampaignStatusSummary, error)
 	ListCampaigns(ctx context.Context, filter store.ListCampaignsFilter) ([]models.Campaign, int64, error)
+	ListCampaignJobs(ctx context.Context, campaignID uuid.UUID, limit, offset int) (*CampaignJobHistoryResponse, error)
+	ListDomainDedupDecisions(ctx context.Context, campaignID uuid.UUID, limit, offset int) ([]*models.DomainDedupDecision, error)
+	ListCampaignStatusHistory(ctx context.Context, campaignID uuid.UUID, limit, offset int) ([]CampaignStatusTransition, error)
+	// GetCampaignStats gathers result statistics, returning a partial response if ctx ends first
+	GetCampaignStats(ctx context.Context, campaignID uuid.UUID) (*CampaignStatsResponse, error)
 
 	// Methods for fetching campaign results
 	GetGeneratedDomainsForCampaign(ctx context.Context, campaignID uuid.UUID, limit int, cursor int64) (*GeneratedDomainsResponse, error)
@@ -204,4 +256,6 @@ type HTTPKeywordCampaignService interface {
 // CampaignWorkerService manages the pool of background workers that process campaign jobs.
 type CampaignWorkerService interface {
 	StartWorkers(ctx context.Context, numWorkers int)
+	// Health reports whether the workers are running and making progress on queued jobs.
+	Health(ctx context.Context) WorkerHealth
 }
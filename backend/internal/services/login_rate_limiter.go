@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+)
+
+// RateLimitActionLogin is the auth.rate_limits action failed logins are counted under
+const RateLimitActionLogin = "login"
+
+// Scopes of a LoginRateLimitError
+const (
+	RateLimitScopeIP      = "ip"
+	RateLimitScopeAccount = "account"
+)
+
+// LoginRateLimitError is returned for an attempt made while its client IP or account is blocked
+type LoginRateLimitError struct {
+	Scope        string // RateLimitScopeIP or RateLimitScopeAccount
+	BlockedUntil time.Time
+}
+
+func (e *LoginRateLimitError) Error() string {
+	return fmt.Sprintf("too many failed attempts from this %s; blocked until %s", e.Scope, e.BlockedUntil.Format(time.RFC3339))
+}
+
+// LoginRateLimiter limits failed attempts at an action, such as logging in, per client IP and per
+// account in auth.rate_limits. Each has its own threshold and either being blocked refuses the
+// attempt: an attacker rotating addresses is stopped by the account's count, and users behind one
+// NAT address are not blocked by each other's typos before the larger per-IP threshold.
+type LoginRateLimiter struct {
+	store store.RateLimitStore
+	cfg   config.LoginRateLimitConfig
+	now   func() time.Time
+}
+
+// NewLoginRateLimiter creates a limiter with the windows and thresholds of cfg. Zero values take
+// the config defaults.
+func NewLoginRateLimiter(rateLimitStore store.RateLimitStore, cfg config.LoginRateLimitConfig) *LoginRateLimiter {
+	if cfg.WindowSeconds <= 0 {
+		cfg.WindowSeconds = config.DefaultLoginRateLimitWindowSeconds
+	}
+	if cfg.MaxFailuresPerIP <= 0 {
+		cfg.MaxFailuresPerIP = config.DefaultLoginMaxFailuresPerIP
+	}
+	if cfg.MaxFailuresPerAccount <= 0 {
+		cfg.MaxFailuresPerAccount = config.DefaultLoginMaxFailuresPerAccount
+	}
+	if cfg.BlockSeconds <= 0 {
+		cfg.BlockSeconds = config.DefaultLoginRateLimitBlockSeconds
+	}
+	return &LoginRateLimiter{store: rateLimitStore, cfg: cfg, now: time.Now}
+}
+
+// AccountRateLimitKey is the rate limit identifier of the account with userID, the same
+// identifier an administrator's unlock clears
+func AccountRateLimitKey(userID uuid.UUID) string {
+	return userID.String()
+}
+
+// UnknownAccountRateLimitKey is the rate limit identifier of an email no account has. Counting
+// such emails like accounts keeps the responses for unknown and existing accounts alike.
+func UnknownAccountRateLimitKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// Check returns a *LoginRateLimitError when ipAddress or account is blocked from action. An empty
+// ipAddress or account is not checked.
+func (l *LoginRateLimiter) Check(ctx context.Context, action, ipAddress, account string) error {
+	now := l.now()
+	for _, key := range []struct{ scope, identifier string }{
+		{RateLimitScopeIP, ipAddress},
+		{RateLimitScopeAccount, account},
+	} {
+		if key.identifier == "" {
+			continue
+		}
+		limit, err := l.store.GetRateLimit(ctx, nil, key.identifier, action)
+		if errors.Is(err, store.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check the %s rate limit: %w", key.scope, err)
+		}
+		if limit.BlockedUntil != nil && now.Before(*limit.BlockedUntil) {
+			return &LoginRateLimitError{Scope: key.scope, BlockedUntil: *limit.BlockedUntil}
+		}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed attempt at action against ipAddress and account, blocking either
+// once its window holds its maximum number of failures
+func (l *LoginRateLimiter) RecordFailure(ctx context.Context, action, ipAddress, account string) error {
+	now := l.now()
+	window := time.Duration(l.cfg.WindowSeconds) * time.Second
+	blockedUntil := now.Add(time.Duration(l.cfg.BlockSeconds) * time.Second)
+	var errs []error
+	if ipAddress != "" {
+		if _, err := l.store.RecordRateLimitAttempt(ctx, nil, ipAddress, action, now, now.Add(-window), l.cfg.MaxFailuresPerIP, blockedUntil); err != nil {
+			errs = append(errs, fmt.Errorf("failed to count the failure against IP %s: %w", ipAddress, err))
+		}
+	}
+	if account != "" {
+		if _, err := l.store.RecordRateLimitAttempt(ctx, nil, account, action, now, now.Add(-window), l.cfg.MaxFailuresPerAccount, blockedUntil); err != nil {
+			errs = append(errs, fmt.Errorf("failed to count the failure against account %s: %w", account, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RecordSuccess forgets the account's failures at action. Those of the IP are kept, so an attacker
+// cannot clear their address's count by signing in to an account of their own.
+func (l *LoginRateLimiter) RecordSuccess(ctx context.Context, action, account string) error {
+	if account == "" {
+		return nil
+	}
+	return l.store.DeleteRateLimit(ctx, nil, account, action)
+}
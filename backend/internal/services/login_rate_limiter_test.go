@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRateLimitStore keeps rate limits in memory and counts attempts like the Postgres store
+type memoryRateLimitStore struct {
+	limits map[string]*models.RateLimit
+}
+
+func newMemoryRateLimitStore() *memoryRateLimitStore {
+	return &memoryRateLimitStore{limits: make(map[string]*models.RateLimit)}
+}
+
+func (m *memoryRateLimitStore) GetRateLimit(ctx context.Context, exec store.Querier, identifier, action string) (*models.RateLimit, error) {
+	limit, ok := m.limits[action+"/"+identifier]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	copied := *limit
+	return &copied, nil
+}
+
+func (m *memoryRateLimitStore) RecordRateLimitAttempt(ctx context.Context, exec store.Querier, identifier, action string, now, windowStart time.Time, maxAttempts int, blockedUntil time.Time) (*models.RateLimit, error) {
+	limit, ok := m.limits[action+"/"+identifier]
+	if !ok {
+		limit = &models.RateLimit{Identifier: identifier, Action: action, WindowStart: now}
+		m.limits[action+"/"+identifier] = limit
+	}
+	if limit.WindowStart.Before(windowStart) {
+		limit.Attempts, limit.WindowStart = 0, now
+	}
+	limit.Attempts++
+	if limit.Attempts >= maxAttempts {
+		limit.BlockedUntil = &blockedUntil
+	}
+	copied := *limit
+	return &copied, nil
+}
+
+func (m *memoryRateLimitStore) DeleteRateLimit(ctx context.Context, exec store.Querier, identifier, action string) error {
+	delete(m.limits, action+"/"+identifier)
+	return nil
+}
+
+func newTestLoginRateLimiter(now *time.Time) *LoginRateLimiter {
+	limiter := NewLoginRateLimiter(newMemoryRateLimitStore(), config.LoginRateLimitConfig{
+		WindowSeconds: 900, MaxFailuresPerIP: 50, MaxFailuresPerAccount: 10, BlockSeconds: 600,
+	})
+	limiter.now = func() time.Time { return *now }
+	return limiter
+}
+
+func TestLoginRateLimiter_BlocksAnAccountAttackedFromRotatingIPs(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	limiter := newTestLoginRateLimiter(&now)
+	victim := AccountRateLimitKey(uuid.New())
+
+	// Every guess comes from a fresh address, so no IP gets near its limit
+	for i := 0; i < 10; i++ {
+		ip := fmt.Sprintf("198.51.100.%d", i+1)
+		require.NoError(t, limiter.Check(ctx, RateLimitActionLogin, ip, victim), "guess %d", i+1)
+		require.NoError(t, limiter.RecordFailure(ctx, RateLimitActionLogin, ip, victim))
+	}
+
+	err := limiter.Check(ctx, RateLimitActionLogin, "198.51.100.99", victim)
+	var limited *LoginRateLimitError
+	require.ErrorAs(t, err, &limited)
+	assert.Equal(t, RateLimitScopeAccount, limited.Scope)
+	assert.Equal(t, now.Add(10*time.Minute), limited.BlockedUntil)
+
+	// Other accounts are unaffected, and the block lifts once it expires
+	assert.NoError(t, limiter.Check(ctx, RateLimitActionLogin, "198.51.100.99", AccountRateLimitKey(uuid.New())))
+	now = now.Add(10 * time.Minute)
+	assert.NoError(t, limiter.Check(ctx, RateLimitActionLogin, "198.51.100.99", victim))
+}
+
+func TestLoginRateLimiter_DoesNotBlockUsersSharingANATAddress(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	limiter := newTestLoginRateLimiter(&now)
+	const office = "203.0.113.7"
+
+	// Twenty colleagues behind one address each mistype their password twice, then sign in
+	for i := 0; i < 20; i++ {
+		account := AccountRateLimitKey(uuid.New())
+		for attempt := 0; attempt < 2; attempt++ {
+			require.NoError(t, limiter.Check(ctx, RateLimitActionLogin, office, account))
+			require.NoError(t, limiter.RecordFailure(ctx, RateLimitActionLogin, office, account))
+		}
+		require.NoError(t, limiter.Check(ctx, RateLimitActionLogin, office, account), "user %d was blocked by the others' failures", i+1)
+		require.NoError(t, limiter.RecordSuccess(ctx, RateLimitActionLogin, account))
+	}
+
+	// The address is only blocked once its own, larger threshold is reached
+	for i := 0; i < 10; i++ {
+		require.NoError(t, limiter.RecordFailure(ctx, RateLimitActionLogin, office, UnknownAccountRateLimitKey(fmt.Sprintf("user%d@example.com", i))))
+	}
+	err := limiter.Check(ctx, RateLimitActionLogin, office, AccountRateLimitKey(uuid.New()))
+	var limited *LoginRateLimitError
+	require.ErrorAs(t, err, &limited)
+	assert.Equal(t, RateLimitScopeIP, limited.Scope)
+	assert.NoError(t, limiter.Check(ctx, RateLimitActionLogin, "192.0.2.1", AccountRateLimitKey(uuid.New())))
+}
+
+func TestLoginRateLimiter_CountsFailuresPerWindow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	limiter := newTestLoginRateLimiter(&now)
+	account := UnknownAccountRateLimitKey(" Someone@Example.com ")
+	assert.Equal(t, "email:someone@example.com", account)
+
+	// Nine failures in one window and nine in the next never block
+	for window := 0; window < 2; window++ {
+		for i := 0; i < 9; i++ {
+			require.NoError(t, limiter.RecordFailure(ctx, RateLimitActionLogin, "", account))
+		}
+		assert.NoError(t, limiter.Check(ctx, RateLimitActionLogin, "", account))
+		now = now.Add(16 * time.Minute)
+	}
+
+	// A success clears the account's count
+	for i := 0; i < 9; i++ {
+		require.NoError(t, limiter.RecordFailure(ctx, RateLimitActionLogin, "", account))
+	}
+	require.NoError(t, limiter.RecordSuccess(ctx, RateLimitActionLogin, account))
+	require.NoError(t, limiter.RecordFailure(ctx, RateLimitActionLogin, "", account))
+	assert.NoError(t, limiter.Check(ctx, RateLimitActionLogin, "", account))
+}
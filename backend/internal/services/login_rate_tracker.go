@@ -0,0 +1,222 @@
+package services
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+)
+
+// LoginOutcome is the result of a login attempt as counted by LoginRateTracker
+type LoginOutcome string
+
+const (
+	LoginOutcomeSuccess LoginOutcome = "success"
+	LoginOutcomeFailure LoginOutcome = "failure" // Unknown email, wrong password or inactive account
+	LoginOutcomeLockout LoginOutcome = "lockout" // Right password on a locked account
+)
+
+// loginRateBuckets is the number of slices a window is counted in. Counts leave the window one
+// slice at a time, so the rates cover between window-window/loginRateBuckets and window.
+const loginRateBuckets = 60
+
+// LoginRates are the login outcomes counted over a tracker's window
+type LoginRates struct {
+	Successes          int64   `json:"successes"`
+	Failures           int64   `json:"failures"`
+	Lockouts           int64   `json:"lockouts"`
+	SuccessesPerMinute float64 `json:"successesPerMinute"`
+	FailuresPerMinute  float64 `json:"failuresPerMinute"`
+	LockoutsPerMinute  float64 `json:"lockoutsPerMinute"`
+	FailureRatio       float64 `json:"failureRatio"` // Failures and lockouts over all attempts, 0 without attempts
+}
+
+// IPLoginRates are the login rates of one client IP
+type IPLoginRates struct {
+	IPAddress string `json:"ipAddress"`
+	LoginRates
+}
+
+// LoginRateSnapshot reports the rolling login rates overall and for the IPs with the most failures
+type LoginRateSnapshot struct {
+	WindowSeconds int            `json:"windowSeconds"`
+	Global        LoginRates     `json:"global"`
+	TrackedIPs    int            `json:"trackedIps"`
+	TopIPs        []IPLoginRates `json:"topIps"` // By failures and lockouts, most first
+}
+
+type loginCounts struct {
+	successes, failures, lockouts int64
+}
+
+// loginRateWindow counts outcomes per time slice in a ring of loginRateBuckets slots
+type loginRateWindow struct {
+	counts   [loginRateBuckets]loginCounts
+	slots    [loginRateBuckets]int64 // Time slice each slot currently counts
+	lastSlot int64
+}
+
+func (w *loginRateWindow) add(slot int64, outcome LoginOutcome) {
+	i := slot % loginRateBuckets
+	if w.slots[i] != slot {
+		w.slots[i] = slot
+		w.counts[i] = loginCounts{}
+	}
+	switch outcome {
+	case LoginOutcomeSuccess:
+		w.counts[i].successes++
+	case LoginOutcomeFailure:
+		w.counts[i].failures++
+	case LoginOutcomeLockout:
+		w.counts[i].lockouts++
+	}
+	w.lastSlot = slot
+}
+
+func (w *loginRateWindow) sum(nowSlot int64) loginCounts {
+	var total loginCounts
+	for i := range w.slots {
+		if w.slots[i] > nowSlot-loginRateBuckets && w.slots[i] <= nowSlot {
+			total.successes += w.counts[i].successes
+			total.failures += w.counts[i].failures
+			total.lockouts += w.counts[i].lockouts
+		}
+	}
+	return total
+}
+
+// LoginRateTracker keeps rolling counts of login outcomes, overall and per client IP, so that
+// credential stuffing shows up without querying the audit log. It is safe for concurrent use.
+type LoginRateTracker struct {
+	mu          sync.Mutex
+	window      time.Duration
+	bucketWidth time.Duration
+	maxIPs      int
+	global      loginRateWindow
+	byIP        map[string]*loginRateWindow
+	now         func() time.Time
+}
+
+// NewLoginRateTracker creates a tracker with the configured window and IP limit
+func NewLoginRateTracker(cfg config.LoginRateConfig) *LoginRateTracker {
+	window := time.Duration(cfg.WindowSeconds) * time.Second
+	if window <= 0 {
+		window = config.DefaultLoginRateWindowSeconds * time.Second
+	}
+	bucketWidth := window / loginRateBuckets
+	if bucketWidth <= 0 {
+		bucketWidth = time.Nanosecond
+	}
+	maxIPs := cfg.MaxTrackedIPs
+	if maxIPs <= 0 {
+		maxIPs = config.DefaultLoginRateMaxTrackedIPs
+	}
+	return &LoginRateTracker{
+		window:      bucketWidth * loginRateBuckets,
+		bucketWidth: bucketWidth,
+		maxIPs:      maxIPs,
+		byIP:        make(map[string]*loginRateWindow),
+		now:         time.Now,
+	}
+}
+
+func (t *LoginRateTracker) slot(now time.Time) int64 {
+	return now.UnixNano() / int64(t.bucketWidth)
+}
+
+// Record counts a login attempt from ipAddress
+func (t *LoginRateTracker) Record(ipAddress string, outcome LoginOutcome) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	slot := t.slot(t.now())
+	t.global.add(slot, outcome)
+	if ipAddress == "" {
+		return
+	}
+	ipWindow, ok := t.byIP[ipAddress]
+	if !ok {
+		if len(t.byIP) >= t.maxIPs {
+			t.evictIPs(slot)
+		}
+		ipWindow = &loginRateWindow{}
+		t.byIP[ipAddress] = ipWindow
+	}
+	ipWindow.add(slot, outcome)
+}
+
+// evictIPs drops IPs without attempts in the window, or else the least recently seen IP
+func (t *LoginRateTracker) evictIPs(nowSlot int64) {
+	var oldestIP string
+	oldestSlot := int64(-1)
+	for ip, ipWindow := range t.byIP {
+		if ipWindow.lastSlot <= nowSlot-loginRateBuckets {
+			delete(t.byIP, ip)
+			continue
+		}
+		if oldestSlot < 0 || ipWindow.lastSlot < oldestSlot {
+			oldestIP, oldestSlot = ip, ipWindow.lastSlot
+		}
+	}
+	if len(t.byIP) >= t.maxIPs && oldestSlot >= 0 {
+		delete(t.byIP, oldestIP)
+	}
+}
+
+func (t *LoginRateTracker) rates(counts loginCounts) LoginRates {
+	minutes := t.window.Minutes()
+	rates := LoginRates{
+		Successes:          counts.successes,
+		Failures:           counts.failures,
+		Lockouts:           counts.lockouts,
+		SuccessesPerMinute: float64(counts.successes) / minutes,
+		FailuresPerMinute:  float64(counts.failures) / minutes,
+		LockoutsPerMinute:  float64(counts.lockouts) / minutes,
+	}
+	if total := counts.successes + counts.failures + counts.lockouts; total > 0 {
+		rates.FailureRatio = float64(counts.failures+counts.lockouts) / float64(total)
+	}
+	return rates
+}
+
+// IPRates returns the rates of one client IP, which are zero for an IP without attempts in the window
+func (t *LoginRateTracker) IPRates(ipAddress string) LoginRates {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ipWindow, ok := t.byIP[ipAddress]
+	if !ok {
+		return t.rates(loginCounts{})
+	}
+	return t.rates(ipWindow.sum(t.slot(t.now())))
+}
+
+// Snapshot returns the overall rates and the topIPs IPs with the most failures and lockouts
+func (t *LoginRateTracker) Snapshot(topIPs int) LoginRateSnapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	nowSlot := t.slot(t.now())
+	snapshot := LoginRateSnapshot{
+		WindowSeconds: int(t.window / time.Second),
+		Global:        t.rates(t.global.sum(nowSlot)),
+		TopIPs:        []IPLoginRates{},
+	}
+	for ip, ipWindow := range t.byIP {
+		counts := ipWindow.sum(nowSlot)
+		if counts == (loginCounts{}) {
+			continue
+		}
+		snapshot.TrackedIPs++
+		snapshot.TopIPs = append(snapshot.TopIPs, IPLoginRates{IPAddress: ip, LoginRates: t.rates(counts)})
+	}
+	sort.Slice(snapshot.TopIPs, func(i, j int) bool {
+		a, b := snapshot.TopIPs[i], snapshot.TopIPs[j]
+		if a.Failures+a.Lockouts != b.Failures+b.Lockouts {
+			return a.Failures+a.Lockouts > b.Failures+b.Lockouts
+		}
+		return a.IPAddress < b.IPAddress
+	})
+	if topIPs >= 0 && len(snapshot.TopIPs) > topIPs {
+		snapshot.TopIPs = snapshot.TopIPs[:topIPs]
+	}
+	return snapshot
+}
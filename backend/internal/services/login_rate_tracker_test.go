@@ -0,0 +1,97 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLoginRateTracker(window, maxIPs int) (*LoginRateTracker, *time.Time) {
+	now := time.Date(2025, 6, 14, 12, 0, 0, 0, time.UTC)
+	tracker := NewLoginRateTracker(config.LoginRateConfig{WindowSeconds: window, MaxTrackedIPs: maxIPs})
+	tracker.now = func() time.Time { return now }
+	return tracker, &now
+}
+
+func TestLoginRateTracker_RatesOverWindow(t *testing.T) {
+	tracker, now := newTestLoginRateTracker(300, 100)
+
+	// A credential-stuffing source and a regular user
+	for i := 0; i < 50; i++ {
+		tracker.Record("198.51.100.9", LoginOutcomeFailure)
+	}
+	tracker.Record("198.51.100.9", LoginOutcomeLockout)
+	tracker.Record("203.0.113.7", LoginOutcomeFailure)
+	for i := 0; i < 9; i++ {
+		tracker.Record("203.0.113.7", LoginOutcomeSuccess)
+	}
+
+	snapshot := tracker.Snapshot(10)
+	assert.Equal(t, 300, snapshot.WindowSeconds)
+	assert.EqualValues(t, 9, snapshot.Global.Successes)
+	assert.EqualValues(t, 51, snapshot.Global.Failures)
+	assert.EqualValues(t, 1, snapshot.Global.Lockouts)
+	assert.InDelta(t, 51.0/5, snapshot.Global.FailuresPerMinute, 1e-9)
+	assert.InDelta(t, 52.0/61, snapshot.Global.FailureRatio, 1e-9)
+	assert.Equal(t, 2, snapshot.TrackedIPs)
+	require.Len(t, snapshot.TopIPs, 2)
+	assert.Equal(t, "198.51.100.9", snapshot.TopIPs[0].IPAddress)
+	assert.InDelta(t, 1.0, snapshot.TopIPs[0].FailureRatio, 1e-9)
+	assert.InDelta(t, 0.1, tracker.IPRates("203.0.113.7").FailureRatio, 1e-9)
+	assert.Len(t, tracker.Snapshot(1).TopIPs, 1)
+
+	// Half a window later the earlier attempts still count, alongside new ones
+	*now = now.Add(150 * time.Second)
+	tracker.Record("203.0.113.7", LoginOutcomeSuccess)
+	assert.EqualValues(t, 10, tracker.Snapshot(10).Global.Successes)
+
+	// Once the first attempts are a window old only the later one remains
+	*now = now.Add(151 * time.Second)
+	snapshot = tracker.Snapshot(10)
+	assert.EqualValues(t, 1, snapshot.Global.Successes)
+	assert.Zero(t, snapshot.Global.Failures)
+	assert.Equal(t, 1, snapshot.TrackedIPs)
+	assert.Zero(t, tracker.IPRates("198.51.100.9").Failures)
+
+	// And after a quiet window there is nothing left
+	*now = now.Add(time.Hour)
+	assert.Equal(t, LoginRates{}, tracker.Snapshot(10).Global)
+}
+
+func TestLoginRateTracker_BoundsTrackedIPs(t *testing.T) {
+	tracker, now := newTestLoginRateTracker(60, 3)
+	for i := 0; i < 3; i++ {
+		tracker.Record(fmt.Sprintf("192.0.2.%d", i), LoginOutcomeFailure)
+		*now = now.Add(time.Second)
+	}
+
+	// A fourth IP replaces the least recently seen one
+	tracker.Record("192.0.2.99", LoginOutcomeFailure)
+	assert.Len(t, tracker.byIP, 3)
+	assert.NotContains(t, tracker.byIP, "192.0.2.0")
+	assert.EqualValues(t, 4, tracker.Snapshot(10).Global.Failures, "global counts keep every attempt")
+}
+
+func TestLoginRateTracker_ConcurrentRecords(t *testing.T) {
+	tracker, _ := newTestLoginRateTracker(300, 1000)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				tracker.Record(fmt.Sprintf("192.0.2.%d", g), LoginOutcomeFailure)
+				tracker.Snapshot(3)
+			}
+		}(g)
+	}
+	wg.Wait()
+	snapshot := tracker.Snapshot(10)
+	assert.EqualValues(t, 4000, snapshot.Global.Failures)
+	assert.Equal(t, 8, snapshot.TrackedIPs)
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PasswordHashScheme names the format of a stored password hash
+type PasswordHashScheme string
+
+// Password hash schemes. Logins are verified with pgcrypto's crypt(), which understands bcrypt only
+// in its $2a$ form; the other schemes come from older pgcrypto salts or from outside tools.
+const (
+	PasswordHashBcrypt        PasswordHashScheme = "bcrypt"         // $2a$, what the application writes
+	PasswordHashBcryptVariant PasswordHashScheme = "bcrypt-variant" // $2b$, $2x$, $2y$: unsupported by pgcrypto
+	PasswordHashMD5Crypt      PasswordHashScheme = "md5-crypt"      // pgcrypto gen_salt('md5')
+	PasswordHashExtDESCrypt   PasswordHashScheme = "xdes-crypt"     // pgcrypto gen_salt('xdes')
+	PasswordHashDESCrypt      PasswordHashScheme = "des-crypt"      // pgcrypto gen_salt('des')
+	PasswordHashSHACrypt      PasswordHashScheme = "sha-crypt"      // $5$, $6$: unsupported by pgcrypto
+	PasswordHashUnknown       PasswordHashScheme = "unknown"
+)
+
+// ClassifyPasswordHash identifies the scheme of a stored password hash from its format
+func ClassifyPasswordHash(hash string) PasswordHashScheme {
+	switch {
+	case strings.HasPrefix(hash, "$2a$") && len(hash) == 60:
+		return PasswordHashBcrypt
+	case strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2x$"), strings.HasPrefix(hash, "$2y$"):
+		return PasswordHashBcryptVariant
+	case strings.HasPrefix(hash, "$1$"):
+		return PasswordHashMD5Crypt
+	case strings.HasPrefix(hash, "$5$"), strings.HasPrefix(hash, "$6$"):
+		return PasswordHashSHACrypt
+	case strings.HasPrefix(hash, "_") && len(hash) == 20:
+		return PasswordHashExtDESCrypt
+	case len(hash) == 13 && !strings.HasPrefix(hash, "$"):
+		return PasswordHashDESCrypt
+	}
+	return PasswordHashUnknown
+}
+
+// PasswordHashMismatchError reports that a login failed on a stored hash the login path may be
+// unable to verify even for the correct password. It is for logs and admins, never for the user,
+// who sees the usual invalid-credentials response.
+type PasswordHashMismatchError struct {
+	Scheme                PasswordHashScheme `json:"scheme"`
+	PepperVersion         int                `json:"pepperVersion"`
+	ExpectedPepperVersion int                `json:"expectedPepperVersion"`
+	Reason                string             `json:"reason"`
+}
+
+func (e *PasswordHashMismatchError) Error() string {
+	return fmt.Sprintf("password hash incompatible with login verification: %s", e.Reason)
+}
+
+// DiagnosePasswordHash returns why a failed comparison against hash may be a hash problem rather
+// than a wrong password, or nil when the hash is a current bcrypt hash with the expected pepper.
+func DiagnosePasswordHash(hash string, pepperVersion, expectedPepperVersion int) *PasswordHashMismatchError {
+	mismatch := &PasswordHashMismatchError{
+		Scheme:                ClassifyPasswordHash(hash),
+		PepperVersion:         pepperVersion,
+		ExpectedPepperVersion: expectedPepperVersion,
+	}
+	switch mismatch.Scheme {
+	case PasswordHashBcrypt:
+		if pepperVersion == expectedPepperVersion {
+			return nil
+		}
+		mismatch.Reason = fmt.Sprintf("hash uses pepper version %d, logins verify version %d", pepperVersion, expectedPepperVersion)
+	case PasswordHashMD5Crypt, PasswordHashExtDESCrypt, PasswordHashDESCrypt:
+		mismatch.Reason = fmt.Sprintf("hash uses the legacy pgcrypto %s scheme instead of bcrypt", mismatch.Scheme)
+	case PasswordHashBcryptVariant, PasswordHashSHACrypt:
+		mismatch.Reason = fmt.Sprintf("hash uses the %s scheme, which pgcrypto cannot verify", mismatch.Scheme)
+	default:
+		mismatch.Reason = "hash format is not recognized"
+	}
+	return mismatch
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnosePasswordHash(t *testing.T) {
+	bcryptHash := "$2a$10$" + "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0"
+	require.Len(t, bcryptHash, 60)
+
+	tests := []struct {
+		name          string
+		hash          string
+		pepperVersion int
+		scheme        PasswordHashScheme
+		mismatch      bool
+	}{
+		{"current bcrypt", bcryptHash, 1, PasswordHashBcrypt, false},
+		{"bcrypt with an old pepper", bcryptHash, 0, PasswordHashBcrypt, true},
+		{"pgcrypto md5", "$1$saltsalt$qjXMvbEw8oaL.CzflDugX/", 1, PasswordHashMD5Crypt, true},
+		{"pgcrypto xdes", "_J9..salt" + "abcdefghijk", 1, PasswordHashExtDESCrypt, true},
+		{"pgcrypto des", "saSMnZmVy7N3.", 1, PasswordHashDESCrypt, true},
+		{"bcrypt $2y$ from another tool", "$2y$10$abcdefghijklmnopqrstuv", 1, PasswordHashBcryptVariant, true},
+		{"sha512-crypt", "$6$salt$hash", 1, PasswordHashSHACrypt, true},
+		{"plain text", "not-a-hash", 1, PasswordHashUnknown, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.scheme, ClassifyPasswordHash(tt.hash))
+			mismatch := DiagnosePasswordHash(tt.hash, tt.pepperVersion, 1)
+			if !tt.mismatch {
+				assert.Nil(t, mismatch)
+				return
+			}
+			require.NotNil(t, mismatch)
+			assert.Equal(t, tt.scheme, mismatch.Scheme)
+			assert.NotEmpty(t, mismatch.Reason)
+			assert.Contains(t, mismatch.Error(), mismatch.Reason)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+)
+
+// LegacyPepperMigrationResult reports the accounts still hashed with a legacy pepper and what a
+// migration run did about them
+type LegacyPepperMigrationResult struct {
+	CurrentPepperVersion int         `json:"currentPepperVersion"`
+	GracePeriodDays      int         `json:"gracePeriodDays"`
+	DryRun               bool        `json:"dryRun"`
+	LegacyUsers          int         `json:"legacyUsers"`
+	WithinGracePeriod    int         `json:"withinGracePeriod"`
+	AlreadyFlagged       int         `json:"alreadyFlagged"`
+	Flagged              int         `json:"flagged"`
+	UsersByPepperVersion map[int]int `json:"usersByPepperVersion"`
+}
+
+// PasswordMigrationService moves accounts off legacy password peppers. A bcrypt hash cannot be
+// rehashed without the plaintext, so accounts whose legacy-pepper password has been in place for
+// longer than the grace period are made to change it; the new password is hashed with the current
+// pepper.
+type PasswordMigrationService struct {
+	store                store.PasswordMigrationStore
+	currentPepperVersion int
+	gracePeriod          time.Duration
+	now                  func() time.Time
+}
+
+// NewPasswordMigrationService creates a migration service for the configured pepper version
+func NewPasswordMigrationService(migrationStore store.PasswordMigrationStore, cfg config.PasswordHashConfig) *PasswordMigrationService {
+	s := &PasswordMigrationService{
+		store:                migrationStore,
+		currentPepperVersion: cfg.ExpectedPepperVersion,
+		gracePeriod:          time.Duration(cfg.LegacyPepperGraceDays) * 24 * time.Hour,
+		now:                  time.Now,
+	}
+	if s.currentPepperVersion <= 0 {
+		s.currentPepperVersion = config.DefaultPasswordPepperVersion
+	}
+	if s.gracePeriod <= 0 {
+		s.gracePeriod = config.DefaultLegacyPepperGraceDays * 24 * time.Hour
+	}
+	return s
+}
+
+// FlagLegacyPepperUsers requires a password change from every active account on a legacy pepper
+// whose password was set before the grace period. With dryRun it only counts them.
+func (s *PasswordMigrationService) FlagLegacyPepperUsers(ctx context.Context, dryRun bool) (*LegacyPepperMigrationResult, error) {
+	users, err := s.store.ListLegacyPepperUsers(ctx, nil, s.currentPepperVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list legacy pepper users: %w", err)
+	}
+
+	result := &LegacyPepperMigrationResult{
+		CurrentPepperVersion: s.currentPepperVersion,
+		GracePeriodDays:      int(s.gracePeriod / (24 * time.Hour)),
+		DryRun:               dryRun,
+		LegacyUsers:          len(users),
+		UsersByPepperVersion: make(map[int]int),
+	}
+	cutoff := s.now().Add(-s.gracePeriod)
+	due := make([]uuid.UUID, 0, len(users))
+	for _, user := range users {
+		result.UsersByPepperVersion[user.PepperVersion]++
+		switch {
+		case user.MustChangePassword:
+			result.AlreadyFlagged++
+		case user.PasswordChangedAt.After(cutoff):
+			result.WithinGracePeriod++
+		default:
+			due = append(due, user.UserID)
+		}
+	}
+	if dryRun {
+		result.Flagged = len(due)
+		return result, nil
+	}
+
+	flagged, err := s.store.RequirePasswordChange(ctx, nil, due)
+	if err != nil {
+		return nil, fmt.Errorf("failed to require password change: %w", err)
+	}
+	result.Flagged = int(flagged)
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryPasswordMigrationStore keeps accounts in memory and selects them like the Postgres store
+type memoryPasswordMigrationStore struct {
+	users map[uuid.UUID]*models.UserPasswordPepperState
+}
+
+func (m *memoryPasswordMigrationStore) ListLegacyPepperUsers(ctx context.Context, exec store.Querier, currentPepperVersion int) ([]*models.UserPasswordPepperState, error) {
+	var users []*models.UserPasswordPepperState
+	for _, user := range m.users {
+		if user.PepperVersion < currentPepperVersion {
+			copied := *user
+			users = append(users, &copied)
+		}
+	}
+	return users, nil
+}
+
+func (m *memoryPasswordMigrationStore) RequirePasswordChange(ctx context.Context, exec store.Querier, userIDs []uuid.UUID) (int64, error) {
+	var changed int64
+	for _, id := range userIDs {
+		if user, ok := m.users[id]; ok && !user.MustChangePassword {
+			user.MustChangePassword = true
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+func TestPasswordMigrationService_FlagsLegacyPepperUsersPastGracePeriod(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	addUser := func(m *memoryPasswordMigrationStore, version int, changedAgo time.Duration) uuid.UUID {
+		id := uuid.New()
+		m.users[id] = &models.UserPasswordPepperState{UserID: id, PepperVersion: version, PasswordChangedAt: now.Add(-changedAgo)}
+		return id
+	}
+	memStore := &memoryPasswordMigrationStore{users: make(map[uuid.UUID]*models.UserPasswordPepperState)}
+	legacyOld := addUser(memStore, 1, 90*24*time.Hour)
+	legacyOlder := addUser(memStore, 0, 400*24*time.Hour)
+	legacyRecent := addUser(memStore, 1, 2*24*time.Hour)
+	current := addUser(memStore, 2, 400*24*time.Hour)
+
+	svc := NewPasswordMigrationService(memStore, config.PasswordHashConfig{ExpectedPepperVersion: 2, LegacyPepperGraceDays: 30})
+	svc.now = func() time.Time { return now }
+
+	report, err := svc.FlagLegacyPepperUsers(context.Background(), true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.LegacyUsers)
+	assert.Equal(t, 2, report.Flagged)
+	assert.Equal(t, map[int]int{0: 1, 1: 2}, report.UsersByPepperVersion)
+	assert.False(t, memStore.users[legacyOld].MustChangePassword, "a dry run changes nothing")
+
+	result, err := svc.FlagLegacyPepperUsers(context.Background(), false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Flagged)
+	assert.Equal(t, 1, result.WithinGracePeriod)
+	assert.True(t, memStore.users[legacyOld].MustChangePassword)
+	assert.True(t, memStore.users[legacyOlder].MustChangePassword)
+	assert.False(t, memStore.users[legacyRecent].MustChangePassword, "the grace period has not passed")
+	assert.False(t, memStore.users[current].MustChangePassword, "current-pepper accounts are never flagged")
+
+	again, err := svc.FlagLegacyPepperUsers(context.Background(), false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, again.Flagged)
+	assert.Equal(t, 2, again.AlreadyFlagged)
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+)
+
+// MinPasswordPepperLength is the shortest pepper the server starts with
+const MinPasswordPepperLength = 32
+
+// PasswordPepper keys passwords with a server-side secret before they are hashed, so a leaked
+// users table cannot be attacked offline without the secret as well. bcrypt is given the
+// base64-encoded HMAC-SHA256 of the password under the pepper, which stays at 44 bytes however
+// long the password or pepper is. Every hash records the password_pepper_version it was made with;
+// version 1 (config.UnpepperedPasswordPepperVersion) marks hashes of the bare password made before
+// peppering, which always verify as a legacy version.
+//
+// Rotating the pepper: set PASSWORD_PEPPER to the new secret, increment PASSWORD_PEPPER_VERSION,
+// and move the old secret to PASSWORD_LEGACY_PEPPERS under its version (e.g. "2=<old secret>").
+// Legacy peppers only verify logins: every successful login on a legacy version rehashes the
+// password with the current pepper and version. Accounts that do not sign in during the grace
+// period are made to change their password by the legacy-pepper migration (see
+// PasswordMigrationService), after which the old secret can be dropped from the legacy list.
+type PasswordPepper struct {
+	current string
+	version int
+	legacy  map[int]string
+}
+
+// NewPasswordPepper returns the configured pepper, or an error when it is missing or shorter than
+// MinPasswordPepperLength bytes
+func NewPasswordPepper(cfg config.PasswordHashConfig) (*PasswordPepper, error) {
+	if cfg.Pepper == "" {
+		return nil, fmt.Errorf("password pepper is not configured (set PASSWORD_PEPPER)")
+	}
+	if len(cfg.Pepper) < MinPasswordPepperLength {
+		return nil, fmt.Errorf("password pepper must be at least %d bytes, got %d", MinPasswordPepperLength, len(cfg.Pepper))
+	}
+	p := &PasswordPepper{
+		current: cfg.Pepper,
+		version: cfg.ExpectedPepperVersion,
+		legacy:  make(map[int]string, len(cfg.LegacyPeppers)),
+	}
+	if p.version <= 0 {
+		p.version = config.DefaultPasswordPepperVersion
+	}
+	if p.version == config.UnpepperedPasswordPepperVersion {
+		return nil, fmt.Errorf("password pepper version %d is reserved for unpeppered hashes", p.version)
+	}
+	for version, pepper := range cfg.LegacyPeppers {
+		if version == p.version {
+			return nil, fmt.Errorf("legacy pepper version %d is the current pepper version", version)
+		}
+		if version == config.UnpepperedPasswordPepperVersion && pepper != "" {
+			return nil, fmt.Errorf("legacy pepper version %d is reserved for unpeppered hashes", version)
+		}
+		p.legacy[version] = pepper
+	}
+	p.legacy[config.UnpepperedPasswordPepperVersion] = ""
+	return p, nil
+}
+
+// Version returns the pepper version stored with new hashes
+func (p *PasswordPepper) Version() int {
+	return p.version
+}
+
+// Apply returns the password as hashed with the current pepper
+func (p *PasswordPepper) Apply(password string) string {
+	return pepperPassword(password, p.current)
+}
+
+// ApplyVersion returns the password as hashed with the pepper of the given version, or false when
+// that version's pepper is not configured and the hash cannot be verified
+func (p *PasswordPepper) ApplyVersion(password string, version int) (string, bool) {
+	if version == p.version {
+		return p.Apply(password), true
+	}
+	pepper, ok := p.legacy[version]
+	if !ok {
+		return "", false
+	}
+	return pepperPassword(password, pepper), true
+}
+
+// NeedsRehash reports whether a hash of the given version should be replaced on the next login
+func (p *PasswordPepper) NeedsRehash(version int) bool {
+	return version != p.version
+}
+
+// pepperPassword returns the base64-encoded HMAC-SHA256 of password keyed with pepper, or the
+// password itself for the empty pepper of unpeppered hashes
+func pepperPassword(password, pepper string) string {
+	if pepper == "" {
+		return password
+	}
+	mac := hmac.New(sha256.New, []byte(pepper))
+	mac.Write([]byte(password))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestNewPasswordPepper_RequiresLongPepper(t *testing.T) {
+	_, err := NewPasswordPepper(config.PasswordHashConfig{})
+	assert.Error(t, err, "a missing pepper fails startup")
+	_, err = NewPasswordPepper(config.PasswordHashConfig{Pepper: strings.Repeat("p", MinPasswordPepperLength-1)})
+	assert.Error(t, err)
+	_, err = NewPasswordPepper(config.PasswordHashConfig{Pepper: strings.Repeat("p", MinPasswordPepperLength),
+		ExpectedPepperVersion: 3, LegacyPeppers: map[int]string{3: "old"}})
+	assert.Error(t, err, "the current version cannot also be legacy")
+	_, err = NewPasswordPepper(config.PasswordHashConfig{Pepper: strings.Repeat("p", MinPasswordPepperLength),
+		ExpectedPepperVersion: config.UnpepperedPasswordPepperVersion})
+	assert.Error(t, err, "version 1 marks unpeppered hashes")
+	_, err = NewPasswordPepper(config.PasswordHashConfig{Pepper: strings.Repeat("p", MinPasswordPepperLength),
+		LegacyPeppers: map[int]string{config.UnpepperedPasswordPepperVersion: "old"}})
+	assert.Error(t, err, "version 1 marks unpeppered hashes")
+}
+
+func TestPasswordPepper_LegacyVersionsVerifyOnly(t *testing.T) {
+	current := strings.Repeat("n", MinPasswordPepperLength)
+	pepper, err := NewPasswordPepper(config.PasswordHashConfig{Pepper: current, ExpectedPepperVersion: 3,
+		LegacyPeppers: map[int]string{2: "old-secret"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, pepper.Version())
+	assert.Equal(t, pepperPassword("secret", current), pepper.Apply("secret"))
+	assert.NotEqual(t, pepper.Apply("secret"), pepper.Apply("secreT"))
+
+	peppered, ok := pepper.ApplyVersion("secret", 2)
+	require.True(t, ok)
+	assert.Equal(t, pepperPassword("secret", "old-secret"), peppered)
+	assert.NotEqual(t, pepper.Apply("secret"), peppered)
+	peppered, ok = pepper.ApplyVersion("secret", config.UnpepperedPasswordPepperVersion)
+	require.True(t, ok)
+	assert.Equal(t, "secret", peppered, "version 1 hashes were made without a pepper")
+	_, ok = pepper.ApplyVersion("secret", 4)
+	assert.False(t, ok)
+
+	assert.True(t, pepper.NeedsRehash(config.UnpepperedPasswordPepperVersion))
+	assert.True(t, pepper.NeedsRehash(2))
+	assert.False(t, pepper.NeedsRehash(3))
+}
+
+func TestPasswordPepper_FitsBcryptForLongPasswords(t *testing.T) {
+	pepper, err := NewPasswordPepper(config.PasswordHashConfig{Pepper: strings.Repeat("n", 4*MinPasswordPepperLength)})
+	require.NoError(t, err)
+	assert.Equal(t, config.DefaultPasswordPepperVersion, pepper.Version())
+
+	long := strings.Repeat("x", 200)
+	peppered := pepper.Apply(long)
+	assert.Len(t, peppered, 44, "base64 of a SHA-256 HMAC")
+	assert.NotEqual(t, peppered, pepper.Apply(long+"y"), "bytes past bcrypt's 72-byte limit still count")
+	_, err = bcrypt.GenerateFromPassword([]byte(peppered), bcrypt.MinCost)
+	assert.NoError(t, err)
+}
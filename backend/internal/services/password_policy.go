@@ -0,0 +1,146 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+)
+
+// PasswordPolicy decides whether a new password is acceptable. It is applied when a password is
+// set, never when one is verified, so accounts keep signing in after the policy is tightened.
+type PasswordPolicy struct {
+	rules    PasswordPolicyRules
+	denylist map[string]struct{}
+}
+
+// PasswordPolicyRules are the rules of a password policy as published to clients, so they can show
+// and check them before submitting. The denylist itself is not published.
+type PasswordPolicyRules struct {
+	MinLength        int  `json:"minLength"`
+	MaxLength        int  `json:"maxLength,omitempty"` // 0 for no limit
+	RequireUppercase bool `json:"requireUppercase"`
+	RequireLowercase bool `json:"requireLowercase"`
+	RequireDigit     bool `json:"requireDigit"`
+	RequireSymbol    bool `json:"requireSymbol"`
+	Denylist         bool `json:"denylist"` // Whether common passwords are refused
+}
+
+// PasswordPolicyError lists every rule a password broke
+type PasswordPolicyError struct {
+	Problems []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password does not meet the policy: " + strings.Join(e.Problems, "; ")
+}
+
+// NewPasswordPolicy builds the policy of cfg, reading its denylist file if it has one. A zero
+// minimum length is config.DefaultPasswordMinLength.
+func NewPasswordPolicy(cfg config.PasswordPolicyConfig) (*PasswordPolicy, error) {
+	p := &PasswordPolicy{
+		rules: PasswordPolicyRules{
+			MinLength:        cfg.MinLength,
+			MaxLength:        cfg.MaxLength,
+			RequireUppercase: cfg.RequireUppercase,
+			RequireLowercase: cfg.RequireLowercase,
+			RequireDigit:     cfg.RequireDigit,
+			RequireSymbol:    cfg.RequireSymbol,
+		},
+		denylist: make(map[string]struct{}, len(cfg.Denylist)),
+	}
+	if p.rules.MinLength <= 0 {
+		p.rules.MinLength = config.DefaultPasswordMinLength
+	}
+	if p.rules.MaxLength > 0 && p.rules.MaxLength < p.rules.MinLength {
+		return nil, fmt.Errorf("password max length %d is below the min length %d", p.rules.MaxLength, p.rules.MinLength)
+	}
+
+	for _, password := range cfg.Denylist {
+		p.deny(password)
+	}
+	if cfg.DenylistFile != "" {
+		file, err := os.Open(cfg.DenylistFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open password denylist: %w", err)
+		}
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			p.deny(scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read password denylist %s: %w", cfg.DenylistFile, err)
+		}
+	}
+	p.rules.Denylist = len(p.denylist) > 0
+	return p, nil
+}
+
+// DefaultPasswordPolicy returns the policy of an empty configuration: at least
+// config.DefaultPasswordMinLength characters and nothing else
+func DefaultPasswordPolicy() *PasswordPolicy {
+	p, _ := NewPasswordPolicy(config.PasswordPolicyConfig{})
+	return p
+}
+
+func (p *PasswordPolicy) deny(password string) {
+	if password = strings.TrimSpace(password); password != "" {
+		p.denylist[strings.ToLower(password)] = struct{}{}
+	}
+}
+
+// Rules returns the policy's rules
+func (p *PasswordPolicy) Rules() PasswordPolicyRules {
+	return p.rules
+}
+
+// Validate returns a *PasswordPolicyError naming every rule password breaks, or nil
+func (p *PasswordPolicy) Validate(password string) error {
+	var problems []string
+	length := utf8.RuneCountInString(password)
+	if length < p.rules.MinLength {
+		problems = append(problems, fmt.Sprintf("must be at least %d characters", p.rules.MinLength))
+	}
+	if p.rules.MaxLength > 0 && length > p.rules.MaxLength {
+		problems = append(problems, fmt.Sprintf("must be at most %d characters", p.rules.MaxLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if p.rules.RequireUppercase && !upper {
+		problems = append(problems, "must contain an upper-case letter")
+	}
+	if p.rules.RequireLowercase && !lower {
+		problems = append(problems, "must contain a lower-case letter")
+	}
+	if p.rules.RequireDigit && !digit {
+		problems = append(problems, "must contain a digit")
+	}
+	if p.rules.RequireSymbol && !symbol {
+		problems = append(problems, "must contain a symbol")
+	}
+	if _, denied := p.denylist[strings.ToLower(strings.TrimSpace(password))]; denied {
+		problems = append(problems, "is too common")
+	}
+
+	if len(problems) > 0 {
+		return &PasswordPolicyError{Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.PasswordPolicyConfig
+		password string
+		problems []string
+	}{
+		{"default accepts any 12 characters", config.PasswordPolicyConfig{}, "aaaaaaaaaaaa", nil},
+		{"default refuses 11 characters", config.PasswordPolicyConfig{}, "aaaaaaaaaaa", []string{"must be at least 12 characters"}},
+		{"length counts characters, not bytes", config.PasswordPolicyConfig{MinLength: 4}, "äöüß", nil},
+		{"min length", config.PasswordPolicyConfig{MinLength: 16}, "fifteen-chars!!", []string{"must be at least 16 characters"}},
+		{"max length", config.PasswordPolicyConfig{MaxLength: 14}, "fifteen-chars!!", []string{"must be at most 14 characters"}},
+		{"uppercase", config.PasswordPolicyConfig{RequireUppercase: true}, "no-upper-case-1", []string{"must contain an upper-case letter"}},
+		{"uppercase met", config.PasswordPolicyConfig{RequireUppercase: true}, "One-Upper-Case", nil},
+		{"lowercase", config.PasswordPolicyConfig{RequireLowercase: true}, "NO-LOWER-CASE-1", []string{"must contain a lower-case letter"}},
+		{"lowercase met", config.PasswordPolicyConfig{RequireLowercase: true}, "SOME-lower-CASE", nil},
+		{"digit", config.PasswordPolicyConfig{RequireDigit: true}, "no-digits-at-all", []string{"must contain a digit"}},
+		{"digit met", config.PasswordPolicyConfig{RequireDigit: true}, "one-digit-here-7", nil},
+		{"symbol", config.PasswordPolicyConfig{RequireSymbol: true}, "no symbols at all", []string{"must contain a symbol"}},
+		{"symbol met", config.PasswordPolicyConfig{RequireSymbol: true}, "one symbol here!", nil},
+		{"denylist ignores case", config.PasswordPolicyConfig{Denylist: []string{"CorrectHorseBattery"}}, "correcthorsebattery", []string{"is too common"}},
+		{"denylist allows others", config.PasswordPolicyConfig{Denylist: []string{"correcthorsebattery"}}, "correcthorsebatterystaple", nil},
+		{
+			"every problem is reported",
+			config.PasswordPolicyConfig{RequireUppercase: true, RequireDigit: true, RequireSymbol: true},
+			"short",
+			[]string{"must be at least 12 characters", "must contain an upper-case letter", "must contain a digit", "must contain a symbol"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewPasswordPolicy(tt.cfg)
+			require.NoError(t, err)
+			err = policy.Validate(tt.password)
+			if tt.problems == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var policyErr *PasswordPolicyError
+			require.ErrorAs(t, err, &policyErr)
+			assert.Equal(t, tt.problems, policyErr.Problems)
+		})
+	}
+}
+
+func TestNewPasswordPolicy_ReadsTheDenylistFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "denylist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("password1234\n\n  Qwertyuiop12  \n"), 0o600))
+
+	policy, err := NewPasswordPolicy(config.PasswordPolicyConfig{DenylistFile: path})
+	require.NoError(t, err)
+	assert.Error(t, policy.Validate("password1234"))
+	assert.Error(t, policy.Validate("qwertyuiop12"))
+	assert.NoError(t, policy.Validate("password12345"))
+	assert.True(t, policy.Rules().Denylist)
+
+	_, err = NewPasswordPolicy(config.PasswordPolicyConfig{DenylistFile: filepath.Join(t.TempDir(), "missing.txt")})
+	assert.Error(t, err)
+}
+
+func TestNewPasswordPolicy_Rules(t *testing.T) {
+	assert.Equal(t, PasswordPolicyRules{MinLength: config.DefaultPasswordMinLength}, DefaultPasswordPolicy().Rules())
+
+	_, err := NewPasswordPolicy(config.PasswordPolicyConfig{MinLength: 12, MaxLength: 8})
+	assert.Error(t, err, "a max length below the min length accepts no password")
+}
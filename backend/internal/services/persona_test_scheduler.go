@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+)
+
+// Persona test outcomes
+const (
+	PersonaTestPassed = "passed"
+	PersonaTestFailed = "failed"
+)
+
+// PersonaTestResult is the outcome of one scheduled test of a persona
+type PersonaTestResult struct {
+	Status    string    `json:"status"` // passed or failed
+	LatencyMs int64     `json:"latencyMs"`
+	Error     string    `json:"error,omitempty"`
+	TestedAt  time.Time `json:"testedAt"`
+}
+
+// PersonaTestScheduler runs each enabled persona's real test, a DNS lookup or an HTTP fetch through
+// the persona's own settings, on a fixed interval and keeps the latest results per persona in
+// memory, so flaky resolvers and endpoints show up over time. Personas tested less than an interval
+// ago are skipped. A nil scheduler records nothing.
+type PersonaTestScheduler struct {
+	personaStore store.PersonaStore
+	interval     time.Duration
+	concurrency  int
+	timeout      time.Duration
+	historySize  int
+
+	test func(ctx context.Context, persona *models.Persona) error
+	now  func() time.Time
+
+	mu      sync.Mutex
+	history map[uuid.UUID][]PersonaTestResult // Oldest first
+}
+
+// NewPersonaTestScheduler returns nil when scheduled persona tests are disabled
+func NewPersonaTestScheduler(appCfg *config.AppConfig, personaStore store.PersonaStore) *PersonaTestScheduler {
+	if appCfg == nil || !appCfg.PersonaTests.ScheduledTestsEnabled() {
+		return nil
+	}
+	cfg := appCfg.PersonaTests
+	scheduler := &PersonaTestScheduler{
+		personaStore: personaStore,
+		interval:     time.Duration(cfg.IntervalSeconds) * time.Second,
+		concurrency:  cfg.Concurrency,
+		timeout:      time.Duration(cfg.TimeoutSeconds) * time.Second,
+		historySize:  cfg.HistorySize,
+		now:          time.Now,
+		history:      make(map[uuid.UUID][]PersonaTestResult),
+	}
+	if scheduler.interval <= 0 {
+		scheduler.interval = config.DefaultPersonaTestIntervalSeconds * time.Second
+	}
+	if scheduler.concurrency <= 0 {
+		scheduler.concurrency = config.DefaultPersonaTestConcurrency
+	}
+	if scheduler.timeout <= 0 {
+		scheduler.timeout = config.DefaultPersonaTestTimeoutSeconds * time.Second
+	}
+	if scheduler.historySize <= 0 {
+		scheduler.historySize = config.DefaultPersonaTestHistorySize
+	}
+	tester := NewPersonaTester(appCfg)
+	scheduler.test = func(ctx context.Context, persona *models.Persona) error {
+		if result := tester.probe(ctx, persona); !result.Success {
+			return errors.New(result.Error)
+		}
+		return nil
+	}
+	return scheduler
+}
+
+// Interval is how often each persona is tested
+func (s *PersonaTestScheduler) Interval() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return s.interval
+}
+
+// Start runs test sweeps until ctx is cancelled. Sweeps run four times per interval so each persona
+// is retested between one and one and a quarter intervals after its last test.
+func (s *PersonaTestScheduler) Start(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	sweepEvery := s.interval / 4
+	if sweepEvery < time.Second {
+		sweepEvery = time.Second
+	}
+	go func() {
+		s.RunDue(ctx)
+		ticker := time.NewTicker(sweepEvery)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.RunDue(ctx)
+			}
+		}
+	}()
+}
+
+// RunDue tests every enabled persona not tested within the last interval, at most concurrency at a
+// time, and returns how many were tested
+func (s *PersonaTestScheduler) RunDue(ctx context.Context) int {
+	if s == nil {
+		return 0
+	}
+	enabled := true
+	personas, err := s.personaStore.ListPersonas(ctx, nil, store.ListPersonasFilter{IsEnabled: &enabled})
+	if err != nil {
+		log.Printf("PersonaTestScheduler: Failed to list personas: %v", err)
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, s.concurrency)
+	tested := 0
+	for _, persona := range personas {
+		if !s.due(persona.ID) {
+			continue
+		}
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return tested
+		}
+		tested++
+		wg.Add(1)
+		go func(persona *models.Persona) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			s.testPersona(ctx, persona)
+		}(persona)
+	}
+	wg.Wait()
+	return tested
+}
+
+// due reports whether a persona has no result from the last interval
+func (s *PersonaTestScheduler) due(personaID uuid.UUID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	results := s.history[personaID]
+	return len(results) == 0 || s.now().Sub(results[len(results)-1].TestedAt) >= s.interval
+}
+
+func (s *PersonaTestScheduler) testPersona(ctx context.Context, persona *models.Persona) {
+	testCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	started := s.now()
+	err := s.test(testCtx, persona)
+	if ctx.Err() != nil {
+		return // Shutting down; the result says nothing about the persona
+	}
+	result := PersonaTestResult{Status: PersonaTestPassed, LatencyMs: s.now().Sub(started).Milliseconds(), TestedAt: started}
+	if err != nil {
+		result.Status, result.Error = PersonaTestFailed, err.Error()
+		log.Printf("PersonaTestScheduler: %s persona %s (%s) failed its test: %v", persona.PersonaType, persona.ID, persona.Name, err)
+	}
+	s.record(persona.ID, result)
+}
+
+// record appends a result, dropping the oldest beyond historySize
+func (s *PersonaTestScheduler) record(personaID uuid.UUID, result PersonaTestResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	results := append(s.history[personaID], result)
+	if len(results) > s.historySize {
+		results = append([]PersonaTestResult(nil), results[len(results)-s.historySize:]...)
+	}
+	s.history[personaID] = results
+}
+
+// History returns a persona's recorded results, newest first
+func (s *PersonaTestScheduler) History(personaID uuid.UUID) []PersonaTestResult {
+	if s == nil {
+		return []PersonaTestResult{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	results := s.history[personaID]
+	newestFirst := make([]PersonaTestResult, len(results))
+	for i, result := range results {
+		newestFirst[len(results)-1-i] = result
+	}
+	return newestFirst
+}
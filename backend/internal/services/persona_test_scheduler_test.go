@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// enabledPersonaStore answers ListPersonas with a fixed set of personas
+type enabledPersonaStore struct {
+	store.PersonaStore
+	personas []*models.Persona
+}
+
+func (s *enabledPersonaStore) ListPersonas(ctx context.Context, exec store.Querier, filter store.ListPersonasFilter) ([]*models.Persona, error) {
+	return s.personas, nil
+}
+
+// newTestPersonaScheduler returns a scheduler on a manual clock whose test fails for the given personas
+func newTestPersonaScheduler(personas []*models.Persona, failing map[uuid.UUID]bool, clock *time.Time, calls *int32) *PersonaTestScheduler {
+	var mu sync.Mutex
+	return &PersonaTestScheduler{
+		personaStore: &enabledPersonaStore{personas: personas},
+		interval:     5 * time.Minute,
+		concurrency:  2,
+		timeout:      time.Second,
+		historySize:  3,
+		test: func(ctx context.Context, persona *models.Persona) error {
+			atomic.AddInt32(calls, 1)
+			if failing[persona.ID] {
+				return errors.New("resolver timed out")
+			}
+			return nil
+		},
+		now: func() time.Time {
+			mu.Lock()
+			defer mu.Unlock()
+			return *clock
+		},
+		history: make(map[uuid.UUID][]PersonaTestResult),
+	}
+}
+
+func TestPersonaTestScheduler_AppendsResultsToHistory(t *testing.T) {
+	dns := &models.Persona{ID: uuid.New(), PersonaType: models.PersonaTypeDNS, IsEnabled: true}
+	flaky := &models.Persona{ID: uuid.New(), PersonaType: models.PersonaTypeHTTP, IsEnabled: true}
+	clock := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	var calls int32
+	scheduler := newTestPersonaScheduler([]*models.Persona{dns, flaky}, map[uuid.UUID]bool{flaky.ID: true}, &clock, &calls)
+
+	assert.Equal(t, 2, scheduler.RunDue(context.Background()))
+	clock = clock.Add(5 * time.Minute)
+	assert.Equal(t, 2, scheduler.RunDue(context.Background()))
+
+	history := scheduler.History(dns.ID)
+	require.Len(t, history, 2)
+	assert.Equal(t, PersonaTestPassed, history[0].Status)
+	assert.Equal(t, clock, history[0].TestedAt, "newest first")
+	assert.Empty(t, history[0].Error)
+
+	failed := scheduler.History(flaky.ID)
+	require.Len(t, failed, 2)
+	assert.Equal(t, PersonaTestFailed, failed[0].Status)
+	assert.Equal(t, "resolver timed out", failed[0].Error)
+
+	assert.Empty(t, scheduler.History(uuid.New()))
+}
+
+func TestPersonaTestScheduler_SkipsPersonasTestedWithinTheInterval(t *testing.T) {
+	persona := &models.Persona{ID: uuid.New(), PersonaType: models.PersonaTypeDNS, IsEnabled: true}
+	clock := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	var calls int32
+	scheduler := newTestPersonaScheduler([]*models.Persona{persona}, nil, &clock, &calls)
+
+	scheduler.RunDue(context.Background())
+	for _, elapsed := range []time.Duration{time.Second, time.Minute, 4*time.Minute + 59*time.Second} {
+		clock = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC).Add(elapsed)
+		assert.Zero(t, scheduler.RunDue(context.Background()), "after %s", elapsed)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	clock = time.Date(2026, 10, 16, 12, 5, 0, 0, time.UTC)
+	assert.Equal(t, 1, scheduler.RunDue(context.Background()))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestPersonaTestScheduler_HistoryIsBounded(t *testing.T) {
+	persona := &models.Persona{ID: uuid.New(), PersonaType: models.PersonaTypeDNS, IsEnabled: true}
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	clock := start
+	var calls int32
+	scheduler := newTestPersonaScheduler([]*models.Persona{persona}, nil, &clock, &calls)
+
+	for i := 0; i < 5; i++ {
+		clock = start.Add(time.Duration(i) * 5 * time.Minute)
+		scheduler.RunDue(context.Background())
+	}
+	history := scheduler.History(persona.ID)
+	require.Len(t, history, 3)
+	assert.Equal(t, start.Add(20*time.Minute), history[0].TestedAt)
+	assert.Equal(t, start.Add(10*time.Minute), history[2].TestedAt)
+}
+
+func TestPersonaTestScheduler_BoundsConcurrency(t *testing.T) {
+	var personas []*models.Persona
+	for i := 0; i < 6; i++ {
+		personas = append(personas, &models.Persona{ID: uuid.New(), PersonaType: models.PersonaTypeHTTP, IsEnabled: true})
+	}
+	clock := time.Now()
+	var calls, running, peak int32
+	scheduler := newTestPersonaScheduler(personas, nil, &clock, &calls)
+	scheduler.test = func(ctx context.Context, persona *models.Persona) error {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			seen := atomic.LoadInt32(&peak)
+			if current <= seen || atomic.CompareAndSwapInt32(&peak, seen, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+
+	assert.Equal(t, 6, scheduler.RunDue(context.Background()))
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
+}
+
+func TestNewPersonaTestScheduler_DisabledByDefault(t *testing.T) {
+	assert.Nil(t, NewPersonaTestScheduler(&config.AppConfig{}, nil))
+
+	var nilScheduler *PersonaTestScheduler
+	assert.Zero(t, nilScheduler.RunDue(context.Background()))
+	assert.Empty(t, nilScheduler.History(uuid.New()))
+}
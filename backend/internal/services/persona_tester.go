@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/dnsvalidator"
+	"github.com/fntelecomllc/studio/backend/internal/httpvalidator"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// PersonaProbeResult is the outcome of one test of a persona run on demand
+type PersonaProbeResult struct {
+	PersonaID   uuid.UUID              `json:"personaId"`
+	PersonaType models.PersonaTypeEnum `json:"personaType"`
+	Success     bool                   `json:"success"`
+	Status      string                 `json:"status"` // passed or failed
+	Target      string                 `json:"target"` // The probe domain or URL
+	LatencyMs   int64                  `json:"latencyMs"`
+	Records     []string               `json:"records,omitempty"`    // DNS: addresses the probe domain resolved to
+	Resolver    string                 `json:"resolver,omitempty"`   // DNS: resolver that was asked
+	StatusCode  int                    `json:"statusCode,omitempty"` // HTTP: status of the response
+	FinalURL    string                 `json:"finalUrl,omitempty"`   // HTTP: URL after redirects
+	Error       string                 `json:"error,omitempty"`
+	TestedAt    time.Time              `json:"testedAt"`
+}
+
+// PersonaTester runs a persona's real test: a DNS persona resolves the probe domain through its own
+// resolvers, and an HTTP persona fetches the probe URL with its own headers, user agent, TLS
+// settings and accepted status codes, without reading the body. A nil tester tests nothing.
+type PersonaTester struct {
+	dnsProbeDomain string
+	httpProbeURL   string
+	timeout        time.Duration
+	httpValidator  *httpvalidator.HTTPValidator
+	now            func() time.Time
+}
+
+// NewPersonaTester returns a tester whose tests are cut off after the on-demand timeout
+func NewPersonaTester(appCfg *config.AppConfig) *PersonaTester {
+	if appCfg == nil {
+		appCfg = &config.AppConfig{}
+	}
+	cfg := appCfg.PersonaTests
+	tester := &PersonaTester{
+		dnsProbeDomain: cfg.DNSProbeDomain,
+		httpProbeURL:   cfg.HTTPProbeURL,
+		timeout:        time.Duration(cfg.OnDemandTimeoutSeconds) * time.Second,
+		httpValidator:  httpvalidator.NewHTTPValidator(appCfg),
+		now:            time.Now,
+	}
+	if tester.dnsProbeDomain == "" {
+		tester.dnsProbeDomain = config.DefaultPersonaTestDNSProbeDomain
+	}
+	if tester.httpProbeURL == "" {
+		tester.httpProbeURL = config.DefaultPersonaTestHTTPProbeURL
+	}
+	if tester.timeout <= 0 {
+		tester.timeout = config.DefaultPersonaTestOnDemandTimeoutSeconds * time.Second
+	}
+	return tester
+}
+
+// Test tests one persona now. A failed test is reported in the result; an error is only returned
+// when ctx ends first, since the outcome then says nothing about the persona.
+func (t *PersonaTester) Test(ctx context.Context, persona *models.Persona) (*PersonaProbeResult, error) {
+	if t == nil {
+		return nil, fmt.Errorf("persona tests are not available")
+	}
+	testCtx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	result := t.probe(testCtx, persona)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if !result.Success && testCtx.Err() != nil {
+		result.Error = fmt.Sprintf("timed out after %s: %s", t.timeout, result.Error)
+	}
+	return result, nil
+}
+
+// probe resolves the probe domain with a DNS persona's resolvers or fetches the probe URL with an
+// HTTP persona's settings, within the deadline of ctx
+func (t *PersonaTester) probe(ctx context.Context, persona *models.Persona) *PersonaProbeResult {
+	started := t.now()
+	result := &PersonaProbeResult{PersonaID: persona.ID, PersonaType: persona.PersonaType, TestedAt: started.UTC()}
+	var err error
+	switch persona.PersonaType {
+	case models.PersonaTypeDNS:
+		err = t.probeDNS(ctx, persona, result)
+	case models.PersonaTypeHTTP:
+		err = t.probeHTTP(ctx, persona, result)
+	default:
+		err = fmt.Errorf("unknown persona type %q", persona.PersonaType)
+	}
+	result.LatencyMs = t.now().Sub(started).Milliseconds()
+	result.Success, result.Status = err == nil, PersonaTestPassed
+	if err != nil {
+		result.Status, result.Error = PersonaTestFailed, err.Error()
+	}
+	return result
+}
+
+func (t *PersonaTester) probeDNS(ctx context.Context, persona *models.Persona, result *PersonaProbeResult) error {
+	result.Target = t.dnsProbeDomain
+	var details models.DNSConfigDetails
+	if err := json.Unmarshal(persona.ConfigDetails, &details); err != nil {
+		return fmt.Errorf("invalid DNS config: %w", err)
+	}
+	validator := dnsvalidator.New(config.ConvertJSONToDNSConfig(modelsDNStoConfigDNSJSON(details)))
+	validation := validator.ValidateSingleDomain(t.dnsProbeDomain, ctx)
+	result.Records, result.Resolver = validation.IPs, validation.Resolver
+	if validation.Status != "Resolved" {
+		return fmt.Errorf("%s did not resolve (%s): %s", t.dnsProbeDomain, validation.Status, validation.Error)
+	}
+	return nil
+}
+
+func (t *PersonaTester) probeHTTP(ctx context.Context, persona *models.Persona, result *PersonaProbeResult) error {
+	result.Target = t.httpProbeURL
+	probe, err := url.Parse(t.httpProbeURL)
+	if err != nil {
+		return fmt.Errorf("invalid probe URL: %w", err)
+	}
+	validation, err := t.httpValidator.ValidateLiveness(ctx, probe.Hostname(), t.httpProbeURL, persona, nil)
+	if validation != nil {
+		result.StatusCode, result.FinalURL = validation.StatusCode, validation.FinalURL
+	}
+	if err != nil {
+		return err
+	}
+	if !validation.IsSuccess {
+		return fmt.Errorf("%s failed (%s): %s", t.httpProbeURL, validation.Status, validation.Error)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startTestResolver serves A records for the names in records over UDP and NXDOMAIN for the rest,
+// and returns its address
+func startTestResolver(t *testing.T, records map[string]string) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &dns.Server{PacketConn: conn, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		reply := new(dns.Msg)
+		reply.SetReply(r)
+		question := r.Question[0]
+		ip, ok := records[question.Name]
+		switch {
+		case !ok:
+			reply.Rcode = dns.RcodeNameError
+		case question.Qtype == dns.TypeA:
+			reply.Answer = append(reply.Answer, &dns.A{
+				Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+				A:   net.ParseIP(ip),
+			})
+		}
+		_ = w.WriteMsg(reply)
+	})}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+	return conn.LocalAddr().String()
+}
+
+func newTestPersona(t *testing.T, personaType models.PersonaTypeEnum, details interface{}) *models.Persona {
+	t.Helper()
+	raw, err := json.Marshal(details)
+	require.NoError(t, err)
+	return &models.Persona{ID: uuid.New(), Name: "probe", PersonaType: personaType, ConfigDetails: raw, IsEnabled: true}
+}
+
+func TestPersonaTester_DNS(t *testing.T) {
+	resolver := startTestResolver(t, map[string]string{"probe.example.": "192.0.2.10"})
+	tester := NewPersonaTester(&config.AppConfig{PersonaTests: config.PersonaTestConfig{DNSProbeDomain: "probe.example"}})
+	persona := newTestPersona(t, models.PersonaTypeDNS, models.DNSConfigDetails{
+		Resolvers: []string{resolver}, ResolverStrategy: "random_rotation", QueryTimeoutSeconds: 1,
+	})
+
+	result, err := tester.Test(context.Background(), persona)
+	require.NoError(t, err)
+	assert.True(t, result.Success, result.Error)
+	assert.Equal(t, PersonaTestPassed, result.Status)
+	assert.Equal(t, persona.ID, result.PersonaID)
+	assert.Equal(t, models.PersonaTypeDNS, result.PersonaType)
+	assert.Equal(t, "probe.example", result.Target)
+	assert.Equal(t, []string{"192.0.2.10"}, result.Records)
+	assert.Equal(t, resolver, result.Resolver)
+	assert.Empty(t, result.Error)
+
+	tester.dnsProbeDomain = "missing.example"
+	result, err = tester.Test(context.Background(), persona)
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, PersonaTestFailed, result.Status)
+	assert.Contains(t, result.Error, "missing.example did not resolve (Not Found)")
+	assert.Empty(t, result.Records)
+}
+
+func TestPersonaTester_HTTP(t *testing.T) {
+	var userAgent, probeHeader string
+	var method string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent, probeHeader, method = r.UserAgent(), r.Header.Get("X-Probe"), r.Method
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tester := NewPersonaTester(&config.AppConfig{PersonaTests: config.PersonaTestConfig{HTTPProbeURL: server.URL + "/up"}})
+	persona := newTestPersona(t, models.PersonaTypeHTTP, models.HTTPConfigDetails{
+		UserAgent: "ProbeAgent/1.0", Headers: map[string]string{"X-Probe": "persona"},
+	})
+
+	result, err := tester.Test(context.Background(), persona)
+	require.NoError(t, err)
+	assert.True(t, result.Success, result.Error)
+	assert.Equal(t, http.StatusNoContent, result.StatusCode)
+	assert.Equal(t, server.URL+"/up", result.FinalURL)
+	assert.Equal(t, "ProbeAgent/1.0", userAgent, "the persona's user agent is sent")
+	assert.Equal(t, "persona", probeHeader, "the persona's headers are sent")
+	assert.Equal(t, http.MethodGet, method)
+
+	tester.httpProbeURL = server.URL + "/down"
+	result, err = tester.Test(context.Background(), persona)
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, PersonaTestFailed, result.Status)
+	assert.Equal(t, http.StatusServiceUnavailable, result.StatusCode)
+	assert.NotEmpty(t, result.Error)
+
+	// The persona accepting 503 turns the same response into a pass
+	persona = newTestPersona(t, models.PersonaTypeHTTP, models.HTTPConfigDetails{
+		UserAgent: "ProbeAgent/1.0", AllowedStatusCodes: []int{http.StatusServiceUnavailable},
+	})
+	result, err = tester.Test(context.Background(), persona)
+	require.NoError(t, err)
+	assert.True(t, result.Success, result.Error)
+}
+
+func TestPersonaTester_TimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	tester := NewPersonaTester(&config.AppConfig{PersonaTests: config.PersonaTestConfig{HTTPProbeURL: server.URL}})
+	tester.timeout = 100 * time.Millisecond
+	persona := newTestPersona(t, models.PersonaTypeHTTP, models.HTTPConfigDetails{UserAgent: "ProbeAgent/1.0"})
+
+	started := time.Now()
+	result, err := tester.Test(context.Background(), persona)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(started), 5*time.Second)
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Error, "timed out after 100ms")
+	assert.GreaterOrEqual(t, result.LatencyMs, int64(100))
+}
+
+func TestPersonaTester_ShortDefaultTimeout(t *testing.T) {
+	tester := NewPersonaTester(nil)
+	assert.Equal(t, config.DefaultPersonaTestOnDemandTimeoutSeconds*time.Second, tester.timeout)
+	assert.Equal(t, config.DefaultPersonaTestDNSProbeDomain, tester.dnsProbeDomain)
+	assert.Equal(t, config.DefaultPersonaTestHTTPProbeURL, tester.httpProbeURL)
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Proxy last_status values written by health checks, next to ProxyLastStatusActive for a passed check
+const (
+	ProxyLastStatusFailing   = "Failing"   // Failed its latest checks but not yet enough of them to be unhealthy
+	ProxyLastStatusUnhealthy = "Unhealthy" // Failed the configured number of consecutive checks
+)
+
+// ErrProxyCheckUnsupported is returned for proxies whose protocol cannot be checked
+var ErrProxyCheckUnsupported = errors.New("proxy protocol cannot be health checked")
+
+// ProxyCheckResult is the outcome of one health check of a stored proxy
+type ProxyCheckResult struct {
+	ProxyID             uuid.UUID `json:"proxyId"`
+	IsHealthy           bool      `json:"isHealthy"`
+	LastStatus          string    `json:"lastStatus"`
+	LatencyMs           *int64    `json:"latencyMs,omitempty"` // Set when the check passed
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	Error               string    `json:"error,omitempty"`
+	CheckedAt           time.Time `json:"checkedAt"`
+}
+
+// ProxyHealthChecker fetches a target URL through each enabled stored proxy and writes the outcome,
+// the latency and the time of the check onto the proxy. A proxy stays healthy through isolated
+// failures and is marked unhealthy once it fails the configured number of checks in a row; one
+// passed check makes it healthy again. Consecutive failures are counted in memory, so a restart
+// starts the count over while keeping the stored health. A nil checker checks nothing.
+type ProxyHealthChecker struct {
+	db               *sqlx.DB
+	proxyStore       store.ProxyStore
+	scheduled        bool
+	interval         time.Duration
+	timeout          time.Duration
+	concurrency      int
+	failureThreshold int
+	target           string
+
+	dial func(ctx context.Context, proxy *models.Proxy, target string) error
+	now  func() time.Time
+
+	mu       sync.Mutex
+	failures map[uuid.UUID]int
+}
+
+// NewProxyHealthChecker returns a checker for on-demand checks; it only checks on a schedule when
+// scheduled proxy checks are enabled
+func NewProxyHealthChecker(appCfg *config.AppConfig, db *sqlx.DB, proxyStore store.ProxyStore) *ProxyHealthChecker {
+	var cfg config.ProxyHealthConfig
+	if appCfg != nil {
+		cfg = appCfg.ProxyHealth
+	}
+	checker := &ProxyHealthChecker{
+		db:               db,
+		proxyStore:       proxyStore,
+		scheduled:        cfg.ScheduledChecksEnabled(),
+		interval:         time.Duration(cfg.CheckIntervalSeconds) * time.Second,
+		timeout:          time.Duration(cfg.CheckTimeoutSeconds) * time.Second,
+		concurrency:      cfg.CheckConcurrency,
+		failureThreshold: cfg.CheckFailureThreshold,
+		target:           cfg.CheckTargetURL,
+		dial:             dialThroughProxy,
+		now:              time.Now,
+		failures:         make(map[uuid.UUID]int),
+	}
+	if checker.interval <= 0 {
+		checker.interval = config.DefaultProxyCheckIntervalSeconds * time.Second
+	}
+	if checker.timeout <= 0 {
+		checker.timeout = config.DefaultProxyCheckTimeoutSeconds * time.Second
+	}
+	if checker.concurrency <= 0 {
+		checker.concurrency = config.DefaultProxyCheckConcurrency
+	}
+	if checker.failureThreshold <= 0 {
+		checker.failureThreshold = config.DefaultProxyCheckFailureThreshold
+	}
+	if checker.target == "" {
+		checker.target = config.DefaultProxyCheckTargetURL
+	}
+	return checker
+}
+
+// proxyCheckURL returns the URL a stored proxy is reached at: its address when that is a full URL,
+// otherwise the address behind the proxy's protocol
+func proxyCheckURL(proxy *models.Proxy) (*url.URL, error) {
+	address := proxy.Address
+	if !strings.Contains(address, "://") {
+		protocol := models.ProxyProtocolHTTP
+		if proxy.Protocol != nil && *proxy.Protocol != "" {
+			protocol = *proxy.Protocol
+		}
+		address = string(protocol) + "://" + address
+	}
+	proxyURL, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy address: %w", err)
+	}
+	switch strings.ToLower(proxyURL.Scheme) {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrProxyCheckUnsupported, proxyURL.Scheme)
+	}
+	if proxyURL.User == nil && proxy.Username.Valid && proxy.Username.String != "" {
+		proxyURL.User = url.User(proxy.Username.String)
+	}
+	return proxyURL, nil
+}
+
+// dialThroughProxy fetches target through the proxy and fails on transport errors and 5xx answers
+func dialThroughProxy(ctx context.Context, proxy *models.Proxy, target string) error {
+	proxyURL, err := proxyCheckURL(proxy)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL), DisableKeepAlives: true}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("invalid check target: %w", err)
+	}
+	req.Header.Set("User-Agent", "DomainFlowProxyHealthChecker/1.0")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("%s answered %d through the proxy", target, resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *ProxyHealthChecker) querier() store.Querier {
+	if c.db != nil {
+		return c.db
+	}
+	return nil
+}
+
+// Start checks every enabled proxy once per interval until ctx is cancelled. It does nothing
+// unless scheduled checks are enabled.
+func (c *ProxyHealthChecker) Start(ctx context.Context) {
+	if c == nil || !c.scheduled {
+		return
+	}
+	go func() {
+		c.CheckAll(ctx)
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.CheckAll(ctx)
+			}
+		}
+	}()
+}
+
+// CheckAll checks every enabled proxy, at most concurrency at a time, and returns how many were checked
+func (c *ProxyHealthChecker) CheckAll(ctx context.Context) int {
+	if c == nil {
+		return 0
+	}
+	enabled := true
+	proxies, err := c.proxyStore.ListProxies(ctx, c.querier(), store.ListProxiesFilter{IsEnabled: &enabled})
+	if err != nil {
+		log.Printf("ProxyHealthChecker: Failed to list proxies: %v", err)
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, c.concurrency)
+	checked := 0
+	for _, proxy := range proxies {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return checked
+		}
+		checked++
+		wg.Add(1)
+		go func(proxy *models.Proxy) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			if _, err := c.Check(ctx, proxy); err != nil && !errors.Is(err, ErrProxyCheckUnsupported) && ctx.Err() == nil {
+				log.Printf("ProxyHealthChecker: Failed to check proxy %s (%s): %v", proxy.ID, proxy.Name, err)
+			}
+		}(proxy)
+	}
+	wg.Wait()
+	return checked
+}
+
+// Check checks one proxy now and stores the outcome. Proxies whose protocol cannot be checked are
+// left untouched and return ErrProxyCheckUnsupported.
+func (c *ProxyHealthChecker) Check(ctx context.Context, proxy *models.Proxy) (*ProxyCheckResult, error) {
+	if c == nil {
+		return nil, errors.New("proxy health checks are not available")
+	}
+	if _, err := proxyCheckURL(proxy); errors.Is(err, ErrProxyCheckUnsupported) {
+		return nil, err
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	started := c.now()
+	dialErr := c.dial(checkCtx, proxy, c.target)
+	if ctx.Err() != nil {
+		return nil, ctx.Err() // Shutting down; the outcome says nothing about the proxy
+	}
+
+	result := &ProxyCheckResult{ProxyID: proxy.ID, CheckedAt: started.UTC()}
+	latency := sql.NullInt32{}
+	c.mu.Lock()
+	if dialErr == nil {
+		delete(c.failures, proxy.ID)
+		latencyMs := c.now().Sub(started).Milliseconds()
+		result.IsHealthy, result.LastStatus, result.LatencyMs = true, ProxyLastStatusActive, &latencyMs
+		latency = sql.NullInt32{Int32: int32(latencyMs), Valid: true}
+	} else {
+		c.failures[proxy.ID]++
+		result.ConsecutiveFailures = c.failures[proxy.ID]
+		result.Error = dialErr.Error()
+		// An unhealthy proxy stays unhealthy until it passes a check
+		result.IsHealthy = proxy.IsHealthy && result.ConsecutiveFailures < c.failureThreshold
+		result.LastStatus = ProxyLastStatusFailing
+		if !result.IsHealthy {
+			result.LastStatus = ProxyLastStatusUnhealthy
+		}
+	}
+	c.mu.Unlock()
+
+	if err := c.proxyStore.UpdateProxyHealth(ctx, c.querier(), proxy.ID, result.IsHealthy, result.LastStatus, latency, result.CheckedAt); err != nil {
+		return nil, err
+	}
+	if proxy.IsHealthy && !result.IsHealthy {
+		log.Printf("ProxyHealthChecker: Proxy %s (%s) marked unhealthy after %d failed checks: %v",
+			proxy.ID, proxy.Name, result.ConsecutiveFailures, dialErr)
+	}
+	proxy.IsHealthy = result.IsHealthy
+	proxy.LastStatus = sql.NullString{String: result.LastStatus, Valid: true}
+	proxy.LastCheckedAt = sql.NullTime{Time: result.CheckedAt, Valid: true}
+	proxy.LatencyMs = latency
+	return result, nil
+}
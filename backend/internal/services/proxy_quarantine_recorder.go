@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/proxymanager"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+)
+
+// Proxy last_status values written when a proxy enters or leaves quarantine
+const (
+	ProxyLastStatusQuarantined = "Quarantined"
+	ProxyLastStatusActive      = "Active"
+)
+
+// proxyQuarantineRecordTimeout bounds the store writes made for a single quarantine event
+const proxyQuarantineRecordTimeout = 10 * time.Second
+
+// NewProxyQuarantineRecorder returns a ProxyManager quarantine listener that writes an audit
+// entry for every quarantine change and mirrors the state onto the stored proxy record.
+func NewProxyQuarantineRecorder(proxyStore store.ProxyStore, auditLogStore store.AuditLogStore) func(proxymanager.ProxyQuarantineEvent) {
+	return func(event proxymanager.ProxyQuarantineEvent) {
+		ctx, cancel := context.WithTimeout(context.Background(), proxyQuarantineRecordTimeout)
+		defer cancel()
+
+		action := "Proxy Reinstated"
+		lastStatus := ProxyLastStatusActive
+		if event.Quarantined {
+			action = "Proxy Quarantined"
+			lastStatus = ProxyLastStatusQuarantined
+		}
+
+		// Proxies loaded from config files may not have UUID identifiers or a stored record
+		proxyID, parseErr := uuid.Parse(event.ProxyID)
+		if parseErr == nil && proxyStore != nil {
+			proxy, err := proxyStore.GetProxyByID(ctx, nil, proxyID)
+			if err == nil {
+				proxy.IsHealthy = !event.Quarantined
+				proxy.LastStatus = sql.NullString{String: lastStatus, Valid: true}
+				proxy.LastCheckedAt = sql.NullTime{Time: event.At.UTC(), Valid: true}
+				proxy.UpdatedAt = time.Now().UTC()
+				if err := proxyStore.UpdateProxy(ctx, nil, proxy); err != nil {
+					log.Printf("ProxyQuarantineRecorder: failed to update proxy %s: %v", proxyID, err)
+				}
+			} else if err != store.ErrNotFound {
+				log.Printf("ProxyQuarantineRecorder: failed to load proxy %s: %v", proxyID, err)
+			}
+		}
+
+		if auditLogStore == nil {
+			return
+		}
+		details, _ := json.Marshal(map[string]interface{}{
+			"proxyId":             event.ProxyID,
+			"address":             event.Address,
+			"consecutiveFailures": event.ConsecutiveFailures,
+			"reason":              event.Reason,
+		})
+		auditLog := &models.AuditLog{
+			Timestamp:  event.At.UTC(),
+			Action:     action,
+			EntityType: sql.NullString{String: "Proxy", Valid: true},
+			Details:    models.JSONRawMessagePtr(details),
+		}
+		if parseErr == nil {
+			auditLog.EntityID = uuid.NullUUID{UUID: proxyID, Valid: true}
+		}
+		if err := auditLogStore.CreateAuditLog(ctx, nil, auditLog); err != nil {
+			log.Printf("ProxyQuarantineRecorder: failed to write audit log for proxy %s: %v", event.ProxyID, err)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+)
+
+// Proxy selection strategies of HTTP keyword campaigns
+const (
+	ProxySelectionRoundRobin    = "round_robin"    // Each proxy in turn
+	ProxySelectionRandom        = "random"         // A uniformly random proxy
+	ProxySelectionLowestLatency = "lowest_latency" // The proxy with the lowest latency measured by health checks
+)
+
+// ValidateProxySelectionStrategy checks an HTTP keyword campaign's proxy selection strategy and
+// returns it normalized, round_robin when unset
+func ValidateProxySelectionStrategy(strategy string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(strategy))
+	switch normalized {
+	case "":
+		return ProxySelectionRoundRobin, nil
+	case ProxySelectionRoundRobin, ProxySelectionRandom, ProxySelectionLowestLatency:
+		return normalized, nil
+	default:
+		return "", fmt.Errorf("proxySelectionStrategy %q must be round_robin, random or lowest_latency", strategy)
+	}
+}
+
+// proxyRotation is the selection state of one campaign
+type proxyRotation struct {
+	next     int           // Round robin position
+	current  *models.Proxy // Proxy kept until the rotation interval passes
+	pickedAt time.Time
+}
+
+// proxySelector picks the proxy of each request of an HTTP keyword campaign by the campaign's
+// strategy. With a rotation interval the campaign keeps the picked proxy for that long before
+// picking again. State is kept per campaign and shared by all workers processing it.
+type proxySelector struct {
+	mu        sync.Mutex
+	campaigns map[uuid.UUID]*proxyRotation
+	random    *rand.Rand // Guarded by mu
+	now       func() time.Time
+}
+
+func newProxySelector() *proxySelector {
+	return &proxySelector{
+		campaigns: make(map[uuid.UUID]*proxyRotation),
+		random:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		now:       time.Now,
+	}
+}
+
+// pick returns the campaign's proxy among candidates, or nil when there are none
+func (s *proxySelector) pick(campaignID uuid.UUID, strategy string, rotationInterval time.Duration, candidates []*models.Proxy) *models.Proxy {
+	if len(candidates) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rotation, ok := s.campaigns[campaignID]
+	if !ok {
+		rotation = &proxyRotation{}
+		s.campaigns[campaignID] = rotation
+	}
+	now := s.now()
+	if rotationInterval > 0 && rotation.current != nil && now.Sub(rotation.pickedAt) < rotationInterval {
+		// Keep the proxy while it is still a candidate
+		for _, candidate := range candidates {
+			if candidate.ID == rotation.current.ID {
+				return candidate
+			}
+		}
+	}
+
+	var picked *models.Proxy
+	switch strategy {
+	case ProxySelectionRandom:
+		picked = candidates[s.random.Intn(len(candidates))]
+	case ProxySelectionLowestLatency:
+		picked = lowestLatencyProxy(candidates)
+	default:
+		picked = candidates[rotation.next%len(candidates)]
+		rotation.next = (rotation.next + 1) % len(candidates)
+	}
+	rotation.current, rotation.pickedAt = picked, now
+	return picked
+}
+
+// lowestLatencyProxy returns the first proxy with the lowest measured latency, or the first proxy
+// when none was measured
+func lowestLatencyProxy(candidates []*models.Proxy) *models.Proxy {
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if candidate.LatencyMs.Valid && (!best.LatencyMs.Valid || candidate.LatencyMs.Int32 < best.LatencyMs.Int32) {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// forget drops a campaign's selection state once it stops running
+func (s *proxySelector) forget(campaignID uuid.UUID) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.campaigns, campaignID)
+}
+
+// campaignProxyCandidates returns the proxies a campaign's requests are spread over
+func (s *httpKeywordCampaignServiceImpl) campaignProxyCandidates(ctx context.Context, exec store.Querier, campaign *models.Campaign) ([]*models.Proxy, error) {
+	return proxyCandidates(ctx, exec, s.proxyStore, activeProxyEntries(s.proxyManager), campaignProxyAccess(s.appConfig, campaign), func(proxyID string) bool {
+		return s.resourceHealth.proxyUsable(campaign.ID, proxyID)
+	})
+}
+
+// campaignProxyEntries returns the active proxies a campaign may use
+func (s *httpKeywordCampaignServiceImpl) campaignProxyEntries(ctx context.Context, exec store.Querier, campaign *models.Campaign) ([]config.ProxyConfigEntry, error) {
+	entries := activeProxyEntries(s.proxyManager)
+	accessibleTo := campaignProxyAccess(s.appConfig, campaign)
+	if !accessibleTo.Valid || len(entries) == 0 {
+		return entries, nil
+	}
+	accessible, err := s.proxyStore.ListProxies(ctx, exec, store.ListProxiesFilter{AccessibleTo: accessibleTo})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list proxies: %w", err)
+	}
+	allowed := make(map[string]bool, len(accessible))
+	for _, proxy := range accessible {
+		allowed[proxy.ID.String()] = true
+	}
+	var usable []config.ProxyConfigEntry
+	for _, entry := range entries {
+		if allowed[entry.ID] {
+			usable = append(usable, entry)
+		}
+	}
+	return usable, nil
+}
+
+// campaignProxyAccess returns the user whose own and shared proxies a campaign is limited to: its
+// owner when ownership is enforced. Campaigns without an owner may use every proxy.
+func campaignProxyAccess(cfg *config.AppConfig, campaign *models.Campaign) uuid.NullUUID {
+	if cfg == nil || !cfg.ResourceAccess.OwnershipEnforced() || campaign.UserID == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *campaign.UserID, Valid: true}
+}
+
+// proxyCandidates returns the stored records of the active proxies that are enabled, not marked
+// unhealthy by health checks, usable and, when accessibleTo is valid, owned by that user or shared
+func proxyCandidates(ctx context.Context, exec store.Querier, proxyStore store.ProxyStore, activeEntries []config.ProxyConfigEntry, accessibleTo uuid.NullUUID, usable func(proxyID string) bool) ([]*models.Proxy, error) {
+	active := make(map[string]bool)
+	for _, entry := range activeEntries {
+		if usable(entry.ID) {
+			active[entry.ID] = true
+		}
+	}
+	if len(active) == 0 {
+		return nil, nil
+	}
+	enabled, healthy := true, true
+	stored, err := proxyStore.ListProxies(ctx, exec, store.ListProxiesFilter{IsEnabled: &enabled, IsHealthy: &healthy, AccessibleTo: accessibleTo})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list proxies: %w", err)
+	}
+	candidates := make([]*models.Proxy, 0, len(active))
+	for _, proxy := range stored {
+		if active[proxy.ID.String()] {
+			candidates = append(candidates, proxy)
+		}
+	}
+	return candidates, nil
+}
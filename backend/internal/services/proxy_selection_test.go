@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func selectionCandidates(latencies ...int32) []*models.Proxy {
+	candidates := make([]*models.Proxy, len(latencies))
+	for i, latency := range latencies {
+		candidates[i] = &models.Proxy{ID: uuid.New(), IsEnabled: true, IsHealthy: true}
+		if latency > 0 {
+			candidates[i].LatencyMs = sql.NullInt32{Int32: latency, Valid: true}
+		}
+	}
+	return candidates
+}
+
+// pickConcurrently picks picks proxies from workers goroutines and counts how often each was chosen
+func pickConcurrently(selector *proxySelector, campaignID uuid.UUID, strategy string, candidates []*models.Proxy, workers, picks int) map[uuid.UUID]int {
+	var mu sync.Mutex
+	counts := make(map[uuid.UUID]int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < picks/workers; i++ {
+				picked := selector.pick(campaignID, strategy, 0, candidates)
+				mu.Lock()
+				counts[picked.ID]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return counts
+}
+
+func TestProxySelector_RoundRobinDistribution(t *testing.T) {
+	selector := newProxySelector()
+	candidates := selectionCandidates(300, 20, 100)
+
+	counts := pickConcurrently(selector, uuid.New(), ProxySelectionRoundRobin, candidates, 10, 600)
+	for _, candidate := range candidates {
+		assert.Equal(t, 200, counts[candidate.ID], "workers of one campaign share the rotation")
+	}
+
+	// Campaigns rotate independently
+	first, second := uuid.New(), uuid.New()
+	assert.Equal(t, candidates[0], selector.pick(first, "", 0, candidates), "round robin is the default")
+	assert.Equal(t, candidates[0], selector.pick(second, ProxySelectionRoundRobin, 0, candidates))
+	assert.Equal(t, candidates[1], selector.pick(first, ProxySelectionRoundRobin, 0, candidates))
+}
+
+func TestProxySelector_RandomDistribution(t *testing.T) {
+	selector := newProxySelector()
+	selector.random = rand.New(rand.NewSource(7))
+	candidates := selectionCandidates(300, 20, 100, 50)
+
+	counts := pickConcurrently(selector, uuid.New(), ProxySelectionRandom, candidates, 8, 4000)
+	for _, candidate := range candidates {
+		assert.InDelta(t, 1000, counts[candidate.ID], 150, "every proxy gets about a quarter of the requests")
+	}
+}
+
+func TestProxySelector_LowestLatencyDistribution(t *testing.T) {
+	selector := newProxySelector()
+	candidates := selectionCandidates(0, 300, 20, 100)
+
+	counts := pickConcurrently(selector, uuid.New(), ProxySelectionLowestLatency, candidates, 4, 400)
+	assert.Equal(t, map[uuid.UUID]int{candidates[2].ID: 400}, counts)
+
+	// Without measured latencies the first proxy is used
+	unmeasured := selectionCandidates(0, 0)
+	assert.Equal(t, unmeasured[0], selector.pick(uuid.New(), ProxySelectionLowestLatency, 0, unmeasured))
+}
+
+func TestProxySelector_StickyRotation(t *testing.T) {
+	selector := newProxySelector()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	selector.now = func() time.Time { return now }
+	candidates := selectionCandidates(300, 20, 100)
+	campaignID := uuid.New()
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, candidates[0], selector.pick(campaignID, ProxySelectionRoundRobin, time.Minute, candidates))
+	}
+	now = now.Add(time.Minute)
+	assert.Equal(t, candidates[1], selector.pick(campaignID, ProxySelectionRoundRobin, time.Minute, candidates), "the interval passed")
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, candidates[1], selector.pick(campaignID, ProxySelectionRoundRobin, time.Minute, candidates))
+
+	// A kept proxy that stops being a candidate is replaced at once
+	assert.Equal(t, candidates[0], selector.pick(campaignID, ProxySelectionRoundRobin, time.Minute, []*models.Proxy{candidates[0], candidates[2]}))
+
+	// A stopped campaign starts over
+	selector.forget(campaignID)
+	assert.Equal(t, candidates[0], selector.pick(campaignID, ProxySelectionRoundRobin, time.Minute, candidates))
+	assert.Nil(t, selector.pick(campaignID, ProxySelectionRoundRobin, 0, nil))
+}
+
+func TestCampaignProxyCandidates_SkipsUnhealthyProxies(t *testing.T) {
+	healthy := newCheckedProxy(models.ProxyProtocolHTTP, true)
+	unhealthy := newCheckedProxy(models.ProxyProtocolHTTP, true)
+	unhealthy.IsHealthy = false
+	failedAtStart := newCheckedProxy(models.ProxyProtocolHTTP, true)
+	inactive := newCheckedProxy(models.ProxyProtocolHTTP, true)
+	_, ps := newTestProxyHealthChecker(healthy, unhealthy, failedAtStart, inactive)
+	active := []config.ProxyConfigEntry{{ID: healthy.ID.String()}, {ID: unhealthy.ID.String()}, {ID: failedAtStart.ID.String()}}
+	usable := func(proxyID string) bool { return proxyID != failedAtStart.ID.String() }
+
+	candidates, err := proxyCandidates(context.Background(), nil, ps, active, uuid.NullUUID{}, usable)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, healthy.ID, candidates[0].ID)
+
+	// Without a proxy manager there are no active proxies to pick from
+	s := &httpKeywordCampaignServiceImpl{proxyStore: ps}
+	candidates, err = s.campaignProxyCandidates(context.Background(), nil, &models.Campaign{ID: uuid.New()})
+	require.NoError(t, err)
+	assert.Empty(t, candidates)
+}
+
+func TestCampaignProxyCandidates_LimitedToOwnedAndSharedProxies(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	owned := newCheckedProxy(models.ProxyProtocolHTTP, true)
+	owned.OwnerID = &owner
+	shared := newCheckedProxy(models.ProxyProtocolHTTP, true)
+	shared.OwnerID, shared.Shared = &other, true
+	private := newCheckedProxy(models.ProxyProtocolHTTP, true)
+	private.OwnerID = &other
+	_, ps := newTestProxyHealthChecker(owned, shared, private)
+	active := []config.ProxyConfigEntry{{ID: owned.ID.String()}, {ID: shared.ID.String()}, {ID: private.ID.String()}}
+	usable := func(string) bool { return true }
+	enforced := true
+	campaign := &models.Campaign{ID: uuid.New(), UserID: &owner}
+
+	accessibleTo := campaignProxyAccess(&config.AppConfig{ResourceAccess: config.ResourceAccessConfig{EnforceOwnership: &enforced}}, campaign)
+	candidates, err := proxyCandidates(context.Background(), nil, ps, active, accessibleTo, usable)
+	require.NoError(t, err)
+	var ids []uuid.UUID
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.ID)
+	}
+	assert.ElementsMatch(t, []uuid.UUID{owned.ID, shared.ID}, ids, "another user's private proxy is never picked")
+
+	// Without enforcement, and for campaigns without an owner, every proxy may be used
+	assert.False(t, campaignProxyAccess(&config.AppConfig{}, campaign).Valid)
+	assert.False(t, campaignProxyAccess(&config.AppConfig{ResourceAccess: config.ResourceAccessConfig{EnforceOwnership: &enforced}},
+		&models.Campaign{ID: uuid.New()}).Valid)
+}
+
+func TestValidateProxySelectionStrategy(t *testing.T) {
+	strategy, err := ValidateProxySelectionStrategy("")
+	require.NoError(t, err)
+	assert.Equal(t, ProxySelectionRoundRobin, strategy)
+	strategy, err = ValidateProxySelectionStrategy(" Lowest_Latency ")
+	require.NoError(t, err)
+	assert.Equal(t, ProxySelectionLowestLatency, strategy)
+	_, err = ValidateProxySelectionStrategy("fastest")
+	assert.Error(t, err)
+}
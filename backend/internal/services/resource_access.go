@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrResourceAccessDenied is returned when a campaign references a persona that is neither owned by
+// nor shared with the requesting user
+var ErrResourceAccessDenied = errors.New("resource access denied")
+
+// ResourceAccessChecker enforces persona ownership when campaigns are created
+type ResourceAccessChecker struct {
+	db           *sqlx.DB
+	personaStore store.PersonaStore
+}
+
+// NewResourceAccessChecker creates a checker that loads referenced personas from personaStore
+func NewResourceAccessChecker(db *sqlx.DB, personaStore store.PersonaStore) *ResourceAccessChecker {
+	return &ResourceAccessChecker{db: db, personaStore: personaStore}
+}
+
+// CheckCampaignRequest returns an error wrapping ErrResourceAccessDenied when req references a
+// persona userID may not use. Personas that do not exist are left for campaign creation to reject.
+func (c *ResourceAccessChecker) CheckCampaignRequest(ctx context.Context, userID uuid.UUID, req CreateCampaignRequest) error {
+	var querier store.Querier
+	if c.db != nil {
+		querier = c.db
+	}
+	for _, personaID := range campaignPersonaIDs(req) {
+		persona, err := c.personaStore.GetPersonaByID(ctx, querier, personaID)
+		if errors.Is(err, store.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load persona %s: %w", personaID, err)
+		}
+		if !persona.AccessibleTo(userID) {
+			return fmt.Errorf("%w: persona %s is not owned by or shared with the user", ErrResourceAccessDenied, personaID)
+		}
+	}
+	return nil
+}
+
+// campaignPersonaIDs returns the personas a creation request references
+func campaignPersonaIDs(req CreateCampaignRequest) []uuid.UUID {
+	var personaIDs []uuid.UUID
+	if req.DnsValidationParams != nil {
+		personaIDs = append(personaIDs, req.DnsValidationParams.PersonaIDs...)
+	}
+	if req.HttpKeywordParams != nil {
+		personaIDs = append(personaIDs, req.HttpKeywordParams.PersonaIDs...)
+	}
+	if req.DomainGenerationParams != nil && req.DomainGenerationParams.DNSPromotion != nil {
+		personaIDs = append(personaIDs, req.DomainGenerationParams.DNSPromotion.PersonaIDs...)
+	}
+	return personaIDs
+}
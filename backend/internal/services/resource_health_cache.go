@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/proxymanager"
+	"github.com/google/uuid"
+)
+
+// resourceHealth is the outcome of testing one persona or proxy before a campaign uses it
+type resourceHealth struct {
+	healthy   bool
+	err       string
+	checkedAt time.Time
+	version   time.Time // Persona UpdatedAt when tested; a changed persona is tested again
+}
+
+// campaignResourceHealth holds the start-up test results of one campaign's personas and proxies
+type campaignResourceHealth struct {
+	personas map[uuid.UUID]resourceHealth
+	proxies  map[string]resourceHealth
+}
+
+// resourceHealthCache tests a campaign's personas and proxies once, with bounded concurrency and a
+// time box, when the campaign starts and keeps the results for as long as the campaign runs, so
+// workers skip known-bad resources instead of probing them again batch after batch. Resources
+// without a result, including those the time box cut off, are used as before.
+type resourceHealthCache struct {
+	mu          sync.Mutex
+	campaigns   map[uuid.UUID]*campaignResourceHealth
+	concurrency int
+	timeout     time.Duration
+
+	checkPersona func(ctx context.Context, persona *models.Persona) error
+	checkProxy   func(ctx context.Context, proxy config.ProxyConfigEntry) error
+	now          func() time.Time
+}
+
+// newResourceHealthCache returns nil when pre-warming is disabled. Results for a proxy are dropped
+// whenever the proxy manager quarantines or reinstates it.
+func newResourceHealthCache(appCfg *config.AppConfig, pm *proxymanager.ProxyManager) *resourceHealthCache {
+	if appCfg == nil || !appCfg.Worker.ResourcePrewarmEnabled() {
+		return nil
+	}
+	cache := &resourceHealthCache{
+		campaigns:    make(map[uuid.UUID]*campaignResourceHealth),
+		concurrency:  appCfg.Worker.PrewarmConcurrency,
+		timeout:      time.Duration(appCfg.Worker.PrewarmTimeoutSeconds) * time.Second,
+		checkPersona: checkPersonaConfig,
+		now:          time.Now,
+	}
+	if cache.concurrency <= 0 {
+		cache.concurrency = config.DefaultPrewarmConcurrency
+	}
+	if cache.timeout <= 0 {
+		cache.timeout = config.DefaultPrewarmTimeoutSeconds * time.Second
+	}
+	if pm != nil {
+		cache.checkProxy = func(ctx context.Context, proxy config.ProxyConfigEntry) error {
+			if result := pm.CheckProxy(ctx, proxy); !result.Success {
+				return fmt.Errorf("%s", result.Error)
+			}
+			return nil
+		}
+		pm.AddQuarantineListener(func(event proxymanager.ProxyQuarantineEvent) {
+			cache.invalidateProxy(event.ProxyID)
+		})
+	}
+	return cache
+}
+
+// checkPersonaConfig tests that a persona's configuration can drive its validator
+func checkPersonaConfig(ctx context.Context, persona *models.Persona) error {
+	if !persona.IsEnabled {
+		return fmt.Errorf("persona is disabled")
+	}
+	switch persona.PersonaType {
+	case models.PersonaTypeDNS:
+		var details models.DNSConfigDetails
+		if err := json.Unmarshal(persona.ConfigDetails, &details); err != nil {
+			return fmt.Errorf("invalid DNS config: %w", err)
+		}
+		if len(details.Resolvers) == 0 && !details.UseSystemResolvers {
+			return fmt.Errorf("DNS config has no resolvers")
+		}
+	case models.PersonaTypeHTTP:
+		var details models.HTTPConfigDetails
+		if err := json.Unmarshal(persona.ConfigDetails, &details); err != nil {
+			return fmt.Errorf("invalid HTTP config: %w", err)
+		}
+		if details.UserAgent == "" {
+			return fmt.Errorf("HTTP config has no user agent")
+		}
+	}
+	return nil
+}
+
+// activeProxyEntries returns the proxies the manager currently hands out
+func activeProxyEntries(pm *proxymanager.ProxyManager) []config.ProxyConfigEntry {
+	if pm == nil {
+		return nil
+	}
+	var entries []config.ProxyConfigEntry
+	for _, status := range pm.GetAllProxyStatuses() {
+		enabled := status.UserEnabled == nil || *status.UserEnabled
+		if status.IsHealthy && enabled && !status.Quarantined {
+			entries = append(entries, status.ProxyConfigEntry)
+		}
+	}
+	return entries
+}
+
+// prewarm tests the campaign's personas and proxies unless they were already tested for it. It
+// returns whether the tests ran. Tests still running when the time box ends record no result.
+func (c *resourceHealthCache) prewarm(ctx context.Context, campaignID uuid.UUID, personas []*models.Persona, proxies []config.ProxyConfigEntry) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	if _, ok := c.campaigns[campaignID]; ok {
+		c.mu.Unlock()
+		return false
+	}
+	entry := &campaignResourceHealth{personas: make(map[uuid.UUID]resourceHealth), proxies: make(map[string]resourceHealth)}
+	c.campaigns[campaignID] = entry
+	c.mu.Unlock()
+
+	prewarmCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, c.concurrency)
+	run := func(check func(ctx context.Context) error, record func(resourceHealth)) {
+		select {
+		case semaphore <- struct{}{}:
+		case <-prewarmCtx.Done():
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			err := check(prewarmCtx)
+			if prewarmCtx.Err() != nil {
+				return // Cut off by the time box; the result says nothing about the resource
+			}
+			health := resourceHealth{healthy: err == nil, checkedAt: c.now()}
+			if err != nil {
+				health.err = err.Error()
+			}
+			c.mu.Lock()
+			record(health)
+			c.mu.Unlock()
+		}()
+	}
+
+	for _, persona := range personas {
+		persona := persona
+		run(func(ctx context.Context) error { return c.checkPersona(ctx, persona) }, func(health resourceHealth) {
+			health.version = persona.UpdatedAt
+			entry.personas[persona.ID] = health
+		})
+	}
+	if c.checkProxy != nil {
+		for _, proxy := range proxies {
+			proxy := proxy
+			run(func(ctx context.Context) error { return c.checkProxy(ctx, proxy) }, func(health resourceHealth) {
+				entry.proxies[proxy.ID] = health
+			})
+		}
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	personaCount, proxyCount, unhealthy := len(entry.personas), len(entry.proxies), 0
+	for _, health := range entry.personas {
+		if !health.healthy {
+			unhealthy++
+		}
+	}
+	for _, health := range entry.proxies {
+		if !health.healthy {
+			unhealthy++
+		}
+	}
+	c.mu.Unlock()
+	log.Printf("ResourceHealthCache: Tested %d/%d personas and %d/%d proxies for campaign %s; %d unhealthy.",
+		personaCount, len(personas), proxyCount, len(proxies), campaignID, unhealthy)
+	return true
+}
+
+// usablePersonas drops personas whose start-up test failed. A persona changed since its test is
+// kept and its result forgotten. If no persona would remain, all are returned unchanged.
+func (c *resourceHealthCache) usablePersonas(campaignID uuid.UUID, personas []*models.Persona) []*models.Persona {
+	if c == nil {
+		return personas
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.campaigns[campaignID]
+	if !ok {
+		return personas
+	}
+	usable := make([]*models.Persona, 0, len(personas))
+	for _, persona := range personas {
+		health, tested := entry.personas[persona.ID]
+		if tested && !health.version.Equal(persona.UpdatedAt) {
+			delete(entry.personas, persona.ID)
+			tested = false
+		}
+		if tested && !health.healthy {
+			continue
+		}
+		usable = append(usable, persona)
+	}
+	if len(usable) == 0 {
+		log.Printf("ResourceHealthCache: Every persona of campaign %s failed its start-up test; using them anyway.", campaignID)
+		return personas
+	}
+	return usable
+}
+
+// proxyUsable reports whether a proxy has no failed start-up test for the campaign
+func (c *resourceHealthCache) proxyUsable(campaignID uuid.UUID, proxyID string) bool {
+	if c == nil {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.campaigns[campaignID]
+	if !ok {
+		return true
+	}
+	health, tested := entry.proxies[proxyID]
+	return !tested || health.healthy
+}
+
+// invalidateProxy forgets every campaign's result for a proxy whose health changed
+func (c *resourceHealthCache) invalidateProxy(proxyID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, entry := range c.campaigns {
+		delete(entry.proxies, proxyID)
+	}
+}
+
+// forget drops a campaign's results once it stops running
+func (c *resourceHealthCache) forget(campaignID uuid.UUID) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.campaigns, campaignID)
+}
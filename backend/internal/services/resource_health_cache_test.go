@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCountingHealthCache returns a cache whose checks count their calls and fail for the given IDs
+func newCountingHealthCache(failing map[string]bool, personaChecks, proxyChecks *int32) *resourceHealthCache {
+	return &resourceHealthCache{
+		campaigns:   make(map[uuid.UUID]*campaignResourceHealth),
+		concurrency: 2,
+		timeout:     time.Second,
+		checkPersona: func(ctx context.Context, persona *models.Persona) error {
+			atomic.AddInt32(personaChecks, 1)
+			if failing[persona.ID.String()] {
+				return errors.New("persona test failed")
+			}
+			return nil
+		},
+		checkProxy: func(ctx context.Context, proxy config.ProxyConfigEntry) error {
+			atomic.AddInt32(proxyChecks, 1)
+			if failing[proxy.ID] {
+				return errors.New("proxy test failed")
+			}
+			return nil
+		},
+		now: time.Now,
+	}
+}
+
+func TestResourceHealthCache_PrewarmRunsOncePerCampaign(t *testing.T) {
+	good := &models.Persona{ID: uuid.New(), PersonaType: models.PersonaTypeHTTP, IsEnabled: true}
+	bad := &models.Persona{ID: uuid.New(), PersonaType: models.PersonaTypeHTTP, IsEnabled: true}
+	proxies := []config.ProxyConfigEntry{{ID: "proxy-ok"}, {ID: "proxy-down"}}
+	var personaChecks, proxyChecks int32
+	cache := newCountingHealthCache(map[string]bool{bad.ID.String(): true, "proxy-down": true}, &personaChecks, &proxyChecks)
+	campaignID := uuid.New()
+
+	for batch := 0; batch < 5; batch++ {
+		ran := cache.prewarm(context.Background(), campaignID, []*models.Persona{good, bad}, proxies)
+		assert.Equal(t, batch == 0, ran, "batch %d", batch)
+
+		personas := cache.usablePersonas(campaignID, []*models.Persona{good, bad})
+		assert.Equal(t, []*models.Persona{good}, personas, "batch %d", batch)
+		assert.True(t, cache.proxyUsable(campaignID, "proxy-ok"))
+		assert.False(t, cache.proxyUsable(campaignID, "proxy-down"))
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&personaChecks), "every persona is tested once for the campaign")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&proxyChecks), "every proxy is tested once for the campaign")
+
+	// Another campaign is tested separately, and a finished campaign is tested again if restarted
+	assert.True(t, cache.prewarm(context.Background(), uuid.New(), []*models.Persona{good}, nil))
+	cache.forget(campaignID)
+	assert.True(t, cache.prewarm(context.Background(), campaignID, []*models.Persona{good}, nil))
+	assert.Equal(t, int32(4), atomic.LoadInt32(&personaChecks))
+}
+
+func TestResourceHealthCache_InvalidatesOnHealthChanges(t *testing.T) {
+	persona := &models.Persona{ID: uuid.New(), PersonaType: models.PersonaTypeHTTP, UpdatedAt: time.Now()}
+	var personaChecks, proxyChecks int32
+	cache := newCountingHealthCache(map[string]bool{persona.ID.String(): true, "proxy-down": true}, &personaChecks, &proxyChecks)
+	campaignID := uuid.New()
+	other := &models.Persona{ID: uuid.New(), PersonaType: models.PersonaTypeHTTP}
+	cache.prewarm(context.Background(), campaignID, []*models.Persona{persona, other}, []config.ProxyConfigEntry{{ID: "proxy-down"}})
+	require.Equal(t, []*models.Persona{other}, cache.usablePersonas(campaignID, []*models.Persona{persona, other}))
+	require.False(t, cache.proxyUsable(campaignID, "proxy-down"))
+
+	// The proxy manager reinstating a proxy and an edited persona both drop the stale results
+	cache.invalidateProxy("proxy-down")
+	assert.True(t, cache.proxyUsable(campaignID, "proxy-down"))
+	edited := *persona
+	edited.UpdatedAt = persona.UpdatedAt.Add(time.Minute)
+	assert.Len(t, cache.usablePersonas(campaignID, []*models.Persona{&edited, other}), 2)
+
+	// With every persona failing, the campaign keeps using them rather than stopping
+	assert.Equal(t, []*models.Persona{persona}, cache.usablePersonas(campaignID, []*models.Persona{persona}))
+}
+
+func TestResourceHealthCache_TimeBoxLeavesUnfinishedTestsUnrecorded(t *testing.T) {
+	slow := &models.Persona{ID: uuid.New()}
+	cache := &resourceHealthCache{
+		campaigns:   make(map[uuid.UUID]*campaignResourceHealth),
+		concurrency: 1,
+		timeout:     20 * time.Millisecond,
+		checkPersona: func(ctx context.Context, persona *models.Persona) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		now: time.Now,
+	}
+	campaignID := uuid.New()
+
+	start := time.Now()
+	require.True(t, cache.prewarm(context.Background(), campaignID, []*models.Persona{slow, {ID: uuid.New()}}, nil))
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, []*models.Persona{slow}, cache.usablePersonas(campaignID, []*models.Persona{slow}), "a timed-out test is not a failure")
+}
+
+func TestNewResourceHealthCache_DisabledByDefault(t *testing.T) {
+	assert.Nil(t, newResourceHealthCache(&config.AppConfig{}, nil))
+
+	enabled := true
+	cache := newResourceHealthCache(&config.AppConfig{Worker: config.WorkerConfig{PrewarmResourceHealth: &enabled}}, nil)
+	require.NotNil(t, cache)
+	assert.Equal(t, config.DefaultPrewarmConcurrency, cache.concurrency)
+	assert.Equal(t, config.DefaultPrewarmTimeoutSeconds*time.Second, cache.timeout)
+
+	var nilCache *resourceHealthCache
+	personas := []*models.Persona{{ID: uuid.New()}}
+	assert.False(t, nilCache.prewarm(context.Background(), uuid.New(), personas, nil))
+	assert.Equal(t, personas, nilCache.usablePersonas(uuid.New(), personas))
+}
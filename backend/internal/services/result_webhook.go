@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+)
+
+// WebhookEventValidationResults is the event type of result batches sent by ResultWebhookStreamer
+const WebhookEventValidationResults = "campaign.validation_results"
+
+// ResultWebhookItem is one validation result in a result batch
+type ResultWebhookItem struct {
+	CampaignID   uuid.UUID   `json:"campaignId"`
+	CampaignType string      `json:"campaignType"`
+	Result       interface{} `json:"result"` // models.DNSValidationResult or models.HTTPKeywordResult
+}
+
+// ResultWebhookBatch is the payload of a result batch delivery
+type ResultWebhookBatch struct {
+	Sequence int64               `json:"sequence"`          // Increases by one with every batch sent by the server
+	Dropped  int64               `json:"dropped,omitempty"` // Results dropped from a full buffer since the previous batch
+	Results  []ResultWebhookItem `json:"results"`
+}
+
+// ResultWebhookStreamer sends validation results to the configured webhook in batches, through the
+// WebhookService so batches are signed and recorded like any other event delivery. Publish only
+// buffers, so a slow or failing webhook never holds up validation; batches are sent in order by
+// one goroutine, which retries a failed batch with exponential backoff before giving it up.
+type ResultWebhookStreamer struct {
+	webhooks      *WebhookService
+	url           string
+	batchSize     int
+	flushInterval time.Duration
+	maxAttempts   int
+	retryBackoff  time.Duration
+	maxBuffered   int
+
+	mu       sync.Mutex
+	pending  []ResultWebhookItem
+	dropped  int64
+	sequence int64
+	full     chan struct{} // Signalled when a full batch is pending
+}
+
+// NewResultWebhookStreamer creates a streamer for the configured webhook, or returns nil when no
+// result webhook URL is configured
+func NewResultWebhookStreamer(webhooks *WebhookService, cfg config.ResultWebhookConfig) *ResultWebhookStreamer {
+	if cfg.URL == "" || webhooks == nil {
+		return nil
+	}
+	streamer := &ResultWebhookStreamer{
+		webhooks:      webhooks,
+		url:           cfg.URL,
+		batchSize:     cfg.BatchSize,
+		flushInterval: time.Duration(cfg.FlushIntervalSeconds) * time.Second,
+		maxAttempts:   cfg.MaxAttempts,
+		retryBackoff:  time.Duration(cfg.RetryBackoffSeconds) * time.Second,
+		maxBuffered:   cfg.MaxBufferedResults,
+		full:          make(chan struct{}, 1),
+	}
+	if streamer.batchSize <= 0 {
+		streamer.batchSize = config.DefaultResultWebhookBatchSize
+	}
+	if streamer.flushInterval <= 0 {
+		streamer.flushInterval = time.Duration(config.DefaultResultWebhookFlushIntervalSeconds) * time.Second
+	}
+	if streamer.maxAttempts <= 0 {
+		streamer.maxAttempts = config.DefaultResultWebhookMaxAttempts
+	}
+	if streamer.retryBackoff <= 0 {
+		streamer.retryBackoff = time.Duration(config.DefaultResultWebhookRetryBackoffSeconds) * time.Second
+	}
+	if streamer.maxBuffered <= 0 {
+		streamer.maxBuffered = config.DefaultResultWebhookMaxBufferedResults
+	}
+	if streamer.maxBuffered < streamer.batchSize {
+		streamer.maxBuffered = streamer.batchSize
+	}
+	return streamer
+}
+
+// Publish buffers results for the webhook without blocking. When the buffer is full the oldest
+// results are dropped and counted in the next batch.
+func (s *ResultWebhookStreamer) Publish(items ...ResultWebhookItem) {
+	if s == nil || len(items) == 0 {
+		return
+	}
+	s.mu.Lock()
+	s.pending = append(s.pending, items...)
+	if overflow := len(s.pending) - s.maxBuffered; overflow > 0 {
+		s.pending = append([]ResultWebhookItem(nil), s.pending[overflow:]...)
+		s.dropped += int64(overflow)
+	}
+	isFull := len(s.pending) >= s.batchSize
+	s.mu.Unlock()
+
+	if isFull {
+		select {
+		case s.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Start sends full batches as they fill and partial batches every flush interval until ctx is cancelled
+func (s *ResultWebhookStreamer) Start(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.full:
+				s.flush(ctx, false)
+			case <-ticker.C:
+				s.flush(ctx, true)
+			}
+		}
+	}()
+}
+
+// flush sends every full pending batch and, with partial set, the remainder as well
+func (s *ResultWebhookStreamer) flush(ctx context.Context, partial bool) {
+	for ctx.Err() == nil {
+		batch, ok := s.nextBatch(partial)
+		if !ok {
+			return
+		}
+		s.send(ctx, batch)
+	}
+}
+
+func (s *ResultWebhookStreamer) nextBatch(partial bool) (*ResultWebhookBatch, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.pending) == 0 || (!partial && len(s.pending) < s.batchSize) {
+		return nil, false
+	}
+	n := len(s.pending)
+	if n > s.batchSize {
+		n = s.batchSize
+	}
+	s.sequence++
+	batch := &ResultWebhookBatch{
+		Sequence: s.sequence,
+		Dropped:  s.dropped,
+		Results:  append([]ResultWebhookItem(nil), s.pending[:n]...),
+	}
+	s.pending = s.pending[n:]
+	s.dropped = 0
+	return batch, true
+}
+
+// send delivers a batch, retrying a failed delivery up to the attempt limit. A batch that is given
+// up stays recorded as a failed event delivery and can be replayed by an admin.
+func (s *ResultWebhookStreamer) send(ctx context.Context, batch *ResultWebhookBatch) {
+	delivery, err := s.webhooks.Dispatch(ctx, WebhookEventValidationResults, s.url, batch)
+	if delivery == nil {
+		log.Printf("ResultWebhookStreamer: Failed to send result batch %d: %v", batch.Sequence, err)
+		return
+	}
+	backoff := s.retryBackoff
+	for attempt := 1; delivery.Status != models.EventDeliveryStatusDelivered; attempt++ {
+		if attempt >= s.maxAttempts {
+			log.Printf("ResultWebhookStreamer: Giving up result batch %d (delivery %s) after %d attempts", batch.Sequence, delivery.ID, attempt)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if delivery, err = s.webhooks.Replay(ctx, delivery.ID); delivery == nil {
+			log.Printf("ResultWebhookStreamer: Failed to retry result batch %d: %v", batch.Sequence, err)
+			return
+		}
+	}
+}
+
+// resultStreamingCampaignStore publishes validation results to a ResultWebhookStreamer once they
+// are saved. Results of a chunked write that failed part-way are published as far as they were
+// committed.
+type resultStreamingCampaignStore struct {
+	store.CampaignStore
+	streamer *ResultWebhookStreamer
+}
+
+// NewResultStreamingCampaignStore wraps campaignStore so saved DNS and HTTP validation results are
+// streamed to the result webhook. It returns campaignStore itself when streamer is nil.
+func NewResultStreamingCampaignStore(campaignStore store.CampaignStore, streamer *ResultWebhookStreamer) store.CampaignStore {
+	if streamer == nil {
+		return campaignStore
+	}
+	return &resultStreamingCampaignStore{CampaignStore: campaignStore, streamer: streamer}
+}
+
+func (s *resultStreamingCampaignStore) CreateDNSValidationResults(ctx context.Context, exec store.Querier, results []*models.DNSValidationResult) error {
+	err := s.CampaignStore.CreateDNSValidationResults(ctx, exec, results)
+	committed := committedResults(len(results), err)
+	items := make([]ResultWebhookItem, 0, committed)
+	for _, result := range results[:committed] {
+		items = append(items, ResultWebhookItem{CampaignID: result.DNSCampaignID, CampaignType: string(models.CampaignTypeDNSValidation), Result: result})
+	}
+	s.streamer.Publish(items...)
+	return err
+}
+
+func (s *resultStreamingCampaignStore) CreateHTTPKeywordResults(ctx context.Context, exec store.Querier, results []*models.HTTPKeywordResult) error {
+	err := s.CampaignStore.CreateHTTPKeywordResults(ctx, exec, results)
+	committed := committedResults(len(results), err)
+	items := make([]ResultWebhookItem, 0, committed)
+	for _, result := range results[:committed] {
+		items = append(items, ResultWebhookItem{CampaignID: result.HTTPKeywordCampaignID, CampaignType: string(models.CampaignTypeHTTPKeywordValidation), Result: result})
+	}
+	s.streamer.Publish(items...)
+	return err
+}
+
+// committedResults returns how many of n results a write that returned err saved
+func committedResults(n int, err error) int {
+	if err == nil {
+		return n
+	}
+	var chunkErr *store.ChunkedWriteError
+	if errors.As(err, &chunkErr) && chunkErr.CommittedRows <= n {
+		return chunkErr.CommittedRows
+	}
+	return 0
+}
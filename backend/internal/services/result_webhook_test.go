@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resultSavingCampaignStore accepts results, failing part-way with saveErr when it is set
+type resultSavingCampaignStore struct {
+	store.CampaignStore
+	saveErr error
+}
+
+func (s *resultSavingCampaignStore) CreateDNSValidationResults(ctx context.Context, exec store.Querier, results []*models.DNSValidationResult) error {
+	return s.saveErr
+}
+
+func (s *resultSavingCampaignStore) CreateHTTPKeywordResults(ctx context.Context, exec store.Querier, results []*models.HTTPKeywordResult) error {
+	return s.saveErr
+}
+
+// batchReceiver records the result batches posted to it, failing the first failures requests
+type batchReceiver struct {
+	t        *testing.T
+	secret   string
+	failures int32
+	requests atomic.Int32
+	mu       sync.Mutex
+	batches  []ResultWebhookBatch
+}
+
+func (r *batchReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	timestamp, err := strconv.ParseInt(req.Header.Get(WebhookTimestampHeader), 10, 64)
+	if assert.NoError(r.t, err) {
+		assert.Equal(r.t, SignWebhookPayload(r.secret, timestamp, body), req.Header.Get(WebhookSignatureHeader))
+	}
+	assert.Equal(r.t, WebhookEventValidationResults, req.Header.Get(WebhookEventHeader))
+	if r.requests.Add(1) <= r.failures {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var batch ResultWebhookBatch
+	require.NoError(r.t, json.Unmarshal(body, &batch))
+	r.mu.Lock()
+	r.batches = append(r.batches, batch)
+	r.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+func (r *batchReceiver) received() []ResultWebhookBatch {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ResultWebhookBatch(nil), r.batches...)
+}
+
+func newTestResultStreamer(t *testing.T, receiver *batchReceiver, deliveryStore *memoryEventDeliveryStore, cfg config.ResultWebhookConfig) *ResultWebhookStreamer {
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+	cfg.URL = server.URL
+	webhooks := NewWebhookService(deliveryStore, config.WebhookConfig{SigningSecret: receiver.secret, TimeoutSeconds: 5})
+	streamer := NewResultWebhookStreamer(webhooks, cfg)
+	require.NotNil(t, streamer)
+	streamer.flushInterval = 20 * time.Millisecond
+	streamer.retryBackoff = 5 * time.Millisecond
+	return streamer
+}
+
+func dnsResults(campaignID uuid.UUID, domains ...string) []*models.DNSValidationResult {
+	results := make([]*models.DNSValidationResult, 0, len(domains))
+	for _, domain := range domains {
+		results = append(results, &models.DNSValidationResult{ID: uuid.New(), DNSCampaignID: campaignID, DomainName: domain, ValidationStatus: "resolved"})
+	}
+	return results
+}
+
+func TestResultWebhookStreamer_DeliversSavedResultsInBatches(t *testing.T) {
+	receiver := &batchReceiver{t: t, secret: "results-secret"}
+	streamer := newTestResultStreamer(t, receiver, newMemoryEventDeliveryStore(), config.ResultWebhookConfig{BatchSize: 2, MaxAttempts: 3})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	streamer.Start(ctx)
+
+	cs := NewResultStreamingCampaignStore(&resultSavingCampaignStore{}, streamer)
+	dnsCampaignID, httpCampaignID := uuid.New(), uuid.New()
+	require.NoError(t, cs.CreateDNSValidationResults(ctx, nil, dnsResults(dnsCampaignID, "a.com", "b.com", "c.com")))
+	require.NoError(t, cs.CreateHTTPKeywordResults(ctx, nil, []*models.HTTPKeywordResult{
+		{ID: uuid.New(), HTTPKeywordCampaignID: httpCampaignID, DomainName: "a.com", ValidationStatus: "lead_valid"},
+		{ID: uuid.New(), HTTPKeywordCampaignID: httpCampaignID, DomainName: "b.com", ValidationStatus: "http_valid_no_keywords"},
+	}))
+
+	// Full batches go out at once and the remainder after the flush interval
+	var items []ResultWebhookItem
+	require.Eventually(t, func() bool {
+		items = nil
+		for _, batch := range receiver.received() {
+			items = append(items, batch.Results...)
+		}
+		return len(items) == 5
+	}, 2*time.Second, 10*time.Millisecond)
+	var domains []string
+	for i, batch := range receiver.received() {
+		assert.Equal(t, int64(i+1), batch.Sequence)
+		assert.LessOrEqual(t, len(batch.Results), 2)
+	}
+	for _, item := range items {
+		domains = append(domains, item.Result.(map[string]interface{})["domainName"].(string))
+	}
+	assert.Equal(t, []string{"a.com", "b.com", "c.com", "a.com", "b.com"}, domains)
+	assert.Equal(t, dnsCampaignID, items[0].CampaignID)
+	assert.Equal(t, string(models.CampaignTypeDNSValidation), items[0].CampaignType)
+	assert.Equal(t, httpCampaignID, items[4].CampaignID)
+	assert.Equal(t, string(models.CampaignTypeHTTPKeywordValidation), items[4].CampaignType)
+}
+
+func TestResultWebhookStreamer_RetriesFailedBatches(t *testing.T) {
+	receiver := &batchReceiver{t: t, secret: "results-secret", failures: 2}
+	deliveryStore := newMemoryEventDeliveryStore()
+	streamer := newTestResultStreamer(t, receiver, deliveryStore, config.ResultWebhookConfig{BatchSize: 10, MaxAttempts: 3})
+	ctx := context.Background()
+
+	streamer.Publish(ResultWebhookItem{CampaignID: uuid.New(), CampaignType: string(models.CampaignTypeDNSValidation), Result: "a.com"})
+	streamer.flush(ctx, true)
+
+	require.Len(t, receiver.received(), 1)
+	assert.Equal(t, int32(3), receiver.requests.Load())
+	deliveries, err := deliveryStore.ListEventDeliveries(ctx, nil, store.ListEventDeliveriesFilter{})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.EventDeliveryStatusDelivered, deliveries[0].Status)
+	assert.Equal(t, 3, deliveries[0].Attempts)
+
+	// A batch failing every attempt is given up and left failed for replay
+	receiver.failures = 100
+	streamer.Publish(ResultWebhookItem{CampaignID: uuid.New(), CampaignType: string(models.CampaignTypeDNSValidation), Result: "b.com"})
+	streamer.flush(ctx, true)
+	assert.Equal(t, int32(6), receiver.requests.Load())
+	failed, err := deliveryStore.ListEventDeliveries(ctx, nil, store.ListEventDeliveriesFilter{Status: models.EventDeliveryStatusFailed})
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, 3, failed[0].Attempts)
+}
+
+func TestResultWebhookStreamer_DropsOldestWhenBufferFull(t *testing.T) {
+	streamer := NewResultWebhookStreamer(NewWebhookService(newMemoryEventDeliveryStore(), config.WebhookConfig{}),
+		config.ResultWebhookConfig{URL: "http://127.0.0.1:0", BatchSize: 2, MaxBufferedResults: 3})
+	for _, domain := range []string{"a.com", "b.com", "c.com", "d.com", "e.com"} {
+		streamer.Publish(ResultWebhookItem{Result: domain})
+	}
+
+	batch, ok := streamer.nextBatch(false)
+	require.True(t, ok)
+	assert.Equal(t, int64(2), batch.Dropped)
+	assert.Equal(t, []ResultWebhookItem{{Result: "c.com"}, {Result: "d.com"}}, batch.Results)
+
+	_, ok = streamer.nextBatch(false)
+	assert.False(t, ok, "a partial batch waits for the flush interval")
+	batch, ok = streamer.nextBatch(true)
+	require.True(t, ok)
+	assert.Zero(t, batch.Dropped)
+	assert.Equal(t, []ResultWebhookItem{{Result: "e.com"}}, batch.Results)
+}
+
+func TestResultStreamingCampaignStore_PublishesCommittedChunksOnly(t *testing.T) {
+	streamer := NewResultWebhookStreamer(NewWebhookService(newMemoryEventDeliveryStore(), config.WebhookConfig{}),
+		config.ResultWebhookConfig{URL: "http://127.0.0.1:0", BatchSize: 10})
+	saveErr := &store.ChunkedWriteError{CommittedChunks: 1, TotalChunks: 2, CommittedRows: 2, Err: io.ErrUnexpectedEOF}
+	cs := NewResultStreamingCampaignStore(&resultSavingCampaignStore{saveErr: saveErr}, streamer)
+
+	err := cs.CreateDNSValidationResults(context.Background(), nil, dnsResults(uuid.New(), "a.com", "b.com", "c.com"))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	batch, ok := streamer.nextBatch(true)
+	require.True(t, ok)
+	assert.Len(t, batch.Results, 2)
+
+	// Without a streamer the store is returned unwrapped
+	inner := &resultSavingCampaignStore{}
+	assert.Same(t, inner, NewResultStreamingCampaignStore(inner, nil))
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+)
+
+// SuperAdminRoleName is the system role that is granted every essential permission
+const SuperAdminRoleName = "super_admin"
+
+// EssentialPermissions lists the permissions the application relies on. They match the rows seeded
+// by database/schema.sql; databases created before a permission was added get it, granted to the
+// super_admin role, from RolePermissionSyncService.SyncSuperAdminPermissions.
+var EssentialPermissions = []models.Permission{
+	essentialPermission("00000000-0000-0000-0001-000000000001", "campaigns", "create", "Create Campaigns", "Create new campaigns"),
+	essentialPermission("00000000-0000-0000-0001-000000000002", "campaigns", "read", "Read Campaigns", "View campaign details"),
+	essentialPermission("00000000-0000-0000-0001-000000000003", "campaigns", "update", "Update Campaigns", "Modify existing campaigns"),
+	essentialPermission("00000000-0000-0000-0001-000000000004", "campaigns", "delete", "Delete Campaigns", "Remove campaigns from system"),
+	essentialPermission("00000000-0000-0000-0001-000000000005", "campaigns", "execute", "Execute Campaigns", "Start and stop campaign execution"),
+	essentialPermission("00000000-0000-0000-0001-000000000006", "personas", "create", "Create Personas", "Create new validation personas"),
+	essentialPermission("00000000-0000-0000-0001-000000000007", "personas", "read", "Read Personas", "View persona configurations"),
+	essentialPermission("00000000-0000-0000-0001-000000000008", "personas", "update", "Update Personas", "Modify existing personas"),
+	essentialPermission("00000000-0000-0000-0001-000000000009", "personas", "delete", "Delete Personas", "Remove personas from system"),
+	essentialPermission("00000000-0000-0000-0001-000000000010", "proxies", "create", "Create Proxies", "Add new proxy servers"),
+	essentialPermission("00000000-0000-0000-0001-000000000011", "proxies", "read", "Read Proxies", "View proxy configurations and status"),
+	essentialPermission("00000000-0000-0000-0001-000000000012", "proxies", "update", "Update Proxies", "Modify existing proxy settings"),
+	essentialPermission("00000000-0000-0000-0001-000000000013", "proxies", "delete", "Delete Proxies", "Remove proxy servers"),
+	essentialPermission("00000000-0000-0000-0001-000000000014", "system", "admin", "System Administration", "Full administrative access to system"),
+	essentialPermission("00000000-0000-0000-0001-000000000015", "system", "config", "System Configuration", "Modify system configuration settings"),
+	essentialPermission("00000000-0000-0000-0001-000000000016", "users", "manage", "User Management", "Create, update, and delete user accounts"),
+	essentialPermission("00000000-0000-0000-0001-000000000017", "reports", "generate", "Generate Reports", "Generate and export system reports"),
+	essentialPermission("00000000-0000-0000-0001-000000000018", "admin", "users", "Administer Users", "Access the admin user management endpoints"),
+	essentialPermission("00000000-0000-0000-0001-000000000019", "system", "users", "User Accounts and Data Requests",
+		"List, unlock, disable and reactivate accounts, and export and erase a user's personal data"),
+	essentialPermission("00000000-0000-0000-0001-000000000020", "system", "audit", "Audit Log Access", "Query and export the audit log"),
+}
+
+func essentialPermission(id, resource, action, displayName, description string) models.Permission {
+	return models.Permission{
+		ID:          uuid.MustParse(id),
+		Name:        resource + ":" + action,
+		DisplayName: displayName,
+		Description: &description,
+		Resource:    resource,
+		Action:      action,
+	}
+}
+
+// PermissionSyncResult reports what a permission sync changed
+type PermissionSyncResult struct {
+	RoleID             uuid.UUID `json:"roleId"`
+	RoleName           string    `json:"roleName"`
+	CreatedPermissions []string  `json:"createdPermissions"`
+	GrantedPermissions []string  `json:"grantedPermissions"`
+	TotalPermissions   int       `json:"totalPermissions"`
+}
+
+// RolePermissionSyncService re-applies the essential permission seeding to an existing database
+type RolePermissionSyncService struct {
+	store       store.RolePermissionStore
+	permissions []models.Permission
+}
+
+// NewRolePermissionSyncService creates a sync service for the given essential permissions
+func NewRolePermissionSyncService(rolePermissionStore store.RolePermissionStore, permissions []models.Permission) *RolePermissionSyncService {
+	return &RolePermissionSyncService{store: rolePermissionStore, permissions: permissions}
+}
+
+// SyncSuperAdminPermissions creates any missing essential permissions and grants every one of them
+// to the super_admin role. It is idempotent: a second run reports nothing created or granted.
+func (s *RolePermissionSyncService) SyncSuperAdminPermissions(ctx context.Context) (*PermissionSyncResult, error) {
+	role, err := s.store.GetRoleByName(ctx, nil, SuperAdminRoleName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load role %s: %w", SuperAdminRoleName, err)
+	}
+
+	result := &PermissionSyncResult{
+		RoleID:             role.ID,
+		RoleName:           role.Name,
+		CreatedPermissions: []string{},
+		GrantedPermissions: []string{},
+		TotalPermissions:   len(s.permissions),
+	}
+	for _, essential := range s.permissions {
+		perm := essential
+		created, err := s.store.EnsurePermission(ctx, nil, &perm)
+		if err != nil {
+			return nil, fmt.Errorf("failed to ensure permission %s: %w", perm.Name, err)
+		}
+		if created {
+			result.CreatedPermissions = append(result.CreatedPermissions, perm.Name)
+		}
+
+		granted, err := s.store.GrantPermissionToRole(ctx, nil, role.ID, perm.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to grant permission %s to role %s: %w", perm.Name, role.Name, err)
+		}
+		if granted {
+			result.GrantedPermissions = append(result.GrantedPermissions, perm.Name)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRolePermissionStore keeps roles, permissions and grants in memory with the same conflict rules as Postgres
+type memoryRolePermissionStore struct {
+	roles       map[string]*models.Role
+	permissions map[string]uuid.UUID // resource:action -> permission ID
+	grants      map[uuid.UUID]map[uuid.UUID]bool
+}
+
+func newMemoryRolePermissionStore(roles ...string) *memoryRolePermissionStore {
+	m := &memoryRolePermissionStore{
+		roles:       make(map[string]*models.Role),
+		permissions: make(map[string]uuid.UUID),
+		grants:      make(map[uuid.UUID]map[uuid.UUID]bool),
+	}
+	for _, name := range roles {
+		m.roles[name] = &models.Role{ID: uuid.New(), Name: name}
+	}
+	return m
+}
+
+func (m *memoryRolePermissionStore) EnsurePermission(ctx context.Context, exec store.Querier, perm *models.Permission) (bool, error) {
+	key := perm.Resource + ":" + perm.Action
+	if id, ok := m.permissions[key]; ok {
+		perm.ID = id
+		return false, nil
+	}
+	if perm.ID == uuid.Nil {
+		perm.ID = uuid.New()
+	}
+	m.permissions[key] = perm.ID
+	return true, nil
+}
+
+func (m *memoryRolePermissionStore) GetRoleByName(ctx context.Context, exec store.Querier, name string) (*models.Role, error) {
+	role, ok := m.roles[name]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return role, nil
+}
+
+func (m *memoryRolePermissionStore) GrantPermissionToRole(ctx context.Context, exec store.Querier, roleID, permissionID uuid.UUID) (bool, error) {
+	if m.grants[roleID] == nil {
+		m.grants[roleID] = make(map[uuid.UUID]bool)
+	}
+	if m.grants[roleID][permissionID] {
+		return false, nil
+	}
+	m.grants[roleID][permissionID] = true
+	return true, nil
+}
+
+func (m *memoryRolePermissionStore) hasGrant(roleName, resource, action string) bool {
+	role := m.roles[roleName]
+	permID, ok := m.permissions[resource+":"+action]
+	return ok && role != nil && m.grants[role.ID][permID]
+}
+
+func TestSyncSuperAdminPermissions_GrantsNewEssentialPermission(t *testing.T) {
+	ctx := context.Background()
+	rolePermissionStore := newMemoryRolePermissionStore(SuperAdminRoleName, "viewer")
+
+	// Initial seeding grants every essential permission
+	first, err := NewRolePermissionSyncService(rolePermissionStore, EssentialPermissions).SyncSuperAdminPermissions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, first.CreatedPermissions, len(EssentialPermissions))
+	assert.Len(t, first.GrantedPermissions, len(EssentialPermissions))
+	assert.True(t, rolePermissionStore.hasGrant(SuperAdminRoleName, "admin", "users"))
+
+	// A release adds a new essential permission; syncing creates it and grants it to super_admin only
+	withNew := append(append([]models.Permission{}, EssentialPermissions...), essentialPermission(
+		"00000000-0000-0000-0001-000000000099", "keywords", "manage", "Manage Keywords", "Manage keyword sets"))
+	require.False(t, rolePermissionStore.hasGrant(SuperAdminRoleName, "keywords", "manage"))
+
+	second, err := NewRolePermissionSyncService(rolePermissionStore, withNew).SyncSuperAdminPermissions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"keywords:manage"}, second.CreatedPermissions)
+	assert.Equal(t, []string{"keywords:manage"}, second.GrantedPermissions)
+	assert.Equal(t, len(withNew), second.TotalPermissions)
+	assert.True(t, rolePermissionStore.hasGrant(SuperAdminRoleName, "keywords", "manage"))
+	assert.False(t, rolePermissionStore.hasGrant("viewer", "keywords", "manage"))
+
+	// Running the sync again changes nothing
+	third, err := NewRolePermissionSyncService(rolePermissionStore, withNew).SyncSuperAdminPermissions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, third.CreatedPermissions)
+	assert.Empty(t, third.GrantedPermissions)
+}
+
+func TestSyncSuperAdminPermissions_GrantsExistingPermissionMissingFromRole(t *testing.T) {
+	ctx := context.Background()
+	rolePermissionStore := newMemoryRolePermissionStore(SuperAdminRoleName)
+	existing := EssentialPermissions[0]
+	_, err := rolePermissionStore.EnsurePermission(ctx, nil, &existing)
+	require.NoError(t, err)
+
+	result, err := NewRolePermissionSyncService(rolePermissionStore, EssentialPermissions[:1]).SyncSuperAdminPermissions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, result.CreatedPermissions)
+	assert.Equal(t, []string{existing.Name}, result.GrantedPermissions)
+}
+
+func TestSyncSuperAdminPermissions_MissingRole(t *testing.T) {
+	_, err := NewRolePermissionSyncService(newMemoryRolePermissionStore(), EssentialPermissions).SyncSuperAdminPermissions(context.Background())
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
+
+// seededPermissionPattern matches a row of the default permissions inserted by schema.sql
+var seededPermissionPattern = regexp.MustCompile(`\('([0-9a-f-]{36})', '([a-z_]+:[a-z_]+)', '((?:[^']|'')*)', '((?:[^']|'')*)', '([a-z_]+)', '([a-z_]+)'\)`)
+
+func TestEssentialPermissions_MatchTheSchemaSeed(t *testing.T) {
+	schema, err := os.ReadFile(filepath.Join("..", "..", "database", "schema.sql"))
+	require.NoError(t, err)
+	seeded := make(map[string]models.Permission)
+	for _, row := range seededPermissionPattern.FindAllStringSubmatch(string(schema), -1) {
+		description := strings.ReplaceAll(row[4], "''", "'")
+		seeded[row[2]] = models.Permission{ID: uuid.MustParse(row[1]), Name: row[2],
+			DisplayName: strings.ReplaceAll(row[3], "''", "'"), Description: &description, Resource: row[5], Action: row[6]}
+	}
+
+	// A database seeded through the sync describes each permission as a fresh schema does
+	for _, permission := range EssentialPermissions {
+		want, ok := seeded[permission.Name]
+		if assert.True(t, ok, "%s is not seeded by schema.sql", permission.Name) {
+			assert.Equal(t, want, permission)
+		}
+	}
+}
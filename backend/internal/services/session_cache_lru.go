@@ -0,0 +1,64 @@
+package services
+
+import (
+	"container/list"
+	"sync"
+)
+
+// sessionLRU tracks the recency of cached session IDs so the in-memory session store can stay
+// within a fixed size. It only orders IDs; the session data itself stays in InMemorySessionStore.
+type sessionLRU struct {
+	capacity int // 0 or less means unbounded
+	order    *list.List
+	index    map[string]*list.Element
+	mutex    sync.Mutex
+}
+
+func newSessionLRU(capacity int) *sessionLRU {
+	return &sessionLRU{capacity: capacity, order: list.New(), index: make(map[string]*list.Element)}
+}
+
+// add marks sessionID as most recently used and returns the IDs pushed out by it, oldest first
+func (l *sessionLRU) add(sessionID string) []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if element, ok := l.index[sessionID]; ok {
+		l.order.MoveToFront(element)
+		return nil
+	}
+	l.index[sessionID] = l.order.PushFront(sessionID)
+
+	var evicted []string
+	for l.capacity > 0 && l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		id := l.order.Remove(oldest).(string)
+		delete(l.index, id)
+		evicted = append(evicted, id)
+	}
+	return evicted
+}
+
+// touch marks a cached sessionID as most recently used
+func (l *sessionLRU) touch(sessionID string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if element, ok := l.index[sessionID]; ok {
+		l.order.MoveToFront(element)
+	}
+}
+
+func (l *sessionLRU) remove(sessionID string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if element, ok := l.index[sessionID]; ok {
+		l.order.Remove(element)
+		delete(l.index, sessionID)
+	}
+}
+
+func (l *sessionLRU) len() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.order.Len()
+}
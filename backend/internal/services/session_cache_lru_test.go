@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCachedSession(userID uuid.UUID) *SessionData {
+	now := time.Now()
+	return &SessionData{ID: uuid.NewString(), UserID: userID, IPAddress: "203.0.113.7", CreatedAt: now, LastActivity: now, ExpiresAt: now.Add(time.Hour), IsActive: true}
+}
+
+func TestSessionCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	cfg := DefaultSessionConfig()
+	cfg.MaxCachedSessions = 2
+	svc, err := NewSessionService(sqlx.NewDb(mockDB, "postgres"), cfg, nil)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	first, second, third := newCachedSession(userID), newCachedSession(userID), newCachedSession(userID)
+	svc.cacheSession(first)
+	svc.cacheSession(second)
+
+	// Using the first session makes the second the least recently used
+	mock.ExpectExec("UPDATE auth.sessions SET last_activity_at").WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = svc.ValidateSession(first.ID, first.IPAddress)
+	require.NoError(t, err)
+
+	svc.cacheSession(third)
+	_, cached := svc.cachedSession(second.ID)
+	assert.False(t, cached, "the least recently used session is evicted")
+	_, cached = svc.cachedSession(first.ID)
+	assert.True(t, cached)
+	metrics := svc.GetMetrics()
+	assert.Equal(t, int64(1), metrics.CacheEvictions)
+	assert.Equal(t, int64(2), metrics.CachedSessions)
+
+	// The evicted session is still valid: it is reloaded from the database, never invalidated there
+	mock.ExpectQuery("FROM auth.sessions").WithArgs(second.ID).WillReturnRows(sqlmock.NewRows([]string{
+		"id", "user_id", "ip_address", "user_agent", "session_fingerprint", "browser_fingerprint",
+		"screen_resolution", "is_active", "expires_at", "last_activity_at", "created_at", "device_public_key",
+	}).AddRow(second.ID, userID, second.IPAddress, nil, nil, nil, nil, true, second.ExpiresAt, second.LastActivity, second.CreatedAt, nil))
+	mock.ExpectQuery("FROM auth.roles").WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("user"))
+	mock.ExpectQuery("FROM auth.permissions").WithArgs(userID).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("campaigns:read"))
+	mock.ExpectExec("UPDATE auth.sessions SET last_activity_at").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	session, err := svc.ValidateSession(second.ID, second.IPAddress)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"campaigns:read"}, session.Permissions)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// Reloading it pushes out the third session, which was used less recently than the first
+	_, cached = svc.cachedSession(third.ID)
+	assert.False(t, cached)
+	assert.Equal(t, int64(2), svc.GetMetrics().CacheEvictions)
+
+	sessionIDs, err := svc.sessionStore.ListByUser(context.Background(), userID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{first.ID, second.ID, third.ID}, sessionIDs, "evicted sessions still count toward the user's session limit")
+}
+
+func TestSessionCache_Unbounded(t *testing.T) {
+	cfg := DefaultSessionConfig()
+	cfg.MaxCachedSessions = 0
+	svc, err := NewSessionService(nil, cfg, nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 50; i++ {
+		svc.cacheSession(newCachedSession(uuid.New()))
+	}
+	metrics := svc.GetMetrics()
+	assert.Equal(t, int64(50), metrics.CachedSessions)
+	assert.Zero(t, metrics.CacheEvictions)
+}
+
+func TestSessionService_HydratesActiveSessionsOnStartup(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	cfg := DefaultSessionConfig()
+	cfg.MaxCachedSessions = 100
+	cfg.MaxSessionsPerUser = 2
+	userID, otherUserID := uuid.New(), uuid.New()
+	older, newer, other := newCachedSession(userID), newCachedSession(userID), newCachedSession(otherUserID)
+	older.CreatedAt = older.CreatedAt.Add(-time.Minute)
+
+	rows := sqlmock.NewRows([]string{
+		"id", "user_id", "ip_address", "user_agent", "session_fingerprint", "browser_fingerprint",
+		"screen_resolution", "is_active", "expires_at", "last_activity_at", "created_at", "device_public_key",
+	})
+	for _, session := range []*SessionData{older, newer, other} {
+		rows.AddRow(session.ID, session.UserID, session.IPAddress, nil, nil, nil, nil, true, session.ExpiresAt, session.LastActivity, session.CreatedAt, nil)
+	}
+	mock.ExpectQuery("FROM auth.sessions\\s+WHERE is_active = true AND expires_at > NOW\\(\\)").WithArgs(100).WillReturnRows(rows)
+	// Permissions are loaded once per user
+	for _, id := range []uuid.UUID{userID, otherUserID} {
+		mock.ExpectQuery("FROM auth.roles").WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("user"))
+		mock.ExpectQuery("FROM auth.permissions").WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("campaigns:read"))
+	}
+
+	svc, err := NewSessionService(sqlx.NewDb(mockDB, "postgres"), cfg, nil)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	for _, session := range []*SessionData{older, newer, other} {
+		restored, found := svc.cachedSession(session.ID)
+		require.True(t, found)
+		assert.Equal(t, []string{"campaigns:read"}, restored.Permissions)
+	}
+	sessionIDs, err := svc.sessionStore.ListByUser(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{older.ID, newer.ID}, sessionIDs)
+
+	// The restored sessions count towards the per-user limit
+	mock.ExpectExec("UPDATE auth.sessions SET is_active = false WHERE id").WithArgs(older.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, svc.enforceSessionLimits(userID))
+	require.NoError(t, mock.ExpectationsWereMet())
+	_, found := svc.cachedSession(older.ID)
+	assert.False(t, found)
+}
+
+func TestSessionService_StartsEmptyWhenHydrationFails(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	mock.ExpectQuery("FROM auth.sessions").WillReturnError(assert.AnError)
+	svc, err := NewSessionService(sqlx.NewDb(mockDB, "postgres"), DefaultSessionConfig(), nil)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	cached, _ := svc.cacheStats()
+	assert.Zero(t, cached)
+}
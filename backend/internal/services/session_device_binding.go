@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/logging"
+)
+
+// Device binding errors
+var (
+	ErrDeviceKeyInvalid        = fmt.Errorf("invalid device public key")
+	ErrDeviceKeyRequired       = fmt.Errorf("device public key required")
+	ErrDeviceSignatureRequired = fmt.Errorf("device signature required")
+	ErrDeviceSignatureInvalid  = fmt.Errorf("device signature invalid")
+)
+
+// DeviceProof is the signed request proof a client holding a session's device key sends with each request
+type DeviceProof struct {
+	Timestamp string // Unix seconds at which the request was signed
+	Nonce     string // Single-use random value chosen by the client
+	Signature string // Base64 signature over DeviceSignaturePayload
+	Method    string // HTTP method of the signed request
+	Path      string // Request URI (path and query) of the signed request
+}
+
+// DeviceSignaturePayload returns the bytes a client signs for a request: the timestamp, nonce,
+// upper-cased method and request URI joined by newlines.
+func DeviceSignaturePayload(timestamp, nonce, method, path string) []byte {
+	return []byte(strings.Join([]string{timestamp, nonce, strings.ToUpper(method), path}, "\n"))
+}
+
+// ParseDevicePublicKey decodes a base64 DER (SPKI) public key. Ed25519 and ECDSA P-256 keys are accepted.
+func ParseDevicePublicKey(encoded string) (interface{}, error) {
+	der, err := decodeBase64(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDeviceKeyInvalid, err)
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDeviceKeyInvalid, err)
+	}
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		return k, nil
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("%w: unsupported ECDSA curve %s", ErrDeviceKeyInvalid, k.Curve.Params().Name)
+		}
+		return k, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported key type %T", ErrDeviceKeyInvalid, key)
+	}
+}
+
+// DeviceBindingMode returns the configured device binding mode, defaulting to off
+func (s *SessionService) DeviceBindingMode() string {
+	switch s.config.DeviceBinding {
+	case config.DeviceBindingOptional, config.DeviceBindingRequired:
+		return s.config.DeviceBinding
+	}
+	return config.DeviceBindingOff
+}
+
+// PrepareDeviceKey validates the device public key supplied at login and returns the key to bind
+// the new session to. The key is ignored when binding is off and mandatory when it is required.
+func (s *SessionService) PrepareDeviceKey(encoded string) (string, error) {
+	mode := s.DeviceBindingMode()
+	if mode == config.DeviceBindingOff {
+		return "", nil
+	}
+	encoded = strings.TrimSpace(encoded)
+	if encoded == "" {
+		if mode == config.DeviceBindingRequired {
+			return "", ErrDeviceKeyRequired
+		}
+		return "", nil
+	}
+	if _, err := ParseDevicePublicKey(encoded); err != nil {
+		return "", err
+	}
+	return encoded, nil
+}
+
+// VerifyDeviceProof checks that a request on a device-bound session is signed by the session's
+// device key. Unbound sessions pass when binding is optional; with binding off no check is made.
+// Timestamps outside the configured skew and reused nonces are rejected.
+func (s *SessionService) VerifyDeviceProof(session *SessionData, proof DeviceProof) error {
+	mode := s.DeviceBindingMode()
+	if mode == config.DeviceBindingOff {
+		return nil
+	}
+	if session.DeviceKey == "" {
+		if mode == config.DeviceBindingRequired {
+			return ErrDeviceSignatureRequired
+		}
+		return nil
+	}
+	if proof.Timestamp == "" || proof.Nonce == "" || proof.Signature == "" {
+		return ErrDeviceSignatureRequired
+	}
+
+	seconds, err := strconv.ParseInt(proof.Timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: malformed timestamp", ErrDeviceSignatureInvalid)
+	}
+	maxSkew := s.config.DeviceSignatureMaxSkew
+	if maxSkew <= 0 {
+		maxSkew = config.DefaultDeviceSignatureMaxSkew
+	}
+	now := time.Now()
+	signedAt := time.Unix(seconds, 0)
+	if signedAt.Before(now.Add(-maxSkew)) || signedAt.After(now.Add(maxSkew)) {
+		return fmt.Errorf("%w: timestamp outside allowed skew", ErrDeviceSignatureInvalid)
+	}
+
+	key, err := ParseDevicePublicKey(session.DeviceKey)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDeviceSignatureInvalid, err)
+	}
+	signature, err := decodeBase64(proof.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrDeviceSignatureInvalid)
+	}
+	payload := DeviceSignaturePayload(proof.Timestamp, proof.Nonce, proof.Method, proof.Path)
+	if !verifyDeviceSignature(key, payload, signature) {
+		return ErrDeviceSignatureInvalid
+	}
+
+	// Only a correctly signed nonce is remembered so forged requests cannot burn a client's nonces
+	if !s.useDeviceNonce(session.ID, proof.Nonce, 2*maxSkew, now) {
+		return fmt.Errorf("%w: nonce already used", ErrDeviceSignatureInvalid)
+	}
+	return nil
+}
+
+// useDeviceNonce records a verified nonce until it could no longer pass the skew check and reports
+// whether it had not been used before. A session store shared between instances records it for all
+// of them; while that store fails, nonces are only recorded by this instance.
+func (s *SessionService) useDeviceNonce(sessionID, nonce string, ttl time.Duration, now time.Time) bool {
+	if nonces, ok := s.sessionStore.(deviceNonceStore); ok {
+		fresh, err := nonces.useDeviceNonce(context.Background(), sessionID, nonce, ttl)
+		if err == nil {
+			return fresh
+		}
+		log.Printf("SessionService: failed to record a device nonce of session %s in the session store: %v",
+			logging.RedactSessionID(sessionID), err)
+	}
+	return s.deviceNonces.use(sessionID+"\n"+nonce, now.Add(ttl), now)
+}
+
+// verifyDeviceSignature accepts Ed25519 signatures and ECDSA P-256 signatures over SHA-256 in either
+// raw r||s (WebCrypto) or ASN.1 DER form.
+func verifyDeviceSignature(key interface{}, payload, signature []byte) bool {
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, payload, signature)
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(payload)
+		if len(signature) == 64 {
+			r := new(big.Int).SetBytes(signature[:32])
+			sig := new(big.Int).SetBytes(signature[32:])
+			return ecdsa.Verify(k, digest[:], r, sig)
+		}
+		return ecdsa.VerifyASN1(k, digest[:], signature)
+	}
+	return false
+}
+
+func decodeBase64(value string) ([]byte, error) {
+	if decoded, err := base64.StdEncoding.DecodeString(value); err == nil {
+		return decoded, nil
+	}
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+// deviceNonceCache remembers nonces of verified device signatures on this instance until they could
+// no longer pass the skew check
+type deviceNonceCache struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+}
+
+func newDeviceNonceCache() *deviceNonceCache {
+	return &deviceNonceCache{nonces: make(map[string]time.Time)}
+}
+
+// use records key and reports whether it had not been seen before
+func (c *deviceNonceCache) use(key string, expiresAt, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if expiry, seen := c.nonces[key]; seen && now.Before(expiry) {
+		return false
+	}
+	c.nonces[key] = expiresAt
+	return true
+}
+
+func (c *deviceNonceCache) prune(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, expiry := range c.nonces {
+		if !now.Before(expiry) {
+			delete(c.nonces, key)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDeviceBindingService(mode string) *SessionService {
+	cfg := DefaultSessionConfig()
+	cfg.DeviceBinding = mode
+	cfg.DeviceSignatureMaxSkew = time.Minute
+	return &SessionService{config: cfg, deviceNonces: newDeviceNonceCache()}
+}
+
+func encodePublicKey(t *testing.T, key interface{}) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(der)
+}
+
+// signedProof signs a request the way a client does, using the current time and the given nonce
+func signedProof(sign func([]byte) []byte, nonce, method, path string) DeviceProof {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	return DeviceProof{
+		Timestamp: timestamp,
+		Nonce:     nonce,
+		Signature: base64.StdEncoding.EncodeToString(sign(DeviceSignaturePayload(timestamp, nonce, method, path))),
+		Method:    method,
+		Path:      path,
+	}
+}
+
+func newEd25519Device(t *testing.T) (string, func([]byte) []byte) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return encodePublicKey(t, public), func(msg []byte) []byte { return ed25519.Sign(private, msg) }
+}
+
+func TestVerifyDeviceProof_AcceptsValidSignatures(t *testing.T) {
+	svc := newDeviceBindingService(config.DeviceBindingOptional)
+
+	edKey, edSign := newEd25519Device(t)
+	edSession := &SessionData{ID: "ed-session", UserID: uuid.New(), DeviceKey: edKey}
+	assert.NoError(t, svc.VerifyDeviceProof(edSession, signedProof(edSign, "n-1", "GET", "/api/v2/campaigns?limit=10")))
+
+	ecPrivate, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecSession := &SessionData{ID: "ec-session", UserID: uuid.New(), DeviceKey: encodePublicKey(t, &ecPrivate.PublicKey)}
+
+	// ASN.1 DER signatures
+	asn1Sign := func(msg []byte) []byte {
+		digest := sha256.Sum256(msg)
+		sig, err := ecdsa.SignASN1(rand.Reader, ecPrivate, digest[:])
+		require.NoError(t, err)
+		return sig
+	}
+	assert.NoError(t, svc.VerifyDeviceProof(ecSession, signedProof(asn1Sign, "n-2", "POST", "/api/v2/campaigns")))
+
+	// Raw r||s signatures as produced by WebCrypto
+	rawSign := func(msg []byte) []byte {
+		digest := sha256.Sum256(msg)
+		r, s, err := ecdsa.Sign(rand.Reader, ecPrivate, digest[:])
+		require.NoError(t, err)
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+		return sig
+	}
+	assert.NoError(t, svc.VerifyDeviceProof(ecSession, signedProof(rawSign, "n-3", "DELETE", "/api/v2/campaigns/1")))
+}
+
+func TestVerifyDeviceProof_MissingSignatureRejected(t *testing.T) {
+	svc := newDeviceBindingService(config.DeviceBindingOptional)
+	key, _ := newEd25519Device(t)
+	session := &SessionData{ID: "bound", UserID: uuid.New(), DeviceKey: key}
+
+	err := svc.VerifyDeviceProof(session, DeviceProof{Method: "GET", Path: "/api/v2/me"})
+	assert.ErrorIs(t, err, ErrDeviceSignatureRequired)
+
+	// Unbound sessions are only accepted without a signature when binding is optional
+	unbound := &SessionData{ID: "unbound", UserID: uuid.New()}
+	assert.NoError(t, svc.VerifyDeviceProof(unbound, DeviceProof{}))
+	assert.ErrorIs(t, newDeviceBindingService(config.DeviceBindingRequired).VerifyDeviceProof(unbound, DeviceProof{}), ErrDeviceSignatureRequired)
+}
+
+func TestVerifyDeviceProof_StolenCookieWithoutKeyRejected(t *testing.T) {
+	svc := newDeviceBindingService(config.DeviceBindingRequired)
+	victimKey, victimSign := newEd25519Device(t)
+	session := &SessionData{ID: "victim-session", UserID: uuid.New(), DeviceKey: victimKey}
+
+	// The attacker replays the session cookie but can only sign with their own key
+	_, attackerSign := newEd25519Device(t)
+	err := svc.VerifyDeviceProof(session, signedProof(attackerSign, "attacker-nonce", "GET", "/api/v2/me"))
+	assert.ErrorIs(t, err, ErrDeviceSignatureInvalid)
+
+	// A captured signature does not transfer to another request
+	captured := signedProof(victimSign, "victim-nonce", "GET", "/api/v2/me")
+	forged := captured
+	forged.Method, forged.Path = "DELETE", "/api/v2/users/1"
+	assert.ErrorIs(t, svc.VerifyDeviceProof(session, forged), ErrDeviceSignatureInvalid)
+
+	// Nor can it be replayed once the client has used it
+	require.NoError(t, svc.VerifyDeviceProof(session, captured))
+	assert.ErrorIs(t, svc.VerifyDeviceProof(session, captured), ErrDeviceSignatureInvalid)
+}
+
+func TestVerifyDeviceProof_StaleTimestampRejected(t *testing.T) {
+	svc := newDeviceBindingService(config.DeviceBindingOptional)
+	key, sign := newEd25519Device(t)
+	session := &SessionData{ID: "bound", UserID: uuid.New(), DeviceKey: key}
+
+	timestamp := strconv.FormatInt(time.Now().Add(-2*time.Minute).Unix(), 10)
+	proof := DeviceProof{
+		Timestamp: timestamp,
+		Nonce:     "late",
+		Signature: base64.StdEncoding.EncodeToString(sign(DeviceSignaturePayload(timestamp, "late", "GET", "/"))),
+		Method:    "GET",
+		Path:      "/",
+	}
+	assert.ErrorIs(t, svc.VerifyDeviceProof(session, proof), ErrDeviceSignatureInvalid)
+}
+
+func TestVerifyDeviceProof_NoncesAreSharedThroughTheRedisStore(t *testing.T) {
+	sessionStore, server := newTestRedisSessionStore(t)
+	cfg := DefaultSessionConfig()
+	cfg.DeviceBinding = config.DeviceBindingRequired
+	cfg.DeviceSignatureMaxSkew = time.Minute
+	newNode := func() *SessionService {
+		mockDB, _, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { mockDB.Close() })
+		svc, err := NewSessionService(sqlx.NewDb(mockDB, "postgres"), cfg, nil, WithSessionStore(sessionStore))
+		require.NoError(t, err)
+		return svc
+	}
+	first, second := newNode(), newNode()
+	key, sign := newEd25519Device(t)
+	session := &SessionData{ID: "shared-session", UserID: uuid.New(), DeviceKey: key}
+
+	// A request captured after the first node accepted it cannot be replayed against the second
+	captured := signedProof(sign, "shared-nonce", "GET", "/api/v2/me")
+	require.NoError(t, first.VerifyDeviceProof(session, captured))
+	assert.ErrorIs(t, second.VerifyDeviceProof(session, captured), ErrDeviceSignatureInvalid)
+	assert.NoError(t, second.VerifyDeviceProof(session, signedProof(sign, "next-nonce", "GET", "/api/v2/me")))
+
+	nonceKeys := server.Keys()
+	assert.Len(t, nonceKeys, 2)
+	for _, nonceKey := range nonceKeys {
+		assert.True(t, strings.HasPrefix(nonceKey, "test:device_nonce:shared-session:"))
+		assert.Equal(t, 2*time.Minute, server.TTL(nonceKey), "a nonce is kept until it could no longer pass the skew check")
+	}
+
+	// While Redis is down each node still rejects the nonces it has seen itself
+	server.Close()
+	offline := signedProof(sign, "offline-nonce", "GET", "/api/v2/me")
+	require.NoError(t, first.VerifyDeviceProof(session, offline))
+	assert.ErrorIs(t, first.VerifyDeviceProof(session, offline), ErrDeviceSignatureInvalid)
+}
+
+func TestVerifyDeviceProof_DisabledSkipsCheck(t *testing.T) {
+	svc := newDeviceBindingService(config.DeviceBindingOff)
+	key, _ := newEd25519Device(t)
+	assert.NoError(t, svc.VerifyDeviceProof(&SessionData{ID: "bound", DeviceKey: key}, DeviceProof{}))
+}
+
+func TestPrepareDeviceKey(t *testing.T) {
+	key, _ := newEd25519Device(t)
+
+	bound, err := newDeviceBindingService(config.DeviceBindingOff).PrepareDeviceKey(key)
+	require.NoError(t, err)
+	assert.Empty(t, bound, "keys are ignored when binding is off")
+
+	bound, err = newDeviceBindingService(config.DeviceBindingOptional).PrepareDeviceKey(key)
+	require.NoError(t, err)
+	assert.Equal(t, key, bound)
+
+	_, err = newDeviceBindingService(config.DeviceBindingOptional).PrepareDeviceKey("not-a-key")
+	assert.ErrorIs(t, err, ErrDeviceKeyInvalid)
+
+	_, err = newDeviceBindingService(config.DeviceBindingRequired).PrepareDeviceKey("")
+	assert.ErrorIs(t, err, ErrDeviceKeyRequired)
+
+	// Only Ed25519 and P-256 keys are accepted
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	_, err = newDeviceBindingService(config.DeviceBindingOptional).PrepareDeviceKey(encodePublicKey(t, &p384.PublicKey))
+	assert.ErrorIs(t, err, ErrDeviceKeyInvalid)
+}
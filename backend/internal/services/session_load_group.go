@@ -0,0 +1,105 @@
+package services
+
+import "sync"
+
+// sessionLoad is a database load of a session in progress; concurrent validations of the same
+// session wait for it
+type sessionLoad struct {
+	done    chan struct{}
+	session *SessionData
+	err     error
+	stale   bool // Set when the session is invalidated during the load; the result is not kept
+}
+
+// sessionLoadGroup lets concurrent validations of a session that is missing from the session store
+// share one database load instead of each running their own. A nil group loads every time.
+type sessionLoadGroup struct {
+	mutex    sync.Mutex
+	inflight map[string]*sessionLoad
+}
+
+func newSessionLoadGroup() *sessionLoadGroup {
+	return &sessionLoadGroup{inflight: make(map[string]*sessionLoad)}
+}
+
+// do returns the session loaded by load, running it only when no load of the session is already
+// in progress, and reports whether the result came from another caller's load. keep stores a
+// loaded session and drop removes it again when the session was invalidated while it was loading;
+// callers then get an inactive copy. Each caller gets its own copy of the session.
+func (g *sessionLoadGroup) do(sessionID string, load func() (*SessionData, error), keep, drop func(*SessionData)) (*SessionData, bool, error) {
+	if g == nil {
+		session, err := load()
+		if err == nil {
+			keep(session)
+		}
+		return session, false, err
+	}
+
+	g.mutex.Lock()
+	if pending, ok := g.inflight[sessionID]; ok {
+		g.mutex.Unlock()
+		<-pending.done
+		return pending.result(), true, pending.err
+	}
+	pending := &sessionLoad{done: make(chan struct{})}
+	g.inflight[sessionID] = pending
+	g.mutex.Unlock()
+
+	pending.session, pending.err = load()
+	if pending.err == nil {
+		keep(pending.session)
+	}
+
+	// An invalidation that marked the load stale has already removed the session from the store,
+	// so the copy kept above is removed again. One that comes later removes it itself.
+	g.mutex.Lock()
+	if g.inflight[sessionID] == pending {
+		delete(g.inflight, sessionID)
+	}
+	stale := pending.stale
+	g.mutex.Unlock()
+	if stale && pending.err == nil {
+		drop(pending.session)
+	}
+	close(pending.done)
+	return pending.result(), false, pending.err
+}
+
+// result returns a copy of the loaded session, inactive when it was invalidated during the load
+func (l *sessionLoad) result() *SessionData {
+	if l.session == nil {
+		return nil
+	}
+	session := *l.session
+	if l.stale {
+		session.IsActive = false
+	}
+	return &session
+}
+
+// invalidate marks a load of the session in progress stale; later validations start a new one
+func (g *sessionLoadGroup) invalidate(sessionID string) {
+	if g == nil {
+		return
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if pending, ok := g.inflight[sessionID]; ok {
+		pending.stale = true
+		delete(g.inflight, sessionID)
+	}
+}
+
+// invalidateAll marks every load in progress stale, for invalidations that do not know which
+// sessions they affect until they are loaded
+func (g *sessionLoadGroup) invalidateAll() {
+	if g == nil {
+		return
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	for sessionID, pending := range g.inflight {
+		pending.stale = true
+		delete(g.inflight, sessionID)
+	}
+}
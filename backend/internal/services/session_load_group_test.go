@@ -0,0 +1,133 @@
+package services
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSession_ConcurrentMissesShareOneDatabaseLoad(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	svc, err := NewSessionService(sqlx.NewDb(mockDB, "postgres"), DefaultSessionConfig(), nil)
+	require.NoError(t, err)
+
+	const validations = 50
+	session := newCachedSession(uuid.New())
+	mock.ExpectQuery("FROM auth.sessions").WithArgs(session.ID).WillDelayFor(200 * time.Millisecond).WillReturnRows(sqlmock.NewRows([]string{
+		"id", "user_id", "ip_address", "user_agent", "session_fingerprint", "browser_fingerprint",
+		"screen_resolution", "is_active", "expires_at", "last_activity_at", "created_at", "device_public_key",
+	}).AddRow(session.ID, session.UserID, session.IPAddress, nil, nil, nil, nil, true, session.ExpiresAt, session.LastActivity, session.CreatedAt, nil))
+	mock.ExpectQuery("FROM auth.roles").WithArgs(session.UserID).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("user"))
+	mock.ExpectQuery("FROM auth.permissions").WithArgs(session.UserID).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("campaigns:read"))
+	for i := 0; i < validations; i++ {
+		mock.ExpectExec("UPDATE auth.sessions SET last_activity_at").WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	start := make(chan struct{})
+	errs := make([]error, validations)
+	var wg sync.WaitGroup
+	for i := 0; i < validations; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			validated, err := svc.ValidateSession(session.ID, session.IPAddress)
+			if errs[i] = err; err == nil {
+				assert.Equal(t, []string{"campaigns:read"}, validated.Permissions)
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	require.NoError(t, mock.ExpectationsWereMet(), "the session is loaded from the database once")
+	assert.Positive(t, svc.GetMetrics().SharedLoads)
+	_, cached := svc.cachedSession(session.ID)
+	assert.True(t, cached)
+}
+
+func TestSessionLoadGroup_InvalidationDuringLoad(t *testing.T) {
+	group := newSessionLoadGroup()
+	session := newCachedSession(uuid.New())
+	loading, release := make(chan struct{}), make(chan struct{})
+	var kept, dropped []*SessionData
+	keep := func(s *SessionData) { kept = append(kept, s) }
+	drop := func(s *SessionData) { dropped = append(dropped, s) }
+
+	type outcome struct {
+		session *SessionData
+		shared  bool
+		err     error
+	}
+	leader, waiter := make(chan outcome), make(chan outcome)
+	go func() {
+		s, shared, err := group.do(session.ID, func() (*SessionData, error) {
+			close(loading)
+			<-release
+			return session, nil
+		}, keep, drop)
+		leader <- outcome{s, shared, err}
+	}()
+	<-loading
+	go func() {
+		s, shared, err := group.do(session.ID, func() (*SessionData, error) {
+			t.Error("a second load must not run while the first is in progress")
+			return session, nil
+		}, keep, drop)
+		waiter <- outcome{s, shared, err}
+	}()
+	// Let the waiter join the load before the session is invalidated
+	time.Sleep(50 * time.Millisecond)
+	group.invalidate(session.ID)
+	close(release)
+
+	first, second := <-leader, <-waiter
+	require.NoError(t, first.err)
+	require.NoError(t, second.err)
+	assert.False(t, first.shared)
+	assert.True(t, second.shared)
+	assert.False(t, first.session.IsActive, "a session invalidated while loading is not valid")
+	assert.False(t, second.session.IsActive)
+	assert.NotSame(t, first.session, second.session, "every caller gets its own copy")
+	assert.Equal(t, []*SessionData{session}, dropped, "the stale session is removed from the store again")
+	assert.True(t, session.IsActive, "the loaded session itself is left as read")
+
+	// Later validations load it again
+	loads := 0
+	_, shared, err := group.do(session.ID, func() (*SessionData, error) { loads++; return session, nil }, keep, drop)
+	require.NoError(t, err)
+	assert.False(t, shared)
+	assert.Equal(t, 1, loads)
+	assert.Len(t, dropped, 1)
+	assert.Len(t, kept, 2)
+}
+
+func TestSessionLoadGroup_Disabled(t *testing.T) {
+	cfg := DefaultSessionConfig()
+	cfg.DeduplicateLoads = false
+	svc, err := NewSessionService(nil, cfg, nil)
+	require.NoError(t, err)
+	assert.Nil(t, svc.loads)
+
+	var group *sessionLoadGroup
+	loads := 0
+	for i := 0; i < 2; i++ {
+		_, shared, err := group.do("session", func() (*SessionData, error) { loads++; return newCachedSession(uuid.New()), nil },
+			func(*SessionData) {}, func(*SessionData) {})
+		require.NoError(t, err)
+		assert.False(t, shared)
+	}
+	assert.Equal(t, 2, loads)
+}
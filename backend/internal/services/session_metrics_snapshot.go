@@ -0,0 +1,156 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// sessionMetricCounters are the session metrics that only ever grow, and so are kept across restarts
+type sessionMetricCounters struct {
+	TotalSessions  int64 `db:"total_sessions"`
+	CleanupCount   int64 `db:"cleanup_count"`
+	SecurityEvents int64 `db:"security_events"`
+	CacheEvictions int64 `db:"cache_evictions"`
+}
+
+func (c sessionMetricCounters) minus(o sessionMetricCounters) sessionMetricCounters {
+	return sessionMetricCounters{
+		TotalSessions:  c.TotalSessions - o.TotalSessions,
+		CleanupCount:   c.CleanupCount - o.CleanupCount,
+		SecurityEvents: c.SecurityEvents - o.SecurityEvents,
+		CacheEvictions: c.CacheEvictions - o.CacheEvictions,
+	}
+}
+
+// sessionMetricsSnapshot tracks what has been saved of this process's session metrics. Each
+// snapshot adds only the growth since the previous one, so instances sharing a database add up
+// rather than overwrite each other.
+type sessionMetricsSnapshot struct {
+	mu       sync.Mutex
+	saved    sessionMetricCounters // Counters as of the last successful snapshot or restore
+	restored bool
+	ticker   *time.Ticker
+	done     chan struct{}
+}
+
+// RestoreMetrics adds the saved cumulative session metrics to the in-memory counters. It only
+// restores once per service.
+func (s *SessionService) RestoreMetrics() error {
+	s.metricsSnapshot.mu.Lock()
+	defer s.metricsSnapshot.mu.Unlock()
+	if s.metricsSnapshot.restored {
+		return nil
+	}
+
+	var saved sessionMetricCounters
+	err := s.db.Get(&saved, `SELECT total_sessions, cleanup_count, security_events, cache_evictions
+	                           FROM auth.session_metrics WHERE id = 1`)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	metrics := s.metrics
+	metrics.mutex.Lock()
+	metrics.TotalSessions += saved.TotalSessions
+	metrics.CleanupCount += saved.CleanupCount
+	metrics.SecurityEvents += saved.SecurityEvents
+	metrics.CacheEvictions += saved.CacheEvictions
+	metrics.mutex.Unlock()
+
+	// Growth before the restore has not been saved yet, so only the restored values count as saved
+	s.metricsSnapshot.saved = sessionMetricCounters{
+		TotalSessions:  s.metricsSnapshot.saved.TotalSessions + saved.TotalSessions,
+		CleanupCount:   s.metricsSnapshot.saved.CleanupCount + saved.CleanupCount,
+		SecurityEvents: s.metricsSnapshot.saved.SecurityEvents + saved.SecurityEvents,
+		CacheEvictions: s.metricsSnapshot.saved.CacheEvictions + saved.CacheEvictions,
+	}
+	s.metricsSnapshot.restored = true
+	return nil
+}
+
+// SnapshotMetrics saves the growth of the cumulative session metrics since the last snapshot
+func (s *SessionService) SnapshotMetrics() error {
+	s.metricsSnapshot.mu.Lock()
+	defer s.metricsSnapshot.mu.Unlock()
+
+	current := s.cumulativeMetrics()
+	delta := current.minus(s.metricsSnapshot.saved)
+	if delta == (sessionMetricCounters{}) {
+		return nil
+	}
+	_, err := s.db.Exec(`INSERT INTO auth.session_metrics (id, total_sessions, cleanup_count, security_events, cache_evictions, updated_at)
+	                     VALUES (1, $1, $2, $3, $4, NOW())
+	                     ON CONFLICT (id) DO UPDATE SET
+	                         total_sessions = auth.session_metrics.total_sessions + EXCLUDED.total_sessions,
+	                         cleanup_count = auth.session_metrics.cleanup_count + EXCLUDED.cleanup_count,
+	                         security_events = auth.session_metrics.security_events + EXCLUDED.security_events,
+	                         cache_evictions = auth.session_metrics.cache_evictions + EXCLUDED.cache_evictions,
+	                         updated_at = EXCLUDED.updated_at`,
+		delta.TotalSessions, delta.CleanupCount, delta.SecurityEvents, delta.CacheEvictions)
+	if err != nil {
+		return err
+	}
+	s.metricsSnapshot.saved = current
+	return nil
+}
+
+func (s *SessionService) cumulativeMetrics() sessionMetricCounters {
+	metrics := s.metrics
+	_, evictions := s.cacheStats()
+	metrics.mutex.RLock()
+	defer metrics.mutex.RUnlock()
+	return sessionMetricCounters{
+		TotalSessions:  metrics.TotalSessions,
+		CleanupCount:   metrics.CleanupCount,
+		SecurityEvents: metrics.SecurityEvents,
+		CacheEvictions: metrics.CacheEvictions + evictions,
+	}
+}
+
+// startMetricsSnapshots restores the saved metrics and saves them on the configured interval
+func (s *SessionService) startMetricsSnapshots() {
+	if s.db == nil || s.config.MetricsSnapshotInterval <= 0 {
+		return
+	}
+	if err := s.RestoreMetrics(); err != nil {
+		log.Printf("SessionService: failed to restore session metrics, counting from zero: %v", err)
+	}
+
+	ticker := time.NewTicker(s.config.MetricsSnapshotInterval)
+	done := make(chan struct{})
+	s.metricsSnapshot.mu.Lock()
+	s.metricsSnapshot.ticker, s.metricsSnapshot.done = ticker, done
+	s.metricsSnapshot.mu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.SnapshotMetrics(); err != nil {
+					log.Printf("SessionService: failed to save session metrics: %v", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+// stopMetricsSnapshots stops the periodic snapshots and saves what has accumulated since the last one
+func (s *SessionService) stopMetricsSnapshots() {
+	s.metricsSnapshot.mu.Lock()
+	ticker, done := s.metricsSnapshot.ticker, s.metricsSnapshot.done
+	s.metricsSnapshot.ticker, s.metricsSnapshot.done = nil, nil
+	s.metricsSnapshot.mu.Unlock()
+	if ticker == nil {
+		return
+	}
+	ticker.Stop()
+	close(done)
+	if err := s.SnapshotMetrics(); err != nil {
+		log.Printf("SessionService: failed to save session metrics at shutdown: %v", err)
+	}
+}
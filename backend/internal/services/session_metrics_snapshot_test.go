@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSnapshotTestSessionService(t *testing.T) (*SessionService, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+
+	cfg := DefaultSessionConfig()
+	cfg.CleanupInterval = time.Hour
+	cfg.MetricsSnapshotInterval = time.Hour
+	svc, err := NewSessionService(sqlx.NewDb(mockDB, "postgres"), cfg, nil)
+	require.NoError(t, err)
+	return svc, mock
+}
+
+func addSessionMetrics(svc *SessionService, sessions, cleanups, securityEvents int64) {
+	metrics := svc.metrics
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+	metrics.TotalSessions += sessions
+	metrics.CleanupCount += cleanups
+	metrics.SecurityEvents += securityEvents
+}
+
+var sessionMetricsColumns = []string{"total_sessions", "cleanup_count", "security_events", "cache_evictions"}
+
+func TestSessionMetrics_SurviveRestart(t *testing.T) {
+	// First run: nothing saved yet, counters grow, shutdown saves them
+	first, mock := newSnapshotTestSessionService(t)
+	mock.ExpectQuery("FROM auth.session_metrics").WillReturnRows(sqlmock.NewRows(sessionMetricsColumns))
+	first.Start()
+	addSessionMetrics(first, 12, 3, 2)
+	mock.ExpectExec("INSERT INTO auth.session_metrics").WithArgs(int64(12), int64(3), int64(2), int64(0)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	first.Stop()
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// Second run: the saved totals are restored and keep accumulating
+	second, mock := newSnapshotTestSessionService(t)
+	mock.ExpectQuery("FROM auth.session_metrics").
+		WillReturnRows(sqlmock.NewRows(sessionMetricsColumns).AddRow(12, 3, 2, 0))
+	second.Start()
+	metrics := second.GetMetrics()
+	assert.Equal(t, int64(12), metrics.TotalSessions)
+	assert.Equal(t, int64(3), metrics.CleanupCount)
+	assert.Equal(t, int64(2), metrics.SecurityEvents)
+
+	addSessionMetrics(second, 5, 1, 0)
+	assert.Equal(t, int64(17), second.GetMetrics().TotalSessions, "counters continue from the restored totals")
+
+	// Only the growth since the restore is added to the saved totals
+	mock.ExpectExec("INSERT INTO auth.session_metrics").WithArgs(int64(5), int64(1), int64(0), int64(0)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, second.SnapshotMetrics())
+
+	// Nothing new to save at shutdown
+	second.Stop()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionMetrics_SnapshotsDisabled(t *testing.T) {
+	svc, mock := newSnapshotTestSessionService(t)
+	svc.config.MetricsSnapshotInterval = 0
+	svc.Start()
+	addSessionMetrics(svc, 4, 0, 0)
+	svc.Stop()
+	assert.Equal(t, int64(4), svc.GetMetrics().TotalSessions)
+	require.NoError(t, mock.ExpectationsWereMet(), "no metrics queries when snapshots are disabled")
+}
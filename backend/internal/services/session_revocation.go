@@ -0,0 +1,85 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// SessionIDPrefixLength is how much of a session ID its user is shown and names it by. The rest of
+// the ID stays secret, as the full ID is the session's bearer credential.
+const SessionIDPrefixLength = 12
+
+// ErrSessionIDAmbiguous is returned when a session ID prefix matches more than one session
+var ErrSessionIDAmbiguous = fmt.Errorf("session ID prefix matches more than one session")
+
+// SessionIDPrefix returns the part of a session ID shown to its user
+func SessionIDPrefix(sessionID string) string {
+	if len(sessionID) <= SessionIDPrefixLength {
+		return sessionID
+	}
+	return sessionID[:SessionIDPrefixLength]
+}
+
+// ListUserSessions returns the active, unexpired sessions of a user from auth.sessions, most
+// recently active first. Permissions and roles are not loaded.
+func (s *SessionService) ListUserSessions(userID uuid.UUID) ([]*SessionData, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM auth.sessions
+		WHERE user_id = $1 AND is_active = true AND expires_at > NOW()
+		ORDER BY last_activity_at DESC`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions of user %s: %w", userID, err)
+	}
+	defer rows.Close()
+
+	var sessions []*SessionData
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read session of user %s: %w", userID, err)
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list sessions of user %s: %w", userID, err)
+	}
+	return sessions, nil
+}
+
+// RevokeUserSession invalidates the active session of userID whose ID starts with idPrefix, which
+// must be at least SessionIDPrefixLength characters. Only the user's own sessions are searched, so
+// the prefix of another user's session is not found. The revocation is audited.
+func (s *SessionService) RevokeUserSession(userID uuid.UUID, idPrefix string) (*SessionData, error) {
+	if len(idPrefix) < SessionIDPrefixLength {
+		return nil, ErrSessionNotFound
+	}
+	sessions, err := s.ListUserSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+	var match *SessionData
+	for _, session := range sessions {
+		if !strings.HasPrefix(session.ID, idPrefix) {
+			continue
+		}
+		if match != nil {
+			return nil, ErrSessionIDAmbiguous
+		}
+		match = session
+	}
+	if match == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	if err := s.InvalidateSession(match.ID); err != nil {
+		return nil, fmt.Errorf("failed to revoke session of user %s: %w", userID, err)
+	}
+	prefix := SessionIDPrefix(match.ID)
+	s.logAuditEvent(nil, prefix, userID, "session_revoked",
+		fmt.Sprintf("Session %s of user %s revoked from %s", prefix, userID, match.IPAddress))
+	return match, nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"net"
+	"time"
+)
+
+// Risk scores run from MinRiskScore to MaxRiskScore. Scores up to LowRiskMax are low risk, up to
+// MediumRiskMax medium, and above it high.
+const (
+	MinRiskScore  = 0
+	MaxRiskScore  = 10
+	LowRiskMax    = 3
+	MediumRiskMax = 6
+)
+
+// RiskBand names the band of a risk score: "low", "medium" or "high"
+func RiskBand(score int) string {
+	switch {
+	case score <= LowRiskMax:
+		return "low"
+	case score <= MediumRiskMax:
+		return "medium"
+	}
+	return "high"
+}
+
+// computeRiskScore rates how unlike its creation a request on a session looks, from 0 to 10. A new
+// network scores 4 and another address on the same network 1; another browser family scores 4 and
+// another User-Agent of the same browser 1; an idle gap of at least half the idle timeout scores 2
+// and of a quarter 1. Empty request details are not scored, since not every caller knows them.
+func (s *SessionService) computeRiskScore(session *SessionData, clientIP, userAgent string) int {
+	score := 0
+
+	if clientIP != "" && session.IPAddress != "" && clientIP != session.IPAddress {
+		if sameNetwork(session.IPAddress, clientIP) {
+			score++
+		} else {
+			score += 4
+		}
+	}
+
+	if userAgent != "" && session.UserAgent != "" && userAgent != session.UserAgent {
+		if ExtractBrowserInfo(userAgent) == ExtractBrowserInfo(session.UserAgent) {
+			score++
+		} else {
+			score += 4
+		}
+	}
+
+	if idleTimeout := s.config.IdleTimeout; idleTimeout > 0 && !session.LastActivity.IsZero() {
+		switch idle := time.Since(session.LastActivity); {
+		case idle >= idleTimeout/2:
+			score += 2
+		case idle >= idleTimeout/4:
+			score++
+		}
+	}
+
+	if score > MaxRiskScore {
+		score = MaxRiskScore
+	}
+	return score
+}
+
+// sameNetwork reports whether two addresses share an IPv4 /24 or an IPv6 /64
+func sameNetwork(a, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return false
+	}
+	if v4A, v4B := ipA.To4(), ipB.To4(); v4A != nil || v4B != nil {
+		if v4A == nil || v4B == nil {
+			return false
+		}
+		mask := net.CIDRMask(24, 32)
+		return v4A.Mask(mask).Equal(v4B.Mask(mask))
+	}
+	mask := net.CIDRMask(64, 128)
+	return ipA.Mask(mask).Equal(ipB.Mask(mask))
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	riskFirefoxUA       = "Mozilla/5.0 (X11; Linux x86_64; rv:126.0) Gecko/20100101 Firefox/126.0"
+	riskFirefoxUpdateUA = "Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0"
+	riskChromeUA        = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/125.0.0.0 Safari/537.36"
+)
+
+func newRiskSession(idle time.Duration) *SessionData {
+	now := time.Now()
+	return &SessionData{
+		ID: uuid.NewString(), UserID: uuid.New(), IPAddress: "203.0.113.7", UserAgent: riskFirefoxUA,
+		CreatedAt: now.Add(-time.Hour), LastActivity: now.Add(-idle), ExpiresAt: now.Add(time.Hour), IsActive: true,
+	}
+}
+
+func TestComputeRiskScore_Bands(t *testing.T) {
+	svc := &SessionService{config: DefaultSessionConfig()} // 30 minute idle timeout
+
+	tests := []struct {
+		name      string
+		idle      time.Duration
+		clientIP  string
+		userAgent string
+		score     int
+		band      string
+	}{
+		{"unchanged", time.Minute, "203.0.113.7", riskFirefoxUA, 0, "low"},
+		{"caller without details", time.Minute, "", "", 0, "low"},
+		{"browser update", time.Minute, "203.0.113.7", riskFirefoxUpdateUA, 1, "low"},
+		{"same network after a pause", 10 * time.Minute, "203.0.113.99", riskFirefoxUA, 2, "low"},
+		{"new network", time.Minute, "198.51.100.9", riskFirefoxUA, 4, "medium"},
+		{"other browser after a long pause", 20 * time.Minute, "203.0.113.7", riskChromeUA, 6, "medium"},
+		{"new network and browser", time.Minute, "198.51.100.9", riskChromeUA, 8, "high"},
+		{"new network and browser after a long pause", 20 * time.Minute, "2001:db8::1", riskChromeUA, 10, "high"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := svc.computeRiskScore(newRiskSession(tt.idle), tt.clientIP, tt.userAgent)
+			assert.Equal(t, tt.score, score)
+			assert.Equal(t, tt.band, RiskBand(score))
+		})
+	}
+}
+
+func TestSameNetwork(t *testing.T) {
+	assert.True(t, sameNetwork("203.0.113.7", "203.0.113.250"))
+	assert.False(t, sameNetwork("203.0.113.7", "203.0.114.7"))
+	assert.True(t, sameNetwork("2001:db8:1:2::1", "2001:db8:1:2:ffff::9"))
+	assert.False(t, sameNetwork("2001:db8:1:2::1", "2001:db8:1:3::1"))
+	assert.False(t, sameNetwork("203.0.113.7", "::ffff:198.51.100.9"))
+	assert.False(t, sameNetwork("203.0.113.7", "not-an-ip"))
+}
+
+func TestValidateSessionWithRisk_RevokesAboveThreshold(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	cfg := DefaultSessionConfig()
+	cfg.RiskRevokeThreshold = 6
+	svc, err := NewSessionService(sqlx.NewDb(mockDB, "postgres"), cfg, nil)
+	require.NoError(t, err)
+
+	// A medium score is reported but the session stays valid
+	session := newRiskSession(time.Minute)
+	svc.cacheSession(session)
+	mock.ExpectExec("UPDATE auth.sessions SET last_activity_at").WillReturnResult(sqlmock.NewResult(0, 1))
+	_, score, err := svc.ValidateSessionWithRisk(session.ID, "198.51.100.9", riskFirefoxUA)
+	require.NoError(t, err)
+	assert.Equal(t, 4, score)
+
+	// A new network and browser is above the threshold and ends the session
+	mock.ExpectExec("UPDATE auth.sessions SET is_active = false").WithArgs(session.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+	_, score, err = svc.ValidateSessionWithRisk(session.ID, "198.51.100.9", riskChromeUA)
+	assert.ErrorIs(t, err, ErrSessionSecurityViolation)
+	assert.Equal(t, 8, score)
+	_, cached := svc.cachedSession(session.ID)
+	assert.False(t, cached)
+	assert.Equal(t, int64(1), svc.GetMetrics().SecurityEvents)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
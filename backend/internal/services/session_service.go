@@ -6,8 +6,9 @@ import (
 	"crypto/sha256"
 	"database/sql"
 	"encoding/hex"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"sync"
 	"time"
 
@@ -36,21 +37,17 @@ func DefaultSessionConfig() *config.SessionConfig {
 		CleanupInterval:    5 * time.Minute,
 		MaxSessionsPerUser: 5,
 		SessionIDLength:    128,
+		MaxCachedSessions:  config.DefaultMaxCachedSessions,
+		PermissionCacheTTL:       config.DefaultPermissionCacheTTL,
+		MaxCachedPermissionUsers: config.DefaultMaxCachedPermissionUsers,
+		MetricsSnapshotInterval:  config.DefaultMetricsSnapshotInterval,
+		DeduplicateLoads:         true,
 		RequireIPMatch:     false, // Disabled by default for flexibility
 		RequireUAMatch:     false, // Disabled by default for flexibility
 	}
 }
 
-// InMemorySessionStore provides fast in-memory session storage
-type InMemorySessionStore struct {
-	sessions     *sync.Map // sessionID -> *SessionData
-	userSessions *sync.Map // userID -> []sessionID
-	cleanup      *time.Ticker
-	metrics      *SessionMetrics
-	mutex        sync.RWMutex
-}
-
-// SessionData represents session information stored in memory
+// SessionData represents session information held by a SessionStore
 type SessionData struct {
 	ID                     string
 	UserID                 uuid.UUID
@@ -66,6 +63,7 @@ type SessionData struct {
 	Roles                  []string
 	IsActive               bool
 	RequiresPasswordChange bool
+	DeviceKey              string // Base64 SPKI public key the session is bound to, empty when unbound
 }
 
 // SessionMetrics tracks session performance metrics
@@ -76,47 +74,75 @@ type SessionMetrics struct {
 	AvgLookupTime    time.Duration
 	CleanupCount     int64
 	SecurityEvents   int64
+	CachedSessions   int64 // Sessions currently held in memory, 0 for stores without a bound
+	CacheEvictions   int64 // Sessions dropped from memory to stay within MaxCachedSessions
+	SharedLoads      int64 // Validations that waited for a concurrent database load instead of running their own
 	mutex            sync.RWMutex
 }
 
 // SessionService provides comprehensive session management
 type SessionService struct {
 	db              *sqlx.DB
-	inMemoryStore   *InMemorySessionStore
+	sessionStore    SessionStore
+	metrics         *SessionMetrics
 	config          *config.SessionConfig
 	auditLogStore   store.AuditLogStore
 	cleanupTicker   *time.Ticker
 	mutex           sync.RWMutex
+	deviceNonces    *deviceNonceCache
+	permissions     *UserPermissionCache
+	metricsSnapshot *sessionMetricsSnapshot
+	loads           *sessionLoadGroup // Shares database loads between concurrent validations; nil when disabled
 }
 
-// NewSessionService creates a new session service
-func NewSessionService(db *sqlx.DB, config *config.SessionConfig, auditLogStore store.AuditLogStore) (*SessionService, error) {
-	if config == nil {
-		config = DefaultSessionConfig()
+// SessionServiceOption configures optional behaviour of the session service
+type SessionServiceOption func(*SessionService)
+
+// WithSessionStore replaces the default InMemorySessionStore, for example with a
+// RedisSessionStore shared by every instance. A nil store keeps the default.
+func WithSessionStore(sessionStore SessionStore) SessionServiceOption {
+	return func(s *SessionService) {
+		if sessionStore != nil {
+			s.sessionStore = sessionStore
+		}
 	}
+}
 
-	// Initialize in-memory store
-	inMemoryStore := &InMemorySessionStore{
-		sessions:     &sync.Map{},
-		userSessions: &sync.Map{},
-		metrics:      &SessionMetrics{},
+// NewSessionService creates a new session service. Call Start to begin expiry cleanup.
+func NewSessionService(db *sqlx.DB, config *config.SessionConfig, auditLogStore store.AuditLogStore, opts ...SessionServiceOption) (*SessionService, error) {
+	if config == nil {
+		config = DefaultSessionConfig()
 	}
 
 	service := &SessionService{
 		db:            db,
-		inMemoryStore: inMemoryStore,
+		sessionStore:  NewInMemorySessionStore(config.MaxCachedSessions),
+		metrics:       &SessionMetrics{},
 		config:        config,
 		auditLogStore: auditLogStore,
+		deviceNonces:  newDeviceNonceCache(),
+		permissions:   NewUserPermissionCache(config.PermissionCacheTTL, config.MaxCachedPermissionUsers),
+		metricsSnapshot: &sessionMetricsSnapshot{},
 	}
-
-	// Start cleanup goroutine
-	service.startCleanup()
+	if config.DeduplicateLoads {
+		service.loads = newSessionLoadGroup()
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	service.hydrateFromDatabase()
 
 	return service, nil
 }
 
 // CreateSession creates a new session with fingerprinting
 func (s *SessionService) CreateSession(userID uuid.UUID, ipAddress, userAgent string) (*SessionData, error) {
+	return s.CreateDeviceBoundSession(userID, ipAddress, userAgent, "")
+}
+
+// CreateDeviceBoundSession creates a new session bound to the device public key sent at login.
+// An empty deviceKey creates an unbound session.
+func (s *SessionService) CreateDeviceBoundSession(userID uuid.UUID, ipAddress, userAgent, deviceKey string) (*SessionData, error) {
 	startTime := time.Now()
 	requestID := uuid.New().String()
 
@@ -139,7 +165,6 @@ func (s *SessionService) CreateSession(userID uuid.UUID, ipAddress, userAgent st
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate session ID: %w", err)
 	}
-	fmt.Printf("DEBUG: Generated session ID: %s\n", sessionID)
 
 	// Check concurrent session limits
 	if err := s.enforceSessionLimits(userID); err != nil {
@@ -165,25 +190,26 @@ func (s *SessionService) CreateSession(userID uuid.UUID, ipAddress, userAgent st
 		Permissions:  permissions,
 		Roles:        roles,
 		IsActive:     true,
+		DeviceKey:    deviceKey,
 	}
 
 	// Store in database
-	fmt.Printf("DEBUG: Persisting session to database: %s\n", session.ID)
 	if err := s.persistSession(session); err != nil {
 		return nil, fmt.Errorf("failed to persist session: %w", err)
 	}
-	fmt.Printf("DEBUG: Session successfully persisted to database\n")
 
-	// Store in memory for fast access
-	fmt.Printf("DEBUG: Storing session in memory: %s\n", session.ID)
-	s.storeInMemory(session)
-	fmt.Printf("DEBUG: Session successfully stored in memory\n")
+	// Store in the session store for fast access
+	s.cacheSession(session)
+	logging.Debug(logging.CategorySession, "session_stored", map[string]interface{}{
+		"session": logging.RedactSessionID(session.ID),
+		"userId":  userID.String(),
+	})
 
 	// Update metrics
-	s.inMemoryStore.metrics.mutex.Lock()
-	s.inMemoryStore.metrics.TotalSessions++
-	s.inMemoryStore.metrics.ActiveSessions++
-	s.inMemoryStore.metrics.mutex.Unlock()
+	s.metrics.mutex.Lock()
+	s.metrics.TotalSessions++
+	s.metrics.ActiveSessions++
+	s.metrics.mutex.Unlock()
 
 	duration := time.Since(startTime)
 
@@ -213,26 +239,48 @@ func (s *SessionService) CreateSession(userID uuid.UUID, ipAddress, userAgent st
 
 // ValidateSession validates a session and returns session data
 func (s *SessionService) ValidateSession(sessionID, clientIP string) (*SessionData, error) {
+	session, _, err := s.ValidateSessionWithRisk(sessionID, clientIP, "")
+	return session, err
+}
+
+// ValidateSessionWithRisk validates a session for a request from clientIP with userAgent and
+// returns the request's risk score (see computeRiskScore). A session whose score is above
+// RiskRevokeThreshold is revoked and ErrSessionSecurityViolation returned.
+func (s *SessionService) ValidateSessionWithRisk(sessionID, clientIP, userAgent string) (*SessionData, int, error) {
 	startTime := time.Now()
-	fmt.Printf("DEBUG: Validating session ID: %s for client IP: %s\n", sessionID, clientIP)
-	
-	// Try memory first for performance
-	session, found := s.getFromMemory(sessionID)
+
+	// Try the session store first for performance
+	session, found := s.cachedSession(sessionID)
 	cacheHit := found
-	fmt.Printf("DEBUG: Memory lookup result: found=%v\n", found)
-	
+	logging.Debug(logging.CategorySession, "session_lookup", map[string]interface{}{
+		"session":  logging.RedactSessionID(sessionID),
+		"clientIp": clientIP,
+		"cacheHit": found,
+	})
+
 	if !found {
-		// Fallback to database
-		fmt.Printf("DEBUG: Session not in memory, checking database\n")
+		// Fallback to database, sharing the load with concurrent validations of the same session
 		var err error
-		session, err = s.loadFromDatabase(sessionID)
+		var shared bool
+		session, shared, err = s.loads.do(sessionID, func() (*SessionData, error) {
+			// A load that finished just before this one started has stored the session already
+			if stored, ok := s.cachedSession(sessionID); ok {
+				return stored, nil
+			}
+			return s.loadFromDatabase(sessionID)
+		}, s.cacheSession, func(stale *SessionData) { s.uncacheSession(stale.ID) })
+		if shared {
+			s.metrics.mutex.Lock()
+			s.metrics.SharedLoads++
+			s.metrics.mutex.Unlock()
+		}
 		if err != nil {
-			fmt.Printf("DEBUG: Database lookup failed: %v\n", err)
-			return nil, ErrSessionNotFound
+			logging.Debug(logging.CategorySession, "session_lookup_failed", map[string]interface{}{
+				"session": logging.RedactSessionID(sessionID),
+				"error":   err.Error(),
+			})
+			return nil, 0, ErrSessionNotFound
 		}
-		fmt.Printf("DEBUG: Session found in database, caching in memory\n")
-		// Cache in memory
-		s.storeInMemory(session)
 	}
 
 	// Update cache hit rate metric
@@ -240,7 +288,7 @@ func (s *SessionService) ValidateSession(sessionID, clientIP string) (*SessionDa
 
 	// Validate session state
 	if !session.IsActive {
-		return nil, ErrSessionExpired
+		return nil, 0, ErrSessionExpired
 	}
 
 	now := time.Now()
@@ -248,26 +296,38 @@ func (s *SessionService) ValidateSession(sessionID, clientIP string) (*SessionDa
 	// Check hard expiration
 	if now.After(session.ExpiresAt) {
 		s.invalidateSession(sessionID)
-		return nil, ErrSessionExpired
+		return nil, 0, ErrSessionExpired
 	}
 
 	// Check idle timeout
 	if now.Sub(session.LastActivity) > s.config.IdleTimeout {
 		s.invalidateSession(sessionID)
 		s.logAuditEvent(nil, sessionID, session.UserID, "session_expired", "Session expired due to idle timeout")
-		return nil, ErrSessionExpired
+		return nil, 0, ErrSessionExpired
 	}
 
 	// Enhanced security checks
 	if err := s.validateSessionSecurity(session, clientIP, ""); err != nil {
 		s.logAuditEvent(nil, sessionID, session.UserID, "session_security_violation", fmt.Sprintf("Security violation: %s", err.Error()))
 		s.invalidateSession(sessionID)
-		return nil, err
+		return nil, 0, err
+	}
+
+	// Score the request before its activity is recorded, so the idle gap counts
+	riskScore := s.computeRiskScore(session, clientIP, userAgent)
+	if threshold := s.config.RiskRevokeThreshold; threshold > 0 && riskScore > threshold {
+		s.metrics.mutex.Lock()
+		s.metrics.SecurityEvents++
+		s.metrics.mutex.Unlock()
+		s.logAuditEvent(nil, sessionID, session.UserID, "session_risk_revoked", fmt.Sprintf("Risk score %d is above %d", riskScore, threshold))
+		s.invalidateSession(sessionID)
+		return nil, riskScore, ErrSessionSecurityViolation
 	}
 
 	// Update last activity
 	session.LastActivity = now
 	s.updateLastActivity(sessionID, now)
+	s.cacheSession(session)
 
 	duration := time.Since(startTime)
 
@@ -277,70 +337,78 @@ func (s *SessionService) ValidateSession(sessionID, clientIP string) (*SessionDa
 		&session.UserID,
 		&sessionID,
 		clientIP,
-		"",
+		userAgent,
 		true,
 		&session.ExpiresAt,
 		map[string]interface{}{
 			"validation_duration_ms": duration.Milliseconds(),
 			"cache_hit":              cacheHit,
 			"idle_time_mins":         now.Sub(session.LastActivity).Minutes(),
+			"risk_score":             riskScore,
 		},
 	)
 
-	return session, nil
+	return session, riskScore, nil
 }
 
 // InvalidateSession invalidates a specific session
 func (s *SessionService) InvalidateSession(sessionID string) error {
-	s.removeFromMemory(sessionID)
+	s.loads.invalidate(sessionID)
+	s.uncacheSession(sessionID)
 	
 	// Update metrics
-	s.inMemoryStore.metrics.mutex.Lock()
-	s.inMemoryStore.metrics.ActiveSessions--
-	s.inMemoryStore.metrics.mutex.Unlock()
+	s.metrics.mutex.Lock()
+	s.metrics.ActiveSessions--
+	s.metrics.mutex.Unlock()
 	
 	return s.markInactiveInDatabase(sessionID)
 }
 
-// InvalidateAllUserSessions invalidates all sessions for a user
+// InvalidateAllUserSessions invalidates every session of a user, in auth.sessions and in the
+// session store, so that none of them validates again. A user without active sessions is left as is.
 func (s *SessionService) InvalidateAllUserSessions(userID uuid.UUID) error {
-	s.inMemoryStore.mutex.Lock()
-	defer s.inMemoryStore.mutex.Unlock()
+	// Mark inactive in the database first, so a session reloaded from it from now on is inactive
+	result, err := s.db.Exec(`UPDATE auth.sessions SET is_active = false WHERE user_id = $1 AND is_active = true`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate sessions of user %s: %w", userID, err)
+	}
+	invalidated, _ := result.RowsAffected()
+	s.loads.invalidateAll()
 
-	// Get all user sessions from memory
-	if sessionIDsInterface, exists := s.inMemoryStore.userSessions.Load(userID); exists {
-		sessionIDs := sessionIDsInterface.([]string)
-		
-		// Remove from memory
-		for _, sessionID := range sessionIDs {
-			s.inMemoryStore.sessions.Delete(sessionID)
-			
-			// Update metrics
-			s.inMemoryStore.metrics.mutex.Lock()
-			s.inMemoryStore.metrics.ActiveSessions--
-			s.inMemoryStore.metrics.mutex.Unlock()
+	// Remove from the session store, which holds sessions loaded before the update
+	ctx := context.Background()
+	sessionIDs, err := s.sessionStore.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list stored sessions of user %s: %w", userID, err)
+	}
+	removed := int64(0)
+	for _, sessionID := range sessionIDs {
+		held, err := s.sessionStore.Delete(ctx, sessionID)
+		if err != nil {
+			return fmt.Errorf("failed to remove stored session of user %s: %w", userID, err)
+		}
+		if held {
+			removed++
 		}
-		s.inMemoryStore.userSessions.Delete(userID)
 	}
 
-	// Mark inactive in database
-	query := `UPDATE auth.sessions SET is_active = false WHERE user_id = $1`
-	_, err := s.db.Exec(query, userID)
-	
-	if err == nil {
+	if removed > 0 {
+		s.metrics.mutex.Lock()
+		s.metrics.ActiveSessions -= removed
+		s.metrics.mutex.Unlock()
+	}
+	if invalidated > 0 || removed > 0 {
 		s.logAuditEvent(nil, "", userID, "all_sessions_invalidated", fmt.Sprintf("All sessions invalidated for user %s", userID))
 	}
-	
-	return err
+	return nil
 }
 
 // ExtendSession extends a session's expiration time
 func (s *SessionService) ExtendSession(sessionID string, newExpiry time.Time) error {
-	// Update in memory
-	if sessionInterface, exists := s.inMemoryStore.sessions.Load(sessionID); exists {
-		session := sessionInterface.(*SessionData)
+	// Update in the session store
+	if session, found := s.cachedSession(sessionID); found {
 		session.ExpiresAt = newExpiry
-		s.inMemoryStore.sessions.Store(sessionID, session)
+		s.cacheSession(session)
 	}
 
 	// Update in database
@@ -356,17 +424,21 @@ func (s *SessionService) GetConfig() *config.SessionConfig {
 
 // GetMetrics returns session metrics
 func (s *SessionService) GetMetrics() *SessionMetrics {
-	s.inMemoryStore.metrics.mutex.RLock()
-	defer s.inMemoryStore.metrics.mutex.RUnlock()
+	s.metrics.mutex.RLock()
+	defer s.metrics.mutex.RUnlock()
 	
 	// Return a copy to avoid race conditions
+	cached, evictions := s.cacheStats()
 	return &SessionMetrics{
-		TotalSessions:  s.inMemoryStore.metrics.TotalSessions,
-		ActiveSessions: s.inMemoryStore.metrics.ActiveSessions,
-		CacheHitRate:   s.inMemoryStore.metrics.CacheHitRate,
-		AvgLookupTime:  s.inMemoryStore.metrics.AvgLookupTime,
-		CleanupCount:   s.inMemoryStore.metrics.CleanupCount,
-		SecurityEvents: s.inMemoryStore.metrics.SecurityEvents,
+		TotalSessions:  s.metrics.TotalSessions,
+		ActiveSessions: s.metrics.ActiveSessions,
+		CacheHitRate:   s.metrics.CacheHitRate,
+		AvgLookupTime:  s.metrics.AvgLookupTime,
+		CleanupCount:   s.metrics.CleanupCount,
+		SecurityEvents: s.metrics.SecurityEvents,
+		CachedSessions: cached,
+		CacheEvictions: s.metrics.CacheEvictions + evictions,
+		SharedLoads:    s.metrics.SharedLoads,
 	}
 }
 
@@ -387,23 +459,35 @@ func (s *SessionService) generateFingerprint(ipAddress, userAgent string) string
 }
 
 func (s *SessionService) enforceSessionLimits(userID uuid.UUID) error {
-	if sessionIDsInterface, exists := s.inMemoryStore.userSessions.Load(userID); exists {
-		sessionIDs := sessionIDsInterface.([]string)
-		
-		if len(sessionIDs) >= s.config.MaxSessionsPerUser {
-			// Remove oldest session
-			oldestSessionID := sessionIDs[0]
-			s.InvalidateSession(oldestSessionID)
-			
-			// Update user sessions list
-			newSessionIDs := sessionIDs[1:]
-			s.inMemoryStore.userSessions.Store(userID, newSessionIDs)
-		}
+	sessionIDs, err := s.sessionStore.ListByUser(context.Background(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to list sessions of user %s: %w", userID, err)
+	}
+	if len(sessionIDs) >= s.config.MaxSessionsPerUser {
+		// Remove oldest session, which also drops it from the user's sessions
+		s.InvalidateSession(sessionIDs[0])
 	}
 	return nil
 }
 
+// PermissionCache returns the cache shared by everything that loads a user's roles and permissions,
+// or nil when caching is disabled
+func (s *SessionService) PermissionCache() *UserPermissionCache {
+	return s.permissions
+}
+
+// UserPermissions returns the user's permission and role names, from the permission cache when fresh
+func (s *SessionService) UserPermissions(userID uuid.UUID) ([]string, []string, error) {
+	return s.loadUserPermissions(userID)
+}
+
 func (s *SessionService) loadUserPermissions(userID uuid.UUID) ([]string, []string, error) {
+	return s.permissions.Get(userID, func() ([]string, []string, error) {
+		return s.queryUserPermissions(userID)
+	})
+}
+
+func (s *SessionService) queryUserPermissions(userID uuid.UUID) ([]string, []string, error) {
 	// Load roles
 	rolesQuery := `
 		SELECT r.name
@@ -439,11 +523,12 @@ func (s *SessionService) persistSession(session *SessionData) error {
 	// Only insert the essential fields and let the database populate the fingerprint fields
 	insertQuery := `
 		INSERT INTO auth.sessions (id, user_id, ip_address, user_agent, is_active, expires_at,
-		                          last_activity_at, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		                          last_activity_at, created_at, device_public_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
 
 	_, err := s.db.Exec(insertQuery, session.ID, session.UserID, session.IPAddress, session.UserAgent,
-		session.IsActive, session.ExpiresAt, session.LastActivity, session.CreatedAt)
+		session.IsActive, session.ExpiresAt, session.LastActivity, session.CreatedAt,
+		sql.NullString{String: session.DeviceKey, Valid: session.DeviceKey != ""})
 	
 	if err != nil {
 		return err
@@ -469,67 +554,55 @@ func (s *SessionService) persistSession(session *SessionData) error {
 	return nil
 }
 
-func (s *SessionService) storeInMemory(session *SessionData) {
-	s.inMemoryStore.sessions.Store(session.ID, session)
-	
-	// Update user sessions mapping
-	if sessionIDsInterface, exists := s.inMemoryStore.userSessions.Load(session.UserID); exists {
-		sessionIDs := sessionIDsInterface.([]string)
-		sessionIDs = append(sessionIDs, session.ID)
-		s.inMemoryStore.userSessions.Store(session.UserID, sessionIDs)
-	} else {
-		s.inMemoryStore.userSessions.Store(session.UserID, []string{session.ID})
+// cacheSession stores a session for fast validation. A session the store fails to hold is
+// reloaded from the database on its next validation.
+func (s *SessionService) cacheSession(session *SessionData) {
+	if err := s.sessionStore.Set(context.Background(), session); err != nil {
+		log.Printf("SessionService: failed to store session %s: %v", logging.RedactSessionID(session.ID), err)
 	}
 }
 
-func (s *SessionService) getFromMemory(sessionID string) (*SessionData, bool) {
-	if sessionInterface, exists := s.inMemoryStore.sessions.Load(sessionID); exists {
-		return sessionInterface.(*SessionData), true
+// cachedSession returns a session from the session store. A store that fails is treated as a miss.
+func (s *SessionService) cachedSession(sessionID string) (*SessionData, bool) {
+	session, err := s.sessionStore.Get(context.Background(), sessionID)
+	if err != nil {
+		if !errors.Is(err, ErrSessionNotFound) {
+			log.Printf("SessionService: failed to read session %s from the session store: %v", logging.RedactSessionID(sessionID), err)
+		}
+		return nil, false
 	}
-	return nil, false
+	return session, true
 }
 
-func (s *SessionService) removeFromMemory(sessionID string) {
-	if sessionInterface, exists := s.inMemoryStore.sessions.Load(sessionID); exists {
-		session := sessionInterface.(*SessionData)
-		
-		// Remove from sessions map
-		s.inMemoryStore.sessions.Delete(sessionID)
-		
-		// Remove from user sessions map
-		if sessionIDsInterface, exists := s.inMemoryStore.userSessions.Load(session.UserID); exists {
-			sessionIDs := sessionIDsInterface.([]string)
-			newSessionIDs := make([]string, 0, len(sessionIDs))
-			for _, id := range sessionIDs {
-				if id != sessionID {
-					newSessionIDs = append(newSessionIDs, id)
-				}
-			}
-			if len(newSessionIDs) > 0 {
-				s.inMemoryStore.userSessions.Store(session.UserID, newSessionIDs)
-			} else {
-				s.inMemoryStore.userSessions.Delete(session.UserID)
-			}
-		}
+// uncacheSession removes a session from the session store
+func (s *SessionService) uncacheSession(sessionID string) {
+	if _, err := s.sessionStore.Delete(context.Background(), sessionID); err != nil {
+		log.Printf("SessionService: failed to remove session %s from the session store: %v", logging.RedactSessionID(sessionID), err)
 	}
 }
 
-func (s *SessionService) loadFromDatabase(sessionID string) (*SessionData, error) {
-	query := `
-		SELECT id, user_id, ip_address, user_agent, session_fingerprint, browser_fingerprint,
-		       screen_resolution, is_active, expires_at, last_activity_at, created_at
-		FROM auth.sessions
-		WHERE id = $1`
+// cacheStats returns the sessions held by a bounded session store and how many it has evicted
+func (s *SessionService) cacheStats() (cached, evictions int64) {
+	if stats, ok := s.sessionStore.(sessionCacheStats); ok {
+		return stats.cacheStats()
+	}
+	return 0, 0
+}
 
+// sessionColumns are the auth.sessions columns read by scanSession
+const sessionColumns = `id, user_id, ip_address, user_agent, session_fingerprint, browser_fingerprint,
+		       screen_resolution, is_active, expires_at, last_activity_at, created_at, device_public_key`
+
+// scanSession reads a row of sessionColumns, without permissions and roles
+func scanSession(row interface{ Scan(dest ...interface{}) error }) (*SessionData, error) {
 	var session SessionData
-	var ipAddress, userAgent, fingerprint, browserFingerprint, screenResolution sql.NullString
-	
-	err := s.db.QueryRow(query, sessionID).Scan(
+	var ipAddress, userAgent, fingerprint, browserFingerprint, screenResolution, deviceKey sql.NullString
+
+	err := row.Scan(
 		&session.ID, &session.UserID, &ipAddress, &userAgent, &fingerprint,
 		&browserFingerprint, &screenResolution, &session.IsActive,
-		&session.ExpiresAt, &session.LastActivity, &session.CreatedAt,
+		&session.ExpiresAt, &session.LastActivity, &session.CreatedAt, &deviceKey,
 	)
-	
 	if err != nil {
 		return nil, err
 	}
@@ -540,6 +613,20 @@ func (s *SessionService) loadFromDatabase(sessionID string) (*SessionData, error
 	session.Fingerprint = fingerprint.String
 	session.BrowserFingerprint = browserFingerprint.String
 	session.ScreenResolution = screenResolution.String
+	session.DeviceKey = deviceKey.String
+	return &session, nil
+}
+
+func (s *SessionService) loadFromDatabase(sessionID string) (*SessionData, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM auth.sessions
+		WHERE id = $1`
+
+	session, err := scanSession(s.db.QueryRow(query, sessionID))
+	if err != nil {
+		return nil, err
+	}
 
 	// Load permissions and roles
 	permissions, roles, err := s.loadUserPermissions(session.UserID)
@@ -549,7 +636,73 @@ func (s *SessionService) loadFromDatabase(sessionID string) (*SessionData, error
 	session.Permissions = permissions
 	session.Roles = roles
 
-	return &session, nil
+	return session, nil
+}
+
+// defaultSessionHydrationLimit caps hydrateFromDatabase when MaxCachedSessions leaves the cache unbounded
+const defaultSessionHydrationLimit = 10000
+
+// hydrateFromDatabase warms an in-memory session store with the active sessions in auth.sessions,
+// so that requests after a restart are served from memory and per-user limits count the sessions
+// created before it. The most recently active sessions are loaded, up to the cache bound. A shared
+// store outlives restarts and is left as it is.
+func (s *SessionService) hydrateFromDatabase() {
+	if s.db == nil {
+		return
+	}
+	if _, inMemory := s.sessionStore.(*InMemorySessionStore); !inMemory {
+		return
+	}
+	limit := s.config.MaxCachedSessions
+	if limit <= 0 {
+		limit = defaultSessionHydrationLimit
+	}
+
+	// Oldest first, so that each user's sessions are listed in creation order
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM (
+			SELECT * FROM auth.sessions
+			WHERE is_active = true AND expires_at > NOW()
+			ORDER BY last_activity_at DESC
+			LIMIT $1
+		) recent
+		ORDER BY created_at`
+	rows, err := s.db.Query(query, limit)
+	if err != nil {
+		log.Printf("SessionService: failed to load active sessions, starting with an empty cache: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	var sessions []*SessionData
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			log.Printf("SessionService: failed to read active session, starting with an empty cache: %v", err)
+			return
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("SessionService: failed to load active sessions, starting with an empty cache: %v", err)
+		return
+	}
+
+	restored := 0
+	for _, session := range sessions {
+		// Sessions of users whose permissions cannot be loaded are left to be reloaded on their next validation
+		permissions, roles, err := s.loadUserPermissions(session.UserID)
+		if err != nil {
+			log.Printf("SessionService: failed to load permissions of user %s, not restoring session: %v", session.UserID, err)
+			continue
+		}
+		session.Permissions = permissions
+		session.Roles = roles
+		s.cacheSession(session)
+		restored++
+	}
+	log.Printf("SessionService: restored %d active sessions from the database", restored)
 }
 
 func (s *SessionService) markInactiveInDatabase(sessionID string) error {
@@ -567,18 +720,18 @@ func (s *SessionService) updateLastActivity(sessionID string, lastActivity time.
 func (s *SessionService) validateSessionSecurity(session *SessionData, clientIP, userAgent string) error {
 	// IP validation (if enabled)
 	if s.config.RequireIPMatch && session.IPAddress != clientIP {
-		s.inMemoryStore.metrics.mutex.Lock()
-		s.inMemoryStore.metrics.SecurityEvents++
-		s.inMemoryStore.metrics.mutex.Unlock()
+		s.metrics.mutex.Lock()
+		s.metrics.SecurityEvents++
+		s.metrics.mutex.Unlock()
 		
 		return fmt.Errorf("IP address mismatch: expected %s, got %s", session.IPAddress, clientIP)
 	}
 
 	// User Agent validation (if enabled)
 	if s.config.RequireUAMatch && userAgent != "" && session.UserAgent != userAgent {
-		s.inMemoryStore.metrics.mutex.Lock()
-		s.inMemoryStore.metrics.SecurityEvents++
-		s.inMemoryStore.metrics.mutex.Unlock()
+		s.metrics.mutex.Lock()
+		s.metrics.SecurityEvents++
+		s.metrics.mutex.Unlock()
 		
 		return fmt.Errorf("user agent mismatch")
 	}
@@ -587,17 +740,28 @@ func (s *SessionService) validateSessionSecurity(session *SessionData, clientIP,
 }
 
 func (s *SessionService) updateCacheMetrics(cacheHit bool) {
-	s.inMemoryStore.metrics.mutex.Lock()
-	defer s.inMemoryStore.metrics.mutex.Unlock()
+	s.metrics.mutex.Lock()
+	defer s.metrics.mutex.Unlock()
 	
 	// Simple moving average for cache hit rate
 	if cacheHit {
-		s.inMemoryStore.metrics.CacheHitRate = (s.inMemoryStore.metrics.CacheHitRate*0.9 + 1.0*0.1)
+		s.metrics.CacheHitRate = (s.metrics.CacheHitRate*0.9 + 1.0*0.1)
 	} else {
-		s.inMemoryStore.metrics.CacheHitRate = (s.inMemoryStore.metrics.CacheHitRate * 0.9)
+		s.metrics.CacheHitRate = (s.metrics.CacheHitRate * 0.9)
 	}
 }
 
+// Start begins the periodic cleanup of expired sessions. Calling Start again has no effect.
+func (s *SessionService) Start() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.cleanupTicker != nil {
+		return
+	}
+	s.startCleanup()
+	s.startMetricsSnapshots()
+}
+
 func (s *SessionService) startCleanup() {
 	s.cleanupTicker = time.NewTicker(s.config.CleanupInterval)
 	
@@ -610,23 +774,20 @@ func (s *SessionService) startCleanup() {
 
 func (s *SessionService) performCleanup() {
 	now := time.Now()
-	expiredSessions := 0
-	
-	// Clean up expired sessions from memory
-	s.inMemoryStore.sessions.Range(func(key, value interface{}) bool {
-		session := value.(*SessionData)
-		if now.After(session.ExpiresAt) || now.Sub(session.LastActivity) > s.config.IdleTimeout {
-			s.inMemoryStore.sessions.Delete(key)
-			expiredSessions++
-		}
-		return true
-	})
+
+	// Clean up expired sessions from the session store
+	expiredSessions, err := s.sessionStore.Cleanup(context.Background(), now, s.config.IdleTimeout)
+	if err != nil {
+		log.Printf("SessionService: failed to clean up the session store: %v", err)
+	}
+
+	s.deviceNonces.prune(now)
 
 	// Clean up expired sessions from database
 	query := `UPDATE auth.sessions SET is_active = false 
 	          WHERE is_active = true AND (expires_at < NOW() OR last_activity_at < NOW() - INTERVAL '%d minutes')`
 	
-	_, err := s.db.Exec(fmt.Sprintf(query, int(s.config.IdleTimeout.Minutes())))
+	_, err = s.db.Exec(fmt.Sprintf(query, int(s.config.IdleTimeout.Minutes())))
 	if err != nil {
 		logging.LogDatabaseOperation(
 			"session_cleanup",
@@ -642,10 +803,10 @@ func (s *SessionService) performCleanup() {
 	}
 
 	// Update cleanup metrics
-	s.inMemoryStore.metrics.mutex.Lock()
-	s.inMemoryStore.metrics.CleanupCount++
-	s.inMemoryStore.metrics.ActiveSessions -= int64(expiredSessions)
-	s.inMemoryStore.metrics.mutex.Unlock()
+	s.metrics.mutex.Lock()
+	s.metrics.CleanupCount++
+	s.metrics.ActiveSessions -= int64(expiredSessions)
+	s.metrics.mutex.Unlock()
 
 	if expiredSessions > 0 {
 		logging.LogSessionEvent(
@@ -658,7 +819,7 @@ func (s *SessionService) performCleanup() {
 			nil,
 			map[string]interface{}{
 				"expired_sessions": expiredSessions,
-				"cleanup_count":    s.inMemoryStore.metrics.CleanupCount,
+				"cleanup_count":    s.metrics.CleanupCount,
 			},
 		)
 	}
@@ -670,7 +831,11 @@ func (s *SessionService) logAuditEvent(ctx context.Context, sessionID string, us
 		return
 	}
 
-	detailsJSON := json.RawMessage(fmt.Sprintf(`{"session_id": "%s", "description": "%s"}`, sessionID, description))
+	details, err := models.NewAuditDetails(map[string]string{"session_id": sessionID, "description": description})
+	if err != nil {
+		fmt.Printf("Failed to create audit log for session %s: %v\n", logging.RedactSessionID(sessionID), err)
+		return
+	}
 	auditLog := &models.AuditLog{
 		ID:         uuid.New(),
 		Timestamp:  time.Now().UTC(),
@@ -678,7 +843,7 @@ func (s *SessionService) logAuditEvent(ctx context.Context, sessionID string, us
 		Action:     action,
 		EntityType: sql.NullString{String: "session", Valid: true},
 		EntityID:   uuid.NullUUID{UUID: userID, Valid: true}, // Use userID as entity for session events
-		Details:    &detailsJSON,
+		Details:    details,
 	}
 
 	if ctx == nil {
@@ -687,15 +852,16 @@ func (s *SessionService) logAuditEvent(ctx context.Context, sessionID string, us
 
 	if err := s.auditLogStore.CreateAuditLog(ctx, s.db, auditLog); err != nil {
 		// Log error but don't fail the operation
-		fmt.Printf("Failed to create audit log for session %s: %v\n", sessionID, err)
+		fmt.Printf("Failed to create audit log for session %s: %v\n", logging.RedactSessionID(sessionID), err)
 	}
 }
 
-// Stop stops the session service cleanup
+// Stop stops the session service cleanup and saves the session metrics one last time
 func (s *SessionService) Stop() {
 	if s.cleanupTicker != nil {
 		s.cleanupTicker.Stop()
 	}
+	s.stopMetricsSnapshots()
 }
 
 // invalidateSession is a private helper that calls InvalidateSession
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionStore holds the sessions SessionService validates without a database round trip.
+// auth.sessions stays the system of record: a session the store does not hold is reloaded from
+// it, so a store may drop sessions at any time.
+type SessionStore interface {
+	// Get returns the session, or ErrSessionNotFound when the store does not hold it
+	Get(ctx context.Context, sessionID string) (*SessionData, error)
+	// Set stores or replaces the session until its ExpiresAt
+	Set(ctx context.Context, session *SessionData) error
+	// Delete removes the session and reports whether the store held it
+	Delete(ctx context.Context, sessionID string) (bool, error)
+	// ListByUser returns the IDs of the user's sessions, oldest first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]string, error)
+	// Cleanup removes the sessions that have expired or been idle for longer than idleTimeout and
+	// returns how many it removed
+	Cleanup(ctx context.Context, now time.Time, idleTimeout time.Duration) (int, error)
+}
+
+// sessionCacheStats is implemented by stores that bound the sessions they hold
+type sessionCacheStats interface {
+	cacheStats() (cached, evictions int64)
+}
+
+// deviceNonceStore is implemented by stores shared between instances, so that a device-proof nonce
+// used on one instance is rejected on every other
+type deviceNonceStore interface {
+	// useDeviceNonce records the session's nonce for ttl and reports whether it had not been recorded
+	useDeviceNonce(ctx context.Context, sessionID, nonce string, ttl time.Duration) (bool, error)
+}
+
+// InMemorySessionStore provides fast in-memory session storage local to one instance. Beyond
+// maxCachedSessions the least recently used sessions are evicted.
+type InMemorySessionStore struct {
+	sessions     *sync.Map // sessionID -> *SessionData
+	userSessions *sync.Map // userID -> []sessionID, kept for evicted sessions so per-user limits still apply
+	lru          *sessionLRU
+	evictions    atomic.Int64
+	mutex        sync.RWMutex // Serialises changes to userSessions
+}
+
+var _ SessionStore = (*InMemorySessionStore)(nil)
+
+// NewInMemorySessionStore creates an in-memory store. maxCachedSessions of 0 or less keeps every session.
+func NewInMemorySessionStore(maxCachedSessions int) *InMemorySessionStore {
+	return &InMemorySessionStore{
+		sessions:     &sync.Map{},
+		userSessions: &sync.Map{},
+		lru:          newSessionLRU(maxCachedSessions),
+	}
+}
+
+// Get returns the session and marks it as most recently used
+func (m *InMemorySessionStore) Get(ctx context.Context, sessionID string) (*SessionData, error) {
+	if sessionInterface, exists := m.sessions.Load(sessionID); exists {
+		m.lru.touch(sessionID)
+		return sessionInterface.(*SessionData), nil
+	}
+	return nil, ErrSessionNotFound
+}
+
+// Set stores the session and evicts the least recently used sessions beyond the bound. Evicted
+// sessions stay listed for their user.
+func (m *InMemorySessionStore) Set(ctx context.Context, session *SessionData) error {
+	m.sessions.Store(session.ID, session)
+
+	// Update user sessions mapping. A session reloaded after eviction is already listed.
+	m.mutex.Lock()
+	if sessionIDsInterface, exists := m.userSessions.Load(session.UserID); exists {
+		sessionIDs := sessionIDsInterface.([]string)
+		listed := false
+		for _, id := range sessionIDs {
+			if id == session.ID {
+				listed = true
+				break
+			}
+		}
+		if !listed {
+			m.userSessions.Store(session.UserID, append(sessionIDs, session.ID))
+		}
+	} else {
+		m.userSessions.Store(session.UserID, []string{session.ID})
+	}
+	m.mutex.Unlock()
+
+	// Evicted sessions stay valid in the database and are reloaded from there on their next validation
+	for _, evictedID := range m.lru.add(session.ID) {
+		m.sessions.Delete(evictedID)
+		m.evictions.Add(1)
+	}
+	return nil
+}
+
+// Delete removes the session from memory and from its user's sessions, including a session that
+// was evicted
+func (m *InMemorySessionStore) Delete(ctx context.Context, sessionID string) (bool, error) {
+	m.lru.remove(sessionID)
+	sessionInterface, held := m.sessions.LoadAndDelete(sessionID)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if held {
+		m.unlistLocked(sessionInterface.(*SessionData).UserID, sessionID)
+		return true, nil
+	}
+	m.userSessions.Range(func(key, value interface{}) bool {
+		for _, id := range value.([]string) {
+			if id == sessionID {
+				m.unlistLocked(key.(uuid.UUID), sessionID)
+				return false
+			}
+		}
+		return true
+	})
+	return false, nil
+}
+
+// unlistLocked removes a session ID from its user's sessions. The caller holds m.mutex.
+func (m *InMemorySessionStore) unlistLocked(userID uuid.UUID, sessionID string) {
+	sessionIDsInterface, exists := m.userSessions.Load(userID)
+	if !exists {
+		return
+	}
+	sessionIDs := sessionIDsInterface.([]string)
+	newSessionIDs := make([]string, 0, len(sessionIDs))
+	for _, id := range sessionIDs {
+		if id != sessionID {
+			newSessionIDs = append(newSessionIDs, id)
+		}
+	}
+	if len(newSessionIDs) > 0 {
+		m.userSessions.Store(userID, newSessionIDs)
+	} else {
+		m.userSessions.Delete(userID)
+	}
+}
+
+// ListByUser returns the user's sessions in the order they were stored, including evicted ones
+func (m *InMemorySessionStore) ListByUser(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if sessionIDsInterface, exists := m.userSessions.Load(userID); exists {
+		return append([]string(nil), sessionIDsInterface.([]string)...), nil
+	}
+	return nil, nil
+}
+
+// Cleanup removes expired and idle sessions held in memory
+func (m *InMemorySessionStore) Cleanup(ctx context.Context, now time.Time, idleTimeout time.Duration) (int, error) {
+	var expired []string
+	m.sessions.Range(func(key, value interface{}) bool {
+		session := value.(*SessionData)
+		if now.After(session.ExpiresAt) || now.Sub(session.LastActivity) > idleTimeout {
+			expired = append(expired, key.(string))
+		}
+		return true
+	})
+	removed := 0
+	for _, sessionID := range expired {
+		if held, _ := m.Delete(ctx, sessionID); held {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (m *InMemorySessionStore) cacheStats() (cached, evictions int64) {
+	return int64(m.lru.len()), m.evictions.Load()
+}
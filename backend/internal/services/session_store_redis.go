@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisSessionKeyPrefix namespaces the keys of RedisSessionStore
+const DefaultRedisSessionKeyPrefix = "domainflow:"
+
+// redisSessionScanCount is the SCAN batch size used by Cleanup
+const redisSessionScanCount = 500
+
+// RedisSessionStore keeps sessions in Redis so that every instance sharing it validates sessions
+// created on any of them. Each session is a JSON encoded SessionData under <prefix>session:<id>
+// that expires with the session; <prefix>user:<id> is a sorted set of the user's session IDs
+// scored by creation time.
+type RedisSessionStore struct {
+	client    redis.UniversalClient
+	keyPrefix string
+}
+
+var (
+	_ SessionStore     = (*RedisSessionStore)(nil)
+	_ deviceNonceStore = (*RedisSessionStore)(nil)
+)
+
+// NewRedisSessionStore creates a store on client. An empty keyPrefix uses DefaultRedisSessionKeyPrefix.
+func NewRedisSessionStore(client redis.UniversalClient, keyPrefix string) *RedisSessionStore {
+	if keyPrefix == "" {
+		keyPrefix = DefaultRedisSessionKeyPrefix
+	}
+	return &RedisSessionStore{client: client, keyPrefix: keyPrefix}
+}
+
+// NewSessionStoreFromSettings returns the store chosen by settings.Store: a RedisSessionStore for
+// redis, or nil for memory so that NewSessionService keeps its in-memory default. An unreachable
+// Redis is only logged, as sessions are reloaded from the database while it is down.
+func NewSessionStoreFromSettings(settings *config.SessionSettings) (SessionStore, error) {
+	if settings == nil || settings.Store == "" || settings.Store == config.SessionStoreMemory {
+		return nil, nil
+	}
+	if settings.Store != config.SessionStoreRedis {
+		return nil, fmt.Errorf("unknown session store %q", settings.Store)
+	}
+	if settings.RedisURL == "" {
+		return nil, fmt.Errorf("the redis session store needs a Redis URL")
+	}
+	options, err := redis.ParseURL(settings.RedisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session Redis URL: %w", err)
+	}
+	client := redis.NewClient(options)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("SessionService: Redis session store at %s is not reachable yet: %v", options.Addr, err)
+	}
+	return NewRedisSessionStore(client, settings.RedisKeyPrefix), nil
+}
+
+func (r *RedisSessionStore) sessionKey(sessionID string) string {
+	return r.keyPrefix + "session:" + sessionID
+}
+
+func (r *RedisSessionStore) userKey(userID uuid.UUID) string {
+	return r.keyPrefix + "user:" + userID.String()
+}
+
+// deviceNonceKey hashes the client-chosen nonce to bound the key length
+func (r *RedisSessionStore) deviceNonceKey(sessionID, nonce string) string {
+	digest := sha256.Sum256([]byte(nonce))
+	return r.keyPrefix + "device_nonce:" + sessionID + ":" + hex.EncodeToString(digest[:])
+}
+
+// Get returns ErrSessionNotFound for sessions that were never stored or have expired
+func (r *RedisSessionStore) Get(ctx context.Context, sessionID string) (*SessionData, error) {
+	encoded, err := r.client.Get(ctx, r.sessionKey(sessionID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis: failed to get session: %w", err)
+	}
+	var session SessionData
+	if err := json.Unmarshal(encoded, &session); err != nil {
+		return nil, fmt.Errorf("redis: failed to decode session: %w", err)
+	}
+	return &session, nil
+}
+
+// Set stores the session until its ExpiresAt and lists it for its user. A session that has
+// already expired is removed instead.
+func (r *RedisSessionStore) Set(ctx context.Context, session *SessionData) error {
+	ttl := time.Until(session.ExpiresAt)
+	if ttl <= 0 {
+		_, err := r.Delete(ctx, session.ID)
+		return err
+	}
+	encoded, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("redis: failed to encode session: %w", err)
+	}
+
+	userKey := r.userKey(session.UserID)
+	userTTL, err := r.client.PTTL(ctx, userKey).Result()
+	if err != nil {
+		return fmt.Errorf("redis: failed to read user sessions expiry: %w", err)
+	}
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, r.sessionKey(session.ID), encoded, ttl)
+		pipe.ZAdd(ctx, userKey, redis.Z{Score: float64(session.CreatedAt.UnixNano()), Member: session.ID})
+		// The user's list lives as long as their longest session
+		if userTTL < ttl {
+			pipe.PExpire(ctx, userKey, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("redis: failed to store session: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the session and unlists it from its user
+func (r *RedisSessionStore) Delete(ctx context.Context, sessionID string) (bool, error) {
+	session, err := r.Get(ctx, sessionID)
+	if errors.Is(err, ErrSessionNotFound) {
+		// Expired sessions are unlisted by ListByUser
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var deleted *redis.IntCmd
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		deleted = pipe.Del(ctx, r.sessionKey(sessionID))
+		pipe.ZRem(ctx, r.userKey(session.UserID), sessionID)
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("redis: failed to delete session: %w", err)
+	}
+	return deleted.Val() > 0, nil
+}
+
+// ListByUser returns the user's sessions, oldest first, and unlists the ones that have expired
+func (r *RedisSessionStore) ListByUser(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	userKey := r.userKey(userID)
+	listed, err := r.client.ZRange(ctx, userKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis: failed to list user sessions: %w", err)
+	}
+	if len(listed) == 0 {
+		return nil, nil
+	}
+
+	exists := make([]*redis.IntCmd, len(listed))
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, sessionID := range listed {
+			exists[i] = pipe.Exists(ctx, r.sessionKey(sessionID))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("redis: failed to check user sessions: %w", err)
+	}
+
+	var sessionIDs []string
+	var expired []interface{}
+	for i, sessionID := range listed {
+		if exists[i].Val() > 0 {
+			sessionIDs = append(sessionIDs, sessionID)
+		} else {
+			expired = append(expired, sessionID)
+		}
+	}
+	if len(expired) > 0 {
+		if err := r.client.ZRem(ctx, userKey, expired...).Err(); err != nil {
+			return nil, fmt.Errorf("redis: failed to unlist expired sessions: %w", err)
+		}
+	}
+	return sessionIDs, nil
+}
+
+// useDeviceNonce sets the nonce's key only if it is not already set (SET NX PX), so exactly one
+// instance accepts each nonce until ttl passes
+func (r *RedisSessionStore) useDeviceNonce(ctx context.Context, sessionID, nonce string, ttl time.Duration) (bool, error) {
+	fresh, err := r.client.SetNX(ctx, r.deviceNonceKey(sessionID, nonce), 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis: failed to record device nonce: %w", err)
+	}
+	return fresh, nil
+}
+
+// Cleanup removes idle sessions. Expired sessions need no cleanup, as Redis expires their keys.
+func (r *RedisSessionStore) Cleanup(ctx context.Context, now time.Time, idleTimeout time.Duration) (int, error) {
+	removed := 0
+	iter := r.client.Scan(ctx, 0, r.keyPrefix+"session:*", redisSessionScanCount).Iterator()
+	for iter.Next(ctx) {
+		sessionID := iter.Val()[len(r.keyPrefix+"session:"):]
+		session, err := r.Get(ctx, sessionID)
+		if errors.Is(err, ErrSessionNotFound) {
+			continue
+		}
+		if err != nil {
+			return removed, err
+		}
+		if now.After(session.ExpiresAt) || now.Sub(session.LastActivity) > idleTimeout {
+			held, err := r.Delete(ctx, sessionID)
+			if err != nil {
+				return removed, err
+			}
+			if held {
+				removed++
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return removed, fmt.Errorf("redis: failed to scan sessions: %w", err)
+	}
+	return removed, nil
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisSessionStore(t *testing.T) (*RedisSessionStore, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisSessionStore(client, "test:"), server
+}
+
+// storedSession is a session created at offset from now that is still valid
+func storedSession(userID uuid.UUID, offset time.Duration) *SessionData {
+	createdAt := time.Now().Add(offset)
+	return &SessionData{ID: uuid.NewString(), UserID: userID, IPAddress: "203.0.113.7", CreatedAt: createdAt,
+		LastActivity: time.Now(), ExpiresAt: time.Now().Add(time.Hour), IsActive: true, Roles: []string{"user"}}
+}
+
+func TestSessionStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) SessionStore{
+		"memory": func(t *testing.T) SessionStore { return NewInMemorySessionStore(0) },
+		"redis": func(t *testing.T) SessionStore {
+			sessionStore, _ := newTestRedisSessionStore(t)
+			return sessionStore
+		},
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			sessionStore := newStore(t)
+			userID := uuid.New()
+			older, newer, other := storedSession(userID, -time.Minute), storedSession(userID, 0), storedSession(uuid.New(), 0)
+			for _, session := range []*SessionData{older, newer, other} {
+				require.NoError(t, sessionStore.Set(ctx, session))
+			}
+
+			got, err := sessionStore.Get(ctx, older.ID)
+			require.NoError(t, err)
+			assert.Equal(t, older.UserID, got.UserID)
+			assert.Equal(t, older.IPAddress, got.IPAddress)
+			assert.Equal(t, []string{"user"}, got.Roles)
+			assert.WithinDuration(t, older.ExpiresAt, got.ExpiresAt, time.Millisecond)
+
+			_, err = sessionStore.Get(ctx, uuid.NewString())
+			assert.ErrorIs(t, err, ErrSessionNotFound)
+
+			sessionIDs, err := sessionStore.ListByUser(ctx, userID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{older.ID, newer.ID}, sessionIDs, "oldest first")
+
+			held, err := sessionStore.Delete(ctx, older.ID)
+			require.NoError(t, err)
+			assert.True(t, held)
+			held, err = sessionStore.Delete(ctx, older.ID)
+			require.NoError(t, err)
+			assert.False(t, held)
+			_, err = sessionStore.Get(ctx, older.ID)
+			assert.ErrorIs(t, err, ErrSessionNotFound)
+			sessionIDs, err = sessionStore.ListByUser(ctx, userID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{newer.ID}, sessionIDs)
+
+			// Sessions idle for longer than the timeout are cleaned up
+			other.LastActivity = time.Now().Add(-time.Hour)
+			require.NoError(t, sessionStore.Set(ctx, other))
+			removed, err := sessionStore.Cleanup(ctx, time.Now(), 30*time.Minute)
+			require.NoError(t, err)
+			assert.Equal(t, 1, removed)
+			_, err = sessionStore.Get(ctx, other.ID)
+			assert.ErrorIs(t, err, ErrSessionNotFound)
+			_, err = sessionStore.Get(ctx, newer.ID)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRedisSessionStore_ExpiresWithSession(t *testing.T) {
+	ctx := context.Background()
+	sessionStore, server := newTestRedisSessionStore(t)
+	userID := uuid.New()
+	short, long := storedSession(userID, -time.Minute), storedSession(userID, 0)
+	short.ExpiresAt = time.Now().Add(10 * time.Minute)
+	require.NoError(t, sessionStore.Set(ctx, short))
+	require.NoError(t, sessionStore.Set(ctx, long))
+
+	assert.InDelta(t, (10 * time.Minute).Seconds(), server.TTL("test:session:"+short.ID).Seconds(), 1)
+	assert.InDelta(t, time.Hour.Seconds(), server.TTL("test:user:"+userID.String()).Seconds(), 1,
+		"the user's list lives as long as the longest session")
+
+	server.FastForward(11 * time.Minute)
+	_, err := sessionStore.Get(ctx, short.ID)
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+	sessionIDs, err := sessionStore.ListByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{long.ID}, sessionIDs)
+
+	// A session that has already expired is not stored
+	expired := storedSession(userID, 0)
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+	require.NoError(t, sessionStore.Set(ctx, expired))
+	assert.False(t, server.Exists("test:session:"+expired.ID))
+}
+
+func TestSessionService_SharedRedisStore(t *testing.T) {
+	sessionStore, _ := newTestRedisSessionStore(t)
+	newNode := func() (*SessionService, sqlmock.Sqlmock) {
+		mockDB, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { mockDB.Close() })
+		svc, err := NewSessionService(sqlx.NewDb(mockDB, "postgres"), DefaultSessionConfig(), nil, WithSessionStore(sessionStore))
+		require.NoError(t, err)
+		return svc, mock
+	}
+	first, firstMock := newNode()
+	second, secondMock := newNode()
+
+	session := storedSession(uuid.New(), 0)
+	first.cacheSession(session)
+
+	// The second node validates the session without loading it from the database
+	secondMock.ExpectExec("UPDATE auth.sessions SET last_activity_at").WillReturnResult(sqlmock.NewResult(0, 1))
+	validated, err := second.ValidateSession(session.ID, session.IPAddress)
+	require.NoError(t, err)
+	assert.Equal(t, session.UserID, validated.UserID)
+	require.NoError(t, secondMock.ExpectationsWereMet())
+
+	// Invalidating on the first node removes it for the second, which falls back to the database
+	firstMock.ExpectExec("UPDATE auth.sessions SET is_active = false WHERE user_id").WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, first.InvalidateAllUserSessions(session.UserID))
+	require.NoError(t, firstMock.ExpectationsWereMet())
+	_, found := second.cachedSession(session.ID)
+	assert.False(t, found)
+}
+
+func TestNewSessionStoreFromSettings(t *testing.T) {
+	settings := config.GetDefaultSessionSettings()
+	sessionStore, err := NewSessionStoreFromSettings(settings)
+	require.NoError(t, err)
+	assert.Nil(t, sessionStore, "the in-memory default is kept")
+
+	settings.Store = config.SessionStoreRedis
+	_, err = NewSessionStoreFromSettings(settings)
+	assert.Error(t, err, "redis needs a URL")
+
+	server := miniredis.RunT(t)
+	settings.RedisURL = "redis://" + server.Addr() + "/0"
+	sessionStore, err = NewSessionStoreFromSettings(settings)
+	require.NoError(t, err)
+	require.IsType(t, &RedisSessionStore{}, sessionStore)
+	require.NoError(t, sessionStore.Set(context.Background(), storedSession(uuid.New(), 0)))
+	assert.Len(t, server.Keys(), 2)
+	assert.NoError(t, sessionStore.(*RedisSessionStore).client.Close())
+}
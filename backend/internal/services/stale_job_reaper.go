@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/metrics"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+)
+
+// staleJobReapBatchSize bounds the jobs requeued by one sweep
+const staleJobReapBatchSize = 100
+
+// StaleJobReaper requeues jobs left in processing by workers that died mid-job. Workers record a
+// heartbeat on the job they process; a job without one for the processing timeout is set back to
+// retry, where any worker can pick it up, and an audit entry names the worker that abandoned it.
+type StaleJobReaper struct {
+	jobStore      store.CampaignJobStore
+	auditLogStore store.AuditLogStore
+	interval      time.Duration
+	staleAfter    time.Duration
+	now           func() time.Time
+}
+
+// NewStaleJobReaper returns nil when worker.staleJobReapIntervalSeconds is negative
+func NewStaleJobReaper(appCfg *config.AppConfig, jobStore store.CampaignJobStore, auditLogStore store.AuditLogStore) *StaleJobReaper {
+	cfg := config.WorkerConfig{}
+	if appCfg != nil {
+		cfg = appCfg.Worker
+	}
+	if cfg.StaleJobReapIntervalSeconds < 0 {
+		return nil
+	}
+	reaper := &StaleJobReaper{
+		jobStore:      jobStore,
+		auditLogStore: auditLogStore,
+		interval:      time.Duration(cfg.StaleJobReapIntervalSeconds) * time.Second,
+		staleAfter:    time.Duration(cfg.JobProcessingTimeoutMinutes) * time.Minute,
+		now:           time.Now,
+	}
+	if reaper.interval <= 0 {
+		reaper.interval = config.DefaultStaleJobReapSeconds * time.Second
+	}
+	if reaper.staleAfter <= 0 {
+		reaper.staleAfter = config.DefaultJobProcessingTimeoutMinutes * time.Minute
+	}
+	return reaper
+}
+
+// Start reaps stale jobs every interval until ctx is cancelled
+func (r *StaleJobReaper) Start(ctx context.Context) {
+	if r == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.Reap(ctx)
+			}
+		}
+	}()
+}
+
+// Reap sets processing jobs without a recent heartbeat back to retry and returns them
+func (r *StaleJobReaper) Reap(ctx context.Context) []*models.CampaignJob {
+	if r == nil {
+		return nil
+	}
+	staleBefore := r.now().UTC().Add(-r.staleAfter)
+	jobs, err := r.jobStore.ReapStaleJobs(ctx, staleBefore, staleJobReapBatchSize)
+	if err != nil {
+		log.Printf("StaleJobReaper: Failed to reap stale jobs: %v", err)
+		return nil
+	}
+	for _, job := range jobs {
+		log.Printf("StaleJobReaper: Requeued job %s of campaign %s, abandoned by worker %s", job.ID, job.CampaignID, job.ProcessingServerID.String)
+		metrics.RecordJobTransition(job.JobType, job.Status)
+		r.audit(ctx, job)
+	}
+	return jobs
+}
+
+func (r *StaleJobReaper) audit(ctx context.Context, job *models.CampaignJob) {
+	if r.auditLogStore == nil {
+		return
+	}
+	details := map[string]interface{}{
+		"campaignId": job.CampaignID,
+		"jobType":    job.JobType,
+		"workerId":   job.ProcessingServerID.String,
+		"attempts":   job.Attempts,
+	}
+	if job.LastHeartbeatAt.Valid {
+		details["lastHeartbeatAt"] = job.LastHeartbeatAt.Time
+	}
+	encoded, _ := json.Marshal(details)
+	auditLog := &models.AuditLog{
+		Timestamp:  r.now().UTC(),
+		Action:     "Reap Campaign Job",
+		EntityType: sql.NullString{String: "CampaignJob", Valid: true},
+		EntityID:   uuid.NullUUID{UUID: job.ID, Valid: true},
+		Details:    models.JSONRawMessagePtr(encoded),
+	}
+	if err := r.auditLogStore.CreateAuditLog(ctx, nil, auditLog); err != nil {
+		log.Printf("StaleJobReaper: Failed to write audit log for job %s: %v", job.ID, err)
+	}
+}
+
+// jobHeartbeat records a worker's heartbeats on the job it processes
+type jobHeartbeat struct {
+	done chan struct{}
+	wg   sync.WaitGroup
+	lost atomic.Bool // The job was reaped or claimed by another worker
+}
+
+// startJobHeartbeat records a heartbeat on job every interval until stop is called. When the job
+// is no longer processing by workerName, cancel is called so the worker gives the job up.
+func (s *campaignWorkerServiceImpl) startJobHeartbeat(ctx context.Context, cancel context.CancelFunc, job *models.CampaignJob, workerName string, interval time.Duration) *jobHeartbeat {
+	h := &jobHeartbeat{done: make(chan struct{})}
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-h.done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				err := s.jobStore.TouchJobHeartbeat(ctx, job.ID, workerName)
+				if errors.Is(err, store.ErrNotFound) {
+					log.Printf("Worker [%s]: Job %s was requeued while it ran; giving it up", workerName, job.ID)
+					h.lost.Store(true)
+					cancel()
+					return
+				}
+				if err != nil {
+					log.Printf("Worker [%s]: Failed to record heartbeat of job %s: %v", workerName, job.ID, err)
+				}
+			}
+		}
+	}()
+	return h
+}
+
+func (h *jobHeartbeat) stop() {
+	close(h.done)
+	h.wg.Wait()
+}
+
+// jobHeartbeatInterval is how often a job's heartbeat is recorded: a third of the job timeout at
+// most, so a live job is never mistaken for a stale one
+func (s *campaignWorkerServiceImpl) jobHeartbeatInterval(jobTimeout time.Duration) time.Duration {
+	interval := s.heartbeatInterval
+	if interval <= 0 {
+		interval = workerJobHeartbeatDefault
+	}
+	if limit := jobTimeout / 3; interval > limit {
+		interval = limit
+	}
+	return interval
+}
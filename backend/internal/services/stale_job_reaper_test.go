@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingDNSService runs each DNS validation batch until its context is cancelled or release is closed
+type blockingDNSService struct {
+	DNSCampaignService
+	release chan struct{}
+}
+
+func (s *blockingDNSService) ProcessDNSValidationCampaignBatch(ctx context.Context, campaignID uuid.UUID) (bool, int, error) {
+	select {
+	case <-ctx.Done():
+		return false, 0, ctx.Err()
+	case <-s.release:
+		return true, 1, nil
+	}
+}
+
+func processingJob(workerID string, heartbeat time.Time) *models.CampaignJob {
+	return &models.CampaignJob{
+		ID:                 uuid.New(),
+		CampaignID:         uuid.New(),
+		JobType:            models.CampaignTypeDNSValidation,
+		Status:             models.JobStatusProcessing,
+		Attempts:           1,
+		MaxAttempts:        3,
+		ProcessingServerID: sql.NullString{String: workerID, Valid: true},
+		LastHeartbeatAt:    sql.NullTime{Time: heartbeat, Valid: true},
+	}
+}
+
+func TestStaleJobReaper_RequeuesJobsOfCrashedWorkers(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	jobStore := &memoryCampaignJobStore{}
+	// One worker died 20 minutes into its job; another is still sending heartbeats
+	crashed := processingJob("worker-crashed", now.Add(-20*time.Minute))
+	alive := processingJob("worker-alive", now.Add(-time.Minute))
+	require.NoError(t, jobStore.CreateJob(ctx, nil, crashed))
+	require.NoError(t, jobStore.CreateJob(ctx, nil, alive))
+	audits := &memoryAuditLogStore{}
+
+	reaper := NewStaleJobReaper(&config.AppConfig{Worker: config.WorkerConfig{JobProcessingTimeoutMinutes: 15}}, jobStore, audits)
+	reaper.now = func() time.Time { return now }
+	reaped := reaper.Reap(ctx)
+	require.Len(t, reaped, 1)
+	assert.Equal(t, crashed.ID, reaped[0].ID)
+
+	stored, err := jobStore.GetJobByID(ctx, crashed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusRetry, stored.Status)
+	assert.Equal(t, store.StaleJobError, stored.LastError.String)
+	stored, err = jobStore.GetJobByID(ctx, alive.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusProcessing, stored.Status)
+
+	require.Len(t, audits.entries, 1)
+	entry := audits.entries[0]
+	assert.Equal(t, "Reap Campaign Job", entry.Action)
+	assert.Equal(t, crashed.ID, entry.EntityID.UUID)
+	var details map[string]interface{}
+	require.NoError(t, json.Unmarshal(*entry.Details, &details))
+	assert.Equal(t, "worker-crashed", details["workerId"])
+	assert.Equal(t, crashed.CampaignID.String(), details["campaignId"])
+
+	assert.Empty(t, reaper.Reap(ctx), "a requeued job is not reaped again")
+	assert.Nil(t, NewStaleJobReaper(&config.AppConfig{Worker: config.WorkerConfig{StaleJobReapIntervalSeconds: -1}}, jobStore, audits))
+}
+
+func TestProcessJob_RecordsHeartbeats(t *testing.T) {
+	ctx := context.Background()
+	jobStore := &memoryCampaignJobStore{}
+	dns := &blockingDNSService{release: make(chan struct{})}
+	worker := NewCampaignWorkerService(jobStore, nil, dns, nil, nil, "worker-test", &config.AppConfig{}).(*campaignWorkerServiceImpl)
+	worker.heartbeatInterval = 5 * time.Millisecond
+
+	job := processingJob("worker-test-0", time.Now().UTC().Add(-time.Hour))
+	require.NoError(t, jobStore.CreateJob(ctx, nil, job))
+	heartbeats := make(chan time.Time, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		stored, err := jobStore.GetJobByID(ctx, job.ID)
+		if assert.NoError(t, err) {
+			heartbeats <- stored.LastHeartbeatAt.Time
+		}
+		close(dns.release)
+	}()
+	worker.processJob(ctx, job, "worker-test-0")
+
+	assert.WithinDuration(t, time.Now(), <-heartbeats, time.Second, "the heartbeat is kept up while the batch runs")
+	stored, err := jobStore.GetJobByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusCompleted, stored.Status)
+}
+
+func TestProcessJob_GivesUpRequeuedJob(t *testing.T) {
+	ctx := context.Background()
+	jobStore := &memoryCampaignJobStore{}
+	worker := NewCampaignWorkerService(jobStore, nil, &blockingDNSService{}, nil, nil, "worker-test", &config.AppConfig{}).(*campaignWorkerServiceImpl)
+	worker.heartbeatInterval = 5 * time.Millisecond
+
+	// The reaper requeued the job after this worker stalled; another worker may already hold it
+	job := processingJob("worker-test-0", time.Now().UTC())
+	requeued := *job
+	requeued.Status = models.JobStatusRetry
+	requeued.LastError = sql.NullString{String: store.StaleJobError, Valid: true}
+	require.NoError(t, jobStore.CreateJob(ctx, nil, &requeued))
+
+	done := make(chan struct{})
+	go func() {
+		worker.processJob(ctx, job, "worker-test-0")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("worker kept processing a requeued job")
+	}
+
+	stored, err := jobStore.GetJobByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusRetry, stored.Status, "the requeued job is left as the reaper set it")
+	assert.Equal(t, store.StaleJobError, stored.LastError.String)
+	assert.Equal(t, 1, stored.Attempts)
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/store"
+)
+
+// Account lockout kept in auth.users: this many failed logins in a row lock an account for
+// AccountLockoutDuration
+const (
+	AccountLockoutThreshold = 5
+	AccountLockoutDuration  = 30 * time.Minute
+)
+
+// RateLimitActionUnknownAccountLockout is the auth.rate_limits action the failed logins at emails no
+// account has are counted under
+const RateLimitActionUnknownAccountLockout = "unknown_account_lockout"
+
+// UnknownAccountLockouts locks emails no account has out after as many failed logins, and for as
+// long, as an account is locked out, so that telling a client when a lockout ends does not reveal
+// which emails are registered. Like an account's count, an email's count does not expire: once a
+// lockout ends, the next failure locks the email out again.
+type UnknownAccountLockouts struct {
+	store store.RateLimitStore
+	now   func() time.Time
+}
+
+// NewUnknownAccountLockouts creates lockouts kept in rateLimitStore
+func NewUnknownAccountLockouts(rateLimitStore store.RateLimitStore) *UnknownAccountLockouts {
+	return &UnknownAccountLockouts{store: rateLimitStore, now: time.Now}
+}
+
+// LockedUntil returns when the lockout of email ends and true while it is locked out
+func (l *UnknownAccountLockouts) LockedUntil(ctx context.Context, email string) (time.Time, bool, error) {
+	limit, err := l.store.GetRateLimit(ctx, nil, UnknownAccountRateLimitKey(email), RateLimitActionUnknownAccountLockout)
+	if errors.Is(err, store.ErrNotFound) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to check the lockout of an unknown email: %w", err)
+	}
+	if limit.BlockedUntil == nil || !l.now().Before(*limit.BlockedUntil) {
+		return time.Time{}, false, nil
+	}
+	return *limit.BlockedUntil, true, nil
+}
+
+// RecordFailure counts a failed login at email, locking it out at AccountLockoutThreshold failures.
+// Attempts during a lockout are not recorded, so they do not extend it.
+func (l *UnknownAccountLockouts) RecordFailure(ctx context.Context, email string) error {
+	now := l.now()
+	// The zero window start keeps every earlier failure in the count
+	_, err := l.store.RecordRateLimitAttempt(ctx, nil, UnknownAccountRateLimitKey(email), RateLimitActionUnknownAccountLockout,
+		now, time.Time{}, AccountLockoutThreshold, now.Add(AccountLockoutDuration))
+	if err != nil {
+		return fmt.Errorf("failed to count a failed login at an unknown email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnknownAccountLockouts_LockLikeAnAccount(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	lockouts := NewUnknownAccountLockouts(newMemoryRateLimitStore())
+	lockouts.now = func() time.Time { return now }
+
+	for i := 0; i < AccountLockoutThreshold; i++ {
+		_, locked, err := lockouts.LockedUntil(ctx, "Nobody@example.com")
+		require.NoError(t, err)
+		require.False(t, locked, "failure %d", i+1)
+		require.NoError(t, lockouts.RecordFailure(ctx, "nobody@example.com"))
+	}
+	lockedUntil, locked, err := lockouts.LockedUntil(ctx, " NOBODY@example.com")
+	require.NoError(t, err)
+	require.True(t, locked, "emails are compared like rate limit keys")
+	assert.Equal(t, now.Add(AccountLockoutDuration), lockedUntil)
+
+	// Like an account's count, the email's count does not expire: the next failure after the
+	// lockout locks it out again
+	now = lockedUntil
+	_, locked, err = lockouts.LockedUntil(ctx, "nobody@example.com")
+	require.NoError(t, err)
+	require.False(t, locked)
+	require.NoError(t, lockouts.RecordFailure(ctx, "nobody@example.com"))
+	lockedUntil, locked, err = lockouts.LockedUntil(ctx, "nobody@example.com")
+	require.NoError(t, err)
+	assert.True(t, locked)
+	assert.Equal(t, now.Add(AccountLockoutDuration), lockedUntil)
+}
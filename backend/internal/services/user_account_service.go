@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// UserAccountActor is the administrator changing an account, and where they made the change from
+type UserAccountActor struct {
+	UserID    uuid.UUID
+	IPAddress string
+	UserAgent string
+}
+
+// userAccountAuditDetails are the details of an account change in auth.auth_audit_log
+type userAccountAuditDetails struct {
+	ChangedBy           *uuid.UUID `json:"changedBy,omitempty"`
+	WasLocked           *bool      `json:"wasLocked,omitempty"`
+	FailedLoginAttempts *int       `json:"failedLoginAttempts,omitempty"`
+	RateLimitsCleared   *int64     `json:"rateLimitsCleared,omitempty"`
+}
+
+// UserAccountService lets support staff list, unlock, disable and reactivate user accounts. Each
+// change is written to auth.auth_audit_log in the same transaction.
+type UserAccountService struct {
+	db             *sqlx.DB
+	accountStore   store.UserAccountStore
+	sessionService *SessionService
+	now            func() time.Time
+}
+
+// NewUserAccountService creates a UserAccountService. Disabling an account ends its sessions through
+// sessionService.
+func NewUserAccountService(db *sqlx.DB, accountStore store.UserAccountStore, sessionService *SessionService) *UserAccountService {
+	return &UserAccountService{
+		db:             db,
+		accountStore:   accountStore,
+		sessionService: sessionService,
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// ListAccounts returns a page of the accounts matching filter and how many match in all
+func (s *UserAccountService) ListAccounts(ctx context.Context, filter store.ListUserAccountsFilter) ([]*models.UserAccount, int64, error) {
+	accounts, err := s.accountStore.ListUserAccounts(ctx, nil, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list user accounts: %w", err)
+	}
+	total, err := s.accountStore.CountUserAccounts(ctx, nil, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count user accounts: %w", err)
+	}
+	return accounts, total, nil
+}
+
+// GetSecurityStatus returns the account's lock state and last login, or store.ErrNotFound
+func (s *UserAccountService) GetSecurityStatus(ctx context.Context, userID uuid.UUID) (*models.UserSecurityStatus, error) {
+	return s.accountStore.GetUserSecurityStatus(ctx, nil, userID)
+}
+
+// Unlock clears the account's lock, its failed login count and the login rate limits held against
+// it, and records an account_unlocked event. Unlocking an account that is not locked still resets
+// its count and rate limits.
+func (s *UserAccountService) Unlock(ctx context.Context, userID uuid.UUID, actor UserAccountActor) (*models.UserAccountUnlock, error) {
+	var result *models.UserAccountUnlock
+	err := s.inTx(ctx, func(exec store.Querier) error {
+		var err error
+		result, err = s.accountStore.UnlockUserAccount(ctx, exec, userID, s.now())
+		if err != nil {
+			return err
+		}
+		return s.audit(ctx, exec, userID, "account_unlocked", actor, userAccountAuditDetails{
+			WasLocked:           &result.WasLocked,
+			FailedLoginAttempts: &result.FailedLoginAttempts,
+			RateLimitsCleared:   &result.RateLimitsCleared,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Disable deactivates the account, records an account_disabled event and ends every session of the
+// user, so they are signed out at once rather than when their sessions expire. Sessions are ended
+// even when the account was already disabled.
+func (s *UserAccountService) Disable(ctx context.Context, userID uuid.UUID, actor UserAccountActor) (*models.UserSecurityStatus, error) {
+	if err := s.setActive(ctx, userID, false, "account_disabled", actor); err != nil {
+		return nil, err
+	}
+	if err := s.sessionService.InvalidateAllUserSessions(userID); err != nil {
+		return nil, fmt.Errorf("account disabled but its sessions were not ended: %w", err)
+	}
+	return s.accountStore.GetUserSecurityStatus(ctx, nil, userID)
+}
+
+// Enable reactivates a disabled account and records an account_enabled event. A locked account stays
+// locked.
+func (s *UserAccountService) Enable(ctx context.Context, userID uuid.UUID, actor UserAccountActor) (*models.UserSecurityStatus, error) {
+	if err := s.setActive(ctx, userID, true, "account_enabled", actor); err != nil {
+		return nil, err
+	}
+	return s.accountStore.GetUserSecurityStatus(ctx, nil, userID)
+}
+
+// setActive changes whether the account is active, recording eventType only when that changed it
+func (s *UserAccountService) setActive(ctx context.Context, userID uuid.UUID, active bool, eventType string, actor UserAccountActor) error {
+	return s.inTx(ctx, func(exec store.Querier) error {
+		changed, err := s.accountStore.SetUserActive(ctx, exec, userID, active, s.now())
+		if err != nil || !changed {
+			return err
+		}
+		return s.audit(ctx, exec, userID, eventType, actor, userAccountAuditDetails{})
+	})
+}
+
+// inTx runs fn in a transaction, or directly on the store when the service has no database
+func (s *UserAccountService) inTx(ctx context.Context, fn func(exec store.Querier) error) error {
+	if s.db == nil {
+		return fn(nil)
+	}
+	tx, err := s.accountStore.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin account transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit account change: %w", err)
+	}
+	return nil
+}
+
+func (s *UserAccountService) audit(ctx context.Context, exec store.Querier, userID uuid.UUID, eventType string, actor UserAccountActor, details userAccountAuditDetails) error {
+	if actor.UserID != uuid.Nil {
+		details.ChangedBy = &actor.UserID
+	}
+	encoded, err := models.NewAuditDetails(details)
+	if err != nil {
+		return err
+	}
+	detailsText := string(*encoded)
+	entry := &models.AuthAuditLog{
+		UserID:      &userID,
+		EventType:   eventType,
+		EventStatus: "success",
+		Details:     &detailsText,
+		RiskScore:   1,
+		CreatedAt:   s.now(),
+	}
+	if actor.IPAddress != "" {
+		entry.IPAddress = &actor.IPAddress
+	}
+	if actor.UserAgent != "" {
+		entry.UserAgent = &actor.UserAgent
+	}
+	if err := s.accountStore.CreateAuthAuditLog(ctx, exec, entry); err != nil {
+		return fmt.Errorf("failed to record %s event: %w", eventType, err)
+	}
+	return nil
+}
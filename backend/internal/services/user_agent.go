@@ -0,0 +1,24 @@
+package services
+
+import "strings"
+
+// ExtractBrowserInfo names the browser family of a User-Agent, "unknown" when it is not recognised.
+// Versions are ignored so that browser updates do not look like a different client.
+func ExtractBrowserInfo(userAgent string) string {
+	userAgent = strings.ToLower(userAgent)
+
+	// Edge and Opera also claim to be Chrome, and every Chromium browser claims to be Safari
+	switch {
+	case strings.Contains(userAgent, "edg/") || strings.Contains(userAgent, "edge"):
+		return "edge"
+	case strings.Contains(userAgent, "opr") || strings.Contains(userAgent, "opera"):
+		return "opera"
+	case strings.Contains(userAgent, "chrome") || strings.Contains(userAgent, "crios"):
+		return "chrome"
+	case strings.Contains(userAgent, "firefox") || strings.Contains(userAgent, "fxios"):
+		return "firefox"
+	case strings.Contains(userAgent, "safari"):
+		return "safari"
+	}
+	return "unknown"
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Sections of a user data export, in the order they are written
+const (
+	UserDataSectionProfile     = "profile"
+	UserDataSectionAuditEvents = "auditEvents"
+	UserDataSectionSessions    = "sessions"
+	UserDataSectionCampaigns   = "campaigns"
+)
+
+// AllUserDataSections lists every section of a user data export
+var AllUserDataSections = []string{
+	UserDataSectionProfile,
+	UserDataSectionAuditEvents,
+	UserDataSectionSessions,
+	UserDataSectionCampaigns,
+}
+
+// userDataExportPageSize bounds how many rows of a section are held in memory while exporting
+const userDataExportPageSize = 500
+
+// ParseUserDataSections parses a comma-separated section list. An empty list selects every section.
+func ParseUserDataSections(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return AllUserDataSections, nil
+	}
+	requested := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		name := strings.TrimSpace(part)
+		if name == "" {
+			continue
+		}
+		known := false
+		for _, section := range AllUserDataSections {
+			if strings.EqualFold(name, section) {
+				requested[section] = true
+				known = true
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown export section %q", name)
+		}
+	}
+	sections := make([]string, 0, len(requested))
+	for _, section := range AllUserDataSections {
+		if requested[section] {
+			sections = append(sections, section)
+		}
+	}
+	return sections, nil
+}
+
+// UserDataService answers data-subject requests: exporting everything held about a user and erasing
+// their personal data.
+type UserDataService struct {
+	db             *sqlx.DB
+	userDataStore  store.UserDataStore
+	auditLogStore  store.AuditLogStore
+	campaignStore  store.CampaignStore
+	sessionService *SessionService
+	pageSize       int
+	now            func() time.Time
+}
+
+// NewUserDataService creates a UserDataService. Erasing a user's data ends their sessions through
+// sessionService.
+func NewUserDataService(db *sqlx.DB, userDataStore store.UserDataStore, auditLogStore store.AuditLogStore, campaignStore store.CampaignStore, sessionService *SessionService) *UserDataService {
+	return &UserDataService{
+		db:             db,
+		userDataStore:  userDataStore,
+		auditLogStore:  auditLogStore,
+		campaignStore:  campaignStore,
+		sessionService: sessionService,
+		pageSize:       userDataExportPageSize,
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (s *UserDataService) querier() store.Querier {
+	if s.db == nil {
+		return nil
+	}
+	return s.db
+}
+
+// Export writes a JSON bundle of the user's data to w, one page at a time so large histories are
+// streamed rather than built in memory. It returns store.ErrNotFound before writing anything when
+// the user does not exist.
+func (s *UserDataService) Export(ctx context.Context, userID uuid.UUID, sections []string, w io.Writer) error {
+	profile, err := s.userDataStore.GetUserDataProfile(ctx, s.querier(), userID)
+	if err != nil {
+		return err
+	}
+	exportedAt := s.now()
+
+	out := &userDataWriter{w: w}
+	out.raw(`{"userId":`)
+	out.value(userID)
+	out.raw(`,"exportedAt":`)
+	out.value(exportedAt)
+	out.raw(`,"sections":`)
+	out.value(sections)
+	for _, section := range sections {
+		out.raw(`,"` + section + `":`)
+		switch section {
+		case UserDataSectionProfile:
+			out.value(profile)
+		case UserDataSectionAuditEvents:
+			s.writeAuditEvents(ctx, out, userID, exportedAt)
+		case UserDataSectionSessions:
+			s.writeSessions(ctx, out, userID)
+		case UserDataSectionCampaigns:
+			s.writeCampaigns(ctx, out, userID)
+		}
+	}
+	out.raw("}\n")
+	return out.err
+}
+
+// writeAuditEvents exports the entries the user performed followed by the entries about the user's
+// account that others performed, with the other user's identity redacted. Entries written after the
+// export started are left out so paging stays stable.
+func (s *UserDataService) writeAuditEvents(ctx context.Context, out *userDataWriter, userID uuid.UUID, until time.Time) {
+	out.raw("[")
+	first := true
+	filters := []store.ListAuditLogsFilter{
+		{UserID: userID.String(), EndDate: until},
+		{EntityType: "User", EntityID: uuid.NullUUID{UUID: userID, Valid: true}, EndDate: until},
+	}
+	for i, filter := range filters {
+		filter.Limit = s.pageSize
+		for out.err == nil {
+			entries, err := s.auditLogStore.ListAuditLogs(ctx, s.querier(), filter)
+			if err != nil {
+				out.fail(fmt.Errorf("list audit logs: %w", err))
+				break
+			}
+			for _, entry := range entries {
+				if i > 0 && entry.UserID.Valid && entry.UserID.UUID == userID {
+					continue // already exported with the user's own entries
+				}
+				out.element(&first, userDataAuditEvent(entry, userID))
+			}
+			if len(entries) < filter.Limit {
+				break
+			}
+			filter.Offset += filter.Limit
+		}
+	}
+	out.raw("]")
+}
+
+func (s *UserDataService) writeSessions(ctx context.Context, out *userDataWriter, userID uuid.UUID) {
+	out.raw("[")
+	first := true
+	for offset := 0; out.err == nil; offset += s.pageSize {
+		sessions, err := s.userDataStore.ListUserDataSessions(ctx, s.querier(), userID, s.pageSize, offset)
+		if err != nil {
+			out.fail(fmt.Errorf("list sessions: %w", err))
+			break
+		}
+		for _, session := range sessions {
+			out.element(&first, session)
+		}
+		if len(sessions) < s.pageSize {
+			break
+		}
+	}
+	out.raw("]")
+}
+
+func (s *UserDataService) writeCampaigns(ctx context.Context, out *userDataWriter, userID uuid.UUID) {
+	out.raw("[")
+	first := true
+	filter := store.ListCampaignsFilter{UserID: userID.String(), SortBy: "created_at", SortOrder: "ASC", Limit: s.pageSize}
+	for out.err == nil {
+		campaigns, err := s.campaignStore.ListCampaigns(ctx, s.querier(), filter)
+		if err != nil {
+			out.fail(fmt.Errorf("list campaigns: %w", err))
+			break
+		}
+		for _, campaign := range campaigns {
+			out.element(&first, campaign)
+		}
+		if len(campaigns) < filter.Limit {
+			break
+		}
+		filter.Offset += filter.Limit
+	}
+	out.raw("]")
+}
+
+// userDataAuditEvent converts an audit entry for export. Entries performed by another user drop that
+// user's ID, address and user agent; entries the subject performed on another user's account drop
+// that account's ID and details.
+func userDataAuditEvent(entry *models.AuditLog, subjectID uuid.UUID) models.UserDataAuditEvent {
+	bySubject := entry.UserID.Valid && entry.UserID.UUID == subjectID
+	event := models.UserDataAuditEvent{
+		ID:                 entry.ID,
+		Timestamp:          entry.Timestamp,
+		Action:             entry.Action,
+		EntityType:         entry.EntityType.String,
+		Details:            entry.Details,
+		PerformedBySubject: bySubject,
+	}
+	if entry.EntityID.Valid {
+		entityID := entry.EntityID.UUID
+		event.EntityID = &entityID
+	}
+	if bySubject {
+		event.ClientIP = entry.ClientIP.String
+		event.UserAgent = entry.UserAgent.String
+	} else if entry.UserID.Valid {
+		event.Redacted = true
+	}
+	if event.EntityType == "User" && entry.EntityID.Valid && entry.EntityID.UUID != subjectID {
+		event.EntityID = nil
+		event.Details = nil
+		event.Redacted = true
+	}
+	return event
+}
+
+// Erase anonymizes the user's account and scrubs their personal data in one transaction, then ends
+// the sessions the session service still holds for the user
+func (s *UserDataService) Erase(ctx context.Context, userID uuid.UUID) (*models.UserDataErasure, error) {
+	result, err := s.erase(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessionService.InvalidateAllUserSessions(userID); err != nil {
+		return nil, fmt.Errorf("user data erased but its sessions were not ended: %w", err)
+	}
+	return result, nil
+}
+
+func (s *UserDataService) erase(ctx context.Context, userID uuid.UUID) (*models.UserDataErasure, error) {
+	if s.db == nil {
+		return s.userDataStore.EraseUserData(ctx, nil, userID, s.now())
+	}
+	tx, err := s.userDataStore.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin erasure transaction: %w", err)
+	}
+	result, err := s.userDataStore.EraseUserData(ctx, tx, userID, s.now())
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit erasure: %w", err)
+	}
+	return result, nil
+}
+
+// userDataWriter writes the export and keeps the first error, after which all writes are dropped
+type userDataWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (o *userDataWriter) fail(err error) {
+	if o.err == nil {
+		o.err = err
+	}
+}
+
+func (o *userDataWriter) raw(s string) {
+	if o.err != nil {
+		return
+	}
+	if _, err := io.WriteString(o.w, s); err != nil {
+		o.fail(err)
+	}
+}
+
+func (o *userDataWriter) value(v interface{}) {
+	if o.err != nil {
+		return
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		o.fail(err)
+		return
+	}
+	if _, err := o.w.Write(encoded); err != nil {
+		o.fail(err)
+	}
+}
+
+// element writes v as the next element of an array
+func (o *userDataWriter) element(first *bool, v interface{}) {
+	if !*first {
+		o.raw(",")
+	}
+	*first = false
+	o.value(v)
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryUserDataStore serves one user's profile and sessions and records erasures
+type memoryUserDataStore struct {
+	store.UserDataStore
+	profile  *models.UserDataProfile
+	sessions []*models.UserDataSession
+	erased   []uuid.UUID
+}
+
+func (m *memoryUserDataStore) GetUserDataProfile(ctx context.Context, exec store.Querier, userID uuid.UUID) (*models.UserDataProfile, error) {
+	if m.profile == nil || m.profile.ID != userID {
+		return nil, store.ErrNotFound
+	}
+	return m.profile, nil
+}
+
+func (m *memoryUserDataStore) ListUserDataSessions(ctx context.Context, exec store.Querier, userID uuid.UUID, limit, offset int) ([]*models.UserDataSession, error) {
+	return pageOf(m.sessions, limit, offset), nil
+}
+
+func (m *memoryUserDataStore) EraseUserData(ctx context.Context, exec store.Querier, userID uuid.UUID, erasedAt time.Time) (*models.UserDataErasure, error) {
+	if m.profile == nil || m.profile.ID != userID {
+		return nil, store.ErrNotFound
+	}
+	m.erased = append(m.erased, userID)
+	return &models.UserDataErasure{UserID: userID, SessionsDeleted: int64(len(m.sessions)), ErasedAt: erasedAt}, nil
+}
+
+// memoryAuditLogStore filters audit entries the way the Postgres store does
+type memoryAuditLogStore struct {
+	store.AuditLogStore
+	entries       []*models.AuditLog
+	keysetQueries int
+}
+
+func (m *memoryAuditLogStore) CreateAuditLog(ctx context.Context, exec store.Querier, logEntry *models.AuditLog) error {
+	m.entries = append(m.entries, logEntry)
+	return nil
+}
+
+func (m *memoryAuditLogStore) ListAuditLogs(ctx context.Context, exec store.Querier, filter store.ListAuditLogsFilter) ([]*models.AuditLog, error) {
+	return pageOf(m.matching(filter), filter.Limit, filter.Offset), nil
+}
+
+func (m *memoryAuditLogStore) ListAuditLogsAfter(ctx context.Context, exec store.Querier, filter store.ListAuditLogsFilter, after *store.AuditLogCursor) ([]*models.AuditLog, error) {
+	m.keysetQueries++
+	matched := m.matching(filter)
+	sort.Slice(matched, func(i, j int) bool { return auditLogBefore(matched[i], matched[j].Timestamp, matched[j].ID) })
+	var page []*models.AuditLog
+	for _, entry := range matched {
+		if after != nil && !auditLogBefore(&models.AuditLog{Timestamp: after.Timestamp, ID: after.ID}, entry.Timestamp, entry.ID) {
+			continue
+		}
+		if len(page) == filter.Limit {
+			break
+		}
+		page = append(page, entry)
+	}
+	return page, nil
+}
+
+func (m *memoryAuditLogStore) matching(filter store.ListAuditLogsFilter) []*models.AuditLog {
+	var matched []*models.AuditLog
+	for _, entry := range m.entries {
+		if filter.UserID != "" && (!entry.UserID.Valid || entry.UserID.UUID.String() != filter.UserID) {
+			continue
+		}
+		if filter.EntityType != "" && entry.EntityType.String != filter.EntityType {
+			continue
+		}
+		if filter.EntityID.Valid && entry.EntityID != filter.EntityID {
+			continue
+		}
+		if filter.Action != "" && entry.Action != filter.Action {
+			continue
+		}
+		if !filter.StartDate.IsZero() && entry.Timestamp.Before(filter.StartDate) {
+			continue
+		}
+		if !filter.EndDate.IsZero() && entry.Timestamp.After(filter.EndDate) {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+	return matched
+}
+
+// auditLogBefore orders entries by (timestamp, id) the way Postgres compares the row values
+func auditLogBefore(entry *models.AuditLog, timestamp time.Time, id uuid.UUID) bool {
+	if !entry.Timestamp.Equal(timestamp) {
+		return entry.Timestamp.Before(timestamp)
+	}
+	return bytes.Compare(entry.ID[:], id[:]) < 0
+}
+
+type ownedCampaignStore struct {
+	store.CampaignStore
+	campaigns []*models.Campaign
+}
+
+func (m *ownedCampaignStore) ListCampaigns(ctx context.Context, exec store.Querier, filter store.ListCampaignsFilter) ([]*models.Campaign, error) {
+	var owned []*models.Campaign
+	for _, campaign := range m.campaigns {
+		if campaign.UserID != nil && campaign.UserID.String() == filter.UserID {
+			owned = append(owned, campaign)
+		}
+	}
+	return pageOf(owned, filter.Limit, filter.Offset), nil
+}
+
+func pageOf[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	end := offset + limit
+	if limit <= 0 || end > len(items) {
+		end = len(items)
+	}
+	return items[offset:end]
+}
+
+type userDataFixture struct {
+	service   *UserDataService
+	dataStore *memoryUserDataStore
+	sessions  *SessionService
+	mock      sqlmock.Sqlmock
+	subject   uuid.UUID
+	admin     uuid.UUID
+}
+
+func newUserDataFixture(t *testing.T) *userDataFixture {
+	subject, admin, colleague := uuid.New(), uuid.New(), uuid.New()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	ip, agent := "203.0.113.7", "Mozilla/5.0 (subject)"
+
+	dataStore := &memoryUserDataStore{
+		profile: &models.UserDataProfile{ID: subject, Email: "subject@example.com", FirstName: "Sam", LastName: "Subject", Roles: pq.StringArray{"user"}},
+	}
+	for i := 0; i < 5; i++ {
+		dataStore.sessions = append(dataStore.sessions, &models.UserDataSession{IPAddress: &ip, UserAgent: &agent, ExpiresAt: now})
+	}
+
+	entry := func(actor uuid.UUID, action string, entityID uuid.UUID, details string) *models.AuditLog {
+		return &models.AuditLog{
+			ID:         uuid.New(),
+			Timestamp:  now.Add(-time.Hour),
+			UserID:     uuid.NullUUID{UUID: actor, Valid: true},
+			Action:     action,
+			EntityType: sql.NullString{String: "User", Valid: true},
+			EntityID:   uuid.NullUUID{UUID: entityID, Valid: true},
+			Details:    models.JSONRawMessagePtr(json.RawMessage(details)),
+			ClientIP:   sql.NullString{String: "198.51.100.1", Valid: true},
+			UserAgent:  sql.NullString{String: "admin-browser", Valid: true},
+		}
+	}
+	auditStore := &memoryAuditLogStore{entries: []*models.AuditLog{
+		entry(admin, "Create User", subject, `{"email":"subject@example.com"}`),
+		entry(subject, "Update User", subject, `{"firstName":"Sam"}`),
+		entry(subject, "Create User", colleague, `{"email":"colleague@example.com"}`),
+		entry(admin, "Update User", colleague, `{"email":"colleague@example.com"}`),
+	}}
+
+	campaignStore := &ownedCampaignStore{}
+	for i, owner := range []uuid.UUID{subject, colleague, subject} {
+		owner := owner
+		campaignStore.campaigns = append(campaignStore.campaigns, &models.Campaign{ID: uuid.New(), Name: []string{"mine", "theirs", "mine too"}[i], UserID: &owner})
+	}
+
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+	sessions, err := NewSessionService(sqlx.NewDb(mockDB, "postgres"), DefaultSessionConfig(), nil)
+	require.NoError(t, err)
+
+	service := NewUserDataService(nil, dataStore, auditStore, campaignStore, sessions)
+	service.pageSize = 2
+	service.now = func() time.Time { return now }
+	return &userDataFixture{service: service, dataStore: dataStore, sessions: sessions, mock: mock, subject: subject, admin: admin}
+}
+
+type exportedUserData struct {
+	UserID      uuid.UUID                   `json:"userId"`
+	Sections    []string                    `json:"sections"`
+	Profile     map[string]interface{}      `json:"profile"`
+	AuditEvents []models.UserDataAuditEvent `json:"auditEvents"`
+	Sessions    []map[string]interface{}    `json:"sessions"`
+	Campaigns   []models.Campaign           `json:"campaigns"`
+}
+
+func TestUserDataExport_ContentsAndRedaction(t *testing.T) {
+	f := newUserDataFixture(t)
+	var buf bytes.Buffer
+	require.NoError(t, f.service.Export(context.Background(), f.subject, AllUserDataSections, &buf))
+
+	var export exportedUserData
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &export), "the streamed export is one JSON document")
+	assert.Equal(t, f.subject, export.UserID)
+	assert.Equal(t, "subject@example.com", export.Profile["email"])
+	assert.NotContains(t, export.Profile, "passwordHash")
+
+	require.Len(t, export.Sessions, 5, "sessions are paged through completely")
+	assert.NotContains(t, export.Sessions[0], "id", "session tokens are never exported")
+
+	require.Len(t, export.Campaigns, 2, "only the user's own campaigns are exported")
+	for _, campaign := range export.Campaigns {
+		assert.Equal(t, f.subject, *campaign.UserID)
+	}
+
+	require.Len(t, export.AuditEvents, 3, "the admin's change to another account is not part of the export")
+	byAction := map[string]models.UserDataAuditEvent{}
+	for _, event := range export.AuditEvents {
+		byAction[event.Action+"/"+map[bool]string{true: "self", false: "other"}[event.PerformedBySubject]] = event
+	}
+
+	own := byAction["Update User/self"]
+	assert.False(t, own.Redacted)
+	assert.Equal(t, "198.51.100.1", own.ClientIP)
+	assert.JSONEq(t, `{"firstName":"Sam"}`, string(*own.Details))
+
+	byAdmin := byAction["Create User/other"]
+	assert.True(t, byAdmin.Redacted)
+	assert.Empty(t, byAdmin.ClientIP, "the admin's address is redacted")
+	assert.Empty(t, byAdmin.UserAgent)
+	assert.NotContains(t, buf.String(), f.admin.String(), "the admin's identity does not appear anywhere")
+
+	onColleague := byAction["Create User/self"]
+	assert.True(t, onColleague.Redacted)
+	assert.Nil(t, onColleague.EntityID)
+	assert.Nil(t, onColleague.Details)
+	assert.NotContains(t, buf.String(), "colleague@example.com")
+}
+
+func TestUserDataExport_SectionsAndMissingUser(t *testing.T) {
+	f := newUserDataFixture(t)
+	sections, err := ParseUserDataSections(" Sessions,profile ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{UserDataSectionProfile, UserDataSectionSessions}, sections, "sections keep the export order")
+
+	var buf bytes.Buffer
+	require.NoError(t, f.service.Export(context.Background(), f.subject, sections, &buf))
+	var export map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &export))
+	assert.Contains(t, export, "sessions")
+	assert.NotContains(t, export, "auditEvents")
+	assert.NotContains(t, export, "campaigns")
+
+	_, err = ParseUserDataSections("profile,passwords")
+	assert.Error(t, err)
+
+	buf.Reset()
+	err = f.service.Export(context.Background(), uuid.New(), AllUserDataSections, &buf)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	assert.Zero(t, buf.Len(), "nothing is written for an unknown user")
+}
+
+func TestUserDataErase(t *testing.T) {
+	f := newUserDataFixture(t)
+	cached := newCachedSession(f.subject)
+	f.sessions.cacheSession(cached)
+	f.mock.ExpectExec("UPDATE auth.sessions SET is_active = false").WithArgs(f.subject).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result, err := f.service.Erase(context.Background(), f.subject)
+	require.NoError(t, err)
+	assert.Equal(t, f.subject, result.UserID)
+	assert.Equal(t, int64(5), result.SessionsDeleted)
+	assert.Equal(t, []uuid.UUID{f.subject}, f.dataStore.erased)
+	_, found := f.sessions.cachedSession(cached.ID)
+	assert.False(t, found, "a cached session does not outlive the erasure")
+	assert.NoError(t, f.mock.ExpectationsWereMet())
+
+	_, err = f.service.Erase(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// permissionCacheEntry is one user's cached roles and permissions
+type permissionCacheEntry struct {
+	userID      uuid.UUID
+	permissions []string
+	roles       []string
+	loadedAt    time.Time
+	size        int64
+}
+
+// permissionLoad is a lookup in progress; concurrent lookups for the same user wait for it
+type permissionLoad struct {
+	done        chan struct{}
+	permissions []string
+	roles       []string
+	err         error
+	stale       bool // Set when invalidated during the load; the result is returned but not stored
+}
+
+// PermissionCacheStats reports how well the permission cache is working and what it holds
+type PermissionCacheStats struct {
+	Hits        int64 `json:"hits"`
+	Misses      int64 `json:"misses"`
+	SharedLoads int64 `json:"sharedLoads"` // Lookups that waited for another caller's query instead of running their own
+	Evictions   int64 `json:"evictions"`
+	Entries     int   `json:"entries"`
+	ApproxBytes int64 `json:"approxBytes"`
+	MaxEntries  int   `json:"maxEntries"`
+	TTLSeconds  int64 `json:"ttlSeconds"`
+}
+
+// UserPermissionCache keeps each user's role and permission names for a short TTL so session
+// creation, session reloads and /me share one pair of queries. Concurrent lookups for a user
+// whose entry is missing wait for a single load. Entries beyond maxEntries are evicted least
+// recently used first. Invalidate after changing a user's roles, InvalidateAll after changing
+// what a role grants. A nil cache loads every time.
+type UserPermissionCache struct {
+	ttl        time.Duration
+	maxEntries int // 0 or less means unbounded
+	now        func() time.Time
+
+	mutex    sync.Mutex
+	entries  map[uuid.UUID]*list.Element
+	order    *list.List // Most recently used at the front
+	inflight map[uuid.UUID]*permissionLoad
+	stats    PermissionCacheStats
+}
+
+// NewUserPermissionCache returns nil, disabling caching, when ttl is not positive
+func NewUserPermissionCache(ttl time.Duration, maxEntries int) *UserPermissionCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &UserPermissionCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[uuid.UUID]*list.Element),
+		order:      list.New(),
+		inflight:   make(map[uuid.UUID]*permissionLoad),
+	}
+}
+
+// Get returns the user's permission and role names, calling load only when no fresh entry exists
+// and no other caller is already loading them. Failed loads are not cached.
+func (c *UserPermissionCache) Get(userID uuid.UUID, load func() ([]string, []string, error)) ([]string, []string, error) {
+	if c == nil {
+		return load()
+	}
+
+	c.mutex.Lock()
+	if element, ok := c.entries[userID]; ok {
+		entry := element.Value.(*permissionCacheEntry)
+		if c.now().Sub(entry.loadedAt) < c.ttl {
+			c.order.MoveToFront(element)
+			c.stats.Hits++
+			c.mutex.Unlock()
+			return entry.permissions, entry.roles, nil
+		}
+		c.removeElement(element)
+	}
+	if pending, ok := c.inflight[userID]; ok {
+		c.stats.SharedLoads++
+		c.mutex.Unlock()
+		<-pending.done
+		return pending.permissions, pending.roles, pending.err
+	}
+	pending := &permissionLoad{done: make(chan struct{})}
+	c.inflight[userID] = pending
+	c.stats.Misses++
+	c.mutex.Unlock()
+
+	pending.permissions, pending.roles, pending.err = load()
+
+	c.mutex.Lock()
+	if c.inflight[userID] == pending {
+		delete(c.inflight, userID)
+	}
+	if pending.err == nil && !pending.stale {
+		c.store(userID, pending.permissions, pending.roles)
+	}
+	c.mutex.Unlock()
+	close(pending.done)
+	return pending.permissions, pending.roles, pending.err
+}
+
+// store adds an entry and evicts the least recently used ones beyond maxEntries. Callers hold the mutex.
+func (c *UserPermissionCache) store(userID uuid.UUID, permissions, roles []string) {
+	if element, ok := c.entries[userID]; ok {
+		c.removeElement(element)
+	}
+	entry := &permissionCacheEntry{userID: userID, permissions: permissions, roles: roles, loadedAt: c.now()}
+	entry.size = permissionEntrySize(permissions, roles)
+	c.entries[userID] = c.order.PushFront(entry)
+	c.stats.ApproxBytes += entry.size
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+// removeElement drops an entry. Callers hold the mutex.
+func (c *UserPermissionCache) removeElement(element *list.Element) {
+	entry := c.order.Remove(element).(*permissionCacheEntry)
+	delete(c.entries, entry.userID)
+	c.stats.ApproxBytes -= entry.size
+}
+
+// permissionEntrySize estimates the memory an entry holds: its strings, slice headers and bookkeeping
+func permissionEntrySize(permissions, roles []string) int64 {
+	const overhead = 128 // Entry struct, list element and map slot
+	const stringHeader = 16
+	size := int64(overhead)
+	for _, names := range [][]string{permissions, roles} {
+		for _, name := range names {
+			size += stringHeader + int64(len(name))
+		}
+	}
+	return size
+}
+
+// Invalidate drops a user's entry after the user's roles changed. A load already running is not
+// stored, and later lookups start a new one rather than waiting for it.
+func (c *UserPermissionCache) Invalidate(userID uuid.UUID) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if element, ok := c.entries[userID]; ok {
+		c.removeElement(element)
+	}
+	if pending, ok := c.inflight[userID]; ok {
+		pending.stale = true
+		delete(c.inflight, userID)
+	}
+}
+
+// InvalidateAll drops every entry, after a role's permissions changed
+func (c *UserPermissionCache) InvalidateAll() {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for userID, pending := range c.inflight {
+		pending.stale = true
+		delete(c.inflight, userID)
+	}
+	c.entries = make(map[uuid.UUID]*list.Element)
+	c.order.Init()
+	c.stats.ApproxBytes = 0
+}
+
+// Stats returns the cache's counters and current size
+func (c *UserPermissionCache) Stats() PermissionCacheStats {
+	if c == nil {
+		return PermissionCacheStats{}
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	stats.MaxEntries = c.maxEntries
+	stats.TTLSeconds = int64(c.ttl / time.Second)
+	return stats
+}
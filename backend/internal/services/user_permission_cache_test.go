@@ -0,0 +1,121 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingPermissionLoad returns a loader for fixed names that counts its calls
+func countingPermissionLoad(calls *int32) func() ([]string, []string, error) {
+	return func() ([]string, []string, error) {
+		atomic.AddInt32(calls, 1)
+		return []string{"campaigns:read"}, []string{"user"}, nil
+	}
+}
+
+func TestUserPermissionCache_HitsWithinTTLAndInvalidates(t *testing.T) {
+	now := time.Now()
+	cache := NewUserPermissionCache(30*time.Second, 0)
+	cache.now = func() time.Time { return now }
+	userID := uuid.New()
+	var calls int32
+
+	for i := 0; i < 5; i++ {
+		permissions, roles, err := cache.Get(userID, countingPermissionLoad(&calls))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"campaigns:read"}, permissions)
+		assert.Equal(t, []string{"user"}, roles)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "repeated lookups within the TTL hit the cache")
+	assert.Equal(t, int64(4), cache.Stats().Hits)
+
+	cache.Invalidate(userID)
+	_, _, _ = cache.Get(userID, countingPermissionLoad(&calls))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "a role change forces a fresh load")
+
+	cache.InvalidateAll()
+	_, _, _ = cache.Get(userID, countingPermissionLoad(&calls))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	now = now.Add(31 * time.Second)
+	_, _, _ = cache.Get(userID, countingPermissionLoad(&calls))
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls), "expired entries are loaded again")
+
+	// Failed loads are not cached
+	other := uuid.New()
+	_, _, err := cache.Get(other, func() ([]string, []string, error) { return nil, nil, errors.New("db down") })
+	require.Error(t, err)
+	_, _, err = cache.Get(other, countingPermissionLoad(&calls))
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&calls))
+}
+
+func TestUserPermissionCache_ConcurrentLookupsShareOneLoad(t *testing.T) {
+	cache := NewUserPermissionCache(time.Minute, 0)
+	userID := uuid.New()
+	release := make(chan struct{})
+	var calls int32
+	load := func() ([]string, []string, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return []string{"campaigns:read"}, nil, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			permissions, _, err := cache.Get(userID, load)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"campaigns:read"}, permissions)
+		}()
+	}
+	require.Eventually(t, func() bool { return cache.Stats().SharedLoads == 19 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestUserPermissionCache_InvalidationDuringLoadIsNotCached(t *testing.T) {
+	cache := NewUserPermissionCache(time.Minute, 0)
+	userID := uuid.New()
+	var calls int32
+	_, _, _ = cache.Get(userID, func() ([]string, []string, error) {
+		atomic.AddInt32(&calls, 1)
+		cache.Invalidate(userID) // Roles change while the old ones are being read
+		return []string{"stale"}, nil, nil
+	})
+	permissions, _, _ := cache.Get(userID, countingPermissionLoad(&calls))
+	assert.Equal(t, []string{"campaigns:read"}, permissions)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestUserPermissionCache_BoundsEntriesAndMeasuresMemory(t *testing.T) {
+	cache := NewUserPermissionCache(time.Minute, 2)
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	var calls int32
+	for _, id := range []uuid.UUID{first, second, first, third} {
+		_, _, _ = cache.Get(id, countingPermissionLoad(&calls))
+	}
+	stats := cache.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, int64(1), stats.Evictions)
+	assert.Equal(t, 2*permissionEntrySize([]string{"campaigns:read"}, []string{"user"}), stats.ApproxBytes)
+
+	// second was least recently used and is the one evicted
+	_, _, _ = cache.Get(first, countingPermissionLoad(&calls))
+	_, _, _ = cache.Get(second, countingPermissionLoad(&calls))
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+
+	cache.InvalidateAll()
+	assert.Zero(t, cache.Stats().ApproxBytes)
+	assert.Nil(t, NewUserPermissionCache(0, 10), "a zero TTL disables caching")
+}
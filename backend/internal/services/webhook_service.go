@@ -0,0 +1,218 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+)
+
+// Webhook request headers sent with every delivery
+const (
+	WebhookEventHeader     = "X-DomainFlow-Event"
+	WebhookDeliveryHeader  = "X-DomainFlow-Delivery"
+	WebhookTimestampHeader = "X-DomainFlow-Timestamp"
+	WebhookSignatureHeader = "X-DomainFlow-Signature"
+)
+
+// maxWebhookErrorBodyBytes limits how much of a failed response body is kept in last_error
+const maxWebhookErrorBodyBytes = 512
+
+// WebhookService delivers signed webhook events and records every delivery attempt
+type WebhookService struct {
+	deliveryStore        store.EventDeliveryStore
+	campaignWebhookStore store.CampaignWebhookStore // Secrets of campaign webhooks; optional
+	signingSecret        string
+	client               *http.Client
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(deliveryStore store.EventDeliveryStore, cfg config.WebhookConfig) *WebhookService {
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = time.Duration(config.DefaultWebhookTimeoutSeconds) * time.Second
+	}
+	return &WebhookService{
+		deliveryStore: deliveryStore,
+		signingSecret: cfg.SigningSecret,
+		client:        &http.Client{Timeout: timeout},
+	}
+}
+
+// SetCampaignWebhookStore lets the service sign deliveries to campaign webhooks with the webhook's
+// own secret. Without it such deliveries fail.
+func (s *WebhookService) SetCampaignWebhookStore(campaignWebhookStore store.CampaignWebhookStore) {
+	s.campaignWebhookStore = campaignWebhookStore
+}
+
+// SignWebhookPayload returns the signature header value for a payload sent at the given unix timestamp.
+// Receivers verify it by computing HMAC-SHA256 over "<timestamp>.<body>" with the shared secret.
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatch persists a new event delivery and makes the first delivery attempt.
+// A failed attempt is recorded on the delivery rather than returned as an error.
+func (s *WebhookService) Dispatch(ctx context.Context, eventType, targetURL string, payload interface{}) (*models.EventDelivery, error) {
+	return s.dispatch(ctx, &models.EventDelivery{EventType: eventType, TargetURL: targetURL}, payload)
+}
+
+// DispatchToCampaignWebhook is Dispatch for a webhook registered on a campaign. The delivery is
+// signed with the webhook's secret rather than the server's, on replay as well.
+func (s *WebhookService) DispatchToCampaignWebhook(ctx context.Context, eventType string, webhook *models.CampaignWebhook, payload interface{}) (*models.EventDelivery, error) {
+	return s.dispatch(ctx, &models.EventDelivery{
+		EventType:         eventType,
+		TargetURL:         webhook.URL,
+		CampaignWebhookID: uuid.NullUUID{UUID: webhook.ID, Valid: true},
+	}, payload)
+}
+
+func (s *WebhookService) dispatch(ctx context.Context, delivery *models.EventDelivery, payload interface{}) (*models.EventDelivery, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	delivery.Payload = body
+	delivery.Status = models.EventDeliveryStatusPending
+	if err := s.deliveryStore.CreateEventDelivery(ctx, nil, delivery); err != nil {
+		return nil, fmt.Errorf("failed to persist event delivery: %w", err)
+	}
+
+	if err := s.attemptDelivery(ctx, delivery, false); err != nil {
+		return delivery, err
+	}
+	return delivery, nil
+}
+
+// Replay re-attempts delivery of a previously persisted event
+func (s *WebhookService) Replay(ctx context.Context, deliveryID uuid.UUID) (*models.EventDelivery, error) {
+	delivery, err := s.deliveryStore.GetEventDeliveryByID(ctx, nil, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attemptDelivery(ctx, delivery, true); err != nil {
+		return delivery, err
+	}
+	return delivery, nil
+}
+
+// ListDeliveries returns persisted event deliveries matching the filter
+func (s *WebhookService) ListDeliveries(ctx context.Context, filter store.ListEventDeliveriesFilter) ([]*models.EventDelivery, error) {
+	return s.deliveryStore.ListEventDeliveries(ctx, nil, filter)
+}
+
+// ListAttempts returns the recorded attempts for a delivery
+func (s *WebhookService) ListAttempts(ctx context.Context, deliveryID uuid.UUID) ([]*models.EventDeliveryAttempt, error) {
+	return s.deliveryStore.ListEventDeliveryAttempts(ctx, nil, deliveryID)
+}
+
+// attemptDelivery sends the delivery payload once and records the outcome.
+// Only persistence failures are returned; delivery failures are stored on the delivery.
+func (s *WebhookService) attemptDelivery(ctx context.Context, delivery *models.EventDelivery, isReplay bool) error {
+	startedAt := time.Now().UTC()
+	var statusCode int
+	secret, sendErr := s.signingSecretFor(ctx, delivery)
+	if sendErr == nil {
+		statusCode, sendErr = s.send(ctx, delivery, secret, startedAt)
+	}
+	duration := time.Since(startedAt)
+
+	delivery.Attempts++
+	delivery.LastAttemptedAt = sql.NullTime{Time: startedAt, Valid: true}
+	attempt := &models.EventDeliveryAttempt{
+		DeliveryID:    delivery.ID,
+		AttemptNumber: delivery.Attempts,
+		DurationMs:    duration.Milliseconds(),
+		IsReplay:      isReplay,
+		AttemptedAt:   startedAt,
+	}
+	if statusCode > 0 {
+		delivery.LastStatusCode = sql.NullInt32{Int32: int32(statusCode), Valid: true}
+		attempt.StatusCode = delivery.LastStatusCode
+	}
+
+	if sendErr != nil {
+		log.Printf("WebhookService: delivery %s of event %s to %s failed: %v", delivery.ID, delivery.EventType, delivery.TargetURL, sendErr)
+		delivery.Status = models.EventDeliveryStatusFailed
+		delivery.LastError = sql.NullString{String: sendErr.Error(), Valid: true}
+		attempt.Error = delivery.LastError
+	} else {
+		delivery.Status = models.EventDeliveryStatusDelivered
+		delivery.LastError = sql.NullString{}
+		delivery.DeliveredAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	}
+
+	if err := s.deliveryStore.CreateEventDeliveryAttempt(ctx, nil, attempt); err != nil {
+		return fmt.Errorf("failed to record delivery attempt: %w", err)
+	}
+	if err := s.deliveryStore.UpdateEventDelivery(ctx, nil, delivery); err != nil {
+		return fmt.Errorf("failed to update event delivery: %w", err)
+	}
+	return nil
+}
+
+// signingSecretFor returns the secret a delivery is signed with: its campaign webhook's, or else the server's
+func (s *WebhookService) signingSecretFor(ctx context.Context, delivery *models.EventDelivery) (string, error) {
+	if !delivery.CampaignWebhookID.Valid {
+		return s.signingSecret, nil
+	}
+	if s.campaignWebhookStore == nil {
+		return "", fmt.Errorf("campaign webhooks are not configured")
+	}
+	webhook, err := s.campaignWebhookStore.GetCampaignWebhookByID(ctx, nil, delivery.CampaignWebhookID.UUID)
+	if errors.Is(err, store.ErrNotFound) {
+		return "", fmt.Errorf("campaign webhook %s no longer exists", delivery.CampaignWebhookID.UUID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load campaign webhook: %w", err)
+	}
+	return webhook.Secret, nil
+}
+
+// send posts the payload signed with secret and returns the response status code
+func (s *WebhookService) send(ctx context.Context, delivery *models.EventDelivery, secret string, sentAt time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.TargetURL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook request: %w", err)
+	}
+	timestamp := sentAt.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID.String())
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	if secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(secret, timestamp, delivery.Payload))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookErrorBodyBytes))
+		return resp.StatusCode, fmt.Errorf("receiver responded with status %d: %s", resp.StatusCode, string(snippet))
+	}
+	return resp.StatusCode, nil
+}
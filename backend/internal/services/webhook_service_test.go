@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryEventDeliveryStore is an in-memory store.EventDeliveryStore for tests
+type memoryEventDeliveryStore struct {
+	mu         sync.Mutex
+	deliveries map[uuid.UUID]models.EventDelivery
+	attempts   []*models.EventDeliveryAttempt
+}
+
+func newMemoryEventDeliveryStore() *memoryEventDeliveryStore {
+	return &memoryEventDeliveryStore{deliveries: make(map[uuid.UUID]models.EventDelivery)}
+}
+
+func (m *memoryEventDeliveryStore) CreateEventDelivery(ctx context.Context, exec store.Querier, delivery *models.EventDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	m.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (m *memoryEventDeliveryStore) GetEventDeliveryByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.EventDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delivery, ok := m.deliveries[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return &delivery, nil
+}
+
+func (m *memoryEventDeliveryStore) UpdateEventDelivery(ctx context.Context, exec store.Querier, delivery *models.EventDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.deliveries[delivery.ID]; !ok {
+		return store.ErrNotFound
+	}
+	m.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (m *memoryEventDeliveryStore) ListEventDeliveries(ctx context.Context, exec store.Querier, filter store.ListEventDeliveriesFilter) ([]*models.EventDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := []*models.EventDelivery{}
+	for _, delivery := range m.deliveries {
+		if filter.Status != "" && delivery.Status != filter.Status {
+			continue
+		}
+		d := delivery
+		result = append(result, &d)
+	}
+	return result, nil
+}
+
+func (m *memoryEventDeliveryStore) CreateEventDeliveryAttempt(ctx context.Context, exec store.Querier, attempt *models.EventDeliveryAttempt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.attempts = append(m.attempts, attempt)
+	return nil
+}
+
+func (m *memoryEventDeliveryStore) ListEventDeliveryAttempts(ctx context.Context, exec store.Querier, deliveryID uuid.UUID) ([]*models.EventDeliveryAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := []*models.EventDeliveryAttempt{}
+	for _, attempt := range m.attempts {
+		if attempt.DeliveryID == deliveryID {
+			result = append(result, attempt)
+		}
+	}
+	return result, nil
+}
+
+func TestWebhookService_ReplayFailedDelivery(t *testing.T) {
+	const secret = "test-signing-secret"
+	var healthy atomic.Bool
+	var requests atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		timestamp, err := strconv.ParseInt(r.Header.Get(WebhookTimestampHeader), 10, 64)
+		if assert.NoError(t, err) {
+			assert.Equal(t, SignWebhookPayload(secret, timestamp, body), r.Header.Get(WebhookSignatureHeader))
+		}
+		assert.Equal(t, "campaign.completed", r.Header.Get(WebhookEventHeader))
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	deliveryStore := newMemoryEventDeliveryStore()
+	svc := NewWebhookService(deliveryStore, config.WebhookConfig{SigningSecret: secret, TimeoutSeconds: 5})
+	ctx := context.Background()
+
+	delivery, err := svc.Dispatch(ctx, "campaign.completed", receiver.URL, map[string]string{"campaignId": uuid.NewString()})
+	require.NoError(t, err)
+	assert.Equal(t, models.EventDeliveryStatusFailed, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, int32(http.StatusServiceUnavailable), delivery.LastStatusCode.Int32)
+
+	healthy.Store(true)
+	replayed, err := svc.Replay(ctx, delivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.EventDeliveryStatusDelivered, replayed.Status)
+	assert.Equal(t, 2, replayed.Attempts)
+	assert.False(t, replayed.LastError.Valid)
+	assert.True(t, replayed.DeliveredAt.Valid)
+	assert.Equal(t, int32(2), requests.Load())
+
+	attempts, err := svc.ListAttempts(ctx, delivery.ID)
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+	assert.False(t, attempts[0].IsReplay)
+	assert.True(t, attempts[0].Error.Valid)
+	assert.True(t, attempts[1].IsReplay)
+	assert.Equal(t, 2, attempts[1].AttemptNumber)
+	assert.Equal(t, int32(http.StatusOK), attempts[1].StatusCode.Int32)
+
+	stored, err := deliveryStore.GetEventDeliveryByID(ctx, nil, delivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.EventDeliveryStatusDelivered, stored.Status)
+}
+
+func TestWebhookService_ReplayUnknownDelivery(t *testing.T) {
+	svc := NewWebhookService(newMemoryEventDeliveryStore(), config.WebhookConfig{})
+	_, err := svc.Replay(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+)
+
+// Worker health statuses
+const (
+	WorkerHealthOK      = "ok"
+	WorkerHealthStopped = "stopped" // No worker loop is running
+	WorkerHealthStalled = "stalled" // Jobs are queued but none has completed within the stall window
+)
+
+const workerHealthStallWindowDefault = 30 * time.Minute
+
+// WorkerHealth is a snapshot of the campaign worker subsystem
+type WorkerHealth struct {
+	Healthy             bool                            `json:"healthy"`
+	Status              string                          `json:"status"`
+	Reason              string                          `json:"reason,omitempty"`
+	ActiveWorkers       int                             `json:"activeWorkers"`
+	InFlight            map[models.CampaignTypeEnum]int `json:"inFlight"`
+	QueueEmpty          bool                            `json:"queueEmpty"`
+	LastJobCompletedAt  *time.Time                      `json:"lastJobCompletedAt,omitempty"`
+	ConsecutiveFailures int                             `json:"consecutiveFailures"`
+	PollerAlive         bool                            `json:"pollerAlive"`
+	LastPollAt          *time.Time                      `json:"lastPollAt,omitempty"`
+	StallWindowSeconds  int                             `json:"stallWindowSeconds"`
+	CheckedAt           time.Time                       `json:"checkedAt"`
+}
+
+// workerHealthTracker records what the worker loops are doing so Health can be answered without
+// touching them
+type workerHealthTracker struct {
+	mutex               sync.Mutex
+	startedAt           time.Time
+	activeWorkers       int
+	inFlight            map[models.CampaignTypeEnum]int
+	lastCompletedAt     time.Time
+	consecutiveFailures int
+	lastPollAt          time.Time
+	pollInterval        time.Duration
+}
+
+func newWorkerHealthTracker() *workerHealthTracker {
+	return &workerHealthTracker{inFlight: make(map[models.CampaignTypeEnum]int)}
+}
+
+func (t *workerHealthTracker) workerStarted(now time.Time, pollInterval time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.activeWorkers == 0 {
+		t.startedAt = now
+	}
+	t.activeWorkers++
+	t.pollInterval = pollInterval
+}
+
+func (t *workerHealthTracker) workerStopped() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.activeWorkers--
+}
+
+func (t *workerHealthTracker) polled(now time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.lastPollAt = now
+}
+
+func (t *workerHealthTracker) jobStarted(jobType models.CampaignTypeEnum) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.inFlight[jobType]++
+}
+
+// jobFinished records the outcome of processing one job batch
+func (t *workerHealthTracker) jobFinished(jobType models.CampaignTypeEnum, err error, now time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.inFlight[jobType]--; t.inFlight[jobType] <= 0 {
+		delete(t.inFlight, jobType)
+	}
+	if err != nil {
+		t.consecutiveFailures++
+		return
+	}
+	t.consecutiveFailures = 0
+	t.lastCompletedAt = now
+}
+
+// snapshot evaluates the tracked state. A worker that has not completed a job within stallWindow
+// (counted from when the workers started if none has completed yet) is stalled when jobs are queued.
+func (t *workerHealthTracker) snapshot(now time.Time, stallWindow time.Duration, queueEmpty bool) WorkerHealth {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	health := WorkerHealth{
+		Healthy:             true,
+		Status:              WorkerHealthOK,
+		ActiveWorkers:       t.activeWorkers,
+		InFlight:            make(map[models.CampaignTypeEnum]int, len(t.inFlight)),
+		QueueEmpty:          queueEmpty,
+		ConsecutiveFailures: t.consecutiveFailures,
+		StallWindowSeconds:  int(stallWindow.Seconds()),
+		CheckedAt:           now,
+	}
+	for jobType, count := range t.inFlight {
+		health.InFlight[jobType] = count
+	}
+	if !t.lastCompletedAt.IsZero() {
+		completedAt := t.lastCompletedAt
+		health.LastJobCompletedAt = &completedAt
+	}
+	if !t.lastPollAt.IsZero() {
+		polledAt := t.lastPollAt
+		health.LastPollAt = &polledAt
+		// A loop busy with a job does not poll, so only a loop that is neither polling nor working is dead
+		health.PollerAlive = t.activeWorkers > 0 && (len(t.inFlight) > 0 || now.Sub(polledAt) <= 3*t.pollInterval)
+	}
+
+	if t.activeWorkers <= 0 {
+		health.Healthy, health.Status, health.Reason = false, WorkerHealthStopped, "no worker loop is running"
+		return health
+	}
+	progressSince := t.lastCompletedAt
+	if progressSince.IsZero() {
+		progressSince = t.startedAt
+	}
+	if !queueEmpty && now.Sub(progressSince) > stallWindow {
+		health.Healthy, health.Status = false, WorkerHealthStalled
+		health.Reason = "jobs are queued but none has completed within the stall window"
+	}
+	return health
+}
+
+// Health reports whether the campaign workers are running and making progress
+func (s *campaignWorkerServiceImpl) Health(ctx context.Context) WorkerHealth {
+	stallWindow := time.Duration(s.appConfig.Worker.HealthStallWindowSeconds) * time.Second
+	if stallWindow <= 0 {
+		stallWindow = workerHealthStallWindowDefault
+	}
+	return s.health.snapshot(s.now(), stallWindow, s.jobQueueEmpty(ctx))
+}
+
+// jobQueueEmpty reports whether no job is waiting to be picked up. An unreadable queue counts as
+// non-empty so a broken job store cannot hide a stall.
+func (s *campaignWorkerServiceImpl) jobQueueEmpty(ctx context.Context) bool {
+	if s.jobStore == nil {
+		return true
+	}
+	for _, status := range []models.CampaignJobStatusEnum{models.JobStatusQueued, models.JobStatusRetry} {
+		jobs, err := s.jobStore.ListJobs(ctx, store.ListJobsFilter{Status: status, Limit: 1})
+		if err != nil || len(jobs) > 0 {
+			return false
+		}
+	}
+	return true
+}
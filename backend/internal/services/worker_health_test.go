@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// healthJobStore reports a fixed queue and accepts job updates
+type healthJobStore struct {
+	store.CampaignJobStore
+	mutex  sync.Mutex
+	queued []*models.CampaignJob
+}
+
+func (m *healthJobStore) ListJobs(ctx context.Context, filter store.ListJobsFilter) ([]*models.CampaignJob, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	var jobs []*models.CampaignJob
+	for _, job := range m.queued {
+		if filter.Status == "" || job.Status == filter.Status {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func (m *healthJobStore) CreateJob(ctx context.Context, exec store.Querier, job *models.CampaignJob) error {
+	return nil
+}
+
+func (m *healthJobStore) UpdateJob(ctx context.Context, exec store.Querier, job *models.CampaignJob) error {
+	return nil
+}
+
+// gatedGenerationService blocks each batch until release is closed, like a hung worker
+type gatedGenerationService struct {
+	DomainGenerationService
+	entered chan struct{}
+	release chan struct{}
+	err     error
+}
+
+func (m *gatedGenerationService) ProcessGenerationCampaignBatch(ctx context.Context, campaignID uuid.UUID) (bool, int, error) {
+	if m.release != nil {
+		m.entered <- struct{}{}
+		<-m.release
+	}
+	return false, 1, m.err
+}
+
+type workerHealthFixture struct {
+	worker   *campaignWorkerServiceImpl
+	jobStore *healthJobStore
+	gen      *gatedGenerationService
+	clock    time.Time
+}
+
+func newWorkerHealthFixture() *workerHealthFixture {
+	f := &workerHealthFixture{
+		jobStore: &healthJobStore{queued: []*models.CampaignJob{{ID: uuid.New(), Status: models.JobStatusQueued}}},
+		gen:      &gatedGenerationService{},
+		clock:    time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+	}
+	appConfig := &config.AppConfig{Worker: config.WorkerConfig{HealthStallWindowSeconds: 600}}
+	f.worker = NewCampaignWorkerService(f.jobStore, f.gen, nil, nil, nil, "health-test", appConfig).(*campaignWorkerServiceImpl)
+	f.worker.now = func() time.Time { return f.clock }
+	f.worker.health.workerStarted(f.clock, 5*time.Second)
+	f.worker.health.polled(f.clock)
+	return f
+}
+
+// runJob processes one domain generation job the way a worker loop does
+func (f *workerHealthFixture) runJob() {
+	job := &models.CampaignJob{ID: uuid.New(), CampaignID: uuid.New(), JobType: models.CampaignTypeDomainGeneration, MaxAttempts: 3}
+	f.worker.health.jobStarted(job.JobType)
+	f.worker.processJob(context.Background(), job, "health-test-0")
+}
+
+func TestWorkerHealth_StalledWorkerIsUnhealthy(t *testing.T) {
+	f := newWorkerHealthFixture()
+	ctx := context.Background()
+
+	f.clock = f.clock.Add(time.Minute)
+	f.runJob()
+	health := f.worker.Health(ctx)
+	require.True(t, health.Healthy)
+	assert.Equal(t, 1, health.ActiveWorkers)
+	assert.Equal(t, f.clock, *health.LastJobCompletedAt)
+
+	// The next job hangs and nothing completes for longer than the stall window
+	f.gen.entered, f.gen.release = make(chan struct{}), make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		f.runJob()
+		close(done)
+	}()
+	<-f.gen.entered
+	f.clock = f.clock.Add(11 * time.Minute)
+	health = f.worker.Health(ctx)
+	assert.False(t, health.Healthy)
+	assert.Equal(t, WorkerHealthStalled, health.Status)
+	assert.Equal(t, 1, health.InFlight[models.CampaignTypeDomainGeneration])
+	assert.False(t, health.QueueEmpty)
+	assert.True(t, health.PollerAlive, "a loop busy with a job is not a dead poller")
+
+	close(f.gen.release)
+	<-done
+	health = f.worker.Health(ctx)
+	assert.True(t, health.Healthy, "completing a job clears the stall")
+	assert.Empty(t, health.InFlight)
+}
+
+func TestWorkerHealth_IdleFailingAndStopped(t *testing.T) {
+	f := newWorkerHealthFixture()
+	ctx := context.Background()
+
+	// No job has ever completed, but nothing is queued either
+	f.jobStore.queued = nil
+	f.clock = f.clock.Add(time.Hour)
+	f.worker.health.polled(f.clock)
+	health := f.worker.Health(ctx)
+	assert.True(t, health.Healthy)
+	assert.True(t, health.QueueEmpty)
+	assert.Nil(t, health.LastJobCompletedAt)
+
+	f.gen.err = errors.New("resolver unreachable")
+	f.runJob()
+	f.runJob()
+	assert.Equal(t, 2, f.worker.Health(ctx).ConsecutiveFailures)
+	f.gen.err = nil
+	f.runJob()
+	assert.Zero(t, f.worker.Health(ctx).ConsecutiveFailures)
+
+	// A loop that stopped polling is reported dead, and with no loop left the workers are stopped
+	f.clock = f.clock.Add(time.Minute)
+	assert.False(t, f.worker.Health(ctx).PollerAlive)
+	f.worker.health.workerStopped()
+	health = f.worker.Health(ctx)
+	assert.False(t, health.Healthy)
+	assert.Equal(t, WorkerHealthStopped, health.Status)
+}
@@ -0,0 +1,314 @@
+// Package settings provides runtime-adjustable application settings.
+//
+// Every setting is declared in a Registry with its type and default value. Values are stored as
+// JSON through a store.SettingsStore and cached in memory; the cache is updated on every write made
+// through the Service and entries expire after the cache TTL so changes made by other instances
+// are picked up.
+package settings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrUnknownSetting is returned for keys that are not registered
+	ErrUnknownSetting = errors.New("unknown setting")
+	// ErrInvalidValue is returned when a value does not match its setting's type or bounds
+	ErrInvalidValue = errors.New("invalid setting value")
+)
+
+// Type is the value type of a setting
+type Type string
+
+const (
+	TypeString Type = "string"
+	TypeInt    Type = "int"
+	TypeBool   Type = "bool"
+	TypeJSON   Type = "json"
+)
+
+// Registered setting keys
+const (
+	KeyMaintenanceMode       = "maintenance.enabled"
+	KeyMaintenanceMessage    = "maintenance.message"
+	KeyAuditLogRetentionDays = "retention.auditLogDays"
+	KeyFeatureFlags          = "features.flags"
+)
+
+// Definition describes one registered setting
+type Definition struct {
+	Key         string      `json:"key"`
+	Type        Type        `json:"type"`
+	Default     interface{} `json:"default"`
+	Description string      `json:"description"`
+	Min         *int64      `json:"min,omitempty"` // Lower bound for int settings
+	Max         *int64      `json:"max,omitempty"` // Upper bound for int settings
+}
+
+// Registry is the schema of known settings, keyed by setting key
+type Registry map[string]Definition
+
+func int64Ptr(v int64) *int64 { return &v }
+
+// DefaultRegistry returns the settings known to the application
+func DefaultRegistry() Registry {
+	return NewRegistry(
+		Definition{Key: KeyMaintenanceMode, Type: TypeBool, Default: false, Description: "Reject non-admin API requests while maintenance is in progress"},
+		Definition{Key: KeyMaintenanceMessage, Type: TypeString, Default: "", Description: "Message shown to users while maintenance mode is enabled"},
+		Definition{Key: KeyAuditLogRetentionDays, Type: TypeInt, Default: 90, Min: int64Ptr(1), Max: int64Ptr(3650), Description: "Days to keep audit log entries"},
+		Definition{Key: KeyFeatureFlags, Type: TypeJSON, Default: map[string]bool{}, Description: "Feature flags as a JSON object of flag name to enabled"},
+	)
+}
+
+// NewRegistry builds a registry from setting definitions
+func NewRegistry(defs ...Definition) Registry {
+	r := make(Registry, len(defs))
+	for _, def := range defs {
+		r[def.Key] = def
+	}
+	return r
+}
+
+// Keys returns the registered keys in sorted order
+func (r Registry) Keys() []string {
+	keys := make([]string, 0, len(r))
+	for key := range r {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Validate checks that key is registered and value is valid JSON for its type, returning the
+// value in canonical compact form.
+func (r Registry) Validate(key string, value json.RawMessage) (json.RawMessage, error) {
+	def, ok := r[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSetting, key)
+	}
+	trimmed := bytes.TrimSpace(value)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil, fmt.Errorf("%w: %s requires a %s value", ErrInvalidValue, key, def.Type)
+	}
+
+	var err error
+	switch def.Type {
+	case TypeString:
+		var v string
+		err = json.Unmarshal(trimmed, &v)
+	case TypeBool:
+		var v bool
+		err = json.Unmarshal(trimmed, &v)
+	case TypeInt:
+		var v int64
+		if err = json.Unmarshal(trimmed, &v); err == nil {
+			if def.Min != nil && v < *def.Min {
+				return nil, fmt.Errorf("%w: %s must be at least %d", ErrInvalidValue, key, *def.Min)
+			}
+			if def.Max != nil && v > *def.Max {
+				return nil, fmt.Errorf("%w: %s must be at most %d", ErrInvalidValue, key, *def.Max)
+			}
+		}
+	case TypeJSON:
+		if !json.Valid(trimmed) {
+			err = errors.New("not valid JSON")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s requires a %s value", ErrInvalidValue, key, def.Type)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, trimmed); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidValue, key, err)
+	}
+	return compact.Bytes(), nil
+}
+
+// DefaultCacheTTL is how long a cached value is used before it is reloaded from the store
+const DefaultCacheTTL = 30 * time.Second
+
+type cacheEntry struct {
+	setting  *models.Setting // nil when the setting has no stored value
+	loadedAt time.Time
+}
+
+// Service reads and writes registered settings with an in-memory cache. It is safe for concurrent use.
+type Service struct {
+	store    store.SettingsStore
+	registry Registry
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu    sync.RWMutex
+	cache map[string]cacheEntry
+}
+
+// NewService creates a settings service. A non-positive ttl uses DefaultCacheTTL.
+func NewService(settingsStore store.SettingsStore, registry Registry, ttl time.Duration) *Service {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &Service{
+		store:    settingsStore,
+		registry: registry,
+		ttl:      ttl,
+		now:      time.Now,
+		cache:    make(map[string]cacheEntry),
+	}
+}
+
+// Registry returns the schema of settings the service accepts
+func (s *Service) Registry() Registry {
+	return s.registry
+}
+
+// Invalidate drops the cached value for key, or every cached value when no key is given
+func (s *Service) Invalidate(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(keys) == 0 {
+		s.cache = make(map[string]cacheEntry)
+		return
+	}
+	for _, key := range keys {
+		delete(s.cache, key)
+	}
+}
+
+// Get returns the stored setting for key, or nil when only the default applies
+func (s *Service) Get(ctx context.Context, key string) (*models.Setting, error) {
+	if _, ok := s.registry[key]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSetting, key)
+	}
+
+	s.mu.RLock()
+	entry, ok := s.cache[key]
+	s.mu.RUnlock()
+	if ok && s.now().Sub(entry.loadedAt) < s.ttl {
+		return entry.setting, nil
+	}
+
+	setting, err := s.store.GetSetting(ctx, nil, key)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load setting %s: %w", key, err)
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		setting = nil
+	}
+	s.mu.Lock()
+	s.cache[key] = cacheEntry{setting: setting, loadedAt: s.now()}
+	s.mu.Unlock()
+	return setting, nil
+}
+
+// Raw returns the JSON value of key, falling back to the registered default
+func (s *Service) Raw(ctx context.Context, key string) (json.RawMessage, error) {
+	setting, err := s.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if setting != nil {
+		return setting.Value, nil
+	}
+	return json.Marshal(s.registry[key].Default)
+}
+
+// Set validates and stores the JSON value of key
+func (s *Service) Set(ctx context.Context, key string, value json.RawMessage, updatedBy uuid.NullUUID) (*models.Setting, error) {
+	canonical, err := s.registry.Validate(key, value)
+	if err != nil {
+		return nil, err
+	}
+	setting := &models.Setting{Key: key, Value: canonical, UpdatedBy: updatedBy, UpdatedAt: s.now().UTC()}
+	if err := s.store.UpsertSetting(ctx, nil, setting); err != nil {
+		return nil, fmt.Errorf("failed to save setting %s: %w", key, err)
+	}
+	s.mu.Lock()
+	s.cache[key] = cacheEntry{setting: setting, loadedAt: s.now()}
+	s.mu.Unlock()
+	return setting, nil
+}
+
+func (s *Service) getTyped(ctx context.Context, key string, want Type, dest interface{}) error {
+	if def, ok := s.registry[key]; ok && def.Type != want {
+		return fmt.Errorf("%w: %s is a %s setting, not %s", ErrInvalidValue, key, def.Type, want)
+	}
+	raw, err := s.Raw(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, dest); err != nil {
+		return fmt.Errorf("%w: stored value of %s: %v", ErrInvalidValue, key, err)
+	}
+	return nil
+}
+
+func (s *Service) setTyped(ctx context.Context, key string, want Type, value interface{}, updatedBy uuid.NullUUID) error {
+	if def, ok := s.registry[key]; ok && def.Type != want {
+		return fmt.Errorf("%w: %s is a %s setting, not %s", ErrInvalidValue, key, def.Type, want)
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidValue, key, err)
+	}
+	_, err = s.Set(ctx, key, raw, updatedBy)
+	return err
+}
+
+// GetString returns the value of a string setting
+func (s *Service) GetString(ctx context.Context, key string) (string, error) {
+	var v string
+	err := s.getTyped(ctx, key, TypeString, &v)
+	return v, err
+}
+
+// GetInt returns the value of an int setting
+func (s *Service) GetInt(ctx context.Context, key string) (int64, error) {
+	var v int64
+	err := s.getTyped(ctx, key, TypeInt, &v)
+	return v, err
+}
+
+// GetBool returns the value of a bool setting
+func (s *Service) GetBool(ctx context.Context, key string) (bool, error) {
+	var v bool
+	err := s.getTyped(ctx, key, TypeBool, &v)
+	return v, err
+}
+
+// GetJSON decodes the value of a JSON setting into dest
+func (s *Service) GetJSON(ctx context.Context, key string, dest interface{}) error {
+	return s.getTyped(ctx, key, TypeJSON, dest)
+}
+
+// SetString stores the value of a string setting
+func (s *Service) SetString(ctx context.Context, key, value string, updatedBy uuid.NullUUID) error {
+	return s.setTyped(ctx, key, TypeString, value, updatedBy)
+}
+
+// SetInt stores the value of an int setting
+func (s *Service) SetInt(ctx context.Context, key string, value int64, updatedBy uuid.NullUUID) error {
+	return s.setTyped(ctx, key, TypeInt, value, updatedBy)
+}
+
+// SetBool stores the value of a bool setting
+func (s *Service) SetBool(ctx context.Context, key string, value bool, updatedBy uuid.NullUUID) error {
+	return s.setTyped(ctx, key, TypeBool, value, updatedBy)
+}
+
+// SetJSON stores value, encoded as JSON, in a JSON setting
+func (s *Service) SetJSON(ctx context.Context, key string, value interface{}, updatedBy uuid.NullUUID) error {
+	return s.setTyped(ctx, key, TypeJSON, value, updatedBy)
+}
@@ -0,0 +1,159 @@
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySettingsStore is an in-memory store.SettingsStore that counts reads
+type memorySettingsStore struct {
+	mu       sync.Mutex
+	settings map[string]models.Setting
+	reads    int
+}
+
+func newMemorySettingsStore() *memorySettingsStore {
+	return &memorySettingsStore{settings: make(map[string]models.Setting)}
+}
+
+func (m *memorySettingsStore) GetSetting(ctx context.Context, exec store.Querier, key string) (*models.Setting, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reads++
+	setting, ok := m.settings[key]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return &setting, nil
+}
+
+func (m *memorySettingsStore) ListSettings(ctx context.Context, exec store.Querier) ([]*models.Setting, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := []*models.Setting{}
+	for _, setting := range m.settings {
+		s := setting
+		result = append(result, &s)
+	}
+	return result, nil
+}
+
+func (m *memorySettingsStore) UpsertSetting(ctx context.Context, exec store.Querier, setting *models.Setting) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.settings[setting.Key] = *setting
+	return nil
+}
+
+func TestService_TypedRoundTrips(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(newMemorySettingsStore(), DefaultRegistry(), time.Minute)
+	admin := uuid.NullUUID{UUID: uuid.New(), Valid: true}
+
+	require.NoError(t, svc.SetBool(ctx, KeyMaintenanceMode, true, admin))
+	enabled, err := svc.GetBool(ctx, KeyMaintenanceMode)
+	require.NoError(t, err)
+	assert.True(t, enabled)
+
+	require.NoError(t, svc.SetString(ctx, KeyMaintenanceMessage, "Back at 10:00 UTC", admin))
+	message, err := svc.GetString(ctx, KeyMaintenanceMessage)
+	require.NoError(t, err)
+	assert.Equal(t, "Back at 10:00 UTC", message)
+
+	require.NoError(t, svc.SetInt(ctx, KeyAuditLogRetentionDays, 30, admin))
+	days, err := svc.GetInt(ctx, KeyAuditLogRetentionDays)
+	require.NoError(t, err)
+	assert.Equal(t, int64(30), days)
+
+	require.NoError(t, svc.SetJSON(ctx, KeyFeatureFlags, map[string]bool{"bulkExport": true}, admin))
+	var flags map[string]bool
+	require.NoError(t, svc.GetJSON(ctx, KeyFeatureFlags, &flags))
+	assert.Equal(t, map[string]bool{"bulkExport": true}, flags)
+
+	setting, err := svc.Get(ctx, KeyFeatureFlags)
+	require.NoError(t, err)
+	assert.Equal(t, admin, setting.UpdatedBy)
+}
+
+func TestService_DefaultsApplyWhenUnset(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(newMemorySettingsStore(), DefaultRegistry(), time.Minute)
+
+	enabled, err := svc.GetBool(ctx, KeyMaintenanceMode)
+	require.NoError(t, err)
+	assert.False(t, enabled)
+
+	days, err := svc.GetInt(ctx, KeyAuditLogRetentionDays)
+	require.NoError(t, err)
+	assert.Equal(t, int64(90), days)
+}
+
+func TestService_RejectsUnknownKeysAndInvalidValues(t *testing.T) {
+	ctx := context.Background()
+	settingsStore := newMemorySettingsStore()
+	svc := NewService(settingsStore, DefaultRegistry(), time.Minute)
+
+	_, err := svc.Set(ctx, "maintenance.enabeld", json.RawMessage(`true`), uuid.NullUUID{})
+	assert.ErrorIs(t, err, ErrUnknownSetting)
+	_, err = svc.GetString(ctx, "no.such.key")
+	assert.ErrorIs(t, err, ErrUnknownSetting)
+
+	_, err = svc.Set(ctx, KeyMaintenanceMode, json.RawMessage(`"yes"`), uuid.NullUUID{})
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	_, err = svc.Set(ctx, KeyAuditLogRetentionDays, json.RawMessage(`1.5`), uuid.NullUUID{})
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	_, err = svc.Set(ctx, KeyAuditLogRetentionDays, json.RawMessage(`0`), uuid.NullUUID{})
+	assert.ErrorIs(t, err, ErrInvalidValue, "below the registered minimum")
+	_, err = svc.Set(ctx, KeyFeatureFlags, json.RawMessage(`null`), uuid.NullUUID{})
+	assert.ErrorIs(t, err, ErrInvalidValue)
+
+	// Typed accessors must match the registered type
+	assert.ErrorIs(t, svc.SetString(ctx, KeyAuditLogRetentionDays, "30", uuid.NullUUID{}), ErrInvalidValue)
+	_, err = svc.GetBool(ctx, KeyMaintenanceMessage)
+	assert.ErrorIs(t, err, ErrInvalidValue)
+
+	assert.Empty(t, settingsStore.settings, "rejected values are never stored")
+}
+
+func TestService_CachesUntilInvalidatedOrExpired(t *testing.T) {
+	ctx := context.Background()
+	settingsStore := newMemorySettingsStore()
+	svc := NewService(settingsStore, DefaultRegistry(), time.Minute)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	_, err := svc.GetBool(ctx, KeyMaintenanceMode)
+	require.NoError(t, err)
+	_, err = svc.GetBool(ctx, KeyMaintenanceMode)
+	require.NoError(t, err)
+	assert.Equal(t, 1, settingsStore.reads)
+
+	// Another instance changes the value directly in the store
+	require.NoError(t, settingsStore.UpsertSetting(ctx, nil, &models.Setting{Key: KeyMaintenanceMode, Value: json.RawMessage(`true`)}))
+	enabled, _ := svc.GetBool(ctx, KeyMaintenanceMode)
+	assert.False(t, enabled, "cached value is served within the TTL")
+
+	svc.Invalidate(KeyMaintenanceMode)
+	enabled, _ = svc.GetBool(ctx, KeyMaintenanceMode)
+	assert.True(t, enabled)
+
+	require.NoError(t, settingsStore.UpsertSetting(ctx, nil, &models.Setting{Key: KeyMaintenanceMode, Value: json.RawMessage(`false`)}))
+	now = now.Add(time.Minute)
+	enabled, _ = svc.GetBool(ctx, KeyMaintenanceMode)
+	assert.False(t, enabled, "expired entries are reloaded")
+}
+
+func TestRegistry_ValidateCompactsValues(t *testing.T) {
+	value, err := DefaultRegistry().Validate(KeyFeatureFlags, json.RawMessage("{ \"a\" : true }"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":true}`, string(value))
+}
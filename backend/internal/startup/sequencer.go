@@ -0,0 +1,137 @@
+// Package startup runs the ordered steps that must complete before the server reports ready.
+//
+// The HTTP server starts listening as soon as routes are registered so liveness probes answer,
+// while a Sequencer waits for the database, verifies migrations and starts background services.
+// Readiness only passes once every enabled step has finished, so load balancers do not route
+// traffic to an instance that cannot serve it yet.
+package startup
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// StepState is the progress of a single startup step
+type StepState string
+
+const (
+	StepPending StepState = "pending"
+	StepRunning StepState = "running"
+	StepDone    StepState = "done"
+	StepSkipped StepState = "skipped"
+	StepFailed  StepState = "failed"
+)
+
+// Step is one unit of startup work. Disabled steps are logged and skipped.
+type Step struct {
+	Name    string
+	Enabled bool
+	Run     func(ctx context.Context) error
+}
+
+// StepStatus reports the state of a step for readiness responses
+type StepStatus struct {
+	Name  string    `json:"name"`
+	State StepState `json:"state"`
+	Error string    `json:"error,omitempty"`
+}
+
+// Sequencer runs startup steps in order and tracks whether all of them completed.
+// It is safe for concurrent use.
+type Sequencer struct {
+	mu       sync.RWMutex
+	steps    []Step
+	statuses []StepStatus
+	ready    bool
+}
+
+// NewSequencer creates a Sequencer for the given steps
+func NewSequencer(steps ...Step) *Sequencer {
+	statuses := make([]StepStatus, len(steps))
+	for i, step := range steps {
+		statuses[i] = StepStatus{Name: step.Name, State: StepPending}
+	}
+	return &Sequencer{steps: steps, statuses: statuses}
+}
+
+// Run executes the steps in order, stopping at the first failure. The sequencer becomes ready
+// only when every step has finished or been skipped.
+func (s *Sequencer) Run(ctx context.Context) error {
+	for i, step := range s.steps {
+		if !step.Enabled {
+			log.Printf("Startup: step %q disabled, skipping.", step.Name)
+			s.setState(i, StepSkipped, nil)
+			continue
+		}
+
+		log.Printf("Startup: step %q starting.", step.Name)
+		s.setState(i, StepRunning, nil)
+		started := time.Now()
+		if err := step.Run(ctx); err != nil {
+			log.Printf("Startup: step %q failed after %s: %v", step.Name, time.Since(started).Round(time.Millisecond), err)
+			s.setState(i, StepFailed, err)
+			return fmt.Errorf("startup step %q failed: %w", step.Name, err)
+		}
+		log.Printf("Startup: step %q completed in %s.", step.Name, time.Since(started).Round(time.Millisecond))
+		s.setState(i, StepDone, nil)
+	}
+
+	s.mu.Lock()
+	s.ready = true
+	s.mu.Unlock()
+	log.Println("Startup: all steps completed, instance is ready.")
+	return nil
+}
+
+// Ready reports whether every startup step has completed
+func (s *Sequencer) Ready() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ready
+}
+
+// Steps returns the current state of each step
+func (s *Sequencer) Steps() []StepStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]StepStatus(nil), s.statuses...)
+}
+
+func (s *Sequencer) setState(i int, state StepState, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statuses[i].State = state
+	s.statuses[i].Error = ""
+	if err != nil {
+		s.statuses[i].Error = err.Error()
+	}
+}
+
+// PingWithRetry returns a step function that pings until it succeeds, trying up to attempts times
+// with interval between failures.
+func PingWithRetry(ping func(ctx context.Context) error, attempts int, interval time.Duration) func(ctx context.Context) error {
+	if attempts <= 0 {
+		attempts = 1
+	}
+	return func(ctx context.Context) error {
+		var err error
+		for attempt := 1; attempt <= attempts; attempt++ {
+			if err = ping(ctx); err == nil {
+				return nil
+			}
+			if attempt == attempts {
+				break
+			}
+			log.Printf("Startup: ping attempt %d/%d failed: %v; retrying in %s", attempt, attempts, err, interval)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(interval):
+			}
+		}
+		return fmt.Errorf("no successful ping after %d attempts: %w", attempts, err)
+	}
+}
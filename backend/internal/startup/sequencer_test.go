@@ -0,0 +1,80 @@
+package startup
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSequencer_ReadyOnlyAfterDelayedDatabaseAndAllSteps(t *testing.T) {
+	// The database refuses pings until it has been asked three times
+	var pings int32
+	ping := func(ctx context.Context) error {
+		if atomic.AddInt32(&pings, 1) < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	workersStarted := make(chan struct{})
+	releaseWorkers := make(chan struct{})
+	seq := NewSequencer(
+		Step{Name: "database", Enabled: true, Run: PingWithRetry(ping, 5, time.Millisecond)},
+		Step{Name: "migrations", Enabled: false},
+		Step{Name: "workers", Enabled: true, Run: func(ctx context.Context) error {
+			close(workersStarted)
+			<-releaseWorkers
+			return nil
+		}},
+	)
+	assert.False(t, seq.Ready())
+
+	done := make(chan error, 1)
+	go func() { done <- seq.Run(context.Background()) }()
+
+	<-workersStarted
+	assert.False(t, seq.Ready(), "not ready while a step is still running")
+	assert.EqualValues(t, 3, atomic.LoadInt32(&pings))
+	steps := seq.Steps()
+	assert.Equal(t, StepDone, steps[0].State)
+	assert.Equal(t, StepSkipped, steps[1].State)
+	assert.Equal(t, StepRunning, steps[2].State)
+
+	close(releaseWorkers)
+	require.NoError(t, <-done)
+	assert.True(t, seq.Ready())
+	assert.Equal(t, StepDone, seq.Steps()[2].State)
+}
+
+func TestSequencer_FailedStepNeverBecomesReady(t *testing.T) {
+	var laterRan bool
+	seq := NewSequencer(
+		Step{Name: "database", Enabled: true, Run: PingWithRetry(func(ctx context.Context) error {
+			return errors.New("connection refused")
+		}, 2, time.Millisecond)},
+		Step{Name: "workers", Enabled: true, Run: func(ctx context.Context) error {
+			laterRan = true
+			return nil
+		}},
+	)
+
+	err := seq.Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "after 2 attempts")
+	assert.False(t, seq.Ready())
+	assert.False(t, laterRan, "steps after a failure do not run")
+	assert.Equal(t, StepFailed, seq.Steps()[0].State)
+	assert.Equal(t, StepPending, seq.Steps()[1].State)
+}
+
+func TestPingWithRetry_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := PingWithRetry(func(ctx context.Context) error { return errors.New("down") }, 10, time.Hour)(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -1,6 +1,9 @@
 package store
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrNotFound is returned when a requested record is not found in the database.
@@ -9,6 +12,10 @@ var (
 	// ErrDuplicateEntry is returned when an insert or update operation violates a unique constraint.
 	ErrDuplicateEntry = errors.New("database record already exists or violates unique constraint")
 
+	// ErrCampaignNameTaken is returned when unique campaign names are enforced and the user already
+	// has a campaign with the name. It wraps ErrDuplicateEntry.
+	ErrCampaignNameTaken = fmt.Errorf("%w: campaign name already in use", ErrDuplicateEntry)
+
 	// ErrUpdateFailed is returned when an update operation does not affect any rows,
 	// potentially because the record does not exist or the data hasn't changed.
 	ErrUpdateFailed = errors.New("database record update failed")
@@ -16,3 +23,21 @@ var (
 	// ErrOptimisticLock is returned when an update operation fails due to a version mismatch in optimistic locking.
 	// ErrOptimisticLock = errors.New("database record update failed due to version mismatch (optimistic lock)")
 )
+
+// ChunkedWriteError is returned when a chunked bulk write fails part-way through.
+// Chunks before the failing one have already been committed.
+type ChunkedWriteError struct {
+	CommittedChunks int
+	TotalChunks     int
+	CommittedRows   int
+	Err             error
+}
+
+func (e *ChunkedWriteError) Error() string {
+	return fmt.Sprintf("chunked write failed after committing %d of %d chunks (%d rows): %v",
+		e.CommittedChunks, e.TotalChunks, e.CommittedRows, e.Err)
+}
+
+func (e *ChunkedWriteError) Unwrap() error {
+	return e.Err
+}
@@ -37,7 +37,26 @@ type CampaignStore interface {
 	DeleteCampaign(ctx context.Context, exec Querier, id uuid.UUID) error
 	ListCampaigns(ctx context.Context, exec Querier, filter ListCampaignsFilter) ([]*models.Campaign, error)
 	CountCampaigns(ctx context.Context, exec Querier, filter ListCampaignsFilter) (int64, error)
+	// ListCampaignStatuses returns the status of each of the campaigns that exists, in one query.
+	// A non-empty userID leaves out campaigns of other users.
+	ListCampaignStatuses(ctx context.Context, exec Querier, ids []uuid.UUID, userID string) ([]CampaignStatusSummary, error)
+	// ListFailedCampaignsToRetry returns up to limit failed auto-retry campaigns with fewer than
+	// maxRetries retries and no retry run yet
+	ListFailedCampaignsToRetry(ctx context.Context, exec Querier, maxRetries, limit int) ([]*models.Campaign, error)
+	// ListCompletedCampaignsToPromote returns up to limit completed domain generation campaigns whose
+	// DNS promotion has not created its DNS validation campaign yet
+	ListCompletedCampaignsToPromote(ctx context.Context, exec Querier, limit int) ([]*models.Campaign, error)
+	// ListCampaignRetryChain returns the original run and retries of the campaign's retry chain, in order
+	ListCampaignRetryChain(ctx context.Context, exec Querier, campaignID uuid.UUID) ([]*models.Campaign, error)
+	// ListCampaignIDsUsingPersona returns the DNS and HTTP keyword campaigns whose parameters list the persona
+	ListCampaignIDsUsingPersona(ctx context.Context, exec Querier, personaID uuid.UUID) ([]uuid.UUID, error)
+	// ListCampaignIDsUsingProxy returns the HTTP keyword campaigns whose parameters list the proxy
+	ListCampaignIDsUsingProxy(ctx context.Context, exec Querier, proxyID uuid.UUID) ([]uuid.UUID, error)
+	// AddDeletedResourceSnapshot stores snapshot under deletedResources.<id> in the metadata of each campaign
+	AddDeletedResourceSnapshot(ctx context.Context, exec Querier, campaignIDs []uuid.UUID, snapshot *models.DeletedResourceSnapshot) error
 	UpdateCampaignStatus(ctx context.Context, exec Querier, id uuid.UUID, status models.CampaignStatusEnum, errorMessage sql.NullString) error
+	// GetCampaignStatusForUpdate returns a campaign's status and locks its row until exec's transaction ends
+	GetCampaignStatusForUpdate(ctx context.Context, exec Querier, id uuid.UUID) (models.CampaignStatusEnum, error)
 	UpdateCampaignProgress(ctx context.Context, exec Querier, id uuid.UUID, processedItems, totalItems int64, progressPercentage float64) error
 
 	CreateDomainGenerationParams(ctx context.Context, exec Querier, params *models.DomainGenerationCampaignParams) error
@@ -57,7 +76,17 @@ type CampaignStore interface {
 
 	CreateDNSValidationResults(ctx context.Context, exec Querier, results []*models.DNSValidationResult) error
 	GetDNSValidationResultsByCampaign(ctx context.Context, exec Querier, campaignID uuid.UUID, filter ListValidationResultsFilter) ([]*models.DNSValidationResult, error)
+	// GetDNSValidationResultByDomain returns the campaign's DNS result for one domain, or ErrNotFound
+	GetDNSValidationResultByDomain(ctx context.Context, exec Querier, campaignID uuid.UUID, domainName string) (*models.DNSValidationResult, error)
 	CountDNSValidationResults(ctx context.Context, exec Querier, campaignID uuid.UUID, onlyValid bool) (int64, error)
+	CountDNSValidationResultsByStatus(ctx context.Context, exec Querier, campaignID uuid.UUID) (map[string]int64, error)
+	GetDNSValidationAttempts(ctx context.Context, exec Querier, campaignID uuid.UUID, domainNames []string) (map[string]int, error)
+	MarkDNSValidationMaxAttemptsExceeded(ctx context.Context, exec Querier, campaignID uuid.UUID, domainNames []string) error
+	// ListRecentDNSValidationErrors returns up to limit failed DNS results, most recently checked first
+	ListRecentDNSValidationErrors(ctx context.Context, exec Querier, campaignID uuid.UUID, limit int) ([]CampaignResultError, error)
+	// ListDNSValidationStatusesAfter returns up to limit DNS result statuses whose domain sorts after
+	// afterDomain, in byte order of the domain name
+	ListDNSValidationStatusesAfter(ctx context.Context, exec Querier, campaignID uuid.UUID, afterDomain string, limit int) ([]CampaignResultStatus, error)
 	GetDomainsForDNSValidation(ctx context.Context, exec Querier, dnsCampaignID uuid.UUID, sourceGenerationCampaignID uuid.UUID, limit int, lastOffsetIndex int64) ([]*models.GeneratedDomain, error)
 
 	CreateHTTPKeywordParams(ctx context.Context, exec Querier, params *models.HTTPKeywordCampaignParams) error
@@ -65,26 +94,83 @@ type CampaignStore interface {
 
 	CreateHTTPKeywordResults(ctx context.Context, exec Querier, results []*models.HTTPKeywordResult) error
 	GetHTTPKeywordResultsByCampaign(ctx context.Context, exec Querier, campaignID uuid.UUID, filter ListValidationResultsFilter) ([]*models.HTTPKeywordResult, error)
+	// GetHTTPKeywordResultByDomain returns the campaign's HTTP keyword result for one domain, or ErrNotFound
+	GetHTTPKeywordResultByDomain(ctx context.Context, exec Querier, campaignID uuid.UUID, domainName string) (*models.HTTPKeywordResult, error)
+	CountHTTPKeywordResultsByStatus(ctx context.Context, exec Querier, campaignID uuid.UUID) (map[string]int64, error)
+	GetHTTPKeywordAttempts(ctx context.Context, exec Querier, campaignID uuid.UUID, domainNames []string) (map[string]int, error)
+	MarkHTTPKeywordMaxAttemptsExceeded(ctx context.Context, exec Querier, campaignID uuid.UUID, domainNames []string) error
+	// UpdateHTTPKeywordResultClassifications rewrites the validation status and keyword matches of
+	// stored HTTP keyword results, leaving what was fetched untouched
+	UpdateHTTPKeywordResultClassifications(ctx context.Context, exec Querier, results []*models.HTTPKeywordResult) error
+	// ListRecentHTTPKeywordErrors returns up to limit failed HTTP keyword results, most recently checked first
+	ListRecentHTTPKeywordErrors(ctx context.Context, exec Querier, campaignID uuid.UUID, limit int) ([]CampaignResultError, error)
+	// ListHTTPKeywordStatusesAfter returns up to limit HTTP keyword result statuses whose domain sorts
+	// after afterDomain, in byte order of the domain name
+	ListHTTPKeywordStatusesAfter(ctx context.Context, exec Querier, campaignID uuid.UUID, afterDomain string, limit int) ([]CampaignResultStatus, error)
 	GetDomainsForHTTPValidation(ctx context.Context, exec Querier, httpKeywordCampaignID uuid.UUID, sourceCampaignID uuid.UUID, limit int, lastDomainName string) ([]*models.DNSValidationResult, error)
+	// HTTPResumePointExists reports whether the domain an HTTP keyword campaign resumes after is still a source DNS result
+	HTTPResumePointExists(ctx context.Context, exec Querier, sourceCampaignID uuid.UUID, domainName string) (bool, error)
+	// GetSafeHTTPResumePoint returns the last valid source domain before pointer up to which every valid source
+	// domain has a finished HTTP keyword result; an empty string means resuming from the start.
+	GetSafeHTTPResumePoint(ctx context.Context, exec Querier, httpKeywordCampaignID uuid.UUID, sourceCampaignID uuid.UUID, pointer string) (string, error)
+
+	// Domain deduplication decisions for campaigns with deduplicate_domains enabled
+	CreateDomainDedupDecisions(ctx context.Context, exec Querier, decisions []*models.DomainDedupDecision) error
+	GetKeptDedupItems(ctx context.Context, exec Querier, campaignID uuid.UUID, normalizedDomains []string) (map[string]uuid.UUID, error)
+	ListDomainDedupDecisions(ctx context.Context, exec Querier, campaignID uuid.UUID, limit, offset int) ([]*models.DomainDedupDecision, error)
 }
 
 // ListCampaignsFilter and ListValidationResultsFilter remain the same
 
+// Tag match modes for ListCampaignsFilter.TagMatch
+const (
+	TagMatchAny = "any" // Campaign has at least one of the tags (default)
+	TagMatchAll = "all" // Campaign has every tag
+)
+
 type ListCampaignsFilter struct {
 	Type      models.CampaignTypeEnum
 	Status    models.CampaignStatusEnum
 	UserID    string
+	Tags      []string
+	TagMatch  string // TagMatchAny or TagMatchAll; empty means TagMatchAny
 	Limit     int
 	Offset    int
 	SortBy    string
 	SortOrder string
 }
 
+// CampaignStatusSummary is the status and progress of one campaign, as read by ListCampaignStatuses
+type CampaignStatusSummary struct {
+	ID                 uuid.UUID                 `db:"id" json:"id"`
+	Status             models.CampaignStatusEnum `db:"status" json:"status"`
+	ProgressPercentage *float64                  `db:"progress_percentage" json:"progressPercentage,omitempty"`
+}
+
+// CampaignResultError is a validation result that failed. Error is only known for DNS results,
+// whose resolver error is kept with the result.
+type CampaignResultError struct {
+	DomainName       string    `db:"domain_name" json:"domainName"`
+	ValidationStatus string    `db:"validation_status" json:"validationStatus"`
+	Error            *string   `db:"error" json:"error,omitempty"`
+	Attempts         int       `db:"attempts" json:"attempts"`
+	CheckedAt        time.Time `db:"checked_at" json:"checkedAt"`
+}
+
+// CampaignResultStatus is the status a campaign recorded for one domain, as read when comparing campaigns
+type CampaignResultStatus struct {
+	DomainName       string `db:"domain_name" json:"domainName"`
+	ValidationStatus string `db:"validation_status" json:"validationStatus"`
+}
+
 type ListValidationResultsFilter struct {
 	ValidationStatus string
 	HasKeywords      *bool
 	Limit            int
 	Offset           int
+	AfterDomainName  string // Set to page by domain name instead of Offset: only results whose domain sorts after it
+	// Set to list only HTTP keyword results whose captured TLS certificate expires before it
+	CertificateExpiresBefore time.Time
 }
 
 // PersonaStore, ProxyStore, KeywordStore, AuditLogStore: methods will accept exec Querier where transactional execution is an option.
@@ -100,10 +186,11 @@ type PersonaStore interface {
 }
 
 type ListPersonasFilter struct {
-	Type      models.PersonaTypeEnum
-	IsEnabled *bool
-	Limit     int
-	Offset    int
+	Type         models.PersonaTypeEnum
+	IsEnabled    *bool
+	AccessibleTo uuid.NullUUID // When valid, only personas owned by this user or shared
+	Limit        int
+	Offset       int
 }
 
 type ProxyStore interface {
@@ -113,15 +200,21 @@ type ProxyStore interface {
 	UpdateProxy(ctx context.Context, exec Querier, proxy *models.Proxy) error
 	DeleteProxy(ctx context.Context, exec Querier, id uuid.UUID) error
 	ListProxies(ctx context.Context, exec Querier, filter ListProxiesFilter) ([]*models.Proxy, error)
-	UpdateProxyHealth(ctx context.Context, exec Querier, id uuid.UUID, isHealthy bool, latencyMs sql.NullInt32, lastCheckedAt time.Time) error
+	CountProxies(ctx context.Context, exec Querier, filter ListProxiesFilter) (int64, error)
+	UpdateProxyHealth(ctx context.Context, exec Querier, id uuid.UUID, isHealthy bool, lastStatus string, latencyMs sql.NullInt32, lastCheckedAt time.Time) error
 }
 
 type ListProxiesFilter struct {
-	Protocol  models.ProxyProtocolEnum
-	IsEnabled *bool
-	IsHealthy *bool
-	Limit     int
-	Offset    int
+	Protocol     models.ProxyProtocolEnum
+	IsEnabled    *bool
+	IsHealthy    *bool
+	CountryCode  string
+	Provider     string
+	AccessibleTo uuid.NullUUID // When valid, only proxies owned by this user or shared
+	SortBy       string        // "name" (default) or "latency"; proxies never checked sort last by latency
+	SortOrder    string        // "asc" (default) or "desc"
+	Limit        int
+	Offset       int
 }
 
 type KeywordStore interface {
@@ -152,6 +245,9 @@ type AuditLogStore interface {
 	// No Transactor needed. exec Querier allows running in existing Tx if caller provides one.
 	CreateAuditLog(ctx context.Context, exec Querier, logEntry *models.AuditLog) error
 	ListAuditLogs(ctx context.Context, exec Querier, filter ListAuditLogsFilter) ([]*models.AuditLog, error)
+	// ListAuditLogsAfter returns up to filter.Limit entries in ascending (timestamp, id) order that
+	// come after the cursor; a nil cursor starts at the oldest entry. Offset is ignored.
+	ListAuditLogsAfter(ctx context.Context, exec Querier, filter ListAuditLogsFilter, after *AuditLogCursor) ([]*models.AuditLog, error)
 }
 
 type ListAuditLogsFilter struct {
@@ -165,8 +261,17 @@ type ListAuditLogsFilter struct {
 	Offset     int
 }
 
+// AuditLogCursor is the position of an audit log entry when paging in (timestamp, id) order
+type AuditLogCursor struct {
+	Timestamp time.Time
+	ID        uuid.UUID
+}
+
+// StaleJobError is the last error ReapStaleJobs sets on the jobs it requeues
+const StaleJobError = "worker stopped sending heartbeats while processing the job"
+
 // CampaignJobStore: Most methods will use internal client/db directly.
-// GetNextQueuedJob manages its own transaction for atomicity.
+// GetNextQueuedJob claims a job atomically, so no two workers are handed the same job.
 // Transactor is kept if a service needs to batch multiple job store operations into one Tx.
 type CampaignJobStore interface {
 	Transactor // For potential service-managed transactions involving multiple job ops
@@ -175,8 +280,36 @@ type CampaignJobStore interface {
 	GetJobByID(ctx context.Context, jobID uuid.UUID) (*models.CampaignJob, error)
 	UpdateJob(ctx context.Context, exec Querier, job *models.CampaignJob) error
 	GetNextQueuedJob(ctx context.Context, campaignTypes []models.CampaignTypeEnum, workerID string) (*models.CampaignJob, error)
+	// TouchJobHeartbeat records that workerID is still processing the job; ErrNotFound once the job
+	// is no longer processing by workerID
+	TouchJobHeartbeat(ctx context.Context, jobID uuid.UUID, workerID string) error
+	// ReapStaleJobs sets up to limit processing jobs whose last heartbeat is before staleBefore back
+	// to retry and returns them, still naming the worker that abandoned them
+	ReapStaleJobs(ctx context.Context, staleBefore time.Time, limit int) ([]*models.CampaignJob, error)
 	DeleteJob(ctx context.Context, jobID uuid.UUID) error
 	ListJobs(ctx context.Context, filter ListJobsFilter) ([]*models.CampaignJob, error)
+	// CountJobs counts the jobs ListJobs would return for filter without its limit and offset
+	CountJobs(ctx context.Context, filter ListJobsFilter) (int64, error)
+}
+
+// CampaignJobDeadLetterStore moves campaign jobs that failed on every attempt out of the job queue
+// and back into it. A CampaignJobStore that also implements it has workers dead-letter exhausted
+// jobs instead of leaving them failed in the queue.
+type CampaignJobDeadLetterStore interface {
+	// DeadLetterJob removes the job from the queue and keeps it, as given, as a dead letter;
+	// ErrNotFound if the job is no longer queued
+	DeadLetterJob(ctx context.Context, exec Querier, job *models.CampaignJob) (*models.DeadLetterJob, error)
+	ListDeadLetterJobs(ctx context.Context, exec Querier, filter ListDeadLetterJobsFilter) ([]*models.DeadLetterJob, error)
+	// ReplayDeadLetterJob puts the dead letter back in the queue as a queued job with its ID and
+	// no attempts made; ErrNotFound if there is no such dead letter
+	ReplayDeadLetterJob(ctx context.Context, exec Querier, id uuid.UUID) (*models.CampaignJob, error)
+}
+
+// ListDeadLetterJobsFilter selects dead letter jobs, most recently dead-lettered first
+type ListDeadLetterJobsFilter struct {
+	CampaignID uuid.NullUUID
+	Limit      int
+	Offset     int
 }
 
 type ListJobsFilter struct {
@@ -189,6 +322,155 @@ type ListJobsFilter struct {
 	SortOrder    string
 }
 
+// EventDeliveryStore persists outbound webhook event deliveries and their attempts.
+type EventDeliveryStore interface {
+	CreateEventDelivery(ctx context.Context, exec Querier, delivery *models.EventDelivery) error
+	GetEventDeliveryByID(ctx context.Context, exec Querier, id uuid.UUID) (*models.EventDelivery, error)
+	UpdateEventDelivery(ctx context.Context, exec Querier, delivery *models.EventDelivery) error
+	ListEventDeliveries(ctx context.Context, exec Querier, filter ListEventDeliveriesFilter) ([]*models.EventDelivery, error)
+	CreateEventDeliveryAttempt(ctx context.Context, exec Querier, attempt *models.EventDeliveryAttempt) error
+	ListEventDeliveryAttempts(ctx context.Context, exec Querier, deliveryID uuid.UUID) ([]*models.EventDeliveryAttempt, error)
+}
+
+type ListEventDeliveriesFilter struct {
+	EventType string
+	Status    models.EventDeliveryStatusEnum
+	TargetURL string
+	Limit     int
+	Offset    int
+}
+
+// CampaignListViewStore persists saved per-user campaign list views.
+type CampaignListViewStore interface {
+	CreateCampaignListView(ctx context.Context, exec Querier, view *models.CampaignListView) error
+	GetCampaignListViewByName(ctx context.Context, exec Querier, userID uuid.UUID, name string) (*models.CampaignListView, error)
+	ListCampaignListViews(ctx context.Context, exec Querier, userID uuid.UUID) ([]*models.CampaignListView, error)
+}
+
+// RolePermissionStore manages roles, permissions and the grants between them.
+type RolePermissionStore interface {
+	// EnsurePermission creates the permission unless one with the same resource and action exists,
+	// fills in perm.ID with the stored permission's ID and reports whether it was created.
+	EnsurePermission(ctx context.Context, exec Querier, perm *models.Permission) (bool, error)
+	GetRoleByName(ctx context.Context, exec Querier, name string) (*models.Role, error)
+	// GrantPermissionToRole assigns the permission to the role and reports whether the grant is new.
+	GrantPermissionToRole(ctx context.Context, exec Querier, roleID, permissionID uuid.UUID) (bool, error)
+}
+
+// SettingsStore persists runtime settings as JSON values keyed by setting name.
+type SettingsStore interface {
+	GetSetting(ctx context.Context, exec Querier, key string) (*models.Setting, error)
+	ListSettings(ctx context.Context, exec Querier) ([]*models.Setting, error)
+	UpsertSetting(ctx context.Context, exec Querier, setting *models.Setting) error
+}
+
+// UserDataStore reads and erases a user's personal data for data-subject requests.
+type UserDataStore interface {
+	Transactor
+
+	GetUserDataProfile(ctx context.Context, exec Querier, userID uuid.UUID) (*models.UserDataProfile, error)
+	ListUserDataSessions(ctx context.Context, exec Querier, userID uuid.UUID, limit, offset int) ([]*models.UserDataSession, error)
+	// EraseUserData anonymizes the account, deletes its sessions and reset tokens and scrubs personal
+	// data from audit records, keeping the rows so aggregates and ownership stay intact.
+	EraseUserData(ctx context.Context, exec Querier, userID uuid.UUID, erasedAt time.Time) (*models.UserDataErasure, error)
+}
+
+// ListUserAccountsFilter selects accounts by state. A nil state matches every account.
+type ListUserAccountsFilter struct {
+	IsActive *bool
+	IsLocked *bool
+	Limit    int
+	Offset   int
+}
+
+// UserAccountStore lists user accounts and changes their lockout and active state for administrators.
+type UserAccountStore interface {
+	Transactor
+
+	// ListUserAccounts returns the matching accounts, newest first
+	ListUserAccounts(ctx context.Context, exec Querier, filter ListUserAccountsFilter) ([]*models.UserAccount, error)
+	CountUserAccounts(ctx context.Context, exec Querier, filter ListUserAccountsFilter) (int64, error)
+	GetUserSecurityStatus(ctx context.Context, exec Querier, userID uuid.UUID) (*models.UserSecurityStatus, error)
+	// UnlockUserAccount clears the account's lock and failed login count and deletes the login rate
+	// limits held against the user and the addresses their failed logins came from. ErrNotFound if
+	// there is no such user.
+	UnlockUserAccount(ctx context.Context, exec Querier, userID uuid.UUID, unlockedAt time.Time) (*models.UserAccountUnlock, error)
+	// SetUserActive enables or disables the account and reports whether that changed it; ErrNotFound
+	// if there is no such user.
+	SetUserActive(ctx context.Context, exec Querier, userID uuid.UUID, active bool, changedAt time.Time) (bool, error)
+	CreateAuthAuditLog(ctx context.Context, exec Querier, entry *models.AuthAuditLog) error
+}
+
+// RateLimitStore counts attempts at rate-limited actions, such as failed logins, per identifier: a
+// client IP address or an account.
+type RateLimitStore interface {
+	// GetRateLimit returns the identifier's count for the action; ErrNotFound if it has none
+	GetRateLimit(ctx context.Context, exec Querier, identifier, action string) (*models.RateLimit, error)
+	// RecordRateLimitAttempt counts one attempt at now and returns the updated count. A count whose
+	// window started before windowStart starts over. Once the window holds maxAttempts attempts the
+	// identifier is blocked until blockedUntil.
+	RecordRateLimitAttempt(ctx context.Context, exec Querier, identifier, action string, now, windowStart time.Time, maxAttempts int, blockedUntil time.Time) (*models.RateLimit, error)
+	// DeleteRateLimit forgets the identifier's count for the action
+	DeleteRateLimit(ctx context.Context, exec Querier, identifier, action string) error
+}
+
+// PasswordMigrationStore finds accounts whose password hash predates the current pepper and makes
+// them change their password.
+type PasswordMigrationStore interface {
+	// ListLegacyPepperUsers returns the active accounts hashed with a pepper version below current
+	ListLegacyPepperUsers(ctx context.Context, exec Querier, currentPepperVersion int) ([]*models.UserPasswordPepperState, error)
+	// RequirePasswordChange sets must_change_password and password_migration_required on the accounts
+	// and returns how many were changed.
+	RequirePasswordChange(ctx context.Context, exec Querier, userIDs []uuid.UUID) (int64, error)
+}
+
+// APIKeyStore persists API keys by the hash of the key.
+type APIKeyStore interface {
+	CreateAPIKey(ctx context.Context, exec Querier, key *models.APIKey) error
+	// GetAPIKeyByHash returns the key with the hash, including revoked and expired keys, as long as
+	// its owner is active; otherwise ErrNotFound.
+	GetAPIKeyByHash(ctx context.Context, exec Querier, keyHash string) (*models.APIKey, error)
+	ListAPIKeys(ctx context.Context, exec Querier, userID uuid.UUID) ([]*models.APIKey, error)
+	// RevokeAPIKey marks the user's key revoked; ErrNotFound if the user has no such unrevoked key.
+	RevokeAPIKey(ctx context.Context, exec Querier, userID, keyID uuid.UUID, revokedAt time.Time) error
+}
+
+// CampaignWebhookStore persists the webhooks notified when a campaign reaches a terminal status.
+type CampaignWebhookStore interface {
+	CreateCampaignWebhook(ctx context.Context, exec Querier, webhook *models.CampaignWebhook) error
+	GetCampaignWebhookByID(ctx context.Context, exec Querier, id uuid.UUID) (*models.CampaignWebhook, error)
+	// DeleteCampaignWebhook removes the campaign's webhook; ErrNotFound if the campaign has no such webhook.
+	DeleteCampaignWebhook(ctx context.Context, exec Querier, campaignID, webhookID uuid.UUID) error
+	// ListCampaignWebhooksToNotify returns up to limit webhooks of completed, failed or cancelled
+	// campaigns that have not been notified of the campaign's current status
+	ListCampaignWebhooksToNotify(ctx context.Context, exec Querier, limit int) ([]*models.CampaignWebhook, error)
+	// MarkCampaignWebhookNotified records that the webhook is being notified of status and reports
+	// whether it had not been already, so only one server sends each notification.
+	MarkCampaignWebhookNotified(ctx context.Context, exec Querier, webhookID uuid.UUID, status models.CampaignStatusEnum, notifiedAt time.Time) (bool, error)
+}
+
+// CampaignScheduleStore persists the schedules that start campaigns at a set time or on a cron expression.
+type CampaignScheduleStore interface {
+	// UpsertCampaignSchedule creates the campaign's schedule or replaces the one it has, keeping
+	// the outcome of its last firing and releasing any lock on it
+	UpsertCampaignSchedule(ctx context.Context, exec Querier, schedule *models.CampaignSchedule) error
+	GetCampaignScheduleByCampaignID(ctx context.Context, exec Querier, campaignID uuid.UUID) (*models.CampaignSchedule, error)
+	// DeleteCampaignSchedule removes the campaign's schedule; ErrNotFound if it has none.
+	DeleteCampaignSchedule(ctx context.Context, exec Querier, campaignID uuid.UUID) error
+	// ClaimDueCampaignSchedules locks up to limit schedules due by now that no server holds until
+	// lockedUntil and returns them, so only the server that claimed a schedule fires it
+	ClaimDueCampaignSchedules(ctx context.Context, exec Querier, now, lockedUntil time.Time, limit int) ([]*models.CampaignSchedule, error)
+	// RecordCampaignScheduleRun stores the outcome of a firing and the next run time and releases
+	// the lock. It reports false when the schedule was replaced or deleted while it was firing.
+	RecordCampaignScheduleRun(ctx context.Context, exec Querier, schedule *models.CampaignSchedule) (bool, error)
+}
+
+// MetricsStore counts what the metrics endpoint publishes.
+type MetricsStore interface {
+	CountCampaignsByStatus(ctx context.Context, exec Querier) (map[models.CampaignStatusEnum]int64, error)
+	CountCampaignJobsByStatus(ctx context.Context, exec Querier) (map[models.CampaignJobStatusEnum]int64, error)
+}
+
 func BoolPtr(b bool) *bool {
 	return &b
 }
@@ -0,0 +1,39 @@
+package store
+
+import (
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+)
+
+// EffectiveJobPriority is the priority GetNextQueuedJob orders a runnable job by: its base priority
+// plus one for every full agingInterval it has waited since it was scheduled. An agingInterval of
+// zero or less disables aging.
+func EffectiveJobPriority(job *models.CampaignJob, now time.Time, agingInterval time.Duration) int {
+	if agingInterval <= 0 {
+		return job.Priority
+	}
+	waitingSince := job.ScheduledAt
+	if waitingSince.IsZero() {
+		waitingSince = job.CreatedAt
+	}
+	waited := now.Sub(waitingSince)
+	if waited <= 0 {
+		return job.Priority
+	}
+	return job.Priority + int(waited/agingInterval)
+}
+
+// JobFetchesBefore reports whether GetNextQueuedJob hands out a before b: higher effective priority
+// first, then earlier scheduled, then earlier created. In-memory job stores use it to match the
+// PostgreSQL ordering.
+func JobFetchesBefore(a, b *models.CampaignJob, now time.Time, agingInterval time.Duration) bool {
+	pa, pb := EffectiveJobPriority(a, now, agingInterval), EffectiveJobPriority(b, now, agingInterval)
+	if pa != pb {
+		return pa > pb
+	}
+	if !a.ScheduledAt.Equal(b.ScheduledAt) {
+		return a.ScheduledAt.Before(b.ScheduledAt)
+	}
+	return a.CreatedAt.Before(b.CreatedAt)
+}
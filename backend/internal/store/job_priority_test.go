@@ -0,0 +1,71 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffectiveJobPriority(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	job := &models.CampaignJob{Priority: 2, ScheduledAt: now.Add(-150 * time.Second)}
+
+	assert.Equal(t, 4, EffectiveJobPriority(job, now, time.Minute))
+	assert.Equal(t, 2, EffectiveJobPriority(job, now, 0), "aging disabled")
+	assert.Equal(t, 2, EffectiveJobPriority(job, now.Add(-time.Hour), time.Minute), "not yet runnable")
+
+	unscheduled := &models.CampaignJob{Priority: 1, CreatedAt: now.Add(-3 * time.Minute)}
+	assert.Equal(t, 4, EffectiveJobPriority(unscheduled, now, time.Minute))
+}
+
+// nextJob removes and returns the job GetNextQueuedJob would hand out at now
+func nextJob(queue []*models.CampaignJob, now time.Time, agingInterval time.Duration) (*models.CampaignJob, []*models.CampaignJob) {
+	best := 0
+	for i := range queue {
+		if JobFetchesBefore(queue[i], queue[best], now, agingInterval) {
+			best = i
+		}
+	}
+	job := queue[best]
+	return job, append(queue[:best], queue[best+1:]...)
+}
+
+func TestJobFetchesBefore_AgedLowPriorityJobIsNotStarved(t *testing.T) {
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	lowPriority := &models.CampaignJob{ID: uuid.New(), Priority: 0, ScheduledAt: start, CreatedAt: start}
+
+	run := func(agingInterval time.Duration) (pickedAt int) {
+		queue := []*models.CampaignJob{lowPriority}
+		// Each minute a new priority-10 job arrives and one job is fetched, so high-priority work never runs out
+		for minute := 1; minute <= 60; minute++ {
+			now := start.Add(time.Duration(minute) * time.Minute)
+			queue = append(queue, &models.CampaignJob{ID: uuid.New(), Priority: 10, ScheduledAt: now, CreatedAt: now})
+
+			var job *models.CampaignJob
+			job, queue = nextJob(queue, now, agingInterval)
+			if job.ID == lowPriority.ID {
+				return minute
+			}
+		}
+		return -1
+	}
+
+	assert.Equal(t, -1, run(0), "without aging the low-priority job waits behind every newer job")
+
+	pickedAt := run(time.Minute)
+	require.NotEqual(t, -1, pickedAt, "aged job was never fetched")
+	assert.Equal(t, 10, pickedAt, "fetched once its age makes up the 10-point gap")
+}
+
+func TestJobFetchesBefore_TiesGoToTheOlderJob(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	older := &models.CampaignJob{Priority: 5, ScheduledAt: now.Add(-10 * time.Second), CreatedAt: now.Add(-10 * time.Second)}
+	newer := &models.CampaignJob{Priority: 5, ScheduledAt: now.Add(-5 * time.Second), CreatedAt: now.Add(-5 * time.Second)}
+
+	assert.True(t, JobFetchesBefore(older, newer, now, time.Minute))
+	assert.False(t, JobFetchesBefore(newer, older, now, time.Minute))
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// apiKeyStorePostgres implements the store.APIKeyStore interface
+type apiKeyStorePostgres struct {
+	db *sqlx.DB
+}
+
+// NewAPIKeyStorePostgres creates a new APIKeyStore for PostgreSQL
+func NewAPIKeyStorePostgres(db *sqlx.DB) store.APIKeyStore {
+	return &apiKeyStorePostgres{db: db}
+}
+
+func (s *apiKeyStorePostgres) querier(exec store.Querier) store.Querier {
+	if exec == nil {
+		return s.db
+	}
+	return exec
+}
+
+func (s *apiKeyStorePostgres) CreateAPIKey(ctx context.Context, exec store.Querier, key *models.APIKey) error {
+	if key.ID == uuid.Nil {
+		key.ID = uuid.New()
+	}
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now().UTC()
+	}
+	if key.Scopes == nil {
+		key.Scopes = pq.StringArray{}
+	}
+	query := `INSERT INTO auth.api_keys (id, user_id, label, key_hash, scopes, expires_at, revoked_at, created_at)
+			  VALUES (:id, :user_id, :label, :key_hash, :scopes, :expires_at, :revoked_at, :created_at)`
+	_, err := s.querier(exec).NamedExecContext(ctx, query, key)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // 23505 is unique_violation
+		return store.ErrDuplicateEntry
+	}
+	return err
+}
+
+func (s *apiKeyStorePostgres) GetAPIKeyByHash(ctx context.Context, exec store.Querier, keyHash string) (*models.APIKey, error) {
+	key := &models.APIKey{}
+	query := `SELECT k.id, k.user_id, k.label, k.key_hash, k.scopes, k.expires_at, k.revoked_at, k.created_at
+			  FROM auth.api_keys k JOIN auth.users u ON u.id = k.user_id
+			  WHERE k.key_hash = $1 AND u.is_active`
+	err := s.querier(exec).GetContext(ctx, key, query, keyHash)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *apiKeyStorePostgres) ListAPIKeys(ctx context.Context, exec store.Querier, userID uuid.UUID) ([]*models.APIKey, error) {
+	keys := []*models.APIKey{}
+	query := `SELECT id, user_id, label, key_hash, scopes, expires_at, revoked_at, created_at
+			  FROM auth.api_keys WHERE user_id = $1 ORDER BY created_at DESC, id`
+	if err := s.querier(exec).SelectContext(ctx, &keys, query, userID); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (s *apiKeyStorePostgres) RevokeAPIKey(ctx context.Context, exec store.Querier, userID, keyID uuid.UUID, revokedAt time.Time) error {
+	query := `UPDATE auth.api_keys SET revoked_at = $3 WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	result, err := s.querier(exec).ExecContext(ctx, query, keyID, userID, revokedAt)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
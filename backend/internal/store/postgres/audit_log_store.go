@@ -55,7 +55,54 @@ func (s *auditLogStorePostgres) CreateAuditLog(ctx context.Context, exec store.Q
 }
 
 func (s *auditLogStorePostgres) ListAuditLogs(ctx context.Context, exec store.Querier, filter store.ListAuditLogsFilter) ([]*models.AuditLog, error) {
-	baseQuery := `SELECT id, timestamp, user_id, action, entity_type, entity_id, details, client_ip, user_agent FROM audit_logs`
+	conditions, args := auditLogFilterConditions(filter)
+
+	finalQuery := auditLogSelect
+	if len(conditions) > 0 {
+		finalQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	finalQuery += " ORDER BY timestamp DESC"
+
+	if filter.Limit > 0 {
+		finalQuery += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+	if filter.Offset > 0 {
+		finalQuery += " OFFSET ?"
+		args = append(args, filter.Offset)
+	}
+
+	return selectAuditLogs(ctx, exec, finalQuery, args)
+}
+
+// ListAuditLogsAfter pages through the entries matching filter by keyset rather than offset, so
+// each page costs the same however deep into the range it is
+func (s *auditLogStorePostgres) ListAuditLogsAfter(ctx context.Context, exec store.Querier, filter store.ListAuditLogsFilter, after *store.AuditLogCursor) ([]*models.AuditLog, error) {
+	conditions, args := auditLogFilterConditions(filter)
+	if after != nil {
+		// The plain timestamp bound lets the timestamp index narrow the scan before the row comparison
+		conditions = append(conditions, "timestamp >= ?", "(timestamp, id) > (?, ?)")
+		args = append(args, after.Timestamp, after.Timestamp, after.ID)
+	}
+
+	finalQuery := auditLogSelect
+	if len(conditions) > 0 {
+		finalQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	finalQuery += " ORDER BY timestamp ASC, id ASC"
+	if filter.Limit > 0 {
+		finalQuery += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	return selectAuditLogs(ctx, exec, finalQuery, args)
+}
+
+const auditLogSelect = `SELECT id, timestamp, user_id, action, entity_type, entity_id, details, client_ip, user_agent FROM audit_logs`
+
+// auditLogFilterConditions turns a filter into WHERE conditions with ? placeholders
+func auditLogFilterConditions(filter store.ListAuditLogsFilter) ([]string, []interface{}) {
 	args := []interface{}{}
 	conditions := []string{}
 
@@ -84,35 +131,25 @@ func (s *auditLogStorePostgres) ListAuditLogs(ctx context.Context, exec store.Qu
 		conditions = append(conditions, "timestamp <= ?")
 		args = append(args, filter.EndDate)
 	}
+	return conditions, args
+}
 
-	finalQuery := baseQuery
-	if len(conditions) > 0 {
-		finalQuery += " WHERE " + strings.Join(conditions, " AND ")
-	}
-
-	finalQuery += " ORDER BY timestamp DESC"
-
-	if filter.Limit > 0 {
-		finalQuery += " LIMIT ?"
-		args = append(args, filter.Limit)
-	}
-	if filter.Offset > 0 {
-		finalQuery += " OFFSET ?"
-		args = append(args, filter.Offset)
-	}
-
+func selectAuditLogs(ctx context.Context, exec store.Querier, query string, args []interface{}) ([]*models.AuditLog, error) {
 	var reboundQuery string
 	switch q := exec.(type) {
 	case *sqlx.DB:
-		reboundQuery = q.Rebind(finalQuery)
+		reboundQuery = q.Rebind(query)
 	case *sqlx.Tx:
-		reboundQuery = q.Rebind(finalQuery)
+		reboundQuery = q.Rebind(query)
 	default:
 		return nil, fmt.Errorf("unexpected Querier type for Rebind: %T", exec)
 	}
 
 	logs := []*models.AuditLog{}
 	err := exec.SelectContext(ctx, &logs, reboundQuery, args...)
+	for _, logEntry := range logs {
+		logEntry.Details = models.ReadableAuditDetails(logEntry.Details)
+	}
 	return logs, err
 }
 
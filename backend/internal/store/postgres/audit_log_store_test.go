@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAuditLogsAfter(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	db := sqlx.NewDb(mockDB, "postgres")
+	auditLogStore := NewAuditLogStorePostgres(db)
+
+	columns := []string{"id", "timestamp", "user_id", "action", "entity_type", "entity_id", "details", "client_ip", "user_agent"}
+	endDate := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	filter := store.ListAuditLogsFilter{Action: "Login", EndDate: endDate, Limit: 2, Offset: 40}
+
+	// The first page starts at the oldest entry and ignores the offset
+	first, second := uuid.New(), uuid.New()
+	at := endDate.Add(-time.Hour)
+	mock.ExpectQuery(`FROM audit_logs WHERE action = \$1 AND timestamp <= \$2 ORDER BY timestamp ASC, id ASC LIMIT \$3$`).
+		WithArgs("Login", endDate, 2).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(first, at, nil, "Login", nil, nil, nil, nil, nil).
+			AddRow(second, at, nil, "Login", nil, nil, nil, nil, nil))
+	page, err := auditLogStore.ListAuditLogsAfter(context.Background(), db, filter, nil)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, first, page[0].ID)
+
+	// Later pages continue strictly after the last (timestamp, id) seen
+	mock.ExpectQuery(`FROM audit_logs WHERE action = \$1 AND timestamp <= \$2 AND timestamp >= \$3 AND \(timestamp, id\) > \(\$4, \$5\) ORDER BY timestamp ASC, id ASC LIMIT \$6$`).
+		WithArgs("Login", endDate, at, at, second, 2).
+		WillReturnRows(sqlmock.NewRows(columns))
+	page, err = auditLogStore.ListAuditLogsAfter(context.Background(), db, filter, &store.AuditLogCursor{Timestamp: at, ID: second})
+	require.NoError(t, err)
+	assert.Empty(t, page)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
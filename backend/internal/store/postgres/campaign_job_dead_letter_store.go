@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+const deadLetterJobColumns = `id, campaign_id, job_type, priority, job_payload, attempts, max_attempts, last_error,
+	attempt_history, processing_server_id, job_created_at, dead_lettered_at`
+
+// NewCampaignJobDeadLetterStorePostgres creates a CampaignJobDeadLetterStore for PostgreSQL. The
+// store returned by NewCampaignJobStorePostgres implements it too.
+func NewCampaignJobDeadLetterStorePostgres(db *sqlx.DB) store.CampaignJobDeadLetterStore {
+	return &campaignJobStorePostgres{db: db}
+}
+
+func (s *campaignJobStorePostgres) querier(exec store.Querier) store.Querier {
+	if exec != nil {
+		return exec
+	}
+	return s.db
+}
+
+// DeadLetterJob deletes the job from campaign_jobs and inserts it into campaign_jobs_dead_letter in
+// one statement, so a job is never in both or neither
+func (s *campaignJobStorePostgres) DeadLetterJob(ctx context.Context, exec store.Querier, job *models.CampaignJob) (*models.DeadLetterJob, error) {
+	var attemptHistory interface{}
+	if job.AttemptHistory != nil && len(*job.AttemptHistory) > 0 {
+		attemptHistory = string(*job.AttemptHistory)
+	}
+	query := `WITH moved AS (
+			DELETE FROM campaign_jobs WHERE id = $1
+			RETURNING id, campaign_id, job_type, priority, job_payload, created_at
+		)
+		INSERT INTO campaign_jobs_dead_letter (` + deadLetterJobColumns + `)
+		SELECT id, campaign_id, job_type, priority, job_payload, $2, $3, $4, $5, $6, created_at, NOW()
+		FROM moved
+		RETURNING ` + deadLetterJobColumns
+	deadLetter := &models.DeadLetterJob{}
+	err := s.querier(exec).GetContext(ctx, deadLetter, query,
+		job.ID, job.Attempts, job.MaxAttempts, job.LastError.String, attemptHistory, job.ProcessingServerID)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("pg: failed to dead-letter job %s: %w", job.ID, err)
+	}
+	return deadLetter, nil
+}
+
+func (s *campaignJobStorePostgres) ListDeadLetterJobs(ctx context.Context, exec store.Querier, filter store.ListDeadLetterJobsFilter) ([]*models.DeadLetterJob, error) {
+	query := `SELECT ` + deadLetterJobColumns + ` FROM campaign_jobs_dead_letter`
+	args := []interface{}{}
+	conditions := []string{}
+	if filter.CampaignID.Valid {
+		args = append(args, filter.CampaignID.UUID)
+		conditions = append(conditions, fmt.Sprintf("campaign_id = $%d", len(args)))
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY dead_lettered_at DESC, id"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	deadLetters := []*models.DeadLetterJob{}
+	if err := s.querier(exec).SelectContext(ctx, &deadLetters, query, args...); err != nil {
+		return nil, fmt.Errorf("pg: failed to list dead letter jobs: %w", err)
+	}
+	return deadLetters, nil
+}
+
+// ReplayDeadLetterJob deletes the dead letter and queues its job again in one statement. The
+// attempt history is kept, so it spans every attempt the job has had.
+func (s *campaignJobStorePostgres) ReplayDeadLetterJob(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.CampaignJob, error) {
+	query := `WITH replayed AS (
+			DELETE FROM campaign_jobs_dead_letter WHERE id = $1
+			RETURNING id, campaign_id, job_type, priority, job_payload, max_attempts, attempt_history
+		)
+		INSERT INTO campaign_jobs (id, campaign_id, job_type, status, priority, job_payload, attempts, max_attempts,
+			attempt_history, created_at, updated_at, scheduled_at, next_execution_at)
+		SELECT id, campaign_id, job_type, $2, priority, job_payload, 0, max_attempts,
+			attempt_history, NOW(), NOW(), NOW(), NOW()
+		FROM replayed
+		RETURNING id, campaign_id, job_type, status, priority, job_payload, attempts, max_attempts, last_error,
+			last_attempted_at, created_at, updated_at, scheduled_at, next_execution_at, processing_server_id,
+			locked_at, locked_by, attempt_history`
+	job := &models.CampaignJob{}
+	err := s.querier(exec).GetContext(ctx, job, query, id, models.JobStatusQueued)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("pg: failed to replay dead letter job %s: %w", id, err)
+	}
+	return job, nil
+}
+
+var _ store.CampaignJobDeadLetterStore = (*campaignJobStorePostgres)(nil)
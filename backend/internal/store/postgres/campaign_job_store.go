@@ -14,14 +14,50 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// campaignJobColumns are the campaign_jobs columns read into models.CampaignJob
+const campaignJobColumns = `id, campaign_id, job_type, status, priority, job_payload,
+				attempts, max_attempts, last_error, last_attempted_at, created_at, updated_at, scheduled_at,
+				next_execution_at, processing_server_id, locked_at, locked_by, attempt_history, last_heartbeat_at`
+
 // campaignJobStorePostgres implements store.CampaignJobStore for PostgreSQL
 type campaignJobStorePostgres struct {
-	db *sqlx.DB
+	db                 *sqlx.DB
+	agingInterval      time.Duration // Waiting time that raises a queued job's fetch priority by one; 0 disables aging
+	maxJobsPerCampaign int           // Jobs of one campaign processed at once; 0 is unlimited
+}
+
+// CampaignJobStoreOption configures optional behaviour of the PostgreSQL campaign job store
+type CampaignJobStoreOption func(*campaignJobStorePostgres)
+
+// WithPriorityAging raises a queued job's fetch priority by one for every interval it has waited.
+// Values <= 0 disable aging, so jobs are fetched by base priority alone.
+func WithPriorityAging(interval time.Duration) CampaignJobStoreOption {
+	return func(s *campaignJobStorePostgres) {
+		if interval < 0 {
+			interval = 0
+		}
+		s.agingInterval = interval
+	}
+}
+
+// WithMaxConcurrentJobsPerCampaign stops GetNextQueuedJob handing out a campaign's jobs while n of
+// them are processing. Values <= 0 leave campaigns unlimited.
+func WithMaxConcurrentJobsPerCampaign(n int) CampaignJobStoreOption {
+	return func(s *campaignJobStorePostgres) {
+		if n < 0 {
+			n = 0
+		}
+		s.maxJobsPerCampaign = n
+	}
 }
 
 // NewCampaignJobStorePostgres creates a new CampaignJobStore for PostgreSQL.
-func NewCampaignJobStorePostgres(db *sqlx.DB) store.CampaignJobStore {
-	return &campaignJobStorePostgres{db: db}
+func NewCampaignJobStorePostgres(db *sqlx.DB, opts ...CampaignJobStoreOption) store.CampaignJobStore {
+	s := &campaignJobStorePostgres{db: db}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // BeginTxx starts a new transaction.
@@ -51,6 +87,7 @@ func (s *campaignJobStorePostgres) CreateJob(ctx context.Context, exec store.Que
 		"campaign_id":          job.CampaignID,
 		"job_type":             string(job.JobType), // Changed from job.CampaignType
 		"status":               string(job.Status),
+		"priority":             job.Priority,
 		"job_payload":          nil, // Will be set below if payload exists
 		"attempts":             job.Attempts,
 		"max_attempts":         job.MaxAttempts,
@@ -69,10 +106,10 @@ func (s *campaignJobStorePostgres) CreateJob(ctx context.Context, exec store.Que
 	}
 
 	query := `INSERT INTO campaign_jobs
-			(id, campaign_id, job_type, status, job_payload, attempts, max_attempts, last_error, last_attempted_at,
+			(id, campaign_id, job_type, status, priority, job_payload, attempts, max_attempts, last_error, last_attempted_at,
 			 created_at, updated_at, scheduled_at, processing_server_id)
 		  VALUES
-			(:id, :campaign_id, :job_type, :status, :job_payload, :attempts, :max_attempts, :last_error, :last_attempted_at,
+			(:id, :campaign_id, :job_type, :status, :priority, :job_payload, :attempts, :max_attempts, :last_error, :last_attempted_at,
 			 :created_at, :updated_at, :scheduled_at, :processing_server_id)`
 
 	// Use the provided transaction if available, otherwise use the db connection
@@ -90,6 +127,7 @@ func (s *campaignJobStorePostgres) GetJobByID(ctx context.Context, jobID uuid.UU
 		CampaignID         uuid.UUID               `db:"campaign_id"`
 		JobType            models.CampaignTypeEnum `db:"job_type"` // Changed CampaignType to JobType and db tag
 		Status             string                  `db:"status"`
+		Priority           int                     `db:"priority"`
 		JobPayload         *json.RawMessage        `db:"job_payload"` // Changed Payload to JobPayload and db tag
 		Attempts           int                     `db:"attempts"`
 		MaxAttempts        int                     `db:"max_attempts"`
@@ -102,10 +140,12 @@ func (s *campaignJobStorePostgres) GetJobByID(ctx context.Context, jobID uuid.UU
 		NextExecutionAt    sql.NullTime            `db:"next_execution_at"`    // Kept for compatibility if used by GetNextQueuedJob logic
 		LockedAt           sql.NullTime            `db:"locked_at"`            // Added
 		LockedBy           sql.NullString          `db:"locked_by"`            // Added
+		AttemptHistory     *json.RawMessage        `db:"attempt_history"`
+		LastHeartbeatAt    sql.NullTime            `db:"last_heartbeat_at"`
 	}
 
 	dbj := &dbJob{}
-	query := `SELECT id, campaign_id, job_type, status, job_payload, attempts, max_attempts, last_error, last_attempted_at, created_at, updated_at, scheduled_at, processing_server_id, next_execution_at, locked_at, locked_by
+	query := `SELECT id, campaign_id, job_type, status, priority, job_payload, attempts, max_attempts, last_error, last_attempted_at, created_at, updated_at, scheduled_at, processing_server_id, next_execution_at, locked_at, locked_by, attempt_history, last_heartbeat_at
 			  FROM campaign_jobs WHERE id = $1`
 	err := s.db.GetContext(ctx, dbj, query, jobID)
 	if err == sql.ErrNoRows {
@@ -121,6 +161,7 @@ func (s *campaignJobStorePostgres) GetJobByID(ctx context.Context, jobID uuid.UU
 		CampaignID:         dbj.CampaignID,
 		JobType:            dbj.JobType, // Changed from CampaignType
 		Status:             models.CampaignJobStatusEnum(dbj.Status),
+		Priority:           dbj.Priority,
 		JobPayload:         dbj.JobPayload, // Changed from Payload
 		Attempts:           dbj.Attempts,
 		MaxAttempts:        dbj.MaxAttempts,
@@ -133,6 +174,8 @@ func (s *campaignJobStorePostgres) GetJobByID(ctx context.Context, jobID uuid.UU
 		NextExecutionAt:    dbj.NextExecutionAt,    // Keep if distinct logic needed
 		LockedAt:           dbj.LockedAt,           // Added
 		LockedBy:           dbj.LockedBy,           // Added
+		AttemptHistory:     dbj.AttemptHistory,
+		LastHeartbeatAt:    dbj.LastHeartbeatAt,
 	}
 
 	// Ensure ScheduledAt is valid if dbj.ScheduledAt was NULL
@@ -155,7 +198,8 @@ func (s *campaignJobStorePostgres) UpdateJob(ctx context.Context, exec store.Que
 				updated_at = :updated_at,
 				scheduled_at = :scheduled_at,
 				next_execution_at = :next_execution_at,
-				processing_server_id = :processing_server_id
+				processing_server_id = :processing_server_id,
+				attempt_history = :attempt_history
 				 WHERE id = :id`
 
 	// Use the provided transaction if available, otherwise use the db connection
@@ -174,6 +218,10 @@ func (s *campaignJobStorePostgres) UpdateJob(ctx context.Context, exec store.Que
 		"scheduled_at":         job.ScheduledAt,        // Use job.ScheduledAt directly
 		"next_execution_at":    job.NextExecutionAt,    // Added for retry scheduling
 		"processing_server_id": job.ProcessingServerID, // Changed from job.WorkerID
+		"attempt_history":      nil,
+	}
+	if job.AttemptHistory != nil && len(*job.AttemptHistory) > 0 {
+		jobData["attempt_history"] = string(*job.AttemptHistory)
 	}
 
 	// If JobPayload is not nil, set it as JSON string
@@ -203,83 +251,145 @@ func (s *campaignJobStorePostgres) UpdateJob(ctx context.Context, exec store.Que
 	return err
 }
 
+// GetNextQueuedJob claims the next queued job, or retry job that is due, for workerID. The job is
+// picked and set to processing in one statement; the pick locks its row and skips rows locked by
+// other claims, so concurrent workers never take the same job.
 func (s *campaignJobStorePostgres) GetNextQueuedJob(ctx context.Context, campaignTypes []models.CampaignTypeEnum, workerID string) (*models.CampaignJob, error) {
-	tx, err := s.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return nil, fmt.Errorf("pg: failed to begin transaction for GetNextQueuedJob: %w", err)
-	}
-	defer tx.Rollback()
-
 	now := time.Now().UTC()
-	selectArgs := []interface{}{models.JobStatusQueued, models.JobStatusRetry, now}
+	args := []interface{}{models.JobStatusQueued, models.JobStatusRetry, now}
 	// Check for both queued jobs AND retry jobs that are ready to be executed again
-	selectQuery := "SELECT id FROM campaign_jobs WHERE (status = $1 OR (status = $2 AND next_execution_at <= $3)) AND (scheduled_at IS NULL OR scheduled_at <= $3)"
+	selectQuery := "SELECT id FROM campaign_jobs candidate WHERE (status = $1 OR (status = $2 AND next_execution_at <= $3)) AND (scheduled_at IS NULL OR scheduled_at <= $3)"
 
 	if len(campaignTypes) > 0 {
 		var typePlaceholders []string
 		for _, ct := range campaignTypes {
-			typePlaceholders = append(typePlaceholders, fmt.Sprintf("$%d", len(selectArgs)+1))
-			selectArgs = append(selectArgs, string(ct))
+			typePlaceholders = append(typePlaceholders, fmt.Sprintf("$%d", len(args)+1))
+			args = append(args, string(ct))
 		}
 		selectQuery += fmt.Sprintf(" AND job_type IN (%s)", strings.Join(typePlaceholders, ","))
 	}
-	selectQuery += " ORDER BY COALESCE(scheduled_at, '1970-01-01'::timestamp) ASC, created_at ASC FOR UPDATE SKIP LOCKED LIMIT 1"
+	// Skip campaigns already at their share of the workers
+	if s.maxJobsPerCampaign > 0 {
+		args = append(args, models.JobStatusProcessing, s.maxJobsPerCampaign)
+		selectQuery += fmt.Sprintf(" AND (SELECT COUNT(*) FROM campaign_jobs running WHERE running.campaign_id = candidate.campaign_id AND running.status = $%d) < $%d",
+			len(args)-1, len(args))
+	}
+	// Higher effective priority first (see store.EffectiveJobPriority), then oldest first
+	priorityOrder := "priority"
+	if s.agingInterval > 0 {
+		args = append(args, s.agingInterval.Seconds())
+		priorityOrder = fmt.Sprintf("priority + GREATEST(FLOOR(EXTRACT(EPOCH FROM ($3 - COALESCE(scheduled_at, created_at))) / $%d), 0)", len(args))
+	}
+	selectQuery += " ORDER BY " + priorityOrder + " DESC, COALESCE(scheduled_at, '1970-01-01'::timestamp) ASC, created_at ASC FOR UPDATE SKIP LOCKED LIMIT 1"
+
+	args = append(args, models.JobStatusProcessing, workerID)
+	claimQuery := fmt.Sprintf(`UPDATE campaign_jobs SET
+				status = $%d,
+				processing_server_id = $%d,
+				updated_at = NOW(),
+				attempts = attempts + 1,
+				last_attempted_at = NOW(),
+				last_heartbeat_at = NOW(),
+				scheduled_at = COALESCE(scheduled_at, NOW())
+			  WHERE id = (%s)
+			  RETURNING `+campaignJobColumns,
+		len(args)-1, len(args), selectQuery)
+
+	if s.maxJobsPerCampaign <= 0 {
+		job := &models.CampaignJob{}
+		err := s.db.GetContext(ctx, job, claimQuery, args...)
+		if err == sql.ErrNoRows {
+			return nil, store.ErrNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("pg: failed to claim next queued job: %w", err)
+		}
+		return job, nil
+	}
 
-	var jobID uuid.UUID
-	err = tx.GetContext(ctx, &jobID, selectQuery, selectArgs...)
+	// The campaign's slot is checked after the claim, so claim and check share a transaction
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("pg: failed to begin transaction for GetNextQueuedJob: %w", err)
+	}
+	defer tx.Rollback()
+
+	job := &models.CampaignJob{}
+	err = tx.GetContext(ctx, job, claimQuery, args...)
 	if err == sql.ErrNoRows {
 		return nil, store.ErrNotFound
 	}
 	if err != nil {
-		return nil, fmt.Errorf("pg: failed to select next queued job: %w", err)
+		return nil, fmt.Errorf("pg: failed to claim next queued job: %w", err)
 	}
+	if err = s.checkCampaignSlot(ctx, tx, job.CampaignID); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("pg: failed to commit transaction for GetNextQueuedJob: %w", err)
+	}
+	return job, nil
+}
 
-	// First, update the job to mark it as processing
-	updateQuery := `UPDATE campaign_jobs SET 
-				status = $1, 
-				processing_server_id = $2, 
-				updated_at = NOW(), 
-				attempts = attempts + 1,
-				scheduled_at = COALESCE(scheduled_at, NOW())
-			  WHERE id = $3`
-
-	result, err := tx.ExecContext(ctx, updateQuery, models.JobStatusProcessing, workerID, jobID)
+// TouchJobHeartbeat records that workerID is still processing the job
+func (s *campaignJobStorePostgres) TouchJobHeartbeat(ctx context.Context, jobID uuid.UUID, workerID string) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE campaign_jobs SET last_heartbeat_at = NOW()
+		WHERE id = $1 AND status = $2 AND processing_server_id = $3`, jobID, models.JobStatusProcessing, workerID)
 	if err != nil {
-		return nil, fmt.Errorf("pg: failed to update job %s: %w", jobID, err)
+		return fmt.Errorf("pg: failed to record heartbeat of job %s: %w", jobID, err)
 	}
-
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return nil, fmt.Errorf("pg: failed to get rows affected for job %s: %w", jobID, err)
+		return fmt.Errorf("pg: failed to get rows affected for job %s: %w", jobID, err)
 	}
 	if rowsAffected == 0 {
-		return nil, store.ErrNotFound
+		return store.ErrNotFound
 	}
+	return nil
+}
 
-	// Then, fetch the full job details
-	job := &models.CampaignJob{} // This will be populated by GetContext
-	fetchQuery := `SELECT id, campaign_id, job_type, status, job_payload,
-					attempts, max_attempts, last_error, last_attempted_at, created_at, updated_at, scheduled_at,
-					next_execution_at, -- Assuming next_execution_at is a distinct column or handled by COALESCE if needed
-					processing_server_id, locked_at, locked_by
-			  FROM campaign_jobs
-			  WHERE id = $1`
-	// sqlx.GetContext will map columns to struct fields based on db tags in models.CampaignJob
-	err = tx.GetContext(ctx, job, fetchQuery, jobID)
+// ReapStaleJobs sets processing jobs without a heartbeat since staleBefore back to retry, due at
+// once. Jobs claimed before heartbeats were recorded count from their last attempt. Rows another
+// transaction holds are skipped, so reapers on several servers do not reap a job twice.
+func (s *campaignJobStorePostgres) ReapStaleJobs(ctx context.Context, staleBefore time.Time, limit int) ([]*models.CampaignJob, error) {
+	query := `UPDATE campaign_jobs SET
+				status = $1,
+				next_execution_at = NOW(),
+				updated_at = NOW(),
+				last_error = $2
+			  WHERE id IN (
+				SELECT id FROM campaign_jobs
+				WHERE status = $3 AND COALESCE(last_heartbeat_at, last_attempted_at, updated_at) < $4
+				ORDER BY COALESCE(last_heartbeat_at, last_attempted_at, updated_at) ASC
+				LIMIT $5 FOR UPDATE SKIP LOCKED)
+			  RETURNING ` + campaignJobColumns
+	jobs := []*models.CampaignJob{}
+	err := s.db.SelectContext(ctx, &jobs, query, models.JobStatusRetry, store.StaleJobError, models.JobStatusProcessing, staleBefore, limit)
 	if err != nil {
-		return nil, fmt.Errorf("pg: failed to fetch updated job %s: %w", jobID, err)
+		return nil, fmt.Errorf("pg: failed to reap stale jobs: %w", err)
 	}
-	// No need to manually set job.ScheduledAt if it's correctly fetched via db tag
-	// and dbJob struct is not used for this final fetch.
-	// If job.ScheduledAt can be NULL in DB and needs a default in Go model if NULL,
-	// that logic would be here or in the model itself.
-	// For now, assume direct mapping is sufficient.
+	return jobs, nil
+}
 
-	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("pg: failed to commit transaction for GetNextQueuedJob: %w", err)
+// checkCampaignSlot makes sure a job just claimed in tx leaves campaignID within its limit.
+// Claims of the same campaign are serialized on a transaction-scoped advisory lock, so the count
+// below sees every claim committed before it and two workers cannot both take the campaign's last
+// slot. When the campaign filled up after the job was picked, store.ErrNotFound is returned, the
+// claim is rolled back and the worker polls again.
+func (s *campaignJobStorePostgres) checkCampaignSlot(ctx context.Context, tx *sqlx.Tx, campaignID uuid.UUID) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1::text))`, campaignID); err != nil {
+		return fmt.Errorf("pg: failed to lock job claims of campaign %s: %w", campaignID, err)
+	}
+	var processing int
+	if err := tx.GetContext(ctx, &processing, `SELECT COUNT(*) FROM campaign_jobs WHERE campaign_id = $1 AND status = $2`,
+		campaignID, models.JobStatusProcessing); err != nil {
+		return fmt.Errorf("pg: failed to count processing jobs of campaign %s: %w", campaignID, err)
+	}
+	// The count includes the job claimed in tx
+	if processing > s.maxJobsPerCampaign {
+		return store.ErrNotFound
 	}
-
-	return job, nil
+	return nil
 }
 
 func (s *campaignJobStorePostgres) DeleteJob(ctx context.Context, jobID uuid.UUID) error {
@@ -296,28 +406,15 @@ func (s *campaignJobStorePostgres) DeleteJob(ctx context.Context, jobID uuid.UUI
 }
 
 func (s *campaignJobStorePostgres) ListJobs(ctx context.Context, filter store.ListJobsFilter) ([]*models.CampaignJob, error) {
-	baseQuery := `SELECT id, campaign_id, job_type, status, job_payload, attempts, max_attempts, last_error, created_at, updated_at, scheduled_at, scheduled_at as next_execution_at, processing_server_id, locked_at, locked_by FROM campaign_jobs`
-	args := []interface{}{}
-	conditions := []string{}
-
-	if filter.CampaignID.Valid {
-		conditions = append(conditions, "campaign_id = ?")
-		args = append(args, filter.CampaignID.UUID)
-	}
-	if filter.CampaignType != "" {
-		conditions = append(conditions, "job_type = ?")
-		args = append(args, filter.CampaignType)
-	}
-	if filter.Status != "" {
-		conditions = append(conditions, "status = ?")
-		args = append(args, filter.Status)
-	}
+	baseQuery := `SELECT id, campaign_id, job_type, status, priority, job_payload, attempts, max_attempts, last_error, last_attempted_at, created_at, updated_at, scheduled_at, scheduled_at as next_execution_at, processing_server_id, locked_at, locked_by FROM campaign_jobs`
+	conditions, args := jobFilterConditions(filter)
 
 	if len(conditions) > 0 {
 		baseQuery += " WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	baseQuery += " ORDER BY created_at DESC"
+	// id breaks ties between jobs created in the same instant so pages neither repeat nor skip jobs
+	baseQuery += " ORDER BY created_at DESC, id DESC"
 
 	if filter.Limit > 0 {
 		baseQuery += fmt.Sprintf(" LIMIT %d", filter.Limit)
@@ -338,4 +435,37 @@ func (s *campaignJobStorePostgres) ListJobs(ctx context.Context, filter store.Li
 	return jobs, nil
 }
 
+func (s *campaignJobStorePostgres) CountJobs(ctx context.Context, filter store.ListJobsFilter) (int64, error) {
+	query := `SELECT COUNT(*) FROM campaign_jobs`
+	conditions, args := jobFilterConditions(filter)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var count int64
+	if err := s.db.GetContext(ctx, &count, s.db.Rebind(query), args...); err != nil {
+		return 0, fmt.Errorf("pg: failed to count jobs: %w", err)
+	}
+	return count, nil
+}
+
+// jobFilterConditions returns the WHERE conditions and arguments shared by ListJobs and CountJobs
+func jobFilterConditions(filter store.ListJobsFilter) ([]string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+	if filter.CampaignID.Valid {
+		conditions = append(conditions, "campaign_id = ?")
+		args = append(args, filter.CampaignID.UUID)
+	}
+	if filter.CampaignType != "" {
+		conditions = append(conditions, "job_type = ?")
+		args = append(args, filter.CampaignType)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	return conditions, args
+}
+
 var _ store.CampaignJobStore = (*campaignJobStorePostgres)(nil)
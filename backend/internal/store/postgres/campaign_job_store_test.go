@@ -0,0 +1,244 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const claimQueryPattern = `UPDATE campaign_jobs SET\s+status = \$\d+,\s+processing_server_id = \$\d+,.*WHERE id = \(SELECT id FROM campaign_jobs candidate WHERE .* FOR UPDATE SKIP LOCKED LIMIT 1\)\s+RETURNING id, campaign_id`
+
+// expectJobClaim registers one GetNextQueuedJob transaction that claims jobID of campaignID and
+// then finds processing of the campaign's jobs running, itself included; the claim is kept only
+// when that is within limit
+func expectJobClaim(mock sqlmock.Sqlmock, jobID, campaignID uuid.UUID, processing, limit int) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(claimQueryPattern).
+		WithArgs(models.JobStatusQueued, models.JobStatusRetry, sqlmock.AnyArg(), models.JobStatusProcessing, limit, models.JobStatusProcessing, "worker").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "campaign_id", "job_type", "status"}).
+			AddRow(jobID, campaignID, models.CampaignTypeHTTPKeywordValidation, models.JobStatusProcessing))
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtext\(\$1::text\)\)`).
+		WithArgs(campaignID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM campaign_jobs WHERE campaign_id = \$1 AND status = \$2`).
+		WithArgs(campaignID, models.JobStatusProcessing).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(processing))
+	if processing > limit {
+		mock.ExpectRollback()
+		return
+	}
+	mock.ExpectCommit()
+}
+
+func TestGetNextQueuedJob_ClaimsInOneStatement(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	jobStore := NewCampaignJobStorePostgres(sqlx.NewDb(mockDB, "postgres"), WithMaxConcurrentJobsPerCampaign(-1))
+
+	jobID, campaignID := uuid.New(), uuid.New()
+	mock.ExpectQuery(claimQueryPattern).
+		WithArgs(models.JobStatusQueued, models.JobStatusRetry, sqlmock.AnyArg(), models.JobStatusProcessing, "worker").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "campaign_id", "status", "processing_server_id"}).
+			AddRow(jobID, campaignID, models.JobStatusProcessing, "worker"))
+	job, err := jobStore.GetNextQueuedJob(context.Background(), nil, "worker")
+	require.NoError(t, err)
+	assert.Equal(t, jobID, job.ID)
+	assert.Equal(t, "worker", job.ProcessingServerID.String)
+
+	mock.ExpectQuery(claimQueryPattern).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = jobStore.GetNextQueuedJob(context.Background(), nil, "worker")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetNextQueuedJob_LimitsJobsPerCampaign(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	jobStore := NewCampaignJobStorePostgres(sqlx.NewDb(mockDB, "postgres"), WithMaxConcurrentJobsPerCampaign(1))
+	ctx := context.Background()
+
+	// A large campaign queued ahead of a small one, and a pool of two workers
+	large, small := uuid.New(), uuid.New()
+	expectJobClaim(mock, uuid.New(), large, 1, 1)
+	job, err := jobStore.GetNextQueuedJob(ctx, nil, "worker")
+	require.NoError(t, err)
+	assert.Equal(t, large, job.CampaignID)
+
+	// The large campaign is at its limit, so the query hands the second worker the small campaign's job
+	expectJobClaim(mock, uuid.New(), small, 1, 1)
+	job, err = jobStore.GetNextQueuedJob(ctx, nil, "worker")
+	require.NoError(t, err)
+	assert.Equal(t, small, job.CampaignID)
+
+	// A claim racing a committed claim of the same campaign is rolled back once the count under the lock shows the campaign full
+	expectJobClaim(mock, uuid.New(), large, 2, 1)
+	_, err = jobStore.GetNextQueuedJob(ctx, nil, "worker")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// queueTestJobs creates a pending campaign with n queued jobs in testDB
+func queueTestJobs(t *testing.T, jobStore store.CampaignJobStore, n int) uuid.UUID {
+	t.Helper()
+	ctx := context.Background()
+	campaign := &models.Campaign{
+		ID:           uuid.New(),
+		Name:         "Job claims " + uuid.NewString(),
+		CampaignType: models.CampaignTypeDNSValidation,
+		Status:       models.CampaignStatusRunning,
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+	}
+	require.NoError(t, NewCampaignStorePostgres(testDB).CreateCampaign(ctx, nil, campaign))
+	t.Cleanup(func() {
+		testDB.Exec(`DELETE FROM campaigns WHERE id = $1`, campaign.ID)
+	})
+	for i := 0; i < n; i++ {
+		require.NoError(t, jobStore.CreateJob(ctx, nil, &models.CampaignJob{
+			CampaignID:  campaign.ID,
+			JobType:     models.CampaignTypeDNSValidation,
+			Status:      models.JobStatusQueued,
+			MaxAttempts: 3,
+		}))
+	}
+	return campaign.ID
+}
+
+// claimConcurrently has workers claim jobs until none is left and returns the worker that claimed each job
+func claimConcurrently(t *testing.T, jobStore store.CampaignJobStore, workers int) map[uuid.UUID][]string {
+	t.Helper()
+	var mu sync.Mutex
+	claims := make(map[uuid.UUID][]string)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(workerID string) {
+			defer wg.Done()
+			for {
+				job, err := jobStore.GetNextQueuedJob(context.Background(), []models.CampaignTypeEnum{models.CampaignTypeDNSValidation}, workerID)
+				if errors.Is(err, store.ErrNotFound) {
+					return
+				}
+				if !assert.NoError(t, err) {
+					return
+				}
+				mu.Lock()
+				claims[job.ID] = append(claims[job.ID], workerID)
+				mu.Unlock()
+			}
+		}(uuid.NewString())
+	}
+	wg.Wait()
+	return claims
+}
+
+func TestGetNextQueuedJob_ConcurrentWorkers(t *testing.T) {
+	require.NotNil(t, testDB, "testDB is nil. Ensure TestMain ran or TEST_POSTGRES_DSN is set.")
+	jobStore := NewCampaignJobStorePostgres(testDB)
+	const jobs = 40
+	campaignID := queueTestJobs(t, jobStore, jobs)
+
+	claims := claimConcurrently(t, jobStore, 8)
+	require.Len(t, claims, jobs)
+	for jobID, workers := range claims {
+		require.Len(t, workers, 1, "job %s was claimed more than once", jobID)
+		job, err := jobStore.GetJobByID(context.Background(), jobID)
+		require.NoError(t, err)
+		assert.Equal(t, campaignID, job.CampaignID)
+		assert.Equal(t, models.JobStatusProcessing, job.Status)
+		assert.Equal(t, workers[0], job.ProcessingServerID.String)
+		assert.Equal(t, 1, job.Attempts)
+	}
+}
+
+func TestGetNextQueuedJob_ConcurrentWorkersKeepCampaignLimit(t *testing.T) {
+	require.NotNil(t, testDB, "testDB is nil. Ensure TestMain ran or TEST_POSTGRES_DSN is set.")
+	const limit = 2
+	jobStore := NewCampaignJobStorePostgres(testDB, WithMaxConcurrentJobsPerCampaign(limit))
+	large := queueTestJobs(t, jobStore, 20)
+	small := queueTestJobs(t, jobStore, 3)
+
+	// Claimed jobs stay processing, so each campaign stops at its limit however many workers compete
+	claims := claimConcurrently(t, jobStore, 6)
+	perCampaign := make(map[uuid.UUID]int)
+	for jobID, workers := range claims {
+		require.Len(t, workers, 1, "job %s was claimed more than once", jobID)
+		job, err := jobStore.GetJobByID(context.Background(), jobID)
+		require.NoError(t, err)
+		perCampaign[job.CampaignID]++
+	}
+	assert.Equal(t, map[uuid.UUID]int{large: limit, small: limit}, perCampaign)
+}
+
+func TestReapStaleJobs_RequeuesProcessingJobsWithoutHeartbeat(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	jobStore := NewCampaignJobStorePostgres(sqlx.NewDb(mockDB, "postgres"))
+
+	staleBefore := time.Now().UTC().Add(-15 * time.Minute)
+	jobID := uuid.New()
+	mock.ExpectQuery(`UPDATE campaign_jobs SET\s+status = \$1,.*WHERE id IN \(\s+SELECT id FROM campaign_jobs\s+WHERE status = \$3 AND COALESCE\(last_heartbeat_at, last_attempted_at, updated_at\) < \$4.*LIMIT \$5 FOR UPDATE SKIP LOCKED\)\s+RETURNING id`).
+		WithArgs(models.JobStatusRetry, store.StaleJobError, models.JobStatusProcessing, staleBefore, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "processing_server_id"}).AddRow(jobID, models.JobStatusRetry, "worker-crashed"))
+
+	jobs, err := jobStore.ReapStaleJobs(context.Background(), staleBefore, 100)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, jobID, jobs[0].ID)
+	assert.Equal(t, "worker-crashed", jobs[0].ProcessingServerID.String, "the reaped job still names the worker that abandoned it")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTouchJobHeartbeat_OnlyForTheClaimingWorker(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	jobStore := NewCampaignJobStorePostgres(sqlx.NewDb(mockDB, "postgres"))
+	jobID := uuid.New()
+
+	touch := `UPDATE campaign_jobs SET last_heartbeat_at = NOW\(\)\s+WHERE id = \$1 AND status = \$2 AND processing_server_id = \$3`
+	mock.ExpectExec(touch).WithArgs(jobID, models.JobStatusProcessing, "worker-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(touch).WithArgs(jobID, models.JobStatusProcessing, "worker-2").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	require.NoError(t, jobStore.TouchJobHeartbeat(context.Background(), jobID, "worker-1"))
+	assert.ErrorIs(t, jobStore.TouchJobHeartbeat(context.Background(), jobID, "worker-2"), store.ErrNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListJobs_OrdersByCreationThenIDAndCountsSameFilter(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	jobStore := NewCampaignJobStorePostgres(sqlx.NewDb(mockDB, "postgres"))
+
+	campaignID := uuid.New()
+	filter := store.ListJobsFilter{CampaignID: uuid.NullUUID{UUID: campaignID, Valid: true}, Limit: 20, Offset: 40}
+	mock.ExpectQuery(`FROM campaign_jobs WHERE campaign_id = \$1 ORDER BY created_at DESC, id DESC LIMIT 20 OFFSET 40$`).
+		WithArgs(campaignID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "campaign_id"}).AddRow(uuid.New(), campaignID))
+	mock.ExpectQuery(`^SELECT COUNT\(\*\) FROM campaign_jobs WHERE campaign_id = \$1$`).
+		WithArgs(campaignID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(41))
+
+	jobs, err := jobStore.ListJobs(context.Background(), filter)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+	count, err := jobStore.CountJobs(context.Background(), filter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(41), count)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // Imported for pq.Error
+)
+
+// campaignListViewStorePostgres implements the store.CampaignListViewStore interface
+type campaignListViewStorePostgres struct {
+	db *sqlx.DB
+}
+
+// NewCampaignListViewStorePostgres creates a new CampaignListViewStore for PostgreSQL
+func NewCampaignListViewStorePostgres(db *sqlx.DB) store.CampaignListViewStore {
+	return &campaignListViewStorePostgres{db: db}
+}
+
+func (s *campaignListViewStorePostgres) querier(exec store.Querier) store.Querier {
+	if exec == nil {
+		return s.db
+	}
+	return exec
+}
+
+func (s *campaignListViewStorePostgres) CreateCampaignListView(ctx context.Context, exec store.Querier, view *models.CampaignListView) error {
+	if view.ID == uuid.Nil {
+		view.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if view.CreatedAt.IsZero() {
+		view.CreatedAt = now
+	}
+	view.UpdatedAt = now
+	query := `INSERT INTO campaign_list_views (id, user_id, name, status, campaign_type, sort_by, sort_order, page_size, created_at, updated_at)
+			  VALUES (:id, :user_id, :name, :status, :campaign_type, :sort_by, :sort_order, :page_size, :created_at, :updated_at)`
+	_, err := s.querier(exec).NamedExecContext(ctx, query, view)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // 23505 is unique_violation
+			return store.ErrDuplicateEntry
+		}
+	}
+	return err
+}
+
+func (s *campaignListViewStorePostgres) GetCampaignListViewByName(ctx context.Context, exec store.Querier, userID uuid.UUID, name string) (*models.CampaignListView, error) {
+	view := &models.CampaignListView{}
+	query := `SELECT id, user_id, name, status, campaign_type, sort_by, sort_order, page_size, created_at, updated_at
+			  FROM campaign_list_views WHERE user_id = $1 AND name = $2`
+	err := s.querier(exec).GetContext(ctx, view, query, userID, name)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	return view, err
+}
+
+func (s *campaignListViewStorePostgres) ListCampaignListViews(ctx context.Context, exec store.Querier, userID uuid.UUID) ([]*models.CampaignListView, error) {
+	views := []*models.CampaignListView{}
+	query := `SELECT id, user_id, name, status, campaign_type, sort_by, sort_order, page_size, created_at, updated_at
+			  FROM campaign_list_views WHERE user_id = $1 ORDER BY name ASC`
+	err := s.querier(exec).SelectContext(ctx, &views, query, userID)
+	return views, err
+}
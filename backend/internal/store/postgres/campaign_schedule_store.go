@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+const campaignScheduleColumns = `id, campaign_id, run_at, cron_expression, next_run_at, locked_until,
+			  last_run_at, last_campaign_id, last_error, created_by, created_at, updated_at`
+
+// campaignScheduleStorePostgres implements the store.CampaignScheduleStore interface
+type campaignScheduleStorePostgres struct {
+	db *sqlx.DB
+}
+
+// NewCampaignScheduleStorePostgres creates a new CampaignScheduleStore for PostgreSQL
+func NewCampaignScheduleStorePostgres(db *sqlx.DB) store.CampaignScheduleStore {
+	return &campaignScheduleStorePostgres{db: db}
+}
+
+func (s *campaignScheduleStorePostgres) querier(exec store.Querier) store.Querier {
+	if exec == nil {
+		return s.db
+	}
+	return exec
+}
+
+func (s *campaignScheduleStorePostgres) UpsertCampaignSchedule(ctx context.Context, exec store.Querier, schedule *models.CampaignSchedule) error {
+	if schedule.ID == uuid.Nil {
+		schedule.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if schedule.CreatedAt.IsZero() {
+		schedule.CreatedAt = now
+	}
+	schedule.UpdatedAt = now
+	query := `INSERT INTO campaign_schedules (id, campaign_id, run_at, cron_expression, next_run_at, created_by, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			  ON CONFLICT (campaign_id) DO UPDATE SET
+				  run_at = EXCLUDED.run_at,
+				  cron_expression = EXCLUDED.cron_expression,
+				  next_run_at = EXCLUDED.next_run_at,
+				  locked_until = NULL,
+				  created_by = EXCLUDED.created_by,
+				  updated_at = EXCLUDED.updated_at
+			  RETURNING ` + campaignScheduleColumns
+	return s.querier(exec).GetContext(ctx, schedule, query, schedule.ID, schedule.CampaignID, schedule.RunAt,
+		schedule.CronExpression, schedule.NextRunAt, schedule.CreatedBy, schedule.CreatedAt, schedule.UpdatedAt)
+}
+
+func (s *campaignScheduleStorePostgres) GetCampaignScheduleByCampaignID(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.CampaignSchedule, error) {
+	schedule := &models.CampaignSchedule{}
+	query := `SELECT ` + campaignScheduleColumns + ` FROM campaign_schedules WHERE campaign_id = $1`
+	err := s.querier(exec).GetContext(ctx, schedule, query, campaignID)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *campaignScheduleStorePostgres) DeleteCampaignSchedule(ctx context.Context, exec store.Querier, campaignID uuid.UUID) error {
+	result, err := s.querier(exec).ExecContext(ctx, `DELETE FROM campaign_schedules WHERE campaign_id = $1`, campaignID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err == nil && rowsAffected == 0 {
+		return store.ErrNotFound
+	}
+	return err
+}
+
+func (s *campaignScheduleStorePostgres) ClaimDueCampaignSchedules(ctx context.Context, exec store.Querier, now, lockedUntil time.Time, limit int) ([]*models.CampaignSchedule, error) {
+	schedules := []*models.CampaignSchedule{}
+	query := `UPDATE campaign_schedules SET locked_until = $2
+			  WHERE id IN (
+				  SELECT id FROM campaign_schedules
+				  WHERE next_run_at <= $1 AND (locked_until IS NULL OR locked_until <= $1)
+				  ORDER BY next_run_at ASC
+				  LIMIT $3
+				  FOR UPDATE SKIP LOCKED
+			  )
+			  RETURNING ` + campaignScheduleColumns
+	if err := s.querier(exec).SelectContext(ctx, &schedules, query, now, lockedUntil, limit); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (s *campaignScheduleStorePostgres) RecordCampaignScheduleRun(ctx context.Context, exec store.Querier, schedule *models.CampaignSchedule) (bool, error) {
+	query := `UPDATE campaign_schedules
+			  SET next_run_at = $3, last_run_at = $4, last_campaign_id = $5, last_error = $6, locked_until = NULL, updated_at = NOW()
+			  WHERE id = $1 AND locked_until = $2`
+	result, err := s.querier(exec).ExecContext(ctx, query, schedule.ID, schedule.LockedUntil, schedule.NextRunAt,
+		schedule.LastRunAt, schedule.LastCampaignID, schedule.LastError)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+var _ store.CampaignScheduleStore = (*campaignScheduleStorePostgres)(nil)
@@ -6,9 +6,11 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"log"
 	"strings" // For ListCampaigns dynamic query
 	"time"
 
+	"github.com/fntelecomllc/studio/backend/internal/circuitbreaker"
 	"github.com/fntelecomllc/studio/backend/internal/models"
 	"github.com/fntelecomllc/studio/backend/internal/store"
 
@@ -17,14 +19,125 @@ import (
 	"github.com/lib/pq" // For pq.Array if needed for array types, and for error checking
 )
 
+// DefaultResultCommitChunkSize is the number of validation result rows committed per transaction
+// when results are written outside a caller-supplied transaction.
+const DefaultResultCommitChunkSize = 500
+
 // campaignStorePostgres implements the store.CampaignStore interface for PostgreSQL
 type campaignStorePostgres struct {
-	db *sqlx.DB
+	db                    *sqlx.DB
+	resultCommitChunkSize int
+	uniqueNames           bool                // Reject a campaign name its user already has, ignoring case
+	reads                 *ReadRoutingQuerier // Serves listings, status counts and results when a read replica is configured
+}
+
+// CampaignStoreOption configures optional behaviour of the PostgreSQL campaign store
+type CampaignStoreOption func(*campaignStorePostgres)
+
+// WithResultCommitChunkSize sets how many validation result rows are committed per transaction.
+// Values <= 0 keep DefaultResultCommitChunkSize.
+func WithResultCommitChunkSize(size int) CampaignStoreOption {
+	return func(s *campaignStorePostgres) {
+		if size > 0 {
+			s.resultCommitChunkSize = size
+		}
+	}
+}
+
+// WithUniqueCampaignNames makes creating or renaming a campaign fail with store.ErrCampaignNameTaken
+// when its user already has a campaign of that name, compared ignoring case. Campaigns without a
+// user are not checked.
+func WithUniqueCampaignNames(enabled bool) CampaignStoreOption {
+	return func(s *campaignStorePostgres) {
+		s.uniqueNames = enabled
+	}
+}
+
+// WithReadReplica routes listings, status counts and result pages that are not part of a
+// transaction to replica, falling back to the primary while the replica fails. A nil replica keeps
+// every query on the primary.
+func WithReadReplica(replica *sqlx.DB, retryInterval time.Duration) CampaignStoreOption {
+	return func(s *campaignStorePostgres) {
+		s.reads = NewReadRoutingQuerier(s.db, replica, retryInterval)
+	}
 }
 
 // NewCampaignStorePostgres creates a new CampaignStore for PostgreSQL
-func NewCampaignStorePostgres(db *sqlx.DB) store.CampaignStore {
-	return &campaignStorePostgres{db: db}
+func NewCampaignStorePostgres(db *sqlx.DB, opts ...CampaignStoreOption) store.CampaignStore {
+	s := &campaignStorePostgres{db: db, resultCommitChunkSize: DefaultResultCommitChunkSize}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// execInChunks writes total rows through write, one chunk of rows per call.
+// A caller-supplied transaction keeps every row inside it, so the caller still decides when to commit.
+// Otherwise each chunk is committed in its own transaction so progress is durable and row locks are
+// held briefly; a failure part-way through is reported as a *store.ChunkedWriteError.
+func (s *campaignStorePostgres) execInChunks(ctx context.Context, exec store.Querier, total int, write func(q store.Querier, start, end int) error) error {
+	var db *sqlx.DB
+	switch q := exec.(type) {
+	case nil:
+		db = s.db
+	case *sqlx.DB:
+		db = q
+	default:
+		return write(exec, 0, total)
+	}
+	if db == nil {
+		return fmt.Errorf("both exec and internal db connection are nil")
+	}
+
+	chunkSize := s.resultCommitChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultResultCommitChunkSize
+	}
+	totalChunks := (total + chunkSize - 1) / chunkSize
+	committedChunks, committedRows := 0, 0
+	chunkErr := func(err error) error {
+		return &store.ChunkedWriteError{
+			CommittedChunks: committedChunks,
+			TotalChunks:     totalChunks,
+			CommittedRows:   committedRows,
+			Err:             err,
+		}
+	}
+
+	for start := 0; start < total; start += chunkSize {
+		end := start + chunkSize
+		if end > total {
+			end = total
+		}
+		tx, err := db.BeginTxx(ctx, nil)
+		if err != nil {
+			return chunkErr(fmt.Errorf("begin chunk transaction: %w", err))
+		}
+		if err := write(tx, start, end); err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("execInChunks: rollback of chunk %d/%d failed: %v", committedChunks+1, totalChunks, rbErr)
+			}
+			return chunkErr(err)
+		}
+		if err := tx.Commit(); err != nil {
+			return chunkErr(fmt.Errorf("commit chunk transaction: %w", err))
+		}
+		committedChunks++
+		committedRows += end - start
+	}
+	return nil
+}
+
+// reader returns the querier for a read that may be served by the replica. Transactions and
+// queriers other than the store's own connection are used as they are.
+func (s *campaignStorePostgres) reader(exec store.Querier) store.Querier {
+	if exec == nil || exec == store.Querier(s.db) {
+		if s.reads != nil {
+			return s.reads
+		}
+		return s.db
+	}
+	return exec
 }
 
 // BeginTxx starts a new transaction.
@@ -35,10 +148,25 @@ func (s *campaignStorePostgres) BeginTxx(ctx context.Context, opts *sql.TxOption
 // --- Campaign CRUD --- //
 
 func (s *campaignStorePostgres) CreateCampaign(ctx context.Context, exec store.Querier, campaign *models.Campaign) error {
+	if s.uniqueNames && campaign.UserID != nil {
+		// Serialises concurrent creates of the same name by the same user until the transaction ends
+		if _, err := exec.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1::text || '/' || lower($2)))`, *campaign.UserID, campaign.Name); err != nil {
+			return fmt.Errorf("pg: failed to lock campaign name: %w", err)
+		}
+		var taken bool
+		if err := exec.GetContext(ctx, &taken, `SELECT EXISTS (SELECT 1 FROM campaigns WHERE user_id = $1 AND lower(name) = lower($2))`, *campaign.UserID, campaign.Name); err != nil {
+			return fmt.Errorf("pg: failed to check campaign name: %w", err)
+		}
+		if taken {
+			return store.ErrCampaignNameTaken
+		}
+	}
 	query := `INSERT INTO campaigns (id, name, campaign_type, status, user_id, created_at, updated_at,
-							 started_at, completed_at, progress_percentage, total_items, processed_items, successful_items, failed_items, metadata, error_message)
+							 started_at, completed_at, progress_percentage, total_items, processed_items, successful_items, failed_items, metadata, error_message, tags,
+							 auto_retry, retry_count, retry_of_campaign_id, promoted_from_campaign_id)
 			  VALUES (:id, :name, :campaign_type, :status, :user_id, :created_at, :updated_at,
-					  :started_at, :completed_at, :progress_percentage, :total_items, :processed_items, :successful_items, :failed_items, :metadata, :error_message)`
+					  :started_at, :completed_at, :progress_percentage, :total_items, :processed_items, :successful_items, :failed_items, :metadata, :error_message, COALESCE(:tags, '{}'::text[]),
+					  :auto_retry, :retry_count, :retry_of_campaign_id, :promoted_from_campaign_id)`
 	_, err := exec.NamedExecContext(ctx, query, campaign)
 	return err
 }
@@ -46,7 +174,8 @@ func (s *campaignStorePostgres) CreateCampaign(ctx context.Context, exec store.Q
 func (s *campaignStorePostgres) GetCampaignByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Campaign, error) {
 	campaign := &models.Campaign{}
 	query := `SELECT id, name, campaign_type, status, user_id, created_at, updated_at,
-					 started_at, completed_at, progress_percentage, total_items, processed_items, successful_items, failed_items, metadata, error_message
+					 started_at, completed_at, progress_percentage, total_items, processed_items, successful_items, failed_items, metadata, error_message, tags,
+					 auto_retry, retry_count, retry_of_campaign_id, promoted_from_campaign_id
 			  FROM campaigns WHERE id = $1`
 	err := exec.GetContext(ctx, campaign, query, id)
 	if err == sql.ErrNoRows {
@@ -60,14 +189,30 @@ func (s *campaignStorePostgres) UpdateCampaign(ctx context.Context, exec store.Q
 				name = :name, campaign_type = :campaign_type, status = :status, user_id = :user_id,
 				updated_at = :updated_at, started_at = :started_at, completed_at = :completed_at,
 				progress_percentage = :progress_percentage, total_items = :total_items,
-				processed_items = :processed_items, successful_items = :successful_items, failed_items = :failed_items, metadata = :metadata, error_message = :error_message
+				processed_items = :processed_items, successful_items = :successful_items, failed_items = :failed_items, metadata = :metadata, error_message = :error_message,
+				tags = COALESCE(:tags, '{}'::text[])
 			  WHERE id = :id`
+	if s.uniqueNames && campaign.UserID != nil {
+		// Only a rename is checked, so campaigns that already shared a name can still be updated
+		query += ` AND (lower(campaigns.name) = lower(:name) OR NOT EXISTS (
+				SELECT 1 FROM campaigns other
+				WHERE other.user_id = :user_id AND lower(other.name) = lower(:name) AND other.id <> :id))`
+	}
 	result, err := exec.NamedExecContext(ctx, query, campaign)
 	if err != nil {
 		return err
 	}
 	rowsAffected, err := result.RowsAffected()
 	if err == nil && rowsAffected == 0 {
+		if s.uniqueNames && campaign.UserID != nil {
+			var exists bool
+			if err := exec.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM campaigns WHERE id = $1)`, campaign.ID); err != nil {
+				return err
+			}
+			if exists {
+				return store.ErrCampaignNameTaken
+			}
+		}
 		return store.ErrNotFound
 	}
 	return err
@@ -86,10 +231,9 @@ func (s *campaignStorePostgres) DeleteCampaign(ctx context.Context, exec store.Q
 	return err
 }
 
-func (s *campaignStorePostgres) ListCampaigns(ctx context.Context, exec store.Querier, filter store.ListCampaignsFilter) ([]*models.Campaign, error) {
-	baseQuery := `SELECT id, name, campaign_type, status, user_id, created_at, updated_at,
-					 started_at, completed_at, progress_percentage, total_items, processed_items, successful_items, failed_items, metadata, error_message
-			      FROM campaigns`
+// campaignFilterConditions builds the WHERE conditions shared by ListCampaigns and CountCampaigns.
+// Tag filters use the array overlap (any) and containment (all) operators served by the GIN index on tags.
+func campaignFilterConditions(filter store.ListCampaignsFilter) ([]string, []interface{}) {
 	args := []interface{}{}
 	conditions := []string{}
 
@@ -105,6 +249,24 @@ func (s *campaignStorePostgres) ListCampaigns(ctx context.Context, exec store.Qu
 		conditions = append(conditions, "user_id = ?")
 		args = append(args, filter.UserID)
 	}
+	if len(filter.Tags) > 0 {
+		if filter.TagMatch == store.TagMatchAll {
+			conditions = append(conditions, "tags @> ?::text[]")
+		} else {
+			conditions = append(conditions, "tags && ?::text[]")
+		}
+		args = append(args, pq.Array(filter.Tags))
+	}
+	return conditions, args
+}
+
+func (s *campaignStorePostgres) ListCampaigns(ctx context.Context, exec store.Querier, filter store.ListCampaignsFilter) ([]*models.Campaign, error) {
+	exec = s.reader(exec)
+	baseQuery := `SELECT id, name, campaign_type, status, user_id, created_at, updated_at,
+					 started_at, completed_at, progress_percentage, total_items, processed_items, successful_items, failed_items, metadata, error_message, tags,
+					 auto_retry, retry_count, retry_of_campaign_id, promoted_from_campaign_id
+			      FROM campaigns`
+	conditions, args := campaignFilterConditions(filter)
 
 	finalQuery := baseQuery
 	if len(conditions) > 0 {
@@ -142,6 +304,8 @@ func (s *campaignStorePostgres) ListCampaigns(ctx context.Context, exec store.Qu
 		reboundQuery = q.Rebind(finalQuery)
 	case *sqlx.Tx:
 		reboundQuery = q.Rebind(finalQuery)
+	case *ReadRoutingQuerier:
+		reboundQuery = q.Rebind(finalQuery)
 	default:
 		return nil, fmt.Errorf("unexpected Querier type: %T", exec)
 	}
@@ -152,22 +316,9 @@ func (s *campaignStorePostgres) ListCampaigns(ctx context.Context, exec store.Qu
 }
 
 func (s *campaignStorePostgres) CountCampaigns(ctx context.Context, exec store.Querier, filter store.ListCampaignsFilter) (int64, error) {
+	exec = s.reader(exec)
 	baseQuery := `SELECT COUNT(*) FROM campaigns`
-	args := []interface{}{}
-	conditions := []string{}
-
-	if filter.Type != "" {
-		conditions = append(conditions, "campaign_type = ?")
-		args = append(args, filter.Type)
-	}
-	if filter.Status != "" {
-		conditions = append(conditions, "status = ?")
-		args = append(args, filter.Status)
-	}
-	if filter.UserID != "" {
-		conditions = append(conditions, "user_id = ?")
-		args = append(args, filter.UserID)
-	}
+	conditions, args := campaignFilterConditions(filter)
 
 	finalQuery := baseQuery
 	if len(conditions) > 0 {
@@ -180,6 +331,8 @@ func (s *campaignStorePostgres) CountCampaigns(ctx context.Context, exec store.Q
 		reboundQuery = q.Rebind(finalQuery)
 	case *sqlx.Tx:
 		reboundQuery = q.Rebind(finalQuery)
+	case *ReadRoutingQuerier:
+		reboundQuery = q.Rebind(finalQuery)
 	default:
 		return 0, fmt.Errorf("unexpected Querier type: %T", exec)
 	}
@@ -189,6 +342,145 @@ func (s *campaignStorePostgres) CountCampaigns(ctx context.Context, exec store.Q
 	return count, err
 }
 
+func (s *campaignStorePostgres) ListCampaignStatuses(ctx context.Context, exec store.Querier, ids []uuid.UUID, userID string) ([]store.CampaignStatusSummary, error) {
+	statuses := []store.CampaignStatusSummary{}
+	if len(ids) == 0 {
+		return statuses, nil
+	}
+	query, args, err := sqlx.In(`SELECT id, status, progress_percentage FROM campaigns WHERE id IN (?)`, ids)
+	if err != nil {
+		return nil, err
+	}
+	if userID != "" {
+		query += ` AND user_id = ?`
+		args = append(args, userID)
+	}
+	err = s.reader(exec).SelectContext(ctx, &statuses, sqlx.Rebind(sqlx.DOLLAR, query), args...)
+	return statuses, err
+}
+
+// ListFailedCampaignsToRetry returns failed campaigns that opted into automatic retry, have been
+// retried fewer than maxRetries times and have no retry run yet, longest failed first
+func (s *campaignStorePostgres) ListFailedCampaignsToRetry(ctx context.Context, exec store.Querier, maxRetries, limit int) ([]*models.Campaign, error) {
+	if exec == nil {
+		exec = s.db
+	}
+	query := `SELECT id, name, campaign_type, status, user_id, created_at, updated_at,
+					 started_at, completed_at, progress_percentage, total_items, processed_items, successful_items, failed_items, metadata, error_message, tags,
+					 auto_retry, retry_count, retry_of_campaign_id, promoted_from_campaign_id
+			  FROM campaigns c
+			  WHERE status = $1 AND auto_retry AND retry_count < $2
+			    AND NOT EXISTS (SELECT 1 FROM campaigns r WHERE r.retry_of_campaign_id = c.id)
+			  ORDER BY COALESCE(completed_at, updated_at) ASC
+			  LIMIT $3`
+	campaigns := []*models.Campaign{}
+	err := exec.SelectContext(ctx, &campaigns, query, models.CampaignStatusFailed, maxRetries, limit)
+	return campaigns, err
+}
+
+// ListCompletedCampaignsToPromote returns completed domain generation campaigns with a DNS
+// promotion that has not created its DNS validation campaign yet, longest completed first
+func (s *campaignStorePostgres) ListCompletedCampaignsToPromote(ctx context.Context, exec store.Querier, limit int) ([]*models.Campaign, error) {
+	if exec == nil {
+		exec = s.db
+	}
+	query := `SELECT id, name, campaign_type, status, user_id, created_at, updated_at,
+					 started_at, completed_at, progress_percentage, total_items, processed_items, successful_items, failed_items, metadata, error_message, tags,
+					 auto_retry, retry_count, retry_of_campaign_id, promoted_from_campaign_id
+			  FROM campaigns c
+			  WHERE status = $1 AND campaign_type = $2
+			    AND EXISTS (SELECT 1 FROM domain_generation_campaign_params p WHERE p.campaign_id = c.id AND p.dns_promotion IS NOT NULL)
+			    AND NOT EXISTS (SELECT 1 FROM campaigns d WHERE d.promoted_from_campaign_id = c.id)
+			  ORDER BY COALESCE(completed_at, updated_at) ASC
+			  LIMIT $3`
+	campaigns := []*models.Campaign{}
+	err := exec.SelectContext(ctx, &campaigns, query, models.CampaignStatusCompleted, models.CampaignTypeDomainGeneration, limit)
+	return campaigns, err
+}
+
+// ListCampaignRetryChain returns the original run of the campaign's retry chain followed by each
+// retry in order. A campaign that was never retried is a chain of one.
+func (s *campaignStorePostgres) ListCampaignRetryChain(ctx context.Context, exec store.Querier, campaignID uuid.UUID) ([]*models.Campaign, error) {
+	if exec == nil {
+		exec = s.db
+	}
+	query := `WITH RECURSIVE ancestors AS (
+				SELECT id, retry_of_campaign_id FROM campaigns WHERE id = $1
+				UNION ALL
+				SELECT c.id, c.retry_of_campaign_id FROM campaigns c JOIN ancestors a ON c.id = a.retry_of_campaign_id
+			  ), chain AS (
+				SELECT c.* FROM campaigns c JOIN ancestors a ON c.id = a.id WHERE a.retry_of_campaign_id IS NULL
+				UNION ALL
+				SELECT c.* FROM campaigns c JOIN chain ON c.retry_of_campaign_id = chain.id
+			  )
+			  SELECT id, name, campaign_type, status, user_id, created_at, updated_at,
+					 started_at, completed_at, progress_percentage, total_items, processed_items, successful_items, failed_items, metadata, error_message, tags,
+					 auto_retry, retry_count, retry_of_campaign_id, promoted_from_campaign_id
+			  FROM chain ORDER BY retry_count ASC`
+	campaigns := []*models.Campaign{}
+	if err := exec.SelectContext(ctx, &campaigns, query, campaignID); err != nil {
+		return nil, err
+	}
+	if len(campaigns) == 0 {
+		return nil, store.ErrNotFound
+	}
+	return campaigns, nil
+}
+
+func (s *campaignStorePostgres) ListCampaignIDsUsingPersona(ctx context.Context, exec store.Querier, personaID uuid.UUID) ([]uuid.UUID, error) {
+	if exec == nil {
+		exec = s.db
+	}
+	query := `SELECT campaign_id FROM dns_validation_params WHERE $1 = ANY(persona_ids)
+			  UNION
+			  SELECT campaign_id FROM http_keyword_campaign_params WHERE $1 = ANY(persona_ids)
+			  ORDER BY campaign_id`
+	ids := []uuid.UUID{}
+	if err := exec.SelectContext(ctx, &ids, query, personaID); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (s *campaignStorePostgres) ListCampaignIDsUsingProxy(ctx context.Context, exec store.Querier, proxyID uuid.UUID) ([]uuid.UUID, error) {
+	if exec == nil {
+		exec = s.db
+	}
+	query := `SELECT campaign_id FROM http_keyword_campaign_params WHERE $1 = ANY(proxy_ids) ORDER BY campaign_id`
+	ids := []uuid.UUID{}
+	if err := exec.SelectContext(ctx, &ids, query, proxyID); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (s *campaignStorePostgres) AddDeletedResourceSnapshot(ctx context.Context, exec store.Querier, campaignIDs []uuid.UUID, snapshot *models.DeletedResourceSnapshot) error {
+	if len(campaignIDs) == 0 {
+		return nil
+	}
+	if exec == nil {
+		exec = s.db
+	}
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot of %s %s: %w", snapshot.ResourceType, snapshot.ID, err)
+	}
+	ids := make(pq.StringArray, len(campaignIDs))
+	for i, id := range campaignIDs {
+		ids[i] = id.String()
+	}
+	// Metadata that is missing or not an object is replaced
+	query := `UPDATE campaigns SET
+				metadata = (CASE WHEN jsonb_typeof(metadata) = 'object' THEN metadata ELSE '{}'::jsonb END) ||
+					jsonb_build_object('deletedResources',
+						(CASE WHEN jsonb_typeof(metadata->'deletedResources') = 'object' THEN metadata->'deletedResources' ELSE '{}'::jsonb END) ||
+						jsonb_build_object($1::text, $2::jsonb)),
+				updated_at = NOW()
+			  WHERE id = ANY($3::uuid[])`
+	_, err = exec.ExecContext(ctx, query, snapshot.ID.String(), string(encoded), ids)
+	return err
+}
+
 func (s *campaignStorePostgres) UpdateCampaignStatus(ctx context.Context, exec store.Querier, id uuid.UUID, status models.CampaignStatusEnum, errorMessage sql.NullString) error {
 	query := `UPDATE campaigns SET status = $1, error_message = $2, updated_at = NOW() WHERE id = $3`
 	result, err := exec.ExecContext(ctx, query, status, errorMessage, id)
@@ -202,6 +494,15 @@ func (s *campaignStorePostgres) UpdateCampaignStatus(ctx context.Context, exec s
 	return err
 }
 
+func (s *campaignStorePostgres) GetCampaignStatusForUpdate(ctx context.Context, exec store.Querier, id uuid.UUID) (models.CampaignStatusEnum, error) {
+	var status models.CampaignStatusEnum
+	err := exec.GetContext(ctx, &status, `SELECT status FROM campaigns WHERE id = $1 FOR UPDATE`, id)
+	if err == sql.ErrNoRows {
+		return "", store.ErrNotFound
+	}
+	return status, err
+}
+
 func (s *campaignStorePostgres) UpdateCampaignProgress(ctx context.Context, exec store.Querier, id uuid.UUID, processedItems, totalItems int64, progressPercentage float64) error {
 	// First, update the progress and set status to 'running' if it's not already completed or failed
 	query := `UPDATE campaigns 
@@ -235,21 +536,50 @@ func (s *campaignStorePostgres) UpdateCampaignProgress(ctx context.Context, exec
 
 func (s *campaignStorePostgres) CreateDomainGenerationParams(ctx context.Context, exec store.Querier, params *models.DomainGenerationCampaignParams) error {
 	query := `INSERT INTO domain_generation_campaign_params 
-				(campaign_id, pattern_type, variable_length, character_set, constant_string, tld, num_domains_to_generate, total_possible_combinations, current_offset) 
-			  VALUES (:campaign_id, :pattern_type, :variable_length, :character_set, :constant_string, :tld, :num_domains_to_generate, :total_possible_combinations, :current_offset)`
-	_, err := exec.NamedExecContext(ctx, query, params)
+				(campaign_id, pattern_type, variable_length, character_set, constant_string, tld, num_domains_to_generate, total_possible_combinations, current_offset, dns_promotion) 
+			  VALUES (:campaign_id, :pattern_type, :variable_length, :character_set, :constant_string, :tld, :num_domains_to_generate, :total_possible_combinations, :current_offset, :dns_promotion)`
+
+	var dnsPromotion *json.RawMessage
+	if params.DNSPromotion != nil {
+		encoded, err := json.Marshal(params.DNSPromotion)
+		if err != nil {
+			return fmt.Errorf("CreateDomainGenerationParams: failed to encode DNS promotion: %w", err)
+		}
+		dnsPromotion = models.JSONRawMessagePtr(encoded)
+	}
+	arg := struct {
+		*models.DomainGenerationCampaignParams
+		DNSPromotion *json.RawMessage `db:"dns_promotion"`
+	}{
+		DomainGenerationCampaignParams: params,
+		DNSPromotion:                   dnsPromotion,
+	}
+	_, err := exec.NamedExecContext(ctx, query, &arg)
 	return err
 }
 
 func (s *campaignStorePostgres) GetDomainGenerationParams(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (*models.DomainGenerationCampaignParams, error) {
-	params := &models.DomainGenerationCampaignParams{}
-	query := `SELECT campaign_id, pattern_type, variable_length, character_set, constant_string, tld, num_domains_to_generate, total_possible_combinations, current_offset 
+	scanTarget := struct {
+		models.DomainGenerationCampaignParams
+		DNSPromotion *json.RawMessage `db:"dns_promotion"`
+	}{}
+	query := `SELECT campaign_id, pattern_type, variable_length, character_set, constant_string, tld, num_domains_to_generate, total_possible_combinations, current_offset, dns_promotion 
 			  FROM domain_generation_campaign_params WHERE campaign_id = $1`
-	err := exec.GetContext(ctx, params, query, campaignID)
+	err := exec.GetContext(ctx, &scanTarget, query, campaignID)
 	if err == sql.ErrNoRows {
 		return nil, store.ErrNotFound
 	}
-	return params, err
+	if err != nil {
+		return nil, err
+	}
+	params := &scanTarget.DomainGenerationCampaignParams
+	if scanTarget.DNSPromotion != nil {
+		params.DNSPromotion = &models.DNSPromotion{}
+		if err := json.Unmarshal(*scanTarget.DNSPromotion, params.DNSPromotion); err != nil {
+			return nil, fmt.Errorf("GetDomainGenerationParams: DNS promotion decode error: %w", err)
+		}
+	}
+	return params, nil
 }
 
 func (s *campaignStorePostgres) UpdateDomainGenerationParamsOffset(ctx context.Context, exec store.Querier, campaignID uuid.UUID, newOffset int64) error {
@@ -322,6 +652,7 @@ func (s *campaignStorePostgres) CreateGeneratedDomains(ctx context.Context, exec
 }
 
 func (s *campaignStorePostgres) GetGeneratedDomainsByCampaign(ctx context.Context, exec store.Querier, campaignID uuid.UUID, limit int, lastOffsetIndex int64) ([]*models.GeneratedDomain, error) {
+	exec = s.reader(exec)
 	domains := []*models.GeneratedDomain{}
 	// lastOffsetIndex = -1 can indicate to fetch the first page
 	query := `SELECT id, domain_generation_campaign_id, domain_name, source_keyword, source_pattern, tld, offset_index, generated_at, created_at
@@ -334,6 +665,7 @@ func (s *campaignStorePostgres) GetGeneratedDomainsByCampaign(ctx context.Contex
 }
 
 func (s *campaignStorePostgres) CountGeneratedDomainsByCampaign(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (int64, error) {
+	exec = s.reader(exec)
 	var count int64
 	query := `SELECT COUNT(*) FROM generated_domains WHERE domain_generation_campaign_id = $1`
 	err := exec.GetContext(ctx, &count, query, campaignID)
@@ -344,20 +676,31 @@ func (s *campaignStorePostgres) CountGeneratedDomainsByCampaign(ctx context.Cont
 
 func (s *campaignStorePostgres) CreateDNSValidationParams(ctx context.Context, exec store.Querier, params *models.DNSValidationCampaignParams) error {
 	query := `INSERT INTO dns_validation_params
-	               (campaign_id, source_generation_campaign_id, persona_ids, rotation_interval_seconds, processing_speed_per_minute, batch_size, retry_attempts, metadata)
-	             VALUES (:campaign_id, :source_generation_campaign_id, :persona_ids, :rotation_interval_seconds, :processing_speed_per_minute, :batch_size, :retry_attempts, :metadata)`
+	               (campaign_id, source_generation_campaign_id, persona_ids, rotation_interval_seconds, processing_speed_per_minute, batch_size, retry_attempts, deduplicate_domains, consensus_mode, consensus_quorum, expected_records, metadata)
+	             VALUES (:campaign_id, :source_generation_campaign_id, :persona_ids, :rotation_interval_seconds, :processing_speed_per_minute, :batch_size, :retry_attempts, :deduplicate_domains, :consensus_mode, :consensus_quorum, :expected_records, :metadata)`
 
 	personaIDStrings := make([]string, len(params.PersonaIDs))
 	for i, pid := range params.PersonaIDs {
 		personaIDStrings[i] = pid.String()
 	}
 
+	var expectedRecords *json.RawMessage
+	if params.ExpectedRecords != nil {
+		encoded, err := json.Marshal(params.ExpectedRecords)
+		if err != nil {
+			return fmt.Errorf("CreateDNSValidationParams: failed to encode expected records: %w", err)
+		}
+		expectedRecords = models.JSONRawMessagePtr(encoded)
+	}
+
 	arg := struct {
 		*models.DNSValidationCampaignParams
-		PersonaIDs pq.StringArray `db:"persona_ids"`
+		PersonaIDs      pq.StringArray   `db:"persona_ids"`
+		ExpectedRecords *json.RawMessage `db:"expected_records"`
 	}{
 		DNSValidationCampaignParams: params,
 		PersonaIDs:                  pq.StringArray(personaIDStrings),
+		ExpectedRecords:             expectedRecords,
 	}
 
 	_, err := exec.NamedExecContext(ctx, query, &arg)
@@ -374,11 +717,15 @@ func (s *campaignStorePostgres) GetDNSValidationParams(ctx context.Context, exec
 		ProcessingSpeedPerMinute   int              `db:"processing_speed_per_minute"`
 		BatchSize                  int              `db:"batch_size"`
 		RetryAttempts              int              `db:"retry_attempts"`
+		DeduplicateDomains         bool             `db:"deduplicate_domains"`
+		ConsensusMode              string           `db:"consensus_mode"`
+		ConsensusQuorum            int              `db:"consensus_quorum"`
+		ExpectedRecords            *json.RawMessage `db:"expected_records"`
 		Metadata                   *json.RawMessage `db:"metadata"`
 	}
 
 	scanTarget := &dnsParamsScan{}
-	query := `SELECT campaign_id, source_generation_campaign_id, persona_ids, rotation_interval_seconds, processing_speed_per_minute, batch_size, retry_attempts, metadata
+	query := `SELECT campaign_id, source_generation_campaign_id, persona_ids, rotation_interval_seconds, processing_speed_per_minute, batch_size, retry_attempts, deduplicate_domains, consensus_mode, consensus_quorum, expected_records, metadata
 		         FROM dns_validation_params WHERE campaign_id = $1`
 	err := exec.GetContext(ctx, scanTarget, query, campaignID)
 	if err != nil {
@@ -396,6 +743,9 @@ func (s *campaignStorePostgres) GetDNSValidationParams(ctx context.Context, exec
 		ProcessingSpeedPerMinute:   models.IntPtr(scanTarget.ProcessingSpeedPerMinute),
 		BatchSize:                  models.IntPtr(scanTarget.BatchSize),
 		RetryAttempts:              models.IntPtr(scanTarget.RetryAttempts),
+		DeduplicateDomains:         scanTarget.DeduplicateDomains,
+		ConsensusMode:              scanTarget.ConsensusMode,
+		ConsensusQuorum:            scanTarget.ConsensusQuorum,
 		Metadata:                   scanTarget.Metadata,
 		PersonaIDs:                 make([]uuid.UUID, 0, len(scanTarget.ScannedPersonaIDs)),
 	}
@@ -407,21 +757,35 @@ func (s *campaignStorePostgres) GetDNSValidationParams(ctx context.Context, exec
 		}
 		params.PersonaIDs = append(params.PersonaIDs, id)
 	}
+	if scanTarget.ExpectedRecords != nil {
+		params.ExpectedRecords = &models.DNSExpectedRecords{}
+		if err := json.Unmarshal(*scanTarget.ExpectedRecords, params.ExpectedRecords); err != nil {
+			return nil, fmt.Errorf("GetDNSValidationParams: expected records decode error: %w", err)
+		}
+	}
 
 	return params, nil
 }
 
 // --- DNS Validation Results --- //
 
+// CreateDNSValidationResults upserts DNS validation results, committing them in chunks unless exec is a transaction.
 func (s *campaignStorePostgres) CreateDNSValidationResults(ctx context.Context, exec store.Querier, results []*models.DNSValidationResult) error {
 	if len(results) == 0 {
 		return nil
 	}
+	return s.execInChunks(ctx, exec, len(results), func(q store.Querier, start, end int) error {
+		return insertDNSValidationResults(ctx, q, results[start:end])
+	})
+}
+
+func insertDNSValidationResults(ctx context.Context, exec store.Querier, results []*models.DNSValidationResult) error {
 	stmt, err := exec.PrepareNamedContext(ctx, `INSERT INTO dns_validation_results
-	       (id, dns_campaign_id, generated_domain_id, domain_name, validation_status, dns_records, validated_by_persona_id, attempts, last_checked_at, created_at)
-	       VALUES (:id, :dns_campaign_id, :generated_domain_id, :domain_name, :validation_status, :dns_records, :validated_by_persona_id, :attempts, :last_checked_at, :created_at)
+	       (id, dns_campaign_id, generated_domain_id, domain_name, validation_status, dns_records, consensus, expectation, validated_by_persona_id, attempts, last_checked_at, created_at)
+	       VALUES (:id, :dns_campaign_id, :generated_domain_id, :domain_name, :validation_status, :dns_records, :consensus, :expectation, :validated_by_persona_id, :attempts, :last_checked_at, :created_at)
 	       ON CONFLICT (dns_campaign_id, domain_name) DO UPDATE SET
-	           validation_status = EXCLUDED.validation_status, dns_records = EXCLUDED.dns_records,
+	           validation_status = EXCLUDED.validation_status, dns_records = EXCLUDED.dns_records, consensus = EXCLUDED.consensus,
+	           expectation = EXCLUDED.expectation,
 	           validated_by_persona_id = EXCLUDED.validated_by_persona_id, attempts = dns_validation_results.attempts + 1,
 	           last_checked_at = EXCLUDED.last_checked_at, created_at = EXCLUDED.created_at`)
 	if err != nil {
@@ -449,8 +813,9 @@ func (s *campaignStorePostgres) CreateDNSValidationResults(ctx context.Context,
 }
 
 func (s *campaignStorePostgres) GetDNSValidationResultsByCampaign(ctx context.Context, exec store.Querier, campaignID uuid.UUID, filter store.ListValidationResultsFilter) ([]*models.DNSValidationResult, error) {
+	exec = s.reader(exec)
 	results := []*models.DNSValidationResult{}
-	baseQuery := `SELECT id, dns_campaign_id, generated_domain_id, domain_name, validation_status, dns_records, validated_by_persona_id, attempts, last_checked_at, created_at
+	baseQuery := `SELECT id, dns_campaign_id, generated_domain_id, domain_name, validation_status, dns_records, consensus, expectation, validated_by_persona_id, attempts, last_checked_at, created_at
 		                FROM dns_validation_results WHERE dns_campaign_id = ?`
 	args := []interface{}{campaignID}
 	finalQuery := baseQuery
@@ -459,6 +824,10 @@ func (s *campaignStorePostgres) GetDNSValidationResultsByCampaign(ctx context.Co
 		finalQuery += " AND validation_status = ?"
 		args = append(args, filter.ValidationStatus)
 	}
+	if filter.AfterDomainName != "" {
+		finalQuery += " AND domain_name > ?"
+		args = append(args, filter.AfterDomainName)
+	}
 	finalQuery += " ORDER BY domain_name ASC"
 	if filter.Limit > 0 {
 		finalQuery += " LIMIT ?"
@@ -475,6 +844,8 @@ func (s *campaignStorePostgres) GetDNSValidationResultsByCampaign(ctx context.Co
 		reboundQuery = q.Rebind(finalQuery)
 	case *sqlx.Tx:
 		reboundQuery = q.Rebind(finalQuery)
+	case *ReadRoutingQuerier:
+		reboundQuery = q.Rebind(finalQuery)
 	default:
 		return nil, fmt.Errorf("unexpected Querier type: %T", exec)
 	}
@@ -483,6 +854,20 @@ func (s *campaignStorePostgres) GetDNSValidationResultsByCampaign(ctx context.Co
 	return results, err
 }
 
+func (s *campaignStorePostgres) GetDNSValidationResultByDomain(ctx context.Context, exec store.Querier, campaignID uuid.UUID, domainName string) (*models.DNSValidationResult, error) {
+	exec = s.reader(exec)
+	result := &models.DNSValidationResult{}
+	query := `SELECT id, dns_campaign_id, generated_domain_id, domain_name, validation_status, dns_records, consensus, expectation, validated_by_persona_id, attempts, last_checked_at, created_at
+	            FROM dns_validation_results WHERE dns_campaign_id = $1 AND domain_name = $2`
+	if err := exec.GetContext(ctx, result, query, campaignID, domainName); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrNotFound
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
 func (s *campaignStorePostgres) CountDNSValidationResults(ctx context.Context, exec store.Querier, campaignID uuid.UUID, onlyValid bool) (int64, error) {
 	query := `SELECT COUNT(*) FROM dns_validation_results WHERE dns_campaign_id = $1`
 	args := []interface{}{campaignID}
@@ -495,6 +880,64 @@ func (s *campaignStorePostgres) CountDNSValidationResults(ctx context.Context, e
 	return count, err
 }
 
+func (s *campaignStorePostgres) CountDNSValidationResultsByStatus(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (map[string]int64, error) {
+	exec = s.reader(exec)
+	query := `SELECT validation_status, COUNT(*) AS count FROM dns_validation_results WHERE dns_campaign_id = $1 GROUP BY validation_status`
+	return countByStatus(ctx, exec, query, campaignID)
+}
+
+// ListRecentDNSValidationErrors returns failed DNS results: those that recorded a resolver error
+// or reached the attempt cap
+func (s *campaignStorePostgres) ListRecentDNSValidationErrors(ctx context.Context, exec store.Querier, campaignID uuid.UUID, limit int) ([]store.CampaignResultError, error) {
+	exec = s.reader(exec)
+	query := `SELECT domain_name, validation_status, dns_records->>'error' AS error, COALESCE(attempts, 0) AS attempts,
+	                 COALESCE(last_checked_at, created_at) AS checked_at
+	            FROM dns_validation_results
+	           WHERE dns_campaign_id = $1 AND (dns_records->>'error' IS NOT NULL OR validation_status = $2)
+	           ORDER BY checked_at DESC LIMIT $3`
+	results := []store.CampaignResultError{}
+	err := exec.SelectContext(ctx, &results, query, campaignID, string(models.ValidationStatusMaxAttemptsExceeded), limit)
+	return results, err
+}
+
+// ListDNSValidationStatusesAfter pages through a campaign's DNS results by domain name. Names are
+// compared with the "C" collation so the order matches Go string comparison.
+func (s *campaignStorePostgres) ListDNSValidationStatusesAfter(ctx context.Context, exec store.Querier, campaignID uuid.UUID, afterDomain string, limit int) ([]store.CampaignResultStatus, error) {
+	if exec == nil {
+		exec = s.db
+	}
+	query := `SELECT domain_name, validation_status FROM dns_validation_results
+	           WHERE dns_campaign_id = $1 AND domain_name COLLATE "C" > $2
+	           ORDER BY domain_name COLLATE "C" LIMIT $3`
+	results := []store.CampaignResultStatus{}
+	err := exec.SelectContext(ctx, &results, query, campaignID, afterDomain, limit)
+	return results, err
+}
+
+// GetDNSValidationAttempts returns the recorded attempt count of each named domain that has a DNS result
+func (s *campaignStorePostgres) GetDNSValidationAttempts(ctx context.Context, exec store.Querier, campaignID uuid.UUID, domainNames []string) (map[string]int, error) {
+	if exec == nil {
+		exec = s.db
+	}
+	query := `SELECT domain_name, COALESCE(attempts, 0) AS attempts FROM dns_validation_results
+	             WHERE dns_campaign_id = $1 AND domain_name = ANY($2)`
+	return attemptsByDomain(ctx, exec, query, campaignID, domainNames)
+}
+
+// MarkDNSValidationMaxAttemptsExceeded marks the named domains' DNS results as having reached the attempt cap
+func (s *campaignStorePostgres) MarkDNSValidationMaxAttemptsExceeded(ctx context.Context, exec store.Querier, campaignID uuid.UUID, domainNames []string) error {
+	if len(domainNames) == 0 {
+		return nil
+	}
+	if exec == nil {
+		exec = s.db
+	}
+	query := `UPDATE dns_validation_results SET validation_status = $1, last_checked_at = NOW()
+	             WHERE dns_campaign_id = $2 AND domain_name = ANY($3)`
+	_, err := exec.ExecContext(ctx, query, string(models.ValidationStatusMaxAttemptsExceeded), campaignID, pq.Array(domainNames))
+	return err
+}
+
 func (s *campaignStorePostgres) GetDomainsForDNSValidation(ctx context.Context, exec store.Querier, dnsCampaignID uuid.UUID, sourceGenerationCampaignID uuid.UUID, limit int, lastOffsetIndex int64) ([]*models.GeneratedDomain, error) {
 	domains := []*models.GeneratedDomain{}
 	// Fetches generated domains that either don't have a DNS result for this campaign OR their result is not 'valid_dns'
@@ -505,7 +948,10 @@ func (s *campaignStorePostgres) GetDomainsForDNSValidation(ctx context.Context,
 	       LEFT JOIN dns_validation_results dvr ON gd.id = dvr.generated_domain_id AND dvr.dns_campaign_id = $1
 	       WHERE gd.domain_generation_campaign_id = $2
 	         AND gd.offset_index > $3
-	         AND (dvr.id IS NULL OR dvr.validation_status NOT IN ('valid_dns'))
+	         AND (dvr.id IS NULL OR dvr.validation_status NOT IN ('valid_dns', 'max_attempts_exceeded'))
+	         AND NOT EXISTS (
+	               SELECT 1 FROM campaign_domain_dedup_decisions cdd
+	               WHERE cdd.campaign_id = $1 AND cdd.source_item_id = gd.id AND cdd.decision = 'duplicate')
 	       ORDER BY gd.offset_index ASC
 	       LIMIT $4`
 	err := exec.SelectContext(ctx, &domains, query, dnsCampaignID, sourceGenerationCampaignID, lastOffsetIndex, limit)
@@ -516,8 +962,8 @@ func (s *campaignStorePostgres) GetDomainsForDNSValidation(ctx context.Context,
 
 func (s *campaignStorePostgres) CreateHTTPKeywordParams(ctx context.Context, exec store.Querier, params *models.HTTPKeywordCampaignParams) error {
 	query := `INSERT INTO http_keyword_campaign_params
-	               (campaign_id, source_campaign_id, source_type, keyword_set_ids, ad_hoc_keywords, persona_ids, proxy_ids, proxy_pool_id, proxy_selection_strategy, rotation_interval_seconds, processing_speed_per_minute, batch_size, retry_attempts, target_http_ports, last_processed_domain_name, metadata)
-	             VALUES (:campaign_id, :source_campaign_id, :source_type, :keyword_set_ids, :ad_hoc_keywords, :persona_ids, :proxy_ids, :proxy_pool_id, :proxy_selection_strategy, :rotation_interval_seconds, :processing_speed_per_minute, :batch_size, :retry_attempts, :target_http_ports, :last_processed_domain_name, :metadata)
+	               (campaign_id, source_campaign_id, source_type, keyword_set_ids, ad_hoc_keywords, persona_ids, proxy_ids, proxy_pool_id, proxy_selection_strategy, rotation_interval_seconds, processing_speed_per_minute, batch_size, retry_attempts, target_http_ports, last_processed_domain_name, deduplicate_domains, validation_mode, match_type, metadata)
+	             VALUES (:campaign_id, :source_campaign_id, :source_type, :keyword_set_ids, :ad_hoc_keywords, :persona_ids, :proxy_ids, :proxy_pool_id, :proxy_selection_strategy, :rotation_interval_seconds, :processing_speed_per_minute, :batch_size, :retry_attempts, :target_http_ports, :last_processed_domain_name, :deduplicate_domains, COALESCE(NULLIF(:validation_mode, ''), 'keywords'), COALESCE(NULLIF(:match_type, ''), 'substring'), :metadata)
 	             ON CONFLICT (campaign_id) DO UPDATE SET
 	               source_campaign_id = EXCLUDED.source_campaign_id,
 	               source_type = EXCLUDED.source_type,
@@ -533,6 +979,9 @@ func (s *campaignStorePostgres) CreateHTTPKeywordParams(ctx context.Context, exe
 	               retry_attempts = EXCLUDED.retry_attempts,
 	               target_http_ports = EXCLUDED.target_http_ports,
 	               last_processed_domain_name = EXCLUDED.last_processed_domain_name,
+	               deduplicate_domains = EXCLUDED.deduplicate_domains,
+	               validation_mode = EXCLUDED.validation_mode,
+	               match_type = EXCLUDED.match_type,
 	               metadata = EXCLUDED.metadata`
 
 	arg := struct {
@@ -602,7 +1051,7 @@ func (s *campaignStorePostgres) GetHTTPKeywordParams(ctx context.Context, exec s
 	}
 	scanTarget := &httpParamsScan{}
 
-	query := `SELECT campaign_id, source_campaign_id, source_type, keyword_set_ids, ad_hoc_keywords, persona_ids, proxy_ids, proxy_pool_id, proxy_selection_strategy, rotation_interval_seconds, processing_speed_per_minute, batch_size, retry_attempts, target_http_ports, last_processed_domain_name, metadata
+	query := `SELECT campaign_id, source_campaign_id, source_type, keyword_set_ids, ad_hoc_keywords, persona_ids, proxy_ids, proxy_pool_id, proxy_selection_strategy, rotation_interval_seconds, processing_speed_per_minute, batch_size, retry_attempts, target_http_ports, last_processed_domain_name, deduplicate_domains, validation_mode, match_type, metadata
 	             FROM http_keyword_campaign_params WHERE campaign_id = $1`
 	err := exec.GetContext(ctx, scanTarget, query, campaignID)
 	if err != nil {
@@ -624,6 +1073,9 @@ func (s *campaignStorePostgres) GetHTTPKeywordParams(ctx context.Context, exec s
 		BatchSize:                scanTarget.BatchSize,
 		RetryAttempts:            scanTarget.RetryAttempts,
 		LastProcessedDomainName:  scanTarget.LastProcessedDomainName,
+		DeduplicateDomains:       scanTarget.DeduplicateDomains,
+		ValidationMode:           scanTarget.ValidationMode,
+		MatchType:                scanTarget.MatchType,
 		Metadata:                 scanTarget.Metadata,
 	}
 
@@ -673,18 +1125,26 @@ func (s *campaignStorePostgres) GetHTTPKeywordParams(ctx context.Context, exec s
 	return result, nil
 }
 
+// CreateHTTPKeywordResults upserts HTTP keyword results, committing them in chunks unless exec is a transaction.
 func (s *campaignStorePostgres) CreateHTTPKeywordResults(ctx context.Context, exec store.Querier, results []*models.HTTPKeywordResult) error {
 	if len(results) == 0 {
 		return nil
 	}
+	return s.execInChunks(ctx, exec, len(results), func(q store.Querier, start, end int) error {
+		return insertHTTPKeywordResults(ctx, q, results[start:end])
+	})
+}
+
+func insertHTTPKeywordResults(ctx context.Context, exec store.Querier, results []*models.HTTPKeywordResult) error {
 	stmt, err := exec.PrepareNamedContext(ctx, `INSERT INTO http_keyword_results
-		      (id, http_keyword_campaign_id, dns_result_id, domain_name, validation_status, http_status_code, response_headers, page_title, extracted_content_snippet, found_keywords_from_sets, found_ad_hoc_keywords, content_hash, validated_by_persona_id, used_proxy_id, attempts, last_checked_at, created_at)
-		      VALUES (:id, :http_keyword_campaign_id, :dns_result_id, :domain_name, :validation_status, :http_status_code, :response_headers, :page_title, :extracted_content_snippet, :found_keywords_from_sets, :found_ad_hoc_keywords, :content_hash, :validated_by_persona_id, :used_proxy_id, :attempts, :last_checked_at, :created_at)
+		      (id, http_keyword_campaign_id, dns_result_id, domain_name, validation_status, http_status_code, response_headers, page_title, extracted_content_snippet, found_keywords_from_sets, found_ad_hoc_keywords, content_hash, body_truncated, tls_certificate, validated_by_persona_id, used_proxy_id, attempts, last_checked_at, created_at)
+		      VALUES (:id, :http_keyword_campaign_id, :dns_result_id, :domain_name, :validation_status, :http_status_code, :response_headers, :page_title, :extracted_content_snippet, :found_keywords_from_sets, :found_ad_hoc_keywords, :content_hash, :body_truncated, :tls_certificate, :validated_by_persona_id, :used_proxy_id, :attempts, :last_checked_at, :created_at)
 		      ON CONFLICT (http_keyword_campaign_id, domain_name) DO UPDATE SET
 		          validation_status = EXCLUDED.validation_status, http_status_code = EXCLUDED.http_status_code,
 		          response_headers = EXCLUDED.response_headers, page_title = EXCLUDED.page_title,
 		          extracted_content_snippet = EXCLUDED.extracted_content_snippet, found_keywords_from_sets = EXCLUDED.found_keywords_from_sets,
-		          found_ad_hoc_keywords = EXCLUDED.found_ad_hoc_keywords, content_hash = EXCLUDED.content_hash,
+		          found_ad_hoc_keywords = EXCLUDED.found_ad_hoc_keywords, content_hash = EXCLUDED.content_hash, body_truncated = EXCLUDED.body_truncated,
+		          tls_certificate = EXCLUDED.tls_certificate,
 		          validated_by_persona_id = EXCLUDED.validated_by_persona_id, used_proxy_id = EXCLUDED.used_proxy_id,
 		          attempts = http_keyword_results.attempts + 1, last_checked_at = EXCLUDED.last_checked_at, created_at = EXCLUDED.created_at`)
 	if err != nil {
@@ -713,9 +1173,36 @@ func (s *campaignStorePostgres) CreateHTTPKeywordResults(ctx context.Context, ex
 	return nil
 }
 
+// UpdateHTTPKeywordResultClassifications rewrites the validation status and keyword matches of the
+// given results, by ID
+func (s *campaignStorePostgres) UpdateHTTPKeywordResultClassifications(ctx context.Context, exec store.Querier, results []*models.HTTPKeywordResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	if exec == nil {
+		exec = s.db
+	}
+	stmt, err := exec.PrepareNamedContext(ctx, `UPDATE http_keyword_results
+		      SET validation_status = :validation_status, found_keywords_from_sets = :found_keywords_from_sets,
+		          found_ad_hoc_keywords = :found_ad_hoc_keywords
+		      WHERE id = :id`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, result := range results {
+		if _, err := stmt.ExecContext(ctx, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *campaignStorePostgres) GetHTTPKeywordResultsByCampaign(ctx context.Context, exec store.Querier, campaignID uuid.UUID, filter store.ListValidationResultsFilter) ([]*models.HTTPKeywordResult, error) {
+	exec = s.reader(exec)
 	results := []*models.HTTPKeywordResult{}
-	baseQuery := `SELECT id, http_keyword_campaign_id, dns_result_id, domain_name, validation_status, http_status_code, response_headers, page_title, extracted_content_snippet, found_keywords_from_sets, found_ad_hoc_keywords, content_hash, validated_by_persona_id, used_proxy_id, attempts, last_checked_at, created_at
+	baseQuery := `SELECT id, http_keyword_campaign_id, dns_result_id, domain_name, validation_status, http_status_code, response_headers, page_title, extracted_content_snippet, found_keywords_from_sets, found_ad_hoc_keywords, content_hash, body_truncated, tls_certificate, validated_by_persona_id, used_proxy_id, attempts, last_checked_at, created_at
 		                FROM http_keyword_results WHERE http_keyword_campaign_id = ?`
 	args := []interface{}{campaignID}
 	finalQuery := baseQuery
@@ -724,6 +1211,14 @@ func (s *campaignStorePostgres) GetHTTPKeywordResultsByCampaign(ctx context.Cont
 		finalQuery += " AND validation_status = ?"
 		args = append(args, filter.ValidationStatus)
 	}
+	if filter.AfterDomainName != "" {
+		finalQuery += " AND domain_name > ?"
+		args = append(args, filter.AfterDomainName)
+	}
+	if !filter.CertificateExpiresBefore.IsZero() {
+		finalQuery += " AND (tls_certificate->>'notAfter')::timestamptz < ?"
+		args = append(args, filter.CertificateExpiresBefore)
+	}
 	finalQuery += " ORDER BY domain_name ASC"
 	if filter.Limit > 0 {
 		finalQuery += " LIMIT ?"
@@ -740,6 +1235,8 @@ func (s *campaignStorePostgres) GetHTTPKeywordResultsByCampaign(ctx context.Cont
 		reboundQuery = q.Rebind(finalQuery)
 	case *sqlx.Tx:
 		reboundQuery = q.Rebind(finalQuery)
+	case *ReadRoutingQuerier:
+		reboundQuery = q.Rebind(finalQuery)
 	default:
 		return nil, fmt.Errorf("unexpected Querier type: %T", exec)
 	}
@@ -748,19 +1245,225 @@ func (s *campaignStorePostgres) GetHTTPKeywordResultsByCampaign(ctx context.Cont
 	return results, err
 }
 
+func (s *campaignStorePostgres) GetHTTPKeywordResultByDomain(ctx context.Context, exec store.Querier, campaignID uuid.UUID, domainName string) (*models.HTTPKeywordResult, error) {
+	exec = s.reader(exec)
+	result := &models.HTTPKeywordResult{}
+	query := `SELECT id, http_keyword_campaign_id, dns_result_id, domain_name, validation_status, http_status_code, response_headers, page_title, extracted_content_snippet, found_keywords_from_sets, found_ad_hoc_keywords, content_hash, body_truncated, tls_certificate, validated_by_persona_id, used_proxy_id, attempts, last_checked_at, created_at
+	            FROM http_keyword_results WHERE http_keyword_campaign_id = $1 AND domain_name = $2`
+	if err := exec.GetContext(ctx, result, query, campaignID, domainName); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrNotFound
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *campaignStorePostgres) CountHTTPKeywordResultsByStatus(ctx context.Context, exec store.Querier, campaignID uuid.UUID) (map[string]int64, error) {
+	exec = s.reader(exec)
+	query := `SELECT validation_status, COUNT(*) AS count FROM http_keyword_results WHERE http_keyword_campaign_id = $1 GROUP BY validation_status`
+	return countByStatus(ctx, exec, query, campaignID)
+}
+
+// httpKeywordErrorStatuses are the HTTP keyword result statuses of domains that could not be
+// checked, as opposed to domains that answered without keywords or with an unwanted status code
+var httpKeywordErrorStatuses = []string{
+	"invalid_http_response_error",
+	"processing_failed_before_http",
+	circuitbreaker.StatusCircuitOpen,
+	string(models.ValidationStatusMaxAttemptsExceeded),
+}
+
+// ListRecentHTTPKeywordErrors returns HTTP keyword results whose domain could not be checked
+func (s *campaignStorePostgres) ListRecentHTTPKeywordErrors(ctx context.Context, exec store.Querier, campaignID uuid.UUID, limit int) ([]store.CampaignResultError, error) {
+	exec = s.reader(exec)
+	query := `SELECT domain_name, validation_status, NULL AS error, COALESCE(attempts, 0) AS attempts,
+	                 COALESCE(last_checked_at, created_at) AS checked_at
+	            FROM http_keyword_results
+	           WHERE http_keyword_campaign_id = $1 AND validation_status = ANY($2)
+	           ORDER BY checked_at DESC LIMIT $3`
+	results := []store.CampaignResultError{}
+	err := exec.SelectContext(ctx, &results, query, campaignID, pq.Array(httpKeywordErrorStatuses), limit)
+	return results, err
+}
+
+// ListHTTPKeywordStatusesAfter pages through a campaign's HTTP keyword results by domain name, in
+// the same order as ListDNSValidationStatusesAfter
+func (s *campaignStorePostgres) ListHTTPKeywordStatusesAfter(ctx context.Context, exec store.Querier, campaignID uuid.UUID, afterDomain string, limit int) ([]store.CampaignResultStatus, error) {
+	if exec == nil {
+		exec = s.db
+	}
+	query := `SELECT domain_name, validation_status FROM http_keyword_results
+	           WHERE http_keyword_campaign_id = $1 AND domain_name COLLATE "C" > $2
+	           ORDER BY domain_name COLLATE "C" LIMIT $3`
+	results := []store.CampaignResultStatus{}
+	err := exec.SelectContext(ctx, &results, query, campaignID, afterDomain, limit)
+	return results, err
+}
+
+// countByStatus runs a "status, count" GROUP BY query and returns the counts keyed by status
+func countByStatus(ctx context.Context, exec store.Querier, query string, args ...interface{}) (map[string]int64, error) {
+	rows := []struct {
+		Status string `db:"validation_status"`
+		Count  int64  `db:"count"`
+	}{}
+	if err := exec.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// GetHTTPKeywordAttempts returns the recorded attempt count of each named domain that has an HTTP result
+func (s *campaignStorePostgres) GetHTTPKeywordAttempts(ctx context.Context, exec store.Querier, campaignID uuid.UUID, domainNames []string) (map[string]int, error) {
+	if exec == nil {
+		exec = s.db
+	}
+	query := `SELECT domain_name, COALESCE(attempts, 0) AS attempts FROM http_keyword_results
+	             WHERE http_keyword_campaign_id = $1 AND domain_name = ANY($2)`
+	return attemptsByDomain(ctx, exec, query, campaignID, domainNames)
+}
+
+// MarkHTTPKeywordMaxAttemptsExceeded marks the named domains' HTTP results as having reached the attempt cap
+func (s *campaignStorePostgres) MarkHTTPKeywordMaxAttemptsExceeded(ctx context.Context, exec store.Querier, campaignID uuid.UUID, domainNames []string) error {
+	if len(domainNames) == 0 {
+		return nil
+	}
+	if exec == nil {
+		exec = s.db
+	}
+	query := `UPDATE http_keyword_results SET validation_status = $1, last_checked_at = NOW()
+	             WHERE http_keyword_campaign_id = $2 AND domain_name = ANY($3)`
+	_, err := exec.ExecContext(ctx, query, string(models.ValidationStatusMaxAttemptsExceeded), campaignID, pq.Array(domainNames))
+	return err
+}
+
+// attemptsByDomain runs a (domain_name, attempts) query scoped to a campaign and a set of domain names
+func attemptsByDomain(ctx context.Context, exec store.Querier, query string, campaignID uuid.UUID, domainNames []string) (map[string]int, error) {
+	attempts := make(map[string]int, len(domainNames))
+	if len(domainNames) == 0 {
+		return attempts, nil
+	}
+	rows := []struct {
+		DomainName string `db:"domain_name"`
+		Attempts   int    `db:"attempts"`
+	}{}
+	if err := exec.SelectContext(ctx, &rows, query, campaignID, pq.Array(domainNames)); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		attempts[row.DomainName] = row.Attempts
+	}
+	return attempts, nil
+}
+
 func (s *campaignStorePostgres) GetDomainsForHTTPValidation(ctx context.Context, exec store.Querier, httpKeywordCampaignID uuid.UUID, sourceCampaignID uuid.UUID, limit int, lastDomainName string) ([]*models.DNSValidationResult, error) {
 	dnsResults := []*models.DNSValidationResult{}
 	query := `
 	       SELECT dvr.id, dvr.dns_campaign_id, dvr.generated_domain_id, dvr.domain_name, dvr.validation_status,
-	              dvr.dns_records, dvr.validated_by_persona_id, dvr.attempts, dvr.last_checked_at, dvr.created_at
+	              dvr.dns_records, dvr.consensus, dvr.expectation, dvr.validated_by_persona_id, dvr.attempts, dvr.last_checked_at, dvr.created_at
 	       FROM dns_validation_results dvr
 	       LEFT JOIN http_keyword_results hkr ON dvr.domain_name = hkr.domain_name AND hkr.http_keyword_campaign_id = $1
 	       WHERE dvr.dns_campaign_id = $2 AND dvr.validation_status = 'valid_dns'
 	         AND dvr.domain_name > $3
-	         AND (hkr.id IS NULL OR hkr.validation_status NOT IN ('lead_valid', 'http_valid_no_keywords'))
+	         AND (hkr.id IS NULL OR hkr.validation_status NOT IN ('lead_valid', 'http_valid_no_keywords', 'http_valid', 'max_attempts_exceeded'))
+	         AND NOT EXISTS (
+	               SELECT 1 FROM campaign_domain_dedup_decisions cdd
+	               WHERE cdd.campaign_id = $1 AND cdd.source_item_id = dvr.id AND cdd.decision = 'duplicate')
 	       ORDER BY dvr.domain_name ASC LIMIT $4`
 	err := exec.SelectContext(ctx, &dnsResults, query, httpKeywordCampaignID, sourceCampaignID, lastDomainName, limit)
 	return dnsResults, err
 }
 
+func (s *campaignStorePostgres) HTTPResumePointExists(ctx context.Context, exec store.Querier, sourceCampaignID uuid.UUID, domainName string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM dns_validation_results WHERE dns_campaign_id = $1 AND domain_name = $2)`
+	err := exec.GetContext(ctx, &exists, query, sourceCampaignID, domainName)
+	return exists, err
+}
+
+func (s *campaignStorePostgres) GetSafeHTTPResumePoint(ctx context.Context, exec store.Querier, httpKeywordCampaignID uuid.UUID, sourceCampaignID uuid.UUID, pointer string) (string, error) {
+	var safePoint string
+	query := `
+	       WITH candidates AS (
+	           SELECT dvr.domain_name,
+	                  EXISTS (SELECT 1 FROM http_keyword_results hkr
+	                          WHERE hkr.http_keyword_campaign_id = $1 AND hkr.domain_name = dvr.domain_name
+	                            AND hkr.validation_status IN ('lead_valid', 'http_valid_no_keywords', 'http_valid', 'max_attempts_exceeded')) AS finished
+	           FROM dns_validation_results dvr
+	           WHERE dvr.dns_campaign_id = $2 AND dvr.validation_status = 'valid_dns' AND dvr.domain_name < $3)
+	       SELECT COALESCE(MAX(domain_name), '') FROM candidates
+	       WHERE domain_name < COALESCE((SELECT MIN(domain_name) FROM candidates WHERE NOT finished), $3)`
+	err := exec.GetContext(ctx, &safePoint, query, httpKeywordCampaignID, sourceCampaignID, pointer)
+	return safePoint, err
+}
+
+// --- Domain Dedup Decisions --- //
+
+// CreateDomainDedupDecisions records dedup decisions. Items that already have a decision, and kept
+// claims for a normalized domain another item already holds, are left unchanged.
+func (s *campaignStorePostgres) CreateDomainDedupDecisions(ctx context.Context, exec store.Querier, decisions []*models.DomainDedupDecision) error {
+	if len(decisions) == 0 {
+		return nil
+	}
+	if exec == nil {
+		exec = s.db
+	}
+	query := `INSERT INTO campaign_domain_dedup_decisions
+	               (id, campaign_id, normalized_domain, domain_name, source_item_id, kept_item_id, decision, decided_at)
+	             VALUES (:id, :campaign_id, :normalized_domain, :domain_name, :source_item_id, :kept_item_id, :decision, :decided_at)
+	             ON CONFLICT DO NOTHING`
+	for _, decision := range decisions {
+		if decision.ID == uuid.Nil {
+			decision.ID = uuid.New()
+		}
+		if decision.DecidedAt.IsZero() {
+			decision.DecidedAt = time.Now().UTC()
+		}
+		if _, err := exec.NamedExecContext(ctx, query, decision); err != nil {
+			return fmt.Errorf("CreateDomainDedupDecisions: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetKeptDedupItems returns the kept item for each of the given normalized domains that already has one
+func (s *campaignStorePostgres) GetKeptDedupItems(ctx context.Context, exec store.Querier, campaignID uuid.UUID, normalizedDomains []string) (map[string]uuid.UUID, error) {
+	kept := make(map[string]uuid.UUID)
+	if len(normalizedDomains) == 0 {
+		return kept, nil
+	}
+	if exec == nil {
+		exec = s.db
+	}
+	rows := []struct {
+		NormalizedDomain string    `db:"normalized_domain"`
+		KeptItemID       uuid.UUID `db:"kept_item_id"`
+	}{}
+	query := `SELECT normalized_domain, kept_item_id FROM campaign_domain_dedup_decisions
+	             WHERE campaign_id = $1 AND decision = 'kept' AND normalized_domain = ANY($2)`
+	if err := exec.SelectContext(ctx, &rows, query, campaignID, pq.Array(normalizedDomains)); err != nil {
+		return nil, fmt.Errorf("GetKeptDedupItems: %w", err)
+	}
+	for _, row := range rows {
+		kept[row.NormalizedDomain] = row.KeptItemID
+	}
+	return kept, nil
+}
+
+func (s *campaignStorePostgres) ListDomainDedupDecisions(ctx context.Context, exec store.Querier, campaignID uuid.UUID, limit, offset int) ([]*models.DomainDedupDecision, error) {
+	if exec == nil {
+		exec = s.db
+	}
+	decisions := []*models.DomainDedupDecision{}
+	query := `SELECT id, campaign_id, normalized_domain, domain_name, source_item_id, kept_item_id, decision, decided_at
+	             FROM campaign_domain_dedup_decisions WHERE campaign_id = $1
+	             ORDER BY decided_at ASC, normalized_domain ASC LIMIT $2 OFFSET $3`
+	err := exec.SelectContext(ctx, &decisions, query, campaignID, limit, offset)
+	return decisions, err
+}
+
 var _ store.CampaignStore = (*campaignStorePostgres)(nil)
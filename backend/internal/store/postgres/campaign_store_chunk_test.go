@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newChunkTestResults(n int) []*models.DNSValidationResult {
+	campaignID := uuid.New()
+	results := make([]*models.DNSValidationResult, n)
+	for i := range results {
+		results[i] = &models.DNSValidationResult{
+			DNSCampaignID:    campaignID,
+			DomainName:       fmt.Sprintf("domain-%04d.com", i),
+			ValidationStatus: "valid_dns",
+			Attempts:         models.IntPtr(1),
+		}
+	}
+	return results
+}
+
+// expectDNSResultChunks registers one begin/prepare/exec.../commit sequence per chunk
+func expectDNSResultChunks(mock sqlmock.Sqlmock, total, chunkSize int) {
+	for start := 0; start < total; start += chunkSize {
+		end := start + chunkSize
+		if end > total {
+			end = total
+		}
+		mock.ExpectBegin()
+		prep := mock.ExpectPrepare("INSERT INTO dns_validation_results")
+		for i := start; i < end; i++ {
+			prep.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		mock.ExpectCommit()
+	}
+}
+
+func TestCreateDNSValidationResults_CommitsInChunks(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	db := sqlx.NewDb(mockDB, "postgres")
+
+	const total, chunkSize = 1050, 250
+	results := newChunkTestResults(total)
+	campaignStore := NewCampaignStorePostgres(db, WithResultCommitChunkSize(chunkSize))
+
+	expectDNSResultChunks(mock, total, chunkSize)
+	require.NoError(t, campaignStore.CreateDNSValidationResults(context.Background(), nil, results))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	ids := make(map[uuid.UUID]bool, total)
+	for _, r := range results {
+		ids[r.ID] = true
+	}
+	assert.Len(t, ids, total)
+
+	// Retrying the same batch upserts the same rows again in the same chunks without minting new IDs
+	expectDNSResultChunks(mock, total, chunkSize)
+	require.NoError(t, campaignStore.CreateDNSValidationResults(context.Background(), db, results))
+	require.NoError(t, mock.ExpectationsWereMet())
+	for _, r := range results {
+		assert.True(t, ids[r.ID], "result %s was assigned a new ID on retry", r.DomainName)
+	}
+}
+
+func TestCreateDNSValidationResults_ReportsCommittedChunksOnFailure(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	db := sqlx.NewDb(mockDB, "postgres")
+
+	const chunkSize = 100
+	results := newChunkTestResults(350)
+	campaignStore := NewCampaignStorePostgres(db, WithResultCommitChunkSize(chunkSize))
+
+	expectDNSResultChunks(mock, 200, chunkSize)
+	mock.ExpectBegin()
+	prep := mock.ExpectPrepare("INSERT INTO dns_validation_results")
+	prep.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	prep.ExpectExec().WillReturnError(errors.New("deadlock detected"))
+	mock.ExpectRollback()
+
+	err = campaignStore.CreateDNSValidationResults(context.Background(), nil, results)
+	require.Error(t, err)
+
+	var chunkErr *store.ChunkedWriteError
+	require.True(t, errors.As(err, &chunkErr))
+	assert.Equal(t, 2, chunkErr.CommittedChunks)
+	assert.Equal(t, 4, chunkErr.TotalChunks)
+	assert.Equal(t, 200, chunkErr.CommittedRows)
+	assert.ErrorContains(t, err, "deadlock detected")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateDNSValidationResults_CallerTransactionIsNotChunked(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	db := sqlx.NewDb(mockDB, "postgres")
+
+	results := newChunkTestResults(30)
+	campaignStore := NewCampaignStorePostgres(db, WithResultCommitChunkSize(10))
+
+	mock.ExpectBegin()
+	prep := mock.ExpectPrepare("INSERT INTO dns_validation_results")
+	for range results {
+		prep.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+
+	tx, err := db.Beginx()
+	require.NoError(t, err)
+	require.NoError(t, campaignStore.CreateDNSValidationResults(context.Background(), tx, results))
+	require.NoError(t, tx.Commit())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCampaignIDsUsingPersona(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	db := sqlx.NewDb(mockDB, "postgres")
+	campaignStore := NewCampaignStorePostgres(db)
+
+	personaID, dnsCampaign, httpCampaign := uuid.New(), uuid.New(), uuid.New()
+	mock.ExpectQuery(`FROM dns_validation_params WHERE \$1 = ANY\(persona_ids\)\s+UNION\s+SELECT campaign_id FROM http_keyword_campaign_params`).
+		WithArgs(personaID).
+		WillReturnRows(sqlmock.NewRows([]string{"campaign_id"}).AddRow(dnsCampaign).AddRow(httpCampaign))
+
+	ids, err := campaignStore.ListCampaignIDsUsingPersona(context.Background(), db, personaID)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{dnsCampaign, httpCampaign}, ids)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddDeletedResourceSnapshot(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	db := sqlx.NewDb(mockDB, "postgres")
+	campaignStore := NewCampaignStorePostgres(db)
+
+	snapshot := &models.DeletedResourceSnapshot{ResourceType: models.DeletedResourceProxy, ID: uuid.New(), Name: "residential",
+		Config: json.RawMessage(`{"address":"proxy.example:8080"}`), DeletedAt: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)}
+	encoded, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	first, second := uuid.New(), uuid.New()
+	mock.ExpectExec(`UPDATE campaigns SET\s+metadata = .*jsonb_build_object\('deletedResources'`).
+		WithArgs(snapshot.ID.String(), string(encoded), pq.StringArray{first.String(), second.String()}).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	require.NoError(t, campaignStore.AddDeletedResourceSnapshot(context.Background(), db, []uuid.UUID{first, second}, snapshot))
+	require.NoError(t, campaignStore.AddDeletedResourceSnapshot(context.Background(), db, nil, snapshot), "no campaigns, no query")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -200,6 +200,33 @@ func (s *CampaignStoreTestSuite) TestListCampaigns() {
 	assert.True(s.T(), found2, "Campaign 2 not found in list")
 }
 
+func (s *CampaignStoreTestSuite) TestListCampaignStatuses() {
+	t := s.T()
+	ctx := context.Background()
+	running := s.createTestCampaign(t, "Status Running", models.CampaignTypeDomainGeneration)
+	require.NoError(t, s.store.UpdateCampaignStatus(ctx, s.tx, running.ID, models.CampaignStatusRunning, sql.NullString{}))
+	require.NoError(t, s.store.UpdateCampaignProgress(ctx, s.tx, running.ID, 25, 100, 25))
+	pending := s.createTestCampaign(t, "Status Pending", models.CampaignTypeDNSValidation)
+
+	statuses, err := s.store.ListCampaignStatuses(ctx, s.tx, []uuid.UUID{running.ID, pending.ID, uuid.New()}, "")
+	require.NoError(t, err)
+	byID := map[uuid.UUID]store.CampaignStatusSummary{}
+	for _, status := range statuses {
+		byID[status.ID] = status
+	}
+	require.Len(t, byID, 2)
+	assert.Equal(t, models.CampaignStatusRunning, byID[running.ID].Status)
+	require.NotNil(t, byID[running.ID].ProgressPercentage)
+	assert.Equal(t, 25.0, *byID[running.ID].ProgressPercentage)
+	assert.Equal(t, models.CampaignStatusPending, byID[pending.ID].Status)
+
+	// Scoped to a user, other users' campaigns are left out
+	statuses, err = s.store.ListCampaignStatuses(ctx, s.tx, []uuid.UUID{running.ID, pending.ID}, running.UserID.String())
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, running.ID, statuses[0].ID)
+}
+
 func (s *CampaignStoreTestSuite) TestUpdateCampaignStatus() {
 	// Create a test campaign
 	campaign := s.createTestCampaign(s.T(), "Test Status Update", models.CampaignTypeDomainGeneration)
@@ -345,6 +372,63 @@ func (s *CampaignStoreTestSuite) TestCampaignPagination() {
 	assert.Equal(s.T(), int64(15), count)
 }
 
+func (s *CampaignStoreTestSuite) TestListCampaignsByTags() {
+	t := s.T()
+	ctx := context.Background()
+	tagged := func(name string, tags ...string) *models.Campaign {
+		campaign := s.createTestCampaign(t, name, models.CampaignTypeDomainGeneration)
+		campaign.Tags = tags
+		require.NoError(t, s.store.UpdateCampaign(ctx, s.tx, campaign))
+		return campaign
+	}
+	acmeProd := tagged("Acme prod", "client-acme", "production")
+	acmeStaging := tagged("Acme staging", "client-acme", "staging")
+	globexProd := tagged("Globex prod", "client-globex", "production")
+	untagged := s.createTestCampaign(t, "Untagged", models.CampaignTypeDomainGeneration)
+
+	ids := func(campaigns []*models.Campaign) []uuid.UUID {
+		out := make([]uuid.UUID, 0, len(campaigns))
+		for _, c := range campaigns {
+			out = append(out, c.ID)
+		}
+		return out
+	}
+
+	retrieved, err := s.store.GetCampaignByID(ctx, s.tx, acmeProd.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"client-acme", "production"}, []string(retrieved.Tags))
+	retrieved, err = s.store.GetCampaignByID(ctx, s.tx, untagged.ID)
+	require.NoError(t, err)
+	assert.Empty(t, retrieved.Tags)
+
+	// Match any: campaigns with at least one of the tags
+	anyFilter := store.ListCampaignsFilter{Tags: []string{"staging", "client-globex"}}
+	campaigns, err := s.store.ListCampaigns(ctx, s.tx, anyFilter)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{acmeStaging.ID, globexProd.ID}, ids(campaigns))
+	count, err := s.store.CountCampaigns(ctx, s.tx, anyFilter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// Match all: campaigns with every tag
+	allFilter := store.ListCampaignsFilter{Tags: []string{"client-acme", "production"}, TagMatch: store.TagMatchAll}
+	campaigns, err = s.store.ListCampaigns(ctx, s.tx, allFilter)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{acmeProd.ID}, ids(campaigns))
+	count, err = s.store.CountCampaigns(ctx, s.tx, allFilter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// The count ignores pagination but keeps the tag filter
+	pagedFilter := store.ListCampaignsFilter{Tags: []string{"production"}, Limit: 1}
+	campaigns, err = s.store.ListCampaigns(ctx, s.tx, pagedFilter)
+	require.NoError(t, err)
+	assert.Len(t, campaigns, 1)
+	count, err = s.store.CountCampaigns(ctx, s.tx, pagedFilter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
 func (s *CampaignStoreTestSuite) TestTransactionRollback() {
 	// Test transaction rollback on error
 	tx, err := s.db.BeginTxx(context.Background(), nil)
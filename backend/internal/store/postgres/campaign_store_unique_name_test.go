@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUniqueNameTestCampaign() *models.Campaign {
+	userID := uuid.New()
+	now := time.Now().UTC()
+	return &models.Campaign{
+		ID:           uuid.New(),
+		Name:         "Spring Launch",
+		CampaignType: models.CampaignTypeDomainGeneration,
+		Status:       models.CampaignStatusPending,
+		UserID:       &userID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+func newUniqueNameTestStore(t *testing.T, unique bool) (store.CampaignStore, *sqlx.DB, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+	db := sqlx.NewDb(mockDB, "postgres")
+	return NewCampaignStorePostgres(db, WithUniqueCampaignNames(unique)), db, mock
+}
+
+func TestCreateCampaign_RejectsTakenNameWhenUnique(t *testing.T) {
+	campaignStore, db, mock := newUniqueNameTestStore(t, true)
+	campaign := newUniqueNameTestCampaign()
+
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs(*campaign.UserID, campaign.Name).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	err := campaignStore.CreateCampaign(context.Background(), db, campaign)
+	assert.ErrorIs(t, err, store.ErrCampaignNameTaken)
+	assert.ErrorIs(t, err, store.ErrDuplicateEntry)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT EXISTS").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec("INSERT INTO campaigns").WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, campaignStore.CreateCampaign(context.Background(), db, campaign))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateCampaign_SkipsNameCheckWhenNotUnique(t *testing.T) {
+	campaignStore, db, mock := newUniqueNameTestStore(t, false)
+
+	mock.ExpectExec("INSERT INTO campaigns").WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, campaignStore.CreateCampaign(context.Background(), db, newUniqueNameTestCampaign()))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateCampaign_RejectsRenameToTakenName(t *testing.T) {
+	campaignStore, db, mock := newUniqueNameTestStore(t, true)
+	campaign := newUniqueNameTestCampaign()
+
+	// The guarded update matches nothing, but the campaign exists, so the name was the problem
+	mock.ExpectExec("UPDATE campaigns SET .* NOT EXISTS").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs(campaign.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	assert.ErrorIs(t, campaignStore.UpdateCampaign(context.Background(), db, campaign), store.ErrCampaignNameTaken)
+
+	mock.ExpectExec("UPDATE campaigns SET .* NOT EXISTS").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs(campaign.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	assert.ErrorIs(t, campaignStore.UpdateCampaign(context.Background(), db, campaign), store.ErrNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateCampaign_SkipsNameCheckWhenNotUnique(t *testing.T) {
+	campaignStore, db, mock := newUniqueNameTestStore(t, false)
+
+	mock.ExpectExec(`UPDATE campaigns SET .* WHERE id = \$16$`).WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, campaignStore.UpdateCampaign(context.Background(), db, newUniqueNameTestCampaign()))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
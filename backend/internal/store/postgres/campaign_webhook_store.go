@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// campaignWebhookStorePostgres implements the store.CampaignWebhookStore interface
+type campaignWebhookStorePostgres struct {
+	db *sqlx.DB
+}
+
+// NewCampaignWebhookStorePostgres creates a new CampaignWebhookStore for PostgreSQL
+func NewCampaignWebhookStorePostgres(db *sqlx.DB) store.CampaignWebhookStore {
+	return &campaignWebhookStorePostgres{db: db}
+}
+
+func (s *campaignWebhookStorePostgres) querier(exec store.Querier) store.Querier {
+	if exec == nil {
+		return s.db
+	}
+	return exec
+}
+
+func (s *campaignWebhookStorePostgres) CreateCampaignWebhook(ctx context.Context, exec store.Querier, webhook *models.CampaignWebhook) error {
+	if webhook.ID == uuid.Nil {
+		webhook.ID = uuid.New()
+	}
+	if webhook.CreatedAt.IsZero() {
+		webhook.CreatedAt = time.Now().UTC()
+	}
+	query := `INSERT INTO campaign_webhooks (id, campaign_id, url, secret, created_by, notified_status, notified_at, created_at)
+			  VALUES (:id, :campaign_id, :url, :secret, :created_by, :notified_status, :notified_at, :created_at)`
+	_, err := s.querier(exec).NamedExecContext(ctx, query, webhook)
+	return err
+}
+
+func (s *campaignWebhookStorePostgres) GetCampaignWebhookByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.CampaignWebhook, error) {
+	webhook := &models.CampaignWebhook{}
+	query := `SELECT id, campaign_id, url, secret, created_by, notified_status, notified_at, created_at
+			  FROM campaign_webhooks WHERE id = $1`
+	err := s.querier(exec).GetContext(ctx, webhook, query, id)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (s *campaignWebhookStorePostgres) DeleteCampaignWebhook(ctx context.Context, exec store.Querier, campaignID, webhookID uuid.UUID) error {
+	result, err := s.querier(exec).ExecContext(ctx, `DELETE FROM campaign_webhooks WHERE id = $1 AND campaign_id = $2`, webhookID, campaignID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err == nil && rowsAffected == 0 {
+		return store.ErrNotFound
+	}
+	return err
+}
+
+func (s *campaignWebhookStorePostgres) ListCampaignWebhooksToNotify(ctx context.Context, exec store.Querier, limit int) ([]*models.CampaignWebhook, error) {
+	webhooks := []*models.CampaignWebhook{}
+	query := `SELECT w.id, w.campaign_id, w.url, w.secret, w.created_by, w.notified_status, w.notified_at, w.created_at
+			  FROM campaign_webhooks w JOIN campaigns c ON c.id = w.campaign_id
+			  WHERE c.status IN ('completed', 'failed', 'cancelled') AND w.notified_status IS DISTINCT FROM c.status
+			  ORDER BY c.updated_at ASC, w.created_at ASC
+			  LIMIT $1`
+	if err := s.querier(exec).SelectContext(ctx, &webhooks, query, limit); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (s *campaignWebhookStorePostgres) MarkCampaignWebhookNotified(ctx context.Context, exec store.Querier, webhookID uuid.UUID, status models.CampaignStatusEnum, notifiedAt time.Time) (bool, error) {
+	query := `UPDATE campaign_webhooks SET notified_status = $2, notified_at = $3
+			  WHERE id = $1 AND notified_status IS DISTINCT FROM $2`
+	result, err := s.querier(exec).ExecContext(ctx, query, webhookID, string(status), notifiedAt)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+var _ store.CampaignWebhookStore = (*campaignWebhookStorePostgres)(nil)
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// eventDeliveryStorePostgres implements the store.EventDeliveryStore interface
+type eventDeliveryStorePostgres struct {
+	db *sqlx.DB
+}
+
+// NewEventDeliveryStorePostgres creates a new EventDeliveryStore for PostgreSQL
+func NewEventDeliveryStorePostgres(db *sqlx.DB) store.EventDeliveryStore {
+	return &eventDeliveryStorePostgres{db: db}
+}
+
+func (s *eventDeliveryStorePostgres) querier(exec store.Querier) store.Querier {
+	if exec == nil {
+		return s.db
+	}
+	return exec
+}
+
+func (s *eventDeliveryStorePostgres) CreateEventDelivery(ctx context.Context, exec store.Querier, delivery *models.EventDelivery) error {
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = now
+	}
+	delivery.UpdatedAt = now
+	if delivery.Status == "" {
+		delivery.Status = models.EventDeliveryStatusPending
+	}
+	query := `INSERT INTO event_deliveries (id, event_type, target_url, payload, status, attempts, last_status_code, last_error, last_attempted_at, delivered_at, created_at, updated_at, campaign_webhook_id)
+			  VALUES (:id, :event_type, :target_url, :payload, :status, :attempts, :last_status_code, :last_error, :last_attempted_at, :delivered_at, :created_at, :updated_at, :campaign_webhook_id)`
+	_, err := s.querier(exec).NamedExecContext(ctx, query, delivery)
+	return err
+}
+
+func (s *eventDeliveryStorePostgres) GetEventDeliveryByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.EventDelivery, error) {
+	delivery := &models.EventDelivery{}
+	query := `SELECT id, event_type, target_url, payload, status, attempts, last_status_code, last_error, last_attempted_at, delivered_at, created_at, updated_at, campaign_webhook_id
+			  FROM event_deliveries WHERE id = $1`
+	err := s.querier(exec).GetContext(ctx, delivery, query, id)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	return delivery, err
+}
+
+func (s *eventDeliveryStorePostgres) UpdateEventDelivery(ctx context.Context, exec store.Querier, delivery *models.EventDelivery) error {
+	delivery.UpdatedAt = time.Now().UTC()
+	query := `UPDATE event_deliveries SET
+				status = :status, attempts = :attempts, last_status_code = :last_status_code, last_error = :last_error,
+				last_attempted_at = :last_attempted_at, delivered_at = :delivered_at, updated_at = :updated_at
+			  WHERE id = :id`
+	result, err := s.querier(exec).NamedExecContext(ctx, query, delivery)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err == nil && rowsAffected == 0 {
+		return store.ErrNotFound
+	}
+	return err
+}
+
+func (s *eventDeliveryStorePostgres) ListEventDeliveries(ctx context.Context, exec store.Querier, filter store.ListEventDeliveriesFilter) ([]*models.EventDelivery, error) {
+	baseQuery := `SELECT id, event_type, target_url, payload, status, attempts, last_status_code, last_error, last_attempted_at, delivered_at, created_at, updated_at, campaign_webhook_id
+				  FROM event_deliveries`
+	args := []interface{}{}
+	conditions := []string{}
+
+	if filter.EventType != "" {
+		conditions = append(conditions, "event_type = ?")
+		args = append(args, filter.EventType)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.TargetURL != "" {
+		conditions = append(conditions, "target_url = ?")
+		args = append(args, filter.TargetURL)
+	}
+
+	finalQuery := baseQuery
+	if len(conditions) > 0 {
+		finalQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	finalQuery += " ORDER BY created_at DESC"
+
+	if filter.Limit > 0 {
+		finalQuery += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+	if filter.Offset > 0 {
+		finalQuery += " OFFSET ?"
+		args = append(args, filter.Offset)
+	}
+
+	deliveries := []*models.EventDelivery{}
+	err := s.querier(exec).SelectContext(ctx, &deliveries, sqlx.Rebind(sqlx.DOLLAR, finalQuery), args...)
+	if err != nil {
+		return nil, fmt.Errorf("ListEventDeliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (s *eventDeliveryStorePostgres) CreateEventDeliveryAttempt(ctx context.Context, exec store.Querier, attempt *models.EventDeliveryAttempt) error {
+	if attempt.ID == uuid.Nil {
+		attempt.ID = uuid.New()
+	}
+	if attempt.AttemptedAt.IsZero() {
+		attempt.AttemptedAt = time.Now().UTC()
+	}
+	query := `INSERT INTO event_delivery_attempts (id, delivery_id, attempt_number, status_code, error, duration_ms, is_replay, attempted_at)
+			  VALUES (:id, :delivery_id, :attempt_number, :status_code, :error, :duration_ms, :is_replay, :attempted_at)`
+	_, err := s.querier(exec).NamedExecContext(ctx, query, attempt)
+	return err
+}
+
+func (s *eventDeliveryStorePostgres) ListEventDeliveryAttempts(ctx context.Context, exec store.Querier, deliveryID uuid.UUID) ([]*models.EventDeliveryAttempt, error) {
+	attempts := []*models.EventDeliveryAttempt{}
+	query := `SELECT id, delivery_id, attempt_number, status_code, error, duration_ms, is_replay, attempted_at
+			  FROM event_delivery_attempts WHERE delivery_id = $1 ORDER BY attempt_number ASC`
+	err := s.querier(exec).SelectContext(ctx, &attempts, query, deliveryID)
+	return attempts, err
+}
+
+var _ store.EventDeliveryStore = (*eventDeliveryStorePostgres)(nil)
@@ -34,8 +34,8 @@ func (s *keywordStorePostgres) BeginTxx(ctx context.Context, opts *sql.TxOptions
 // --- KeywordSet CRUD --- //
 
 func (s *keywordStorePostgres) CreateKeywordSet(ctx context.Context, exec store.Querier, keywordSet *models.KeywordSet) error {
-	query := `INSERT INTO keyword_sets (id, name, description, is_enabled, created_at, updated_at)
-              VALUES (:id, :name, :description, :is_enabled, :created_at, :updated_at)`
+	query := `INSERT INTO keyword_sets (id, name, description, is_enabled, match_type, created_at, updated_at)
+              VALUES (:id, :name, :description, :is_enabled, COALESCE(NULLIF(:match_type, ''), 'substring'), :created_at, :updated_at)`
 	_, err := exec.NamedExecContext(ctx, query, keywordSet)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // 23505 is unique_violation
@@ -47,7 +47,7 @@ func (s *keywordStorePostgres) CreateKeywordSet(ctx context.Context, exec store.
 
 func (s *keywordStorePostgres) GetKeywordSetByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.KeywordSet, error) {
 	keywordSet := &models.KeywordSet{}
-	query := `SELECT id, name, description, is_enabled, created_at, updated_at
+	query := `SELECT id, name, description, is_enabled, match_type, created_at, updated_at
               FROM keyword_sets WHERE id = $1`
 	err := exec.GetContext(ctx, keywordSet, query, id)
 	if err == sql.ErrNoRows {
@@ -58,7 +58,7 @@ func (s *keywordStorePostgres) GetKeywordSetByID(ctx context.Context, exec store
 
 func (s *keywordStorePostgres) GetKeywordSetByName(ctx context.Context, exec store.Querier, name string) (*models.KeywordSet, error) {
 	keywordSet := &models.KeywordSet{}
-	query := `SELECT id, name, description, is_enabled, created_at, updated_at
+	query := `SELECT id, name, description, is_enabled, match_type, created_at, updated_at
               FROM keyword_sets WHERE name = $1`
 	err := exec.GetContext(ctx, keywordSet, query, name)
 	if err == sql.ErrNoRows {
@@ -72,6 +72,7 @@ func (s *keywordStorePostgres) UpdateKeywordSet(ctx context.Context, exec store.
                 name = :name,
                 description = :description,
                 is_enabled = :is_enabled,
+                match_type = COALESCE(NULLIF(:match_type, ''), 'substring'),
                 updated_at = :updated_at
               WHERE id = :id`
 	result, err := exec.NamedExecContext(ctx, query, keywordSet)
@@ -156,7 +157,7 @@ func (s *keywordStorePostgres) listKeywordSetsWithDB(ctx context.Context, db *sq
 	}()
 
 	// Safely build the query with defensive programming
-	baseQuery := `SELECT id, name, description, is_enabled, created_at, updated_at FROM keyword_sets`
+	baseQuery := `SELECT id, name, description, is_enabled, match_type, created_at, updated_at FROM keyword_sets`
 
 	// Initialize args and conditions
 	args := []interface{}{}
@@ -219,7 +220,7 @@ func (s *keywordStorePostgres) listKeywordSetsWithExec(ctx context.Context, exec
 	}
 
 	// Safely build the query with defensive programming
-	baseQuery := `SELECT id, name, description, is_enabled, created_at, updated_at FROM keyword_sets`
+	baseQuery := `SELECT id, name, description, is_enabled, match_type, created_at, updated_at FROM keyword_sets`
 	if baseQuery == "" {
 		return keywordSets, fmt.Errorf("failed to initialize base query")
 	}
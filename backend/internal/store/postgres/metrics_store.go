@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/jmoiron/sqlx"
+)
+
+// metricsStorePostgres implements the store.MetricsStore interface
+type metricsStorePostgres struct {
+	db *sqlx.DB
+}
+
+// NewMetricsStorePostgres creates a new MetricsStore for PostgreSQL
+func NewMetricsStorePostgres(db *sqlx.DB) store.MetricsStore {
+	return &metricsStorePostgres{db: db}
+}
+
+func (s *metricsStorePostgres) querier(exec store.Querier) store.Querier {
+	if exec == nil {
+		return s.db
+	}
+	return exec
+}
+
+type statusCount struct {
+	Status string `db:"status"`
+	Count  int64  `db:"count"`
+}
+
+func (s *metricsStorePostgres) countByStatus(ctx context.Context, exec store.Querier, table string) ([]statusCount, error) {
+	var rows []statusCount
+	err := s.querier(exec).SelectContext(ctx, &rows, `SELECT status, COUNT(*) AS count FROM `+table+` GROUP BY status`)
+	return rows, err
+}
+
+func (s *metricsStorePostgres) CountCampaignsByStatus(ctx context.Context, exec store.Querier) (map[models.CampaignStatusEnum]int64, error) {
+	rows, err := s.countByStatus(ctx, exec, "campaigns")
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[models.CampaignStatusEnum]int64, len(rows))
+	for _, row := range rows {
+		counts[models.CampaignStatusEnum(row.Status)] = row.Count
+	}
+	return counts, nil
+}
+
+func (s *metricsStorePostgres) CountCampaignJobsByStatus(ctx context.Context, exec store.Querier) (map[models.CampaignJobStatusEnum]int64, error) {
+	rows, err := s.countByStatus(ctx, exec, "campaign_jobs")
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[models.CampaignJobStatusEnum]int64, len(rows))
+	for _, row := range rows {
+		counts[models.CampaignJobStatusEnum(row.Status)] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// passwordMigrationStorePostgres implements the store.PasswordMigrationStore interface
+type passwordMigrationStorePostgres struct {
+	db *sqlx.DB
+}
+
+// NewPasswordMigrationStorePostgres creates a new PasswordMigrationStore for PostgreSQL
+func NewPasswordMigrationStorePostgres(db *sqlx.DB) store.PasswordMigrationStore {
+	return &passwordMigrationStorePostgres{db: db}
+}
+
+func (s *passwordMigrationStorePostgres) querier(exec store.Querier) store.Querier {
+	if exec == nil {
+		return s.db
+	}
+	return exec
+}
+
+func (s *passwordMigrationStorePostgres) ListLegacyPepperUsers(ctx context.Context, exec store.Querier, currentPepperVersion int) ([]*models.UserPasswordPepperState, error) {
+	users := []*models.UserPasswordPepperState{}
+	query := `SELECT id, password_pepper_version, password_changed_at, must_change_password
+	          FROM auth.users
+	          WHERE is_active = TRUE AND password_pepper_version < $1
+	          ORDER BY password_changed_at ASC, id ASC`
+	err := s.querier(exec).SelectContext(ctx, &users, query, currentPepperVersion)
+	return users, err
+}
+
+func (s *passwordMigrationStorePostgres) RequirePasswordChange(ctx context.Context, exec store.Querier, userIDs []uuid.UUID) (int64, error) {
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+	query := `UPDATE auth.users
+	          SET must_change_password = TRUE, password_migration_required = TRUE, updated_at = NOW()
+	          WHERE id = ANY($1::uuid[]) AND must_change_password = FALSE`
+	result, err := s.querier(exec).ExecContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
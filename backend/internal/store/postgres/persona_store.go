@@ -30,8 +30,8 @@ func (s *personaStorePostgres) BeginTxx(ctx context.Context, opts *sql.TxOptions
 }
 
 func (s *personaStorePostgres) CreatePersona(ctx context.Context, exec store.Querier, persona *models.Persona) error {
-	query := `INSERT INTO personas (id, name, persona_type, description, config_details, is_enabled, created_at, updated_at)
-			  VALUES (:id, :name, :persona_type, :description, :config_details, :is_enabled, :created_at, :updated_at)`
+	query := `INSERT INTO personas (id, name, persona_type, description, config_details, is_enabled, owner_id, shared, created_at, updated_at)
+			  VALUES (:id, :name, :persona_type, :description, :config_details, :is_enabled, :owner_id, :shared, :created_at, :updated_at)`
 	_, err := exec.NamedExecContext(ctx, query, persona)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // 23505 is unique_violation
@@ -43,7 +43,7 @@ func (s *personaStorePostgres) CreatePersona(ctx context.Context, exec store.Que
 
 func (s *personaStorePostgres) GetPersonaByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Persona, error) {
 	persona := &models.Persona{}
-	query := `SELECT id, name, persona_type, description, config_details, is_enabled, created_at, updated_at 
+	query := `SELECT id, name, persona_type, description, config_details, is_enabled, owner_id, shared, created_at, updated_at 
 			  FROM personas WHERE id = $1`
 	err := exec.GetContext(ctx, persona, query, id)
 	if err == sql.ErrNoRows {
@@ -54,7 +54,7 @@ func (s *personaStorePostgres) GetPersonaByID(ctx context.Context, exec store.Qu
 
 func (s *personaStorePostgres) GetPersonaByName(ctx context.Context, exec store.Querier, name string) (*models.Persona, error) {
 	persona := &models.Persona{}
-	query := `SELECT id, name, persona_type, description, config_details, is_enabled, created_at, updated_at 
+	query := `SELECT id, name, persona_type, description, config_details, is_enabled, owner_id, shared, created_at, updated_at 
 			  FROM personas WHERE name = $1`
 	err := exec.GetContext(ctx, persona, query, name)
 	if err == sql.ErrNoRows {
@@ -70,6 +70,7 @@ func (s *personaStorePostgres) UpdatePersona(ctx context.Context, exec store.Que
 				description = :description, 
 				config_details = :config_details, 
 				is_enabled = :is_enabled, 
+				shared = :shared, 
 				updated_at = :updated_at
 			  WHERE id = :id`
 	result, err := exec.NamedExecContext(ctx, query, persona)
@@ -100,7 +101,7 @@ func (s *personaStorePostgres) DeletePersona(ctx context.Context, exec store.Que
 }
 
 func (s *personaStorePostgres) ListPersonas(ctx context.Context, exec store.Querier, filter store.ListPersonasFilter) ([]*models.Persona, error) {
-	baseQuery := `SELECT id, name, persona_type, description, config_details, is_enabled, created_at, updated_at FROM personas`
+	baseQuery := `SELECT id, name, persona_type, description, config_details, is_enabled, owner_id, shared, created_at, updated_at FROM personas`
 	args := []interface{}{}
 	conditions := []string{}
 
@@ -112,6 +113,10 @@ func (s *personaStorePostgres) ListPersonas(ctx context.Context, exec store.Quer
 		conditions = append(conditions, "is_enabled = ?")
 		args = append(args, *filter.IsEnabled)
 	}
+	if filter.AccessibleTo.Valid {
+		conditions = append(conditions, "(shared OR owner_id = ?)")
+		args = append(args, filter.AccessibleTo.UUID)
+	}
 
 	finalQuery := baseQuery
 	if len(conditions) > 0 {
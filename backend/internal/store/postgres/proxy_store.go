@@ -32,8 +32,8 @@ func (s *proxyStorePostgres) BeginTxx(ctx context.Context, opts *sql.TxOptions)
 }
 
 func (s *proxyStorePostgres) CreateProxy(ctx context.Context, exec store.Querier, proxy *models.Proxy) error {
-	query := `INSERT INTO proxies (id, name, description, address, protocol, username, password_hash, host, port, is_enabled, is_healthy, last_status, last_checked_at, latency_ms, city, country_code, provider, created_at, updated_at)
-	             VALUES (:id, :name, :description, :address, :protocol, :username, :password_hash, :host, :port, :is_enabled, :is_healthy, :last_status, :last_checked_at, :latency_ms, :city, :country_code, :provider, :created_at, :updated_at)`
+	query := `INSERT INTO proxies (id, name, description, address, protocol, username, password_hash, host, port, is_enabled, is_healthy, last_status, last_checked_at, latency_ms, city, country_code, provider, owner_id, shared, created_at, updated_at)
+	             VALUES (:id, :name, :description, :address, :protocol, :username, :password_hash, :host, :port, :is_enabled, :is_healthy, :last_status, :last_checked_at, :latency_ms, :city, :country_code, :provider, :owner_id, :shared, :created_at, :updated_at)`
 	_, err := exec.NamedExecContext(ctx, query, proxy)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // 23505 is unique_violation
@@ -45,7 +45,7 @@ func (s *proxyStorePostgres) CreateProxy(ctx context.Context, exec store.Querier
 
 func (s *proxyStorePostgres) GetProxyByID(ctx context.Context, exec store.Querier, id uuid.UUID) (*models.Proxy, error) {
 	proxy := &models.Proxy{}
-	query := `SELECT id, name, description, address, protocol, username, password_hash, host, port, is_enabled, is_healthy, last_status, last_checked_at, latency_ms, city, country_code, provider, created_at, updated_at
+	query := `SELECT id, name, description, address, protocol, username, password_hash, host, port, is_enabled, is_healthy, last_status, last_checked_at, latency_ms, city, country_code, provider, owner_id, shared, created_at, updated_at
 	                FROM proxies WHERE id = $1`
 	err := exec.GetContext(ctx, proxy, query, id)
 	if err == sql.ErrNoRows {
@@ -72,6 +72,7 @@ func (s *proxyStorePostgres) UpdateProxy(ctx context.Context, exec store.Querier
 	                  city = :city,
 	                  country_code = :country_code,
 	                  provider = :provider,
+	                  shared = :shared,
 	                  updated_at = :updated_at
 	                WHERE id = :id`
 	result, err := exec.NamedExecContext(ctx, query, proxy)
@@ -101,8 +102,8 @@ func (s *proxyStorePostgres) DeleteProxy(ctx context.Context, exec store.Querier
 	return err
 }
 
-func (s *proxyStorePostgres) ListProxies(ctx context.Context, exec store.Querier, filter store.ListProxiesFilter) ([]*models.Proxy, error) {
-	baseQuery := `SELECT id, name, description, address, protocol, username, password_hash, host, port, is_enabled, is_healthy, last_status, last_checked_at, latency_ms, city, country_code, provider, created_at, updated_at FROM proxies`
+// proxyFilterConditions builds the WHERE conditions shared by ListProxies and CountProxies
+func proxyFilterConditions(filter store.ListProxiesFilter) ([]string, []interface{}) {
 	args := []interface{}{}
 	conditions := []string{}
 
@@ -118,13 +119,43 @@ func (s *proxyStorePostgres) ListProxies(ctx context.Context, exec store.Querier
 		conditions = append(conditions, "is_healthy = ?")
 		args = append(args, *filter.IsHealthy)
 	}
+	if filter.CountryCode != "" {
+		conditions = append(conditions, "UPPER(country_code) = UPPER(?)")
+		args = append(args, filter.CountryCode)
+	}
+	if filter.Provider != "" {
+		conditions = append(conditions, "LOWER(provider) = LOWER(?)")
+		args = append(args, filter.Provider)
+	}
+	if filter.AccessibleTo.Valid {
+		conditions = append(conditions, "(shared OR owner_id = ?)")
+		args = append(args, filter.AccessibleTo.UUID)
+	}
+	return conditions, args
+}
+
+// proxyOrderClause returns the ORDER BY clause for a filter. Only whitelisted columns are used.
+func proxyOrderClause(filter store.ListProxiesFilter) string {
+	direction := "ASC"
+	if strings.EqualFold(filter.SortOrder, "desc") {
+		direction = "DESC"
+	}
+	if filter.SortBy == "latency" {
+		return " ORDER BY latency_ms " + direction + " NULLS LAST, name ASC"
+	}
+	return " ORDER BY name " + direction
+}
+
+func (s *proxyStorePostgres) ListProxies(ctx context.Context, exec store.Querier, filter store.ListProxiesFilter) ([]*models.Proxy, error) {
+	baseQuery := `SELECT id, name, description, address, protocol, username, password_hash, host, port, is_enabled, is_healthy, last_status, last_checked_at, latency_ms, city, country_code, provider, owner_id, shared, created_at, updated_at FROM proxies`
+	conditions, args := proxyFilterConditions(filter)
 
 	finalQuery := baseQuery
 	if len(conditions) > 0 {
 		finalQuery += " WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	finalQuery += " ORDER BY name ASC"
+	finalQuery += proxyOrderClause(filter)
 
 	if filter.Limit > 0 {
 		finalQuery += " LIMIT ?"
@@ -150,14 +181,37 @@ func (s *proxyStorePostgres) ListProxies(ctx context.Context, exec store.Querier
 	return proxies, err
 }
 
-func (s *proxyStorePostgres) UpdateProxyHealth(ctx context.Context, exec store.Querier, id uuid.UUID, isHealthy bool, latencyMs sql.NullInt32, lastCheckedAt time.Time) error {
+func (s *proxyStorePostgres) CountProxies(ctx context.Context, exec store.Querier, filter store.ListProxiesFilter) (int64, error) {
+	conditions, args := proxyFilterConditions(filter)
+	finalQuery := `SELECT COUNT(*) FROM proxies`
+	if len(conditions) > 0 {
+		finalQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var reboundQuery string
+	switch q := exec.(type) {
+	case *sqlx.DB:
+		reboundQuery = q.Rebind(finalQuery)
+	case *sqlx.Tx:
+		reboundQuery = q.Rebind(finalQuery)
+	default:
+		return 0, fmt.Errorf("unexpected Querier type for Rebind: %T", exec)
+	}
+
+	var count int64
+	err := exec.GetContext(ctx, &count, reboundQuery, args...)
+	return count, err
+}
+
+func (s *proxyStorePostgres) UpdateProxyHealth(ctx context.Context, exec store.Querier, id uuid.UUID, isHealthy bool, lastStatus string, latencyMs sql.NullInt32, lastCheckedAt time.Time) error {
 	query := `UPDATE proxies SET
                 is_healthy = $1,
                 last_checked_at = $2,
                 latency_ms = $3,
+                last_status = $4,
                 updated_at = NOW()
-              WHERE id = $4`
-	result, err := exec.ExecContext(ctx, query, isHealthy, lastCheckedAt, latencyMs, id)
+              WHERE id = $5`
+	result, err := exec.ExecContext(ctx, query, isHealthy, lastCheckedAt, latencyMs, lastStatus, id)
 	if err != nil {
 		return err
 	}
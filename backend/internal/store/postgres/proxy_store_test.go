@@ -277,6 +277,81 @@ func TestProxyStore_ListProxies(t *testing.T) {
 	})
 }
 
+func TestProxyStore_ListProxies_LocationFiltersAndLatencySort(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Skipping Postgres tests as TEST_POSTGRES_DSN is not set.")
+	}
+	proxyStore := NewProxyStorePostgres(testDB)
+	ctx := context.Background()
+	clearProxiesTable(t)
+
+	latency := func(ms int32) sql.NullInt32 { return sql.NullInt32{Int32: ms, Valid: true} }
+	text := func(v string) sql.NullString { return sql.NullString{String: v, Valid: true} }
+	proxiesData := []models.Proxy{
+		{ID: uuid.New(), Name: "Slow US", Address: "http://us1.com:80", Protocol: models.ProxyProtocolEnumPtr(models.ProxyProtocolHTTP), IsEnabled: true, IsHealthy: true, LatencyMs: latency(900), CountryCode: text("US"), Provider: text("Acme"), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ID: uuid.New(), Name: "Fast US", Address: "http://us2.com:80", Protocol: models.ProxyProtocolEnumPtr(models.ProxyProtocolHTTP), IsEnabled: true, IsHealthy: true, LatencyMs: latency(40), CountryCode: text("US"), Provider: text("Globex"), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ID: uuid.New(), Name: "Medium DE", Address: "socks5://de1.com:1080", Protocol: models.ProxyProtocolEnumPtr(models.ProxyProtocolSOCKS5), IsEnabled: true, IsHealthy: false, LatencyMs: latency(300), CountryCode: text("DE"), Provider: text("Acme"), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ID: uuid.New(), Name: "Unchecked DE", Address: "http://de2.com:80", Protocol: models.ProxyProtocolEnumPtr(models.ProxyProtocolHTTP), IsEnabled: false, IsHealthy: true, CountryCode: text("DE"), CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+	for i := range proxiesData {
+		require.NoError(t, proxyStore.CreateProxy(ctx, testDB, &proxiesData[i]))
+	}
+
+	names := func(proxies []*models.Proxy) []string {
+		out := make([]string, 0, len(proxies))
+		for _, p := range proxies {
+			out = append(out, p.Name)
+		}
+		return out
+	}
+
+	t.Run("filter by country code is case-insensitive", func(t *testing.T) {
+		filter := store.ListProxiesFilter{CountryCode: "de"}
+		listed, err := proxyStore.ListProxies(ctx, testDB, filter)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Medium DE", "Unchecked DE"}, names(listed))
+
+		count, err := proxyStore.CountProxies(ctx, testDB, filter)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("filter by provider", func(t *testing.T) {
+		listed, err := proxyStore.ListProxies(ctx, testDB, store.ListProxiesFilter{Provider: "acme"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Medium DE", "Slow US"}, names(listed))
+	})
+
+	t.Run("combined filters", func(t *testing.T) {
+		enabled, healthy := true, true
+		filter := store.ListProxiesFilter{CountryCode: "US", IsEnabled: &enabled, IsHealthy: &healthy, Protocol: models.ProxyProtocolHTTP}
+		listed, err := proxyStore.ListProxies(ctx, testDB, filter)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Fast US", "Slow US"}, names(listed))
+	})
+
+	t.Run("sort by latency puts unchecked proxies last", func(t *testing.T) {
+		listed, err := proxyStore.ListProxies(ctx, testDB, store.ListProxiesFilter{SortBy: "latency"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Fast US", "Medium DE", "Slow US", "Unchecked DE"}, names(listed))
+
+		listed, err = proxyStore.ListProxies(ctx, testDB, store.ListProxiesFilter{SortBy: "latency", SortOrder: "desc"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Slow US", "Medium DE", "Fast US", "Unchecked DE"}, names(listed))
+	})
+
+	t.Run("count ignores limit and offset", func(t *testing.T) {
+		filter := store.ListProxiesFilter{SortBy: "latency", Limit: 1, Offset: 1}
+		listed, err := proxyStore.ListProxies(ctx, testDB, filter)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Medium DE"}, names(listed))
+
+		count, err := proxyStore.CountProxies(ctx, testDB, filter)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), count)
+	})
+}
+
 func TestProxyStore_UpdateProxyHealth(t *testing.T) {
 	if testDB == nil {
 		t.Skip("Skipping Postgres tests as TEST_POSTGRES_DSN is not set.")
@@ -301,12 +376,13 @@ func TestProxyStore_UpdateProxyHealth(t *testing.T) {
 	newLatency := sql.NullInt32{Int32: 120, Valid: true}
 	newLastCheckedAt := time.Now().UTC().Truncate(time.Millisecond)
 
-	err = proxyStore.UpdateProxyHealth(ctx, testDB, proxy.ID, newHealth, newLatency, newLastCheckedAt)
+	err = proxyStore.UpdateProxyHealth(ctx, testDB, proxy.ID, newHealth, "Active", newLatency, newLastCheckedAt)
 	assert.NoError(t, err)
 
 	updated, err := proxyStore.GetProxyByID(ctx, testDB, proxy.ID)
 	require.NoError(t, err)
 	assert.Equal(t, newHealth, updated.IsHealthy)
+	assert.Equal(t, "Active", updated.LastStatus.String)
 	if newLatency.Valid {
 		assert.True(t, updated.LatencyMs.Valid)
 		assert.Equal(t, newLatency.Int32, updated.LatencyMs.Int32)
@@ -317,7 +393,7 @@ func TestProxyStore_UpdateProxyHealth(t *testing.T) {
 	assert.True(t, updated.UpdatedAt.After(proxy.CreatedAt), "UpdatedAt should have been modified")
 
 	// Test update health of non-existent proxy
-	err = proxyStore.UpdateProxyHealth(ctx, testDB, uuid.New(), true, sql.NullInt32{}, time.Now())
+	err = proxyStore.UpdateProxyHealth(ctx, testDB, uuid.New(), true, "Active", sql.NullInt32{}, time.Now())
 	assert.ErrorIs(t, err, store.ErrNotFound)
 }
 
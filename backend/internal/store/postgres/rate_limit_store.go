@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/jmoiron/sqlx"
+)
+
+// rateLimitStorePostgres implements the store.RateLimitStore interface on auth.rate_limits
+type rateLimitStorePostgres struct {
+	db *sqlx.DB
+}
+
+// NewRateLimitStorePostgres creates a new RateLimitStore for PostgreSQL
+func NewRateLimitStorePostgres(db *sqlx.DB) store.RateLimitStore {
+	return &rateLimitStorePostgres{db: db}
+}
+
+func (s *rateLimitStorePostgres) querier(exec store.Querier) store.Querier {
+	if exec == nil {
+		return s.db
+	}
+	return exec
+}
+
+func (s *rateLimitStorePostgres) GetRateLimit(ctx context.Context, exec store.Querier, identifier, action string) (*models.RateLimit, error) {
+	limit := &models.RateLimit{}
+	err := s.querier(exec).GetContext(ctx, limit, `SELECT id, identifier, action, attempts, window_start, blocked_until
+	          FROM auth.rate_limits WHERE identifier = $1 AND action = $2`, identifier, action)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return limit, nil
+}
+
+func (s *rateLimitStorePostgres) RecordRateLimitAttempt(ctx context.Context, exec store.Querier, identifier, action string, now, windowStart time.Time, maxAttempts int, blockedUntil time.Time) (*models.RateLimit, error) {
+	// A count whose window has passed starts over at one attempt, keeping any block still in force
+	limit := &models.RateLimit{}
+	err := s.querier(exec).GetContext(ctx, limit, `INSERT INTO auth.rate_limits AS rl (identifier, action, attempts, window_start, blocked_until)
+	          VALUES ($1, $2, 1, $3, CASE WHEN $5 <= 1 THEN $6::timestamp END)
+	          ON CONFLICT (identifier, action) DO UPDATE
+	          SET attempts = CASE WHEN rl.window_start < $4 THEN 1 ELSE rl.attempts + 1 END,
+	              window_start = CASE WHEN rl.window_start < $4 THEN $3 ELSE rl.window_start END,
+	              blocked_until = CASE
+	                  WHEN (CASE WHEN rl.window_start < $4 THEN 1 ELSE rl.attempts + 1 END) >= $5 THEN $6
+	                  ELSE rl.blocked_until
+	              END
+	          RETURNING id, identifier, action, attempts, window_start, blocked_until`,
+		identifier, action, now.UTC(), windowStart.UTC(), maxAttempts, blockedUntil.UTC())
+	if err != nil {
+		return nil, err
+	}
+	return limit, nil
+}
+
+func (s *rateLimitStorePostgres) DeleteRateLimit(ctx context.Context, exec store.Querier, identifier, action string) error {
+	_, err := s.querier(exec).ExecContext(ctx, `DELETE FROM auth.rate_limits WHERE identifier = $1 AND action = $2`, identifier, action)
+	return err
+}
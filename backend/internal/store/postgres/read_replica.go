@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/jmoiron/sqlx"
+)
+
+// DefaultReplicaRetryInterval is how long reads stay on the primary after the replica failed
+const DefaultReplicaRetryInterval = 30 * time.Second
+
+// ReadRoutingQuerier is a store.Querier that sends reads to a read replica and writes to the
+// primary. A read that fails on the replica is retried on the primary, and further reads go to the
+// primary until the retry interval has passed. Transactions are begun by the stores on the primary
+// and never pass through this querier.
+type ReadRoutingQuerier struct {
+	primary       *sqlx.DB
+	replica       *sqlx.DB
+	retryInterval time.Duration
+	replicaDownAt atomic.Int64 // Unix nanoseconds of the last replica failure, 0 while it is healthy
+	now           func() time.Time
+}
+
+var _ store.Querier = (*ReadRoutingQuerier)(nil)
+
+// NewReadRoutingQuerier returns nil when replica is nil, so callers keep using the primary
+func NewReadRoutingQuerier(primary, replica *sqlx.DB, retryInterval time.Duration) *ReadRoutingQuerier {
+	if replica == nil {
+		return nil
+	}
+	if retryInterval <= 0 {
+		retryInterval = DefaultReplicaRetryInterval
+	}
+	return &ReadRoutingQuerier{primary: primary, replica: replica, retryInterval: retryInterval, now: time.Now}
+}
+
+// GetContext reads from the replica, falling back to the primary
+func (q *ReadRoutingQuerier) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return q.read(ctx, func(db *sqlx.DB) error {
+		return db.GetContext(ctx, dest, query, args...)
+	})
+}
+
+// SelectContext reads from the replica, falling back to the primary
+func (q *ReadRoutingQuerier) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return q.read(ctx, func(db *sqlx.DB) error {
+		return db.SelectContext(ctx, dest, query, args...)
+	})
+}
+
+// NamedExecContext always runs on the primary
+func (q *ReadRoutingQuerier) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	return q.primary.NamedExecContext(ctx, query, arg)
+}
+
+// ExecContext always runs on the primary
+func (q *ReadRoutingQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return q.primary.ExecContext(ctx, query, args...)
+}
+
+// PrepareNamedContext always prepares on the primary, as the statement may write
+func (q *ReadRoutingQuerier) PrepareNamedContext(ctx context.Context, query string) (*sqlx.NamedStmt, error) {
+	return q.primary.PrepareNamedContext(ctx, query)
+}
+
+// Rebind converts ? placeholders for the primary's driver, which the replica shares
+func (q *ReadRoutingQuerier) Rebind(query string) string {
+	return q.primary.Rebind(query)
+}
+
+// read runs fn on the replica unless it failed within the retry interval. sql.ErrNoRows and a
+// cancelled caller are answers, not replica failures, and are returned as they are.
+func (q *ReadRoutingQuerier) read(ctx context.Context, fn func(db *sqlx.DB) error) error {
+	if downAt := q.replicaDownAt.Load(); downAt != 0 && q.now().Sub(time.Unix(0, downAt)) < q.retryInterval {
+		return fn(q.primary)
+	}
+	err := fn(q.replica)
+	if err == nil || errors.Is(err, sql.ErrNoRows) || ctx.Err() != nil {
+		q.replicaDownAt.Store(0)
+		return err
+	}
+	q.replicaDownAt.Store(q.now().UnixNano())
+	log.Printf("ReadRoutingQuerier: Read replica failed, reading from the primary for %s: %v", q.retryInterval, err)
+	return fn(q.primary)
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openMockDSN opens a sqlx handle on a sqlmock connection registered under dsn
+func openMockDSN(t *testing.T, dsn string) (*sqlx.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	_, mock, err := sqlmock.NewWithDSN(dsn)
+	require.NoError(t, err)
+	conn, err := sql.Open("sqlmock", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return sqlx.NewDb(conn, "postgres"), mock
+}
+
+func newReplicaTestStore(t *testing.T) (*campaignStorePostgres, *sqlx.DB, sqlmock.Sqlmock, sqlmock.Sqlmock) {
+	primary, primaryMock := openMockDSN(t, "primary-"+uuid.NewString())
+	replica, replicaMock := openMockDSN(t, "replica-"+uuid.NewString())
+	cs := NewCampaignStorePostgres(primary, WithReadReplica(replica, time.Minute)).(*campaignStorePostgres)
+	require.NotNil(t, cs.reads)
+	return cs, primary, primaryMock, replicaMock
+}
+
+var countCampaignsQuery = regexp.QuoteMeta(`SELECT COUNT(*) FROM campaigns`)
+
+func TestReadReplica_ReadsHitReplicaAndWritesHitPrimary(t *testing.T) {
+	ctx := context.Background()
+	cs, primary, primaryMock, replicaMock := newReplicaTestStore(t)
+
+	replicaMock.ExpectQuery(countCampaignsQuery).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+	replicaMock.ExpectQuery(regexp.QuoteMeta(`FROM campaigns WHERE status = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(uuid.New(), "Nightly"))
+	primaryMock.ExpectExec(regexp.QuoteMeta(`UPDATE campaigns SET status = $1`)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	count, err := cs.CountCampaigns(ctx, primary, store.ListCampaignsFilter{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 7, count)
+
+	campaigns, err := cs.ListCampaigns(ctx, nil, store.ListCampaignsFilter{Status: models.CampaignStatusRunning, SortBy: "name"})
+	require.NoError(t, err)
+	require.Len(t, campaigns, 1)
+	assert.Equal(t, "Nightly", campaigns[0].Name)
+
+	require.NoError(t, cs.UpdateCampaignStatus(ctx, primary, uuid.New(), models.CampaignStatusPaused, sql.NullString{}))
+
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+}
+
+func TestReadReplica_TransactionsStayOnPrimary(t *testing.T) {
+	ctx := context.Background()
+	cs, _, primaryMock, replicaMock := newReplicaTestStore(t)
+
+	primaryMock.ExpectBegin()
+	primaryMock.ExpectQuery(countCampaignsQuery).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	primaryMock.ExpectCommit()
+
+	tx, err := cs.BeginTxx(ctx, nil)
+	require.NoError(t, err)
+	count, err := cs.CountCampaigns(ctx, tx, store.ListCampaignsFilter{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, count)
+	require.NoError(t, tx.Commit())
+
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+}
+
+func TestReadReplica_FallsBackToPrimaryWhenReplicaFails(t *testing.T) {
+	ctx := context.Background()
+	cs, primary, primaryMock, replicaMock := newReplicaTestStore(t)
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	cs.reads.now = func() time.Time { return now }
+	countRows := func(n int) *sqlmock.Rows { return sqlmock.NewRows([]string{"count"}).AddRow(n) }
+
+	// The failed read is answered by the primary, and so is the next one within the retry interval
+	replicaMock.ExpectQuery(countCampaignsQuery).WillReturnError(errors.New("dial tcp: connection refused"))
+	primaryMock.ExpectQuery(countCampaignsQuery).WillReturnRows(countRows(5))
+	primaryMock.ExpectQuery(countCampaignsQuery).WillReturnRows(countRows(6))
+	for _, want := range []int64{5, 6} {
+		count, err := cs.CountCampaigns(ctx, primary, store.ListCampaignsFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, want, count)
+	}
+	require.NoError(t, replicaMock.ExpectationsWereMet())
+	require.NoError(t, primaryMock.ExpectationsWereMet())
+
+	// Once the interval has passed the replica is tried again
+	now = now.Add(time.Minute)
+	replicaMock.ExpectQuery(countCampaignsQuery).WillReturnRows(countRows(8))
+	count, err := cs.CountCampaigns(ctx, primary, store.ListCampaignsFilter{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 8, count)
+
+	// A missing row is an answer, not a replica failure
+	replicaMock.ExpectQuery(countCampaignsQuery).WillReturnError(sql.ErrNoRows)
+	_, err = cs.CountCampaigns(ctx, primary, store.ListCampaignsFilter{})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+}
+
+func TestNewReadRoutingQuerier_NilReplica(t *testing.T) {
+	assert.Nil(t, NewReadRoutingQuerier(&sqlx.DB{}, nil, 0))
+}
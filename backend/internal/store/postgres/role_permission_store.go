@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// rolePermissionStorePostgres implements the store.RolePermissionStore interface
+type rolePermissionStorePostgres struct {
+	db *sqlx.DB
+}
+
+// NewRolePermissionStorePostgres creates a new RolePermissionStore for PostgreSQL
+func NewRolePermissionStorePostgres(db *sqlx.DB) store.RolePermissionStore {
+	return &rolePermissionStorePostgres{db: db}
+}
+
+func (s *rolePermissionStorePostgres) querier(exec store.Querier) store.Querier {
+	if exec == nil {
+		return s.db
+	}
+	return exec
+}
+
+func (s *rolePermissionStorePostgres) EnsurePermission(ctx context.Context, exec store.Querier, perm *models.Permission) (bool, error) {
+	q := s.querier(exec)
+	if perm.ID == uuid.Nil {
+		perm.ID = uuid.New()
+	}
+	if perm.CreatedAt.IsZero() {
+		perm.CreatedAt = time.Now().UTC()
+	}
+	query := `INSERT INTO auth.permissions (id, name, display_name, description, resource, action, created_at)
+			  VALUES (:id, :name, :display_name, :description, :resource, :action, :created_at)
+			  ON CONFLICT DO NOTHING`
+	result, err := q.NamedExecContext(ctx, query, perm)
+	if err != nil {
+		return false, err
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows > 0 {
+		return true, nil
+	}
+
+	// The permission already exists (possibly under a different ID); use the stored row
+	var existingID uuid.UUID
+	err = q.GetContext(ctx, &existingID, `SELECT id FROM auth.permissions WHERE resource = $1 AND action = $2`, perm.Resource, perm.Action)
+	if err == sql.ErrNoRows {
+		err = q.GetContext(ctx, &existingID, `SELECT id FROM auth.permissions WHERE name = $1`, perm.Name)
+	}
+	if err == sql.ErrNoRows {
+		return false, store.ErrNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+	perm.ID = existingID
+	return false, nil
+}
+
+func (s *rolePermissionStorePostgres) GetRoleByName(ctx context.Context, exec store.Querier, name string) (*models.Role, error) {
+	role := &models.Role{}
+	query := `SELECT id, name, display_name, description, is_system_role, created_at, updated_at
+			  FROM auth.roles WHERE name = $1`
+	err := s.querier(exec).GetContext(ctx, role, query, name)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	return role, err
+}
+
+func (s *rolePermissionStorePostgres) GrantPermissionToRole(ctx context.Context, exec store.Querier, roleID, permissionID uuid.UUID) (bool, error) {
+	query := `INSERT INTO auth.role_permissions (role_id, permission_id) VALUES ($1, $2)
+			  ON CONFLICT (role_id, permission_id) DO NOTHING`
+	result, err := s.querier(exec).ExecContext(ctx, query, roleID, permissionID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// settingsStorePostgres implements the store.SettingsStore interface
+type settingsStorePostgres struct {
+	db *sqlx.DB
+}
+
+// NewSettingsStorePostgres creates a new SettingsStore for PostgreSQL
+func NewSettingsStorePostgres(db *sqlx.DB) store.SettingsStore {
+	return &settingsStorePostgres{db: db}
+}
+
+func (s *settingsStorePostgres) querier(exec store.Querier) store.Querier {
+	if exec == nil {
+		return s.db
+	}
+	return exec
+}
+
+func (s *settingsStorePostgres) GetSetting(ctx context.Context, exec store.Querier, key string) (*models.Setting, error) {
+	setting := &models.Setting{}
+	query := `SELECT key, value, updated_by, updated_at FROM settings WHERE key = $1`
+	err := s.querier(exec).GetContext(ctx, setting, query, key)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	return setting, err
+}
+
+func (s *settingsStorePostgres) ListSettings(ctx context.Context, exec store.Querier) ([]*models.Setting, error) {
+	settings := []*models.Setting{}
+	query := `SELECT key, value, updated_by, updated_at FROM settings ORDER BY key`
+	err := s.querier(exec).SelectContext(ctx, &settings, query)
+	return settings, err
+}
+
+func (s *settingsStorePostgres) UpsertSetting(ctx context.Context, exec store.Querier, setting *models.Setting) error {
+	if setting.UpdatedAt.IsZero() {
+		setting.UpdatedAt = time.Now().UTC()
+	}
+	query := `INSERT INTO settings (key, value, updated_by, updated_at)
+			  VALUES (:key, :value, :updated_by, :updated_at)
+			  ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at`
+	_, err := s.querier(exec).NamedExecContext(ctx, query, setting)
+	return err
+}
@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// userAccountStorePostgres implements the store.UserAccountStore interface
+type userAccountStorePostgres struct {
+	db *sqlx.DB
+}
+
+// NewUserAccountStorePostgres creates a new UserAccountStore for PostgreSQL
+func NewUserAccountStorePostgres(db *sqlx.DB) store.UserAccountStore {
+	return &userAccountStorePostgres{db: db}
+}
+
+func (s *userAccountStorePostgres) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error) {
+	return s.db.BeginTxx(ctx, opts)
+}
+
+func (s *userAccountStorePostgres) querier(exec store.Querier) store.Querier {
+	if exec == nil {
+		return s.db
+	}
+	return exec
+}
+
+// userAccountConditions returns the WHERE clause of the filter and its arguments
+func userAccountConditions(filter store.ListUserAccountsFilter) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+	if filter.IsActive != nil {
+		conditions = append(conditions, "u.is_active = ?")
+		args = append(args, *filter.IsActive)
+	}
+	if filter.IsLocked != nil {
+		conditions = append(conditions, "u.is_locked = ?")
+		args = append(args, *filter.IsLocked)
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (s *userAccountStorePostgres) ListUserAccounts(ctx context.Context, exec store.Querier, filter store.ListUserAccountsFilter) ([]*models.UserAccount, error) {
+	where, args := userAccountConditions(filter)
+	query := `SELECT u.id, u.email, u.email_verified, u.first_name, u.last_name, u.avatar_url, u.is_active,
+	                 u.is_locked, u.failed_login_attempts, u.last_login_at, u.mfa_enabled, u.created_at, u.updated_at,
+	                 ARRAY(SELECT r.name FROM auth.user_roles ur JOIN auth.roles r ON r.id = ur.role_id
+	                       WHERE ur.user_id = u.id AND (ur.expires_at IS NULL OR ur.expires_at > NOW())
+	                       ORDER BY r.name)::text[] AS roles
+	          FROM auth.users u` + where + ` ORDER BY u.created_at DESC, u.id`
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, filter.Offset)
+	}
+	accounts := []*models.UserAccount{}
+	err := s.querier(exec).SelectContext(ctx, &accounts, s.db.Rebind(query), args...)
+	return accounts, err
+}
+
+func (s *userAccountStorePostgres) CountUserAccounts(ctx context.Context, exec store.Querier, filter store.ListUserAccountsFilter) (int64, error) {
+	where, args := userAccountConditions(filter)
+	var count int64
+	err := s.querier(exec).GetContext(ctx, &count, s.db.Rebind("SELECT COUNT(*) FROM auth.users u"+where), args...)
+	return count, err
+}
+
+func (s *userAccountStorePostgres) GetUserSecurityStatus(ctx context.Context, exec store.Querier, userID uuid.UUID) (*models.UserSecurityStatus, error) {
+	status := &models.UserSecurityStatus{}
+	query := `SELECT id, email, is_active, is_locked, locked_until, failed_login_attempts,
+	                 last_login_at, host(last_login_ip) AS last_login_ip
+	          FROM auth.users WHERE id = $1`
+	err := s.querier(exec).GetContext(ctx, status, query, userID)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	return status, err
+}
+
+func (s *userAccountStorePostgres) UnlockUserAccount(ctx context.Context, exec store.Querier, userID uuid.UUID, unlockedAt time.Time) (*models.UserAccountUnlock, error) {
+	q := s.querier(exec)
+	var before struct {
+		IsLocked            bool `db:"is_locked"`
+		FailedLoginAttempts int  `db:"failed_login_attempts"`
+	}
+	err := q.GetContext(ctx, &before, `SELECT is_locked, failed_login_attempts FROM auth.users WHERE id = $1 FOR UPDATE`, userID)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := q.ExecContext(ctx, `UPDATE auth.users
+	          SET is_locked = FALSE, locked_until = NULL, failed_login_attempts = 0, updated_at = $2
+	          WHERE id = $1`, userID, unlockedAt); err != nil {
+		return nil, err
+	}
+
+	// Rate limits are keyed by user ID or by address; clear those of the addresses the user last
+	// logged in from or failed to log in from
+	result, err := q.ExecContext(ctx, `DELETE FROM auth.rate_limits
+	          WHERE identifier = $1::text
+	             OR identifier IN (SELECT host(last_login_ip) FROM auth.users
+	                               WHERE id = $1 AND last_login_ip IS NOT NULL
+	                               UNION
+	                               SELECT host(ip_address) FROM auth.auth_audit_log
+	                               WHERE user_id = $1 AND event_type = 'login' AND event_status = 'failure'
+	                                 AND ip_address IS NOT NULL)`, userID)
+	if err != nil {
+		return nil, err
+	}
+	cleared, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UserAccountUnlock{
+		UserID:              userID,
+		WasLocked:           before.IsLocked,
+		FailedLoginAttempts: before.FailedLoginAttempts,
+		RateLimitsCleared:   cleared,
+		UnlockedAt:          unlockedAt,
+	}, nil
+}
+
+func (s *userAccountStorePostgres) SetUserActive(ctx context.Context, exec store.Querier, userID uuid.UUID, active bool, changedAt time.Time) (bool, error) {
+	var wasActive bool
+	err := s.querier(exec).GetContext(ctx, &wasActive, `SELECT is_active FROM auth.users WHERE id = $1 FOR UPDATE`, userID)
+	if err == sql.ErrNoRows {
+		return false, store.ErrNotFound
+	}
+	if err != nil || wasActive == active {
+		return false, err
+	}
+	_, err = s.querier(exec).ExecContext(ctx, `UPDATE auth.users SET is_active = $2, updated_at = $3 WHERE id = $1`,
+		userID, active, changedAt)
+	return err == nil, err
+}
+
+func (s *userAccountStorePostgres) CreateAuthAuditLog(ctx context.Context, exec store.Querier, entry *models.AuthAuditLog) error {
+	query := `INSERT INTO auth.auth_audit_log
+	          (user_id, session_id, event_type, event_status, ip_address, user_agent, details, risk_score, created_at)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	          RETURNING id`
+	return s.querier(exec).GetContext(ctx, &entry.ID, query, entry.UserID, entry.SessionID, entry.EventType,
+		entry.EventStatus, entry.IPAddress, entry.UserAgent, entry.Details, entry.RiskScore, entry.CreatedAt)
+}
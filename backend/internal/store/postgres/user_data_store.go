@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// userDataStorePostgres implements the store.UserDataStore interface
+type userDataStorePostgres struct {
+	db *sqlx.DB
+}
+
+// NewUserDataStorePostgres creates a new UserDataStore for PostgreSQL
+func NewUserDataStorePostgres(db *sqlx.DB) store.UserDataStore {
+	return &userDataStorePostgres{db: db}
+}
+
+func (s *userDataStorePostgres) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error) {
+	return s.db.BeginTxx(ctx, opts)
+}
+
+func (s *userDataStorePostgres) querier(exec store.Querier) store.Querier {
+	if exec == nil {
+		return s.db
+	}
+	return exec
+}
+
+func (s *userDataStorePostgres) GetUserDataProfile(ctx context.Context, exec store.Querier, userID uuid.UUID) (*models.UserDataProfile, error) {
+	profile := &models.UserDataProfile{}
+	query := `SELECT u.id, u.email, u.email_verified, u.first_name, u.last_name, u.avatar_url, u.is_active, u.is_locked,
+	                 u.last_login_at, host(u.last_login_ip) AS last_login_ip, u.password_changed_at, u.must_change_password,
+	                 u.mfa_enabled, u.created_at, u.updated_at,
+	                 ARRAY(SELECT r.name FROM auth.user_roles ur JOIN auth.roles r ON r.id = ur.role_id
+	                       WHERE ur.user_id = u.id ORDER BY r.name)::text[] AS roles
+	          FROM auth.users u WHERE u.id = $1`
+	err := s.querier(exec).GetContext(ctx, profile, query, userID)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	return profile, err
+}
+
+func (s *userDataStorePostgres) ListUserDataSessions(ctx context.Context, exec store.Querier, userID uuid.UUID, limit, offset int) ([]*models.UserDataSession, error) {
+	sessions := []*models.UserDataSession{}
+	query := `SELECT host(ip_address) AS ip_address, user_agent, COALESCE(is_active, FALSE) AS is_active,
+	                 expires_at, last_activity_at, created_at
+	          FROM auth.sessions WHERE user_id = $1
+	          ORDER BY created_at ASC, id ASC LIMIT $2 OFFSET $3`
+	err := s.querier(exec).SelectContext(ctx, &sessions, query, userID, limit, offset)
+	return sessions, err
+}
+
+func (s *userDataStorePostgres) EraseUserData(ctx context.Context, exec store.Querier, userID uuid.UUID, erasedAt time.Time) (*models.UserDataErasure, error) {
+	q := s.querier(exec)
+	result := &models.UserDataErasure{UserID: userID, ErasedAt: erasedAt}
+
+	// The account row stays so campaigns and audit entries keep a valid owner; everything that
+	// identifies the person is replaced and the account can no longer sign in.
+	res, err := q.ExecContext(ctx, `UPDATE auth.users SET
+	           email = $2, email_verified = FALSE, email_verification_token = NULL, email_verification_expires_at = NULL,
+	           password_hash = '', first_name = 'Erased', last_name = 'User', avatar_url = NULL,
+	           is_active = FALSE, last_login_ip = NULL, mfa_enabled = FALSE, updated_at = $3
+	         WHERE id = $1`, userID, fmt.Sprintf("erased-%s@erased.invalid", userID), erasedAt)
+	if err != nil {
+		return nil, fmt.Errorf("EraseUserData: anonymize user: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return nil, store.ErrNotFound
+	}
+
+	steps := []struct {
+		name  string
+		query string
+		count *int64
+	}{
+		{"delete sessions", `DELETE FROM auth.sessions WHERE user_id = $1`, &result.SessionsDeleted},
+		{"delete reset tokens", `DELETE FROM auth.password_reset_tokens WHERE user_id = $1`, &result.ResetTokensDeleted},
+		{"scrub audit logs", `UPDATE audit_logs SET client_ip = NULL, user_agent = NULL,
+		           details = CASE WHEN entity_type = 'User' AND entity_id = $1 THEN NULL ELSE details END
+		         WHERE user_id = $1 OR (entity_type = 'User' AND entity_id = $1)`, &result.AuditLogsScrubbed},
+		{"scrub auth events", `UPDATE auth.auth_audit_log SET ip_address = NULL, user_agent = NULL,
+		           session_fingerprint = NULL, details = NULL
+		         WHERE user_id = $1`, &result.AuthEventsScrubbed},
+	}
+	for _, step := range steps {
+		res, err := q.ExecContext(ctx, step.query, userID)
+		if err != nil {
+			return nil, fmt.Errorf("EraseUserData: %s: %w", step.name, err)
+		}
+		*step.count, _ = res.RowsAffected()
+	}
+
+	if err := q.GetContext(ctx, &result.CampaignsRetained, `SELECT COUNT(*) FROM campaigns WHERE user_id = $1`, userID); err != nil {
+		return nil, fmt.Errorf("EraseUserData: count campaigns: %w", err)
+	}
+	return result, nil
+}
+
+// Ensure userDataStorePostgres implements store.UserDataStore
+var _ store.UserDataStore = (*userDataStorePostgres)(nil)
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/fntelecomllc/studio/backend/internal/models"
+	"github.com/fntelecomllc/studio/backend/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserDataStore_EraseUserData(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Skipping Postgres tests as TEST_POSTGRES_DSN is not set.")
+	}
+	ctx := context.Background()
+	userDataStore := NewUserDataStorePostgres(testDB)
+	auditStore := NewAuditLogStorePostgres(testDB)
+	campaignStore := NewCampaignStorePostgres(testDB)
+
+	userID := uuid.New()
+	_, err := testDB.ExecContext(ctx, `INSERT INTO auth.users (id, email, password_hash, first_name, last_name, avatar_url, last_login_ip)
+	                                   VALUES ($1, $2, 'hash', 'Sam', 'Subject', 'https://example.com/sam.png', '203.0.113.7')`,
+		userID, "sam-"+userID.String()+"@example.com")
+	require.NoError(t, err)
+	_, err = testDB.ExecContext(ctx, `INSERT INTO auth.sessions (id, user_id, ip_address, user_agent, expires_at)
+	                                   VALUES ($1, $2, '203.0.113.7', 'agent', NOW() + INTERVAL '1 hour')`, uuid.NewString(), userID)
+	require.NoError(t, err)
+	require.NoError(t, auditStore.CreateAuditLog(ctx, nil, &models.AuditLog{
+		UserID:     uuid.NullUUID{UUID: userID, Valid: true},
+		Action:     "Create User",
+		EntityType: sql.NullString{String: "User", Valid: true},
+		EntityID:   uuid.NullUUID{UUID: userID, Valid: true},
+		Details:    models.JSONRawMessagePtr([]byte(`{"email":"sam@example.com"}`)),
+		ClientIP:   sql.NullString{String: "203.0.113.7", Valid: true},
+		UserAgent:  sql.NullString{String: "agent", Valid: true},
+	}))
+	now := time.Now().UTC()
+	require.NoError(t, campaignStore.CreateCampaign(ctx, testDB, &models.Campaign{
+		ID: uuid.New(), Name: "owned", CampaignType: models.CampaignTypeDomainGeneration,
+		Status: models.CampaignStatusCompleted, UserID: &userID, CreatedAt: now, UpdatedAt: now,
+	}))
+
+	result, err := userDataStore.EraseUserData(ctx, testDB, userID, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.SessionsDeleted)
+	assert.Equal(t, int64(1), result.AuditLogsScrubbed)
+	assert.Equal(t, int64(1), result.CampaignsRetained)
+
+	profile, err := userDataStore.GetUserDataProfile(ctx, testDB, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "erased-"+userID.String()+"@erased.invalid", profile.Email)
+	assert.Equal(t, "Erased", profile.FirstName)
+	assert.Nil(t, profile.AvatarURL)
+	assert.Nil(t, profile.LastLoginIP)
+	assert.False(t, profile.IsActive)
+
+	logs, err := auditStore.ListAuditLogs(ctx, testDB, store.ListAuditLogsFilter{UserID: userID.String()})
+	require.NoError(t, err)
+	require.Len(t, logs, 1, "audit entries are kept for aggregate integrity")
+	assert.False(t, logs[0].ClientIP.Valid)
+	assert.Nil(t, logs[0].Details)
+
+	campaigns, err := campaignStore.ListCampaigns(ctx, testDB, store.ListCampaignsFilter{UserID: userID.String()})
+	require.NoError(t, err)
+	assert.Len(t, campaigns, 1, "the user's campaigns are retained")
+
+	_, err = userDataStore.EraseUserData(ctx, testDB, uuid.New(), now)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
@@ -0,0 +1,272 @@
+package tracing
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WrapConnector traces the queries run on connections of c. Each query within a traced request or
+// job gets a span named after the function that ran it, such as
+// postgres.campaignJobStorePostgres.GetNextQueuedJob, carrying the statement and the rows it
+// affected or returned. Queries outside a trace, like the workers' polling, are not traced.
+//
+// The wrapped driver must support contexts, as lib/pq does.
+func WrapConnector(c driver.Connector) driver.Connector {
+	return &tracedConnector{Connector: c}
+}
+
+type tracedConnector struct {
+	driver.Connector
+}
+
+func (c *tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{Conn: conn}, nil
+}
+
+type tracedConn struct {
+	driver.Conn
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span := startQuerySpan(ctx, query)
+	rows, err := queryer.QueryContext(ctx, query, args)
+	return traceRows(span, rows, err)
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span := startQuerySpan(ctx, query)
+	result, err := execer.ExecContext(ctx, query, args)
+	return traceResult(span, result, err)
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tracedStmt{Stmt: stmt, query: query}, nil
+}
+
+func (c *tracedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+type tracedStmt struct {
+	driver.Stmt
+	query string
+}
+
+func (s *tracedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, span := startQuerySpan(ctx, s.query)
+	var rows driver.Rows
+	var err error
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		rows, err = s.Stmt.Query(namedValues(args))
+	}
+	return traceRows(span, rows, err)
+}
+
+func (s *tracedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	ctx, span := startQuerySpan(ctx, s.query)
+	var result driver.Result
+	var err error
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		result, err = execer.ExecContext(ctx, args)
+	} else {
+		result, err = s.Stmt.Exec(namedValues(args))
+	}
+	return traceResult(span, result, err)
+}
+
+func namedValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
+
+// tracedRows ends its query's span when closed, with the number of rows read
+type tracedRows struct {
+	driver.Rows
+	span trace.Span
+	read int64
+	err  error
+}
+
+func (r *tracedRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	if err == nil {
+		r.read++
+	} else if err != io.EOF {
+		r.err = err
+	}
+	return err
+}
+
+func (r *tracedRows) Close() error {
+	err := r.Rows.Close()
+	r.span.SetAttributes(attribute.Int64("db.response.returned_rows", r.read))
+	if r.err == nil {
+		r.err = err
+	}
+	End(r.span, r.err)
+	return err
+}
+
+func (r *tracedRows) ColumnTypeScanType(index int) reflect.Type {
+	if typed, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return typed.ColumnTypeScanType(index)
+	}
+	return reflect.TypeOf(new(any)).Elem()
+}
+
+func (r *tracedRows) ColumnTypeDatabaseTypeName(index int) string {
+	if typed, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return typed.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+func traceRows(span trace.Span, rows driver.Rows, err error) (driver.Rows, error) {
+	if span == nil {
+		return rows, err
+	}
+	if err != nil {
+		End(span, err)
+		return nil, err
+	}
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+func traceResult(span trace.Span, result driver.Result, err error) (driver.Result, error) {
+	if span == nil {
+		return result, err
+	}
+	if err == nil {
+		if affected, affectedErr := result.RowsAffected(); affectedErr == nil {
+			span.SetAttributes(attribute.Int64("db.response.rows_affected", affected))
+		}
+	}
+	End(span, err)
+	return result, err
+}
+
+// startQuerySpan starts a span for query when ctx is part of a trace, and returns a nil span otherwise
+func startQuerySpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, nil
+	}
+	name := queryName()
+	return Tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation.name", name),
+		attribute.String("db.query.text", query),
+	))
+}
+
+// sqlFile is this file, whose functions are skipped when naming queries
+var sqlFile = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return file
+}()
+
+// queryName names a query after the first function on the stack outside database/sql, sqlx and
+// this file, leaving out the module path, pointer receivers and closures:
+// github.com/.../store/postgres.(*campaignStorePostgres).GetCampaignByID.func1 becomes
+// postgres.campaignStorePostgres.GetCampaignByID.
+func queryName() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		fn := frame.Function
+		if fn != "" && frame.File != sqlFile && !strings.HasPrefix(fn, "database/sql.") && !strings.HasPrefix(fn, "github.com/jmoiron/sqlx.") {
+			return shortFunctionName(fn)
+		}
+		if !more {
+			return "sql.query"
+		}
+	}
+}
+
+func shortFunctionName(fn string) string {
+	if slash := strings.LastIndex(fn, "/"); slash >= 0 {
+		fn = fn[slash+1:]
+	}
+	fn = strings.NewReplacer("(*", "", ")", "").Replace(fn)
+	parts := strings.Split(fn, ".")
+	for len(parts) > 1 && isClosureName(parts[len(parts)-1]) {
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, ".")
+}
+
+// isClosureName reports whether part names an anonymous function, like func1 or the 2 of func1.2
+func isClosureName(part string) bool {
+	part = strings.TrimPrefix(part, "func")
+	if part == "" {
+		return false
+	}
+	for _, r := range part {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// dsnConnector opens connections of a driver without a connector of its own, like sqlmock's
+type dsnConnector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) { return c.driver.Open(c.dsn) }
+func (c dsnConnector) Driver() driver.Driver                        { return c.driver }
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func tracedMockDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.NewWithDSN(t.Name(), sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() { mockDB.Close() })
+	db := sqlx.NewDb(sql.OpenDB(WrapConnector(dsnConnector{driver: mockDB.Driver(), dsn: t.Name()})), "postgres")
+	t.Cleanup(func() { db.Close() })
+	return db, mock
+}
+
+type campaignStore struct{ db *sqlx.DB }
+
+func (s *campaignStore) ListNames(ctx context.Context) ([]string, error) {
+	var names []string
+	err := s.db.SelectContext(ctx, &names, "SELECT name FROM campaigns")
+	return names, err
+}
+
+func (s *campaignStore) Rename(ctx context.Context, name string) (int64, error) {
+	result, err := s.db.ExecContext(ctx, "UPDATE campaigns SET name = $1", name)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestWrapConnector_TracesQueriesWithinATrace(t *testing.T) {
+	recorder := recordSpans(t)
+	db, mock := tracedMockDB(t)
+	store := &campaignStore{db: db}
+	mock.ExpectQuery("SELECT name FROM campaigns").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a").AddRow("b"))
+	mock.ExpectExec("UPDATE campaigns SET name = $1").WithArgs("c").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("SELECT name FROM campaigns").WillReturnRows(sqlmock.NewRows([]string{"name"}))
+
+	ctx, request := Tracer().Start(context.Background(), "request")
+	names, err := store.ListNames(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, names)
+	affected, err := store.Rename(ctx, "c")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, affected)
+	request.End()
+
+	_, err = store.ListNames(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3, "the query outside a trace is not traced")
+	list, rename := spans[0], spans[1]
+	assert.Equal(t, "tracing.campaignStore.ListNames", list.Name())
+	assert.Equal(t, request.SpanContext().SpanID(), list.Parent().SpanID())
+	assert.Equal(t, "SELECT name FROM campaigns", spanAttributes(list)["db.query.text"].AsString())
+	assert.EqualValues(t, 2, spanAttributes(list)["db.response.returned_rows"].AsInt64())
+	assert.Equal(t, "tracing.campaignStore.Rename", rename.Name())
+	assert.EqualValues(t, 2, spanAttributes(rename)["db.response.rows_affected"].AsInt64())
+}
+
+func TestShortFunctionName(t *testing.T) {
+	assert.Equal(t, "postgres.campaignStorePostgres.GetCampaignByID",
+		shortFunctionName("github.com/fntelecomllc/studio/backend/internal/store/postgres.(*campaignStorePostgres).GetCampaignByID.func1.2"))
+	assert.Equal(t, "services.NewSessionService", shortFunctionName("github.com/fntelecomllc/studio/backend/internal/services.NewSessionService"))
+}
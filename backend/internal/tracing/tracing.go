@@ -0,0 +1,77 @@
+// Package tracing sets up OpenTelemetry tracing of requests, campaign operations, store queries and
+// worker jobs. Until Setup is called with an OTLP endpoint the global tracer provider is the
+// OpenTelemetry no-op, so spans cost next to nothing in development.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fntelecomllc/studio/backend/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/fntelecomllc/studio/backend"
+
+// Setup exports spans to cfg.OTLPEndpoint, a host:port or URL of an OTLP/HTTP collector. It
+// returns false, leaving tracing off, when no endpoint is set. The returned function flushes
+// buffered spans and stops the exporter.
+func Setup(ctx context.Context, cfg config.TracingConfig) (bool, func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if cfg.OTLPEndpoint == "" {
+		return false, noop, nil
+	}
+
+	var opts []otlptracehttp.Option
+	if strings.Contains(cfg.OTLPEndpoint, "://") {
+		opts = append(opts, otlptracehttp.WithEndpointURL(cfg.OTLPEndpoint))
+	} else {
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.OTLPEndpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return false, noop, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = config.DefaultTracingServiceName
+	}
+	sampleRatio := cfg.SampleRatio
+	if sampleRatio <= 0 || sampleRatio > 1 {
+		sampleRatio = config.DefaultTracingSampleRatio
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return true, provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of the backend's spans
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// End records err, if any, on span and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}